@@ -19,6 +19,8 @@ import (
 	"github.com/Wei-Shaw/sub2api/internal/config"
 	"github.com/Wei-Shaw/sub2api/internal/handler"
 	"github.com/Wei-Shaw/sub2api/internal/pkg/logger"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/tracing"
+	"github.com/Wei-Shaw/sub2api/internal/repository"
 	"github.com/Wei-Shaw/sub2api/internal/server/middleware"
 	"github.com/Wei-Shaw/sub2api/internal/setup"
 	"github.com/Wei-Shaw/sub2api/internal/web"
@@ -61,6 +63,7 @@ func main() {
 	// Parse command line flags
 	setupMode := flag.Bool("setup", false, "Run setup wizard in CLI mode")
 	showVersion := flag.Bool("version", false, "Show version information")
+	rotateCredentialKey := flag.Bool("rotate-credential-key", false, "Re-wrap encrypted account credentials and proxy passwords with the current master key, then exit")
 	flag.Parse()
 
 	if *showVersion {
@@ -76,6 +79,14 @@ func main() {
 		return
 	}
 
+	// 主密钥轮换：可在服务运行期间执行
+	if *rotateCredentialKey {
+		if !runCredentialKeyRotation() {
+			os.Exit(1)
+		}
+		return
+	}
+
 	// Check if setup is needed
 	if setup.NeedsSetup() {
 		// Check if auto-setup is enabled (for Docker deployment)
@@ -128,6 +139,43 @@ func runSetupServer() {
 	}
 }
 
+// runCredentialKeyRotation 使用当前主密钥重新包装所有已加密凭证的数据密钥，并加密残留的明文。
+// 每行以 compare-and-swap 方式写回，不会覆盖运行中实例的并发修改，因此无需停机。
+// 返回 false 表示仍有行未完成轮换。
+func runCredentialKeyRotation() bool {
+	cfg, err := config.LoadForBootstrap()
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+	encryptor, err := repository.NewCredentialEncryptor(cfg)
+	if err != nil {
+		log.Fatalf("Invalid credential encryption config: %v", err)
+	}
+	if !encryptor.Enabled() {
+		log.Fatalf("security.credential_encryption.master_key is not configured")
+	}
+
+	client, db, err := repository.InitEnt(cfg)
+	if err != nil {
+		log.Fatalf("Failed to initialize database: %v", err)
+	}
+	defer func() { _ = client.Close() }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+	defer cancel()
+	stats, err := repository.RotateCredentialEncryption(ctx, db, encryptor)
+	if err != nil {
+		log.Fatalf("Credential key rotation failed: %v", err)
+	}
+	log.Printf("Credential key rotation finished (active key %s): accounts=%d proxies=%d conflicts=%d failed=%d",
+		encryptor.ActiveKeyID(), stats.AccountsUpdated, stats.ProxiesUpdated, stats.Conflicts, stats.Failed)
+	if stats.Conflicts > 0 || stats.Failed > 0 {
+		log.Println("Some rows were not re-wrapped; keep previous_keys configured and run the command again.")
+		return false
+	}
+	return true
+}
+
 func runMainServer() {
 	cfg, err := config.LoadForBootstrap()
 	if err != nil {
@@ -136,6 +184,18 @@ func runMainServer() {
 	if err := logger.Init(logger.OptionsFromConfig(cfg.Log)); err != nil {
 		log.Fatalf("Failed to initialize logger: %v", err)
 	}
+	shutdownTracing, err := tracing.Init(context.Background(), tracing.OptionsFromConfig(cfg, Version))
+	if err != nil {
+		log.Fatalf("Failed to initialize tracing: %v", err)
+	}
+	// 在 app.Cleanup 之后执行（defer 逆序），确保关闭过程中的 Span 也能导出
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdownTracing(ctx); err != nil {
+			log.Printf("Failed to flush traces: %v", err)
+		}
+	}()
 	if cfg.RunMode == config.RunModeSimple {
 		log.Println("⚠️  WARNING: Running in SIMPLE mode - billing and quota checks are DISABLED")
 	}
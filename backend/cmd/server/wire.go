@@ -68,24 +68,33 @@ func provideCleanup(
 	opsAlertEvaluator *service.OpsAlertEvaluatorService,
 	opsCleanup *service.OpsCleanupService,
 	opsScheduledReport *service.OpsScheduledReportService,
+	opsNotification *service.OpsNotificationService,
 	opsSystemLogSink *service.OpsSystemLogSink,
 	soraMediaCleanup *service.SoraMediaCleanupService,
 	schedulerSnapshot *service.SchedulerSnapshotService,
 	tokenRefresh *service.TokenRefreshService,
 	accountExpiry *service.AccountExpiryService,
 	subscriptionExpiry *service.SubscriptionExpiryService,
+	balanceLedger *service.BalanceLedgerService,
 	usageCleanup *service.UsageCleanupService,
+	batch *service.BatchService,
 	idempotencyCleanup *service.IdempotencyCleanupService,
+	loginHistoryCleanup *service.LoginHistoryCleanupService,
+	payment *service.PaymentService,
+	referral *service.ReferralService,
+	activity *service.ActivityService,
 	pricing *service.PricingService,
 	emailQueue *service.EmailQueueService,
 	billingCache *service.BillingCacheService,
 	usageRecordWorkerPool *service.UsageRecordWorkerPool,
+	usageJournal *service.UsageJournalService,
 	subscriptionService *service.SubscriptionService,
 	oauth *service.OAuthService,
 	openaiOAuth *service.OpenAIOAuthService,
 	geminiOAuth *service.GeminiOAuthService,
 	antigravityOAuth *service.AntigravityOAuthService,
 	openAIGateway *service.OpenAIGatewayService,
+	metricsServer *server.MetricsServer,
 ) func() {
 	return func() {
 		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
@@ -128,6 +137,12 @@ func provideCleanup(
 				}
 				return nil
 			}},
+			{"OpsNotificationService", func() error {
+				if opsNotification != nil {
+					opsNotification.Stop()
+				}
+				return nil
+			}},
 			{"OpsAggregationService", func() error {
 				if opsAggregation != nil {
 					opsAggregation.Stop()
@@ -152,12 +167,42 @@ func provideCleanup(
 				}
 				return nil
 			}},
+			{"BatchService", func() error {
+				if batch != nil {
+					batch.Stop()
+				}
+				return nil
+			}},
 			{"IdempotencyCleanupService", func() error {
 				if idempotencyCleanup != nil {
 					idempotencyCleanup.Stop()
 				}
 				return nil
 			}},
+			{"LoginHistoryCleanupService", func() error {
+				if loginHistoryCleanup != nil {
+					loginHistoryCleanup.Stop()
+				}
+				return nil
+			}},
+			{"PaymentService", func() error {
+				if payment != nil {
+					payment.Stop()
+				}
+				return nil
+			}},
+			{"ReferralService", func() error {
+				if referral != nil {
+					referral.Stop()
+				}
+				return nil
+			}},
+			{"ActivityService", func() error {
+				if activity != nil {
+					activity.Stop()
+				}
+				return nil
+			}},
 			{"TokenRefreshService", func() error {
 				tokenRefresh.Stop()
 				return nil
@@ -170,6 +215,10 @@ func provideCleanup(
 				subscriptionExpiry.Stop()
 				return nil
 			}},
+			{"BalanceLedgerService", func() error {
+				balanceLedger.Stop()
+				return nil
+			}},
 			{"SubscriptionService", func() error {
 				if subscriptionService != nil {
 					subscriptionService.Stop()
@@ -194,6 +243,10 @@ func provideCleanup(
 				}
 				return nil
 			}},
+			{"UsageJournalService", func() error {
+				usageJournal.Stop()
+				return nil
+			}},
 			{"OAuthService", func() error {
 				oauth.Stop()
 				return nil
@@ -216,6 +269,9 @@ func provideCleanup(
 				}
 				return nil
 			}},
+			{"MetricsServer", func() error {
+				return metricsServer.Stop(ctx)
+			}},
 		}
 
 		infraSteps := []cleanupStep{
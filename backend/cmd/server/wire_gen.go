@@ -59,26 +59,41 @@ func initializeApplication(buildInfo handler.BuildInfo) (*Application, error) {
 	billingCache := repository.NewBillingCache(redisClient)
 	userSubscriptionRepository := repository.NewUserSubscriptionRepository(client)
 	apiKeyRepository := repository.NewAPIKeyRepository(client, db)
-	billingCacheService := service.NewBillingCacheService(billingCache, userRepository, userSubscriptionRepository, apiKeyRepository, configConfig)
+	organizationRepository := repository.NewOrganizationRepository(db)
+	billingCacheService := service.NewBillingCacheService(billingCache, userRepository, userSubscriptionRepository, apiKeyRepository, organizationRepository, configConfig)
 	userGroupRateRepository := repository.NewUserGroupRateRepository(db)
 	apiKeyCache := repository.NewAPIKeyCache(redisClient)
 	apiKeyService := service.NewAPIKeyService(apiKeyRepository, userRepository, groupRepository, userSubscriptionRepository, userGroupRateRepository, apiKeyCache, configConfig)
 	apiKeyAuthCacheInvalidator := service.ProvideAPIKeyAuthCacheInvalidator(apiKeyService)
-	promoService := service.NewPromoService(promoCodeRepository, userRepository, billingCacheService, client, apiKeyAuthCacheInvalidator)
 	subscriptionService := service.NewSubscriptionService(groupRepository, userSubscriptionRepository, billingCacheService, client, configConfig)
+	userAttributeDefinitionRepository := repository.NewUserAttributeDefinitionRepository(client)
+	userAttributeValueRepository := repository.NewUserAttributeValueRepository(client)
+	userAttributeService := service.NewUserAttributeService(userAttributeDefinitionRepository, userAttributeValueRepository)
+	promoService := service.NewPromoService(promoCodeRepository, userRepository, subscriptionService, userAttributeService, billingCacheService, client, apiKeyAuthCacheInvalidator)
 	authService := service.NewAuthService(userRepository, redeemCodeRepository, refreshTokenCache, configConfig, settingService, emailService, turnstileService, emailQueueService, promoService, subscriptionService)
 	userService := service.NewUserService(userRepository, apiKeyAuthCacheInvalidator, billingCache)
 	redeemCache := repository.NewRedeemCache(redisClient)
 	distributorService := service.NewDistributorService(db, userRepository, groupRepository)
-	redeemService := service.ProvideRedeemService(redeemCodeRepository, userRepository, subscriptionService, settingService, redeemCache, billingCacheService, client, apiKeyAuthCacheInvalidator, distributorService)
+	redeemService := service.ProvideRedeemService(redeemCodeRepository, userRepository, subscriptionService, settingService, redeemCache, billingCacheService, client, apiKeyAuthCacheInvalidator, distributorService, promoService)
 	secretEncryptor, err := repository.NewAESEncryptor(configConfig)
 	if err != nil {
 		return nil, err
 	}
 	totpCache := repository.NewTotpCache(redisClient)
 	totpService := service.NewTotpService(userRepository, secretEncryptor, totpCache, settingService, emailService, emailQueueService)
-	authHandler := handler.NewAuthHandler(configConfig, authService, userService, settingService, promoService, redeemService, totpService)
-	userHandler := handler.NewUserHandler(userService)
+	webAuthnCredentialRepository := repository.NewWebAuthnCredentialRepository(db)
+	webAuthnCache := repository.NewWebAuthnCache(redisClient)
+	webAuthnService := service.NewWebAuthnService(webAuthnCredentialRepository, webAuthnCache, userRepository, authService, totpService, configConfig)
+	recoveryCodeRepository := repository.NewRecoveryCodeRepository(db)
+	recoveryCodeService := service.NewRecoveryCodeService(recoveryCodeRepository, userRepository, secretEncryptor, totpCache, totpService)
+	loginHistoryRepository := repository.NewLoginHistoryRepository(db)
+	loginHistoryService := service.NewLoginHistoryService(loginHistoryRepository, userRepository, settingService, emailQueueService, configConfig)
+	referralRepository := repository.NewReferralRepository(db)
+	referralService := service.ProvideReferralService(referralRepository, userRepository, billingCacheService, apiKeyAuthCacheInvalidator, client, configConfig)
+	authHandler := handler.NewAuthHandler(configConfig, authService, userService, settingService, promoService, redeemService, totpService, webAuthnService, recoveryCodeService, loginHistoryService, referralService)
+	balanceLedgerRepository := repository.NewBalanceLedgerRepository(db)
+	balanceLedgerService := service.ProvideBalanceLedgerService(balanceLedgerRepository, configConfig)
+	userHandler := handler.NewUserHandler(userService, balanceLedgerService)
 	apiKeyHandler := handler.NewAPIKeyHandler(apiKeyService)
 	usageLogRepository := repository.NewUsageLogRepository(client, db)
 	usageService := service.NewUsageService(usageLogRepository, userRepository, client, apiKeyAuthCacheInvalidator)
@@ -88,6 +103,8 @@ func initializeApplication(buildInfo handler.BuildInfo) (*Application, error) {
 		return nil, err
 	}
 	billingService := service.NewBillingService(configConfig, pricingService)
+	billingHoldCache := repository.NewBillingHoldCache(redisClient)
+	billingHoldService := service.NewBillingHoldService(billingHoldCache, billingService, billingCacheService, configConfig)
 	schedulerCache := repository.NewSchedulerCache(redisClient)
 	accountRepository := repository.NewAccountRepository(client, db, schedulerCache)
 	gatewayCache := repository.NewGatewayCache(redisClient)
@@ -112,11 +129,34 @@ func initializeApplication(buildInfo handler.BuildInfo) (*Application, error) {
 	}
 	deferredService := service.ProvideDeferredService(accountRepository, timingWheelService)
 	openAITokenProvider := service.NewOpenAITokenProvider(accountRepository, geminiTokenCache, openAIOAuthService)
-	openAIGatewayService := service.NewOpenAIGatewayService(accountRepository, usageLogRepository, userRepository, userSubscriptionRepository, gatewayCache, configConfig, schedulerSnapshotService, concurrencyService, billingService, rateLimitService, billingCacheService, httpUpstream, deferredService, openAITokenProvider)
+	usageJournalStore := repository.ProvideUsageJournalStore(redisClient, configConfig)
+	usageBillingDedupRepository := repository.NewUsageBillingDedupRepository(db)
+	usageJournalService := service.ProvideUsageJournalService(usageJournalStore, usageBillingDedupRepository, usageLogRepository, userRepository, userSubscriptionRepository, apiKeyService, billingCacheService, client, configConfig)
+	apiKeyTrafficCache := repository.NewAPIKeyTrafficCache(redisClient)
+	apiKeyTrafficLimitService := service.NewAPIKeyTrafficLimitService(apiKeyTrafficCache)
+	openAIGatewayService := service.NewOpenAIGatewayService(accountRepository, usageLogRepository, userRepository, userSubscriptionRepository, gatewayCache, configConfig, schedulerSnapshotService, concurrencyService, billingService, rateLimitService, billingCacheService, httpUpstream, deferredService, openAITokenProvider, usageJournalService, apiKeyTrafficLimitService)
 	usageHandler := handler.NewUsageHandler(usageService, apiKeyService, billingService, openAIGatewayService)
 	voiceChatService := service.NewVoiceChatService(httpUpstream, openAIGatewayService, configConfig)
 	voiceHandler := handler.NewVoiceHandler(voiceChatService, apiKeyService, subscriptionService, billingCacheService, billingService, openAIGatewayService)
 	redeemHandler := handler.NewRedeemHandler(redeemService)
+	organizationService := service.NewOrganizationService(organizationRepository, userRepository, apiKeyService, billingCacheService, redeemService, balanceLedgerService, subscriptionService, settingService, client, configConfig)
+	organizationHandler := handler.NewOrganizationHandler(organizationService)
+	paymentProductRepository := repository.NewPaymentProductRepository(db)
+	paymentOrderRepository := repository.NewPaymentOrderRepository(db)
+	paymentProviders := repository.NewPaymentProviders(configConfig)
+	couponRepository := repository.NewCouponRepository(db)
+	couponService := service.NewCouponService(couponRepository, userRepository, groupRepository)
+	paymentService := service.ProvidePaymentService(paymentProductRepository, paymentOrderRepository, groupRepository, userRepository, redeemService, subscriptionService, couponService, billingCacheService, apiKeyAuthCacheInvalidator, paymentProviders, configConfig)
+	paymentHandler := handler.NewPaymentHandler(paymentService)
+	referralHandler := handler.NewReferralHandler(referralService)
+	pointsRepository := repository.NewPointsRepository(db)
+	pointsService := service.NewPointsService(pointsRepository, userRepository, groupRepository, redeemService, subscriptionService, billingCacheService, apiKeyAuthCacheInvalidator, client)
+	pointsHandler := handler.NewPointsHandler(pointsService)
+	couponHandler := handler.NewCouponHandler(couponService, paymentService)
+	handlerPromoHandler := handler.NewPromoHandler(promoService, settingService)
+	activityTaskRepository := repository.NewActivityTaskRepository(db)
+	activityService := service.ProvideActivityService(client, userRepository, activityTaskRepository, pointsService, couponService, totpService)
+	activityHandler := handler.NewActivityHandler(activityService)
 	subscriptionHandler := handler.NewSubscriptionHandler(subscriptionService)
 	announcementRepository := repository.NewAnnouncementRepository(client)
 	announcementReadRepository := repository.NewAnnouncementReadRepository(client)
@@ -167,7 +207,7 @@ func initializeApplication(buildInfo handler.BuildInfo) (*Application, error) {
 	identityService := service.NewIdentityService(identityCache)
 	claudeTokenProvider := service.NewClaudeTokenProvider(accountRepository, geminiTokenCache, oAuthService)
 	digestSessionStore := service.NewDigestSessionStore()
-	gatewayService := service.NewGatewayService(accountRepository, groupRepository, usageLogRepository, userRepository, userSubscriptionRepository, userGroupRateRepository, gatewayCache, configConfig, schedulerSnapshotService, concurrencyService, billingService, rateLimitService, billingCacheService, identityService, httpUpstream, deferredService, claudeTokenProvider, sessionLimitCache, rpmCache, digestSessionStore)
+	gatewayService := service.NewGatewayService(accountRepository, groupRepository, usageLogRepository, userRepository, userSubscriptionRepository, userGroupRateRepository, gatewayCache, configConfig, schedulerSnapshotService, concurrencyService, billingService, rateLimitService, billingCacheService, identityService, httpUpstream, deferredService, claudeTokenProvider, sessionLimitCache, rpmCache, digestSessionStore, usageJournalService, apiKeyTrafficLimitService)
 	geminiMessagesCompatService := service.NewGeminiMessagesCompatService(accountRepository, groupRepository, gatewayCache, schedulerSnapshotService, geminiTokenProvider, rateLimitService, httpUpstream, antigravityGatewayService, configConfig)
 	opsSystemLogSink := service.ProvideOpsSystemLogSink(opsRepository)
 	opsService := service.NewOpsService(opsRepository, settingRepository, configConfig, accountRepository, userRepository, concurrencyService, gatewayService, openAIGatewayService, geminiMessagesCompatService, antigravityGatewayService, opsSystemLogSink)
@@ -185,50 +225,68 @@ func initializeApplication(buildInfo handler.BuildInfo) (*Application, error) {
 	adminSubscriptionHandler := admin.NewSubscriptionHandler(subscriptionService)
 	usageCleanupRepository := repository.NewUsageCleanupRepository(client, db)
 	usageCleanupService := service.ProvideUsageCleanupService(usageCleanupRepository, timingWheelService, dashboardAggregationService, configConfig)
-	adminUsageHandler := admin.NewUsageHandler(usageService, apiKeyService, adminService, usageCleanupService)
-	userAttributeDefinitionRepository := repository.NewUserAttributeDefinitionRepository(client)
-	userAttributeValueRepository := repository.NewUserAttributeValueRepository(client)
-	userAttributeService := service.NewUserAttributeService(userAttributeDefinitionRepository, userAttributeValueRepository)
+	adminUsageHandler := admin.NewUsageHandler(usageService, apiKeyService, adminService, usageCleanupService, usageJournalService)
 	userAttributeHandler := admin.NewUserAttributeHandler(userAttributeService)
+	ssoProviderRepository := repository.NewSSOProviderRepository(db)
+	ssoProviderClient := repository.NewSSOProviderClient(configConfig)
+	ssoService := service.ProvideSSOService(ssoProviderRepository, ssoProviderClient, secretEncryptor, userRepository, groupRepository, authService, settingService, userAttributeService, client, configConfig)
+	ssoHandler := handler.NewSSOHandler(ssoService, loginHistoryService)
+	ssoProviderHandler := admin.NewSSOProviderHandler(ssoService)
 	errorPassthroughRepository := repository.NewErrorPassthroughRepository(client)
 	errorPassthroughCache := repository.NewErrorPassthroughCache(redisClient)
 	errorPassthroughService := service.NewErrorPassthroughService(errorPassthroughRepository, errorPassthroughCache)
 	errorPassthroughHandler := admin.NewErrorPassthroughHandler(errorPassthroughService)
 	adminAPIKeyHandler := admin.NewAdminAPIKeyHandler(adminService)
-	adminHandlers := handler.ProvideAdminHandlers(dashboardHandler, adminUserHandler, groupHandler, accountHandler, adminAnnouncementHandler, dataManagementHandler, oAuthHandler, openAIOAuthHandler, geminiOAuthHandler, antigravityOAuthHandler, proxyHandler, adminRedeemHandler, promoHandler, settingHandler, adminDistributorHandler, opsHandler, systemHandler, adminSubscriptionHandler, adminUsageHandler, userAttributeHandler, errorPassthroughHandler, adminAPIKeyHandler)
+	balanceLedgerHandler := admin.NewBalanceLedgerHandler(balanceLedgerService)
+	userSessionHandler := admin.NewUserSessionHandler(authService, loginHistoryService)
+	adminPaymentHandler := admin.NewPaymentHandler(paymentService)
+	adminReferralHandler := admin.NewReferralHandler(referralService)
+	adminPointsHandler := admin.NewPointsHandler(pointsService)
+	adminCouponHandler := admin.NewCouponHandler(couponService)
+	adminHandlers := handler.ProvideAdminHandlers(dashboardHandler, adminUserHandler, groupHandler, accountHandler, adminAnnouncementHandler, dataManagementHandler, oAuthHandler, openAIOAuthHandler, geminiOAuthHandler, antigravityOAuthHandler, proxyHandler, adminRedeemHandler, promoHandler, settingHandler, adminDistributorHandler, opsHandler, systemHandler, adminSubscriptionHandler, adminUsageHandler, userAttributeHandler, errorPassthroughHandler, adminAPIKeyHandler, balanceLedgerHandler, ssoProviderHandler, userSessionHandler, adminPaymentHandler, adminReferralHandler, adminPointsHandler, adminCouponHandler)
 	usageRecordWorkerPool := service.NewUsageRecordWorkerPool(configConfig)
 	userMsgQueueCache := repository.NewUserMsgQueueCache(redisClient)
 	userMessageQueueService := service.ProvideUserMessageQueueService(userMsgQueueCache, rpmCache, configConfig)
-	gatewayHandler := handler.NewGatewayHandler(gatewayService, geminiMessagesCompatService, antigravityGatewayService, userService, concurrencyService, billingCacheService, usageService, apiKeyService, usageRecordWorkerPool, errorPassthroughService, userMessageQueueService, configConfig, settingService)
-	openAIGatewayHandler := handler.NewOpenAIGatewayHandler(openAIGatewayService, concurrencyService, billingCacheService, apiKeyService, usageRecordWorkerPool, errorPassthroughService, configConfig)
+	responsesConversationCache := repository.NewResponsesConversationCache(redisClient)
+	responsesConversationService := service.NewResponsesConversationService(responsesConversationCache)
+	gatewayHandler := handler.NewGatewayHandler(gatewayService, geminiMessagesCompatService, antigravityGatewayService, userService, concurrencyService, billingCacheService, billingHoldService, apiKeyTrafficLimitService, usageService, apiKeyService, usageRecordWorkerPool, errorPassthroughService, userMessageQueueService, configConfig, settingService, responsesConversationService)
+	openAIGatewayHandler := handler.NewOpenAIGatewayHandler(openAIGatewayService, concurrencyService, billingCacheService, billingHoldService, apiKeyTrafficLimitService, apiKeyService, usageRecordWorkerPool, errorPassthroughService, configConfig)
 	soraSDKClient := service.ProvideSoraSDKClient(configConfig, httpUpstream, openAITokenProvider, accountRepository, soraAccountRepository)
 	soraGatewayService := service.NewSoraGatewayService(soraSDKClient, rateLimitService, httpUpstream, configConfig)
-	soraGatewayHandler := handler.NewSoraGatewayHandler(gatewayService, soraGatewayService, concurrencyService, billingCacheService, usageRecordWorkerPool, configConfig)
+	soraGatewayHandler := handler.NewSoraGatewayHandler(gatewayService, soraGatewayService, concurrencyService, billingCacheService, apiKeyTrafficLimitService, usageRecordWorkerPool, configConfig)
 	soraGenerationRepository := repository.NewSoraGenerationRepository(db)
 	soraQuotaService := service.NewSoraQuotaService(userRepository, groupRepository, settingService)
 	soraGenerationService := service.NewSoraGenerationService(soraGenerationRepository, soraS3Storage, soraQuotaService)
 	soraMediaStorage := service.ProvideSoraMediaStorage(configConfig)
 	soraClientHandler := handler.NewSoraClientHandler(soraGenerationService, soraQuotaService, soraS3Storage, soraGatewayService, gatewayService, soraMediaStorage, apiKeyService)
+	batchRepository := repository.NewBatchRepository(db)
+	batchService := service.ProvideBatchService(batchRepository, apiKeyRepository, accountRepository, concurrencyService, timingWheelService, billingHoldService, configConfig)
+	batchHandler := handler.NewBatchHandler(batchService)
 	handlerSettingHandler := handler.ProvideSettingHandler(settingService, buildInfo)
 	totpHandler := handler.NewTotpHandler(totpService)
+	securityHandler := handler.NewSecurityHandler(webAuthnService, recoveryCodeService, authService, loginHistoryService)
 	idempotencyCoordinator := service.ProvideIdempotencyCoordinator(idempotencyRepository, configConfig)
 	idempotencyCleanupService := service.ProvideIdempotencyCleanupService(idempotencyRepository, configConfig)
-	handlers := handler.ProvideHandlers(authHandler, userHandler, apiKeyHandler, usageHandler, voiceHandler, redeemHandler, subscriptionHandler, announcementHandler, distributorHandler, adminHandlers, gatewayHandler, openAIGatewayHandler, soraGatewayHandler, soraClientHandler, handlerSettingHandler, totpHandler, idempotencyCoordinator, idempotencyCleanupService)
+	loginHistoryCleanupService := service.ProvideLoginHistoryCleanupService(loginHistoryRepository, configConfig)
+	handlers := handler.ProvideHandlers(authHandler, ssoHandler, userHandler, apiKeyHandler, usageHandler, voiceHandler, redeemHandler, organizationHandler, paymentHandler, referralHandler, pointsHandler, couponHandler, handlerPromoHandler, activityHandler, subscriptionHandler, announcementHandler, distributorHandler, adminHandlers, gatewayHandler, openAIGatewayHandler, soraGatewayHandler, soraClientHandler, batchHandler, handlerSettingHandler, totpHandler, securityHandler, idempotencyCoordinator, idempotencyCleanupService)
 	jwtAuthMiddleware := middleware.NewJWTAuthMiddleware(authService, userService)
 	adminAuthMiddleware := middleware.NewAdminAuthMiddleware(authService, userService, settingService)
 	apiKeyAuthMiddleware := middleware.NewAPIKeyAuthMiddleware(apiKeyService, subscriptionService, configConfig)
-	engine := server.ProvideRouter(configConfig, handlers, jwtAuthMiddleware, adminAuthMiddleware, apiKeyAuthMiddleware, apiKeyService, subscriptionService, opsService, settingService, redisClient)
+	engine := server.ProvideRouter(configConfig, handlers, jwtAuthMiddleware, adminAuthMiddleware, apiKeyAuthMiddleware, apiKeyService, subscriptionService, opsService, settingService, batchService, redisClient)
 	httpServer := server.ProvideHTTPServer(configConfig, engine)
 	opsMetricsCollector := service.ProvideOpsMetricsCollector(opsRepository, settingRepository, accountRepository, concurrencyService, db, redisClient, configConfig)
 	opsAggregationService := service.ProvideOpsAggregationService(opsRepository, settingRepository, db, redisClient, configConfig)
-	opsAlertEvaluatorService := service.ProvideOpsAlertEvaluatorService(opsService, opsRepository, emailService, redisClient, configConfig)
+	opsNotificationService := service.ProvideOpsNotificationService(opsRepository, configConfig)
+	opsAlertEvaluatorService := service.ProvideOpsAlertEvaluatorService(opsService, opsRepository, emailService, opsNotificationService, redisClient, balanceLedgerService, configConfig)
 	opsCleanupService := service.ProvideOpsCleanupService(opsRepository, db, redisClient, configConfig)
-	opsScheduledReportService := service.ProvideOpsScheduledReportService(opsService, userService, emailService, redisClient, configConfig)
+	opsScheduledReportService := service.ProvideOpsScheduledReportService(opsService, userService, emailService, opsNotificationService, redisClient, configConfig)
 	soraMediaCleanupService := service.ProvideSoraMediaCleanupService(soraMediaStorage, configConfig)
 	tokenRefreshService := service.ProvideTokenRefreshService(accountRepository, soraAccountRepository, oAuthService, openAIOAuthService, geminiOAuthService, antigravityOAuthService, compositeTokenCacheInvalidator, schedulerCache, configConfig, tempUnschedCache)
 	accountExpiryService := service.ProvideAccountExpiryService(accountRepository)
 	subscriptionExpiryService := service.ProvideSubscriptionExpiryService(userSubscriptionRepository)
-	v := provideCleanup(client, redisClient, opsMetricsCollector, opsAggregationService, opsAlertEvaluatorService, opsCleanupService, opsScheduledReportService, opsSystemLogSink, soraMediaCleanupService, schedulerSnapshotService, tokenRefreshService, accountExpiryService, subscriptionExpiryService, usageCleanupService, idempotencyCleanupService, pricingService, emailQueueService, billingCacheService, usageRecordWorkerPool, subscriptionService, oAuthService, openAIOAuthService, geminiOAuthService, antigravityOAuthService, openAIGatewayService)
+	prometheusMetricsCollector := service.ProvidePrometheusMetricsCollector(accountRepository, concurrencyService, openAIGatewayService, usageRecordWorkerPool, schedulerSnapshotService, configConfig)
+	metricsServer := server.ProvideMetricsServer(configConfig, prometheusMetricsCollector)
+	v := provideCleanup(client, redisClient, opsMetricsCollector, opsAggregationService, opsAlertEvaluatorService, opsCleanupService, opsScheduledReportService, opsNotificationService, opsSystemLogSink, soraMediaCleanupService, schedulerSnapshotService, tokenRefreshService, accountExpiryService, subscriptionExpiryService, balanceLedgerService, usageCleanupService, batchService, idempotencyCleanupService, loginHistoryCleanupService, paymentService, referralService, activityService, pricingService, emailQueueService, billingCacheService, usageRecordWorkerPool, usageJournalService, subscriptionService, oAuthService, openAIOAuthService, geminiOAuthService, antigravityOAuthService, openAIGatewayService, metricsServer)
 	application := &Application{
 		Server:  httpServer,
 		Cleanup: v,
@@ -258,24 +316,33 @@ func provideCleanup(
 	opsAlertEvaluator *service.OpsAlertEvaluatorService,
 	opsCleanup *service.OpsCleanupService,
 	opsScheduledReport *service.OpsScheduledReportService,
+	opsNotification *service.OpsNotificationService,
 	opsSystemLogSink *service.OpsSystemLogSink,
 	soraMediaCleanup *service.SoraMediaCleanupService,
 	schedulerSnapshot *service.SchedulerSnapshotService,
 	tokenRefresh *service.TokenRefreshService,
 	accountExpiry *service.AccountExpiryService,
 	subscriptionExpiry *service.SubscriptionExpiryService,
+	balanceLedger *service.BalanceLedgerService,
 	usageCleanup *service.UsageCleanupService,
+	batch *service.BatchService,
 	idempotencyCleanup *service.IdempotencyCleanupService,
+	loginHistoryCleanup *service.LoginHistoryCleanupService,
+	payment *service.PaymentService,
+	referral *service.ReferralService,
+	activity *service.ActivityService,
 	pricing *service.PricingService,
 	emailQueue *service.EmailQueueService,
 	billingCache *service.BillingCacheService,
 	usageRecordWorkerPool *service.UsageRecordWorkerPool,
+	usageJournal *service.UsageJournalService,
 	subscriptionService *service.SubscriptionService,
 	oauth *service.OAuthService,
 	openaiOAuth *service.OpenAIOAuthService,
 	geminiOAuth *service.GeminiOAuthService,
 	antigravityOAuth *service.AntigravityOAuthService,
 	openAIGateway *service.OpenAIGatewayService,
+	metricsServer *server.MetricsServer,
 ) func() {
 	return func() {
 		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
@@ -317,6 +384,12 @@ func provideCleanup(
 				}
 				return nil
 			}},
+			{"OpsNotificationService", func() error {
+				if opsNotification != nil {
+					opsNotification.Stop()
+				}
+				return nil
+			}},
 			{"OpsAggregationService", func() error {
 				if opsAggregation != nil {
 					opsAggregation.Stop()
@@ -341,12 +414,42 @@ func provideCleanup(
 				}
 				return nil
 			}},
+			{"BatchService", func() error {
+				if batch != nil {
+					batch.Stop()
+				}
+				return nil
+			}},
 			{"IdempotencyCleanupService", func() error {
 				if idempotencyCleanup != nil {
 					idempotencyCleanup.Stop()
 				}
 				return nil
 			}},
+			{"LoginHistoryCleanupService", func() error {
+				if loginHistoryCleanup != nil {
+					loginHistoryCleanup.Stop()
+				}
+				return nil
+			}},
+			{"PaymentService", func() error {
+				if payment != nil {
+					payment.Stop()
+				}
+				return nil
+			}},
+			{"ReferralService", func() error {
+				if referral != nil {
+					referral.Stop()
+				}
+				return nil
+			}},
+			{"ActivityService", func() error {
+				if activity != nil {
+					activity.Stop()
+				}
+				return nil
+			}},
 			{"TokenRefreshService", func() error {
 				tokenRefresh.Stop()
 				return nil
@@ -359,6 +462,10 @@ func provideCleanup(
 				subscriptionExpiry.Stop()
 				return nil
 			}},
+			{"BalanceLedgerService", func() error {
+				balanceLedger.Stop()
+				return nil
+			}},
 			{"SubscriptionService", func() error {
 				if subscriptionService != nil {
 					subscriptionService.Stop()
@@ -383,6 +490,10 @@ func provideCleanup(
 				}
 				return nil
 			}},
+			{"UsageJournalService", func() error {
+				usageJournal.Stop()
+				return nil
+			}},
 			{"OAuthService", func() error {
 				oauth.Stop()
 				return nil
@@ -405,6 +516,9 @@ func provideCleanup(
 				}
 				return nil
 			}},
+			{"MetricsServer", func() error {
+				return metricsServer.Stop(ctx)
+			}},
 		}
 
 		infraSteps := []cleanupStep{
@@ -6,6 +6,7 @@ import (
 
 	"github.com/Wei-Shaw/sub2api/internal/config"
 	"github.com/Wei-Shaw/sub2api/internal/handler"
+	"github.com/Wei-Shaw/sub2api/internal/server"
 	"github.com/Wei-Shaw/sub2api/internal/service"
 	"github.com/stretchr/testify/require"
 )
@@ -41,10 +42,15 @@ func TestProvideCleanup_WithMinimalDependencies_NoPanic(t *testing.T) {
 	)
 	accountExpirySvc := service.NewAccountExpiryService(nil, time.Second)
 	subscriptionExpirySvc := service.NewSubscriptionExpiryService(nil, time.Second)
+	balanceLedgerSvc := service.NewBalanceLedgerService(nil, cfg)
 	pricingSvc := service.NewPricingService(cfg, nil)
 	emailQueueSvc := service.NewEmailQueueService(nil, 1)
-	billingCacheSvc := service.NewBillingCacheService(nil, nil, nil, nil, cfg)
+	billingCacheSvc := service.NewBillingCacheService(nil, nil, nil, nil, nil, cfg)
 	idempotencyCleanupSvc := service.NewIdempotencyCleanupService(nil, cfg)
+	loginHistoryCleanupSvc := service.NewLoginHistoryCleanupService(nil, cfg)
+	paymentSvc := service.NewPaymentService(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, cfg)
+	referralSvc := service.NewReferralService(nil, nil, nil, nil, nil, cfg)
+	activitySvc := service.NewActivityService(nil, nil, nil, nil, nil)
 	schedulerSnapshotSvc := service.NewSchedulerSnapshotService(nil, nil, nil, nil, cfg)
 	opsSystemLogSinkSvc := service.NewOpsSystemLogSink(nil)
 
@@ -56,24 +62,33 @@ func TestProvideCleanup_WithMinimalDependencies_NoPanic(t *testing.T) {
 		&service.OpsAlertEvaluatorService{},
 		&service.OpsCleanupService{},
 		&service.OpsScheduledReportService{},
+		&service.OpsNotificationService{},
 		opsSystemLogSinkSvc,
 		&service.SoraMediaCleanupService{},
 		schedulerSnapshotSvc,
 		tokenRefreshSvc,
 		accountExpirySvc,
 		subscriptionExpirySvc,
+		balanceLedgerSvc,
 		&service.UsageCleanupService{},
+		&service.BatchService{},
 		idempotencyCleanupSvc,
+		loginHistoryCleanupSvc,
+		paymentSvc,
+		referralSvc,
+		activitySvc,
 		pricingSvc,
 		emailQueueSvc,
 		billingCacheSvc,
 		&service.UsageRecordWorkerPool{},
+		&service.UsageJournalService{},
 		&service.SubscriptionService{},
 		oauthSvc,
 		openAIOAuthSvc,
 		geminiOAuthSvc,
 		antigravityOAuthSvc,
 		nil, // openAIGateway
+		&server.MetricsServer{},
 	)
 
 	require.NotPanics(t, func() {
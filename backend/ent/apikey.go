@@ -13,6 +13,7 @@ import (
 	"github.com/Wei-Shaw/sub2api/ent/apikey"
 	"github.com/Wei-Shaw/sub2api/ent/group"
 	"github.com/Wei-Shaw/sub2api/ent/user"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
 )
 
 // APIKey is the model entity for the APIKey schema.
@@ -42,30 +43,42 @@ type APIKey struct {
 	IPWhitelist []string `json:"ip_whitelist,omitempty"`
 	// Blocked IPs/CIDRs
 	IPBlacklist []string `json:"ip_blacklist,omitempty"`
+	// Allowed model glob patterns, e.g. ["claude-*-haiku-*"] (empty = all models)
+	ModelAllowlist []string `json:"model_allowlist,omitempty"`
+	// Denied model glob patterns, checked before the allowlist
+	ModelDenylist []string `json:"model_denylist,omitempty"`
 	// Quota limit in USD for this API key (0 = unlimited)
-	Quota float64 `json:"quota,omitempty"`
+	Quota money.Amount `json:"quota,omitempty"`
 	// Used quota amount in USD
-	QuotaUsed float64 `json:"quota_used,omitempty"`
+	QuotaUsed money.Amount `json:"quota_used,omitempty"`
 	// Expiration time for this API key (null = never expires)
 	ExpiresAt *time.Time `json:"expires_at,omitempty"`
 	// Rate limit in USD per 5 hours (0 = unlimited)
-	RateLimit5h float64 `json:"rate_limit_5h,omitempty"`
+	RateLimit5h money.Amount `json:"rate_limit_5h,omitempty"`
 	// Rate limit in USD per day (0 = unlimited)
-	RateLimit1d float64 `json:"rate_limit_1d,omitempty"`
+	RateLimit1d money.Amount `json:"rate_limit_1d,omitempty"`
 	// Rate limit in USD per 7 days (0 = unlimited)
-	RateLimit7d float64 `json:"rate_limit_7d,omitempty"`
+	RateLimit7d money.Amount `json:"rate_limit_7d,omitempty"`
 	// Used amount in USD for the current 5h window
-	Usage5h float64 `json:"usage_5h,omitempty"`
+	Usage5h money.Amount `json:"usage_5h,omitempty"`
 	// Used amount in USD for the current 1d window
-	Usage1d float64 `json:"usage_1d,omitempty"`
+	Usage1d money.Amount `json:"usage_1d,omitempty"`
 	// Used amount in USD for the current 7d window
-	Usage7d float64 `json:"usage_7d,omitempty"`
+	Usage7d money.Amount `json:"usage_7d,omitempty"`
 	// Start time of the current 5h rate limit window
 	Window5hStart *time.Time `json:"window_5h_start,omitempty"`
 	// Start time of the current 1d rate limit window
 	Window1dStart *time.Time `json:"window_1d_start,omitempty"`
 	// Start time of the current 7d rate limit window
 	Window7dStart *time.Time `json:"window_7d_start,omitempty"`
+	// Max requests per minute for this API key (0 = unlimited)
+	RpmLimit int `json:"rpm_limit,omitempty"`
+	// Max tokens per minute for this API key (0 = unlimited)
+	TpmLimit int `json:"tpm_limit,omitempty"`
+	// Max in-flight requests for this API key (0 = unlimited)
+	MaxConcurrency int `json:"max_concurrency,omitempty"`
+	// Organization member who created this key (null = personal key)
+	CreatedByUserID *int64 `json:"created_by_user_id,omitempty"`
 	// Edges holds the relations/edges for other nodes in the graph.
 	// The values are being populated by the APIKeyQuery when eager-loading is set.
 	Edges        APIKeyEdges `json:"edges"`
@@ -121,11 +134,11 @@ func (*APIKey) scanValues(columns []string) ([]any, error) {
 	values := make([]any, len(columns))
 	for i := range columns {
 		switch columns[i] {
-		case apikey.FieldIPWhitelist, apikey.FieldIPBlacklist:
+		case apikey.FieldIPWhitelist, apikey.FieldIPBlacklist, apikey.FieldModelAllowlist, apikey.FieldModelDenylist:
 			values[i] = new([]byte)
 		case apikey.FieldQuota, apikey.FieldQuotaUsed, apikey.FieldRateLimit5h, apikey.FieldRateLimit1d, apikey.FieldRateLimit7d, apikey.FieldUsage5h, apikey.FieldUsage1d, apikey.FieldUsage7d:
-			values[i] = new(sql.NullFloat64)
-		case apikey.FieldID, apikey.FieldUserID, apikey.FieldGroupID:
+			values[i] = new(money.Amount)
+		case apikey.FieldID, apikey.FieldUserID, apikey.FieldGroupID, apikey.FieldRpmLimit, apikey.FieldTpmLimit, apikey.FieldMaxConcurrency, apikey.FieldCreatedByUserID:
 			values[i] = new(sql.NullInt64)
 		case apikey.FieldKey, apikey.FieldName, apikey.FieldStatus:
 			values[i] = new(sql.NullString)
@@ -225,17 +238,33 @@ func (_m *APIKey) assignValues(columns []string, values []any) error {
 					return fmt.Errorf("unmarshal field ip_blacklist: %w", err)
 				}
 			}
+		case apikey.FieldModelAllowlist:
+			if value, ok := values[i].(*[]byte); !ok {
+				return fmt.Errorf("unexpected type %T for field model_allowlist", values[i])
+			} else if value != nil && len(*value) > 0 {
+				if err := json.Unmarshal(*value, &_m.ModelAllowlist); err != nil {
+					return fmt.Errorf("unmarshal field model_allowlist: %w", err)
+				}
+			}
+		case apikey.FieldModelDenylist:
+			if value, ok := values[i].(*[]byte); !ok {
+				return fmt.Errorf("unexpected type %T for field model_denylist", values[i])
+			} else if value != nil && len(*value) > 0 {
+				if err := json.Unmarshal(*value, &_m.ModelDenylist); err != nil {
+					return fmt.Errorf("unmarshal field model_denylist: %w", err)
+				}
+			}
 		case apikey.FieldQuota:
-			if value, ok := values[i].(*sql.NullFloat64); !ok {
+			if value, ok := values[i].(*money.Amount); !ok {
 				return fmt.Errorf("unexpected type %T for field quota", values[i])
-			} else if value.Valid {
-				_m.Quota = value.Float64
+			} else if value != nil {
+				_m.Quota = *value
 			}
 		case apikey.FieldQuotaUsed:
-			if value, ok := values[i].(*sql.NullFloat64); !ok {
+			if value, ok := values[i].(*money.Amount); !ok {
 				return fmt.Errorf("unexpected type %T for field quota_used", values[i])
-			} else if value.Valid {
-				_m.QuotaUsed = value.Float64
+			} else if value != nil {
+				_m.QuotaUsed = *value
 			}
 		case apikey.FieldExpiresAt:
 			if value, ok := values[i].(*sql.NullTime); !ok {
@@ -245,40 +274,40 @@ func (_m *APIKey) assignValues(columns []string, values []any) error {
 				*_m.ExpiresAt = value.Time
 			}
 		case apikey.FieldRateLimit5h:
-			if value, ok := values[i].(*sql.NullFloat64); !ok {
+			if value, ok := values[i].(*money.Amount); !ok {
 				return fmt.Errorf("unexpected type %T for field rate_limit_5h", values[i])
-			} else if value.Valid {
-				_m.RateLimit5h = value.Float64
+			} else if value != nil {
+				_m.RateLimit5h = *value
 			}
 		case apikey.FieldRateLimit1d:
-			if value, ok := values[i].(*sql.NullFloat64); !ok {
+			if value, ok := values[i].(*money.Amount); !ok {
 				return fmt.Errorf("unexpected type %T for field rate_limit_1d", values[i])
-			} else if value.Valid {
-				_m.RateLimit1d = value.Float64
+			} else if value != nil {
+				_m.RateLimit1d = *value
 			}
 		case apikey.FieldRateLimit7d:
-			if value, ok := values[i].(*sql.NullFloat64); !ok {
+			if value, ok := values[i].(*money.Amount); !ok {
 				return fmt.Errorf("unexpected type %T for field rate_limit_7d", values[i])
-			} else if value.Valid {
-				_m.RateLimit7d = value.Float64
+			} else if value != nil {
+				_m.RateLimit7d = *value
 			}
 		case apikey.FieldUsage5h:
-			if value, ok := values[i].(*sql.NullFloat64); !ok {
+			if value, ok := values[i].(*money.Amount); !ok {
 				return fmt.Errorf("unexpected type %T for field usage_5h", values[i])
-			} else if value.Valid {
-				_m.Usage5h = value.Float64
+			} else if value != nil {
+				_m.Usage5h = *value
 			}
 		case apikey.FieldUsage1d:
-			if value, ok := values[i].(*sql.NullFloat64); !ok {
+			if value, ok := values[i].(*money.Amount); !ok {
 				return fmt.Errorf("unexpected type %T for field usage_1d", values[i])
-			} else if value.Valid {
-				_m.Usage1d = value.Float64
+			} else if value != nil {
+				_m.Usage1d = *value
 			}
 		case apikey.FieldUsage7d:
-			if value, ok := values[i].(*sql.NullFloat64); !ok {
+			if value, ok := values[i].(*money.Amount); !ok {
 				return fmt.Errorf("unexpected type %T for field usage_7d", values[i])
-			} else if value.Valid {
-				_m.Usage7d = value.Float64
+			} else if value != nil {
+				_m.Usage7d = *value
 			}
 		case apikey.FieldWindow5hStart:
 			if value, ok := values[i].(*sql.NullTime); !ok {
@@ -301,6 +330,31 @@ func (_m *APIKey) assignValues(columns []string, values []any) error {
 				_m.Window7dStart = new(time.Time)
 				*_m.Window7dStart = value.Time
 			}
+		case apikey.FieldRpmLimit:
+			if value, ok := values[i].(*sql.NullInt64); !ok {
+				return fmt.Errorf("unexpected type %T for field rpm_limit", values[i])
+			} else if value.Valid {
+				_m.RpmLimit = int(value.Int64)
+			}
+		case apikey.FieldTpmLimit:
+			if value, ok := values[i].(*sql.NullInt64); !ok {
+				return fmt.Errorf("unexpected type %T for field tpm_limit", values[i])
+			} else if value.Valid {
+				_m.TpmLimit = int(value.Int64)
+			}
+		case apikey.FieldMaxConcurrency:
+			if value, ok := values[i].(*sql.NullInt64); !ok {
+				return fmt.Errorf("unexpected type %T for field max_concurrency", values[i])
+			} else if value.Valid {
+				_m.MaxConcurrency = int(value.Int64)
+			}
+		case apikey.FieldCreatedByUserID:
+			if value, ok := values[i].(*sql.NullInt64); !ok {
+				return fmt.Errorf("unexpected type %T for field created_by_user_id", values[i])
+			} else if value.Valid {
+				_m.CreatedByUserID = new(int64)
+				*_m.CreatedByUserID = value.Int64
+			}
 		default:
 			_m.selectValues.Set(columns[i], values[i])
 		}
@@ -391,6 +445,12 @@ func (_m *APIKey) String() string {
 	builder.WriteString("ip_blacklist=")
 	builder.WriteString(fmt.Sprintf("%v", _m.IPBlacklist))
 	builder.WriteString(", ")
+	builder.WriteString("model_allowlist=")
+	builder.WriteString(fmt.Sprintf("%v", _m.ModelAllowlist))
+	builder.WriteString(", ")
+	builder.WriteString("model_denylist=")
+	builder.WriteString(fmt.Sprintf("%v", _m.ModelDenylist))
+	builder.WriteString(", ")
 	builder.WriteString("quota=")
 	builder.WriteString(fmt.Sprintf("%v", _m.Quota))
 	builder.WriteString(", ")
@@ -434,6 +494,20 @@ func (_m *APIKey) String() string {
 		builder.WriteString("window_7d_start=")
 		builder.WriteString(v.Format(time.ANSIC))
 	}
+	builder.WriteString(", ")
+	builder.WriteString("rpm_limit=")
+	builder.WriteString(fmt.Sprintf("%v", _m.RpmLimit))
+	builder.WriteString(", ")
+	builder.WriteString("tpm_limit=")
+	builder.WriteString(fmt.Sprintf("%v", _m.TpmLimit))
+	builder.WriteString(", ")
+	builder.WriteString("max_concurrency=")
+	builder.WriteString(fmt.Sprintf("%v", _m.MaxConcurrency))
+	builder.WriteString(", ")
+	if v := _m.CreatedByUserID; v != nil {
+		builder.WriteString("created_by_user_id=")
+		builder.WriteString(fmt.Sprintf("%v", *v))
+	}
 	builder.WriteByte(')')
 	return builder.String()
 }
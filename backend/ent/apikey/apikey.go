@@ -8,6 +8,7 @@ import (
 	"entgo.io/ent"
 	"entgo.io/ent/dialect/sql"
 	"entgo.io/ent/dialect/sql/sqlgraph"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
 )
 
 const (
@@ -37,6 +38,10 @@ const (
 	FieldIPWhitelist = "ip_whitelist"
 	// FieldIPBlacklist holds the string denoting the ip_blacklist field in the database.
 	FieldIPBlacklist = "ip_blacklist"
+	// FieldModelAllowlist holds the string denoting the model_allowlist field in the database.
+	FieldModelAllowlist = "model_allowlist"
+	// FieldModelDenylist holds the string denoting the model_denylist field in the database.
+	FieldModelDenylist = "model_denylist"
 	// FieldQuota holds the string denoting the quota field in the database.
 	FieldQuota = "quota"
 	// FieldQuotaUsed holds the string denoting the quota_used field in the database.
@@ -61,6 +66,14 @@ const (
 	FieldWindow1dStart = "window_1d_start"
 	// FieldWindow7dStart holds the string denoting the window_7d_start field in the database.
 	FieldWindow7dStart = "window_7d_start"
+	// FieldRpmLimit holds the string denoting the rpm_limit field in the database.
+	FieldRpmLimit = "rpm_limit"
+	// FieldTpmLimit holds the string denoting the tpm_limit field in the database.
+	FieldTpmLimit = "tpm_limit"
+	// FieldMaxConcurrency holds the string denoting the max_concurrency field in the database.
+	FieldMaxConcurrency = "max_concurrency"
+	// FieldCreatedByUserID holds the string denoting the created_by_user_id field in the database.
+	FieldCreatedByUserID = "created_by_user_id"
 	// EdgeUser holds the string denoting the user edge name in mutations.
 	EdgeUser = "user"
 	// EdgeGroup holds the string denoting the group edge name in mutations.
@@ -106,6 +119,8 @@ var Columns = []string{
 	FieldLastUsedAt,
 	FieldIPWhitelist,
 	FieldIPBlacklist,
+	FieldModelAllowlist,
+	FieldModelDenylist,
 	FieldQuota,
 	FieldQuotaUsed,
 	FieldExpiresAt,
@@ -118,6 +133,10 @@ var Columns = []string{
 	FieldWindow5hStart,
 	FieldWindow1dStart,
 	FieldWindow7dStart,
+	FieldRpmLimit,
+	FieldTpmLimit,
+	FieldMaxConcurrency,
+	FieldCreatedByUserID,
 }
 
 // ValidColumn reports if the column name is valid (part of the table columns).
@@ -153,21 +172,27 @@ var (
 	// StatusValidator is a validator for the "status" field. It is called by the builders before save.
 	StatusValidator func(string) error
 	// DefaultQuota holds the default value on creation for the "quota" field.
-	DefaultQuota float64
+	DefaultQuota money.Amount
 	// DefaultQuotaUsed holds the default value on creation for the "quota_used" field.
-	DefaultQuotaUsed float64
+	DefaultQuotaUsed money.Amount
 	// DefaultRateLimit5h holds the default value on creation for the "rate_limit_5h" field.
-	DefaultRateLimit5h float64
+	DefaultRateLimit5h money.Amount
 	// DefaultRateLimit1d holds the default value on creation for the "rate_limit_1d" field.
-	DefaultRateLimit1d float64
+	DefaultRateLimit1d money.Amount
 	// DefaultRateLimit7d holds the default value on creation for the "rate_limit_7d" field.
-	DefaultRateLimit7d float64
+	DefaultRateLimit7d money.Amount
 	// DefaultUsage5h holds the default value on creation for the "usage_5h" field.
-	DefaultUsage5h float64
+	DefaultUsage5h money.Amount
 	// DefaultUsage1d holds the default value on creation for the "usage_1d" field.
-	DefaultUsage1d float64
+	DefaultUsage1d money.Amount
 	// DefaultUsage7d holds the default value on creation for the "usage_7d" field.
-	DefaultUsage7d float64
+	DefaultUsage7d money.Amount
+	// DefaultRpmLimit holds the default value on creation for the "rpm_limit" field.
+	DefaultRpmLimit int
+	// DefaultTpmLimit holds the default value on creation for the "tpm_limit" field.
+	DefaultTpmLimit int
+	// DefaultMaxConcurrency holds the default value on creation for the "max_concurrency" field.
+	DefaultMaxConcurrency int
 )
 
 // OrderOption defines the ordering options for the APIKey queries.
@@ -283,6 +308,26 @@ func ByWindow7dStart(opts ...sql.OrderTermOption) OrderOption {
 	return sql.OrderByField(FieldWindow7dStart, opts...).ToFunc()
 }
 
+// ByRpmLimit orders the results by the rpm_limit field.
+func ByRpmLimit(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldRpmLimit, opts...).ToFunc()
+}
+
+// ByTpmLimit orders the results by the tpm_limit field.
+func ByTpmLimit(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldTpmLimit, opts...).ToFunc()
+}
+
+// ByMaxConcurrency orders the results by the max_concurrency field.
+func ByMaxConcurrency(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldMaxConcurrency, opts...).ToFunc()
+}
+
+// ByCreatedByUserID orders the results by the created_by_user_id field.
+func ByCreatedByUserID(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldCreatedByUserID, opts...).ToFunc()
+}
+
 // ByUserField orders the results by user field.
 func ByUserField(field string, opts ...sql.OrderTermOption) OrderOption {
 	return func(s *sql.Selector) {
@@ -8,6 +8,7 @@ import (
 	"entgo.io/ent/dialect/sql"
 	"entgo.io/ent/dialect/sql/sqlgraph"
 	"github.com/Wei-Shaw/sub2api/ent/predicate"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
 )
 
 // ID filters vertices based on their ID field.
@@ -101,12 +102,12 @@ func LastUsedAt(v time.Time) predicate.APIKey {
 }
 
 // Quota applies equality check predicate on the "quota" field. It's identical to QuotaEQ.
-func Quota(v float64) predicate.APIKey {
+func Quota(v money.Amount) predicate.APIKey {
 	return predicate.APIKey(sql.FieldEQ(FieldQuota, v))
 }
 
 // QuotaUsed applies equality check predicate on the "quota_used" field. It's identical to QuotaUsedEQ.
-func QuotaUsed(v float64) predicate.APIKey {
+func QuotaUsed(v money.Amount) predicate.APIKey {
 	return predicate.APIKey(sql.FieldEQ(FieldQuotaUsed, v))
 }
 
@@ -116,32 +117,32 @@ func ExpiresAt(v time.Time) predicate.APIKey {
 }
 
 // RateLimit5h applies equality check predicate on the "rate_limit_5h" field. It's identical to RateLimit5hEQ.
-func RateLimit5h(v float64) predicate.APIKey {
+func RateLimit5h(v money.Amount) predicate.APIKey {
 	return predicate.APIKey(sql.FieldEQ(FieldRateLimit5h, v))
 }
 
 // RateLimit1d applies equality check predicate on the "rate_limit_1d" field. It's identical to RateLimit1dEQ.
-func RateLimit1d(v float64) predicate.APIKey {
+func RateLimit1d(v money.Amount) predicate.APIKey {
 	return predicate.APIKey(sql.FieldEQ(FieldRateLimit1d, v))
 }
 
 // RateLimit7d applies equality check predicate on the "rate_limit_7d" field. It's identical to RateLimit7dEQ.
-func RateLimit7d(v float64) predicate.APIKey {
+func RateLimit7d(v money.Amount) predicate.APIKey {
 	return predicate.APIKey(sql.FieldEQ(FieldRateLimit7d, v))
 }
 
 // Usage5h applies equality check predicate on the "usage_5h" field. It's identical to Usage5hEQ.
-func Usage5h(v float64) predicate.APIKey {
+func Usage5h(v money.Amount) predicate.APIKey {
 	return predicate.APIKey(sql.FieldEQ(FieldUsage5h, v))
 }
 
 // Usage1d applies equality check predicate on the "usage_1d" field. It's identical to Usage1dEQ.
-func Usage1d(v float64) predicate.APIKey {
+func Usage1d(v money.Amount) predicate.APIKey {
 	return predicate.APIKey(sql.FieldEQ(FieldUsage1d, v))
 }
 
 // Usage7d applies equality check predicate on the "usage_7d" field. It's identical to Usage7dEQ.
-func Usage7d(v float64) predicate.APIKey {
+func Usage7d(v money.Amount) predicate.APIKey {
 	return predicate.APIKey(sql.FieldEQ(FieldUsage7d, v))
 }
 
@@ -160,6 +161,26 @@ func Window7dStart(v time.Time) predicate.APIKey {
 	return predicate.APIKey(sql.FieldEQ(FieldWindow7dStart, v))
 }
 
+// RpmLimit applies equality check predicate on the "rpm_limit" field. It's identical to RpmLimitEQ.
+func RpmLimit(v int) predicate.APIKey {
+	return predicate.APIKey(sql.FieldEQ(FieldRpmLimit, v))
+}
+
+// TpmLimit applies equality check predicate on the "tpm_limit" field. It's identical to TpmLimitEQ.
+func TpmLimit(v int) predicate.APIKey {
+	return predicate.APIKey(sql.FieldEQ(FieldTpmLimit, v))
+}
+
+// MaxConcurrency applies equality check predicate on the "max_concurrency" field. It's identical to MaxConcurrencyEQ.
+func MaxConcurrency(v int) predicate.APIKey {
+	return predicate.APIKey(sql.FieldEQ(FieldMaxConcurrency, v))
+}
+
+// CreatedByUserID applies equality check predicate on the "created_by_user_id" field. It's identical to CreatedByUserIDEQ.
+func CreatedByUserID(v int64) predicate.APIKey {
+	return predicate.APIKey(sql.FieldEQ(FieldCreatedByUserID, v))
+}
+
 // CreatedAtEQ applies the EQ predicate on the "created_at" field.
 func CreatedAtEQ(v time.Time) predicate.APIKey {
 	return predicate.APIKey(sql.FieldEQ(FieldCreatedAt, v))
@@ -605,83 +626,103 @@ func IPBlacklistNotNil() predicate.APIKey {
 	return predicate.APIKey(sql.FieldNotNull(FieldIPBlacklist))
 }
 
+// ModelAllowlistIsNil applies the IsNil predicate on the "model_allowlist" field.
+func ModelAllowlistIsNil() predicate.APIKey {
+	return predicate.APIKey(sql.FieldIsNull(FieldModelAllowlist))
+}
+
+// ModelAllowlistNotNil applies the NotNil predicate on the "model_allowlist" field.
+func ModelAllowlistNotNil() predicate.APIKey {
+	return predicate.APIKey(sql.FieldNotNull(FieldModelAllowlist))
+}
+
+// ModelDenylistIsNil applies the IsNil predicate on the "model_denylist" field.
+func ModelDenylistIsNil() predicate.APIKey {
+	return predicate.APIKey(sql.FieldIsNull(FieldModelDenylist))
+}
+
+// ModelDenylistNotNil applies the NotNil predicate on the "model_denylist" field.
+func ModelDenylistNotNil() predicate.APIKey {
+	return predicate.APIKey(sql.FieldNotNull(FieldModelDenylist))
+}
+
 // QuotaEQ applies the EQ predicate on the "quota" field.
-func QuotaEQ(v float64) predicate.APIKey {
+func QuotaEQ(v money.Amount) predicate.APIKey {
 	return predicate.APIKey(sql.FieldEQ(FieldQuota, v))
 }
 
 // QuotaNEQ applies the NEQ predicate on the "quota" field.
-func QuotaNEQ(v float64) predicate.APIKey {
+func QuotaNEQ(v money.Amount) predicate.APIKey {
 	return predicate.APIKey(sql.FieldNEQ(FieldQuota, v))
 }
 
 // QuotaIn applies the In predicate on the "quota" field.
-func QuotaIn(vs ...float64) predicate.APIKey {
+func QuotaIn(vs ...money.Amount) predicate.APIKey {
 	return predicate.APIKey(sql.FieldIn(FieldQuota, vs...))
 }
 
 // QuotaNotIn applies the NotIn predicate on the "quota" field.
-func QuotaNotIn(vs ...float64) predicate.APIKey {
+func QuotaNotIn(vs ...money.Amount) predicate.APIKey {
 	return predicate.APIKey(sql.FieldNotIn(FieldQuota, vs...))
 }
 
 // QuotaGT applies the GT predicate on the "quota" field.
-func QuotaGT(v float64) predicate.APIKey {
+func QuotaGT(v money.Amount) predicate.APIKey {
 	return predicate.APIKey(sql.FieldGT(FieldQuota, v))
 }
 
 // QuotaGTE applies the GTE predicate on the "quota" field.
-func QuotaGTE(v float64) predicate.APIKey {
+func QuotaGTE(v money.Amount) predicate.APIKey {
 	return predicate.APIKey(sql.FieldGTE(FieldQuota, v))
 }
 
 // QuotaLT applies the LT predicate on the "quota" field.
-func QuotaLT(v float64) predicate.APIKey {
+func QuotaLT(v money.Amount) predicate.APIKey {
 	return predicate.APIKey(sql.FieldLT(FieldQuota, v))
 }
 
 // QuotaLTE applies the LTE predicate on the "quota" field.
-func QuotaLTE(v float64) predicate.APIKey {
+func QuotaLTE(v money.Amount) predicate.APIKey {
 	return predicate.APIKey(sql.FieldLTE(FieldQuota, v))
 }
 
 // QuotaUsedEQ applies the EQ predicate on the "quota_used" field.
-func QuotaUsedEQ(v float64) predicate.APIKey {
+func QuotaUsedEQ(v money.Amount) predicate.APIKey {
 	return predicate.APIKey(sql.FieldEQ(FieldQuotaUsed, v))
 }
 
 // QuotaUsedNEQ applies the NEQ predicate on the "quota_used" field.
-func QuotaUsedNEQ(v float64) predicate.APIKey {
+func QuotaUsedNEQ(v money.Amount) predicate.APIKey {
 	return predicate.APIKey(sql.FieldNEQ(FieldQuotaUsed, v))
 }
 
 // QuotaUsedIn applies the In predicate on the "quota_used" field.
-func QuotaUsedIn(vs ...float64) predicate.APIKey {
+func QuotaUsedIn(vs ...money.Amount) predicate.APIKey {
 	return predicate.APIKey(sql.FieldIn(FieldQuotaUsed, vs...))
 }
 
 // QuotaUsedNotIn applies the NotIn predicate on the "quota_used" field.
-func QuotaUsedNotIn(vs ...float64) predicate.APIKey {
+func QuotaUsedNotIn(vs ...money.Amount) predicate.APIKey {
 	return predicate.APIKey(sql.FieldNotIn(FieldQuotaUsed, vs...))
 }
 
 // QuotaUsedGT applies the GT predicate on the "quota_used" field.
-func QuotaUsedGT(v float64) predicate.APIKey {
+func QuotaUsedGT(v money.Amount) predicate.APIKey {
 	return predicate.APIKey(sql.FieldGT(FieldQuotaUsed, v))
 }
 
 // QuotaUsedGTE applies the GTE predicate on the "quota_used" field.
-func QuotaUsedGTE(v float64) predicate.APIKey {
+func QuotaUsedGTE(v money.Amount) predicate.APIKey {
 	return predicate.APIKey(sql.FieldGTE(FieldQuotaUsed, v))
 }
 
 // QuotaUsedLT applies the LT predicate on the "quota_used" field.
-func QuotaUsedLT(v float64) predicate.APIKey {
+func QuotaUsedLT(v money.Amount) predicate.APIKey {
 	return predicate.APIKey(sql.FieldLT(FieldQuotaUsed, v))
 }
 
 // QuotaUsedLTE applies the LTE predicate on the "quota_used" field.
-func QuotaUsedLTE(v float64) predicate.APIKey {
+func QuotaUsedLTE(v money.Amount) predicate.APIKey {
 	return predicate.APIKey(sql.FieldLTE(FieldQuotaUsed, v))
 }
 
@@ -736,242 +777,242 @@ func ExpiresAtNotNil() predicate.APIKey {
 }
 
 // RateLimit5hEQ applies the EQ predicate on the "rate_limit_5h" field.
-func RateLimit5hEQ(v float64) predicate.APIKey {
+func RateLimit5hEQ(v money.Amount) predicate.APIKey {
 	return predicate.APIKey(sql.FieldEQ(FieldRateLimit5h, v))
 }
 
 // RateLimit5hNEQ applies the NEQ predicate on the "rate_limit_5h" field.
-func RateLimit5hNEQ(v float64) predicate.APIKey {
+func RateLimit5hNEQ(v money.Amount) predicate.APIKey {
 	return predicate.APIKey(sql.FieldNEQ(FieldRateLimit5h, v))
 }
 
 // RateLimit5hIn applies the In predicate on the "rate_limit_5h" field.
-func RateLimit5hIn(vs ...float64) predicate.APIKey {
+func RateLimit5hIn(vs ...money.Amount) predicate.APIKey {
 	return predicate.APIKey(sql.FieldIn(FieldRateLimit5h, vs...))
 }
 
 // RateLimit5hNotIn applies the NotIn predicate on the "rate_limit_5h" field.
-func RateLimit5hNotIn(vs ...float64) predicate.APIKey {
+func RateLimit5hNotIn(vs ...money.Amount) predicate.APIKey {
 	return predicate.APIKey(sql.FieldNotIn(FieldRateLimit5h, vs...))
 }
 
 // RateLimit5hGT applies the GT predicate on the "rate_limit_5h" field.
-func RateLimit5hGT(v float64) predicate.APIKey {
+func RateLimit5hGT(v money.Amount) predicate.APIKey {
 	return predicate.APIKey(sql.FieldGT(FieldRateLimit5h, v))
 }
 
 // RateLimit5hGTE applies the GTE predicate on the "rate_limit_5h" field.
-func RateLimit5hGTE(v float64) predicate.APIKey {
+func RateLimit5hGTE(v money.Amount) predicate.APIKey {
 	return predicate.APIKey(sql.FieldGTE(FieldRateLimit5h, v))
 }
 
 // RateLimit5hLT applies the LT predicate on the "rate_limit_5h" field.
-func RateLimit5hLT(v float64) predicate.APIKey {
+func RateLimit5hLT(v money.Amount) predicate.APIKey {
 	return predicate.APIKey(sql.FieldLT(FieldRateLimit5h, v))
 }
 
 // RateLimit5hLTE applies the LTE predicate on the "rate_limit_5h" field.
-func RateLimit5hLTE(v float64) predicate.APIKey {
+func RateLimit5hLTE(v money.Amount) predicate.APIKey {
 	return predicate.APIKey(sql.FieldLTE(FieldRateLimit5h, v))
 }
 
 // RateLimit1dEQ applies the EQ predicate on the "rate_limit_1d" field.
-func RateLimit1dEQ(v float64) predicate.APIKey {
+func RateLimit1dEQ(v money.Amount) predicate.APIKey {
 	return predicate.APIKey(sql.FieldEQ(FieldRateLimit1d, v))
 }
 
 // RateLimit1dNEQ applies the NEQ predicate on the "rate_limit_1d" field.
-func RateLimit1dNEQ(v float64) predicate.APIKey {
+func RateLimit1dNEQ(v money.Amount) predicate.APIKey {
 	return predicate.APIKey(sql.FieldNEQ(FieldRateLimit1d, v))
 }
 
 // RateLimit1dIn applies the In predicate on the "rate_limit_1d" field.
-func RateLimit1dIn(vs ...float64) predicate.APIKey {
+func RateLimit1dIn(vs ...money.Amount) predicate.APIKey {
 	return predicate.APIKey(sql.FieldIn(FieldRateLimit1d, vs...))
 }
 
 // RateLimit1dNotIn applies the NotIn predicate on the "rate_limit_1d" field.
-func RateLimit1dNotIn(vs ...float64) predicate.APIKey {
+func RateLimit1dNotIn(vs ...money.Amount) predicate.APIKey {
 	return predicate.APIKey(sql.FieldNotIn(FieldRateLimit1d, vs...))
 }
 
 // RateLimit1dGT applies the GT predicate on the "rate_limit_1d" field.
-func RateLimit1dGT(v float64) predicate.APIKey {
+func RateLimit1dGT(v money.Amount) predicate.APIKey {
 	return predicate.APIKey(sql.FieldGT(FieldRateLimit1d, v))
 }
 
 // RateLimit1dGTE applies the GTE predicate on the "rate_limit_1d" field.
-func RateLimit1dGTE(v float64) predicate.APIKey {
+func RateLimit1dGTE(v money.Amount) predicate.APIKey {
 	return predicate.APIKey(sql.FieldGTE(FieldRateLimit1d, v))
 }
 
 // RateLimit1dLT applies the LT predicate on the "rate_limit_1d" field.
-func RateLimit1dLT(v float64) predicate.APIKey {
+func RateLimit1dLT(v money.Amount) predicate.APIKey {
 	return predicate.APIKey(sql.FieldLT(FieldRateLimit1d, v))
 }
 
 // RateLimit1dLTE applies the LTE predicate on the "rate_limit_1d" field.
-func RateLimit1dLTE(v float64) predicate.APIKey {
+func RateLimit1dLTE(v money.Amount) predicate.APIKey {
 	return predicate.APIKey(sql.FieldLTE(FieldRateLimit1d, v))
 }
 
 // RateLimit7dEQ applies the EQ predicate on the "rate_limit_7d" field.
-func RateLimit7dEQ(v float64) predicate.APIKey {
+func RateLimit7dEQ(v money.Amount) predicate.APIKey {
 	return predicate.APIKey(sql.FieldEQ(FieldRateLimit7d, v))
 }
 
 // RateLimit7dNEQ applies the NEQ predicate on the "rate_limit_7d" field.
-func RateLimit7dNEQ(v float64) predicate.APIKey {
+func RateLimit7dNEQ(v money.Amount) predicate.APIKey {
 	return predicate.APIKey(sql.FieldNEQ(FieldRateLimit7d, v))
 }
 
 // RateLimit7dIn applies the In predicate on the "rate_limit_7d" field.
-func RateLimit7dIn(vs ...float64) predicate.APIKey {
+func RateLimit7dIn(vs ...money.Amount) predicate.APIKey {
 	return predicate.APIKey(sql.FieldIn(FieldRateLimit7d, vs...))
 }
 
 // RateLimit7dNotIn applies the NotIn predicate on the "rate_limit_7d" field.
-func RateLimit7dNotIn(vs ...float64) predicate.APIKey {
+func RateLimit7dNotIn(vs ...money.Amount) predicate.APIKey {
 	return predicate.APIKey(sql.FieldNotIn(FieldRateLimit7d, vs...))
 }
 
 // RateLimit7dGT applies the GT predicate on the "rate_limit_7d" field.
-func RateLimit7dGT(v float64) predicate.APIKey {
+func RateLimit7dGT(v money.Amount) predicate.APIKey {
 	return predicate.APIKey(sql.FieldGT(FieldRateLimit7d, v))
 }
 
 // RateLimit7dGTE applies the GTE predicate on the "rate_limit_7d" field.
-func RateLimit7dGTE(v float64) predicate.APIKey {
+func RateLimit7dGTE(v money.Amount) predicate.APIKey {
 	return predicate.APIKey(sql.FieldGTE(FieldRateLimit7d, v))
 }
 
 // RateLimit7dLT applies the LT predicate on the "rate_limit_7d" field.
-func RateLimit7dLT(v float64) predicate.APIKey {
+func RateLimit7dLT(v money.Amount) predicate.APIKey {
 	return predicate.APIKey(sql.FieldLT(FieldRateLimit7d, v))
 }
 
 // RateLimit7dLTE applies the LTE predicate on the "rate_limit_7d" field.
-func RateLimit7dLTE(v float64) predicate.APIKey {
+func RateLimit7dLTE(v money.Amount) predicate.APIKey {
 	return predicate.APIKey(sql.FieldLTE(FieldRateLimit7d, v))
 }
 
 // Usage5hEQ applies the EQ predicate on the "usage_5h" field.
-func Usage5hEQ(v float64) predicate.APIKey {
+func Usage5hEQ(v money.Amount) predicate.APIKey {
 	return predicate.APIKey(sql.FieldEQ(FieldUsage5h, v))
 }
 
 // Usage5hNEQ applies the NEQ predicate on the "usage_5h" field.
-func Usage5hNEQ(v float64) predicate.APIKey {
+func Usage5hNEQ(v money.Amount) predicate.APIKey {
 	return predicate.APIKey(sql.FieldNEQ(FieldUsage5h, v))
 }
 
 // Usage5hIn applies the In predicate on the "usage_5h" field.
-func Usage5hIn(vs ...float64) predicate.APIKey {
+func Usage5hIn(vs ...money.Amount) predicate.APIKey {
 	return predicate.APIKey(sql.FieldIn(FieldUsage5h, vs...))
 }
 
 // Usage5hNotIn applies the NotIn predicate on the "usage_5h" field.
-func Usage5hNotIn(vs ...float64) predicate.APIKey {
+func Usage5hNotIn(vs ...money.Amount) predicate.APIKey {
 	return predicate.APIKey(sql.FieldNotIn(FieldUsage5h, vs...))
 }
 
 // Usage5hGT applies the GT predicate on the "usage_5h" field.
-func Usage5hGT(v float64) predicate.APIKey {
+func Usage5hGT(v money.Amount) predicate.APIKey {
 	return predicate.APIKey(sql.FieldGT(FieldUsage5h, v))
 }
 
 // Usage5hGTE applies the GTE predicate on the "usage_5h" field.
-func Usage5hGTE(v float64) predicate.APIKey {
+func Usage5hGTE(v money.Amount) predicate.APIKey {
 	return predicate.APIKey(sql.FieldGTE(FieldUsage5h, v))
 }
 
 // Usage5hLT applies the LT predicate on the "usage_5h" field.
-func Usage5hLT(v float64) predicate.APIKey {
+func Usage5hLT(v money.Amount) predicate.APIKey {
 	return predicate.APIKey(sql.FieldLT(FieldUsage5h, v))
 }
 
 // Usage5hLTE applies the LTE predicate on the "usage_5h" field.
-func Usage5hLTE(v float64) predicate.APIKey {
+func Usage5hLTE(v money.Amount) predicate.APIKey {
 	return predicate.APIKey(sql.FieldLTE(FieldUsage5h, v))
 }
 
 // Usage1dEQ applies the EQ predicate on the "usage_1d" field.
-func Usage1dEQ(v float64) predicate.APIKey {
+func Usage1dEQ(v money.Amount) predicate.APIKey {
 	return predicate.APIKey(sql.FieldEQ(FieldUsage1d, v))
 }
 
 // Usage1dNEQ applies the NEQ predicate on the "usage_1d" field.
-func Usage1dNEQ(v float64) predicate.APIKey {
+func Usage1dNEQ(v money.Amount) predicate.APIKey {
 	return predicate.APIKey(sql.FieldNEQ(FieldUsage1d, v))
 }
 
 // Usage1dIn applies the In predicate on the "usage_1d" field.
-func Usage1dIn(vs ...float64) predicate.APIKey {
+func Usage1dIn(vs ...money.Amount) predicate.APIKey {
 	return predicate.APIKey(sql.FieldIn(FieldUsage1d, vs...))
 }
 
 // Usage1dNotIn applies the NotIn predicate on the "usage_1d" field.
-func Usage1dNotIn(vs ...float64) predicate.APIKey {
+func Usage1dNotIn(vs ...money.Amount) predicate.APIKey {
 	return predicate.APIKey(sql.FieldNotIn(FieldUsage1d, vs...))
 }
 
 // Usage1dGT applies the GT predicate on the "usage_1d" field.
-func Usage1dGT(v float64) predicate.APIKey {
+func Usage1dGT(v money.Amount) predicate.APIKey {
 	return predicate.APIKey(sql.FieldGT(FieldUsage1d, v))
 }
 
 // Usage1dGTE applies the GTE predicate on the "usage_1d" field.
-func Usage1dGTE(v float64) predicate.APIKey {
+func Usage1dGTE(v money.Amount) predicate.APIKey {
 	return predicate.APIKey(sql.FieldGTE(FieldUsage1d, v))
 }
 
 // Usage1dLT applies the LT predicate on the "usage_1d" field.
-func Usage1dLT(v float64) predicate.APIKey {
+func Usage1dLT(v money.Amount) predicate.APIKey {
 	return predicate.APIKey(sql.FieldLT(FieldUsage1d, v))
 }
 
 // Usage1dLTE applies the LTE predicate on the "usage_1d" field.
-func Usage1dLTE(v float64) predicate.APIKey {
+func Usage1dLTE(v money.Amount) predicate.APIKey {
 	return predicate.APIKey(sql.FieldLTE(FieldUsage1d, v))
 }
 
 // Usage7dEQ applies the EQ predicate on the "usage_7d" field.
-func Usage7dEQ(v float64) predicate.APIKey {
+func Usage7dEQ(v money.Amount) predicate.APIKey {
 	return predicate.APIKey(sql.FieldEQ(FieldUsage7d, v))
 }
 
 // Usage7dNEQ applies the NEQ predicate on the "usage_7d" field.
-func Usage7dNEQ(v float64) predicate.APIKey {
+func Usage7dNEQ(v money.Amount) predicate.APIKey {
 	return predicate.APIKey(sql.FieldNEQ(FieldUsage7d, v))
 }
 
 // Usage7dIn applies the In predicate on the "usage_7d" field.
-func Usage7dIn(vs ...float64) predicate.APIKey {
+func Usage7dIn(vs ...money.Amount) predicate.APIKey {
 	return predicate.APIKey(sql.FieldIn(FieldUsage7d, vs...))
 }
 
 // Usage7dNotIn applies the NotIn predicate on the "usage_7d" field.
-func Usage7dNotIn(vs ...float64) predicate.APIKey {
+func Usage7dNotIn(vs ...money.Amount) predicate.APIKey {
 	return predicate.APIKey(sql.FieldNotIn(FieldUsage7d, vs...))
 }
 
 // Usage7dGT applies the GT predicate on the "usage_7d" field.
-func Usage7dGT(v float64) predicate.APIKey {
+func Usage7dGT(v money.Amount) predicate.APIKey {
 	return predicate.APIKey(sql.FieldGT(FieldUsage7d, v))
 }
 
 // Usage7dGTE applies the GTE predicate on the "usage_7d" field.
-func Usage7dGTE(v float64) predicate.APIKey {
+func Usage7dGTE(v money.Amount) predicate.APIKey {
 	return predicate.APIKey(sql.FieldGTE(FieldUsage7d, v))
 }
 
 // Usage7dLT applies the LT predicate on the "usage_7d" field.
-func Usage7dLT(v float64) predicate.APIKey {
+func Usage7dLT(v money.Amount) predicate.APIKey {
 	return predicate.APIKey(sql.FieldLT(FieldUsage7d, v))
 }
 
 // Usage7dLTE applies the LTE predicate on the "usage_7d" field.
-func Usage7dLTE(v float64) predicate.APIKey {
+func Usage7dLTE(v money.Amount) predicate.APIKey {
 	return predicate.APIKey(sql.FieldLTE(FieldUsage7d, v))
 }
 
@@ -1125,6 +1166,176 @@ func Window7dStartNotNil() predicate.APIKey {
 	return predicate.APIKey(sql.FieldNotNull(FieldWindow7dStart))
 }
 
+// RpmLimitEQ applies the EQ predicate on the "rpm_limit" field.
+func RpmLimitEQ(v int) predicate.APIKey {
+	return predicate.APIKey(sql.FieldEQ(FieldRpmLimit, v))
+}
+
+// RpmLimitNEQ applies the NEQ predicate on the "rpm_limit" field.
+func RpmLimitNEQ(v int) predicate.APIKey {
+	return predicate.APIKey(sql.FieldNEQ(FieldRpmLimit, v))
+}
+
+// RpmLimitIn applies the In predicate on the "rpm_limit" field.
+func RpmLimitIn(vs ...int) predicate.APIKey {
+	return predicate.APIKey(sql.FieldIn(FieldRpmLimit, vs...))
+}
+
+// RpmLimitNotIn applies the NotIn predicate on the "rpm_limit" field.
+func RpmLimitNotIn(vs ...int) predicate.APIKey {
+	return predicate.APIKey(sql.FieldNotIn(FieldRpmLimit, vs...))
+}
+
+// RpmLimitGT applies the GT predicate on the "rpm_limit" field.
+func RpmLimitGT(v int) predicate.APIKey {
+	return predicate.APIKey(sql.FieldGT(FieldRpmLimit, v))
+}
+
+// RpmLimitGTE applies the GTE predicate on the "rpm_limit" field.
+func RpmLimitGTE(v int) predicate.APIKey {
+	return predicate.APIKey(sql.FieldGTE(FieldRpmLimit, v))
+}
+
+// RpmLimitLT applies the LT predicate on the "rpm_limit" field.
+func RpmLimitLT(v int) predicate.APIKey {
+	return predicate.APIKey(sql.FieldLT(FieldRpmLimit, v))
+}
+
+// RpmLimitLTE applies the LTE predicate on the "rpm_limit" field.
+func RpmLimitLTE(v int) predicate.APIKey {
+	return predicate.APIKey(sql.FieldLTE(FieldRpmLimit, v))
+}
+
+// TpmLimitEQ applies the EQ predicate on the "tpm_limit" field.
+func TpmLimitEQ(v int) predicate.APIKey {
+	return predicate.APIKey(sql.FieldEQ(FieldTpmLimit, v))
+}
+
+// TpmLimitNEQ applies the NEQ predicate on the "tpm_limit" field.
+func TpmLimitNEQ(v int) predicate.APIKey {
+	return predicate.APIKey(sql.FieldNEQ(FieldTpmLimit, v))
+}
+
+// TpmLimitIn applies the In predicate on the "tpm_limit" field.
+func TpmLimitIn(vs ...int) predicate.APIKey {
+	return predicate.APIKey(sql.FieldIn(FieldTpmLimit, vs...))
+}
+
+// TpmLimitNotIn applies the NotIn predicate on the "tpm_limit" field.
+func TpmLimitNotIn(vs ...int) predicate.APIKey {
+	return predicate.APIKey(sql.FieldNotIn(FieldTpmLimit, vs...))
+}
+
+// TpmLimitGT applies the GT predicate on the "tpm_limit" field.
+func TpmLimitGT(v int) predicate.APIKey {
+	return predicate.APIKey(sql.FieldGT(FieldTpmLimit, v))
+}
+
+// TpmLimitGTE applies the GTE predicate on the "tpm_limit" field.
+func TpmLimitGTE(v int) predicate.APIKey {
+	return predicate.APIKey(sql.FieldGTE(FieldTpmLimit, v))
+}
+
+// TpmLimitLT applies the LT predicate on the "tpm_limit" field.
+func TpmLimitLT(v int) predicate.APIKey {
+	return predicate.APIKey(sql.FieldLT(FieldTpmLimit, v))
+}
+
+// TpmLimitLTE applies the LTE predicate on the "tpm_limit" field.
+func TpmLimitLTE(v int) predicate.APIKey {
+	return predicate.APIKey(sql.FieldLTE(FieldTpmLimit, v))
+}
+
+// MaxConcurrencyEQ applies the EQ predicate on the "max_concurrency" field.
+func MaxConcurrencyEQ(v int) predicate.APIKey {
+	return predicate.APIKey(sql.FieldEQ(FieldMaxConcurrency, v))
+}
+
+// MaxConcurrencyNEQ applies the NEQ predicate on the "max_concurrency" field.
+func MaxConcurrencyNEQ(v int) predicate.APIKey {
+	return predicate.APIKey(sql.FieldNEQ(FieldMaxConcurrency, v))
+}
+
+// MaxConcurrencyIn applies the In predicate on the "max_concurrency" field.
+func MaxConcurrencyIn(vs ...int) predicate.APIKey {
+	return predicate.APIKey(sql.FieldIn(FieldMaxConcurrency, vs...))
+}
+
+// MaxConcurrencyNotIn applies the NotIn predicate on the "max_concurrency" field.
+func MaxConcurrencyNotIn(vs ...int) predicate.APIKey {
+	return predicate.APIKey(sql.FieldNotIn(FieldMaxConcurrency, vs...))
+}
+
+// MaxConcurrencyGT applies the GT predicate on the "max_concurrency" field.
+func MaxConcurrencyGT(v int) predicate.APIKey {
+	return predicate.APIKey(sql.FieldGT(FieldMaxConcurrency, v))
+}
+
+// MaxConcurrencyGTE applies the GTE predicate on the "max_concurrency" field.
+func MaxConcurrencyGTE(v int) predicate.APIKey {
+	return predicate.APIKey(sql.FieldGTE(FieldMaxConcurrency, v))
+}
+
+// MaxConcurrencyLT applies the LT predicate on the "max_concurrency" field.
+func MaxConcurrencyLT(v int) predicate.APIKey {
+	return predicate.APIKey(sql.FieldLT(FieldMaxConcurrency, v))
+}
+
+// MaxConcurrencyLTE applies the LTE predicate on the "max_concurrency" field.
+func MaxConcurrencyLTE(v int) predicate.APIKey {
+	return predicate.APIKey(sql.FieldLTE(FieldMaxConcurrency, v))
+}
+
+// CreatedByUserIDEQ applies the EQ predicate on the "created_by_user_id" field.
+func CreatedByUserIDEQ(v int64) predicate.APIKey {
+	return predicate.APIKey(sql.FieldEQ(FieldCreatedByUserID, v))
+}
+
+// CreatedByUserIDNEQ applies the NEQ predicate on the "created_by_user_id" field.
+func CreatedByUserIDNEQ(v int64) predicate.APIKey {
+	return predicate.APIKey(sql.FieldNEQ(FieldCreatedByUserID, v))
+}
+
+// CreatedByUserIDIn applies the In predicate on the "created_by_user_id" field.
+func CreatedByUserIDIn(vs ...int64) predicate.APIKey {
+	return predicate.APIKey(sql.FieldIn(FieldCreatedByUserID, vs...))
+}
+
+// CreatedByUserIDNotIn applies the NotIn predicate on the "created_by_user_id" field.
+func CreatedByUserIDNotIn(vs ...int64) predicate.APIKey {
+	return predicate.APIKey(sql.FieldNotIn(FieldCreatedByUserID, vs...))
+}
+
+// CreatedByUserIDGT applies the GT predicate on the "created_by_user_id" field.
+func CreatedByUserIDGT(v int64) predicate.APIKey {
+	return predicate.APIKey(sql.FieldGT(FieldCreatedByUserID, v))
+}
+
+// CreatedByUserIDGTE applies the GTE predicate on the "created_by_user_id" field.
+func CreatedByUserIDGTE(v int64) predicate.APIKey {
+	return predicate.APIKey(sql.FieldGTE(FieldCreatedByUserID, v))
+}
+
+// CreatedByUserIDLT applies the LT predicate on the "created_by_user_id" field.
+func CreatedByUserIDLT(v int64) predicate.APIKey {
+	return predicate.APIKey(sql.FieldLT(FieldCreatedByUserID, v))
+}
+
+// CreatedByUserIDLTE applies the LTE predicate on the "created_by_user_id" field.
+func CreatedByUserIDLTE(v int64) predicate.APIKey {
+	return predicate.APIKey(sql.FieldLTE(FieldCreatedByUserID, v))
+}
+
+// CreatedByUserIDIsNil applies the IsNil predicate on the "created_by_user_id" field.
+func CreatedByUserIDIsNil() predicate.APIKey {
+	return predicate.APIKey(sql.FieldIsNull(FieldCreatedByUserID))
+}
+
+// CreatedByUserIDNotNil applies the NotNil predicate on the "created_by_user_id" field.
+func CreatedByUserIDNotNil() predicate.APIKey {
+	return predicate.APIKey(sql.FieldNotNull(FieldCreatedByUserID))
+}
+
 // HasUser applies the HasEdge predicate on the "user" edge.
 func HasUser() predicate.APIKey {
 	return predicate.APIKey(func(s *sql.Selector) {
@@ -15,6 +15,7 @@ import (
 	"github.com/Wei-Shaw/sub2api/ent/group"
 	"github.com/Wei-Shaw/sub2api/ent/usagelog"
 	"github.com/Wei-Shaw/sub2api/ent/user"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
 )
 
 // APIKeyCreate is the builder for creating a APIKey entity.
@@ -139,14 +140,26 @@ func (_c *APIKeyCreate) SetIPBlacklist(v []string) *APIKeyCreate {
 	return _c
 }
 
+// SetModelAllowlist sets the "model_allowlist" field.
+func (_c *APIKeyCreate) SetModelAllowlist(v []string) *APIKeyCreate {
+	_c.mutation.SetModelAllowlist(v)
+	return _c
+}
+
+// SetModelDenylist sets the "model_denylist" field.
+func (_c *APIKeyCreate) SetModelDenylist(v []string) *APIKeyCreate {
+	_c.mutation.SetModelDenylist(v)
+	return _c
+}
+
 // SetQuota sets the "quota" field.
-func (_c *APIKeyCreate) SetQuota(v float64) *APIKeyCreate {
+func (_c *APIKeyCreate) SetQuota(v money.Amount) *APIKeyCreate {
 	_c.mutation.SetQuota(v)
 	return _c
 }
 
 // SetNillableQuota sets the "quota" field if the given value is not nil.
-func (_c *APIKeyCreate) SetNillableQuota(v *float64) *APIKeyCreate {
+func (_c *APIKeyCreate) SetNillableQuota(v *money.Amount) *APIKeyCreate {
 	if v != nil {
 		_c.SetQuota(*v)
 	}
@@ -154,13 +167,13 @@ func (_c *APIKeyCreate) SetNillableQuota(v *float64) *APIKeyCreate {
 }
 
 // SetQuotaUsed sets the "quota_used" field.
-func (_c *APIKeyCreate) SetQuotaUsed(v float64) *APIKeyCreate {
+func (_c *APIKeyCreate) SetQuotaUsed(v money.Amount) *APIKeyCreate {
 	_c.mutation.SetQuotaUsed(v)
 	return _c
 }
 
 // SetNillableQuotaUsed sets the "quota_used" field if the given value is not nil.
-func (_c *APIKeyCreate) SetNillableQuotaUsed(v *float64) *APIKeyCreate {
+func (_c *APIKeyCreate) SetNillableQuotaUsed(v *money.Amount) *APIKeyCreate {
 	if v != nil {
 		_c.SetQuotaUsed(*v)
 	}
@@ -182,13 +195,13 @@ func (_c *APIKeyCreate) SetNillableExpiresAt(v *time.Time) *APIKeyCreate {
 }
 
 // SetRateLimit5h sets the "rate_limit_5h" field.
-func (_c *APIKeyCreate) SetRateLimit5h(v float64) *APIKeyCreate {
+func (_c *APIKeyCreate) SetRateLimit5h(v money.Amount) *APIKeyCreate {
 	_c.mutation.SetRateLimit5h(v)
 	return _c
 }
 
 // SetNillableRateLimit5h sets the "rate_limit_5h" field if the given value is not nil.
-func (_c *APIKeyCreate) SetNillableRateLimit5h(v *float64) *APIKeyCreate {
+func (_c *APIKeyCreate) SetNillableRateLimit5h(v *money.Amount) *APIKeyCreate {
 	if v != nil {
 		_c.SetRateLimit5h(*v)
 	}
@@ -196,13 +209,13 @@ func (_c *APIKeyCreate) SetNillableRateLimit5h(v *float64) *APIKeyCreate {
 }
 
 // SetRateLimit1d sets the "rate_limit_1d" field.
-func (_c *APIKeyCreate) SetRateLimit1d(v float64) *APIKeyCreate {
+func (_c *APIKeyCreate) SetRateLimit1d(v money.Amount) *APIKeyCreate {
 	_c.mutation.SetRateLimit1d(v)
 	return _c
 }
 
 // SetNillableRateLimit1d sets the "rate_limit_1d" field if the given value is not nil.
-func (_c *APIKeyCreate) SetNillableRateLimit1d(v *float64) *APIKeyCreate {
+func (_c *APIKeyCreate) SetNillableRateLimit1d(v *money.Amount) *APIKeyCreate {
 	if v != nil {
 		_c.SetRateLimit1d(*v)
 	}
@@ -210,13 +223,13 @@ func (_c *APIKeyCreate) SetNillableRateLimit1d(v *float64) *APIKeyCreate {
 }
 
 // SetRateLimit7d sets the "rate_limit_7d" field.
-func (_c *APIKeyCreate) SetRateLimit7d(v float64) *APIKeyCreate {
+func (_c *APIKeyCreate) SetRateLimit7d(v money.Amount) *APIKeyCreate {
 	_c.mutation.SetRateLimit7d(v)
 	return _c
 }
 
 // SetNillableRateLimit7d sets the "rate_limit_7d" field if the given value is not nil.
-func (_c *APIKeyCreate) SetNillableRateLimit7d(v *float64) *APIKeyCreate {
+func (_c *APIKeyCreate) SetNillableRateLimit7d(v *money.Amount) *APIKeyCreate {
 	if v != nil {
 		_c.SetRateLimit7d(*v)
 	}
@@ -224,13 +237,13 @@ func (_c *APIKeyCreate) SetNillableRateLimit7d(v *float64) *APIKeyCreate {
 }
 
 // SetUsage5h sets the "usage_5h" field.
-func (_c *APIKeyCreate) SetUsage5h(v float64) *APIKeyCreate {
+func (_c *APIKeyCreate) SetUsage5h(v money.Amount) *APIKeyCreate {
 	_c.mutation.SetUsage5h(v)
 	return _c
 }
 
 // SetNillableUsage5h sets the "usage_5h" field if the given value is not nil.
-func (_c *APIKeyCreate) SetNillableUsage5h(v *float64) *APIKeyCreate {
+func (_c *APIKeyCreate) SetNillableUsage5h(v *money.Amount) *APIKeyCreate {
 	if v != nil {
 		_c.SetUsage5h(*v)
 	}
@@ -238,13 +251,13 @@ func (_c *APIKeyCreate) SetNillableUsage5h(v *float64) *APIKeyCreate {
 }
 
 // SetUsage1d sets the "usage_1d" field.
-func (_c *APIKeyCreate) SetUsage1d(v float64) *APIKeyCreate {
+func (_c *APIKeyCreate) SetUsage1d(v money.Amount) *APIKeyCreate {
 	_c.mutation.SetUsage1d(v)
 	return _c
 }
 
 // SetNillableUsage1d sets the "usage_1d" field if the given value is not nil.
-func (_c *APIKeyCreate) SetNillableUsage1d(v *float64) *APIKeyCreate {
+func (_c *APIKeyCreate) SetNillableUsage1d(v *money.Amount) *APIKeyCreate {
 	if v != nil {
 		_c.SetUsage1d(*v)
 	}
@@ -252,13 +265,13 @@ func (_c *APIKeyCreate) SetNillableUsage1d(v *float64) *APIKeyCreate {
 }
 
 // SetUsage7d sets the "usage_7d" field.
-func (_c *APIKeyCreate) SetUsage7d(v float64) *APIKeyCreate {
+func (_c *APIKeyCreate) SetUsage7d(v money.Amount) *APIKeyCreate {
 	_c.mutation.SetUsage7d(v)
 	return _c
 }
 
 // SetNillableUsage7d sets the "usage_7d" field if the given value is not nil.
-func (_c *APIKeyCreate) SetNillableUsage7d(v *float64) *APIKeyCreate {
+func (_c *APIKeyCreate) SetNillableUsage7d(v *money.Amount) *APIKeyCreate {
 	if v != nil {
 		_c.SetUsage7d(*v)
 	}
@@ -307,6 +320,62 @@ func (_c *APIKeyCreate) SetNillableWindow7dStart(v *time.Time) *APIKeyCreate {
 	return _c
 }
 
+// SetRpmLimit sets the "rpm_limit" field.
+func (_c *APIKeyCreate) SetRpmLimit(v int) *APIKeyCreate {
+	_c.mutation.SetRpmLimit(v)
+	return _c
+}
+
+// SetNillableRpmLimit sets the "rpm_limit" field if the given value is not nil.
+func (_c *APIKeyCreate) SetNillableRpmLimit(v *int) *APIKeyCreate {
+	if v != nil {
+		_c.SetRpmLimit(*v)
+	}
+	return _c
+}
+
+// SetTpmLimit sets the "tpm_limit" field.
+func (_c *APIKeyCreate) SetTpmLimit(v int) *APIKeyCreate {
+	_c.mutation.SetTpmLimit(v)
+	return _c
+}
+
+// SetNillableTpmLimit sets the "tpm_limit" field if the given value is not nil.
+func (_c *APIKeyCreate) SetNillableTpmLimit(v *int) *APIKeyCreate {
+	if v != nil {
+		_c.SetTpmLimit(*v)
+	}
+	return _c
+}
+
+// SetMaxConcurrency sets the "max_concurrency" field.
+func (_c *APIKeyCreate) SetMaxConcurrency(v int) *APIKeyCreate {
+	_c.mutation.SetMaxConcurrency(v)
+	return _c
+}
+
+// SetNillableMaxConcurrency sets the "max_concurrency" field if the given value is not nil.
+func (_c *APIKeyCreate) SetNillableMaxConcurrency(v *int) *APIKeyCreate {
+	if v != nil {
+		_c.SetMaxConcurrency(*v)
+	}
+	return _c
+}
+
+// SetCreatedByUserID sets the "created_by_user_id" field.
+func (_c *APIKeyCreate) SetCreatedByUserID(v int64) *APIKeyCreate {
+	_c.mutation.SetCreatedByUserID(v)
+	return _c
+}
+
+// SetNillableCreatedByUserID sets the "created_by_user_id" field if the given value is not nil.
+func (_c *APIKeyCreate) SetNillableCreatedByUserID(v *int64) *APIKeyCreate {
+	if v != nil {
+		_c.SetCreatedByUserID(*v)
+	}
+	return _c
+}
+
 // SetUser sets the "user" edge to the User entity.
 func (_c *APIKeyCreate) SetUser(v *User) *APIKeyCreate {
 	return _c.SetUserID(v.ID)
@@ -419,6 +488,18 @@ func (_c *APIKeyCreate) defaults() error {
 		v := apikey.DefaultUsage7d
 		_c.mutation.SetUsage7d(v)
 	}
+	if _, ok := _c.mutation.RpmLimit(); !ok {
+		v := apikey.DefaultRpmLimit
+		_c.mutation.SetRpmLimit(v)
+	}
+	if _, ok := _c.mutation.TpmLimit(); !ok {
+		v := apikey.DefaultTpmLimit
+		_c.mutation.SetTpmLimit(v)
+	}
+	if _, ok := _c.mutation.MaxConcurrency(); !ok {
+		v := apikey.DefaultMaxConcurrency
+		_c.mutation.SetMaxConcurrency(v)
+	}
 	return nil
 }
 
@@ -481,6 +562,15 @@ func (_c *APIKeyCreate) check() error {
 	if _, ok := _c.mutation.Usage7d(); !ok {
 		return &ValidationError{Name: "usage_7d", err: errors.New(`ent: missing required field "APIKey.usage_7d"`)}
 	}
+	if _, ok := _c.mutation.RpmLimit(); !ok {
+		return &ValidationError{Name: "rpm_limit", err: errors.New(`ent: missing required field "APIKey.rpm_limit"`)}
+	}
+	if _, ok := _c.mutation.TpmLimit(); !ok {
+		return &ValidationError{Name: "tpm_limit", err: errors.New(`ent: missing required field "APIKey.tpm_limit"`)}
+	}
+	if _, ok := _c.mutation.MaxConcurrency(); !ok {
+		return &ValidationError{Name: "max_concurrency", err: errors.New(`ent: missing required field "APIKey.max_concurrency"`)}
+	}
 	if len(_c.mutation.UserIDs()) == 0 {
 		return &ValidationError{Name: "user", err: errors.New(`ent: missing required edge "APIKey.user"`)}
 	}
@@ -547,12 +637,20 @@ func (_c *APIKeyCreate) createSpec() (*APIKey, *sqlgraph.CreateSpec) {
 		_spec.SetField(apikey.FieldIPBlacklist, field.TypeJSON, value)
 		_node.IPBlacklist = value
 	}
+	if value, ok := _c.mutation.ModelAllowlist(); ok {
+		_spec.SetField(apikey.FieldModelAllowlist, field.TypeJSON, value)
+		_node.ModelAllowlist = value
+	}
+	if value, ok := _c.mutation.ModelDenylist(); ok {
+		_spec.SetField(apikey.FieldModelDenylist, field.TypeJSON, value)
+		_node.ModelDenylist = value
+	}
 	if value, ok := _c.mutation.Quota(); ok {
-		_spec.SetField(apikey.FieldQuota, field.TypeFloat64, value)
+		_spec.SetField(apikey.FieldQuota, field.TypeInt64, value)
 		_node.Quota = value
 	}
 	if value, ok := _c.mutation.QuotaUsed(); ok {
-		_spec.SetField(apikey.FieldQuotaUsed, field.TypeFloat64, value)
+		_spec.SetField(apikey.FieldQuotaUsed, field.TypeInt64, value)
 		_node.QuotaUsed = value
 	}
 	if value, ok := _c.mutation.ExpiresAt(); ok {
@@ -560,27 +658,27 @@ func (_c *APIKeyCreate) createSpec() (*APIKey, *sqlgraph.CreateSpec) {
 		_node.ExpiresAt = &value
 	}
 	if value, ok := _c.mutation.RateLimit5h(); ok {
-		_spec.SetField(apikey.FieldRateLimit5h, field.TypeFloat64, value)
+		_spec.SetField(apikey.FieldRateLimit5h, field.TypeInt64, value)
 		_node.RateLimit5h = value
 	}
 	if value, ok := _c.mutation.RateLimit1d(); ok {
-		_spec.SetField(apikey.FieldRateLimit1d, field.TypeFloat64, value)
+		_spec.SetField(apikey.FieldRateLimit1d, field.TypeInt64, value)
 		_node.RateLimit1d = value
 	}
 	if value, ok := _c.mutation.RateLimit7d(); ok {
-		_spec.SetField(apikey.FieldRateLimit7d, field.TypeFloat64, value)
+		_spec.SetField(apikey.FieldRateLimit7d, field.TypeInt64, value)
 		_node.RateLimit7d = value
 	}
 	if value, ok := _c.mutation.Usage5h(); ok {
-		_spec.SetField(apikey.FieldUsage5h, field.TypeFloat64, value)
+		_spec.SetField(apikey.FieldUsage5h, field.TypeInt64, value)
 		_node.Usage5h = value
 	}
 	if value, ok := _c.mutation.Usage1d(); ok {
-		_spec.SetField(apikey.FieldUsage1d, field.TypeFloat64, value)
+		_spec.SetField(apikey.FieldUsage1d, field.TypeInt64, value)
 		_node.Usage1d = value
 	}
 	if value, ok := _c.mutation.Usage7d(); ok {
-		_spec.SetField(apikey.FieldUsage7d, field.TypeFloat64, value)
+		_spec.SetField(apikey.FieldUsage7d, field.TypeInt64, value)
 		_node.Usage7d = value
 	}
 	if value, ok := _c.mutation.Window5hStart(); ok {
@@ -595,6 +693,22 @@ func (_c *APIKeyCreate) createSpec() (*APIKey, *sqlgraph.CreateSpec) {
 		_spec.SetField(apikey.FieldWindow7dStart, field.TypeTime, value)
 		_node.Window7dStart = &value
 	}
+	if value, ok := _c.mutation.RpmLimit(); ok {
+		_spec.SetField(apikey.FieldRpmLimit, field.TypeInt, value)
+		_node.RpmLimit = value
+	}
+	if value, ok := _c.mutation.TpmLimit(); ok {
+		_spec.SetField(apikey.FieldTpmLimit, field.TypeInt, value)
+		_node.TpmLimit = value
+	}
+	if value, ok := _c.mutation.MaxConcurrency(); ok {
+		_spec.SetField(apikey.FieldMaxConcurrency, field.TypeInt, value)
+		_node.MaxConcurrency = value
+	}
+	if value, ok := _c.mutation.CreatedByUserID(); ok {
+		_spec.SetField(apikey.FieldCreatedByUserID, field.TypeInt64, value)
+		_node.CreatedByUserID = &value
+	}
 	if nodes := _c.mutation.UserIDs(); len(nodes) > 0 {
 		edge := &sqlgraph.EdgeSpec{
 			Rel:     sqlgraph.M2O,
@@ -847,8 +961,44 @@ func (u *APIKeyUpsert) ClearIPBlacklist() *APIKeyUpsert {
 	return u
 }
 
+// SetModelAllowlist sets the "model_allowlist" field.
+func (u *APIKeyUpsert) SetModelAllowlist(v []string) *APIKeyUpsert {
+	u.Set(apikey.FieldModelAllowlist, v)
+	return u
+}
+
+// UpdateModelAllowlist sets the "model_allowlist" field to the value that was provided on create.
+func (u *APIKeyUpsert) UpdateModelAllowlist() *APIKeyUpsert {
+	u.SetExcluded(apikey.FieldModelAllowlist)
+	return u
+}
+
+// ClearModelAllowlist clears the value of the "model_allowlist" field.
+func (u *APIKeyUpsert) ClearModelAllowlist() *APIKeyUpsert {
+	u.SetNull(apikey.FieldModelAllowlist)
+	return u
+}
+
+// SetModelDenylist sets the "model_denylist" field.
+func (u *APIKeyUpsert) SetModelDenylist(v []string) *APIKeyUpsert {
+	u.Set(apikey.FieldModelDenylist, v)
+	return u
+}
+
+// UpdateModelDenylist sets the "model_denylist" field to the value that was provided on create.
+func (u *APIKeyUpsert) UpdateModelDenylist() *APIKeyUpsert {
+	u.SetExcluded(apikey.FieldModelDenylist)
+	return u
+}
+
+// ClearModelDenylist clears the value of the "model_denylist" field.
+func (u *APIKeyUpsert) ClearModelDenylist() *APIKeyUpsert {
+	u.SetNull(apikey.FieldModelDenylist)
+	return u
+}
+
 // SetQuota sets the "quota" field.
-func (u *APIKeyUpsert) SetQuota(v float64) *APIKeyUpsert {
+func (u *APIKeyUpsert) SetQuota(v money.Amount) *APIKeyUpsert {
 	u.Set(apikey.FieldQuota, v)
 	return u
 }
@@ -860,13 +1010,13 @@ func (u *APIKeyUpsert) UpdateQuota() *APIKeyUpsert {
 }
 
 // AddQuota adds v to the "quota" field.
-func (u *APIKeyUpsert) AddQuota(v float64) *APIKeyUpsert {
+func (u *APIKeyUpsert) AddQuota(v money.Amount) *APIKeyUpsert {
 	u.Add(apikey.FieldQuota, v)
 	return u
 }
 
 // SetQuotaUsed sets the "quota_used" field.
-func (u *APIKeyUpsert) SetQuotaUsed(v float64) *APIKeyUpsert {
+func (u *APIKeyUpsert) SetQuotaUsed(v money.Amount) *APIKeyUpsert {
 	u.Set(apikey.FieldQuotaUsed, v)
 	return u
 }
@@ -878,7 +1028,7 @@ func (u *APIKeyUpsert) UpdateQuotaUsed() *APIKeyUpsert {
 }
 
 // AddQuotaUsed adds v to the "quota_used" field.
-func (u *APIKeyUpsert) AddQuotaUsed(v float64) *APIKeyUpsert {
+func (u *APIKeyUpsert) AddQuotaUsed(v money.Amount) *APIKeyUpsert {
 	u.Add(apikey.FieldQuotaUsed, v)
 	return u
 }
@@ -902,7 +1052,7 @@ func (u *APIKeyUpsert) ClearExpiresAt() *APIKeyUpsert {
 }
 
 // SetRateLimit5h sets the "rate_limit_5h" field.
-func (u *APIKeyUpsert) SetRateLimit5h(v float64) *APIKeyUpsert {
+func (u *APIKeyUpsert) SetRateLimit5h(v money.Amount) *APIKeyUpsert {
 	u.Set(apikey.FieldRateLimit5h, v)
 	return u
 }
@@ -914,13 +1064,13 @@ func (u *APIKeyUpsert) UpdateRateLimit5h() *APIKeyUpsert {
 }
 
 // AddRateLimit5h adds v to the "rate_limit_5h" field.
-func (u *APIKeyUpsert) AddRateLimit5h(v float64) *APIKeyUpsert {
+func (u *APIKeyUpsert) AddRateLimit5h(v money.Amount) *APIKeyUpsert {
 	u.Add(apikey.FieldRateLimit5h, v)
 	return u
 }
 
 // SetRateLimit1d sets the "rate_limit_1d" field.
-func (u *APIKeyUpsert) SetRateLimit1d(v float64) *APIKeyUpsert {
+func (u *APIKeyUpsert) SetRateLimit1d(v money.Amount) *APIKeyUpsert {
 	u.Set(apikey.FieldRateLimit1d, v)
 	return u
 }
@@ -932,13 +1082,13 @@ func (u *APIKeyUpsert) UpdateRateLimit1d() *APIKeyUpsert {
 }
 
 // AddRateLimit1d adds v to the "rate_limit_1d" field.
-func (u *APIKeyUpsert) AddRateLimit1d(v float64) *APIKeyUpsert {
+func (u *APIKeyUpsert) AddRateLimit1d(v money.Amount) *APIKeyUpsert {
 	u.Add(apikey.FieldRateLimit1d, v)
 	return u
 }
 
 // SetRateLimit7d sets the "rate_limit_7d" field.
-func (u *APIKeyUpsert) SetRateLimit7d(v float64) *APIKeyUpsert {
+func (u *APIKeyUpsert) SetRateLimit7d(v money.Amount) *APIKeyUpsert {
 	u.Set(apikey.FieldRateLimit7d, v)
 	return u
 }
@@ -950,13 +1100,13 @@ func (u *APIKeyUpsert) UpdateRateLimit7d() *APIKeyUpsert {
 }
 
 // AddRateLimit7d adds v to the "rate_limit_7d" field.
-func (u *APIKeyUpsert) AddRateLimit7d(v float64) *APIKeyUpsert {
+func (u *APIKeyUpsert) AddRateLimit7d(v money.Amount) *APIKeyUpsert {
 	u.Add(apikey.FieldRateLimit7d, v)
 	return u
 }
 
 // SetUsage5h sets the "usage_5h" field.
-func (u *APIKeyUpsert) SetUsage5h(v float64) *APIKeyUpsert {
+func (u *APIKeyUpsert) SetUsage5h(v money.Amount) *APIKeyUpsert {
 	u.Set(apikey.FieldUsage5h, v)
 	return u
 }
@@ -968,13 +1118,13 @@ func (u *APIKeyUpsert) UpdateUsage5h() *APIKeyUpsert {
 }
 
 // AddUsage5h adds v to the "usage_5h" field.
-func (u *APIKeyUpsert) AddUsage5h(v float64) *APIKeyUpsert {
+func (u *APIKeyUpsert) AddUsage5h(v money.Amount) *APIKeyUpsert {
 	u.Add(apikey.FieldUsage5h, v)
 	return u
 }
 
 // SetUsage1d sets the "usage_1d" field.
-func (u *APIKeyUpsert) SetUsage1d(v float64) *APIKeyUpsert {
+func (u *APIKeyUpsert) SetUsage1d(v money.Amount) *APIKeyUpsert {
 	u.Set(apikey.FieldUsage1d, v)
 	return u
 }
@@ -986,13 +1136,13 @@ func (u *APIKeyUpsert) UpdateUsage1d() *APIKeyUpsert {
 }
 
 // AddUsage1d adds v to the "usage_1d" field.
-func (u *APIKeyUpsert) AddUsage1d(v float64) *APIKeyUpsert {
+func (u *APIKeyUpsert) AddUsage1d(v money.Amount) *APIKeyUpsert {
 	u.Add(apikey.FieldUsage1d, v)
 	return u
 }
 
 // SetUsage7d sets the "usage_7d" field.
-func (u *APIKeyUpsert) SetUsage7d(v float64) *APIKeyUpsert {
+func (u *APIKeyUpsert) SetUsage7d(v money.Amount) *APIKeyUpsert {
 	u.Set(apikey.FieldUsage7d, v)
 	return u
 }
@@ -1004,7 +1154,7 @@ func (u *APIKeyUpsert) UpdateUsage7d() *APIKeyUpsert {
 }
 
 // AddUsage7d adds v to the "usage_7d" field.
-func (u *APIKeyUpsert) AddUsage7d(v float64) *APIKeyUpsert {
+func (u *APIKeyUpsert) AddUsage7d(v money.Amount) *APIKeyUpsert {
 	u.Add(apikey.FieldUsage7d, v)
 	return u
 }
@@ -1063,6 +1213,84 @@ func (u *APIKeyUpsert) ClearWindow7dStart() *APIKeyUpsert {
 	return u
 }
 
+// SetRpmLimit sets the "rpm_limit" field.
+func (u *APIKeyUpsert) SetRpmLimit(v int) *APIKeyUpsert {
+	u.Set(apikey.FieldRpmLimit, v)
+	return u
+}
+
+// UpdateRpmLimit sets the "rpm_limit" field to the value that was provided on create.
+func (u *APIKeyUpsert) UpdateRpmLimit() *APIKeyUpsert {
+	u.SetExcluded(apikey.FieldRpmLimit)
+	return u
+}
+
+// AddRpmLimit adds v to the "rpm_limit" field.
+func (u *APIKeyUpsert) AddRpmLimit(v int) *APIKeyUpsert {
+	u.Add(apikey.FieldRpmLimit, v)
+	return u
+}
+
+// SetTpmLimit sets the "tpm_limit" field.
+func (u *APIKeyUpsert) SetTpmLimit(v int) *APIKeyUpsert {
+	u.Set(apikey.FieldTpmLimit, v)
+	return u
+}
+
+// UpdateTpmLimit sets the "tpm_limit" field to the value that was provided on create.
+func (u *APIKeyUpsert) UpdateTpmLimit() *APIKeyUpsert {
+	u.SetExcluded(apikey.FieldTpmLimit)
+	return u
+}
+
+// AddTpmLimit adds v to the "tpm_limit" field.
+func (u *APIKeyUpsert) AddTpmLimit(v int) *APIKeyUpsert {
+	u.Add(apikey.FieldTpmLimit, v)
+	return u
+}
+
+// SetMaxConcurrency sets the "max_concurrency" field.
+func (u *APIKeyUpsert) SetMaxConcurrency(v int) *APIKeyUpsert {
+	u.Set(apikey.FieldMaxConcurrency, v)
+	return u
+}
+
+// UpdateMaxConcurrency sets the "max_concurrency" field to the value that was provided on create.
+func (u *APIKeyUpsert) UpdateMaxConcurrency() *APIKeyUpsert {
+	u.SetExcluded(apikey.FieldMaxConcurrency)
+	return u
+}
+
+// AddMaxConcurrency adds v to the "max_concurrency" field.
+func (u *APIKeyUpsert) AddMaxConcurrency(v int) *APIKeyUpsert {
+	u.Add(apikey.FieldMaxConcurrency, v)
+	return u
+}
+
+// SetCreatedByUserID sets the "created_by_user_id" field.
+func (u *APIKeyUpsert) SetCreatedByUserID(v int64) *APIKeyUpsert {
+	u.Set(apikey.FieldCreatedByUserID, v)
+	return u
+}
+
+// UpdateCreatedByUserID sets the "created_by_user_id" field to the value that was provided on create.
+func (u *APIKeyUpsert) UpdateCreatedByUserID() *APIKeyUpsert {
+	u.SetExcluded(apikey.FieldCreatedByUserID)
+	return u
+}
+
+// AddCreatedByUserID adds v to the "created_by_user_id" field.
+func (u *APIKeyUpsert) AddCreatedByUserID(v int64) *APIKeyUpsert {
+	u.Add(apikey.FieldCreatedByUserID, v)
+	return u
+}
+
+// ClearCreatedByUserID clears the value of the "created_by_user_id" field.
+func (u *APIKeyUpsert) ClearCreatedByUserID() *APIKeyUpsert {
+	u.SetNull(apikey.FieldCreatedByUserID)
+	return u
+}
+
 // UpdateNewValues updates the mutable fields using the new values that were set on create.
 // Using this option is equivalent to using:
 //
@@ -1283,15 +1511,57 @@ func (u *APIKeyUpsertOne) ClearIPBlacklist() *APIKeyUpsertOne {
 	})
 }
 
+// SetModelAllowlist sets the "model_allowlist" field.
+func (u *APIKeyUpsertOne) SetModelAllowlist(v []string) *APIKeyUpsertOne {
+	return u.Update(func(s *APIKeyUpsert) {
+		s.SetModelAllowlist(v)
+	})
+}
+
+// UpdateModelAllowlist sets the "model_allowlist" field to the value that was provided on create.
+func (u *APIKeyUpsertOne) UpdateModelAllowlist() *APIKeyUpsertOne {
+	return u.Update(func(s *APIKeyUpsert) {
+		s.UpdateModelAllowlist()
+	})
+}
+
+// ClearModelAllowlist clears the value of the "model_allowlist" field.
+func (u *APIKeyUpsertOne) ClearModelAllowlist() *APIKeyUpsertOne {
+	return u.Update(func(s *APIKeyUpsert) {
+		s.ClearModelAllowlist()
+	})
+}
+
+// SetModelDenylist sets the "model_denylist" field.
+func (u *APIKeyUpsertOne) SetModelDenylist(v []string) *APIKeyUpsertOne {
+	return u.Update(func(s *APIKeyUpsert) {
+		s.SetModelDenylist(v)
+	})
+}
+
+// UpdateModelDenylist sets the "model_denylist" field to the value that was provided on create.
+func (u *APIKeyUpsertOne) UpdateModelDenylist() *APIKeyUpsertOne {
+	return u.Update(func(s *APIKeyUpsert) {
+		s.UpdateModelDenylist()
+	})
+}
+
+// ClearModelDenylist clears the value of the "model_denylist" field.
+func (u *APIKeyUpsertOne) ClearModelDenylist() *APIKeyUpsertOne {
+	return u.Update(func(s *APIKeyUpsert) {
+		s.ClearModelDenylist()
+	})
+}
+
 // SetQuota sets the "quota" field.
-func (u *APIKeyUpsertOne) SetQuota(v float64) *APIKeyUpsertOne {
+func (u *APIKeyUpsertOne) SetQuota(v money.Amount) *APIKeyUpsertOne {
 	return u.Update(func(s *APIKeyUpsert) {
 		s.SetQuota(v)
 	})
 }
 
 // AddQuota adds v to the "quota" field.
-func (u *APIKeyUpsertOne) AddQuota(v float64) *APIKeyUpsertOne {
+func (u *APIKeyUpsertOne) AddQuota(v money.Amount) *APIKeyUpsertOne {
 	return u.Update(func(s *APIKeyUpsert) {
 		s.AddQuota(v)
 	})
@@ -1305,14 +1575,14 @@ func (u *APIKeyUpsertOne) UpdateQuota() *APIKeyUpsertOne {
 }
 
 // SetQuotaUsed sets the "quota_used" field.
-func (u *APIKeyUpsertOne) SetQuotaUsed(v float64) *APIKeyUpsertOne {
+func (u *APIKeyUpsertOne) SetQuotaUsed(v money.Amount) *APIKeyUpsertOne {
 	return u.Update(func(s *APIKeyUpsert) {
 		s.SetQuotaUsed(v)
 	})
 }
 
 // AddQuotaUsed adds v to the "quota_used" field.
-func (u *APIKeyUpsertOne) AddQuotaUsed(v float64) *APIKeyUpsertOne {
+func (u *APIKeyUpsertOne) AddQuotaUsed(v money.Amount) *APIKeyUpsertOne {
 	return u.Update(func(s *APIKeyUpsert) {
 		s.AddQuotaUsed(v)
 	})
@@ -1347,14 +1617,14 @@ func (u *APIKeyUpsertOne) ClearExpiresAt() *APIKeyUpsertOne {
 }
 
 // SetRateLimit5h sets the "rate_limit_5h" field.
-func (u *APIKeyUpsertOne) SetRateLimit5h(v float64) *APIKeyUpsertOne {
+func (u *APIKeyUpsertOne) SetRateLimit5h(v money.Amount) *APIKeyUpsertOne {
 	return u.Update(func(s *APIKeyUpsert) {
 		s.SetRateLimit5h(v)
 	})
 }
 
 // AddRateLimit5h adds v to the "rate_limit_5h" field.
-func (u *APIKeyUpsertOne) AddRateLimit5h(v float64) *APIKeyUpsertOne {
+func (u *APIKeyUpsertOne) AddRateLimit5h(v money.Amount) *APIKeyUpsertOne {
 	return u.Update(func(s *APIKeyUpsert) {
 		s.AddRateLimit5h(v)
 	})
@@ -1368,14 +1638,14 @@ func (u *APIKeyUpsertOne) UpdateRateLimit5h() *APIKeyUpsertOne {
 }
 
 // SetRateLimit1d sets the "rate_limit_1d" field.
-func (u *APIKeyUpsertOne) SetRateLimit1d(v float64) *APIKeyUpsertOne {
+func (u *APIKeyUpsertOne) SetRateLimit1d(v money.Amount) *APIKeyUpsertOne {
 	return u.Update(func(s *APIKeyUpsert) {
 		s.SetRateLimit1d(v)
 	})
 }
 
 // AddRateLimit1d adds v to the "rate_limit_1d" field.
-func (u *APIKeyUpsertOne) AddRateLimit1d(v float64) *APIKeyUpsertOne {
+func (u *APIKeyUpsertOne) AddRateLimit1d(v money.Amount) *APIKeyUpsertOne {
 	return u.Update(func(s *APIKeyUpsert) {
 		s.AddRateLimit1d(v)
 	})
@@ -1389,14 +1659,14 @@ func (u *APIKeyUpsertOne) UpdateRateLimit1d() *APIKeyUpsertOne {
 }
 
 // SetRateLimit7d sets the "rate_limit_7d" field.
-func (u *APIKeyUpsertOne) SetRateLimit7d(v float64) *APIKeyUpsertOne {
+func (u *APIKeyUpsertOne) SetRateLimit7d(v money.Amount) *APIKeyUpsertOne {
 	return u.Update(func(s *APIKeyUpsert) {
 		s.SetRateLimit7d(v)
 	})
 }
 
 // AddRateLimit7d adds v to the "rate_limit_7d" field.
-func (u *APIKeyUpsertOne) AddRateLimit7d(v float64) *APIKeyUpsertOne {
+func (u *APIKeyUpsertOne) AddRateLimit7d(v money.Amount) *APIKeyUpsertOne {
 	return u.Update(func(s *APIKeyUpsert) {
 		s.AddRateLimit7d(v)
 	})
@@ -1410,14 +1680,14 @@ func (u *APIKeyUpsertOne) UpdateRateLimit7d() *APIKeyUpsertOne {
 }
 
 // SetUsage5h sets the "usage_5h" field.
-func (u *APIKeyUpsertOne) SetUsage5h(v float64) *APIKeyUpsertOne {
+func (u *APIKeyUpsertOne) SetUsage5h(v money.Amount) *APIKeyUpsertOne {
 	return u.Update(func(s *APIKeyUpsert) {
 		s.SetUsage5h(v)
 	})
 }
 
 // AddUsage5h adds v to the "usage_5h" field.
-func (u *APIKeyUpsertOne) AddUsage5h(v float64) *APIKeyUpsertOne {
+func (u *APIKeyUpsertOne) AddUsage5h(v money.Amount) *APIKeyUpsertOne {
 	return u.Update(func(s *APIKeyUpsert) {
 		s.AddUsage5h(v)
 	})
@@ -1431,14 +1701,14 @@ func (u *APIKeyUpsertOne) UpdateUsage5h() *APIKeyUpsertOne {
 }
 
 // SetUsage1d sets the "usage_1d" field.
-func (u *APIKeyUpsertOne) SetUsage1d(v float64) *APIKeyUpsertOne {
+func (u *APIKeyUpsertOne) SetUsage1d(v money.Amount) *APIKeyUpsertOne {
 	return u.Update(func(s *APIKeyUpsert) {
 		s.SetUsage1d(v)
 	})
 }
 
 // AddUsage1d adds v to the "usage_1d" field.
-func (u *APIKeyUpsertOne) AddUsage1d(v float64) *APIKeyUpsertOne {
+func (u *APIKeyUpsertOne) AddUsage1d(v money.Amount) *APIKeyUpsertOne {
 	return u.Update(func(s *APIKeyUpsert) {
 		s.AddUsage1d(v)
 	})
@@ -1452,14 +1722,14 @@ func (u *APIKeyUpsertOne) UpdateUsage1d() *APIKeyUpsertOne {
 }
 
 // SetUsage7d sets the "usage_7d" field.
-func (u *APIKeyUpsertOne) SetUsage7d(v float64) *APIKeyUpsertOne {
+func (u *APIKeyUpsertOne) SetUsage7d(v money.Amount) *APIKeyUpsertOne {
 	return u.Update(func(s *APIKeyUpsert) {
 		s.SetUsage7d(v)
 	})
 }
 
 // AddUsage7d adds v to the "usage_7d" field.
-func (u *APIKeyUpsertOne) AddUsage7d(v float64) *APIKeyUpsertOne {
+func (u *APIKeyUpsertOne) AddUsage7d(v money.Amount) *APIKeyUpsertOne {
 	return u.Update(func(s *APIKeyUpsert) {
 		s.AddUsage7d(v)
 	})
@@ -1535,6 +1805,97 @@ func (u *APIKeyUpsertOne) ClearWindow7dStart() *APIKeyUpsertOne {
 	})
 }
 
+// SetRpmLimit sets the "rpm_limit" field.
+func (u *APIKeyUpsertOne) SetRpmLimit(v int) *APIKeyUpsertOne {
+	return u.Update(func(s *APIKeyUpsert) {
+		s.SetRpmLimit(v)
+	})
+}
+
+// AddRpmLimit adds v to the "rpm_limit" field.
+func (u *APIKeyUpsertOne) AddRpmLimit(v int) *APIKeyUpsertOne {
+	return u.Update(func(s *APIKeyUpsert) {
+		s.AddRpmLimit(v)
+	})
+}
+
+// UpdateRpmLimit sets the "rpm_limit" field to the value that was provided on create.
+func (u *APIKeyUpsertOne) UpdateRpmLimit() *APIKeyUpsertOne {
+	return u.Update(func(s *APIKeyUpsert) {
+		s.UpdateRpmLimit()
+	})
+}
+
+// SetTpmLimit sets the "tpm_limit" field.
+func (u *APIKeyUpsertOne) SetTpmLimit(v int) *APIKeyUpsertOne {
+	return u.Update(func(s *APIKeyUpsert) {
+		s.SetTpmLimit(v)
+	})
+}
+
+// AddTpmLimit adds v to the "tpm_limit" field.
+func (u *APIKeyUpsertOne) AddTpmLimit(v int) *APIKeyUpsertOne {
+	return u.Update(func(s *APIKeyUpsert) {
+		s.AddTpmLimit(v)
+	})
+}
+
+// UpdateTpmLimit sets the "tpm_limit" field to the value that was provided on create.
+func (u *APIKeyUpsertOne) UpdateTpmLimit() *APIKeyUpsertOne {
+	return u.Update(func(s *APIKeyUpsert) {
+		s.UpdateTpmLimit()
+	})
+}
+
+// SetMaxConcurrency sets the "max_concurrency" field.
+func (u *APIKeyUpsertOne) SetMaxConcurrency(v int) *APIKeyUpsertOne {
+	return u.Update(func(s *APIKeyUpsert) {
+		s.SetMaxConcurrency(v)
+	})
+}
+
+// AddMaxConcurrency adds v to the "max_concurrency" field.
+func (u *APIKeyUpsertOne) AddMaxConcurrency(v int) *APIKeyUpsertOne {
+	return u.Update(func(s *APIKeyUpsert) {
+		s.AddMaxConcurrency(v)
+	})
+}
+
+// UpdateMaxConcurrency sets the "max_concurrency" field to the value that was provided on create.
+func (u *APIKeyUpsertOne) UpdateMaxConcurrency() *APIKeyUpsertOne {
+	return u.Update(func(s *APIKeyUpsert) {
+		s.UpdateMaxConcurrency()
+	})
+}
+
+// SetCreatedByUserID sets the "created_by_user_id" field.
+func (u *APIKeyUpsertOne) SetCreatedByUserID(v int64) *APIKeyUpsertOne {
+	return u.Update(func(s *APIKeyUpsert) {
+		s.SetCreatedByUserID(v)
+	})
+}
+
+// AddCreatedByUserID adds v to the "created_by_user_id" field.
+func (u *APIKeyUpsertOne) AddCreatedByUserID(v int64) *APIKeyUpsertOne {
+	return u.Update(func(s *APIKeyUpsert) {
+		s.AddCreatedByUserID(v)
+	})
+}
+
+// UpdateCreatedByUserID sets the "created_by_user_id" field to the value that was provided on create.
+func (u *APIKeyUpsertOne) UpdateCreatedByUserID() *APIKeyUpsertOne {
+	return u.Update(func(s *APIKeyUpsert) {
+		s.UpdateCreatedByUserID()
+	})
+}
+
+// ClearCreatedByUserID clears the value of the "created_by_user_id" field.
+func (u *APIKeyUpsertOne) ClearCreatedByUserID() *APIKeyUpsertOne {
+	return u.Update(func(s *APIKeyUpsert) {
+		s.ClearCreatedByUserID()
+	})
+}
+
 // Exec executes the query.
 func (u *APIKeyUpsertOne) Exec(ctx context.Context) error {
 	if len(u.create.conflict) == 0 {
@@ -1921,15 +2282,57 @@ func (u *APIKeyUpsertBulk) ClearIPBlacklist() *APIKeyUpsertBulk {
 	})
 }
 
+// SetModelAllowlist sets the "model_allowlist" field.
+func (u *APIKeyUpsertBulk) SetModelAllowlist(v []string) *APIKeyUpsertBulk {
+	return u.Update(func(s *APIKeyUpsert) {
+		s.SetModelAllowlist(v)
+	})
+}
+
+// UpdateModelAllowlist sets the "model_allowlist" field to the value that was provided on create.
+func (u *APIKeyUpsertBulk) UpdateModelAllowlist() *APIKeyUpsertBulk {
+	return u.Update(func(s *APIKeyUpsert) {
+		s.UpdateModelAllowlist()
+	})
+}
+
+// ClearModelAllowlist clears the value of the "model_allowlist" field.
+func (u *APIKeyUpsertBulk) ClearModelAllowlist() *APIKeyUpsertBulk {
+	return u.Update(func(s *APIKeyUpsert) {
+		s.ClearModelAllowlist()
+	})
+}
+
+// SetModelDenylist sets the "model_denylist" field.
+func (u *APIKeyUpsertBulk) SetModelDenylist(v []string) *APIKeyUpsertBulk {
+	return u.Update(func(s *APIKeyUpsert) {
+		s.SetModelDenylist(v)
+	})
+}
+
+// UpdateModelDenylist sets the "model_denylist" field to the value that was provided on create.
+func (u *APIKeyUpsertBulk) UpdateModelDenylist() *APIKeyUpsertBulk {
+	return u.Update(func(s *APIKeyUpsert) {
+		s.UpdateModelDenylist()
+	})
+}
+
+// ClearModelDenylist clears the value of the "model_denylist" field.
+func (u *APIKeyUpsertBulk) ClearModelDenylist() *APIKeyUpsertBulk {
+	return u.Update(func(s *APIKeyUpsert) {
+		s.ClearModelDenylist()
+	})
+}
+
 // SetQuota sets the "quota" field.
-func (u *APIKeyUpsertBulk) SetQuota(v float64) *APIKeyUpsertBulk {
+func (u *APIKeyUpsertBulk) SetQuota(v money.Amount) *APIKeyUpsertBulk {
 	return u.Update(func(s *APIKeyUpsert) {
 		s.SetQuota(v)
 	})
 }
 
 // AddQuota adds v to the "quota" field.
-func (u *APIKeyUpsertBulk) AddQuota(v float64) *APIKeyUpsertBulk {
+func (u *APIKeyUpsertBulk) AddQuota(v money.Amount) *APIKeyUpsertBulk {
 	return u.Update(func(s *APIKeyUpsert) {
 		s.AddQuota(v)
 	})
@@ -1943,14 +2346,14 @@ func (u *APIKeyUpsertBulk) UpdateQuota() *APIKeyUpsertBulk {
 }
 
 // SetQuotaUsed sets the "quota_used" field.
-func (u *APIKeyUpsertBulk) SetQuotaUsed(v float64) *APIKeyUpsertBulk {
+func (u *APIKeyUpsertBulk) SetQuotaUsed(v money.Amount) *APIKeyUpsertBulk {
 	return u.Update(func(s *APIKeyUpsert) {
 		s.SetQuotaUsed(v)
 	})
 }
 
 // AddQuotaUsed adds v to the "quota_used" field.
-func (u *APIKeyUpsertBulk) AddQuotaUsed(v float64) *APIKeyUpsertBulk {
+func (u *APIKeyUpsertBulk) AddQuotaUsed(v money.Amount) *APIKeyUpsertBulk {
 	return u.Update(func(s *APIKeyUpsert) {
 		s.AddQuotaUsed(v)
 	})
@@ -1985,14 +2388,14 @@ func (u *APIKeyUpsertBulk) ClearExpiresAt() *APIKeyUpsertBulk {
 }
 
 // SetRateLimit5h sets the "rate_limit_5h" field.
-func (u *APIKeyUpsertBulk) SetRateLimit5h(v float64) *APIKeyUpsertBulk {
+func (u *APIKeyUpsertBulk) SetRateLimit5h(v money.Amount) *APIKeyUpsertBulk {
 	return u.Update(func(s *APIKeyUpsert) {
 		s.SetRateLimit5h(v)
 	})
 }
 
 // AddRateLimit5h adds v to the "rate_limit_5h" field.
-func (u *APIKeyUpsertBulk) AddRateLimit5h(v float64) *APIKeyUpsertBulk {
+func (u *APIKeyUpsertBulk) AddRateLimit5h(v money.Amount) *APIKeyUpsertBulk {
 	return u.Update(func(s *APIKeyUpsert) {
 		s.AddRateLimit5h(v)
 	})
@@ -2006,14 +2409,14 @@ func (u *APIKeyUpsertBulk) UpdateRateLimit5h() *APIKeyUpsertBulk {
 }
 
 // SetRateLimit1d sets the "rate_limit_1d" field.
-func (u *APIKeyUpsertBulk) SetRateLimit1d(v float64) *APIKeyUpsertBulk {
+func (u *APIKeyUpsertBulk) SetRateLimit1d(v money.Amount) *APIKeyUpsertBulk {
 	return u.Update(func(s *APIKeyUpsert) {
 		s.SetRateLimit1d(v)
 	})
 }
 
 // AddRateLimit1d adds v to the "rate_limit_1d" field.
-func (u *APIKeyUpsertBulk) AddRateLimit1d(v float64) *APIKeyUpsertBulk {
+func (u *APIKeyUpsertBulk) AddRateLimit1d(v money.Amount) *APIKeyUpsertBulk {
 	return u.Update(func(s *APIKeyUpsert) {
 		s.AddRateLimit1d(v)
 	})
@@ -2027,14 +2430,14 @@ func (u *APIKeyUpsertBulk) UpdateRateLimit1d() *APIKeyUpsertBulk {
 }
 
 // SetRateLimit7d sets the "rate_limit_7d" field.
-func (u *APIKeyUpsertBulk) SetRateLimit7d(v float64) *APIKeyUpsertBulk {
+func (u *APIKeyUpsertBulk) SetRateLimit7d(v money.Amount) *APIKeyUpsertBulk {
 	return u.Update(func(s *APIKeyUpsert) {
 		s.SetRateLimit7d(v)
 	})
 }
 
 // AddRateLimit7d adds v to the "rate_limit_7d" field.
-func (u *APIKeyUpsertBulk) AddRateLimit7d(v float64) *APIKeyUpsertBulk {
+func (u *APIKeyUpsertBulk) AddRateLimit7d(v money.Amount) *APIKeyUpsertBulk {
 	return u.Update(func(s *APIKeyUpsert) {
 		s.AddRateLimit7d(v)
 	})
@@ -2048,14 +2451,14 @@ func (u *APIKeyUpsertBulk) UpdateRateLimit7d() *APIKeyUpsertBulk {
 }
 
 // SetUsage5h sets the "usage_5h" field.
-func (u *APIKeyUpsertBulk) SetUsage5h(v float64) *APIKeyUpsertBulk {
+func (u *APIKeyUpsertBulk) SetUsage5h(v money.Amount) *APIKeyUpsertBulk {
 	return u.Update(func(s *APIKeyUpsert) {
 		s.SetUsage5h(v)
 	})
 }
 
 // AddUsage5h adds v to the "usage_5h" field.
-func (u *APIKeyUpsertBulk) AddUsage5h(v float64) *APIKeyUpsertBulk {
+func (u *APIKeyUpsertBulk) AddUsage5h(v money.Amount) *APIKeyUpsertBulk {
 	return u.Update(func(s *APIKeyUpsert) {
 		s.AddUsage5h(v)
 	})
@@ -2069,14 +2472,14 @@ func (u *APIKeyUpsertBulk) UpdateUsage5h() *APIKeyUpsertBulk {
 }
 
 // SetUsage1d sets the "usage_1d" field.
-func (u *APIKeyUpsertBulk) SetUsage1d(v float64) *APIKeyUpsertBulk {
+func (u *APIKeyUpsertBulk) SetUsage1d(v money.Amount) *APIKeyUpsertBulk {
 	return u.Update(func(s *APIKeyUpsert) {
 		s.SetUsage1d(v)
 	})
 }
 
 // AddUsage1d adds v to the "usage_1d" field.
-func (u *APIKeyUpsertBulk) AddUsage1d(v float64) *APIKeyUpsertBulk {
+func (u *APIKeyUpsertBulk) AddUsage1d(v money.Amount) *APIKeyUpsertBulk {
 	return u.Update(func(s *APIKeyUpsert) {
 		s.AddUsage1d(v)
 	})
@@ -2090,14 +2493,14 @@ func (u *APIKeyUpsertBulk) UpdateUsage1d() *APIKeyUpsertBulk {
 }
 
 // SetUsage7d sets the "usage_7d" field.
-func (u *APIKeyUpsertBulk) SetUsage7d(v float64) *APIKeyUpsertBulk {
+func (u *APIKeyUpsertBulk) SetUsage7d(v money.Amount) *APIKeyUpsertBulk {
 	return u.Update(func(s *APIKeyUpsert) {
 		s.SetUsage7d(v)
 	})
 }
 
 // AddUsage7d adds v to the "usage_7d" field.
-func (u *APIKeyUpsertBulk) AddUsage7d(v float64) *APIKeyUpsertBulk {
+func (u *APIKeyUpsertBulk) AddUsage7d(v money.Amount) *APIKeyUpsertBulk {
 	return u.Update(func(s *APIKeyUpsert) {
 		s.AddUsage7d(v)
 	})
@@ -2173,6 +2576,97 @@ func (u *APIKeyUpsertBulk) ClearWindow7dStart() *APIKeyUpsertBulk {
 	})
 }
 
+// SetRpmLimit sets the "rpm_limit" field.
+func (u *APIKeyUpsertBulk) SetRpmLimit(v int) *APIKeyUpsertBulk {
+	return u.Update(func(s *APIKeyUpsert) {
+		s.SetRpmLimit(v)
+	})
+}
+
+// AddRpmLimit adds v to the "rpm_limit" field.
+func (u *APIKeyUpsertBulk) AddRpmLimit(v int) *APIKeyUpsertBulk {
+	return u.Update(func(s *APIKeyUpsert) {
+		s.AddRpmLimit(v)
+	})
+}
+
+// UpdateRpmLimit sets the "rpm_limit" field to the value that was provided on create.
+func (u *APIKeyUpsertBulk) UpdateRpmLimit() *APIKeyUpsertBulk {
+	return u.Update(func(s *APIKeyUpsert) {
+		s.UpdateRpmLimit()
+	})
+}
+
+// SetTpmLimit sets the "tpm_limit" field.
+func (u *APIKeyUpsertBulk) SetTpmLimit(v int) *APIKeyUpsertBulk {
+	return u.Update(func(s *APIKeyUpsert) {
+		s.SetTpmLimit(v)
+	})
+}
+
+// AddTpmLimit adds v to the "tpm_limit" field.
+func (u *APIKeyUpsertBulk) AddTpmLimit(v int) *APIKeyUpsertBulk {
+	return u.Update(func(s *APIKeyUpsert) {
+		s.AddTpmLimit(v)
+	})
+}
+
+// UpdateTpmLimit sets the "tpm_limit" field to the value that was provided on create.
+func (u *APIKeyUpsertBulk) UpdateTpmLimit() *APIKeyUpsertBulk {
+	return u.Update(func(s *APIKeyUpsert) {
+		s.UpdateTpmLimit()
+	})
+}
+
+// SetMaxConcurrency sets the "max_concurrency" field.
+func (u *APIKeyUpsertBulk) SetMaxConcurrency(v int) *APIKeyUpsertBulk {
+	return u.Update(func(s *APIKeyUpsert) {
+		s.SetMaxConcurrency(v)
+	})
+}
+
+// AddMaxConcurrency adds v to the "max_concurrency" field.
+func (u *APIKeyUpsertBulk) AddMaxConcurrency(v int) *APIKeyUpsertBulk {
+	return u.Update(func(s *APIKeyUpsert) {
+		s.AddMaxConcurrency(v)
+	})
+}
+
+// UpdateMaxConcurrency sets the "max_concurrency" field to the value that was provided on create.
+func (u *APIKeyUpsertBulk) UpdateMaxConcurrency() *APIKeyUpsertBulk {
+	return u.Update(func(s *APIKeyUpsert) {
+		s.UpdateMaxConcurrency()
+	})
+}
+
+// SetCreatedByUserID sets the "created_by_user_id" field.
+func (u *APIKeyUpsertBulk) SetCreatedByUserID(v int64) *APIKeyUpsertBulk {
+	return u.Update(func(s *APIKeyUpsert) {
+		s.SetCreatedByUserID(v)
+	})
+}
+
+// AddCreatedByUserID adds v to the "created_by_user_id" field.
+func (u *APIKeyUpsertBulk) AddCreatedByUserID(v int64) *APIKeyUpsertBulk {
+	return u.Update(func(s *APIKeyUpsert) {
+		s.AddCreatedByUserID(v)
+	})
+}
+
+// UpdateCreatedByUserID sets the "created_by_user_id" field to the value that was provided on create.
+func (u *APIKeyUpsertBulk) UpdateCreatedByUserID() *APIKeyUpsertBulk {
+	return u.Update(func(s *APIKeyUpsert) {
+		s.UpdateCreatedByUserID()
+	})
+}
+
+// ClearCreatedByUserID clears the value of the "created_by_user_id" field.
+func (u *APIKeyUpsertBulk) ClearCreatedByUserID() *APIKeyUpsertBulk {
+	return u.Update(func(s *APIKeyUpsert) {
+		s.ClearCreatedByUserID()
+	})
+}
+
 // Exec executes the query.
 func (u *APIKeyUpsertBulk) Exec(ctx context.Context) error {
 	if u.create.err != nil {
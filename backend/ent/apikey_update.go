@@ -17,6 +17,7 @@ import (
 	"github.com/Wei-Shaw/sub2api/ent/predicate"
 	"github.com/Wei-Shaw/sub2api/ent/usagelog"
 	"github.com/Wei-Shaw/sub2api/ent/user"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
 )
 
 // APIKeyUpdate is the builder for updating APIKey entities.
@@ -190,15 +191,51 @@ func (_u *APIKeyUpdate) ClearIPBlacklist() *APIKeyUpdate {
 	return _u
 }
 
+// SetModelAllowlist sets the "model_allowlist" field.
+func (_u *APIKeyUpdate) SetModelAllowlist(v []string) *APIKeyUpdate {
+	_u.mutation.SetModelAllowlist(v)
+	return _u
+}
+
+// AppendModelAllowlist appends value to the "model_allowlist" field.
+func (_u *APIKeyUpdate) AppendModelAllowlist(v []string) *APIKeyUpdate {
+	_u.mutation.AppendModelAllowlist(v)
+	return _u
+}
+
+// ClearModelAllowlist clears the value of the "model_allowlist" field.
+func (_u *APIKeyUpdate) ClearModelAllowlist() *APIKeyUpdate {
+	_u.mutation.ClearModelAllowlist()
+	return _u
+}
+
+// SetModelDenylist sets the "model_denylist" field.
+func (_u *APIKeyUpdate) SetModelDenylist(v []string) *APIKeyUpdate {
+	_u.mutation.SetModelDenylist(v)
+	return _u
+}
+
+// AppendModelDenylist appends value to the "model_denylist" field.
+func (_u *APIKeyUpdate) AppendModelDenylist(v []string) *APIKeyUpdate {
+	_u.mutation.AppendModelDenylist(v)
+	return _u
+}
+
+// ClearModelDenylist clears the value of the "model_denylist" field.
+func (_u *APIKeyUpdate) ClearModelDenylist() *APIKeyUpdate {
+	_u.mutation.ClearModelDenylist()
+	return _u
+}
+
 // SetQuota sets the "quota" field.
-func (_u *APIKeyUpdate) SetQuota(v float64) *APIKeyUpdate {
+func (_u *APIKeyUpdate) SetQuota(v money.Amount) *APIKeyUpdate {
 	_u.mutation.ResetQuota()
 	_u.mutation.SetQuota(v)
 	return _u
 }
 
 // SetNillableQuota sets the "quota" field if the given value is not nil.
-func (_u *APIKeyUpdate) SetNillableQuota(v *float64) *APIKeyUpdate {
+func (_u *APIKeyUpdate) SetNillableQuota(v *money.Amount) *APIKeyUpdate {
 	if v != nil {
 		_u.SetQuota(*v)
 	}
@@ -206,20 +243,20 @@ func (_u *APIKeyUpdate) SetNillableQuota(v *float64) *APIKeyUpdate {
 }
 
 // AddQuota adds value to the "quota" field.
-func (_u *APIKeyUpdate) AddQuota(v float64) *APIKeyUpdate {
+func (_u *APIKeyUpdate) AddQuota(v money.Amount) *APIKeyUpdate {
 	_u.mutation.AddQuota(v)
 	return _u
 }
 
 // SetQuotaUsed sets the "quota_used" field.
-func (_u *APIKeyUpdate) SetQuotaUsed(v float64) *APIKeyUpdate {
+func (_u *APIKeyUpdate) SetQuotaUsed(v money.Amount) *APIKeyUpdate {
 	_u.mutation.ResetQuotaUsed()
 	_u.mutation.SetQuotaUsed(v)
 	return _u
 }
 
 // SetNillableQuotaUsed sets the "quota_used" field if the given value is not nil.
-func (_u *APIKeyUpdate) SetNillableQuotaUsed(v *float64) *APIKeyUpdate {
+func (_u *APIKeyUpdate) SetNillableQuotaUsed(v *money.Amount) *APIKeyUpdate {
 	if v != nil {
 		_u.SetQuotaUsed(*v)
 	}
@@ -227,7 +264,7 @@ func (_u *APIKeyUpdate) SetNillableQuotaUsed(v *float64) *APIKeyUpdate {
 }
 
 // AddQuotaUsed adds value to the "quota_used" field.
-func (_u *APIKeyUpdate) AddQuotaUsed(v float64) *APIKeyUpdate {
+func (_u *APIKeyUpdate) AddQuotaUsed(v money.Amount) *APIKeyUpdate {
 	_u.mutation.AddQuotaUsed(v)
 	return _u
 }
@@ -253,14 +290,14 @@ func (_u *APIKeyUpdate) ClearExpiresAt() *APIKeyUpdate {
 }
 
 // SetRateLimit5h sets the "rate_limit_5h" field.
-func (_u *APIKeyUpdate) SetRateLimit5h(v float64) *APIKeyUpdate {
+func (_u *APIKeyUpdate) SetRateLimit5h(v money.Amount) *APIKeyUpdate {
 	_u.mutation.ResetRateLimit5h()
 	_u.mutation.SetRateLimit5h(v)
 	return _u
 }
 
 // SetNillableRateLimit5h sets the "rate_limit_5h" field if the given value is not nil.
-func (_u *APIKeyUpdate) SetNillableRateLimit5h(v *float64) *APIKeyUpdate {
+func (_u *APIKeyUpdate) SetNillableRateLimit5h(v *money.Amount) *APIKeyUpdate {
 	if v != nil {
 		_u.SetRateLimit5h(*v)
 	}
@@ -268,20 +305,20 @@ func (_u *APIKeyUpdate) SetNillableRateLimit5h(v *float64) *APIKeyUpdate {
 }
 
 // AddRateLimit5h adds value to the "rate_limit_5h" field.
-func (_u *APIKeyUpdate) AddRateLimit5h(v float64) *APIKeyUpdate {
+func (_u *APIKeyUpdate) AddRateLimit5h(v money.Amount) *APIKeyUpdate {
 	_u.mutation.AddRateLimit5h(v)
 	return _u
 }
 
 // SetRateLimit1d sets the "rate_limit_1d" field.
-func (_u *APIKeyUpdate) SetRateLimit1d(v float64) *APIKeyUpdate {
+func (_u *APIKeyUpdate) SetRateLimit1d(v money.Amount) *APIKeyUpdate {
 	_u.mutation.ResetRateLimit1d()
 	_u.mutation.SetRateLimit1d(v)
 	return _u
 }
 
 // SetNillableRateLimit1d sets the "rate_limit_1d" field if the given value is not nil.
-func (_u *APIKeyUpdate) SetNillableRateLimit1d(v *float64) *APIKeyUpdate {
+func (_u *APIKeyUpdate) SetNillableRateLimit1d(v *money.Amount) *APIKeyUpdate {
 	if v != nil {
 		_u.SetRateLimit1d(*v)
 	}
@@ -289,20 +326,20 @@ func (_u *APIKeyUpdate) SetNillableRateLimit1d(v *float64) *APIKeyUpdate {
 }
 
 // AddRateLimit1d adds value to the "rate_limit_1d" field.
-func (_u *APIKeyUpdate) AddRateLimit1d(v float64) *APIKeyUpdate {
+func (_u *APIKeyUpdate) AddRateLimit1d(v money.Amount) *APIKeyUpdate {
 	_u.mutation.AddRateLimit1d(v)
 	return _u
 }
 
 // SetRateLimit7d sets the "rate_limit_7d" field.
-func (_u *APIKeyUpdate) SetRateLimit7d(v float64) *APIKeyUpdate {
+func (_u *APIKeyUpdate) SetRateLimit7d(v money.Amount) *APIKeyUpdate {
 	_u.mutation.ResetRateLimit7d()
 	_u.mutation.SetRateLimit7d(v)
 	return _u
 }
 
 // SetNillableRateLimit7d sets the "rate_limit_7d" field if the given value is not nil.
-func (_u *APIKeyUpdate) SetNillableRateLimit7d(v *float64) *APIKeyUpdate {
+func (_u *APIKeyUpdate) SetNillableRateLimit7d(v *money.Amount) *APIKeyUpdate {
 	if v != nil {
 		_u.SetRateLimit7d(*v)
 	}
@@ -310,20 +347,20 @@ func (_u *APIKeyUpdate) SetNillableRateLimit7d(v *float64) *APIKeyUpdate {
 }
 
 // AddRateLimit7d adds value to the "rate_limit_7d" field.
-func (_u *APIKeyUpdate) AddRateLimit7d(v float64) *APIKeyUpdate {
+func (_u *APIKeyUpdate) AddRateLimit7d(v money.Amount) *APIKeyUpdate {
 	_u.mutation.AddRateLimit7d(v)
 	return _u
 }
 
 // SetUsage5h sets the "usage_5h" field.
-func (_u *APIKeyUpdate) SetUsage5h(v float64) *APIKeyUpdate {
+func (_u *APIKeyUpdate) SetUsage5h(v money.Amount) *APIKeyUpdate {
 	_u.mutation.ResetUsage5h()
 	_u.mutation.SetUsage5h(v)
 	return _u
 }
 
 // SetNillableUsage5h sets the "usage_5h" field if the given value is not nil.
-func (_u *APIKeyUpdate) SetNillableUsage5h(v *float64) *APIKeyUpdate {
+func (_u *APIKeyUpdate) SetNillableUsage5h(v *money.Amount) *APIKeyUpdate {
 	if v != nil {
 		_u.SetUsage5h(*v)
 	}
@@ -331,20 +368,20 @@ func (_u *APIKeyUpdate) SetNillableUsage5h(v *float64) *APIKeyUpdate {
 }
 
 // AddUsage5h adds value to the "usage_5h" field.
-func (_u *APIKeyUpdate) AddUsage5h(v float64) *APIKeyUpdate {
+func (_u *APIKeyUpdate) AddUsage5h(v money.Amount) *APIKeyUpdate {
 	_u.mutation.AddUsage5h(v)
 	return _u
 }
 
 // SetUsage1d sets the "usage_1d" field.
-func (_u *APIKeyUpdate) SetUsage1d(v float64) *APIKeyUpdate {
+func (_u *APIKeyUpdate) SetUsage1d(v money.Amount) *APIKeyUpdate {
 	_u.mutation.ResetUsage1d()
 	_u.mutation.SetUsage1d(v)
 	return _u
 }
 
 // SetNillableUsage1d sets the "usage_1d" field if the given value is not nil.
-func (_u *APIKeyUpdate) SetNillableUsage1d(v *float64) *APIKeyUpdate {
+func (_u *APIKeyUpdate) SetNillableUsage1d(v *money.Amount) *APIKeyUpdate {
 	if v != nil {
 		_u.SetUsage1d(*v)
 	}
@@ -352,20 +389,20 @@ func (_u *APIKeyUpdate) SetNillableUsage1d(v *float64) *APIKeyUpdate {
 }
 
 // AddUsage1d adds value to the "usage_1d" field.
-func (_u *APIKeyUpdate) AddUsage1d(v float64) *APIKeyUpdate {
+func (_u *APIKeyUpdate) AddUsage1d(v money.Amount) *APIKeyUpdate {
 	_u.mutation.AddUsage1d(v)
 	return _u
 }
 
 // SetUsage7d sets the "usage_7d" field.
-func (_u *APIKeyUpdate) SetUsage7d(v float64) *APIKeyUpdate {
+func (_u *APIKeyUpdate) SetUsage7d(v money.Amount) *APIKeyUpdate {
 	_u.mutation.ResetUsage7d()
 	_u.mutation.SetUsage7d(v)
 	return _u
 }
 
 // SetNillableUsage7d sets the "usage_7d" field if the given value is not nil.
-func (_u *APIKeyUpdate) SetNillableUsage7d(v *float64) *APIKeyUpdate {
+func (_u *APIKeyUpdate) SetNillableUsage7d(v *money.Amount) *APIKeyUpdate {
 	if v != nil {
 		_u.SetUsage7d(*v)
 	}
@@ -373,7 +410,7 @@ func (_u *APIKeyUpdate) SetNillableUsage7d(v *float64) *APIKeyUpdate {
 }
 
 // AddUsage7d adds value to the "usage_7d" field.
-func (_u *APIKeyUpdate) AddUsage7d(v float64) *APIKeyUpdate {
+func (_u *APIKeyUpdate) AddUsage7d(v money.Amount) *APIKeyUpdate {
 	_u.mutation.AddUsage7d(v)
 	return _u
 }
@@ -438,6 +475,96 @@ func (_u *APIKeyUpdate) ClearWindow7dStart() *APIKeyUpdate {
 	return _u
 }
 
+// SetRpmLimit sets the "rpm_limit" field.
+func (_u *APIKeyUpdate) SetRpmLimit(v int) *APIKeyUpdate {
+	_u.mutation.ResetRpmLimit()
+	_u.mutation.SetRpmLimit(v)
+	return _u
+}
+
+// SetNillableRpmLimit sets the "rpm_limit" field if the given value is not nil.
+func (_u *APIKeyUpdate) SetNillableRpmLimit(v *int) *APIKeyUpdate {
+	if v != nil {
+		_u.SetRpmLimit(*v)
+	}
+	return _u
+}
+
+// AddRpmLimit adds value to the "rpm_limit" field.
+func (_u *APIKeyUpdate) AddRpmLimit(v int) *APIKeyUpdate {
+	_u.mutation.AddRpmLimit(v)
+	return _u
+}
+
+// SetTpmLimit sets the "tpm_limit" field.
+func (_u *APIKeyUpdate) SetTpmLimit(v int) *APIKeyUpdate {
+	_u.mutation.ResetTpmLimit()
+	_u.mutation.SetTpmLimit(v)
+	return _u
+}
+
+// SetNillableTpmLimit sets the "tpm_limit" field if the given value is not nil.
+func (_u *APIKeyUpdate) SetNillableTpmLimit(v *int) *APIKeyUpdate {
+	if v != nil {
+		_u.SetTpmLimit(*v)
+	}
+	return _u
+}
+
+// AddTpmLimit adds value to the "tpm_limit" field.
+func (_u *APIKeyUpdate) AddTpmLimit(v int) *APIKeyUpdate {
+	_u.mutation.AddTpmLimit(v)
+	return _u
+}
+
+// SetMaxConcurrency sets the "max_concurrency" field.
+func (_u *APIKeyUpdate) SetMaxConcurrency(v int) *APIKeyUpdate {
+	_u.mutation.ResetMaxConcurrency()
+	_u.mutation.SetMaxConcurrency(v)
+	return _u
+}
+
+// SetNillableMaxConcurrency sets the "max_concurrency" field if the given value is not nil.
+func (_u *APIKeyUpdate) SetNillableMaxConcurrency(v *int) *APIKeyUpdate {
+	if v != nil {
+		_u.SetMaxConcurrency(*v)
+	}
+	return _u
+}
+
+// AddMaxConcurrency adds value to the "max_concurrency" field.
+func (_u *APIKeyUpdate) AddMaxConcurrency(v int) *APIKeyUpdate {
+	_u.mutation.AddMaxConcurrency(v)
+	return _u
+}
+
+// SetCreatedByUserID sets the "created_by_user_id" field.
+func (_u *APIKeyUpdate) SetCreatedByUserID(v int64) *APIKeyUpdate {
+	_u.mutation.ResetCreatedByUserID()
+	_u.mutation.SetCreatedByUserID(v)
+	return _u
+}
+
+// SetNillableCreatedByUserID sets the "created_by_user_id" field if the given value is not nil.
+func (_u *APIKeyUpdate) SetNillableCreatedByUserID(v *int64) *APIKeyUpdate {
+	if v != nil {
+		_u.SetCreatedByUserID(*v)
+	}
+	return _u
+}
+
+// AddCreatedByUserID adds value to the "created_by_user_id" field.
+func (_u *APIKeyUpdate) AddCreatedByUserID(v int64) *APIKeyUpdate {
+	_u.mutation.AddCreatedByUserID(v)
+	return _u
+}
+
+// ClearCreatedByUserID clears the value of the "created_by_user_id" field.
+func (_u *APIKeyUpdate) ClearCreatedByUserID() *APIKeyUpdate {
+	_u.mutation.ClearCreatedByUserID()
+	return _u
+}
+
 // SetUser sets the "user" edge to the User entity.
 func (_u *APIKeyUpdate) SetUser(v *User) *APIKeyUpdate {
 	return _u.SetUserID(v.ID)
@@ -624,17 +751,39 @@ func (_u *APIKeyUpdate) sqlSave(ctx context.Context) (_node int, err error) {
 	if _u.mutation.IPBlacklistCleared() {
 		_spec.ClearField(apikey.FieldIPBlacklist, field.TypeJSON)
 	}
+	if value, ok := _u.mutation.ModelAllowlist(); ok {
+		_spec.SetField(apikey.FieldModelAllowlist, field.TypeJSON, value)
+	}
+	if value, ok := _u.mutation.AppendedModelAllowlist(); ok {
+		_spec.AddModifier(func(u *sql.UpdateBuilder) {
+			sqljson.Append(u, apikey.FieldModelAllowlist, value)
+		})
+	}
+	if _u.mutation.ModelAllowlistCleared() {
+		_spec.ClearField(apikey.FieldModelAllowlist, field.TypeJSON)
+	}
+	if value, ok := _u.mutation.ModelDenylist(); ok {
+		_spec.SetField(apikey.FieldModelDenylist, field.TypeJSON, value)
+	}
+	if value, ok := _u.mutation.AppendedModelDenylist(); ok {
+		_spec.AddModifier(func(u *sql.UpdateBuilder) {
+			sqljson.Append(u, apikey.FieldModelDenylist, value)
+		})
+	}
+	if _u.mutation.ModelDenylistCleared() {
+		_spec.ClearField(apikey.FieldModelDenylist, field.TypeJSON)
+	}
 	if value, ok := _u.mutation.Quota(); ok {
-		_spec.SetField(apikey.FieldQuota, field.TypeFloat64, value)
+		_spec.SetField(apikey.FieldQuota, field.TypeInt64, value)
 	}
 	if value, ok := _u.mutation.AddedQuota(); ok {
-		_spec.AddField(apikey.FieldQuota, field.TypeFloat64, value)
+		_spec.AddField(apikey.FieldQuota, field.TypeInt64, value)
 	}
 	if value, ok := _u.mutation.QuotaUsed(); ok {
-		_spec.SetField(apikey.FieldQuotaUsed, field.TypeFloat64, value)
+		_spec.SetField(apikey.FieldQuotaUsed, field.TypeInt64, value)
 	}
 	if value, ok := _u.mutation.AddedQuotaUsed(); ok {
-		_spec.AddField(apikey.FieldQuotaUsed, field.TypeFloat64, value)
+		_spec.AddField(apikey.FieldQuotaUsed, field.TypeInt64, value)
 	}
 	if value, ok := _u.mutation.ExpiresAt(); ok {
 		_spec.SetField(apikey.FieldExpiresAt, field.TypeTime, value)
@@ -643,40 +792,40 @@ func (_u *APIKeyUpdate) sqlSave(ctx context.Context) (_node int, err error) {
 		_spec.ClearField(apikey.FieldExpiresAt, field.TypeTime)
 	}
 	if value, ok := _u.mutation.RateLimit5h(); ok {
-		_spec.SetField(apikey.FieldRateLimit5h, field.TypeFloat64, value)
+		_spec.SetField(apikey.FieldRateLimit5h, field.TypeInt64, value)
 	}
 	if value, ok := _u.mutation.AddedRateLimit5h(); ok {
-		_spec.AddField(apikey.FieldRateLimit5h, field.TypeFloat64, value)
+		_spec.AddField(apikey.FieldRateLimit5h, field.TypeInt64, value)
 	}
 	if value, ok := _u.mutation.RateLimit1d(); ok {
-		_spec.SetField(apikey.FieldRateLimit1d, field.TypeFloat64, value)
+		_spec.SetField(apikey.FieldRateLimit1d, field.TypeInt64, value)
 	}
 	if value, ok := _u.mutation.AddedRateLimit1d(); ok {
-		_spec.AddField(apikey.FieldRateLimit1d, field.TypeFloat64, value)
+		_spec.AddField(apikey.FieldRateLimit1d, field.TypeInt64, value)
 	}
 	if value, ok := _u.mutation.RateLimit7d(); ok {
-		_spec.SetField(apikey.FieldRateLimit7d, field.TypeFloat64, value)
+		_spec.SetField(apikey.FieldRateLimit7d, field.TypeInt64, value)
 	}
 	if value, ok := _u.mutation.AddedRateLimit7d(); ok {
-		_spec.AddField(apikey.FieldRateLimit7d, field.TypeFloat64, value)
+		_spec.AddField(apikey.FieldRateLimit7d, field.TypeInt64, value)
 	}
 	if value, ok := _u.mutation.Usage5h(); ok {
-		_spec.SetField(apikey.FieldUsage5h, field.TypeFloat64, value)
+		_spec.SetField(apikey.FieldUsage5h, field.TypeInt64, value)
 	}
 	if value, ok := _u.mutation.AddedUsage5h(); ok {
-		_spec.AddField(apikey.FieldUsage5h, field.TypeFloat64, value)
+		_spec.AddField(apikey.FieldUsage5h, field.TypeInt64, value)
 	}
 	if value, ok := _u.mutation.Usage1d(); ok {
-		_spec.SetField(apikey.FieldUsage1d, field.TypeFloat64, value)
+		_spec.SetField(apikey.FieldUsage1d, field.TypeInt64, value)
 	}
 	if value, ok := _u.mutation.AddedUsage1d(); ok {
-		_spec.AddField(apikey.FieldUsage1d, field.TypeFloat64, value)
+		_spec.AddField(apikey.FieldUsage1d, field.TypeInt64, value)
 	}
 	if value, ok := _u.mutation.Usage7d(); ok {
-		_spec.SetField(apikey.FieldUsage7d, field.TypeFloat64, value)
+		_spec.SetField(apikey.FieldUsage7d, field.TypeInt64, value)
 	}
 	if value, ok := _u.mutation.AddedUsage7d(); ok {
-		_spec.AddField(apikey.FieldUsage7d, field.TypeFloat64, value)
+		_spec.AddField(apikey.FieldUsage7d, field.TypeInt64, value)
 	}
 	if value, ok := _u.mutation.Window5hStart(); ok {
 		_spec.SetField(apikey.FieldWindow5hStart, field.TypeTime, value)
@@ -696,6 +845,33 @@ func (_u *APIKeyUpdate) sqlSave(ctx context.Context) (_node int, err error) {
 	if _u.mutation.Window7dStartCleared() {
 		_spec.ClearField(apikey.FieldWindow7dStart, field.TypeTime)
 	}
+	if value, ok := _u.mutation.RpmLimit(); ok {
+		_spec.SetField(apikey.FieldRpmLimit, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.AddedRpmLimit(); ok {
+		_spec.AddField(apikey.FieldRpmLimit, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.TpmLimit(); ok {
+		_spec.SetField(apikey.FieldTpmLimit, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.AddedTpmLimit(); ok {
+		_spec.AddField(apikey.FieldTpmLimit, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.MaxConcurrency(); ok {
+		_spec.SetField(apikey.FieldMaxConcurrency, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.AddedMaxConcurrency(); ok {
+		_spec.AddField(apikey.FieldMaxConcurrency, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.CreatedByUserID(); ok {
+		_spec.SetField(apikey.FieldCreatedByUserID, field.TypeInt64, value)
+	}
+	if value, ok := _u.mutation.AddedCreatedByUserID(); ok {
+		_spec.AddField(apikey.FieldCreatedByUserID, field.TypeInt64, value)
+	}
+	if _u.mutation.CreatedByUserIDCleared() {
+		_spec.ClearField(apikey.FieldCreatedByUserID, field.TypeInt64)
+	}
 	if _u.mutation.UserCleared() {
 		edge := &sqlgraph.EdgeSpec{
 			Rel:     sqlgraph.M2O,
@@ -977,15 +1153,51 @@ func (_u *APIKeyUpdateOne) ClearIPBlacklist() *APIKeyUpdateOne {
 	return _u
 }
 
+// SetModelAllowlist sets the "model_allowlist" field.
+func (_u *APIKeyUpdateOne) SetModelAllowlist(v []string) *APIKeyUpdateOne {
+	_u.mutation.SetModelAllowlist(v)
+	return _u
+}
+
+// AppendModelAllowlist appends value to the "model_allowlist" field.
+func (_u *APIKeyUpdateOne) AppendModelAllowlist(v []string) *APIKeyUpdateOne {
+	_u.mutation.AppendModelAllowlist(v)
+	return _u
+}
+
+// ClearModelAllowlist clears the value of the "model_allowlist" field.
+func (_u *APIKeyUpdateOne) ClearModelAllowlist() *APIKeyUpdateOne {
+	_u.mutation.ClearModelAllowlist()
+	return _u
+}
+
+// SetModelDenylist sets the "model_denylist" field.
+func (_u *APIKeyUpdateOne) SetModelDenylist(v []string) *APIKeyUpdateOne {
+	_u.mutation.SetModelDenylist(v)
+	return _u
+}
+
+// AppendModelDenylist appends value to the "model_denylist" field.
+func (_u *APIKeyUpdateOne) AppendModelDenylist(v []string) *APIKeyUpdateOne {
+	_u.mutation.AppendModelDenylist(v)
+	return _u
+}
+
+// ClearModelDenylist clears the value of the "model_denylist" field.
+func (_u *APIKeyUpdateOne) ClearModelDenylist() *APIKeyUpdateOne {
+	_u.mutation.ClearModelDenylist()
+	return _u
+}
+
 // SetQuota sets the "quota" field.
-func (_u *APIKeyUpdateOne) SetQuota(v float64) *APIKeyUpdateOne {
+func (_u *APIKeyUpdateOne) SetQuota(v money.Amount) *APIKeyUpdateOne {
 	_u.mutation.ResetQuota()
 	_u.mutation.SetQuota(v)
 	return _u
 }
 
 // SetNillableQuota sets the "quota" field if the given value is not nil.
-func (_u *APIKeyUpdateOne) SetNillableQuota(v *float64) *APIKeyUpdateOne {
+func (_u *APIKeyUpdateOne) SetNillableQuota(v *money.Amount) *APIKeyUpdateOne {
 	if v != nil {
 		_u.SetQuota(*v)
 	}
@@ -993,20 +1205,20 @@ func (_u *APIKeyUpdateOne) SetNillableQuota(v *float64) *APIKeyUpdateOne {
 }
 
 // AddQuota adds value to the "quota" field.
-func (_u *APIKeyUpdateOne) AddQuota(v float64) *APIKeyUpdateOne {
+func (_u *APIKeyUpdateOne) AddQuota(v money.Amount) *APIKeyUpdateOne {
 	_u.mutation.AddQuota(v)
 	return _u
 }
 
 // SetQuotaUsed sets the "quota_used" field.
-func (_u *APIKeyUpdateOne) SetQuotaUsed(v float64) *APIKeyUpdateOne {
+func (_u *APIKeyUpdateOne) SetQuotaUsed(v money.Amount) *APIKeyUpdateOne {
 	_u.mutation.ResetQuotaUsed()
 	_u.mutation.SetQuotaUsed(v)
 	return _u
 }
 
 // SetNillableQuotaUsed sets the "quota_used" field if the given value is not nil.
-func (_u *APIKeyUpdateOne) SetNillableQuotaUsed(v *float64) *APIKeyUpdateOne {
+func (_u *APIKeyUpdateOne) SetNillableQuotaUsed(v *money.Amount) *APIKeyUpdateOne {
 	if v != nil {
 		_u.SetQuotaUsed(*v)
 	}
@@ -1014,7 +1226,7 @@ func (_u *APIKeyUpdateOne) SetNillableQuotaUsed(v *float64) *APIKeyUpdateOne {
 }
 
 // AddQuotaUsed adds value to the "quota_used" field.
-func (_u *APIKeyUpdateOne) AddQuotaUsed(v float64) *APIKeyUpdateOne {
+func (_u *APIKeyUpdateOne) AddQuotaUsed(v money.Amount) *APIKeyUpdateOne {
 	_u.mutation.AddQuotaUsed(v)
 	return _u
 }
@@ -1040,14 +1252,14 @@ func (_u *APIKeyUpdateOne) ClearExpiresAt() *APIKeyUpdateOne {
 }
 
 // SetRateLimit5h sets the "rate_limit_5h" field.
-func (_u *APIKeyUpdateOne) SetRateLimit5h(v float64) *APIKeyUpdateOne {
+func (_u *APIKeyUpdateOne) SetRateLimit5h(v money.Amount) *APIKeyUpdateOne {
 	_u.mutation.ResetRateLimit5h()
 	_u.mutation.SetRateLimit5h(v)
 	return _u
 }
 
 // SetNillableRateLimit5h sets the "rate_limit_5h" field if the given value is not nil.
-func (_u *APIKeyUpdateOne) SetNillableRateLimit5h(v *float64) *APIKeyUpdateOne {
+func (_u *APIKeyUpdateOne) SetNillableRateLimit5h(v *money.Amount) *APIKeyUpdateOne {
 	if v != nil {
 		_u.SetRateLimit5h(*v)
 	}
@@ -1055,20 +1267,20 @@ func (_u *APIKeyUpdateOne) SetNillableRateLimit5h(v *float64) *APIKeyUpdateOne {
 }
 
 // AddRateLimit5h adds value to the "rate_limit_5h" field.
-func (_u *APIKeyUpdateOne) AddRateLimit5h(v float64) *APIKeyUpdateOne {
+func (_u *APIKeyUpdateOne) AddRateLimit5h(v money.Amount) *APIKeyUpdateOne {
 	_u.mutation.AddRateLimit5h(v)
 	return _u
 }
 
 // SetRateLimit1d sets the "rate_limit_1d" field.
-func (_u *APIKeyUpdateOne) SetRateLimit1d(v float64) *APIKeyUpdateOne {
+func (_u *APIKeyUpdateOne) SetRateLimit1d(v money.Amount) *APIKeyUpdateOne {
 	_u.mutation.ResetRateLimit1d()
 	_u.mutation.SetRateLimit1d(v)
 	return _u
 }
 
 // SetNillableRateLimit1d sets the "rate_limit_1d" field if the given value is not nil.
-func (_u *APIKeyUpdateOne) SetNillableRateLimit1d(v *float64) *APIKeyUpdateOne {
+func (_u *APIKeyUpdateOne) SetNillableRateLimit1d(v *money.Amount) *APIKeyUpdateOne {
 	if v != nil {
 		_u.SetRateLimit1d(*v)
 	}
@@ -1076,20 +1288,20 @@ func (_u *APIKeyUpdateOne) SetNillableRateLimit1d(v *float64) *APIKeyUpdateOne {
 }
 
 // AddRateLimit1d adds value to the "rate_limit_1d" field.
-func (_u *APIKeyUpdateOne) AddRateLimit1d(v float64) *APIKeyUpdateOne {
+func (_u *APIKeyUpdateOne) AddRateLimit1d(v money.Amount) *APIKeyUpdateOne {
 	_u.mutation.AddRateLimit1d(v)
 	return _u
 }
 
 // SetRateLimit7d sets the "rate_limit_7d" field.
-func (_u *APIKeyUpdateOne) SetRateLimit7d(v float64) *APIKeyUpdateOne {
+func (_u *APIKeyUpdateOne) SetRateLimit7d(v money.Amount) *APIKeyUpdateOne {
 	_u.mutation.ResetRateLimit7d()
 	_u.mutation.SetRateLimit7d(v)
 	return _u
 }
 
 // SetNillableRateLimit7d sets the "rate_limit_7d" field if the given value is not nil.
-func (_u *APIKeyUpdateOne) SetNillableRateLimit7d(v *float64) *APIKeyUpdateOne {
+func (_u *APIKeyUpdateOne) SetNillableRateLimit7d(v *money.Amount) *APIKeyUpdateOne {
 	if v != nil {
 		_u.SetRateLimit7d(*v)
 	}
@@ -1097,20 +1309,20 @@ func (_u *APIKeyUpdateOne) SetNillableRateLimit7d(v *float64) *APIKeyUpdateOne {
 }
 
 // AddRateLimit7d adds value to the "rate_limit_7d" field.
-func (_u *APIKeyUpdateOne) AddRateLimit7d(v float64) *APIKeyUpdateOne {
+func (_u *APIKeyUpdateOne) AddRateLimit7d(v money.Amount) *APIKeyUpdateOne {
 	_u.mutation.AddRateLimit7d(v)
 	return _u
 }
 
 // SetUsage5h sets the "usage_5h" field.
-func (_u *APIKeyUpdateOne) SetUsage5h(v float64) *APIKeyUpdateOne {
+func (_u *APIKeyUpdateOne) SetUsage5h(v money.Amount) *APIKeyUpdateOne {
 	_u.mutation.ResetUsage5h()
 	_u.mutation.SetUsage5h(v)
 	return _u
 }
 
 // SetNillableUsage5h sets the "usage_5h" field if the given value is not nil.
-func (_u *APIKeyUpdateOne) SetNillableUsage5h(v *float64) *APIKeyUpdateOne {
+func (_u *APIKeyUpdateOne) SetNillableUsage5h(v *money.Amount) *APIKeyUpdateOne {
 	if v != nil {
 		_u.SetUsage5h(*v)
 	}
@@ -1118,20 +1330,20 @@ func (_u *APIKeyUpdateOne) SetNillableUsage5h(v *float64) *APIKeyUpdateOne {
 }
 
 // AddUsage5h adds value to the "usage_5h" field.
-func (_u *APIKeyUpdateOne) AddUsage5h(v float64) *APIKeyUpdateOne {
+func (_u *APIKeyUpdateOne) AddUsage5h(v money.Amount) *APIKeyUpdateOne {
 	_u.mutation.AddUsage5h(v)
 	return _u
 }
 
 // SetUsage1d sets the "usage_1d" field.
-func (_u *APIKeyUpdateOne) SetUsage1d(v float64) *APIKeyUpdateOne {
+func (_u *APIKeyUpdateOne) SetUsage1d(v money.Amount) *APIKeyUpdateOne {
 	_u.mutation.ResetUsage1d()
 	_u.mutation.SetUsage1d(v)
 	return _u
 }
 
 // SetNillableUsage1d sets the "usage_1d" field if the given value is not nil.
-func (_u *APIKeyUpdateOne) SetNillableUsage1d(v *float64) *APIKeyUpdateOne {
+func (_u *APIKeyUpdateOne) SetNillableUsage1d(v *money.Amount) *APIKeyUpdateOne {
 	if v != nil {
 		_u.SetUsage1d(*v)
 	}
@@ -1139,20 +1351,20 @@ func (_u *APIKeyUpdateOne) SetNillableUsage1d(v *float64) *APIKeyUpdateOne {
 }
 
 // AddUsage1d adds value to the "usage_1d" field.
-func (_u *APIKeyUpdateOne) AddUsage1d(v float64) *APIKeyUpdateOne {
+func (_u *APIKeyUpdateOne) AddUsage1d(v money.Amount) *APIKeyUpdateOne {
 	_u.mutation.AddUsage1d(v)
 	return _u
 }
 
 // SetUsage7d sets the "usage_7d" field.
-func (_u *APIKeyUpdateOne) SetUsage7d(v float64) *APIKeyUpdateOne {
+func (_u *APIKeyUpdateOne) SetUsage7d(v money.Amount) *APIKeyUpdateOne {
 	_u.mutation.ResetUsage7d()
 	_u.mutation.SetUsage7d(v)
 	return _u
 }
 
 // SetNillableUsage7d sets the "usage_7d" field if the given value is not nil.
-func (_u *APIKeyUpdateOne) SetNillableUsage7d(v *float64) *APIKeyUpdateOne {
+func (_u *APIKeyUpdateOne) SetNillableUsage7d(v *money.Amount) *APIKeyUpdateOne {
 	if v != nil {
 		_u.SetUsage7d(*v)
 	}
@@ -1160,7 +1372,7 @@ func (_u *APIKeyUpdateOne) SetNillableUsage7d(v *float64) *APIKeyUpdateOne {
 }
 
 // AddUsage7d adds value to the "usage_7d" field.
-func (_u *APIKeyUpdateOne) AddUsage7d(v float64) *APIKeyUpdateOne {
+func (_u *APIKeyUpdateOne) AddUsage7d(v money.Amount) *APIKeyUpdateOne {
 	_u.mutation.AddUsage7d(v)
 	return _u
 }
@@ -1225,6 +1437,96 @@ func (_u *APIKeyUpdateOne) ClearWindow7dStart() *APIKeyUpdateOne {
 	return _u
 }
 
+// SetRpmLimit sets the "rpm_limit" field.
+func (_u *APIKeyUpdateOne) SetRpmLimit(v int) *APIKeyUpdateOne {
+	_u.mutation.ResetRpmLimit()
+	_u.mutation.SetRpmLimit(v)
+	return _u
+}
+
+// SetNillableRpmLimit sets the "rpm_limit" field if the given value is not nil.
+func (_u *APIKeyUpdateOne) SetNillableRpmLimit(v *int) *APIKeyUpdateOne {
+	if v != nil {
+		_u.SetRpmLimit(*v)
+	}
+	return _u
+}
+
+// AddRpmLimit adds value to the "rpm_limit" field.
+func (_u *APIKeyUpdateOne) AddRpmLimit(v int) *APIKeyUpdateOne {
+	_u.mutation.AddRpmLimit(v)
+	return _u
+}
+
+// SetTpmLimit sets the "tpm_limit" field.
+func (_u *APIKeyUpdateOne) SetTpmLimit(v int) *APIKeyUpdateOne {
+	_u.mutation.ResetTpmLimit()
+	_u.mutation.SetTpmLimit(v)
+	return _u
+}
+
+// SetNillableTpmLimit sets the "tpm_limit" field if the given value is not nil.
+func (_u *APIKeyUpdateOne) SetNillableTpmLimit(v *int) *APIKeyUpdateOne {
+	if v != nil {
+		_u.SetTpmLimit(*v)
+	}
+	return _u
+}
+
+// AddTpmLimit adds value to the "tpm_limit" field.
+func (_u *APIKeyUpdateOne) AddTpmLimit(v int) *APIKeyUpdateOne {
+	_u.mutation.AddTpmLimit(v)
+	return _u
+}
+
+// SetMaxConcurrency sets the "max_concurrency" field.
+func (_u *APIKeyUpdateOne) SetMaxConcurrency(v int) *APIKeyUpdateOne {
+	_u.mutation.ResetMaxConcurrency()
+	_u.mutation.SetMaxConcurrency(v)
+	return _u
+}
+
+// SetNillableMaxConcurrency sets the "max_concurrency" field if the given value is not nil.
+func (_u *APIKeyUpdateOne) SetNillableMaxConcurrency(v *int) *APIKeyUpdateOne {
+	if v != nil {
+		_u.SetMaxConcurrency(*v)
+	}
+	return _u
+}
+
+// AddMaxConcurrency adds value to the "max_concurrency" field.
+func (_u *APIKeyUpdateOne) AddMaxConcurrency(v int) *APIKeyUpdateOne {
+	_u.mutation.AddMaxConcurrency(v)
+	return _u
+}
+
+// SetCreatedByUserID sets the "created_by_user_id" field.
+func (_u *APIKeyUpdateOne) SetCreatedByUserID(v int64) *APIKeyUpdateOne {
+	_u.mutation.ResetCreatedByUserID()
+	_u.mutation.SetCreatedByUserID(v)
+	return _u
+}
+
+// SetNillableCreatedByUserID sets the "created_by_user_id" field if the given value is not nil.
+func (_u *APIKeyUpdateOne) SetNillableCreatedByUserID(v *int64) *APIKeyUpdateOne {
+	if v != nil {
+		_u.SetCreatedByUserID(*v)
+	}
+	return _u
+}
+
+// AddCreatedByUserID adds value to the "created_by_user_id" field.
+func (_u *APIKeyUpdateOne) AddCreatedByUserID(v int64) *APIKeyUpdateOne {
+	_u.mutation.AddCreatedByUserID(v)
+	return _u
+}
+
+// ClearCreatedByUserID clears the value of the "created_by_user_id" field.
+func (_u *APIKeyUpdateOne) ClearCreatedByUserID() *APIKeyUpdateOne {
+	_u.mutation.ClearCreatedByUserID()
+	return _u
+}
+
 // SetUser sets the "user" edge to the User entity.
 func (_u *APIKeyUpdateOne) SetUser(v *User) *APIKeyUpdateOne {
 	return _u.SetUserID(v.ID)
@@ -1441,17 +1743,39 @@ func (_u *APIKeyUpdateOne) sqlSave(ctx context.Context) (_node *APIKey, err erro
 	if _u.mutation.IPBlacklistCleared() {
 		_spec.ClearField(apikey.FieldIPBlacklist, field.TypeJSON)
 	}
+	if value, ok := _u.mutation.ModelAllowlist(); ok {
+		_spec.SetField(apikey.FieldModelAllowlist, field.TypeJSON, value)
+	}
+	if value, ok := _u.mutation.AppendedModelAllowlist(); ok {
+		_spec.AddModifier(func(u *sql.UpdateBuilder) {
+			sqljson.Append(u, apikey.FieldModelAllowlist, value)
+		})
+	}
+	if _u.mutation.ModelAllowlistCleared() {
+		_spec.ClearField(apikey.FieldModelAllowlist, field.TypeJSON)
+	}
+	if value, ok := _u.mutation.ModelDenylist(); ok {
+		_spec.SetField(apikey.FieldModelDenylist, field.TypeJSON, value)
+	}
+	if value, ok := _u.mutation.AppendedModelDenylist(); ok {
+		_spec.AddModifier(func(u *sql.UpdateBuilder) {
+			sqljson.Append(u, apikey.FieldModelDenylist, value)
+		})
+	}
+	if _u.mutation.ModelDenylistCleared() {
+		_spec.ClearField(apikey.FieldModelDenylist, field.TypeJSON)
+	}
 	if value, ok := _u.mutation.Quota(); ok {
-		_spec.SetField(apikey.FieldQuota, field.TypeFloat64, value)
+		_spec.SetField(apikey.FieldQuota, field.TypeInt64, value)
 	}
 	if value, ok := _u.mutation.AddedQuota(); ok {
-		_spec.AddField(apikey.FieldQuota, field.TypeFloat64, value)
+		_spec.AddField(apikey.FieldQuota, field.TypeInt64, value)
 	}
 	if value, ok := _u.mutation.QuotaUsed(); ok {
-		_spec.SetField(apikey.FieldQuotaUsed, field.TypeFloat64, value)
+		_spec.SetField(apikey.FieldQuotaUsed, field.TypeInt64, value)
 	}
 	if value, ok := _u.mutation.AddedQuotaUsed(); ok {
-		_spec.AddField(apikey.FieldQuotaUsed, field.TypeFloat64, value)
+		_spec.AddField(apikey.FieldQuotaUsed, field.TypeInt64, value)
 	}
 	if value, ok := _u.mutation.ExpiresAt(); ok {
 		_spec.SetField(apikey.FieldExpiresAt, field.TypeTime, value)
@@ -1460,40 +1784,40 @@ func (_u *APIKeyUpdateOne) sqlSave(ctx context.Context) (_node *APIKey, err erro
 		_spec.ClearField(apikey.FieldExpiresAt, field.TypeTime)
 	}
 	if value, ok := _u.mutation.RateLimit5h(); ok {
-		_spec.SetField(apikey.FieldRateLimit5h, field.TypeFloat64, value)
+		_spec.SetField(apikey.FieldRateLimit5h, field.TypeInt64, value)
 	}
 	if value, ok := _u.mutation.AddedRateLimit5h(); ok {
-		_spec.AddField(apikey.FieldRateLimit5h, field.TypeFloat64, value)
+		_spec.AddField(apikey.FieldRateLimit5h, field.TypeInt64, value)
 	}
 	if value, ok := _u.mutation.RateLimit1d(); ok {
-		_spec.SetField(apikey.FieldRateLimit1d, field.TypeFloat64, value)
+		_spec.SetField(apikey.FieldRateLimit1d, field.TypeInt64, value)
 	}
 	if value, ok := _u.mutation.AddedRateLimit1d(); ok {
-		_spec.AddField(apikey.FieldRateLimit1d, field.TypeFloat64, value)
+		_spec.AddField(apikey.FieldRateLimit1d, field.TypeInt64, value)
 	}
 	if value, ok := _u.mutation.RateLimit7d(); ok {
-		_spec.SetField(apikey.FieldRateLimit7d, field.TypeFloat64, value)
+		_spec.SetField(apikey.FieldRateLimit7d, field.TypeInt64, value)
 	}
 	if value, ok := _u.mutation.AddedRateLimit7d(); ok {
-		_spec.AddField(apikey.FieldRateLimit7d, field.TypeFloat64, value)
+		_spec.AddField(apikey.FieldRateLimit7d, field.TypeInt64, value)
 	}
 	if value, ok := _u.mutation.Usage5h(); ok {
-		_spec.SetField(apikey.FieldUsage5h, field.TypeFloat64, value)
+		_spec.SetField(apikey.FieldUsage5h, field.TypeInt64, value)
 	}
 	if value, ok := _u.mutation.AddedUsage5h(); ok {
-		_spec.AddField(apikey.FieldUsage5h, field.TypeFloat64, value)
+		_spec.AddField(apikey.FieldUsage5h, field.TypeInt64, value)
 	}
 	if value, ok := _u.mutation.Usage1d(); ok {
-		_spec.SetField(apikey.FieldUsage1d, field.TypeFloat64, value)
+		_spec.SetField(apikey.FieldUsage1d, field.TypeInt64, value)
 	}
 	if value, ok := _u.mutation.AddedUsage1d(); ok {
-		_spec.AddField(apikey.FieldUsage1d, field.TypeFloat64, value)
+		_spec.AddField(apikey.FieldUsage1d, field.TypeInt64, value)
 	}
 	if value, ok := _u.mutation.Usage7d(); ok {
-		_spec.SetField(apikey.FieldUsage7d, field.TypeFloat64, value)
+		_spec.SetField(apikey.FieldUsage7d, field.TypeInt64, value)
 	}
 	if value, ok := _u.mutation.AddedUsage7d(); ok {
-		_spec.AddField(apikey.FieldUsage7d, field.TypeFloat64, value)
+		_spec.AddField(apikey.FieldUsage7d, field.TypeInt64, value)
 	}
 	if value, ok := _u.mutation.Window5hStart(); ok {
 		_spec.SetField(apikey.FieldWindow5hStart, field.TypeTime, value)
@@ -1513,6 +1837,33 @@ func (_u *APIKeyUpdateOne) sqlSave(ctx context.Context) (_node *APIKey, err erro
 	if _u.mutation.Window7dStartCleared() {
 		_spec.ClearField(apikey.FieldWindow7dStart, field.TypeTime)
 	}
+	if value, ok := _u.mutation.RpmLimit(); ok {
+		_spec.SetField(apikey.FieldRpmLimit, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.AddedRpmLimit(); ok {
+		_spec.AddField(apikey.FieldRpmLimit, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.TpmLimit(); ok {
+		_spec.SetField(apikey.FieldTpmLimit, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.AddedTpmLimit(); ok {
+		_spec.AddField(apikey.FieldTpmLimit, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.MaxConcurrency(); ok {
+		_spec.SetField(apikey.FieldMaxConcurrency, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.AddedMaxConcurrency(); ok {
+		_spec.AddField(apikey.FieldMaxConcurrency, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.CreatedByUserID(); ok {
+		_spec.SetField(apikey.FieldCreatedByUserID, field.TypeInt64, value)
+	}
+	if value, ok := _u.mutation.AddedCreatedByUserID(); ok {
+		_spec.AddField(apikey.FieldCreatedByUserID, field.TypeInt64, value)
+	}
+	if _u.mutation.CreatedByUserIDCleared() {
+		_spec.ClearField(apikey.FieldCreatedByUserID, field.TypeInt64)
+	}
 	if _u.mutation.UserCleared() {
 		edge := &sqlgraph.EdgeSpec{
 			Rel:     sqlgraph.M2O,
@@ -11,6 +11,7 @@ import (
 	"entgo.io/ent"
 	"entgo.io/ent/dialect/sql"
 	"github.com/Wei-Shaw/sub2api/ent/group"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
 )
 
 // Group is the model entity for the Group schema.
@@ -39,33 +40,37 @@ type Group struct {
 	// SubscriptionType holds the value of the "subscription_type" field.
 	SubscriptionType string `json:"subscription_type,omitempty"`
 	// DailyLimitUsd holds the value of the "daily_limit_usd" field.
-	DailyLimitUsd *float64 `json:"daily_limit_usd,omitempty"`
+	DailyLimitUsd *money.Amount `json:"daily_limit_usd,omitempty"`
 	// WeeklyLimitUsd holds the value of the "weekly_limit_usd" field.
-	WeeklyLimitUsd *float64 `json:"weekly_limit_usd,omitempty"`
+	WeeklyLimitUsd *money.Amount `json:"weekly_limit_usd,omitempty"`
 	// MonthlyLimitUsd holds the value of the "monthly_limit_usd" field.
-	MonthlyLimitUsd *float64 `json:"monthly_limit_usd,omitempty"`
+	MonthlyLimitUsd *money.Amount `json:"monthly_limit_usd,omitempty"`
 	// DefaultValidityDays holds the value of the "default_validity_days" field.
 	DefaultValidityDays int `json:"default_validity_days,omitempty"`
 	// ImagePrice1k holds the value of the "image_price_1k" field.
-	ImagePrice1k *float64 `json:"image_price_1k,omitempty"`
+	ImagePrice1k *money.Amount `json:"image_price_1k,omitempty"`
 	// ImagePrice2k holds the value of the "image_price_2k" field.
-	ImagePrice2k *float64 `json:"image_price_2k,omitempty"`
+	ImagePrice2k *money.Amount `json:"image_price_2k,omitempty"`
 	// ImagePrice4k holds the value of the "image_price_4k" field.
-	ImagePrice4k *float64 `json:"image_price_4k,omitempty"`
+	ImagePrice4k *money.Amount `json:"image_price_4k,omitempty"`
 	// SoraImagePrice360 holds the value of the "sora_image_price_360" field.
-	SoraImagePrice360 *float64 `json:"sora_image_price_360,omitempty"`
+	SoraImagePrice360 *money.Amount `json:"sora_image_price_360,omitempty"`
 	// SoraImagePrice540 holds the value of the "sora_image_price_540" field.
-	SoraImagePrice540 *float64 `json:"sora_image_price_540,omitempty"`
+	SoraImagePrice540 *money.Amount `json:"sora_image_price_540,omitempty"`
 	// SoraVideoPricePerRequest holds the value of the "sora_video_price_per_request" field.
-	SoraVideoPricePerRequest *float64 `json:"sora_video_price_per_request,omitempty"`
+	SoraVideoPricePerRequest *money.Amount `json:"sora_video_price_per_request,omitempty"`
 	// SoraVideoPricePerRequestHd holds the value of the "sora_video_price_per_request_hd" field.
-	SoraVideoPricePerRequestHd *float64 `json:"sora_video_price_per_request_hd,omitempty"`
+	SoraVideoPricePerRequestHd *money.Amount `json:"sora_video_price_per_request_hd,omitempty"`
 	// SoraStorageQuotaBytes holds the value of the "sora_storage_quota_bytes" field.
 	SoraStorageQuotaBytes int64 `json:"sora_storage_quota_bytes,omitempty"`
 	// 视频生成单次请求价格（标准质量）
-	VideoPricePerRequest *float64 `json:"video_price_per_request,omitempty"`
+	VideoPricePerRequest *money.Amount `json:"video_price_per_request,omitempty"`
 	// 视频生成单次请求价格（高清质量）
-	VideoPricePerRequestHd *float64 `json:"video_price_per_request_hd,omitempty"`
+	VideoPricePerRequestHd *money.Amount `json:"video_price_per_request_hd,omitempty"`
+	// 音频转写/翻译每分钟价格
+	AudioPricePerMinute *money.Amount `json:"audio_price_per_minute,omitempty"`
+	// 语音合成每百万字符价格
+	AudioSpeechPricePer1mChars *money.Amount `json:"audio_speech_price_per_1m_chars,omitempty"`
 	// 是否仅允许 Claude Code 客户端
 	ClaudeCodeOnly bool `json:"claude_code_only,omitempty"`
 	// 非 Claude Code 请求降级使用的分组 ID
@@ -188,11 +193,13 @@ func (*Group) scanValues(columns []string) ([]any, error) {
 	values := make([]any, len(columns))
 	for i := range columns {
 		switch columns[i] {
+		case group.FieldDailyLimitUsd, group.FieldWeeklyLimitUsd, group.FieldMonthlyLimitUsd, group.FieldImagePrice1k, group.FieldImagePrice2k, group.FieldImagePrice4k, group.FieldSoraImagePrice360, group.FieldSoraImagePrice540, group.FieldSoraVideoPricePerRequest, group.FieldSoraVideoPricePerRequestHd, group.FieldVideoPricePerRequest, group.FieldVideoPricePerRequestHd, group.FieldAudioPricePerMinute, group.FieldAudioSpeechPricePer1mChars:
+			values[i] = &sql.NullScanner{S: new(money.Amount)}
 		case group.FieldModelRouting, group.FieldSupportedModelScopes:
 			values[i] = new([]byte)
 		case group.FieldIsExclusive, group.FieldClaudeCodeOnly, group.FieldModelRoutingEnabled, group.FieldMcpXMLInject:
 			values[i] = new(sql.NullBool)
-		case group.FieldRateMultiplier, group.FieldDailyLimitUsd, group.FieldWeeklyLimitUsd, group.FieldMonthlyLimitUsd, group.FieldImagePrice1k, group.FieldImagePrice2k, group.FieldImagePrice4k, group.FieldSoraImagePrice360, group.FieldSoraImagePrice540, group.FieldSoraVideoPricePerRequest, group.FieldSoraVideoPricePerRequestHd, group.FieldVideoPricePerRequest, group.FieldVideoPricePerRequestHd:
+		case group.FieldRateMultiplier:
 			values[i] = new(sql.NullFloat64)
 		case group.FieldID, group.FieldDefaultValidityDays, group.FieldSoraStorageQuotaBytes, group.FieldFallbackGroupID, group.FieldFallbackGroupIDOnInvalidRequest, group.FieldSortOrder:
 			values[i] = new(sql.NullInt64)
@@ -284,25 +291,25 @@ func (_m *Group) assignValues(columns []string, values []any) error {
 				_m.SubscriptionType = value.String
 			}
 		case group.FieldDailyLimitUsd:
-			if value, ok := values[i].(*sql.NullFloat64); !ok {
+			if value, ok := values[i].(*sql.NullScanner); !ok {
 				return fmt.Errorf("unexpected type %T for field daily_limit_usd", values[i])
 			} else if value.Valid {
-				_m.DailyLimitUsd = new(float64)
-				*_m.DailyLimitUsd = value.Float64
+				_m.DailyLimitUsd = new(money.Amount)
+				*_m.DailyLimitUsd = *value.S.(*money.Amount)
 			}
 		case group.FieldWeeklyLimitUsd:
-			if value, ok := values[i].(*sql.NullFloat64); !ok {
+			if value, ok := values[i].(*sql.NullScanner); !ok {
 				return fmt.Errorf("unexpected type %T for field weekly_limit_usd", values[i])
 			} else if value.Valid {
-				_m.WeeklyLimitUsd = new(float64)
-				*_m.WeeklyLimitUsd = value.Float64
+				_m.WeeklyLimitUsd = new(money.Amount)
+				*_m.WeeklyLimitUsd = *value.S.(*money.Amount)
 			}
 		case group.FieldMonthlyLimitUsd:
-			if value, ok := values[i].(*sql.NullFloat64); !ok {
+			if value, ok := values[i].(*sql.NullScanner); !ok {
 				return fmt.Errorf("unexpected type %T for field monthly_limit_usd", values[i])
 			} else if value.Valid {
-				_m.MonthlyLimitUsd = new(float64)
-				*_m.MonthlyLimitUsd = value.Float64
+				_m.MonthlyLimitUsd = new(money.Amount)
+				*_m.MonthlyLimitUsd = *value.S.(*money.Amount)
 			}
 		case group.FieldDefaultValidityDays:
 			if value, ok := values[i].(*sql.NullInt64); !ok {
@@ -311,53 +318,53 @@ func (_m *Group) assignValues(columns []string, values []any) error {
 				_m.DefaultValidityDays = int(value.Int64)
 			}
 		case group.FieldImagePrice1k:
-			if value, ok := values[i].(*sql.NullFloat64); !ok {
+			if value, ok := values[i].(*sql.NullScanner); !ok {
 				return fmt.Errorf("unexpected type %T for field image_price_1k", values[i])
 			} else if value.Valid {
-				_m.ImagePrice1k = new(float64)
-				*_m.ImagePrice1k = value.Float64
+				_m.ImagePrice1k = new(money.Amount)
+				*_m.ImagePrice1k = *value.S.(*money.Amount)
 			}
 		case group.FieldImagePrice2k:
-			if value, ok := values[i].(*sql.NullFloat64); !ok {
+			if value, ok := values[i].(*sql.NullScanner); !ok {
 				return fmt.Errorf("unexpected type %T for field image_price_2k", values[i])
 			} else if value.Valid {
-				_m.ImagePrice2k = new(float64)
-				*_m.ImagePrice2k = value.Float64
+				_m.ImagePrice2k = new(money.Amount)
+				*_m.ImagePrice2k = *value.S.(*money.Amount)
 			}
 		case group.FieldImagePrice4k:
-			if value, ok := values[i].(*sql.NullFloat64); !ok {
+			if value, ok := values[i].(*sql.NullScanner); !ok {
 				return fmt.Errorf("unexpected type %T for field image_price_4k", values[i])
 			} else if value.Valid {
-				_m.ImagePrice4k = new(float64)
-				*_m.ImagePrice4k = value.Float64
+				_m.ImagePrice4k = new(money.Amount)
+				*_m.ImagePrice4k = *value.S.(*money.Amount)
 			}
 		case group.FieldSoraImagePrice360:
-			if value, ok := values[i].(*sql.NullFloat64); !ok {
+			if value, ok := values[i].(*sql.NullScanner); !ok {
 				return fmt.Errorf("unexpected type %T for field sora_image_price_360", values[i])
 			} else if value.Valid {
-				_m.SoraImagePrice360 = new(float64)
-				*_m.SoraImagePrice360 = value.Float64
+				_m.SoraImagePrice360 = new(money.Amount)
+				*_m.SoraImagePrice360 = *value.S.(*money.Amount)
 			}
 		case group.FieldSoraImagePrice540:
-			if value, ok := values[i].(*sql.NullFloat64); !ok {
+			if value, ok := values[i].(*sql.NullScanner); !ok {
 				return fmt.Errorf("unexpected type %T for field sora_image_price_540", values[i])
 			} else if value.Valid {
-				_m.SoraImagePrice540 = new(float64)
-				*_m.SoraImagePrice540 = value.Float64
+				_m.SoraImagePrice540 = new(money.Amount)
+				*_m.SoraImagePrice540 = *value.S.(*money.Amount)
 			}
 		case group.FieldSoraVideoPricePerRequest:
-			if value, ok := values[i].(*sql.NullFloat64); !ok {
+			if value, ok := values[i].(*sql.NullScanner); !ok {
 				return fmt.Errorf("unexpected type %T for field sora_video_price_per_request", values[i])
 			} else if value.Valid {
-				_m.SoraVideoPricePerRequest = new(float64)
-				*_m.SoraVideoPricePerRequest = value.Float64
+				_m.SoraVideoPricePerRequest = new(money.Amount)
+				*_m.SoraVideoPricePerRequest = *value.S.(*money.Amount)
 			}
 		case group.FieldSoraVideoPricePerRequestHd:
-			if value, ok := values[i].(*sql.NullFloat64); !ok {
+			if value, ok := values[i].(*sql.NullScanner); !ok {
 				return fmt.Errorf("unexpected type %T for field sora_video_price_per_request_hd", values[i])
 			} else if value.Valid {
-				_m.SoraVideoPricePerRequestHd = new(float64)
-				*_m.SoraVideoPricePerRequestHd = value.Float64
+				_m.SoraVideoPricePerRequestHd = new(money.Amount)
+				*_m.SoraVideoPricePerRequestHd = *value.S.(*money.Amount)
 			}
 		case group.FieldSoraStorageQuotaBytes:
 			if value, ok := values[i].(*sql.NullInt64); !ok {
@@ -366,18 +373,32 @@ func (_m *Group) assignValues(columns []string, values []any) error {
 				_m.SoraStorageQuotaBytes = value.Int64
 			}
 		case group.FieldVideoPricePerRequest:
-			if value, ok := values[i].(*sql.NullFloat64); !ok {
+			if value, ok := values[i].(*sql.NullScanner); !ok {
 				return fmt.Errorf("unexpected type %T for field video_price_per_request", values[i])
 			} else if value.Valid {
-				_m.VideoPricePerRequest = new(float64)
-				*_m.VideoPricePerRequest = value.Float64
+				_m.VideoPricePerRequest = new(money.Amount)
+				*_m.VideoPricePerRequest = *value.S.(*money.Amount)
 			}
 		case group.FieldVideoPricePerRequestHd:
-			if value, ok := values[i].(*sql.NullFloat64); !ok {
+			if value, ok := values[i].(*sql.NullScanner); !ok {
 				return fmt.Errorf("unexpected type %T for field video_price_per_request_hd", values[i])
 			} else if value.Valid {
-				_m.VideoPricePerRequestHd = new(float64)
-				*_m.VideoPricePerRequestHd = value.Float64
+				_m.VideoPricePerRequestHd = new(money.Amount)
+				*_m.VideoPricePerRequestHd = *value.S.(*money.Amount)
+			}
+		case group.FieldAudioPricePerMinute:
+			if value, ok := values[i].(*sql.NullScanner); !ok {
+				return fmt.Errorf("unexpected type %T for field audio_price_per_minute", values[i])
+			} else if value.Valid {
+				_m.AudioPricePerMinute = new(money.Amount)
+				*_m.AudioPricePerMinute = *value.S.(*money.Amount)
+			}
+		case group.FieldAudioSpeechPricePer1mChars:
+			if value, ok := values[i].(*sql.NullScanner); !ok {
+				return fmt.Errorf("unexpected type %T for field audio_speech_price_per_1m_chars", values[i])
+			} else if value.Valid {
+				_m.AudioSpeechPricePer1mChars = new(money.Amount)
+				*_m.AudioSpeechPricePer1mChars = *value.S.(*money.Amount)
 			}
 		case group.FieldClaudeCodeOnly:
 			if value, ok := values[i].(*sql.NullBool); !ok {
@@ -609,6 +630,16 @@ func (_m *Group) String() string {
 		builder.WriteString(fmt.Sprintf("%v", *v))
 	}
 	builder.WriteString(", ")
+	if v := _m.AudioPricePerMinute; v != nil {
+		builder.WriteString("audio_price_per_minute=")
+		builder.WriteString(fmt.Sprintf("%v", *v))
+	}
+	builder.WriteString(", ")
+	if v := _m.AudioSpeechPricePer1mChars; v != nil {
+		builder.WriteString("audio_speech_price_per_1m_chars=")
+		builder.WriteString(fmt.Sprintf("%v", *v))
+	}
+	builder.WriteString(", ")
 	builder.WriteString("claude_code_only=")
 	builder.WriteString(fmt.Sprintf("%v", _m.ClaudeCodeOnly))
 	builder.WriteString(", ")
@@ -63,6 +63,10 @@ const (
 	FieldVideoPricePerRequest = "video_price_per_request"
 	// FieldVideoPricePerRequestHd holds the string denoting the video_price_per_request_hd field in the database.
 	FieldVideoPricePerRequestHd = "video_price_per_request_hd"
+	// FieldAudioPricePerMinute holds the string denoting the audio_price_per_minute field in the database.
+	FieldAudioPricePerMinute = "audio_price_per_minute"
+	// FieldAudioSpeechPricePer1mChars holds the string denoting the audio_speech_price_per_1m_chars field in the database.
+	FieldAudioSpeechPricePer1mChars = "audio_speech_price_per_1m_chars"
 	// FieldClaudeCodeOnly holds the string denoting the claude_code_only field in the database.
 	FieldClaudeCodeOnly = "claude_code_only"
 	// FieldFallbackGroupID holds the string denoting the fallback_group_id field in the database.
@@ -178,6 +182,8 @@ var Columns = []string{
 	FieldSoraStorageQuotaBytes,
 	FieldVideoPricePerRequest,
 	FieldVideoPricePerRequestHd,
+	FieldAudioPricePerMinute,
+	FieldAudioSpeechPricePer1mChars,
 	FieldClaudeCodeOnly,
 	FieldFallbackGroupID,
 	FieldFallbackGroupIDOnInvalidRequest,
@@ -383,6 +389,16 @@ func ByVideoPricePerRequestHd(opts ...sql.OrderTermOption) OrderOption {
 	return sql.OrderByField(FieldVideoPricePerRequestHd, opts...).ToFunc()
 }
 
+// ByAudioPricePerMinute orders the results by the audio_price_per_minute field.
+func ByAudioPricePerMinute(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldAudioPricePerMinute, opts...).ToFunc()
+}
+
+// ByAudioSpeechPricePer1mChars orders the results by the audio_speech_price_per_1m_chars field.
+func ByAudioSpeechPricePer1mChars(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldAudioSpeechPricePer1mChars, opts...).ToFunc()
+}
+
 // ByClaudeCodeOnly orders the results by the claude_code_only field.
 func ByClaudeCodeOnly(opts ...sql.OrderTermOption) OrderOption {
 	return sql.OrderByField(FieldClaudeCodeOnly, opts...).ToFunc()
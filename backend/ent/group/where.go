@@ -8,6 +8,7 @@ import (
 	"entgo.io/ent/dialect/sql"
 	"entgo.io/ent/dialect/sql/sqlgraph"
 	"github.com/Wei-Shaw/sub2api/ent/predicate"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
 )
 
 // ID filters vertices based on their ID field.
@@ -106,17 +107,17 @@ func SubscriptionType(v string) predicate.Group {
 }
 
 // DailyLimitUsd applies equality check predicate on the "daily_limit_usd" field. It's identical to DailyLimitUsdEQ.
-func DailyLimitUsd(v float64) predicate.Group {
+func DailyLimitUsd(v money.Amount) predicate.Group {
 	return predicate.Group(sql.FieldEQ(FieldDailyLimitUsd, v))
 }
 
 // WeeklyLimitUsd applies equality check predicate on the "weekly_limit_usd" field. It's identical to WeeklyLimitUsdEQ.
-func WeeklyLimitUsd(v float64) predicate.Group {
+func WeeklyLimitUsd(v money.Amount) predicate.Group {
 	return predicate.Group(sql.FieldEQ(FieldWeeklyLimitUsd, v))
 }
 
 // MonthlyLimitUsd applies equality check predicate on the "monthly_limit_usd" field. It's identical to MonthlyLimitUsdEQ.
-func MonthlyLimitUsd(v float64) predicate.Group {
+func MonthlyLimitUsd(v money.Amount) predicate.Group {
 	return predicate.Group(sql.FieldEQ(FieldMonthlyLimitUsd, v))
 }
 
@@ -126,37 +127,37 @@ func DefaultValidityDays(v int) predicate.Group {
 }
 
 // ImagePrice1k applies equality check predicate on the "image_price_1k" field. It's identical to ImagePrice1kEQ.
-func ImagePrice1k(v float64) predicate.Group {
+func ImagePrice1k(v money.Amount) predicate.Group {
 	return predicate.Group(sql.FieldEQ(FieldImagePrice1k, v))
 }
 
 // ImagePrice2k applies equality check predicate on the "image_price_2k" field. It's identical to ImagePrice2kEQ.
-func ImagePrice2k(v float64) predicate.Group {
+func ImagePrice2k(v money.Amount) predicate.Group {
 	return predicate.Group(sql.FieldEQ(FieldImagePrice2k, v))
 }
 
 // ImagePrice4k applies equality check predicate on the "image_price_4k" field. It's identical to ImagePrice4kEQ.
-func ImagePrice4k(v float64) predicate.Group {
+func ImagePrice4k(v money.Amount) predicate.Group {
 	return predicate.Group(sql.FieldEQ(FieldImagePrice4k, v))
 }
 
 // SoraImagePrice360 applies equality check predicate on the "sora_image_price_360" field. It's identical to SoraImagePrice360EQ.
-func SoraImagePrice360(v float64) predicate.Group {
+func SoraImagePrice360(v money.Amount) predicate.Group {
 	return predicate.Group(sql.FieldEQ(FieldSoraImagePrice360, v))
 }
 
 // SoraImagePrice540 applies equality check predicate on the "sora_image_price_540" field. It's identical to SoraImagePrice540EQ.
-func SoraImagePrice540(v float64) predicate.Group {
+func SoraImagePrice540(v money.Amount) predicate.Group {
 	return predicate.Group(sql.FieldEQ(FieldSoraImagePrice540, v))
 }
 
 // SoraVideoPricePerRequest applies equality check predicate on the "sora_video_price_per_request" field. It's identical to SoraVideoPricePerRequestEQ.
-func SoraVideoPricePerRequest(v float64) predicate.Group {
+func SoraVideoPricePerRequest(v money.Amount) predicate.Group {
 	return predicate.Group(sql.FieldEQ(FieldSoraVideoPricePerRequest, v))
 }
 
 // SoraVideoPricePerRequestHd applies equality check predicate on the "sora_video_price_per_request_hd" field. It's identical to SoraVideoPricePerRequestHdEQ.
-func SoraVideoPricePerRequestHd(v float64) predicate.Group {
+func SoraVideoPricePerRequestHd(v money.Amount) predicate.Group {
 	return predicate.Group(sql.FieldEQ(FieldSoraVideoPricePerRequestHd, v))
 }
 
@@ -166,15 +167,25 @@ func SoraStorageQuotaBytes(v int64) predicate.Group {
 }
 
 // VideoPricePerRequest applies equality check predicate on the "video_price_per_request" field. It's identical to VideoPricePerRequestEQ.
-func VideoPricePerRequest(v float64) predicate.Group {
+func VideoPricePerRequest(v money.Amount) predicate.Group {
 	return predicate.Group(sql.FieldEQ(FieldVideoPricePerRequest, v))
 }
 
 // VideoPricePerRequestHd applies equality check predicate on the "video_price_per_request_hd" field. It's identical to VideoPricePerRequestHdEQ.
-func VideoPricePerRequestHd(v float64) predicate.Group {
+func VideoPricePerRequestHd(v money.Amount) predicate.Group {
 	return predicate.Group(sql.FieldEQ(FieldVideoPricePerRequestHd, v))
 }
 
+// AudioPricePerMinute applies equality check predicate on the "audio_price_per_minute" field. It's identical to AudioPricePerMinuteEQ.
+func AudioPricePerMinute(v money.Amount) predicate.Group {
+	return predicate.Group(sql.FieldEQ(FieldAudioPricePerMinute, v))
+}
+
+// AudioSpeechPricePer1mChars applies equality check predicate on the "audio_speech_price_per_1m_chars" field. It's identical to AudioSpeechPricePer1mCharsEQ.
+func AudioSpeechPricePer1mChars(v money.Amount) predicate.Group {
+	return predicate.Group(sql.FieldEQ(FieldAudioSpeechPricePer1mChars, v))
+}
+
 // ClaudeCodeOnly applies equality check predicate on the "claude_code_only" field. It's identical to ClaudeCodeOnlyEQ.
 func ClaudeCodeOnly(v bool) predicate.Group {
 	return predicate.Group(sql.FieldEQ(FieldClaudeCodeOnly, v))
@@ -721,42 +732,42 @@ func SubscriptionTypeContainsFold(v string) predicate.Group {
 }
 
 // DailyLimitUsdEQ applies the EQ predicate on the "daily_limit_usd" field.
-func DailyLimitUsdEQ(v float64) predicate.Group {
+func DailyLimitUsdEQ(v money.Amount) predicate.Group {
 	return predicate.Group(sql.FieldEQ(FieldDailyLimitUsd, v))
 }
 
 // DailyLimitUsdNEQ applies the NEQ predicate on the "daily_limit_usd" field.
-func DailyLimitUsdNEQ(v float64) predicate.Group {
+func DailyLimitUsdNEQ(v money.Amount) predicate.Group {
 	return predicate.Group(sql.FieldNEQ(FieldDailyLimitUsd, v))
 }
 
 // DailyLimitUsdIn applies the In predicate on the "daily_limit_usd" field.
-func DailyLimitUsdIn(vs ...float64) predicate.Group {
+func DailyLimitUsdIn(vs ...money.Amount) predicate.Group {
 	return predicate.Group(sql.FieldIn(FieldDailyLimitUsd, vs...))
 }
 
 // DailyLimitUsdNotIn applies the NotIn predicate on the "daily_limit_usd" field.
-func DailyLimitUsdNotIn(vs ...float64) predicate.Group {
+func DailyLimitUsdNotIn(vs ...money.Amount) predicate.Group {
 	return predicate.Group(sql.FieldNotIn(FieldDailyLimitUsd, vs...))
 }
 
 // DailyLimitUsdGT applies the GT predicate on the "daily_limit_usd" field.
-func DailyLimitUsdGT(v float64) predicate.Group {
+func DailyLimitUsdGT(v money.Amount) predicate.Group {
 	return predicate.Group(sql.FieldGT(FieldDailyLimitUsd, v))
 }
 
 // DailyLimitUsdGTE applies the GTE predicate on the "daily_limit_usd" field.
-func DailyLimitUsdGTE(v float64) predicate.Group {
+func DailyLimitUsdGTE(v money.Amount) predicate.Group {
 	return predicate.Group(sql.FieldGTE(FieldDailyLimitUsd, v))
 }
 
 // DailyLimitUsdLT applies the LT predicate on the "daily_limit_usd" field.
-func DailyLimitUsdLT(v float64) predicate.Group {
+func DailyLimitUsdLT(v money.Amount) predicate.Group {
 	return predicate.Group(sql.FieldLT(FieldDailyLimitUsd, v))
 }
 
 // DailyLimitUsdLTE applies the LTE predicate on the "daily_limit_usd" field.
-func DailyLimitUsdLTE(v float64) predicate.Group {
+func DailyLimitUsdLTE(v money.Amount) predicate.Group {
 	return predicate.Group(sql.FieldLTE(FieldDailyLimitUsd, v))
 }
 
@@ -771,42 +782,42 @@ func DailyLimitUsdNotNil() predicate.Group {
 }
 
 // WeeklyLimitUsdEQ applies the EQ predicate on the "weekly_limit_usd" field.
-func WeeklyLimitUsdEQ(v float64) predicate.Group {
+func WeeklyLimitUsdEQ(v money.Amount) predicate.Group {
 	return predicate.Group(sql.FieldEQ(FieldWeeklyLimitUsd, v))
 }
 
 // WeeklyLimitUsdNEQ applies the NEQ predicate on the "weekly_limit_usd" field.
-func WeeklyLimitUsdNEQ(v float64) predicate.Group {
+func WeeklyLimitUsdNEQ(v money.Amount) predicate.Group {
 	return predicate.Group(sql.FieldNEQ(FieldWeeklyLimitUsd, v))
 }
 
 // WeeklyLimitUsdIn applies the In predicate on the "weekly_limit_usd" field.
-func WeeklyLimitUsdIn(vs ...float64) predicate.Group {
+func WeeklyLimitUsdIn(vs ...money.Amount) predicate.Group {
 	return predicate.Group(sql.FieldIn(FieldWeeklyLimitUsd, vs...))
 }
 
 // WeeklyLimitUsdNotIn applies the NotIn predicate on the "weekly_limit_usd" field.
-func WeeklyLimitUsdNotIn(vs ...float64) predicate.Group {
+func WeeklyLimitUsdNotIn(vs ...money.Amount) predicate.Group {
 	return predicate.Group(sql.FieldNotIn(FieldWeeklyLimitUsd, vs...))
 }
 
 // WeeklyLimitUsdGT applies the GT predicate on the "weekly_limit_usd" field.
-func WeeklyLimitUsdGT(v float64) predicate.Group {
+func WeeklyLimitUsdGT(v money.Amount) predicate.Group {
 	return predicate.Group(sql.FieldGT(FieldWeeklyLimitUsd, v))
 }
 
 // WeeklyLimitUsdGTE applies the GTE predicate on the "weekly_limit_usd" field.
-func WeeklyLimitUsdGTE(v float64) predicate.Group {
+func WeeklyLimitUsdGTE(v money.Amount) predicate.Group {
 	return predicate.Group(sql.FieldGTE(FieldWeeklyLimitUsd, v))
 }
 
 // WeeklyLimitUsdLT applies the LT predicate on the "weekly_limit_usd" field.
-func WeeklyLimitUsdLT(v float64) predicate.Group {
+func WeeklyLimitUsdLT(v money.Amount) predicate.Group {
 	return predicate.Group(sql.FieldLT(FieldWeeklyLimitUsd, v))
 }
 
 // WeeklyLimitUsdLTE applies the LTE predicate on the "weekly_limit_usd" field.
-func WeeklyLimitUsdLTE(v float64) predicate.Group {
+func WeeklyLimitUsdLTE(v money.Amount) predicate.Group {
 	return predicate.Group(sql.FieldLTE(FieldWeeklyLimitUsd, v))
 }
 
@@ -821,42 +832,42 @@ func WeeklyLimitUsdNotNil() predicate.Group {
 }
 
 // MonthlyLimitUsdEQ applies the EQ predicate on the "monthly_limit_usd" field.
-func MonthlyLimitUsdEQ(v float64) predicate.Group {
+func MonthlyLimitUsdEQ(v money.Amount) predicate.Group {
 	return predicate.Group(sql.FieldEQ(FieldMonthlyLimitUsd, v))
 }
 
 // MonthlyLimitUsdNEQ applies the NEQ predicate on the "monthly_limit_usd" field.
-func MonthlyLimitUsdNEQ(v float64) predicate.Group {
+func MonthlyLimitUsdNEQ(v money.Amount) predicate.Group {
 	return predicate.Group(sql.FieldNEQ(FieldMonthlyLimitUsd, v))
 }
 
 // MonthlyLimitUsdIn applies the In predicate on the "monthly_limit_usd" field.
-func MonthlyLimitUsdIn(vs ...float64) predicate.Group {
+func MonthlyLimitUsdIn(vs ...money.Amount) predicate.Group {
 	return predicate.Group(sql.FieldIn(FieldMonthlyLimitUsd, vs...))
 }
 
 // MonthlyLimitUsdNotIn applies the NotIn predicate on the "monthly_limit_usd" field.
-func MonthlyLimitUsdNotIn(vs ...float64) predicate.Group {
+func MonthlyLimitUsdNotIn(vs ...money.Amount) predicate.Group {
 	return predicate.Group(sql.FieldNotIn(FieldMonthlyLimitUsd, vs...))
 }
 
 // MonthlyLimitUsdGT applies the GT predicate on the "monthly_limit_usd" field.
-func MonthlyLimitUsdGT(v float64) predicate.Group {
+func MonthlyLimitUsdGT(v money.Amount) predicate.Group {
 	return predicate.Group(sql.FieldGT(FieldMonthlyLimitUsd, v))
 }
 
 // MonthlyLimitUsdGTE applies the GTE predicate on the "monthly_limit_usd" field.
-func MonthlyLimitUsdGTE(v float64) predicate.Group {
+func MonthlyLimitUsdGTE(v money.Amount) predicate.Group {
 	return predicate.Group(sql.FieldGTE(FieldMonthlyLimitUsd, v))
 }
 
 // MonthlyLimitUsdLT applies the LT predicate on the "monthly_limit_usd" field.
-func MonthlyLimitUsdLT(v float64) predicate.Group {
+func MonthlyLimitUsdLT(v money.Amount) predicate.Group {
 	return predicate.Group(sql.FieldLT(FieldMonthlyLimitUsd, v))
 }
 
 // MonthlyLimitUsdLTE applies the LTE predicate on the "monthly_limit_usd" field.
-func MonthlyLimitUsdLTE(v float64) predicate.Group {
+func MonthlyLimitUsdLTE(v money.Amount) predicate.Group {
 	return predicate.Group(sql.FieldLTE(FieldMonthlyLimitUsd, v))
 }
 
@@ -911,42 +922,42 @@ func DefaultValidityDaysLTE(v int) predicate.Group {
 }
 
 // ImagePrice1kEQ applies the EQ predicate on the "image_price_1k" field.
-func ImagePrice1kEQ(v float64) predicate.Group {
+func ImagePrice1kEQ(v money.Amount) predicate.Group {
 	return predicate.Group(sql.FieldEQ(FieldImagePrice1k, v))
 }
 
 // ImagePrice1kNEQ applies the NEQ predicate on the "image_price_1k" field.
-func ImagePrice1kNEQ(v float64) predicate.Group {
+func ImagePrice1kNEQ(v money.Amount) predicate.Group {
 	return predicate.Group(sql.FieldNEQ(FieldImagePrice1k, v))
 }
 
 // ImagePrice1kIn applies the In predicate on the "image_price_1k" field.
-func ImagePrice1kIn(vs ...float64) predicate.Group {
+func ImagePrice1kIn(vs ...money.Amount) predicate.Group {
 	return predicate.Group(sql.FieldIn(FieldImagePrice1k, vs...))
 }
 
 // ImagePrice1kNotIn applies the NotIn predicate on the "image_price_1k" field.
-func ImagePrice1kNotIn(vs ...float64) predicate.Group {
+func ImagePrice1kNotIn(vs ...money.Amount) predicate.Group {
 	return predicate.Group(sql.FieldNotIn(FieldImagePrice1k, vs...))
 }
 
 // ImagePrice1kGT applies the GT predicate on the "image_price_1k" field.
-func ImagePrice1kGT(v float64) predicate.Group {
+func ImagePrice1kGT(v money.Amount) predicate.Group {
 	return predicate.Group(sql.FieldGT(FieldImagePrice1k, v))
 }
 
 // ImagePrice1kGTE applies the GTE predicate on the "image_price_1k" field.
-func ImagePrice1kGTE(v float64) predicate.Group {
+func ImagePrice1kGTE(v money.Amount) predicate.Group {
 	return predicate.Group(sql.FieldGTE(FieldImagePrice1k, v))
 }
 
 // ImagePrice1kLT applies the LT predicate on the "image_price_1k" field.
-func ImagePrice1kLT(v float64) predicate.Group {
+func ImagePrice1kLT(v money.Amount) predicate.Group {
 	return predicate.Group(sql.FieldLT(FieldImagePrice1k, v))
 }
 
 // ImagePrice1kLTE applies the LTE predicate on the "image_price_1k" field.
-func ImagePrice1kLTE(v float64) predicate.Group {
+func ImagePrice1kLTE(v money.Amount) predicate.Group {
 	return predicate.Group(sql.FieldLTE(FieldImagePrice1k, v))
 }
 
@@ -961,42 +972,42 @@ func ImagePrice1kNotNil() predicate.Group {
 }
 
 // ImagePrice2kEQ applies the EQ predicate on the "image_price_2k" field.
-func ImagePrice2kEQ(v float64) predicate.Group {
+func ImagePrice2kEQ(v money.Amount) predicate.Group {
 	return predicate.Group(sql.FieldEQ(FieldImagePrice2k, v))
 }
 
 // ImagePrice2kNEQ applies the NEQ predicate on the "image_price_2k" field.
-func ImagePrice2kNEQ(v float64) predicate.Group {
+func ImagePrice2kNEQ(v money.Amount) predicate.Group {
 	return predicate.Group(sql.FieldNEQ(FieldImagePrice2k, v))
 }
 
 // ImagePrice2kIn applies the In predicate on the "image_price_2k" field.
-func ImagePrice2kIn(vs ...float64) predicate.Group {
+func ImagePrice2kIn(vs ...money.Amount) predicate.Group {
 	return predicate.Group(sql.FieldIn(FieldImagePrice2k, vs...))
 }
 
 // ImagePrice2kNotIn applies the NotIn predicate on the "image_price_2k" field.
-func ImagePrice2kNotIn(vs ...float64) predicate.Group {
+func ImagePrice2kNotIn(vs ...money.Amount) predicate.Group {
 	return predicate.Group(sql.FieldNotIn(FieldImagePrice2k, vs...))
 }
 
 // ImagePrice2kGT applies the GT predicate on the "image_price_2k" field.
-func ImagePrice2kGT(v float64) predicate.Group {
+func ImagePrice2kGT(v money.Amount) predicate.Group {
 	return predicate.Group(sql.FieldGT(FieldImagePrice2k, v))
 }
 
 // ImagePrice2kGTE applies the GTE predicate on the "image_price_2k" field.
-func ImagePrice2kGTE(v float64) predicate.Group {
+func ImagePrice2kGTE(v money.Amount) predicate.Group {
 	return predicate.Group(sql.FieldGTE(FieldImagePrice2k, v))
 }
 
 // ImagePrice2kLT applies the LT predicate on the "image_price_2k" field.
-func ImagePrice2kLT(v float64) predicate.Group {
+func ImagePrice2kLT(v money.Amount) predicate.Group {
 	return predicate.Group(sql.FieldLT(FieldImagePrice2k, v))
 }
 
 // ImagePrice2kLTE applies the LTE predicate on the "image_price_2k" field.
-func ImagePrice2kLTE(v float64) predicate.Group {
+func ImagePrice2kLTE(v money.Amount) predicate.Group {
 	return predicate.Group(sql.FieldLTE(FieldImagePrice2k, v))
 }
 
@@ -1011,42 +1022,42 @@ func ImagePrice2kNotNil() predicate.Group {
 }
 
 // ImagePrice4kEQ applies the EQ predicate on the "image_price_4k" field.
-func ImagePrice4kEQ(v float64) predicate.Group {
+func ImagePrice4kEQ(v money.Amount) predicate.Group {
 	return predicate.Group(sql.FieldEQ(FieldImagePrice4k, v))
 }
 
 // ImagePrice4kNEQ applies the NEQ predicate on the "image_price_4k" field.
-func ImagePrice4kNEQ(v float64) predicate.Group {
+func ImagePrice4kNEQ(v money.Amount) predicate.Group {
 	return predicate.Group(sql.FieldNEQ(FieldImagePrice4k, v))
 }
 
 // ImagePrice4kIn applies the In predicate on the "image_price_4k" field.
-func ImagePrice4kIn(vs ...float64) predicate.Group {
+func ImagePrice4kIn(vs ...money.Amount) predicate.Group {
 	return predicate.Group(sql.FieldIn(FieldImagePrice4k, vs...))
 }
 
 // ImagePrice4kNotIn applies the NotIn predicate on the "image_price_4k" field.
-func ImagePrice4kNotIn(vs ...float64) predicate.Group {
+func ImagePrice4kNotIn(vs ...money.Amount) predicate.Group {
 	return predicate.Group(sql.FieldNotIn(FieldImagePrice4k, vs...))
 }
 
 // ImagePrice4kGT applies the GT predicate on the "image_price_4k" field.
-func ImagePrice4kGT(v float64) predicate.Group {
+func ImagePrice4kGT(v money.Amount) predicate.Group {
 	return predicate.Group(sql.FieldGT(FieldImagePrice4k, v))
 }
 
 // ImagePrice4kGTE applies the GTE predicate on the "image_price_4k" field.
-func ImagePrice4kGTE(v float64) predicate.Group {
+func ImagePrice4kGTE(v money.Amount) predicate.Group {
 	return predicate.Group(sql.FieldGTE(FieldImagePrice4k, v))
 }
 
 // ImagePrice4kLT applies the LT predicate on the "image_price_4k" field.
-func ImagePrice4kLT(v float64) predicate.Group {
+func ImagePrice4kLT(v money.Amount) predicate.Group {
 	return predicate.Group(sql.FieldLT(FieldImagePrice4k, v))
 }
 
 // ImagePrice4kLTE applies the LTE predicate on the "image_price_4k" field.
-func ImagePrice4kLTE(v float64) predicate.Group {
+func ImagePrice4kLTE(v money.Amount) predicate.Group {
 	return predicate.Group(sql.FieldLTE(FieldImagePrice4k, v))
 }
 
@@ -1061,42 +1072,42 @@ func ImagePrice4kNotNil() predicate.Group {
 }
 
 // SoraImagePrice360EQ applies the EQ predicate on the "sora_image_price_360" field.
-func SoraImagePrice360EQ(v float64) predicate.Group {
+func SoraImagePrice360EQ(v money.Amount) predicate.Group {
 	return predicate.Group(sql.FieldEQ(FieldSoraImagePrice360, v))
 }
 
 // SoraImagePrice360NEQ applies the NEQ predicate on the "sora_image_price_360" field.
-func SoraImagePrice360NEQ(v float64) predicate.Group {
+func SoraImagePrice360NEQ(v money.Amount) predicate.Group {
 	return predicate.Group(sql.FieldNEQ(FieldSoraImagePrice360, v))
 }
 
 // SoraImagePrice360In applies the In predicate on the "sora_image_price_360" field.
-func SoraImagePrice360In(vs ...float64) predicate.Group {
+func SoraImagePrice360In(vs ...money.Amount) predicate.Group {
 	return predicate.Group(sql.FieldIn(FieldSoraImagePrice360, vs...))
 }
 
 // SoraImagePrice360NotIn applies the NotIn predicate on the "sora_image_price_360" field.
-func SoraImagePrice360NotIn(vs ...float64) predicate.Group {
+func SoraImagePrice360NotIn(vs ...money.Amount) predicate.Group {
 	return predicate.Group(sql.FieldNotIn(FieldSoraImagePrice360, vs...))
 }
 
 // SoraImagePrice360GT applies the GT predicate on the "sora_image_price_360" field.
-func SoraImagePrice360GT(v float64) predicate.Group {
+func SoraImagePrice360GT(v money.Amount) predicate.Group {
 	return predicate.Group(sql.FieldGT(FieldSoraImagePrice360, v))
 }
 
 // SoraImagePrice360GTE applies the GTE predicate on the "sora_image_price_360" field.
-func SoraImagePrice360GTE(v float64) predicate.Group {
+func SoraImagePrice360GTE(v money.Amount) predicate.Group {
 	return predicate.Group(sql.FieldGTE(FieldSoraImagePrice360, v))
 }
 
 // SoraImagePrice360LT applies the LT predicate on the "sora_image_price_360" field.
-func SoraImagePrice360LT(v float64) predicate.Group {
+func SoraImagePrice360LT(v money.Amount) predicate.Group {
 	return predicate.Group(sql.FieldLT(FieldSoraImagePrice360, v))
 }
 
 // SoraImagePrice360LTE applies the LTE predicate on the "sora_image_price_360" field.
-func SoraImagePrice360LTE(v float64) predicate.Group {
+func SoraImagePrice360LTE(v money.Amount) predicate.Group {
 	return predicate.Group(sql.FieldLTE(FieldSoraImagePrice360, v))
 }
 
@@ -1111,42 +1122,42 @@ func SoraImagePrice360NotNil() predicate.Group {
 }
 
 // SoraImagePrice540EQ applies the EQ predicate on the "sora_image_price_540" field.
-func SoraImagePrice540EQ(v float64) predicate.Group {
+func SoraImagePrice540EQ(v money.Amount) predicate.Group {
 	return predicate.Group(sql.FieldEQ(FieldSoraImagePrice540, v))
 }
 
 // SoraImagePrice540NEQ applies the NEQ predicate on the "sora_image_price_540" field.
-func SoraImagePrice540NEQ(v float64) predicate.Group {
+func SoraImagePrice540NEQ(v money.Amount) predicate.Group {
 	return predicate.Group(sql.FieldNEQ(FieldSoraImagePrice540, v))
 }
 
 // SoraImagePrice540In applies the In predicate on the "sora_image_price_540" field.
-func SoraImagePrice540In(vs ...float64) predicate.Group {
+func SoraImagePrice540In(vs ...money.Amount) predicate.Group {
 	return predicate.Group(sql.FieldIn(FieldSoraImagePrice540, vs...))
 }
 
 // SoraImagePrice540NotIn applies the NotIn predicate on the "sora_image_price_540" field.
-func SoraImagePrice540NotIn(vs ...float64) predicate.Group {
+func SoraImagePrice540NotIn(vs ...money.Amount) predicate.Group {
 	return predicate.Group(sql.FieldNotIn(FieldSoraImagePrice540, vs...))
 }
 
 // SoraImagePrice540GT applies the GT predicate on the "sora_image_price_540" field.
-func SoraImagePrice540GT(v float64) predicate.Group {
+func SoraImagePrice540GT(v money.Amount) predicate.Group {
 	return predicate.Group(sql.FieldGT(FieldSoraImagePrice540, v))
 }
 
 // SoraImagePrice540GTE applies the GTE predicate on the "sora_image_price_540" field.
-func SoraImagePrice540GTE(v float64) predicate.Group {
+func SoraImagePrice540GTE(v money.Amount) predicate.Group {
 	return predicate.Group(sql.FieldGTE(FieldSoraImagePrice540, v))
 }
 
 // SoraImagePrice540LT applies the LT predicate on the "sora_image_price_540" field.
-func SoraImagePrice540LT(v float64) predicate.Group {
+func SoraImagePrice540LT(v money.Amount) predicate.Group {
 	return predicate.Group(sql.FieldLT(FieldSoraImagePrice540, v))
 }
 
 // SoraImagePrice540LTE applies the LTE predicate on the "sora_image_price_540" field.
-func SoraImagePrice540LTE(v float64) predicate.Group {
+func SoraImagePrice540LTE(v money.Amount) predicate.Group {
 	return predicate.Group(sql.FieldLTE(FieldSoraImagePrice540, v))
 }
 
@@ -1161,42 +1172,42 @@ func SoraImagePrice540NotNil() predicate.Group {
 }
 
 // SoraVideoPricePerRequestEQ applies the EQ predicate on the "sora_video_price_per_request" field.
-func SoraVideoPricePerRequestEQ(v float64) predicate.Group {
+func SoraVideoPricePerRequestEQ(v money.Amount) predicate.Group {
 	return predicate.Group(sql.FieldEQ(FieldSoraVideoPricePerRequest, v))
 }
 
 // SoraVideoPricePerRequestNEQ applies the NEQ predicate on the "sora_video_price_per_request" field.
-func SoraVideoPricePerRequestNEQ(v float64) predicate.Group {
+func SoraVideoPricePerRequestNEQ(v money.Amount) predicate.Group {
 	return predicate.Group(sql.FieldNEQ(FieldSoraVideoPricePerRequest, v))
 }
 
 // SoraVideoPricePerRequestIn applies the In predicate on the "sora_video_price_per_request" field.
-func SoraVideoPricePerRequestIn(vs ...float64) predicate.Group {
+func SoraVideoPricePerRequestIn(vs ...money.Amount) predicate.Group {
 	return predicate.Group(sql.FieldIn(FieldSoraVideoPricePerRequest, vs...))
 }
 
 // SoraVideoPricePerRequestNotIn applies the NotIn predicate on the "sora_video_price_per_request" field.
-func SoraVideoPricePerRequestNotIn(vs ...float64) predicate.Group {
+func SoraVideoPricePerRequestNotIn(vs ...money.Amount) predicate.Group {
 	return predicate.Group(sql.FieldNotIn(FieldSoraVideoPricePerRequest, vs...))
 }
 
 // SoraVideoPricePerRequestGT applies the GT predicate on the "sora_video_price_per_request" field.
-func SoraVideoPricePerRequestGT(v float64) predicate.Group {
+func SoraVideoPricePerRequestGT(v money.Amount) predicate.Group {
 	return predicate.Group(sql.FieldGT(FieldSoraVideoPricePerRequest, v))
 }
 
 // SoraVideoPricePerRequestGTE applies the GTE predicate on the "sora_video_price_per_request" field.
-func SoraVideoPricePerRequestGTE(v float64) predicate.Group {
+func SoraVideoPricePerRequestGTE(v money.Amount) predicate.Group {
 	return predicate.Group(sql.FieldGTE(FieldSoraVideoPricePerRequest, v))
 }
 
 // SoraVideoPricePerRequestLT applies the LT predicate on the "sora_video_price_per_request" field.
-func SoraVideoPricePerRequestLT(v float64) predicate.Group {
+func SoraVideoPricePerRequestLT(v money.Amount) predicate.Group {
 	return predicate.Group(sql.FieldLT(FieldSoraVideoPricePerRequest, v))
 }
 
 // SoraVideoPricePerRequestLTE applies the LTE predicate on the "sora_video_price_per_request" field.
-func SoraVideoPricePerRequestLTE(v float64) predicate.Group {
+func SoraVideoPricePerRequestLTE(v money.Amount) predicate.Group {
 	return predicate.Group(sql.FieldLTE(FieldSoraVideoPricePerRequest, v))
 }
 
@@ -1211,42 +1222,42 @@ func SoraVideoPricePerRequestNotNil() predicate.Group {
 }
 
 // SoraVideoPricePerRequestHdEQ applies the EQ predicate on the "sora_video_price_per_request_hd" field.
-func SoraVideoPricePerRequestHdEQ(v float64) predicate.Group {
+func SoraVideoPricePerRequestHdEQ(v money.Amount) predicate.Group {
 	return predicate.Group(sql.FieldEQ(FieldSoraVideoPricePerRequestHd, v))
 }
 
 // SoraVideoPricePerRequestHdNEQ applies the NEQ predicate on the "sora_video_price_per_request_hd" field.
-func SoraVideoPricePerRequestHdNEQ(v float64) predicate.Group {
+func SoraVideoPricePerRequestHdNEQ(v money.Amount) predicate.Group {
 	return predicate.Group(sql.FieldNEQ(FieldSoraVideoPricePerRequestHd, v))
 }
 
 // SoraVideoPricePerRequestHdIn applies the In predicate on the "sora_video_price_per_request_hd" field.
-func SoraVideoPricePerRequestHdIn(vs ...float64) predicate.Group {
+func SoraVideoPricePerRequestHdIn(vs ...money.Amount) predicate.Group {
 	return predicate.Group(sql.FieldIn(FieldSoraVideoPricePerRequestHd, vs...))
 }
 
 // SoraVideoPricePerRequestHdNotIn applies the NotIn predicate on the "sora_video_price_per_request_hd" field.
-func SoraVideoPricePerRequestHdNotIn(vs ...float64) predicate.Group {
+func SoraVideoPricePerRequestHdNotIn(vs ...money.Amount) predicate.Group {
 	return predicate.Group(sql.FieldNotIn(FieldSoraVideoPricePerRequestHd, vs...))
 }
 
 // SoraVideoPricePerRequestHdGT applies the GT predicate on the "sora_video_price_per_request_hd" field.
-func SoraVideoPricePerRequestHdGT(v float64) predicate.Group {
+func SoraVideoPricePerRequestHdGT(v money.Amount) predicate.Group {
 	return predicate.Group(sql.FieldGT(FieldSoraVideoPricePerRequestHd, v))
 }
 
 // SoraVideoPricePerRequestHdGTE applies the GTE predicate on the "sora_video_price_per_request_hd" field.
-func SoraVideoPricePerRequestHdGTE(v float64) predicate.Group {
+func SoraVideoPricePerRequestHdGTE(v money.Amount) predicate.Group {
 	return predicate.Group(sql.FieldGTE(FieldSoraVideoPricePerRequestHd, v))
 }
 
 // SoraVideoPricePerRequestHdLT applies the LT predicate on the "sora_video_price_per_request_hd" field.
-func SoraVideoPricePerRequestHdLT(v float64) predicate.Group {
+func SoraVideoPricePerRequestHdLT(v money.Amount) predicate.Group {
 	return predicate.Group(sql.FieldLT(FieldSoraVideoPricePerRequestHd, v))
 }
 
 // SoraVideoPricePerRequestHdLTE applies the LTE predicate on the "sora_video_price_per_request_hd" field.
-func SoraVideoPricePerRequestHdLTE(v float64) predicate.Group {
+func SoraVideoPricePerRequestHdLTE(v money.Amount) predicate.Group {
 	return predicate.Group(sql.FieldLTE(FieldSoraVideoPricePerRequestHd, v))
 }
 
@@ -1301,42 +1312,42 @@ func SoraStorageQuotaBytesLTE(v int64) predicate.Group {
 }
 
 // VideoPricePerRequestEQ applies the EQ predicate on the "video_price_per_request" field.
-func VideoPricePerRequestEQ(v float64) predicate.Group {
+func VideoPricePerRequestEQ(v money.Amount) predicate.Group {
 	return predicate.Group(sql.FieldEQ(FieldVideoPricePerRequest, v))
 }
 
 // VideoPricePerRequestNEQ applies the NEQ predicate on the "video_price_per_request" field.
-func VideoPricePerRequestNEQ(v float64) predicate.Group {
+func VideoPricePerRequestNEQ(v money.Amount) predicate.Group {
 	return predicate.Group(sql.FieldNEQ(FieldVideoPricePerRequest, v))
 }
 
 // VideoPricePerRequestIn applies the In predicate on the "video_price_per_request" field.
-func VideoPricePerRequestIn(vs ...float64) predicate.Group {
+func VideoPricePerRequestIn(vs ...money.Amount) predicate.Group {
 	return predicate.Group(sql.FieldIn(FieldVideoPricePerRequest, vs...))
 }
 
 // VideoPricePerRequestNotIn applies the NotIn predicate on the "video_price_per_request" field.
-func VideoPricePerRequestNotIn(vs ...float64) predicate.Group {
+func VideoPricePerRequestNotIn(vs ...money.Amount) predicate.Group {
 	return predicate.Group(sql.FieldNotIn(FieldVideoPricePerRequest, vs...))
 }
 
 // VideoPricePerRequestGT applies the GT predicate on the "video_price_per_request" field.
-func VideoPricePerRequestGT(v float64) predicate.Group {
+func VideoPricePerRequestGT(v money.Amount) predicate.Group {
 	return predicate.Group(sql.FieldGT(FieldVideoPricePerRequest, v))
 }
 
 // VideoPricePerRequestGTE applies the GTE predicate on the "video_price_per_request" field.
-func VideoPricePerRequestGTE(v float64) predicate.Group {
+func VideoPricePerRequestGTE(v money.Amount) predicate.Group {
 	return predicate.Group(sql.FieldGTE(FieldVideoPricePerRequest, v))
 }
 
 // VideoPricePerRequestLT applies the LT predicate on the "video_price_per_request" field.
-func VideoPricePerRequestLT(v float64) predicate.Group {
+func VideoPricePerRequestLT(v money.Amount) predicate.Group {
 	return predicate.Group(sql.FieldLT(FieldVideoPricePerRequest, v))
 }
 
 // VideoPricePerRequestLTE applies the LTE predicate on the "video_price_per_request" field.
-func VideoPricePerRequestLTE(v float64) predicate.Group {
+func VideoPricePerRequestLTE(v money.Amount) predicate.Group {
 	return predicate.Group(sql.FieldLTE(FieldVideoPricePerRequest, v))
 }
 
@@ -1351,42 +1362,42 @@ func VideoPricePerRequestNotNil() predicate.Group {
 }
 
 // VideoPricePerRequestHdEQ applies the EQ predicate on the "video_price_per_request_hd" field.
-func VideoPricePerRequestHdEQ(v float64) predicate.Group {
+func VideoPricePerRequestHdEQ(v money.Amount) predicate.Group {
 	return predicate.Group(sql.FieldEQ(FieldVideoPricePerRequestHd, v))
 }
 
 // VideoPricePerRequestHdNEQ applies the NEQ predicate on the "video_price_per_request_hd" field.
-func VideoPricePerRequestHdNEQ(v float64) predicate.Group {
+func VideoPricePerRequestHdNEQ(v money.Amount) predicate.Group {
 	return predicate.Group(sql.FieldNEQ(FieldVideoPricePerRequestHd, v))
 }
 
 // VideoPricePerRequestHdIn applies the In predicate on the "video_price_per_request_hd" field.
-func VideoPricePerRequestHdIn(vs ...float64) predicate.Group {
+func VideoPricePerRequestHdIn(vs ...money.Amount) predicate.Group {
 	return predicate.Group(sql.FieldIn(FieldVideoPricePerRequestHd, vs...))
 }
 
 // VideoPricePerRequestHdNotIn applies the NotIn predicate on the "video_price_per_request_hd" field.
-func VideoPricePerRequestHdNotIn(vs ...float64) predicate.Group {
+func VideoPricePerRequestHdNotIn(vs ...money.Amount) predicate.Group {
 	return predicate.Group(sql.FieldNotIn(FieldVideoPricePerRequestHd, vs...))
 }
 
 // VideoPricePerRequestHdGT applies the GT predicate on the "video_price_per_request_hd" field.
-func VideoPricePerRequestHdGT(v float64) predicate.Group {
+func VideoPricePerRequestHdGT(v money.Amount) predicate.Group {
 	return predicate.Group(sql.FieldGT(FieldVideoPricePerRequestHd, v))
 }
 
 // VideoPricePerRequestHdGTE applies the GTE predicate on the "video_price_per_request_hd" field.
-func VideoPricePerRequestHdGTE(v float64) predicate.Group {
+func VideoPricePerRequestHdGTE(v money.Amount) predicate.Group {
 	return predicate.Group(sql.FieldGTE(FieldVideoPricePerRequestHd, v))
 }
 
 // VideoPricePerRequestHdLT applies the LT predicate on the "video_price_per_request_hd" field.
-func VideoPricePerRequestHdLT(v float64) predicate.Group {
+func VideoPricePerRequestHdLT(v money.Amount) predicate.Group {
 	return predicate.Group(sql.FieldLT(FieldVideoPricePerRequestHd, v))
 }
 
 // VideoPricePerRequestHdLTE applies the LTE predicate on the "video_price_per_request_hd" field.
-func VideoPricePerRequestHdLTE(v float64) predicate.Group {
+func VideoPricePerRequestHdLTE(v money.Amount) predicate.Group {
 	return predicate.Group(sql.FieldLTE(FieldVideoPricePerRequestHd, v))
 }
 
@@ -1400,6 +1411,106 @@ func VideoPricePerRequestHdNotNil() predicate.Group {
 	return predicate.Group(sql.FieldNotNull(FieldVideoPricePerRequestHd))
 }
 
+// AudioPricePerMinuteEQ applies the EQ predicate on the "audio_price_per_minute" field.
+func AudioPricePerMinuteEQ(v money.Amount) predicate.Group {
+	return predicate.Group(sql.FieldEQ(FieldAudioPricePerMinute, v))
+}
+
+// AudioPricePerMinuteNEQ applies the NEQ predicate on the "audio_price_per_minute" field.
+func AudioPricePerMinuteNEQ(v money.Amount) predicate.Group {
+	return predicate.Group(sql.FieldNEQ(FieldAudioPricePerMinute, v))
+}
+
+// AudioPricePerMinuteIn applies the In predicate on the "audio_price_per_minute" field.
+func AudioPricePerMinuteIn(vs ...money.Amount) predicate.Group {
+	return predicate.Group(sql.FieldIn(FieldAudioPricePerMinute, vs...))
+}
+
+// AudioPricePerMinuteNotIn applies the NotIn predicate on the "audio_price_per_minute" field.
+func AudioPricePerMinuteNotIn(vs ...money.Amount) predicate.Group {
+	return predicate.Group(sql.FieldNotIn(FieldAudioPricePerMinute, vs...))
+}
+
+// AudioPricePerMinuteGT applies the GT predicate on the "audio_price_per_minute" field.
+func AudioPricePerMinuteGT(v money.Amount) predicate.Group {
+	return predicate.Group(sql.FieldGT(FieldAudioPricePerMinute, v))
+}
+
+// AudioPricePerMinuteGTE applies the GTE predicate on the "audio_price_per_minute" field.
+func AudioPricePerMinuteGTE(v money.Amount) predicate.Group {
+	return predicate.Group(sql.FieldGTE(FieldAudioPricePerMinute, v))
+}
+
+// AudioPricePerMinuteLT applies the LT predicate on the "audio_price_per_minute" field.
+func AudioPricePerMinuteLT(v money.Amount) predicate.Group {
+	return predicate.Group(sql.FieldLT(FieldAudioPricePerMinute, v))
+}
+
+// AudioPricePerMinuteLTE applies the LTE predicate on the "audio_price_per_minute" field.
+func AudioPricePerMinuteLTE(v money.Amount) predicate.Group {
+	return predicate.Group(sql.FieldLTE(FieldAudioPricePerMinute, v))
+}
+
+// AudioPricePerMinuteIsNil applies the IsNil predicate on the "audio_price_per_minute" field.
+func AudioPricePerMinuteIsNil() predicate.Group {
+	return predicate.Group(sql.FieldIsNull(FieldAudioPricePerMinute))
+}
+
+// AudioPricePerMinuteNotNil applies the NotNil predicate on the "audio_price_per_minute" field.
+func AudioPricePerMinuteNotNil() predicate.Group {
+	return predicate.Group(sql.FieldNotNull(FieldAudioPricePerMinute))
+}
+
+// AudioSpeechPricePer1mCharsEQ applies the EQ predicate on the "audio_speech_price_per_1m_chars" field.
+func AudioSpeechPricePer1mCharsEQ(v money.Amount) predicate.Group {
+	return predicate.Group(sql.FieldEQ(FieldAudioSpeechPricePer1mChars, v))
+}
+
+// AudioSpeechPricePer1mCharsNEQ applies the NEQ predicate on the "audio_speech_price_per_1m_chars" field.
+func AudioSpeechPricePer1mCharsNEQ(v money.Amount) predicate.Group {
+	return predicate.Group(sql.FieldNEQ(FieldAudioSpeechPricePer1mChars, v))
+}
+
+// AudioSpeechPricePer1mCharsIn applies the In predicate on the "audio_speech_price_per_1m_chars" field.
+func AudioSpeechPricePer1mCharsIn(vs ...money.Amount) predicate.Group {
+	return predicate.Group(sql.FieldIn(FieldAudioSpeechPricePer1mChars, vs...))
+}
+
+// AudioSpeechPricePer1mCharsNotIn applies the NotIn predicate on the "audio_speech_price_per_1m_chars" field.
+func AudioSpeechPricePer1mCharsNotIn(vs ...money.Amount) predicate.Group {
+	return predicate.Group(sql.FieldNotIn(FieldAudioSpeechPricePer1mChars, vs...))
+}
+
+// AudioSpeechPricePer1mCharsGT applies the GT predicate on the "audio_speech_price_per_1m_chars" field.
+func AudioSpeechPricePer1mCharsGT(v money.Amount) predicate.Group {
+	return predicate.Group(sql.FieldGT(FieldAudioSpeechPricePer1mChars, v))
+}
+
+// AudioSpeechPricePer1mCharsGTE applies the GTE predicate on the "audio_speech_price_per_1m_chars" field.
+func AudioSpeechPricePer1mCharsGTE(v money.Amount) predicate.Group {
+	return predicate.Group(sql.FieldGTE(FieldAudioSpeechPricePer1mChars, v))
+}
+
+// AudioSpeechPricePer1mCharsLT applies the LT predicate on the "audio_speech_price_per_1m_chars" field.
+func AudioSpeechPricePer1mCharsLT(v money.Amount) predicate.Group {
+	return predicate.Group(sql.FieldLT(FieldAudioSpeechPricePer1mChars, v))
+}
+
+// AudioSpeechPricePer1mCharsLTE applies the LTE predicate on the "audio_speech_price_per_1m_chars" field.
+func AudioSpeechPricePer1mCharsLTE(v money.Amount) predicate.Group {
+	return predicate.Group(sql.FieldLTE(FieldAudioSpeechPricePer1mChars, v))
+}
+
+// AudioSpeechPricePer1mCharsIsNil applies the IsNil predicate on the "audio_speech_price_per_1m_chars" field.
+func AudioSpeechPricePer1mCharsIsNil() predicate.Group {
+	return predicate.Group(sql.FieldIsNull(FieldAudioSpeechPricePer1mChars))
+}
+
+// AudioSpeechPricePer1mCharsNotNil applies the NotNil predicate on the "audio_speech_price_per_1m_chars" field.
+func AudioSpeechPricePer1mCharsNotNil() predicate.Group {
+	return predicate.Group(sql.FieldNotNull(FieldAudioSpeechPricePer1mChars))
+}
+
 // ClaudeCodeOnlyEQ applies the EQ predicate on the "claude_code_only" field.
 func ClaudeCodeOnlyEQ(v bool) predicate.Group {
 	return predicate.Group(sql.FieldEQ(FieldClaudeCodeOnly, v))
@@ -18,6 +18,7 @@ import (
 	"github.com/Wei-Shaw/sub2api/ent/usagelog"
 	"github.com/Wei-Shaw/sub2api/ent/user"
 	"github.com/Wei-Shaw/sub2api/ent/usersubscription"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
 )
 
 // GroupCreate is the builder for creating a Group entity.
@@ -161,13 +162,13 @@ func (_c *GroupCreate) SetNillableSubscriptionType(v *string) *GroupCreate {
 }
 
 // SetDailyLimitUsd sets the "daily_limit_usd" field.
-func (_c *GroupCreate) SetDailyLimitUsd(v float64) *GroupCreate {
+func (_c *GroupCreate) SetDailyLimitUsd(v money.Amount) *GroupCreate {
 	_c.mutation.SetDailyLimitUsd(v)
 	return _c
 }
 
 // SetNillableDailyLimitUsd sets the "daily_limit_usd" field if the given value is not nil.
-func (_c *GroupCreate) SetNillableDailyLimitUsd(v *float64) *GroupCreate {
+func (_c *GroupCreate) SetNillableDailyLimitUsd(v *money.Amount) *GroupCreate {
 	if v != nil {
 		_c.SetDailyLimitUsd(*v)
 	}
@@ -175,13 +176,13 @@ func (_c *GroupCreate) SetNillableDailyLimitUsd(v *float64) *GroupCreate {
 }
 
 // SetWeeklyLimitUsd sets the "weekly_limit_usd" field.
-func (_c *GroupCreate) SetWeeklyLimitUsd(v float64) *GroupCreate {
+func (_c *GroupCreate) SetWeeklyLimitUsd(v money.Amount) *GroupCreate {
 	_c.mutation.SetWeeklyLimitUsd(v)
 	return _c
 }
 
 // SetNillableWeeklyLimitUsd sets the "weekly_limit_usd" field if the given value is not nil.
-func (_c *GroupCreate) SetNillableWeeklyLimitUsd(v *float64) *GroupCreate {
+func (_c *GroupCreate) SetNillableWeeklyLimitUsd(v *money.Amount) *GroupCreate {
 	if v != nil {
 		_c.SetWeeklyLimitUsd(*v)
 	}
@@ -189,13 +190,13 @@ func (_c *GroupCreate) SetNillableWeeklyLimitUsd(v *float64) *GroupCreate {
 }
 
 // SetMonthlyLimitUsd sets the "monthly_limit_usd" field.
-func (_c *GroupCreate) SetMonthlyLimitUsd(v float64) *GroupCreate {
+func (_c *GroupCreate) SetMonthlyLimitUsd(v money.Amount) *GroupCreate {
 	_c.mutation.SetMonthlyLimitUsd(v)
 	return _c
 }
 
 // SetNillableMonthlyLimitUsd sets the "monthly_limit_usd" field if the given value is not nil.
-func (_c *GroupCreate) SetNillableMonthlyLimitUsd(v *float64) *GroupCreate {
+func (_c *GroupCreate) SetNillableMonthlyLimitUsd(v *money.Amount) *GroupCreate {
 	if v != nil {
 		_c.SetMonthlyLimitUsd(*v)
 	}
@@ -217,13 +218,13 @@ func (_c *GroupCreate) SetNillableDefaultValidityDays(v *int) *GroupCreate {
 }
 
 // SetImagePrice1k sets the "image_price_1k" field.
-func (_c *GroupCreate) SetImagePrice1k(v float64) *GroupCreate {
+func (_c *GroupCreate) SetImagePrice1k(v money.Amount) *GroupCreate {
 	_c.mutation.SetImagePrice1k(v)
 	return _c
 }
 
 // SetNillableImagePrice1k sets the "image_price_1k" field if the given value is not nil.
-func (_c *GroupCreate) SetNillableImagePrice1k(v *float64) *GroupCreate {
+func (_c *GroupCreate) SetNillableImagePrice1k(v *money.Amount) *GroupCreate {
 	if v != nil {
 		_c.SetImagePrice1k(*v)
 	}
@@ -231,13 +232,13 @@ func (_c *GroupCreate) SetNillableImagePrice1k(v *float64) *GroupCreate {
 }
 
 // SetImagePrice2k sets the "image_price_2k" field.
-func (_c *GroupCreate) SetImagePrice2k(v float64) *GroupCreate {
+func (_c *GroupCreate) SetImagePrice2k(v money.Amount) *GroupCreate {
 	_c.mutation.SetImagePrice2k(v)
 	return _c
 }
 
 // SetNillableImagePrice2k sets the "image_price_2k" field if the given value is not nil.
-func (_c *GroupCreate) SetNillableImagePrice2k(v *float64) *GroupCreate {
+func (_c *GroupCreate) SetNillableImagePrice2k(v *money.Amount) *GroupCreate {
 	if v != nil {
 		_c.SetImagePrice2k(*v)
 	}
@@ -245,13 +246,13 @@ func (_c *GroupCreate) SetNillableImagePrice2k(v *float64) *GroupCreate {
 }
 
 // SetImagePrice4k sets the "image_price_4k" field.
-func (_c *GroupCreate) SetImagePrice4k(v float64) *GroupCreate {
+func (_c *GroupCreate) SetImagePrice4k(v money.Amount) *GroupCreate {
 	_c.mutation.SetImagePrice4k(v)
 	return _c
 }
 
 // SetNillableImagePrice4k sets the "image_price_4k" field if the given value is not nil.
-func (_c *GroupCreate) SetNillableImagePrice4k(v *float64) *GroupCreate {
+func (_c *GroupCreate) SetNillableImagePrice4k(v *money.Amount) *GroupCreate {
 	if v != nil {
 		_c.SetImagePrice4k(*v)
 	}
@@ -259,13 +260,13 @@ func (_c *GroupCreate) SetNillableImagePrice4k(v *float64) *GroupCreate {
 }
 
 // SetSoraImagePrice360 sets the "sora_image_price_360" field.
-func (_c *GroupCreate) SetSoraImagePrice360(v float64) *GroupCreate {
+func (_c *GroupCreate) SetSoraImagePrice360(v money.Amount) *GroupCreate {
 	_c.mutation.SetSoraImagePrice360(v)
 	return _c
 }
 
 // SetNillableSoraImagePrice360 sets the "sora_image_price_360" field if the given value is not nil.
-func (_c *GroupCreate) SetNillableSoraImagePrice360(v *float64) *GroupCreate {
+func (_c *GroupCreate) SetNillableSoraImagePrice360(v *money.Amount) *GroupCreate {
 	if v != nil {
 		_c.SetSoraImagePrice360(*v)
 	}
@@ -273,13 +274,13 @@ func (_c *GroupCreate) SetNillableSoraImagePrice360(v *float64) *GroupCreate {
 }
 
 // SetSoraImagePrice540 sets the "sora_image_price_540" field.
-func (_c *GroupCreate) SetSoraImagePrice540(v float64) *GroupCreate {
+func (_c *GroupCreate) SetSoraImagePrice540(v money.Amount) *GroupCreate {
 	_c.mutation.SetSoraImagePrice540(v)
 	return _c
 }
 
 // SetNillableSoraImagePrice540 sets the "sora_image_price_540" field if the given value is not nil.
-func (_c *GroupCreate) SetNillableSoraImagePrice540(v *float64) *GroupCreate {
+func (_c *GroupCreate) SetNillableSoraImagePrice540(v *money.Amount) *GroupCreate {
 	if v != nil {
 		_c.SetSoraImagePrice540(*v)
 	}
@@ -287,13 +288,13 @@ func (_c *GroupCreate) SetNillableSoraImagePrice540(v *float64) *GroupCreate {
 }
 
 // SetSoraVideoPricePerRequest sets the "sora_video_price_per_request" field.
-func (_c *GroupCreate) SetSoraVideoPricePerRequest(v float64) *GroupCreate {
+func (_c *GroupCreate) SetSoraVideoPricePerRequest(v money.Amount) *GroupCreate {
 	_c.mutation.SetSoraVideoPricePerRequest(v)
 	return _c
 }
 
 // SetNillableSoraVideoPricePerRequest sets the "sora_video_price_per_request" field if the given value is not nil.
-func (_c *GroupCreate) SetNillableSoraVideoPricePerRequest(v *float64) *GroupCreate {
+func (_c *GroupCreate) SetNillableSoraVideoPricePerRequest(v *money.Amount) *GroupCreate {
 	if v != nil {
 		_c.SetSoraVideoPricePerRequest(*v)
 	}
@@ -301,13 +302,13 @@ func (_c *GroupCreate) SetNillableSoraVideoPricePerRequest(v *float64) *GroupCre
 }
 
 // SetSoraVideoPricePerRequestHd sets the "sora_video_price_per_request_hd" field.
-func (_c *GroupCreate) SetSoraVideoPricePerRequestHd(v float64) *GroupCreate {
+func (_c *GroupCreate) SetSoraVideoPricePerRequestHd(v money.Amount) *GroupCreate {
 	_c.mutation.SetSoraVideoPricePerRequestHd(v)
 	return _c
 }
 
 // SetNillableSoraVideoPricePerRequestHd sets the "sora_video_price_per_request_hd" field if the given value is not nil.
-func (_c *GroupCreate) SetNillableSoraVideoPricePerRequestHd(v *float64) *GroupCreate {
+func (_c *GroupCreate) SetNillableSoraVideoPricePerRequestHd(v *money.Amount) *GroupCreate {
 	if v != nil {
 		_c.SetSoraVideoPricePerRequestHd(*v)
 	}
@@ -329,13 +330,13 @@ func (_c *GroupCreate) SetNillableSoraStorageQuotaBytes(v *int64) *GroupCreate {
 }
 
 // SetVideoPricePerRequest sets the "video_price_per_request" field.
-func (_c *GroupCreate) SetVideoPricePerRequest(v float64) *GroupCreate {
+func (_c *GroupCreate) SetVideoPricePerRequest(v money.Amount) *GroupCreate {
 	_c.mutation.SetVideoPricePerRequest(v)
 	return _c
 }
 
 // SetNillableVideoPricePerRequest sets the "video_price_per_request" field if the given value is not nil.
-func (_c *GroupCreate) SetNillableVideoPricePerRequest(v *float64) *GroupCreate {
+func (_c *GroupCreate) SetNillableVideoPricePerRequest(v *money.Amount) *GroupCreate {
 	if v != nil {
 		_c.SetVideoPricePerRequest(*v)
 	}
@@ -343,19 +344,47 @@ func (_c *GroupCreate) SetNillableVideoPricePerRequest(v *float64) *GroupCreate
 }
 
 // SetVideoPricePerRequestHd sets the "video_price_per_request_hd" field.
-func (_c *GroupCreate) SetVideoPricePerRequestHd(v float64) *GroupCreate {
+func (_c *GroupCreate) SetVideoPricePerRequestHd(v money.Amount) *GroupCreate {
 	_c.mutation.SetVideoPricePerRequestHd(v)
 	return _c
 }
 
 // SetNillableVideoPricePerRequestHd sets the "video_price_per_request_hd" field if the given value is not nil.
-func (_c *GroupCreate) SetNillableVideoPricePerRequestHd(v *float64) *GroupCreate {
+func (_c *GroupCreate) SetNillableVideoPricePerRequestHd(v *money.Amount) *GroupCreate {
 	if v != nil {
 		_c.SetVideoPricePerRequestHd(*v)
 	}
 	return _c
 }
 
+// SetAudioPricePerMinute sets the "audio_price_per_minute" field.
+func (_c *GroupCreate) SetAudioPricePerMinute(v money.Amount) *GroupCreate {
+	_c.mutation.SetAudioPricePerMinute(v)
+	return _c
+}
+
+// SetNillableAudioPricePerMinute sets the "audio_price_per_minute" field if the given value is not nil.
+func (_c *GroupCreate) SetNillableAudioPricePerMinute(v *money.Amount) *GroupCreate {
+	if v != nil {
+		_c.SetAudioPricePerMinute(*v)
+	}
+	return _c
+}
+
+// SetAudioSpeechPricePer1mChars sets the "audio_speech_price_per_1m_chars" field.
+func (_c *GroupCreate) SetAudioSpeechPricePer1mChars(v money.Amount) *GroupCreate {
+	_c.mutation.SetAudioSpeechPricePer1mChars(v)
+	return _c
+}
+
+// SetNillableAudioSpeechPricePer1mChars sets the "audio_speech_price_per_1m_chars" field if the given value is not nil.
+func (_c *GroupCreate) SetNillableAudioSpeechPricePer1mChars(v *money.Amount) *GroupCreate {
+	if v != nil {
+		_c.SetAudioSpeechPricePer1mChars(*v)
+	}
+	return _c
+}
+
 // SetClaudeCodeOnly sets the "claude_code_only" field.
 func (_c *GroupCreate) SetClaudeCodeOnly(v bool) *GroupCreate {
 	_c.mutation.SetClaudeCodeOnly(v)
@@ -779,15 +808,15 @@ func (_c *GroupCreate) createSpec() (*Group, *sqlgraph.CreateSpec) {
 		_node.SubscriptionType = value
 	}
 	if value, ok := _c.mutation.DailyLimitUsd(); ok {
-		_spec.SetField(group.FieldDailyLimitUsd, field.TypeFloat64, value)
+		_spec.SetField(group.FieldDailyLimitUsd, field.TypeInt64, value)
 		_node.DailyLimitUsd = &value
 	}
 	if value, ok := _c.mutation.WeeklyLimitUsd(); ok {
-		_spec.SetField(group.FieldWeeklyLimitUsd, field.TypeFloat64, value)
+		_spec.SetField(group.FieldWeeklyLimitUsd, field.TypeInt64, value)
 		_node.WeeklyLimitUsd = &value
 	}
 	if value, ok := _c.mutation.MonthlyLimitUsd(); ok {
-		_spec.SetField(group.FieldMonthlyLimitUsd, field.TypeFloat64, value)
+		_spec.SetField(group.FieldMonthlyLimitUsd, field.TypeInt64, value)
 		_node.MonthlyLimitUsd = &value
 	}
 	if value, ok := _c.mutation.DefaultValidityDays(); ok {
@@ -795,31 +824,31 @@ func (_c *GroupCreate) createSpec() (*Group, *sqlgraph.CreateSpec) {
 		_node.DefaultValidityDays = value
 	}
 	if value, ok := _c.mutation.ImagePrice1k(); ok {
-		_spec.SetField(group.FieldImagePrice1k, field.TypeFloat64, value)
+		_spec.SetField(group.FieldImagePrice1k, field.TypeInt64, value)
 		_node.ImagePrice1k = &value
 	}
 	if value, ok := _c.mutation.ImagePrice2k(); ok {
-		_spec.SetField(group.FieldImagePrice2k, field.TypeFloat64, value)
+		_spec.SetField(group.FieldImagePrice2k, field.TypeInt64, value)
 		_node.ImagePrice2k = &value
 	}
 	if value, ok := _c.mutation.ImagePrice4k(); ok {
-		_spec.SetField(group.FieldImagePrice4k, field.TypeFloat64, value)
+		_spec.SetField(group.FieldImagePrice4k, field.TypeInt64, value)
 		_node.ImagePrice4k = &value
 	}
 	if value, ok := _c.mutation.SoraImagePrice360(); ok {
-		_spec.SetField(group.FieldSoraImagePrice360, field.TypeFloat64, value)
+		_spec.SetField(group.FieldSoraImagePrice360, field.TypeInt64, value)
 		_node.SoraImagePrice360 = &value
 	}
 	if value, ok := _c.mutation.SoraImagePrice540(); ok {
-		_spec.SetField(group.FieldSoraImagePrice540, field.TypeFloat64, value)
+		_spec.SetField(group.FieldSoraImagePrice540, field.TypeInt64, value)
 		_node.SoraImagePrice540 = &value
 	}
 	if value, ok := _c.mutation.SoraVideoPricePerRequest(); ok {
-		_spec.SetField(group.FieldSoraVideoPricePerRequest, field.TypeFloat64, value)
+		_spec.SetField(group.FieldSoraVideoPricePerRequest, field.TypeInt64, value)
 		_node.SoraVideoPricePerRequest = &value
 	}
 	if value, ok := _c.mutation.SoraVideoPricePerRequestHd(); ok {
-		_spec.SetField(group.FieldSoraVideoPricePerRequestHd, field.TypeFloat64, value)
+		_spec.SetField(group.FieldSoraVideoPricePerRequestHd, field.TypeInt64, value)
 		_node.SoraVideoPricePerRequestHd = &value
 	}
 	if value, ok := _c.mutation.SoraStorageQuotaBytes(); ok {
@@ -827,13 +856,21 @@ func (_c *GroupCreate) createSpec() (*Group, *sqlgraph.CreateSpec) {
 		_node.SoraStorageQuotaBytes = value
 	}
 	if value, ok := _c.mutation.VideoPricePerRequest(); ok {
-		_spec.SetField(group.FieldVideoPricePerRequest, field.TypeFloat64, value)
+		_spec.SetField(group.FieldVideoPricePerRequest, field.TypeInt64, value)
 		_node.VideoPricePerRequest = &value
 	}
 	if value, ok := _c.mutation.VideoPricePerRequestHd(); ok {
-		_spec.SetField(group.FieldVideoPricePerRequestHd, field.TypeFloat64, value)
+		_spec.SetField(group.FieldVideoPricePerRequestHd, field.TypeInt64, value)
 		_node.VideoPricePerRequestHd = &value
 	}
+	if value, ok := _c.mutation.AudioPricePerMinute(); ok {
+		_spec.SetField(group.FieldAudioPricePerMinute, field.TypeInt64, value)
+		_node.AudioPricePerMinute = &value
+	}
+	if value, ok := _c.mutation.AudioSpeechPricePer1mChars(); ok {
+		_spec.SetField(group.FieldAudioSpeechPricePer1mChars, field.TypeInt64, value)
+		_node.AudioSpeechPricePer1mChars = &value
+	}
 	if value, ok := _c.mutation.ClaudeCodeOnly(); ok {
 		_spec.SetField(group.FieldClaudeCodeOnly, field.TypeBool, value)
 		_node.ClaudeCodeOnly = value
@@ -1149,7 +1186,7 @@ func (u *GroupUpsert) UpdateSubscriptionType() *GroupUpsert {
 }
 
 // SetDailyLimitUsd sets the "daily_limit_usd" field.
-func (u *GroupUpsert) SetDailyLimitUsd(v float64) *GroupUpsert {
+func (u *GroupUpsert) SetDailyLimitUsd(v money.Amount) *GroupUpsert {
 	u.Set(group.FieldDailyLimitUsd, v)
 	return u
 }
@@ -1161,7 +1198,7 @@ func (u *GroupUpsert) UpdateDailyLimitUsd() *GroupUpsert {
 }
 
 // AddDailyLimitUsd adds v to the "daily_limit_usd" field.
-func (u *GroupUpsert) AddDailyLimitUsd(v float64) *GroupUpsert {
+func (u *GroupUpsert) AddDailyLimitUsd(v money.Amount) *GroupUpsert {
 	u.Add(group.FieldDailyLimitUsd, v)
 	return u
 }
@@ -1173,7 +1210,7 @@ func (u *GroupUpsert) ClearDailyLimitUsd() *GroupUpsert {
 }
 
 // SetWeeklyLimitUsd sets the "weekly_limit_usd" field.
-func (u *GroupUpsert) SetWeeklyLimitUsd(v float64) *GroupUpsert {
+func (u *GroupUpsert) SetWeeklyLimitUsd(v money.Amount) *GroupUpsert {
 	u.Set(group.FieldWeeklyLimitUsd, v)
 	return u
 }
@@ -1185,7 +1222,7 @@ func (u *GroupUpsert) UpdateWeeklyLimitUsd() *GroupUpsert {
 }
 
 // AddWeeklyLimitUsd adds v to the "weekly_limit_usd" field.
-func (u *GroupUpsert) AddWeeklyLimitUsd(v float64) *GroupUpsert {
+func (u *GroupUpsert) AddWeeklyLimitUsd(v money.Amount) *GroupUpsert {
 	u.Add(group.FieldWeeklyLimitUsd, v)
 	return u
 }
@@ -1197,7 +1234,7 @@ func (u *GroupUpsert) ClearWeeklyLimitUsd() *GroupUpsert {
 }
 
 // SetMonthlyLimitUsd sets the "monthly_limit_usd" field.
-func (u *GroupUpsert) SetMonthlyLimitUsd(v float64) *GroupUpsert {
+func (u *GroupUpsert) SetMonthlyLimitUsd(v money.Amount) *GroupUpsert {
 	u.Set(group.FieldMonthlyLimitUsd, v)
 	return u
 }
@@ -1209,7 +1246,7 @@ func (u *GroupUpsert) UpdateMonthlyLimitUsd() *GroupUpsert {
 }
 
 // AddMonthlyLimitUsd adds v to the "monthly_limit_usd" field.
-func (u *GroupUpsert) AddMonthlyLimitUsd(v float64) *GroupUpsert {
+func (u *GroupUpsert) AddMonthlyLimitUsd(v money.Amount) *GroupUpsert {
 	u.Add(group.FieldMonthlyLimitUsd, v)
 	return u
 }
@@ -1239,7 +1276,7 @@ func (u *GroupUpsert) AddDefaultValidityDays(v int) *GroupUpsert {
 }
 
 // SetImagePrice1k sets the "image_price_1k" field.
-func (u *GroupUpsert) SetImagePrice1k(v float64) *GroupUpsert {
+func (u *GroupUpsert) SetImagePrice1k(v money.Amount) *GroupUpsert {
 	u.Set(group.FieldImagePrice1k, v)
 	return u
 }
@@ -1251,7 +1288,7 @@ func (u *GroupUpsert) UpdateImagePrice1k() *GroupUpsert {
 }
 
 // AddImagePrice1k adds v to the "image_price_1k" field.
-func (u *GroupUpsert) AddImagePrice1k(v float64) *GroupUpsert {
+func (u *GroupUpsert) AddImagePrice1k(v money.Amount) *GroupUpsert {
 	u.Add(group.FieldImagePrice1k, v)
 	return u
 }
@@ -1263,7 +1300,7 @@ func (u *GroupUpsert) ClearImagePrice1k() *GroupUpsert {
 }
 
 // SetImagePrice2k sets the "image_price_2k" field.
-func (u *GroupUpsert) SetImagePrice2k(v float64) *GroupUpsert {
+func (u *GroupUpsert) SetImagePrice2k(v money.Amount) *GroupUpsert {
 	u.Set(group.FieldImagePrice2k, v)
 	return u
 }
@@ -1275,7 +1312,7 @@ func (u *GroupUpsert) UpdateImagePrice2k() *GroupUpsert {
 }
 
 // AddImagePrice2k adds v to the "image_price_2k" field.
-func (u *GroupUpsert) AddImagePrice2k(v float64) *GroupUpsert {
+func (u *GroupUpsert) AddImagePrice2k(v money.Amount) *GroupUpsert {
 	u.Add(group.FieldImagePrice2k, v)
 	return u
 }
@@ -1287,7 +1324,7 @@ func (u *GroupUpsert) ClearImagePrice2k() *GroupUpsert {
 }
 
 // SetImagePrice4k sets the "image_price_4k" field.
-func (u *GroupUpsert) SetImagePrice4k(v float64) *GroupUpsert {
+func (u *GroupUpsert) SetImagePrice4k(v money.Amount) *GroupUpsert {
 	u.Set(group.FieldImagePrice4k, v)
 	return u
 }
@@ -1299,7 +1336,7 @@ func (u *GroupUpsert) UpdateImagePrice4k() *GroupUpsert {
 }
 
 // AddImagePrice4k adds v to the "image_price_4k" field.
-func (u *GroupUpsert) AddImagePrice4k(v float64) *GroupUpsert {
+func (u *GroupUpsert) AddImagePrice4k(v money.Amount) *GroupUpsert {
 	u.Add(group.FieldImagePrice4k, v)
 	return u
 }
@@ -1311,7 +1348,7 @@ func (u *GroupUpsert) ClearImagePrice4k() *GroupUpsert {
 }
 
 // SetSoraImagePrice360 sets the "sora_image_price_360" field.
-func (u *GroupUpsert) SetSoraImagePrice360(v float64) *GroupUpsert {
+func (u *GroupUpsert) SetSoraImagePrice360(v money.Amount) *GroupUpsert {
 	u.Set(group.FieldSoraImagePrice360, v)
 	return u
 }
@@ -1323,7 +1360,7 @@ func (u *GroupUpsert) UpdateSoraImagePrice360() *GroupUpsert {
 }
 
 // AddSoraImagePrice360 adds v to the "sora_image_price_360" field.
-func (u *GroupUpsert) AddSoraImagePrice360(v float64) *GroupUpsert {
+func (u *GroupUpsert) AddSoraImagePrice360(v money.Amount) *GroupUpsert {
 	u.Add(group.FieldSoraImagePrice360, v)
 	return u
 }
@@ -1335,7 +1372,7 @@ func (u *GroupUpsert) ClearSoraImagePrice360() *GroupUpsert {
 }
 
 // SetSoraImagePrice540 sets the "sora_image_price_540" field.
-func (u *GroupUpsert) SetSoraImagePrice540(v float64) *GroupUpsert {
+func (u *GroupUpsert) SetSoraImagePrice540(v money.Amount) *GroupUpsert {
 	u.Set(group.FieldSoraImagePrice540, v)
 	return u
 }
@@ -1347,7 +1384,7 @@ func (u *GroupUpsert) UpdateSoraImagePrice540() *GroupUpsert {
 }
 
 // AddSoraImagePrice540 adds v to the "sora_image_price_540" field.
-func (u *GroupUpsert) AddSoraImagePrice540(v float64) *GroupUpsert {
+func (u *GroupUpsert) AddSoraImagePrice540(v money.Amount) *GroupUpsert {
 	u.Add(group.FieldSoraImagePrice540, v)
 	return u
 }
@@ -1359,7 +1396,7 @@ func (u *GroupUpsert) ClearSoraImagePrice540() *GroupUpsert {
 }
 
 // SetSoraVideoPricePerRequest sets the "sora_video_price_per_request" field.
-func (u *GroupUpsert) SetSoraVideoPricePerRequest(v float64) *GroupUpsert {
+func (u *GroupUpsert) SetSoraVideoPricePerRequest(v money.Amount) *GroupUpsert {
 	u.Set(group.FieldSoraVideoPricePerRequest, v)
 	return u
 }
@@ -1371,7 +1408,7 @@ func (u *GroupUpsert) UpdateSoraVideoPricePerRequest() *GroupUpsert {
 }
 
 // AddSoraVideoPricePerRequest adds v to the "sora_video_price_per_request" field.
-func (u *GroupUpsert) AddSoraVideoPricePerRequest(v float64) *GroupUpsert {
+func (u *GroupUpsert) AddSoraVideoPricePerRequest(v money.Amount) *GroupUpsert {
 	u.Add(group.FieldSoraVideoPricePerRequest, v)
 	return u
 }
@@ -1383,7 +1420,7 @@ func (u *GroupUpsert) ClearSoraVideoPricePerRequest() *GroupUpsert {
 }
 
 // SetSoraVideoPricePerRequestHd sets the "sora_video_price_per_request_hd" field.
-func (u *GroupUpsert) SetSoraVideoPricePerRequestHd(v float64) *GroupUpsert {
+func (u *GroupUpsert) SetSoraVideoPricePerRequestHd(v money.Amount) *GroupUpsert {
 	u.Set(group.FieldSoraVideoPricePerRequestHd, v)
 	return u
 }
@@ -1395,7 +1432,7 @@ func (u *GroupUpsert) UpdateSoraVideoPricePerRequestHd() *GroupUpsert {
 }
 
 // AddSoraVideoPricePerRequestHd adds v to the "sora_video_price_per_request_hd" field.
-func (u *GroupUpsert) AddSoraVideoPricePerRequestHd(v float64) *GroupUpsert {
+func (u *GroupUpsert) AddSoraVideoPricePerRequestHd(v money.Amount) *GroupUpsert {
 	u.Add(group.FieldSoraVideoPricePerRequestHd, v)
 	return u
 }
@@ -1425,7 +1462,7 @@ func (u *GroupUpsert) AddSoraStorageQuotaBytes(v int64) *GroupUpsert {
 }
 
 // SetVideoPricePerRequest sets the "video_price_per_request" field.
-func (u *GroupUpsert) SetVideoPricePerRequest(v float64) *GroupUpsert {
+func (u *GroupUpsert) SetVideoPricePerRequest(v money.Amount) *GroupUpsert {
 	u.Set(group.FieldVideoPricePerRequest, v)
 	return u
 }
@@ -1437,7 +1474,7 @@ func (u *GroupUpsert) UpdateVideoPricePerRequest() *GroupUpsert {
 }
 
 // AddVideoPricePerRequest adds v to the "video_price_per_request" field.
-func (u *GroupUpsert) AddVideoPricePerRequest(v float64) *GroupUpsert {
+func (u *GroupUpsert) AddVideoPricePerRequest(v money.Amount) *GroupUpsert {
 	u.Add(group.FieldVideoPricePerRequest, v)
 	return u
 }
@@ -1449,7 +1486,7 @@ func (u *GroupUpsert) ClearVideoPricePerRequest() *GroupUpsert {
 }
 
 // SetVideoPricePerRequestHd sets the "video_price_per_request_hd" field.
-func (u *GroupUpsert) SetVideoPricePerRequestHd(v float64) *GroupUpsert {
+func (u *GroupUpsert) SetVideoPricePerRequestHd(v money.Amount) *GroupUpsert {
 	u.Set(group.FieldVideoPricePerRequestHd, v)
 	return u
 }
@@ -1461,7 +1498,7 @@ func (u *GroupUpsert) UpdateVideoPricePerRequestHd() *GroupUpsert {
 }
 
 // AddVideoPricePerRequestHd adds v to the "video_price_per_request_hd" field.
-func (u *GroupUpsert) AddVideoPricePerRequestHd(v float64) *GroupUpsert {
+func (u *GroupUpsert) AddVideoPricePerRequestHd(v money.Amount) *GroupUpsert {
 	u.Add(group.FieldVideoPricePerRequestHd, v)
 	return u
 }
@@ -1472,6 +1509,54 @@ func (u *GroupUpsert) ClearVideoPricePerRequestHd() *GroupUpsert {
 	return u
 }
 
+// SetAudioPricePerMinute sets the "audio_price_per_minute" field.
+func (u *GroupUpsert) SetAudioPricePerMinute(v money.Amount) *GroupUpsert {
+	u.Set(group.FieldAudioPricePerMinute, v)
+	return u
+}
+
+// UpdateAudioPricePerMinute sets the "audio_price_per_minute" field to the value that was provided on create.
+func (u *GroupUpsert) UpdateAudioPricePerMinute() *GroupUpsert {
+	u.SetExcluded(group.FieldAudioPricePerMinute)
+	return u
+}
+
+// AddAudioPricePerMinute adds v to the "audio_price_per_minute" field.
+func (u *GroupUpsert) AddAudioPricePerMinute(v money.Amount) *GroupUpsert {
+	u.Add(group.FieldAudioPricePerMinute, v)
+	return u
+}
+
+// ClearAudioPricePerMinute clears the value of the "audio_price_per_minute" field.
+func (u *GroupUpsert) ClearAudioPricePerMinute() *GroupUpsert {
+	u.SetNull(group.FieldAudioPricePerMinute)
+	return u
+}
+
+// SetAudioSpeechPricePer1mChars sets the "audio_speech_price_per_1m_chars" field.
+func (u *GroupUpsert) SetAudioSpeechPricePer1mChars(v money.Amount) *GroupUpsert {
+	u.Set(group.FieldAudioSpeechPricePer1mChars, v)
+	return u
+}
+
+// UpdateAudioSpeechPricePer1mChars sets the "audio_speech_price_per_1m_chars" field to the value that was provided on create.
+func (u *GroupUpsert) UpdateAudioSpeechPricePer1mChars() *GroupUpsert {
+	u.SetExcluded(group.FieldAudioSpeechPricePer1mChars)
+	return u
+}
+
+// AddAudioSpeechPricePer1mChars adds v to the "audio_speech_price_per_1m_chars" field.
+func (u *GroupUpsert) AddAudioSpeechPricePer1mChars(v money.Amount) *GroupUpsert {
+	u.Add(group.FieldAudioSpeechPricePer1mChars, v)
+	return u
+}
+
+// ClearAudioSpeechPricePer1mChars clears the value of the "audio_speech_price_per_1m_chars" field.
+func (u *GroupUpsert) ClearAudioSpeechPricePer1mChars() *GroupUpsert {
+	u.SetNull(group.FieldAudioSpeechPricePer1mChars)
+	return u
+}
+
 // SetClaudeCodeOnly sets the "claude_code_only" field.
 func (u *GroupUpsert) SetClaudeCodeOnly(v bool) *GroupUpsert {
 	u.Set(group.FieldClaudeCodeOnly, v)
@@ -1797,14 +1882,14 @@ func (u *GroupUpsertOne) UpdateSubscriptionType() *GroupUpsertOne {
 }
 
 // SetDailyLimitUsd sets the "daily_limit_usd" field.
-func (u *GroupUpsertOne) SetDailyLimitUsd(v float64) *GroupUpsertOne {
+func (u *GroupUpsertOne) SetDailyLimitUsd(v money.Amount) *GroupUpsertOne {
 	return u.Update(func(s *GroupUpsert) {
 		s.SetDailyLimitUsd(v)
 	})
 }
 
 // AddDailyLimitUsd adds v to the "daily_limit_usd" field.
-func (u *GroupUpsertOne) AddDailyLimitUsd(v float64) *GroupUpsertOne {
+func (u *GroupUpsertOne) AddDailyLimitUsd(v money.Amount) *GroupUpsertOne {
 	return u.Update(func(s *GroupUpsert) {
 		s.AddDailyLimitUsd(v)
 	})
@@ -1825,14 +1910,14 @@ func (u *GroupUpsertOne) ClearDailyLimitUsd() *GroupUpsertOne {
 }
 
 // SetWeeklyLimitUsd sets the "weekly_limit_usd" field.
-func (u *GroupUpsertOne) SetWeeklyLimitUsd(v float64) *GroupUpsertOne {
+func (u *GroupUpsertOne) SetWeeklyLimitUsd(v money.Amount) *GroupUpsertOne {
 	return u.Update(func(s *GroupUpsert) {
 		s.SetWeeklyLimitUsd(v)
 	})
 }
 
 // AddWeeklyLimitUsd adds v to the "weekly_limit_usd" field.
-func (u *GroupUpsertOne) AddWeeklyLimitUsd(v float64) *GroupUpsertOne {
+func (u *GroupUpsertOne) AddWeeklyLimitUsd(v money.Amount) *GroupUpsertOne {
 	return u.Update(func(s *GroupUpsert) {
 		s.AddWeeklyLimitUsd(v)
 	})
@@ -1853,14 +1938,14 @@ func (u *GroupUpsertOne) ClearWeeklyLimitUsd() *GroupUpsertOne {
 }
 
 // SetMonthlyLimitUsd sets the "monthly_limit_usd" field.
-func (u *GroupUpsertOne) SetMonthlyLimitUsd(v float64) *GroupUpsertOne {
+func (u *GroupUpsertOne) SetMonthlyLimitUsd(v money.Amount) *GroupUpsertOne {
 	return u.Update(func(s *GroupUpsert) {
 		s.SetMonthlyLimitUsd(v)
 	})
 }
 
 // AddMonthlyLimitUsd adds v to the "monthly_limit_usd" field.
-func (u *GroupUpsertOne) AddMonthlyLimitUsd(v float64) *GroupUpsertOne {
+func (u *GroupUpsertOne) AddMonthlyLimitUsd(v money.Amount) *GroupUpsertOne {
 	return u.Update(func(s *GroupUpsert) {
 		s.AddMonthlyLimitUsd(v)
 	})
@@ -1902,14 +1987,14 @@ func (u *GroupUpsertOne) UpdateDefaultValidityDays() *GroupUpsertOne {
 }
 
 // SetImagePrice1k sets the "image_price_1k" field.
-func (u *GroupUpsertOne) SetImagePrice1k(v float64) *GroupUpsertOne {
+func (u *GroupUpsertOne) SetImagePrice1k(v money.Amount) *GroupUpsertOne {
 	return u.Update(func(s *GroupUpsert) {
 		s.SetImagePrice1k(v)
 	})
 }
 
 // AddImagePrice1k adds v to the "image_price_1k" field.
-func (u *GroupUpsertOne) AddImagePrice1k(v float64) *GroupUpsertOne {
+func (u *GroupUpsertOne) AddImagePrice1k(v money.Amount) *GroupUpsertOne {
 	return u.Update(func(s *GroupUpsert) {
 		s.AddImagePrice1k(v)
 	})
@@ -1930,14 +2015,14 @@ func (u *GroupUpsertOne) ClearImagePrice1k() *GroupUpsertOne {
 }
 
 // SetImagePrice2k sets the "image_price_2k" field.
-func (u *GroupUpsertOne) SetImagePrice2k(v float64) *GroupUpsertOne {
+func (u *GroupUpsertOne) SetImagePrice2k(v money.Amount) *GroupUpsertOne {
 	return u.Update(func(s *GroupUpsert) {
 		s.SetImagePrice2k(v)
 	})
 }
 
 // AddImagePrice2k adds v to the "image_price_2k" field.
-func (u *GroupUpsertOne) AddImagePrice2k(v float64) *GroupUpsertOne {
+func (u *GroupUpsertOne) AddImagePrice2k(v money.Amount) *GroupUpsertOne {
 	return u.Update(func(s *GroupUpsert) {
 		s.AddImagePrice2k(v)
 	})
@@ -1958,14 +2043,14 @@ func (u *GroupUpsertOne) ClearImagePrice2k() *GroupUpsertOne {
 }
 
 // SetImagePrice4k sets the "image_price_4k" field.
-func (u *GroupUpsertOne) SetImagePrice4k(v float64) *GroupUpsertOne {
+func (u *GroupUpsertOne) SetImagePrice4k(v money.Amount) *GroupUpsertOne {
 	return u.Update(func(s *GroupUpsert) {
 		s.SetImagePrice4k(v)
 	})
 }
 
 // AddImagePrice4k adds v to the "image_price_4k" field.
-func (u *GroupUpsertOne) AddImagePrice4k(v float64) *GroupUpsertOne {
+func (u *GroupUpsertOne) AddImagePrice4k(v money.Amount) *GroupUpsertOne {
 	return u.Update(func(s *GroupUpsert) {
 		s.AddImagePrice4k(v)
 	})
@@ -1986,14 +2071,14 @@ func (u *GroupUpsertOne) ClearImagePrice4k() *GroupUpsertOne {
 }
 
 // SetSoraImagePrice360 sets the "sora_image_price_360" field.
-func (u *GroupUpsertOne) SetSoraImagePrice360(v float64) *GroupUpsertOne {
+func (u *GroupUpsertOne) SetSoraImagePrice360(v money.Amount) *GroupUpsertOne {
 	return u.Update(func(s *GroupUpsert) {
 		s.SetSoraImagePrice360(v)
 	})
 }
 
 // AddSoraImagePrice360 adds v to the "sora_image_price_360" field.
-func (u *GroupUpsertOne) AddSoraImagePrice360(v float64) *GroupUpsertOne {
+func (u *GroupUpsertOne) AddSoraImagePrice360(v money.Amount) *GroupUpsertOne {
 	return u.Update(func(s *GroupUpsert) {
 		s.AddSoraImagePrice360(v)
 	})
@@ -2014,14 +2099,14 @@ func (u *GroupUpsertOne) ClearSoraImagePrice360() *GroupUpsertOne {
 }
 
 // SetSoraImagePrice540 sets the "sora_image_price_540" field.
-func (u *GroupUpsertOne) SetSoraImagePrice540(v float64) *GroupUpsertOne {
+func (u *GroupUpsertOne) SetSoraImagePrice540(v money.Amount) *GroupUpsertOne {
 	return u.Update(func(s *GroupUpsert) {
 		s.SetSoraImagePrice540(v)
 	})
 }
 
 // AddSoraImagePrice540 adds v to the "sora_image_price_540" field.
-func (u *GroupUpsertOne) AddSoraImagePrice540(v float64) *GroupUpsertOne {
+func (u *GroupUpsertOne) AddSoraImagePrice540(v money.Amount) *GroupUpsertOne {
 	return u.Update(func(s *GroupUpsert) {
 		s.AddSoraImagePrice540(v)
 	})
@@ -2042,14 +2127,14 @@ func (u *GroupUpsertOne) ClearSoraImagePrice540() *GroupUpsertOne {
 }
 
 // SetSoraVideoPricePerRequest sets the "sora_video_price_per_request" field.
-func (u *GroupUpsertOne) SetSoraVideoPricePerRequest(v float64) *GroupUpsertOne {
+func (u *GroupUpsertOne) SetSoraVideoPricePerRequest(v money.Amount) *GroupUpsertOne {
 	return u.Update(func(s *GroupUpsert) {
 		s.SetSoraVideoPricePerRequest(v)
 	})
 }
 
 // AddSoraVideoPricePerRequest adds v to the "sora_video_price_per_request" field.
-func (u *GroupUpsertOne) AddSoraVideoPricePerRequest(v float64) *GroupUpsertOne {
+func (u *GroupUpsertOne) AddSoraVideoPricePerRequest(v money.Amount) *GroupUpsertOne {
 	return u.Update(func(s *GroupUpsert) {
 		s.AddSoraVideoPricePerRequest(v)
 	})
@@ -2070,14 +2155,14 @@ func (u *GroupUpsertOne) ClearSoraVideoPricePerRequest() *GroupUpsertOne {
 }
 
 // SetSoraVideoPricePerRequestHd sets the "sora_video_price_per_request_hd" field.
-func (u *GroupUpsertOne) SetSoraVideoPricePerRequestHd(v float64) *GroupUpsertOne {
+func (u *GroupUpsertOne) SetSoraVideoPricePerRequestHd(v money.Amount) *GroupUpsertOne {
 	return u.Update(func(s *GroupUpsert) {
 		s.SetSoraVideoPricePerRequestHd(v)
 	})
 }
 
 // AddSoraVideoPricePerRequestHd adds v to the "sora_video_price_per_request_hd" field.
-func (u *GroupUpsertOne) AddSoraVideoPricePerRequestHd(v float64) *GroupUpsertOne {
+func (u *GroupUpsertOne) AddSoraVideoPricePerRequestHd(v money.Amount) *GroupUpsertOne {
 	return u.Update(func(s *GroupUpsert) {
 		s.AddSoraVideoPricePerRequestHd(v)
 	})
@@ -2119,14 +2204,14 @@ func (u *GroupUpsertOne) UpdateSoraStorageQuotaBytes() *GroupUpsertOne {
 }
 
 // SetVideoPricePerRequest sets the "video_price_per_request" field.
-func (u *GroupUpsertOne) SetVideoPricePerRequest(v float64) *GroupUpsertOne {
+func (u *GroupUpsertOne) SetVideoPricePerRequest(v money.Amount) *GroupUpsertOne {
 	return u.Update(func(s *GroupUpsert) {
 		s.SetVideoPricePerRequest(v)
 	})
 }
 
 // AddVideoPricePerRequest adds v to the "video_price_per_request" field.
-func (u *GroupUpsertOne) AddVideoPricePerRequest(v float64) *GroupUpsertOne {
+func (u *GroupUpsertOne) AddVideoPricePerRequest(v money.Amount) *GroupUpsertOne {
 	return u.Update(func(s *GroupUpsert) {
 		s.AddVideoPricePerRequest(v)
 	})
@@ -2147,14 +2232,14 @@ func (u *GroupUpsertOne) ClearVideoPricePerRequest() *GroupUpsertOne {
 }
 
 // SetVideoPricePerRequestHd sets the "video_price_per_request_hd" field.
-func (u *GroupUpsertOne) SetVideoPricePerRequestHd(v float64) *GroupUpsertOne {
+func (u *GroupUpsertOne) SetVideoPricePerRequestHd(v money.Amount) *GroupUpsertOne {
 	return u.Update(func(s *GroupUpsert) {
 		s.SetVideoPricePerRequestHd(v)
 	})
 }
 
 // AddVideoPricePerRequestHd adds v to the "video_price_per_request_hd" field.
-func (u *GroupUpsertOne) AddVideoPricePerRequestHd(v float64) *GroupUpsertOne {
+func (u *GroupUpsertOne) AddVideoPricePerRequestHd(v money.Amount) *GroupUpsertOne {
 	return u.Update(func(s *GroupUpsert) {
 		s.AddVideoPricePerRequestHd(v)
 	})
@@ -2174,6 +2259,62 @@ func (u *GroupUpsertOne) ClearVideoPricePerRequestHd() *GroupUpsertOne {
 	})
 }
 
+// SetAudioPricePerMinute sets the "audio_price_per_minute" field.
+func (u *GroupUpsertOne) SetAudioPricePerMinute(v money.Amount) *GroupUpsertOne {
+	return u.Update(func(s *GroupUpsert) {
+		s.SetAudioPricePerMinute(v)
+	})
+}
+
+// AddAudioPricePerMinute adds v to the "audio_price_per_minute" field.
+func (u *GroupUpsertOne) AddAudioPricePerMinute(v money.Amount) *GroupUpsertOne {
+	return u.Update(func(s *GroupUpsert) {
+		s.AddAudioPricePerMinute(v)
+	})
+}
+
+// UpdateAudioPricePerMinute sets the "audio_price_per_minute" field to the value that was provided on create.
+func (u *GroupUpsertOne) UpdateAudioPricePerMinute() *GroupUpsertOne {
+	return u.Update(func(s *GroupUpsert) {
+		s.UpdateAudioPricePerMinute()
+	})
+}
+
+// ClearAudioPricePerMinute clears the value of the "audio_price_per_minute" field.
+func (u *GroupUpsertOne) ClearAudioPricePerMinute() *GroupUpsertOne {
+	return u.Update(func(s *GroupUpsert) {
+		s.ClearAudioPricePerMinute()
+	})
+}
+
+// SetAudioSpeechPricePer1mChars sets the "audio_speech_price_per_1m_chars" field.
+func (u *GroupUpsertOne) SetAudioSpeechPricePer1mChars(v money.Amount) *GroupUpsertOne {
+	return u.Update(func(s *GroupUpsert) {
+		s.SetAudioSpeechPricePer1mChars(v)
+	})
+}
+
+// AddAudioSpeechPricePer1mChars adds v to the "audio_speech_price_per_1m_chars" field.
+func (u *GroupUpsertOne) AddAudioSpeechPricePer1mChars(v money.Amount) *GroupUpsertOne {
+	return u.Update(func(s *GroupUpsert) {
+		s.AddAudioSpeechPricePer1mChars(v)
+	})
+}
+
+// UpdateAudioSpeechPricePer1mChars sets the "audio_speech_price_per_1m_chars" field to the value that was provided on create.
+func (u *GroupUpsertOne) UpdateAudioSpeechPricePer1mChars() *GroupUpsertOne {
+	return u.Update(func(s *GroupUpsert) {
+		s.UpdateAudioSpeechPricePer1mChars()
+	})
+}
+
+// ClearAudioSpeechPricePer1mChars clears the value of the "audio_speech_price_per_1m_chars" field.
+func (u *GroupUpsertOne) ClearAudioSpeechPricePer1mChars() *GroupUpsertOne {
+	return u.Update(func(s *GroupUpsert) {
+		s.ClearAudioSpeechPricePer1mChars()
+	})
+}
+
 // SetClaudeCodeOnly sets the "claude_code_only" field.
 func (u *GroupUpsertOne) SetClaudeCodeOnly(v bool) *GroupUpsertOne {
 	return u.Update(func(s *GroupUpsert) {
@@ -2687,14 +2828,14 @@ func (u *GroupUpsertBulk) UpdateSubscriptionType() *GroupUpsertBulk {
 }
 
 // SetDailyLimitUsd sets the "daily_limit_usd" field.
-func (u *GroupUpsertBulk) SetDailyLimitUsd(v float64) *GroupUpsertBulk {
+func (u *GroupUpsertBulk) SetDailyLimitUsd(v money.Amount) *GroupUpsertBulk {
 	return u.Update(func(s *GroupUpsert) {
 		s.SetDailyLimitUsd(v)
 	})
 }
 
 // AddDailyLimitUsd adds v to the "daily_limit_usd" field.
-func (u *GroupUpsertBulk) AddDailyLimitUsd(v float64) *GroupUpsertBulk {
+func (u *GroupUpsertBulk) AddDailyLimitUsd(v money.Amount) *GroupUpsertBulk {
 	return u.Update(func(s *GroupUpsert) {
 		s.AddDailyLimitUsd(v)
 	})
@@ -2715,14 +2856,14 @@ func (u *GroupUpsertBulk) ClearDailyLimitUsd() *GroupUpsertBulk {
 }
 
 // SetWeeklyLimitUsd sets the "weekly_limit_usd" field.
-func (u *GroupUpsertBulk) SetWeeklyLimitUsd(v float64) *GroupUpsertBulk {
+func (u *GroupUpsertBulk) SetWeeklyLimitUsd(v money.Amount) *GroupUpsertBulk {
 	return u.Update(func(s *GroupUpsert) {
 		s.SetWeeklyLimitUsd(v)
 	})
 }
 
 // AddWeeklyLimitUsd adds v to the "weekly_limit_usd" field.
-func (u *GroupUpsertBulk) AddWeeklyLimitUsd(v float64) *GroupUpsertBulk {
+func (u *GroupUpsertBulk) AddWeeklyLimitUsd(v money.Amount) *GroupUpsertBulk {
 	return u.Update(func(s *GroupUpsert) {
 		s.AddWeeklyLimitUsd(v)
 	})
@@ -2743,14 +2884,14 @@ func (u *GroupUpsertBulk) ClearWeeklyLimitUsd() *GroupUpsertBulk {
 }
 
 // SetMonthlyLimitUsd sets the "monthly_limit_usd" field.
-func (u *GroupUpsertBulk) SetMonthlyLimitUsd(v float64) *GroupUpsertBulk {
+func (u *GroupUpsertBulk) SetMonthlyLimitUsd(v money.Amount) *GroupUpsertBulk {
 	return u.Update(func(s *GroupUpsert) {
 		s.SetMonthlyLimitUsd(v)
 	})
 }
 
 // AddMonthlyLimitUsd adds v to the "monthly_limit_usd" field.
-func (u *GroupUpsertBulk) AddMonthlyLimitUsd(v float64) *GroupUpsertBulk {
+func (u *GroupUpsertBulk) AddMonthlyLimitUsd(v money.Amount) *GroupUpsertBulk {
 	return u.Update(func(s *GroupUpsert) {
 		s.AddMonthlyLimitUsd(v)
 	})
@@ -2792,14 +2933,14 @@ func (u *GroupUpsertBulk) UpdateDefaultValidityDays() *GroupUpsertBulk {
 }
 
 // SetImagePrice1k sets the "image_price_1k" field.
-func (u *GroupUpsertBulk) SetImagePrice1k(v float64) *GroupUpsertBulk {
+func (u *GroupUpsertBulk) SetImagePrice1k(v money.Amount) *GroupUpsertBulk {
 	return u.Update(func(s *GroupUpsert) {
 		s.SetImagePrice1k(v)
 	})
 }
 
 // AddImagePrice1k adds v to the "image_price_1k" field.
-func (u *GroupUpsertBulk) AddImagePrice1k(v float64) *GroupUpsertBulk {
+func (u *GroupUpsertBulk) AddImagePrice1k(v money.Amount) *GroupUpsertBulk {
 	return u.Update(func(s *GroupUpsert) {
 		s.AddImagePrice1k(v)
 	})
@@ -2820,14 +2961,14 @@ func (u *GroupUpsertBulk) ClearImagePrice1k() *GroupUpsertBulk {
 }
 
 // SetImagePrice2k sets the "image_price_2k" field.
-func (u *GroupUpsertBulk) SetImagePrice2k(v float64) *GroupUpsertBulk {
+func (u *GroupUpsertBulk) SetImagePrice2k(v money.Amount) *GroupUpsertBulk {
 	return u.Update(func(s *GroupUpsert) {
 		s.SetImagePrice2k(v)
 	})
 }
 
 // AddImagePrice2k adds v to the "image_price_2k" field.
-func (u *GroupUpsertBulk) AddImagePrice2k(v float64) *GroupUpsertBulk {
+func (u *GroupUpsertBulk) AddImagePrice2k(v money.Amount) *GroupUpsertBulk {
 	return u.Update(func(s *GroupUpsert) {
 		s.AddImagePrice2k(v)
 	})
@@ -2848,14 +2989,14 @@ func (u *GroupUpsertBulk) ClearImagePrice2k() *GroupUpsertBulk {
 }
 
 // SetImagePrice4k sets the "image_price_4k" field.
-func (u *GroupUpsertBulk) SetImagePrice4k(v float64) *GroupUpsertBulk {
+func (u *GroupUpsertBulk) SetImagePrice4k(v money.Amount) *GroupUpsertBulk {
 	return u.Update(func(s *GroupUpsert) {
 		s.SetImagePrice4k(v)
 	})
 }
 
 // AddImagePrice4k adds v to the "image_price_4k" field.
-func (u *GroupUpsertBulk) AddImagePrice4k(v float64) *GroupUpsertBulk {
+func (u *GroupUpsertBulk) AddImagePrice4k(v money.Amount) *GroupUpsertBulk {
 	return u.Update(func(s *GroupUpsert) {
 		s.AddImagePrice4k(v)
 	})
@@ -2876,14 +3017,14 @@ func (u *GroupUpsertBulk) ClearImagePrice4k() *GroupUpsertBulk {
 }
 
 // SetSoraImagePrice360 sets the "sora_image_price_360" field.
-func (u *GroupUpsertBulk) SetSoraImagePrice360(v float64) *GroupUpsertBulk {
+func (u *GroupUpsertBulk) SetSoraImagePrice360(v money.Amount) *GroupUpsertBulk {
 	return u.Update(func(s *GroupUpsert) {
 		s.SetSoraImagePrice360(v)
 	})
 }
 
 // AddSoraImagePrice360 adds v to the "sora_image_price_360" field.
-func (u *GroupUpsertBulk) AddSoraImagePrice360(v float64) *GroupUpsertBulk {
+func (u *GroupUpsertBulk) AddSoraImagePrice360(v money.Amount) *GroupUpsertBulk {
 	return u.Update(func(s *GroupUpsert) {
 		s.AddSoraImagePrice360(v)
 	})
@@ -2904,14 +3045,14 @@ func (u *GroupUpsertBulk) ClearSoraImagePrice360() *GroupUpsertBulk {
 }
 
 // SetSoraImagePrice540 sets the "sora_image_price_540" field.
-func (u *GroupUpsertBulk) SetSoraImagePrice540(v float64) *GroupUpsertBulk {
+func (u *GroupUpsertBulk) SetSoraImagePrice540(v money.Amount) *GroupUpsertBulk {
 	return u.Update(func(s *GroupUpsert) {
 		s.SetSoraImagePrice540(v)
 	})
 }
 
 // AddSoraImagePrice540 adds v to the "sora_image_price_540" field.
-func (u *GroupUpsertBulk) AddSoraImagePrice540(v float64) *GroupUpsertBulk {
+func (u *GroupUpsertBulk) AddSoraImagePrice540(v money.Amount) *GroupUpsertBulk {
 	return u.Update(func(s *GroupUpsert) {
 		s.AddSoraImagePrice540(v)
 	})
@@ -2932,14 +3073,14 @@ func (u *GroupUpsertBulk) ClearSoraImagePrice540() *GroupUpsertBulk {
 }
 
 // SetSoraVideoPricePerRequest sets the "sora_video_price_per_request" field.
-func (u *GroupUpsertBulk) SetSoraVideoPricePerRequest(v float64) *GroupUpsertBulk {
+func (u *GroupUpsertBulk) SetSoraVideoPricePerRequest(v money.Amount) *GroupUpsertBulk {
 	return u.Update(func(s *GroupUpsert) {
 		s.SetSoraVideoPricePerRequest(v)
 	})
 }
 
 // AddSoraVideoPricePerRequest adds v to the "sora_video_price_per_request" field.
-func (u *GroupUpsertBulk) AddSoraVideoPricePerRequest(v float64) *GroupUpsertBulk {
+func (u *GroupUpsertBulk) AddSoraVideoPricePerRequest(v money.Amount) *GroupUpsertBulk {
 	return u.Update(func(s *GroupUpsert) {
 		s.AddSoraVideoPricePerRequest(v)
 	})
@@ -2960,14 +3101,14 @@ func (u *GroupUpsertBulk) ClearSoraVideoPricePerRequest() *GroupUpsertBulk {
 }
 
 // SetSoraVideoPricePerRequestHd sets the "sora_video_price_per_request_hd" field.
-func (u *GroupUpsertBulk) SetSoraVideoPricePerRequestHd(v float64) *GroupUpsertBulk {
+func (u *GroupUpsertBulk) SetSoraVideoPricePerRequestHd(v money.Amount) *GroupUpsertBulk {
 	return u.Update(func(s *GroupUpsert) {
 		s.SetSoraVideoPricePerRequestHd(v)
 	})
 }
 
 // AddSoraVideoPricePerRequestHd adds v to the "sora_video_price_per_request_hd" field.
-func (u *GroupUpsertBulk) AddSoraVideoPricePerRequestHd(v float64) *GroupUpsertBulk {
+func (u *GroupUpsertBulk) AddSoraVideoPricePerRequestHd(v money.Amount) *GroupUpsertBulk {
 	return u.Update(func(s *GroupUpsert) {
 		s.AddSoraVideoPricePerRequestHd(v)
 	})
@@ -3009,14 +3150,14 @@ func (u *GroupUpsertBulk) UpdateSoraStorageQuotaBytes() *GroupUpsertBulk {
 }
 
 // SetVideoPricePerRequest sets the "video_price_per_request" field.
-func (u *GroupUpsertBulk) SetVideoPricePerRequest(v float64) *GroupUpsertBulk {
+func (u *GroupUpsertBulk) SetVideoPricePerRequest(v money.Amount) *GroupUpsertBulk {
 	return u.Update(func(s *GroupUpsert) {
 		s.SetVideoPricePerRequest(v)
 	})
 }
 
 // AddVideoPricePerRequest adds v to the "video_price_per_request" field.
-func (u *GroupUpsertBulk) AddVideoPricePerRequest(v float64) *GroupUpsertBulk {
+func (u *GroupUpsertBulk) AddVideoPricePerRequest(v money.Amount) *GroupUpsertBulk {
 	return u.Update(func(s *GroupUpsert) {
 		s.AddVideoPricePerRequest(v)
 	})
@@ -3037,14 +3178,14 @@ func (u *GroupUpsertBulk) ClearVideoPricePerRequest() *GroupUpsertBulk {
 }
 
 // SetVideoPricePerRequestHd sets the "video_price_per_request_hd" field.
-func (u *GroupUpsertBulk) SetVideoPricePerRequestHd(v float64) *GroupUpsertBulk {
+func (u *GroupUpsertBulk) SetVideoPricePerRequestHd(v money.Amount) *GroupUpsertBulk {
 	return u.Update(func(s *GroupUpsert) {
 		s.SetVideoPricePerRequestHd(v)
 	})
 }
 
 // AddVideoPricePerRequestHd adds v to the "video_price_per_request_hd" field.
-func (u *GroupUpsertBulk) AddVideoPricePerRequestHd(v float64) *GroupUpsertBulk {
+func (u *GroupUpsertBulk) AddVideoPricePerRequestHd(v money.Amount) *GroupUpsertBulk {
 	return u.Update(func(s *GroupUpsert) {
 		s.AddVideoPricePerRequestHd(v)
 	})
@@ -3064,6 +3205,62 @@ func (u *GroupUpsertBulk) ClearVideoPricePerRequestHd() *GroupUpsertBulk {
 	})
 }
 
+// SetAudioPricePerMinute sets the "audio_price_per_minute" field.
+func (u *GroupUpsertBulk) SetAudioPricePerMinute(v money.Amount) *GroupUpsertBulk {
+	return u.Update(func(s *GroupUpsert) {
+		s.SetAudioPricePerMinute(v)
+	})
+}
+
+// AddAudioPricePerMinute adds v to the "audio_price_per_minute" field.
+func (u *GroupUpsertBulk) AddAudioPricePerMinute(v money.Amount) *GroupUpsertBulk {
+	return u.Update(func(s *GroupUpsert) {
+		s.AddAudioPricePerMinute(v)
+	})
+}
+
+// UpdateAudioPricePerMinute sets the "audio_price_per_minute" field to the value that was provided on create.
+func (u *GroupUpsertBulk) UpdateAudioPricePerMinute() *GroupUpsertBulk {
+	return u.Update(func(s *GroupUpsert) {
+		s.UpdateAudioPricePerMinute()
+	})
+}
+
+// ClearAudioPricePerMinute clears the value of the "audio_price_per_minute" field.
+func (u *GroupUpsertBulk) ClearAudioPricePerMinute() *GroupUpsertBulk {
+	return u.Update(func(s *GroupUpsert) {
+		s.ClearAudioPricePerMinute()
+	})
+}
+
+// SetAudioSpeechPricePer1mChars sets the "audio_speech_price_per_1m_chars" field.
+func (u *GroupUpsertBulk) SetAudioSpeechPricePer1mChars(v money.Amount) *GroupUpsertBulk {
+	return u.Update(func(s *GroupUpsert) {
+		s.SetAudioSpeechPricePer1mChars(v)
+	})
+}
+
+// AddAudioSpeechPricePer1mChars adds v to the "audio_speech_price_per_1m_chars" field.
+func (u *GroupUpsertBulk) AddAudioSpeechPricePer1mChars(v money.Amount) *GroupUpsertBulk {
+	return u.Update(func(s *GroupUpsert) {
+		s.AddAudioSpeechPricePer1mChars(v)
+	})
+}
+
+// UpdateAudioSpeechPricePer1mChars sets the "audio_speech_price_per_1m_chars" field to the value that was provided on create.
+func (u *GroupUpsertBulk) UpdateAudioSpeechPricePer1mChars() *GroupUpsertBulk {
+	return u.Update(func(s *GroupUpsert) {
+		s.UpdateAudioSpeechPricePer1mChars()
+	})
+}
+
+// ClearAudioSpeechPricePer1mChars clears the value of the "audio_speech_price_per_1m_chars" field.
+func (u *GroupUpsertBulk) ClearAudioSpeechPricePer1mChars() *GroupUpsertBulk {
+	return u.Update(func(s *GroupUpsert) {
+		s.ClearAudioSpeechPricePer1mChars()
+	})
+}
+
 // SetClaudeCodeOnly sets the "claude_code_only" field.
 func (u *GroupUpsertBulk) SetClaudeCodeOnly(v bool) *GroupUpsertBulk {
 	return u.Update(func(s *GroupUpsert) {
@@ -20,6 +20,7 @@ import (
 	"github.com/Wei-Shaw/sub2api/ent/usagelog"
 	"github.com/Wei-Shaw/sub2api/ent/user"
 	"github.com/Wei-Shaw/sub2api/ent/usersubscription"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
 )
 
 // GroupUpdate is the builder for updating Group entities.
@@ -173,14 +174,14 @@ func (_u *GroupUpdate) SetNillableSubscriptionType(v *string) *GroupUpdate {
 }
 
 // SetDailyLimitUsd sets the "daily_limit_usd" field.
-func (_u *GroupUpdate) SetDailyLimitUsd(v float64) *GroupUpdate {
+func (_u *GroupUpdate) SetDailyLimitUsd(v money.Amount) *GroupUpdate {
 	_u.mutation.ResetDailyLimitUsd()
 	_u.mutation.SetDailyLimitUsd(v)
 	return _u
 }
 
 // SetNillableDailyLimitUsd sets the "daily_limit_usd" field if the given value is not nil.
-func (_u *GroupUpdate) SetNillableDailyLimitUsd(v *float64) *GroupUpdate {
+func (_u *GroupUpdate) SetNillableDailyLimitUsd(v *money.Amount) *GroupUpdate {
 	if v != nil {
 		_u.SetDailyLimitUsd(*v)
 	}
@@ -188,7 +189,7 @@ func (_u *GroupUpdate) SetNillableDailyLimitUsd(v *float64) *GroupUpdate {
 }
 
 // AddDailyLimitUsd adds value to the "daily_limit_usd" field.
-func (_u *GroupUpdate) AddDailyLimitUsd(v float64) *GroupUpdate {
+func (_u *GroupUpdate) AddDailyLimitUsd(v money.Amount) *GroupUpdate {
 	_u.mutation.AddDailyLimitUsd(v)
 	return _u
 }
@@ -200,14 +201,14 @@ func (_u *GroupUpdate) ClearDailyLimitUsd() *GroupUpdate {
 }
 
 // SetWeeklyLimitUsd sets the "weekly_limit_usd" field.
-func (_u *GroupUpdate) SetWeeklyLimitUsd(v float64) *GroupUpdate {
+func (_u *GroupUpdate) SetWeeklyLimitUsd(v money.Amount) *GroupUpdate {
 	_u.mutation.ResetWeeklyLimitUsd()
 	_u.mutation.SetWeeklyLimitUsd(v)
 	return _u
 }
 
 // SetNillableWeeklyLimitUsd sets the "weekly_limit_usd" field if the given value is not nil.
-func (_u *GroupUpdate) SetNillableWeeklyLimitUsd(v *float64) *GroupUpdate {
+func (_u *GroupUpdate) SetNillableWeeklyLimitUsd(v *money.Amount) *GroupUpdate {
 	if v != nil {
 		_u.SetWeeklyLimitUsd(*v)
 	}
@@ -215,7 +216,7 @@ func (_u *GroupUpdate) SetNillableWeeklyLimitUsd(v *float64) *GroupUpdate {
 }
 
 // AddWeeklyLimitUsd adds value to the "weekly_limit_usd" field.
-func (_u *GroupUpdate) AddWeeklyLimitUsd(v float64) *GroupUpdate {
+func (_u *GroupUpdate) AddWeeklyLimitUsd(v money.Amount) *GroupUpdate {
 	_u.mutation.AddWeeklyLimitUsd(v)
 	return _u
 }
@@ -227,14 +228,14 @@ func (_u *GroupUpdate) ClearWeeklyLimitUsd() *GroupUpdate {
 }
 
 // SetMonthlyLimitUsd sets the "monthly_limit_usd" field.
-func (_u *GroupUpdate) SetMonthlyLimitUsd(v float64) *GroupUpdate {
+func (_u *GroupUpdate) SetMonthlyLimitUsd(v money.Amount) *GroupUpdate {
 	_u.mutation.ResetMonthlyLimitUsd()
 	_u.mutation.SetMonthlyLimitUsd(v)
 	return _u
 }
 
 // SetNillableMonthlyLimitUsd sets the "monthly_limit_usd" field if the given value is not nil.
-func (_u *GroupUpdate) SetNillableMonthlyLimitUsd(v *float64) *GroupUpdate {
+func (_u *GroupUpdate) SetNillableMonthlyLimitUsd(v *money.Amount) *GroupUpdate {
 	if v != nil {
 		_u.SetMonthlyLimitUsd(*v)
 	}
@@ -242,7 +243,7 @@ func (_u *GroupUpdate) SetNillableMonthlyLimitUsd(v *float64) *GroupUpdate {
 }
 
 // AddMonthlyLimitUsd adds value to the "monthly_limit_usd" field.
-func (_u *GroupUpdate) AddMonthlyLimitUsd(v float64) *GroupUpdate {
+func (_u *GroupUpdate) AddMonthlyLimitUsd(v money.Amount) *GroupUpdate {
 	_u.mutation.AddMonthlyLimitUsd(v)
 	return _u
 }
@@ -275,14 +276,14 @@ func (_u *GroupUpdate) AddDefaultValidityDays(v int) *GroupUpdate {
 }
 
 // SetImagePrice1k sets the "image_price_1k" field.
-func (_u *GroupUpdate) SetImagePrice1k(v float64) *GroupUpdate {
+func (_u *GroupUpdate) SetImagePrice1k(v money.Amount) *GroupUpdate {
 	_u.mutation.ResetImagePrice1k()
 	_u.mutation.SetImagePrice1k(v)
 	return _u
 }
 
 // SetNillableImagePrice1k sets the "image_price_1k" field if the given value is not nil.
-func (_u *GroupUpdate) SetNillableImagePrice1k(v *float64) *GroupUpdate {
+func (_u *GroupUpdate) SetNillableImagePrice1k(v *money.Amount) *GroupUpdate {
 	if v != nil {
 		_u.SetImagePrice1k(*v)
 	}
@@ -290,7 +291,7 @@ func (_u *GroupUpdate) SetNillableImagePrice1k(v *float64) *GroupUpdate {
 }
 
 // AddImagePrice1k adds value to the "image_price_1k" field.
-func (_u *GroupUpdate) AddImagePrice1k(v float64) *GroupUpdate {
+func (_u *GroupUpdate) AddImagePrice1k(v money.Amount) *GroupUpdate {
 	_u.mutation.AddImagePrice1k(v)
 	return _u
 }
@@ -302,14 +303,14 @@ func (_u *GroupUpdate) ClearImagePrice1k() *GroupUpdate {
 }
 
 // SetImagePrice2k sets the "image_price_2k" field.
-func (_u *GroupUpdate) SetImagePrice2k(v float64) *GroupUpdate {
+func (_u *GroupUpdate) SetImagePrice2k(v money.Amount) *GroupUpdate {
 	_u.mutation.ResetImagePrice2k()
 	_u.mutation.SetImagePrice2k(v)
 	return _u
 }
 
 // SetNillableImagePrice2k sets the "image_price_2k" field if the given value is not nil.
-func (_u *GroupUpdate) SetNillableImagePrice2k(v *float64) *GroupUpdate {
+func (_u *GroupUpdate) SetNillableImagePrice2k(v *money.Amount) *GroupUpdate {
 	if v != nil {
 		_u.SetImagePrice2k(*v)
 	}
@@ -317,7 +318,7 @@ func (_u *GroupUpdate) SetNillableImagePrice2k(v *float64) *GroupUpdate {
 }
 
 // AddImagePrice2k adds value to the "image_price_2k" field.
-func (_u *GroupUpdate) AddImagePrice2k(v float64) *GroupUpdate {
+func (_u *GroupUpdate) AddImagePrice2k(v money.Amount) *GroupUpdate {
 	_u.mutation.AddImagePrice2k(v)
 	return _u
 }
@@ -329,14 +330,14 @@ func (_u *GroupUpdate) ClearImagePrice2k() *GroupUpdate {
 }
 
 // SetImagePrice4k sets the "image_price_4k" field.
-func (_u *GroupUpdate) SetImagePrice4k(v float64) *GroupUpdate {
+func (_u *GroupUpdate) SetImagePrice4k(v money.Amount) *GroupUpdate {
 	_u.mutation.ResetImagePrice4k()
 	_u.mutation.SetImagePrice4k(v)
 	return _u
 }
 
 // SetNillableImagePrice4k sets the "image_price_4k" field if the given value is not nil.
-func (_u *GroupUpdate) SetNillableImagePrice4k(v *float64) *GroupUpdate {
+func (_u *GroupUpdate) SetNillableImagePrice4k(v *money.Amount) *GroupUpdate {
 	if v != nil {
 		_u.SetImagePrice4k(*v)
 	}
@@ -344,7 +345,7 @@ func (_u *GroupUpdate) SetNillableImagePrice4k(v *float64) *GroupUpdate {
 }
 
 // AddImagePrice4k adds value to the "image_price_4k" field.
-func (_u *GroupUpdate) AddImagePrice4k(v float64) *GroupUpdate {
+func (_u *GroupUpdate) AddImagePrice4k(v money.Amount) *GroupUpdate {
 	_u.mutation.AddImagePrice4k(v)
 	return _u
 }
@@ -356,14 +357,14 @@ func (_u *GroupUpdate) ClearImagePrice4k() *GroupUpdate {
 }
 
 // SetSoraImagePrice360 sets the "sora_image_price_360" field.
-func (_u *GroupUpdate) SetSoraImagePrice360(v float64) *GroupUpdate {
+func (_u *GroupUpdate) SetSoraImagePrice360(v money.Amount) *GroupUpdate {
 	_u.mutation.ResetSoraImagePrice360()
 	_u.mutation.SetSoraImagePrice360(v)
 	return _u
 }
 
 // SetNillableSoraImagePrice360 sets the "sora_image_price_360" field if the given value is not nil.
-func (_u *GroupUpdate) SetNillableSoraImagePrice360(v *float64) *GroupUpdate {
+func (_u *GroupUpdate) SetNillableSoraImagePrice360(v *money.Amount) *GroupUpdate {
 	if v != nil {
 		_u.SetSoraImagePrice360(*v)
 	}
@@ -371,7 +372,7 @@ func (_u *GroupUpdate) SetNillableSoraImagePrice360(v *float64) *GroupUpdate {
 }
 
 // AddSoraImagePrice360 adds value to the "sora_image_price_360" field.
-func (_u *GroupUpdate) AddSoraImagePrice360(v float64) *GroupUpdate {
+func (_u *GroupUpdate) AddSoraImagePrice360(v money.Amount) *GroupUpdate {
 	_u.mutation.AddSoraImagePrice360(v)
 	return _u
 }
@@ -383,14 +384,14 @@ func (_u *GroupUpdate) ClearSoraImagePrice360() *GroupUpdate {
 }
 
 // SetSoraImagePrice540 sets the "sora_image_price_540" field.
-func (_u *GroupUpdate) SetSoraImagePrice540(v float64) *GroupUpdate {
+func (_u *GroupUpdate) SetSoraImagePrice540(v money.Amount) *GroupUpdate {
 	_u.mutation.ResetSoraImagePrice540()
 	_u.mutation.SetSoraImagePrice540(v)
 	return _u
 }
 
 // SetNillableSoraImagePrice540 sets the "sora_image_price_540" field if the given value is not nil.
-func (_u *GroupUpdate) SetNillableSoraImagePrice540(v *float64) *GroupUpdate {
+func (_u *GroupUpdate) SetNillableSoraImagePrice540(v *money.Amount) *GroupUpdate {
 	if v != nil {
 		_u.SetSoraImagePrice540(*v)
 	}
@@ -398,7 +399,7 @@ func (_u *GroupUpdate) SetNillableSoraImagePrice540(v *float64) *GroupUpdate {
 }
 
 // AddSoraImagePrice540 adds value to the "sora_image_price_540" field.
-func (_u *GroupUpdate) AddSoraImagePrice540(v float64) *GroupUpdate {
+func (_u *GroupUpdate) AddSoraImagePrice540(v money.Amount) *GroupUpdate {
 	_u.mutation.AddSoraImagePrice540(v)
 	return _u
 }
@@ -410,14 +411,14 @@ func (_u *GroupUpdate) ClearSoraImagePrice540() *GroupUpdate {
 }
 
 // SetSoraVideoPricePerRequest sets the "sora_video_price_per_request" field.
-func (_u *GroupUpdate) SetSoraVideoPricePerRequest(v float64) *GroupUpdate {
+func (_u *GroupUpdate) SetSoraVideoPricePerRequest(v money.Amount) *GroupUpdate {
 	_u.mutation.ResetSoraVideoPricePerRequest()
 	_u.mutation.SetSoraVideoPricePerRequest(v)
 	return _u
 }
 
 // SetNillableSoraVideoPricePerRequest sets the "sora_video_price_per_request" field if the given value is not nil.
-func (_u *GroupUpdate) SetNillableSoraVideoPricePerRequest(v *float64) *GroupUpdate {
+func (_u *GroupUpdate) SetNillableSoraVideoPricePerRequest(v *money.Amount) *GroupUpdate {
 	if v != nil {
 		_u.SetSoraVideoPricePerRequest(*v)
 	}
@@ -425,7 +426,7 @@ func (_u *GroupUpdate) SetNillableSoraVideoPricePerRequest(v *float64) *GroupUpd
 }
 
 // AddSoraVideoPricePerRequest adds value to the "sora_video_price_per_request" field.
-func (_u *GroupUpdate) AddSoraVideoPricePerRequest(v float64) *GroupUpdate {
+func (_u *GroupUpdate) AddSoraVideoPricePerRequest(v money.Amount) *GroupUpdate {
 	_u.mutation.AddSoraVideoPricePerRequest(v)
 	return _u
 }
@@ -437,14 +438,14 @@ func (_u *GroupUpdate) ClearSoraVideoPricePerRequest() *GroupUpdate {
 }
 
 // SetSoraVideoPricePerRequestHd sets the "sora_video_price_per_request_hd" field.
-func (_u *GroupUpdate) SetSoraVideoPricePerRequestHd(v float64) *GroupUpdate {
+func (_u *GroupUpdate) SetSoraVideoPricePerRequestHd(v money.Amount) *GroupUpdate {
 	_u.mutation.ResetSoraVideoPricePerRequestHd()
 	_u.mutation.SetSoraVideoPricePerRequestHd(v)
 	return _u
 }
 
 // SetNillableSoraVideoPricePerRequestHd sets the "sora_video_price_per_request_hd" field if the given value is not nil.
-func (_u *GroupUpdate) SetNillableSoraVideoPricePerRequestHd(v *float64) *GroupUpdate {
+func (_u *GroupUpdate) SetNillableSoraVideoPricePerRequestHd(v *money.Amount) *GroupUpdate {
 	if v != nil {
 		_u.SetSoraVideoPricePerRequestHd(*v)
 	}
@@ -452,7 +453,7 @@ func (_u *GroupUpdate) SetNillableSoraVideoPricePerRequestHd(v *float64) *GroupU
 }
 
 // AddSoraVideoPricePerRequestHd adds value to the "sora_video_price_per_request_hd" field.
-func (_u *GroupUpdate) AddSoraVideoPricePerRequestHd(v float64) *GroupUpdate {
+func (_u *GroupUpdate) AddSoraVideoPricePerRequestHd(v money.Amount) *GroupUpdate {
 	_u.mutation.AddSoraVideoPricePerRequestHd(v)
 	return _u
 }
@@ -485,14 +486,14 @@ func (_u *GroupUpdate) AddSoraStorageQuotaBytes(v int64) *GroupUpdate {
 }
 
 // SetVideoPricePerRequest sets the "video_price_per_request" field.
-func (_u *GroupUpdate) SetVideoPricePerRequest(v float64) *GroupUpdate {
+func (_u *GroupUpdate) SetVideoPricePerRequest(v money.Amount) *GroupUpdate {
 	_u.mutation.ResetVideoPricePerRequest()
 	_u.mutation.SetVideoPricePerRequest(v)
 	return _u
 }
 
 // SetNillableVideoPricePerRequest sets the "video_price_per_request" field if the given value is not nil.
-func (_u *GroupUpdate) SetNillableVideoPricePerRequest(v *float64) *GroupUpdate {
+func (_u *GroupUpdate) SetNillableVideoPricePerRequest(v *money.Amount) *GroupUpdate {
 	if v != nil {
 		_u.SetVideoPricePerRequest(*v)
 	}
@@ -500,7 +501,7 @@ func (_u *GroupUpdate) SetNillableVideoPricePerRequest(v *float64) *GroupUpdate
 }
 
 // AddVideoPricePerRequest adds value to the "video_price_per_request" field.
-func (_u *GroupUpdate) AddVideoPricePerRequest(v float64) *GroupUpdate {
+func (_u *GroupUpdate) AddVideoPricePerRequest(v money.Amount) *GroupUpdate {
 	_u.mutation.AddVideoPricePerRequest(v)
 	return _u
 }
@@ -512,14 +513,14 @@ func (_u *GroupUpdate) ClearVideoPricePerRequest() *GroupUpdate {
 }
 
 // SetVideoPricePerRequestHd sets the "video_price_per_request_hd" field.
-func (_u *GroupUpdate) SetVideoPricePerRequestHd(v float64) *GroupUpdate {
+func (_u *GroupUpdate) SetVideoPricePerRequestHd(v money.Amount) *GroupUpdate {
 	_u.mutation.ResetVideoPricePerRequestHd()
 	_u.mutation.SetVideoPricePerRequestHd(v)
 	return _u
 }
 
 // SetNillableVideoPricePerRequestHd sets the "video_price_per_request_hd" field if the given value is not nil.
-func (_u *GroupUpdate) SetNillableVideoPricePerRequestHd(v *float64) *GroupUpdate {
+func (_u *GroupUpdate) SetNillableVideoPricePerRequestHd(v *money.Amount) *GroupUpdate {
 	if v != nil {
 		_u.SetVideoPricePerRequestHd(*v)
 	}
@@ -527,7 +528,7 @@ func (_u *GroupUpdate) SetNillableVideoPricePerRequestHd(v *float64) *GroupUpdat
 }
 
 // AddVideoPricePerRequestHd adds value to the "video_price_per_request_hd" field.
-func (_u *GroupUpdate) AddVideoPricePerRequestHd(v float64) *GroupUpdate {
+func (_u *GroupUpdate) AddVideoPricePerRequestHd(v money.Amount) *GroupUpdate {
 	_u.mutation.AddVideoPricePerRequestHd(v)
 	return _u
 }
@@ -538,6 +539,60 @@ func (_u *GroupUpdate) ClearVideoPricePerRequestHd() *GroupUpdate {
 	return _u
 }
 
+// SetAudioPricePerMinute sets the "audio_price_per_minute" field.
+func (_u *GroupUpdate) SetAudioPricePerMinute(v money.Amount) *GroupUpdate {
+	_u.mutation.ResetAudioPricePerMinute()
+	_u.mutation.SetAudioPricePerMinute(v)
+	return _u
+}
+
+// SetNillableAudioPricePerMinute sets the "audio_price_per_minute" field if the given value is not nil.
+func (_u *GroupUpdate) SetNillableAudioPricePerMinute(v *money.Amount) *GroupUpdate {
+	if v != nil {
+		_u.SetAudioPricePerMinute(*v)
+	}
+	return _u
+}
+
+// AddAudioPricePerMinute adds value to the "audio_price_per_minute" field.
+func (_u *GroupUpdate) AddAudioPricePerMinute(v money.Amount) *GroupUpdate {
+	_u.mutation.AddAudioPricePerMinute(v)
+	return _u
+}
+
+// ClearAudioPricePerMinute clears the value of the "audio_price_per_minute" field.
+func (_u *GroupUpdate) ClearAudioPricePerMinute() *GroupUpdate {
+	_u.mutation.ClearAudioPricePerMinute()
+	return _u
+}
+
+// SetAudioSpeechPricePer1mChars sets the "audio_speech_price_per_1m_chars" field.
+func (_u *GroupUpdate) SetAudioSpeechPricePer1mChars(v money.Amount) *GroupUpdate {
+	_u.mutation.ResetAudioSpeechPricePer1mChars()
+	_u.mutation.SetAudioSpeechPricePer1mChars(v)
+	return _u
+}
+
+// SetNillableAudioSpeechPricePer1mChars sets the "audio_speech_price_per_1m_chars" field if the given value is not nil.
+func (_u *GroupUpdate) SetNillableAudioSpeechPricePer1mChars(v *money.Amount) *GroupUpdate {
+	if v != nil {
+		_u.SetAudioSpeechPricePer1mChars(*v)
+	}
+	return _u
+}
+
+// AddAudioSpeechPricePer1mChars adds value to the "audio_speech_price_per_1m_chars" field.
+func (_u *GroupUpdate) AddAudioSpeechPricePer1mChars(v money.Amount) *GroupUpdate {
+	_u.mutation.AddAudioSpeechPricePer1mChars(v)
+	return _u
+}
+
+// ClearAudioSpeechPricePer1mChars clears the value of the "audio_speech_price_per_1m_chars" field.
+func (_u *GroupUpdate) ClearAudioSpeechPricePer1mChars() *GroupUpdate {
+	_u.mutation.ClearAudioSpeechPricePer1mChars()
+	return _u
+}
+
 // SetClaudeCodeOnly sets the "claude_code_only" field.
 func (_u *GroupUpdate) SetClaudeCodeOnly(v bool) *GroupUpdate {
 	_u.mutation.SetClaudeCodeOnly(v)
@@ -1016,31 +1071,31 @@ func (_u *GroupUpdate) sqlSave(ctx context.Context) (_node int, err error) {
 		_spec.SetField(group.FieldSubscriptionType, field.TypeString, value)
 	}
 	if value, ok := _u.mutation.DailyLimitUsd(); ok {
-		_spec.SetField(group.FieldDailyLimitUsd, field.TypeFloat64, value)
+		_spec.SetField(group.FieldDailyLimitUsd, field.TypeInt64, value)
 	}
 	if value, ok := _u.mutation.AddedDailyLimitUsd(); ok {
-		_spec.AddField(group.FieldDailyLimitUsd, field.TypeFloat64, value)
+		_spec.AddField(group.FieldDailyLimitUsd, field.TypeInt64, value)
 	}
 	if _u.mutation.DailyLimitUsdCleared() {
-		_spec.ClearField(group.FieldDailyLimitUsd, field.TypeFloat64)
+		_spec.ClearField(group.FieldDailyLimitUsd, field.TypeInt64)
 	}
 	if value, ok := _u.mutation.WeeklyLimitUsd(); ok {
-		_spec.SetField(group.FieldWeeklyLimitUsd, field.TypeFloat64, value)
+		_spec.SetField(group.FieldWeeklyLimitUsd, field.TypeInt64, value)
 	}
 	if value, ok := _u.mutation.AddedWeeklyLimitUsd(); ok {
-		_spec.AddField(group.FieldWeeklyLimitUsd, field.TypeFloat64, value)
+		_spec.AddField(group.FieldWeeklyLimitUsd, field.TypeInt64, value)
 	}
 	if _u.mutation.WeeklyLimitUsdCleared() {
-		_spec.ClearField(group.FieldWeeklyLimitUsd, field.TypeFloat64)
+		_spec.ClearField(group.FieldWeeklyLimitUsd, field.TypeInt64)
 	}
 	if value, ok := _u.mutation.MonthlyLimitUsd(); ok {
-		_spec.SetField(group.FieldMonthlyLimitUsd, field.TypeFloat64, value)
+		_spec.SetField(group.FieldMonthlyLimitUsd, field.TypeInt64, value)
 	}
 	if value, ok := _u.mutation.AddedMonthlyLimitUsd(); ok {
-		_spec.AddField(group.FieldMonthlyLimitUsd, field.TypeFloat64, value)
+		_spec.AddField(group.FieldMonthlyLimitUsd, field.TypeInt64, value)
 	}
 	if _u.mutation.MonthlyLimitUsdCleared() {
-		_spec.ClearField(group.FieldMonthlyLimitUsd, field.TypeFloat64)
+		_spec.ClearField(group.FieldMonthlyLimitUsd, field.TypeInt64)
 	}
 	if value, ok := _u.mutation.DefaultValidityDays(); ok {
 		_spec.SetField(group.FieldDefaultValidityDays, field.TypeInt, value)
@@ -1049,67 +1104,67 @@ func (_u *GroupUpdate) sqlSave(ctx context.Context) (_node int, err error) {
 		_spec.AddField(group.FieldDefaultValidityDays, field.TypeInt, value)
 	}
 	if value, ok := _u.mutation.ImagePrice1k(); ok {
-		_spec.SetField(group.FieldImagePrice1k, field.TypeFloat64, value)
+		_spec.SetField(group.FieldImagePrice1k, field.TypeInt64, value)
 	}
 	if value, ok := _u.mutation.AddedImagePrice1k(); ok {
-		_spec.AddField(group.FieldImagePrice1k, field.TypeFloat64, value)
+		_spec.AddField(group.FieldImagePrice1k, field.TypeInt64, value)
 	}
 	if _u.mutation.ImagePrice1kCleared() {
-		_spec.ClearField(group.FieldImagePrice1k, field.TypeFloat64)
+		_spec.ClearField(group.FieldImagePrice1k, field.TypeInt64)
 	}
 	if value, ok := _u.mutation.ImagePrice2k(); ok {
-		_spec.SetField(group.FieldImagePrice2k, field.TypeFloat64, value)
+		_spec.SetField(group.FieldImagePrice2k, field.TypeInt64, value)
 	}
 	if value, ok := _u.mutation.AddedImagePrice2k(); ok {
-		_spec.AddField(group.FieldImagePrice2k, field.TypeFloat64, value)
+		_spec.AddField(group.FieldImagePrice2k, field.TypeInt64, value)
 	}
 	if _u.mutation.ImagePrice2kCleared() {
-		_spec.ClearField(group.FieldImagePrice2k, field.TypeFloat64)
+		_spec.ClearField(group.FieldImagePrice2k, field.TypeInt64)
 	}
 	if value, ok := _u.mutation.ImagePrice4k(); ok {
-		_spec.SetField(group.FieldImagePrice4k, field.TypeFloat64, value)
+		_spec.SetField(group.FieldImagePrice4k, field.TypeInt64, value)
 	}
 	if value, ok := _u.mutation.AddedImagePrice4k(); ok {
-		_spec.AddField(group.FieldImagePrice4k, field.TypeFloat64, value)
+		_spec.AddField(group.FieldImagePrice4k, field.TypeInt64, value)
 	}
 	if _u.mutation.ImagePrice4kCleared() {
-		_spec.ClearField(group.FieldImagePrice4k, field.TypeFloat64)
+		_spec.ClearField(group.FieldImagePrice4k, field.TypeInt64)
 	}
 	if value, ok := _u.mutation.SoraImagePrice360(); ok {
-		_spec.SetField(group.FieldSoraImagePrice360, field.TypeFloat64, value)
+		_spec.SetField(group.FieldSoraImagePrice360, field.TypeInt64, value)
 	}
 	if value, ok := _u.mutation.AddedSoraImagePrice360(); ok {
-		_spec.AddField(group.FieldSoraImagePrice360, field.TypeFloat64, value)
+		_spec.AddField(group.FieldSoraImagePrice360, field.TypeInt64, value)
 	}
 	if _u.mutation.SoraImagePrice360Cleared() {
-		_spec.ClearField(group.FieldSoraImagePrice360, field.TypeFloat64)
+		_spec.ClearField(group.FieldSoraImagePrice360, field.TypeInt64)
 	}
 	if value, ok := _u.mutation.SoraImagePrice540(); ok {
-		_spec.SetField(group.FieldSoraImagePrice540, field.TypeFloat64, value)
+		_spec.SetField(group.FieldSoraImagePrice540, field.TypeInt64, value)
 	}
 	if value, ok := _u.mutation.AddedSoraImagePrice540(); ok {
-		_spec.AddField(group.FieldSoraImagePrice540, field.TypeFloat64, value)
+		_spec.AddField(group.FieldSoraImagePrice540, field.TypeInt64, value)
 	}
 	if _u.mutation.SoraImagePrice540Cleared() {
-		_spec.ClearField(group.FieldSoraImagePrice540, field.TypeFloat64)
+		_spec.ClearField(group.FieldSoraImagePrice540, field.TypeInt64)
 	}
 	if value, ok := _u.mutation.SoraVideoPricePerRequest(); ok {
-		_spec.SetField(group.FieldSoraVideoPricePerRequest, field.TypeFloat64, value)
+		_spec.SetField(group.FieldSoraVideoPricePerRequest, field.TypeInt64, value)
 	}
 	if value, ok := _u.mutation.AddedSoraVideoPricePerRequest(); ok {
-		_spec.AddField(group.FieldSoraVideoPricePerRequest, field.TypeFloat64, value)
+		_spec.AddField(group.FieldSoraVideoPricePerRequest, field.TypeInt64, value)
 	}
 	if _u.mutation.SoraVideoPricePerRequestCleared() {
-		_spec.ClearField(group.FieldSoraVideoPricePerRequest, field.TypeFloat64)
+		_spec.ClearField(group.FieldSoraVideoPricePerRequest, field.TypeInt64)
 	}
 	if value, ok := _u.mutation.SoraVideoPricePerRequestHd(); ok {
-		_spec.SetField(group.FieldSoraVideoPricePerRequestHd, field.TypeFloat64, value)
+		_spec.SetField(group.FieldSoraVideoPricePerRequestHd, field.TypeInt64, value)
 	}
 	if value, ok := _u.mutation.AddedSoraVideoPricePerRequestHd(); ok {
-		_spec.AddField(group.FieldSoraVideoPricePerRequestHd, field.TypeFloat64, value)
+		_spec.AddField(group.FieldSoraVideoPricePerRequestHd, field.TypeInt64, value)
 	}
 	if _u.mutation.SoraVideoPricePerRequestHdCleared() {
-		_spec.ClearField(group.FieldSoraVideoPricePerRequestHd, field.TypeFloat64)
+		_spec.ClearField(group.FieldSoraVideoPricePerRequestHd, field.TypeInt64)
 	}
 	if value, ok := _u.mutation.SoraStorageQuotaBytes(); ok {
 		_spec.SetField(group.FieldSoraStorageQuotaBytes, field.TypeInt64, value)
@@ -1118,22 +1173,40 @@ func (_u *GroupUpdate) sqlSave(ctx context.Context) (_node int, err error) {
 		_spec.AddField(group.FieldSoraStorageQuotaBytes, field.TypeInt64, value)
 	}
 	if value, ok := _u.mutation.VideoPricePerRequest(); ok {
-		_spec.SetField(group.FieldVideoPricePerRequest, field.TypeFloat64, value)
+		_spec.SetField(group.FieldVideoPricePerRequest, field.TypeInt64, value)
 	}
 	if value, ok := _u.mutation.AddedVideoPricePerRequest(); ok {
-		_spec.AddField(group.FieldVideoPricePerRequest, field.TypeFloat64, value)
+		_spec.AddField(group.FieldVideoPricePerRequest, field.TypeInt64, value)
 	}
 	if _u.mutation.VideoPricePerRequestCleared() {
-		_spec.ClearField(group.FieldVideoPricePerRequest, field.TypeFloat64)
+		_spec.ClearField(group.FieldVideoPricePerRequest, field.TypeInt64)
 	}
 	if value, ok := _u.mutation.VideoPricePerRequestHd(); ok {
-		_spec.SetField(group.FieldVideoPricePerRequestHd, field.TypeFloat64, value)
+		_spec.SetField(group.FieldVideoPricePerRequestHd, field.TypeInt64, value)
 	}
 	if value, ok := _u.mutation.AddedVideoPricePerRequestHd(); ok {
-		_spec.AddField(group.FieldVideoPricePerRequestHd, field.TypeFloat64, value)
+		_spec.AddField(group.FieldVideoPricePerRequestHd, field.TypeInt64, value)
 	}
 	if _u.mutation.VideoPricePerRequestHdCleared() {
-		_spec.ClearField(group.FieldVideoPricePerRequestHd, field.TypeFloat64)
+		_spec.ClearField(group.FieldVideoPricePerRequestHd, field.TypeInt64)
+	}
+	if value, ok := _u.mutation.AudioPricePerMinute(); ok {
+		_spec.SetField(group.FieldAudioPricePerMinute, field.TypeInt64, value)
+	}
+	if value, ok := _u.mutation.AddedAudioPricePerMinute(); ok {
+		_spec.AddField(group.FieldAudioPricePerMinute, field.TypeInt64, value)
+	}
+	if _u.mutation.AudioPricePerMinuteCleared() {
+		_spec.ClearField(group.FieldAudioPricePerMinute, field.TypeInt64)
+	}
+	if value, ok := _u.mutation.AudioSpeechPricePer1mChars(); ok {
+		_spec.SetField(group.FieldAudioSpeechPricePer1mChars, field.TypeInt64, value)
+	}
+	if value, ok := _u.mutation.AddedAudioSpeechPricePer1mChars(); ok {
+		_spec.AddField(group.FieldAudioSpeechPricePer1mChars, field.TypeInt64, value)
+	}
+	if _u.mutation.AudioSpeechPricePer1mCharsCleared() {
+		_spec.ClearField(group.FieldAudioSpeechPricePer1mChars, field.TypeInt64)
 	}
 	if value, ok := _u.mutation.ClaudeCodeOnly(); ok {
 		_spec.SetField(group.FieldClaudeCodeOnly, field.TypeBool, value)
@@ -1634,14 +1707,14 @@ func (_u *GroupUpdateOne) SetNillableSubscriptionType(v *string) *GroupUpdateOne
 }
 
 // SetDailyLimitUsd sets the "daily_limit_usd" field.
-func (_u *GroupUpdateOne) SetDailyLimitUsd(v float64) *GroupUpdateOne {
+func (_u *GroupUpdateOne) SetDailyLimitUsd(v money.Amount) *GroupUpdateOne {
 	_u.mutation.ResetDailyLimitUsd()
 	_u.mutation.SetDailyLimitUsd(v)
 	return _u
 }
 
 // SetNillableDailyLimitUsd sets the "daily_limit_usd" field if the given value is not nil.
-func (_u *GroupUpdateOne) SetNillableDailyLimitUsd(v *float64) *GroupUpdateOne {
+func (_u *GroupUpdateOne) SetNillableDailyLimitUsd(v *money.Amount) *GroupUpdateOne {
 	if v != nil {
 		_u.SetDailyLimitUsd(*v)
 	}
@@ -1649,7 +1722,7 @@ func (_u *GroupUpdateOne) SetNillableDailyLimitUsd(v *float64) *GroupUpdateOne {
 }
 
 // AddDailyLimitUsd adds value to the "daily_limit_usd" field.
-func (_u *GroupUpdateOne) AddDailyLimitUsd(v float64) *GroupUpdateOne {
+func (_u *GroupUpdateOne) AddDailyLimitUsd(v money.Amount) *GroupUpdateOne {
 	_u.mutation.AddDailyLimitUsd(v)
 	return _u
 }
@@ -1661,14 +1734,14 @@ func (_u *GroupUpdateOne) ClearDailyLimitUsd() *GroupUpdateOne {
 }
 
 // SetWeeklyLimitUsd sets the "weekly_limit_usd" field.
-func (_u *GroupUpdateOne) SetWeeklyLimitUsd(v float64) *GroupUpdateOne {
+func (_u *GroupUpdateOne) SetWeeklyLimitUsd(v money.Amount) *GroupUpdateOne {
 	_u.mutation.ResetWeeklyLimitUsd()
 	_u.mutation.SetWeeklyLimitUsd(v)
 	return _u
 }
 
 // SetNillableWeeklyLimitUsd sets the "weekly_limit_usd" field if the given value is not nil.
-func (_u *GroupUpdateOne) SetNillableWeeklyLimitUsd(v *float64) *GroupUpdateOne {
+func (_u *GroupUpdateOne) SetNillableWeeklyLimitUsd(v *money.Amount) *GroupUpdateOne {
 	if v != nil {
 		_u.SetWeeklyLimitUsd(*v)
 	}
@@ -1676,7 +1749,7 @@ func (_u *GroupUpdateOne) SetNillableWeeklyLimitUsd(v *float64) *GroupUpdateOne
 }
 
 // AddWeeklyLimitUsd adds value to the "weekly_limit_usd" field.
-func (_u *GroupUpdateOne) AddWeeklyLimitUsd(v float64) *GroupUpdateOne {
+func (_u *GroupUpdateOne) AddWeeklyLimitUsd(v money.Amount) *GroupUpdateOne {
 	_u.mutation.AddWeeklyLimitUsd(v)
 	return _u
 }
@@ -1688,14 +1761,14 @@ func (_u *GroupUpdateOne) ClearWeeklyLimitUsd() *GroupUpdateOne {
 }
 
 // SetMonthlyLimitUsd sets the "monthly_limit_usd" field.
-func (_u *GroupUpdateOne) SetMonthlyLimitUsd(v float64) *GroupUpdateOne {
+func (_u *GroupUpdateOne) SetMonthlyLimitUsd(v money.Amount) *GroupUpdateOne {
 	_u.mutation.ResetMonthlyLimitUsd()
 	_u.mutation.SetMonthlyLimitUsd(v)
 	return _u
 }
 
 // SetNillableMonthlyLimitUsd sets the "monthly_limit_usd" field if the given value is not nil.
-func (_u *GroupUpdateOne) SetNillableMonthlyLimitUsd(v *float64) *GroupUpdateOne {
+func (_u *GroupUpdateOne) SetNillableMonthlyLimitUsd(v *money.Amount) *GroupUpdateOne {
 	if v != nil {
 		_u.SetMonthlyLimitUsd(*v)
 	}
@@ -1703,7 +1776,7 @@ func (_u *GroupUpdateOne) SetNillableMonthlyLimitUsd(v *float64) *GroupUpdateOne
 }
 
 // AddMonthlyLimitUsd adds value to the "monthly_limit_usd" field.
-func (_u *GroupUpdateOne) AddMonthlyLimitUsd(v float64) *GroupUpdateOne {
+func (_u *GroupUpdateOne) AddMonthlyLimitUsd(v money.Amount) *GroupUpdateOne {
 	_u.mutation.AddMonthlyLimitUsd(v)
 	return _u
 }
@@ -1736,14 +1809,14 @@ func (_u *GroupUpdateOne) AddDefaultValidityDays(v int) *GroupUpdateOne {
 }
 
 // SetImagePrice1k sets the "image_price_1k" field.
-func (_u *GroupUpdateOne) SetImagePrice1k(v float64) *GroupUpdateOne {
+func (_u *GroupUpdateOne) SetImagePrice1k(v money.Amount) *GroupUpdateOne {
 	_u.mutation.ResetImagePrice1k()
 	_u.mutation.SetImagePrice1k(v)
 	return _u
 }
 
 // SetNillableImagePrice1k sets the "image_price_1k" field if the given value is not nil.
-func (_u *GroupUpdateOne) SetNillableImagePrice1k(v *float64) *GroupUpdateOne {
+func (_u *GroupUpdateOne) SetNillableImagePrice1k(v *money.Amount) *GroupUpdateOne {
 	if v != nil {
 		_u.SetImagePrice1k(*v)
 	}
@@ -1751,7 +1824,7 @@ func (_u *GroupUpdateOne) SetNillableImagePrice1k(v *float64) *GroupUpdateOne {
 }
 
 // AddImagePrice1k adds value to the "image_price_1k" field.
-func (_u *GroupUpdateOne) AddImagePrice1k(v float64) *GroupUpdateOne {
+func (_u *GroupUpdateOne) AddImagePrice1k(v money.Amount) *GroupUpdateOne {
 	_u.mutation.AddImagePrice1k(v)
 	return _u
 }
@@ -1763,14 +1836,14 @@ func (_u *GroupUpdateOne) ClearImagePrice1k() *GroupUpdateOne {
 }
 
 // SetImagePrice2k sets the "image_price_2k" field.
-func (_u *GroupUpdateOne) SetImagePrice2k(v float64) *GroupUpdateOne {
+func (_u *GroupUpdateOne) SetImagePrice2k(v money.Amount) *GroupUpdateOne {
 	_u.mutation.ResetImagePrice2k()
 	_u.mutation.SetImagePrice2k(v)
 	return _u
 }
 
 // SetNillableImagePrice2k sets the "image_price_2k" field if the given value is not nil.
-func (_u *GroupUpdateOne) SetNillableImagePrice2k(v *float64) *GroupUpdateOne {
+func (_u *GroupUpdateOne) SetNillableImagePrice2k(v *money.Amount) *GroupUpdateOne {
 	if v != nil {
 		_u.SetImagePrice2k(*v)
 	}
@@ -1778,7 +1851,7 @@ func (_u *GroupUpdateOne) SetNillableImagePrice2k(v *float64) *GroupUpdateOne {
 }
 
 // AddImagePrice2k adds value to the "image_price_2k" field.
-func (_u *GroupUpdateOne) AddImagePrice2k(v float64) *GroupUpdateOne {
+func (_u *GroupUpdateOne) AddImagePrice2k(v money.Amount) *GroupUpdateOne {
 	_u.mutation.AddImagePrice2k(v)
 	return _u
 }
@@ -1790,14 +1863,14 @@ func (_u *GroupUpdateOne) ClearImagePrice2k() *GroupUpdateOne {
 }
 
 // SetImagePrice4k sets the "image_price_4k" field.
-func (_u *GroupUpdateOne) SetImagePrice4k(v float64) *GroupUpdateOne {
+func (_u *GroupUpdateOne) SetImagePrice4k(v money.Amount) *GroupUpdateOne {
 	_u.mutation.ResetImagePrice4k()
 	_u.mutation.SetImagePrice4k(v)
 	return _u
 }
 
 // SetNillableImagePrice4k sets the "image_price_4k" field if the given value is not nil.
-func (_u *GroupUpdateOne) SetNillableImagePrice4k(v *float64) *GroupUpdateOne {
+func (_u *GroupUpdateOne) SetNillableImagePrice4k(v *money.Amount) *GroupUpdateOne {
 	if v != nil {
 		_u.SetImagePrice4k(*v)
 	}
@@ -1805,7 +1878,7 @@ func (_u *GroupUpdateOne) SetNillableImagePrice4k(v *float64) *GroupUpdateOne {
 }
 
 // AddImagePrice4k adds value to the "image_price_4k" field.
-func (_u *GroupUpdateOne) AddImagePrice4k(v float64) *GroupUpdateOne {
+func (_u *GroupUpdateOne) AddImagePrice4k(v money.Amount) *GroupUpdateOne {
 	_u.mutation.AddImagePrice4k(v)
 	return _u
 }
@@ -1817,14 +1890,14 @@ func (_u *GroupUpdateOne) ClearImagePrice4k() *GroupUpdateOne {
 }
 
 // SetSoraImagePrice360 sets the "sora_image_price_360" field.
-func (_u *GroupUpdateOne) SetSoraImagePrice360(v float64) *GroupUpdateOne {
+func (_u *GroupUpdateOne) SetSoraImagePrice360(v money.Amount) *GroupUpdateOne {
 	_u.mutation.ResetSoraImagePrice360()
 	_u.mutation.SetSoraImagePrice360(v)
 	return _u
 }
 
 // SetNillableSoraImagePrice360 sets the "sora_image_price_360" field if the given value is not nil.
-func (_u *GroupUpdateOne) SetNillableSoraImagePrice360(v *float64) *GroupUpdateOne {
+func (_u *GroupUpdateOne) SetNillableSoraImagePrice360(v *money.Amount) *GroupUpdateOne {
 	if v != nil {
 		_u.SetSoraImagePrice360(*v)
 	}
@@ -1832,7 +1905,7 @@ func (_u *GroupUpdateOne) SetNillableSoraImagePrice360(v *float64) *GroupUpdateO
 }
 
 // AddSoraImagePrice360 adds value to the "sora_image_price_360" field.
-func (_u *GroupUpdateOne) AddSoraImagePrice360(v float64) *GroupUpdateOne {
+func (_u *GroupUpdateOne) AddSoraImagePrice360(v money.Amount) *GroupUpdateOne {
 	_u.mutation.AddSoraImagePrice360(v)
 	return _u
 }
@@ -1844,14 +1917,14 @@ func (_u *GroupUpdateOne) ClearSoraImagePrice360() *GroupUpdateOne {
 }
 
 // SetSoraImagePrice540 sets the "sora_image_price_540" field.
-func (_u *GroupUpdateOne) SetSoraImagePrice540(v float64) *GroupUpdateOne {
+func (_u *GroupUpdateOne) SetSoraImagePrice540(v money.Amount) *GroupUpdateOne {
 	_u.mutation.ResetSoraImagePrice540()
 	_u.mutation.SetSoraImagePrice540(v)
 	return _u
 }
 
 // SetNillableSoraImagePrice540 sets the "sora_image_price_540" field if the given value is not nil.
-func (_u *GroupUpdateOne) SetNillableSoraImagePrice540(v *float64) *GroupUpdateOne {
+func (_u *GroupUpdateOne) SetNillableSoraImagePrice540(v *money.Amount) *GroupUpdateOne {
 	if v != nil {
 		_u.SetSoraImagePrice540(*v)
 	}
@@ -1859,7 +1932,7 @@ func (_u *GroupUpdateOne) SetNillableSoraImagePrice540(v *float64) *GroupUpdateO
 }
 
 // AddSoraImagePrice540 adds value to the "sora_image_price_540" field.
-func (_u *GroupUpdateOne) AddSoraImagePrice540(v float64) *GroupUpdateOne {
+func (_u *GroupUpdateOne) AddSoraImagePrice540(v money.Amount) *GroupUpdateOne {
 	_u.mutation.AddSoraImagePrice540(v)
 	return _u
 }
@@ -1871,14 +1944,14 @@ func (_u *GroupUpdateOne) ClearSoraImagePrice540() *GroupUpdateOne {
 }
 
 // SetSoraVideoPricePerRequest sets the "sora_video_price_per_request" field.
-func (_u *GroupUpdateOne) SetSoraVideoPricePerRequest(v float64) *GroupUpdateOne {
+func (_u *GroupUpdateOne) SetSoraVideoPricePerRequest(v money.Amount) *GroupUpdateOne {
 	_u.mutation.ResetSoraVideoPricePerRequest()
 	_u.mutation.SetSoraVideoPricePerRequest(v)
 	return _u
 }
 
 // SetNillableSoraVideoPricePerRequest sets the "sora_video_price_per_request" field if the given value is not nil.
-func (_u *GroupUpdateOne) SetNillableSoraVideoPricePerRequest(v *float64) *GroupUpdateOne {
+func (_u *GroupUpdateOne) SetNillableSoraVideoPricePerRequest(v *money.Amount) *GroupUpdateOne {
 	if v != nil {
 		_u.SetSoraVideoPricePerRequest(*v)
 	}
@@ -1886,7 +1959,7 @@ func (_u *GroupUpdateOne) SetNillableSoraVideoPricePerRequest(v *float64) *Group
 }
 
 // AddSoraVideoPricePerRequest adds value to the "sora_video_price_per_request" field.
-func (_u *GroupUpdateOne) AddSoraVideoPricePerRequest(v float64) *GroupUpdateOne {
+func (_u *GroupUpdateOne) AddSoraVideoPricePerRequest(v money.Amount) *GroupUpdateOne {
 	_u.mutation.AddSoraVideoPricePerRequest(v)
 	return _u
 }
@@ -1898,14 +1971,14 @@ func (_u *GroupUpdateOne) ClearSoraVideoPricePerRequest() *GroupUpdateOne {
 }
 
 // SetSoraVideoPricePerRequestHd sets the "sora_video_price_per_request_hd" field.
-func (_u *GroupUpdateOne) SetSoraVideoPricePerRequestHd(v float64) *GroupUpdateOne {
+func (_u *GroupUpdateOne) SetSoraVideoPricePerRequestHd(v money.Amount) *GroupUpdateOne {
 	_u.mutation.ResetSoraVideoPricePerRequestHd()
 	_u.mutation.SetSoraVideoPricePerRequestHd(v)
 	return _u
 }
 
 // SetNillableSoraVideoPricePerRequestHd sets the "sora_video_price_per_request_hd" field if the given value is not nil.
-func (_u *GroupUpdateOne) SetNillableSoraVideoPricePerRequestHd(v *float64) *GroupUpdateOne {
+func (_u *GroupUpdateOne) SetNillableSoraVideoPricePerRequestHd(v *money.Amount) *GroupUpdateOne {
 	if v != nil {
 		_u.SetSoraVideoPricePerRequestHd(*v)
 	}
@@ -1913,7 +1986,7 @@ func (_u *GroupUpdateOne) SetNillableSoraVideoPricePerRequestHd(v *float64) *Gro
 }
 
 // AddSoraVideoPricePerRequestHd adds value to the "sora_video_price_per_request_hd" field.
-func (_u *GroupUpdateOne) AddSoraVideoPricePerRequestHd(v float64) *GroupUpdateOne {
+func (_u *GroupUpdateOne) AddSoraVideoPricePerRequestHd(v money.Amount) *GroupUpdateOne {
 	_u.mutation.AddSoraVideoPricePerRequestHd(v)
 	return _u
 }
@@ -1946,14 +2019,14 @@ func (_u *GroupUpdateOne) AddSoraStorageQuotaBytes(v int64) *GroupUpdateOne {
 }
 
 // SetVideoPricePerRequest sets the "video_price_per_request" field.
-func (_u *GroupUpdateOne) SetVideoPricePerRequest(v float64) *GroupUpdateOne {
+func (_u *GroupUpdateOne) SetVideoPricePerRequest(v money.Amount) *GroupUpdateOne {
 	_u.mutation.ResetVideoPricePerRequest()
 	_u.mutation.SetVideoPricePerRequest(v)
 	return _u
 }
 
 // SetNillableVideoPricePerRequest sets the "video_price_per_request" field if the given value is not nil.
-func (_u *GroupUpdateOne) SetNillableVideoPricePerRequest(v *float64) *GroupUpdateOne {
+func (_u *GroupUpdateOne) SetNillableVideoPricePerRequest(v *money.Amount) *GroupUpdateOne {
 	if v != nil {
 		_u.SetVideoPricePerRequest(*v)
 	}
@@ -1961,7 +2034,7 @@ func (_u *GroupUpdateOne) SetNillableVideoPricePerRequest(v *float64) *GroupUpda
 }
 
 // AddVideoPricePerRequest adds value to the "video_price_per_request" field.
-func (_u *GroupUpdateOne) AddVideoPricePerRequest(v float64) *GroupUpdateOne {
+func (_u *GroupUpdateOne) AddVideoPricePerRequest(v money.Amount) *GroupUpdateOne {
 	_u.mutation.AddVideoPricePerRequest(v)
 	return _u
 }
@@ -1973,14 +2046,14 @@ func (_u *GroupUpdateOne) ClearVideoPricePerRequest() *GroupUpdateOne {
 }
 
 // SetVideoPricePerRequestHd sets the "video_price_per_request_hd" field.
-func (_u *GroupUpdateOne) SetVideoPricePerRequestHd(v float64) *GroupUpdateOne {
+func (_u *GroupUpdateOne) SetVideoPricePerRequestHd(v money.Amount) *GroupUpdateOne {
 	_u.mutation.ResetVideoPricePerRequestHd()
 	_u.mutation.SetVideoPricePerRequestHd(v)
 	return _u
 }
 
 // SetNillableVideoPricePerRequestHd sets the "video_price_per_request_hd" field if the given value is not nil.
-func (_u *GroupUpdateOne) SetNillableVideoPricePerRequestHd(v *float64) *GroupUpdateOne {
+func (_u *GroupUpdateOne) SetNillableVideoPricePerRequestHd(v *money.Amount) *GroupUpdateOne {
 	if v != nil {
 		_u.SetVideoPricePerRequestHd(*v)
 	}
@@ -1988,7 +2061,7 @@ func (_u *GroupUpdateOne) SetNillableVideoPricePerRequestHd(v *float64) *GroupUp
 }
 
 // AddVideoPricePerRequestHd adds value to the "video_price_per_request_hd" field.
-func (_u *GroupUpdateOne) AddVideoPricePerRequestHd(v float64) *GroupUpdateOne {
+func (_u *GroupUpdateOne) AddVideoPricePerRequestHd(v money.Amount) *GroupUpdateOne {
 	_u.mutation.AddVideoPricePerRequestHd(v)
 	return _u
 }
@@ -1999,6 +2072,60 @@ func (_u *GroupUpdateOne) ClearVideoPricePerRequestHd() *GroupUpdateOne {
 	return _u
 }
 
+// SetAudioPricePerMinute sets the "audio_price_per_minute" field.
+func (_u *GroupUpdateOne) SetAudioPricePerMinute(v money.Amount) *GroupUpdateOne {
+	_u.mutation.ResetAudioPricePerMinute()
+	_u.mutation.SetAudioPricePerMinute(v)
+	return _u
+}
+
+// SetNillableAudioPricePerMinute sets the "audio_price_per_minute" field if the given value is not nil.
+func (_u *GroupUpdateOne) SetNillableAudioPricePerMinute(v *money.Amount) *GroupUpdateOne {
+	if v != nil {
+		_u.SetAudioPricePerMinute(*v)
+	}
+	return _u
+}
+
+// AddAudioPricePerMinute adds value to the "audio_price_per_minute" field.
+func (_u *GroupUpdateOne) AddAudioPricePerMinute(v money.Amount) *GroupUpdateOne {
+	_u.mutation.AddAudioPricePerMinute(v)
+	return _u
+}
+
+// ClearAudioPricePerMinute clears the value of the "audio_price_per_minute" field.
+func (_u *GroupUpdateOne) ClearAudioPricePerMinute() *GroupUpdateOne {
+	_u.mutation.ClearAudioPricePerMinute()
+	return _u
+}
+
+// SetAudioSpeechPricePer1mChars sets the "audio_speech_price_per_1m_chars" field.
+func (_u *GroupUpdateOne) SetAudioSpeechPricePer1mChars(v money.Amount) *GroupUpdateOne {
+	_u.mutation.ResetAudioSpeechPricePer1mChars()
+	_u.mutation.SetAudioSpeechPricePer1mChars(v)
+	return _u
+}
+
+// SetNillableAudioSpeechPricePer1mChars sets the "audio_speech_price_per_1m_chars" field if the given value is not nil.
+func (_u *GroupUpdateOne) SetNillableAudioSpeechPricePer1mChars(v *money.Amount) *GroupUpdateOne {
+	if v != nil {
+		_u.SetAudioSpeechPricePer1mChars(*v)
+	}
+	return _u
+}
+
+// AddAudioSpeechPricePer1mChars adds value to the "audio_speech_price_per_1m_chars" field.
+func (_u *GroupUpdateOne) AddAudioSpeechPricePer1mChars(v money.Amount) *GroupUpdateOne {
+	_u.mutation.AddAudioSpeechPricePer1mChars(v)
+	return _u
+}
+
+// ClearAudioSpeechPricePer1mChars clears the value of the "audio_speech_price_per_1m_chars" field.
+func (_u *GroupUpdateOne) ClearAudioSpeechPricePer1mChars() *GroupUpdateOne {
+	_u.mutation.ClearAudioSpeechPricePer1mChars()
+	return _u
+}
+
 // SetClaudeCodeOnly sets the "claude_code_only" field.
 func (_u *GroupUpdateOne) SetClaudeCodeOnly(v bool) *GroupUpdateOne {
 	_u.mutation.SetClaudeCodeOnly(v)
@@ -2507,31 +2634,31 @@ func (_u *GroupUpdateOne) sqlSave(ctx context.Context) (_node *Group, err error)
 		_spec.SetField(group.FieldSubscriptionType, field.TypeString, value)
 	}
 	if value, ok := _u.mutation.DailyLimitUsd(); ok {
-		_spec.SetField(group.FieldDailyLimitUsd, field.TypeFloat64, value)
+		_spec.SetField(group.FieldDailyLimitUsd, field.TypeInt64, value)
 	}
 	if value, ok := _u.mutation.AddedDailyLimitUsd(); ok {
-		_spec.AddField(group.FieldDailyLimitUsd, field.TypeFloat64, value)
+		_spec.AddField(group.FieldDailyLimitUsd, field.TypeInt64, value)
 	}
 	if _u.mutation.DailyLimitUsdCleared() {
-		_spec.ClearField(group.FieldDailyLimitUsd, field.TypeFloat64)
+		_spec.ClearField(group.FieldDailyLimitUsd, field.TypeInt64)
 	}
 	if value, ok := _u.mutation.WeeklyLimitUsd(); ok {
-		_spec.SetField(group.FieldWeeklyLimitUsd, field.TypeFloat64, value)
+		_spec.SetField(group.FieldWeeklyLimitUsd, field.TypeInt64, value)
 	}
 	if value, ok := _u.mutation.AddedWeeklyLimitUsd(); ok {
-		_spec.AddField(group.FieldWeeklyLimitUsd, field.TypeFloat64, value)
+		_spec.AddField(group.FieldWeeklyLimitUsd, field.TypeInt64, value)
 	}
 	if _u.mutation.WeeklyLimitUsdCleared() {
-		_spec.ClearField(group.FieldWeeklyLimitUsd, field.TypeFloat64)
+		_spec.ClearField(group.FieldWeeklyLimitUsd, field.TypeInt64)
 	}
 	if value, ok := _u.mutation.MonthlyLimitUsd(); ok {
-		_spec.SetField(group.FieldMonthlyLimitUsd, field.TypeFloat64, value)
+		_spec.SetField(group.FieldMonthlyLimitUsd, field.TypeInt64, value)
 	}
 	if value, ok := _u.mutation.AddedMonthlyLimitUsd(); ok {
-		_spec.AddField(group.FieldMonthlyLimitUsd, field.TypeFloat64, value)
+		_spec.AddField(group.FieldMonthlyLimitUsd, field.TypeInt64, value)
 	}
 	if _u.mutation.MonthlyLimitUsdCleared() {
-		_spec.ClearField(group.FieldMonthlyLimitUsd, field.TypeFloat64)
+		_spec.ClearField(group.FieldMonthlyLimitUsd, field.TypeInt64)
 	}
 	if value, ok := _u.mutation.DefaultValidityDays(); ok {
 		_spec.SetField(group.FieldDefaultValidityDays, field.TypeInt, value)
@@ -2540,67 +2667,67 @@ func (_u *GroupUpdateOne) sqlSave(ctx context.Context) (_node *Group, err error)
 		_spec.AddField(group.FieldDefaultValidityDays, field.TypeInt, value)
 	}
 	if value, ok := _u.mutation.ImagePrice1k(); ok {
-		_spec.SetField(group.FieldImagePrice1k, field.TypeFloat64, value)
+		_spec.SetField(group.FieldImagePrice1k, field.TypeInt64, value)
 	}
 	if value, ok := _u.mutation.AddedImagePrice1k(); ok {
-		_spec.AddField(group.FieldImagePrice1k, field.TypeFloat64, value)
+		_spec.AddField(group.FieldImagePrice1k, field.TypeInt64, value)
 	}
 	if _u.mutation.ImagePrice1kCleared() {
-		_spec.ClearField(group.FieldImagePrice1k, field.TypeFloat64)
+		_spec.ClearField(group.FieldImagePrice1k, field.TypeInt64)
 	}
 	if value, ok := _u.mutation.ImagePrice2k(); ok {
-		_spec.SetField(group.FieldImagePrice2k, field.TypeFloat64, value)
+		_spec.SetField(group.FieldImagePrice2k, field.TypeInt64, value)
 	}
 	if value, ok := _u.mutation.AddedImagePrice2k(); ok {
-		_spec.AddField(group.FieldImagePrice2k, field.TypeFloat64, value)
+		_spec.AddField(group.FieldImagePrice2k, field.TypeInt64, value)
 	}
 	if _u.mutation.ImagePrice2kCleared() {
-		_spec.ClearField(group.FieldImagePrice2k, field.TypeFloat64)
+		_spec.ClearField(group.FieldImagePrice2k, field.TypeInt64)
 	}
 	if value, ok := _u.mutation.ImagePrice4k(); ok {
-		_spec.SetField(group.FieldImagePrice4k, field.TypeFloat64, value)
+		_spec.SetField(group.FieldImagePrice4k, field.TypeInt64, value)
 	}
 	if value, ok := _u.mutation.AddedImagePrice4k(); ok {
-		_spec.AddField(group.FieldImagePrice4k, field.TypeFloat64, value)
+		_spec.AddField(group.FieldImagePrice4k, field.TypeInt64, value)
 	}
 	if _u.mutation.ImagePrice4kCleared() {
-		_spec.ClearField(group.FieldImagePrice4k, field.TypeFloat64)
+		_spec.ClearField(group.FieldImagePrice4k, field.TypeInt64)
 	}
 	if value, ok := _u.mutation.SoraImagePrice360(); ok {
-		_spec.SetField(group.FieldSoraImagePrice360, field.TypeFloat64, value)
+		_spec.SetField(group.FieldSoraImagePrice360, field.TypeInt64, value)
 	}
 	if value, ok := _u.mutation.AddedSoraImagePrice360(); ok {
-		_spec.AddField(group.FieldSoraImagePrice360, field.TypeFloat64, value)
+		_spec.AddField(group.FieldSoraImagePrice360, field.TypeInt64, value)
 	}
 	if _u.mutation.SoraImagePrice360Cleared() {
-		_spec.ClearField(group.FieldSoraImagePrice360, field.TypeFloat64)
+		_spec.ClearField(group.FieldSoraImagePrice360, field.TypeInt64)
 	}
 	if value, ok := _u.mutation.SoraImagePrice540(); ok {
-		_spec.SetField(group.FieldSoraImagePrice540, field.TypeFloat64, value)
+		_spec.SetField(group.FieldSoraImagePrice540, field.TypeInt64, value)
 	}
 	if value, ok := _u.mutation.AddedSoraImagePrice540(); ok {
-		_spec.AddField(group.FieldSoraImagePrice540, field.TypeFloat64, value)
+		_spec.AddField(group.FieldSoraImagePrice540, field.TypeInt64, value)
 	}
 	if _u.mutation.SoraImagePrice540Cleared() {
-		_spec.ClearField(group.FieldSoraImagePrice540, field.TypeFloat64)
+		_spec.ClearField(group.FieldSoraImagePrice540, field.TypeInt64)
 	}
 	if value, ok := _u.mutation.SoraVideoPricePerRequest(); ok {
-		_spec.SetField(group.FieldSoraVideoPricePerRequest, field.TypeFloat64, value)
+		_spec.SetField(group.FieldSoraVideoPricePerRequest, field.TypeInt64, value)
 	}
 	if value, ok := _u.mutation.AddedSoraVideoPricePerRequest(); ok {
-		_spec.AddField(group.FieldSoraVideoPricePerRequest, field.TypeFloat64, value)
+		_spec.AddField(group.FieldSoraVideoPricePerRequest, field.TypeInt64, value)
 	}
 	if _u.mutation.SoraVideoPricePerRequestCleared() {
-		_spec.ClearField(group.FieldSoraVideoPricePerRequest, field.TypeFloat64)
+		_spec.ClearField(group.FieldSoraVideoPricePerRequest, field.TypeInt64)
 	}
 	if value, ok := _u.mutation.SoraVideoPricePerRequestHd(); ok {
-		_spec.SetField(group.FieldSoraVideoPricePerRequestHd, field.TypeFloat64, value)
+		_spec.SetField(group.FieldSoraVideoPricePerRequestHd, field.TypeInt64, value)
 	}
 	if value, ok := _u.mutation.AddedSoraVideoPricePerRequestHd(); ok {
-		_spec.AddField(group.FieldSoraVideoPricePerRequestHd, field.TypeFloat64, value)
+		_spec.AddField(group.FieldSoraVideoPricePerRequestHd, field.TypeInt64, value)
 	}
 	if _u.mutation.SoraVideoPricePerRequestHdCleared() {
-		_spec.ClearField(group.FieldSoraVideoPricePerRequestHd, field.TypeFloat64)
+		_spec.ClearField(group.FieldSoraVideoPricePerRequestHd, field.TypeInt64)
 	}
 	if value, ok := _u.mutation.SoraStorageQuotaBytes(); ok {
 		_spec.SetField(group.FieldSoraStorageQuotaBytes, field.TypeInt64, value)
@@ -2609,22 +2736,40 @@ func (_u *GroupUpdateOne) sqlSave(ctx context.Context) (_node *Group, err error)
 		_spec.AddField(group.FieldSoraStorageQuotaBytes, field.TypeInt64, value)
 	}
 	if value, ok := _u.mutation.VideoPricePerRequest(); ok {
-		_spec.SetField(group.FieldVideoPricePerRequest, field.TypeFloat64, value)
+		_spec.SetField(group.FieldVideoPricePerRequest, field.TypeInt64, value)
 	}
 	if value, ok := _u.mutation.AddedVideoPricePerRequest(); ok {
-		_spec.AddField(group.FieldVideoPricePerRequest, field.TypeFloat64, value)
+		_spec.AddField(group.FieldVideoPricePerRequest, field.TypeInt64, value)
 	}
 	if _u.mutation.VideoPricePerRequestCleared() {
-		_spec.ClearField(group.FieldVideoPricePerRequest, field.TypeFloat64)
+		_spec.ClearField(group.FieldVideoPricePerRequest, field.TypeInt64)
 	}
 	if value, ok := _u.mutation.VideoPricePerRequestHd(); ok {
-		_spec.SetField(group.FieldVideoPricePerRequestHd, field.TypeFloat64, value)
+		_spec.SetField(group.FieldVideoPricePerRequestHd, field.TypeInt64, value)
 	}
 	if value, ok := _u.mutation.AddedVideoPricePerRequestHd(); ok {
-		_spec.AddField(group.FieldVideoPricePerRequestHd, field.TypeFloat64, value)
+		_spec.AddField(group.FieldVideoPricePerRequestHd, field.TypeInt64, value)
 	}
 	if _u.mutation.VideoPricePerRequestHdCleared() {
-		_spec.ClearField(group.FieldVideoPricePerRequestHd, field.TypeFloat64)
+		_spec.ClearField(group.FieldVideoPricePerRequestHd, field.TypeInt64)
+	}
+	if value, ok := _u.mutation.AudioPricePerMinute(); ok {
+		_spec.SetField(group.FieldAudioPricePerMinute, field.TypeInt64, value)
+	}
+	if value, ok := _u.mutation.AddedAudioPricePerMinute(); ok {
+		_spec.AddField(group.FieldAudioPricePerMinute, field.TypeInt64, value)
+	}
+	if _u.mutation.AudioPricePerMinuteCleared() {
+		_spec.ClearField(group.FieldAudioPricePerMinute, field.TypeInt64)
+	}
+	if value, ok := _u.mutation.AudioSpeechPricePer1mChars(); ok {
+		_spec.SetField(group.FieldAudioSpeechPricePer1mChars, field.TypeInt64, value)
+	}
+	if value, ok := _u.mutation.AddedAudioSpeechPricePer1mChars(); ok {
+		_spec.AddField(group.FieldAudioSpeechPricePer1mChars, field.TypeInt64, value)
+	}
+	if _u.mutation.AudioSpeechPricePer1mCharsCleared() {
+		_spec.ClearField(group.FieldAudioSpeechPricePer1mChars, field.TypeInt64)
 	}
 	if value, ok := _u.mutation.ClaudeCodeOnly(); ok {
 		_spec.SetField(group.FieldClaudeCodeOnly, field.TypeBool, value)
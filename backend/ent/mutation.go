@@ -38,6 +38,7 @@ import (
 	"github.com/Wei-Shaw/sub2api/ent/userattributevalue"
 	"github.com/Wei-Shaw/sub2api/ent/usersubscription"
 	"github.com/Wei-Shaw/sub2api/internal/domain"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
 )
 
 const (
@@ -78,51 +79,63 @@ const (
 // APIKeyMutation represents an operation that mutates the APIKey nodes in the graph.
 type APIKeyMutation struct {
 	config
-	op                 Op
-	typ                string
-	id                 *int64
-	created_at         *time.Time
-	updated_at         *time.Time
-	deleted_at         *time.Time
-	key                *string
-	name               *string
-	status             *string
-	last_used_at       *time.Time
-	ip_whitelist       *[]string
-	appendip_whitelist []string
-	ip_blacklist       *[]string
-	appendip_blacklist []string
-	quota              *float64
-	addquota           *float64
-	quota_used         *float64
-	addquota_used      *float64
-	expires_at         *time.Time
-	rate_limit_5h      *float64
-	addrate_limit_5h   *float64
-	rate_limit_1d      *float64
-	addrate_limit_1d   *float64
-	rate_limit_7d      *float64
-	addrate_limit_7d   *float64
-	usage_5h           *float64
-	addusage_5h        *float64
-	usage_1d           *float64
-	addusage_1d        *float64
-	usage_7d           *float64
-	addusage_7d        *float64
-	window_5h_start    *time.Time
-	window_1d_start    *time.Time
-	window_7d_start    *time.Time
-	clearedFields      map[string]struct{}
-	user               *int64
-	cleareduser        bool
-	group              *int64
-	clearedgroup       bool
-	usage_logs         map[int64]struct{}
-	removedusage_logs  map[int64]struct{}
-	clearedusage_logs  bool
-	done               bool
-	oldValue           func(context.Context) (*APIKey, error)
-	predicates         []predicate.APIKey
+	op                    Op
+	typ                   string
+	id                    *int64
+	created_at            *time.Time
+	updated_at            *time.Time
+	deleted_at            *time.Time
+	key                   *string
+	name                  *string
+	status                *string
+	last_used_at          *time.Time
+	ip_whitelist          *[]string
+	appendip_whitelist    []string
+	ip_blacklist          *[]string
+	appendip_blacklist    []string
+	model_allowlist       *[]string
+	appendmodel_allowlist []string
+	model_denylist        *[]string
+	appendmodel_denylist  []string
+	quota                 *money.Amount
+	addquota              *money.Amount
+	quota_used            *money.Amount
+	addquota_used         *money.Amount
+	expires_at            *time.Time
+	rate_limit_5h         *money.Amount
+	addrate_limit_5h      *money.Amount
+	rate_limit_1d         *money.Amount
+	addrate_limit_1d      *money.Amount
+	rate_limit_7d         *money.Amount
+	addrate_limit_7d      *money.Amount
+	usage_5h              *money.Amount
+	addusage_5h           *money.Amount
+	usage_1d              *money.Amount
+	addusage_1d           *money.Amount
+	usage_7d              *money.Amount
+	addusage_7d           *money.Amount
+	window_5h_start       *time.Time
+	window_1d_start       *time.Time
+	window_7d_start       *time.Time
+	rpm_limit             *int
+	addrpm_limit          *int
+	tpm_limit             *int
+	addtpm_limit          *int
+	max_concurrency       *int
+	addmax_concurrency    *int
+	created_by_user_id    *int64
+	addcreated_by_user_id *int64
+	clearedFields         map[string]struct{}
+	user                  *int64
+	cleareduser           bool
+	group                 *int64
+	clearedgroup          bool
+	usage_logs            map[int64]struct{}
+	removedusage_logs     map[int64]struct{}
+	clearedusage_logs     bool
+	done                  bool
+	oldValue              func(context.Context) (*APIKey, error)
+	predicates            []predicate.APIKey
 }
 
 var _ ent.Mutation = (*APIKeyMutation)(nil)
@@ -716,14 +729,144 @@ func (m *APIKeyMutation) ResetIPBlacklist() {
 	delete(m.clearedFields, apikey.FieldIPBlacklist)
 }
 
+// SetModelAllowlist sets the "model_allowlist" field.
+func (m *APIKeyMutation) SetModelAllowlist(s []string) {
+	m.model_allowlist = &s
+	m.appendmodel_allowlist = nil
+}
+
+// ModelAllowlist returns the value of the "model_allowlist" field in the mutation.
+func (m *APIKeyMutation) ModelAllowlist() (r []string, exists bool) {
+	v := m.model_allowlist
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldModelAllowlist returns the old "model_allowlist" field's value of the APIKey entity.
+// If the APIKey object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *APIKeyMutation) OldModelAllowlist(ctx context.Context) (v []string, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldModelAllowlist is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldModelAllowlist requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldModelAllowlist: %w", err)
+	}
+	return oldValue.ModelAllowlist, nil
+}
+
+// AppendModelAllowlist adds s to the "model_allowlist" field.
+func (m *APIKeyMutation) AppendModelAllowlist(s []string) {
+	m.appendmodel_allowlist = append(m.appendmodel_allowlist, s...)
+}
+
+// AppendedModelAllowlist returns the list of values that were appended to the "model_allowlist" field in this mutation.
+func (m *APIKeyMutation) AppendedModelAllowlist() ([]string, bool) {
+	if len(m.appendmodel_allowlist) == 0 {
+		return nil, false
+	}
+	return m.appendmodel_allowlist, true
+}
+
+// ClearModelAllowlist clears the value of the "model_allowlist" field.
+func (m *APIKeyMutation) ClearModelAllowlist() {
+	m.model_allowlist = nil
+	m.appendmodel_allowlist = nil
+	m.clearedFields[apikey.FieldModelAllowlist] = struct{}{}
+}
+
+// ModelAllowlistCleared returns if the "model_allowlist" field was cleared in this mutation.
+func (m *APIKeyMutation) ModelAllowlistCleared() bool {
+	_, ok := m.clearedFields[apikey.FieldModelAllowlist]
+	return ok
+}
+
+// ResetModelAllowlist resets all changes to the "model_allowlist" field.
+func (m *APIKeyMutation) ResetModelAllowlist() {
+	m.model_allowlist = nil
+	m.appendmodel_allowlist = nil
+	delete(m.clearedFields, apikey.FieldModelAllowlist)
+}
+
+// SetModelDenylist sets the "model_denylist" field.
+func (m *APIKeyMutation) SetModelDenylist(s []string) {
+	m.model_denylist = &s
+	m.appendmodel_denylist = nil
+}
+
+// ModelDenylist returns the value of the "model_denylist" field in the mutation.
+func (m *APIKeyMutation) ModelDenylist() (r []string, exists bool) {
+	v := m.model_denylist
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldModelDenylist returns the old "model_denylist" field's value of the APIKey entity.
+// If the APIKey object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *APIKeyMutation) OldModelDenylist(ctx context.Context) (v []string, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldModelDenylist is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldModelDenylist requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldModelDenylist: %w", err)
+	}
+	return oldValue.ModelDenylist, nil
+}
+
+// AppendModelDenylist adds s to the "model_denylist" field.
+func (m *APIKeyMutation) AppendModelDenylist(s []string) {
+	m.appendmodel_denylist = append(m.appendmodel_denylist, s...)
+}
+
+// AppendedModelDenylist returns the list of values that were appended to the "model_denylist" field in this mutation.
+func (m *APIKeyMutation) AppendedModelDenylist() ([]string, bool) {
+	if len(m.appendmodel_denylist) == 0 {
+		return nil, false
+	}
+	return m.appendmodel_denylist, true
+}
+
+// ClearModelDenylist clears the value of the "model_denylist" field.
+func (m *APIKeyMutation) ClearModelDenylist() {
+	m.model_denylist = nil
+	m.appendmodel_denylist = nil
+	m.clearedFields[apikey.FieldModelDenylist] = struct{}{}
+}
+
+// ModelDenylistCleared returns if the "model_denylist" field was cleared in this mutation.
+func (m *APIKeyMutation) ModelDenylistCleared() bool {
+	_, ok := m.clearedFields[apikey.FieldModelDenylist]
+	return ok
+}
+
+// ResetModelDenylist resets all changes to the "model_denylist" field.
+func (m *APIKeyMutation) ResetModelDenylist() {
+	m.model_denylist = nil
+	m.appendmodel_denylist = nil
+	delete(m.clearedFields, apikey.FieldModelDenylist)
+}
+
 // SetQuota sets the "quota" field.
-func (m *APIKeyMutation) SetQuota(f float64) {
-	m.quota = &f
+func (m *APIKeyMutation) SetQuota(value money.Amount) {
+	m.quota = &value
 	m.addquota = nil
 }
 
 // Quota returns the value of the "quota" field in the mutation.
-func (m *APIKeyMutation) Quota() (r float64, exists bool) {
+func (m *APIKeyMutation) Quota() (r money.Amount, exists bool) {
 	v := m.quota
 	if v == nil {
 		return
@@ -734,7 +877,7 @@ func (m *APIKeyMutation) Quota() (r float64, exists bool) {
 // OldQuota returns the old "quota" field's value of the APIKey entity.
 // If the APIKey object wasn't provided to the builder, the object is fetched from the database.
 // An error is returned if the mutation operation is not UpdateOne, or the database query fails.
-func (m *APIKeyMutation) OldQuota(ctx context.Context) (v float64, err error) {
+func (m *APIKeyMutation) OldQuota(ctx context.Context) (v money.Amount, err error) {
 	if !m.op.Is(OpUpdateOne) {
 		return v, errors.New("OldQuota is only allowed on UpdateOne operations")
 	}
@@ -748,17 +891,17 @@ func (m *APIKeyMutation) OldQuota(ctx context.Context) (v float64, err error) {
 	return oldValue.Quota, nil
 }
 
-// AddQuota adds f to the "quota" field.
-func (m *APIKeyMutation) AddQuota(f float64) {
+// AddQuota adds value to the "quota" field.
+func (m *APIKeyMutation) AddQuota(value money.Amount) {
 	if m.addquota != nil {
-		*m.addquota += f
+		*m.addquota += value
 	} else {
-		m.addquota = &f
+		m.addquota = &value
 	}
 }
 
 // AddedQuota returns the value that was added to the "quota" field in this mutation.
-func (m *APIKeyMutation) AddedQuota() (r float64, exists bool) {
+func (m *APIKeyMutation) AddedQuota() (r money.Amount, exists bool) {
 	v := m.addquota
 	if v == nil {
 		return
@@ -773,13 +916,13 @@ func (m *APIKeyMutation) ResetQuota() {
 }
 
 // SetQuotaUsed sets the "quota_used" field.
-func (m *APIKeyMutation) SetQuotaUsed(f float64) {
-	m.quota_used = &f
+func (m *APIKeyMutation) SetQuotaUsed(value money.Amount) {
+	m.quota_used = &value
 	m.addquota_used = nil
 }
 
 // QuotaUsed returns the value of the "quota_used" field in the mutation.
-func (m *APIKeyMutation) QuotaUsed() (r float64, exists bool) {
+func (m *APIKeyMutation) QuotaUsed() (r money.Amount, exists bool) {
 	v := m.quota_used
 	if v == nil {
 		return
@@ -790,7 +933,7 @@ func (m *APIKeyMutation) QuotaUsed() (r float64, exists bool) {
 // OldQuotaUsed returns the old "quota_used" field's value of the APIKey entity.
 // If the APIKey object wasn't provided to the builder, the object is fetched from the database.
 // An error is returned if the mutation operation is not UpdateOne, or the database query fails.
-func (m *APIKeyMutation) OldQuotaUsed(ctx context.Context) (v float64, err error) {
+func (m *APIKeyMutation) OldQuotaUsed(ctx context.Context) (v money.Amount, err error) {
 	if !m.op.Is(OpUpdateOne) {
 		return v, errors.New("OldQuotaUsed is only allowed on UpdateOne operations")
 	}
@@ -804,17 +947,17 @@ func (m *APIKeyMutation) OldQuotaUsed(ctx context.Context) (v float64, err error
 	return oldValue.QuotaUsed, nil
 }
 
-// AddQuotaUsed adds f to the "quota_used" field.
-func (m *APIKeyMutation) AddQuotaUsed(f float64) {
+// AddQuotaUsed adds value to the "quota_used" field.
+func (m *APIKeyMutation) AddQuotaUsed(value money.Amount) {
 	if m.addquota_used != nil {
-		*m.addquota_used += f
+		*m.addquota_used += value
 	} else {
-		m.addquota_used = &f
+		m.addquota_used = &value
 	}
 }
 
 // AddedQuotaUsed returns the value that was added to the "quota_used" field in this mutation.
-func (m *APIKeyMutation) AddedQuotaUsed() (r float64, exists bool) {
+func (m *APIKeyMutation) AddedQuotaUsed() (r money.Amount, exists bool) {
 	v := m.addquota_used
 	if v == nil {
 		return
@@ -878,13 +1021,13 @@ func (m *APIKeyMutation) ResetExpiresAt() {
 }
 
 // SetRateLimit5h sets the "rate_limit_5h" field.
-func (m *APIKeyMutation) SetRateLimit5h(f float64) {
-	m.rate_limit_5h = &f
+func (m *APIKeyMutation) SetRateLimit5h(value money.Amount) {
+	m.rate_limit_5h = &value
 	m.addrate_limit_5h = nil
 }
 
 // RateLimit5h returns the value of the "rate_limit_5h" field in the mutation.
-func (m *APIKeyMutation) RateLimit5h() (r float64, exists bool) {
+func (m *APIKeyMutation) RateLimit5h() (r money.Amount, exists bool) {
 	v := m.rate_limit_5h
 	if v == nil {
 		return
@@ -895,7 +1038,7 @@ func (m *APIKeyMutation) RateLimit5h() (r float64, exists bool) {
 // OldRateLimit5h returns the old "rate_limit_5h" field's value of the APIKey entity.
 // If the APIKey object wasn't provided to the builder, the object is fetched from the database.
 // An error is returned if the mutation operation is not UpdateOne, or the database query fails.
-func (m *APIKeyMutation) OldRateLimit5h(ctx context.Context) (v float64, err error) {
+func (m *APIKeyMutation) OldRateLimit5h(ctx context.Context) (v money.Amount, err error) {
 	if !m.op.Is(OpUpdateOne) {
 		return v, errors.New("OldRateLimit5h is only allowed on UpdateOne operations")
 	}
@@ -909,17 +1052,17 @@ func (m *APIKeyMutation) OldRateLimit5h(ctx context.Context) (v float64, err err
 	return oldValue.RateLimit5h, nil
 }
 
-// AddRateLimit5h adds f to the "rate_limit_5h" field.
-func (m *APIKeyMutation) AddRateLimit5h(f float64) {
+// AddRateLimit5h adds value to the "rate_limit_5h" field.
+func (m *APIKeyMutation) AddRateLimit5h(value money.Amount) {
 	if m.addrate_limit_5h != nil {
-		*m.addrate_limit_5h += f
+		*m.addrate_limit_5h += value
 	} else {
-		m.addrate_limit_5h = &f
+		m.addrate_limit_5h = &value
 	}
 }
 
 // AddedRateLimit5h returns the value that was added to the "rate_limit_5h" field in this mutation.
-func (m *APIKeyMutation) AddedRateLimit5h() (r float64, exists bool) {
+func (m *APIKeyMutation) AddedRateLimit5h() (r money.Amount, exists bool) {
 	v := m.addrate_limit_5h
 	if v == nil {
 		return
@@ -934,13 +1077,13 @@ func (m *APIKeyMutation) ResetRateLimit5h() {
 }
 
 // SetRateLimit1d sets the "rate_limit_1d" field.
-func (m *APIKeyMutation) SetRateLimit1d(f float64) {
-	m.rate_limit_1d = &f
+func (m *APIKeyMutation) SetRateLimit1d(value money.Amount) {
+	m.rate_limit_1d = &value
 	m.addrate_limit_1d = nil
 }
 
 // RateLimit1d returns the value of the "rate_limit_1d" field in the mutation.
-func (m *APIKeyMutation) RateLimit1d() (r float64, exists bool) {
+func (m *APIKeyMutation) RateLimit1d() (r money.Amount, exists bool) {
 	v := m.rate_limit_1d
 	if v == nil {
 		return
@@ -951,7 +1094,7 @@ func (m *APIKeyMutation) RateLimit1d() (r float64, exists bool) {
 // OldRateLimit1d returns the old "rate_limit_1d" field's value of the APIKey entity.
 // If the APIKey object wasn't provided to the builder, the object is fetched from the database.
 // An error is returned if the mutation operation is not UpdateOne, or the database query fails.
-func (m *APIKeyMutation) OldRateLimit1d(ctx context.Context) (v float64, err error) {
+func (m *APIKeyMutation) OldRateLimit1d(ctx context.Context) (v money.Amount, err error) {
 	if !m.op.Is(OpUpdateOne) {
 		return v, errors.New("OldRateLimit1d is only allowed on UpdateOne operations")
 	}
@@ -965,17 +1108,17 @@ func (m *APIKeyMutation) OldRateLimit1d(ctx context.Context) (v float64, err err
 	return oldValue.RateLimit1d, nil
 }
 
-// AddRateLimit1d adds f to the "rate_limit_1d" field.
-func (m *APIKeyMutation) AddRateLimit1d(f float64) {
+// AddRateLimit1d adds value to the "rate_limit_1d" field.
+func (m *APIKeyMutation) AddRateLimit1d(value money.Amount) {
 	if m.addrate_limit_1d != nil {
-		*m.addrate_limit_1d += f
+		*m.addrate_limit_1d += value
 	} else {
-		m.addrate_limit_1d = &f
+		m.addrate_limit_1d = &value
 	}
 }
 
 // AddedRateLimit1d returns the value that was added to the "rate_limit_1d" field in this mutation.
-func (m *APIKeyMutation) AddedRateLimit1d() (r float64, exists bool) {
+func (m *APIKeyMutation) AddedRateLimit1d() (r money.Amount, exists bool) {
 	v := m.addrate_limit_1d
 	if v == nil {
 		return
@@ -990,13 +1133,13 @@ func (m *APIKeyMutation) ResetRateLimit1d() {
 }
 
 // SetRateLimit7d sets the "rate_limit_7d" field.
-func (m *APIKeyMutation) SetRateLimit7d(f float64) {
-	m.rate_limit_7d = &f
+func (m *APIKeyMutation) SetRateLimit7d(value money.Amount) {
+	m.rate_limit_7d = &value
 	m.addrate_limit_7d = nil
 }
 
 // RateLimit7d returns the value of the "rate_limit_7d" field in the mutation.
-func (m *APIKeyMutation) RateLimit7d() (r float64, exists bool) {
+func (m *APIKeyMutation) RateLimit7d() (r money.Amount, exists bool) {
 	v := m.rate_limit_7d
 	if v == nil {
 		return
@@ -1007,7 +1150,7 @@ func (m *APIKeyMutation) RateLimit7d() (r float64, exists bool) {
 // OldRateLimit7d returns the old "rate_limit_7d" field's value of the APIKey entity.
 // If the APIKey object wasn't provided to the builder, the object is fetched from the database.
 // An error is returned if the mutation operation is not UpdateOne, or the database query fails.
-func (m *APIKeyMutation) OldRateLimit7d(ctx context.Context) (v float64, err error) {
+func (m *APIKeyMutation) OldRateLimit7d(ctx context.Context) (v money.Amount, err error) {
 	if !m.op.Is(OpUpdateOne) {
 		return v, errors.New("OldRateLimit7d is only allowed on UpdateOne operations")
 	}
@@ -1021,17 +1164,17 @@ func (m *APIKeyMutation) OldRateLimit7d(ctx context.Context) (v float64, err err
 	return oldValue.RateLimit7d, nil
 }
 
-// AddRateLimit7d adds f to the "rate_limit_7d" field.
-func (m *APIKeyMutation) AddRateLimit7d(f float64) {
+// AddRateLimit7d adds value to the "rate_limit_7d" field.
+func (m *APIKeyMutation) AddRateLimit7d(value money.Amount) {
 	if m.addrate_limit_7d != nil {
-		*m.addrate_limit_7d += f
+		*m.addrate_limit_7d += value
 	} else {
-		m.addrate_limit_7d = &f
+		m.addrate_limit_7d = &value
 	}
 }
 
 // AddedRateLimit7d returns the value that was added to the "rate_limit_7d" field in this mutation.
-func (m *APIKeyMutation) AddedRateLimit7d() (r float64, exists bool) {
+func (m *APIKeyMutation) AddedRateLimit7d() (r money.Amount, exists bool) {
 	v := m.addrate_limit_7d
 	if v == nil {
 		return
@@ -1046,13 +1189,13 @@ func (m *APIKeyMutation) ResetRateLimit7d() {
 }
 
 // SetUsage5h sets the "usage_5h" field.
-func (m *APIKeyMutation) SetUsage5h(f float64) {
-	m.usage_5h = &f
+func (m *APIKeyMutation) SetUsage5h(value money.Amount) {
+	m.usage_5h = &value
 	m.addusage_5h = nil
 }
 
 // Usage5h returns the value of the "usage_5h" field in the mutation.
-func (m *APIKeyMutation) Usage5h() (r float64, exists bool) {
+func (m *APIKeyMutation) Usage5h() (r money.Amount, exists bool) {
 	v := m.usage_5h
 	if v == nil {
 		return
@@ -1063,7 +1206,7 @@ func (m *APIKeyMutation) Usage5h() (r float64, exists bool) {
 // OldUsage5h returns the old "usage_5h" field's value of the APIKey entity.
 // If the APIKey object wasn't provided to the builder, the object is fetched from the database.
 // An error is returned if the mutation operation is not UpdateOne, or the database query fails.
-func (m *APIKeyMutation) OldUsage5h(ctx context.Context) (v float64, err error) {
+func (m *APIKeyMutation) OldUsage5h(ctx context.Context) (v money.Amount, err error) {
 	if !m.op.Is(OpUpdateOne) {
 		return v, errors.New("OldUsage5h is only allowed on UpdateOne operations")
 	}
@@ -1077,17 +1220,17 @@ func (m *APIKeyMutation) OldUsage5h(ctx context.Context) (v float64, err error)
 	return oldValue.Usage5h, nil
 }
 
-// AddUsage5h adds f to the "usage_5h" field.
-func (m *APIKeyMutation) AddUsage5h(f float64) {
+// AddUsage5h adds value to the "usage_5h" field.
+func (m *APIKeyMutation) AddUsage5h(value money.Amount) {
 	if m.addusage_5h != nil {
-		*m.addusage_5h += f
+		*m.addusage_5h += value
 	} else {
-		m.addusage_5h = &f
+		m.addusage_5h = &value
 	}
 }
 
 // AddedUsage5h returns the value that was added to the "usage_5h" field in this mutation.
-func (m *APIKeyMutation) AddedUsage5h() (r float64, exists bool) {
+func (m *APIKeyMutation) AddedUsage5h() (r money.Amount, exists bool) {
 	v := m.addusage_5h
 	if v == nil {
 		return
@@ -1102,13 +1245,13 @@ func (m *APIKeyMutation) ResetUsage5h() {
 }
 
 // SetUsage1d sets the "usage_1d" field.
-func (m *APIKeyMutation) SetUsage1d(f float64) {
-	m.usage_1d = &f
+func (m *APIKeyMutation) SetUsage1d(value money.Amount) {
+	m.usage_1d = &value
 	m.addusage_1d = nil
 }
 
 // Usage1d returns the value of the "usage_1d" field in the mutation.
-func (m *APIKeyMutation) Usage1d() (r float64, exists bool) {
+func (m *APIKeyMutation) Usage1d() (r money.Amount, exists bool) {
 	v := m.usage_1d
 	if v == nil {
 		return
@@ -1119,7 +1262,7 @@ func (m *APIKeyMutation) Usage1d() (r float64, exists bool) {
 // OldUsage1d returns the old "usage_1d" field's value of the APIKey entity.
 // If the APIKey object wasn't provided to the builder, the object is fetched from the database.
 // An error is returned if the mutation operation is not UpdateOne, or the database query fails.
-func (m *APIKeyMutation) OldUsage1d(ctx context.Context) (v float64, err error) {
+func (m *APIKeyMutation) OldUsage1d(ctx context.Context) (v money.Amount, err error) {
 	if !m.op.Is(OpUpdateOne) {
 		return v, errors.New("OldUsage1d is only allowed on UpdateOne operations")
 	}
@@ -1133,17 +1276,17 @@ func (m *APIKeyMutation) OldUsage1d(ctx context.Context) (v float64, err error)
 	return oldValue.Usage1d, nil
 }
 
-// AddUsage1d adds f to the "usage_1d" field.
-func (m *APIKeyMutation) AddUsage1d(f float64) {
+// AddUsage1d adds value to the "usage_1d" field.
+func (m *APIKeyMutation) AddUsage1d(value money.Amount) {
 	if m.addusage_1d != nil {
-		*m.addusage_1d += f
+		*m.addusage_1d += value
 	} else {
-		m.addusage_1d = &f
+		m.addusage_1d = &value
 	}
 }
 
 // AddedUsage1d returns the value that was added to the "usage_1d" field in this mutation.
-func (m *APIKeyMutation) AddedUsage1d() (r float64, exists bool) {
+func (m *APIKeyMutation) AddedUsage1d() (r money.Amount, exists bool) {
 	v := m.addusage_1d
 	if v == nil {
 		return
@@ -1158,13 +1301,13 @@ func (m *APIKeyMutation) ResetUsage1d() {
 }
 
 // SetUsage7d sets the "usage_7d" field.
-func (m *APIKeyMutation) SetUsage7d(f float64) {
-	m.usage_7d = &f
+func (m *APIKeyMutation) SetUsage7d(value money.Amount) {
+	m.usage_7d = &value
 	m.addusage_7d = nil
 }
 
 // Usage7d returns the value of the "usage_7d" field in the mutation.
-func (m *APIKeyMutation) Usage7d() (r float64, exists bool) {
+func (m *APIKeyMutation) Usage7d() (r money.Amount, exists bool) {
 	v := m.usage_7d
 	if v == nil {
 		return
@@ -1175,7 +1318,7 @@ func (m *APIKeyMutation) Usage7d() (r float64, exists bool) {
 // OldUsage7d returns the old "usage_7d" field's value of the APIKey entity.
 // If the APIKey object wasn't provided to the builder, the object is fetched from the database.
 // An error is returned if the mutation operation is not UpdateOne, or the database query fails.
-func (m *APIKeyMutation) OldUsage7d(ctx context.Context) (v float64, err error) {
+func (m *APIKeyMutation) OldUsage7d(ctx context.Context) (v money.Amount, err error) {
 	if !m.op.Is(OpUpdateOne) {
 		return v, errors.New("OldUsage7d is only allowed on UpdateOne operations")
 	}
@@ -1189,17 +1332,17 @@ func (m *APIKeyMutation) OldUsage7d(ctx context.Context) (v float64, err error)
 	return oldValue.Usage7d, nil
 }
 
-// AddUsage7d adds f to the "usage_7d" field.
-func (m *APIKeyMutation) AddUsage7d(f float64) {
+// AddUsage7d adds value to the "usage_7d" field.
+func (m *APIKeyMutation) AddUsage7d(value money.Amount) {
 	if m.addusage_7d != nil {
-		*m.addusage_7d += f
+		*m.addusage_7d += value
 	} else {
-		m.addusage_7d = &f
+		m.addusage_7d = &value
 	}
 }
 
 // AddedUsage7d returns the value that was added to the "usage_7d" field in this mutation.
-func (m *APIKeyMutation) AddedUsage7d() (r float64, exists bool) {
+func (m *APIKeyMutation) AddedUsage7d() (r money.Amount, exists bool) {
 	v := m.addusage_7d
 	if v == nil {
 		return
@@ -1360,6 +1503,244 @@ func (m *APIKeyMutation) ResetWindow7dStart() {
 	delete(m.clearedFields, apikey.FieldWindow7dStart)
 }
 
+// SetRpmLimit sets the "rpm_limit" field.
+func (m *APIKeyMutation) SetRpmLimit(i int) {
+	m.rpm_limit = &i
+	m.addrpm_limit = nil
+}
+
+// RpmLimit returns the value of the "rpm_limit" field in the mutation.
+func (m *APIKeyMutation) RpmLimit() (r int, exists bool) {
+	v := m.rpm_limit
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldRpmLimit returns the old "rpm_limit" field's value of the APIKey entity.
+// If the APIKey object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *APIKeyMutation) OldRpmLimit(ctx context.Context) (v int, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldRpmLimit is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldRpmLimit requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldRpmLimit: %w", err)
+	}
+	return oldValue.RpmLimit, nil
+}
+
+// AddRpmLimit adds i to the "rpm_limit" field.
+func (m *APIKeyMutation) AddRpmLimit(i int) {
+	if m.addrpm_limit != nil {
+		*m.addrpm_limit += i
+	} else {
+		m.addrpm_limit = &i
+	}
+}
+
+// AddedRpmLimit returns the value that was added to the "rpm_limit" field in this mutation.
+func (m *APIKeyMutation) AddedRpmLimit() (r int, exists bool) {
+	v := m.addrpm_limit
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// ResetRpmLimit resets all changes to the "rpm_limit" field.
+func (m *APIKeyMutation) ResetRpmLimit() {
+	m.rpm_limit = nil
+	m.addrpm_limit = nil
+}
+
+// SetTpmLimit sets the "tpm_limit" field.
+func (m *APIKeyMutation) SetTpmLimit(i int) {
+	m.tpm_limit = &i
+	m.addtpm_limit = nil
+}
+
+// TpmLimit returns the value of the "tpm_limit" field in the mutation.
+func (m *APIKeyMutation) TpmLimit() (r int, exists bool) {
+	v := m.tpm_limit
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldTpmLimit returns the old "tpm_limit" field's value of the APIKey entity.
+// If the APIKey object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *APIKeyMutation) OldTpmLimit(ctx context.Context) (v int, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldTpmLimit is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldTpmLimit requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldTpmLimit: %w", err)
+	}
+	return oldValue.TpmLimit, nil
+}
+
+// AddTpmLimit adds i to the "tpm_limit" field.
+func (m *APIKeyMutation) AddTpmLimit(i int) {
+	if m.addtpm_limit != nil {
+		*m.addtpm_limit += i
+	} else {
+		m.addtpm_limit = &i
+	}
+}
+
+// AddedTpmLimit returns the value that was added to the "tpm_limit" field in this mutation.
+func (m *APIKeyMutation) AddedTpmLimit() (r int, exists bool) {
+	v := m.addtpm_limit
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// ResetTpmLimit resets all changes to the "tpm_limit" field.
+func (m *APIKeyMutation) ResetTpmLimit() {
+	m.tpm_limit = nil
+	m.addtpm_limit = nil
+}
+
+// SetMaxConcurrency sets the "max_concurrency" field.
+func (m *APIKeyMutation) SetMaxConcurrency(i int) {
+	m.max_concurrency = &i
+	m.addmax_concurrency = nil
+}
+
+// MaxConcurrency returns the value of the "max_concurrency" field in the mutation.
+func (m *APIKeyMutation) MaxConcurrency() (r int, exists bool) {
+	v := m.max_concurrency
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldMaxConcurrency returns the old "max_concurrency" field's value of the APIKey entity.
+// If the APIKey object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *APIKeyMutation) OldMaxConcurrency(ctx context.Context) (v int, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldMaxConcurrency is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldMaxConcurrency requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldMaxConcurrency: %w", err)
+	}
+	return oldValue.MaxConcurrency, nil
+}
+
+// AddMaxConcurrency adds i to the "max_concurrency" field.
+func (m *APIKeyMutation) AddMaxConcurrency(i int) {
+	if m.addmax_concurrency != nil {
+		*m.addmax_concurrency += i
+	} else {
+		m.addmax_concurrency = &i
+	}
+}
+
+// AddedMaxConcurrency returns the value that was added to the "max_concurrency" field in this mutation.
+func (m *APIKeyMutation) AddedMaxConcurrency() (r int, exists bool) {
+	v := m.addmax_concurrency
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// ResetMaxConcurrency resets all changes to the "max_concurrency" field.
+func (m *APIKeyMutation) ResetMaxConcurrency() {
+	m.max_concurrency = nil
+	m.addmax_concurrency = nil
+}
+
+// SetCreatedByUserID sets the "created_by_user_id" field.
+func (m *APIKeyMutation) SetCreatedByUserID(i int64) {
+	m.created_by_user_id = &i
+	m.addcreated_by_user_id = nil
+}
+
+// CreatedByUserID returns the value of the "created_by_user_id" field in the mutation.
+func (m *APIKeyMutation) CreatedByUserID() (r int64, exists bool) {
+	v := m.created_by_user_id
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldCreatedByUserID returns the old "created_by_user_id" field's value of the APIKey entity.
+// If the APIKey object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *APIKeyMutation) OldCreatedByUserID(ctx context.Context) (v *int64, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldCreatedByUserID is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldCreatedByUserID requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldCreatedByUserID: %w", err)
+	}
+	return oldValue.CreatedByUserID, nil
+}
+
+// AddCreatedByUserID adds i to the "created_by_user_id" field.
+func (m *APIKeyMutation) AddCreatedByUserID(i int64) {
+	if m.addcreated_by_user_id != nil {
+		*m.addcreated_by_user_id += i
+	} else {
+		m.addcreated_by_user_id = &i
+	}
+}
+
+// AddedCreatedByUserID returns the value that was added to the "created_by_user_id" field in this mutation.
+func (m *APIKeyMutation) AddedCreatedByUserID() (r int64, exists bool) {
+	v := m.addcreated_by_user_id
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// ClearCreatedByUserID clears the value of the "created_by_user_id" field.
+func (m *APIKeyMutation) ClearCreatedByUserID() {
+	m.created_by_user_id = nil
+	m.addcreated_by_user_id = nil
+	m.clearedFields[apikey.FieldCreatedByUserID] = struct{}{}
+}
+
+// CreatedByUserIDCleared returns if the "created_by_user_id" field was cleared in this mutation.
+func (m *APIKeyMutation) CreatedByUserIDCleared() bool {
+	_, ok := m.clearedFields[apikey.FieldCreatedByUserID]
+	return ok
+}
+
+// ResetCreatedByUserID resets all changes to the "created_by_user_id" field.
+func (m *APIKeyMutation) ResetCreatedByUserID() {
+	m.created_by_user_id = nil
+	m.addcreated_by_user_id = nil
+	delete(m.clearedFields, apikey.FieldCreatedByUserID)
+}
+
 // ClearUser clears the "user" edge to the User entity.
 func (m *APIKeyMutation) ClearUser() {
 	m.cleareduser = true
@@ -1502,7 +1883,7 @@ func (m *APIKeyMutation) Type() string {
 // order to get all numeric fields that were incremented/decremented, call
 // AddedFields().
 func (m *APIKeyMutation) Fields() []string {
-	fields := make([]string, 0, 23)
+	fields := make([]string, 0, 29)
 	if m.created_at != nil {
 		fields = append(fields, apikey.FieldCreatedAt)
 	}
@@ -1536,6 +1917,12 @@ func (m *APIKeyMutation) Fields() []string {
 	if m.ip_blacklist != nil {
 		fields = append(fields, apikey.FieldIPBlacklist)
 	}
+	if m.model_allowlist != nil {
+		fields = append(fields, apikey.FieldModelAllowlist)
+	}
+	if m.model_denylist != nil {
+		fields = append(fields, apikey.FieldModelDenylist)
+	}
 	if m.quota != nil {
 		fields = append(fields, apikey.FieldQuota)
 	}
@@ -1572,6 +1959,18 @@ func (m *APIKeyMutation) Fields() []string {
 	if m.window_7d_start != nil {
 		fields = append(fields, apikey.FieldWindow7dStart)
 	}
+	if m.rpm_limit != nil {
+		fields = append(fields, apikey.FieldRpmLimit)
+	}
+	if m.tpm_limit != nil {
+		fields = append(fields, apikey.FieldTpmLimit)
+	}
+	if m.max_concurrency != nil {
+		fields = append(fields, apikey.FieldMaxConcurrency)
+	}
+	if m.created_by_user_id != nil {
+		fields = append(fields, apikey.FieldCreatedByUserID)
+	}
 	return fields
 }
 
@@ -1602,6 +2001,10 @@ func (m *APIKeyMutation) Field(name string) (ent.Value, bool) {
 		return m.IPWhitelist()
 	case apikey.FieldIPBlacklist:
 		return m.IPBlacklist()
+	case apikey.FieldModelAllowlist:
+		return m.ModelAllowlist()
+	case apikey.FieldModelDenylist:
+		return m.ModelDenylist()
 	case apikey.FieldQuota:
 		return m.Quota()
 	case apikey.FieldQuotaUsed:
@@ -1626,6 +2029,14 @@ func (m *APIKeyMutation) Field(name string) (ent.Value, bool) {
 		return m.Window1dStart()
 	case apikey.FieldWindow7dStart:
 		return m.Window7dStart()
+	case apikey.FieldRpmLimit:
+		return m.RpmLimit()
+	case apikey.FieldTpmLimit:
+		return m.TpmLimit()
+	case apikey.FieldMaxConcurrency:
+		return m.MaxConcurrency()
+	case apikey.FieldCreatedByUserID:
+		return m.CreatedByUserID()
 	}
 	return nil, false
 }
@@ -1657,6 +2068,10 @@ func (m *APIKeyMutation) OldField(ctx context.Context, name string) (ent.Value,
 		return m.OldIPWhitelist(ctx)
 	case apikey.FieldIPBlacklist:
 		return m.OldIPBlacklist(ctx)
+	case apikey.FieldModelAllowlist:
+		return m.OldModelAllowlist(ctx)
+	case apikey.FieldModelDenylist:
+		return m.OldModelDenylist(ctx)
 	case apikey.FieldQuota:
 		return m.OldQuota(ctx)
 	case apikey.FieldQuotaUsed:
@@ -1681,6 +2096,14 @@ func (m *APIKeyMutation) OldField(ctx context.Context, name string) (ent.Value,
 		return m.OldWindow1dStart(ctx)
 	case apikey.FieldWindow7dStart:
 		return m.OldWindow7dStart(ctx)
+	case apikey.FieldRpmLimit:
+		return m.OldRpmLimit(ctx)
+	case apikey.FieldTpmLimit:
+		return m.OldTpmLimit(ctx)
+	case apikey.FieldMaxConcurrency:
+		return m.OldMaxConcurrency(ctx)
+	case apikey.FieldCreatedByUserID:
+		return m.OldCreatedByUserID(ctx)
 	}
 	return nil, fmt.Errorf("unknown APIKey field %s", name)
 }
@@ -1767,15 +2190,29 @@ func (m *APIKeyMutation) SetField(name string, value ent.Value) error {
 		}
 		m.SetIPBlacklist(v)
 		return nil
+	case apikey.FieldModelAllowlist:
+		v, ok := value.([]string)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetModelAllowlist(v)
+		return nil
+	case apikey.FieldModelDenylist:
+		v, ok := value.([]string)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetModelDenylist(v)
+		return nil
 	case apikey.FieldQuota:
-		v, ok := value.(float64)
+		v, ok := value.(money.Amount)
 		if !ok {
 			return fmt.Errorf("unexpected type %T for field %s", value, name)
 		}
 		m.SetQuota(v)
 		return nil
 	case apikey.FieldQuotaUsed:
-		v, ok := value.(float64)
+		v, ok := value.(money.Amount)
 		if !ok {
 			return fmt.Errorf("unexpected type %T for field %s", value, name)
 		}
@@ -1789,42 +2226,42 @@ func (m *APIKeyMutation) SetField(name string, value ent.Value) error {
 		m.SetExpiresAt(v)
 		return nil
 	case apikey.FieldRateLimit5h:
-		v, ok := value.(float64)
+		v, ok := value.(money.Amount)
 		if !ok {
 			return fmt.Errorf("unexpected type %T for field %s", value, name)
 		}
 		m.SetRateLimit5h(v)
 		return nil
 	case apikey.FieldRateLimit1d:
-		v, ok := value.(float64)
+		v, ok := value.(money.Amount)
 		if !ok {
 			return fmt.Errorf("unexpected type %T for field %s", value, name)
 		}
 		m.SetRateLimit1d(v)
 		return nil
 	case apikey.FieldRateLimit7d:
-		v, ok := value.(float64)
+		v, ok := value.(money.Amount)
 		if !ok {
 			return fmt.Errorf("unexpected type %T for field %s", value, name)
 		}
 		m.SetRateLimit7d(v)
 		return nil
 	case apikey.FieldUsage5h:
-		v, ok := value.(float64)
+		v, ok := value.(money.Amount)
 		if !ok {
 			return fmt.Errorf("unexpected type %T for field %s", value, name)
 		}
 		m.SetUsage5h(v)
 		return nil
 	case apikey.FieldUsage1d:
-		v, ok := value.(float64)
+		v, ok := value.(money.Amount)
 		if !ok {
 			return fmt.Errorf("unexpected type %T for field %s", value, name)
 		}
 		m.SetUsage1d(v)
 		return nil
 	case apikey.FieldUsage7d:
-		v, ok := value.(float64)
+		v, ok := value.(money.Amount)
 		if !ok {
 			return fmt.Errorf("unexpected type %T for field %s", value, name)
 		}
@@ -1851,6 +2288,34 @@ func (m *APIKeyMutation) SetField(name string, value ent.Value) error {
 		}
 		m.SetWindow7dStart(v)
 		return nil
+	case apikey.FieldRpmLimit:
+		v, ok := value.(int)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetRpmLimit(v)
+		return nil
+	case apikey.FieldTpmLimit:
+		v, ok := value.(int)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetTpmLimit(v)
+		return nil
+	case apikey.FieldMaxConcurrency:
+		v, ok := value.(int)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetMaxConcurrency(v)
+		return nil
+	case apikey.FieldCreatedByUserID:
+		v, ok := value.(int64)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetCreatedByUserID(v)
+		return nil
 	}
 	return fmt.Errorf("unknown APIKey field %s", name)
 }
@@ -1883,6 +2348,18 @@ func (m *APIKeyMutation) AddedFields() []string {
 	if m.addusage_7d != nil {
 		fields = append(fields, apikey.FieldUsage7d)
 	}
+	if m.addrpm_limit != nil {
+		fields = append(fields, apikey.FieldRpmLimit)
+	}
+	if m.addtpm_limit != nil {
+		fields = append(fields, apikey.FieldTpmLimit)
+	}
+	if m.addmax_concurrency != nil {
+		fields = append(fields, apikey.FieldMaxConcurrency)
+	}
+	if m.addcreated_by_user_id != nil {
+		fields = append(fields, apikey.FieldCreatedByUserID)
+	}
 	return fields
 }
 
@@ -1907,6 +2384,14 @@ func (m *APIKeyMutation) AddedField(name string) (ent.Value, bool) {
 		return m.AddedUsage1d()
 	case apikey.FieldUsage7d:
 		return m.AddedUsage7d()
+	case apikey.FieldRpmLimit:
+		return m.AddedRpmLimit()
+	case apikey.FieldTpmLimit:
+		return m.AddedTpmLimit()
+	case apikey.FieldMaxConcurrency:
+		return m.AddedMaxConcurrency()
+	case apikey.FieldCreatedByUserID:
+		return m.AddedCreatedByUserID()
 	}
 	return nil, false
 }
@@ -1917,61 +2402,89 @@ func (m *APIKeyMutation) AddedField(name string) (ent.Value, bool) {
 func (m *APIKeyMutation) AddField(name string, value ent.Value) error {
 	switch name {
 	case apikey.FieldQuota:
-		v, ok := value.(float64)
+		v, ok := value.(money.Amount)
 		if !ok {
 			return fmt.Errorf("unexpected type %T for field %s", value, name)
 		}
 		m.AddQuota(v)
 		return nil
 	case apikey.FieldQuotaUsed:
-		v, ok := value.(float64)
+		v, ok := value.(money.Amount)
 		if !ok {
 			return fmt.Errorf("unexpected type %T for field %s", value, name)
 		}
 		m.AddQuotaUsed(v)
 		return nil
 	case apikey.FieldRateLimit5h:
-		v, ok := value.(float64)
+		v, ok := value.(money.Amount)
 		if !ok {
 			return fmt.Errorf("unexpected type %T for field %s", value, name)
 		}
 		m.AddRateLimit5h(v)
 		return nil
 	case apikey.FieldRateLimit1d:
-		v, ok := value.(float64)
+		v, ok := value.(money.Amount)
 		if !ok {
 			return fmt.Errorf("unexpected type %T for field %s", value, name)
 		}
 		m.AddRateLimit1d(v)
 		return nil
 	case apikey.FieldRateLimit7d:
-		v, ok := value.(float64)
+		v, ok := value.(money.Amount)
 		if !ok {
 			return fmt.Errorf("unexpected type %T for field %s", value, name)
 		}
 		m.AddRateLimit7d(v)
 		return nil
 	case apikey.FieldUsage5h:
-		v, ok := value.(float64)
+		v, ok := value.(money.Amount)
 		if !ok {
 			return fmt.Errorf("unexpected type %T for field %s", value, name)
 		}
 		m.AddUsage5h(v)
 		return nil
 	case apikey.FieldUsage1d:
-		v, ok := value.(float64)
+		v, ok := value.(money.Amount)
 		if !ok {
 			return fmt.Errorf("unexpected type %T for field %s", value, name)
 		}
 		m.AddUsage1d(v)
 		return nil
 	case apikey.FieldUsage7d:
-		v, ok := value.(float64)
+		v, ok := value.(money.Amount)
 		if !ok {
 			return fmt.Errorf("unexpected type %T for field %s", value, name)
 		}
 		m.AddUsage7d(v)
 		return nil
+	case apikey.FieldRpmLimit:
+		v, ok := value.(int)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.AddRpmLimit(v)
+		return nil
+	case apikey.FieldTpmLimit:
+		v, ok := value.(int)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.AddTpmLimit(v)
+		return nil
+	case apikey.FieldMaxConcurrency:
+		v, ok := value.(int)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.AddMaxConcurrency(v)
+		return nil
+	case apikey.FieldCreatedByUserID:
+		v, ok := value.(int64)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.AddCreatedByUserID(v)
+		return nil
 	}
 	return fmt.Errorf("unknown APIKey numeric field %s", name)
 }
@@ -1995,6 +2508,12 @@ func (m *APIKeyMutation) ClearedFields() []string {
 	if m.FieldCleared(apikey.FieldIPBlacklist) {
 		fields = append(fields, apikey.FieldIPBlacklist)
 	}
+	if m.FieldCleared(apikey.FieldModelAllowlist) {
+		fields = append(fields, apikey.FieldModelAllowlist)
+	}
+	if m.FieldCleared(apikey.FieldModelDenylist) {
+		fields = append(fields, apikey.FieldModelDenylist)
+	}
 	if m.FieldCleared(apikey.FieldExpiresAt) {
 		fields = append(fields, apikey.FieldExpiresAt)
 	}
@@ -2007,6 +2526,9 @@ func (m *APIKeyMutation) ClearedFields() []string {
 	if m.FieldCleared(apikey.FieldWindow7dStart) {
 		fields = append(fields, apikey.FieldWindow7dStart)
 	}
+	if m.FieldCleared(apikey.FieldCreatedByUserID) {
+		fields = append(fields, apikey.FieldCreatedByUserID)
+	}
 	return fields
 }
 
@@ -2036,6 +2558,12 @@ func (m *APIKeyMutation) ClearField(name string) error {
 	case apikey.FieldIPBlacklist:
 		m.ClearIPBlacklist()
 		return nil
+	case apikey.FieldModelAllowlist:
+		m.ClearModelAllowlist()
+		return nil
+	case apikey.FieldModelDenylist:
+		m.ClearModelDenylist()
+		return nil
 	case apikey.FieldExpiresAt:
 		m.ClearExpiresAt()
 		return nil
@@ -2048,6 +2576,9 @@ func (m *APIKeyMutation) ClearField(name string) error {
 	case apikey.FieldWindow7dStart:
 		m.ClearWindow7dStart()
 		return nil
+	case apikey.FieldCreatedByUserID:
+		m.ClearCreatedByUserID()
+		return nil
 	}
 	return fmt.Errorf("unknown APIKey nullable field %s", name)
 }
@@ -2089,6 +2620,12 @@ func (m *APIKeyMutation) ResetField(name string) error {
 	case apikey.FieldIPBlacklist:
 		m.ResetIPBlacklist()
 		return nil
+	case apikey.FieldModelAllowlist:
+		m.ResetModelAllowlist()
+		return nil
+	case apikey.FieldModelDenylist:
+		m.ResetModelDenylist()
+		return nil
 	case apikey.FieldQuota:
 		m.ResetQuota()
 		return nil
@@ -2125,6 +2662,18 @@ func (m *APIKeyMutation) ResetField(name string) error {
 	case apikey.FieldWindow7dStart:
 		m.ResetWindow7dStart()
 		return nil
+	case apikey.FieldRpmLimit:
+		m.ResetRpmLimit()
+		return nil
+	case apikey.FieldTpmLimit:
+		m.ResetTpmLimit()
+		return nil
+	case apikey.FieldMaxConcurrency:
+		m.ResetMaxConcurrency()
+		return nil
+	case apikey.FieldCreatedByUserID:
+		m.ResetCreatedByUserID()
+		return nil
 	}
 	return fmt.Errorf("unknown APIKey field %s", name)
 }
@@ -12756,34 +13305,38 @@ type GroupMutation struct {
 	status                                  *string
 	platform                                *string
 	subscription_type                       *string
-	daily_limit_usd                         *float64
-	adddaily_limit_usd                      *float64
-	weekly_limit_usd                        *float64
-	addweekly_limit_usd                     *float64
-	monthly_limit_usd                       *float64
-	addmonthly_limit_usd                    *float64
+	daily_limit_usd                         *money.Amount
+	adddaily_limit_usd                      *money.Amount
+	weekly_limit_usd                        *money.Amount
+	addweekly_limit_usd                     *money.Amount
+	monthly_limit_usd                       *money.Amount
+	addmonthly_limit_usd                    *money.Amount
 	default_validity_days                   *int
 	adddefault_validity_days                *int
-	image_price_1k                          *float64
-	addimage_price_1k                       *float64
-	image_price_2k                          *float64
-	addimage_price_2k                       *float64
-	image_price_4k                          *float64
-	addimage_price_4k                       *float64
-	sora_image_price_360                    *float64
-	addsora_image_price_360                 *float64
-	sora_image_price_540                    *float64
-	addsora_image_price_540                 *float64
-	sora_video_price_per_request            *float64
-	addsora_video_price_per_request         *float64
-	sora_video_price_per_request_hd         *float64
-	addsora_video_price_per_request_hd      *float64
+	image_price_1k                          *money.Amount
+	addimage_price_1k                       *money.Amount
+	image_price_2k                          *money.Amount
+	addimage_price_2k                       *money.Amount
+	image_price_4k                          *money.Amount
+	addimage_price_4k                       *money.Amount
+	sora_image_price_360                    *money.Amount
+	addsora_image_price_360                 *money.Amount
+	sora_image_price_540                    *money.Amount
+	addsora_image_price_540                 *money.Amount
+	sora_video_price_per_request            *money.Amount
+	addsora_video_price_per_request         *money.Amount
+	sora_video_price_per_request_hd         *money.Amount
+	addsora_video_price_per_request_hd      *money.Amount
 	sora_storage_quota_bytes                *int64
 	addsora_storage_quota_bytes             *int64
-	video_price_per_request                 *float64
-	addvideo_price_per_request              *float64
-	video_price_per_request_hd              *float64
-	addvideo_price_per_request_hd           *float64
+	video_price_per_request                 *money.Amount
+	addvideo_price_per_request              *money.Amount
+	video_price_per_request_hd              *money.Amount
+	addvideo_price_per_request_hd           *money.Amount
+	audio_price_per_minute                  *money.Amount
+	addaudio_price_per_minute               *money.Amount
+	audio_speech_price_per_1m_chars         *money.Amount
+	addaudio_speech_price_per_1m_chars      *money.Amount
 	claude_code_only                        *bool
 	fallback_group_id                       *int64
 	addfallback_group_id                    *int64
@@ -13325,13 +13878,13 @@ func (m *GroupMutation) ResetSubscriptionType() {
 }
 
 // SetDailyLimitUsd sets the "daily_limit_usd" field.
-func (m *GroupMutation) SetDailyLimitUsd(f float64) {
-	m.daily_limit_usd = &f
+func (m *GroupMutation) SetDailyLimitUsd(value money.Amount) {
+	m.daily_limit_usd = &value
 	m.adddaily_limit_usd = nil
 }
 
 // DailyLimitUsd returns the value of the "daily_limit_usd" field in the mutation.
-func (m *GroupMutation) DailyLimitUsd() (r float64, exists bool) {
+func (m *GroupMutation) DailyLimitUsd() (r money.Amount, exists bool) {
 	v := m.daily_limit_usd
 	if v == nil {
 		return
@@ -13342,7 +13895,7 @@ func (m *GroupMutation) DailyLimitUsd() (r float64, exists bool) {
 // OldDailyLimitUsd returns the old "daily_limit_usd" field's value of the Group entity.
 // If the Group object wasn't provided to the builder, the object is fetched from the database.
 // An error is returned if the mutation operation is not UpdateOne, or the database query fails.
-func (m *GroupMutation) OldDailyLimitUsd(ctx context.Context) (v *float64, err error) {
+func (m *GroupMutation) OldDailyLimitUsd(ctx context.Context) (v *money.Amount, err error) {
 	if !m.op.Is(OpUpdateOne) {
 		return v, errors.New("OldDailyLimitUsd is only allowed on UpdateOne operations")
 	}
@@ -13356,17 +13909,17 @@ func (m *GroupMutation) OldDailyLimitUsd(ctx context.Context) (v *float64, err e
 	return oldValue.DailyLimitUsd, nil
 }
 
-// AddDailyLimitUsd adds f to the "daily_limit_usd" field.
-func (m *GroupMutation) AddDailyLimitUsd(f float64) {
+// AddDailyLimitUsd adds value to the "daily_limit_usd" field.
+func (m *GroupMutation) AddDailyLimitUsd(value money.Amount) {
 	if m.adddaily_limit_usd != nil {
-		*m.adddaily_limit_usd += f
+		*m.adddaily_limit_usd += value
 	} else {
-		m.adddaily_limit_usd = &f
+		m.adddaily_limit_usd = &value
 	}
 }
 
 // AddedDailyLimitUsd returns the value that was added to the "daily_limit_usd" field in this mutation.
-func (m *GroupMutation) AddedDailyLimitUsd() (r float64, exists bool) {
+func (m *GroupMutation) AddedDailyLimitUsd() (r money.Amount, exists bool) {
 	v := m.adddaily_limit_usd
 	if v == nil {
 		return
@@ -13395,13 +13948,13 @@ func (m *GroupMutation) ResetDailyLimitUsd() {
 }
 
 // SetWeeklyLimitUsd sets the "weekly_limit_usd" field.
-func (m *GroupMutation) SetWeeklyLimitUsd(f float64) {
-	m.weekly_limit_usd = &f
+func (m *GroupMutation) SetWeeklyLimitUsd(value money.Amount) {
+	m.weekly_limit_usd = &value
 	m.addweekly_limit_usd = nil
 }
 
 // WeeklyLimitUsd returns the value of the "weekly_limit_usd" field in the mutation.
-func (m *GroupMutation) WeeklyLimitUsd() (r float64, exists bool) {
+func (m *GroupMutation) WeeklyLimitUsd() (r money.Amount, exists bool) {
 	v := m.weekly_limit_usd
 	if v == nil {
 		return
@@ -13412,7 +13965,7 @@ func (m *GroupMutation) WeeklyLimitUsd() (r float64, exists bool) {
 // OldWeeklyLimitUsd returns the old "weekly_limit_usd" field's value of the Group entity.
 // If the Group object wasn't provided to the builder, the object is fetched from the database.
 // An error is returned if the mutation operation is not UpdateOne, or the database query fails.
-func (m *GroupMutation) OldWeeklyLimitUsd(ctx context.Context) (v *float64, err error) {
+func (m *GroupMutation) OldWeeklyLimitUsd(ctx context.Context) (v *money.Amount, err error) {
 	if !m.op.Is(OpUpdateOne) {
 		return v, errors.New("OldWeeklyLimitUsd is only allowed on UpdateOne operations")
 	}
@@ -13426,17 +13979,17 @@ func (m *GroupMutation) OldWeeklyLimitUsd(ctx context.Context) (v *float64, err
 	return oldValue.WeeklyLimitUsd, nil
 }
 
-// AddWeeklyLimitUsd adds f to the "weekly_limit_usd" field.
-func (m *GroupMutation) AddWeeklyLimitUsd(f float64) {
+// AddWeeklyLimitUsd adds value to the "weekly_limit_usd" field.
+func (m *GroupMutation) AddWeeklyLimitUsd(value money.Amount) {
 	if m.addweekly_limit_usd != nil {
-		*m.addweekly_limit_usd += f
+		*m.addweekly_limit_usd += value
 	} else {
-		m.addweekly_limit_usd = &f
+		m.addweekly_limit_usd = &value
 	}
 }
 
 // AddedWeeklyLimitUsd returns the value that was added to the "weekly_limit_usd" field in this mutation.
-func (m *GroupMutation) AddedWeeklyLimitUsd() (r float64, exists bool) {
+func (m *GroupMutation) AddedWeeklyLimitUsd() (r money.Amount, exists bool) {
 	v := m.addweekly_limit_usd
 	if v == nil {
 		return
@@ -13465,13 +14018,13 @@ func (m *GroupMutation) ResetWeeklyLimitUsd() {
 }
 
 // SetMonthlyLimitUsd sets the "monthly_limit_usd" field.
-func (m *GroupMutation) SetMonthlyLimitUsd(f float64) {
-	m.monthly_limit_usd = &f
+func (m *GroupMutation) SetMonthlyLimitUsd(value money.Amount) {
+	m.monthly_limit_usd = &value
 	m.addmonthly_limit_usd = nil
 }
 
 // MonthlyLimitUsd returns the value of the "monthly_limit_usd" field in the mutation.
-func (m *GroupMutation) MonthlyLimitUsd() (r float64, exists bool) {
+func (m *GroupMutation) MonthlyLimitUsd() (r money.Amount, exists bool) {
 	v := m.monthly_limit_usd
 	if v == nil {
 		return
@@ -13482,7 +14035,7 @@ func (m *GroupMutation) MonthlyLimitUsd() (r float64, exists bool) {
 // OldMonthlyLimitUsd returns the old "monthly_limit_usd" field's value of the Group entity.
 // If the Group object wasn't provided to the builder, the object is fetched from the database.
 // An error is returned if the mutation operation is not UpdateOne, or the database query fails.
-func (m *GroupMutation) OldMonthlyLimitUsd(ctx context.Context) (v *float64, err error) {
+func (m *GroupMutation) OldMonthlyLimitUsd(ctx context.Context) (v *money.Amount, err error) {
 	if !m.op.Is(OpUpdateOne) {
 		return v, errors.New("OldMonthlyLimitUsd is only allowed on UpdateOne operations")
 	}
@@ -13496,17 +14049,17 @@ func (m *GroupMutation) OldMonthlyLimitUsd(ctx context.Context) (v *float64, err
 	return oldValue.MonthlyLimitUsd, nil
 }
 
-// AddMonthlyLimitUsd adds f to the "monthly_limit_usd" field.
-func (m *GroupMutation) AddMonthlyLimitUsd(f float64) {
+// AddMonthlyLimitUsd adds value to the "monthly_limit_usd" field.
+func (m *GroupMutation) AddMonthlyLimitUsd(value money.Amount) {
 	if m.addmonthly_limit_usd != nil {
-		*m.addmonthly_limit_usd += f
+		*m.addmonthly_limit_usd += value
 	} else {
-		m.addmonthly_limit_usd = &f
+		m.addmonthly_limit_usd = &value
 	}
 }
 
 // AddedMonthlyLimitUsd returns the value that was added to the "monthly_limit_usd" field in this mutation.
-func (m *GroupMutation) AddedMonthlyLimitUsd() (r float64, exists bool) {
+func (m *GroupMutation) AddedMonthlyLimitUsd() (r money.Amount, exists bool) {
 	v := m.addmonthly_limit_usd
 	if v == nil {
 		return
@@ -13591,13 +14144,13 @@ func (m *GroupMutation) ResetDefaultValidityDays() {
 }
 
 // SetImagePrice1k sets the "image_price_1k" field.
-func (m *GroupMutation) SetImagePrice1k(f float64) {
-	m.image_price_1k = &f
+func (m *GroupMutation) SetImagePrice1k(value money.Amount) {
+	m.image_price_1k = &value
 	m.addimage_price_1k = nil
 }
 
 // ImagePrice1k returns the value of the "image_price_1k" field in the mutation.
-func (m *GroupMutation) ImagePrice1k() (r float64, exists bool) {
+func (m *GroupMutation) ImagePrice1k() (r money.Amount, exists bool) {
 	v := m.image_price_1k
 	if v == nil {
 		return
@@ -13608,7 +14161,7 @@ func (m *GroupMutation) ImagePrice1k() (r float64, exists bool) {
 // OldImagePrice1k returns the old "image_price_1k" field's value of the Group entity.
 // If the Group object wasn't provided to the builder, the object is fetched from the database.
 // An error is returned if the mutation operation is not UpdateOne, or the database query fails.
-func (m *GroupMutation) OldImagePrice1k(ctx context.Context) (v *float64, err error) {
+func (m *GroupMutation) OldImagePrice1k(ctx context.Context) (v *money.Amount, err error) {
 	if !m.op.Is(OpUpdateOne) {
 		return v, errors.New("OldImagePrice1k is only allowed on UpdateOne operations")
 	}
@@ -13622,17 +14175,17 @@ func (m *GroupMutation) OldImagePrice1k(ctx context.Context) (v *float64, err er
 	return oldValue.ImagePrice1k, nil
 }
 
-// AddImagePrice1k adds f to the "image_price_1k" field.
-func (m *GroupMutation) AddImagePrice1k(f float64) {
+// AddImagePrice1k adds value to the "image_price_1k" field.
+func (m *GroupMutation) AddImagePrice1k(value money.Amount) {
 	if m.addimage_price_1k != nil {
-		*m.addimage_price_1k += f
+		*m.addimage_price_1k += value
 	} else {
-		m.addimage_price_1k = &f
+		m.addimage_price_1k = &value
 	}
 }
 
 // AddedImagePrice1k returns the value that was added to the "image_price_1k" field in this mutation.
-func (m *GroupMutation) AddedImagePrice1k() (r float64, exists bool) {
+func (m *GroupMutation) AddedImagePrice1k() (r money.Amount, exists bool) {
 	v := m.addimage_price_1k
 	if v == nil {
 		return
@@ -13661,13 +14214,13 @@ func (m *GroupMutation) ResetImagePrice1k() {
 }
 
 // SetImagePrice2k sets the "image_price_2k" field.
-func (m *GroupMutation) SetImagePrice2k(f float64) {
-	m.image_price_2k = &f
+func (m *GroupMutation) SetImagePrice2k(value money.Amount) {
+	m.image_price_2k = &value
 	m.addimage_price_2k = nil
 }
 
 // ImagePrice2k returns the value of the "image_price_2k" field in the mutation.
-func (m *GroupMutation) ImagePrice2k() (r float64, exists bool) {
+func (m *GroupMutation) ImagePrice2k() (r money.Amount, exists bool) {
 	v := m.image_price_2k
 	if v == nil {
 		return
@@ -13678,7 +14231,7 @@ func (m *GroupMutation) ImagePrice2k() (r float64, exists bool) {
 // OldImagePrice2k returns the old "image_price_2k" field's value of the Group entity.
 // If the Group object wasn't provided to the builder, the object is fetched from the database.
 // An error is returned if the mutation operation is not UpdateOne, or the database query fails.
-func (m *GroupMutation) OldImagePrice2k(ctx context.Context) (v *float64, err error) {
+func (m *GroupMutation) OldImagePrice2k(ctx context.Context) (v *money.Amount, err error) {
 	if !m.op.Is(OpUpdateOne) {
 		return v, errors.New("OldImagePrice2k is only allowed on UpdateOne operations")
 	}
@@ -13692,17 +14245,17 @@ func (m *GroupMutation) OldImagePrice2k(ctx context.Context) (v *float64, err er
 	return oldValue.ImagePrice2k, nil
 }
 
-// AddImagePrice2k adds f to the "image_price_2k" field.
-func (m *GroupMutation) AddImagePrice2k(f float64) {
+// AddImagePrice2k adds value to the "image_price_2k" field.
+func (m *GroupMutation) AddImagePrice2k(value money.Amount) {
 	if m.addimage_price_2k != nil {
-		*m.addimage_price_2k += f
+		*m.addimage_price_2k += value
 	} else {
-		m.addimage_price_2k = &f
+		m.addimage_price_2k = &value
 	}
 }
 
 // AddedImagePrice2k returns the value that was added to the "image_price_2k" field in this mutation.
-func (m *GroupMutation) AddedImagePrice2k() (r float64, exists bool) {
+func (m *GroupMutation) AddedImagePrice2k() (r money.Amount, exists bool) {
 	v := m.addimage_price_2k
 	if v == nil {
 		return
@@ -13731,13 +14284,13 @@ func (m *GroupMutation) ResetImagePrice2k() {
 }
 
 // SetImagePrice4k sets the "image_price_4k" field.
-func (m *GroupMutation) SetImagePrice4k(f float64) {
-	m.image_price_4k = &f
+func (m *GroupMutation) SetImagePrice4k(value money.Amount) {
+	m.image_price_4k = &value
 	m.addimage_price_4k = nil
 }
 
 // ImagePrice4k returns the value of the "image_price_4k" field in the mutation.
-func (m *GroupMutation) ImagePrice4k() (r float64, exists bool) {
+func (m *GroupMutation) ImagePrice4k() (r money.Amount, exists bool) {
 	v := m.image_price_4k
 	if v == nil {
 		return
@@ -13748,7 +14301,7 @@ func (m *GroupMutation) ImagePrice4k() (r float64, exists bool) {
 // OldImagePrice4k returns the old "image_price_4k" field's value of the Group entity.
 // If the Group object wasn't provided to the builder, the object is fetched from the database.
 // An error is returned if the mutation operation is not UpdateOne, or the database query fails.
-func (m *GroupMutation) OldImagePrice4k(ctx context.Context) (v *float64, err error) {
+func (m *GroupMutation) OldImagePrice4k(ctx context.Context) (v *money.Amount, err error) {
 	if !m.op.Is(OpUpdateOne) {
 		return v, errors.New("OldImagePrice4k is only allowed on UpdateOne operations")
 	}
@@ -13762,17 +14315,17 @@ func (m *GroupMutation) OldImagePrice4k(ctx context.Context) (v *float64, err er
 	return oldValue.ImagePrice4k, nil
 }
 
-// AddImagePrice4k adds f to the "image_price_4k" field.
-func (m *GroupMutation) AddImagePrice4k(f float64) {
+// AddImagePrice4k adds value to the "image_price_4k" field.
+func (m *GroupMutation) AddImagePrice4k(value money.Amount) {
 	if m.addimage_price_4k != nil {
-		*m.addimage_price_4k += f
+		*m.addimage_price_4k += value
 	} else {
-		m.addimage_price_4k = &f
+		m.addimage_price_4k = &value
 	}
 }
 
 // AddedImagePrice4k returns the value that was added to the "image_price_4k" field in this mutation.
-func (m *GroupMutation) AddedImagePrice4k() (r float64, exists bool) {
+func (m *GroupMutation) AddedImagePrice4k() (r money.Amount, exists bool) {
 	v := m.addimage_price_4k
 	if v == nil {
 		return
@@ -13801,13 +14354,13 @@ func (m *GroupMutation) ResetImagePrice4k() {
 }
 
 // SetSoraImagePrice360 sets the "sora_image_price_360" field.
-func (m *GroupMutation) SetSoraImagePrice360(f float64) {
-	m.sora_image_price_360 = &f
+func (m *GroupMutation) SetSoraImagePrice360(value money.Amount) {
+	m.sora_image_price_360 = &value
 	m.addsora_image_price_360 = nil
 }
 
 // SoraImagePrice360 returns the value of the "sora_image_price_360" field in the mutation.
-func (m *GroupMutation) SoraImagePrice360() (r float64, exists bool) {
+func (m *GroupMutation) SoraImagePrice360() (r money.Amount, exists bool) {
 	v := m.sora_image_price_360
 	if v == nil {
 		return
@@ -13818,7 +14371,7 @@ func (m *GroupMutation) SoraImagePrice360() (r float64, exists bool) {
 // OldSoraImagePrice360 returns the old "sora_image_price_360" field's value of the Group entity.
 // If the Group object wasn't provided to the builder, the object is fetched from the database.
 // An error is returned if the mutation operation is not UpdateOne, or the database query fails.
-func (m *GroupMutation) OldSoraImagePrice360(ctx context.Context) (v *float64, err error) {
+func (m *GroupMutation) OldSoraImagePrice360(ctx context.Context) (v *money.Amount, err error) {
 	if !m.op.Is(OpUpdateOne) {
 		return v, errors.New("OldSoraImagePrice360 is only allowed on UpdateOne operations")
 	}
@@ -13832,17 +14385,17 @@ func (m *GroupMutation) OldSoraImagePrice360(ctx context.Context) (v *float64, e
 	return oldValue.SoraImagePrice360, nil
 }
 
-// AddSoraImagePrice360 adds f to the "sora_image_price_360" field.
-func (m *GroupMutation) AddSoraImagePrice360(f float64) {
+// AddSoraImagePrice360 adds value to the "sora_image_price_360" field.
+func (m *GroupMutation) AddSoraImagePrice360(value money.Amount) {
 	if m.addsora_image_price_360 != nil {
-		*m.addsora_image_price_360 += f
+		*m.addsora_image_price_360 += value
 	} else {
-		m.addsora_image_price_360 = &f
+		m.addsora_image_price_360 = &value
 	}
 }
 
 // AddedSoraImagePrice360 returns the value that was added to the "sora_image_price_360" field in this mutation.
-func (m *GroupMutation) AddedSoraImagePrice360() (r float64, exists bool) {
+func (m *GroupMutation) AddedSoraImagePrice360() (r money.Amount, exists bool) {
 	v := m.addsora_image_price_360
 	if v == nil {
 		return
@@ -13871,13 +14424,13 @@ func (m *GroupMutation) ResetSoraImagePrice360() {
 }
 
 // SetSoraImagePrice540 sets the "sora_image_price_540" field.
-func (m *GroupMutation) SetSoraImagePrice540(f float64) {
-	m.sora_image_price_540 = &f
+func (m *GroupMutation) SetSoraImagePrice540(value money.Amount) {
+	m.sora_image_price_540 = &value
 	m.addsora_image_price_540 = nil
 }
 
 // SoraImagePrice540 returns the value of the "sora_image_price_540" field in the mutation.
-func (m *GroupMutation) SoraImagePrice540() (r float64, exists bool) {
+func (m *GroupMutation) SoraImagePrice540() (r money.Amount, exists bool) {
 	v := m.sora_image_price_540
 	if v == nil {
 		return
@@ -13888,7 +14441,7 @@ func (m *GroupMutation) SoraImagePrice540() (r float64, exists bool) {
 // OldSoraImagePrice540 returns the old "sora_image_price_540" field's value of the Group entity.
 // If the Group object wasn't provided to the builder, the object is fetched from the database.
 // An error is returned if the mutation operation is not UpdateOne, or the database query fails.
-func (m *GroupMutation) OldSoraImagePrice540(ctx context.Context) (v *float64, err error) {
+func (m *GroupMutation) OldSoraImagePrice540(ctx context.Context) (v *money.Amount, err error) {
 	if !m.op.Is(OpUpdateOne) {
 		return v, errors.New("OldSoraImagePrice540 is only allowed on UpdateOne operations")
 	}
@@ -13902,17 +14455,17 @@ func (m *GroupMutation) OldSoraImagePrice540(ctx context.Context) (v *float64, e
 	return oldValue.SoraImagePrice540, nil
 }
 
-// AddSoraImagePrice540 adds f to the "sora_image_price_540" field.
-func (m *GroupMutation) AddSoraImagePrice540(f float64) {
+// AddSoraImagePrice540 adds value to the "sora_image_price_540" field.
+func (m *GroupMutation) AddSoraImagePrice540(value money.Amount) {
 	if m.addsora_image_price_540 != nil {
-		*m.addsora_image_price_540 += f
+		*m.addsora_image_price_540 += value
 	} else {
-		m.addsora_image_price_540 = &f
+		m.addsora_image_price_540 = &value
 	}
 }
 
 // AddedSoraImagePrice540 returns the value that was added to the "sora_image_price_540" field in this mutation.
-func (m *GroupMutation) AddedSoraImagePrice540() (r float64, exists bool) {
+func (m *GroupMutation) AddedSoraImagePrice540() (r money.Amount, exists bool) {
 	v := m.addsora_image_price_540
 	if v == nil {
 		return
@@ -13941,13 +14494,13 @@ func (m *GroupMutation) ResetSoraImagePrice540() {
 }
 
 // SetSoraVideoPricePerRequest sets the "sora_video_price_per_request" field.
-func (m *GroupMutation) SetSoraVideoPricePerRequest(f float64) {
-	m.sora_video_price_per_request = &f
+func (m *GroupMutation) SetSoraVideoPricePerRequest(value money.Amount) {
+	m.sora_video_price_per_request = &value
 	m.addsora_video_price_per_request = nil
 }
 
 // SoraVideoPricePerRequest returns the value of the "sora_video_price_per_request" field in the mutation.
-func (m *GroupMutation) SoraVideoPricePerRequest() (r float64, exists bool) {
+func (m *GroupMutation) SoraVideoPricePerRequest() (r money.Amount, exists bool) {
 	v := m.sora_video_price_per_request
 	if v == nil {
 		return
@@ -13958,7 +14511,7 @@ func (m *GroupMutation) SoraVideoPricePerRequest() (r float64, exists bool) {
 // OldSoraVideoPricePerRequest returns the old "sora_video_price_per_request" field's value of the Group entity.
 // If the Group object wasn't provided to the builder, the object is fetched from the database.
 // An error is returned if the mutation operation is not UpdateOne, or the database query fails.
-func (m *GroupMutation) OldSoraVideoPricePerRequest(ctx context.Context) (v *float64, err error) {
+func (m *GroupMutation) OldSoraVideoPricePerRequest(ctx context.Context) (v *money.Amount, err error) {
 	if !m.op.Is(OpUpdateOne) {
 		return v, errors.New("OldSoraVideoPricePerRequest is only allowed on UpdateOne operations")
 	}
@@ -13972,17 +14525,17 @@ func (m *GroupMutation) OldSoraVideoPricePerRequest(ctx context.Context) (v *flo
 	return oldValue.SoraVideoPricePerRequest, nil
 }
 
-// AddSoraVideoPricePerRequest adds f to the "sora_video_price_per_request" field.
-func (m *GroupMutation) AddSoraVideoPricePerRequest(f float64) {
+// AddSoraVideoPricePerRequest adds value to the "sora_video_price_per_request" field.
+func (m *GroupMutation) AddSoraVideoPricePerRequest(value money.Amount) {
 	if m.addsora_video_price_per_request != nil {
-		*m.addsora_video_price_per_request += f
+		*m.addsora_video_price_per_request += value
 	} else {
-		m.addsora_video_price_per_request = &f
+		m.addsora_video_price_per_request = &value
 	}
 }
 
 // AddedSoraVideoPricePerRequest returns the value that was added to the "sora_video_price_per_request" field in this mutation.
-func (m *GroupMutation) AddedSoraVideoPricePerRequest() (r float64, exists bool) {
+func (m *GroupMutation) AddedSoraVideoPricePerRequest() (r money.Amount, exists bool) {
 	v := m.addsora_video_price_per_request
 	if v == nil {
 		return
@@ -14011,13 +14564,13 @@ func (m *GroupMutation) ResetSoraVideoPricePerRequest() {
 }
 
 // SetSoraVideoPricePerRequestHd sets the "sora_video_price_per_request_hd" field.
-func (m *GroupMutation) SetSoraVideoPricePerRequestHd(f float64) {
-	m.sora_video_price_per_request_hd = &f
+func (m *GroupMutation) SetSoraVideoPricePerRequestHd(value money.Amount) {
+	m.sora_video_price_per_request_hd = &value
 	m.addsora_video_price_per_request_hd = nil
 }
 
 // SoraVideoPricePerRequestHd returns the value of the "sora_video_price_per_request_hd" field in the mutation.
-func (m *GroupMutation) SoraVideoPricePerRequestHd() (r float64, exists bool) {
+func (m *GroupMutation) SoraVideoPricePerRequestHd() (r money.Amount, exists bool) {
 	v := m.sora_video_price_per_request_hd
 	if v == nil {
 		return
@@ -14028,7 +14581,7 @@ func (m *GroupMutation) SoraVideoPricePerRequestHd() (r float64, exists bool) {
 // OldSoraVideoPricePerRequestHd returns the old "sora_video_price_per_request_hd" field's value of the Group entity.
 // If the Group object wasn't provided to the builder, the object is fetched from the database.
 // An error is returned if the mutation operation is not UpdateOne, or the database query fails.
-func (m *GroupMutation) OldSoraVideoPricePerRequestHd(ctx context.Context) (v *float64, err error) {
+func (m *GroupMutation) OldSoraVideoPricePerRequestHd(ctx context.Context) (v *money.Amount, err error) {
 	if !m.op.Is(OpUpdateOne) {
 		return v, errors.New("OldSoraVideoPricePerRequestHd is only allowed on UpdateOne operations")
 	}
@@ -14042,17 +14595,17 @@ func (m *GroupMutation) OldSoraVideoPricePerRequestHd(ctx context.Context) (v *f
 	return oldValue.SoraVideoPricePerRequestHd, nil
 }
 
-// AddSoraVideoPricePerRequestHd adds f to the "sora_video_price_per_request_hd" field.
-func (m *GroupMutation) AddSoraVideoPricePerRequestHd(f float64) {
+// AddSoraVideoPricePerRequestHd adds value to the "sora_video_price_per_request_hd" field.
+func (m *GroupMutation) AddSoraVideoPricePerRequestHd(value money.Amount) {
 	if m.addsora_video_price_per_request_hd != nil {
-		*m.addsora_video_price_per_request_hd += f
+		*m.addsora_video_price_per_request_hd += value
 	} else {
-		m.addsora_video_price_per_request_hd = &f
+		m.addsora_video_price_per_request_hd = &value
 	}
 }
 
 // AddedSoraVideoPricePerRequestHd returns the value that was added to the "sora_video_price_per_request_hd" field in this mutation.
-func (m *GroupMutation) AddedSoraVideoPricePerRequestHd() (r float64, exists bool) {
+func (m *GroupMutation) AddedSoraVideoPricePerRequestHd() (r money.Amount, exists bool) {
 	v := m.addsora_video_price_per_request_hd
 	if v == nil {
 		return
@@ -14137,13 +14690,13 @@ func (m *GroupMutation) ResetSoraStorageQuotaBytes() {
 }
 
 // SetVideoPricePerRequest sets the "video_price_per_request" field.
-func (m *GroupMutation) SetVideoPricePerRequest(f float64) {
-	m.video_price_per_request = &f
+func (m *GroupMutation) SetVideoPricePerRequest(value money.Amount) {
+	m.video_price_per_request = &value
 	m.addvideo_price_per_request = nil
 }
 
 // VideoPricePerRequest returns the value of the "video_price_per_request" field in the mutation.
-func (m *GroupMutation) VideoPricePerRequest() (r float64, exists bool) {
+func (m *GroupMutation) VideoPricePerRequest() (r money.Amount, exists bool) {
 	v := m.video_price_per_request
 	if v == nil {
 		return
@@ -14154,7 +14707,7 @@ func (m *GroupMutation) VideoPricePerRequest() (r float64, exists bool) {
 // OldVideoPricePerRequest returns the old "video_price_per_request" field's value of the Group entity.
 // If the Group object wasn't provided to the builder, the object is fetched from the database.
 // An error is returned if the mutation operation is not UpdateOne, or the database query fails.
-func (m *GroupMutation) OldVideoPricePerRequest(ctx context.Context) (v *float64, err error) {
+func (m *GroupMutation) OldVideoPricePerRequest(ctx context.Context) (v *money.Amount, err error) {
 	if !m.op.Is(OpUpdateOne) {
 		return v, errors.New("OldVideoPricePerRequest is only allowed on UpdateOne operations")
 	}
@@ -14168,17 +14721,17 @@ func (m *GroupMutation) OldVideoPricePerRequest(ctx context.Context) (v *float64
 	return oldValue.VideoPricePerRequest, nil
 }
 
-// AddVideoPricePerRequest adds f to the "video_price_per_request" field.
-func (m *GroupMutation) AddVideoPricePerRequest(f float64) {
+// AddVideoPricePerRequest adds value to the "video_price_per_request" field.
+func (m *GroupMutation) AddVideoPricePerRequest(value money.Amount) {
 	if m.addvideo_price_per_request != nil {
-		*m.addvideo_price_per_request += f
+		*m.addvideo_price_per_request += value
 	} else {
-		m.addvideo_price_per_request = &f
+		m.addvideo_price_per_request = &value
 	}
 }
 
 // AddedVideoPricePerRequest returns the value that was added to the "video_price_per_request" field in this mutation.
-func (m *GroupMutation) AddedVideoPricePerRequest() (r float64, exists bool) {
+func (m *GroupMutation) AddedVideoPricePerRequest() (r money.Amount, exists bool) {
 	v := m.addvideo_price_per_request
 	if v == nil {
 		return
@@ -14207,13 +14760,13 @@ func (m *GroupMutation) ResetVideoPricePerRequest() {
 }
 
 // SetVideoPricePerRequestHd sets the "video_price_per_request_hd" field.
-func (m *GroupMutation) SetVideoPricePerRequestHd(f float64) {
-	m.video_price_per_request_hd = &f
+func (m *GroupMutation) SetVideoPricePerRequestHd(value money.Amount) {
+	m.video_price_per_request_hd = &value
 	m.addvideo_price_per_request_hd = nil
 }
 
 // VideoPricePerRequestHd returns the value of the "video_price_per_request_hd" field in the mutation.
-func (m *GroupMutation) VideoPricePerRequestHd() (r float64, exists bool) {
+func (m *GroupMutation) VideoPricePerRequestHd() (r money.Amount, exists bool) {
 	v := m.video_price_per_request_hd
 	if v == nil {
 		return
@@ -14224,7 +14777,7 @@ func (m *GroupMutation) VideoPricePerRequestHd() (r float64, exists bool) {
 // OldVideoPricePerRequestHd returns the old "video_price_per_request_hd" field's value of the Group entity.
 // If the Group object wasn't provided to the builder, the object is fetched from the database.
 // An error is returned if the mutation operation is not UpdateOne, or the database query fails.
-func (m *GroupMutation) OldVideoPricePerRequestHd(ctx context.Context) (v *float64, err error) {
+func (m *GroupMutation) OldVideoPricePerRequestHd(ctx context.Context) (v *money.Amount, err error) {
 	if !m.op.Is(OpUpdateOne) {
 		return v, errors.New("OldVideoPricePerRequestHd is only allowed on UpdateOne operations")
 	}
@@ -14238,17 +14791,17 @@ func (m *GroupMutation) OldVideoPricePerRequestHd(ctx context.Context) (v *float
 	return oldValue.VideoPricePerRequestHd, nil
 }
 
-// AddVideoPricePerRequestHd adds f to the "video_price_per_request_hd" field.
-func (m *GroupMutation) AddVideoPricePerRequestHd(f float64) {
+// AddVideoPricePerRequestHd adds value to the "video_price_per_request_hd" field.
+func (m *GroupMutation) AddVideoPricePerRequestHd(value money.Amount) {
 	if m.addvideo_price_per_request_hd != nil {
-		*m.addvideo_price_per_request_hd += f
+		*m.addvideo_price_per_request_hd += value
 	} else {
-		m.addvideo_price_per_request_hd = &f
+		m.addvideo_price_per_request_hd = &value
 	}
 }
 
 // AddedVideoPricePerRequestHd returns the value that was added to the "video_price_per_request_hd" field in this mutation.
-func (m *GroupMutation) AddedVideoPricePerRequestHd() (r float64, exists bool) {
+func (m *GroupMutation) AddedVideoPricePerRequestHd() (r money.Amount, exists bool) {
 	v := m.addvideo_price_per_request_hd
 	if v == nil {
 		return
@@ -14276,6 +14829,146 @@ func (m *GroupMutation) ResetVideoPricePerRequestHd() {
 	delete(m.clearedFields, group.FieldVideoPricePerRequestHd)
 }
 
+// SetAudioPricePerMinute sets the "audio_price_per_minute" field.
+func (m *GroupMutation) SetAudioPricePerMinute(value money.Amount) {
+	m.audio_price_per_minute = &value
+	m.addaudio_price_per_minute = nil
+}
+
+// AudioPricePerMinute returns the value of the "audio_price_per_minute" field in the mutation.
+func (m *GroupMutation) AudioPricePerMinute() (r money.Amount, exists bool) {
+	v := m.audio_price_per_minute
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldAudioPricePerMinute returns the old "audio_price_per_minute" field's value of the Group entity.
+// If the Group object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *GroupMutation) OldAudioPricePerMinute(ctx context.Context) (v *money.Amount, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldAudioPricePerMinute is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldAudioPricePerMinute requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldAudioPricePerMinute: %w", err)
+	}
+	return oldValue.AudioPricePerMinute, nil
+}
+
+// AddAudioPricePerMinute adds value to the "audio_price_per_minute" field.
+func (m *GroupMutation) AddAudioPricePerMinute(value money.Amount) {
+	if m.addaudio_price_per_minute != nil {
+		*m.addaudio_price_per_minute += value
+	} else {
+		m.addaudio_price_per_minute = &value
+	}
+}
+
+// AddedAudioPricePerMinute returns the value that was added to the "audio_price_per_minute" field in this mutation.
+func (m *GroupMutation) AddedAudioPricePerMinute() (r money.Amount, exists bool) {
+	v := m.addaudio_price_per_minute
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// ClearAudioPricePerMinute clears the value of the "audio_price_per_minute" field.
+func (m *GroupMutation) ClearAudioPricePerMinute() {
+	m.audio_price_per_minute = nil
+	m.addaudio_price_per_minute = nil
+	m.clearedFields[group.FieldAudioPricePerMinute] = struct{}{}
+}
+
+// AudioPricePerMinuteCleared returns if the "audio_price_per_minute" field was cleared in this mutation.
+func (m *GroupMutation) AudioPricePerMinuteCleared() bool {
+	_, ok := m.clearedFields[group.FieldAudioPricePerMinute]
+	return ok
+}
+
+// ResetAudioPricePerMinute resets all changes to the "audio_price_per_minute" field.
+func (m *GroupMutation) ResetAudioPricePerMinute() {
+	m.audio_price_per_minute = nil
+	m.addaudio_price_per_minute = nil
+	delete(m.clearedFields, group.FieldAudioPricePerMinute)
+}
+
+// SetAudioSpeechPricePer1mChars sets the "audio_speech_price_per_1m_chars" field.
+func (m *GroupMutation) SetAudioSpeechPricePer1mChars(value money.Amount) {
+	m.audio_speech_price_per_1m_chars = &value
+	m.addaudio_speech_price_per_1m_chars = nil
+}
+
+// AudioSpeechPricePer1mChars returns the value of the "audio_speech_price_per_1m_chars" field in the mutation.
+func (m *GroupMutation) AudioSpeechPricePer1mChars() (r money.Amount, exists bool) {
+	v := m.audio_speech_price_per_1m_chars
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldAudioSpeechPricePer1mChars returns the old "audio_speech_price_per_1m_chars" field's value of the Group entity.
+// If the Group object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *GroupMutation) OldAudioSpeechPricePer1mChars(ctx context.Context) (v *money.Amount, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldAudioSpeechPricePer1mChars is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldAudioSpeechPricePer1mChars requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldAudioSpeechPricePer1mChars: %w", err)
+	}
+	return oldValue.AudioSpeechPricePer1mChars, nil
+}
+
+// AddAudioSpeechPricePer1mChars adds value to the "audio_speech_price_per_1m_chars" field.
+func (m *GroupMutation) AddAudioSpeechPricePer1mChars(value money.Amount) {
+	if m.addaudio_speech_price_per_1m_chars != nil {
+		*m.addaudio_speech_price_per_1m_chars += value
+	} else {
+		m.addaudio_speech_price_per_1m_chars = &value
+	}
+}
+
+// AddedAudioSpeechPricePer1mChars returns the value that was added to the "audio_speech_price_per_1m_chars" field in this mutation.
+func (m *GroupMutation) AddedAudioSpeechPricePer1mChars() (r money.Amount, exists bool) {
+	v := m.addaudio_speech_price_per_1m_chars
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// ClearAudioSpeechPricePer1mChars clears the value of the "audio_speech_price_per_1m_chars" field.
+func (m *GroupMutation) ClearAudioSpeechPricePer1mChars() {
+	m.audio_speech_price_per_1m_chars = nil
+	m.addaudio_speech_price_per_1m_chars = nil
+	m.clearedFields[group.FieldAudioSpeechPricePer1mChars] = struct{}{}
+}
+
+// AudioSpeechPricePer1mCharsCleared returns if the "audio_speech_price_per_1m_chars" field was cleared in this mutation.
+func (m *GroupMutation) AudioSpeechPricePer1mCharsCleared() bool {
+	_, ok := m.clearedFields[group.FieldAudioSpeechPricePer1mChars]
+	return ok
+}
+
+// ResetAudioSpeechPricePer1mChars resets all changes to the "audio_speech_price_per_1m_chars" field.
+func (m *GroupMutation) ResetAudioSpeechPricePer1mChars() {
+	m.audio_speech_price_per_1m_chars = nil
+	m.addaudio_speech_price_per_1m_chars = nil
+	delete(m.clearedFields, group.FieldAudioSpeechPricePer1mChars)
+}
+
 // SetClaudeCodeOnly sets the "claude_code_only" field.
 func (m *GroupMutation) SetClaudeCodeOnly(b bool) {
 	m.claude_code_only = &b
@@ -15038,7 +15731,7 @@ func (m *GroupMutation) Type() string {
 // order to get all numeric fields that were incremented/decremented, call
 // AddedFields().
 func (m *GroupMutation) Fields() []string {
-	fields := make([]string, 0, 32)
+	fields := make([]string, 0, 34)
 	if m.created_at != nil {
 		fields = append(fields, group.FieldCreatedAt)
 	}
@@ -15111,6 +15804,12 @@ func (m *GroupMutation) Fields() []string {
 	if m.video_price_per_request_hd != nil {
 		fields = append(fields, group.FieldVideoPricePerRequestHd)
 	}
+	if m.audio_price_per_minute != nil {
+		fields = append(fields, group.FieldAudioPricePerMinute)
+	}
+	if m.audio_speech_price_per_1m_chars != nil {
+		fields = append(fields, group.FieldAudioSpeechPricePer1mChars)
+	}
 	if m.claude_code_only != nil {
 		fields = append(fields, group.FieldClaudeCodeOnly)
 	}
@@ -15191,6 +15890,10 @@ func (m *GroupMutation) Field(name string) (ent.Value, bool) {
 		return m.VideoPricePerRequest()
 	case group.FieldVideoPricePerRequestHd:
 		return m.VideoPricePerRequestHd()
+	case group.FieldAudioPricePerMinute:
+		return m.AudioPricePerMinute()
+	case group.FieldAudioSpeechPricePer1mChars:
+		return m.AudioSpeechPricePer1mChars()
 	case group.FieldClaudeCodeOnly:
 		return m.ClaudeCodeOnly()
 	case group.FieldFallbackGroupID:
@@ -15264,6 +15967,10 @@ func (m *GroupMutation) OldField(ctx context.Context, name string) (ent.Value, e
 		return m.OldVideoPricePerRequest(ctx)
 	case group.FieldVideoPricePerRequestHd:
 		return m.OldVideoPricePerRequestHd(ctx)
+	case group.FieldAudioPricePerMinute:
+		return m.OldAudioPricePerMinute(ctx)
+	case group.FieldAudioSpeechPricePer1mChars:
+		return m.OldAudioSpeechPricePer1mChars(ctx)
 	case group.FieldClaudeCodeOnly:
 		return m.OldClaudeCodeOnly(ctx)
 	case group.FieldFallbackGroupID:
@@ -15360,21 +16067,21 @@ func (m *GroupMutation) SetField(name string, value ent.Value) error {
 		m.SetSubscriptionType(v)
 		return nil
 	case group.FieldDailyLimitUsd:
-		v, ok := value.(float64)
+		v, ok := value.(money.Amount)
 		if !ok {
 			return fmt.Errorf("unexpected type %T for field %s", value, name)
 		}
 		m.SetDailyLimitUsd(v)
 		return nil
 	case group.FieldWeeklyLimitUsd:
-		v, ok := value.(float64)
+		v, ok := value.(money.Amount)
 		if !ok {
 			return fmt.Errorf("unexpected type %T for field %s", value, name)
 		}
 		m.SetWeeklyLimitUsd(v)
 		return nil
 	case group.FieldMonthlyLimitUsd:
-		v, ok := value.(float64)
+		v, ok := value.(money.Amount)
 		if !ok {
 			return fmt.Errorf("unexpected type %T for field %s", value, name)
 		}
@@ -15388,49 +16095,49 @@ func (m *GroupMutation) SetField(name string, value ent.Value) error {
 		m.SetDefaultValidityDays(v)
 		return nil
 	case group.FieldImagePrice1k:
-		v, ok := value.(float64)
+		v, ok := value.(money.Amount)
 		if !ok {
 			return fmt.Errorf("unexpected type %T for field %s", value, name)
 		}
 		m.SetImagePrice1k(v)
 		return nil
 	case group.FieldImagePrice2k:
-		v, ok := value.(float64)
+		v, ok := value.(money.Amount)
 		if !ok {
 			return fmt.Errorf("unexpected type %T for field %s", value, name)
 		}
 		m.SetImagePrice2k(v)
 		return nil
 	case group.FieldImagePrice4k:
-		v, ok := value.(float64)
+		v, ok := value.(money.Amount)
 		if !ok {
 			return fmt.Errorf("unexpected type %T for field %s", value, name)
 		}
 		m.SetImagePrice4k(v)
 		return nil
 	case group.FieldSoraImagePrice360:
-		v, ok := value.(float64)
+		v, ok := value.(money.Amount)
 		if !ok {
 			return fmt.Errorf("unexpected type %T for field %s", value, name)
 		}
 		m.SetSoraImagePrice360(v)
 		return nil
 	case group.FieldSoraImagePrice540:
-		v, ok := value.(float64)
+		v, ok := value.(money.Amount)
 		if !ok {
 			return fmt.Errorf("unexpected type %T for field %s", value, name)
 		}
 		m.SetSoraImagePrice540(v)
 		return nil
 	case group.FieldSoraVideoPricePerRequest:
-		v, ok := value.(float64)
+		v, ok := value.(money.Amount)
 		if !ok {
 			return fmt.Errorf("unexpected type %T for field %s", value, name)
 		}
 		m.SetSoraVideoPricePerRequest(v)
 		return nil
 	case group.FieldSoraVideoPricePerRequestHd:
-		v, ok := value.(float64)
+		v, ok := value.(money.Amount)
 		if !ok {
 			return fmt.Errorf("unexpected type %T for field %s", value, name)
 		}
@@ -15444,19 +16151,33 @@ func (m *GroupMutation) SetField(name string, value ent.Value) error {
 		m.SetSoraStorageQuotaBytes(v)
 		return nil
 	case group.FieldVideoPricePerRequest:
-		v, ok := value.(float64)
+		v, ok := value.(money.Amount)
 		if !ok {
 			return fmt.Errorf("unexpected type %T for field %s", value, name)
 		}
 		m.SetVideoPricePerRequest(v)
 		return nil
 	case group.FieldVideoPricePerRequestHd:
-		v, ok := value.(float64)
+		v, ok := value.(money.Amount)
 		if !ok {
 			return fmt.Errorf("unexpected type %T for field %s", value, name)
 		}
 		m.SetVideoPricePerRequestHd(v)
 		return nil
+	case group.FieldAudioPricePerMinute:
+		v, ok := value.(money.Amount)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetAudioPricePerMinute(v)
+		return nil
+	case group.FieldAudioSpeechPricePer1mChars:
+		v, ok := value.(money.Amount)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetAudioSpeechPricePer1mChars(v)
+		return nil
 	case group.FieldClaudeCodeOnly:
 		v, ok := value.(bool)
 		if !ok {
@@ -15566,6 +16287,12 @@ func (m *GroupMutation) AddedFields() []string {
 	if m.addvideo_price_per_request_hd != nil {
 		fields = append(fields, group.FieldVideoPricePerRequestHd)
 	}
+	if m.addaudio_price_per_minute != nil {
+		fields = append(fields, group.FieldAudioPricePerMinute)
+	}
+	if m.addaudio_speech_price_per_1m_chars != nil {
+		fields = append(fields, group.FieldAudioSpeechPricePer1mChars)
+	}
 	if m.addfallback_group_id != nil {
 		fields = append(fields, group.FieldFallbackGroupID)
 	}
@@ -15613,6 +16340,10 @@ func (m *GroupMutation) AddedField(name string) (ent.Value, bool) {
 		return m.AddedVideoPricePerRequest()
 	case group.FieldVideoPricePerRequestHd:
 		return m.AddedVideoPricePerRequestHd()
+	case group.FieldAudioPricePerMinute:
+		return m.AddedAudioPricePerMinute()
+	case group.FieldAudioSpeechPricePer1mChars:
+		return m.AddedAudioSpeechPricePer1mChars()
 	case group.FieldFallbackGroupID:
 		return m.AddedFallbackGroupID()
 	case group.FieldFallbackGroupIDOnInvalidRequest:
@@ -15636,21 +16367,21 @@ func (m *GroupMutation) AddField(name string, value ent.Value) error {
 		m.AddRateMultiplier(v)
 		return nil
 	case group.FieldDailyLimitUsd:
-		v, ok := value.(float64)
+		v, ok := value.(money.Amount)
 		if !ok {
 			return fmt.Errorf("unexpected type %T for field %s", value, name)
 		}
 		m.AddDailyLimitUsd(v)
 		return nil
 	case group.FieldWeeklyLimitUsd:
-		v, ok := value.(float64)
+		v, ok := value.(money.Amount)
 		if !ok {
 			return fmt.Errorf("unexpected type %T for field %s", value, name)
 		}
 		m.AddWeeklyLimitUsd(v)
 		return nil
 	case group.FieldMonthlyLimitUsd:
-		v, ok := value.(float64)
+		v, ok := value.(money.Amount)
 		if !ok {
 			return fmt.Errorf("unexpected type %T for field %s", value, name)
 		}
@@ -15664,49 +16395,49 @@ func (m *GroupMutation) AddField(name string, value ent.Value) error {
 		m.AddDefaultValidityDays(v)
 		return nil
 	case group.FieldImagePrice1k:
-		v, ok := value.(float64)
+		v, ok := value.(money.Amount)
 		if !ok {
 			return fmt.Errorf("unexpected type %T for field %s", value, name)
 		}
 		m.AddImagePrice1k(v)
 		return nil
 	case group.FieldImagePrice2k:
-		v, ok := value.(float64)
+		v, ok := value.(money.Amount)
 		if !ok {
 			return fmt.Errorf("unexpected type %T for field %s", value, name)
 		}
 		m.AddImagePrice2k(v)
 		return nil
 	case group.FieldImagePrice4k:
-		v, ok := value.(float64)
+		v, ok := value.(money.Amount)
 		if !ok {
 			return fmt.Errorf("unexpected type %T for field %s", value, name)
 		}
 		m.AddImagePrice4k(v)
 		return nil
 	case group.FieldSoraImagePrice360:
-		v, ok := value.(float64)
+		v, ok := value.(money.Amount)
 		if !ok {
 			return fmt.Errorf("unexpected type %T for field %s", value, name)
 		}
 		m.AddSoraImagePrice360(v)
 		return nil
 	case group.FieldSoraImagePrice540:
-		v, ok := value.(float64)
+		v, ok := value.(money.Amount)
 		if !ok {
 			return fmt.Errorf("unexpected type %T for field %s", value, name)
 		}
 		m.AddSoraImagePrice540(v)
 		return nil
 	case group.FieldSoraVideoPricePerRequest:
-		v, ok := value.(float64)
+		v, ok := value.(money.Amount)
 		if !ok {
 			return fmt.Errorf("unexpected type %T for field %s", value, name)
 		}
 		m.AddSoraVideoPricePerRequest(v)
 		return nil
 	case group.FieldSoraVideoPricePerRequestHd:
-		v, ok := value.(float64)
+		v, ok := value.(money.Amount)
 		if !ok {
 			return fmt.Errorf("unexpected type %T for field %s", value, name)
 		}
@@ -15720,19 +16451,33 @@ func (m *GroupMutation) AddField(name string, value ent.Value) error {
 		m.AddSoraStorageQuotaBytes(v)
 		return nil
 	case group.FieldVideoPricePerRequest:
-		v, ok := value.(float64)
+		v, ok := value.(money.Amount)
 		if !ok {
 			return fmt.Errorf("unexpected type %T for field %s", value, name)
 		}
 		m.AddVideoPricePerRequest(v)
 		return nil
 	case group.FieldVideoPricePerRequestHd:
-		v, ok := value.(float64)
+		v, ok := value.(money.Amount)
 		if !ok {
 			return fmt.Errorf("unexpected type %T for field %s", value, name)
 		}
 		m.AddVideoPricePerRequestHd(v)
 		return nil
+	case group.FieldAudioPricePerMinute:
+		v, ok := value.(money.Amount)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.AddAudioPricePerMinute(v)
+		return nil
+	case group.FieldAudioSpeechPricePer1mChars:
+		v, ok := value.(money.Amount)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.AddAudioSpeechPricePer1mChars(v)
+		return nil
 	case group.FieldFallbackGroupID:
 		v, ok := value.(int64)
 		if !ok {
@@ -15804,6 +16549,12 @@ func (m *GroupMutation) ClearedFields() []string {
 	if m.FieldCleared(group.FieldVideoPricePerRequestHd) {
 		fields = append(fields, group.FieldVideoPricePerRequestHd)
 	}
+	if m.FieldCleared(group.FieldAudioPricePerMinute) {
+		fields = append(fields, group.FieldAudioPricePerMinute)
+	}
+	if m.FieldCleared(group.FieldAudioSpeechPricePer1mChars) {
+		fields = append(fields, group.FieldAudioSpeechPricePer1mChars)
+	}
 	if m.FieldCleared(group.FieldFallbackGroupID) {
 		fields = append(fields, group.FieldFallbackGroupID)
 	}
@@ -15869,6 +16620,12 @@ func (m *GroupMutation) ClearField(name string) error {
 	case group.FieldVideoPricePerRequestHd:
 		m.ClearVideoPricePerRequestHd()
 		return nil
+	case group.FieldAudioPricePerMinute:
+		m.ClearAudioPricePerMinute()
+		return nil
+	case group.FieldAudioSpeechPricePer1mChars:
+		m.ClearAudioSpeechPricePer1mChars()
+		return nil
 	case group.FieldFallbackGroupID:
 		m.ClearFallbackGroupID()
 		return nil
@@ -15958,6 +16715,12 @@ func (m *GroupMutation) ResetField(name string) error {
 	case group.FieldVideoPricePerRequestHd:
 		m.ResetVideoPricePerRequestHd()
 		return nil
+	case group.FieldAudioPricePerMinute:
+		m.ResetAudioPricePerMinute()
+		return nil
+	case group.FieldAudioSpeechPricePer1mChars:
+		m.ResetAudioSpeechPricePer1mChars()
+		return nil
 	case group.FieldClaudeCodeOnly:
 		m.ResetClaudeCodeOnly()
 		return nil
@@ -17185,28 +17948,44 @@ func (m *IdempotencyRecordMutation) ResetEdge(name string) error {
 // PromoCodeMutation represents an operation that mutates the PromoCode nodes in the graph.
 type PromoCodeMutation struct {
 	config
-	op                   Op
-	typ                  string
-	id                   *int64
-	code                 *string
-	bonus_amount         *float64
-	addbonus_amount      *float64
-	max_uses             *int
-	addmax_uses          *int
-	used_count           *int
-	addused_count        *int
-	status               *string
-	expires_at           *time.Time
-	notes                *string
-	created_at           *time.Time
-	updated_at           *time.Time
-	clearedFields        map[string]struct{}
-	usage_records        map[int64]struct{}
-	removedusage_records map[int64]struct{}
-	clearedusage_records bool
-	done                 bool
-	oldValue             func(context.Context) (*PromoCode, error)
-	predicates           []predicate.PromoCode
+	op                          Op
+	typ                         string
+	id                          *int64
+	code                        *string
+	bonus_amount                *money.Amount
+	addbonus_amount             *money.Amount
+	max_uses                    *int
+	addmax_uses                 *int
+	used_count                  *int
+	addused_count               *int
+	topup_bonus_percent         *float64
+	addtopup_bonus_percent      *float64
+	topup_bonus_max             *money.Amount
+	addtopup_bonus_max          *money.Amount
+	min_topup_amount            *money.Amount
+	addmin_topup_amount         *money.Amount
+	per_user_limit              *int
+	addper_user_limit           *int
+	allowed_email_domains       *[]string
+	appendallowed_email_domains []string
+	required_attributes         *map[string]string
+	stackable                   *bool
+	trial_group_id              *int64
+	addtrial_group_id           *int64
+	trial_days                  *int
+	addtrial_days               *int
+	status                      *string
+	expires_at                  *time.Time
+	notes                       *string
+	created_at                  *time.Time
+	updated_at                  *time.Time
+	clearedFields               map[string]struct{}
+	usage_records               map[int64]struct{}
+	removedusage_records        map[int64]struct{}
+	clearedusage_records        bool
+	done                        bool
+	oldValue                    func(context.Context) (*PromoCode, error)
+	predicates                  []predicate.PromoCode
 }
 
 var _ ent.Mutation = (*PromoCodeMutation)(nil)
@@ -17344,13 +18123,13 @@ func (m *PromoCodeMutation) ResetCode() {
 }
 
 // SetBonusAmount sets the "bonus_amount" field.
-func (m *PromoCodeMutation) SetBonusAmount(f float64) {
-	m.bonus_amount = &f
+func (m *PromoCodeMutation) SetBonusAmount(value money.Amount) {
+	m.bonus_amount = &value
 	m.addbonus_amount = nil
 }
 
 // BonusAmount returns the value of the "bonus_amount" field in the mutation.
-func (m *PromoCodeMutation) BonusAmount() (r float64, exists bool) {
+func (m *PromoCodeMutation) BonusAmount() (r money.Amount, exists bool) {
 	v := m.bonus_amount
 	if v == nil {
 		return
@@ -17361,7 +18140,7 @@ func (m *PromoCodeMutation) BonusAmount() (r float64, exists bool) {
 // OldBonusAmount returns the old "bonus_amount" field's value of the PromoCode entity.
 // If the PromoCode object wasn't provided to the builder, the object is fetched from the database.
 // An error is returned if the mutation operation is not UpdateOne, or the database query fails.
-func (m *PromoCodeMutation) OldBonusAmount(ctx context.Context) (v float64, err error) {
+func (m *PromoCodeMutation) OldBonusAmount(ctx context.Context) (v money.Amount, err error) {
 	if !m.op.Is(OpUpdateOne) {
 		return v, errors.New("OldBonusAmount is only allowed on UpdateOne operations")
 	}
@@ -17375,17 +18154,17 @@ func (m *PromoCodeMutation) OldBonusAmount(ctx context.Context) (v float64, err
 	return oldValue.BonusAmount, nil
 }
 
-// AddBonusAmount adds f to the "bonus_amount" field.
-func (m *PromoCodeMutation) AddBonusAmount(f float64) {
+// AddBonusAmount adds value to the "bonus_amount" field.
+func (m *PromoCodeMutation) AddBonusAmount(value money.Amount) {
 	if m.addbonus_amount != nil {
-		*m.addbonus_amount += f
+		*m.addbonus_amount += value
 	} else {
-		m.addbonus_amount = &f
+		m.addbonus_amount = &value
 	}
 }
 
 // AddedBonusAmount returns the value that was added to the "bonus_amount" field in this mutation.
-func (m *PromoCodeMutation) AddedBonusAmount() (r float64, exists bool) {
+func (m *PromoCodeMutation) AddedBonusAmount() (r money.Amount, exists bool) {
 	v := m.addbonus_amount
 	if v == nil {
 		return
@@ -17511,141 +18290,641 @@ func (m *PromoCodeMutation) ResetUsedCount() {
 	m.addused_count = nil
 }
 
-// SetStatus sets the "status" field.
-func (m *PromoCodeMutation) SetStatus(s string) {
-	m.status = &s
+// SetTopupBonusPercent sets the "topup_bonus_percent" field.
+func (m *PromoCodeMutation) SetTopupBonusPercent(f float64) {
+	m.topup_bonus_percent = &f
+	m.addtopup_bonus_percent = nil
 }
 
-// Status returns the value of the "status" field in the mutation.
-func (m *PromoCodeMutation) Status() (r string, exists bool) {
-	v := m.status
+// TopupBonusPercent returns the value of the "topup_bonus_percent" field in the mutation.
+func (m *PromoCodeMutation) TopupBonusPercent() (r float64, exists bool) {
+	v := m.topup_bonus_percent
 	if v == nil {
 		return
 	}
 	return *v, true
 }
 
-// OldStatus returns the old "status" field's value of the PromoCode entity.
+// OldTopupBonusPercent returns the old "topup_bonus_percent" field's value of the PromoCode entity.
 // If the PromoCode object wasn't provided to the builder, the object is fetched from the database.
 // An error is returned if the mutation operation is not UpdateOne, or the database query fails.
-func (m *PromoCodeMutation) OldStatus(ctx context.Context) (v string, err error) {
+func (m *PromoCodeMutation) OldTopupBonusPercent(ctx context.Context) (v float64, err error) {
 	if !m.op.Is(OpUpdateOne) {
-		return v, errors.New("OldStatus is only allowed on UpdateOne operations")
+		return v, errors.New("OldTopupBonusPercent is only allowed on UpdateOne operations")
 	}
 	if m.id == nil || m.oldValue == nil {
-		return v, errors.New("OldStatus requires an ID field in the mutation")
+		return v, errors.New("OldTopupBonusPercent requires an ID field in the mutation")
 	}
 	oldValue, err := m.oldValue(ctx)
 	if err != nil {
-		return v, fmt.Errorf("querying old value for OldStatus: %w", err)
+		return v, fmt.Errorf("querying old value for OldTopupBonusPercent: %w", err)
 	}
-	return oldValue.Status, nil
+	return oldValue.TopupBonusPercent, nil
 }
 
-// ResetStatus resets all changes to the "status" field.
-func (m *PromoCodeMutation) ResetStatus() {
-	m.status = nil
+// AddTopupBonusPercent adds f to the "topup_bonus_percent" field.
+func (m *PromoCodeMutation) AddTopupBonusPercent(f float64) {
+	if m.addtopup_bonus_percent != nil {
+		*m.addtopup_bonus_percent += f
+	} else {
+		m.addtopup_bonus_percent = &f
+	}
 }
 
-// SetExpiresAt sets the "expires_at" field.
-func (m *PromoCodeMutation) SetExpiresAt(t time.Time) {
-	m.expires_at = &t
+// AddedTopupBonusPercent returns the value that was added to the "topup_bonus_percent" field in this mutation.
+func (m *PromoCodeMutation) AddedTopupBonusPercent() (r float64, exists bool) {
+	v := m.addtopup_bonus_percent
+	if v == nil {
+		return
+	}
+	return *v, true
 }
 
-// ExpiresAt returns the value of the "expires_at" field in the mutation.
-func (m *PromoCodeMutation) ExpiresAt() (r time.Time, exists bool) {
-	v := m.expires_at
+// ResetTopupBonusPercent resets all changes to the "topup_bonus_percent" field.
+func (m *PromoCodeMutation) ResetTopupBonusPercent() {
+	m.topup_bonus_percent = nil
+	m.addtopup_bonus_percent = nil
+}
+
+// SetTopupBonusMax sets the "topup_bonus_max" field.
+func (m *PromoCodeMutation) SetTopupBonusMax(value money.Amount) {
+	m.topup_bonus_max = &value
+	m.addtopup_bonus_max = nil
+}
+
+// TopupBonusMax returns the value of the "topup_bonus_max" field in the mutation.
+func (m *PromoCodeMutation) TopupBonusMax() (r money.Amount, exists bool) {
+	v := m.topup_bonus_max
 	if v == nil {
 		return
 	}
 	return *v, true
 }
 
-// OldExpiresAt returns the old "expires_at" field's value of the PromoCode entity.
+// OldTopupBonusMax returns the old "topup_bonus_max" field's value of the PromoCode entity.
 // If the PromoCode object wasn't provided to the builder, the object is fetched from the database.
 // An error is returned if the mutation operation is not UpdateOne, or the database query fails.
-func (m *PromoCodeMutation) OldExpiresAt(ctx context.Context) (v *time.Time, err error) {
+func (m *PromoCodeMutation) OldTopupBonusMax(ctx context.Context) (v money.Amount, err error) {
 	if !m.op.Is(OpUpdateOne) {
-		return v, errors.New("OldExpiresAt is only allowed on UpdateOne operations")
+		return v, errors.New("OldTopupBonusMax is only allowed on UpdateOne operations")
 	}
 	if m.id == nil || m.oldValue == nil {
-		return v, errors.New("OldExpiresAt requires an ID field in the mutation")
+		return v, errors.New("OldTopupBonusMax requires an ID field in the mutation")
 	}
 	oldValue, err := m.oldValue(ctx)
 	if err != nil {
-		return v, fmt.Errorf("querying old value for OldExpiresAt: %w", err)
+		return v, fmt.Errorf("querying old value for OldTopupBonusMax: %w", err)
 	}
-	return oldValue.ExpiresAt, nil
+	return oldValue.TopupBonusMax, nil
 }
 
-// ClearExpiresAt clears the value of the "expires_at" field.
-func (m *PromoCodeMutation) ClearExpiresAt() {
-	m.expires_at = nil
-	m.clearedFields[promocode.FieldExpiresAt] = struct{}{}
+// AddTopupBonusMax adds value to the "topup_bonus_max" field.
+func (m *PromoCodeMutation) AddTopupBonusMax(value money.Amount) {
+	if m.addtopup_bonus_max != nil {
+		*m.addtopup_bonus_max += value
+	} else {
+		m.addtopup_bonus_max = &value
+	}
 }
 
-// ExpiresAtCleared returns if the "expires_at" field was cleared in this mutation.
-func (m *PromoCodeMutation) ExpiresAtCleared() bool {
-	_, ok := m.clearedFields[promocode.FieldExpiresAt]
-	return ok
+// AddedTopupBonusMax returns the value that was added to the "topup_bonus_max" field in this mutation.
+func (m *PromoCodeMutation) AddedTopupBonusMax() (r money.Amount, exists bool) {
+	v := m.addtopup_bonus_max
+	if v == nil {
+		return
+	}
+	return *v, true
 }
 
-// ResetExpiresAt resets all changes to the "expires_at" field.
-func (m *PromoCodeMutation) ResetExpiresAt() {
-	m.expires_at = nil
-	delete(m.clearedFields, promocode.FieldExpiresAt)
+// ResetTopupBonusMax resets all changes to the "topup_bonus_max" field.
+func (m *PromoCodeMutation) ResetTopupBonusMax() {
+	m.topup_bonus_max = nil
+	m.addtopup_bonus_max = nil
 }
 
-// SetNotes sets the "notes" field.
-func (m *PromoCodeMutation) SetNotes(s string) {
-	m.notes = &s
+// SetMinTopupAmount sets the "min_topup_amount" field.
+func (m *PromoCodeMutation) SetMinTopupAmount(value money.Amount) {
+	m.min_topup_amount = &value
+	m.addmin_topup_amount = nil
 }
 
-// Notes returns the value of the "notes" field in the mutation.
-func (m *PromoCodeMutation) Notes() (r string, exists bool) {
-	v := m.notes
+// MinTopupAmount returns the value of the "min_topup_amount" field in the mutation.
+func (m *PromoCodeMutation) MinTopupAmount() (r money.Amount, exists bool) {
+	v := m.min_topup_amount
 	if v == nil {
 		return
 	}
 	return *v, true
 }
 
-// OldNotes returns the old "notes" field's value of the PromoCode entity.
+// OldMinTopupAmount returns the old "min_topup_amount" field's value of the PromoCode entity.
 // If the PromoCode object wasn't provided to the builder, the object is fetched from the database.
 // An error is returned if the mutation operation is not UpdateOne, or the database query fails.
-func (m *PromoCodeMutation) OldNotes(ctx context.Context) (v *string, err error) {
+func (m *PromoCodeMutation) OldMinTopupAmount(ctx context.Context) (v money.Amount, err error) {
 	if !m.op.Is(OpUpdateOne) {
-		return v, errors.New("OldNotes is only allowed on UpdateOne operations")
+		return v, errors.New("OldMinTopupAmount is only allowed on UpdateOne operations")
 	}
 	if m.id == nil || m.oldValue == nil {
-		return v, errors.New("OldNotes requires an ID field in the mutation")
+		return v, errors.New("OldMinTopupAmount requires an ID field in the mutation")
 	}
 	oldValue, err := m.oldValue(ctx)
 	if err != nil {
-		return v, fmt.Errorf("querying old value for OldNotes: %w", err)
+		return v, fmt.Errorf("querying old value for OldMinTopupAmount: %w", err)
 	}
-	return oldValue.Notes, nil
+	return oldValue.MinTopupAmount, nil
 }
 
-// ClearNotes clears the value of the "notes" field.
-func (m *PromoCodeMutation) ClearNotes() {
-	m.notes = nil
-	m.clearedFields[promocode.FieldNotes] = struct{}{}
+// AddMinTopupAmount adds value to the "min_topup_amount" field.
+func (m *PromoCodeMutation) AddMinTopupAmount(value money.Amount) {
+	if m.addmin_topup_amount != nil {
+		*m.addmin_topup_amount += value
+	} else {
+		m.addmin_topup_amount = &value
+	}
 }
 
-// NotesCleared returns if the "notes" field was cleared in this mutation.
-func (m *PromoCodeMutation) NotesCleared() bool {
-	_, ok := m.clearedFields[promocode.FieldNotes]
-	return ok
+// AddedMinTopupAmount returns the value that was added to the "min_topup_amount" field in this mutation.
+func (m *PromoCodeMutation) AddedMinTopupAmount() (r money.Amount, exists bool) {
+	v := m.addmin_topup_amount
+	if v == nil {
+		return
+	}
+	return *v, true
 }
 
-// ResetNotes resets all changes to the "notes" field.
-func (m *PromoCodeMutation) ResetNotes() {
-	m.notes = nil
-	delete(m.clearedFields, promocode.FieldNotes)
+// ResetMinTopupAmount resets all changes to the "min_topup_amount" field.
+func (m *PromoCodeMutation) ResetMinTopupAmount() {
+	m.min_topup_amount = nil
+	m.addmin_topup_amount = nil
 }
 
-// SetCreatedAt sets the "created_at" field.
+// SetPerUserLimit sets the "per_user_limit" field.
+func (m *PromoCodeMutation) SetPerUserLimit(i int) {
+	m.per_user_limit = &i
+	m.addper_user_limit = nil
+}
+
+// PerUserLimit returns the value of the "per_user_limit" field in the mutation.
+func (m *PromoCodeMutation) PerUserLimit() (r int, exists bool) {
+	v := m.per_user_limit
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldPerUserLimit returns the old "per_user_limit" field's value of the PromoCode entity.
+// If the PromoCode object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *PromoCodeMutation) OldPerUserLimit(ctx context.Context) (v int, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldPerUserLimit is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldPerUserLimit requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldPerUserLimit: %w", err)
+	}
+	return oldValue.PerUserLimit, nil
+}
+
+// AddPerUserLimit adds i to the "per_user_limit" field.
+func (m *PromoCodeMutation) AddPerUserLimit(i int) {
+	if m.addper_user_limit != nil {
+		*m.addper_user_limit += i
+	} else {
+		m.addper_user_limit = &i
+	}
+}
+
+// AddedPerUserLimit returns the value that was added to the "per_user_limit" field in this mutation.
+func (m *PromoCodeMutation) AddedPerUserLimit() (r int, exists bool) {
+	v := m.addper_user_limit
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// ResetPerUserLimit resets all changes to the "per_user_limit" field.
+func (m *PromoCodeMutation) ResetPerUserLimit() {
+	m.per_user_limit = nil
+	m.addper_user_limit = nil
+}
+
+// SetAllowedEmailDomains sets the "allowed_email_domains" field.
+func (m *PromoCodeMutation) SetAllowedEmailDomains(s []string) {
+	m.allowed_email_domains = &s
+	m.appendallowed_email_domains = nil
+}
+
+// AllowedEmailDomains returns the value of the "allowed_email_domains" field in the mutation.
+func (m *PromoCodeMutation) AllowedEmailDomains() (r []string, exists bool) {
+	v := m.allowed_email_domains
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldAllowedEmailDomains returns the old "allowed_email_domains" field's value of the PromoCode entity.
+// If the PromoCode object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *PromoCodeMutation) OldAllowedEmailDomains(ctx context.Context) (v []string, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldAllowedEmailDomains is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldAllowedEmailDomains requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldAllowedEmailDomains: %w", err)
+	}
+	return oldValue.AllowedEmailDomains, nil
+}
+
+// AppendAllowedEmailDomains adds s to the "allowed_email_domains" field.
+func (m *PromoCodeMutation) AppendAllowedEmailDomains(s []string) {
+	m.appendallowed_email_domains = append(m.appendallowed_email_domains, s...)
+}
+
+// AppendedAllowedEmailDomains returns the list of values that were appended to the "allowed_email_domains" field in this mutation.
+func (m *PromoCodeMutation) AppendedAllowedEmailDomains() ([]string, bool) {
+	if len(m.appendallowed_email_domains) == 0 {
+		return nil, false
+	}
+	return m.appendallowed_email_domains, true
+}
+
+// ClearAllowedEmailDomains clears the value of the "allowed_email_domains" field.
+func (m *PromoCodeMutation) ClearAllowedEmailDomains() {
+	m.allowed_email_domains = nil
+	m.appendallowed_email_domains = nil
+	m.clearedFields[promocode.FieldAllowedEmailDomains] = struct{}{}
+}
+
+// AllowedEmailDomainsCleared returns if the "allowed_email_domains" field was cleared in this mutation.
+func (m *PromoCodeMutation) AllowedEmailDomainsCleared() bool {
+	_, ok := m.clearedFields[promocode.FieldAllowedEmailDomains]
+	return ok
+}
+
+// ResetAllowedEmailDomains resets all changes to the "allowed_email_domains" field.
+func (m *PromoCodeMutation) ResetAllowedEmailDomains() {
+	m.allowed_email_domains = nil
+	m.appendallowed_email_domains = nil
+	delete(m.clearedFields, promocode.FieldAllowedEmailDomains)
+}
+
+// SetRequiredAttributes sets the "required_attributes" field.
+func (m *PromoCodeMutation) SetRequiredAttributes(value map[string]string) {
+	m.required_attributes = &value
+}
+
+// RequiredAttributes returns the value of the "required_attributes" field in the mutation.
+func (m *PromoCodeMutation) RequiredAttributes() (r map[string]string, exists bool) {
+	v := m.required_attributes
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldRequiredAttributes returns the old "required_attributes" field's value of the PromoCode entity.
+// If the PromoCode object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *PromoCodeMutation) OldRequiredAttributes(ctx context.Context) (v map[string]string, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldRequiredAttributes is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldRequiredAttributes requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldRequiredAttributes: %w", err)
+	}
+	return oldValue.RequiredAttributes, nil
+}
+
+// ClearRequiredAttributes clears the value of the "required_attributes" field.
+func (m *PromoCodeMutation) ClearRequiredAttributes() {
+	m.required_attributes = nil
+	m.clearedFields[promocode.FieldRequiredAttributes] = struct{}{}
+}
+
+// RequiredAttributesCleared returns if the "required_attributes" field was cleared in this mutation.
+func (m *PromoCodeMutation) RequiredAttributesCleared() bool {
+	_, ok := m.clearedFields[promocode.FieldRequiredAttributes]
+	return ok
+}
+
+// ResetRequiredAttributes resets all changes to the "required_attributes" field.
+func (m *PromoCodeMutation) ResetRequiredAttributes() {
+	m.required_attributes = nil
+	delete(m.clearedFields, promocode.FieldRequiredAttributes)
+}
+
+// SetStackable sets the "stackable" field.
+func (m *PromoCodeMutation) SetStackable(b bool) {
+	m.stackable = &b
+}
+
+// Stackable returns the value of the "stackable" field in the mutation.
+func (m *PromoCodeMutation) Stackable() (r bool, exists bool) {
+	v := m.stackable
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldStackable returns the old "stackable" field's value of the PromoCode entity.
+// If the PromoCode object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *PromoCodeMutation) OldStackable(ctx context.Context) (v bool, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldStackable is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldStackable requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldStackable: %w", err)
+	}
+	return oldValue.Stackable, nil
+}
+
+// ResetStackable resets all changes to the "stackable" field.
+func (m *PromoCodeMutation) ResetStackable() {
+	m.stackable = nil
+}
+
+// SetTrialGroupID sets the "trial_group_id" field.
+func (m *PromoCodeMutation) SetTrialGroupID(i int64) {
+	m.trial_group_id = &i
+	m.addtrial_group_id = nil
+}
+
+// TrialGroupID returns the value of the "trial_group_id" field in the mutation.
+func (m *PromoCodeMutation) TrialGroupID() (r int64, exists bool) {
+	v := m.trial_group_id
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldTrialGroupID returns the old "trial_group_id" field's value of the PromoCode entity.
+// If the PromoCode object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *PromoCodeMutation) OldTrialGroupID(ctx context.Context) (v *int64, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldTrialGroupID is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldTrialGroupID requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldTrialGroupID: %w", err)
+	}
+	return oldValue.TrialGroupID, nil
+}
+
+// AddTrialGroupID adds i to the "trial_group_id" field.
+func (m *PromoCodeMutation) AddTrialGroupID(i int64) {
+	if m.addtrial_group_id != nil {
+		*m.addtrial_group_id += i
+	} else {
+		m.addtrial_group_id = &i
+	}
+}
+
+// AddedTrialGroupID returns the value that was added to the "trial_group_id" field in this mutation.
+func (m *PromoCodeMutation) AddedTrialGroupID() (r int64, exists bool) {
+	v := m.addtrial_group_id
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// ClearTrialGroupID clears the value of the "trial_group_id" field.
+func (m *PromoCodeMutation) ClearTrialGroupID() {
+	m.trial_group_id = nil
+	m.addtrial_group_id = nil
+	m.clearedFields[promocode.FieldTrialGroupID] = struct{}{}
+}
+
+// TrialGroupIDCleared returns if the "trial_group_id" field was cleared in this mutation.
+func (m *PromoCodeMutation) TrialGroupIDCleared() bool {
+	_, ok := m.clearedFields[promocode.FieldTrialGroupID]
+	return ok
+}
+
+// ResetTrialGroupID resets all changes to the "trial_group_id" field.
+func (m *PromoCodeMutation) ResetTrialGroupID() {
+	m.trial_group_id = nil
+	m.addtrial_group_id = nil
+	delete(m.clearedFields, promocode.FieldTrialGroupID)
+}
+
+// SetTrialDays sets the "trial_days" field.
+func (m *PromoCodeMutation) SetTrialDays(i int) {
+	m.trial_days = &i
+	m.addtrial_days = nil
+}
+
+// TrialDays returns the value of the "trial_days" field in the mutation.
+func (m *PromoCodeMutation) TrialDays() (r int, exists bool) {
+	v := m.trial_days
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldTrialDays returns the old "trial_days" field's value of the PromoCode entity.
+// If the PromoCode object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *PromoCodeMutation) OldTrialDays(ctx context.Context) (v int, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldTrialDays is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldTrialDays requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldTrialDays: %w", err)
+	}
+	return oldValue.TrialDays, nil
+}
+
+// AddTrialDays adds i to the "trial_days" field.
+func (m *PromoCodeMutation) AddTrialDays(i int) {
+	if m.addtrial_days != nil {
+		*m.addtrial_days += i
+	} else {
+		m.addtrial_days = &i
+	}
+}
+
+// AddedTrialDays returns the value that was added to the "trial_days" field in this mutation.
+func (m *PromoCodeMutation) AddedTrialDays() (r int, exists bool) {
+	v := m.addtrial_days
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// ResetTrialDays resets all changes to the "trial_days" field.
+func (m *PromoCodeMutation) ResetTrialDays() {
+	m.trial_days = nil
+	m.addtrial_days = nil
+}
+
+// SetStatus sets the "status" field.
+func (m *PromoCodeMutation) SetStatus(s string) {
+	m.status = &s
+}
+
+// Status returns the value of the "status" field in the mutation.
+func (m *PromoCodeMutation) Status() (r string, exists bool) {
+	v := m.status
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldStatus returns the old "status" field's value of the PromoCode entity.
+// If the PromoCode object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *PromoCodeMutation) OldStatus(ctx context.Context) (v string, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldStatus is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldStatus requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldStatus: %w", err)
+	}
+	return oldValue.Status, nil
+}
+
+// ResetStatus resets all changes to the "status" field.
+func (m *PromoCodeMutation) ResetStatus() {
+	m.status = nil
+}
+
+// SetExpiresAt sets the "expires_at" field.
+func (m *PromoCodeMutation) SetExpiresAt(t time.Time) {
+	m.expires_at = &t
+}
+
+// ExpiresAt returns the value of the "expires_at" field in the mutation.
+func (m *PromoCodeMutation) ExpiresAt() (r time.Time, exists bool) {
+	v := m.expires_at
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldExpiresAt returns the old "expires_at" field's value of the PromoCode entity.
+// If the PromoCode object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *PromoCodeMutation) OldExpiresAt(ctx context.Context) (v *time.Time, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldExpiresAt is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldExpiresAt requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldExpiresAt: %w", err)
+	}
+	return oldValue.ExpiresAt, nil
+}
+
+// ClearExpiresAt clears the value of the "expires_at" field.
+func (m *PromoCodeMutation) ClearExpiresAt() {
+	m.expires_at = nil
+	m.clearedFields[promocode.FieldExpiresAt] = struct{}{}
+}
+
+// ExpiresAtCleared returns if the "expires_at" field was cleared in this mutation.
+func (m *PromoCodeMutation) ExpiresAtCleared() bool {
+	_, ok := m.clearedFields[promocode.FieldExpiresAt]
+	return ok
+}
+
+// ResetExpiresAt resets all changes to the "expires_at" field.
+func (m *PromoCodeMutation) ResetExpiresAt() {
+	m.expires_at = nil
+	delete(m.clearedFields, promocode.FieldExpiresAt)
+}
+
+// SetNotes sets the "notes" field.
+func (m *PromoCodeMutation) SetNotes(s string) {
+	m.notes = &s
+}
+
+// Notes returns the value of the "notes" field in the mutation.
+func (m *PromoCodeMutation) Notes() (r string, exists bool) {
+	v := m.notes
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldNotes returns the old "notes" field's value of the PromoCode entity.
+// If the PromoCode object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *PromoCodeMutation) OldNotes(ctx context.Context) (v *string, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldNotes is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldNotes requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldNotes: %w", err)
+	}
+	return oldValue.Notes, nil
+}
+
+// ClearNotes clears the value of the "notes" field.
+func (m *PromoCodeMutation) ClearNotes() {
+	m.notes = nil
+	m.clearedFields[promocode.FieldNotes] = struct{}{}
+}
+
+// NotesCleared returns if the "notes" field was cleared in this mutation.
+func (m *PromoCodeMutation) NotesCleared() bool {
+	_, ok := m.clearedFields[promocode.FieldNotes]
+	return ok
+}
+
+// ResetNotes resets all changes to the "notes" field.
+func (m *PromoCodeMutation) ResetNotes() {
+	m.notes = nil
+	delete(m.clearedFields, promocode.FieldNotes)
+}
+
+// SetCreatedAt sets the "created_at" field.
 func (m *PromoCodeMutation) SetCreatedAt(t time.Time) {
 	m.created_at = &t
 }
@@ -17805,7 +19084,7 @@ func (m *PromoCodeMutation) Type() string {
 // order to get all numeric fields that were incremented/decremented, call
 // AddedFields().
 func (m *PromoCodeMutation) Fields() []string {
-	fields := make([]string, 0, 9)
+	fields := make([]string, 0, 18)
 	if m.code != nil {
 		fields = append(fields, promocode.FieldCode)
 	}
@@ -17818,6 +19097,33 @@ func (m *PromoCodeMutation) Fields() []string {
 	if m.used_count != nil {
 		fields = append(fields, promocode.FieldUsedCount)
 	}
+	if m.topup_bonus_percent != nil {
+		fields = append(fields, promocode.FieldTopupBonusPercent)
+	}
+	if m.topup_bonus_max != nil {
+		fields = append(fields, promocode.FieldTopupBonusMax)
+	}
+	if m.min_topup_amount != nil {
+		fields = append(fields, promocode.FieldMinTopupAmount)
+	}
+	if m.per_user_limit != nil {
+		fields = append(fields, promocode.FieldPerUserLimit)
+	}
+	if m.allowed_email_domains != nil {
+		fields = append(fields, promocode.FieldAllowedEmailDomains)
+	}
+	if m.required_attributes != nil {
+		fields = append(fields, promocode.FieldRequiredAttributes)
+	}
+	if m.stackable != nil {
+		fields = append(fields, promocode.FieldStackable)
+	}
+	if m.trial_group_id != nil {
+		fields = append(fields, promocode.FieldTrialGroupID)
+	}
+	if m.trial_days != nil {
+		fields = append(fields, promocode.FieldTrialDays)
+	}
 	if m.status != nil {
 		fields = append(fields, promocode.FieldStatus)
 	}
@@ -17849,6 +19155,24 @@ func (m *PromoCodeMutation) Field(name string) (ent.Value, bool) {
 		return m.MaxUses()
 	case promocode.FieldUsedCount:
 		return m.UsedCount()
+	case promocode.FieldTopupBonusPercent:
+		return m.TopupBonusPercent()
+	case promocode.FieldTopupBonusMax:
+		return m.TopupBonusMax()
+	case promocode.FieldMinTopupAmount:
+		return m.MinTopupAmount()
+	case promocode.FieldPerUserLimit:
+		return m.PerUserLimit()
+	case promocode.FieldAllowedEmailDomains:
+		return m.AllowedEmailDomains()
+	case promocode.FieldRequiredAttributes:
+		return m.RequiredAttributes()
+	case promocode.FieldStackable:
+		return m.Stackable()
+	case promocode.FieldTrialGroupID:
+		return m.TrialGroupID()
+	case promocode.FieldTrialDays:
+		return m.TrialDays()
 	case promocode.FieldStatus:
 		return m.Status()
 	case promocode.FieldExpiresAt:
@@ -17876,6 +19200,24 @@ func (m *PromoCodeMutation) OldField(ctx context.Context, name string) (ent.Valu
 		return m.OldMaxUses(ctx)
 	case promocode.FieldUsedCount:
 		return m.OldUsedCount(ctx)
+	case promocode.FieldTopupBonusPercent:
+		return m.OldTopupBonusPercent(ctx)
+	case promocode.FieldTopupBonusMax:
+		return m.OldTopupBonusMax(ctx)
+	case promocode.FieldMinTopupAmount:
+		return m.OldMinTopupAmount(ctx)
+	case promocode.FieldPerUserLimit:
+		return m.OldPerUserLimit(ctx)
+	case promocode.FieldAllowedEmailDomains:
+		return m.OldAllowedEmailDomains(ctx)
+	case promocode.FieldRequiredAttributes:
+		return m.OldRequiredAttributes(ctx)
+	case promocode.FieldStackable:
+		return m.OldStackable(ctx)
+	case promocode.FieldTrialGroupID:
+		return m.OldTrialGroupID(ctx)
+	case promocode.FieldTrialDays:
+		return m.OldTrialDays(ctx)
 	case promocode.FieldStatus:
 		return m.OldStatus(ctx)
 	case promocode.FieldExpiresAt:
@@ -17903,7 +19245,7 @@ func (m *PromoCodeMutation) SetField(name string, value ent.Value) error {
 		m.SetCode(v)
 		return nil
 	case promocode.FieldBonusAmount:
-		v, ok := value.(float64)
+		v, ok := value.(money.Amount)
 		if !ok {
 			return fmt.Errorf("unexpected type %T for field %s", value, name)
 		}
@@ -17923,6 +19265,69 @@ func (m *PromoCodeMutation) SetField(name string, value ent.Value) error {
 		}
 		m.SetUsedCount(v)
 		return nil
+	case promocode.FieldTopupBonusPercent:
+		v, ok := value.(float64)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetTopupBonusPercent(v)
+		return nil
+	case promocode.FieldTopupBonusMax:
+		v, ok := value.(money.Amount)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetTopupBonusMax(v)
+		return nil
+	case promocode.FieldMinTopupAmount:
+		v, ok := value.(money.Amount)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetMinTopupAmount(v)
+		return nil
+	case promocode.FieldPerUserLimit:
+		v, ok := value.(int)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetPerUserLimit(v)
+		return nil
+	case promocode.FieldAllowedEmailDomains:
+		v, ok := value.([]string)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetAllowedEmailDomains(v)
+		return nil
+	case promocode.FieldRequiredAttributes:
+		v, ok := value.(map[string]string)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetRequiredAttributes(v)
+		return nil
+	case promocode.FieldStackable:
+		v, ok := value.(bool)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetStackable(v)
+		return nil
+	case promocode.FieldTrialGroupID:
+		v, ok := value.(int64)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetTrialGroupID(v)
+		return nil
+	case promocode.FieldTrialDays:
+		v, ok := value.(int)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetTrialDays(v)
+		return nil
 	case promocode.FieldStatus:
 		v, ok := value.(string)
 		if !ok {
@@ -17975,6 +19380,24 @@ func (m *PromoCodeMutation) AddedFields() []string {
 	if m.addused_count != nil {
 		fields = append(fields, promocode.FieldUsedCount)
 	}
+	if m.addtopup_bonus_percent != nil {
+		fields = append(fields, promocode.FieldTopupBonusPercent)
+	}
+	if m.addtopup_bonus_max != nil {
+		fields = append(fields, promocode.FieldTopupBonusMax)
+	}
+	if m.addmin_topup_amount != nil {
+		fields = append(fields, promocode.FieldMinTopupAmount)
+	}
+	if m.addper_user_limit != nil {
+		fields = append(fields, promocode.FieldPerUserLimit)
+	}
+	if m.addtrial_group_id != nil {
+		fields = append(fields, promocode.FieldTrialGroupID)
+	}
+	if m.addtrial_days != nil {
+		fields = append(fields, promocode.FieldTrialDays)
+	}
 	return fields
 }
 
@@ -17989,6 +19412,18 @@ func (m *PromoCodeMutation) AddedField(name string) (ent.Value, bool) {
 		return m.AddedMaxUses()
 	case promocode.FieldUsedCount:
 		return m.AddedUsedCount()
+	case promocode.FieldTopupBonusPercent:
+		return m.AddedTopupBonusPercent()
+	case promocode.FieldTopupBonusMax:
+		return m.AddedTopupBonusMax()
+	case promocode.FieldMinTopupAmount:
+		return m.AddedMinTopupAmount()
+	case promocode.FieldPerUserLimit:
+		return m.AddedPerUserLimit()
+	case promocode.FieldTrialGroupID:
+		return m.AddedTrialGroupID()
+	case promocode.FieldTrialDays:
+		return m.AddedTrialDays()
 	}
 	return nil, false
 }
@@ -17999,7 +19434,7 @@ func (m *PromoCodeMutation) AddedField(name string) (ent.Value, bool) {
 func (m *PromoCodeMutation) AddField(name string, value ent.Value) error {
 	switch name {
 	case promocode.FieldBonusAmount:
-		v, ok := value.(float64)
+		v, ok := value.(money.Amount)
 		if !ok {
 			return fmt.Errorf("unexpected type %T for field %s", value, name)
 		}
@@ -18019,6 +19454,48 @@ func (m *PromoCodeMutation) AddField(name string, value ent.Value) error {
 		}
 		m.AddUsedCount(v)
 		return nil
+	case promocode.FieldTopupBonusPercent:
+		v, ok := value.(float64)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.AddTopupBonusPercent(v)
+		return nil
+	case promocode.FieldTopupBonusMax:
+		v, ok := value.(money.Amount)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.AddTopupBonusMax(v)
+		return nil
+	case promocode.FieldMinTopupAmount:
+		v, ok := value.(money.Amount)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.AddMinTopupAmount(v)
+		return nil
+	case promocode.FieldPerUserLimit:
+		v, ok := value.(int)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.AddPerUserLimit(v)
+		return nil
+	case promocode.FieldTrialGroupID:
+		v, ok := value.(int64)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.AddTrialGroupID(v)
+		return nil
+	case promocode.FieldTrialDays:
+		v, ok := value.(int)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.AddTrialDays(v)
+		return nil
 	}
 	return fmt.Errorf("unknown PromoCode numeric field %s", name)
 }
@@ -18027,6 +19504,15 @@ func (m *PromoCodeMutation) AddField(name string, value ent.Value) error {
 // mutation.
 func (m *PromoCodeMutation) ClearedFields() []string {
 	var fields []string
+	if m.FieldCleared(promocode.FieldAllowedEmailDomains) {
+		fields = append(fields, promocode.FieldAllowedEmailDomains)
+	}
+	if m.FieldCleared(promocode.FieldRequiredAttributes) {
+		fields = append(fields, promocode.FieldRequiredAttributes)
+	}
+	if m.FieldCleared(promocode.FieldTrialGroupID) {
+		fields = append(fields, promocode.FieldTrialGroupID)
+	}
 	if m.FieldCleared(promocode.FieldExpiresAt) {
 		fields = append(fields, promocode.FieldExpiresAt)
 	}
@@ -18047,6 +19533,15 @@ func (m *PromoCodeMutation) FieldCleared(name string) bool {
 // error if the field is not defined in the schema.
 func (m *PromoCodeMutation) ClearField(name string) error {
 	switch name {
+	case promocode.FieldAllowedEmailDomains:
+		m.ClearAllowedEmailDomains()
+		return nil
+	case promocode.FieldRequiredAttributes:
+		m.ClearRequiredAttributes()
+		return nil
+	case promocode.FieldTrialGroupID:
+		m.ClearTrialGroupID()
+		return nil
 	case promocode.FieldExpiresAt:
 		m.ClearExpiresAt()
 		return nil
@@ -18064,14 +19559,41 @@ func (m *PromoCodeMutation) ResetField(name string) error {
 	case promocode.FieldCode:
 		m.ResetCode()
 		return nil
-	case promocode.FieldBonusAmount:
-		m.ResetBonusAmount()
+	case promocode.FieldBonusAmount:
+		m.ResetBonusAmount()
+		return nil
+	case promocode.FieldMaxUses:
+		m.ResetMaxUses()
+		return nil
+	case promocode.FieldUsedCount:
+		m.ResetUsedCount()
+		return nil
+	case promocode.FieldTopupBonusPercent:
+		m.ResetTopupBonusPercent()
+		return nil
+	case promocode.FieldTopupBonusMax:
+		m.ResetTopupBonusMax()
+		return nil
+	case promocode.FieldMinTopupAmount:
+		m.ResetMinTopupAmount()
+		return nil
+	case promocode.FieldPerUserLimit:
+		m.ResetPerUserLimit()
+		return nil
+	case promocode.FieldAllowedEmailDomains:
+		m.ResetAllowedEmailDomains()
+		return nil
+	case promocode.FieldRequiredAttributes:
+		m.ResetRequiredAttributes()
 		return nil
-	case promocode.FieldMaxUses:
-		m.ResetMaxUses()
+	case promocode.FieldStackable:
+		m.ResetStackable()
 		return nil
-	case promocode.FieldUsedCount:
-		m.ResetUsedCount()
+	case promocode.FieldTrialGroupID:
+		m.ResetTrialGroupID()
+		return nil
+	case promocode.FieldTrialDays:
+		m.ResetTrialDays()
 		return nil
 	case promocode.FieldStatus:
 		m.ResetStatus()
@@ -18179,20 +19701,24 @@ func (m *PromoCodeMutation) ResetEdge(name string) error {
 // PromoCodeUsageMutation represents an operation that mutates the PromoCodeUsage nodes in the graph.
 type PromoCodeUsageMutation struct {
 	config
-	op                Op
-	typ               string
-	id                *int64
-	bonus_amount      *float64
-	addbonus_amount   *float64
-	used_at           *time.Time
-	clearedFields     map[string]struct{}
-	promo_code        *int64
-	clearedpromo_code bool
-	user              *int64
-	cleareduser       bool
-	done              bool
-	oldValue          func(context.Context) (*PromoCodeUsage, error)
-	predicates        []predicate.PromoCodeUsage
+	op                    Op
+	typ                   string
+	id                    *int64
+	bonus_amount          *money.Amount
+	addbonus_amount       *money.Amount
+	topup_bonus_status    *string
+	topup_bonus_amount    *money.Amount
+	addtopup_bonus_amount *money.Amount
+	topup_granted_at      *time.Time
+	used_at               *time.Time
+	clearedFields         map[string]struct{}
+	promo_code            *int64
+	clearedpromo_code     bool
+	user                  *int64
+	cleareduser           bool
+	done                  bool
+	oldValue              func(context.Context) (*PromoCodeUsage, error)
+	predicates            []predicate.PromoCodeUsage
 }
 
 var _ ent.Mutation = (*PromoCodeUsageMutation)(nil)
@@ -18366,13 +19892,13 @@ func (m *PromoCodeUsageMutation) ResetUserID() {
 }
 
 // SetBonusAmount sets the "bonus_amount" field.
-func (m *PromoCodeUsageMutation) SetBonusAmount(f float64) {
-	m.bonus_amount = &f
+func (m *PromoCodeUsageMutation) SetBonusAmount(value money.Amount) {
+	m.bonus_amount = &value
 	m.addbonus_amount = nil
 }
 
 // BonusAmount returns the value of the "bonus_amount" field in the mutation.
-func (m *PromoCodeUsageMutation) BonusAmount() (r float64, exists bool) {
+func (m *PromoCodeUsageMutation) BonusAmount() (r money.Amount, exists bool) {
 	v := m.bonus_amount
 	if v == nil {
 		return
@@ -18383,7 +19909,7 @@ func (m *PromoCodeUsageMutation) BonusAmount() (r float64, exists bool) {
 // OldBonusAmount returns the old "bonus_amount" field's value of the PromoCodeUsage entity.
 // If the PromoCodeUsage object wasn't provided to the builder, the object is fetched from the database.
 // An error is returned if the mutation operation is not UpdateOne, or the database query fails.
-func (m *PromoCodeUsageMutation) OldBonusAmount(ctx context.Context) (v float64, err error) {
+func (m *PromoCodeUsageMutation) OldBonusAmount(ctx context.Context) (v money.Amount, err error) {
 	if !m.op.Is(OpUpdateOne) {
 		return v, errors.New("OldBonusAmount is only allowed on UpdateOne operations")
 	}
@@ -18397,17 +19923,17 @@ func (m *PromoCodeUsageMutation) OldBonusAmount(ctx context.Context) (v float64,
 	return oldValue.BonusAmount, nil
 }
 
-// AddBonusAmount adds f to the "bonus_amount" field.
-func (m *PromoCodeUsageMutation) AddBonusAmount(f float64) {
+// AddBonusAmount adds value to the "bonus_amount" field.
+func (m *PromoCodeUsageMutation) AddBonusAmount(value money.Amount) {
 	if m.addbonus_amount != nil {
-		*m.addbonus_amount += f
+		*m.addbonus_amount += value
 	} else {
-		m.addbonus_amount = &f
+		m.addbonus_amount = &value
 	}
 }
 
 // AddedBonusAmount returns the value that was added to the "bonus_amount" field in this mutation.
-func (m *PromoCodeUsageMutation) AddedBonusAmount() (r float64, exists bool) {
+func (m *PromoCodeUsageMutation) AddedBonusAmount() (r money.Amount, exists bool) {
 	v := m.addbonus_amount
 	if v == nil {
 		return
@@ -18421,6 +19947,147 @@ func (m *PromoCodeUsageMutation) ResetBonusAmount() {
 	m.addbonus_amount = nil
 }
 
+// SetTopupBonusStatus sets the "topup_bonus_status" field.
+func (m *PromoCodeUsageMutation) SetTopupBonusStatus(s string) {
+	m.topup_bonus_status = &s
+}
+
+// TopupBonusStatus returns the value of the "topup_bonus_status" field in the mutation.
+func (m *PromoCodeUsageMutation) TopupBonusStatus() (r string, exists bool) {
+	v := m.topup_bonus_status
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldTopupBonusStatus returns the old "topup_bonus_status" field's value of the PromoCodeUsage entity.
+// If the PromoCodeUsage object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *PromoCodeUsageMutation) OldTopupBonusStatus(ctx context.Context) (v string, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldTopupBonusStatus is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldTopupBonusStatus requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldTopupBonusStatus: %w", err)
+	}
+	return oldValue.TopupBonusStatus, nil
+}
+
+// ResetTopupBonusStatus resets all changes to the "topup_bonus_status" field.
+func (m *PromoCodeUsageMutation) ResetTopupBonusStatus() {
+	m.topup_bonus_status = nil
+}
+
+// SetTopupBonusAmount sets the "topup_bonus_amount" field.
+func (m *PromoCodeUsageMutation) SetTopupBonusAmount(value money.Amount) {
+	m.topup_bonus_amount = &value
+	m.addtopup_bonus_amount = nil
+}
+
+// TopupBonusAmount returns the value of the "topup_bonus_amount" field in the mutation.
+func (m *PromoCodeUsageMutation) TopupBonusAmount() (r money.Amount, exists bool) {
+	v := m.topup_bonus_amount
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldTopupBonusAmount returns the old "topup_bonus_amount" field's value of the PromoCodeUsage entity.
+// If the PromoCodeUsage object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *PromoCodeUsageMutation) OldTopupBonusAmount(ctx context.Context) (v money.Amount, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldTopupBonusAmount is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldTopupBonusAmount requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldTopupBonusAmount: %w", err)
+	}
+	return oldValue.TopupBonusAmount, nil
+}
+
+// AddTopupBonusAmount adds value to the "topup_bonus_amount" field.
+func (m *PromoCodeUsageMutation) AddTopupBonusAmount(value money.Amount) {
+	if m.addtopup_bonus_amount != nil {
+		*m.addtopup_bonus_amount += value
+	} else {
+		m.addtopup_bonus_amount = &value
+	}
+}
+
+// AddedTopupBonusAmount returns the value that was added to the "topup_bonus_amount" field in this mutation.
+func (m *PromoCodeUsageMutation) AddedTopupBonusAmount() (r money.Amount, exists bool) {
+	v := m.addtopup_bonus_amount
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// ResetTopupBonusAmount resets all changes to the "topup_bonus_amount" field.
+func (m *PromoCodeUsageMutation) ResetTopupBonusAmount() {
+	m.topup_bonus_amount = nil
+	m.addtopup_bonus_amount = nil
+}
+
+// SetTopupGrantedAt sets the "topup_granted_at" field.
+func (m *PromoCodeUsageMutation) SetTopupGrantedAt(t time.Time) {
+	m.topup_granted_at = &t
+}
+
+// TopupGrantedAt returns the value of the "topup_granted_at" field in the mutation.
+func (m *PromoCodeUsageMutation) TopupGrantedAt() (r time.Time, exists bool) {
+	v := m.topup_granted_at
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldTopupGrantedAt returns the old "topup_granted_at" field's value of the PromoCodeUsage entity.
+// If the PromoCodeUsage object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *PromoCodeUsageMutation) OldTopupGrantedAt(ctx context.Context) (v *time.Time, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldTopupGrantedAt is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldTopupGrantedAt requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldTopupGrantedAt: %w", err)
+	}
+	return oldValue.TopupGrantedAt, nil
+}
+
+// ClearTopupGrantedAt clears the value of the "topup_granted_at" field.
+func (m *PromoCodeUsageMutation) ClearTopupGrantedAt() {
+	m.topup_granted_at = nil
+	m.clearedFields[promocodeusage.FieldTopupGrantedAt] = struct{}{}
+}
+
+// TopupGrantedAtCleared returns if the "topup_granted_at" field was cleared in this mutation.
+func (m *PromoCodeUsageMutation) TopupGrantedAtCleared() bool {
+	_, ok := m.clearedFields[promocodeusage.FieldTopupGrantedAt]
+	return ok
+}
+
+// ResetTopupGrantedAt resets all changes to the "topup_granted_at" field.
+func (m *PromoCodeUsageMutation) ResetTopupGrantedAt() {
+	m.topup_granted_at = nil
+	delete(m.clearedFields, promocodeusage.FieldTopupGrantedAt)
+}
+
 // SetUsedAt sets the "used_at" field.
 func (m *PromoCodeUsageMutation) SetUsedAt(t time.Time) {
 	m.used_at = &t
@@ -18545,7 +20212,7 @@ func (m *PromoCodeUsageMutation) Type() string {
 // order to get all numeric fields that were incremented/decremented, call
 // AddedFields().
 func (m *PromoCodeUsageMutation) Fields() []string {
-	fields := make([]string, 0, 4)
+	fields := make([]string, 0, 7)
 	if m.promo_code != nil {
 		fields = append(fields, promocodeusage.FieldPromoCodeID)
 	}
@@ -18555,6 +20222,15 @@ func (m *PromoCodeUsageMutation) Fields() []string {
 	if m.bonus_amount != nil {
 		fields = append(fields, promocodeusage.FieldBonusAmount)
 	}
+	if m.topup_bonus_status != nil {
+		fields = append(fields, promocodeusage.FieldTopupBonusStatus)
+	}
+	if m.topup_bonus_amount != nil {
+		fields = append(fields, promocodeusage.FieldTopupBonusAmount)
+	}
+	if m.topup_granted_at != nil {
+		fields = append(fields, promocodeusage.FieldTopupGrantedAt)
+	}
 	if m.used_at != nil {
 		fields = append(fields, promocodeusage.FieldUsedAt)
 	}
@@ -18572,6 +20248,12 @@ func (m *PromoCodeUsageMutation) Field(name string) (ent.Value, bool) {
 		return m.UserID()
 	case promocodeusage.FieldBonusAmount:
 		return m.BonusAmount()
+	case promocodeusage.FieldTopupBonusStatus:
+		return m.TopupBonusStatus()
+	case promocodeusage.FieldTopupBonusAmount:
+		return m.TopupBonusAmount()
+	case promocodeusage.FieldTopupGrantedAt:
+		return m.TopupGrantedAt()
 	case promocodeusage.FieldUsedAt:
 		return m.UsedAt()
 	}
@@ -18589,6 +20271,12 @@ func (m *PromoCodeUsageMutation) OldField(ctx context.Context, name string) (ent
 		return m.OldUserID(ctx)
 	case promocodeusage.FieldBonusAmount:
 		return m.OldBonusAmount(ctx)
+	case promocodeusage.FieldTopupBonusStatus:
+		return m.OldTopupBonusStatus(ctx)
+	case promocodeusage.FieldTopupBonusAmount:
+		return m.OldTopupBonusAmount(ctx)
+	case promocodeusage.FieldTopupGrantedAt:
+		return m.OldTopupGrantedAt(ctx)
 	case promocodeusage.FieldUsedAt:
 		return m.OldUsedAt(ctx)
 	}
@@ -18615,12 +20303,33 @@ func (m *PromoCodeUsageMutation) SetField(name string, value ent.Value) error {
 		m.SetUserID(v)
 		return nil
 	case promocodeusage.FieldBonusAmount:
-		v, ok := value.(float64)
+		v, ok := value.(money.Amount)
 		if !ok {
 			return fmt.Errorf("unexpected type %T for field %s", value, name)
 		}
 		m.SetBonusAmount(v)
 		return nil
+	case promocodeusage.FieldTopupBonusStatus:
+		v, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetTopupBonusStatus(v)
+		return nil
+	case promocodeusage.FieldTopupBonusAmount:
+		v, ok := value.(money.Amount)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetTopupBonusAmount(v)
+		return nil
+	case promocodeusage.FieldTopupGrantedAt:
+		v, ok := value.(time.Time)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetTopupGrantedAt(v)
+		return nil
 	case promocodeusage.FieldUsedAt:
 		v, ok := value.(time.Time)
 		if !ok {
@@ -18639,6 +20348,9 @@ func (m *PromoCodeUsageMutation) AddedFields() []string {
 	if m.addbonus_amount != nil {
 		fields = append(fields, promocodeusage.FieldBonusAmount)
 	}
+	if m.addtopup_bonus_amount != nil {
+		fields = append(fields, promocodeusage.FieldTopupBonusAmount)
+	}
 	return fields
 }
 
@@ -18649,6 +20361,8 @@ func (m *PromoCodeUsageMutation) AddedField(name string) (ent.Value, bool) {
 	switch name {
 	case promocodeusage.FieldBonusAmount:
 		return m.AddedBonusAmount()
+	case promocodeusage.FieldTopupBonusAmount:
+		return m.AddedTopupBonusAmount()
 	}
 	return nil, false
 }
@@ -18659,12 +20373,19 @@ func (m *PromoCodeUsageMutation) AddedField(name string) (ent.Value, bool) {
 func (m *PromoCodeUsageMutation) AddField(name string, value ent.Value) error {
 	switch name {
 	case promocodeusage.FieldBonusAmount:
-		v, ok := value.(float64)
+		v, ok := value.(money.Amount)
 		if !ok {
 			return fmt.Errorf("unexpected type %T for field %s", value, name)
 		}
 		m.AddBonusAmount(v)
 		return nil
+	case promocodeusage.FieldTopupBonusAmount:
+		v, ok := value.(money.Amount)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.AddTopupBonusAmount(v)
+		return nil
 	}
 	return fmt.Errorf("unknown PromoCodeUsage numeric field %s", name)
 }
@@ -18672,7 +20393,11 @@ func (m *PromoCodeUsageMutation) AddField(name string, value ent.Value) error {
 // ClearedFields returns all nullable fields that were cleared during this
 // mutation.
 func (m *PromoCodeUsageMutation) ClearedFields() []string {
-	return nil
+	var fields []string
+	if m.FieldCleared(promocodeusage.FieldTopupGrantedAt) {
+		fields = append(fields, promocodeusage.FieldTopupGrantedAt)
+	}
+	return fields
 }
 
 // FieldCleared returns a boolean indicating if a field with the given name was
@@ -18685,6 +20410,11 @@ func (m *PromoCodeUsageMutation) FieldCleared(name string) bool {
 // ClearField clears the value of the field with the given name. It returns an
 // error if the field is not defined in the schema.
 func (m *PromoCodeUsageMutation) ClearField(name string) error {
+	switch name {
+	case promocodeusage.FieldTopupGrantedAt:
+		m.ClearTopupGrantedAt()
+		return nil
+	}
 	return fmt.Errorf("unknown PromoCodeUsage nullable field %s", name)
 }
 
@@ -18701,6 +20431,15 @@ func (m *PromoCodeUsageMutation) ResetField(name string) error {
 	case promocodeusage.FieldBonusAmount:
 		m.ResetBonusAmount()
 		return nil
+	case promocodeusage.FieldTopupBonusStatus:
+		m.ResetTopupBonusStatus()
+		return nil
+	case promocodeusage.FieldTopupBonusAmount:
+		m.ResetTopupBonusAmount()
+		return nil
+	case promocodeusage.FieldTopupGrantedAt:
+		m.ResetTopupGrantedAt()
+		return nil
 	case promocodeusage.FieldUsedAt:
 		m.ResetUsedAt()
 		return nil
@@ -19809,8 +21548,8 @@ type RedeemCodeMutation struct {
 	id               *int64
 	code             *string
 	_type            *string
-	value            *float64
-	addvalue         *float64
+	value            *money.Amount
+	addvalue         *money.Amount
 	status           *string
 	used_at          *time.Time
 	notes            *string
@@ -19998,13 +21737,13 @@ func (m *RedeemCodeMutation) ResetType() {
 }
 
 // SetValue sets the "value" field.
-func (m *RedeemCodeMutation) SetValue(f float64) {
-	m.value = &f
+func (m *RedeemCodeMutation) SetValue(value money.Amount) {
+	m.value = &value
 	m.addvalue = nil
 }
 
 // Value returns the value of the "value" field in the mutation.
-func (m *RedeemCodeMutation) Value() (r float64, exists bool) {
+func (m *RedeemCodeMutation) Value() (r money.Amount, exists bool) {
 	v := m.value
 	if v == nil {
 		return
@@ -20015,7 +21754,7 @@ func (m *RedeemCodeMutation) Value() (r float64, exists bool) {
 // OldValue returns the old "value" field's value of the RedeemCode entity.
 // If the RedeemCode object wasn't provided to the builder, the object is fetched from the database.
 // An error is returned if the mutation operation is not UpdateOne, or the database query fails.
-func (m *RedeemCodeMutation) OldValue(ctx context.Context) (v float64, err error) {
+func (m *RedeemCodeMutation) OldValue(ctx context.Context) (v money.Amount, err error) {
 	if !m.op.Is(OpUpdateOne) {
 		return v, errors.New("OldValue is only allowed on UpdateOne operations")
 	}
@@ -20029,17 +21768,17 @@ func (m *RedeemCodeMutation) OldValue(ctx context.Context) (v float64, err error
 	return oldValue.Value, nil
 }
 
-// AddValue adds f to the "value" field.
-func (m *RedeemCodeMutation) AddValue(f float64) {
+// AddValue adds value to the "value" field.
+func (m *RedeemCodeMutation) AddValue(value money.Amount) {
 	if m.addvalue != nil {
-		*m.addvalue += f
+		*m.addvalue += value
 	} else {
-		m.addvalue = &f
+		m.addvalue = &value
 	}
 }
 
 // AddedValue returns the value that was added to the "value" field in this mutation.
-func (m *RedeemCodeMutation) AddedValue() (r float64, exists bool) {
+func (m *RedeemCodeMutation) AddedValue() (r money.Amount, exists bool) {
 	v := m.addvalue
 	if v == nil {
 		return
@@ -20590,7 +22329,7 @@ func (m *RedeemCodeMutation) SetField(name string, value ent.Value) error {
 		m.SetType(v)
 		return nil
 	case redeemcode.FieldValue:
-		v, ok := value.(float64)
+		v, ok := value.(money.Amount)
 		if !ok {
 			return fmt.Errorf("unexpected type %T for field %s", value, name)
 		}
@@ -20681,7 +22420,7 @@ func (m *RedeemCodeMutation) AddedField(name string) (ent.Value, bool) {
 func (m *RedeemCodeMutation) AddField(name string, value ent.Value) error {
 	switch name {
 	case redeemcode.FieldValue:
-		v, ok := value.(float64)
+		v, ok := value.(money.Amount)
 		if !ok {
 			return fmt.Errorf("unexpected type %T for field %s", value, name)
 		}
@@ -22899,18 +24638,18 @@ type UsageLogMutation struct {
 	addcache_creation_5m_tokens *int
 	cache_creation_1h_tokens    *int
 	addcache_creation_1h_tokens *int
-	input_cost                  *float64
-	addinput_cost               *float64
-	output_cost                 *float64
-	addoutput_cost              *float64
-	cache_creation_cost         *float64
-	addcache_creation_cost      *float64
-	cache_read_cost             *float64
-	addcache_read_cost          *float64
-	total_cost                  *float64
-	addtotal_cost               *float64
-	actual_cost                 *float64
-	addactual_cost              *float64
+	input_cost                  *money.Amount
+	addinput_cost               *money.Amount
+	output_cost                 *money.Amount
+	addoutput_cost              *money.Amount
+	cache_creation_cost         *money.Amount
+	addcache_creation_cost      *money.Amount
+	cache_read_cost             *money.Amount
+	addcache_read_cost          *money.Amount
+	total_cost                  *money.Amount
+	addtotal_cost               *money.Amount
+	actual_cost                 *money.Amount
+	addactual_cost              *money.Amount
 	rate_multiplier             *float64
 	addrate_multiplier          *float64
 	account_rate_multiplier     *float64
@@ -22928,6 +24667,10 @@ type UsageLogMutation struct {
 	addimage_count              *int
 	image_size                  *string
 	media_type                  *string
+	audio_duration_seconds      *float64
+	addaudio_duration_seconds   *float64
+	audio_characters            *int
+	addaudio_characters         *int
 	cache_ttl_overridden        *bool
 	created_at                  *time.Time
 	clearedFields               map[string]struct{}
@@ -23659,13 +25402,13 @@ func (m *UsageLogMutation) ResetCacheCreation1hTokens() {
 }
 
 // SetInputCost sets the "input_cost" field.
-func (m *UsageLogMutation) SetInputCost(f float64) {
-	m.input_cost = &f
+func (m *UsageLogMutation) SetInputCost(value money.Amount) {
+	m.input_cost = &value
 	m.addinput_cost = nil
 }
 
 // InputCost returns the value of the "input_cost" field in the mutation.
-func (m *UsageLogMutation) InputCost() (r float64, exists bool) {
+func (m *UsageLogMutation) InputCost() (r money.Amount, exists bool) {
 	v := m.input_cost
 	if v == nil {
 		return
@@ -23676,7 +25419,7 @@ func (m *UsageLogMutation) InputCost() (r float64, exists bool) {
 // OldInputCost returns the old "input_cost" field's value of the UsageLog entity.
 // If the UsageLog object wasn't provided to the builder, the object is fetched from the database.
 // An error is returned if the mutation operation is not UpdateOne, or the database query fails.
-func (m *UsageLogMutation) OldInputCost(ctx context.Context) (v float64, err error) {
+func (m *UsageLogMutation) OldInputCost(ctx context.Context) (v money.Amount, err error) {
 	if !m.op.Is(OpUpdateOne) {
 		return v, errors.New("OldInputCost is only allowed on UpdateOne operations")
 	}
@@ -23690,17 +25433,17 @@ func (m *UsageLogMutation) OldInputCost(ctx context.Context) (v float64, err err
 	return oldValue.InputCost, nil
 }
 
-// AddInputCost adds f to the "input_cost" field.
-func (m *UsageLogMutation) AddInputCost(f float64) {
+// AddInputCost adds value to the "input_cost" field.
+func (m *UsageLogMutation) AddInputCost(value money.Amount) {
 	if m.addinput_cost != nil {
-		*m.addinput_cost += f
+		*m.addinput_cost += value
 	} else {
-		m.addinput_cost = &f
+		m.addinput_cost = &value
 	}
 }
 
 // AddedInputCost returns the value that was added to the "input_cost" field in this mutation.
-func (m *UsageLogMutation) AddedInputCost() (r float64, exists bool) {
+func (m *UsageLogMutation) AddedInputCost() (r money.Amount, exists bool) {
 	v := m.addinput_cost
 	if v == nil {
 		return
@@ -23715,13 +25458,13 @@ func (m *UsageLogMutation) ResetInputCost() {
 }
 
 // SetOutputCost sets the "output_cost" field.
-func (m *UsageLogMutation) SetOutputCost(f float64) {
-	m.output_cost = &f
+func (m *UsageLogMutation) SetOutputCost(value money.Amount) {
+	m.output_cost = &value
 	m.addoutput_cost = nil
 }
 
 // OutputCost returns the value of the "output_cost" field in the mutation.
-func (m *UsageLogMutation) OutputCost() (r float64, exists bool) {
+func (m *UsageLogMutation) OutputCost() (r money.Amount, exists bool) {
 	v := m.output_cost
 	if v == nil {
 		return
@@ -23732,7 +25475,7 @@ func (m *UsageLogMutation) OutputCost() (r float64, exists bool) {
 // OldOutputCost returns the old "output_cost" field's value of the UsageLog entity.
 // If the UsageLog object wasn't provided to the builder, the object is fetched from the database.
 // An error is returned if the mutation operation is not UpdateOne, or the database query fails.
-func (m *UsageLogMutation) OldOutputCost(ctx context.Context) (v float64, err error) {
+func (m *UsageLogMutation) OldOutputCost(ctx context.Context) (v money.Amount, err error) {
 	if !m.op.Is(OpUpdateOne) {
 		return v, errors.New("OldOutputCost is only allowed on UpdateOne operations")
 	}
@@ -23746,17 +25489,17 @@ func (m *UsageLogMutation) OldOutputCost(ctx context.Context) (v float64, err er
 	return oldValue.OutputCost, nil
 }
 
-// AddOutputCost adds f to the "output_cost" field.
-func (m *UsageLogMutation) AddOutputCost(f float64) {
+// AddOutputCost adds value to the "output_cost" field.
+func (m *UsageLogMutation) AddOutputCost(value money.Amount) {
 	if m.addoutput_cost != nil {
-		*m.addoutput_cost += f
+		*m.addoutput_cost += value
 	} else {
-		m.addoutput_cost = &f
+		m.addoutput_cost = &value
 	}
 }
 
 // AddedOutputCost returns the value that was added to the "output_cost" field in this mutation.
-func (m *UsageLogMutation) AddedOutputCost() (r float64, exists bool) {
+func (m *UsageLogMutation) AddedOutputCost() (r money.Amount, exists bool) {
 	v := m.addoutput_cost
 	if v == nil {
 		return
@@ -23771,13 +25514,13 @@ func (m *UsageLogMutation) ResetOutputCost() {
 }
 
 // SetCacheCreationCost sets the "cache_creation_cost" field.
-func (m *UsageLogMutation) SetCacheCreationCost(f float64) {
-	m.cache_creation_cost = &f
+func (m *UsageLogMutation) SetCacheCreationCost(value money.Amount) {
+	m.cache_creation_cost = &value
 	m.addcache_creation_cost = nil
 }
 
 // CacheCreationCost returns the value of the "cache_creation_cost" field in the mutation.
-func (m *UsageLogMutation) CacheCreationCost() (r float64, exists bool) {
+func (m *UsageLogMutation) CacheCreationCost() (r money.Amount, exists bool) {
 	v := m.cache_creation_cost
 	if v == nil {
 		return
@@ -23788,7 +25531,7 @@ func (m *UsageLogMutation) CacheCreationCost() (r float64, exists bool) {
 // OldCacheCreationCost returns the old "cache_creation_cost" field's value of the UsageLog entity.
 // If the UsageLog object wasn't provided to the builder, the object is fetched from the database.
 // An error is returned if the mutation operation is not UpdateOne, or the database query fails.
-func (m *UsageLogMutation) OldCacheCreationCost(ctx context.Context) (v float64, err error) {
+func (m *UsageLogMutation) OldCacheCreationCost(ctx context.Context) (v money.Amount, err error) {
 	if !m.op.Is(OpUpdateOne) {
 		return v, errors.New("OldCacheCreationCost is only allowed on UpdateOne operations")
 	}
@@ -23802,17 +25545,17 @@ func (m *UsageLogMutation) OldCacheCreationCost(ctx context.Context) (v float64,
 	return oldValue.CacheCreationCost, nil
 }
 
-// AddCacheCreationCost adds f to the "cache_creation_cost" field.
-func (m *UsageLogMutation) AddCacheCreationCost(f float64) {
+// AddCacheCreationCost adds value to the "cache_creation_cost" field.
+func (m *UsageLogMutation) AddCacheCreationCost(value money.Amount) {
 	if m.addcache_creation_cost != nil {
-		*m.addcache_creation_cost += f
+		*m.addcache_creation_cost += value
 	} else {
-		m.addcache_creation_cost = &f
+		m.addcache_creation_cost = &value
 	}
 }
 
 // AddedCacheCreationCost returns the value that was added to the "cache_creation_cost" field in this mutation.
-func (m *UsageLogMutation) AddedCacheCreationCost() (r float64, exists bool) {
+func (m *UsageLogMutation) AddedCacheCreationCost() (r money.Amount, exists bool) {
 	v := m.addcache_creation_cost
 	if v == nil {
 		return
@@ -23827,13 +25570,13 @@ func (m *UsageLogMutation) ResetCacheCreationCost() {
 }
 
 // SetCacheReadCost sets the "cache_read_cost" field.
-func (m *UsageLogMutation) SetCacheReadCost(f float64) {
-	m.cache_read_cost = &f
+func (m *UsageLogMutation) SetCacheReadCost(value money.Amount) {
+	m.cache_read_cost = &value
 	m.addcache_read_cost = nil
 }
 
 // CacheReadCost returns the value of the "cache_read_cost" field in the mutation.
-func (m *UsageLogMutation) CacheReadCost() (r float64, exists bool) {
+func (m *UsageLogMutation) CacheReadCost() (r money.Amount, exists bool) {
 	v := m.cache_read_cost
 	if v == nil {
 		return
@@ -23844,7 +25587,7 @@ func (m *UsageLogMutation) CacheReadCost() (r float64, exists bool) {
 // OldCacheReadCost returns the old "cache_read_cost" field's value of the UsageLog entity.
 // If the UsageLog object wasn't provided to the builder, the object is fetched from the database.
 // An error is returned if the mutation operation is not UpdateOne, or the database query fails.
-func (m *UsageLogMutation) OldCacheReadCost(ctx context.Context) (v float64, err error) {
+func (m *UsageLogMutation) OldCacheReadCost(ctx context.Context) (v money.Amount, err error) {
 	if !m.op.Is(OpUpdateOne) {
 		return v, errors.New("OldCacheReadCost is only allowed on UpdateOne operations")
 	}
@@ -23858,17 +25601,17 @@ func (m *UsageLogMutation) OldCacheReadCost(ctx context.Context) (v float64, err
 	return oldValue.CacheReadCost, nil
 }
 
-// AddCacheReadCost adds f to the "cache_read_cost" field.
-func (m *UsageLogMutation) AddCacheReadCost(f float64) {
+// AddCacheReadCost adds value to the "cache_read_cost" field.
+func (m *UsageLogMutation) AddCacheReadCost(value money.Amount) {
 	if m.addcache_read_cost != nil {
-		*m.addcache_read_cost += f
+		*m.addcache_read_cost += value
 	} else {
-		m.addcache_read_cost = &f
+		m.addcache_read_cost = &value
 	}
 }
 
 // AddedCacheReadCost returns the value that was added to the "cache_read_cost" field in this mutation.
-func (m *UsageLogMutation) AddedCacheReadCost() (r float64, exists bool) {
+func (m *UsageLogMutation) AddedCacheReadCost() (r money.Amount, exists bool) {
 	v := m.addcache_read_cost
 	if v == nil {
 		return
@@ -23883,13 +25626,13 @@ func (m *UsageLogMutation) ResetCacheReadCost() {
 }
 
 // SetTotalCost sets the "total_cost" field.
-func (m *UsageLogMutation) SetTotalCost(f float64) {
-	m.total_cost = &f
+func (m *UsageLogMutation) SetTotalCost(value money.Amount) {
+	m.total_cost = &value
 	m.addtotal_cost = nil
 }
 
 // TotalCost returns the value of the "total_cost" field in the mutation.
-func (m *UsageLogMutation) TotalCost() (r float64, exists bool) {
+func (m *UsageLogMutation) TotalCost() (r money.Amount, exists bool) {
 	v := m.total_cost
 	if v == nil {
 		return
@@ -23900,7 +25643,7 @@ func (m *UsageLogMutation) TotalCost() (r float64, exists bool) {
 // OldTotalCost returns the old "total_cost" field's value of the UsageLog entity.
 // If the UsageLog object wasn't provided to the builder, the object is fetched from the database.
 // An error is returned if the mutation operation is not UpdateOne, or the database query fails.
-func (m *UsageLogMutation) OldTotalCost(ctx context.Context) (v float64, err error) {
+func (m *UsageLogMutation) OldTotalCost(ctx context.Context) (v money.Amount, err error) {
 	if !m.op.Is(OpUpdateOne) {
 		return v, errors.New("OldTotalCost is only allowed on UpdateOne operations")
 	}
@@ -23914,17 +25657,17 @@ func (m *UsageLogMutation) OldTotalCost(ctx context.Context) (v float64, err err
 	return oldValue.TotalCost, nil
 }
 
-// AddTotalCost adds f to the "total_cost" field.
-func (m *UsageLogMutation) AddTotalCost(f float64) {
+// AddTotalCost adds value to the "total_cost" field.
+func (m *UsageLogMutation) AddTotalCost(value money.Amount) {
 	if m.addtotal_cost != nil {
-		*m.addtotal_cost += f
+		*m.addtotal_cost += value
 	} else {
-		m.addtotal_cost = &f
+		m.addtotal_cost = &value
 	}
 }
 
 // AddedTotalCost returns the value that was added to the "total_cost" field in this mutation.
-func (m *UsageLogMutation) AddedTotalCost() (r float64, exists bool) {
+func (m *UsageLogMutation) AddedTotalCost() (r money.Amount, exists bool) {
 	v := m.addtotal_cost
 	if v == nil {
 		return
@@ -23939,13 +25682,13 @@ func (m *UsageLogMutation) ResetTotalCost() {
 }
 
 // SetActualCost sets the "actual_cost" field.
-func (m *UsageLogMutation) SetActualCost(f float64) {
-	m.actual_cost = &f
+func (m *UsageLogMutation) SetActualCost(value money.Amount) {
+	m.actual_cost = &value
 	m.addactual_cost = nil
 }
 
 // ActualCost returns the value of the "actual_cost" field in the mutation.
-func (m *UsageLogMutation) ActualCost() (r float64, exists bool) {
+func (m *UsageLogMutation) ActualCost() (r money.Amount, exists bool) {
 	v := m.actual_cost
 	if v == nil {
 		return
@@ -23956,7 +25699,7 @@ func (m *UsageLogMutation) ActualCost() (r float64, exists bool) {
 // OldActualCost returns the old "actual_cost" field's value of the UsageLog entity.
 // If the UsageLog object wasn't provided to the builder, the object is fetched from the database.
 // An error is returned if the mutation operation is not UpdateOne, or the database query fails.
-func (m *UsageLogMutation) OldActualCost(ctx context.Context) (v float64, err error) {
+func (m *UsageLogMutation) OldActualCost(ctx context.Context) (v money.Amount, err error) {
 	if !m.op.Is(OpUpdateOne) {
 		return v, errors.New("OldActualCost is only allowed on UpdateOne operations")
 	}
@@ -23970,17 +25713,17 @@ func (m *UsageLogMutation) OldActualCost(ctx context.Context) (v float64, err er
 	return oldValue.ActualCost, nil
 }
 
-// AddActualCost adds f to the "actual_cost" field.
-func (m *UsageLogMutation) AddActualCost(f float64) {
+// AddActualCost adds value to the "actual_cost" field.
+func (m *UsageLogMutation) AddActualCost(value money.Amount) {
 	if m.addactual_cost != nil {
-		*m.addactual_cost += f
+		*m.addactual_cost += value
 	} else {
-		m.addactual_cost = &f
+		m.addactual_cost = &value
 	}
 }
 
 // AddedActualCost returns the value that was added to the "actual_cost" field in this mutation.
-func (m *UsageLogMutation) AddedActualCost() (r float64, exists bool) {
+func (m *UsageLogMutation) AddedActualCost() (r money.Amount, exists bool) {
 	v := m.addactual_cost
 	if v == nil {
 		return
@@ -24604,6 +26347,146 @@ func (m *UsageLogMutation) ResetMediaType() {
 	delete(m.clearedFields, usagelog.FieldMediaType)
 }
 
+// SetAudioDurationSeconds sets the "audio_duration_seconds" field.
+func (m *UsageLogMutation) SetAudioDurationSeconds(f float64) {
+	m.audio_duration_seconds = &f
+	m.addaudio_duration_seconds = nil
+}
+
+// AudioDurationSeconds returns the value of the "audio_duration_seconds" field in the mutation.
+func (m *UsageLogMutation) AudioDurationSeconds() (r float64, exists bool) {
+	v := m.audio_duration_seconds
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldAudioDurationSeconds returns the old "audio_duration_seconds" field's value of the UsageLog entity.
+// If the UsageLog object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *UsageLogMutation) OldAudioDurationSeconds(ctx context.Context) (v *float64, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldAudioDurationSeconds is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldAudioDurationSeconds requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldAudioDurationSeconds: %w", err)
+	}
+	return oldValue.AudioDurationSeconds, nil
+}
+
+// AddAudioDurationSeconds adds f to the "audio_duration_seconds" field.
+func (m *UsageLogMutation) AddAudioDurationSeconds(f float64) {
+	if m.addaudio_duration_seconds != nil {
+		*m.addaudio_duration_seconds += f
+	} else {
+		m.addaudio_duration_seconds = &f
+	}
+}
+
+// AddedAudioDurationSeconds returns the value that was added to the "audio_duration_seconds" field in this mutation.
+func (m *UsageLogMutation) AddedAudioDurationSeconds() (r float64, exists bool) {
+	v := m.addaudio_duration_seconds
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// ClearAudioDurationSeconds clears the value of the "audio_duration_seconds" field.
+func (m *UsageLogMutation) ClearAudioDurationSeconds() {
+	m.audio_duration_seconds = nil
+	m.addaudio_duration_seconds = nil
+	m.clearedFields[usagelog.FieldAudioDurationSeconds] = struct{}{}
+}
+
+// AudioDurationSecondsCleared returns if the "audio_duration_seconds" field was cleared in this mutation.
+func (m *UsageLogMutation) AudioDurationSecondsCleared() bool {
+	_, ok := m.clearedFields[usagelog.FieldAudioDurationSeconds]
+	return ok
+}
+
+// ResetAudioDurationSeconds resets all changes to the "audio_duration_seconds" field.
+func (m *UsageLogMutation) ResetAudioDurationSeconds() {
+	m.audio_duration_seconds = nil
+	m.addaudio_duration_seconds = nil
+	delete(m.clearedFields, usagelog.FieldAudioDurationSeconds)
+}
+
+// SetAudioCharacters sets the "audio_characters" field.
+func (m *UsageLogMutation) SetAudioCharacters(i int) {
+	m.audio_characters = &i
+	m.addaudio_characters = nil
+}
+
+// AudioCharacters returns the value of the "audio_characters" field in the mutation.
+func (m *UsageLogMutation) AudioCharacters() (r int, exists bool) {
+	v := m.audio_characters
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldAudioCharacters returns the old "audio_characters" field's value of the UsageLog entity.
+// If the UsageLog object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *UsageLogMutation) OldAudioCharacters(ctx context.Context) (v *int, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldAudioCharacters is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldAudioCharacters requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldAudioCharacters: %w", err)
+	}
+	return oldValue.AudioCharacters, nil
+}
+
+// AddAudioCharacters adds i to the "audio_characters" field.
+func (m *UsageLogMutation) AddAudioCharacters(i int) {
+	if m.addaudio_characters != nil {
+		*m.addaudio_characters += i
+	} else {
+		m.addaudio_characters = &i
+	}
+}
+
+// AddedAudioCharacters returns the value that was added to the "audio_characters" field in this mutation.
+func (m *UsageLogMutation) AddedAudioCharacters() (r int, exists bool) {
+	v := m.addaudio_characters
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// ClearAudioCharacters clears the value of the "audio_characters" field.
+func (m *UsageLogMutation) ClearAudioCharacters() {
+	m.audio_characters = nil
+	m.addaudio_characters = nil
+	m.clearedFields[usagelog.FieldAudioCharacters] = struct{}{}
+}
+
+// AudioCharactersCleared returns if the "audio_characters" field was cleared in this mutation.
+func (m *UsageLogMutation) AudioCharactersCleared() bool {
+	_, ok := m.clearedFields[usagelog.FieldAudioCharacters]
+	return ok
+}
+
+// ResetAudioCharacters resets all changes to the "audio_characters" field.
+func (m *UsageLogMutation) ResetAudioCharacters() {
+	m.audio_characters = nil
+	m.addaudio_characters = nil
+	delete(m.clearedFields, usagelog.FieldAudioCharacters)
+}
+
 // SetCacheTTLOverridden sets the "cache_ttl_overridden" field.
 func (m *UsageLogMutation) SetCacheTTLOverridden(b bool) {
 	m.cache_ttl_overridden = &b
@@ -24845,7 +26728,7 @@ func (m *UsageLogMutation) Type() string {
 // order to get all numeric fields that were incremented/decremented, call
 // AddedFields().
 func (m *UsageLogMutation) Fields() []string {
-	fields := make([]string, 0, 32)
+	fields := make([]string, 0, 34)
 	if m.user != nil {
 		fields = append(fields, usagelog.FieldUserID)
 	}
@@ -24936,6 +26819,12 @@ func (m *UsageLogMutation) Fields() []string {
 	if m.media_type != nil {
 		fields = append(fields, usagelog.FieldMediaType)
 	}
+	if m.audio_duration_seconds != nil {
+		fields = append(fields, usagelog.FieldAudioDurationSeconds)
+	}
+	if m.audio_characters != nil {
+		fields = append(fields, usagelog.FieldAudioCharacters)
+	}
 	if m.cache_ttl_overridden != nil {
 		fields = append(fields, usagelog.FieldCacheTTLOverridden)
 	}
@@ -25010,6 +26899,10 @@ func (m *UsageLogMutation) Field(name string) (ent.Value, bool) {
 		return m.ImageSize()
 	case usagelog.FieldMediaType:
 		return m.MediaType()
+	case usagelog.FieldAudioDurationSeconds:
+		return m.AudioDurationSeconds()
+	case usagelog.FieldAudioCharacters:
+		return m.AudioCharacters()
 	case usagelog.FieldCacheTTLOverridden:
 		return m.CacheTTLOverridden()
 	case usagelog.FieldCreatedAt:
@@ -25083,6 +26976,10 @@ func (m *UsageLogMutation) OldField(ctx context.Context, name string) (ent.Value
 		return m.OldImageSize(ctx)
 	case usagelog.FieldMediaType:
 		return m.OldMediaType(ctx)
+	case usagelog.FieldAudioDurationSeconds:
+		return m.OldAudioDurationSeconds(ctx)
+	case usagelog.FieldAudioCharacters:
+		return m.OldAudioCharacters(ctx)
 	case usagelog.FieldCacheTTLOverridden:
 		return m.OldCacheTTLOverridden(ctx)
 	case usagelog.FieldCreatedAt:
@@ -25188,42 +27085,42 @@ func (m *UsageLogMutation) SetField(name string, value ent.Value) error {
 		m.SetCacheCreation1hTokens(v)
 		return nil
 	case usagelog.FieldInputCost:
-		v, ok := value.(float64)
+		v, ok := value.(money.Amount)
 		if !ok {
 			return fmt.Errorf("unexpected type %T for field %s", value, name)
 		}
 		m.SetInputCost(v)
 		return nil
 	case usagelog.FieldOutputCost:
-		v, ok := value.(float64)
+		v, ok := value.(money.Amount)
 		if !ok {
 			return fmt.Errorf("unexpected type %T for field %s", value, name)
 		}
 		m.SetOutputCost(v)
 		return nil
 	case usagelog.FieldCacheCreationCost:
-		v, ok := value.(float64)
+		v, ok := value.(money.Amount)
 		if !ok {
 			return fmt.Errorf("unexpected type %T for field %s", value, name)
 		}
 		m.SetCacheCreationCost(v)
 		return nil
 	case usagelog.FieldCacheReadCost:
-		v, ok := value.(float64)
+		v, ok := value.(money.Amount)
 		if !ok {
 			return fmt.Errorf("unexpected type %T for field %s", value, name)
 		}
 		m.SetCacheReadCost(v)
 		return nil
 	case usagelog.FieldTotalCost:
-		v, ok := value.(float64)
+		v, ok := value.(money.Amount)
 		if !ok {
 			return fmt.Errorf("unexpected type %T for field %s", value, name)
 		}
 		m.SetTotalCost(v)
 		return nil
 	case usagelog.FieldActualCost:
-		v, ok := value.(float64)
+		v, ok := value.(money.Amount)
 		if !ok {
 			return fmt.Errorf("unexpected type %T for field %s", value, name)
 		}
@@ -25306,6 +27203,20 @@ func (m *UsageLogMutation) SetField(name string, value ent.Value) error {
 		}
 		m.SetMediaType(v)
 		return nil
+	case usagelog.FieldAudioDurationSeconds:
+		v, ok := value.(float64)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetAudioDurationSeconds(v)
+		return nil
+	case usagelog.FieldAudioCharacters:
+		v, ok := value.(int)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetAudioCharacters(v)
+		return nil
 	case usagelog.FieldCacheTTLOverridden:
 		v, ok := value.(bool)
 		if !ok {
@@ -25382,6 +27293,12 @@ func (m *UsageLogMutation) AddedFields() []string {
 	if m.addimage_count != nil {
 		fields = append(fields, usagelog.FieldImageCount)
 	}
+	if m.addaudio_duration_seconds != nil {
+		fields = append(fields, usagelog.FieldAudioDurationSeconds)
+	}
+	if m.addaudio_characters != nil {
+		fields = append(fields, usagelog.FieldAudioCharacters)
+	}
 	return fields
 }
 
@@ -25426,6 +27343,10 @@ func (m *UsageLogMutation) AddedField(name string) (ent.Value, bool) {
 		return m.AddedFirstTokenMs()
 	case usagelog.FieldImageCount:
 		return m.AddedImageCount()
+	case usagelog.FieldAudioDurationSeconds:
+		return m.AddedAudioDurationSeconds()
+	case usagelog.FieldAudioCharacters:
+		return m.AddedAudioCharacters()
 	}
 	return nil, false
 }
@@ -25478,42 +27399,42 @@ func (m *UsageLogMutation) AddField(name string, value ent.Value) error {
 		m.AddCacheCreation1hTokens(v)
 		return nil
 	case usagelog.FieldInputCost:
-		v, ok := value.(float64)
+		v, ok := value.(money.Amount)
 		if !ok {
 			return fmt.Errorf("unexpected type %T for field %s", value, name)
 		}
 		m.AddInputCost(v)
 		return nil
 	case usagelog.FieldOutputCost:
-		v, ok := value.(float64)
+		v, ok := value.(money.Amount)
 		if !ok {
 			return fmt.Errorf("unexpected type %T for field %s", value, name)
 		}
 		m.AddOutputCost(v)
 		return nil
 	case usagelog.FieldCacheCreationCost:
-		v, ok := value.(float64)
+		v, ok := value.(money.Amount)
 		if !ok {
 			return fmt.Errorf("unexpected type %T for field %s", value, name)
 		}
 		m.AddCacheCreationCost(v)
 		return nil
 	case usagelog.FieldCacheReadCost:
-		v, ok := value.(float64)
+		v, ok := value.(money.Amount)
 		if !ok {
 			return fmt.Errorf("unexpected type %T for field %s", value, name)
 		}
 		m.AddCacheReadCost(v)
 		return nil
 	case usagelog.FieldTotalCost:
-		v, ok := value.(float64)
+		v, ok := value.(money.Amount)
 		if !ok {
 			return fmt.Errorf("unexpected type %T for field %s", value, name)
 		}
 		m.AddTotalCost(v)
 		return nil
 	case usagelog.FieldActualCost:
-		v, ok := value.(float64)
+		v, ok := value.(money.Amount)
 		if !ok {
 			return fmt.Errorf("unexpected type %T for field %s", value, name)
 		}
@@ -25561,6 +27482,20 @@ func (m *UsageLogMutation) AddField(name string, value ent.Value) error {
 		}
 		m.AddImageCount(v)
 		return nil
+	case usagelog.FieldAudioDurationSeconds:
+		v, ok := value.(float64)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.AddAudioDurationSeconds(v)
+		return nil
+	case usagelog.FieldAudioCharacters:
+		v, ok := value.(int)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.AddAudioCharacters(v)
+		return nil
 	}
 	return fmt.Errorf("unknown UsageLog numeric field %s", name)
 }
@@ -25596,6 +27531,12 @@ func (m *UsageLogMutation) ClearedFields() []string {
 	if m.FieldCleared(usagelog.FieldMediaType) {
 		fields = append(fields, usagelog.FieldMediaType)
 	}
+	if m.FieldCleared(usagelog.FieldAudioDurationSeconds) {
+		fields = append(fields, usagelog.FieldAudioDurationSeconds)
+	}
+	if m.FieldCleared(usagelog.FieldAudioCharacters) {
+		fields = append(fields, usagelog.FieldAudioCharacters)
+	}
 	return fields
 }
 
@@ -25637,6 +27578,12 @@ func (m *UsageLogMutation) ClearField(name string) error {
 	case usagelog.FieldMediaType:
 		m.ClearMediaType()
 		return nil
+	case usagelog.FieldAudioDurationSeconds:
+		m.ClearAudioDurationSeconds()
+		return nil
+	case usagelog.FieldAudioCharacters:
+		m.ClearAudioCharacters()
+		return nil
 	}
 	return fmt.Errorf("unknown UsageLog nullable field %s", name)
 }
@@ -25735,6 +27682,12 @@ func (m *UsageLogMutation) ResetField(name string) error {
 	case usagelog.FieldMediaType:
 		m.ResetMediaType()
 		return nil
+	case usagelog.FieldAudioDurationSeconds:
+		m.ResetAudioDurationSeconds()
+		return nil
+	case usagelog.FieldAudioCharacters:
+		m.ResetAudioCharacters()
+		return nil
 	case usagelog.FieldCacheTTLOverridden:
 		m.ResetCacheTTLOverridden()
 		return nil
@@ -25903,8 +27856,8 @@ type UserMutation struct {
 	email                          *string
 	password_hash                  *string
 	role                           *string
-	balance                        *float64
-	addbalance                     *float64
+	balance                        *money.Amount
+	addbalance                     *money.Amount
 	concurrency                    *int
 	addconcurrency                 *int
 	status                         *string
@@ -26281,13 +28234,13 @@ func (m *UserMutation) ResetRole() {
 }
 
 // SetBalance sets the "balance" field.
-func (m *UserMutation) SetBalance(f float64) {
-	m.balance = &f
+func (m *UserMutation) SetBalance(value money.Amount) {
+	m.balance = &value
 	m.addbalance = nil
 }
 
 // Balance returns the value of the "balance" field in the mutation.
-func (m *UserMutation) Balance() (r float64, exists bool) {
+func (m *UserMutation) Balance() (r money.Amount, exists bool) {
 	v := m.balance
 	if v == nil {
 		return
@@ -26298,7 +28251,7 @@ func (m *UserMutation) Balance() (r float64, exists bool) {
 // OldBalance returns the old "balance" field's value of the User entity.
 // If the User object wasn't provided to the builder, the object is fetched from the database.
 // An error is returned if the mutation operation is not UpdateOne, or the database query fails.
-func (m *UserMutation) OldBalance(ctx context.Context) (v float64, err error) {
+func (m *UserMutation) OldBalance(ctx context.Context) (v money.Amount, err error) {
 	if !m.op.Is(OpUpdateOne) {
 		return v, errors.New("OldBalance is only allowed on UpdateOne operations")
 	}
@@ -26312,17 +28265,17 @@ func (m *UserMutation) OldBalance(ctx context.Context) (v float64, err error) {
 	return oldValue.Balance, nil
 }
 
-// AddBalance adds f to the "balance" field.
-func (m *UserMutation) AddBalance(f float64) {
+// AddBalance adds value to the "balance" field.
+func (m *UserMutation) AddBalance(value money.Amount) {
 	if m.addbalance != nil {
-		*m.addbalance += f
+		*m.addbalance += value
 	} else {
-		m.addbalance = &f
+		m.addbalance = &value
 	}
 }
 
 // AddedBalance returns the value that was added to the "balance" field in this mutation.
-func (m *UserMutation) AddedBalance() (r float64, exists bool) {
+func (m *UserMutation) AddedBalance() (r money.Amount, exists bool) {
 	v := m.addbalance
 	if v == nil {
 		return
@@ -27502,7 +29455,7 @@ func (m *UserMutation) SetField(name string, value ent.Value) error {
 		m.SetRole(v)
 		return nil
 	case user.FieldBalance:
-		v, ok := value.(float64)
+		v, ok := value.(money.Amount)
 		if !ok {
 			return fmt.Errorf("unexpected type %T for field %s", value, name)
 		}
@@ -27617,7 +29570,7 @@ func (m *UserMutation) AddedField(name string) (ent.Value, bool) {
 func (m *UserMutation) AddField(name string, value ent.Value) error {
 	switch name {
 	case user.FieldBalance:
-		v, ok := value.(float64)
+		v, ok := value.(money.Amount)
 		if !ok {
 			return fmt.Errorf("unexpected type %T for field %s", value, name)
 		}
@@ -30293,12 +32246,12 @@ type UserSubscriptionMutation struct {
 	daily_window_start      *time.Time
 	weekly_window_start     *time.Time
 	monthly_window_start    *time.Time
-	daily_usage_usd         *float64
-	adddaily_usage_usd      *float64
-	weekly_usage_usd        *float64
-	addweekly_usage_usd     *float64
-	monthly_usage_usd       *float64
-	addmonthly_usage_usd    *float64
+	daily_usage_usd         *money.Amount
+	adddaily_usage_usd      *money.Amount
+	weekly_usage_usd        *money.Amount
+	addweekly_usage_usd     *money.Amount
+	monthly_usage_usd       *money.Amount
+	addmonthly_usage_usd    *money.Amount
 	assigned_at             *time.Time
 	notes                   *string
 	clearedFields           map[string]struct{}
@@ -30863,13 +32816,13 @@ func (m *UserSubscriptionMutation) ResetMonthlyWindowStart() {
 }
 
 // SetDailyUsageUsd sets the "daily_usage_usd" field.
-func (m *UserSubscriptionMutation) SetDailyUsageUsd(f float64) {
-	m.daily_usage_usd = &f
+func (m *UserSubscriptionMutation) SetDailyUsageUsd(value money.Amount) {
+	m.daily_usage_usd = &value
 	m.adddaily_usage_usd = nil
 }
 
 // DailyUsageUsd returns the value of the "daily_usage_usd" field in the mutation.
-func (m *UserSubscriptionMutation) DailyUsageUsd() (r float64, exists bool) {
+func (m *UserSubscriptionMutation) DailyUsageUsd() (r money.Amount, exists bool) {
 	v := m.daily_usage_usd
 	if v == nil {
 		return
@@ -30880,7 +32833,7 @@ func (m *UserSubscriptionMutation) DailyUsageUsd() (r float64, exists bool) {
 // OldDailyUsageUsd returns the old "daily_usage_usd" field's value of the UserSubscription entity.
 // If the UserSubscription object wasn't provided to the builder, the object is fetched from the database.
 // An error is returned if the mutation operation is not UpdateOne, or the database query fails.
-func (m *UserSubscriptionMutation) OldDailyUsageUsd(ctx context.Context) (v float64, err error) {
+func (m *UserSubscriptionMutation) OldDailyUsageUsd(ctx context.Context) (v money.Amount, err error) {
 	if !m.op.Is(OpUpdateOne) {
 		return v, errors.New("OldDailyUsageUsd is only allowed on UpdateOne operations")
 	}
@@ -30894,17 +32847,17 @@ func (m *UserSubscriptionMutation) OldDailyUsageUsd(ctx context.Context) (v floa
 	return oldValue.DailyUsageUsd, nil
 }
 
-// AddDailyUsageUsd adds f to the "daily_usage_usd" field.
-func (m *UserSubscriptionMutation) AddDailyUsageUsd(f float64) {
+// AddDailyUsageUsd adds value to the "daily_usage_usd" field.
+func (m *UserSubscriptionMutation) AddDailyUsageUsd(value money.Amount) {
 	if m.adddaily_usage_usd != nil {
-		*m.adddaily_usage_usd += f
+		*m.adddaily_usage_usd += value
 	} else {
-		m.adddaily_usage_usd = &f
+		m.adddaily_usage_usd = &value
 	}
 }
 
 // AddedDailyUsageUsd returns the value that was added to the "daily_usage_usd" field in this mutation.
-func (m *UserSubscriptionMutation) AddedDailyUsageUsd() (r float64, exists bool) {
+func (m *UserSubscriptionMutation) AddedDailyUsageUsd() (r money.Amount, exists bool) {
 	v := m.adddaily_usage_usd
 	if v == nil {
 		return
@@ -30919,13 +32872,13 @@ func (m *UserSubscriptionMutation) ResetDailyUsageUsd() {
 }
 
 // SetWeeklyUsageUsd sets the "weekly_usage_usd" field.
-func (m *UserSubscriptionMutation) SetWeeklyUsageUsd(f float64) {
-	m.weekly_usage_usd = &f
+func (m *UserSubscriptionMutation) SetWeeklyUsageUsd(value money.Amount) {
+	m.weekly_usage_usd = &value
 	m.addweekly_usage_usd = nil
 }
 
 // WeeklyUsageUsd returns the value of the "weekly_usage_usd" field in the mutation.
-func (m *UserSubscriptionMutation) WeeklyUsageUsd() (r float64, exists bool) {
+func (m *UserSubscriptionMutation) WeeklyUsageUsd() (r money.Amount, exists bool) {
 	v := m.weekly_usage_usd
 	if v == nil {
 		return
@@ -30936,7 +32889,7 @@ func (m *UserSubscriptionMutation) WeeklyUsageUsd() (r float64, exists bool) {
 // OldWeeklyUsageUsd returns the old "weekly_usage_usd" field's value of the UserSubscription entity.
 // If the UserSubscription object wasn't provided to the builder, the object is fetched from the database.
 // An error is returned if the mutation operation is not UpdateOne, or the database query fails.
-func (m *UserSubscriptionMutation) OldWeeklyUsageUsd(ctx context.Context) (v float64, err error) {
+func (m *UserSubscriptionMutation) OldWeeklyUsageUsd(ctx context.Context) (v money.Amount, err error) {
 	if !m.op.Is(OpUpdateOne) {
 		return v, errors.New("OldWeeklyUsageUsd is only allowed on UpdateOne operations")
 	}
@@ -30950,17 +32903,17 @@ func (m *UserSubscriptionMutation) OldWeeklyUsageUsd(ctx context.Context) (v flo
 	return oldValue.WeeklyUsageUsd, nil
 }
 
-// AddWeeklyUsageUsd adds f to the "weekly_usage_usd" field.
-func (m *UserSubscriptionMutation) AddWeeklyUsageUsd(f float64) {
+// AddWeeklyUsageUsd adds value to the "weekly_usage_usd" field.
+func (m *UserSubscriptionMutation) AddWeeklyUsageUsd(value money.Amount) {
 	if m.addweekly_usage_usd != nil {
-		*m.addweekly_usage_usd += f
+		*m.addweekly_usage_usd += value
 	} else {
-		m.addweekly_usage_usd = &f
+		m.addweekly_usage_usd = &value
 	}
 }
 
 // AddedWeeklyUsageUsd returns the value that was added to the "weekly_usage_usd" field in this mutation.
-func (m *UserSubscriptionMutation) AddedWeeklyUsageUsd() (r float64, exists bool) {
+func (m *UserSubscriptionMutation) AddedWeeklyUsageUsd() (r money.Amount, exists bool) {
 	v := m.addweekly_usage_usd
 	if v == nil {
 		return
@@ -30975,13 +32928,13 @@ func (m *UserSubscriptionMutation) ResetWeeklyUsageUsd() {
 }
 
 // SetMonthlyUsageUsd sets the "monthly_usage_usd" field.
-func (m *UserSubscriptionMutation) SetMonthlyUsageUsd(f float64) {
-	m.monthly_usage_usd = &f
+func (m *UserSubscriptionMutation) SetMonthlyUsageUsd(value money.Amount) {
+	m.monthly_usage_usd = &value
 	m.addmonthly_usage_usd = nil
 }
 
 // MonthlyUsageUsd returns the value of the "monthly_usage_usd" field in the mutation.
-func (m *UserSubscriptionMutation) MonthlyUsageUsd() (r float64, exists bool) {
+func (m *UserSubscriptionMutation) MonthlyUsageUsd() (r money.Amount, exists bool) {
 	v := m.monthly_usage_usd
 	if v == nil {
 		return
@@ -30992,7 +32945,7 @@ func (m *UserSubscriptionMutation) MonthlyUsageUsd() (r float64, exists bool) {
 // OldMonthlyUsageUsd returns the old "monthly_usage_usd" field's value of the UserSubscription entity.
 // If the UserSubscription object wasn't provided to the builder, the object is fetched from the database.
 // An error is returned if the mutation operation is not UpdateOne, or the database query fails.
-func (m *UserSubscriptionMutation) OldMonthlyUsageUsd(ctx context.Context) (v float64, err error) {
+func (m *UserSubscriptionMutation) OldMonthlyUsageUsd(ctx context.Context) (v money.Amount, err error) {
 	if !m.op.Is(OpUpdateOne) {
 		return v, errors.New("OldMonthlyUsageUsd is only allowed on UpdateOne operations")
 	}
@@ -31006,17 +32959,17 @@ func (m *UserSubscriptionMutation) OldMonthlyUsageUsd(ctx context.Context) (v fl
 	return oldValue.MonthlyUsageUsd, nil
 }
 
-// AddMonthlyUsageUsd adds f to the "monthly_usage_usd" field.
-func (m *UserSubscriptionMutation) AddMonthlyUsageUsd(f float64) {
+// AddMonthlyUsageUsd adds value to the "monthly_usage_usd" field.
+func (m *UserSubscriptionMutation) AddMonthlyUsageUsd(value money.Amount) {
 	if m.addmonthly_usage_usd != nil {
-		*m.addmonthly_usage_usd += f
+		*m.addmonthly_usage_usd += value
 	} else {
-		m.addmonthly_usage_usd = &f
+		m.addmonthly_usage_usd = &value
 	}
 }
 
 // AddedMonthlyUsageUsd returns the value that was added to the "monthly_usage_usd" field in this mutation.
-func (m *UserSubscriptionMutation) AddedMonthlyUsageUsd() (r float64, exists bool) {
+func (m *UserSubscriptionMutation) AddedMonthlyUsageUsd() (r money.Amount, exists bool) {
 	v := m.addmonthly_usage_usd
 	if v == nil {
 		return
@@ -31570,21 +33523,21 @@ func (m *UserSubscriptionMutation) SetField(name string, value ent.Value) error
 		m.SetMonthlyWindowStart(v)
 		return nil
 	case usersubscription.FieldDailyUsageUsd:
-		v, ok := value.(float64)
+		v, ok := value.(money.Amount)
 		if !ok {
 			return fmt.Errorf("unexpected type %T for field %s", value, name)
 		}
 		m.SetDailyUsageUsd(v)
 		return nil
 	case usersubscription.FieldWeeklyUsageUsd:
-		v, ok := value.(float64)
+		v, ok := value.(money.Amount)
 		if !ok {
 			return fmt.Errorf("unexpected type %T for field %s", value, name)
 		}
 		m.SetWeeklyUsageUsd(v)
 		return nil
 	case usersubscription.FieldMonthlyUsageUsd:
-		v, ok := value.(float64)
+		v, ok := value.(money.Amount)
 		if !ok {
 			return fmt.Errorf("unexpected type %T for field %s", value, name)
 		}
@@ -31652,21 +33605,21 @@ func (m *UserSubscriptionMutation) AddedField(name string) (ent.Value, bool) {
 func (m *UserSubscriptionMutation) AddField(name string, value ent.Value) error {
 	switch name {
 	case usersubscription.FieldDailyUsageUsd:
-		v, ok := value.(float64)
+		v, ok := value.(money.Amount)
 		if !ok {
 			return fmt.Errorf("unexpected type %T for field %s", value, name)
 		}
 		m.AddDailyUsageUsd(v)
 		return nil
 	case usersubscription.FieldWeeklyUsageUsd:
-		v, ok := value.(float64)
+		v, ok := value.(money.Amount)
 		if !ok {
 			return fmt.Errorf("unexpected type %T for field %s", value, name)
 		}
 		m.AddWeeklyUsageUsd(v)
 		return nil
 	case usersubscription.FieldMonthlyUsageUsd:
-		v, ok := value.(float64)
+		v, ok := value.(money.Amount)
 		if !ok {
 			return fmt.Errorf("unexpected type %T for field %s", value, name)
 		}
@@ -3,6 +3,7 @@
 package ent
 
 import (
+	"encoding/json"
 	"fmt"
 	"strings"
 	"time"
@@ -10,6 +11,7 @@ import (
 	"entgo.io/ent"
 	"entgo.io/ent/dialect/sql"
 	"github.com/Wei-Shaw/sub2api/ent/promocode"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
 )
 
 // PromoCode is the model entity for the PromoCode schema.
@@ -20,11 +22,29 @@ type PromoCode struct {
 	// 优惠码
 	Code string `json:"code,omitempty"`
 	// 赠送余额金额
-	BonusAmount float64 `json:"bonus_amount,omitempty"`
+	BonusAmount money.Amount `json:"bonus_amount,omitempty"`
 	// 最大使用次数，0表示无限制
 	MaxUses int `json:"max_uses,omitempty"`
 	// 已使用次数
 	UsedCount int `json:"used_count,omitempty"`
+	// 首次充值赠送比例（百分比），0表示不赠送
+	TopupBonusPercent float64 `json:"topup_bonus_percent,omitempty"`
+	// 首次充值赠送上限，0表示无上限
+	TopupBonusMax money.Amount `json:"topup_bonus_max,omitempty"`
+	// 触发充值赠送的最低单笔充值金额
+	MinTopupAmount money.Amount `json:"min_topup_amount,omitempty"`
+	// 每个用户最多使用次数，0表示无限制
+	PerUserLimit int `json:"per_user_limit,omitempty"`
+	// 允许使用的邮箱域名，空表示不限制
+	AllowedEmailDomains []string `json:"allowed_email_domains,omitempty"`
+	// 要求用户属性取值（属性 key -> 值），空表示不限制
+	RequiredAttributes map[string]string `json:"required_attributes,omitempty"`
+	// 是否可与其他可叠加优惠码同时使用
+	Stackable bool `json:"stackable,omitempty"`
+	// 赠送试用订阅的分组ID
+	TrialGroupID *int64 `json:"trial_group_id,omitempty"`
+	// 试用订阅天数
+	TrialDays int `json:"trial_days,omitempty"`
 	// 状态: active, disabled
 	Status string `json:"status,omitempty"`
 	// 过期时间，null表示永不过期
@@ -64,9 +84,15 @@ func (*PromoCode) scanValues(columns []string) ([]any, error) {
 	values := make([]any, len(columns))
 	for i := range columns {
 		switch columns[i] {
-		case promocode.FieldBonusAmount:
+		case promocode.FieldAllowedEmailDomains, promocode.FieldRequiredAttributes:
+			values[i] = new([]byte)
+		case promocode.FieldBonusAmount, promocode.FieldTopupBonusMax, promocode.FieldMinTopupAmount:
+			values[i] = new(money.Amount)
+		case promocode.FieldStackable:
+			values[i] = new(sql.NullBool)
+		case promocode.FieldTopupBonusPercent:
 			values[i] = new(sql.NullFloat64)
-		case promocode.FieldID, promocode.FieldMaxUses, promocode.FieldUsedCount:
+		case promocode.FieldID, promocode.FieldMaxUses, promocode.FieldUsedCount, promocode.FieldPerUserLimit, promocode.FieldTrialGroupID, promocode.FieldTrialDays:
 			values[i] = new(sql.NullInt64)
 		case promocode.FieldCode, promocode.FieldStatus, promocode.FieldNotes:
 			values[i] = new(sql.NullString)
@@ -100,10 +126,10 @@ func (_m *PromoCode) assignValues(columns []string, values []any) error {
 				_m.Code = value.String
 			}
 		case promocode.FieldBonusAmount:
-			if value, ok := values[i].(*sql.NullFloat64); !ok {
+			if value, ok := values[i].(*money.Amount); !ok {
 				return fmt.Errorf("unexpected type %T for field bonus_amount", values[i])
-			} else if value.Valid {
-				_m.BonusAmount = value.Float64
+			} else if value != nil {
+				_m.BonusAmount = *value
 			}
 		case promocode.FieldMaxUses:
 			if value, ok := values[i].(*sql.NullInt64); !ok {
@@ -117,6 +143,65 @@ func (_m *PromoCode) assignValues(columns []string, values []any) error {
 			} else if value.Valid {
 				_m.UsedCount = int(value.Int64)
 			}
+		case promocode.FieldTopupBonusPercent:
+			if value, ok := values[i].(*sql.NullFloat64); !ok {
+				return fmt.Errorf("unexpected type %T for field topup_bonus_percent", values[i])
+			} else if value.Valid {
+				_m.TopupBonusPercent = value.Float64
+			}
+		case promocode.FieldTopupBonusMax:
+			if value, ok := values[i].(*money.Amount); !ok {
+				return fmt.Errorf("unexpected type %T for field topup_bonus_max", values[i])
+			} else if value != nil {
+				_m.TopupBonusMax = *value
+			}
+		case promocode.FieldMinTopupAmount:
+			if value, ok := values[i].(*money.Amount); !ok {
+				return fmt.Errorf("unexpected type %T for field min_topup_amount", values[i])
+			} else if value != nil {
+				_m.MinTopupAmount = *value
+			}
+		case promocode.FieldPerUserLimit:
+			if value, ok := values[i].(*sql.NullInt64); !ok {
+				return fmt.Errorf("unexpected type %T for field per_user_limit", values[i])
+			} else if value.Valid {
+				_m.PerUserLimit = int(value.Int64)
+			}
+		case promocode.FieldAllowedEmailDomains:
+			if value, ok := values[i].(*[]byte); !ok {
+				return fmt.Errorf("unexpected type %T for field allowed_email_domains", values[i])
+			} else if value != nil && len(*value) > 0 {
+				if err := json.Unmarshal(*value, &_m.AllowedEmailDomains); err != nil {
+					return fmt.Errorf("unmarshal field allowed_email_domains: %w", err)
+				}
+			}
+		case promocode.FieldRequiredAttributes:
+			if value, ok := values[i].(*[]byte); !ok {
+				return fmt.Errorf("unexpected type %T for field required_attributes", values[i])
+			} else if value != nil && len(*value) > 0 {
+				if err := json.Unmarshal(*value, &_m.RequiredAttributes); err != nil {
+					return fmt.Errorf("unmarshal field required_attributes: %w", err)
+				}
+			}
+		case promocode.FieldStackable:
+			if value, ok := values[i].(*sql.NullBool); !ok {
+				return fmt.Errorf("unexpected type %T for field stackable", values[i])
+			} else if value.Valid {
+				_m.Stackable = value.Bool
+			}
+		case promocode.FieldTrialGroupID:
+			if value, ok := values[i].(*sql.NullInt64); !ok {
+				return fmt.Errorf("unexpected type %T for field trial_group_id", values[i])
+			} else if value.Valid {
+				_m.TrialGroupID = new(int64)
+				*_m.TrialGroupID = value.Int64
+			}
+		case promocode.FieldTrialDays:
+			if value, ok := values[i].(*sql.NullInt64); !ok {
+				return fmt.Errorf("unexpected type %T for field trial_days", values[i])
+			} else if value.Valid {
+				_m.TrialDays = int(value.Int64)
+			}
 		case promocode.FieldStatus:
 			if value, ok := values[i].(*sql.NullString); !ok {
 				return fmt.Errorf("unexpected type %T for field status", values[i])
@@ -202,6 +287,35 @@ func (_m *PromoCode) String() string {
 	builder.WriteString("used_count=")
 	builder.WriteString(fmt.Sprintf("%v", _m.UsedCount))
 	builder.WriteString(", ")
+	builder.WriteString("topup_bonus_percent=")
+	builder.WriteString(fmt.Sprintf("%v", _m.TopupBonusPercent))
+	builder.WriteString(", ")
+	builder.WriteString("topup_bonus_max=")
+	builder.WriteString(fmt.Sprintf("%v", _m.TopupBonusMax))
+	builder.WriteString(", ")
+	builder.WriteString("min_topup_amount=")
+	builder.WriteString(fmt.Sprintf("%v", _m.MinTopupAmount))
+	builder.WriteString(", ")
+	builder.WriteString("per_user_limit=")
+	builder.WriteString(fmt.Sprintf("%v", _m.PerUserLimit))
+	builder.WriteString(", ")
+	builder.WriteString("allowed_email_domains=")
+	builder.WriteString(fmt.Sprintf("%v", _m.AllowedEmailDomains))
+	builder.WriteString(", ")
+	builder.WriteString("required_attributes=")
+	builder.WriteString(fmt.Sprintf("%v", _m.RequiredAttributes))
+	builder.WriteString(", ")
+	builder.WriteString("stackable=")
+	builder.WriteString(fmt.Sprintf("%v", _m.Stackable))
+	builder.WriteString(", ")
+	if v := _m.TrialGroupID; v != nil {
+		builder.WriteString("trial_group_id=")
+		builder.WriteString(fmt.Sprintf("%v", *v))
+	}
+	builder.WriteString(", ")
+	builder.WriteString("trial_days=")
+	builder.WriteString(fmt.Sprintf("%v", _m.TrialDays))
+	builder.WriteString(", ")
 	builder.WriteString("status=")
 	builder.WriteString(_m.Status)
 	builder.WriteString(", ")
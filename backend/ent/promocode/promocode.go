@@ -7,6 +7,7 @@ import (
 
 	"entgo.io/ent/dialect/sql"
 	"entgo.io/ent/dialect/sql/sqlgraph"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
 )
 
 const (
@@ -22,6 +23,24 @@ const (
 	FieldMaxUses = "max_uses"
 	// FieldUsedCount holds the string denoting the used_count field in the database.
 	FieldUsedCount = "used_count"
+	// FieldTopupBonusPercent holds the string denoting the topup_bonus_percent field in the database.
+	FieldTopupBonusPercent = "topup_bonus_percent"
+	// FieldTopupBonusMax holds the string denoting the topup_bonus_max field in the database.
+	FieldTopupBonusMax = "topup_bonus_max"
+	// FieldMinTopupAmount holds the string denoting the min_topup_amount field in the database.
+	FieldMinTopupAmount = "min_topup_amount"
+	// FieldPerUserLimit holds the string denoting the per_user_limit field in the database.
+	FieldPerUserLimit = "per_user_limit"
+	// FieldAllowedEmailDomains holds the string denoting the allowed_email_domains field in the database.
+	FieldAllowedEmailDomains = "allowed_email_domains"
+	// FieldRequiredAttributes holds the string denoting the required_attributes field in the database.
+	FieldRequiredAttributes = "required_attributes"
+	// FieldStackable holds the string denoting the stackable field in the database.
+	FieldStackable = "stackable"
+	// FieldTrialGroupID holds the string denoting the trial_group_id field in the database.
+	FieldTrialGroupID = "trial_group_id"
+	// FieldTrialDays holds the string denoting the trial_days field in the database.
+	FieldTrialDays = "trial_days"
 	// FieldStatus holds the string denoting the status field in the database.
 	FieldStatus = "status"
 	// FieldExpiresAt holds the string denoting the expires_at field in the database.
@@ -52,6 +71,15 @@ var Columns = []string{
 	FieldBonusAmount,
 	FieldMaxUses,
 	FieldUsedCount,
+	FieldTopupBonusPercent,
+	FieldTopupBonusMax,
+	FieldMinTopupAmount,
+	FieldPerUserLimit,
+	FieldAllowedEmailDomains,
+	FieldRequiredAttributes,
+	FieldStackable,
+	FieldTrialGroupID,
+	FieldTrialDays,
 	FieldStatus,
 	FieldExpiresAt,
 	FieldNotes,
@@ -73,11 +101,23 @@ var (
 	// CodeValidator is a validator for the "code" field. It is called by the builders before save.
 	CodeValidator func(string) error
 	// DefaultBonusAmount holds the default value on creation for the "bonus_amount" field.
-	DefaultBonusAmount float64
+	DefaultBonusAmount money.Amount
 	// DefaultMaxUses holds the default value on creation for the "max_uses" field.
 	DefaultMaxUses int
 	// DefaultUsedCount holds the default value on creation for the "used_count" field.
 	DefaultUsedCount int
+	// DefaultTopupBonusPercent holds the default value on creation for the "topup_bonus_percent" field.
+	DefaultTopupBonusPercent float64
+	// DefaultTopupBonusMax holds the default value on creation for the "topup_bonus_max" field.
+	DefaultTopupBonusMax money.Amount
+	// DefaultMinTopupAmount holds the default value on creation for the "min_topup_amount" field.
+	DefaultMinTopupAmount money.Amount
+	// DefaultPerUserLimit holds the default value on creation for the "per_user_limit" field.
+	DefaultPerUserLimit int
+	// DefaultStackable holds the default value on creation for the "stackable" field.
+	DefaultStackable bool
+	// DefaultTrialDays holds the default value on creation for the "trial_days" field.
+	DefaultTrialDays int
 	// DefaultStatus holds the default value on creation for the "status" field.
 	DefaultStatus string
 	// StatusValidator is a validator for the "status" field. It is called by the builders before save.
@@ -118,6 +158,41 @@ func ByUsedCount(opts ...sql.OrderTermOption) OrderOption {
 	return sql.OrderByField(FieldUsedCount, opts...).ToFunc()
 }
 
+// ByTopupBonusPercent orders the results by the topup_bonus_percent field.
+func ByTopupBonusPercent(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldTopupBonusPercent, opts...).ToFunc()
+}
+
+// ByTopupBonusMax orders the results by the topup_bonus_max field.
+func ByTopupBonusMax(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldTopupBonusMax, opts...).ToFunc()
+}
+
+// ByMinTopupAmount orders the results by the min_topup_amount field.
+func ByMinTopupAmount(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldMinTopupAmount, opts...).ToFunc()
+}
+
+// ByPerUserLimit orders the results by the per_user_limit field.
+func ByPerUserLimit(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldPerUserLimit, opts...).ToFunc()
+}
+
+// ByStackable orders the results by the stackable field.
+func ByStackable(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldStackable, opts...).ToFunc()
+}
+
+// ByTrialGroupID orders the results by the trial_group_id field.
+func ByTrialGroupID(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldTrialGroupID, opts...).ToFunc()
+}
+
+// ByTrialDays orders the results by the trial_days field.
+func ByTrialDays(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldTrialDays, opts...).ToFunc()
+}
+
 // ByStatus orders the results by the status field.
 func ByStatus(opts ...sql.OrderTermOption) OrderOption {
 	return sql.OrderByField(FieldStatus, opts...).ToFunc()
@@ -8,6 +8,7 @@ import (
 	"entgo.io/ent/dialect/sql"
 	"entgo.io/ent/dialect/sql/sqlgraph"
 	"github.com/Wei-Shaw/sub2api/ent/predicate"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
 )
 
 // ID filters vertices based on their ID field.
@@ -61,7 +62,7 @@ func Code(v string) predicate.PromoCode {
 }
 
 // BonusAmount applies equality check predicate on the "bonus_amount" field. It's identical to BonusAmountEQ.
-func BonusAmount(v float64) predicate.PromoCode {
+func BonusAmount(v money.Amount) predicate.PromoCode {
 	return predicate.PromoCode(sql.FieldEQ(FieldBonusAmount, v))
 }
 
@@ -75,6 +76,41 @@ func UsedCount(v int) predicate.PromoCode {
 	return predicate.PromoCode(sql.FieldEQ(FieldUsedCount, v))
 }
 
+// TopupBonusPercent applies equality check predicate on the "topup_bonus_percent" field. It's identical to TopupBonusPercentEQ.
+func TopupBonusPercent(v float64) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldEQ(FieldTopupBonusPercent, v))
+}
+
+// TopupBonusMax applies equality check predicate on the "topup_bonus_max" field. It's identical to TopupBonusMaxEQ.
+func TopupBonusMax(v money.Amount) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldEQ(FieldTopupBonusMax, v))
+}
+
+// MinTopupAmount applies equality check predicate on the "min_topup_amount" field. It's identical to MinTopupAmountEQ.
+func MinTopupAmount(v money.Amount) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldEQ(FieldMinTopupAmount, v))
+}
+
+// PerUserLimit applies equality check predicate on the "per_user_limit" field. It's identical to PerUserLimitEQ.
+func PerUserLimit(v int) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldEQ(FieldPerUserLimit, v))
+}
+
+// Stackable applies equality check predicate on the "stackable" field. It's identical to StackableEQ.
+func Stackable(v bool) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldEQ(FieldStackable, v))
+}
+
+// TrialGroupID applies equality check predicate on the "trial_group_id" field. It's identical to TrialGroupIDEQ.
+func TrialGroupID(v int64) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldEQ(FieldTrialGroupID, v))
+}
+
+// TrialDays applies equality check predicate on the "trial_days" field. It's identical to TrialDaysEQ.
+func TrialDays(v int) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldEQ(FieldTrialDays, v))
+}
+
 // Status applies equality check predicate on the "status" field. It's identical to StatusEQ.
 func Status(v string) predicate.PromoCode {
 	return predicate.PromoCode(sql.FieldEQ(FieldStatus, v))
@@ -166,42 +202,42 @@ func CodeContainsFold(v string) predicate.PromoCode {
 }
 
 // BonusAmountEQ applies the EQ predicate on the "bonus_amount" field.
-func BonusAmountEQ(v float64) predicate.PromoCode {
+func BonusAmountEQ(v money.Amount) predicate.PromoCode {
 	return predicate.PromoCode(sql.FieldEQ(FieldBonusAmount, v))
 }
 
 // BonusAmountNEQ applies the NEQ predicate on the "bonus_amount" field.
-func BonusAmountNEQ(v float64) predicate.PromoCode {
+func BonusAmountNEQ(v money.Amount) predicate.PromoCode {
 	return predicate.PromoCode(sql.FieldNEQ(FieldBonusAmount, v))
 }
 
 // BonusAmountIn applies the In predicate on the "bonus_amount" field.
-func BonusAmountIn(vs ...float64) predicate.PromoCode {
+func BonusAmountIn(vs ...money.Amount) predicate.PromoCode {
 	return predicate.PromoCode(sql.FieldIn(FieldBonusAmount, vs...))
 }
 
 // BonusAmountNotIn applies the NotIn predicate on the "bonus_amount" field.
-func BonusAmountNotIn(vs ...float64) predicate.PromoCode {
+func BonusAmountNotIn(vs ...money.Amount) predicate.PromoCode {
 	return predicate.PromoCode(sql.FieldNotIn(FieldBonusAmount, vs...))
 }
 
 // BonusAmountGT applies the GT predicate on the "bonus_amount" field.
-func BonusAmountGT(v float64) predicate.PromoCode {
+func BonusAmountGT(v money.Amount) predicate.PromoCode {
 	return predicate.PromoCode(sql.FieldGT(FieldBonusAmount, v))
 }
 
 // BonusAmountGTE applies the GTE predicate on the "bonus_amount" field.
-func BonusAmountGTE(v float64) predicate.PromoCode {
+func BonusAmountGTE(v money.Amount) predicate.PromoCode {
 	return predicate.PromoCode(sql.FieldGTE(FieldBonusAmount, v))
 }
 
 // BonusAmountLT applies the LT predicate on the "bonus_amount" field.
-func BonusAmountLT(v float64) predicate.PromoCode {
+func BonusAmountLT(v money.Amount) predicate.PromoCode {
 	return predicate.PromoCode(sql.FieldLT(FieldBonusAmount, v))
 }
 
 // BonusAmountLTE applies the LTE predicate on the "bonus_amount" field.
-func BonusAmountLTE(v float64) predicate.PromoCode {
+func BonusAmountLTE(v money.Amount) predicate.PromoCode {
 	return predicate.PromoCode(sql.FieldLTE(FieldBonusAmount, v))
 }
 
@@ -285,6 +321,286 @@ func UsedCountLTE(v int) predicate.PromoCode {
 	return predicate.PromoCode(sql.FieldLTE(FieldUsedCount, v))
 }
 
+// TopupBonusPercentEQ applies the EQ predicate on the "topup_bonus_percent" field.
+func TopupBonusPercentEQ(v float64) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldEQ(FieldTopupBonusPercent, v))
+}
+
+// TopupBonusPercentNEQ applies the NEQ predicate on the "topup_bonus_percent" field.
+func TopupBonusPercentNEQ(v float64) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldNEQ(FieldTopupBonusPercent, v))
+}
+
+// TopupBonusPercentIn applies the In predicate on the "topup_bonus_percent" field.
+func TopupBonusPercentIn(vs ...float64) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldIn(FieldTopupBonusPercent, vs...))
+}
+
+// TopupBonusPercentNotIn applies the NotIn predicate on the "topup_bonus_percent" field.
+func TopupBonusPercentNotIn(vs ...float64) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldNotIn(FieldTopupBonusPercent, vs...))
+}
+
+// TopupBonusPercentGT applies the GT predicate on the "topup_bonus_percent" field.
+func TopupBonusPercentGT(v float64) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldGT(FieldTopupBonusPercent, v))
+}
+
+// TopupBonusPercentGTE applies the GTE predicate on the "topup_bonus_percent" field.
+func TopupBonusPercentGTE(v float64) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldGTE(FieldTopupBonusPercent, v))
+}
+
+// TopupBonusPercentLT applies the LT predicate on the "topup_bonus_percent" field.
+func TopupBonusPercentLT(v float64) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldLT(FieldTopupBonusPercent, v))
+}
+
+// TopupBonusPercentLTE applies the LTE predicate on the "topup_bonus_percent" field.
+func TopupBonusPercentLTE(v float64) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldLTE(FieldTopupBonusPercent, v))
+}
+
+// TopupBonusMaxEQ applies the EQ predicate on the "topup_bonus_max" field.
+func TopupBonusMaxEQ(v money.Amount) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldEQ(FieldTopupBonusMax, v))
+}
+
+// TopupBonusMaxNEQ applies the NEQ predicate on the "topup_bonus_max" field.
+func TopupBonusMaxNEQ(v money.Amount) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldNEQ(FieldTopupBonusMax, v))
+}
+
+// TopupBonusMaxIn applies the In predicate on the "topup_bonus_max" field.
+func TopupBonusMaxIn(vs ...money.Amount) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldIn(FieldTopupBonusMax, vs...))
+}
+
+// TopupBonusMaxNotIn applies the NotIn predicate on the "topup_bonus_max" field.
+func TopupBonusMaxNotIn(vs ...money.Amount) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldNotIn(FieldTopupBonusMax, vs...))
+}
+
+// TopupBonusMaxGT applies the GT predicate on the "topup_bonus_max" field.
+func TopupBonusMaxGT(v money.Amount) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldGT(FieldTopupBonusMax, v))
+}
+
+// TopupBonusMaxGTE applies the GTE predicate on the "topup_bonus_max" field.
+func TopupBonusMaxGTE(v money.Amount) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldGTE(FieldTopupBonusMax, v))
+}
+
+// TopupBonusMaxLT applies the LT predicate on the "topup_bonus_max" field.
+func TopupBonusMaxLT(v money.Amount) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldLT(FieldTopupBonusMax, v))
+}
+
+// TopupBonusMaxLTE applies the LTE predicate on the "topup_bonus_max" field.
+func TopupBonusMaxLTE(v money.Amount) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldLTE(FieldTopupBonusMax, v))
+}
+
+// MinTopupAmountEQ applies the EQ predicate on the "min_topup_amount" field.
+func MinTopupAmountEQ(v money.Amount) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldEQ(FieldMinTopupAmount, v))
+}
+
+// MinTopupAmountNEQ applies the NEQ predicate on the "min_topup_amount" field.
+func MinTopupAmountNEQ(v money.Amount) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldNEQ(FieldMinTopupAmount, v))
+}
+
+// MinTopupAmountIn applies the In predicate on the "min_topup_amount" field.
+func MinTopupAmountIn(vs ...money.Amount) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldIn(FieldMinTopupAmount, vs...))
+}
+
+// MinTopupAmountNotIn applies the NotIn predicate on the "min_topup_amount" field.
+func MinTopupAmountNotIn(vs ...money.Amount) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldNotIn(FieldMinTopupAmount, vs...))
+}
+
+// MinTopupAmountGT applies the GT predicate on the "min_topup_amount" field.
+func MinTopupAmountGT(v money.Amount) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldGT(FieldMinTopupAmount, v))
+}
+
+// MinTopupAmountGTE applies the GTE predicate on the "min_topup_amount" field.
+func MinTopupAmountGTE(v money.Amount) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldGTE(FieldMinTopupAmount, v))
+}
+
+// MinTopupAmountLT applies the LT predicate on the "min_topup_amount" field.
+func MinTopupAmountLT(v money.Amount) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldLT(FieldMinTopupAmount, v))
+}
+
+// MinTopupAmountLTE applies the LTE predicate on the "min_topup_amount" field.
+func MinTopupAmountLTE(v money.Amount) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldLTE(FieldMinTopupAmount, v))
+}
+
+// PerUserLimitEQ applies the EQ predicate on the "per_user_limit" field.
+func PerUserLimitEQ(v int) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldEQ(FieldPerUserLimit, v))
+}
+
+// PerUserLimitNEQ applies the NEQ predicate on the "per_user_limit" field.
+func PerUserLimitNEQ(v int) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldNEQ(FieldPerUserLimit, v))
+}
+
+// PerUserLimitIn applies the In predicate on the "per_user_limit" field.
+func PerUserLimitIn(vs ...int) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldIn(FieldPerUserLimit, vs...))
+}
+
+// PerUserLimitNotIn applies the NotIn predicate on the "per_user_limit" field.
+func PerUserLimitNotIn(vs ...int) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldNotIn(FieldPerUserLimit, vs...))
+}
+
+// PerUserLimitGT applies the GT predicate on the "per_user_limit" field.
+func PerUserLimitGT(v int) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldGT(FieldPerUserLimit, v))
+}
+
+// PerUserLimitGTE applies the GTE predicate on the "per_user_limit" field.
+func PerUserLimitGTE(v int) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldGTE(FieldPerUserLimit, v))
+}
+
+// PerUserLimitLT applies the LT predicate on the "per_user_limit" field.
+func PerUserLimitLT(v int) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldLT(FieldPerUserLimit, v))
+}
+
+// PerUserLimitLTE applies the LTE predicate on the "per_user_limit" field.
+func PerUserLimitLTE(v int) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldLTE(FieldPerUserLimit, v))
+}
+
+// AllowedEmailDomainsIsNil applies the IsNil predicate on the "allowed_email_domains" field.
+func AllowedEmailDomainsIsNil() predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldIsNull(FieldAllowedEmailDomains))
+}
+
+// AllowedEmailDomainsNotNil applies the NotNil predicate on the "allowed_email_domains" field.
+func AllowedEmailDomainsNotNil() predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldNotNull(FieldAllowedEmailDomains))
+}
+
+// RequiredAttributesIsNil applies the IsNil predicate on the "required_attributes" field.
+func RequiredAttributesIsNil() predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldIsNull(FieldRequiredAttributes))
+}
+
+// RequiredAttributesNotNil applies the NotNil predicate on the "required_attributes" field.
+func RequiredAttributesNotNil() predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldNotNull(FieldRequiredAttributes))
+}
+
+// StackableEQ applies the EQ predicate on the "stackable" field.
+func StackableEQ(v bool) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldEQ(FieldStackable, v))
+}
+
+// StackableNEQ applies the NEQ predicate on the "stackable" field.
+func StackableNEQ(v bool) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldNEQ(FieldStackable, v))
+}
+
+// TrialGroupIDEQ applies the EQ predicate on the "trial_group_id" field.
+func TrialGroupIDEQ(v int64) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldEQ(FieldTrialGroupID, v))
+}
+
+// TrialGroupIDNEQ applies the NEQ predicate on the "trial_group_id" field.
+func TrialGroupIDNEQ(v int64) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldNEQ(FieldTrialGroupID, v))
+}
+
+// TrialGroupIDIn applies the In predicate on the "trial_group_id" field.
+func TrialGroupIDIn(vs ...int64) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldIn(FieldTrialGroupID, vs...))
+}
+
+// TrialGroupIDNotIn applies the NotIn predicate on the "trial_group_id" field.
+func TrialGroupIDNotIn(vs ...int64) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldNotIn(FieldTrialGroupID, vs...))
+}
+
+// TrialGroupIDGT applies the GT predicate on the "trial_group_id" field.
+func TrialGroupIDGT(v int64) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldGT(FieldTrialGroupID, v))
+}
+
+// TrialGroupIDGTE applies the GTE predicate on the "trial_group_id" field.
+func TrialGroupIDGTE(v int64) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldGTE(FieldTrialGroupID, v))
+}
+
+// TrialGroupIDLT applies the LT predicate on the "trial_group_id" field.
+func TrialGroupIDLT(v int64) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldLT(FieldTrialGroupID, v))
+}
+
+// TrialGroupIDLTE applies the LTE predicate on the "trial_group_id" field.
+func TrialGroupIDLTE(v int64) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldLTE(FieldTrialGroupID, v))
+}
+
+// TrialGroupIDIsNil applies the IsNil predicate on the "trial_group_id" field.
+func TrialGroupIDIsNil() predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldIsNull(FieldTrialGroupID))
+}
+
+// TrialGroupIDNotNil applies the NotNil predicate on the "trial_group_id" field.
+func TrialGroupIDNotNil() predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldNotNull(FieldTrialGroupID))
+}
+
+// TrialDaysEQ applies the EQ predicate on the "trial_days" field.
+func TrialDaysEQ(v int) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldEQ(FieldTrialDays, v))
+}
+
+// TrialDaysNEQ applies the NEQ predicate on the "trial_days" field.
+func TrialDaysNEQ(v int) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldNEQ(FieldTrialDays, v))
+}
+
+// TrialDaysIn applies the In predicate on the "trial_days" field.
+func TrialDaysIn(vs ...int) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldIn(FieldTrialDays, vs...))
+}
+
+// TrialDaysNotIn applies the NotIn predicate on the "trial_days" field.
+func TrialDaysNotIn(vs ...int) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldNotIn(FieldTrialDays, vs...))
+}
+
+// TrialDaysGT applies the GT predicate on the "trial_days" field.
+func TrialDaysGT(v int) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldGT(FieldTrialDays, v))
+}
+
+// TrialDaysGTE applies the GTE predicate on the "trial_days" field.
+func TrialDaysGTE(v int) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldGTE(FieldTrialDays, v))
+}
+
+// TrialDaysLT applies the LT predicate on the "trial_days" field.
+func TrialDaysLT(v int) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldLT(FieldTrialDays, v))
+}
+
+// TrialDaysLTE applies the LTE predicate on the "trial_days" field.
+func TrialDaysLTE(v int) predicate.PromoCode {
+	return predicate.PromoCode(sql.FieldLTE(FieldTrialDays, v))
+}
+
 // StatusEQ applies the EQ predicate on the "status" field.
 func StatusEQ(v string) predicate.PromoCode {
 	return predicate.PromoCode(sql.FieldEQ(FieldStatus, v))
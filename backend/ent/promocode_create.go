@@ -13,6 +13,7 @@ import (
 	"entgo.io/ent/schema/field"
 	"github.com/Wei-Shaw/sub2api/ent/promocode"
 	"github.com/Wei-Shaw/sub2api/ent/promocodeusage"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
 )
 
 // PromoCodeCreate is the builder for creating a PromoCode entity.
@@ -30,13 +31,13 @@ func (_c *PromoCodeCreate) SetCode(v string) *PromoCodeCreate {
 }
 
 // SetBonusAmount sets the "bonus_amount" field.
-func (_c *PromoCodeCreate) SetBonusAmount(v float64) *PromoCodeCreate {
+func (_c *PromoCodeCreate) SetBonusAmount(v money.Amount) *PromoCodeCreate {
 	_c.mutation.SetBonusAmount(v)
 	return _c
 }
 
 // SetNillableBonusAmount sets the "bonus_amount" field if the given value is not nil.
-func (_c *PromoCodeCreate) SetNillableBonusAmount(v *float64) *PromoCodeCreate {
+func (_c *PromoCodeCreate) SetNillableBonusAmount(v *money.Amount) *PromoCodeCreate {
 	if v != nil {
 		_c.SetBonusAmount(*v)
 	}
@@ -71,6 +72,116 @@ func (_c *PromoCodeCreate) SetNillableUsedCount(v *int) *PromoCodeCreate {
 	return _c
 }
 
+// SetTopupBonusPercent sets the "topup_bonus_percent" field.
+func (_c *PromoCodeCreate) SetTopupBonusPercent(v float64) *PromoCodeCreate {
+	_c.mutation.SetTopupBonusPercent(v)
+	return _c
+}
+
+// SetNillableTopupBonusPercent sets the "topup_bonus_percent" field if the given value is not nil.
+func (_c *PromoCodeCreate) SetNillableTopupBonusPercent(v *float64) *PromoCodeCreate {
+	if v != nil {
+		_c.SetTopupBonusPercent(*v)
+	}
+	return _c
+}
+
+// SetTopupBonusMax sets the "topup_bonus_max" field.
+func (_c *PromoCodeCreate) SetTopupBonusMax(v money.Amount) *PromoCodeCreate {
+	_c.mutation.SetTopupBonusMax(v)
+	return _c
+}
+
+// SetNillableTopupBonusMax sets the "topup_bonus_max" field if the given value is not nil.
+func (_c *PromoCodeCreate) SetNillableTopupBonusMax(v *money.Amount) *PromoCodeCreate {
+	if v != nil {
+		_c.SetTopupBonusMax(*v)
+	}
+	return _c
+}
+
+// SetMinTopupAmount sets the "min_topup_amount" field.
+func (_c *PromoCodeCreate) SetMinTopupAmount(v money.Amount) *PromoCodeCreate {
+	_c.mutation.SetMinTopupAmount(v)
+	return _c
+}
+
+// SetNillableMinTopupAmount sets the "min_topup_amount" field if the given value is not nil.
+func (_c *PromoCodeCreate) SetNillableMinTopupAmount(v *money.Amount) *PromoCodeCreate {
+	if v != nil {
+		_c.SetMinTopupAmount(*v)
+	}
+	return _c
+}
+
+// SetPerUserLimit sets the "per_user_limit" field.
+func (_c *PromoCodeCreate) SetPerUserLimit(v int) *PromoCodeCreate {
+	_c.mutation.SetPerUserLimit(v)
+	return _c
+}
+
+// SetNillablePerUserLimit sets the "per_user_limit" field if the given value is not nil.
+func (_c *PromoCodeCreate) SetNillablePerUserLimit(v *int) *PromoCodeCreate {
+	if v != nil {
+		_c.SetPerUserLimit(*v)
+	}
+	return _c
+}
+
+// SetAllowedEmailDomains sets the "allowed_email_domains" field.
+func (_c *PromoCodeCreate) SetAllowedEmailDomains(v []string) *PromoCodeCreate {
+	_c.mutation.SetAllowedEmailDomains(v)
+	return _c
+}
+
+// SetRequiredAttributes sets the "required_attributes" field.
+func (_c *PromoCodeCreate) SetRequiredAttributes(v map[string]string) *PromoCodeCreate {
+	_c.mutation.SetRequiredAttributes(v)
+	return _c
+}
+
+// SetStackable sets the "stackable" field.
+func (_c *PromoCodeCreate) SetStackable(v bool) *PromoCodeCreate {
+	_c.mutation.SetStackable(v)
+	return _c
+}
+
+// SetNillableStackable sets the "stackable" field if the given value is not nil.
+func (_c *PromoCodeCreate) SetNillableStackable(v *bool) *PromoCodeCreate {
+	if v != nil {
+		_c.SetStackable(*v)
+	}
+	return _c
+}
+
+// SetTrialGroupID sets the "trial_group_id" field.
+func (_c *PromoCodeCreate) SetTrialGroupID(v int64) *PromoCodeCreate {
+	_c.mutation.SetTrialGroupID(v)
+	return _c
+}
+
+// SetNillableTrialGroupID sets the "trial_group_id" field if the given value is not nil.
+func (_c *PromoCodeCreate) SetNillableTrialGroupID(v *int64) *PromoCodeCreate {
+	if v != nil {
+		_c.SetTrialGroupID(*v)
+	}
+	return _c
+}
+
+// SetTrialDays sets the "trial_days" field.
+func (_c *PromoCodeCreate) SetTrialDays(v int) *PromoCodeCreate {
+	_c.mutation.SetTrialDays(v)
+	return _c
+}
+
+// SetNillableTrialDays sets the "trial_days" field if the given value is not nil.
+func (_c *PromoCodeCreate) SetNillableTrialDays(v *int) *PromoCodeCreate {
+	if v != nil {
+		_c.SetTrialDays(*v)
+	}
+	return _c
+}
+
 // SetStatus sets the "status" field.
 func (_c *PromoCodeCreate) SetStatus(v string) *PromoCodeCreate {
 	_c.mutation.SetStatus(v)
@@ -203,6 +314,30 @@ func (_c *PromoCodeCreate) defaults() {
 		v := promocode.DefaultUsedCount
 		_c.mutation.SetUsedCount(v)
 	}
+	if _, ok := _c.mutation.TopupBonusPercent(); !ok {
+		v := promocode.DefaultTopupBonusPercent
+		_c.mutation.SetTopupBonusPercent(v)
+	}
+	if _, ok := _c.mutation.TopupBonusMax(); !ok {
+		v := promocode.DefaultTopupBonusMax
+		_c.mutation.SetTopupBonusMax(v)
+	}
+	if _, ok := _c.mutation.MinTopupAmount(); !ok {
+		v := promocode.DefaultMinTopupAmount
+		_c.mutation.SetMinTopupAmount(v)
+	}
+	if _, ok := _c.mutation.PerUserLimit(); !ok {
+		v := promocode.DefaultPerUserLimit
+		_c.mutation.SetPerUserLimit(v)
+	}
+	if _, ok := _c.mutation.Stackable(); !ok {
+		v := promocode.DefaultStackable
+		_c.mutation.SetStackable(v)
+	}
+	if _, ok := _c.mutation.TrialDays(); !ok {
+		v := promocode.DefaultTrialDays
+		_c.mutation.SetTrialDays(v)
+	}
 	if _, ok := _c.mutation.Status(); !ok {
 		v := promocode.DefaultStatus
 		_c.mutation.SetStatus(v)
@@ -236,6 +371,24 @@ func (_c *PromoCodeCreate) check() error {
 	if _, ok := _c.mutation.UsedCount(); !ok {
 		return &ValidationError{Name: "used_count", err: errors.New(`ent: missing required field "PromoCode.used_count"`)}
 	}
+	if _, ok := _c.mutation.TopupBonusPercent(); !ok {
+		return &ValidationError{Name: "topup_bonus_percent", err: errors.New(`ent: missing required field "PromoCode.topup_bonus_percent"`)}
+	}
+	if _, ok := _c.mutation.TopupBonusMax(); !ok {
+		return &ValidationError{Name: "topup_bonus_max", err: errors.New(`ent: missing required field "PromoCode.topup_bonus_max"`)}
+	}
+	if _, ok := _c.mutation.MinTopupAmount(); !ok {
+		return &ValidationError{Name: "min_topup_amount", err: errors.New(`ent: missing required field "PromoCode.min_topup_amount"`)}
+	}
+	if _, ok := _c.mutation.PerUserLimit(); !ok {
+		return &ValidationError{Name: "per_user_limit", err: errors.New(`ent: missing required field "PromoCode.per_user_limit"`)}
+	}
+	if _, ok := _c.mutation.Stackable(); !ok {
+		return &ValidationError{Name: "stackable", err: errors.New(`ent: missing required field "PromoCode.stackable"`)}
+	}
+	if _, ok := _c.mutation.TrialDays(); !ok {
+		return &ValidationError{Name: "trial_days", err: errors.New(`ent: missing required field "PromoCode.trial_days"`)}
+	}
 	if _, ok := _c.mutation.Status(); !ok {
 		return &ValidationError{Name: "status", err: errors.New(`ent: missing required field "PromoCode.status"`)}
 	}
@@ -282,7 +435,7 @@ func (_c *PromoCodeCreate) createSpec() (*PromoCode, *sqlgraph.CreateSpec) {
 		_node.Code = value
 	}
 	if value, ok := _c.mutation.BonusAmount(); ok {
-		_spec.SetField(promocode.FieldBonusAmount, field.TypeFloat64, value)
+		_spec.SetField(promocode.FieldBonusAmount, field.TypeInt64, value)
 		_node.BonusAmount = value
 	}
 	if value, ok := _c.mutation.MaxUses(); ok {
@@ -293,6 +446,42 @@ func (_c *PromoCodeCreate) createSpec() (*PromoCode, *sqlgraph.CreateSpec) {
 		_spec.SetField(promocode.FieldUsedCount, field.TypeInt, value)
 		_node.UsedCount = value
 	}
+	if value, ok := _c.mutation.TopupBonusPercent(); ok {
+		_spec.SetField(promocode.FieldTopupBonusPercent, field.TypeFloat64, value)
+		_node.TopupBonusPercent = value
+	}
+	if value, ok := _c.mutation.TopupBonusMax(); ok {
+		_spec.SetField(promocode.FieldTopupBonusMax, field.TypeInt64, value)
+		_node.TopupBonusMax = value
+	}
+	if value, ok := _c.mutation.MinTopupAmount(); ok {
+		_spec.SetField(promocode.FieldMinTopupAmount, field.TypeInt64, value)
+		_node.MinTopupAmount = value
+	}
+	if value, ok := _c.mutation.PerUserLimit(); ok {
+		_spec.SetField(promocode.FieldPerUserLimit, field.TypeInt, value)
+		_node.PerUserLimit = value
+	}
+	if value, ok := _c.mutation.AllowedEmailDomains(); ok {
+		_spec.SetField(promocode.FieldAllowedEmailDomains, field.TypeJSON, value)
+		_node.AllowedEmailDomains = value
+	}
+	if value, ok := _c.mutation.RequiredAttributes(); ok {
+		_spec.SetField(promocode.FieldRequiredAttributes, field.TypeJSON, value)
+		_node.RequiredAttributes = value
+	}
+	if value, ok := _c.mutation.Stackable(); ok {
+		_spec.SetField(promocode.FieldStackable, field.TypeBool, value)
+		_node.Stackable = value
+	}
+	if value, ok := _c.mutation.TrialGroupID(); ok {
+		_spec.SetField(promocode.FieldTrialGroupID, field.TypeInt64, value)
+		_node.TrialGroupID = &value
+	}
+	if value, ok := _c.mutation.TrialDays(); ok {
+		_spec.SetField(promocode.FieldTrialDays, field.TypeInt, value)
+		_node.TrialDays = value
+	}
 	if value, ok := _c.mutation.Status(); ok {
 		_spec.SetField(promocode.FieldStatus, field.TypeString, value)
 		_node.Status = value
@@ -394,7 +583,7 @@ func (u *PromoCodeUpsert) UpdateCode() *PromoCodeUpsert {
 }
 
 // SetBonusAmount sets the "bonus_amount" field.
-func (u *PromoCodeUpsert) SetBonusAmount(v float64) *PromoCodeUpsert {
+func (u *PromoCodeUpsert) SetBonusAmount(v money.Amount) *PromoCodeUpsert {
 	u.Set(promocode.FieldBonusAmount, v)
 	return u
 }
@@ -406,7 +595,7 @@ func (u *PromoCodeUpsert) UpdateBonusAmount() *PromoCodeUpsert {
 }
 
 // AddBonusAmount adds v to the "bonus_amount" field.
-func (u *PromoCodeUpsert) AddBonusAmount(v float64) *PromoCodeUpsert {
+func (u *PromoCodeUpsert) AddBonusAmount(v money.Amount) *PromoCodeUpsert {
 	u.Add(promocode.FieldBonusAmount, v)
 	return u
 }
@@ -447,6 +636,168 @@ func (u *PromoCodeUpsert) AddUsedCount(v int) *PromoCodeUpsert {
 	return u
 }
 
+// SetTopupBonusPercent sets the "topup_bonus_percent" field.
+func (u *PromoCodeUpsert) SetTopupBonusPercent(v float64) *PromoCodeUpsert {
+	u.Set(promocode.FieldTopupBonusPercent, v)
+	return u
+}
+
+// UpdateTopupBonusPercent sets the "topup_bonus_percent" field to the value that was provided on create.
+func (u *PromoCodeUpsert) UpdateTopupBonusPercent() *PromoCodeUpsert {
+	u.SetExcluded(promocode.FieldTopupBonusPercent)
+	return u
+}
+
+// AddTopupBonusPercent adds v to the "topup_bonus_percent" field.
+func (u *PromoCodeUpsert) AddTopupBonusPercent(v float64) *PromoCodeUpsert {
+	u.Add(promocode.FieldTopupBonusPercent, v)
+	return u
+}
+
+// SetTopupBonusMax sets the "topup_bonus_max" field.
+func (u *PromoCodeUpsert) SetTopupBonusMax(v money.Amount) *PromoCodeUpsert {
+	u.Set(promocode.FieldTopupBonusMax, v)
+	return u
+}
+
+// UpdateTopupBonusMax sets the "topup_bonus_max" field to the value that was provided on create.
+func (u *PromoCodeUpsert) UpdateTopupBonusMax() *PromoCodeUpsert {
+	u.SetExcluded(promocode.FieldTopupBonusMax)
+	return u
+}
+
+// AddTopupBonusMax adds v to the "topup_bonus_max" field.
+func (u *PromoCodeUpsert) AddTopupBonusMax(v money.Amount) *PromoCodeUpsert {
+	u.Add(promocode.FieldTopupBonusMax, v)
+	return u
+}
+
+// SetMinTopupAmount sets the "min_topup_amount" field.
+func (u *PromoCodeUpsert) SetMinTopupAmount(v money.Amount) *PromoCodeUpsert {
+	u.Set(promocode.FieldMinTopupAmount, v)
+	return u
+}
+
+// UpdateMinTopupAmount sets the "min_topup_amount" field to the value that was provided on create.
+func (u *PromoCodeUpsert) UpdateMinTopupAmount() *PromoCodeUpsert {
+	u.SetExcluded(promocode.FieldMinTopupAmount)
+	return u
+}
+
+// AddMinTopupAmount adds v to the "min_topup_amount" field.
+func (u *PromoCodeUpsert) AddMinTopupAmount(v money.Amount) *PromoCodeUpsert {
+	u.Add(promocode.FieldMinTopupAmount, v)
+	return u
+}
+
+// SetPerUserLimit sets the "per_user_limit" field.
+func (u *PromoCodeUpsert) SetPerUserLimit(v int) *PromoCodeUpsert {
+	u.Set(promocode.FieldPerUserLimit, v)
+	return u
+}
+
+// UpdatePerUserLimit sets the "per_user_limit" field to the value that was provided on create.
+func (u *PromoCodeUpsert) UpdatePerUserLimit() *PromoCodeUpsert {
+	u.SetExcluded(promocode.FieldPerUserLimit)
+	return u
+}
+
+// AddPerUserLimit adds v to the "per_user_limit" field.
+func (u *PromoCodeUpsert) AddPerUserLimit(v int) *PromoCodeUpsert {
+	u.Add(promocode.FieldPerUserLimit, v)
+	return u
+}
+
+// SetAllowedEmailDomains sets the "allowed_email_domains" field.
+func (u *PromoCodeUpsert) SetAllowedEmailDomains(v []string) *PromoCodeUpsert {
+	u.Set(promocode.FieldAllowedEmailDomains, v)
+	return u
+}
+
+// UpdateAllowedEmailDomains sets the "allowed_email_domains" field to the value that was provided on create.
+func (u *PromoCodeUpsert) UpdateAllowedEmailDomains() *PromoCodeUpsert {
+	u.SetExcluded(promocode.FieldAllowedEmailDomains)
+	return u
+}
+
+// ClearAllowedEmailDomains clears the value of the "allowed_email_domains" field.
+func (u *PromoCodeUpsert) ClearAllowedEmailDomains() *PromoCodeUpsert {
+	u.SetNull(promocode.FieldAllowedEmailDomains)
+	return u
+}
+
+// SetRequiredAttributes sets the "required_attributes" field.
+func (u *PromoCodeUpsert) SetRequiredAttributes(v map[string]string) *PromoCodeUpsert {
+	u.Set(promocode.FieldRequiredAttributes, v)
+	return u
+}
+
+// UpdateRequiredAttributes sets the "required_attributes" field to the value that was provided on create.
+func (u *PromoCodeUpsert) UpdateRequiredAttributes() *PromoCodeUpsert {
+	u.SetExcluded(promocode.FieldRequiredAttributes)
+	return u
+}
+
+// ClearRequiredAttributes clears the value of the "required_attributes" field.
+func (u *PromoCodeUpsert) ClearRequiredAttributes() *PromoCodeUpsert {
+	u.SetNull(promocode.FieldRequiredAttributes)
+	return u
+}
+
+// SetStackable sets the "stackable" field.
+func (u *PromoCodeUpsert) SetStackable(v bool) *PromoCodeUpsert {
+	u.Set(promocode.FieldStackable, v)
+	return u
+}
+
+// UpdateStackable sets the "stackable" field to the value that was provided on create.
+func (u *PromoCodeUpsert) UpdateStackable() *PromoCodeUpsert {
+	u.SetExcluded(promocode.FieldStackable)
+	return u
+}
+
+// SetTrialGroupID sets the "trial_group_id" field.
+func (u *PromoCodeUpsert) SetTrialGroupID(v int64) *PromoCodeUpsert {
+	u.Set(promocode.FieldTrialGroupID, v)
+	return u
+}
+
+// UpdateTrialGroupID sets the "trial_group_id" field to the value that was provided on create.
+func (u *PromoCodeUpsert) UpdateTrialGroupID() *PromoCodeUpsert {
+	u.SetExcluded(promocode.FieldTrialGroupID)
+	return u
+}
+
+// AddTrialGroupID adds v to the "trial_group_id" field.
+func (u *PromoCodeUpsert) AddTrialGroupID(v int64) *PromoCodeUpsert {
+	u.Add(promocode.FieldTrialGroupID, v)
+	return u
+}
+
+// ClearTrialGroupID clears the value of the "trial_group_id" field.
+func (u *PromoCodeUpsert) ClearTrialGroupID() *PromoCodeUpsert {
+	u.SetNull(promocode.FieldTrialGroupID)
+	return u
+}
+
+// SetTrialDays sets the "trial_days" field.
+func (u *PromoCodeUpsert) SetTrialDays(v int) *PromoCodeUpsert {
+	u.Set(promocode.FieldTrialDays, v)
+	return u
+}
+
+// UpdateTrialDays sets the "trial_days" field to the value that was provided on create.
+func (u *PromoCodeUpsert) UpdateTrialDays() *PromoCodeUpsert {
+	u.SetExcluded(promocode.FieldTrialDays)
+	return u
+}
+
+// AddTrialDays adds v to the "trial_days" field.
+func (u *PromoCodeUpsert) AddTrialDays(v int) *PromoCodeUpsert {
+	u.Add(promocode.FieldTrialDays, v)
+	return u
+}
+
 // SetStatus sets the "status" field.
 func (u *PromoCodeUpsert) SetStatus(v string) *PromoCodeUpsert {
 	u.Set(promocode.FieldStatus, v)
@@ -567,14 +918,14 @@ func (u *PromoCodeUpsertOne) UpdateCode() *PromoCodeUpsertOne {
 }
 
 // SetBonusAmount sets the "bonus_amount" field.
-func (u *PromoCodeUpsertOne) SetBonusAmount(v float64) *PromoCodeUpsertOne {
+func (u *PromoCodeUpsertOne) SetBonusAmount(v money.Amount) *PromoCodeUpsertOne {
 	return u.Update(func(s *PromoCodeUpsert) {
 		s.SetBonusAmount(v)
 	})
 }
 
 // AddBonusAmount adds v to the "bonus_amount" field.
-func (u *PromoCodeUpsertOne) AddBonusAmount(v float64) *PromoCodeUpsertOne {
+func (u *PromoCodeUpsertOne) AddBonusAmount(v money.Amount) *PromoCodeUpsertOne {
 	return u.Update(func(s *PromoCodeUpsert) {
 		s.AddBonusAmount(v)
 	})
@@ -629,6 +980,195 @@ func (u *PromoCodeUpsertOne) UpdateUsedCount() *PromoCodeUpsertOne {
 	})
 }
 
+// SetTopupBonusPercent sets the "topup_bonus_percent" field.
+func (u *PromoCodeUpsertOne) SetTopupBonusPercent(v float64) *PromoCodeUpsertOne {
+	return u.Update(func(s *PromoCodeUpsert) {
+		s.SetTopupBonusPercent(v)
+	})
+}
+
+// AddTopupBonusPercent adds v to the "topup_bonus_percent" field.
+func (u *PromoCodeUpsertOne) AddTopupBonusPercent(v float64) *PromoCodeUpsertOne {
+	return u.Update(func(s *PromoCodeUpsert) {
+		s.AddTopupBonusPercent(v)
+	})
+}
+
+// UpdateTopupBonusPercent sets the "topup_bonus_percent" field to the value that was provided on create.
+func (u *PromoCodeUpsertOne) UpdateTopupBonusPercent() *PromoCodeUpsertOne {
+	return u.Update(func(s *PromoCodeUpsert) {
+		s.UpdateTopupBonusPercent()
+	})
+}
+
+// SetTopupBonusMax sets the "topup_bonus_max" field.
+func (u *PromoCodeUpsertOne) SetTopupBonusMax(v money.Amount) *PromoCodeUpsertOne {
+	return u.Update(func(s *PromoCodeUpsert) {
+		s.SetTopupBonusMax(v)
+	})
+}
+
+// AddTopupBonusMax adds v to the "topup_bonus_max" field.
+func (u *PromoCodeUpsertOne) AddTopupBonusMax(v money.Amount) *PromoCodeUpsertOne {
+	return u.Update(func(s *PromoCodeUpsert) {
+		s.AddTopupBonusMax(v)
+	})
+}
+
+// UpdateTopupBonusMax sets the "topup_bonus_max" field to the value that was provided on create.
+func (u *PromoCodeUpsertOne) UpdateTopupBonusMax() *PromoCodeUpsertOne {
+	return u.Update(func(s *PromoCodeUpsert) {
+		s.UpdateTopupBonusMax()
+	})
+}
+
+// SetMinTopupAmount sets the "min_topup_amount" field.
+func (u *PromoCodeUpsertOne) SetMinTopupAmount(v money.Amount) *PromoCodeUpsertOne {
+	return u.Update(func(s *PromoCodeUpsert) {
+		s.SetMinTopupAmount(v)
+	})
+}
+
+// AddMinTopupAmount adds v to the "min_topup_amount" field.
+func (u *PromoCodeUpsertOne) AddMinTopupAmount(v money.Amount) *PromoCodeUpsertOne {
+	return u.Update(func(s *PromoCodeUpsert) {
+		s.AddMinTopupAmount(v)
+	})
+}
+
+// UpdateMinTopupAmount sets the "min_topup_amount" field to the value that was provided on create.
+func (u *PromoCodeUpsertOne) UpdateMinTopupAmount() *PromoCodeUpsertOne {
+	return u.Update(func(s *PromoCodeUpsert) {
+		s.UpdateMinTopupAmount()
+	})
+}
+
+// SetPerUserLimit sets the "per_user_limit" field.
+func (u *PromoCodeUpsertOne) SetPerUserLimit(v int) *PromoCodeUpsertOne {
+	return u.Update(func(s *PromoCodeUpsert) {
+		s.SetPerUserLimit(v)
+	})
+}
+
+// AddPerUserLimit adds v to the "per_user_limit" field.
+func (u *PromoCodeUpsertOne) AddPerUserLimit(v int) *PromoCodeUpsertOne {
+	return u.Update(func(s *PromoCodeUpsert) {
+		s.AddPerUserLimit(v)
+	})
+}
+
+// UpdatePerUserLimit sets the "per_user_limit" field to the value that was provided on create.
+func (u *PromoCodeUpsertOne) UpdatePerUserLimit() *PromoCodeUpsertOne {
+	return u.Update(func(s *PromoCodeUpsert) {
+		s.UpdatePerUserLimit()
+	})
+}
+
+// SetAllowedEmailDomains sets the "allowed_email_domains" field.
+func (u *PromoCodeUpsertOne) SetAllowedEmailDomains(v []string) *PromoCodeUpsertOne {
+	return u.Update(func(s *PromoCodeUpsert) {
+		s.SetAllowedEmailDomains(v)
+	})
+}
+
+// UpdateAllowedEmailDomains sets the "allowed_email_domains" field to the value that was provided on create.
+func (u *PromoCodeUpsertOne) UpdateAllowedEmailDomains() *PromoCodeUpsertOne {
+	return u.Update(func(s *PromoCodeUpsert) {
+		s.UpdateAllowedEmailDomains()
+	})
+}
+
+// ClearAllowedEmailDomains clears the value of the "allowed_email_domains" field.
+func (u *PromoCodeUpsertOne) ClearAllowedEmailDomains() *PromoCodeUpsertOne {
+	return u.Update(func(s *PromoCodeUpsert) {
+		s.ClearAllowedEmailDomains()
+	})
+}
+
+// SetRequiredAttributes sets the "required_attributes" field.
+func (u *PromoCodeUpsertOne) SetRequiredAttributes(v map[string]string) *PromoCodeUpsertOne {
+	return u.Update(func(s *PromoCodeUpsert) {
+		s.SetRequiredAttributes(v)
+	})
+}
+
+// UpdateRequiredAttributes sets the "required_attributes" field to the value that was provided on create.
+func (u *PromoCodeUpsertOne) UpdateRequiredAttributes() *PromoCodeUpsertOne {
+	return u.Update(func(s *PromoCodeUpsert) {
+		s.UpdateRequiredAttributes()
+	})
+}
+
+// ClearRequiredAttributes clears the value of the "required_attributes" field.
+func (u *PromoCodeUpsertOne) ClearRequiredAttributes() *PromoCodeUpsertOne {
+	return u.Update(func(s *PromoCodeUpsert) {
+		s.ClearRequiredAttributes()
+	})
+}
+
+// SetStackable sets the "stackable" field.
+func (u *PromoCodeUpsertOne) SetStackable(v bool) *PromoCodeUpsertOne {
+	return u.Update(func(s *PromoCodeUpsert) {
+		s.SetStackable(v)
+	})
+}
+
+// UpdateStackable sets the "stackable" field to the value that was provided on create.
+func (u *PromoCodeUpsertOne) UpdateStackable() *PromoCodeUpsertOne {
+	return u.Update(func(s *PromoCodeUpsert) {
+		s.UpdateStackable()
+	})
+}
+
+// SetTrialGroupID sets the "trial_group_id" field.
+func (u *PromoCodeUpsertOne) SetTrialGroupID(v int64) *PromoCodeUpsertOne {
+	return u.Update(func(s *PromoCodeUpsert) {
+		s.SetTrialGroupID(v)
+	})
+}
+
+// AddTrialGroupID adds v to the "trial_group_id" field.
+func (u *PromoCodeUpsertOne) AddTrialGroupID(v int64) *PromoCodeUpsertOne {
+	return u.Update(func(s *PromoCodeUpsert) {
+		s.AddTrialGroupID(v)
+	})
+}
+
+// UpdateTrialGroupID sets the "trial_group_id" field to the value that was provided on create.
+func (u *PromoCodeUpsertOne) UpdateTrialGroupID() *PromoCodeUpsertOne {
+	return u.Update(func(s *PromoCodeUpsert) {
+		s.UpdateTrialGroupID()
+	})
+}
+
+// ClearTrialGroupID clears the value of the "trial_group_id" field.
+func (u *PromoCodeUpsertOne) ClearTrialGroupID() *PromoCodeUpsertOne {
+	return u.Update(func(s *PromoCodeUpsert) {
+		s.ClearTrialGroupID()
+	})
+}
+
+// SetTrialDays sets the "trial_days" field.
+func (u *PromoCodeUpsertOne) SetTrialDays(v int) *PromoCodeUpsertOne {
+	return u.Update(func(s *PromoCodeUpsert) {
+		s.SetTrialDays(v)
+	})
+}
+
+// AddTrialDays adds v to the "trial_days" field.
+func (u *PromoCodeUpsertOne) AddTrialDays(v int) *PromoCodeUpsertOne {
+	return u.Update(func(s *PromoCodeUpsert) {
+		s.AddTrialDays(v)
+	})
+}
+
+// UpdateTrialDays sets the "trial_days" field to the value that was provided on create.
+func (u *PromoCodeUpsertOne) UpdateTrialDays() *PromoCodeUpsertOne {
+	return u.Update(func(s *PromoCodeUpsert) {
+		s.UpdateTrialDays()
+	})
+}
+
 // SetStatus sets the "status" field.
 func (u *PromoCodeUpsertOne) SetStatus(v string) *PromoCodeUpsertOne {
 	return u.Update(func(s *PromoCodeUpsert) {
@@ -925,14 +1465,14 @@ func (u *PromoCodeUpsertBulk) UpdateCode() *PromoCodeUpsertBulk {
 }
 
 // SetBonusAmount sets the "bonus_amount" field.
-func (u *PromoCodeUpsertBulk) SetBonusAmount(v float64) *PromoCodeUpsertBulk {
+func (u *PromoCodeUpsertBulk) SetBonusAmount(v money.Amount) *PromoCodeUpsertBulk {
 	return u.Update(func(s *PromoCodeUpsert) {
 		s.SetBonusAmount(v)
 	})
 }
 
 // AddBonusAmount adds v to the "bonus_amount" field.
-func (u *PromoCodeUpsertBulk) AddBonusAmount(v float64) *PromoCodeUpsertBulk {
+func (u *PromoCodeUpsertBulk) AddBonusAmount(v money.Amount) *PromoCodeUpsertBulk {
 	return u.Update(func(s *PromoCodeUpsert) {
 		s.AddBonusAmount(v)
 	})
@@ -987,6 +1527,195 @@ func (u *PromoCodeUpsertBulk) UpdateUsedCount() *PromoCodeUpsertBulk {
 	})
 }
 
+// SetTopupBonusPercent sets the "topup_bonus_percent" field.
+func (u *PromoCodeUpsertBulk) SetTopupBonusPercent(v float64) *PromoCodeUpsertBulk {
+	return u.Update(func(s *PromoCodeUpsert) {
+		s.SetTopupBonusPercent(v)
+	})
+}
+
+// AddTopupBonusPercent adds v to the "topup_bonus_percent" field.
+func (u *PromoCodeUpsertBulk) AddTopupBonusPercent(v float64) *PromoCodeUpsertBulk {
+	return u.Update(func(s *PromoCodeUpsert) {
+		s.AddTopupBonusPercent(v)
+	})
+}
+
+// UpdateTopupBonusPercent sets the "topup_bonus_percent" field to the value that was provided on create.
+func (u *PromoCodeUpsertBulk) UpdateTopupBonusPercent() *PromoCodeUpsertBulk {
+	return u.Update(func(s *PromoCodeUpsert) {
+		s.UpdateTopupBonusPercent()
+	})
+}
+
+// SetTopupBonusMax sets the "topup_bonus_max" field.
+func (u *PromoCodeUpsertBulk) SetTopupBonusMax(v money.Amount) *PromoCodeUpsertBulk {
+	return u.Update(func(s *PromoCodeUpsert) {
+		s.SetTopupBonusMax(v)
+	})
+}
+
+// AddTopupBonusMax adds v to the "topup_bonus_max" field.
+func (u *PromoCodeUpsertBulk) AddTopupBonusMax(v money.Amount) *PromoCodeUpsertBulk {
+	return u.Update(func(s *PromoCodeUpsert) {
+		s.AddTopupBonusMax(v)
+	})
+}
+
+// UpdateTopupBonusMax sets the "topup_bonus_max" field to the value that was provided on create.
+func (u *PromoCodeUpsertBulk) UpdateTopupBonusMax() *PromoCodeUpsertBulk {
+	return u.Update(func(s *PromoCodeUpsert) {
+		s.UpdateTopupBonusMax()
+	})
+}
+
+// SetMinTopupAmount sets the "min_topup_amount" field.
+func (u *PromoCodeUpsertBulk) SetMinTopupAmount(v money.Amount) *PromoCodeUpsertBulk {
+	return u.Update(func(s *PromoCodeUpsert) {
+		s.SetMinTopupAmount(v)
+	})
+}
+
+// AddMinTopupAmount adds v to the "min_topup_amount" field.
+func (u *PromoCodeUpsertBulk) AddMinTopupAmount(v money.Amount) *PromoCodeUpsertBulk {
+	return u.Update(func(s *PromoCodeUpsert) {
+		s.AddMinTopupAmount(v)
+	})
+}
+
+// UpdateMinTopupAmount sets the "min_topup_amount" field to the value that was provided on create.
+func (u *PromoCodeUpsertBulk) UpdateMinTopupAmount() *PromoCodeUpsertBulk {
+	return u.Update(func(s *PromoCodeUpsert) {
+		s.UpdateMinTopupAmount()
+	})
+}
+
+// SetPerUserLimit sets the "per_user_limit" field.
+func (u *PromoCodeUpsertBulk) SetPerUserLimit(v int) *PromoCodeUpsertBulk {
+	return u.Update(func(s *PromoCodeUpsert) {
+		s.SetPerUserLimit(v)
+	})
+}
+
+// AddPerUserLimit adds v to the "per_user_limit" field.
+func (u *PromoCodeUpsertBulk) AddPerUserLimit(v int) *PromoCodeUpsertBulk {
+	return u.Update(func(s *PromoCodeUpsert) {
+		s.AddPerUserLimit(v)
+	})
+}
+
+// UpdatePerUserLimit sets the "per_user_limit" field to the value that was provided on create.
+func (u *PromoCodeUpsertBulk) UpdatePerUserLimit() *PromoCodeUpsertBulk {
+	return u.Update(func(s *PromoCodeUpsert) {
+		s.UpdatePerUserLimit()
+	})
+}
+
+// SetAllowedEmailDomains sets the "allowed_email_domains" field.
+func (u *PromoCodeUpsertBulk) SetAllowedEmailDomains(v []string) *PromoCodeUpsertBulk {
+	return u.Update(func(s *PromoCodeUpsert) {
+		s.SetAllowedEmailDomains(v)
+	})
+}
+
+// UpdateAllowedEmailDomains sets the "allowed_email_domains" field to the value that was provided on create.
+func (u *PromoCodeUpsertBulk) UpdateAllowedEmailDomains() *PromoCodeUpsertBulk {
+	return u.Update(func(s *PromoCodeUpsert) {
+		s.UpdateAllowedEmailDomains()
+	})
+}
+
+// ClearAllowedEmailDomains clears the value of the "allowed_email_domains" field.
+func (u *PromoCodeUpsertBulk) ClearAllowedEmailDomains() *PromoCodeUpsertBulk {
+	return u.Update(func(s *PromoCodeUpsert) {
+		s.ClearAllowedEmailDomains()
+	})
+}
+
+// SetRequiredAttributes sets the "required_attributes" field.
+func (u *PromoCodeUpsertBulk) SetRequiredAttributes(v map[string]string) *PromoCodeUpsertBulk {
+	return u.Update(func(s *PromoCodeUpsert) {
+		s.SetRequiredAttributes(v)
+	})
+}
+
+// UpdateRequiredAttributes sets the "required_attributes" field to the value that was provided on create.
+func (u *PromoCodeUpsertBulk) UpdateRequiredAttributes() *PromoCodeUpsertBulk {
+	return u.Update(func(s *PromoCodeUpsert) {
+		s.UpdateRequiredAttributes()
+	})
+}
+
+// ClearRequiredAttributes clears the value of the "required_attributes" field.
+func (u *PromoCodeUpsertBulk) ClearRequiredAttributes() *PromoCodeUpsertBulk {
+	return u.Update(func(s *PromoCodeUpsert) {
+		s.ClearRequiredAttributes()
+	})
+}
+
+// SetStackable sets the "stackable" field.
+func (u *PromoCodeUpsertBulk) SetStackable(v bool) *PromoCodeUpsertBulk {
+	return u.Update(func(s *PromoCodeUpsert) {
+		s.SetStackable(v)
+	})
+}
+
+// UpdateStackable sets the "stackable" field to the value that was provided on create.
+func (u *PromoCodeUpsertBulk) UpdateStackable() *PromoCodeUpsertBulk {
+	return u.Update(func(s *PromoCodeUpsert) {
+		s.UpdateStackable()
+	})
+}
+
+// SetTrialGroupID sets the "trial_group_id" field.
+func (u *PromoCodeUpsertBulk) SetTrialGroupID(v int64) *PromoCodeUpsertBulk {
+	return u.Update(func(s *PromoCodeUpsert) {
+		s.SetTrialGroupID(v)
+	})
+}
+
+// AddTrialGroupID adds v to the "trial_group_id" field.
+func (u *PromoCodeUpsertBulk) AddTrialGroupID(v int64) *PromoCodeUpsertBulk {
+	return u.Update(func(s *PromoCodeUpsert) {
+		s.AddTrialGroupID(v)
+	})
+}
+
+// UpdateTrialGroupID sets the "trial_group_id" field to the value that was provided on create.
+func (u *PromoCodeUpsertBulk) UpdateTrialGroupID() *PromoCodeUpsertBulk {
+	return u.Update(func(s *PromoCodeUpsert) {
+		s.UpdateTrialGroupID()
+	})
+}
+
+// ClearTrialGroupID clears the value of the "trial_group_id" field.
+func (u *PromoCodeUpsertBulk) ClearTrialGroupID() *PromoCodeUpsertBulk {
+	return u.Update(func(s *PromoCodeUpsert) {
+		s.ClearTrialGroupID()
+	})
+}
+
+// SetTrialDays sets the "trial_days" field.
+func (u *PromoCodeUpsertBulk) SetTrialDays(v int) *PromoCodeUpsertBulk {
+	return u.Update(func(s *PromoCodeUpsert) {
+		s.SetTrialDays(v)
+	})
+}
+
+// AddTrialDays adds v to the "trial_days" field.
+func (u *PromoCodeUpsertBulk) AddTrialDays(v int) *PromoCodeUpsertBulk {
+	return u.Update(func(s *PromoCodeUpsert) {
+		s.AddTrialDays(v)
+	})
+}
+
+// UpdateTrialDays sets the "trial_days" field to the value that was provided on create.
+func (u *PromoCodeUpsertBulk) UpdateTrialDays() *PromoCodeUpsertBulk {
+	return u.Update(func(s *PromoCodeUpsert) {
+		s.UpdateTrialDays()
+	})
+}
+
 // SetStatus sets the "status" field.
 func (u *PromoCodeUpsertBulk) SetStatus(v string) *PromoCodeUpsertBulk {
 	return u.Update(func(s *PromoCodeUpsert) {
@@ -10,10 +10,12 @@ import (
 
 	"entgo.io/ent/dialect/sql"
 	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/dialect/sql/sqljson"
 	"entgo.io/ent/schema/field"
 	"github.com/Wei-Shaw/sub2api/ent/predicate"
 	"github.com/Wei-Shaw/sub2api/ent/promocode"
 	"github.com/Wei-Shaw/sub2api/ent/promocodeusage"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
 )
 
 // PromoCodeUpdate is the builder for updating PromoCode entities.
@@ -44,14 +46,14 @@ func (_u *PromoCodeUpdate) SetNillableCode(v *string) *PromoCodeUpdate {
 }
 
 // SetBonusAmount sets the "bonus_amount" field.
-func (_u *PromoCodeUpdate) SetBonusAmount(v float64) *PromoCodeUpdate {
+func (_u *PromoCodeUpdate) SetBonusAmount(v money.Amount) *PromoCodeUpdate {
 	_u.mutation.ResetBonusAmount()
 	_u.mutation.SetBonusAmount(v)
 	return _u
 }
 
 // SetNillableBonusAmount sets the "bonus_amount" field if the given value is not nil.
-func (_u *PromoCodeUpdate) SetNillableBonusAmount(v *float64) *PromoCodeUpdate {
+func (_u *PromoCodeUpdate) SetNillableBonusAmount(v *money.Amount) *PromoCodeUpdate {
 	if v != nil {
 		_u.SetBonusAmount(*v)
 	}
@@ -59,7 +61,7 @@ func (_u *PromoCodeUpdate) SetNillableBonusAmount(v *float64) *PromoCodeUpdate {
 }
 
 // AddBonusAmount adds value to the "bonus_amount" field.
-func (_u *PromoCodeUpdate) AddBonusAmount(v float64) *PromoCodeUpdate {
+func (_u *PromoCodeUpdate) AddBonusAmount(v money.Amount) *PromoCodeUpdate {
 	_u.mutation.AddBonusAmount(v)
 	return _u
 }
@@ -106,6 +108,182 @@ func (_u *PromoCodeUpdate) AddUsedCount(v int) *PromoCodeUpdate {
 	return _u
 }
 
+// SetTopupBonusPercent sets the "topup_bonus_percent" field.
+func (_u *PromoCodeUpdate) SetTopupBonusPercent(v float64) *PromoCodeUpdate {
+	_u.mutation.ResetTopupBonusPercent()
+	_u.mutation.SetTopupBonusPercent(v)
+	return _u
+}
+
+// SetNillableTopupBonusPercent sets the "topup_bonus_percent" field if the given value is not nil.
+func (_u *PromoCodeUpdate) SetNillableTopupBonusPercent(v *float64) *PromoCodeUpdate {
+	if v != nil {
+		_u.SetTopupBonusPercent(*v)
+	}
+	return _u
+}
+
+// AddTopupBonusPercent adds value to the "topup_bonus_percent" field.
+func (_u *PromoCodeUpdate) AddTopupBonusPercent(v float64) *PromoCodeUpdate {
+	_u.mutation.AddTopupBonusPercent(v)
+	return _u
+}
+
+// SetTopupBonusMax sets the "topup_bonus_max" field.
+func (_u *PromoCodeUpdate) SetTopupBonusMax(v money.Amount) *PromoCodeUpdate {
+	_u.mutation.ResetTopupBonusMax()
+	_u.mutation.SetTopupBonusMax(v)
+	return _u
+}
+
+// SetNillableTopupBonusMax sets the "topup_bonus_max" field if the given value is not nil.
+func (_u *PromoCodeUpdate) SetNillableTopupBonusMax(v *money.Amount) *PromoCodeUpdate {
+	if v != nil {
+		_u.SetTopupBonusMax(*v)
+	}
+	return _u
+}
+
+// AddTopupBonusMax adds value to the "topup_bonus_max" field.
+func (_u *PromoCodeUpdate) AddTopupBonusMax(v money.Amount) *PromoCodeUpdate {
+	_u.mutation.AddTopupBonusMax(v)
+	return _u
+}
+
+// SetMinTopupAmount sets the "min_topup_amount" field.
+func (_u *PromoCodeUpdate) SetMinTopupAmount(v money.Amount) *PromoCodeUpdate {
+	_u.mutation.ResetMinTopupAmount()
+	_u.mutation.SetMinTopupAmount(v)
+	return _u
+}
+
+// SetNillableMinTopupAmount sets the "min_topup_amount" field if the given value is not nil.
+func (_u *PromoCodeUpdate) SetNillableMinTopupAmount(v *money.Amount) *PromoCodeUpdate {
+	if v != nil {
+		_u.SetMinTopupAmount(*v)
+	}
+	return _u
+}
+
+// AddMinTopupAmount adds value to the "min_topup_amount" field.
+func (_u *PromoCodeUpdate) AddMinTopupAmount(v money.Amount) *PromoCodeUpdate {
+	_u.mutation.AddMinTopupAmount(v)
+	return _u
+}
+
+// SetPerUserLimit sets the "per_user_limit" field.
+func (_u *PromoCodeUpdate) SetPerUserLimit(v int) *PromoCodeUpdate {
+	_u.mutation.ResetPerUserLimit()
+	_u.mutation.SetPerUserLimit(v)
+	return _u
+}
+
+// SetNillablePerUserLimit sets the "per_user_limit" field if the given value is not nil.
+func (_u *PromoCodeUpdate) SetNillablePerUserLimit(v *int) *PromoCodeUpdate {
+	if v != nil {
+		_u.SetPerUserLimit(*v)
+	}
+	return _u
+}
+
+// AddPerUserLimit adds value to the "per_user_limit" field.
+func (_u *PromoCodeUpdate) AddPerUserLimit(v int) *PromoCodeUpdate {
+	_u.mutation.AddPerUserLimit(v)
+	return _u
+}
+
+// SetAllowedEmailDomains sets the "allowed_email_domains" field.
+func (_u *PromoCodeUpdate) SetAllowedEmailDomains(v []string) *PromoCodeUpdate {
+	_u.mutation.SetAllowedEmailDomains(v)
+	return _u
+}
+
+// AppendAllowedEmailDomains appends value to the "allowed_email_domains" field.
+func (_u *PromoCodeUpdate) AppendAllowedEmailDomains(v []string) *PromoCodeUpdate {
+	_u.mutation.AppendAllowedEmailDomains(v)
+	return _u
+}
+
+// ClearAllowedEmailDomains clears the value of the "allowed_email_domains" field.
+func (_u *PromoCodeUpdate) ClearAllowedEmailDomains() *PromoCodeUpdate {
+	_u.mutation.ClearAllowedEmailDomains()
+	return _u
+}
+
+// SetRequiredAttributes sets the "required_attributes" field.
+func (_u *PromoCodeUpdate) SetRequiredAttributes(v map[string]string) *PromoCodeUpdate {
+	_u.mutation.SetRequiredAttributes(v)
+	return _u
+}
+
+// ClearRequiredAttributes clears the value of the "required_attributes" field.
+func (_u *PromoCodeUpdate) ClearRequiredAttributes() *PromoCodeUpdate {
+	_u.mutation.ClearRequiredAttributes()
+	return _u
+}
+
+// SetStackable sets the "stackable" field.
+func (_u *PromoCodeUpdate) SetStackable(v bool) *PromoCodeUpdate {
+	_u.mutation.SetStackable(v)
+	return _u
+}
+
+// SetNillableStackable sets the "stackable" field if the given value is not nil.
+func (_u *PromoCodeUpdate) SetNillableStackable(v *bool) *PromoCodeUpdate {
+	if v != nil {
+		_u.SetStackable(*v)
+	}
+	return _u
+}
+
+// SetTrialGroupID sets the "trial_group_id" field.
+func (_u *PromoCodeUpdate) SetTrialGroupID(v int64) *PromoCodeUpdate {
+	_u.mutation.ResetTrialGroupID()
+	_u.mutation.SetTrialGroupID(v)
+	return _u
+}
+
+// SetNillableTrialGroupID sets the "trial_group_id" field if the given value is not nil.
+func (_u *PromoCodeUpdate) SetNillableTrialGroupID(v *int64) *PromoCodeUpdate {
+	if v != nil {
+		_u.SetTrialGroupID(*v)
+	}
+	return _u
+}
+
+// AddTrialGroupID adds value to the "trial_group_id" field.
+func (_u *PromoCodeUpdate) AddTrialGroupID(v int64) *PromoCodeUpdate {
+	_u.mutation.AddTrialGroupID(v)
+	return _u
+}
+
+// ClearTrialGroupID clears the value of the "trial_group_id" field.
+func (_u *PromoCodeUpdate) ClearTrialGroupID() *PromoCodeUpdate {
+	_u.mutation.ClearTrialGroupID()
+	return _u
+}
+
+// SetTrialDays sets the "trial_days" field.
+func (_u *PromoCodeUpdate) SetTrialDays(v int) *PromoCodeUpdate {
+	_u.mutation.ResetTrialDays()
+	_u.mutation.SetTrialDays(v)
+	return _u
+}
+
+// SetNillableTrialDays sets the "trial_days" field if the given value is not nil.
+func (_u *PromoCodeUpdate) SetNillableTrialDays(v *int) *PromoCodeUpdate {
+	if v != nil {
+		_u.SetTrialDays(*v)
+	}
+	return _u
+}
+
+// AddTrialDays adds value to the "trial_days" field.
+func (_u *PromoCodeUpdate) AddTrialDays(v int) *PromoCodeUpdate {
+	_u.mutation.AddTrialDays(v)
+	return _u
+}
+
 // SetStatus sets the "status" field.
 func (_u *PromoCodeUpdate) SetStatus(v string) *PromoCodeUpdate {
 	_u.mutation.SetStatus(v)
@@ -274,10 +452,10 @@ func (_u *PromoCodeUpdate) sqlSave(ctx context.Context) (_node int, err error) {
 		_spec.SetField(promocode.FieldCode, field.TypeString, value)
 	}
 	if value, ok := _u.mutation.BonusAmount(); ok {
-		_spec.SetField(promocode.FieldBonusAmount, field.TypeFloat64, value)
+		_spec.SetField(promocode.FieldBonusAmount, field.TypeInt64, value)
 	}
 	if value, ok := _u.mutation.AddedBonusAmount(); ok {
-		_spec.AddField(promocode.FieldBonusAmount, field.TypeFloat64, value)
+		_spec.AddField(promocode.FieldBonusAmount, field.TypeInt64, value)
 	}
 	if value, ok := _u.mutation.MaxUses(); ok {
 		_spec.SetField(promocode.FieldMaxUses, field.TypeInt, value)
@@ -291,6 +469,65 @@ func (_u *PromoCodeUpdate) sqlSave(ctx context.Context) (_node int, err error) {
 	if value, ok := _u.mutation.AddedUsedCount(); ok {
 		_spec.AddField(promocode.FieldUsedCount, field.TypeInt, value)
 	}
+	if value, ok := _u.mutation.TopupBonusPercent(); ok {
+		_spec.SetField(promocode.FieldTopupBonusPercent, field.TypeFloat64, value)
+	}
+	if value, ok := _u.mutation.AddedTopupBonusPercent(); ok {
+		_spec.AddField(promocode.FieldTopupBonusPercent, field.TypeFloat64, value)
+	}
+	if value, ok := _u.mutation.TopupBonusMax(); ok {
+		_spec.SetField(promocode.FieldTopupBonusMax, field.TypeInt64, value)
+	}
+	if value, ok := _u.mutation.AddedTopupBonusMax(); ok {
+		_spec.AddField(promocode.FieldTopupBonusMax, field.TypeInt64, value)
+	}
+	if value, ok := _u.mutation.MinTopupAmount(); ok {
+		_spec.SetField(promocode.FieldMinTopupAmount, field.TypeInt64, value)
+	}
+	if value, ok := _u.mutation.AddedMinTopupAmount(); ok {
+		_spec.AddField(promocode.FieldMinTopupAmount, field.TypeInt64, value)
+	}
+	if value, ok := _u.mutation.PerUserLimit(); ok {
+		_spec.SetField(promocode.FieldPerUserLimit, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.AddedPerUserLimit(); ok {
+		_spec.AddField(promocode.FieldPerUserLimit, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.AllowedEmailDomains(); ok {
+		_spec.SetField(promocode.FieldAllowedEmailDomains, field.TypeJSON, value)
+	}
+	if value, ok := _u.mutation.AppendedAllowedEmailDomains(); ok {
+		_spec.AddModifier(func(u *sql.UpdateBuilder) {
+			sqljson.Append(u, promocode.FieldAllowedEmailDomains, value)
+		})
+	}
+	if _u.mutation.AllowedEmailDomainsCleared() {
+		_spec.ClearField(promocode.FieldAllowedEmailDomains, field.TypeJSON)
+	}
+	if value, ok := _u.mutation.RequiredAttributes(); ok {
+		_spec.SetField(promocode.FieldRequiredAttributes, field.TypeJSON, value)
+	}
+	if _u.mutation.RequiredAttributesCleared() {
+		_spec.ClearField(promocode.FieldRequiredAttributes, field.TypeJSON)
+	}
+	if value, ok := _u.mutation.Stackable(); ok {
+		_spec.SetField(promocode.FieldStackable, field.TypeBool, value)
+	}
+	if value, ok := _u.mutation.TrialGroupID(); ok {
+		_spec.SetField(promocode.FieldTrialGroupID, field.TypeInt64, value)
+	}
+	if value, ok := _u.mutation.AddedTrialGroupID(); ok {
+		_spec.AddField(promocode.FieldTrialGroupID, field.TypeInt64, value)
+	}
+	if _u.mutation.TrialGroupIDCleared() {
+		_spec.ClearField(promocode.FieldTrialGroupID, field.TypeInt64)
+	}
+	if value, ok := _u.mutation.TrialDays(); ok {
+		_spec.SetField(promocode.FieldTrialDays, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.AddedTrialDays(); ok {
+		_spec.AddField(promocode.FieldTrialDays, field.TypeInt, value)
+	}
 	if value, ok := _u.mutation.Status(); ok {
 		_spec.SetField(promocode.FieldStatus, field.TypeString, value)
 	}
@@ -389,14 +626,14 @@ func (_u *PromoCodeUpdateOne) SetNillableCode(v *string) *PromoCodeUpdateOne {
 }
 
 // SetBonusAmount sets the "bonus_amount" field.
-func (_u *PromoCodeUpdateOne) SetBonusAmount(v float64) *PromoCodeUpdateOne {
+func (_u *PromoCodeUpdateOne) SetBonusAmount(v money.Amount) *PromoCodeUpdateOne {
 	_u.mutation.ResetBonusAmount()
 	_u.mutation.SetBonusAmount(v)
 	return _u
 }
 
 // SetNillableBonusAmount sets the "bonus_amount" field if the given value is not nil.
-func (_u *PromoCodeUpdateOne) SetNillableBonusAmount(v *float64) *PromoCodeUpdateOne {
+func (_u *PromoCodeUpdateOne) SetNillableBonusAmount(v *money.Amount) *PromoCodeUpdateOne {
 	if v != nil {
 		_u.SetBonusAmount(*v)
 	}
@@ -404,7 +641,7 @@ func (_u *PromoCodeUpdateOne) SetNillableBonusAmount(v *float64) *PromoCodeUpdat
 }
 
 // AddBonusAmount adds value to the "bonus_amount" field.
-func (_u *PromoCodeUpdateOne) AddBonusAmount(v float64) *PromoCodeUpdateOne {
+func (_u *PromoCodeUpdateOne) AddBonusAmount(v money.Amount) *PromoCodeUpdateOne {
 	_u.mutation.AddBonusAmount(v)
 	return _u
 }
@@ -451,6 +688,182 @@ func (_u *PromoCodeUpdateOne) AddUsedCount(v int) *PromoCodeUpdateOne {
 	return _u
 }
 
+// SetTopupBonusPercent sets the "topup_bonus_percent" field.
+func (_u *PromoCodeUpdateOne) SetTopupBonusPercent(v float64) *PromoCodeUpdateOne {
+	_u.mutation.ResetTopupBonusPercent()
+	_u.mutation.SetTopupBonusPercent(v)
+	return _u
+}
+
+// SetNillableTopupBonusPercent sets the "topup_bonus_percent" field if the given value is not nil.
+func (_u *PromoCodeUpdateOne) SetNillableTopupBonusPercent(v *float64) *PromoCodeUpdateOne {
+	if v != nil {
+		_u.SetTopupBonusPercent(*v)
+	}
+	return _u
+}
+
+// AddTopupBonusPercent adds value to the "topup_bonus_percent" field.
+func (_u *PromoCodeUpdateOne) AddTopupBonusPercent(v float64) *PromoCodeUpdateOne {
+	_u.mutation.AddTopupBonusPercent(v)
+	return _u
+}
+
+// SetTopupBonusMax sets the "topup_bonus_max" field.
+func (_u *PromoCodeUpdateOne) SetTopupBonusMax(v money.Amount) *PromoCodeUpdateOne {
+	_u.mutation.ResetTopupBonusMax()
+	_u.mutation.SetTopupBonusMax(v)
+	return _u
+}
+
+// SetNillableTopupBonusMax sets the "topup_bonus_max" field if the given value is not nil.
+func (_u *PromoCodeUpdateOne) SetNillableTopupBonusMax(v *money.Amount) *PromoCodeUpdateOne {
+	if v != nil {
+		_u.SetTopupBonusMax(*v)
+	}
+	return _u
+}
+
+// AddTopupBonusMax adds value to the "topup_bonus_max" field.
+func (_u *PromoCodeUpdateOne) AddTopupBonusMax(v money.Amount) *PromoCodeUpdateOne {
+	_u.mutation.AddTopupBonusMax(v)
+	return _u
+}
+
+// SetMinTopupAmount sets the "min_topup_amount" field.
+func (_u *PromoCodeUpdateOne) SetMinTopupAmount(v money.Amount) *PromoCodeUpdateOne {
+	_u.mutation.ResetMinTopupAmount()
+	_u.mutation.SetMinTopupAmount(v)
+	return _u
+}
+
+// SetNillableMinTopupAmount sets the "min_topup_amount" field if the given value is not nil.
+func (_u *PromoCodeUpdateOne) SetNillableMinTopupAmount(v *money.Amount) *PromoCodeUpdateOne {
+	if v != nil {
+		_u.SetMinTopupAmount(*v)
+	}
+	return _u
+}
+
+// AddMinTopupAmount adds value to the "min_topup_amount" field.
+func (_u *PromoCodeUpdateOne) AddMinTopupAmount(v money.Amount) *PromoCodeUpdateOne {
+	_u.mutation.AddMinTopupAmount(v)
+	return _u
+}
+
+// SetPerUserLimit sets the "per_user_limit" field.
+func (_u *PromoCodeUpdateOne) SetPerUserLimit(v int) *PromoCodeUpdateOne {
+	_u.mutation.ResetPerUserLimit()
+	_u.mutation.SetPerUserLimit(v)
+	return _u
+}
+
+// SetNillablePerUserLimit sets the "per_user_limit" field if the given value is not nil.
+func (_u *PromoCodeUpdateOne) SetNillablePerUserLimit(v *int) *PromoCodeUpdateOne {
+	if v != nil {
+		_u.SetPerUserLimit(*v)
+	}
+	return _u
+}
+
+// AddPerUserLimit adds value to the "per_user_limit" field.
+func (_u *PromoCodeUpdateOne) AddPerUserLimit(v int) *PromoCodeUpdateOne {
+	_u.mutation.AddPerUserLimit(v)
+	return _u
+}
+
+// SetAllowedEmailDomains sets the "allowed_email_domains" field.
+func (_u *PromoCodeUpdateOne) SetAllowedEmailDomains(v []string) *PromoCodeUpdateOne {
+	_u.mutation.SetAllowedEmailDomains(v)
+	return _u
+}
+
+// AppendAllowedEmailDomains appends value to the "allowed_email_domains" field.
+func (_u *PromoCodeUpdateOne) AppendAllowedEmailDomains(v []string) *PromoCodeUpdateOne {
+	_u.mutation.AppendAllowedEmailDomains(v)
+	return _u
+}
+
+// ClearAllowedEmailDomains clears the value of the "allowed_email_domains" field.
+func (_u *PromoCodeUpdateOne) ClearAllowedEmailDomains() *PromoCodeUpdateOne {
+	_u.mutation.ClearAllowedEmailDomains()
+	return _u
+}
+
+// SetRequiredAttributes sets the "required_attributes" field.
+func (_u *PromoCodeUpdateOne) SetRequiredAttributes(v map[string]string) *PromoCodeUpdateOne {
+	_u.mutation.SetRequiredAttributes(v)
+	return _u
+}
+
+// ClearRequiredAttributes clears the value of the "required_attributes" field.
+func (_u *PromoCodeUpdateOne) ClearRequiredAttributes() *PromoCodeUpdateOne {
+	_u.mutation.ClearRequiredAttributes()
+	return _u
+}
+
+// SetStackable sets the "stackable" field.
+func (_u *PromoCodeUpdateOne) SetStackable(v bool) *PromoCodeUpdateOne {
+	_u.mutation.SetStackable(v)
+	return _u
+}
+
+// SetNillableStackable sets the "stackable" field if the given value is not nil.
+func (_u *PromoCodeUpdateOne) SetNillableStackable(v *bool) *PromoCodeUpdateOne {
+	if v != nil {
+		_u.SetStackable(*v)
+	}
+	return _u
+}
+
+// SetTrialGroupID sets the "trial_group_id" field.
+func (_u *PromoCodeUpdateOne) SetTrialGroupID(v int64) *PromoCodeUpdateOne {
+	_u.mutation.ResetTrialGroupID()
+	_u.mutation.SetTrialGroupID(v)
+	return _u
+}
+
+// SetNillableTrialGroupID sets the "trial_group_id" field if the given value is not nil.
+func (_u *PromoCodeUpdateOne) SetNillableTrialGroupID(v *int64) *PromoCodeUpdateOne {
+	if v != nil {
+		_u.SetTrialGroupID(*v)
+	}
+	return _u
+}
+
+// AddTrialGroupID adds value to the "trial_group_id" field.
+func (_u *PromoCodeUpdateOne) AddTrialGroupID(v int64) *PromoCodeUpdateOne {
+	_u.mutation.AddTrialGroupID(v)
+	return _u
+}
+
+// ClearTrialGroupID clears the value of the "trial_group_id" field.
+func (_u *PromoCodeUpdateOne) ClearTrialGroupID() *PromoCodeUpdateOne {
+	_u.mutation.ClearTrialGroupID()
+	return _u
+}
+
+// SetTrialDays sets the "trial_days" field.
+func (_u *PromoCodeUpdateOne) SetTrialDays(v int) *PromoCodeUpdateOne {
+	_u.mutation.ResetTrialDays()
+	_u.mutation.SetTrialDays(v)
+	return _u
+}
+
+// SetNillableTrialDays sets the "trial_days" field if the given value is not nil.
+func (_u *PromoCodeUpdateOne) SetNillableTrialDays(v *int) *PromoCodeUpdateOne {
+	if v != nil {
+		_u.SetTrialDays(*v)
+	}
+	return _u
+}
+
+// AddTrialDays adds value to the "trial_days" field.
+func (_u *PromoCodeUpdateOne) AddTrialDays(v int) *PromoCodeUpdateOne {
+	_u.mutation.AddTrialDays(v)
+	return _u
+}
+
 // SetStatus sets the "status" field.
 func (_u *PromoCodeUpdateOne) SetStatus(v string) *PromoCodeUpdateOne {
 	_u.mutation.SetStatus(v)
@@ -649,10 +1062,10 @@ func (_u *PromoCodeUpdateOne) sqlSave(ctx context.Context) (_node *PromoCode, er
 		_spec.SetField(promocode.FieldCode, field.TypeString, value)
 	}
 	if value, ok := _u.mutation.BonusAmount(); ok {
-		_spec.SetField(promocode.FieldBonusAmount, field.TypeFloat64, value)
+		_spec.SetField(promocode.FieldBonusAmount, field.TypeInt64, value)
 	}
 	if value, ok := _u.mutation.AddedBonusAmount(); ok {
-		_spec.AddField(promocode.FieldBonusAmount, field.TypeFloat64, value)
+		_spec.AddField(promocode.FieldBonusAmount, field.TypeInt64, value)
 	}
 	if value, ok := _u.mutation.MaxUses(); ok {
 		_spec.SetField(promocode.FieldMaxUses, field.TypeInt, value)
@@ -666,6 +1079,65 @@ func (_u *PromoCodeUpdateOne) sqlSave(ctx context.Context) (_node *PromoCode, er
 	if value, ok := _u.mutation.AddedUsedCount(); ok {
 		_spec.AddField(promocode.FieldUsedCount, field.TypeInt, value)
 	}
+	if value, ok := _u.mutation.TopupBonusPercent(); ok {
+		_spec.SetField(promocode.FieldTopupBonusPercent, field.TypeFloat64, value)
+	}
+	if value, ok := _u.mutation.AddedTopupBonusPercent(); ok {
+		_spec.AddField(promocode.FieldTopupBonusPercent, field.TypeFloat64, value)
+	}
+	if value, ok := _u.mutation.TopupBonusMax(); ok {
+		_spec.SetField(promocode.FieldTopupBonusMax, field.TypeInt64, value)
+	}
+	if value, ok := _u.mutation.AddedTopupBonusMax(); ok {
+		_spec.AddField(promocode.FieldTopupBonusMax, field.TypeInt64, value)
+	}
+	if value, ok := _u.mutation.MinTopupAmount(); ok {
+		_spec.SetField(promocode.FieldMinTopupAmount, field.TypeInt64, value)
+	}
+	if value, ok := _u.mutation.AddedMinTopupAmount(); ok {
+		_spec.AddField(promocode.FieldMinTopupAmount, field.TypeInt64, value)
+	}
+	if value, ok := _u.mutation.PerUserLimit(); ok {
+		_spec.SetField(promocode.FieldPerUserLimit, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.AddedPerUserLimit(); ok {
+		_spec.AddField(promocode.FieldPerUserLimit, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.AllowedEmailDomains(); ok {
+		_spec.SetField(promocode.FieldAllowedEmailDomains, field.TypeJSON, value)
+	}
+	if value, ok := _u.mutation.AppendedAllowedEmailDomains(); ok {
+		_spec.AddModifier(func(u *sql.UpdateBuilder) {
+			sqljson.Append(u, promocode.FieldAllowedEmailDomains, value)
+		})
+	}
+	if _u.mutation.AllowedEmailDomainsCleared() {
+		_spec.ClearField(promocode.FieldAllowedEmailDomains, field.TypeJSON)
+	}
+	if value, ok := _u.mutation.RequiredAttributes(); ok {
+		_spec.SetField(promocode.FieldRequiredAttributes, field.TypeJSON, value)
+	}
+	if _u.mutation.RequiredAttributesCleared() {
+		_spec.ClearField(promocode.FieldRequiredAttributes, field.TypeJSON)
+	}
+	if value, ok := _u.mutation.Stackable(); ok {
+		_spec.SetField(promocode.FieldStackable, field.TypeBool, value)
+	}
+	if value, ok := _u.mutation.TrialGroupID(); ok {
+		_spec.SetField(promocode.FieldTrialGroupID, field.TypeInt64, value)
+	}
+	if value, ok := _u.mutation.AddedTrialGroupID(); ok {
+		_spec.AddField(promocode.FieldTrialGroupID, field.TypeInt64, value)
+	}
+	if _u.mutation.TrialGroupIDCleared() {
+		_spec.ClearField(promocode.FieldTrialGroupID, field.TypeInt64)
+	}
+	if value, ok := _u.mutation.TrialDays(); ok {
+		_spec.SetField(promocode.FieldTrialDays, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.AddedTrialDays(); ok {
+		_spec.AddField(promocode.FieldTrialDays, field.TypeInt, value)
+	}
 	if value, ok := _u.mutation.Status(); ok {
 		_spec.SetField(promocode.FieldStatus, field.TypeString, value)
 	}
@@ -12,6 +12,7 @@ import (
 	"github.com/Wei-Shaw/sub2api/ent/promocode"
 	"github.com/Wei-Shaw/sub2api/ent/promocodeusage"
 	"github.com/Wei-Shaw/sub2api/ent/user"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
 )
 
 // PromoCodeUsage is the model entity for the PromoCodeUsage schema.
@@ -24,7 +25,13 @@ type PromoCodeUsage struct {
 	// 使用用户ID
 	UserID int64 `json:"user_id,omitempty"`
 	// 实际赠送金额
-	BonusAmount float64 `json:"bonus_amount,omitempty"`
+	BonusAmount money.Amount `json:"bonus_amount,omitempty"`
+	// 充值赠送状态: none, pending, granted
+	TopupBonusStatus string `json:"topup_bonus_status,omitempty"`
+	// 已发放的充值赠送金额
+	TopupBonusAmount money.Amount `json:"topup_bonus_amount,omitempty"`
+	// 充值赠送发放时间
+	TopupGrantedAt *time.Time `json:"topup_granted_at,omitempty"`
 	// 使用时间
 	UsedAt time.Time `json:"used_at,omitempty"`
 	// Edges holds the relations/edges for other nodes in the graph.
@@ -71,11 +78,13 @@ func (*PromoCodeUsage) scanValues(columns []string) ([]any, error) {
 	values := make([]any, len(columns))
 	for i := range columns {
 		switch columns[i] {
-		case promocodeusage.FieldBonusAmount:
-			values[i] = new(sql.NullFloat64)
+		case promocodeusage.FieldBonusAmount, promocodeusage.FieldTopupBonusAmount:
+			values[i] = new(money.Amount)
 		case promocodeusage.FieldID, promocodeusage.FieldPromoCodeID, promocodeusage.FieldUserID:
 			values[i] = new(sql.NullInt64)
-		case promocodeusage.FieldUsedAt:
+		case promocodeusage.FieldTopupBonusStatus:
+			values[i] = new(sql.NullString)
+		case promocodeusage.FieldTopupGrantedAt, promocodeusage.FieldUsedAt:
 			values[i] = new(sql.NullTime)
 		default:
 			values[i] = new(sql.UnknownType)
@@ -111,10 +120,29 @@ func (_m *PromoCodeUsage) assignValues(columns []string, values []any) error {
 				_m.UserID = value.Int64
 			}
 		case promocodeusage.FieldBonusAmount:
-			if value, ok := values[i].(*sql.NullFloat64); !ok {
+			if value, ok := values[i].(*money.Amount); !ok {
 				return fmt.Errorf("unexpected type %T for field bonus_amount", values[i])
+			} else if value != nil {
+				_m.BonusAmount = *value
+			}
+		case promocodeusage.FieldTopupBonusStatus:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field topup_bonus_status", values[i])
 			} else if value.Valid {
-				_m.BonusAmount = value.Float64
+				_m.TopupBonusStatus = value.String
+			}
+		case promocodeusage.FieldTopupBonusAmount:
+			if value, ok := values[i].(*money.Amount); !ok {
+				return fmt.Errorf("unexpected type %T for field topup_bonus_amount", values[i])
+			} else if value != nil {
+				_m.TopupBonusAmount = *value
+			}
+		case promocodeusage.FieldTopupGrantedAt:
+			if value, ok := values[i].(*sql.NullTime); !ok {
+				return fmt.Errorf("unexpected type %T for field topup_granted_at", values[i])
+			} else if value.Valid {
+				_m.TopupGrantedAt = new(time.Time)
+				*_m.TopupGrantedAt = value.Time
 			}
 		case promocodeusage.FieldUsedAt:
 			if value, ok := values[i].(*sql.NullTime); !ok {
@@ -177,6 +205,17 @@ func (_m *PromoCodeUsage) String() string {
 	builder.WriteString("bonus_amount=")
 	builder.WriteString(fmt.Sprintf("%v", _m.BonusAmount))
 	builder.WriteString(", ")
+	builder.WriteString("topup_bonus_status=")
+	builder.WriteString(_m.TopupBonusStatus)
+	builder.WriteString(", ")
+	builder.WriteString("topup_bonus_amount=")
+	builder.WriteString(fmt.Sprintf("%v", _m.TopupBonusAmount))
+	builder.WriteString(", ")
+	if v := _m.TopupGrantedAt; v != nil {
+		builder.WriteString("topup_granted_at=")
+		builder.WriteString(v.Format(time.ANSIC))
+	}
+	builder.WriteString(", ")
 	builder.WriteString("used_at=")
 	builder.WriteString(_m.UsedAt.Format(time.ANSIC))
 	builder.WriteByte(')')
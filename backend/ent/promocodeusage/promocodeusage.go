@@ -7,6 +7,7 @@ import (
 
 	"entgo.io/ent/dialect/sql"
 	"entgo.io/ent/dialect/sql/sqlgraph"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
 )
 
 const (
@@ -20,6 +21,12 @@ const (
 	FieldUserID = "user_id"
 	// FieldBonusAmount holds the string denoting the bonus_amount field in the database.
 	FieldBonusAmount = "bonus_amount"
+	// FieldTopupBonusStatus holds the string denoting the topup_bonus_status field in the database.
+	FieldTopupBonusStatus = "topup_bonus_status"
+	// FieldTopupBonusAmount holds the string denoting the topup_bonus_amount field in the database.
+	FieldTopupBonusAmount = "topup_bonus_amount"
+	// FieldTopupGrantedAt holds the string denoting the topup_granted_at field in the database.
+	FieldTopupGrantedAt = "topup_granted_at"
 	// FieldUsedAt holds the string denoting the used_at field in the database.
 	FieldUsedAt = "used_at"
 	// EdgePromoCode holds the string denoting the promo_code edge name in mutations.
@@ -50,6 +57,9 @@ var Columns = []string{
 	FieldPromoCodeID,
 	FieldUserID,
 	FieldBonusAmount,
+	FieldTopupBonusStatus,
+	FieldTopupBonusAmount,
+	FieldTopupGrantedAt,
 	FieldUsedAt,
 }
 
@@ -64,6 +74,12 @@ func ValidColumn(column string) bool {
 }
 
 var (
+	// DefaultTopupBonusStatus holds the default value on creation for the "topup_bonus_status" field.
+	DefaultTopupBonusStatus string
+	// TopupBonusStatusValidator is a validator for the "topup_bonus_status" field. It is called by the builders before save.
+	TopupBonusStatusValidator func(string) error
+	// DefaultTopupBonusAmount holds the default value on creation for the "topup_bonus_amount" field.
+	DefaultTopupBonusAmount money.Amount
 	// DefaultUsedAt holds the default value on creation for the "used_at" field.
 	DefaultUsedAt func() time.Time
 )
@@ -91,6 +107,21 @@ func ByBonusAmount(opts ...sql.OrderTermOption) OrderOption {
 	return sql.OrderByField(FieldBonusAmount, opts...).ToFunc()
 }
 
+// ByTopupBonusStatus orders the results by the topup_bonus_status field.
+func ByTopupBonusStatus(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldTopupBonusStatus, opts...).ToFunc()
+}
+
+// ByTopupBonusAmount orders the results by the topup_bonus_amount field.
+func ByTopupBonusAmount(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldTopupBonusAmount, opts...).ToFunc()
+}
+
+// ByTopupGrantedAt orders the results by the topup_granted_at field.
+func ByTopupGrantedAt(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldTopupGrantedAt, opts...).ToFunc()
+}
+
 // ByUsedAt orders the results by the used_at field.
 func ByUsedAt(opts ...sql.OrderTermOption) OrderOption {
 	return sql.OrderByField(FieldUsedAt, opts...).ToFunc()
@@ -8,6 +8,7 @@ import (
 	"entgo.io/ent/dialect/sql"
 	"entgo.io/ent/dialect/sql/sqlgraph"
 	"github.com/Wei-Shaw/sub2api/ent/predicate"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
 )
 
 // ID filters vertices based on their ID field.
@@ -66,10 +67,25 @@ func UserID(v int64) predicate.PromoCodeUsage {
 }
 
 // BonusAmount applies equality check predicate on the "bonus_amount" field. It's identical to BonusAmountEQ.
-func BonusAmount(v float64) predicate.PromoCodeUsage {
+func BonusAmount(v money.Amount) predicate.PromoCodeUsage {
 	return predicate.PromoCodeUsage(sql.FieldEQ(FieldBonusAmount, v))
 }
 
+// TopupBonusStatus applies equality check predicate on the "topup_bonus_status" field. It's identical to TopupBonusStatusEQ.
+func TopupBonusStatus(v string) predicate.PromoCodeUsage {
+	return predicate.PromoCodeUsage(sql.FieldEQ(FieldTopupBonusStatus, v))
+}
+
+// TopupBonusAmount applies equality check predicate on the "topup_bonus_amount" field. It's identical to TopupBonusAmountEQ.
+func TopupBonusAmount(v money.Amount) predicate.PromoCodeUsage {
+	return predicate.PromoCodeUsage(sql.FieldEQ(FieldTopupBonusAmount, v))
+}
+
+// TopupGrantedAt applies equality check predicate on the "topup_granted_at" field. It's identical to TopupGrantedAtEQ.
+func TopupGrantedAt(v time.Time) predicate.PromoCodeUsage {
+	return predicate.PromoCodeUsage(sql.FieldEQ(FieldTopupGrantedAt, v))
+}
+
 // UsedAt applies equality check predicate on the "used_at" field. It's identical to UsedAtEQ.
 func UsedAt(v time.Time) predicate.PromoCodeUsage {
 	return predicate.PromoCodeUsage(sql.FieldEQ(FieldUsedAt, v))
@@ -116,45 +132,200 @@ func UserIDNotIn(vs ...int64) predicate.PromoCodeUsage {
 }
 
 // BonusAmountEQ applies the EQ predicate on the "bonus_amount" field.
-func BonusAmountEQ(v float64) predicate.PromoCodeUsage {
+func BonusAmountEQ(v money.Amount) predicate.PromoCodeUsage {
 	return predicate.PromoCodeUsage(sql.FieldEQ(FieldBonusAmount, v))
 }
 
 // BonusAmountNEQ applies the NEQ predicate on the "bonus_amount" field.
-func BonusAmountNEQ(v float64) predicate.PromoCodeUsage {
+func BonusAmountNEQ(v money.Amount) predicate.PromoCodeUsage {
 	return predicate.PromoCodeUsage(sql.FieldNEQ(FieldBonusAmount, v))
 }
 
 // BonusAmountIn applies the In predicate on the "bonus_amount" field.
-func BonusAmountIn(vs ...float64) predicate.PromoCodeUsage {
+func BonusAmountIn(vs ...money.Amount) predicate.PromoCodeUsage {
 	return predicate.PromoCodeUsage(sql.FieldIn(FieldBonusAmount, vs...))
 }
 
 // BonusAmountNotIn applies the NotIn predicate on the "bonus_amount" field.
-func BonusAmountNotIn(vs ...float64) predicate.PromoCodeUsage {
+func BonusAmountNotIn(vs ...money.Amount) predicate.PromoCodeUsage {
 	return predicate.PromoCodeUsage(sql.FieldNotIn(FieldBonusAmount, vs...))
 }
 
 // BonusAmountGT applies the GT predicate on the "bonus_amount" field.
-func BonusAmountGT(v float64) predicate.PromoCodeUsage {
+func BonusAmountGT(v money.Amount) predicate.PromoCodeUsage {
 	return predicate.PromoCodeUsage(sql.FieldGT(FieldBonusAmount, v))
 }
 
 // BonusAmountGTE applies the GTE predicate on the "bonus_amount" field.
-func BonusAmountGTE(v float64) predicate.PromoCodeUsage {
+func BonusAmountGTE(v money.Amount) predicate.PromoCodeUsage {
 	return predicate.PromoCodeUsage(sql.FieldGTE(FieldBonusAmount, v))
 }
 
 // BonusAmountLT applies the LT predicate on the "bonus_amount" field.
-func BonusAmountLT(v float64) predicate.PromoCodeUsage {
+func BonusAmountLT(v money.Amount) predicate.PromoCodeUsage {
 	return predicate.PromoCodeUsage(sql.FieldLT(FieldBonusAmount, v))
 }
 
 // BonusAmountLTE applies the LTE predicate on the "bonus_amount" field.
-func BonusAmountLTE(v float64) predicate.PromoCodeUsage {
+func BonusAmountLTE(v money.Amount) predicate.PromoCodeUsage {
 	return predicate.PromoCodeUsage(sql.FieldLTE(FieldBonusAmount, v))
 }
 
+// TopupBonusStatusEQ applies the EQ predicate on the "topup_bonus_status" field.
+func TopupBonusStatusEQ(v string) predicate.PromoCodeUsage {
+	return predicate.PromoCodeUsage(sql.FieldEQ(FieldTopupBonusStatus, v))
+}
+
+// TopupBonusStatusNEQ applies the NEQ predicate on the "topup_bonus_status" field.
+func TopupBonusStatusNEQ(v string) predicate.PromoCodeUsage {
+	return predicate.PromoCodeUsage(sql.FieldNEQ(FieldTopupBonusStatus, v))
+}
+
+// TopupBonusStatusIn applies the In predicate on the "topup_bonus_status" field.
+func TopupBonusStatusIn(vs ...string) predicate.PromoCodeUsage {
+	return predicate.PromoCodeUsage(sql.FieldIn(FieldTopupBonusStatus, vs...))
+}
+
+// TopupBonusStatusNotIn applies the NotIn predicate on the "topup_bonus_status" field.
+func TopupBonusStatusNotIn(vs ...string) predicate.PromoCodeUsage {
+	return predicate.PromoCodeUsage(sql.FieldNotIn(FieldTopupBonusStatus, vs...))
+}
+
+// TopupBonusStatusGT applies the GT predicate on the "topup_bonus_status" field.
+func TopupBonusStatusGT(v string) predicate.PromoCodeUsage {
+	return predicate.PromoCodeUsage(sql.FieldGT(FieldTopupBonusStatus, v))
+}
+
+// TopupBonusStatusGTE applies the GTE predicate on the "topup_bonus_status" field.
+func TopupBonusStatusGTE(v string) predicate.PromoCodeUsage {
+	return predicate.PromoCodeUsage(sql.FieldGTE(FieldTopupBonusStatus, v))
+}
+
+// TopupBonusStatusLT applies the LT predicate on the "topup_bonus_status" field.
+func TopupBonusStatusLT(v string) predicate.PromoCodeUsage {
+	return predicate.PromoCodeUsage(sql.FieldLT(FieldTopupBonusStatus, v))
+}
+
+// TopupBonusStatusLTE applies the LTE predicate on the "topup_bonus_status" field.
+func TopupBonusStatusLTE(v string) predicate.PromoCodeUsage {
+	return predicate.PromoCodeUsage(sql.FieldLTE(FieldTopupBonusStatus, v))
+}
+
+// TopupBonusStatusContains applies the Contains predicate on the "topup_bonus_status" field.
+func TopupBonusStatusContains(v string) predicate.PromoCodeUsage {
+	return predicate.PromoCodeUsage(sql.FieldContains(FieldTopupBonusStatus, v))
+}
+
+// TopupBonusStatusHasPrefix applies the HasPrefix predicate on the "topup_bonus_status" field.
+func TopupBonusStatusHasPrefix(v string) predicate.PromoCodeUsage {
+	return predicate.PromoCodeUsage(sql.FieldHasPrefix(FieldTopupBonusStatus, v))
+}
+
+// TopupBonusStatusHasSuffix applies the HasSuffix predicate on the "topup_bonus_status" field.
+func TopupBonusStatusHasSuffix(v string) predicate.PromoCodeUsage {
+	return predicate.PromoCodeUsage(sql.FieldHasSuffix(FieldTopupBonusStatus, v))
+}
+
+// TopupBonusStatusEqualFold applies the EqualFold predicate on the "topup_bonus_status" field.
+func TopupBonusStatusEqualFold(v string) predicate.PromoCodeUsage {
+	return predicate.PromoCodeUsage(sql.FieldEqualFold(FieldTopupBonusStatus, v))
+}
+
+// TopupBonusStatusContainsFold applies the ContainsFold predicate on the "topup_bonus_status" field.
+func TopupBonusStatusContainsFold(v string) predicate.PromoCodeUsage {
+	return predicate.PromoCodeUsage(sql.FieldContainsFold(FieldTopupBonusStatus, v))
+}
+
+// TopupBonusAmountEQ applies the EQ predicate on the "topup_bonus_amount" field.
+func TopupBonusAmountEQ(v money.Amount) predicate.PromoCodeUsage {
+	return predicate.PromoCodeUsage(sql.FieldEQ(FieldTopupBonusAmount, v))
+}
+
+// TopupBonusAmountNEQ applies the NEQ predicate on the "topup_bonus_amount" field.
+func TopupBonusAmountNEQ(v money.Amount) predicate.PromoCodeUsage {
+	return predicate.PromoCodeUsage(sql.FieldNEQ(FieldTopupBonusAmount, v))
+}
+
+// TopupBonusAmountIn applies the In predicate on the "topup_bonus_amount" field.
+func TopupBonusAmountIn(vs ...money.Amount) predicate.PromoCodeUsage {
+	return predicate.PromoCodeUsage(sql.FieldIn(FieldTopupBonusAmount, vs...))
+}
+
+// TopupBonusAmountNotIn applies the NotIn predicate on the "topup_bonus_amount" field.
+func TopupBonusAmountNotIn(vs ...money.Amount) predicate.PromoCodeUsage {
+	return predicate.PromoCodeUsage(sql.FieldNotIn(FieldTopupBonusAmount, vs...))
+}
+
+// TopupBonusAmountGT applies the GT predicate on the "topup_bonus_amount" field.
+func TopupBonusAmountGT(v money.Amount) predicate.PromoCodeUsage {
+	return predicate.PromoCodeUsage(sql.FieldGT(FieldTopupBonusAmount, v))
+}
+
+// TopupBonusAmountGTE applies the GTE predicate on the "topup_bonus_amount" field.
+func TopupBonusAmountGTE(v money.Amount) predicate.PromoCodeUsage {
+	return predicate.PromoCodeUsage(sql.FieldGTE(FieldTopupBonusAmount, v))
+}
+
+// TopupBonusAmountLT applies the LT predicate on the "topup_bonus_amount" field.
+func TopupBonusAmountLT(v money.Amount) predicate.PromoCodeUsage {
+	return predicate.PromoCodeUsage(sql.FieldLT(FieldTopupBonusAmount, v))
+}
+
+// TopupBonusAmountLTE applies the LTE predicate on the "topup_bonus_amount" field.
+func TopupBonusAmountLTE(v money.Amount) predicate.PromoCodeUsage {
+	return predicate.PromoCodeUsage(sql.FieldLTE(FieldTopupBonusAmount, v))
+}
+
+// TopupGrantedAtEQ applies the EQ predicate on the "topup_granted_at" field.
+func TopupGrantedAtEQ(v time.Time) predicate.PromoCodeUsage {
+	return predicate.PromoCodeUsage(sql.FieldEQ(FieldTopupGrantedAt, v))
+}
+
+// TopupGrantedAtNEQ applies the NEQ predicate on the "topup_granted_at" field.
+func TopupGrantedAtNEQ(v time.Time) predicate.PromoCodeUsage {
+	return predicate.PromoCodeUsage(sql.FieldNEQ(FieldTopupGrantedAt, v))
+}
+
+// TopupGrantedAtIn applies the In predicate on the "topup_granted_at" field.
+func TopupGrantedAtIn(vs ...time.Time) predicate.PromoCodeUsage {
+	return predicate.PromoCodeUsage(sql.FieldIn(FieldTopupGrantedAt, vs...))
+}
+
+// TopupGrantedAtNotIn applies the NotIn predicate on the "topup_granted_at" field.
+func TopupGrantedAtNotIn(vs ...time.Time) predicate.PromoCodeUsage {
+	return predicate.PromoCodeUsage(sql.FieldNotIn(FieldTopupGrantedAt, vs...))
+}
+
+// TopupGrantedAtGT applies the GT predicate on the "topup_granted_at" field.
+func TopupGrantedAtGT(v time.Time) predicate.PromoCodeUsage {
+	return predicate.PromoCodeUsage(sql.FieldGT(FieldTopupGrantedAt, v))
+}
+
+// TopupGrantedAtGTE applies the GTE predicate on the "topup_granted_at" field.
+func TopupGrantedAtGTE(v time.Time) predicate.PromoCodeUsage {
+	return predicate.PromoCodeUsage(sql.FieldGTE(FieldTopupGrantedAt, v))
+}
+
+// TopupGrantedAtLT applies the LT predicate on the "topup_granted_at" field.
+func TopupGrantedAtLT(v time.Time) predicate.PromoCodeUsage {
+	return predicate.PromoCodeUsage(sql.FieldLT(FieldTopupGrantedAt, v))
+}
+
+// TopupGrantedAtLTE applies the LTE predicate on the "topup_granted_at" field.
+func TopupGrantedAtLTE(v time.Time) predicate.PromoCodeUsage {
+	return predicate.PromoCodeUsage(sql.FieldLTE(FieldTopupGrantedAt, v))
+}
+
+// TopupGrantedAtIsNil applies the IsNil predicate on the "topup_granted_at" field.
+func TopupGrantedAtIsNil() predicate.PromoCodeUsage {
+	return predicate.PromoCodeUsage(sql.FieldIsNull(FieldTopupGrantedAt))
+}
+
+// TopupGrantedAtNotNil applies the NotNil predicate on the "topup_granted_at" field.
+func TopupGrantedAtNotNil() predicate.PromoCodeUsage {
+	return predicate.PromoCodeUsage(sql.FieldNotNull(FieldTopupGrantedAt))
+}
+
 // UsedAtEQ applies the EQ predicate on the "used_at" field.
 func UsedAtEQ(v time.Time) predicate.PromoCodeUsage {
 	return predicate.PromoCodeUsage(sql.FieldEQ(FieldUsedAt, v))
@@ -14,6 +14,7 @@ import (
 	"github.com/Wei-Shaw/sub2api/ent/promocode"
 	"github.com/Wei-Shaw/sub2api/ent/promocodeusage"
 	"github.com/Wei-Shaw/sub2api/ent/user"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
 )
 
 // PromoCodeUsageCreate is the builder for creating a PromoCodeUsage entity.
@@ -37,11 +38,53 @@ func (_c *PromoCodeUsageCreate) SetUserID(v int64) *PromoCodeUsageCreate {
 }
 
 // SetBonusAmount sets the "bonus_amount" field.
-func (_c *PromoCodeUsageCreate) SetBonusAmount(v float64) *PromoCodeUsageCreate {
+func (_c *PromoCodeUsageCreate) SetBonusAmount(v money.Amount) *PromoCodeUsageCreate {
 	_c.mutation.SetBonusAmount(v)
 	return _c
 }
 
+// SetTopupBonusStatus sets the "topup_bonus_status" field.
+func (_c *PromoCodeUsageCreate) SetTopupBonusStatus(v string) *PromoCodeUsageCreate {
+	_c.mutation.SetTopupBonusStatus(v)
+	return _c
+}
+
+// SetNillableTopupBonusStatus sets the "topup_bonus_status" field if the given value is not nil.
+func (_c *PromoCodeUsageCreate) SetNillableTopupBonusStatus(v *string) *PromoCodeUsageCreate {
+	if v != nil {
+		_c.SetTopupBonusStatus(*v)
+	}
+	return _c
+}
+
+// SetTopupBonusAmount sets the "topup_bonus_amount" field.
+func (_c *PromoCodeUsageCreate) SetTopupBonusAmount(v money.Amount) *PromoCodeUsageCreate {
+	_c.mutation.SetTopupBonusAmount(v)
+	return _c
+}
+
+// SetNillableTopupBonusAmount sets the "topup_bonus_amount" field if the given value is not nil.
+func (_c *PromoCodeUsageCreate) SetNillableTopupBonusAmount(v *money.Amount) *PromoCodeUsageCreate {
+	if v != nil {
+		_c.SetTopupBonusAmount(*v)
+	}
+	return _c
+}
+
+// SetTopupGrantedAt sets the "topup_granted_at" field.
+func (_c *PromoCodeUsageCreate) SetTopupGrantedAt(v time.Time) *PromoCodeUsageCreate {
+	_c.mutation.SetTopupGrantedAt(v)
+	return _c
+}
+
+// SetNillableTopupGrantedAt sets the "topup_granted_at" field if the given value is not nil.
+func (_c *PromoCodeUsageCreate) SetNillableTopupGrantedAt(v *time.Time) *PromoCodeUsageCreate {
+	if v != nil {
+		_c.SetTopupGrantedAt(*v)
+	}
+	return _c
+}
+
 // SetUsedAt sets the "used_at" field.
 func (_c *PromoCodeUsageCreate) SetUsedAt(v time.Time) *PromoCodeUsageCreate {
 	_c.mutation.SetUsedAt(v)
@@ -101,6 +144,14 @@ func (_c *PromoCodeUsageCreate) ExecX(ctx context.Context) {
 
 // defaults sets the default values of the builder before save.
 func (_c *PromoCodeUsageCreate) defaults() {
+	if _, ok := _c.mutation.TopupBonusStatus(); !ok {
+		v := promocodeusage.DefaultTopupBonusStatus
+		_c.mutation.SetTopupBonusStatus(v)
+	}
+	if _, ok := _c.mutation.TopupBonusAmount(); !ok {
+		v := promocodeusage.DefaultTopupBonusAmount
+		_c.mutation.SetTopupBonusAmount(v)
+	}
 	if _, ok := _c.mutation.UsedAt(); !ok {
 		v := promocodeusage.DefaultUsedAt()
 		_c.mutation.SetUsedAt(v)
@@ -118,6 +169,17 @@ func (_c *PromoCodeUsageCreate) check() error {
 	if _, ok := _c.mutation.BonusAmount(); !ok {
 		return &ValidationError{Name: "bonus_amount", err: errors.New(`ent: missing required field "PromoCodeUsage.bonus_amount"`)}
 	}
+	if _, ok := _c.mutation.TopupBonusStatus(); !ok {
+		return &ValidationError{Name: "topup_bonus_status", err: errors.New(`ent: missing required field "PromoCodeUsage.topup_bonus_status"`)}
+	}
+	if v, ok := _c.mutation.TopupBonusStatus(); ok {
+		if err := promocodeusage.TopupBonusStatusValidator(v); err != nil {
+			return &ValidationError{Name: "topup_bonus_status", err: fmt.Errorf(`ent: validator failed for field "PromoCodeUsage.topup_bonus_status": %w`, err)}
+		}
+	}
+	if _, ok := _c.mutation.TopupBonusAmount(); !ok {
+		return &ValidationError{Name: "topup_bonus_amount", err: errors.New(`ent: missing required field "PromoCodeUsage.topup_bonus_amount"`)}
+	}
 	if _, ok := _c.mutation.UsedAt(); !ok {
 		return &ValidationError{Name: "used_at", err: errors.New(`ent: missing required field "PromoCodeUsage.used_at"`)}
 	}
@@ -155,9 +217,21 @@ func (_c *PromoCodeUsageCreate) createSpec() (*PromoCodeUsage, *sqlgraph.CreateS
 	)
 	_spec.OnConflict = _c.conflict
 	if value, ok := _c.mutation.BonusAmount(); ok {
-		_spec.SetField(promocodeusage.FieldBonusAmount, field.TypeFloat64, value)
+		_spec.SetField(promocodeusage.FieldBonusAmount, field.TypeInt64, value)
 		_node.BonusAmount = value
 	}
+	if value, ok := _c.mutation.TopupBonusStatus(); ok {
+		_spec.SetField(promocodeusage.FieldTopupBonusStatus, field.TypeString, value)
+		_node.TopupBonusStatus = value
+	}
+	if value, ok := _c.mutation.TopupBonusAmount(); ok {
+		_spec.SetField(promocodeusage.FieldTopupBonusAmount, field.TypeInt64, value)
+		_node.TopupBonusAmount = value
+	}
+	if value, ok := _c.mutation.TopupGrantedAt(); ok {
+		_spec.SetField(promocodeusage.FieldTopupGrantedAt, field.TypeTime, value)
+		_node.TopupGrantedAt = &value
+	}
 	if value, ok := _c.mutation.UsedAt(); ok {
 		_spec.SetField(promocodeusage.FieldUsedAt, field.TypeTime, value)
 		_node.UsedAt = value
@@ -273,7 +347,7 @@ func (u *PromoCodeUsageUpsert) UpdateUserID() *PromoCodeUsageUpsert {
 }
 
 // SetBonusAmount sets the "bonus_amount" field.
-func (u *PromoCodeUsageUpsert) SetBonusAmount(v float64) *PromoCodeUsageUpsert {
+func (u *PromoCodeUsageUpsert) SetBonusAmount(v money.Amount) *PromoCodeUsageUpsert {
 	u.Set(promocodeusage.FieldBonusAmount, v)
 	return u
 }
@@ -285,11 +359,59 @@ func (u *PromoCodeUsageUpsert) UpdateBonusAmount() *PromoCodeUsageUpsert {
 }
 
 // AddBonusAmount adds v to the "bonus_amount" field.
-func (u *PromoCodeUsageUpsert) AddBonusAmount(v float64) *PromoCodeUsageUpsert {
+func (u *PromoCodeUsageUpsert) AddBonusAmount(v money.Amount) *PromoCodeUsageUpsert {
 	u.Add(promocodeusage.FieldBonusAmount, v)
 	return u
 }
 
+// SetTopupBonusStatus sets the "topup_bonus_status" field.
+func (u *PromoCodeUsageUpsert) SetTopupBonusStatus(v string) *PromoCodeUsageUpsert {
+	u.Set(promocodeusage.FieldTopupBonusStatus, v)
+	return u
+}
+
+// UpdateTopupBonusStatus sets the "topup_bonus_status" field to the value that was provided on create.
+func (u *PromoCodeUsageUpsert) UpdateTopupBonusStatus() *PromoCodeUsageUpsert {
+	u.SetExcluded(promocodeusage.FieldTopupBonusStatus)
+	return u
+}
+
+// SetTopupBonusAmount sets the "topup_bonus_amount" field.
+func (u *PromoCodeUsageUpsert) SetTopupBonusAmount(v money.Amount) *PromoCodeUsageUpsert {
+	u.Set(promocodeusage.FieldTopupBonusAmount, v)
+	return u
+}
+
+// UpdateTopupBonusAmount sets the "topup_bonus_amount" field to the value that was provided on create.
+func (u *PromoCodeUsageUpsert) UpdateTopupBonusAmount() *PromoCodeUsageUpsert {
+	u.SetExcluded(promocodeusage.FieldTopupBonusAmount)
+	return u
+}
+
+// AddTopupBonusAmount adds v to the "topup_bonus_amount" field.
+func (u *PromoCodeUsageUpsert) AddTopupBonusAmount(v money.Amount) *PromoCodeUsageUpsert {
+	u.Add(promocodeusage.FieldTopupBonusAmount, v)
+	return u
+}
+
+// SetTopupGrantedAt sets the "topup_granted_at" field.
+func (u *PromoCodeUsageUpsert) SetTopupGrantedAt(v time.Time) *PromoCodeUsageUpsert {
+	u.Set(promocodeusage.FieldTopupGrantedAt, v)
+	return u
+}
+
+// UpdateTopupGrantedAt sets the "topup_granted_at" field to the value that was provided on create.
+func (u *PromoCodeUsageUpsert) UpdateTopupGrantedAt() *PromoCodeUsageUpsert {
+	u.SetExcluded(promocodeusage.FieldTopupGrantedAt)
+	return u
+}
+
+// ClearTopupGrantedAt clears the value of the "topup_granted_at" field.
+func (u *PromoCodeUsageUpsert) ClearTopupGrantedAt() *PromoCodeUsageUpsert {
+	u.SetNull(promocodeusage.FieldTopupGrantedAt)
+	return u
+}
+
 // SetUsedAt sets the "used_at" field.
 func (u *PromoCodeUsageUpsert) SetUsedAt(v time.Time) *PromoCodeUsageUpsert {
 	u.Set(promocodeusage.FieldUsedAt, v)
@@ -371,14 +493,14 @@ func (u *PromoCodeUsageUpsertOne) UpdateUserID() *PromoCodeUsageUpsertOne {
 }
 
 // SetBonusAmount sets the "bonus_amount" field.
-func (u *PromoCodeUsageUpsertOne) SetBonusAmount(v float64) *PromoCodeUsageUpsertOne {
+func (u *PromoCodeUsageUpsertOne) SetBonusAmount(v money.Amount) *PromoCodeUsageUpsertOne {
 	return u.Update(func(s *PromoCodeUsageUpsert) {
 		s.SetBonusAmount(v)
 	})
 }
 
 // AddBonusAmount adds v to the "bonus_amount" field.
-func (u *PromoCodeUsageUpsertOne) AddBonusAmount(v float64) *PromoCodeUsageUpsertOne {
+func (u *PromoCodeUsageUpsertOne) AddBonusAmount(v money.Amount) *PromoCodeUsageUpsertOne {
 	return u.Update(func(s *PromoCodeUsageUpsert) {
 		s.AddBonusAmount(v)
 	})
@@ -391,6 +513,62 @@ func (u *PromoCodeUsageUpsertOne) UpdateBonusAmount() *PromoCodeUsageUpsertOne {
 	})
 }
 
+// SetTopupBonusStatus sets the "topup_bonus_status" field.
+func (u *PromoCodeUsageUpsertOne) SetTopupBonusStatus(v string) *PromoCodeUsageUpsertOne {
+	return u.Update(func(s *PromoCodeUsageUpsert) {
+		s.SetTopupBonusStatus(v)
+	})
+}
+
+// UpdateTopupBonusStatus sets the "topup_bonus_status" field to the value that was provided on create.
+func (u *PromoCodeUsageUpsertOne) UpdateTopupBonusStatus() *PromoCodeUsageUpsertOne {
+	return u.Update(func(s *PromoCodeUsageUpsert) {
+		s.UpdateTopupBonusStatus()
+	})
+}
+
+// SetTopupBonusAmount sets the "topup_bonus_amount" field.
+func (u *PromoCodeUsageUpsertOne) SetTopupBonusAmount(v money.Amount) *PromoCodeUsageUpsertOne {
+	return u.Update(func(s *PromoCodeUsageUpsert) {
+		s.SetTopupBonusAmount(v)
+	})
+}
+
+// AddTopupBonusAmount adds v to the "topup_bonus_amount" field.
+func (u *PromoCodeUsageUpsertOne) AddTopupBonusAmount(v money.Amount) *PromoCodeUsageUpsertOne {
+	return u.Update(func(s *PromoCodeUsageUpsert) {
+		s.AddTopupBonusAmount(v)
+	})
+}
+
+// UpdateTopupBonusAmount sets the "topup_bonus_amount" field to the value that was provided on create.
+func (u *PromoCodeUsageUpsertOne) UpdateTopupBonusAmount() *PromoCodeUsageUpsertOne {
+	return u.Update(func(s *PromoCodeUsageUpsert) {
+		s.UpdateTopupBonusAmount()
+	})
+}
+
+// SetTopupGrantedAt sets the "topup_granted_at" field.
+func (u *PromoCodeUsageUpsertOne) SetTopupGrantedAt(v time.Time) *PromoCodeUsageUpsertOne {
+	return u.Update(func(s *PromoCodeUsageUpsert) {
+		s.SetTopupGrantedAt(v)
+	})
+}
+
+// UpdateTopupGrantedAt sets the "topup_granted_at" field to the value that was provided on create.
+func (u *PromoCodeUsageUpsertOne) UpdateTopupGrantedAt() *PromoCodeUsageUpsertOne {
+	return u.Update(func(s *PromoCodeUsageUpsert) {
+		s.UpdateTopupGrantedAt()
+	})
+}
+
+// ClearTopupGrantedAt clears the value of the "topup_granted_at" field.
+func (u *PromoCodeUsageUpsertOne) ClearTopupGrantedAt() *PromoCodeUsageUpsertOne {
+	return u.Update(func(s *PromoCodeUsageUpsert) {
+		s.ClearTopupGrantedAt()
+	})
+}
+
 // SetUsedAt sets the "used_at" field.
 func (u *PromoCodeUsageUpsertOne) SetUsedAt(v time.Time) *PromoCodeUsageUpsertOne {
 	return u.Update(func(s *PromoCodeUsageUpsert) {
@@ -638,14 +816,14 @@ func (u *PromoCodeUsageUpsertBulk) UpdateUserID() *PromoCodeUsageUpsertBulk {
 }
 
 // SetBonusAmount sets the "bonus_amount" field.
-func (u *PromoCodeUsageUpsertBulk) SetBonusAmount(v float64) *PromoCodeUsageUpsertBulk {
+func (u *PromoCodeUsageUpsertBulk) SetBonusAmount(v money.Amount) *PromoCodeUsageUpsertBulk {
 	return u.Update(func(s *PromoCodeUsageUpsert) {
 		s.SetBonusAmount(v)
 	})
 }
 
 // AddBonusAmount adds v to the "bonus_amount" field.
-func (u *PromoCodeUsageUpsertBulk) AddBonusAmount(v float64) *PromoCodeUsageUpsertBulk {
+func (u *PromoCodeUsageUpsertBulk) AddBonusAmount(v money.Amount) *PromoCodeUsageUpsertBulk {
 	return u.Update(func(s *PromoCodeUsageUpsert) {
 		s.AddBonusAmount(v)
 	})
@@ -658,6 +836,62 @@ func (u *PromoCodeUsageUpsertBulk) UpdateBonusAmount() *PromoCodeUsageUpsertBulk
 	})
 }
 
+// SetTopupBonusStatus sets the "topup_bonus_status" field.
+func (u *PromoCodeUsageUpsertBulk) SetTopupBonusStatus(v string) *PromoCodeUsageUpsertBulk {
+	return u.Update(func(s *PromoCodeUsageUpsert) {
+		s.SetTopupBonusStatus(v)
+	})
+}
+
+// UpdateTopupBonusStatus sets the "topup_bonus_status" field to the value that was provided on create.
+func (u *PromoCodeUsageUpsertBulk) UpdateTopupBonusStatus() *PromoCodeUsageUpsertBulk {
+	return u.Update(func(s *PromoCodeUsageUpsert) {
+		s.UpdateTopupBonusStatus()
+	})
+}
+
+// SetTopupBonusAmount sets the "topup_bonus_amount" field.
+func (u *PromoCodeUsageUpsertBulk) SetTopupBonusAmount(v money.Amount) *PromoCodeUsageUpsertBulk {
+	return u.Update(func(s *PromoCodeUsageUpsert) {
+		s.SetTopupBonusAmount(v)
+	})
+}
+
+// AddTopupBonusAmount adds v to the "topup_bonus_amount" field.
+func (u *PromoCodeUsageUpsertBulk) AddTopupBonusAmount(v money.Amount) *PromoCodeUsageUpsertBulk {
+	return u.Update(func(s *PromoCodeUsageUpsert) {
+		s.AddTopupBonusAmount(v)
+	})
+}
+
+// UpdateTopupBonusAmount sets the "topup_bonus_amount" field to the value that was provided on create.
+func (u *PromoCodeUsageUpsertBulk) UpdateTopupBonusAmount() *PromoCodeUsageUpsertBulk {
+	return u.Update(func(s *PromoCodeUsageUpsert) {
+		s.UpdateTopupBonusAmount()
+	})
+}
+
+// SetTopupGrantedAt sets the "topup_granted_at" field.
+func (u *PromoCodeUsageUpsertBulk) SetTopupGrantedAt(v time.Time) *PromoCodeUsageUpsertBulk {
+	return u.Update(func(s *PromoCodeUsageUpsert) {
+		s.SetTopupGrantedAt(v)
+	})
+}
+
+// UpdateTopupGrantedAt sets the "topup_granted_at" field to the value that was provided on create.
+func (u *PromoCodeUsageUpsertBulk) UpdateTopupGrantedAt() *PromoCodeUsageUpsertBulk {
+	return u.Update(func(s *PromoCodeUsageUpsert) {
+		s.UpdateTopupGrantedAt()
+	})
+}
+
+// ClearTopupGrantedAt clears the value of the "topup_granted_at" field.
+func (u *PromoCodeUsageUpsertBulk) ClearTopupGrantedAt() *PromoCodeUsageUpsertBulk {
+	return u.Update(func(s *PromoCodeUsageUpsert) {
+		s.ClearTopupGrantedAt()
+	})
+}
+
 // SetUsedAt sets the "used_at" field.
 func (u *PromoCodeUsageUpsertBulk) SetUsedAt(v time.Time) *PromoCodeUsageUpsertBulk {
 	return u.Update(func(s *PromoCodeUsageUpsert) {
@@ -15,6 +15,7 @@ import (
 	"github.com/Wei-Shaw/sub2api/ent/promocode"
 	"github.com/Wei-Shaw/sub2api/ent/promocodeusage"
 	"github.com/Wei-Shaw/sub2api/ent/user"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
 )
 
 // PromoCodeUsageUpdate is the builder for updating PromoCodeUsage entities.
@@ -59,14 +60,14 @@ func (_u *PromoCodeUsageUpdate) SetNillableUserID(v *int64) *PromoCodeUsageUpdat
 }
 
 // SetBonusAmount sets the "bonus_amount" field.
-func (_u *PromoCodeUsageUpdate) SetBonusAmount(v float64) *PromoCodeUsageUpdate {
+func (_u *PromoCodeUsageUpdate) SetBonusAmount(v money.Amount) *PromoCodeUsageUpdate {
 	_u.mutation.ResetBonusAmount()
 	_u.mutation.SetBonusAmount(v)
 	return _u
 }
 
 // SetNillableBonusAmount sets the "bonus_amount" field if the given value is not nil.
-func (_u *PromoCodeUsageUpdate) SetNillableBonusAmount(v *float64) *PromoCodeUsageUpdate {
+func (_u *PromoCodeUsageUpdate) SetNillableBonusAmount(v *money.Amount) *PromoCodeUsageUpdate {
 	if v != nil {
 		_u.SetBonusAmount(*v)
 	}
@@ -74,11 +75,66 @@ func (_u *PromoCodeUsageUpdate) SetNillableBonusAmount(v *float64) *PromoCodeUsa
 }
 
 // AddBonusAmount adds value to the "bonus_amount" field.
-func (_u *PromoCodeUsageUpdate) AddBonusAmount(v float64) *PromoCodeUsageUpdate {
+func (_u *PromoCodeUsageUpdate) AddBonusAmount(v money.Amount) *PromoCodeUsageUpdate {
 	_u.mutation.AddBonusAmount(v)
 	return _u
 }
 
+// SetTopupBonusStatus sets the "topup_bonus_status" field.
+func (_u *PromoCodeUsageUpdate) SetTopupBonusStatus(v string) *PromoCodeUsageUpdate {
+	_u.mutation.SetTopupBonusStatus(v)
+	return _u
+}
+
+// SetNillableTopupBonusStatus sets the "topup_bonus_status" field if the given value is not nil.
+func (_u *PromoCodeUsageUpdate) SetNillableTopupBonusStatus(v *string) *PromoCodeUsageUpdate {
+	if v != nil {
+		_u.SetTopupBonusStatus(*v)
+	}
+	return _u
+}
+
+// SetTopupBonusAmount sets the "topup_bonus_amount" field.
+func (_u *PromoCodeUsageUpdate) SetTopupBonusAmount(v money.Amount) *PromoCodeUsageUpdate {
+	_u.mutation.ResetTopupBonusAmount()
+	_u.mutation.SetTopupBonusAmount(v)
+	return _u
+}
+
+// SetNillableTopupBonusAmount sets the "topup_bonus_amount" field if the given value is not nil.
+func (_u *PromoCodeUsageUpdate) SetNillableTopupBonusAmount(v *money.Amount) *PromoCodeUsageUpdate {
+	if v != nil {
+		_u.SetTopupBonusAmount(*v)
+	}
+	return _u
+}
+
+// AddTopupBonusAmount adds value to the "topup_bonus_amount" field.
+func (_u *PromoCodeUsageUpdate) AddTopupBonusAmount(v money.Amount) *PromoCodeUsageUpdate {
+	_u.mutation.AddTopupBonusAmount(v)
+	return _u
+}
+
+// SetTopupGrantedAt sets the "topup_granted_at" field.
+func (_u *PromoCodeUsageUpdate) SetTopupGrantedAt(v time.Time) *PromoCodeUsageUpdate {
+	_u.mutation.SetTopupGrantedAt(v)
+	return _u
+}
+
+// SetNillableTopupGrantedAt sets the "topup_granted_at" field if the given value is not nil.
+func (_u *PromoCodeUsageUpdate) SetNillableTopupGrantedAt(v *time.Time) *PromoCodeUsageUpdate {
+	if v != nil {
+		_u.SetTopupGrantedAt(*v)
+	}
+	return _u
+}
+
+// ClearTopupGrantedAt clears the value of the "topup_granted_at" field.
+func (_u *PromoCodeUsageUpdate) ClearTopupGrantedAt() *PromoCodeUsageUpdate {
+	_u.mutation.ClearTopupGrantedAt()
+	return _u
+}
+
 // SetUsedAt sets the "used_at" field.
 func (_u *PromoCodeUsageUpdate) SetUsedAt(v time.Time) *PromoCodeUsageUpdate {
 	_u.mutation.SetUsedAt(v)
@@ -149,6 +205,11 @@ func (_u *PromoCodeUsageUpdate) ExecX(ctx context.Context) {
 
 // check runs all checks and user-defined validators on the builder.
 func (_u *PromoCodeUsageUpdate) check() error {
+	if v, ok := _u.mutation.TopupBonusStatus(); ok {
+		if err := promocodeusage.TopupBonusStatusValidator(v); err != nil {
+			return &ValidationError{Name: "topup_bonus_status", err: fmt.Errorf(`ent: validator failed for field "PromoCodeUsage.topup_bonus_status": %w`, err)}
+		}
+	}
 	if _u.mutation.PromoCodeCleared() && len(_u.mutation.PromoCodeIDs()) > 0 {
 		return errors.New(`ent: clearing a required unique edge "PromoCodeUsage.promo_code"`)
 	}
@@ -171,10 +232,25 @@ func (_u *PromoCodeUsageUpdate) sqlSave(ctx context.Context) (_node int, err err
 		}
 	}
 	if value, ok := _u.mutation.BonusAmount(); ok {
-		_spec.SetField(promocodeusage.FieldBonusAmount, field.TypeFloat64, value)
+		_spec.SetField(promocodeusage.FieldBonusAmount, field.TypeInt64, value)
 	}
 	if value, ok := _u.mutation.AddedBonusAmount(); ok {
-		_spec.AddField(promocodeusage.FieldBonusAmount, field.TypeFloat64, value)
+		_spec.AddField(promocodeusage.FieldBonusAmount, field.TypeInt64, value)
+	}
+	if value, ok := _u.mutation.TopupBonusStatus(); ok {
+		_spec.SetField(promocodeusage.FieldTopupBonusStatus, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.TopupBonusAmount(); ok {
+		_spec.SetField(promocodeusage.FieldTopupBonusAmount, field.TypeInt64, value)
+	}
+	if value, ok := _u.mutation.AddedTopupBonusAmount(); ok {
+		_spec.AddField(promocodeusage.FieldTopupBonusAmount, field.TypeInt64, value)
+	}
+	if value, ok := _u.mutation.TopupGrantedAt(); ok {
+		_spec.SetField(promocodeusage.FieldTopupGrantedAt, field.TypeTime, value)
+	}
+	if _u.mutation.TopupGrantedAtCleared() {
+		_spec.ClearField(promocodeusage.FieldTopupGrantedAt, field.TypeTime)
 	}
 	if value, ok := _u.mutation.UsedAt(); ok {
 		_spec.SetField(promocodeusage.FieldUsedAt, field.TypeTime, value)
@@ -286,14 +362,14 @@ func (_u *PromoCodeUsageUpdateOne) SetNillableUserID(v *int64) *PromoCodeUsageUp
 }
 
 // SetBonusAmount sets the "bonus_amount" field.
-func (_u *PromoCodeUsageUpdateOne) SetBonusAmount(v float64) *PromoCodeUsageUpdateOne {
+func (_u *PromoCodeUsageUpdateOne) SetBonusAmount(v money.Amount) *PromoCodeUsageUpdateOne {
 	_u.mutation.ResetBonusAmount()
 	_u.mutation.SetBonusAmount(v)
 	return _u
 }
 
 // SetNillableBonusAmount sets the "bonus_amount" field if the given value is not nil.
-func (_u *PromoCodeUsageUpdateOne) SetNillableBonusAmount(v *float64) *PromoCodeUsageUpdateOne {
+func (_u *PromoCodeUsageUpdateOne) SetNillableBonusAmount(v *money.Amount) *PromoCodeUsageUpdateOne {
 	if v != nil {
 		_u.SetBonusAmount(*v)
 	}
@@ -301,11 +377,66 @@ func (_u *PromoCodeUsageUpdateOne) SetNillableBonusAmount(v *float64) *PromoCode
 }
 
 // AddBonusAmount adds value to the "bonus_amount" field.
-func (_u *PromoCodeUsageUpdateOne) AddBonusAmount(v float64) *PromoCodeUsageUpdateOne {
+func (_u *PromoCodeUsageUpdateOne) AddBonusAmount(v money.Amount) *PromoCodeUsageUpdateOne {
 	_u.mutation.AddBonusAmount(v)
 	return _u
 }
 
+// SetTopupBonusStatus sets the "topup_bonus_status" field.
+func (_u *PromoCodeUsageUpdateOne) SetTopupBonusStatus(v string) *PromoCodeUsageUpdateOne {
+	_u.mutation.SetTopupBonusStatus(v)
+	return _u
+}
+
+// SetNillableTopupBonusStatus sets the "topup_bonus_status" field if the given value is not nil.
+func (_u *PromoCodeUsageUpdateOne) SetNillableTopupBonusStatus(v *string) *PromoCodeUsageUpdateOne {
+	if v != nil {
+		_u.SetTopupBonusStatus(*v)
+	}
+	return _u
+}
+
+// SetTopupBonusAmount sets the "topup_bonus_amount" field.
+func (_u *PromoCodeUsageUpdateOne) SetTopupBonusAmount(v money.Amount) *PromoCodeUsageUpdateOne {
+	_u.mutation.ResetTopupBonusAmount()
+	_u.mutation.SetTopupBonusAmount(v)
+	return _u
+}
+
+// SetNillableTopupBonusAmount sets the "topup_bonus_amount" field if the given value is not nil.
+func (_u *PromoCodeUsageUpdateOne) SetNillableTopupBonusAmount(v *money.Amount) *PromoCodeUsageUpdateOne {
+	if v != nil {
+		_u.SetTopupBonusAmount(*v)
+	}
+	return _u
+}
+
+// AddTopupBonusAmount adds value to the "topup_bonus_amount" field.
+func (_u *PromoCodeUsageUpdateOne) AddTopupBonusAmount(v money.Amount) *PromoCodeUsageUpdateOne {
+	_u.mutation.AddTopupBonusAmount(v)
+	return _u
+}
+
+// SetTopupGrantedAt sets the "topup_granted_at" field.
+func (_u *PromoCodeUsageUpdateOne) SetTopupGrantedAt(v time.Time) *PromoCodeUsageUpdateOne {
+	_u.mutation.SetTopupGrantedAt(v)
+	return _u
+}
+
+// SetNillableTopupGrantedAt sets the "topup_granted_at" field if the given value is not nil.
+func (_u *PromoCodeUsageUpdateOne) SetNillableTopupGrantedAt(v *time.Time) *PromoCodeUsageUpdateOne {
+	if v != nil {
+		_u.SetTopupGrantedAt(*v)
+	}
+	return _u
+}
+
+// ClearTopupGrantedAt clears the value of the "topup_granted_at" field.
+func (_u *PromoCodeUsageUpdateOne) ClearTopupGrantedAt() *PromoCodeUsageUpdateOne {
+	_u.mutation.ClearTopupGrantedAt()
+	return _u
+}
+
 // SetUsedAt sets the "used_at" field.
 func (_u *PromoCodeUsageUpdateOne) SetUsedAt(v time.Time) *PromoCodeUsageUpdateOne {
 	_u.mutation.SetUsedAt(v)
@@ -389,6 +520,11 @@ func (_u *PromoCodeUsageUpdateOne) ExecX(ctx context.Context) {
 
 // check runs all checks and user-defined validators on the builder.
 func (_u *PromoCodeUsageUpdateOne) check() error {
+	if v, ok := _u.mutation.TopupBonusStatus(); ok {
+		if err := promocodeusage.TopupBonusStatusValidator(v); err != nil {
+			return &ValidationError{Name: "topup_bonus_status", err: fmt.Errorf(`ent: validator failed for field "PromoCodeUsage.topup_bonus_status": %w`, err)}
+		}
+	}
 	if _u.mutation.PromoCodeCleared() && len(_u.mutation.PromoCodeIDs()) > 0 {
 		return errors.New(`ent: clearing a required unique edge "PromoCodeUsage.promo_code"`)
 	}
@@ -428,10 +564,25 @@ func (_u *PromoCodeUsageUpdateOne) sqlSave(ctx context.Context) (_node *PromoCod
 		}
 	}
 	if value, ok := _u.mutation.BonusAmount(); ok {
-		_spec.SetField(promocodeusage.FieldBonusAmount, field.TypeFloat64, value)
+		_spec.SetField(promocodeusage.FieldBonusAmount, field.TypeInt64, value)
 	}
 	if value, ok := _u.mutation.AddedBonusAmount(); ok {
-		_spec.AddField(promocodeusage.FieldBonusAmount, field.TypeFloat64, value)
+		_spec.AddField(promocodeusage.FieldBonusAmount, field.TypeInt64, value)
+	}
+	if value, ok := _u.mutation.TopupBonusStatus(); ok {
+		_spec.SetField(promocodeusage.FieldTopupBonusStatus, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.TopupBonusAmount(); ok {
+		_spec.SetField(promocodeusage.FieldTopupBonusAmount, field.TypeInt64, value)
+	}
+	if value, ok := _u.mutation.AddedTopupBonusAmount(); ok {
+		_spec.AddField(promocodeusage.FieldTopupBonusAmount, field.TypeInt64, value)
+	}
+	if value, ok := _u.mutation.TopupGrantedAt(); ok {
+		_spec.SetField(promocodeusage.FieldTopupGrantedAt, field.TypeTime, value)
+	}
+	if _u.mutation.TopupGrantedAtCleared() {
+		_spec.ClearField(promocodeusage.FieldTopupGrantedAt, field.TypeTime)
 	}
 	if value, ok := _u.mutation.UsedAt(); ok {
 		_spec.SetField(promocodeusage.FieldUsedAt, field.TypeTime, value)
@@ -12,6 +12,7 @@ import (
 	"github.com/Wei-Shaw/sub2api/ent/group"
 	"github.com/Wei-Shaw/sub2api/ent/redeemcode"
 	"github.com/Wei-Shaw/sub2api/ent/user"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
 )
 
 // RedeemCode is the model entity for the RedeemCode schema.
@@ -24,7 +25,7 @@ type RedeemCode struct {
 	// Type holds the value of the "type" field.
 	Type string `json:"type,omitempty"`
 	// Value holds the value of the "value" field.
-	Value float64 `json:"value,omitempty"`
+	Value money.Amount `json:"value,omitempty"`
 	// Status holds the value of the "status" field.
 	Status string `json:"status,omitempty"`
 	// UsedBy holds the value of the "used_by" field.
@@ -84,7 +85,7 @@ func (*RedeemCode) scanValues(columns []string) ([]any, error) {
 	for i := range columns {
 		switch columns[i] {
 		case redeemcode.FieldValue:
-			values[i] = new(sql.NullFloat64)
+			values[i] = new(money.Amount)
 		case redeemcode.FieldID, redeemcode.FieldUsedBy, redeemcode.FieldGroupID, redeemcode.FieldValidityDays:
 			values[i] = new(sql.NullInt64)
 		case redeemcode.FieldCode, redeemcode.FieldType, redeemcode.FieldStatus, redeemcode.FieldNotes:
@@ -125,10 +126,10 @@ func (_m *RedeemCode) assignValues(columns []string, values []any) error {
 				_m.Type = value.String
 			}
 		case redeemcode.FieldValue:
-			if value, ok := values[i].(*sql.NullFloat64); !ok {
+			if value, ok := values[i].(*money.Amount); !ok {
 				return fmt.Errorf("unexpected type %T for field value", values[i])
-			} else if value.Valid {
-				_m.Value = value.Float64
+			} else if value != nil {
+				_m.Value = *value
 			}
 		case redeemcode.FieldStatus:
 			if value, ok := values[i].(*sql.NullString); !ok {
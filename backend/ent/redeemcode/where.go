@@ -8,6 +8,7 @@ import (
 	"entgo.io/ent/dialect/sql"
 	"entgo.io/ent/dialect/sql/sqlgraph"
 	"github.com/Wei-Shaw/sub2api/ent/predicate"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
 )
 
 // ID filters vertices based on their ID field.
@@ -66,7 +67,7 @@ func Type(v string) predicate.RedeemCode {
 }
 
 // Value applies equality check predicate on the "value" field. It's identical to ValueEQ.
-func Value(v float64) predicate.RedeemCode {
+func Value(v money.Amount) predicate.RedeemCode {
 	return predicate.RedeemCode(sql.FieldEQ(FieldValue, v))
 }
 
@@ -236,42 +237,42 @@ func TypeContainsFold(v string) predicate.RedeemCode {
 }
 
 // ValueEQ applies the EQ predicate on the "value" field.
-func ValueEQ(v float64) predicate.RedeemCode {
+func ValueEQ(v money.Amount) predicate.RedeemCode {
 	return predicate.RedeemCode(sql.FieldEQ(FieldValue, v))
 }
 
 // ValueNEQ applies the NEQ predicate on the "value" field.
-func ValueNEQ(v float64) predicate.RedeemCode {
+func ValueNEQ(v money.Amount) predicate.RedeemCode {
 	return predicate.RedeemCode(sql.FieldNEQ(FieldValue, v))
 }
 
 // ValueIn applies the In predicate on the "value" field.
-func ValueIn(vs ...float64) predicate.RedeemCode {
+func ValueIn(vs ...money.Amount) predicate.RedeemCode {
 	return predicate.RedeemCode(sql.FieldIn(FieldValue, vs...))
 }
 
 // ValueNotIn applies the NotIn predicate on the "value" field.
-func ValueNotIn(vs ...float64) predicate.RedeemCode {
+func ValueNotIn(vs ...money.Amount) predicate.RedeemCode {
 	return predicate.RedeemCode(sql.FieldNotIn(FieldValue, vs...))
 }
 
 // ValueGT applies the GT predicate on the "value" field.
-func ValueGT(v float64) predicate.RedeemCode {
+func ValueGT(v money.Amount) predicate.RedeemCode {
 	return predicate.RedeemCode(sql.FieldGT(FieldValue, v))
 }
 
 // ValueGTE applies the GTE predicate on the "value" field.
-func ValueGTE(v float64) predicate.RedeemCode {
+func ValueGTE(v money.Amount) predicate.RedeemCode {
 	return predicate.RedeemCode(sql.FieldGTE(FieldValue, v))
 }
 
 // ValueLT applies the LT predicate on the "value" field.
-func ValueLT(v float64) predicate.RedeemCode {
+func ValueLT(v money.Amount) predicate.RedeemCode {
 	return predicate.RedeemCode(sql.FieldLT(FieldValue, v))
 }
 
 // ValueLTE applies the LTE predicate on the "value" field.
-func ValueLTE(v float64) predicate.RedeemCode {
+func ValueLTE(v money.Amount) predicate.RedeemCode {
 	return predicate.RedeemCode(sql.FieldLTE(FieldValue, v))
 }
 
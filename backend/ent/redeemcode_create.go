@@ -14,6 +14,7 @@ import (
 	"github.com/Wei-Shaw/sub2api/ent/group"
 	"github.com/Wei-Shaw/sub2api/ent/redeemcode"
 	"github.com/Wei-Shaw/sub2api/ent/user"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
 )
 
 // RedeemCodeCreate is the builder for creating a RedeemCode entity.
@@ -45,13 +46,13 @@ func (_c *RedeemCodeCreate) SetNillableType(v *string) *RedeemCodeCreate {
 }
 
 // SetValue sets the "value" field.
-func (_c *RedeemCodeCreate) SetValue(v float64) *RedeemCodeCreate {
+func (_c *RedeemCodeCreate) SetValue(v money.Amount) *RedeemCodeCreate {
 	_c.mutation.SetValue(v)
 	return _c
 }
 
 // SetNillableValue sets the "value" field if the given value is not nil.
-func (_c *RedeemCodeCreate) SetNillableValue(v *float64) *RedeemCodeCreate {
+func (_c *RedeemCodeCreate) SetNillableValue(v *money.Amount) *RedeemCodeCreate {
 	if v != nil {
 		_c.SetValue(*v)
 	}
@@ -308,7 +309,7 @@ func (_c *RedeemCodeCreate) createSpec() (*RedeemCode, *sqlgraph.CreateSpec) {
 		_node.Type = value
 	}
 	if value, ok := _c.mutation.Value(); ok {
-		_spec.SetField(redeemcode.FieldValue, field.TypeFloat64, value)
+		_spec.SetField(redeemcode.FieldValue, field.TypeInt64, value)
 		_node.Value = value
 	}
 	if value, ok := _c.mutation.Status(); ok {
@@ -442,7 +443,7 @@ func (u *RedeemCodeUpsert) UpdateType() *RedeemCodeUpsert {
 }
 
 // SetValue sets the "value" field.
-func (u *RedeemCodeUpsert) SetValue(v float64) *RedeemCodeUpsert {
+func (u *RedeemCodeUpsert) SetValue(v money.Amount) *RedeemCodeUpsert {
 	u.Set(redeemcode.FieldValue, v)
 	return u
 }
@@ -454,7 +455,7 @@ func (u *RedeemCodeUpsert) UpdateValue() *RedeemCodeUpsert {
 }
 
 // AddValue adds v to the "value" field.
-func (u *RedeemCodeUpsert) AddValue(v float64) *RedeemCodeUpsert {
+func (u *RedeemCodeUpsert) AddValue(v money.Amount) *RedeemCodeUpsert {
 	u.Add(redeemcode.FieldValue, v)
 	return u
 }
@@ -635,14 +636,14 @@ func (u *RedeemCodeUpsertOne) UpdateType() *RedeemCodeUpsertOne {
 }
 
 // SetValue sets the "value" field.
-func (u *RedeemCodeUpsertOne) SetValue(v float64) *RedeemCodeUpsertOne {
+func (u *RedeemCodeUpsertOne) SetValue(v money.Amount) *RedeemCodeUpsertOne {
 	return u.Update(func(s *RedeemCodeUpsert) {
 		s.SetValue(v)
 	})
 }
 
 // AddValue adds v to the "value" field.
-func (u *RedeemCodeUpsertOne) AddValue(v float64) *RedeemCodeUpsertOne {
+func (u *RedeemCodeUpsertOne) AddValue(v money.Amount) *RedeemCodeUpsertOne {
 	return u.Update(func(s *RedeemCodeUpsert) {
 		s.AddValue(v)
 	})
@@ -1014,14 +1015,14 @@ func (u *RedeemCodeUpsertBulk) UpdateType() *RedeemCodeUpsertBulk {
 }
 
 // SetValue sets the "value" field.
-func (u *RedeemCodeUpsertBulk) SetValue(v float64) *RedeemCodeUpsertBulk {
+func (u *RedeemCodeUpsertBulk) SetValue(v money.Amount) *RedeemCodeUpsertBulk {
 	return u.Update(func(s *RedeemCodeUpsert) {
 		s.SetValue(v)
 	})
 }
 
 // AddValue adds v to the "value" field.
-func (u *RedeemCodeUpsertBulk) AddValue(v float64) *RedeemCodeUpsertBulk {
+func (u *RedeemCodeUpsertBulk) AddValue(v money.Amount) *RedeemCodeUpsertBulk {
 	return u.Update(func(s *RedeemCodeUpsert) {
 		s.AddValue(v)
 	})
@@ -15,6 +15,7 @@ import (
 	"github.com/Wei-Shaw/sub2api/ent/predicate"
 	"github.com/Wei-Shaw/sub2api/ent/redeemcode"
 	"github.com/Wei-Shaw/sub2api/ent/user"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
 )
 
 // RedeemCodeUpdate is the builder for updating RedeemCode entities.
@@ -59,14 +60,14 @@ func (_u *RedeemCodeUpdate) SetNillableType(v *string) *RedeemCodeUpdate {
 }
 
 // SetValue sets the "value" field.
-func (_u *RedeemCodeUpdate) SetValue(v float64) *RedeemCodeUpdate {
+func (_u *RedeemCodeUpdate) SetValue(v money.Amount) *RedeemCodeUpdate {
 	_u.mutation.ResetValue()
 	_u.mutation.SetValue(v)
 	return _u
 }
 
 // SetNillableValue sets the "value" field if the given value is not nil.
-func (_u *RedeemCodeUpdate) SetNillableValue(v *float64) *RedeemCodeUpdate {
+func (_u *RedeemCodeUpdate) SetNillableValue(v *money.Amount) *RedeemCodeUpdate {
 	if v != nil {
 		_u.SetValue(*v)
 	}
@@ -74,7 +75,7 @@ func (_u *RedeemCodeUpdate) SetNillableValue(v *float64) *RedeemCodeUpdate {
 }
 
 // AddValue adds value to the "value" field.
-func (_u *RedeemCodeUpdate) AddValue(v float64) *RedeemCodeUpdate {
+func (_u *RedeemCodeUpdate) AddValue(v money.Amount) *RedeemCodeUpdate {
 	_u.mutation.AddValue(v)
 	return _u
 }
@@ -301,10 +302,10 @@ func (_u *RedeemCodeUpdate) sqlSave(ctx context.Context) (_node int, err error)
 		_spec.SetField(redeemcode.FieldType, field.TypeString, value)
 	}
 	if value, ok := _u.mutation.Value(); ok {
-		_spec.SetField(redeemcode.FieldValue, field.TypeFloat64, value)
+		_spec.SetField(redeemcode.FieldValue, field.TypeInt64, value)
 	}
 	if value, ok := _u.mutation.AddedValue(); ok {
-		_spec.AddField(redeemcode.FieldValue, field.TypeFloat64, value)
+		_spec.AddField(redeemcode.FieldValue, field.TypeInt64, value)
 	}
 	if value, ok := _u.mutation.Status(); ok {
 		_spec.SetField(redeemcode.FieldStatus, field.TypeString, value)
@@ -434,14 +435,14 @@ func (_u *RedeemCodeUpdateOne) SetNillableType(v *string) *RedeemCodeUpdateOne {
 }
 
 // SetValue sets the "value" field.
-func (_u *RedeemCodeUpdateOne) SetValue(v float64) *RedeemCodeUpdateOne {
+func (_u *RedeemCodeUpdateOne) SetValue(v money.Amount) *RedeemCodeUpdateOne {
 	_u.mutation.ResetValue()
 	_u.mutation.SetValue(v)
 	return _u
 }
 
 // SetNillableValue sets the "value" field if the given value is not nil.
-func (_u *RedeemCodeUpdateOne) SetNillableValue(v *float64) *RedeemCodeUpdateOne {
+func (_u *RedeemCodeUpdateOne) SetNillableValue(v *money.Amount) *RedeemCodeUpdateOne {
 	if v != nil {
 		_u.SetValue(*v)
 	}
@@ -449,7 +450,7 @@ func (_u *RedeemCodeUpdateOne) SetNillableValue(v *float64) *RedeemCodeUpdateOne
 }
 
 // AddValue adds value to the "value" field.
-func (_u *RedeemCodeUpdateOne) AddValue(v float64) *RedeemCodeUpdateOne {
+func (_u *RedeemCodeUpdateOne) AddValue(v money.Amount) *RedeemCodeUpdateOne {
 	_u.mutation.AddValue(v)
 	return _u
 }
@@ -706,10 +707,10 @@ func (_u *RedeemCodeUpdateOne) sqlSave(ctx context.Context) (_node *RedeemCode,
 		_spec.SetField(redeemcode.FieldType, field.TypeString, value)
 	}
 	if value, ok := _u.mutation.Value(); ok {
-		_spec.SetField(redeemcode.FieldValue, field.TypeFloat64, value)
+		_spec.SetField(redeemcode.FieldValue, field.TypeInt64, value)
 	}
 	if value, ok := _u.mutation.AddedValue(); ok {
-		_spec.AddField(redeemcode.FieldValue, field.TypeFloat64, value)
+		_spec.AddField(redeemcode.FieldValue, field.TypeInt64, value)
 	}
 	if value, ok := _u.mutation.Status(); ok {
 		_spec.SetField(redeemcode.FieldStatus, field.TypeString, value)
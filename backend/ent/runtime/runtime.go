@@ -30,6 +30,7 @@ import (
 	"github.com/Wei-Shaw/sub2api/ent/userattributedefinition"
 	"github.com/Wei-Shaw/sub2api/ent/userattributevalue"
 	"github.com/Wei-Shaw/sub2api/ent/usersubscription"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
 )
 
 // The init function reads all schema descriptors with runtime code
@@ -98,37 +99,49 @@ func init() {
 	// apikey.StatusValidator is a validator for the "status" field. It is called by the builders before save.
 	apikey.StatusValidator = apikeyDescStatus.Validators[0].(func(string) error)
 	// apikeyDescQuota is the schema descriptor for quota field.
-	apikeyDescQuota := apikeyFields[8].Descriptor()
+	apikeyDescQuota := apikeyFields[10].Descriptor()
 	// apikey.DefaultQuota holds the default value on creation for the quota field.
-	apikey.DefaultQuota = apikeyDescQuota.Default.(float64)
+	apikey.DefaultQuota = money.Amount(apikeyDescQuota.Default.(int64))
 	// apikeyDescQuotaUsed is the schema descriptor for quota_used field.
-	apikeyDescQuotaUsed := apikeyFields[9].Descriptor()
+	apikeyDescQuotaUsed := apikeyFields[11].Descriptor()
 	// apikey.DefaultQuotaUsed holds the default value on creation for the quota_used field.
-	apikey.DefaultQuotaUsed = apikeyDescQuotaUsed.Default.(float64)
+	apikey.DefaultQuotaUsed = money.Amount(apikeyDescQuotaUsed.Default.(int64))
 	// apikeyDescRateLimit5h is the schema descriptor for rate_limit_5h field.
-	apikeyDescRateLimit5h := apikeyFields[11].Descriptor()
+	apikeyDescRateLimit5h := apikeyFields[13].Descriptor()
 	// apikey.DefaultRateLimit5h holds the default value on creation for the rate_limit_5h field.
-	apikey.DefaultRateLimit5h = apikeyDescRateLimit5h.Default.(float64)
+	apikey.DefaultRateLimit5h = money.Amount(apikeyDescRateLimit5h.Default.(int64))
 	// apikeyDescRateLimit1d is the schema descriptor for rate_limit_1d field.
-	apikeyDescRateLimit1d := apikeyFields[12].Descriptor()
+	apikeyDescRateLimit1d := apikeyFields[14].Descriptor()
 	// apikey.DefaultRateLimit1d holds the default value on creation for the rate_limit_1d field.
-	apikey.DefaultRateLimit1d = apikeyDescRateLimit1d.Default.(float64)
+	apikey.DefaultRateLimit1d = money.Amount(apikeyDescRateLimit1d.Default.(int64))
 	// apikeyDescRateLimit7d is the schema descriptor for rate_limit_7d field.
-	apikeyDescRateLimit7d := apikeyFields[13].Descriptor()
+	apikeyDescRateLimit7d := apikeyFields[15].Descriptor()
 	// apikey.DefaultRateLimit7d holds the default value on creation for the rate_limit_7d field.
-	apikey.DefaultRateLimit7d = apikeyDescRateLimit7d.Default.(float64)
+	apikey.DefaultRateLimit7d = money.Amount(apikeyDescRateLimit7d.Default.(int64))
 	// apikeyDescUsage5h is the schema descriptor for usage_5h field.
-	apikeyDescUsage5h := apikeyFields[14].Descriptor()
+	apikeyDescUsage5h := apikeyFields[16].Descriptor()
 	// apikey.DefaultUsage5h holds the default value on creation for the usage_5h field.
-	apikey.DefaultUsage5h = apikeyDescUsage5h.Default.(float64)
+	apikey.DefaultUsage5h = money.Amount(apikeyDescUsage5h.Default.(int64))
 	// apikeyDescUsage1d is the schema descriptor for usage_1d field.
-	apikeyDescUsage1d := apikeyFields[15].Descriptor()
+	apikeyDescUsage1d := apikeyFields[17].Descriptor()
 	// apikey.DefaultUsage1d holds the default value on creation for the usage_1d field.
-	apikey.DefaultUsage1d = apikeyDescUsage1d.Default.(float64)
+	apikey.DefaultUsage1d = money.Amount(apikeyDescUsage1d.Default.(int64))
 	// apikeyDescUsage7d is the schema descriptor for usage_7d field.
-	apikeyDescUsage7d := apikeyFields[16].Descriptor()
+	apikeyDescUsage7d := apikeyFields[18].Descriptor()
 	// apikey.DefaultUsage7d holds the default value on creation for the usage_7d field.
-	apikey.DefaultUsage7d = apikeyDescUsage7d.Default.(float64)
+	apikey.DefaultUsage7d = money.Amount(apikeyDescUsage7d.Default.(int64))
+	// apikeyDescRpmLimit is the schema descriptor for rpm_limit field.
+	apikeyDescRpmLimit := apikeyFields[22].Descriptor()
+	// apikey.DefaultRpmLimit holds the default value on creation for the rpm_limit field.
+	apikey.DefaultRpmLimit = apikeyDescRpmLimit.Default.(int)
+	// apikeyDescTpmLimit is the schema descriptor for tpm_limit field.
+	apikeyDescTpmLimit := apikeyFields[23].Descriptor()
+	// apikey.DefaultTpmLimit holds the default value on creation for the tpm_limit field.
+	apikey.DefaultTpmLimit = apikeyDescTpmLimit.Default.(int)
+	// apikeyDescMaxConcurrency is the schema descriptor for max_concurrency field.
+	apikeyDescMaxConcurrency := apikeyFields[24].Descriptor()
+	// apikey.DefaultMaxConcurrency holds the default value on creation for the max_concurrency field.
+	apikey.DefaultMaxConcurrency = apikeyDescMaxConcurrency.Default.(int)
 	accountMixin := schema.Account{}.Mixin()
 	accountMixinHooks1 := accountMixin[1].Hooks()
 	account.Hooks[0] = accountMixinHooks1[0]
@@ -555,23 +568,23 @@ func init() {
 	// group.DefaultSoraStorageQuotaBytes holds the default value on creation for the sora_storage_quota_bytes field.
 	group.DefaultSoraStorageQuotaBytes = groupDescSoraStorageQuotaBytes.Default.(int64)
 	// groupDescClaudeCodeOnly is the schema descriptor for claude_code_only field.
-	groupDescClaudeCodeOnly := groupFields[21].Descriptor()
+	groupDescClaudeCodeOnly := groupFields[23].Descriptor()
 	// group.DefaultClaudeCodeOnly holds the default value on creation for the claude_code_only field.
 	group.DefaultClaudeCodeOnly = groupDescClaudeCodeOnly.Default.(bool)
 	// groupDescModelRoutingEnabled is the schema descriptor for model_routing_enabled field.
-	groupDescModelRoutingEnabled := groupFields[25].Descriptor()
+	groupDescModelRoutingEnabled := groupFields[27].Descriptor()
 	// group.DefaultModelRoutingEnabled holds the default value on creation for the model_routing_enabled field.
 	group.DefaultModelRoutingEnabled = groupDescModelRoutingEnabled.Default.(bool)
 	// groupDescMcpXMLInject is the schema descriptor for mcp_xml_inject field.
-	groupDescMcpXMLInject := groupFields[26].Descriptor()
+	groupDescMcpXMLInject := groupFields[28].Descriptor()
 	// group.DefaultMcpXMLInject holds the default value on creation for the mcp_xml_inject field.
 	group.DefaultMcpXMLInject = groupDescMcpXMLInject.Default.(bool)
 	// groupDescSupportedModelScopes is the schema descriptor for supported_model_scopes field.
-	groupDescSupportedModelScopes := groupFields[27].Descriptor()
+	groupDescSupportedModelScopes := groupFields[29].Descriptor()
 	// group.DefaultSupportedModelScopes holds the default value on creation for the supported_model_scopes field.
 	group.DefaultSupportedModelScopes = groupDescSupportedModelScopes.Default.([]string)
 	// groupDescSortOrder is the schema descriptor for sort_order field.
-	groupDescSortOrder := groupFields[28].Descriptor()
+	groupDescSortOrder := groupFields[30].Descriptor()
 	// group.DefaultSortOrder holds the default value on creation for the sort_order field.
 	group.DefaultSortOrder = groupDescSortOrder.Default.(int)
 	idempotencyrecordMixin := schema.IdempotencyRecord{}.Mixin()
@@ -632,7 +645,7 @@ func init() {
 	// promocodeDescBonusAmount is the schema descriptor for bonus_amount field.
 	promocodeDescBonusAmount := promocodeFields[1].Descriptor()
 	// promocode.DefaultBonusAmount holds the default value on creation for the bonus_amount field.
-	promocode.DefaultBonusAmount = promocodeDescBonusAmount.Default.(float64)
+	promocode.DefaultBonusAmount = money.Amount(promocodeDescBonusAmount.Default.(int64))
 	// promocodeDescMaxUses is the schema descriptor for max_uses field.
 	promocodeDescMaxUses := promocodeFields[2].Descriptor()
 	// promocode.DefaultMaxUses holds the default value on creation for the max_uses field.
@@ -641,26 +654,60 @@ func init() {
 	promocodeDescUsedCount := promocodeFields[3].Descriptor()
 	// promocode.DefaultUsedCount holds the default value on creation for the used_count field.
 	promocode.DefaultUsedCount = promocodeDescUsedCount.Default.(int)
+	// promocodeDescTopupBonusPercent is the schema descriptor for topup_bonus_percent field.
+	promocodeDescTopupBonusPercent := promocodeFields[4].Descriptor()
+	// promocode.DefaultTopupBonusPercent holds the default value on creation for the topup_bonus_percent field.
+	promocode.DefaultTopupBonusPercent = promocodeDescTopupBonusPercent.Default.(float64)
+	// promocodeDescTopupBonusMax is the schema descriptor for topup_bonus_max field.
+	promocodeDescTopupBonusMax := promocodeFields[5].Descriptor()
+	// promocode.DefaultTopupBonusMax holds the default value on creation for the topup_bonus_max field.
+	promocode.DefaultTopupBonusMax = money.Amount(promocodeDescTopupBonusMax.Default.(int64))
+	// promocodeDescMinTopupAmount is the schema descriptor for min_topup_amount field.
+	promocodeDescMinTopupAmount := promocodeFields[6].Descriptor()
+	// promocode.DefaultMinTopupAmount holds the default value on creation for the min_topup_amount field.
+	promocode.DefaultMinTopupAmount = money.Amount(promocodeDescMinTopupAmount.Default.(int64))
+	// promocodeDescPerUserLimit is the schema descriptor for per_user_limit field.
+	promocodeDescPerUserLimit := promocodeFields[7].Descriptor()
+	// promocode.DefaultPerUserLimit holds the default value on creation for the per_user_limit field.
+	promocode.DefaultPerUserLimit = promocodeDescPerUserLimit.Default.(int)
+	// promocodeDescStackable is the schema descriptor for stackable field.
+	promocodeDescStackable := promocodeFields[10].Descriptor()
+	// promocode.DefaultStackable holds the default value on creation for the stackable field.
+	promocode.DefaultStackable = promocodeDescStackable.Default.(bool)
+	// promocodeDescTrialDays is the schema descriptor for trial_days field.
+	promocodeDescTrialDays := promocodeFields[12].Descriptor()
+	// promocode.DefaultTrialDays holds the default value on creation for the trial_days field.
+	promocode.DefaultTrialDays = promocodeDescTrialDays.Default.(int)
 	// promocodeDescStatus is the schema descriptor for status field.
-	promocodeDescStatus := promocodeFields[4].Descriptor()
+	promocodeDescStatus := promocodeFields[13].Descriptor()
 	// promocode.DefaultStatus holds the default value on creation for the status field.
 	promocode.DefaultStatus = promocodeDescStatus.Default.(string)
 	// promocode.StatusValidator is a validator for the "status" field. It is called by the builders before save.
 	promocode.StatusValidator = promocodeDescStatus.Validators[0].(func(string) error)
 	// promocodeDescCreatedAt is the schema descriptor for created_at field.
-	promocodeDescCreatedAt := promocodeFields[7].Descriptor()
+	promocodeDescCreatedAt := promocodeFields[16].Descriptor()
 	// promocode.DefaultCreatedAt holds the default value on creation for the created_at field.
 	promocode.DefaultCreatedAt = promocodeDescCreatedAt.Default.(func() time.Time)
 	// promocodeDescUpdatedAt is the schema descriptor for updated_at field.
-	promocodeDescUpdatedAt := promocodeFields[8].Descriptor()
+	promocodeDescUpdatedAt := promocodeFields[17].Descriptor()
 	// promocode.DefaultUpdatedAt holds the default value on creation for the updated_at field.
 	promocode.DefaultUpdatedAt = promocodeDescUpdatedAt.Default.(func() time.Time)
 	// promocode.UpdateDefaultUpdatedAt holds the default value on update for the updated_at field.
 	promocode.UpdateDefaultUpdatedAt = promocodeDescUpdatedAt.UpdateDefault.(func() time.Time)
 	promocodeusageFields := schema.PromoCodeUsage{}.Fields()
 	_ = promocodeusageFields
+	// promocodeusageDescTopupBonusStatus is the schema descriptor for topup_bonus_status field.
+	promocodeusageDescTopupBonusStatus := promocodeusageFields[3].Descriptor()
+	// promocodeusage.DefaultTopupBonusStatus holds the default value on creation for the topup_bonus_status field.
+	promocodeusage.DefaultTopupBonusStatus = promocodeusageDescTopupBonusStatus.Default.(string)
+	// promocodeusage.TopupBonusStatusValidator is a validator for the "topup_bonus_status" field. It is called by the builders before save.
+	promocodeusage.TopupBonusStatusValidator = promocodeusageDescTopupBonusStatus.Validators[0].(func(string) error)
+	// promocodeusageDescTopupBonusAmount is the schema descriptor for topup_bonus_amount field.
+	promocodeusageDescTopupBonusAmount := promocodeusageFields[4].Descriptor()
+	// promocodeusage.DefaultTopupBonusAmount holds the default value on creation for the topup_bonus_amount field.
+	promocodeusage.DefaultTopupBonusAmount = money.Amount(promocodeusageDescTopupBonusAmount.Default.(int64))
 	// promocodeusageDescUsedAt is the schema descriptor for used_at field.
-	promocodeusageDescUsedAt := promocodeusageFields[3].Descriptor()
+	promocodeusageDescUsedAt := promocodeusageFields[6].Descriptor()
 	// promocodeusage.DefaultUsedAt holds the default value on creation for the used_at field.
 	promocodeusage.DefaultUsedAt = promocodeusageDescUsedAt.Default.(func() time.Time)
 	proxyMixin := schema.Proxy{}.Mixin()
@@ -740,10 +787,6 @@ func init() {
 	proxyDescUsername := proxyFields[4].Descriptor()
 	// proxy.UsernameValidator is a validator for the "username" field. It is called by the builders before save.
 	proxy.UsernameValidator = proxyDescUsername.Validators[0].(func(string) error)
-	// proxyDescPassword is the schema descriptor for password field.
-	proxyDescPassword := proxyFields[5].Descriptor()
-	// proxy.PasswordValidator is a validator for the "password" field. It is called by the builders before save.
-	proxy.PasswordValidator = proxyDescPassword.Validators[0].(func(string) error)
 	// proxyDescStatus is the schema descriptor for status field.
 	proxyDescStatus := proxyFields[6].Descriptor()
 	// proxy.DefaultStatus holds the default value on creation for the status field.
@@ -779,7 +822,7 @@ func init() {
 	// redeemcodeDescValue is the schema descriptor for value field.
 	redeemcodeDescValue := redeemcodeFields[2].Descriptor()
 	// redeemcode.DefaultValue holds the default value on creation for the value field.
-	redeemcode.DefaultValue = redeemcodeDescValue.Default.(float64)
+	redeemcode.DefaultValue = money.Amount(redeemcodeDescValue.Default.(int64))
 	// redeemcodeDescStatus is the schema descriptor for status field.
 	redeemcodeDescStatus := redeemcodeFields[3].Descriptor()
 	// redeemcode.DefaultStatus holds the default value on creation for the status field.
@@ -959,27 +1002,27 @@ func init() {
 	// usagelogDescInputCost is the schema descriptor for input_cost field.
 	usagelogDescInputCost := usagelogFields[13].Descriptor()
 	// usagelog.DefaultInputCost holds the default value on creation for the input_cost field.
-	usagelog.DefaultInputCost = usagelogDescInputCost.Default.(float64)
+	usagelog.DefaultInputCost = money.Amount(usagelogDescInputCost.Default.(int64))
 	// usagelogDescOutputCost is the schema descriptor for output_cost field.
 	usagelogDescOutputCost := usagelogFields[14].Descriptor()
 	// usagelog.DefaultOutputCost holds the default value on creation for the output_cost field.
-	usagelog.DefaultOutputCost = usagelogDescOutputCost.Default.(float64)
+	usagelog.DefaultOutputCost = money.Amount(usagelogDescOutputCost.Default.(int64))
 	// usagelogDescCacheCreationCost is the schema descriptor for cache_creation_cost field.
 	usagelogDescCacheCreationCost := usagelogFields[15].Descriptor()
 	// usagelog.DefaultCacheCreationCost holds the default value on creation for the cache_creation_cost field.
-	usagelog.DefaultCacheCreationCost = usagelogDescCacheCreationCost.Default.(float64)
+	usagelog.DefaultCacheCreationCost = money.Amount(usagelogDescCacheCreationCost.Default.(int64))
 	// usagelogDescCacheReadCost is the schema descriptor for cache_read_cost field.
 	usagelogDescCacheReadCost := usagelogFields[16].Descriptor()
 	// usagelog.DefaultCacheReadCost holds the default value on creation for the cache_read_cost field.
-	usagelog.DefaultCacheReadCost = usagelogDescCacheReadCost.Default.(float64)
+	usagelog.DefaultCacheReadCost = money.Amount(usagelogDescCacheReadCost.Default.(int64))
 	// usagelogDescTotalCost is the schema descriptor for total_cost field.
 	usagelogDescTotalCost := usagelogFields[17].Descriptor()
 	// usagelog.DefaultTotalCost holds the default value on creation for the total_cost field.
-	usagelog.DefaultTotalCost = usagelogDescTotalCost.Default.(float64)
+	usagelog.DefaultTotalCost = money.Amount(usagelogDescTotalCost.Default.(int64))
 	// usagelogDescActualCost is the schema descriptor for actual_cost field.
 	usagelogDescActualCost := usagelogFields[18].Descriptor()
 	// usagelog.DefaultActualCost holds the default value on creation for the actual_cost field.
-	usagelog.DefaultActualCost = usagelogDescActualCost.Default.(float64)
+	usagelog.DefaultActualCost = money.Amount(usagelogDescActualCost.Default.(int64))
 	// usagelogDescRateMultiplier is the schema descriptor for rate_multiplier field.
 	usagelogDescRateMultiplier := usagelogFields[19].Descriptor()
 	// usagelog.DefaultRateMultiplier holds the default value on creation for the rate_multiplier field.
@@ -1013,11 +1056,11 @@ func init() {
 	// usagelog.MediaTypeValidator is a validator for the "media_type" field. It is called by the builders before save.
 	usagelog.MediaTypeValidator = usagelogDescMediaType.Validators[0].(func(string) error)
 	// usagelogDescCacheTTLOverridden is the schema descriptor for cache_ttl_overridden field.
-	usagelogDescCacheTTLOverridden := usagelogFields[30].Descriptor()
+	usagelogDescCacheTTLOverridden := usagelogFields[32].Descriptor()
 	// usagelog.DefaultCacheTTLOverridden holds the default value on creation for the cache_ttl_overridden field.
 	usagelog.DefaultCacheTTLOverridden = usagelogDescCacheTTLOverridden.Default.(bool)
 	// usagelogDescCreatedAt is the schema descriptor for created_at field.
-	usagelogDescCreatedAt := usagelogFields[31].Descriptor()
+	usagelogDescCreatedAt := usagelogFields[33].Descriptor()
 	// usagelog.DefaultCreatedAt holds the default value on creation for the created_at field.
 	usagelog.DefaultCreatedAt = usagelogDescCreatedAt.Default.(func() time.Time)
 	userMixin := schema.User{}.Mixin()
@@ -1084,7 +1127,7 @@ func init() {
 	// userDescBalance is the schema descriptor for balance field.
 	userDescBalance := userFields[3].Descriptor()
 	// user.DefaultBalance holds the default value on creation for the balance field.
-	user.DefaultBalance = userDescBalance.Default.(float64)
+	user.DefaultBalance = money.Amount(userDescBalance.Default.(int64))
 	// userDescConcurrency is the schema descriptor for concurrency field.
 	userDescConcurrency := userFields[4].Descriptor()
 	// user.DefaultConcurrency holds the default value on creation for the concurrency field.
@@ -1273,15 +1316,15 @@ func init() {
 	// usersubscriptionDescDailyUsageUsd is the schema descriptor for daily_usage_usd field.
 	usersubscriptionDescDailyUsageUsd := usersubscriptionFields[8].Descriptor()
 	// usersubscription.DefaultDailyUsageUsd holds the default value on creation for the daily_usage_usd field.
-	usersubscription.DefaultDailyUsageUsd = usersubscriptionDescDailyUsageUsd.Default.(float64)
+	usersubscription.DefaultDailyUsageUsd = money.Amount(usersubscriptionDescDailyUsageUsd.Default.(int64))
 	// usersubscriptionDescWeeklyUsageUsd is the schema descriptor for weekly_usage_usd field.
 	usersubscriptionDescWeeklyUsageUsd := usersubscriptionFields[9].Descriptor()
 	// usersubscription.DefaultWeeklyUsageUsd holds the default value on creation for the weekly_usage_usd field.
-	usersubscription.DefaultWeeklyUsageUsd = usersubscriptionDescWeeklyUsageUsd.Default.(float64)
+	usersubscription.DefaultWeeklyUsageUsd = money.Amount(usersubscriptionDescWeeklyUsageUsd.Default.(int64))
 	// usersubscriptionDescMonthlyUsageUsd is the schema descriptor for monthly_usage_usd field.
 	usersubscriptionDescMonthlyUsageUsd := usersubscriptionFields[10].Descriptor()
 	// usersubscription.DefaultMonthlyUsageUsd holds the default value on creation for the monthly_usage_usd field.
-	usersubscription.DefaultMonthlyUsageUsd = usersubscriptionDescMonthlyUsageUsd.Default.(float64)
+	usersubscription.DefaultMonthlyUsageUsd = money.Amount(usersubscriptionDescMonthlyUsageUsd.Default.(int64))
 	// usersubscriptionDescAssignedAt is the schema descriptor for assigned_at field.
 	usersubscriptionDescAssignedAt := usersubscriptionFields[12].Descriptor()
 	// usersubscription.DefaultAssignedAt holds the default value on creation for the assigned_at field.
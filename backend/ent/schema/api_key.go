@@ -3,6 +3,7 @@ package schema
 import (
 	"github.com/Wei-Shaw/sub2api/ent/schema/mixins"
 	"github.com/Wei-Shaw/sub2api/internal/domain"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
 
 	"entgo.io/ent"
 	"entgo.io/ent/dialect"
@@ -57,16 +58,24 @@ func (APIKey) Fields() []ent.Field {
 		field.JSON("ip_blacklist", []string{}).
 			Optional().
 			Comment("Blocked IPs/CIDRs"),
+		field.JSON("model_allowlist", []string{}).
+			Optional().
+			Comment("Allowed model glob patterns, e.g. [\"claude-*-haiku-*\"] (empty = all models)"),
+		field.JSON("model_denylist", []string{}).
+			Optional().
+			Comment("Denied model glob patterns, checked before the allowlist"),
 
 		// ========== Quota fields ==========
 		// Quota limit in USD (0 = unlimited)
-		field.Float("quota").
-			SchemaType(map[string]string{dialect.Postgres: "decimal(20,8)"}).
+		field.Int64("quota").
+			GoType(money.Amount(0)).
+			SchemaType(map[string]string{dialect.Postgres: "decimal(20,10)"}).
 			Default(0).
 			Comment("Quota limit in USD for this API key (0 = unlimited)"),
 		// Used quota amount
-		field.Float("quota_used").
-			SchemaType(map[string]string{dialect.Postgres: "decimal(20,8)"}).
+		field.Int64("quota_used").
+			GoType(money.Amount(0)).
+			SchemaType(map[string]string{dialect.Postgres: "decimal(20,10)"}).
 			Default(0).
 			Comment("Used quota amount in USD"),
 		// Expiration time (nil = never expires)
@@ -77,29 +86,35 @@ func (APIKey) Fields() []ent.Field {
 
 		// ========== Rate limit fields ==========
 		// Rate limit configuration (0 = unlimited)
-		field.Float("rate_limit_5h").
-			SchemaType(map[string]string{dialect.Postgres: "decimal(20,8)"}).
+		field.Int64("rate_limit_5h").
+			GoType(money.Amount(0)).
+			SchemaType(map[string]string{dialect.Postgres: "decimal(20,10)"}).
 			Default(0).
 			Comment("Rate limit in USD per 5 hours (0 = unlimited)"),
-		field.Float("rate_limit_1d").
-			SchemaType(map[string]string{dialect.Postgres: "decimal(20,8)"}).
+		field.Int64("rate_limit_1d").
+			GoType(money.Amount(0)).
+			SchemaType(map[string]string{dialect.Postgres: "decimal(20,10)"}).
 			Default(0).
 			Comment("Rate limit in USD per day (0 = unlimited)"),
-		field.Float("rate_limit_7d").
-			SchemaType(map[string]string{dialect.Postgres: "decimal(20,8)"}).
+		field.Int64("rate_limit_7d").
+			GoType(money.Amount(0)).
+			SchemaType(map[string]string{dialect.Postgres: "decimal(20,10)"}).
 			Default(0).
 			Comment("Rate limit in USD per 7 days (0 = unlimited)"),
 		// Rate limit usage tracking
-		field.Float("usage_5h").
-			SchemaType(map[string]string{dialect.Postgres: "decimal(20,8)"}).
+		field.Int64("usage_5h").
+			GoType(money.Amount(0)).
+			SchemaType(map[string]string{dialect.Postgres: "decimal(20,10)"}).
 			Default(0).
 			Comment("Used amount in USD for the current 5h window"),
-		field.Float("usage_1d").
-			SchemaType(map[string]string{dialect.Postgres: "decimal(20,8)"}).
+		field.Int64("usage_1d").
+			GoType(money.Amount(0)).
+			SchemaType(map[string]string{dialect.Postgres: "decimal(20,10)"}).
 			Default(0).
 			Comment("Used amount in USD for the current 1d window"),
-		field.Float("usage_7d").
-			SchemaType(map[string]string{dialect.Postgres: "decimal(20,8)"}).
+		field.Int64("usage_7d").
+			GoType(money.Amount(0)).
+			SchemaType(map[string]string{dialect.Postgres: "decimal(20,10)"}).
 			Default(0).
 			Comment("Used amount in USD for the current 7d window"),
 		// Window start times
@@ -115,6 +130,25 @@ func (APIKey) Fields() []ent.Field {
 			Optional().
 			Nillable().
 			Comment("Start time of the current 7d rate limit window"),
+
+		// ========== Traffic limit fields ==========
+		// Per-key traffic limits enforced in Redis (0 = unlimited)
+		field.Int("rpm_limit").
+			Default(0).
+			Comment("Max requests per minute for this API key (0 = unlimited)"),
+		field.Int("tpm_limit").
+			Default(0).
+			Comment("Max tokens per minute for this API key (0 = unlimited)"),
+		field.Int("max_concurrency").
+			Default(0).
+			Comment("Max in-flight requests for this API key (0 = unlimited)"),
+
+		// ========== Organization fields ==========
+		// Organization keys are owned by the organization account (user_id) and attributed to the creating member
+		field.Int64("created_by_user_id").
+			Optional().
+			Nillable().
+			Comment("Organization member who created this key (null = personal key)"),
 	}
 }
 
@@ -144,5 +178,6 @@ func (APIKey) Indexes() []ent.Index {
 		// Index for quota queries
 		index.Fields("quota", "quota_used"),
 		index.Fields("expires_at"),
+		index.Fields("created_by_user_id"),
 	}
 }
@@ -3,6 +3,7 @@ package schema
 import (
 	"github.com/Wei-Shaw/sub2api/ent/schema/mixins"
 	"github.com/Wei-Shaw/sub2api/internal/domain"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
 
 	"entgo.io/ent"
 	"entgo.io/ent/dialect"
@@ -58,69 +59,95 @@ func (Group) Fields() []ent.Field {
 		field.String("subscription_type").
 			MaxLen(20).
 			Default(domain.SubscriptionTypeStandard),
-		field.Float("daily_limit_usd").
+		field.Int64("daily_limit_usd").
+			GoType(money.Amount(0)).
 			Optional().
 			Nillable().
-			SchemaType(map[string]string{dialect.Postgres: "decimal(20,8)"}),
-		field.Float("weekly_limit_usd").
+			SchemaType(map[string]string{dialect.Postgres: "decimal(20,10)"}),
+		field.Int64("weekly_limit_usd").
+			GoType(money.Amount(0)).
 			Optional().
 			Nillable().
-			SchemaType(map[string]string{dialect.Postgres: "decimal(20,8)"}),
-		field.Float("monthly_limit_usd").
+			SchemaType(map[string]string{dialect.Postgres: "decimal(20,10)"}),
+		field.Int64("monthly_limit_usd").
+			GoType(money.Amount(0)).
 			Optional().
 			Nillable().
-			SchemaType(map[string]string{dialect.Postgres: "decimal(20,8)"}),
+			SchemaType(map[string]string{dialect.Postgres: "decimal(20,10)"}),
 		field.Int("default_validity_days").
 			Default(30),
 
 		// 图片生成计费配置（antigravity 和 gemini 平台使用）
-		field.Float("image_price_1k").
+		field.Int64("image_price_1k").
+			GoType(money.Amount(0)).
 			Optional().
 			Nillable().
-			SchemaType(map[string]string{dialect.Postgres: "decimal(20,8)"}),
-		field.Float("image_price_2k").
+			SchemaType(map[string]string{dialect.Postgres: "decimal(20,10)"}),
+		field.Int64("image_price_2k").
+			GoType(money.Amount(0)).
 			Optional().
 			Nillable().
-			SchemaType(map[string]string{dialect.Postgres: "decimal(20,8)"}),
-		field.Float("image_price_4k").
+			SchemaType(map[string]string{dialect.Postgres: "decimal(20,10)"}),
+		field.Int64("image_price_4k").
+			GoType(money.Amount(0)).
 			Optional().
 			Nillable().
-			SchemaType(map[string]string{dialect.Postgres: "decimal(20,8)"}),
+			SchemaType(map[string]string{dialect.Postgres: "decimal(20,10)"}),
 
 		// Sora 按次计费配置（阶段 1）
-		field.Float("sora_image_price_360").
+		field.Int64("sora_image_price_360").
+			GoType(money.Amount(0)).
 			Optional().
 			Nillable().
-			SchemaType(map[string]string{dialect.Postgres: "decimal(20,8)"}),
-		field.Float("sora_image_price_540").
+			SchemaType(map[string]string{dialect.Postgres: "decimal(20,10)"}),
+		field.Int64("sora_image_price_540").
+			GoType(money.Amount(0)).
 			Optional().
 			Nillable().
-			SchemaType(map[string]string{dialect.Postgres: "decimal(20,8)"}),
-		field.Float("sora_video_price_per_request").
+			SchemaType(map[string]string{dialect.Postgres: "decimal(20,10)"}),
+		field.Int64("sora_video_price_per_request").
+			GoType(money.Amount(0)).
 			Optional().
 			Nillable().
-			SchemaType(map[string]string{dialect.Postgres: "decimal(20,8)"}),
-		field.Float("sora_video_price_per_request_hd").
+			SchemaType(map[string]string{dialect.Postgres: "decimal(20,10)"}),
+		field.Int64("sora_video_price_per_request_hd").
+			GoType(money.Amount(0)).
 			Optional().
 			Nillable().
-			SchemaType(map[string]string{dialect.Postgres: "decimal(20,8)"}),
+			SchemaType(map[string]string{dialect.Postgres: "decimal(20,10)"}),
 
 		// Sora 存储配额
 		field.Int64("sora_storage_quota_bytes").
 			Default(0),
 
 		// 通用视频生成计费配置（用于 Grok 等 OpenAI 兼容平台）
-		field.Float("video_price_per_request").
+		field.Int64("video_price_per_request").
+			GoType(money.Amount(0)).
 			Optional().
 			Nillable().
-			SchemaType(map[string]string{dialect.Postgres: "decimal(20,8)"}).
+			SchemaType(map[string]string{dialect.Postgres: "decimal(20,10)"}).
 			Comment("视频生成单次请求价格（标准质量）"),
-		field.Float("video_price_per_request_hd").
+		field.Int64("video_price_per_request_hd").
+			GoType(money.Amount(0)).
 			Optional().
 			Nillable().
-			SchemaType(map[string]string{dialect.Postgres: "decimal(20,8)"}).
+			SchemaType(map[string]string{dialect.Postgres: "decimal(20,10)"}).
 			Comment("视频生成单次请求价格（高清质量）"),
 
+		// 音频计费配置（OpenAI 兼容平台的转写/翻译/语音合成）
+		field.Int64("audio_price_per_minute").
+			GoType(money.Amount(0)).
+			Optional().
+			Nillable().
+			SchemaType(map[string]string{dialect.Postgres: "decimal(20,10)"}).
+			Comment("音频转写/翻译每分钟价格"),
+		field.Int64("audio_speech_price_per_1m_chars").
+			GoType(money.Amount(0)).
+			Optional().
+			Nillable().
+			SchemaType(map[string]string{dialect.Postgres: "decimal(20,10)"}).
+			Comment("语音合成每百万字符价格"),
+
 		// Claude Code 客户端限制 (added by migration 029)
 		field.Bool("claude_code_only").
 			Default(false).
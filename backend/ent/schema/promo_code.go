@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/Wei-Shaw/sub2api/internal/domain"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
 
 	"entgo.io/ent"
 	"entgo.io/ent/dialect"
@@ -16,8 +17,8 @@ import (
 
 // PromoCode holds the schema definition for the PromoCode entity.
 //
-// 注册优惠码：用户注册时使用，可获得赠送余额
-// 与 RedeemCode 不同，PromoCode 支持多次使用（有使用次数限制）
+// 优惠码：注册时或注册后在个人中心使用，可获得赠送余额、首次充值比例赠送或试用订阅
+// 与 RedeemCode 不同，PromoCode 支持多次使用（有总次数与每用户次数限制）
 //
 // 删除策略：硬删除
 type PromoCode struct {
@@ -37,8 +38,9 @@ func (PromoCode) Fields() []ent.Field {
 			NotEmpty().
 			Unique().
 			Comment("优惠码"),
-		field.Float("bonus_amount").
-			SchemaType(map[string]string{dialect.Postgres: "decimal(20,8)"}).
+		field.Int64("bonus_amount").
+			GoType(money.Amount(0)).
+			SchemaType(map[string]string{dialect.Postgres: "decimal(20,10)"}).
 			Default(0).
 			Comment("赠送余额金额"),
 		field.Int("max_uses").
@@ -47,6 +49,39 @@ func (PromoCode) Fields() []ent.Field {
 		field.Int("used_count").
 			Default(0).
 			Comment("已使用次数"),
+		field.Float("topup_bonus_percent").
+			SchemaType(map[string]string{dialect.Postgres: "decimal(6,2)"}).
+			Default(0).
+			Comment("首次充值赠送比例（百分比），0表示不赠送"),
+		field.Int64("topup_bonus_max").
+			GoType(money.Amount(0)).
+			SchemaType(map[string]string{dialect.Postgres: "decimal(20,10)"}).
+			Default(0).
+			Comment("首次充值赠送上限，0表示无上限"),
+		field.Int64("min_topup_amount").
+			GoType(money.Amount(0)).
+			SchemaType(map[string]string{dialect.Postgres: "decimal(20,10)"}).
+			Default(0).
+			Comment("触发充值赠送的最低单笔充值金额"),
+		field.Int("per_user_limit").
+			Default(1).
+			Comment("每个用户最多使用次数，0表示无限制"),
+		field.JSON("allowed_email_domains", []string{}).
+			Optional().
+			Comment("允许使用的邮箱域名，空表示不限制"),
+		field.JSON("required_attributes", map[string]string{}).
+			Optional().
+			Comment("要求用户属性取值（属性 key -> 值），空表示不限制"),
+		field.Bool("stackable").
+			Default(false).
+			Comment("是否可与其他可叠加优惠码同时使用"),
+		field.Int64("trial_group_id").
+			Optional().
+			Nillable().
+			Comment("赠送试用订阅的分组ID"),
+		field.Int("trial_days").
+			Default(0).
+			Comment("试用订阅天数"),
 		field.String("status").
 			MaxLen(20).
 			Default(domain.PromoCodeStatusActive).
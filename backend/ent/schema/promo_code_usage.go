@@ -3,6 +3,9 @@ package schema
 import (
 	"time"
 
+	"github.com/Wei-Shaw/sub2api/internal/domain"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
+
 	"entgo.io/ent"
 	"entgo.io/ent/dialect"
 	"entgo.io/ent/dialect/entsql"
@@ -31,9 +34,24 @@ func (PromoCodeUsage) Fields() []ent.Field {
 			Comment("优惠码ID"),
 		field.Int64("user_id").
 			Comment("使用用户ID"),
-		field.Float("bonus_amount").
-			SchemaType(map[string]string{dialect.Postgres: "decimal(20,8)"}).
+		field.Int64("bonus_amount").
+			GoType(money.Amount(0)).
+			SchemaType(map[string]string{dialect.Postgres: "decimal(20,10)"}).
 			Comment("实际赠送金额"),
+		field.String("topup_bonus_status").
+			MaxLen(20).
+			Default(domain.PromoTopupBonusStatusNone).
+			Comment("充值赠送状态: none, pending, granted"),
+		field.Int64("topup_bonus_amount").
+			GoType(money.Amount(0)).
+			SchemaType(map[string]string{dialect.Postgres: "decimal(20,10)"}).
+			Default(0).
+			Comment("已发放的充值赠送金额"),
+		field.Time("topup_granted_at").
+			Optional().
+			Nillable().
+			SchemaType(map[string]string{dialect.Postgres: "timestamptz"}).
+			Comment("充值赠送发放时间"),
 		field.Time("used_at").
 			Default(time.Now).
 			SchemaType(map[string]string{dialect.Postgres: "timestamptz"}).
@@ -60,7 +78,8 @@ func (PromoCodeUsage) Indexes() []ent.Index {
 	return []ent.Index{
 		index.Fields("promo_code_id"),
 		index.Fields("user_id"),
-		// 每个用户每个优惠码只能使用一次
-		index.Fields("promo_code_id", "user_id").Unique(),
+		// 每用户使用次数由 per_user_limit 控制，不再唯一
+		index.Fields("promo_code_id", "user_id"),
+		index.Fields("user_id", "topup_bonus_status"),
 	}
 }
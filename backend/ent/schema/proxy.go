@@ -4,6 +4,7 @@ import (
 	"github.com/Wei-Shaw/sub2api/ent/schema/mixins"
 
 	"entgo.io/ent"
+	"entgo.io/ent/dialect"
 	"entgo.io/ent/dialect/entsql"
 	"entgo.io/ent/schema"
 	"entgo.io/ent/schema/edge"
@@ -45,10 +46,11 @@ func (Proxy) Fields() []ent.Field {
 			MaxLen(100).
 			Optional().
 			Nillable(),
+		// 启用凭证加密后存储的是信封密文，长度不再受明文上限约束
 		field.String("password").
-			MaxLen(100).
 			Optional().
-			Nillable(),
+			Nillable().
+			SchemaType(map[string]string{dialect.Postgres: "text"}),
 		field.String("status").
 			MaxLen(20).
 			Default("active"),
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/Wei-Shaw/sub2api/internal/domain"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
 
 	"entgo.io/ent"
 	"entgo.io/ent/dialect"
@@ -42,8 +43,9 @@ func (RedeemCode) Fields() []ent.Field {
 		field.String("type").
 			MaxLen(20).
 			Default(domain.RedeemTypeBalance),
-		field.Float("value").
-			SchemaType(map[string]string{dialect.Postgres: "decimal(20,8)"}).
+		field.Int64("value").
+			GoType(money.Amount(0)).
+			SchemaType(map[string]string{dialect.Postgres: "decimal(20,10)"}).
 			Default(0),
 		field.String("status").
 			MaxLen(20).
@@ -4,6 +4,8 @@ package schema
 import (
 	"time"
 
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
+
 	"entgo.io/ent"
 	"entgo.io/ent/dialect"
 	"entgo.io/ent/dialect/entsql"
@@ -63,22 +65,28 @@ func (UsageLog) Fields() []ent.Field {
 			Default(0),
 
 		// 成本字段
-		field.Float("input_cost").
+		field.Int64("input_cost").
+			GoType(money.Amount(0)).
 			Default(0).
 			SchemaType(map[string]string{dialect.Postgres: "decimal(20,10)"}),
-		field.Float("output_cost").
+		field.Int64("output_cost").
+			GoType(money.Amount(0)).
 			Default(0).
 			SchemaType(map[string]string{dialect.Postgres: "decimal(20,10)"}),
-		field.Float("cache_creation_cost").
+		field.Int64("cache_creation_cost").
+			GoType(money.Amount(0)).
 			Default(0).
 			SchemaType(map[string]string{dialect.Postgres: "decimal(20,10)"}),
-		field.Float("cache_read_cost").
+		field.Int64("cache_read_cost").
+			GoType(money.Amount(0)).
 			Default(0).
 			SchemaType(map[string]string{dialect.Postgres: "decimal(20,10)"}),
-		field.Float("total_cost").
+		field.Int64("total_cost").
+			GoType(money.Amount(0)).
 			Default(0).
 			SchemaType(map[string]string{dialect.Postgres: "decimal(20,10)"}),
-		field.Float("actual_cost").
+		field.Int64("actual_cost").
+			GoType(money.Amount(0)).
 			Default(0).
 			SchemaType(map[string]string{dialect.Postgres: "decimal(20,10)"}),
 		field.Float("rate_multiplier").
@@ -123,6 +131,13 @@ func (UsageLog) Fields() []ent.Field {
 			MaxLen(16).
 			Optional().
 			Nillable(),
+		// 音频计量字段（转写/翻译按时长，语音合成按字符）
+		field.Float("audio_duration_seconds").
+			Optional().
+			Nillable(),
+		field.Int("audio_characters").
+			Optional().
+			Nillable(),
 
 		// Cache TTL Override 标记（管理员强制替换了缓存 TTL 计费）
 		field.Bool("cache_ttl_overridden").
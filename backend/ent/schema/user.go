@@ -3,6 +3,7 @@ package schema
 import (
 	"github.com/Wei-Shaw/sub2api/ent/schema/mixins"
 	"github.com/Wei-Shaw/sub2api/internal/domain"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
 
 	"entgo.io/ent"
 	"entgo.io/ent/dialect"
@@ -44,8 +45,9 @@ func (User) Fields() []ent.Field {
 		field.String("role").
 			MaxLen(20).
 			Default(domain.RoleUser),
-		field.Float("balance").
-			SchemaType(map[string]string{dialect.Postgres: "decimal(20,8)"}).
+		field.Int64("balance").
+			GoType(money.Amount(0)).
+			SchemaType(map[string]string{dialect.Postgres: "decimal(20,10)"}).
 			Default(0),
 		field.Int("concurrency").
 			Default(5),
@@ -5,6 +5,7 @@ import (
 
 	"github.com/Wei-Shaw/sub2api/ent/schema/mixins"
 	"github.com/Wei-Shaw/sub2api/internal/domain"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
 
 	"entgo.io/ent"
 	"entgo.io/ent/dialect"
@@ -59,13 +60,16 @@ func (UserSubscription) Fields() []ent.Field {
 			Nillable().
 			SchemaType(map[string]string{dialect.Postgres: "timestamptz"}),
 
-		field.Float("daily_usage_usd").
+		field.Int64("daily_usage_usd").
+			GoType(money.Amount(0)).
 			SchemaType(map[string]string{dialect.Postgres: "decimal(20,10)"}).
 			Default(0),
-		field.Float("weekly_usage_usd").
+		field.Int64("weekly_usage_usd").
+			GoType(money.Amount(0)).
 			SchemaType(map[string]string{dialect.Postgres: "decimal(20,10)"}).
 			Default(0),
-		field.Float("monthly_usage_usd").
+		field.Int64("monthly_usage_usd").
+			GoType(money.Amount(0)).
 			SchemaType(map[string]string{dialect.Postgres: "decimal(20,10)"}).
 			Default(0),
 
@@ -15,6 +15,7 @@ import (
 	"github.com/Wei-Shaw/sub2api/ent/usagelog"
 	"github.com/Wei-Shaw/sub2api/ent/user"
 	"github.com/Wei-Shaw/sub2api/ent/usersubscription"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
 )
 
 // UsageLog is the model entity for the UsageLog schema.
@@ -49,17 +50,17 @@ type UsageLog struct {
 	// CacheCreation1hTokens holds the value of the "cache_creation_1h_tokens" field.
 	CacheCreation1hTokens int `json:"cache_creation_1h_tokens,omitempty"`
 	// InputCost holds the value of the "input_cost" field.
-	InputCost float64 `json:"input_cost,omitempty"`
+	InputCost money.Amount `json:"input_cost,omitempty"`
 	// OutputCost holds the value of the "output_cost" field.
-	OutputCost float64 `json:"output_cost,omitempty"`
+	OutputCost money.Amount `json:"output_cost,omitempty"`
 	// CacheCreationCost holds the value of the "cache_creation_cost" field.
-	CacheCreationCost float64 `json:"cache_creation_cost,omitempty"`
+	CacheCreationCost money.Amount `json:"cache_creation_cost,omitempty"`
 	// CacheReadCost holds the value of the "cache_read_cost" field.
-	CacheReadCost float64 `json:"cache_read_cost,omitempty"`
+	CacheReadCost money.Amount `json:"cache_read_cost,omitempty"`
 	// TotalCost holds the value of the "total_cost" field.
-	TotalCost float64 `json:"total_cost,omitempty"`
+	TotalCost money.Amount `json:"total_cost,omitempty"`
 	// ActualCost holds the value of the "actual_cost" field.
-	ActualCost float64 `json:"actual_cost,omitempty"`
+	ActualCost money.Amount `json:"actual_cost,omitempty"`
 	// RateMultiplier holds the value of the "rate_multiplier" field.
 	RateMultiplier float64 `json:"rate_multiplier,omitempty"`
 	// AccountRateMultiplier holds the value of the "account_rate_multiplier" field.
@@ -82,6 +83,10 @@ type UsageLog struct {
 	ImageSize *string `json:"image_size,omitempty"`
 	// MediaType holds the value of the "media_type" field.
 	MediaType *string `json:"media_type,omitempty"`
+	// AudioDurationSeconds holds the value of the "audio_duration_seconds" field.
+	AudioDurationSeconds *float64 `json:"audio_duration_seconds,omitempty"`
+	// AudioCharacters holds the value of the "audio_characters" field.
+	AudioCharacters *int `json:"audio_characters,omitempty"`
 	// CacheTTLOverridden holds the value of the "cache_ttl_overridden" field.
 	CacheTTLOverridden bool `json:"cache_ttl_overridden,omitempty"`
 	// CreatedAt holds the value of the "created_at" field.
@@ -169,11 +174,13 @@ func (*UsageLog) scanValues(columns []string) ([]any, error) {
 	values := make([]any, len(columns))
 	for i := range columns {
 		switch columns[i] {
+		case usagelog.FieldInputCost, usagelog.FieldOutputCost, usagelog.FieldCacheCreationCost, usagelog.FieldCacheReadCost, usagelog.FieldTotalCost, usagelog.FieldActualCost:
+			values[i] = new(money.Amount)
 		case usagelog.FieldStream, usagelog.FieldCacheTTLOverridden:
 			values[i] = new(sql.NullBool)
-		case usagelog.FieldInputCost, usagelog.FieldOutputCost, usagelog.FieldCacheCreationCost, usagelog.FieldCacheReadCost, usagelog.FieldTotalCost, usagelog.FieldActualCost, usagelog.FieldRateMultiplier, usagelog.FieldAccountRateMultiplier:
+		case usagelog.FieldRateMultiplier, usagelog.FieldAccountRateMultiplier, usagelog.FieldAudioDurationSeconds:
 			values[i] = new(sql.NullFloat64)
-		case usagelog.FieldID, usagelog.FieldUserID, usagelog.FieldAPIKeyID, usagelog.FieldAccountID, usagelog.FieldGroupID, usagelog.FieldSubscriptionID, usagelog.FieldInputTokens, usagelog.FieldOutputTokens, usagelog.FieldCacheCreationTokens, usagelog.FieldCacheReadTokens, usagelog.FieldCacheCreation5mTokens, usagelog.FieldCacheCreation1hTokens, usagelog.FieldBillingType, usagelog.FieldDurationMs, usagelog.FieldFirstTokenMs, usagelog.FieldImageCount:
+		case usagelog.FieldID, usagelog.FieldUserID, usagelog.FieldAPIKeyID, usagelog.FieldAccountID, usagelog.FieldGroupID, usagelog.FieldSubscriptionID, usagelog.FieldInputTokens, usagelog.FieldOutputTokens, usagelog.FieldCacheCreationTokens, usagelog.FieldCacheReadTokens, usagelog.FieldCacheCreation5mTokens, usagelog.FieldCacheCreation1hTokens, usagelog.FieldBillingType, usagelog.FieldDurationMs, usagelog.FieldFirstTokenMs, usagelog.FieldImageCount, usagelog.FieldAudioCharacters:
 			values[i] = new(sql.NullInt64)
 		case usagelog.FieldRequestID, usagelog.FieldModel, usagelog.FieldUserAgent, usagelog.FieldIPAddress, usagelog.FieldImageSize, usagelog.FieldMediaType:
 			values[i] = new(sql.NullString)
@@ -281,40 +288,40 @@ func (_m *UsageLog) assignValues(columns []string, values []any) error {
 				_m.CacheCreation1hTokens = int(value.Int64)
 			}
 		case usagelog.FieldInputCost:
-			if value, ok := values[i].(*sql.NullFloat64); !ok {
+			if value, ok := values[i].(*money.Amount); !ok {
 				return fmt.Errorf("unexpected type %T for field input_cost", values[i])
-			} else if value.Valid {
-				_m.InputCost = value.Float64
+			} else if value != nil {
+				_m.InputCost = *value
 			}
 		case usagelog.FieldOutputCost:
-			if value, ok := values[i].(*sql.NullFloat64); !ok {
+			if value, ok := values[i].(*money.Amount); !ok {
 				return fmt.Errorf("unexpected type %T for field output_cost", values[i])
-			} else if value.Valid {
-				_m.OutputCost = value.Float64
+			} else if value != nil {
+				_m.OutputCost = *value
 			}
 		case usagelog.FieldCacheCreationCost:
-			if value, ok := values[i].(*sql.NullFloat64); !ok {
+			if value, ok := values[i].(*money.Amount); !ok {
 				return fmt.Errorf("unexpected type %T for field cache_creation_cost", values[i])
-			} else if value.Valid {
-				_m.CacheCreationCost = value.Float64
+			} else if value != nil {
+				_m.CacheCreationCost = *value
 			}
 		case usagelog.FieldCacheReadCost:
-			if value, ok := values[i].(*sql.NullFloat64); !ok {
+			if value, ok := values[i].(*money.Amount); !ok {
 				return fmt.Errorf("unexpected type %T for field cache_read_cost", values[i])
-			} else if value.Valid {
-				_m.CacheReadCost = value.Float64
+			} else if value != nil {
+				_m.CacheReadCost = *value
 			}
 		case usagelog.FieldTotalCost:
-			if value, ok := values[i].(*sql.NullFloat64); !ok {
+			if value, ok := values[i].(*money.Amount); !ok {
 				return fmt.Errorf("unexpected type %T for field total_cost", values[i])
-			} else if value.Valid {
-				_m.TotalCost = value.Float64
+			} else if value != nil {
+				_m.TotalCost = *value
 			}
 		case usagelog.FieldActualCost:
-			if value, ok := values[i].(*sql.NullFloat64); !ok {
+			if value, ok := values[i].(*money.Amount); !ok {
 				return fmt.Errorf("unexpected type %T for field actual_cost", values[i])
-			} else if value.Valid {
-				_m.ActualCost = value.Float64
+			} else if value != nil {
+				_m.ActualCost = *value
 			}
 		case usagelog.FieldRateMultiplier:
 			if value, ok := values[i].(*sql.NullFloat64); !ok {
@@ -389,6 +396,20 @@ func (_m *UsageLog) assignValues(columns []string, values []any) error {
 				_m.MediaType = new(string)
 				*_m.MediaType = value.String
 			}
+		case usagelog.FieldAudioDurationSeconds:
+			if value, ok := values[i].(*sql.NullFloat64); !ok {
+				return fmt.Errorf("unexpected type %T for field audio_duration_seconds", values[i])
+			} else if value.Valid {
+				_m.AudioDurationSeconds = new(float64)
+				*_m.AudioDurationSeconds = value.Float64
+			}
+		case usagelog.FieldAudioCharacters:
+			if value, ok := values[i].(*sql.NullInt64); !ok {
+				return fmt.Errorf("unexpected type %T for field audio_characters", values[i])
+			} else if value.Valid {
+				_m.AudioCharacters = new(int)
+				*_m.AudioCharacters = int(value.Int64)
+			}
 		case usagelog.FieldCacheTTLOverridden:
 			if value, ok := values[i].(*sql.NullBool); !ok {
 				return fmt.Errorf("unexpected type %T for field cache_ttl_overridden", values[i])
@@ -570,6 +591,16 @@ func (_m *UsageLog) String() string {
 		builder.WriteString(*v)
 	}
 	builder.WriteString(", ")
+	if v := _m.AudioDurationSeconds; v != nil {
+		builder.WriteString("audio_duration_seconds=")
+		builder.WriteString(fmt.Sprintf("%v", *v))
+	}
+	builder.WriteString(", ")
+	if v := _m.AudioCharacters; v != nil {
+		builder.WriteString("audio_characters=")
+		builder.WriteString(fmt.Sprintf("%v", *v))
+	}
+	builder.WriteString(", ")
 	builder.WriteString("cache_ttl_overridden=")
 	builder.WriteString(fmt.Sprintf("%v", _m.CacheTTLOverridden))
 	builder.WriteString(", ")
@@ -7,6 +7,7 @@ import (
 
 	"entgo.io/ent/dialect/sql"
 	"entgo.io/ent/dialect/sql/sqlgraph"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
 )
 
 const (
@@ -74,6 +75,10 @@ const (
 	FieldImageSize = "image_size"
 	// FieldMediaType holds the string denoting the media_type field in the database.
 	FieldMediaType = "media_type"
+	// FieldAudioDurationSeconds holds the string denoting the audio_duration_seconds field in the database.
+	FieldAudioDurationSeconds = "audio_duration_seconds"
+	// FieldAudioCharacters holds the string denoting the audio_characters field in the database.
+	FieldAudioCharacters = "audio_characters"
 	// FieldCacheTTLOverridden holds the string denoting the cache_ttl_overridden field in the database.
 	FieldCacheTTLOverridden = "cache_ttl_overridden"
 	// FieldCreatedAt holds the string denoting the created_at field in the database.
@@ -160,6 +165,8 @@ var Columns = []string{
 	FieldImageCount,
 	FieldImageSize,
 	FieldMediaType,
+	FieldAudioDurationSeconds,
+	FieldAudioCharacters,
 	FieldCacheTTLOverridden,
 	FieldCreatedAt,
 }
@@ -192,17 +199,17 @@ var (
 	// DefaultCacheCreation1hTokens holds the default value on creation for the "cache_creation_1h_tokens" field.
 	DefaultCacheCreation1hTokens int
 	// DefaultInputCost holds the default value on creation for the "input_cost" field.
-	DefaultInputCost float64
+	DefaultInputCost money.Amount
 	// DefaultOutputCost holds the default value on creation for the "output_cost" field.
-	DefaultOutputCost float64
+	DefaultOutputCost money.Amount
 	// DefaultCacheCreationCost holds the default value on creation for the "cache_creation_cost" field.
-	DefaultCacheCreationCost float64
+	DefaultCacheCreationCost money.Amount
 	// DefaultCacheReadCost holds the default value on creation for the "cache_read_cost" field.
-	DefaultCacheReadCost float64
+	DefaultCacheReadCost money.Amount
 	// DefaultTotalCost holds the default value on creation for the "total_cost" field.
-	DefaultTotalCost float64
+	DefaultTotalCost money.Amount
 	// DefaultActualCost holds the default value on creation for the "actual_cost" field.
-	DefaultActualCost float64
+	DefaultActualCost money.Amount
 	// DefaultRateMultiplier holds the default value on creation for the "rate_multiplier" field.
 	DefaultRateMultiplier float64
 	// DefaultBillingType holds the default value on creation for the "billing_type" field.
@@ -383,6 +390,16 @@ func ByMediaType(opts ...sql.OrderTermOption) OrderOption {
 	return sql.OrderByField(FieldMediaType, opts...).ToFunc()
 }
 
+// ByAudioDurationSeconds orders the results by the audio_duration_seconds field.
+func ByAudioDurationSeconds(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldAudioDurationSeconds, opts...).ToFunc()
+}
+
+// ByAudioCharacters orders the results by the audio_characters field.
+func ByAudioCharacters(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldAudioCharacters, opts...).ToFunc()
+}
+
 // ByCacheTTLOverridden orders the results by the cache_ttl_overridden field.
 func ByCacheTTLOverridden(opts ...sql.OrderTermOption) OrderOption {
 	return sql.OrderByField(FieldCacheTTLOverridden, opts...).ToFunc()
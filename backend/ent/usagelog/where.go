@@ -8,6 +8,7 @@ import (
 	"entgo.io/ent/dialect/sql"
 	"entgo.io/ent/dialect/sql/sqlgraph"
 	"github.com/Wei-Shaw/sub2api/ent/predicate"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
 )
 
 // ID filters vertices based on their ID field.
@@ -121,32 +122,32 @@ func CacheCreation1hTokens(v int) predicate.UsageLog {
 }
 
 // InputCost applies equality check predicate on the "input_cost" field. It's identical to InputCostEQ.
-func InputCost(v float64) predicate.UsageLog {
+func InputCost(v money.Amount) predicate.UsageLog {
 	return predicate.UsageLog(sql.FieldEQ(FieldInputCost, v))
 }
 
 // OutputCost applies equality check predicate on the "output_cost" field. It's identical to OutputCostEQ.
-func OutputCost(v float64) predicate.UsageLog {
+func OutputCost(v money.Amount) predicate.UsageLog {
 	return predicate.UsageLog(sql.FieldEQ(FieldOutputCost, v))
 }
 
 // CacheCreationCost applies equality check predicate on the "cache_creation_cost" field. It's identical to CacheCreationCostEQ.
-func CacheCreationCost(v float64) predicate.UsageLog {
+func CacheCreationCost(v money.Amount) predicate.UsageLog {
 	return predicate.UsageLog(sql.FieldEQ(FieldCacheCreationCost, v))
 }
 
 // CacheReadCost applies equality check predicate on the "cache_read_cost" field. It's identical to CacheReadCostEQ.
-func CacheReadCost(v float64) predicate.UsageLog {
+func CacheReadCost(v money.Amount) predicate.UsageLog {
 	return predicate.UsageLog(sql.FieldEQ(FieldCacheReadCost, v))
 }
 
 // TotalCost applies equality check predicate on the "total_cost" field. It's identical to TotalCostEQ.
-func TotalCost(v float64) predicate.UsageLog {
+func TotalCost(v money.Amount) predicate.UsageLog {
 	return predicate.UsageLog(sql.FieldEQ(FieldTotalCost, v))
 }
 
 // ActualCost applies equality check predicate on the "actual_cost" field. It's identical to ActualCostEQ.
-func ActualCost(v float64) predicate.UsageLog {
+func ActualCost(v money.Amount) predicate.UsageLog {
 	return predicate.UsageLog(sql.FieldEQ(FieldActualCost, v))
 }
 
@@ -205,6 +206,16 @@ func MediaType(v string) predicate.UsageLog {
 	return predicate.UsageLog(sql.FieldEQ(FieldMediaType, v))
 }
 
+// AudioDurationSeconds applies equality check predicate on the "audio_duration_seconds" field. It's identical to AudioDurationSecondsEQ.
+func AudioDurationSeconds(v float64) predicate.UsageLog {
+	return predicate.UsageLog(sql.FieldEQ(FieldAudioDurationSeconds, v))
+}
+
+// AudioCharacters applies equality check predicate on the "audio_characters" field. It's identical to AudioCharactersEQ.
+func AudioCharacters(v int) predicate.UsageLog {
+	return predicate.UsageLog(sql.FieldEQ(FieldAudioCharacters, v))
+}
+
 // CacheTTLOverridden applies equality check predicate on the "cache_ttl_overridden" field. It's identical to CacheTTLOverriddenEQ.
 func CacheTTLOverridden(v bool) predicate.UsageLog {
 	return predicate.UsageLog(sql.FieldEQ(FieldCacheTTLOverridden, v))
@@ -706,242 +717,242 @@ func CacheCreation1hTokensLTE(v int) predicate.UsageLog {
 }
 
 // InputCostEQ applies the EQ predicate on the "input_cost" field.
-func InputCostEQ(v float64) predicate.UsageLog {
+func InputCostEQ(v money.Amount) predicate.UsageLog {
 	return predicate.UsageLog(sql.FieldEQ(FieldInputCost, v))
 }
 
 // InputCostNEQ applies the NEQ predicate on the "input_cost" field.
-func InputCostNEQ(v float64) predicate.UsageLog {
+func InputCostNEQ(v money.Amount) predicate.UsageLog {
 	return predicate.UsageLog(sql.FieldNEQ(FieldInputCost, v))
 }
 
 // InputCostIn applies the In predicate on the "input_cost" field.
-func InputCostIn(vs ...float64) predicate.UsageLog {
+func InputCostIn(vs ...money.Amount) predicate.UsageLog {
 	return predicate.UsageLog(sql.FieldIn(FieldInputCost, vs...))
 }
 
 // InputCostNotIn applies the NotIn predicate on the "input_cost" field.
-func InputCostNotIn(vs ...float64) predicate.UsageLog {
+func InputCostNotIn(vs ...money.Amount) predicate.UsageLog {
 	return predicate.UsageLog(sql.FieldNotIn(FieldInputCost, vs...))
 }
 
 // InputCostGT applies the GT predicate on the "input_cost" field.
-func InputCostGT(v float64) predicate.UsageLog {
+func InputCostGT(v money.Amount) predicate.UsageLog {
 	return predicate.UsageLog(sql.FieldGT(FieldInputCost, v))
 }
 
 // InputCostGTE applies the GTE predicate on the "input_cost" field.
-func InputCostGTE(v float64) predicate.UsageLog {
+func InputCostGTE(v money.Amount) predicate.UsageLog {
 	return predicate.UsageLog(sql.FieldGTE(FieldInputCost, v))
 }
 
 // InputCostLT applies the LT predicate on the "input_cost" field.
-func InputCostLT(v float64) predicate.UsageLog {
+func InputCostLT(v money.Amount) predicate.UsageLog {
 	return predicate.UsageLog(sql.FieldLT(FieldInputCost, v))
 }
 
 // InputCostLTE applies the LTE predicate on the "input_cost" field.
-func InputCostLTE(v float64) predicate.UsageLog {
+func InputCostLTE(v money.Amount) predicate.UsageLog {
 	return predicate.UsageLog(sql.FieldLTE(FieldInputCost, v))
 }
 
 // OutputCostEQ applies the EQ predicate on the "output_cost" field.
-func OutputCostEQ(v float64) predicate.UsageLog {
+func OutputCostEQ(v money.Amount) predicate.UsageLog {
 	return predicate.UsageLog(sql.FieldEQ(FieldOutputCost, v))
 }
 
 // OutputCostNEQ applies the NEQ predicate on the "output_cost" field.
-func OutputCostNEQ(v float64) predicate.UsageLog {
+func OutputCostNEQ(v money.Amount) predicate.UsageLog {
 	return predicate.UsageLog(sql.FieldNEQ(FieldOutputCost, v))
 }
 
 // OutputCostIn applies the In predicate on the "output_cost" field.
-func OutputCostIn(vs ...float64) predicate.UsageLog {
+func OutputCostIn(vs ...money.Amount) predicate.UsageLog {
 	return predicate.UsageLog(sql.FieldIn(FieldOutputCost, vs...))
 }
 
 // OutputCostNotIn applies the NotIn predicate on the "output_cost" field.
-func OutputCostNotIn(vs ...float64) predicate.UsageLog {
+func OutputCostNotIn(vs ...money.Amount) predicate.UsageLog {
 	return predicate.UsageLog(sql.FieldNotIn(FieldOutputCost, vs...))
 }
 
 // OutputCostGT applies the GT predicate on the "output_cost" field.
-func OutputCostGT(v float64) predicate.UsageLog {
+func OutputCostGT(v money.Amount) predicate.UsageLog {
 	return predicate.UsageLog(sql.FieldGT(FieldOutputCost, v))
 }
 
 // OutputCostGTE applies the GTE predicate on the "output_cost" field.
-func OutputCostGTE(v float64) predicate.UsageLog {
+func OutputCostGTE(v money.Amount) predicate.UsageLog {
 	return predicate.UsageLog(sql.FieldGTE(FieldOutputCost, v))
 }
 
 // OutputCostLT applies the LT predicate on the "output_cost" field.
-func OutputCostLT(v float64) predicate.UsageLog {
+func OutputCostLT(v money.Amount) predicate.UsageLog {
 	return predicate.UsageLog(sql.FieldLT(FieldOutputCost, v))
 }
 
 // OutputCostLTE applies the LTE predicate on the "output_cost" field.
-func OutputCostLTE(v float64) predicate.UsageLog {
+func OutputCostLTE(v money.Amount) predicate.UsageLog {
 	return predicate.UsageLog(sql.FieldLTE(FieldOutputCost, v))
 }
 
 // CacheCreationCostEQ applies the EQ predicate on the "cache_creation_cost" field.
-func CacheCreationCostEQ(v float64) predicate.UsageLog {
+func CacheCreationCostEQ(v money.Amount) predicate.UsageLog {
 	return predicate.UsageLog(sql.FieldEQ(FieldCacheCreationCost, v))
 }
 
 // CacheCreationCostNEQ applies the NEQ predicate on the "cache_creation_cost" field.
-func CacheCreationCostNEQ(v float64) predicate.UsageLog {
+func CacheCreationCostNEQ(v money.Amount) predicate.UsageLog {
 	return predicate.UsageLog(sql.FieldNEQ(FieldCacheCreationCost, v))
 }
 
 // CacheCreationCostIn applies the In predicate on the "cache_creation_cost" field.
-func CacheCreationCostIn(vs ...float64) predicate.UsageLog {
+func CacheCreationCostIn(vs ...money.Amount) predicate.UsageLog {
 	return predicate.UsageLog(sql.FieldIn(FieldCacheCreationCost, vs...))
 }
 
 // CacheCreationCostNotIn applies the NotIn predicate on the "cache_creation_cost" field.
-func CacheCreationCostNotIn(vs ...float64) predicate.UsageLog {
+func CacheCreationCostNotIn(vs ...money.Amount) predicate.UsageLog {
 	return predicate.UsageLog(sql.FieldNotIn(FieldCacheCreationCost, vs...))
 }
 
 // CacheCreationCostGT applies the GT predicate on the "cache_creation_cost" field.
-func CacheCreationCostGT(v float64) predicate.UsageLog {
+func CacheCreationCostGT(v money.Amount) predicate.UsageLog {
 	return predicate.UsageLog(sql.FieldGT(FieldCacheCreationCost, v))
 }
 
 // CacheCreationCostGTE applies the GTE predicate on the "cache_creation_cost" field.
-func CacheCreationCostGTE(v float64) predicate.UsageLog {
+func CacheCreationCostGTE(v money.Amount) predicate.UsageLog {
 	return predicate.UsageLog(sql.FieldGTE(FieldCacheCreationCost, v))
 }
 
 // CacheCreationCostLT applies the LT predicate on the "cache_creation_cost" field.
-func CacheCreationCostLT(v float64) predicate.UsageLog {
+func CacheCreationCostLT(v money.Amount) predicate.UsageLog {
 	return predicate.UsageLog(sql.FieldLT(FieldCacheCreationCost, v))
 }
 
 // CacheCreationCostLTE applies the LTE predicate on the "cache_creation_cost" field.
-func CacheCreationCostLTE(v float64) predicate.UsageLog {
+func CacheCreationCostLTE(v money.Amount) predicate.UsageLog {
 	return predicate.UsageLog(sql.FieldLTE(FieldCacheCreationCost, v))
 }
 
 // CacheReadCostEQ applies the EQ predicate on the "cache_read_cost" field.
-func CacheReadCostEQ(v float64) predicate.UsageLog {
+func CacheReadCostEQ(v money.Amount) predicate.UsageLog {
 	return predicate.UsageLog(sql.FieldEQ(FieldCacheReadCost, v))
 }
 
 // CacheReadCostNEQ applies the NEQ predicate on the "cache_read_cost" field.
-func CacheReadCostNEQ(v float64) predicate.UsageLog {
+func CacheReadCostNEQ(v money.Amount) predicate.UsageLog {
 	return predicate.UsageLog(sql.FieldNEQ(FieldCacheReadCost, v))
 }
 
 // CacheReadCostIn applies the In predicate on the "cache_read_cost" field.
-func CacheReadCostIn(vs ...float64) predicate.UsageLog {
+func CacheReadCostIn(vs ...money.Amount) predicate.UsageLog {
 	return predicate.UsageLog(sql.FieldIn(FieldCacheReadCost, vs...))
 }
 
 // CacheReadCostNotIn applies the NotIn predicate on the "cache_read_cost" field.
-func CacheReadCostNotIn(vs ...float64) predicate.UsageLog {
+func CacheReadCostNotIn(vs ...money.Amount) predicate.UsageLog {
 	return predicate.UsageLog(sql.FieldNotIn(FieldCacheReadCost, vs...))
 }
 
 // CacheReadCostGT applies the GT predicate on the "cache_read_cost" field.
-func CacheReadCostGT(v float64) predicate.UsageLog {
+func CacheReadCostGT(v money.Amount) predicate.UsageLog {
 	return predicate.UsageLog(sql.FieldGT(FieldCacheReadCost, v))
 }
 
 // CacheReadCostGTE applies the GTE predicate on the "cache_read_cost" field.
-func CacheReadCostGTE(v float64) predicate.UsageLog {
+func CacheReadCostGTE(v money.Amount) predicate.UsageLog {
 	return predicate.UsageLog(sql.FieldGTE(FieldCacheReadCost, v))
 }
 
 // CacheReadCostLT applies the LT predicate on the "cache_read_cost" field.
-func CacheReadCostLT(v float64) predicate.UsageLog {
+func CacheReadCostLT(v money.Amount) predicate.UsageLog {
 	return predicate.UsageLog(sql.FieldLT(FieldCacheReadCost, v))
 }
 
 // CacheReadCostLTE applies the LTE predicate on the "cache_read_cost" field.
-func CacheReadCostLTE(v float64) predicate.UsageLog {
+func CacheReadCostLTE(v money.Amount) predicate.UsageLog {
 	return predicate.UsageLog(sql.FieldLTE(FieldCacheReadCost, v))
 }
 
 // TotalCostEQ applies the EQ predicate on the "total_cost" field.
-func TotalCostEQ(v float64) predicate.UsageLog {
+func TotalCostEQ(v money.Amount) predicate.UsageLog {
 	return predicate.UsageLog(sql.FieldEQ(FieldTotalCost, v))
 }
 
 // TotalCostNEQ applies the NEQ predicate on the "total_cost" field.
-func TotalCostNEQ(v float64) predicate.UsageLog {
+func TotalCostNEQ(v money.Amount) predicate.UsageLog {
 	return predicate.UsageLog(sql.FieldNEQ(FieldTotalCost, v))
 }
 
 // TotalCostIn applies the In predicate on the "total_cost" field.
-func TotalCostIn(vs ...float64) predicate.UsageLog {
+func TotalCostIn(vs ...money.Amount) predicate.UsageLog {
 	return predicate.UsageLog(sql.FieldIn(FieldTotalCost, vs...))
 }
 
 // TotalCostNotIn applies the NotIn predicate on the "total_cost" field.
-func TotalCostNotIn(vs ...float64) predicate.UsageLog {
+func TotalCostNotIn(vs ...money.Amount) predicate.UsageLog {
 	return predicate.UsageLog(sql.FieldNotIn(FieldTotalCost, vs...))
 }
 
 // TotalCostGT applies the GT predicate on the "total_cost" field.
-func TotalCostGT(v float64) predicate.UsageLog {
+func TotalCostGT(v money.Amount) predicate.UsageLog {
 	return predicate.UsageLog(sql.FieldGT(FieldTotalCost, v))
 }
 
 // TotalCostGTE applies the GTE predicate on the "total_cost" field.
-func TotalCostGTE(v float64) predicate.UsageLog {
+func TotalCostGTE(v money.Amount) predicate.UsageLog {
 	return predicate.UsageLog(sql.FieldGTE(FieldTotalCost, v))
 }
 
 // TotalCostLT applies the LT predicate on the "total_cost" field.
-func TotalCostLT(v float64) predicate.UsageLog {
+func TotalCostLT(v money.Amount) predicate.UsageLog {
 	return predicate.UsageLog(sql.FieldLT(FieldTotalCost, v))
 }
 
 // TotalCostLTE applies the LTE predicate on the "total_cost" field.
-func TotalCostLTE(v float64) predicate.UsageLog {
+func TotalCostLTE(v money.Amount) predicate.UsageLog {
 	return predicate.UsageLog(sql.FieldLTE(FieldTotalCost, v))
 }
 
 // ActualCostEQ applies the EQ predicate on the "actual_cost" field.
-func ActualCostEQ(v float64) predicate.UsageLog {
+func ActualCostEQ(v money.Amount) predicate.UsageLog {
 	return predicate.UsageLog(sql.FieldEQ(FieldActualCost, v))
 }
 
 // ActualCostNEQ applies the NEQ predicate on the "actual_cost" field.
-func ActualCostNEQ(v float64) predicate.UsageLog {
+func ActualCostNEQ(v money.Amount) predicate.UsageLog {
 	return predicate.UsageLog(sql.FieldNEQ(FieldActualCost, v))
 }
 
 // ActualCostIn applies the In predicate on the "actual_cost" field.
-func ActualCostIn(vs ...float64) predicate.UsageLog {
+func ActualCostIn(vs ...money.Amount) predicate.UsageLog {
 	return predicate.UsageLog(sql.FieldIn(FieldActualCost, vs...))
 }
 
 // ActualCostNotIn applies the NotIn predicate on the "actual_cost" field.
-func ActualCostNotIn(vs ...float64) predicate.UsageLog {
+func ActualCostNotIn(vs ...money.Amount) predicate.UsageLog {
 	return predicate.UsageLog(sql.FieldNotIn(FieldActualCost, vs...))
 }
 
 // ActualCostGT applies the GT predicate on the "actual_cost" field.
-func ActualCostGT(v float64) predicate.UsageLog {
+func ActualCostGT(v money.Amount) predicate.UsageLog {
 	return predicate.UsageLog(sql.FieldGT(FieldActualCost, v))
 }
 
 // ActualCostGTE applies the GTE predicate on the "actual_cost" field.
-func ActualCostGTE(v float64) predicate.UsageLog {
+func ActualCostGTE(v money.Amount) predicate.UsageLog {
 	return predicate.UsageLog(sql.FieldGTE(FieldActualCost, v))
 }
 
 // ActualCostLT applies the LT predicate on the "actual_cost" field.
-func ActualCostLT(v float64) predicate.UsageLog {
+func ActualCostLT(v money.Amount) predicate.UsageLog {
 	return predicate.UsageLog(sql.FieldLT(FieldActualCost, v))
 }
 
 // ActualCostLTE applies the LTE predicate on the "actual_cost" field.
-func ActualCostLTE(v float64) predicate.UsageLog {
+func ActualCostLTE(v money.Amount) predicate.UsageLog {
 	return predicate.UsageLog(sql.FieldLTE(FieldActualCost, v))
 }
 
@@ -1525,6 +1536,106 @@ func MediaTypeContainsFold(v string) predicate.UsageLog {
 	return predicate.UsageLog(sql.FieldContainsFold(FieldMediaType, v))
 }
 
+// AudioDurationSecondsEQ applies the EQ predicate on the "audio_duration_seconds" field.
+func AudioDurationSecondsEQ(v float64) predicate.UsageLog {
+	return predicate.UsageLog(sql.FieldEQ(FieldAudioDurationSeconds, v))
+}
+
+// AudioDurationSecondsNEQ applies the NEQ predicate on the "audio_duration_seconds" field.
+func AudioDurationSecondsNEQ(v float64) predicate.UsageLog {
+	return predicate.UsageLog(sql.FieldNEQ(FieldAudioDurationSeconds, v))
+}
+
+// AudioDurationSecondsIn applies the In predicate on the "audio_duration_seconds" field.
+func AudioDurationSecondsIn(vs ...float64) predicate.UsageLog {
+	return predicate.UsageLog(sql.FieldIn(FieldAudioDurationSeconds, vs...))
+}
+
+// AudioDurationSecondsNotIn applies the NotIn predicate on the "audio_duration_seconds" field.
+func AudioDurationSecondsNotIn(vs ...float64) predicate.UsageLog {
+	return predicate.UsageLog(sql.FieldNotIn(FieldAudioDurationSeconds, vs...))
+}
+
+// AudioDurationSecondsGT applies the GT predicate on the "audio_duration_seconds" field.
+func AudioDurationSecondsGT(v float64) predicate.UsageLog {
+	return predicate.UsageLog(sql.FieldGT(FieldAudioDurationSeconds, v))
+}
+
+// AudioDurationSecondsGTE applies the GTE predicate on the "audio_duration_seconds" field.
+func AudioDurationSecondsGTE(v float64) predicate.UsageLog {
+	return predicate.UsageLog(sql.FieldGTE(FieldAudioDurationSeconds, v))
+}
+
+// AudioDurationSecondsLT applies the LT predicate on the "audio_duration_seconds" field.
+func AudioDurationSecondsLT(v float64) predicate.UsageLog {
+	return predicate.UsageLog(sql.FieldLT(FieldAudioDurationSeconds, v))
+}
+
+// AudioDurationSecondsLTE applies the LTE predicate on the "audio_duration_seconds" field.
+func AudioDurationSecondsLTE(v float64) predicate.UsageLog {
+	return predicate.UsageLog(sql.FieldLTE(FieldAudioDurationSeconds, v))
+}
+
+// AudioDurationSecondsIsNil applies the IsNil predicate on the "audio_duration_seconds" field.
+func AudioDurationSecondsIsNil() predicate.UsageLog {
+	return predicate.UsageLog(sql.FieldIsNull(FieldAudioDurationSeconds))
+}
+
+// AudioDurationSecondsNotNil applies the NotNil predicate on the "audio_duration_seconds" field.
+func AudioDurationSecondsNotNil() predicate.UsageLog {
+	return predicate.UsageLog(sql.FieldNotNull(FieldAudioDurationSeconds))
+}
+
+// AudioCharactersEQ applies the EQ predicate on the "audio_characters" field.
+func AudioCharactersEQ(v int) predicate.UsageLog {
+	return predicate.UsageLog(sql.FieldEQ(FieldAudioCharacters, v))
+}
+
+// AudioCharactersNEQ applies the NEQ predicate on the "audio_characters" field.
+func AudioCharactersNEQ(v int) predicate.UsageLog {
+	return predicate.UsageLog(sql.FieldNEQ(FieldAudioCharacters, v))
+}
+
+// AudioCharactersIn applies the In predicate on the "audio_characters" field.
+func AudioCharactersIn(vs ...int) predicate.UsageLog {
+	return predicate.UsageLog(sql.FieldIn(FieldAudioCharacters, vs...))
+}
+
+// AudioCharactersNotIn applies the NotIn predicate on the "audio_characters" field.
+func AudioCharactersNotIn(vs ...int) predicate.UsageLog {
+	return predicate.UsageLog(sql.FieldNotIn(FieldAudioCharacters, vs...))
+}
+
+// AudioCharactersGT applies the GT predicate on the "audio_characters" field.
+func AudioCharactersGT(v int) predicate.UsageLog {
+	return predicate.UsageLog(sql.FieldGT(FieldAudioCharacters, v))
+}
+
+// AudioCharactersGTE applies the GTE predicate on the "audio_characters" field.
+func AudioCharactersGTE(v int) predicate.UsageLog {
+	return predicate.UsageLog(sql.FieldGTE(FieldAudioCharacters, v))
+}
+
+// AudioCharactersLT applies the LT predicate on the "audio_characters" field.
+func AudioCharactersLT(v int) predicate.UsageLog {
+	return predicate.UsageLog(sql.FieldLT(FieldAudioCharacters, v))
+}
+
+// AudioCharactersLTE applies the LTE predicate on the "audio_characters" field.
+func AudioCharactersLTE(v int) predicate.UsageLog {
+	return predicate.UsageLog(sql.FieldLTE(FieldAudioCharacters, v))
+}
+
+// AudioCharactersIsNil applies the IsNil predicate on the "audio_characters" field.
+func AudioCharactersIsNil() predicate.UsageLog {
+	return predicate.UsageLog(sql.FieldIsNull(FieldAudioCharacters))
+}
+
+// AudioCharactersNotNil applies the NotNil predicate on the "audio_characters" field.
+func AudioCharactersNotNil() predicate.UsageLog {
+	return predicate.UsageLog(sql.FieldNotNull(FieldAudioCharacters))
+}
+
 // CacheTTLOverriddenEQ applies the EQ predicate on the "cache_ttl_overridden" field.
 func CacheTTLOverriddenEQ(v bool) predicate.UsageLog {
 	return predicate.UsageLog(sql.FieldEQ(FieldCacheTTLOverridden, v))
@@ -17,6 +17,7 @@ import (
 	"github.com/Wei-Shaw/sub2api/ent/usagelog"
 	"github.com/Wei-Shaw/sub2api/ent/user"
 	"github.com/Wei-Shaw/sub2api/ent/usersubscription"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
 )
 
 // UsageLogCreate is the builder for creating a UsageLog entity.
@@ -170,13 +171,13 @@ func (_c *UsageLogCreate) SetNillableCacheCreation1hTokens(v *int) *UsageLogCrea
 }
 
 // SetInputCost sets the "input_cost" field.
-func (_c *UsageLogCreate) SetInputCost(v float64) *UsageLogCreate {
+func (_c *UsageLogCreate) SetInputCost(v money.Amount) *UsageLogCreate {
 	_c.mutation.SetInputCost(v)
 	return _c
 }
 
 // SetNillableInputCost sets the "input_cost" field if the given value is not nil.
-func (_c *UsageLogCreate) SetNillableInputCost(v *float64) *UsageLogCreate {
+func (_c *UsageLogCreate) SetNillableInputCost(v *money.Amount) *UsageLogCreate {
 	if v != nil {
 		_c.SetInputCost(*v)
 	}
@@ -184,13 +185,13 @@ func (_c *UsageLogCreate) SetNillableInputCost(v *float64) *UsageLogCreate {
 }
 
 // SetOutputCost sets the "output_cost" field.
-func (_c *UsageLogCreate) SetOutputCost(v float64) *UsageLogCreate {
+func (_c *UsageLogCreate) SetOutputCost(v money.Amount) *UsageLogCreate {
 	_c.mutation.SetOutputCost(v)
 	return _c
 }
 
 // SetNillableOutputCost sets the "output_cost" field if the given value is not nil.
-func (_c *UsageLogCreate) SetNillableOutputCost(v *float64) *UsageLogCreate {
+func (_c *UsageLogCreate) SetNillableOutputCost(v *money.Amount) *UsageLogCreate {
 	if v != nil {
 		_c.SetOutputCost(*v)
 	}
@@ -198,13 +199,13 @@ func (_c *UsageLogCreate) SetNillableOutputCost(v *float64) *UsageLogCreate {
 }
 
 // SetCacheCreationCost sets the "cache_creation_cost" field.
-func (_c *UsageLogCreate) SetCacheCreationCost(v float64) *UsageLogCreate {
+func (_c *UsageLogCreate) SetCacheCreationCost(v money.Amount) *UsageLogCreate {
 	_c.mutation.SetCacheCreationCost(v)
 	return _c
 }
 
 // SetNillableCacheCreationCost sets the "cache_creation_cost" field if the given value is not nil.
-func (_c *UsageLogCreate) SetNillableCacheCreationCost(v *float64) *UsageLogCreate {
+func (_c *UsageLogCreate) SetNillableCacheCreationCost(v *money.Amount) *UsageLogCreate {
 	if v != nil {
 		_c.SetCacheCreationCost(*v)
 	}
@@ -212,13 +213,13 @@ func (_c *UsageLogCreate) SetNillableCacheCreationCost(v *float64) *UsageLogCrea
 }
 
 // SetCacheReadCost sets the "cache_read_cost" field.
-func (_c *UsageLogCreate) SetCacheReadCost(v float64) *UsageLogCreate {
+func (_c *UsageLogCreate) SetCacheReadCost(v money.Amount) *UsageLogCreate {
 	_c.mutation.SetCacheReadCost(v)
 	return _c
 }
 
 // SetNillableCacheReadCost sets the "cache_read_cost" field if the given value is not nil.
-func (_c *UsageLogCreate) SetNillableCacheReadCost(v *float64) *UsageLogCreate {
+func (_c *UsageLogCreate) SetNillableCacheReadCost(v *money.Amount) *UsageLogCreate {
 	if v != nil {
 		_c.SetCacheReadCost(*v)
 	}
@@ -226,13 +227,13 @@ func (_c *UsageLogCreate) SetNillableCacheReadCost(v *float64) *UsageLogCreate {
 }
 
 // SetTotalCost sets the "total_cost" field.
-func (_c *UsageLogCreate) SetTotalCost(v float64) *UsageLogCreate {
+func (_c *UsageLogCreate) SetTotalCost(v money.Amount) *UsageLogCreate {
 	_c.mutation.SetTotalCost(v)
 	return _c
 }
 
 // SetNillableTotalCost sets the "total_cost" field if the given value is not nil.
-func (_c *UsageLogCreate) SetNillableTotalCost(v *float64) *UsageLogCreate {
+func (_c *UsageLogCreate) SetNillableTotalCost(v *money.Amount) *UsageLogCreate {
 	if v != nil {
 		_c.SetTotalCost(*v)
 	}
@@ -240,13 +241,13 @@ func (_c *UsageLogCreate) SetNillableTotalCost(v *float64) *UsageLogCreate {
 }
 
 // SetActualCost sets the "actual_cost" field.
-func (_c *UsageLogCreate) SetActualCost(v float64) *UsageLogCreate {
+func (_c *UsageLogCreate) SetActualCost(v money.Amount) *UsageLogCreate {
 	_c.mutation.SetActualCost(v)
 	return _c
 }
 
 // SetNillableActualCost sets the "actual_cost" field if the given value is not nil.
-func (_c *UsageLogCreate) SetNillableActualCost(v *float64) *UsageLogCreate {
+func (_c *UsageLogCreate) SetNillableActualCost(v *money.Amount) *UsageLogCreate {
 	if v != nil {
 		_c.SetActualCost(*v)
 	}
@@ -407,6 +408,34 @@ func (_c *UsageLogCreate) SetNillableMediaType(v *string) *UsageLogCreate {
 	return _c
 }
 
+// SetAudioDurationSeconds sets the "audio_duration_seconds" field.
+func (_c *UsageLogCreate) SetAudioDurationSeconds(v float64) *UsageLogCreate {
+	_c.mutation.SetAudioDurationSeconds(v)
+	return _c
+}
+
+// SetNillableAudioDurationSeconds sets the "audio_duration_seconds" field if the given value is not nil.
+func (_c *UsageLogCreate) SetNillableAudioDurationSeconds(v *float64) *UsageLogCreate {
+	if v != nil {
+		_c.SetAudioDurationSeconds(*v)
+	}
+	return _c
+}
+
+// SetAudioCharacters sets the "audio_characters" field.
+func (_c *UsageLogCreate) SetAudioCharacters(v int) *UsageLogCreate {
+	_c.mutation.SetAudioCharacters(v)
+	return _c
+}
+
+// SetNillableAudioCharacters sets the "audio_characters" field if the given value is not nil.
+func (_c *UsageLogCreate) SetNillableAudioCharacters(v *int) *UsageLogCreate {
+	if v != nil {
+		_c.SetAudioCharacters(*v)
+	}
+	return _c
+}
+
 // SetCacheTTLOverridden sets the "cache_ttl_overridden" field.
 func (_c *UsageLogCreate) SetCacheTTLOverridden(v bool) *UsageLogCreate {
 	_c.mutation.SetCacheTTLOverridden(v)
@@ -739,27 +768,27 @@ func (_c *UsageLogCreate) createSpec() (*UsageLog, *sqlgraph.CreateSpec) {
 		_node.CacheCreation1hTokens = value
 	}
 	if value, ok := _c.mutation.InputCost(); ok {
-		_spec.SetField(usagelog.FieldInputCost, field.TypeFloat64, value)
+		_spec.SetField(usagelog.FieldInputCost, field.TypeInt64, value)
 		_node.InputCost = value
 	}
 	if value, ok := _c.mutation.OutputCost(); ok {
-		_spec.SetField(usagelog.FieldOutputCost, field.TypeFloat64, value)
+		_spec.SetField(usagelog.FieldOutputCost, field.TypeInt64, value)
 		_node.OutputCost = value
 	}
 	if value, ok := _c.mutation.CacheCreationCost(); ok {
-		_spec.SetField(usagelog.FieldCacheCreationCost, field.TypeFloat64, value)
+		_spec.SetField(usagelog.FieldCacheCreationCost, field.TypeInt64, value)
 		_node.CacheCreationCost = value
 	}
 	if value, ok := _c.mutation.CacheReadCost(); ok {
-		_spec.SetField(usagelog.FieldCacheReadCost, field.TypeFloat64, value)
+		_spec.SetField(usagelog.FieldCacheReadCost, field.TypeInt64, value)
 		_node.CacheReadCost = value
 	}
 	if value, ok := _c.mutation.TotalCost(); ok {
-		_spec.SetField(usagelog.FieldTotalCost, field.TypeFloat64, value)
+		_spec.SetField(usagelog.FieldTotalCost, field.TypeInt64, value)
 		_node.TotalCost = value
 	}
 	if value, ok := _c.mutation.ActualCost(); ok {
-		_spec.SetField(usagelog.FieldActualCost, field.TypeFloat64, value)
+		_spec.SetField(usagelog.FieldActualCost, field.TypeInt64, value)
 		_node.ActualCost = value
 	}
 	if value, ok := _c.mutation.RateMultiplier(); ok {
@@ -806,6 +835,14 @@ func (_c *UsageLogCreate) createSpec() (*UsageLog, *sqlgraph.CreateSpec) {
 		_spec.SetField(usagelog.FieldMediaType, field.TypeString, value)
 		_node.MediaType = &value
 	}
+	if value, ok := _c.mutation.AudioDurationSeconds(); ok {
+		_spec.SetField(usagelog.FieldAudioDurationSeconds, field.TypeFloat64, value)
+		_node.AudioDurationSeconds = &value
+	}
+	if value, ok := _c.mutation.AudioCharacters(); ok {
+		_spec.SetField(usagelog.FieldAudioCharacters, field.TypeInt, value)
+		_node.AudioCharacters = &value
+	}
 	if value, ok := _c.mutation.CacheTTLOverridden(); ok {
 		_spec.SetField(usagelog.FieldCacheTTLOverridden, field.TypeBool, value)
 		_node.CacheTTLOverridden = value
@@ -1156,7 +1193,7 @@ func (u *UsageLogUpsert) AddCacheCreation1hTokens(v int) *UsageLogUpsert {
 }
 
 // SetInputCost sets the "input_cost" field.
-func (u *UsageLogUpsert) SetInputCost(v float64) *UsageLogUpsert {
+func (u *UsageLogUpsert) SetInputCost(v money.Amount) *UsageLogUpsert {
 	u.Set(usagelog.FieldInputCost, v)
 	return u
 }
@@ -1168,13 +1205,13 @@ func (u *UsageLogUpsert) UpdateInputCost() *UsageLogUpsert {
 }
 
 // AddInputCost adds v to the "input_cost" field.
-func (u *UsageLogUpsert) AddInputCost(v float64) *UsageLogUpsert {
+func (u *UsageLogUpsert) AddInputCost(v money.Amount) *UsageLogUpsert {
 	u.Add(usagelog.FieldInputCost, v)
 	return u
 }
 
 // SetOutputCost sets the "output_cost" field.
-func (u *UsageLogUpsert) SetOutputCost(v float64) *UsageLogUpsert {
+func (u *UsageLogUpsert) SetOutputCost(v money.Amount) *UsageLogUpsert {
 	u.Set(usagelog.FieldOutputCost, v)
 	return u
 }
@@ -1186,13 +1223,13 @@ func (u *UsageLogUpsert) UpdateOutputCost() *UsageLogUpsert {
 }
 
 // AddOutputCost adds v to the "output_cost" field.
-func (u *UsageLogUpsert) AddOutputCost(v float64) *UsageLogUpsert {
+func (u *UsageLogUpsert) AddOutputCost(v money.Amount) *UsageLogUpsert {
 	u.Add(usagelog.FieldOutputCost, v)
 	return u
 }
 
 // SetCacheCreationCost sets the "cache_creation_cost" field.
-func (u *UsageLogUpsert) SetCacheCreationCost(v float64) *UsageLogUpsert {
+func (u *UsageLogUpsert) SetCacheCreationCost(v money.Amount) *UsageLogUpsert {
 	u.Set(usagelog.FieldCacheCreationCost, v)
 	return u
 }
@@ -1204,13 +1241,13 @@ func (u *UsageLogUpsert) UpdateCacheCreationCost() *UsageLogUpsert {
 }
 
 // AddCacheCreationCost adds v to the "cache_creation_cost" field.
-func (u *UsageLogUpsert) AddCacheCreationCost(v float64) *UsageLogUpsert {
+func (u *UsageLogUpsert) AddCacheCreationCost(v money.Amount) *UsageLogUpsert {
 	u.Add(usagelog.FieldCacheCreationCost, v)
 	return u
 }
 
 // SetCacheReadCost sets the "cache_read_cost" field.
-func (u *UsageLogUpsert) SetCacheReadCost(v float64) *UsageLogUpsert {
+func (u *UsageLogUpsert) SetCacheReadCost(v money.Amount) *UsageLogUpsert {
 	u.Set(usagelog.FieldCacheReadCost, v)
 	return u
 }
@@ -1222,13 +1259,13 @@ func (u *UsageLogUpsert) UpdateCacheReadCost() *UsageLogUpsert {
 }
 
 // AddCacheReadCost adds v to the "cache_read_cost" field.
-func (u *UsageLogUpsert) AddCacheReadCost(v float64) *UsageLogUpsert {
+func (u *UsageLogUpsert) AddCacheReadCost(v money.Amount) *UsageLogUpsert {
 	u.Add(usagelog.FieldCacheReadCost, v)
 	return u
 }
 
 // SetTotalCost sets the "total_cost" field.
-func (u *UsageLogUpsert) SetTotalCost(v float64) *UsageLogUpsert {
+func (u *UsageLogUpsert) SetTotalCost(v money.Amount) *UsageLogUpsert {
 	u.Set(usagelog.FieldTotalCost, v)
 	return u
 }
@@ -1240,13 +1277,13 @@ func (u *UsageLogUpsert) UpdateTotalCost() *UsageLogUpsert {
 }
 
 // AddTotalCost adds v to the "total_cost" field.
-func (u *UsageLogUpsert) AddTotalCost(v float64) *UsageLogUpsert {
+func (u *UsageLogUpsert) AddTotalCost(v money.Amount) *UsageLogUpsert {
 	u.Add(usagelog.FieldTotalCost, v)
 	return u
 }
 
 // SetActualCost sets the "actual_cost" field.
-func (u *UsageLogUpsert) SetActualCost(v float64) *UsageLogUpsert {
+func (u *UsageLogUpsert) SetActualCost(v money.Amount) *UsageLogUpsert {
 	u.Set(usagelog.FieldActualCost, v)
 	return u
 }
@@ -1258,7 +1295,7 @@ func (u *UsageLogUpsert) UpdateActualCost() *UsageLogUpsert {
 }
 
 // AddActualCost adds v to the "actual_cost" field.
-func (u *UsageLogUpsert) AddActualCost(v float64) *UsageLogUpsert {
+func (u *UsageLogUpsert) AddActualCost(v money.Amount) *UsageLogUpsert {
 	u.Add(usagelog.FieldActualCost, v)
 	return u
 }
@@ -1473,6 +1510,54 @@ func (u *UsageLogUpsert) ClearMediaType() *UsageLogUpsert {
 	return u
 }
 
+// SetAudioDurationSeconds sets the "audio_duration_seconds" field.
+func (u *UsageLogUpsert) SetAudioDurationSeconds(v float64) *UsageLogUpsert {
+	u.Set(usagelog.FieldAudioDurationSeconds, v)
+	return u
+}
+
+// UpdateAudioDurationSeconds sets the "audio_duration_seconds" field to the value that was provided on create.
+func (u *UsageLogUpsert) UpdateAudioDurationSeconds() *UsageLogUpsert {
+	u.SetExcluded(usagelog.FieldAudioDurationSeconds)
+	return u
+}
+
+// AddAudioDurationSeconds adds v to the "audio_duration_seconds" field.
+func (u *UsageLogUpsert) AddAudioDurationSeconds(v float64) *UsageLogUpsert {
+	u.Add(usagelog.FieldAudioDurationSeconds, v)
+	return u
+}
+
+// ClearAudioDurationSeconds clears the value of the "audio_duration_seconds" field.
+func (u *UsageLogUpsert) ClearAudioDurationSeconds() *UsageLogUpsert {
+	u.SetNull(usagelog.FieldAudioDurationSeconds)
+	return u
+}
+
+// SetAudioCharacters sets the "audio_characters" field.
+func (u *UsageLogUpsert) SetAudioCharacters(v int) *UsageLogUpsert {
+	u.Set(usagelog.FieldAudioCharacters, v)
+	return u
+}
+
+// UpdateAudioCharacters sets the "audio_characters" field to the value that was provided on create.
+func (u *UsageLogUpsert) UpdateAudioCharacters() *UsageLogUpsert {
+	u.SetExcluded(usagelog.FieldAudioCharacters)
+	return u
+}
+
+// AddAudioCharacters adds v to the "audio_characters" field.
+func (u *UsageLogUpsert) AddAudioCharacters(v int) *UsageLogUpsert {
+	u.Add(usagelog.FieldAudioCharacters, v)
+	return u
+}
+
+// ClearAudioCharacters clears the value of the "audio_characters" field.
+func (u *UsageLogUpsert) ClearAudioCharacters() *UsageLogUpsert {
+	u.SetNull(usagelog.FieldAudioCharacters)
+	return u
+}
+
 // SetCacheTTLOverridden sets the "cache_ttl_overridden" field.
 func (u *UsageLogUpsert) SetCacheTTLOverridden(v bool) *UsageLogUpsert {
 	u.Set(usagelog.FieldCacheTTLOverridden, v)
@@ -1769,14 +1854,14 @@ func (u *UsageLogUpsertOne) UpdateCacheCreation1hTokens() *UsageLogUpsertOne {
 }
 
 // SetInputCost sets the "input_cost" field.
-func (u *UsageLogUpsertOne) SetInputCost(v float64) *UsageLogUpsertOne {
+func (u *UsageLogUpsertOne) SetInputCost(v money.Amount) *UsageLogUpsertOne {
 	return u.Update(func(s *UsageLogUpsert) {
 		s.SetInputCost(v)
 	})
 }
 
 // AddInputCost adds v to the "input_cost" field.
-func (u *UsageLogUpsertOne) AddInputCost(v float64) *UsageLogUpsertOne {
+func (u *UsageLogUpsertOne) AddInputCost(v money.Amount) *UsageLogUpsertOne {
 	return u.Update(func(s *UsageLogUpsert) {
 		s.AddInputCost(v)
 	})
@@ -1790,14 +1875,14 @@ func (u *UsageLogUpsertOne) UpdateInputCost() *UsageLogUpsertOne {
 }
 
 // SetOutputCost sets the "output_cost" field.
-func (u *UsageLogUpsertOne) SetOutputCost(v float64) *UsageLogUpsertOne {
+func (u *UsageLogUpsertOne) SetOutputCost(v money.Amount) *UsageLogUpsertOne {
 	return u.Update(func(s *UsageLogUpsert) {
 		s.SetOutputCost(v)
 	})
 }
 
 // AddOutputCost adds v to the "output_cost" field.
-func (u *UsageLogUpsertOne) AddOutputCost(v float64) *UsageLogUpsertOne {
+func (u *UsageLogUpsertOne) AddOutputCost(v money.Amount) *UsageLogUpsertOne {
 	return u.Update(func(s *UsageLogUpsert) {
 		s.AddOutputCost(v)
 	})
@@ -1811,14 +1896,14 @@ func (u *UsageLogUpsertOne) UpdateOutputCost() *UsageLogUpsertOne {
 }
 
 // SetCacheCreationCost sets the "cache_creation_cost" field.
-func (u *UsageLogUpsertOne) SetCacheCreationCost(v float64) *UsageLogUpsertOne {
+func (u *UsageLogUpsertOne) SetCacheCreationCost(v money.Amount) *UsageLogUpsertOne {
 	return u.Update(func(s *UsageLogUpsert) {
 		s.SetCacheCreationCost(v)
 	})
 }
 
 // AddCacheCreationCost adds v to the "cache_creation_cost" field.
-func (u *UsageLogUpsertOne) AddCacheCreationCost(v float64) *UsageLogUpsertOne {
+func (u *UsageLogUpsertOne) AddCacheCreationCost(v money.Amount) *UsageLogUpsertOne {
 	return u.Update(func(s *UsageLogUpsert) {
 		s.AddCacheCreationCost(v)
 	})
@@ -1832,14 +1917,14 @@ func (u *UsageLogUpsertOne) UpdateCacheCreationCost() *UsageLogUpsertOne {
 }
 
 // SetCacheReadCost sets the "cache_read_cost" field.
-func (u *UsageLogUpsertOne) SetCacheReadCost(v float64) *UsageLogUpsertOne {
+func (u *UsageLogUpsertOne) SetCacheReadCost(v money.Amount) *UsageLogUpsertOne {
 	return u.Update(func(s *UsageLogUpsert) {
 		s.SetCacheReadCost(v)
 	})
 }
 
 // AddCacheReadCost adds v to the "cache_read_cost" field.
-func (u *UsageLogUpsertOne) AddCacheReadCost(v float64) *UsageLogUpsertOne {
+func (u *UsageLogUpsertOne) AddCacheReadCost(v money.Amount) *UsageLogUpsertOne {
 	return u.Update(func(s *UsageLogUpsert) {
 		s.AddCacheReadCost(v)
 	})
@@ -1853,14 +1938,14 @@ func (u *UsageLogUpsertOne) UpdateCacheReadCost() *UsageLogUpsertOne {
 }
 
 // SetTotalCost sets the "total_cost" field.
-func (u *UsageLogUpsertOne) SetTotalCost(v float64) *UsageLogUpsertOne {
+func (u *UsageLogUpsertOne) SetTotalCost(v money.Amount) *UsageLogUpsertOne {
 	return u.Update(func(s *UsageLogUpsert) {
 		s.SetTotalCost(v)
 	})
 }
 
 // AddTotalCost adds v to the "total_cost" field.
-func (u *UsageLogUpsertOne) AddTotalCost(v float64) *UsageLogUpsertOne {
+func (u *UsageLogUpsertOne) AddTotalCost(v money.Amount) *UsageLogUpsertOne {
 	return u.Update(func(s *UsageLogUpsert) {
 		s.AddTotalCost(v)
 	})
@@ -1874,14 +1959,14 @@ func (u *UsageLogUpsertOne) UpdateTotalCost() *UsageLogUpsertOne {
 }
 
 // SetActualCost sets the "actual_cost" field.
-func (u *UsageLogUpsertOne) SetActualCost(v float64) *UsageLogUpsertOne {
+func (u *UsageLogUpsertOne) SetActualCost(v money.Amount) *UsageLogUpsertOne {
 	return u.Update(func(s *UsageLogUpsert) {
 		s.SetActualCost(v)
 	})
 }
 
 // AddActualCost adds v to the "actual_cost" field.
-func (u *UsageLogUpsertOne) AddActualCost(v float64) *UsageLogUpsertOne {
+func (u *UsageLogUpsertOne) AddActualCost(v money.Amount) *UsageLogUpsertOne {
 	return u.Update(func(s *UsageLogUpsert) {
 		s.AddActualCost(v)
 	})
@@ -2139,6 +2224,62 @@ func (u *UsageLogUpsertOne) ClearMediaType() *UsageLogUpsertOne {
 	})
 }
 
+// SetAudioDurationSeconds sets the "audio_duration_seconds" field.
+func (u *UsageLogUpsertOne) SetAudioDurationSeconds(v float64) *UsageLogUpsertOne {
+	return u.Update(func(s *UsageLogUpsert) {
+		s.SetAudioDurationSeconds(v)
+	})
+}
+
+// AddAudioDurationSeconds adds v to the "audio_duration_seconds" field.
+func (u *UsageLogUpsertOne) AddAudioDurationSeconds(v float64) *UsageLogUpsertOne {
+	return u.Update(func(s *UsageLogUpsert) {
+		s.AddAudioDurationSeconds(v)
+	})
+}
+
+// UpdateAudioDurationSeconds sets the "audio_duration_seconds" field to the value that was provided on create.
+func (u *UsageLogUpsertOne) UpdateAudioDurationSeconds() *UsageLogUpsertOne {
+	return u.Update(func(s *UsageLogUpsert) {
+		s.UpdateAudioDurationSeconds()
+	})
+}
+
+// ClearAudioDurationSeconds clears the value of the "audio_duration_seconds" field.
+func (u *UsageLogUpsertOne) ClearAudioDurationSeconds() *UsageLogUpsertOne {
+	return u.Update(func(s *UsageLogUpsert) {
+		s.ClearAudioDurationSeconds()
+	})
+}
+
+// SetAudioCharacters sets the "audio_characters" field.
+func (u *UsageLogUpsertOne) SetAudioCharacters(v int) *UsageLogUpsertOne {
+	return u.Update(func(s *UsageLogUpsert) {
+		s.SetAudioCharacters(v)
+	})
+}
+
+// AddAudioCharacters adds v to the "audio_characters" field.
+func (u *UsageLogUpsertOne) AddAudioCharacters(v int) *UsageLogUpsertOne {
+	return u.Update(func(s *UsageLogUpsert) {
+		s.AddAudioCharacters(v)
+	})
+}
+
+// UpdateAudioCharacters sets the "audio_characters" field to the value that was provided on create.
+func (u *UsageLogUpsertOne) UpdateAudioCharacters() *UsageLogUpsertOne {
+	return u.Update(func(s *UsageLogUpsert) {
+		s.UpdateAudioCharacters()
+	})
+}
+
+// ClearAudioCharacters clears the value of the "audio_characters" field.
+func (u *UsageLogUpsertOne) ClearAudioCharacters() *UsageLogUpsertOne {
+	return u.Update(func(s *UsageLogUpsert) {
+		s.ClearAudioCharacters()
+	})
+}
+
 // SetCacheTTLOverridden sets the "cache_ttl_overridden" field.
 func (u *UsageLogUpsertOne) SetCacheTTLOverridden(v bool) *UsageLogUpsertOne {
 	return u.Update(func(s *UsageLogUpsert) {
@@ -2603,14 +2744,14 @@ func (u *UsageLogUpsertBulk) UpdateCacheCreation1hTokens() *UsageLogUpsertBulk {
 }
 
 // SetInputCost sets the "input_cost" field.
-func (u *UsageLogUpsertBulk) SetInputCost(v float64) *UsageLogUpsertBulk {
+func (u *UsageLogUpsertBulk) SetInputCost(v money.Amount) *UsageLogUpsertBulk {
 	return u.Update(func(s *UsageLogUpsert) {
 		s.SetInputCost(v)
 	})
 }
 
 // AddInputCost adds v to the "input_cost" field.
-func (u *UsageLogUpsertBulk) AddInputCost(v float64) *UsageLogUpsertBulk {
+func (u *UsageLogUpsertBulk) AddInputCost(v money.Amount) *UsageLogUpsertBulk {
 	return u.Update(func(s *UsageLogUpsert) {
 		s.AddInputCost(v)
 	})
@@ -2624,14 +2765,14 @@ func (u *UsageLogUpsertBulk) UpdateInputCost() *UsageLogUpsertBulk {
 }
 
 // SetOutputCost sets the "output_cost" field.
-func (u *UsageLogUpsertBulk) SetOutputCost(v float64) *UsageLogUpsertBulk {
+func (u *UsageLogUpsertBulk) SetOutputCost(v money.Amount) *UsageLogUpsertBulk {
 	return u.Update(func(s *UsageLogUpsert) {
 		s.SetOutputCost(v)
 	})
 }
 
 // AddOutputCost adds v to the "output_cost" field.
-func (u *UsageLogUpsertBulk) AddOutputCost(v float64) *UsageLogUpsertBulk {
+func (u *UsageLogUpsertBulk) AddOutputCost(v money.Amount) *UsageLogUpsertBulk {
 	return u.Update(func(s *UsageLogUpsert) {
 		s.AddOutputCost(v)
 	})
@@ -2645,14 +2786,14 @@ func (u *UsageLogUpsertBulk) UpdateOutputCost() *UsageLogUpsertBulk {
 }
 
 // SetCacheCreationCost sets the "cache_creation_cost" field.
-func (u *UsageLogUpsertBulk) SetCacheCreationCost(v float64) *UsageLogUpsertBulk {
+func (u *UsageLogUpsertBulk) SetCacheCreationCost(v money.Amount) *UsageLogUpsertBulk {
 	return u.Update(func(s *UsageLogUpsert) {
 		s.SetCacheCreationCost(v)
 	})
 }
 
 // AddCacheCreationCost adds v to the "cache_creation_cost" field.
-func (u *UsageLogUpsertBulk) AddCacheCreationCost(v float64) *UsageLogUpsertBulk {
+func (u *UsageLogUpsertBulk) AddCacheCreationCost(v money.Amount) *UsageLogUpsertBulk {
 	return u.Update(func(s *UsageLogUpsert) {
 		s.AddCacheCreationCost(v)
 	})
@@ -2666,14 +2807,14 @@ func (u *UsageLogUpsertBulk) UpdateCacheCreationCost() *UsageLogUpsertBulk {
 }
 
 // SetCacheReadCost sets the "cache_read_cost" field.
-func (u *UsageLogUpsertBulk) SetCacheReadCost(v float64) *UsageLogUpsertBulk {
+func (u *UsageLogUpsertBulk) SetCacheReadCost(v money.Amount) *UsageLogUpsertBulk {
 	return u.Update(func(s *UsageLogUpsert) {
 		s.SetCacheReadCost(v)
 	})
 }
 
 // AddCacheReadCost adds v to the "cache_read_cost" field.
-func (u *UsageLogUpsertBulk) AddCacheReadCost(v float64) *UsageLogUpsertBulk {
+func (u *UsageLogUpsertBulk) AddCacheReadCost(v money.Amount) *UsageLogUpsertBulk {
 	return u.Update(func(s *UsageLogUpsert) {
 		s.AddCacheReadCost(v)
 	})
@@ -2687,14 +2828,14 @@ func (u *UsageLogUpsertBulk) UpdateCacheReadCost() *UsageLogUpsertBulk {
 }
 
 // SetTotalCost sets the "total_cost" field.
-func (u *UsageLogUpsertBulk) SetTotalCost(v float64) *UsageLogUpsertBulk {
+func (u *UsageLogUpsertBulk) SetTotalCost(v money.Amount) *UsageLogUpsertBulk {
 	return u.Update(func(s *UsageLogUpsert) {
 		s.SetTotalCost(v)
 	})
 }
 
 // AddTotalCost adds v to the "total_cost" field.
-func (u *UsageLogUpsertBulk) AddTotalCost(v float64) *UsageLogUpsertBulk {
+func (u *UsageLogUpsertBulk) AddTotalCost(v money.Amount) *UsageLogUpsertBulk {
 	return u.Update(func(s *UsageLogUpsert) {
 		s.AddTotalCost(v)
 	})
@@ -2708,14 +2849,14 @@ func (u *UsageLogUpsertBulk) UpdateTotalCost() *UsageLogUpsertBulk {
 }
 
 // SetActualCost sets the "actual_cost" field.
-func (u *UsageLogUpsertBulk) SetActualCost(v float64) *UsageLogUpsertBulk {
+func (u *UsageLogUpsertBulk) SetActualCost(v money.Amount) *UsageLogUpsertBulk {
 	return u.Update(func(s *UsageLogUpsert) {
 		s.SetActualCost(v)
 	})
 }
 
 // AddActualCost adds v to the "actual_cost" field.
-func (u *UsageLogUpsertBulk) AddActualCost(v float64) *UsageLogUpsertBulk {
+func (u *UsageLogUpsertBulk) AddActualCost(v money.Amount) *UsageLogUpsertBulk {
 	return u.Update(func(s *UsageLogUpsert) {
 		s.AddActualCost(v)
 	})
@@ -2973,6 +3114,62 @@ func (u *UsageLogUpsertBulk) ClearMediaType() *UsageLogUpsertBulk {
 	})
 }
 
+// SetAudioDurationSeconds sets the "audio_duration_seconds" field.
+func (u *UsageLogUpsertBulk) SetAudioDurationSeconds(v float64) *UsageLogUpsertBulk {
+	return u.Update(func(s *UsageLogUpsert) {
+		s.SetAudioDurationSeconds(v)
+	})
+}
+
+// AddAudioDurationSeconds adds v to the "audio_duration_seconds" field.
+func (u *UsageLogUpsertBulk) AddAudioDurationSeconds(v float64) *UsageLogUpsertBulk {
+	return u.Update(func(s *UsageLogUpsert) {
+		s.AddAudioDurationSeconds(v)
+	})
+}
+
+// UpdateAudioDurationSeconds sets the "audio_duration_seconds" field to the value that was provided on create.
+func (u *UsageLogUpsertBulk) UpdateAudioDurationSeconds() *UsageLogUpsertBulk {
+	return u.Update(func(s *UsageLogUpsert) {
+		s.UpdateAudioDurationSeconds()
+	})
+}
+
+// ClearAudioDurationSeconds clears the value of the "audio_duration_seconds" field.
+func (u *UsageLogUpsertBulk) ClearAudioDurationSeconds() *UsageLogUpsertBulk {
+	return u.Update(func(s *UsageLogUpsert) {
+		s.ClearAudioDurationSeconds()
+	})
+}
+
+// SetAudioCharacters sets the "audio_characters" field.
+func (u *UsageLogUpsertBulk) SetAudioCharacters(v int) *UsageLogUpsertBulk {
+	return u.Update(func(s *UsageLogUpsert) {
+		s.SetAudioCharacters(v)
+	})
+}
+
+// AddAudioCharacters adds v to the "audio_characters" field.
+func (u *UsageLogUpsertBulk) AddAudioCharacters(v int) *UsageLogUpsertBulk {
+	return u.Update(func(s *UsageLogUpsert) {
+		s.AddAudioCharacters(v)
+	})
+}
+
+// UpdateAudioCharacters sets the "audio_characters" field to the value that was provided on create.
+func (u *UsageLogUpsertBulk) UpdateAudioCharacters() *UsageLogUpsertBulk {
+	return u.Update(func(s *UsageLogUpsert) {
+		s.UpdateAudioCharacters()
+	})
+}
+
+// ClearAudioCharacters clears the value of the "audio_characters" field.
+func (u *UsageLogUpsertBulk) ClearAudioCharacters() *UsageLogUpsertBulk {
+	return u.Update(func(s *UsageLogUpsert) {
+		s.ClearAudioCharacters()
+	})
+}
+
 // SetCacheTTLOverridden sets the "cache_ttl_overridden" field.
 func (u *UsageLogUpsertBulk) SetCacheTTLOverridden(v bool) *UsageLogUpsertBulk {
 	return u.Update(func(s *UsageLogUpsert) {
@@ -17,6 +17,7 @@ import (
 	"github.com/Wei-Shaw/sub2api/ent/usagelog"
 	"github.com/Wei-Shaw/sub2api/ent/user"
 	"github.com/Wei-Shaw/sub2api/ent/usersubscription"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
 )
 
 // UsageLogUpdate is the builder for updating UsageLog entities.
@@ -269,14 +270,14 @@ func (_u *UsageLogUpdate) AddCacheCreation1hTokens(v int) *UsageLogUpdate {
 }
 
 // SetInputCost sets the "input_cost" field.
-func (_u *UsageLogUpdate) SetInputCost(v float64) *UsageLogUpdate {
+func (_u *UsageLogUpdate) SetInputCost(v money.Amount) *UsageLogUpdate {
 	_u.mutation.ResetInputCost()
 	_u.mutation.SetInputCost(v)
 	return _u
 }
 
 // SetNillableInputCost sets the "input_cost" field if the given value is not nil.
-func (_u *UsageLogUpdate) SetNillableInputCost(v *float64) *UsageLogUpdate {
+func (_u *UsageLogUpdate) SetNillableInputCost(v *money.Amount) *UsageLogUpdate {
 	if v != nil {
 		_u.SetInputCost(*v)
 	}
@@ -284,20 +285,20 @@ func (_u *UsageLogUpdate) SetNillableInputCost(v *float64) *UsageLogUpdate {
 }
 
 // AddInputCost adds value to the "input_cost" field.
-func (_u *UsageLogUpdate) AddInputCost(v float64) *UsageLogUpdate {
+func (_u *UsageLogUpdate) AddInputCost(v money.Amount) *UsageLogUpdate {
 	_u.mutation.AddInputCost(v)
 	return _u
 }
 
 // SetOutputCost sets the "output_cost" field.
-func (_u *UsageLogUpdate) SetOutputCost(v float64) *UsageLogUpdate {
+func (_u *UsageLogUpdate) SetOutputCost(v money.Amount) *UsageLogUpdate {
 	_u.mutation.ResetOutputCost()
 	_u.mutation.SetOutputCost(v)
 	return _u
 }
 
 // SetNillableOutputCost sets the "output_cost" field if the given value is not nil.
-func (_u *UsageLogUpdate) SetNillableOutputCost(v *float64) *UsageLogUpdate {
+func (_u *UsageLogUpdate) SetNillableOutputCost(v *money.Amount) *UsageLogUpdate {
 	if v != nil {
 		_u.SetOutputCost(*v)
 	}
@@ -305,20 +306,20 @@ func (_u *UsageLogUpdate) SetNillableOutputCost(v *float64) *UsageLogUpdate {
 }
 
 // AddOutputCost adds value to the "output_cost" field.
-func (_u *UsageLogUpdate) AddOutputCost(v float64) *UsageLogUpdate {
+func (_u *UsageLogUpdate) AddOutputCost(v money.Amount) *UsageLogUpdate {
 	_u.mutation.AddOutputCost(v)
 	return _u
 }
 
 // SetCacheCreationCost sets the "cache_creation_cost" field.
-func (_u *UsageLogUpdate) SetCacheCreationCost(v float64) *UsageLogUpdate {
+func (_u *UsageLogUpdate) SetCacheCreationCost(v money.Amount) *UsageLogUpdate {
 	_u.mutation.ResetCacheCreationCost()
 	_u.mutation.SetCacheCreationCost(v)
 	return _u
 }
 
 // SetNillableCacheCreationCost sets the "cache_creation_cost" field if the given value is not nil.
-func (_u *UsageLogUpdate) SetNillableCacheCreationCost(v *float64) *UsageLogUpdate {
+func (_u *UsageLogUpdate) SetNillableCacheCreationCost(v *money.Amount) *UsageLogUpdate {
 	if v != nil {
 		_u.SetCacheCreationCost(*v)
 	}
@@ -326,20 +327,20 @@ func (_u *UsageLogUpdate) SetNillableCacheCreationCost(v *float64) *UsageLogUpda
 }
 
 // AddCacheCreationCost adds value to the "cache_creation_cost" field.
-func (_u *UsageLogUpdate) AddCacheCreationCost(v float64) *UsageLogUpdate {
+func (_u *UsageLogUpdate) AddCacheCreationCost(v money.Amount) *UsageLogUpdate {
 	_u.mutation.AddCacheCreationCost(v)
 	return _u
 }
 
 // SetCacheReadCost sets the "cache_read_cost" field.
-func (_u *UsageLogUpdate) SetCacheReadCost(v float64) *UsageLogUpdate {
+func (_u *UsageLogUpdate) SetCacheReadCost(v money.Amount) *UsageLogUpdate {
 	_u.mutation.ResetCacheReadCost()
 	_u.mutation.SetCacheReadCost(v)
 	return _u
 }
 
 // SetNillableCacheReadCost sets the "cache_read_cost" field if the given value is not nil.
-func (_u *UsageLogUpdate) SetNillableCacheReadCost(v *float64) *UsageLogUpdate {
+func (_u *UsageLogUpdate) SetNillableCacheReadCost(v *money.Amount) *UsageLogUpdate {
 	if v != nil {
 		_u.SetCacheReadCost(*v)
 	}
@@ -347,20 +348,20 @@ func (_u *UsageLogUpdate) SetNillableCacheReadCost(v *float64) *UsageLogUpdate {
 }
 
 // AddCacheReadCost adds value to the "cache_read_cost" field.
-func (_u *UsageLogUpdate) AddCacheReadCost(v float64) *UsageLogUpdate {
+func (_u *UsageLogUpdate) AddCacheReadCost(v money.Amount) *UsageLogUpdate {
 	_u.mutation.AddCacheReadCost(v)
 	return _u
 }
 
 // SetTotalCost sets the "total_cost" field.
-func (_u *UsageLogUpdate) SetTotalCost(v float64) *UsageLogUpdate {
+func (_u *UsageLogUpdate) SetTotalCost(v money.Amount) *UsageLogUpdate {
 	_u.mutation.ResetTotalCost()
 	_u.mutation.SetTotalCost(v)
 	return _u
 }
 
 // SetNillableTotalCost sets the "total_cost" field if the given value is not nil.
-func (_u *UsageLogUpdate) SetNillableTotalCost(v *float64) *UsageLogUpdate {
+func (_u *UsageLogUpdate) SetNillableTotalCost(v *money.Amount) *UsageLogUpdate {
 	if v != nil {
 		_u.SetTotalCost(*v)
 	}
@@ -368,20 +369,20 @@ func (_u *UsageLogUpdate) SetNillableTotalCost(v *float64) *UsageLogUpdate {
 }
 
 // AddTotalCost adds value to the "total_cost" field.
-func (_u *UsageLogUpdate) AddTotalCost(v float64) *UsageLogUpdate {
+func (_u *UsageLogUpdate) AddTotalCost(v money.Amount) *UsageLogUpdate {
 	_u.mutation.AddTotalCost(v)
 	return _u
 }
 
 // SetActualCost sets the "actual_cost" field.
-func (_u *UsageLogUpdate) SetActualCost(v float64) *UsageLogUpdate {
+func (_u *UsageLogUpdate) SetActualCost(v money.Amount) *UsageLogUpdate {
 	_u.mutation.ResetActualCost()
 	_u.mutation.SetActualCost(v)
 	return _u
 }
 
 // SetNillableActualCost sets the "actual_cost" field if the given value is not nil.
-func (_u *UsageLogUpdate) SetNillableActualCost(v *float64) *UsageLogUpdate {
+func (_u *UsageLogUpdate) SetNillableActualCost(v *money.Amount) *UsageLogUpdate {
 	if v != nil {
 		_u.SetActualCost(*v)
 	}
@@ -389,7 +390,7 @@ func (_u *UsageLogUpdate) SetNillableActualCost(v *float64) *UsageLogUpdate {
 }
 
 // AddActualCost adds value to the "actual_cost" field.
-func (_u *UsageLogUpdate) AddActualCost(v float64) *UsageLogUpdate {
+func (_u *UsageLogUpdate) AddActualCost(v money.Amount) *UsageLogUpdate {
 	_u.mutation.AddActualCost(v)
 	return _u
 }
@@ -632,6 +633,60 @@ func (_u *UsageLogUpdate) ClearMediaType() *UsageLogUpdate {
 	return _u
 }
 
+// SetAudioDurationSeconds sets the "audio_duration_seconds" field.
+func (_u *UsageLogUpdate) SetAudioDurationSeconds(v float64) *UsageLogUpdate {
+	_u.mutation.ResetAudioDurationSeconds()
+	_u.mutation.SetAudioDurationSeconds(v)
+	return _u
+}
+
+// SetNillableAudioDurationSeconds sets the "audio_duration_seconds" field if the given value is not nil.
+func (_u *UsageLogUpdate) SetNillableAudioDurationSeconds(v *float64) *UsageLogUpdate {
+	if v != nil {
+		_u.SetAudioDurationSeconds(*v)
+	}
+	return _u
+}
+
+// AddAudioDurationSeconds adds value to the "audio_duration_seconds" field.
+func (_u *UsageLogUpdate) AddAudioDurationSeconds(v float64) *UsageLogUpdate {
+	_u.mutation.AddAudioDurationSeconds(v)
+	return _u
+}
+
+// ClearAudioDurationSeconds clears the value of the "audio_duration_seconds" field.
+func (_u *UsageLogUpdate) ClearAudioDurationSeconds() *UsageLogUpdate {
+	_u.mutation.ClearAudioDurationSeconds()
+	return _u
+}
+
+// SetAudioCharacters sets the "audio_characters" field.
+func (_u *UsageLogUpdate) SetAudioCharacters(v int) *UsageLogUpdate {
+	_u.mutation.ResetAudioCharacters()
+	_u.mutation.SetAudioCharacters(v)
+	return _u
+}
+
+// SetNillableAudioCharacters sets the "audio_characters" field if the given value is not nil.
+func (_u *UsageLogUpdate) SetNillableAudioCharacters(v *int) *UsageLogUpdate {
+	if v != nil {
+		_u.SetAudioCharacters(*v)
+	}
+	return _u
+}
+
+// AddAudioCharacters adds value to the "audio_characters" field.
+func (_u *UsageLogUpdate) AddAudioCharacters(v int) *UsageLogUpdate {
+	_u.mutation.AddAudioCharacters(v)
+	return _u
+}
+
+// ClearAudioCharacters clears the value of the "audio_characters" field.
+func (_u *UsageLogUpdate) ClearAudioCharacters() *UsageLogUpdate {
+	_u.mutation.ClearAudioCharacters()
+	return _u
+}
+
 // SetCacheTTLOverridden sets the "cache_ttl_overridden" field.
 func (_u *UsageLogUpdate) SetCacheTTLOverridden(v bool) *UsageLogUpdate {
 	_u.mutation.SetCacheTTLOverridden(v)
@@ -832,40 +887,40 @@ func (_u *UsageLogUpdate) sqlSave(ctx context.Context) (_node int, err error) {
 		_spec.AddField(usagelog.FieldCacheCreation1hTokens, field.TypeInt, value)
 	}
 	if value, ok := _u.mutation.InputCost(); ok {
-		_spec.SetField(usagelog.FieldInputCost, field.TypeFloat64, value)
+		_spec.SetField(usagelog.FieldInputCost, field.TypeInt64, value)
 	}
 	if value, ok := _u.mutation.AddedInputCost(); ok {
-		_spec.AddField(usagelog.FieldInputCost, field.TypeFloat64, value)
+		_spec.AddField(usagelog.FieldInputCost, field.TypeInt64, value)
 	}
 	if value, ok := _u.mutation.OutputCost(); ok {
-		_spec.SetField(usagelog.FieldOutputCost, field.TypeFloat64, value)
+		_spec.SetField(usagelog.FieldOutputCost, field.TypeInt64, value)
 	}
 	if value, ok := _u.mutation.AddedOutputCost(); ok {
-		_spec.AddField(usagelog.FieldOutputCost, field.TypeFloat64, value)
+		_spec.AddField(usagelog.FieldOutputCost, field.TypeInt64, value)
 	}
 	if value, ok := _u.mutation.CacheCreationCost(); ok {
-		_spec.SetField(usagelog.FieldCacheCreationCost, field.TypeFloat64, value)
+		_spec.SetField(usagelog.FieldCacheCreationCost, field.TypeInt64, value)
 	}
 	if value, ok := _u.mutation.AddedCacheCreationCost(); ok {
-		_spec.AddField(usagelog.FieldCacheCreationCost, field.TypeFloat64, value)
+		_spec.AddField(usagelog.FieldCacheCreationCost, field.TypeInt64, value)
 	}
 	if value, ok := _u.mutation.CacheReadCost(); ok {
-		_spec.SetField(usagelog.FieldCacheReadCost, field.TypeFloat64, value)
+		_spec.SetField(usagelog.FieldCacheReadCost, field.TypeInt64, value)
 	}
 	if value, ok := _u.mutation.AddedCacheReadCost(); ok {
-		_spec.AddField(usagelog.FieldCacheReadCost, field.TypeFloat64, value)
+		_spec.AddField(usagelog.FieldCacheReadCost, field.TypeInt64, value)
 	}
 	if value, ok := _u.mutation.TotalCost(); ok {
-		_spec.SetField(usagelog.FieldTotalCost, field.TypeFloat64, value)
+		_spec.SetField(usagelog.FieldTotalCost, field.TypeInt64, value)
 	}
 	if value, ok := _u.mutation.AddedTotalCost(); ok {
-		_spec.AddField(usagelog.FieldTotalCost, field.TypeFloat64, value)
+		_spec.AddField(usagelog.FieldTotalCost, field.TypeInt64, value)
 	}
 	if value, ok := _u.mutation.ActualCost(); ok {
-		_spec.SetField(usagelog.FieldActualCost, field.TypeFloat64, value)
+		_spec.SetField(usagelog.FieldActualCost, field.TypeInt64, value)
 	}
 	if value, ok := _u.mutation.AddedActualCost(); ok {
-		_spec.AddField(usagelog.FieldActualCost, field.TypeFloat64, value)
+		_spec.AddField(usagelog.FieldActualCost, field.TypeInt64, value)
 	}
 	if value, ok := _u.mutation.RateMultiplier(); ok {
 		_spec.SetField(usagelog.FieldRateMultiplier, field.TypeFloat64, value)
@@ -939,6 +994,24 @@ func (_u *UsageLogUpdate) sqlSave(ctx context.Context) (_node int, err error) {
 	if _u.mutation.MediaTypeCleared() {
 		_spec.ClearField(usagelog.FieldMediaType, field.TypeString)
 	}
+	if value, ok := _u.mutation.AudioDurationSeconds(); ok {
+		_spec.SetField(usagelog.FieldAudioDurationSeconds, field.TypeFloat64, value)
+	}
+	if value, ok := _u.mutation.AddedAudioDurationSeconds(); ok {
+		_spec.AddField(usagelog.FieldAudioDurationSeconds, field.TypeFloat64, value)
+	}
+	if _u.mutation.AudioDurationSecondsCleared() {
+		_spec.ClearField(usagelog.FieldAudioDurationSeconds, field.TypeFloat64)
+	}
+	if value, ok := _u.mutation.AudioCharacters(); ok {
+		_spec.SetField(usagelog.FieldAudioCharacters, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.AddedAudioCharacters(); ok {
+		_spec.AddField(usagelog.FieldAudioCharacters, field.TypeInt, value)
+	}
+	if _u.mutation.AudioCharactersCleared() {
+		_spec.ClearField(usagelog.FieldAudioCharacters, field.TypeInt)
+	}
 	if value, ok := _u.mutation.CacheTTLOverridden(); ok {
 		_spec.SetField(usagelog.FieldCacheTTLOverridden, field.TypeBool, value)
 	}
@@ -1344,14 +1417,14 @@ func (_u *UsageLogUpdateOne) AddCacheCreation1hTokens(v int) *UsageLogUpdateOne
 }
 
 // SetInputCost sets the "input_cost" field.
-func (_u *UsageLogUpdateOne) SetInputCost(v float64) *UsageLogUpdateOne {
+func (_u *UsageLogUpdateOne) SetInputCost(v money.Amount) *UsageLogUpdateOne {
 	_u.mutation.ResetInputCost()
 	_u.mutation.SetInputCost(v)
 	return _u
 }
 
 // SetNillableInputCost sets the "input_cost" field if the given value is not nil.
-func (_u *UsageLogUpdateOne) SetNillableInputCost(v *float64) *UsageLogUpdateOne {
+func (_u *UsageLogUpdateOne) SetNillableInputCost(v *money.Amount) *UsageLogUpdateOne {
 	if v != nil {
 		_u.SetInputCost(*v)
 	}
@@ -1359,20 +1432,20 @@ func (_u *UsageLogUpdateOne) SetNillableInputCost(v *float64) *UsageLogUpdateOne
 }
 
 // AddInputCost adds value to the "input_cost" field.
-func (_u *UsageLogUpdateOne) AddInputCost(v float64) *UsageLogUpdateOne {
+func (_u *UsageLogUpdateOne) AddInputCost(v money.Amount) *UsageLogUpdateOne {
 	_u.mutation.AddInputCost(v)
 	return _u
 }
 
 // SetOutputCost sets the "output_cost" field.
-func (_u *UsageLogUpdateOne) SetOutputCost(v float64) *UsageLogUpdateOne {
+func (_u *UsageLogUpdateOne) SetOutputCost(v money.Amount) *UsageLogUpdateOne {
 	_u.mutation.ResetOutputCost()
 	_u.mutation.SetOutputCost(v)
 	return _u
 }
 
 // SetNillableOutputCost sets the "output_cost" field if the given value is not nil.
-func (_u *UsageLogUpdateOne) SetNillableOutputCost(v *float64) *UsageLogUpdateOne {
+func (_u *UsageLogUpdateOne) SetNillableOutputCost(v *money.Amount) *UsageLogUpdateOne {
 	if v != nil {
 		_u.SetOutputCost(*v)
 	}
@@ -1380,20 +1453,20 @@ func (_u *UsageLogUpdateOne) SetNillableOutputCost(v *float64) *UsageLogUpdateOn
 }
 
 // AddOutputCost adds value to the "output_cost" field.
-func (_u *UsageLogUpdateOne) AddOutputCost(v float64) *UsageLogUpdateOne {
+func (_u *UsageLogUpdateOne) AddOutputCost(v money.Amount) *UsageLogUpdateOne {
 	_u.mutation.AddOutputCost(v)
 	return _u
 }
 
 // SetCacheCreationCost sets the "cache_creation_cost" field.
-func (_u *UsageLogUpdateOne) SetCacheCreationCost(v float64) *UsageLogUpdateOne {
+func (_u *UsageLogUpdateOne) SetCacheCreationCost(v money.Amount) *UsageLogUpdateOne {
 	_u.mutation.ResetCacheCreationCost()
 	_u.mutation.SetCacheCreationCost(v)
 	return _u
 }
 
 // SetNillableCacheCreationCost sets the "cache_creation_cost" field if the given value is not nil.
-func (_u *UsageLogUpdateOne) SetNillableCacheCreationCost(v *float64) *UsageLogUpdateOne {
+func (_u *UsageLogUpdateOne) SetNillableCacheCreationCost(v *money.Amount) *UsageLogUpdateOne {
 	if v != nil {
 		_u.SetCacheCreationCost(*v)
 	}
@@ -1401,20 +1474,20 @@ func (_u *UsageLogUpdateOne) SetNillableCacheCreationCost(v *float64) *UsageLogU
 }
 
 // AddCacheCreationCost adds value to the "cache_creation_cost" field.
-func (_u *UsageLogUpdateOne) AddCacheCreationCost(v float64) *UsageLogUpdateOne {
+func (_u *UsageLogUpdateOne) AddCacheCreationCost(v money.Amount) *UsageLogUpdateOne {
 	_u.mutation.AddCacheCreationCost(v)
 	return _u
 }
 
 // SetCacheReadCost sets the "cache_read_cost" field.
-func (_u *UsageLogUpdateOne) SetCacheReadCost(v float64) *UsageLogUpdateOne {
+func (_u *UsageLogUpdateOne) SetCacheReadCost(v money.Amount) *UsageLogUpdateOne {
 	_u.mutation.ResetCacheReadCost()
 	_u.mutation.SetCacheReadCost(v)
 	return _u
 }
 
 // SetNillableCacheReadCost sets the "cache_read_cost" field if the given value is not nil.
-func (_u *UsageLogUpdateOne) SetNillableCacheReadCost(v *float64) *UsageLogUpdateOne {
+func (_u *UsageLogUpdateOne) SetNillableCacheReadCost(v *money.Amount) *UsageLogUpdateOne {
 	if v != nil {
 		_u.SetCacheReadCost(*v)
 	}
@@ -1422,20 +1495,20 @@ func (_u *UsageLogUpdateOne) SetNillableCacheReadCost(v *float64) *UsageLogUpdat
 }
 
 // AddCacheReadCost adds value to the "cache_read_cost" field.
-func (_u *UsageLogUpdateOne) AddCacheReadCost(v float64) *UsageLogUpdateOne {
+func (_u *UsageLogUpdateOne) AddCacheReadCost(v money.Amount) *UsageLogUpdateOne {
 	_u.mutation.AddCacheReadCost(v)
 	return _u
 }
 
 // SetTotalCost sets the "total_cost" field.
-func (_u *UsageLogUpdateOne) SetTotalCost(v float64) *UsageLogUpdateOne {
+func (_u *UsageLogUpdateOne) SetTotalCost(v money.Amount) *UsageLogUpdateOne {
 	_u.mutation.ResetTotalCost()
 	_u.mutation.SetTotalCost(v)
 	return _u
 }
 
 // SetNillableTotalCost sets the "total_cost" field if the given value is not nil.
-func (_u *UsageLogUpdateOne) SetNillableTotalCost(v *float64) *UsageLogUpdateOne {
+func (_u *UsageLogUpdateOne) SetNillableTotalCost(v *money.Amount) *UsageLogUpdateOne {
 	if v != nil {
 		_u.SetTotalCost(*v)
 	}
@@ -1443,20 +1516,20 @@ func (_u *UsageLogUpdateOne) SetNillableTotalCost(v *float64) *UsageLogUpdateOne
 }
 
 // AddTotalCost adds value to the "total_cost" field.
-func (_u *UsageLogUpdateOne) AddTotalCost(v float64) *UsageLogUpdateOne {
+func (_u *UsageLogUpdateOne) AddTotalCost(v money.Amount) *UsageLogUpdateOne {
 	_u.mutation.AddTotalCost(v)
 	return _u
 }
 
 // SetActualCost sets the "actual_cost" field.
-func (_u *UsageLogUpdateOne) SetActualCost(v float64) *UsageLogUpdateOne {
+func (_u *UsageLogUpdateOne) SetActualCost(v money.Amount) *UsageLogUpdateOne {
 	_u.mutation.ResetActualCost()
 	_u.mutation.SetActualCost(v)
 	return _u
 }
 
 // SetNillableActualCost sets the "actual_cost" field if the given value is not nil.
-func (_u *UsageLogUpdateOne) SetNillableActualCost(v *float64) *UsageLogUpdateOne {
+func (_u *UsageLogUpdateOne) SetNillableActualCost(v *money.Amount) *UsageLogUpdateOne {
 	if v != nil {
 		_u.SetActualCost(*v)
 	}
@@ -1464,7 +1537,7 @@ func (_u *UsageLogUpdateOne) SetNillableActualCost(v *float64) *UsageLogUpdateOn
 }
 
 // AddActualCost adds value to the "actual_cost" field.
-func (_u *UsageLogUpdateOne) AddActualCost(v float64) *UsageLogUpdateOne {
+func (_u *UsageLogUpdateOne) AddActualCost(v money.Amount) *UsageLogUpdateOne {
 	_u.mutation.AddActualCost(v)
 	return _u
 }
@@ -1707,6 +1780,60 @@ func (_u *UsageLogUpdateOne) ClearMediaType() *UsageLogUpdateOne {
 	return _u
 }
 
+// SetAudioDurationSeconds sets the "audio_duration_seconds" field.
+func (_u *UsageLogUpdateOne) SetAudioDurationSeconds(v float64) *UsageLogUpdateOne {
+	_u.mutation.ResetAudioDurationSeconds()
+	_u.mutation.SetAudioDurationSeconds(v)
+	return _u
+}
+
+// SetNillableAudioDurationSeconds sets the "audio_duration_seconds" field if the given value is not nil.
+func (_u *UsageLogUpdateOne) SetNillableAudioDurationSeconds(v *float64) *UsageLogUpdateOne {
+	if v != nil {
+		_u.SetAudioDurationSeconds(*v)
+	}
+	return _u
+}
+
+// AddAudioDurationSeconds adds value to the "audio_duration_seconds" field.
+func (_u *UsageLogUpdateOne) AddAudioDurationSeconds(v float64) *UsageLogUpdateOne {
+	_u.mutation.AddAudioDurationSeconds(v)
+	return _u
+}
+
+// ClearAudioDurationSeconds clears the value of the "audio_duration_seconds" field.
+func (_u *UsageLogUpdateOne) ClearAudioDurationSeconds() *UsageLogUpdateOne {
+	_u.mutation.ClearAudioDurationSeconds()
+	return _u
+}
+
+// SetAudioCharacters sets the "audio_characters" field.
+func (_u *UsageLogUpdateOne) SetAudioCharacters(v int) *UsageLogUpdateOne {
+	_u.mutation.ResetAudioCharacters()
+	_u.mutation.SetAudioCharacters(v)
+	return _u
+}
+
+// SetNillableAudioCharacters sets the "audio_characters" field if the given value is not nil.
+func (_u *UsageLogUpdateOne) SetNillableAudioCharacters(v *int) *UsageLogUpdateOne {
+	if v != nil {
+		_u.SetAudioCharacters(*v)
+	}
+	return _u
+}
+
+// AddAudioCharacters adds value to the "audio_characters" field.
+func (_u *UsageLogUpdateOne) AddAudioCharacters(v int) *UsageLogUpdateOne {
+	_u.mutation.AddAudioCharacters(v)
+	return _u
+}
+
+// ClearAudioCharacters clears the value of the "audio_characters" field.
+func (_u *UsageLogUpdateOne) ClearAudioCharacters() *UsageLogUpdateOne {
+	_u.mutation.ClearAudioCharacters()
+	return _u
+}
+
 // SetCacheTTLOverridden sets the "cache_ttl_overridden" field.
 func (_u *UsageLogUpdateOne) SetCacheTTLOverridden(v bool) *UsageLogUpdateOne {
 	_u.mutation.SetCacheTTLOverridden(v)
@@ -1937,40 +2064,40 @@ func (_u *UsageLogUpdateOne) sqlSave(ctx context.Context) (_node *UsageLog, err
 		_spec.AddField(usagelog.FieldCacheCreation1hTokens, field.TypeInt, value)
 	}
 	if value, ok := _u.mutation.InputCost(); ok {
-		_spec.SetField(usagelog.FieldInputCost, field.TypeFloat64, value)
+		_spec.SetField(usagelog.FieldInputCost, field.TypeInt64, value)
 	}
 	if value, ok := _u.mutation.AddedInputCost(); ok {
-		_spec.AddField(usagelog.FieldInputCost, field.TypeFloat64, value)
+		_spec.AddField(usagelog.FieldInputCost, field.TypeInt64, value)
 	}
 	if value, ok := _u.mutation.OutputCost(); ok {
-		_spec.SetField(usagelog.FieldOutputCost, field.TypeFloat64, value)
+		_spec.SetField(usagelog.FieldOutputCost, field.TypeInt64, value)
 	}
 	if value, ok := _u.mutation.AddedOutputCost(); ok {
-		_spec.AddField(usagelog.FieldOutputCost, field.TypeFloat64, value)
+		_spec.AddField(usagelog.FieldOutputCost, field.TypeInt64, value)
 	}
 	if value, ok := _u.mutation.CacheCreationCost(); ok {
-		_spec.SetField(usagelog.FieldCacheCreationCost, field.TypeFloat64, value)
+		_spec.SetField(usagelog.FieldCacheCreationCost, field.TypeInt64, value)
 	}
 	if value, ok := _u.mutation.AddedCacheCreationCost(); ok {
-		_spec.AddField(usagelog.FieldCacheCreationCost, field.TypeFloat64, value)
+		_spec.AddField(usagelog.FieldCacheCreationCost, field.TypeInt64, value)
 	}
 	if value, ok := _u.mutation.CacheReadCost(); ok {
-		_spec.SetField(usagelog.FieldCacheReadCost, field.TypeFloat64, value)
+		_spec.SetField(usagelog.FieldCacheReadCost, field.TypeInt64, value)
 	}
 	if value, ok := _u.mutation.AddedCacheReadCost(); ok {
-		_spec.AddField(usagelog.FieldCacheReadCost, field.TypeFloat64, value)
+		_spec.AddField(usagelog.FieldCacheReadCost, field.TypeInt64, value)
 	}
 	if value, ok := _u.mutation.TotalCost(); ok {
-		_spec.SetField(usagelog.FieldTotalCost, field.TypeFloat64, value)
+		_spec.SetField(usagelog.FieldTotalCost, field.TypeInt64, value)
 	}
 	if value, ok := _u.mutation.AddedTotalCost(); ok {
-		_spec.AddField(usagelog.FieldTotalCost, field.TypeFloat64, value)
+		_spec.AddField(usagelog.FieldTotalCost, field.TypeInt64, value)
 	}
 	if value, ok := _u.mutation.ActualCost(); ok {
-		_spec.SetField(usagelog.FieldActualCost, field.TypeFloat64, value)
+		_spec.SetField(usagelog.FieldActualCost, field.TypeInt64, value)
 	}
 	if value, ok := _u.mutation.AddedActualCost(); ok {
-		_spec.AddField(usagelog.FieldActualCost, field.TypeFloat64, value)
+		_spec.AddField(usagelog.FieldActualCost, field.TypeInt64, value)
 	}
 	if value, ok := _u.mutation.RateMultiplier(); ok {
 		_spec.SetField(usagelog.FieldRateMultiplier, field.TypeFloat64, value)
@@ -2044,6 +2171,24 @@ func (_u *UsageLogUpdateOne) sqlSave(ctx context.Context) (_node *UsageLog, err
 	if _u.mutation.MediaTypeCleared() {
 		_spec.ClearField(usagelog.FieldMediaType, field.TypeString)
 	}
+	if value, ok := _u.mutation.AudioDurationSeconds(); ok {
+		_spec.SetField(usagelog.FieldAudioDurationSeconds, field.TypeFloat64, value)
+	}
+	if value, ok := _u.mutation.AddedAudioDurationSeconds(); ok {
+		_spec.AddField(usagelog.FieldAudioDurationSeconds, field.TypeFloat64, value)
+	}
+	if _u.mutation.AudioDurationSecondsCleared() {
+		_spec.ClearField(usagelog.FieldAudioDurationSeconds, field.TypeFloat64)
+	}
+	if value, ok := _u.mutation.AudioCharacters(); ok {
+		_spec.SetField(usagelog.FieldAudioCharacters, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.AddedAudioCharacters(); ok {
+		_spec.AddField(usagelog.FieldAudioCharacters, field.TypeInt, value)
+	}
+	if _u.mutation.AudioCharactersCleared() {
+		_spec.ClearField(usagelog.FieldAudioCharacters, field.TypeInt)
+	}
 	if value, ok := _u.mutation.CacheTTLOverridden(); ok {
 		_spec.SetField(usagelog.FieldCacheTTLOverridden, field.TypeBool, value)
 	}
@@ -8,6 +8,7 @@ import (
 	"entgo.io/ent/dialect/sql"
 	"entgo.io/ent/dialect/sql/sqlgraph"
 	"github.com/Wei-Shaw/sub2api/ent/predicate"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
 )
 
 // ID filters vertices based on their ID field.
@@ -86,7 +87,7 @@ func Role(v string) predicate.User {
 }
 
 // Balance applies equality check predicate on the "balance" field. It's identical to BalanceEQ.
-func Balance(v float64) predicate.User {
+func Balance(v money.Amount) predicate.User {
 	return predicate.User(sql.FieldEQ(FieldBalance, v))
 }
 
@@ -461,42 +462,42 @@ func RoleContainsFold(v string) predicate.User {
 }
 
 // BalanceEQ applies the EQ predicate on the "balance" field.
-func BalanceEQ(v float64) predicate.User {
+func BalanceEQ(v money.Amount) predicate.User {
 	return predicate.User(sql.FieldEQ(FieldBalance, v))
 }
 
 // BalanceNEQ applies the NEQ predicate on the "balance" field.
-func BalanceNEQ(v float64) predicate.User {
+func BalanceNEQ(v money.Amount) predicate.User {
 	return predicate.User(sql.FieldNEQ(FieldBalance, v))
 }
 
 // BalanceIn applies the In predicate on the "balance" field.
-func BalanceIn(vs ...float64) predicate.User {
+func BalanceIn(vs ...money.Amount) predicate.User {
 	return predicate.User(sql.FieldIn(FieldBalance, vs...))
 }
 
 // BalanceNotIn applies the NotIn predicate on the "balance" field.
-func BalanceNotIn(vs ...float64) predicate.User {
+func BalanceNotIn(vs ...money.Amount) predicate.User {
 	return predicate.User(sql.FieldNotIn(FieldBalance, vs...))
 }
 
 // BalanceGT applies the GT predicate on the "balance" field.
-func BalanceGT(v float64) predicate.User {
+func BalanceGT(v money.Amount) predicate.User {
 	return predicate.User(sql.FieldGT(FieldBalance, v))
 }
 
 // BalanceGTE applies the GTE predicate on the "balance" field.
-func BalanceGTE(v float64) predicate.User {
+func BalanceGTE(v money.Amount) predicate.User {
 	return predicate.User(sql.FieldGTE(FieldBalance, v))
 }
 
 // BalanceLT applies the LT predicate on the "balance" field.
-func BalanceLT(v float64) predicate.User {
+func BalanceLT(v money.Amount) predicate.User {
 	return predicate.User(sql.FieldLT(FieldBalance, v))
 }
 
 // BalanceLTE applies the LTE predicate on the "balance" field.
-func BalanceLTE(v float64) predicate.User {
+func BalanceLTE(v money.Amount) predicate.User {
 	return predicate.User(sql.FieldLTE(FieldBalance, v))
 }
 
@@ -21,6 +21,7 @@ import (
 	"github.com/Wei-Shaw/sub2api/ent/user"
 	"github.com/Wei-Shaw/sub2api/ent/userattributevalue"
 	"github.com/Wei-Shaw/sub2api/ent/usersubscription"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
 )
 
 // UserCreate is the builder for creating a User entity.
@@ -100,13 +101,13 @@ func (_c *UserCreate) SetNillableRole(v *string) *UserCreate {
 }
 
 // SetBalance sets the "balance" field.
-func (_c *UserCreate) SetBalance(v float64) *UserCreate {
+func (_c *UserCreate) SetBalance(v money.Amount) *UserCreate {
 	_c.mutation.SetBalance(v)
 	return _c
 }
 
 // SetNillableBalance sets the "balance" field if the given value is not nil.
-func (_c *UserCreate) SetNillableBalance(v *float64) *UserCreate {
+func (_c *UserCreate) SetNillableBalance(v *money.Amount) *UserCreate {
 	if v != nil {
 		_c.SetBalance(*v)
 	}
@@ -597,7 +598,7 @@ func (_c *UserCreate) createSpec() (*User, *sqlgraph.CreateSpec) {
 		_node.Role = value
 	}
 	if value, ok := _c.mutation.Balance(); ok {
-		_spec.SetField(user.FieldBalance, field.TypeFloat64, value)
+		_spec.SetField(user.FieldBalance, field.TypeInt64, value)
 		_node.Balance = value
 	}
 	if value, ok := _c.mutation.Concurrency(); ok {
@@ -919,7 +920,7 @@ func (u *UserUpsert) UpdateRole() *UserUpsert {
 }
 
 // SetBalance sets the "balance" field.
-func (u *UserUpsert) SetBalance(v float64) *UserUpsert {
+func (u *UserUpsert) SetBalance(v money.Amount) *UserUpsert {
 	u.Set(user.FieldBalance, v)
 	return u
 }
@@ -931,7 +932,7 @@ func (u *UserUpsert) UpdateBalance() *UserUpsert {
 }
 
 // AddBalance adds v to the "balance" field.
-func (u *UserUpsert) AddBalance(v float64) *UserUpsert {
+func (u *UserUpsert) AddBalance(v money.Amount) *UserUpsert {
 	u.Add(user.FieldBalance, v)
 	return u
 }
@@ -1197,14 +1198,14 @@ func (u *UserUpsertOne) UpdateRole() *UserUpsertOne {
 }
 
 // SetBalance sets the "balance" field.
-func (u *UserUpsertOne) SetBalance(v float64) *UserUpsertOne {
+func (u *UserUpsertOne) SetBalance(v money.Amount) *UserUpsertOne {
 	return u.Update(func(s *UserUpsert) {
 		s.SetBalance(v)
 	})
 }
 
 // AddBalance adds v to the "balance" field.
-func (u *UserUpsertOne) AddBalance(v float64) *UserUpsertOne {
+func (u *UserUpsertOne) AddBalance(v money.Amount) *UserUpsertOne {
 	return u.Update(func(s *UserUpsert) {
 		s.AddBalance(v)
 	})
@@ -1667,14 +1668,14 @@ func (u *UserUpsertBulk) UpdateRole() *UserUpsertBulk {
 }
 
 // SetBalance sets the "balance" field.
-func (u *UserUpsertBulk) SetBalance(v float64) *UserUpsertBulk {
+func (u *UserUpsertBulk) SetBalance(v money.Amount) *UserUpsertBulk {
 	return u.Update(func(s *UserUpsert) {
 		s.SetBalance(v)
 	})
 }
 
 // AddBalance adds v to the "balance" field.
-func (u *UserUpsertBulk) AddBalance(v float64) *UserUpsertBulk {
+func (u *UserUpsertBulk) AddBalance(v money.Amount) *UserUpsertBulk {
 	return u.Update(func(s *UserUpsert) {
 		s.AddBalance(v)
 	})
@@ -22,6 +22,7 @@ import (
 	"github.com/Wei-Shaw/sub2api/ent/user"
 	"github.com/Wei-Shaw/sub2api/ent/userattributevalue"
 	"github.com/Wei-Shaw/sub2api/ent/usersubscription"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
 )
 
 // UserUpdate is the builder for updating User entities.
@@ -106,14 +107,14 @@ func (_u *UserUpdate) SetNillableRole(v *string) *UserUpdate {
 }
 
 // SetBalance sets the "balance" field.
-func (_u *UserUpdate) SetBalance(v float64) *UserUpdate {
+func (_u *UserUpdate) SetBalance(v money.Amount) *UserUpdate {
 	_u.mutation.ResetBalance()
 	_u.mutation.SetBalance(v)
 	return _u
 }
 
 // SetNillableBalance sets the "balance" field if the given value is not nil.
-func (_u *UserUpdate) SetNillableBalance(v *float64) *UserUpdate {
+func (_u *UserUpdate) SetNillableBalance(v *money.Amount) *UserUpdate {
 	if v != nil {
 		_u.SetBalance(*v)
 	}
@@ -121,7 +122,7 @@ func (_u *UserUpdate) SetNillableBalance(v *float64) *UserUpdate {
 }
 
 // AddBalance adds value to the "balance" field.
-func (_u *UserUpdate) AddBalance(v float64) *UserUpdate {
+func (_u *UserUpdate) AddBalance(v money.Amount) *UserUpdate {
 	_u.mutation.AddBalance(v)
 	return _u
 }
@@ -753,10 +754,10 @@ func (_u *UserUpdate) sqlSave(ctx context.Context) (_node int, err error) {
 		_spec.SetField(user.FieldRole, field.TypeString, value)
 	}
 	if value, ok := _u.mutation.Balance(); ok {
-		_spec.SetField(user.FieldBalance, field.TypeFloat64, value)
+		_spec.SetField(user.FieldBalance, field.TypeInt64, value)
 	}
 	if value, ok := _u.mutation.AddedBalance(); ok {
-		_spec.AddField(user.FieldBalance, field.TypeFloat64, value)
+		_spec.AddField(user.FieldBalance, field.TypeInt64, value)
 	}
 	if value, ok := _u.mutation.Concurrency(); ok {
 		_spec.SetField(user.FieldConcurrency, field.TypeInt, value)
@@ -1351,14 +1352,14 @@ func (_u *UserUpdateOne) SetNillableRole(v *string) *UserUpdateOne {
 }
 
 // SetBalance sets the "balance" field.
-func (_u *UserUpdateOne) SetBalance(v float64) *UserUpdateOne {
+func (_u *UserUpdateOne) SetBalance(v money.Amount) *UserUpdateOne {
 	_u.mutation.ResetBalance()
 	_u.mutation.SetBalance(v)
 	return _u
 }
 
 // SetNillableBalance sets the "balance" field if the given value is not nil.
-func (_u *UserUpdateOne) SetNillableBalance(v *float64) *UserUpdateOne {
+func (_u *UserUpdateOne) SetNillableBalance(v *money.Amount) *UserUpdateOne {
 	if v != nil {
 		_u.SetBalance(*v)
 	}
@@ -1366,7 +1367,7 @@ func (_u *UserUpdateOne) SetNillableBalance(v *float64) *UserUpdateOne {
 }
 
 // AddBalance adds value to the "balance" field.
-func (_u *UserUpdateOne) AddBalance(v float64) *UserUpdateOne {
+func (_u *UserUpdateOne) AddBalance(v money.Amount) *UserUpdateOne {
 	_u.mutation.AddBalance(v)
 	return _u
 }
@@ -2028,10 +2029,10 @@ func (_u *UserUpdateOne) sqlSave(ctx context.Context) (_node *User, err error) {
 		_spec.SetField(user.FieldRole, field.TypeString, value)
 	}
 	if value, ok := _u.mutation.Balance(); ok {
-		_spec.SetField(user.FieldBalance, field.TypeFloat64, value)
+		_spec.SetField(user.FieldBalance, field.TypeInt64, value)
 	}
 	if value, ok := _u.mutation.AddedBalance(); ok {
-		_spec.AddField(user.FieldBalance, field.TypeFloat64, value)
+		_spec.AddField(user.FieldBalance, field.TypeInt64, value)
 	}
 	if value, ok := _u.mutation.Concurrency(); ok {
 		_spec.SetField(user.FieldConcurrency, field.TypeInt, value)
@@ -12,6 +12,7 @@ import (
 	"github.com/Wei-Shaw/sub2api/ent/group"
 	"github.com/Wei-Shaw/sub2api/ent/user"
 	"github.com/Wei-Shaw/sub2api/ent/usersubscription"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
 )
 
 // UserSubscription is the model entity for the UserSubscription schema.
@@ -42,11 +43,11 @@ type UserSubscription struct {
 	// MonthlyWindowStart holds the value of the "monthly_window_start" field.
 	MonthlyWindowStart *time.Time `json:"monthly_window_start,omitempty"`
 	// DailyUsageUsd holds the value of the "daily_usage_usd" field.
-	DailyUsageUsd float64 `json:"daily_usage_usd,omitempty"`
+	DailyUsageUsd money.Amount `json:"daily_usage_usd,omitempty"`
 	// WeeklyUsageUsd holds the value of the "weekly_usage_usd" field.
-	WeeklyUsageUsd float64 `json:"weekly_usage_usd,omitempty"`
+	WeeklyUsageUsd money.Amount `json:"weekly_usage_usd,omitempty"`
 	// MonthlyUsageUsd holds the value of the "monthly_usage_usd" field.
-	MonthlyUsageUsd float64 `json:"monthly_usage_usd,omitempty"`
+	MonthlyUsageUsd money.Amount `json:"monthly_usage_usd,omitempty"`
 	// AssignedBy holds the value of the "assigned_by" field.
 	AssignedBy *int64 `json:"assigned_by,omitempty"`
 	// AssignedAt holds the value of the "assigned_at" field.
@@ -122,7 +123,7 @@ func (*UserSubscription) scanValues(columns []string) ([]any, error) {
 	for i := range columns {
 		switch columns[i] {
 		case usersubscription.FieldDailyUsageUsd, usersubscription.FieldWeeklyUsageUsd, usersubscription.FieldMonthlyUsageUsd:
-			values[i] = new(sql.NullFloat64)
+			values[i] = new(money.Amount)
 		case usersubscription.FieldID, usersubscription.FieldUserID, usersubscription.FieldGroupID, usersubscription.FieldAssignedBy:
 			values[i] = new(sql.NullInt64)
 		case usersubscription.FieldStatus, usersubscription.FieldNotes:
@@ -221,22 +222,22 @@ func (_m *UserSubscription) assignValues(columns []string, values []any) error {
 				*_m.MonthlyWindowStart = value.Time
 			}
 		case usersubscription.FieldDailyUsageUsd:
-			if value, ok := values[i].(*sql.NullFloat64); !ok {
+			if value, ok := values[i].(*money.Amount); !ok {
 				return fmt.Errorf("unexpected type %T for field daily_usage_usd", values[i])
-			} else if value.Valid {
-				_m.DailyUsageUsd = value.Float64
+			} else if value != nil {
+				_m.DailyUsageUsd = *value
 			}
 		case usersubscription.FieldWeeklyUsageUsd:
-			if value, ok := values[i].(*sql.NullFloat64); !ok {
+			if value, ok := values[i].(*money.Amount); !ok {
 				return fmt.Errorf("unexpected type %T for field weekly_usage_usd", values[i])
-			} else if value.Valid {
-				_m.WeeklyUsageUsd = value.Float64
+			} else if value != nil {
+				_m.WeeklyUsageUsd = *value
 			}
 		case usersubscription.FieldMonthlyUsageUsd:
-			if value, ok := values[i].(*sql.NullFloat64); !ok {
+			if value, ok := values[i].(*money.Amount); !ok {
 				return fmt.Errorf("unexpected type %T for field monthly_usage_usd", values[i])
-			} else if value.Valid {
-				_m.MonthlyUsageUsd = value.Float64
+			} else if value != nil {
+				_m.MonthlyUsageUsd = *value
 			}
 		case usersubscription.FieldAssignedBy:
 			if value, ok := values[i].(*sql.NullInt64); !ok {
@@ -8,6 +8,7 @@ import (
 	"entgo.io/ent"
 	"entgo.io/ent/dialect/sql"
 	"entgo.io/ent/dialect/sql/sqlgraph"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
 )
 
 const (
@@ -140,11 +141,11 @@ var (
 	// StatusValidator is a validator for the "status" field. It is called by the builders before save.
 	StatusValidator func(string) error
 	// DefaultDailyUsageUsd holds the default value on creation for the "daily_usage_usd" field.
-	DefaultDailyUsageUsd float64
+	DefaultDailyUsageUsd money.Amount
 	// DefaultWeeklyUsageUsd holds the default value on creation for the "weekly_usage_usd" field.
-	DefaultWeeklyUsageUsd float64
+	DefaultWeeklyUsageUsd money.Amount
 	// DefaultMonthlyUsageUsd holds the default value on creation for the "monthly_usage_usd" field.
-	DefaultMonthlyUsageUsd float64
+	DefaultMonthlyUsageUsd money.Amount
 	// DefaultAssignedAt holds the default value on creation for the "assigned_at" field.
 	DefaultAssignedAt func() time.Time
 )
@@ -8,6 +8,7 @@ import (
 	"entgo.io/ent/dialect/sql"
 	"entgo.io/ent/dialect/sql/sqlgraph"
 	"github.com/Wei-Shaw/sub2api/ent/predicate"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
 )
 
 // ID filters vertices based on their ID field.
@@ -111,17 +112,17 @@ func MonthlyWindowStart(v time.Time) predicate.UserSubscription {
 }
 
 // DailyUsageUsd applies equality check predicate on the "daily_usage_usd" field. It's identical to DailyUsageUsdEQ.
-func DailyUsageUsd(v float64) predicate.UserSubscription {
+func DailyUsageUsd(v money.Amount) predicate.UserSubscription {
 	return predicate.UserSubscription(sql.FieldEQ(FieldDailyUsageUsd, v))
 }
 
 // WeeklyUsageUsd applies equality check predicate on the "weekly_usage_usd" field. It's identical to WeeklyUsageUsdEQ.
-func WeeklyUsageUsd(v float64) predicate.UserSubscription {
+func WeeklyUsageUsd(v money.Amount) predicate.UserSubscription {
 	return predicate.UserSubscription(sql.FieldEQ(FieldWeeklyUsageUsd, v))
 }
 
 // MonthlyUsageUsd applies equality check predicate on the "monthly_usage_usd" field. It's identical to MonthlyUsageUsdEQ.
-func MonthlyUsageUsd(v float64) predicate.UserSubscription {
+func MonthlyUsageUsd(v money.Amount) predicate.UserSubscription {
 	return predicate.UserSubscription(sql.FieldEQ(FieldMonthlyUsageUsd, v))
 }
 
@@ -606,122 +607,122 @@ func MonthlyWindowStartNotNil() predicate.UserSubscription {
 }
 
 // DailyUsageUsdEQ applies the EQ predicate on the "daily_usage_usd" field.
-func DailyUsageUsdEQ(v float64) predicate.UserSubscription {
+func DailyUsageUsdEQ(v money.Amount) predicate.UserSubscription {
 	return predicate.UserSubscription(sql.FieldEQ(FieldDailyUsageUsd, v))
 }
 
 // DailyUsageUsdNEQ applies the NEQ predicate on the "daily_usage_usd" field.
-func DailyUsageUsdNEQ(v float64) predicate.UserSubscription {
+func DailyUsageUsdNEQ(v money.Amount) predicate.UserSubscription {
 	return predicate.UserSubscription(sql.FieldNEQ(FieldDailyUsageUsd, v))
 }
 
 // DailyUsageUsdIn applies the In predicate on the "daily_usage_usd" field.
-func DailyUsageUsdIn(vs ...float64) predicate.UserSubscription {
+func DailyUsageUsdIn(vs ...money.Amount) predicate.UserSubscription {
 	return predicate.UserSubscription(sql.FieldIn(FieldDailyUsageUsd, vs...))
 }
 
 // DailyUsageUsdNotIn applies the NotIn predicate on the "daily_usage_usd" field.
-func DailyUsageUsdNotIn(vs ...float64) predicate.UserSubscription {
+func DailyUsageUsdNotIn(vs ...money.Amount) predicate.UserSubscription {
 	return predicate.UserSubscription(sql.FieldNotIn(FieldDailyUsageUsd, vs...))
 }
 
 // DailyUsageUsdGT applies the GT predicate on the "daily_usage_usd" field.
-func DailyUsageUsdGT(v float64) predicate.UserSubscription {
+func DailyUsageUsdGT(v money.Amount) predicate.UserSubscription {
 	return predicate.UserSubscription(sql.FieldGT(FieldDailyUsageUsd, v))
 }
 
 // DailyUsageUsdGTE applies the GTE predicate on the "daily_usage_usd" field.
-func DailyUsageUsdGTE(v float64) predicate.UserSubscription {
+func DailyUsageUsdGTE(v money.Amount) predicate.UserSubscription {
 	return predicate.UserSubscription(sql.FieldGTE(FieldDailyUsageUsd, v))
 }
 
 // DailyUsageUsdLT applies the LT predicate on the "daily_usage_usd" field.
-func DailyUsageUsdLT(v float64) predicate.UserSubscription {
+func DailyUsageUsdLT(v money.Amount) predicate.UserSubscription {
 	return predicate.UserSubscription(sql.FieldLT(FieldDailyUsageUsd, v))
 }
 
 // DailyUsageUsdLTE applies the LTE predicate on the "daily_usage_usd" field.
-func DailyUsageUsdLTE(v float64) predicate.UserSubscription {
+func DailyUsageUsdLTE(v money.Amount) predicate.UserSubscription {
 	return predicate.UserSubscription(sql.FieldLTE(FieldDailyUsageUsd, v))
 }
 
 // WeeklyUsageUsdEQ applies the EQ predicate on the "weekly_usage_usd" field.
-func WeeklyUsageUsdEQ(v float64) predicate.UserSubscription {
+func WeeklyUsageUsdEQ(v money.Amount) predicate.UserSubscription {
 	return predicate.UserSubscription(sql.FieldEQ(FieldWeeklyUsageUsd, v))
 }
 
 // WeeklyUsageUsdNEQ applies the NEQ predicate on the "weekly_usage_usd" field.
-func WeeklyUsageUsdNEQ(v float64) predicate.UserSubscription {
+func WeeklyUsageUsdNEQ(v money.Amount) predicate.UserSubscription {
 	return predicate.UserSubscription(sql.FieldNEQ(FieldWeeklyUsageUsd, v))
 }
 
 // WeeklyUsageUsdIn applies the In predicate on the "weekly_usage_usd" field.
-func WeeklyUsageUsdIn(vs ...float64) predicate.UserSubscription {
+func WeeklyUsageUsdIn(vs ...money.Amount) predicate.UserSubscription {
 	return predicate.UserSubscription(sql.FieldIn(FieldWeeklyUsageUsd, vs...))
 }
 
 // WeeklyUsageUsdNotIn applies the NotIn predicate on the "weekly_usage_usd" field.
-func WeeklyUsageUsdNotIn(vs ...float64) predicate.UserSubscription {
+func WeeklyUsageUsdNotIn(vs ...money.Amount) predicate.UserSubscription {
 	return predicate.UserSubscription(sql.FieldNotIn(FieldWeeklyUsageUsd, vs...))
 }
 
 // WeeklyUsageUsdGT applies the GT predicate on the "weekly_usage_usd" field.
-func WeeklyUsageUsdGT(v float64) predicate.UserSubscription {
+func WeeklyUsageUsdGT(v money.Amount) predicate.UserSubscription {
 	return predicate.UserSubscription(sql.FieldGT(FieldWeeklyUsageUsd, v))
 }
 
 // WeeklyUsageUsdGTE applies the GTE predicate on the "weekly_usage_usd" field.
-func WeeklyUsageUsdGTE(v float64) predicate.UserSubscription {
+func WeeklyUsageUsdGTE(v money.Amount) predicate.UserSubscription {
 	return predicate.UserSubscription(sql.FieldGTE(FieldWeeklyUsageUsd, v))
 }
 
 // WeeklyUsageUsdLT applies the LT predicate on the "weekly_usage_usd" field.
-func WeeklyUsageUsdLT(v float64) predicate.UserSubscription {
+func WeeklyUsageUsdLT(v money.Amount) predicate.UserSubscription {
 	return predicate.UserSubscription(sql.FieldLT(FieldWeeklyUsageUsd, v))
 }
 
 // WeeklyUsageUsdLTE applies the LTE predicate on the "weekly_usage_usd" field.
-func WeeklyUsageUsdLTE(v float64) predicate.UserSubscription {
+func WeeklyUsageUsdLTE(v money.Amount) predicate.UserSubscription {
 	return predicate.UserSubscription(sql.FieldLTE(FieldWeeklyUsageUsd, v))
 }
 
 // MonthlyUsageUsdEQ applies the EQ predicate on the "monthly_usage_usd" field.
-func MonthlyUsageUsdEQ(v float64) predicate.UserSubscription {
+func MonthlyUsageUsdEQ(v money.Amount) predicate.UserSubscription {
 	return predicate.UserSubscription(sql.FieldEQ(FieldMonthlyUsageUsd, v))
 }
 
 // MonthlyUsageUsdNEQ applies the NEQ predicate on the "monthly_usage_usd" field.
-func MonthlyUsageUsdNEQ(v float64) predicate.UserSubscription {
+func MonthlyUsageUsdNEQ(v money.Amount) predicate.UserSubscription {
 	return predicate.UserSubscription(sql.FieldNEQ(FieldMonthlyUsageUsd, v))
 }
 
 // MonthlyUsageUsdIn applies the In predicate on the "monthly_usage_usd" field.
-func MonthlyUsageUsdIn(vs ...float64) predicate.UserSubscription {
+func MonthlyUsageUsdIn(vs ...money.Amount) predicate.UserSubscription {
 	return predicate.UserSubscription(sql.FieldIn(FieldMonthlyUsageUsd, vs...))
 }
 
 // MonthlyUsageUsdNotIn applies the NotIn predicate on the "monthly_usage_usd" field.
-func MonthlyUsageUsdNotIn(vs ...float64) predicate.UserSubscription {
+func MonthlyUsageUsdNotIn(vs ...money.Amount) predicate.UserSubscription {
 	return predicate.UserSubscription(sql.FieldNotIn(FieldMonthlyUsageUsd, vs...))
 }
 
 // MonthlyUsageUsdGT applies the GT predicate on the "monthly_usage_usd" field.
-func MonthlyUsageUsdGT(v float64) predicate.UserSubscription {
+func MonthlyUsageUsdGT(v money.Amount) predicate.UserSubscription {
 	return predicate.UserSubscription(sql.FieldGT(FieldMonthlyUsageUsd, v))
 }
 
 // MonthlyUsageUsdGTE applies the GTE predicate on the "monthly_usage_usd" field.
-func MonthlyUsageUsdGTE(v float64) predicate.UserSubscription {
+func MonthlyUsageUsdGTE(v money.Amount) predicate.UserSubscription {
 	return predicate.UserSubscription(sql.FieldGTE(FieldMonthlyUsageUsd, v))
 }
 
 // MonthlyUsageUsdLT applies the LT predicate on the "monthly_usage_usd" field.
-func MonthlyUsageUsdLT(v float64) predicate.UserSubscription {
+func MonthlyUsageUsdLT(v money.Amount) predicate.UserSubscription {
 	return predicate.UserSubscription(sql.FieldLT(FieldMonthlyUsageUsd, v))
 }
 
 // MonthlyUsageUsdLTE applies the LTE predicate on the "monthly_usage_usd" field.
-func MonthlyUsageUsdLTE(v float64) predicate.UserSubscription {
+func MonthlyUsageUsdLTE(v money.Amount) predicate.UserSubscription {
 	return predicate.UserSubscription(sql.FieldLTE(FieldMonthlyUsageUsd, v))
 }
 
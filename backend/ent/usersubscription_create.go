@@ -15,6 +15,7 @@ import (
 	"github.com/Wei-Shaw/sub2api/ent/usagelog"
 	"github.com/Wei-Shaw/sub2api/ent/user"
 	"github.com/Wei-Shaw/sub2api/ent/usersubscription"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
 )
 
 // UserSubscriptionCreate is the builder for creating a UserSubscription entity.
@@ -148,13 +149,13 @@ func (_c *UserSubscriptionCreate) SetNillableMonthlyWindowStart(v *time.Time) *U
 }
 
 // SetDailyUsageUsd sets the "daily_usage_usd" field.
-func (_c *UserSubscriptionCreate) SetDailyUsageUsd(v float64) *UserSubscriptionCreate {
+func (_c *UserSubscriptionCreate) SetDailyUsageUsd(v money.Amount) *UserSubscriptionCreate {
 	_c.mutation.SetDailyUsageUsd(v)
 	return _c
 }
 
 // SetNillableDailyUsageUsd sets the "daily_usage_usd" field if the given value is not nil.
-func (_c *UserSubscriptionCreate) SetNillableDailyUsageUsd(v *float64) *UserSubscriptionCreate {
+func (_c *UserSubscriptionCreate) SetNillableDailyUsageUsd(v *money.Amount) *UserSubscriptionCreate {
 	if v != nil {
 		_c.SetDailyUsageUsd(*v)
 	}
@@ -162,13 +163,13 @@ func (_c *UserSubscriptionCreate) SetNillableDailyUsageUsd(v *float64) *UserSubs
 }
 
 // SetWeeklyUsageUsd sets the "weekly_usage_usd" field.
-func (_c *UserSubscriptionCreate) SetWeeklyUsageUsd(v float64) *UserSubscriptionCreate {
+func (_c *UserSubscriptionCreate) SetWeeklyUsageUsd(v money.Amount) *UserSubscriptionCreate {
 	_c.mutation.SetWeeklyUsageUsd(v)
 	return _c
 }
 
 // SetNillableWeeklyUsageUsd sets the "weekly_usage_usd" field if the given value is not nil.
-func (_c *UserSubscriptionCreate) SetNillableWeeklyUsageUsd(v *float64) *UserSubscriptionCreate {
+func (_c *UserSubscriptionCreate) SetNillableWeeklyUsageUsd(v *money.Amount) *UserSubscriptionCreate {
 	if v != nil {
 		_c.SetWeeklyUsageUsd(*v)
 	}
@@ -176,13 +177,13 @@ func (_c *UserSubscriptionCreate) SetNillableWeeklyUsageUsd(v *float64) *UserSub
 }
 
 // SetMonthlyUsageUsd sets the "monthly_usage_usd" field.
-func (_c *UserSubscriptionCreate) SetMonthlyUsageUsd(v float64) *UserSubscriptionCreate {
+func (_c *UserSubscriptionCreate) SetMonthlyUsageUsd(v money.Amount) *UserSubscriptionCreate {
 	_c.mutation.SetMonthlyUsageUsd(v)
 	return _c
 }
 
 // SetNillableMonthlyUsageUsd sets the "monthly_usage_usd" field if the given value is not nil.
-func (_c *UserSubscriptionCreate) SetNillableMonthlyUsageUsd(v *float64) *UserSubscriptionCreate {
+func (_c *UserSubscriptionCreate) SetNillableMonthlyUsageUsd(v *money.Amount) *UserSubscriptionCreate {
 	if v != nil {
 		_c.SetMonthlyUsageUsd(*v)
 	}
@@ -462,15 +463,15 @@ func (_c *UserSubscriptionCreate) createSpec() (*UserSubscription, *sqlgraph.Cre
 		_node.MonthlyWindowStart = &value
 	}
 	if value, ok := _c.mutation.DailyUsageUsd(); ok {
-		_spec.SetField(usersubscription.FieldDailyUsageUsd, field.TypeFloat64, value)
+		_spec.SetField(usersubscription.FieldDailyUsageUsd, field.TypeInt64, value)
 		_node.DailyUsageUsd = value
 	}
 	if value, ok := _c.mutation.WeeklyUsageUsd(); ok {
-		_spec.SetField(usersubscription.FieldWeeklyUsageUsd, field.TypeFloat64, value)
+		_spec.SetField(usersubscription.FieldWeeklyUsageUsd, field.TypeInt64, value)
 		_node.WeeklyUsageUsd = value
 	}
 	if value, ok := _c.mutation.MonthlyUsageUsd(); ok {
-		_spec.SetField(usersubscription.FieldMonthlyUsageUsd, field.TypeFloat64, value)
+		_spec.SetField(usersubscription.FieldMonthlyUsageUsd, field.TypeInt64, value)
 		_node.MonthlyUsageUsd = value
 	}
 	if value, ok := _c.mutation.AssignedAt(); ok {
@@ -745,7 +746,7 @@ func (u *UserSubscriptionUpsert) ClearMonthlyWindowStart() *UserSubscriptionUpse
 }
 
 // SetDailyUsageUsd sets the "daily_usage_usd" field.
-func (u *UserSubscriptionUpsert) SetDailyUsageUsd(v float64) *UserSubscriptionUpsert {
+func (u *UserSubscriptionUpsert) SetDailyUsageUsd(v money.Amount) *UserSubscriptionUpsert {
 	u.Set(usersubscription.FieldDailyUsageUsd, v)
 	return u
 }
@@ -757,13 +758,13 @@ func (u *UserSubscriptionUpsert) UpdateDailyUsageUsd() *UserSubscriptionUpsert {
 }
 
 // AddDailyUsageUsd adds v to the "daily_usage_usd" field.
-func (u *UserSubscriptionUpsert) AddDailyUsageUsd(v float64) *UserSubscriptionUpsert {
+func (u *UserSubscriptionUpsert) AddDailyUsageUsd(v money.Amount) *UserSubscriptionUpsert {
 	u.Add(usersubscription.FieldDailyUsageUsd, v)
 	return u
 }
 
 // SetWeeklyUsageUsd sets the "weekly_usage_usd" field.
-func (u *UserSubscriptionUpsert) SetWeeklyUsageUsd(v float64) *UserSubscriptionUpsert {
+func (u *UserSubscriptionUpsert) SetWeeklyUsageUsd(v money.Amount) *UserSubscriptionUpsert {
 	u.Set(usersubscription.FieldWeeklyUsageUsd, v)
 	return u
 }
@@ -775,13 +776,13 @@ func (u *UserSubscriptionUpsert) UpdateWeeklyUsageUsd() *UserSubscriptionUpsert
 }
 
 // AddWeeklyUsageUsd adds v to the "weekly_usage_usd" field.
-func (u *UserSubscriptionUpsert) AddWeeklyUsageUsd(v float64) *UserSubscriptionUpsert {
+func (u *UserSubscriptionUpsert) AddWeeklyUsageUsd(v money.Amount) *UserSubscriptionUpsert {
 	u.Add(usersubscription.FieldWeeklyUsageUsd, v)
 	return u
 }
 
 // SetMonthlyUsageUsd sets the "monthly_usage_usd" field.
-func (u *UserSubscriptionUpsert) SetMonthlyUsageUsd(v float64) *UserSubscriptionUpsert {
+func (u *UserSubscriptionUpsert) SetMonthlyUsageUsd(v money.Amount) *UserSubscriptionUpsert {
 	u.Set(usersubscription.FieldMonthlyUsageUsd, v)
 	return u
 }
@@ -793,7 +794,7 @@ func (u *UserSubscriptionUpsert) UpdateMonthlyUsageUsd() *UserSubscriptionUpsert
 }
 
 // AddMonthlyUsageUsd adds v to the "monthly_usage_usd" field.
-func (u *UserSubscriptionUpsert) AddMonthlyUsageUsd(v float64) *UserSubscriptionUpsert {
+func (u *UserSubscriptionUpsert) AddMonthlyUsageUsd(v money.Amount) *UserSubscriptionUpsert {
 	u.Add(usersubscription.FieldMonthlyUsageUsd, v)
 	return u
 }
@@ -1060,14 +1061,14 @@ func (u *UserSubscriptionUpsertOne) ClearMonthlyWindowStart() *UserSubscriptionU
 }
 
 // SetDailyUsageUsd sets the "daily_usage_usd" field.
-func (u *UserSubscriptionUpsertOne) SetDailyUsageUsd(v float64) *UserSubscriptionUpsertOne {
+func (u *UserSubscriptionUpsertOne) SetDailyUsageUsd(v money.Amount) *UserSubscriptionUpsertOne {
 	return u.Update(func(s *UserSubscriptionUpsert) {
 		s.SetDailyUsageUsd(v)
 	})
 }
 
 // AddDailyUsageUsd adds v to the "daily_usage_usd" field.
-func (u *UserSubscriptionUpsertOne) AddDailyUsageUsd(v float64) *UserSubscriptionUpsertOne {
+func (u *UserSubscriptionUpsertOne) AddDailyUsageUsd(v money.Amount) *UserSubscriptionUpsertOne {
 	return u.Update(func(s *UserSubscriptionUpsert) {
 		s.AddDailyUsageUsd(v)
 	})
@@ -1081,14 +1082,14 @@ func (u *UserSubscriptionUpsertOne) UpdateDailyUsageUsd() *UserSubscriptionUpser
 }
 
 // SetWeeklyUsageUsd sets the "weekly_usage_usd" field.
-func (u *UserSubscriptionUpsertOne) SetWeeklyUsageUsd(v float64) *UserSubscriptionUpsertOne {
+func (u *UserSubscriptionUpsertOne) SetWeeklyUsageUsd(v money.Amount) *UserSubscriptionUpsertOne {
 	return u.Update(func(s *UserSubscriptionUpsert) {
 		s.SetWeeklyUsageUsd(v)
 	})
 }
 
 // AddWeeklyUsageUsd adds v to the "weekly_usage_usd" field.
-func (u *UserSubscriptionUpsertOne) AddWeeklyUsageUsd(v float64) *UserSubscriptionUpsertOne {
+func (u *UserSubscriptionUpsertOne) AddWeeklyUsageUsd(v money.Amount) *UserSubscriptionUpsertOne {
 	return u.Update(func(s *UserSubscriptionUpsert) {
 		s.AddWeeklyUsageUsd(v)
 	})
@@ -1102,14 +1103,14 @@ func (u *UserSubscriptionUpsertOne) UpdateWeeklyUsageUsd() *UserSubscriptionUpse
 }
 
 // SetMonthlyUsageUsd sets the "monthly_usage_usd" field.
-func (u *UserSubscriptionUpsertOne) SetMonthlyUsageUsd(v float64) *UserSubscriptionUpsertOne {
+func (u *UserSubscriptionUpsertOne) SetMonthlyUsageUsd(v money.Amount) *UserSubscriptionUpsertOne {
 	return u.Update(func(s *UserSubscriptionUpsert) {
 		s.SetMonthlyUsageUsd(v)
 	})
 }
 
 // AddMonthlyUsageUsd adds v to the "monthly_usage_usd" field.
-func (u *UserSubscriptionUpsertOne) AddMonthlyUsageUsd(v float64) *UserSubscriptionUpsertOne {
+func (u *UserSubscriptionUpsertOne) AddMonthlyUsageUsd(v money.Amount) *UserSubscriptionUpsertOne {
 	return u.Update(func(s *UserSubscriptionUpsert) {
 		s.AddMonthlyUsageUsd(v)
 	})
@@ -1558,14 +1559,14 @@ func (u *UserSubscriptionUpsertBulk) ClearMonthlyWindowStart() *UserSubscription
 }
 
 // SetDailyUsageUsd sets the "daily_usage_usd" field.
-func (u *UserSubscriptionUpsertBulk) SetDailyUsageUsd(v float64) *UserSubscriptionUpsertBulk {
+func (u *UserSubscriptionUpsertBulk) SetDailyUsageUsd(v money.Amount) *UserSubscriptionUpsertBulk {
 	return u.Update(func(s *UserSubscriptionUpsert) {
 		s.SetDailyUsageUsd(v)
 	})
 }
 
 // AddDailyUsageUsd adds v to the "daily_usage_usd" field.
-func (u *UserSubscriptionUpsertBulk) AddDailyUsageUsd(v float64) *UserSubscriptionUpsertBulk {
+func (u *UserSubscriptionUpsertBulk) AddDailyUsageUsd(v money.Amount) *UserSubscriptionUpsertBulk {
 	return u.Update(func(s *UserSubscriptionUpsert) {
 		s.AddDailyUsageUsd(v)
 	})
@@ -1579,14 +1580,14 @@ func (u *UserSubscriptionUpsertBulk) UpdateDailyUsageUsd() *UserSubscriptionUpse
 }
 
 // SetWeeklyUsageUsd sets the "weekly_usage_usd" field.
-func (u *UserSubscriptionUpsertBulk) SetWeeklyUsageUsd(v float64) *UserSubscriptionUpsertBulk {
+func (u *UserSubscriptionUpsertBulk) SetWeeklyUsageUsd(v money.Amount) *UserSubscriptionUpsertBulk {
 	return u.Update(func(s *UserSubscriptionUpsert) {
 		s.SetWeeklyUsageUsd(v)
 	})
 }
 
 // AddWeeklyUsageUsd adds v to the "weekly_usage_usd" field.
-func (u *UserSubscriptionUpsertBulk) AddWeeklyUsageUsd(v float64) *UserSubscriptionUpsertBulk {
+func (u *UserSubscriptionUpsertBulk) AddWeeklyUsageUsd(v money.Amount) *UserSubscriptionUpsertBulk {
 	return u.Update(func(s *UserSubscriptionUpsert) {
 		s.AddWeeklyUsageUsd(v)
 	})
@@ -1600,14 +1601,14 @@ func (u *UserSubscriptionUpsertBulk) UpdateWeeklyUsageUsd() *UserSubscriptionUps
 }
 
 // SetMonthlyUsageUsd sets the "monthly_usage_usd" field.
-func (u *UserSubscriptionUpsertBulk) SetMonthlyUsageUsd(v float64) *UserSubscriptionUpsertBulk {
+func (u *UserSubscriptionUpsertBulk) SetMonthlyUsageUsd(v money.Amount) *UserSubscriptionUpsertBulk {
 	return u.Update(func(s *UserSubscriptionUpsert) {
 		s.SetMonthlyUsageUsd(v)
 	})
 }
 
 // AddMonthlyUsageUsd adds v to the "monthly_usage_usd" field.
-func (u *UserSubscriptionUpsertBulk) AddMonthlyUsageUsd(v float64) *UserSubscriptionUpsertBulk {
+func (u *UserSubscriptionUpsertBulk) AddMonthlyUsageUsd(v money.Amount) *UserSubscriptionUpsertBulk {
 	return u.Update(func(s *UserSubscriptionUpsert) {
 		s.AddMonthlyUsageUsd(v)
 	})
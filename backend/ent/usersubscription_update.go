@@ -16,6 +16,7 @@ import (
 	"github.com/Wei-Shaw/sub2api/ent/usagelog"
 	"github.com/Wei-Shaw/sub2api/ent/user"
 	"github.com/Wei-Shaw/sub2api/ent/usersubscription"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
 )
 
 // UserSubscriptionUpdate is the builder for updating UserSubscription entities.
@@ -188,14 +189,14 @@ func (_u *UserSubscriptionUpdate) ClearMonthlyWindowStart() *UserSubscriptionUpd
 }
 
 // SetDailyUsageUsd sets the "daily_usage_usd" field.
-func (_u *UserSubscriptionUpdate) SetDailyUsageUsd(v float64) *UserSubscriptionUpdate {
+func (_u *UserSubscriptionUpdate) SetDailyUsageUsd(v money.Amount) *UserSubscriptionUpdate {
 	_u.mutation.ResetDailyUsageUsd()
 	_u.mutation.SetDailyUsageUsd(v)
 	return _u
 }
 
 // SetNillableDailyUsageUsd sets the "daily_usage_usd" field if the given value is not nil.
-func (_u *UserSubscriptionUpdate) SetNillableDailyUsageUsd(v *float64) *UserSubscriptionUpdate {
+func (_u *UserSubscriptionUpdate) SetNillableDailyUsageUsd(v *money.Amount) *UserSubscriptionUpdate {
 	if v != nil {
 		_u.SetDailyUsageUsd(*v)
 	}
@@ -203,20 +204,20 @@ func (_u *UserSubscriptionUpdate) SetNillableDailyUsageUsd(v *float64) *UserSubs
 }
 
 // AddDailyUsageUsd adds value to the "daily_usage_usd" field.
-func (_u *UserSubscriptionUpdate) AddDailyUsageUsd(v float64) *UserSubscriptionUpdate {
+func (_u *UserSubscriptionUpdate) AddDailyUsageUsd(v money.Amount) *UserSubscriptionUpdate {
 	_u.mutation.AddDailyUsageUsd(v)
 	return _u
 }
 
 // SetWeeklyUsageUsd sets the "weekly_usage_usd" field.
-func (_u *UserSubscriptionUpdate) SetWeeklyUsageUsd(v float64) *UserSubscriptionUpdate {
+func (_u *UserSubscriptionUpdate) SetWeeklyUsageUsd(v money.Amount) *UserSubscriptionUpdate {
 	_u.mutation.ResetWeeklyUsageUsd()
 	_u.mutation.SetWeeklyUsageUsd(v)
 	return _u
 }
 
 // SetNillableWeeklyUsageUsd sets the "weekly_usage_usd" field if the given value is not nil.
-func (_u *UserSubscriptionUpdate) SetNillableWeeklyUsageUsd(v *float64) *UserSubscriptionUpdate {
+func (_u *UserSubscriptionUpdate) SetNillableWeeklyUsageUsd(v *money.Amount) *UserSubscriptionUpdate {
 	if v != nil {
 		_u.SetWeeklyUsageUsd(*v)
 	}
@@ -224,20 +225,20 @@ func (_u *UserSubscriptionUpdate) SetNillableWeeklyUsageUsd(v *float64) *UserSub
 }
 
 // AddWeeklyUsageUsd adds value to the "weekly_usage_usd" field.
-func (_u *UserSubscriptionUpdate) AddWeeklyUsageUsd(v float64) *UserSubscriptionUpdate {
+func (_u *UserSubscriptionUpdate) AddWeeklyUsageUsd(v money.Amount) *UserSubscriptionUpdate {
 	_u.mutation.AddWeeklyUsageUsd(v)
 	return _u
 }
 
 // SetMonthlyUsageUsd sets the "monthly_usage_usd" field.
-func (_u *UserSubscriptionUpdate) SetMonthlyUsageUsd(v float64) *UserSubscriptionUpdate {
+func (_u *UserSubscriptionUpdate) SetMonthlyUsageUsd(v money.Amount) *UserSubscriptionUpdate {
 	_u.mutation.ResetMonthlyUsageUsd()
 	_u.mutation.SetMonthlyUsageUsd(v)
 	return _u
 }
 
 // SetNillableMonthlyUsageUsd sets the "monthly_usage_usd" field if the given value is not nil.
-func (_u *UserSubscriptionUpdate) SetNillableMonthlyUsageUsd(v *float64) *UserSubscriptionUpdate {
+func (_u *UserSubscriptionUpdate) SetNillableMonthlyUsageUsd(v *money.Amount) *UserSubscriptionUpdate {
 	if v != nil {
 		_u.SetMonthlyUsageUsd(*v)
 	}
@@ -245,7 +246,7 @@ func (_u *UserSubscriptionUpdate) SetNillableMonthlyUsageUsd(v *float64) *UserSu
 }
 
 // AddMonthlyUsageUsd adds value to the "monthly_usage_usd" field.
-func (_u *UserSubscriptionUpdate) AddMonthlyUsageUsd(v float64) *UserSubscriptionUpdate {
+func (_u *UserSubscriptionUpdate) AddMonthlyUsageUsd(v money.Amount) *UserSubscriptionUpdate {
 	_u.mutation.AddMonthlyUsageUsd(v)
 	return _u
 }
@@ -499,22 +500,22 @@ func (_u *UserSubscriptionUpdate) sqlSave(ctx context.Context) (_node int, err e
 		_spec.ClearField(usersubscription.FieldMonthlyWindowStart, field.TypeTime)
 	}
 	if value, ok := _u.mutation.DailyUsageUsd(); ok {
-		_spec.SetField(usersubscription.FieldDailyUsageUsd, field.TypeFloat64, value)
+		_spec.SetField(usersubscription.FieldDailyUsageUsd, field.TypeInt64, value)
 	}
 	if value, ok := _u.mutation.AddedDailyUsageUsd(); ok {
-		_spec.AddField(usersubscription.FieldDailyUsageUsd, field.TypeFloat64, value)
+		_spec.AddField(usersubscription.FieldDailyUsageUsd, field.TypeInt64, value)
 	}
 	if value, ok := _u.mutation.WeeklyUsageUsd(); ok {
-		_spec.SetField(usersubscription.FieldWeeklyUsageUsd, field.TypeFloat64, value)
+		_spec.SetField(usersubscription.FieldWeeklyUsageUsd, field.TypeInt64, value)
 	}
 	if value, ok := _u.mutation.AddedWeeklyUsageUsd(); ok {
-		_spec.AddField(usersubscription.FieldWeeklyUsageUsd, field.TypeFloat64, value)
+		_spec.AddField(usersubscription.FieldWeeklyUsageUsd, field.TypeInt64, value)
 	}
 	if value, ok := _u.mutation.MonthlyUsageUsd(); ok {
-		_spec.SetField(usersubscription.FieldMonthlyUsageUsd, field.TypeFloat64, value)
+		_spec.SetField(usersubscription.FieldMonthlyUsageUsd, field.TypeInt64, value)
 	}
 	if value, ok := _u.mutation.AddedMonthlyUsageUsd(); ok {
-		_spec.AddField(usersubscription.FieldMonthlyUsageUsd, field.TypeFloat64, value)
+		_spec.AddField(usersubscription.FieldMonthlyUsageUsd, field.TypeInt64, value)
 	}
 	if value, ok := _u.mutation.AssignedAt(); ok {
 		_spec.SetField(usersubscription.FieldAssignedAt, field.TypeTime, value)
@@ -834,14 +835,14 @@ func (_u *UserSubscriptionUpdateOne) ClearMonthlyWindowStart() *UserSubscription
 }
 
 // SetDailyUsageUsd sets the "daily_usage_usd" field.
-func (_u *UserSubscriptionUpdateOne) SetDailyUsageUsd(v float64) *UserSubscriptionUpdateOne {
+func (_u *UserSubscriptionUpdateOne) SetDailyUsageUsd(v money.Amount) *UserSubscriptionUpdateOne {
 	_u.mutation.ResetDailyUsageUsd()
 	_u.mutation.SetDailyUsageUsd(v)
 	return _u
 }
 
 // SetNillableDailyUsageUsd sets the "daily_usage_usd" field if the given value is not nil.
-func (_u *UserSubscriptionUpdateOne) SetNillableDailyUsageUsd(v *float64) *UserSubscriptionUpdateOne {
+func (_u *UserSubscriptionUpdateOne) SetNillableDailyUsageUsd(v *money.Amount) *UserSubscriptionUpdateOne {
 	if v != nil {
 		_u.SetDailyUsageUsd(*v)
 	}
@@ -849,20 +850,20 @@ func (_u *UserSubscriptionUpdateOne) SetNillableDailyUsageUsd(v *float64) *UserS
 }
 
 // AddDailyUsageUsd adds value to the "daily_usage_usd" field.
-func (_u *UserSubscriptionUpdateOne) AddDailyUsageUsd(v float64) *UserSubscriptionUpdateOne {
+func (_u *UserSubscriptionUpdateOne) AddDailyUsageUsd(v money.Amount) *UserSubscriptionUpdateOne {
 	_u.mutation.AddDailyUsageUsd(v)
 	return _u
 }
 
 // SetWeeklyUsageUsd sets the "weekly_usage_usd" field.
-func (_u *UserSubscriptionUpdateOne) SetWeeklyUsageUsd(v float64) *UserSubscriptionUpdateOne {
+func (_u *UserSubscriptionUpdateOne) SetWeeklyUsageUsd(v money.Amount) *UserSubscriptionUpdateOne {
 	_u.mutation.ResetWeeklyUsageUsd()
 	_u.mutation.SetWeeklyUsageUsd(v)
 	return _u
 }
 
 // SetNillableWeeklyUsageUsd sets the "weekly_usage_usd" field if the given value is not nil.
-func (_u *UserSubscriptionUpdateOne) SetNillableWeeklyUsageUsd(v *float64) *UserSubscriptionUpdateOne {
+func (_u *UserSubscriptionUpdateOne) SetNillableWeeklyUsageUsd(v *money.Amount) *UserSubscriptionUpdateOne {
 	if v != nil {
 		_u.SetWeeklyUsageUsd(*v)
 	}
@@ -870,20 +871,20 @@ func (_u *UserSubscriptionUpdateOne) SetNillableWeeklyUsageUsd(v *float64) *User
 }
 
 // AddWeeklyUsageUsd adds value to the "weekly_usage_usd" field.
-func (_u *UserSubscriptionUpdateOne) AddWeeklyUsageUsd(v float64) *UserSubscriptionUpdateOne {
+func (_u *UserSubscriptionUpdateOne) AddWeeklyUsageUsd(v money.Amount) *UserSubscriptionUpdateOne {
 	_u.mutation.AddWeeklyUsageUsd(v)
 	return _u
 }
 
 // SetMonthlyUsageUsd sets the "monthly_usage_usd" field.
-func (_u *UserSubscriptionUpdateOne) SetMonthlyUsageUsd(v float64) *UserSubscriptionUpdateOne {
+func (_u *UserSubscriptionUpdateOne) SetMonthlyUsageUsd(v money.Amount) *UserSubscriptionUpdateOne {
 	_u.mutation.ResetMonthlyUsageUsd()
 	_u.mutation.SetMonthlyUsageUsd(v)
 	return _u
 }
 
 // SetNillableMonthlyUsageUsd sets the "monthly_usage_usd" field if the given value is not nil.
-func (_u *UserSubscriptionUpdateOne) SetNillableMonthlyUsageUsd(v *float64) *UserSubscriptionUpdateOne {
+func (_u *UserSubscriptionUpdateOne) SetNillableMonthlyUsageUsd(v *money.Amount) *UserSubscriptionUpdateOne {
 	if v != nil {
 		_u.SetMonthlyUsageUsd(*v)
 	}
@@ -891,7 +892,7 @@ func (_u *UserSubscriptionUpdateOne) SetNillableMonthlyUsageUsd(v *float64) *Use
 }
 
 // AddMonthlyUsageUsd adds value to the "monthly_usage_usd" field.
-func (_u *UserSubscriptionUpdateOne) AddMonthlyUsageUsd(v float64) *UserSubscriptionUpdateOne {
+func (_u *UserSubscriptionUpdateOne) AddMonthlyUsageUsd(v money.Amount) *UserSubscriptionUpdateOne {
 	_u.mutation.AddMonthlyUsageUsd(v)
 	return _u
 }
@@ -1175,22 +1176,22 @@ func (_u *UserSubscriptionUpdateOne) sqlSave(ctx context.Context) (_node *UserSu
 		_spec.ClearField(usersubscription.FieldMonthlyWindowStart, field.TypeTime)
 	}
 	if value, ok := _u.mutation.DailyUsageUsd(); ok {
-		_spec.SetField(usersubscription.FieldDailyUsageUsd, field.TypeFloat64, value)
+		_spec.SetField(usersubscription.FieldDailyUsageUsd, field.TypeInt64, value)
 	}
 	if value, ok := _u.mutation.AddedDailyUsageUsd(); ok {
-		_spec.AddField(usersubscription.FieldDailyUsageUsd, field.TypeFloat64, value)
+		_spec.AddField(usersubscription.FieldDailyUsageUsd, field.TypeInt64, value)
 	}
 	if value, ok := _u.mutation.WeeklyUsageUsd(); ok {
-		_spec.SetField(usersubscription.FieldWeeklyUsageUsd, field.TypeFloat64, value)
+		_spec.SetField(usersubscription.FieldWeeklyUsageUsd, field.TypeInt64, value)
 	}
 	if value, ok := _u.mutation.AddedWeeklyUsageUsd(); ok {
-		_spec.AddField(usersubscription.FieldWeeklyUsageUsd, field.TypeFloat64, value)
+		_spec.AddField(usersubscription.FieldWeeklyUsageUsd, field.TypeInt64, value)
 	}
 	if value, ok := _u.mutation.MonthlyUsageUsd(); ok {
-		_spec.SetField(usersubscription.FieldMonthlyUsageUsd, field.TypeFloat64, value)
+		_spec.SetField(usersubscription.FieldMonthlyUsageUsd, field.TypeInt64, value)
 	}
 	if value, ok := _u.mutation.AddedMonthlyUsageUsd(); ok {
-		_spec.AddField(usersubscription.FieldMonthlyUsageUsd, field.TypeFloat64, value)
+		_spec.AddField(usersubscription.FieldMonthlyUsageUsd, field.TypeInt64, value)
 	}
 	if value, ok := _u.mutation.AssignedAt(); ok {
 		_spec.SetField(usersubscription.FieldAssignedAt, field.TypeTime, value)
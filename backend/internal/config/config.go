@@ -64,6 +64,10 @@ type Config struct {
 	Ops                     OpsConfig                     `mapstructure:"ops"`
 	JWT                     JWTConfig                     `mapstructure:"jwt"`
 	Totp                    TotpConfig                    `mapstructure:"totp"`
+	WebAuthn                WebAuthnConfig                `mapstructure:"webauthn"`
+	LoginHistory            LoginHistoryConfig            `mapstructure:"login_history"`
+	Payment                 PaymentConfig                 `mapstructure:"payment"`
+	Referral                ReferralConfig                `mapstructure:"referral"`
 	LinuxDo                 LinuxDoConnectConfig          `mapstructure:"linuxdo_connect"`
 	Default                 DefaultConfig                 `mapstructure:"default"`
 	RateLimit               RateLimitConfig               `mapstructure:"rate_limit"`
@@ -75,6 +79,9 @@ type Config struct {
 	Dashboard               DashboardCacheConfig          `mapstructure:"dashboard_cache"`
 	DashboardAgg            DashboardAggregationConfig    `mapstructure:"dashboard_aggregation"`
 	UsageCleanup            UsageCleanupConfig            `mapstructure:"usage_cleanup"`
+	Batch                   BatchConfig                   `mapstructure:"batch"`
+	Metrics                 MetricsConfig                 `mapstructure:"metrics"`
+	Tracing                 TracingConfig                 `mapstructure:"tracing"`
 	Concurrency             ConcurrencyConfig             `mapstructure:"concurrency"`
 	TokenRefresh            TokenRefreshConfig            `mapstructure:"token_refresh"`
 	Sora                    SoraConfig                    `mapstructure:"sora"`
@@ -249,6 +256,8 @@ type SecurityConfig struct {
 	CSP             CSPConfig            `mapstructure:"csp"`
 	ProxyFallback   ProxyFallbackConfig  `mapstructure:"proxy_fallback"`
 	ProxyProbe      ProxyProbeConfig     `mapstructure:"proxy_probe"`
+	// CredentialEncryption 账号凭证与代理密码的静态加密
+	CredentialEncryption CredentialEncryptionConfig `mapstructure:"credential_encryption"`
 }
 
 type URLAllowlistConfig struct {
@@ -287,8 +296,42 @@ type ProxyProbeConfig struct {
 	InsecureSkipVerify bool `mapstructure:"insecure_skip_verify"` // 已禁用：禁止跳过 TLS 证书验证
 }
 
+// CredentialEncryptionConfig 账号凭证（OAuth token、API Key 等）与代理密码的信封加密配置。
+// 每条记录使用独立的数据密钥（DEK）加密，DEK 再由主密钥（KEK）加密后随密文一起存储。
+type CredentialEncryptionConfig struct {
+	// MasterKey 当前主密钥，AES-256（32 字节 hex 编码）；为空表示不加密
+	MasterKey string `mapstructure:"master_key"`
+	// PreviousKeys 轮换期间仍可用于解密的旧主密钥（hex 编码）
+	PreviousKeys []string `mapstructure:"previous_keys"`
+}
+
 type BillingConfig struct {
 	CircuitBreaker CircuitBreakerConfig `mapstructure:"circuit_breaker"`
+	Holds          BillingHoldConfig    `mapstructure:"holds"`
+	// LedgerReconcile 余额流水定时对账
+	LedgerReconcile BillingLedgerReconcileConfig `mapstructure:"ledger_reconcile"`
+}
+
+// BillingLedgerReconcileConfig 余额流水对账配置
+type BillingLedgerReconcileConfig struct {
+	// Enabled: 定时比对每个用户的流水合计与当前余额，不一致时记录对账结果并触发运维告警
+	Enabled bool `mapstructure:"enabled"`
+	// IntervalSeconds: 对账间隔（秒）
+	IntervalSeconds int `mapstructure:"interval_seconds"`
+	// SampleLimit: 每次对账保留的不一致用户样本数
+	SampleLimit int `mapstructure:"sample_limit"`
+}
+
+// BillingHoldConfig 在途请求预授权冻结配置
+type BillingHoldConfig struct {
+	// Enabled: 请求开始时按预估最大费用冻结余额/配额/订阅额度，额度不足以覆盖时拒绝请求
+	Enabled bool `mapstructure:"enabled"`
+	// DefaultMaxOutputTokens: 请求未携带 max_tokens 时用于估算的输出 token 数
+	DefaultMaxOutputTokens int `mapstructure:"default_max_output_tokens"`
+	// TTLSeconds: 冻结最长保留时间，超时自动释放（进程崩溃、记录任务丢弃时兜底）
+	TTLSeconds int `mapstructure:"ttl_seconds"`
+	// SettleGraceSeconds: 结算后按实际费用继续保留的时间，覆盖异步扣减余额缓存的窗口
+	SettleGraceSeconds int `mapstructure:"settle_grace_seconds"`
 }
 
 type CircuitBreakerConfig struct {
@@ -637,6 +680,31 @@ type GatewayUsageRecordConfig struct {
 	AutoScaleCheckIntervalSeconds int `mapstructure:"auto_scale_check_interval_seconds"`
 	// AutoScaleCooldownSeconds: 自动扩缩容冷却时间（秒）
 	AutoScaleCooldownSeconds int `mapstructure:"auto_scale_cooldown_seconds"`
+
+	// Durable: 持久化使用量日志（Redis Stream），节点崩溃或过载时不丢失计费记录
+	Durable GatewayUsageRecordDurableConfig `mapstructure:"durable"`
+}
+
+// GatewayUsageRecordDurableConfig 持久化使用量日志配置
+// 启用后使用量先写入 Redis Stream，再由消费者组以至少一次语义落库扣费，
+// 并基于 usage_billing_dedup 按 (request_id, api_key_id) 去重。
+type GatewayUsageRecordDurableConfig struct {
+	// Enabled: 是否启用持久化使用量日志
+	Enabled bool `mapstructure:"enabled"`
+	// StreamKey: Redis Stream 键名
+	StreamKey string `mapstructure:"stream_key"`
+	// ConsumerGroup: 消费者组名称（所有节点共享）
+	ConsumerGroup string `mapstructure:"consumer_group"`
+	// ConsumerName: 本节点消费者名称（为空时使用主机名）；需在重启间保持稳定以便重放遗留条目
+	ConsumerName string `mapstructure:"consumer_name"`
+	// BatchSize: 单次读取的最大条目数
+	BatchSize int `mapstructure:"batch_size"`
+	// BlockMilliseconds: XREADGROUP 阻塞等待时间（毫秒）
+	BlockMilliseconds int `mapstructure:"block_milliseconds"`
+	// ClaimIdleSeconds: 待确认条目空闲超过该时长后可被其他节点接管（秒）
+	ClaimIdleSeconds int `mapstructure:"claim_idle_seconds"`
+	// MaxDeliveries: 单条记录最大投递次数，超过后转入死信流
+	MaxDeliveries int `mapstructure:"max_deliveries"`
 }
 
 // SoraModelFiltersConfig Sora 模型过滤配置
@@ -858,6 +926,110 @@ type TotpConfig struct {
 	EncryptionKeyConfigured bool `mapstructure:"-"`
 }
 
+// WebAuthnConfig Passkey（WebAuthn）配置
+type WebAuthnConfig struct {
+	// RPID 依赖方 ID（站点域名，如 example.com）；留空时取 server.frontend_url 的主机名
+	RPID string `mapstructure:"rp_id"`
+	// RPName 认证器中展示的站点名称
+	RPName string `mapstructure:"rp_name"`
+	// Origins 允许发起 WebAuthn 的前端来源；留空时取 server.frontend_url 的来源
+	Origins []string `mapstructure:"origins"`
+}
+
+// LoginHistoryConfig 登录记录配置
+type LoginHistoryConfig struct {
+	// RetentionDays 登录记录保留天数，0 表示永久保留
+	RetentionDays int `mapstructure:"retention_days"`
+	// NotifyNewDevice 从未出现过的 IP 或设备登录成功时发送邮件提醒（需配置 SMTP）
+	NotifyNewDevice bool `mapstructure:"notify_new_device"`
+}
+
+// PaymentConfig 内置支付订单配置
+type PaymentConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// OrderExpireMinutes 待支付订单超时关闭时间（分钟）
+	OrderExpireMinutes int `mapstructure:"order_expire_minutes"`
+	// MaxPendingOrders 单个用户同时存在的待支付订单上限
+	MaxPendingOrders int `mapstructure:"max_pending_orders"`
+	// NotifyBaseURL 支付渠道异步通知使用的后端公网地址，回调地址为 {notify_base_url}/api/v1/payment/notify/{provider}
+	NotifyBaseURL string `mapstructure:"notify_base_url"`
+	// ReturnURL 支付完成后跳转的前端页面；留空时为 {server.frontend_url}/payment/result
+	ReturnURL string `mapstructure:"return_url"`
+
+	EPay   EPayConfig          `mapstructure:"epay"`
+	Alipay AlipayConfig        `mapstructure:"alipay"`
+	Wechat WechatPayConfig     `mapstructure:"wechat"`
+	Stripe StripePaymentConfig `mapstructure:"stripe"`
+}
+
+// EPayConfig 易支付（彩虹易支付等兼容接口）配置
+type EPayConfig struct {
+	Enabled    bool   `mapstructure:"enabled"`
+	GatewayURL string `mapstructure:"gateway_url"` // 如 https://pay.example.com/（submit.php / api.php 所在目录）
+	PID        string `mapstructure:"pid"`
+	Key        string `mapstructure:"key"`
+	// Methods 允许用户选择的支付方式（alipay / wxpay / qqpay 等，取决于易支付平台）
+	Methods []string `mapstructure:"methods"`
+}
+
+// AlipayConfig 支付宝开放平台（电脑网站 / 手机网站支付）配置
+type AlipayConfig struct {
+	Enabled    bool   `mapstructure:"enabled"`
+	AppID      string `mapstructure:"app_id"`
+	PrivateKey string `mapstructure:"private_key"` // 应用私钥（RSA2），PEM 或 Base64
+	PublicKey  string `mapstructure:"public_key"`  // 支付宝公钥（非应用公钥），PEM 或 Base64
+	GatewayURL string `mapstructure:"gateway_url"`
+}
+
+// WechatPayConfig 微信支付 APIv3（Native 扫码支付）配置
+type WechatPayConfig struct {
+	Enabled    bool   `mapstructure:"enabled"`
+	AppID      string `mapstructure:"app_id"`
+	MchID      string `mapstructure:"mch_id"`
+	SerialNo   string `mapstructure:"serial_no"`   // 商户 API 证书序列号
+	PrivateKey string `mapstructure:"private_key"` // 商户 API 私钥，PEM
+	APIv3Key   string `mapstructure:"api_v3_key"`  // 32 字节 APIv3 密钥
+	// PlatformPublicKey 微信支付公钥（或平台证书），用于验证应答与回调签名
+	PlatformPublicKey string `mapstructure:"platform_public_key"`
+	APIBaseURL        string `mapstructure:"api_base_url"`
+}
+
+// StripePaymentConfig Stripe Checkout 配置
+type StripePaymentConfig struct {
+	Enabled       bool   `mapstructure:"enabled"`
+	SecretKey     string `mapstructure:"secret_key"`
+	WebhookSecret string `mapstructure:"webhook_secret"`
+	APIBaseURL    string `mapstructure:"api_base_url"`
+}
+
+// ReferralConfig 推荐返佣配置
+type ReferralConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// BonusAmount 每成功推荐一位用户发放给推荐人的一次性奖励（USD），0 = 不发放
+	BonusAmount float64 `mapstructure:"bonus_amount"`
+	// BonusMinRecharge 被推荐人累计充值达到该金额（USD）后才发放一次性奖励，0 = 注册后即发放
+	BonusMinRecharge float64 `mapstructure:"bonus_min_recharge"`
+	// CommissionBasis 返佣基数：recharge（支付订单与外部支付对接的付费充值）或 usage（余额扣费的实际消费）
+	CommissionBasis string `mapstructure:"commission_basis"`
+	// CommissionRate 返佣比例（0-1）
+	CommissionRate float64 `mapstructure:"commission_rate"`
+	// CommissionDays 被推荐人注册后多少天内的充值/消费计入返佣，0 = 永久
+	CommissionDays int `mapstructure:"commission_days"`
+	// Payout 返佣发放方式：balance（直接计入推荐人余额）或 wallet（计入可提现的佣金钱包）
+	Payout string `mapstructure:"payout"`
+	// MinWithdrawal 佣金钱包线下提现的单次最低金额（USD）
+	MinWithdrawal float64 `mapstructure:"min_withdrawal"`
+	// SettleIntervalSeconds 返佣结算间隔（秒）
+	SettleIntervalSeconds int `mapstructure:"settle_interval_seconds"`
+
+	// BlockSameIP 被推荐人注册 IP 是推荐人登录过的 IP 时标记为可疑（不发放奖励与返佣，等待管理员审核）
+	BlockSameIP bool `mapstructure:"block_same_ip"`
+	// BlockSameDevice 被推荐人注册时的 User-Agent 与推荐人登录时使用的完全一致时标记为可疑
+	BlockSameDevice bool `mapstructure:"block_same_device"`
+	// MaxReferralsPerIP 同一推荐人名下来自同一注册 IP 的被推荐人上限，超出的标记为可疑，0 = 不限制
+	MaxReferralsPerIP int `mapstructure:"max_referrals_per_ip"`
+}
+
 type TurnstileConfig struct {
 	Required bool `mapstructure:"required"`
 }
@@ -953,6 +1125,67 @@ type UsageCleanupConfig struct {
 	TaskTimeoutSeconds int `mapstructure:"task_timeout_seconds"`
 }
 
+// BatchConfig 异步批处理（/v1/batches、/v1/messages/batches）配置
+type BatchConfig struct {
+	// Enabled: 是否启用批处理接口与后台执行器
+	Enabled bool `mapstructure:"enabled"`
+	// DiscountRate: 批处理请求在分组/用户倍率之上叠加的计费倍率（0.5 表示五折）
+	DiscountRate float64 `mapstructure:"discount_rate"`
+	// WorkerIntervalSeconds: 后台执行器轮询间隔（秒）
+	WorkerIntervalSeconds int `mapstructure:"worker_interval_seconds"`
+	// Concurrency: 单个任务每轮并发回放的最大行数
+	Concurrency int `mapstructure:"concurrency"`
+	// MaxGroupLoadPercent: 分组账号负载（%）达到该值时暂停领取新行，只使用空闲容量
+	MaxGroupLoadPercent int `mapstructure:"max_group_load_percent"`
+	// MaxFileSizeMB: 单个输入文件最大体积（MB）
+	MaxFileSizeMB int `mapstructure:"max_file_size_mb"`
+	// MaxRequestsPerBatch: 单个任务最大请求行数
+	MaxRequestsPerBatch int `mapstructure:"max_requests_per_batch"`
+	// ItemTimeoutSeconds: 单行请求执行超时（秒），超时未完成的行会被重新领取
+	ItemTimeoutSeconds int `mapstructure:"item_timeout_seconds"`
+	// FileRetentionDays: 上传文件与结果文件保留天数
+	FileRetentionDays int `mapstructure:"file_retention_days"`
+}
+
+// MetricsConfig Prometheus 指标导出配置
+type MetricsConfig struct {
+	// Enabled: 是否暴露 Prometheus 指标端点
+	Enabled bool `mapstructure:"enabled"`
+	// ListenAddr: 独立监听地址（如 "127.0.0.1:9090"）；为空时挂载在主服务端口上
+	ListenAddr string `mapstructure:"listen_addr"`
+	// Path: 指标端点路径
+	Path string `mapstructure:"path"`
+	// BearerToken: 抓取时要求的 Bearer Token；为空表示不鉴权（建议配合独立监听地址或网络隔离使用）
+	BearerToken string `mapstructure:"bearer_token"`
+	// AccountStateRefreshSeconds: 账号状态/并发快照的缓存时间（秒），避免每次抓取都全量扫描账号
+	AccountStateRefreshSeconds int `mapstructure:"account_state_refresh_seconds"`
+}
+
+const (
+	TracingExporterOTLPGRPC = "otlp_grpc"
+	TracingExporterOTLPHTTP = "otlp_http"
+)
+
+// TracingConfig OpenTelemetry 链路追踪配置
+type TracingConfig struct {
+	// Enabled: 是否启用 OTLP 链路追踪导出
+	Enabled bool `mapstructure:"enabled"`
+	// Exporter: 导出协议，otlp_grpc 或 otlp_http
+	Exporter string `mapstructure:"exporter"`
+	// Endpoint: Collector 地址；otlp_grpc 为 host:port，otlp_http 可为 host:port 或完整 URL
+	Endpoint string `mapstructure:"endpoint"`
+	// Insecure: 是否使用明文连接（不启用 TLS）
+	Insecure bool `mapstructure:"insecure"`
+	// Headers: 导出时附带的请求头（如鉴权 Token）
+	Headers map[string]string `mapstructure:"headers"`
+	// SampleRatio: 根 Span 采样比例（0~1）；携带 traceparent 的请求沿用上游采样决定
+	SampleRatio float64 `mapstructure:"sample_ratio"`
+	// ServiceName: 上报的 service.name；为空时沿用 log.service_name
+	ServiceName string `mapstructure:"service_name"`
+	// ExportTimeoutSeconds: 单次导出超时（秒）
+	ExportTimeoutSeconds int `mapstructure:"export_timeout_seconds"`
+}
+
 func NormalizeRunMode(value string) string {
 	normalized := strings.ToLower(strings.TrimSpace(value))
 	switch normalized {
@@ -1176,11 +1409,22 @@ func setDefaults() {
 	// Security - disable direct fallback on proxy error
 	viper.SetDefault("security.proxy_fallback.allow_direct_on_error", false)
 
+	// Security - credential encryption at rest
+	viper.SetDefault("security.credential_encryption.master_key", "")
+	viper.SetDefault("security.credential_encryption.previous_keys", []string{})
+
 	// Billing
 	viper.SetDefault("billing.circuit_breaker.enabled", true)
 	viper.SetDefault("billing.circuit_breaker.failure_threshold", 5)
 	viper.SetDefault("billing.circuit_breaker.reset_timeout_seconds", 30)
 	viper.SetDefault("billing.circuit_breaker.half_open_requests", 3)
+	viper.SetDefault("billing.holds.enabled", false)
+	viper.SetDefault("billing.holds.default_max_output_tokens", 4096)
+	viper.SetDefault("billing.holds.ttl_seconds", 1800)
+	viper.SetDefault("billing.holds.settle_grace_seconds", 30)
+	viper.SetDefault("billing.ledger_reconcile.enabled", true)
+	viper.SetDefault("billing.ledger_reconcile.interval_seconds", 3600)
+	viper.SetDefault("billing.ledger_reconcile.sample_limit", 50)
 
 	// Turnstile
 	viper.SetDefault("turnstile.required", false)
@@ -1249,6 +1493,47 @@ func setDefaults() {
 	// TOTP
 	viper.SetDefault("totp.encryption_key", "")
 
+	// WebAuthn
+	viper.SetDefault("webauthn.rp_id", "")
+	viper.SetDefault("webauthn.rp_name", "Sub2API")
+	viper.SetDefault("webauthn.origins", []string{})
+
+	// Login history
+	viper.SetDefault("login_history.retention_days", 180)
+	viper.SetDefault("login_history.notify_new_device", false)
+
+	// Payment
+	viper.SetDefault("payment.enabled", false)
+	viper.SetDefault("payment.order_expire_minutes", 30)
+	viper.SetDefault("payment.max_pending_orders", 5)
+	viper.SetDefault("payment.notify_base_url", "")
+	viper.SetDefault("payment.return_url", "")
+	viper.SetDefault("payment.epay.enabled", false)
+	viper.SetDefault("payment.epay.gateway_url", "")
+	viper.SetDefault("payment.epay.pid", "")
+	viper.SetDefault("payment.epay.key", "")
+	viper.SetDefault("payment.epay.methods", []string{"alipay", "wxpay"})
+	viper.SetDefault("payment.alipay.enabled", false)
+	viper.SetDefault("payment.alipay.gateway_url", "https://openapi.alipay.com/gateway.do")
+	viper.SetDefault("payment.wechat.enabled", false)
+	viper.SetDefault("payment.wechat.api_base_url", "https://api.mch.weixin.qq.com")
+	viper.SetDefault("payment.stripe.enabled", false)
+	viper.SetDefault("payment.stripe.api_base_url", "https://api.stripe.com")
+
+	// Referral
+	viper.SetDefault("referral.enabled", false)
+	viper.SetDefault("referral.bonus_amount", 0.0)
+	viper.SetDefault("referral.bonus_min_recharge", 0.0)
+	viper.SetDefault("referral.commission_basis", "recharge")
+	viper.SetDefault("referral.commission_rate", 0.1)
+	viper.SetDefault("referral.commission_days", 365)
+	viper.SetDefault("referral.payout", "balance")
+	viper.SetDefault("referral.min_withdrawal", 10.0)
+	viper.SetDefault("referral.settle_interval_seconds", 300)
+	viper.SetDefault("referral.block_same_ip", true)
+	viper.SetDefault("referral.block_same_device", false)
+	viper.SetDefault("referral.max_referrals_per_ip", 3)
+
 	// Default
 	// Admin credentials are created via the setup flow (web wizard / CLI / AUTO_SETUP).
 	// Do not ship fixed defaults here to avoid insecure "known credentials" in production.
@@ -1312,6 +1597,33 @@ func setDefaults() {
 	viper.SetDefault("usage_cleanup.worker_interval_seconds", 10)
 	viper.SetDefault("usage_cleanup.task_timeout_seconds", 1800)
 
+	// Batch
+	viper.SetDefault("batch.enabled", true)
+	viper.SetDefault("batch.discount_rate", 0.5)
+	viper.SetDefault("batch.worker_interval_seconds", 5)
+	viper.SetDefault("batch.concurrency", 4)
+	viper.SetDefault("batch.max_group_load_percent", 70)
+	viper.SetDefault("batch.max_file_size_mb", 100)
+	viper.SetDefault("batch.max_requests_per_batch", 50000)
+	viper.SetDefault("batch.item_timeout_seconds", 600)
+	viper.SetDefault("batch.file_retention_days", 30)
+
+	// Metrics
+	viper.SetDefault("metrics.enabled", false)
+	viper.SetDefault("metrics.listen_addr", "")
+	viper.SetDefault("metrics.path", "/metrics")
+	viper.SetDefault("metrics.bearer_token", "")
+	viper.SetDefault("metrics.account_state_refresh_seconds", 30)
+
+	// Tracing
+	viper.SetDefault("tracing.enabled", false)
+	viper.SetDefault("tracing.exporter", TracingExporterOTLPGRPC)
+	viper.SetDefault("tracing.endpoint", "localhost:4317")
+	viper.SetDefault("tracing.insecure", true)
+	viper.SetDefault("tracing.sample_ratio", 1.0)
+	viper.SetDefault("tracing.service_name", "")
+	viper.SetDefault("tracing.export_timeout_seconds", 10)
+
 	// Idempotency
 	viper.SetDefault("idempotency.observe_only", true)
 	viper.SetDefault("idempotency.default_ttl_seconds", 86400)
@@ -1433,6 +1745,14 @@ func setDefaults() {
 	viper.SetDefault("gateway.usage_record.auto_scale_down_step", 16)
 	viper.SetDefault("gateway.usage_record.auto_scale_check_interval_seconds", 3)
 	viper.SetDefault("gateway.usage_record.auto_scale_cooldown_seconds", 10)
+	viper.SetDefault("gateway.usage_record.durable.enabled", false)
+	viper.SetDefault("gateway.usage_record.durable.stream_key", "usage:journal")
+	viper.SetDefault("gateway.usage_record.durable.consumer_group", "usage-billing")
+	viper.SetDefault("gateway.usage_record.durable.consumer_name", "")
+	viper.SetDefault("gateway.usage_record.durable.batch_size", 100)
+	viper.SetDefault("gateway.usage_record.durable.block_milliseconds", 2000)
+	viper.SetDefault("gateway.usage_record.durable.claim_idle_seconds", 60)
+	viper.SetDefault("gateway.usage_record.durable.max_deliveries", 10)
 	viper.SetDefault("gateway.user_group_rate_cache_ttl_seconds", 30)
 	viper.SetDefault("gateway.models_list_cache_ttl_seconds", 15)
 	// TLS指纹伪装配置（默认关闭，需要账号级别单独启用）
@@ -1591,6 +1911,106 @@ func (c *Config) Validate() error {
 		}
 		warnIfInsecureURL("server.frontend_url", c.Server.FrontendURL)
 	}
+	if strings.Contains(c.WebAuthn.RPID, "/") || strings.Contains(c.WebAuthn.RPID, ":") {
+		return fmt.Errorf("webauthn.rp_id must be a bare domain without scheme or port")
+	}
+	for _, origin := range c.WebAuthn.Origins {
+		if err := ValidateAbsoluteHTTPURL(origin); err != nil {
+			return fmt.Errorf("webauthn.origins invalid: %w", err)
+		}
+	}
+	if c.LoginHistory.RetentionDays < 0 {
+		return fmt.Errorf("login_history.retention_days must be non-negative")
+	}
+	if c.Payment.Enabled {
+		pay := c.Payment
+		if pay.OrderExpireMinutes <= 0 {
+			return fmt.Errorf("payment.order_expire_minutes must be positive")
+		}
+		if pay.MaxPendingOrders < 0 {
+			return fmt.Errorf("payment.max_pending_orders must be non-negative")
+		}
+		if err := ValidateAbsoluteHTTPURL(pay.NotifyBaseURL); err != nil {
+			return fmt.Errorf("payment.notify_base_url invalid: %w", err)
+		}
+		warnIfInsecureURL("payment.notify_base_url", pay.NotifyBaseURL)
+		if strings.TrimSpace(pay.ReturnURL) != "" {
+			if err := ValidateAbsoluteHTTPURL(pay.ReturnURL); err != nil {
+				return fmt.Errorf("payment.return_url invalid: %w", err)
+			}
+		}
+		if pay.EPay.Enabled {
+			if err := ValidateAbsoluteHTTPURL(pay.EPay.GatewayURL); err != nil {
+				return fmt.Errorf("payment.epay.gateway_url invalid: %w", err)
+			}
+			if strings.TrimSpace(pay.EPay.PID) == "" || strings.TrimSpace(pay.EPay.Key) == "" {
+				return fmt.Errorf("payment.epay.pid and payment.epay.key are required when payment.epay.enabled=true")
+			}
+			if len(pay.EPay.Methods) == 0 {
+				return fmt.Errorf("payment.epay.methods must not be empty when payment.epay.enabled=true")
+			}
+		}
+		if pay.Alipay.Enabled {
+			if strings.TrimSpace(pay.Alipay.AppID) == "" || strings.TrimSpace(pay.Alipay.PrivateKey) == "" || strings.TrimSpace(pay.Alipay.PublicKey) == "" {
+				return fmt.Errorf("payment.alipay.app_id, private_key and public_key are required when payment.alipay.enabled=true")
+			}
+			if err := ValidateAbsoluteHTTPURL(pay.Alipay.GatewayURL); err != nil {
+				return fmt.Errorf("payment.alipay.gateway_url invalid: %w", err)
+			}
+		}
+		if pay.Wechat.Enabled {
+			w := pay.Wechat
+			if strings.TrimSpace(w.AppID) == "" || strings.TrimSpace(w.MchID) == "" || strings.TrimSpace(w.SerialNo) == "" ||
+				strings.TrimSpace(w.PrivateKey) == "" || strings.TrimSpace(w.PlatformPublicKey) == "" {
+				return fmt.Errorf("payment.wechat.app_id, mch_id, serial_no, private_key and platform_public_key are required when payment.wechat.enabled=true")
+			}
+			if len(w.APIv3Key) != 32 {
+				return fmt.Errorf("payment.wechat.api_v3_key must be 32 bytes")
+			}
+			if err := ValidateAbsoluteHTTPURL(w.APIBaseURL); err != nil {
+				return fmt.Errorf("payment.wechat.api_base_url invalid: %w", err)
+			}
+		}
+		if pay.Stripe.Enabled {
+			if strings.TrimSpace(pay.Stripe.SecretKey) == "" || strings.TrimSpace(pay.Stripe.WebhookSecret) == "" {
+				return fmt.Errorf("payment.stripe.secret_key and webhook_secret are required when payment.stripe.enabled=true")
+			}
+			if err := ValidateAbsoluteHTTPURL(pay.Stripe.APIBaseURL); err != nil {
+				return fmt.Errorf("payment.stripe.api_base_url invalid: %w", err)
+			}
+		}
+	}
+	if c.Referral.Enabled {
+		ref := c.Referral
+		if ref.BonusAmount < 0 || ref.BonusMinRecharge < 0 {
+			return fmt.Errorf("referral.bonus_amount and referral.bonus_min_recharge must be non-negative")
+		}
+		switch ref.CommissionBasis {
+		case "recharge", "usage":
+		default:
+			return fmt.Errorf("referral.commission_basis must be one of: recharge/usage")
+		}
+		if ref.CommissionRate < 0 || ref.CommissionRate > 1 {
+			return fmt.Errorf("referral.commission_rate must be between 0 and 1")
+		}
+		if ref.CommissionDays < 0 {
+			return fmt.Errorf("referral.commission_days must be non-negative")
+		}
+		switch ref.Payout {
+		case "balance", "wallet":
+		default:
+			return fmt.Errorf("referral.payout must be one of: balance/wallet")
+		}
+		if ref.MinWithdrawal < 0 {
+			return fmt.Errorf("referral.min_withdrawal must be non-negative")
+		}
+		if ref.SettleIntervalSeconds <= 0 {
+			return fmt.Errorf("referral.settle_interval_seconds must be positive")
+		}
+		if ref.MaxReferralsPerIP < 0 {
+			return fmt.Errorf("referral.max_referrals_per_ip must be non-negative")
+		}
+	}
 	if c.JWT.ExpireHour <= 0 {
 		return fmt.Errorf("jwt.expire_hour must be positive")
 	}
@@ -1619,6 +2039,21 @@ func (c *Config) Validate() error {
 	if c.Security.CSP.Enabled && strings.TrimSpace(c.Security.CSP.Policy) == "" {
 		return fmt.Errorf("security.csp.policy is required when CSP is enabled")
 	}
+	credEnc := c.Security.CredentialEncryption
+	if strings.TrimSpace(credEnc.MasterKey) == "" {
+		if len(credEnc.PreviousKeys) > 0 {
+			return fmt.Errorf("security.credential_encryption.previous_keys requires master_key")
+		}
+	} else {
+		if !isAES256HexKey(credEnc.MasterKey) {
+			return fmt.Errorf("security.credential_encryption.master_key must be 32 bytes (64 hex chars)")
+		}
+		for i, key := range credEnc.PreviousKeys {
+			if !isAES256HexKey(key) {
+				return fmt.Errorf("security.credential_encryption.previous_keys[%d] must be 32 bytes (64 hex chars)", i)
+			}
+		}
+	}
 	if c.LinuxDo.Enabled {
 		if strings.TrimSpace(c.LinuxDo.ClientID) == "" {
 			return fmt.Errorf("linuxdo_connect.client_id is required when linuxdo_connect.enabled=true")
@@ -1685,6 +2120,28 @@ func (c *Config) Validate() error {
 			return fmt.Errorf("billing.circuit_breaker.half_open_requests must be positive")
 		}
 	}
+	if c.Billing.Holds.Enabled {
+		if c.Billing.Holds.DefaultMaxOutputTokens <= 0 {
+			return fmt.Errorf("billing.holds.default_max_output_tokens must be positive")
+		}
+		if c.Billing.Holds.TTLSeconds <= 0 {
+			return fmt.Errorf("billing.holds.ttl_seconds must be positive")
+		}
+		if c.Billing.Holds.SettleGraceSeconds <= 0 {
+			return fmt.Errorf("billing.holds.settle_grace_seconds must be positive")
+		}
+		if c.Billing.Holds.SettleGraceSeconds > c.Billing.Holds.TTLSeconds {
+			return fmt.Errorf("billing.holds.settle_grace_seconds must not exceed billing.holds.ttl_seconds")
+		}
+	}
+	if c.Billing.LedgerReconcile.Enabled {
+		if c.Billing.LedgerReconcile.IntervalSeconds <= 0 {
+			return fmt.Errorf("billing.ledger_reconcile.interval_seconds must be positive")
+		}
+		if c.Billing.LedgerReconcile.SampleLimit <= 0 {
+			return fmt.Errorf("billing.ledger_reconcile.sample_limit must be positive")
+		}
+	}
 	if c.Database.MaxOpenConns <= 0 {
 		return fmt.Errorf("database.max_open_conns must be positive")
 	}
@@ -1817,6 +2274,50 @@ func (c *Config) Validate() error {
 			return fmt.Errorf("usage_cleanup.task_timeout_seconds must be non-negative")
 		}
 	}
+	if c.Batch.DiscountRate < 0 {
+		return fmt.Errorf("batch.discount_rate must be non-negative")
+	}
+	if c.Batch.Enabled {
+		if c.Batch.WorkerIntervalSeconds <= 0 {
+			return fmt.Errorf("batch.worker_interval_seconds must be positive")
+		}
+		if c.Batch.Concurrency <= 0 {
+			return fmt.Errorf("batch.concurrency must be positive")
+		}
+		if c.Batch.MaxFileSizeMB <= 0 {
+			return fmt.Errorf("batch.max_file_size_mb must be positive")
+		}
+		if c.Batch.MaxRequestsPerBatch <= 0 {
+			return fmt.Errorf("batch.max_requests_per_batch must be positive")
+		}
+		if c.Batch.ItemTimeoutSeconds <= 0 {
+			return fmt.Errorf("batch.item_timeout_seconds must be positive")
+		}
+	}
+	if c.Metrics.Enabled {
+		if !strings.HasPrefix(c.Metrics.Path, "/") {
+			return fmt.Errorf("metrics.path must start with /")
+		}
+		if c.Metrics.AccountStateRefreshSeconds <= 0 {
+			return fmt.Errorf("metrics.account_state_refresh_seconds must be positive")
+		}
+	}
+	if c.Tracing.Enabled {
+		switch c.Tracing.Exporter {
+		case TracingExporterOTLPGRPC, TracingExporterOTLPHTTP:
+		default:
+			return fmt.Errorf("tracing.exporter must be one of: %s/%s", TracingExporterOTLPGRPC, TracingExporterOTLPHTTP)
+		}
+		if strings.TrimSpace(c.Tracing.Endpoint) == "" {
+			return fmt.Errorf("tracing.endpoint is required when tracing is enabled")
+		}
+		if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+			return fmt.Errorf("tracing.sample_ratio must be between 0 and 1")
+		}
+		if c.Tracing.ExportTimeoutSeconds <= 0 {
+			return fmt.Errorf("tracing.export_timeout_seconds must be positive")
+		}
+	}
 	if c.Idempotency.DefaultTTLSeconds <= 0 {
 		return fmt.Errorf("idempotency.default_ttl_seconds must be positive")
 	}
@@ -2149,6 +2650,26 @@ func (c *Config) Validate() error {
 			return fmt.Errorf("gateway.usage_record.auto_scale_cooldown_seconds must be non-negative")
 		}
 	}
+	if durable := c.Gateway.UsageRecord.Durable; durable.Enabled {
+		if strings.TrimSpace(durable.StreamKey) == "" {
+			return fmt.Errorf("gateway.usage_record.durable.stream_key is required when durable is enabled")
+		}
+		if strings.TrimSpace(durable.ConsumerGroup) == "" {
+			return fmt.Errorf("gateway.usage_record.durable.consumer_group is required when durable is enabled")
+		}
+		if durable.BatchSize <= 0 || durable.BatchSize > 1000 {
+			return fmt.Errorf("gateway.usage_record.durable.batch_size must be between 1-1000")
+		}
+		if durable.BlockMilliseconds <= 0 {
+			return fmt.Errorf("gateway.usage_record.durable.block_milliseconds must be positive")
+		}
+		if durable.ClaimIdleSeconds <= 0 {
+			return fmt.Errorf("gateway.usage_record.durable.claim_idle_seconds must be positive")
+		}
+		if durable.MaxDeliveries <= 0 {
+			return fmt.Errorf("gateway.usage_record.durable.max_deliveries must be positive")
+		}
+	}
 	if c.Gateway.UserGroupRateCacheTTLSeconds <= 0 {
 		return fmt.Errorf("gateway.user_group_rate_cache_ttl_seconds must be positive")
 	}
@@ -2265,6 +2786,12 @@ func generateJWTSecret(byteLength int) (string, error) {
 	return hex.EncodeToString(buf), nil
 }
 
+// isAES256HexKey 判断是否为 hex 编码的 32 字节密钥
+func isAES256HexKey(value string) bool {
+	key, err := hex.DecodeString(strings.TrimSpace(value))
+	return err == nil && len(key) == 32
+}
+
 // GetServerAddress returns the server address (host:port) from config file or environment variable.
 // This is a lightweight function that can be used before full config validation,
 // such as during setup wizard startup.
@@ -909,6 +909,42 @@ func TestValidateConfigErrors(t *testing.T) {
 			mutate:  func(c *Config) { c.Security.CSP.Enabled = true; c.Security.CSP.Policy = "" },
 			wantErr: "security.csp.policy",
 		},
+		{
+			name: "payment notify base url required",
+			mutate: func(c *Config) {
+				c.Payment.Enabled = true
+				c.Payment.NotifyBaseURL = ""
+			},
+			wantErr: "payment.notify_base_url",
+		},
+		{
+			name: "payment wechat api v3 key length",
+			mutate: func(c *Config) {
+				c.Payment.Enabled = true
+				c.Payment.NotifyBaseURL = "https://api.example.com"
+				c.Payment.Wechat = WechatPayConfig{
+					Enabled: true, AppID: "wx1", MchID: "1900000001", SerialNo: "SN", PrivateKey: "k", PlatformPublicKey: "p",
+					APIv3Key: "short", APIBaseURL: "https://api.mch.weixin.qq.com",
+				}
+			},
+			wantErr: "payment.wechat.api_v3_key",
+		},
+		{
+			name: "referral commission basis",
+			mutate: func(c *Config) {
+				c.Referral.Enabled = true
+				c.Referral.CommissionBasis = "signup"
+			},
+			wantErr: "referral.commission_basis",
+		},
+		{
+			name: "referral commission rate range",
+			mutate: func(c *Config) {
+				c.Referral.Enabled = true
+				c.Referral.CommissionRate = 1.5
+			},
+			wantErr: "referral.commission_rate",
+		},
 		{
 			name: "linuxdo client id required",
 			mutate: func(c *Config) {
@@ -1189,6 +1225,55 @@ func TestValidateConfigErrors(t *testing.T) {
 			mutate:  func(c *Config) { c.Gateway.UsageRecord.AutoScaleCheckIntervalSeconds = 0 },
 			wantErr: "gateway.usage_record.auto_scale_check_interval_seconds",
 		},
+		{
+			name: "gateway usage record durable batch size",
+			mutate: func(c *Config) {
+				c.Gateway.UsageRecord.Durable.Enabled = true
+				c.Gateway.UsageRecord.Durable.BatchSize = 0
+			},
+			wantErr: "gateway.usage_record.durable.batch_size",
+		},
+		{
+			name: "gateway usage record durable stream key",
+			mutate: func(c *Config) {
+				c.Gateway.UsageRecord.Durable.Enabled = true
+				c.Gateway.UsageRecord.Durable.StreamKey = " "
+			},
+			wantErr: "gateway.usage_record.durable.stream_key",
+		},
+		{
+			name: "gateway usage record durable max deliveries",
+			mutate: func(c *Config) {
+				c.Gateway.UsageRecord.Durable.Enabled = true
+				c.Gateway.UsageRecord.Durable.MaxDeliveries = 0
+			},
+			wantErr: "gateway.usage_record.durable.max_deliveries",
+		},
+		{
+			name: "billing holds default max output tokens",
+			mutate: func(c *Config) {
+				c.Billing.Holds.Enabled = true
+				c.Billing.Holds.DefaultMaxOutputTokens = 0
+			},
+			wantErr: "billing.holds.default_max_output_tokens",
+		},
+		{
+			name: "billing holds settle grace exceeds ttl",
+			mutate: func(c *Config) {
+				c.Billing.Holds.Enabled = true
+				c.Billing.Holds.TTLSeconds = 10
+				c.Billing.Holds.SettleGraceSeconds = 60
+			},
+			wantErr: "billing.holds.settle_grace_seconds",
+		},
+		{
+			name: "billing ledger reconcile interval",
+			mutate: func(c *Config) {
+				c.Billing.LedgerReconcile.Enabled = true
+				c.Billing.LedgerReconcile.IntervalSeconds = 0
+			},
+			wantErr: "billing.ledger_reconcile.interval_seconds",
+		},
 		{
 			name:    "gateway user group rate cache ttl",
 			mutate:  func(c *Config) { c.Gateway.UserGroupRateCacheTTLSeconds = 0 },
@@ -1268,6 +1353,22 @@ func TestValidateConfigErrors(t *testing.T) {
 			mutate:  func(c *Config) { c.Ops.Cleanup.MinuteMetricsRetentionDays = -1 },
 			wantErr: "ops.cleanup.minute_metrics_retention_days",
 		},
+		{
+			name: "tracing exporter",
+			mutate: func(c *Config) {
+				c.Tracing.Enabled = true
+				c.Tracing.Exporter = "zipkin"
+			},
+			wantErr: "tracing.exporter",
+		},
+		{
+			name: "tracing sample ratio",
+			mutate: func(c *Config) {
+				c.Tracing.Enabled = true
+				c.Tracing.SampleRatio = 1.5
+			},
+			wantErr: "tracing.sample_ratio",
+		},
 	}
 
 	for _, tt := range cases {
@@ -24,6 +24,8 @@ const (
 const (
 	RoleAdmin = "admin"
 	RoleUser  = "user"
+	// RoleOrganization 组织账户：持有组织的共享余额与订阅，不可登录
+	RoleOrganization = "organization"
 )
 
 // Platform constants
@@ -58,6 +60,13 @@ const (
 	PromoCodeStatusDisabled = "disabled"
 )
 
+// PromoCode top-up bonus status constants (promo_code_usages.topup_bonus_status)
+const (
+	PromoTopupBonusStatusNone    = "none"    // 优惠码不含充值赠送
+	PromoTopupBonusStatusPending = "pending" // 等待首次达标充值
+	PromoTopupBonusStatusGranted = "granted" // 已发放
+)
+
 // Admin adjustment type constants
 const (
 	AdjustmentTypeAdminBalance     = "admin_balance"     // 管理员调整余额
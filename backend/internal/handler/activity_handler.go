@@ -92,6 +92,30 @@ func (h *ActivityHandler) ParticipateInActivity(c *gin.Context) {
 	response.Success(c, result)
 }
 
+// GetTaskProgress returns the current user's goal progress for a task or limited-time activity
+// GET /api/v1/activities/:id/progress
+func (h *ActivityHandler) GetTaskProgress(c *gin.Context) {
+	subject, ok := middleware2.GetAuthSubjectFromContext(c)
+	if !ok {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	activityID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "Invalid activity ID")
+		return
+	}
+
+	status, err := h.activityService.GetTaskProgress(c.Request.Context(), subject.UserID, activityID)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+
+	response.Success(c, status)
+}
+
 // GetUserParticipations handles getting user participation history
 // GET /api/v1/activities/participations
 func (h *ActivityHandler) GetUserParticipations(c *gin.Context) {
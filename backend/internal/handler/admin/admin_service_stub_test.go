@@ -6,6 +6,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
 	"github.com/Wei-Shaw/sub2api/internal/service"
 )
 
@@ -84,7 +85,7 @@ func newStubAdminService() *stubAdminService {
 		ID:        5,
 		Code:      "R-TEST",
 		Type:      service.RedeemTypeBalance,
-		Value:     10,
+		Value:     10 * money.USD,
 		Status:    service.StatusUnused,
 		CreatedAt: now,
 	}
@@ -127,8 +128,8 @@ func (s *stubAdminService) DeleteUser(ctx context.Context, id int64) error {
 	return nil
 }
 
-func (s *stubAdminService) UpdateUserBalance(ctx context.Context, userID int64, balance float64, operation string, notes string) (*service.User, error) {
-	user := service.User{ID: userID, Balance: balance, Status: service.StatusActive}
+func (s *stubAdminService) UpdateUserBalance(ctx context.Context, userID int64, balance float64, operation string, notes string, operatorID int64) (*service.User, error) {
+	user := service.User{ID: userID, Balance: money.FromFloat(balance), Status: service.StatusActive}
 	return &user, nil
 }
 
@@ -408,7 +409,7 @@ func (s *stubAdminService) ExpireRedeemCode(ctx context.Context, id int64) (*ser
 	return &code, nil
 }
 
-func (s *stubAdminService) GetUserBalanceHistory(ctx context.Context, userID int64, page, pageSize int, codeType string) ([]service.RedeemCode, int64, float64, error) {
+func (s *stubAdminService) GetUserBalanceHistory(ctx context.Context, userID int64, page, pageSize int, codeType string) ([]service.RedeemCode, int64, money.Amount, error) {
 	return s.redeems, int64(len(s.redeems)), 100.0, nil
 }
 
@@ -0,0 +1,111 @@
+package admin
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/handler/dto"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/response"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/timezone"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// BalanceLedgerHandler handles admin balance ledger search and reconciliation
+type BalanceLedgerHandler struct {
+	balanceLedgerService *service.BalanceLedgerService
+}
+
+// NewBalanceLedgerHandler creates a new admin balance ledger handler
+func NewBalanceLedgerHandler(balanceLedgerService *service.BalanceLedgerService) *BalanceLedgerHandler {
+	return &BalanceLedgerHandler{balanceLedgerService: balanceLedgerService}
+}
+
+// Search handles searching balance ledger entries
+// GET /api/v1/admin/balance-ledger
+// Query params:
+//   - user_id, operator_id: exact match
+//   - source_type: redeem, promo_code, admin_adjust, activity_reward, activity_cost, checkin, usage, initial, opening
+//   - reference_id: redeem/promo code ID, request_id, ...
+//   - start_date / end_date: YYYY-MM-DD in the given timezone
+func (h *BalanceLedgerHandler) Search(c *gin.Context) {
+	page, pageSize := response.ParsePagination(c)
+
+	filter := service.BalanceLedgerFilter{
+		SourceType:  strings.TrimSpace(c.Query("source_type")),
+		ReferenceID: strings.TrimSpace(c.Query("reference_id")),
+	}
+	if userIDStr := c.Query("user_id"); userIDStr != "" {
+		id, err := strconv.ParseInt(userIDStr, 10, 64)
+		if err != nil || id <= 0 {
+			response.BadRequest(c, "Invalid user_id")
+			return
+		}
+		filter.UserID = id
+	}
+	if operatorIDStr := c.Query("operator_id"); operatorIDStr != "" {
+		id, err := strconv.ParseInt(operatorIDStr, 10, 64)
+		if err != nil || id <= 0 {
+			response.BadRequest(c, "Invalid operator_id")
+			return
+		}
+		filter.OperatorID = &id
+	}
+
+	userTZ := c.Query("timezone")
+	if startDateStr := c.Query("start_date"); startDateStr != "" {
+		t, err := timezone.ParseInUserLocation("2006-01-02", startDateStr, userTZ)
+		if err != nil {
+			response.BadRequest(c, "Invalid start_date format, use YYYY-MM-DD")
+			return
+		}
+		filter.StartTime = &t
+	}
+	if endDateStr := c.Query("end_date"); endDateStr != "" {
+		t, err := timezone.ParseInUserLocation("2006-01-02", endDateStr, userTZ)
+		if err != nil {
+			response.BadRequest(c, "Invalid end_date format, use YYYY-MM-DD")
+			return
+		}
+		t = t.Add(24 * time.Hour)
+		filter.EndTime = &t
+	}
+
+	params := pagination.PaginationParams{Page: page, PageSize: pageSize}
+	entries, result, err := h.balanceLedgerService.Search(c.Request.Context(), params, filter)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+
+	out := make([]dto.AdminBalanceTransaction, 0, len(entries))
+	for i := range entries {
+		out = append(out, *dto.BalanceTransactionFromServiceAdmin(&entries[i]))
+	}
+	response.Paginated(c, out, result.Total, page, pageSize)
+}
+
+// GetReconcile returns the latest reconciliation result (null when none has run yet)
+// GET /api/v1/admin/balance-ledger/reconcile
+func (h *BalanceLedgerHandler) GetReconcile(c *gin.Context) {
+	run, err := h.balanceLedgerService.LatestReconcile(c.Request.Context())
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, run)
+}
+
+// RunReconcile runs a reconciliation immediately
+// POST /api/v1/admin/balance-ledger/reconcile
+func (h *BalanceLedgerHandler) RunReconcile(c *gin.Context) {
+	run, err := h.balanceLedgerService.ReconcileNow(c.Request.Context())
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, run)
+}
@@ -0,0 +1,211 @@
+package admin
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/handler/dto"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/response"
+	middleware2 "github.com/Wei-Shaw/sub2api/internal/server/middleware"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CouponHandler handles coupon templates and issued coupons
+type CouponHandler struct {
+	couponService *service.CouponService
+}
+
+// NewCouponHandler creates a new admin coupon handler
+func NewCouponHandler(couponService *service.CouponService) *CouponHandler {
+	return &CouponHandler{couponService: couponService}
+}
+
+// CouponRequest represents create/update coupon template request
+type CouponRequest struct {
+	Name           string     `json:"name" binding:"required,max=100"`
+	Description    string     `json:"description"`
+	DiscountType   string     `json:"discount_type" binding:"required,oneof=percent fixed"`
+	PercentOff     int        `json:"percent_off" binding:"min=0,max=99"`
+	AmountOffCents int64      `json:"amount_off_cents" binding:"min=0"`
+	Currency       string     `json:"currency"`
+	MinAmountCents int64      `json:"min_amount_cents" binding:"min=0"`
+	Scope          string     `json:"scope" binding:"omitempty,oneof=all balance subscription product group"`
+	ScopeID        *int64     `json:"scope_id"`
+	ValidDays      int        `json:"valid_days" binding:"min=0"`
+	ExpiresAt      *time.Time `json:"expires_at"`
+	Enabled        bool       `json:"enabled"`
+}
+
+func (r *CouponRequest) toCoupon(id int64) *service.Coupon {
+	return &service.Coupon{
+		ID:             id,
+		Name:           r.Name,
+		Description:    r.Description,
+		DiscountType:   r.DiscountType,
+		PercentOff:     r.PercentOff,
+		AmountOffCents: r.AmountOffCents,
+		Currency:       r.Currency,
+		MinAmountCents: r.MinAmountCents,
+		Scope:          r.Scope,
+		ScopeID:        r.ScopeID,
+		ValidDays:      r.ValidDays,
+		ExpiresAt:      r.ExpiresAt,
+		Enabled:        r.Enabled,
+	}
+}
+
+// IssueCouponRequest represents the request to issue a coupon to users
+type IssueCouponRequest struct {
+	UserIDs []int64 `json:"user_ids" binding:"required,min=1,max=1000,dive,min=1"`
+}
+
+// ListCoupons lists coupon templates with issued and used counts
+// GET /api/v1/admin/coupons
+func (h *CouponHandler) ListCoupons(c *gin.Context) {
+	page, pageSize := response.ParsePagination(c)
+	params := pagination.PaginationParams{Page: page, PageSize: pageSize}
+
+	coupons, result, err := h.couponService.ListCoupons(c.Request.Context(), params)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+
+	out := make([]dto.AdminCoupon, 0, len(coupons))
+	for i := range coupons {
+		out = append(out, *dto.AdminCouponFromService(&coupons[i]))
+	}
+	response.Paginated(c, out, result.Total, page, pageSize)
+}
+
+// CreateCoupon creates a coupon template
+// POST /api/v1/admin/coupons
+func (h *CouponHandler) CreateCoupon(c *gin.Context) {
+	var req CouponRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	coupon := req.toCoupon(0)
+	if err := h.couponService.CreateCoupon(c.Request.Context(), coupon); err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, dto.AdminCouponFromService(coupon))
+}
+
+// UpdateCoupon replaces a coupon template; issued coupons keep their expiry
+// PUT /api/v1/admin/coupons/:id
+func (h *CouponHandler) UpdateCoupon(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "Invalid coupon ID")
+		return
+	}
+
+	var req CouponRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	if err := h.couponService.UpdateCoupon(c.Request.Context(), req.toCoupon(id)); err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	coupon, err := h.couponService.GetCoupon(c.Request.Context(), id)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, dto.AdminCouponFromService(coupon))
+}
+
+// DeleteCoupon deletes a coupon template and all coupons issued from it
+// DELETE /api/v1/admin/coupons/:id
+func (h *CouponHandler) DeleteCoupon(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "Invalid coupon ID")
+		return
+	}
+
+	if err := h.couponService.DeleteCoupon(c.Request.Context(), id); err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, gin.H{"message": "Coupon deleted successfully"})
+}
+
+// IssueCoupon issues a coupon to the given users
+// POST /api/v1/admin/coupons/:id/issue
+func (h *CouponHandler) IssueCoupon(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "Invalid coupon ID")
+		return
+	}
+
+	var req IssueCouponRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request: "+err.Error())
+		return
+	}
+	var operatorID int64
+	if subject, ok := middleware2.GetAuthSubjectFromContext(c); ok {
+		operatorID = subject.UserID
+	}
+
+	payload := struct {
+		ID int64 `json:"id"`
+		IssueCouponRequest
+	}{ID: id, IssueCouponRequest: req}
+	executeAdminIdempotentJSON(c, "admin.coupons.issue", payload, service.DefaultWriteIdempotencyTTL(), func(ctx context.Context) (any, error) {
+		issued, err := h.couponService.IssueByAdmin(ctx, id, req.UserIDs, operatorID)
+		if err != nil {
+			return nil, err
+		}
+		out := make([]dto.AdminUserCoupon, 0, len(issued))
+		for i := range issued {
+			out = append(out, *dto.AdminUserCouponFromService(&issued[i]))
+		}
+		return out, nil
+	})
+}
+
+// ListIssued lists issued coupons
+// GET /api/v1/admin/coupons/issued
+// Query params:
+//   - user_id / coupon_id: exact match
+//   - status: unused, locked, used, expired
+func (h *CouponHandler) ListIssued(c *gin.Context) {
+	page, pageSize := response.ParsePagination(c)
+
+	filter := service.UserCouponFilter{Status: strings.TrimSpace(c.Query("status"))}
+	var ok bool
+	if filter.UserID, ok = parseOptionalIDQuery(c, "user_id"); !ok {
+		return
+	}
+	if filter.CouponID, ok = parseOptionalIDQuery(c, "coupon_id"); !ok {
+		return
+	}
+
+	params := pagination.PaginationParams{Page: page, PageSize: pageSize}
+	coupons, result, err := h.couponService.ListUserCoupons(c.Request.Context(), params, filter)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+
+	out := make([]dto.AdminUserCoupon, 0, len(coupons))
+	for i := range coupons {
+		out = append(out, *dto.AdminUserCouponFromService(&coupons[i]))
+	}
+	response.Paginated(c, out, result.Total, page, pageSize)
+}
@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/Wei-Shaw/sub2api/internal/handler/dto"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
 	"github.com/Wei-Shaw/sub2api/internal/pkg/response"
 	"github.com/Wei-Shaw/sub2api/internal/service"
 
@@ -25,26 +26,28 @@ func NewGroupHandler(adminService service.AdminService) *GroupHandler {
 
 // CreateGroupRequest represents create group request
 type CreateGroupRequest struct {
-	Name             string   `json:"name" binding:"required"`
-	Description      string   `json:"description"`
-	Platform         string   `json:"platform" binding:"omitempty,oneof=anthropic openai gemini antigravity sora nano-banana"`
-	RateMultiplier   float64  `json:"rate_multiplier"`
-	IsExclusive      bool     `json:"is_exclusive"`
-	SubscriptionType string   `json:"subscription_type" binding:"omitempty,oneof=standard subscription"`
-	DailyLimitUSD    *float64 `json:"daily_limit_usd"`
-	WeeklyLimitUSD   *float64 `json:"weekly_limit_usd"`
-	MonthlyLimitUSD  *float64 `json:"monthly_limit_usd"`
+	Name             string        `json:"name" binding:"required"`
+	Description      string        `json:"description"`
+	Platform         string        `json:"platform" binding:"omitempty,oneof=anthropic openai gemini antigravity sora nano-banana"`
+	RateMultiplier   float64       `json:"rate_multiplier"`
+	IsExclusive      bool          `json:"is_exclusive"`
+	SubscriptionType string        `json:"subscription_type" binding:"omitempty,oneof=standard subscription"`
+	DailyLimitUSD    *money.Amount `json:"daily_limit_usd"`
+	WeeklyLimitUSD   *money.Amount `json:"weekly_limit_usd"`
+	MonthlyLimitUSD  *money.Amount `json:"monthly_limit_usd"`
 	// 图片生成计费配置（antigravity 和 gemini 平台使用，负数表示清除配置）
-	ImagePrice1K                    *float64 `json:"image_price_1k"`
-	ImagePrice2K                    *float64 `json:"image_price_2k"`
-	ImagePrice4K                    *float64 `json:"image_price_4k"`
-	SoraImagePrice360               *float64 `json:"sora_image_price_360"`
-	SoraImagePrice540               *float64 `json:"sora_image_price_540"`
-	SoraVideoPricePerRequest        *float64 `json:"sora_video_price_per_request"`
-	SoraVideoPricePerRequestHD      *float64 `json:"sora_video_price_per_request_hd"`
-	ClaudeCodeOnly                  bool     `json:"claude_code_only"`
-	FallbackGroupID                 *int64   `json:"fallback_group_id"`
-	FallbackGroupIDOnInvalidRequest *int64   `json:"fallback_group_id_on_invalid_request"`
+	ImagePrice1K                    *money.Amount `json:"image_price_1k"`
+	ImagePrice2K                    *money.Amount `json:"image_price_2k"`
+	ImagePrice4K                    *money.Amount `json:"image_price_4k"`
+	SoraImagePrice360               *money.Amount `json:"sora_image_price_360"`
+	SoraImagePrice540               *money.Amount `json:"sora_image_price_540"`
+	SoraVideoPricePerRequest        *money.Amount `json:"sora_video_price_per_request"`
+	SoraVideoPricePerRequestHD      *money.Amount `json:"sora_video_price_per_request_hd"`
+	AudioPricePerMinute             *money.Amount `json:"audio_price_per_minute"`
+	AudioSpeechPricePer1MChars      *money.Amount `json:"audio_speech_price_per_1m_chars"`
+	ClaudeCodeOnly                  bool          `json:"claude_code_only"`
+	FallbackGroupID                 *int64        `json:"fallback_group_id"`
+	FallbackGroupIDOnInvalidRequest *int64        `json:"fallback_group_id_on_invalid_request"`
 	// 模型路由配置（仅 anthropic 平台使用）
 	ModelRouting        map[string][]int64 `json:"model_routing"`
 	ModelRoutingEnabled bool               `json:"model_routing_enabled"`
@@ -59,27 +62,29 @@ type CreateGroupRequest struct {
 
 // UpdateGroupRequest represents update group request
 type UpdateGroupRequest struct {
-	Name             string   `json:"name"`
-	Description      string   `json:"description"`
-	Platform         string   `json:"platform" binding:"omitempty,oneof=anthropic openai gemini antigravity sora nano-banana"`
-	RateMultiplier   *float64 `json:"rate_multiplier"`
-	IsExclusive      *bool    `json:"is_exclusive"`
-	Status           string   `json:"status" binding:"omitempty,oneof=active inactive"`
-	SubscriptionType string   `json:"subscription_type" binding:"omitempty,oneof=standard subscription"`
-	DailyLimitUSD    *float64 `json:"daily_limit_usd"`
-	WeeklyLimitUSD   *float64 `json:"weekly_limit_usd"`
-	MonthlyLimitUSD  *float64 `json:"monthly_limit_usd"`
+	Name             string        `json:"name"`
+	Description      string        `json:"description"`
+	Platform         string        `json:"platform" binding:"omitempty,oneof=anthropic openai gemini antigravity sora nano-banana"`
+	RateMultiplier   *float64      `json:"rate_multiplier"`
+	IsExclusive      *bool         `json:"is_exclusive"`
+	Status           string        `json:"status" binding:"omitempty,oneof=active inactive"`
+	SubscriptionType string        `json:"subscription_type" binding:"omitempty,oneof=standard subscription"`
+	DailyLimitUSD    *money.Amount `json:"daily_limit_usd"`
+	WeeklyLimitUSD   *money.Amount `json:"weekly_limit_usd"`
+	MonthlyLimitUSD  *money.Amount `json:"monthly_limit_usd"`
 	// 图片生成计费配置（antigravity 和 gemini 平台使用，负数表示清除配置）
-	ImagePrice1K                    *float64 `json:"image_price_1k"`
-	ImagePrice2K                    *float64 `json:"image_price_2k"`
-	ImagePrice4K                    *float64 `json:"image_price_4k"`
-	SoraImagePrice360               *float64 `json:"sora_image_price_360"`
-	SoraImagePrice540               *float64 `json:"sora_image_price_540"`
-	SoraVideoPricePerRequest        *float64 `json:"sora_video_price_per_request"`
-	SoraVideoPricePerRequestHD      *float64 `json:"sora_video_price_per_request_hd"`
-	ClaudeCodeOnly                  *bool    `json:"claude_code_only"`
-	FallbackGroupID                 *int64   `json:"fallback_group_id"`
-	FallbackGroupIDOnInvalidRequest *int64   `json:"fallback_group_id_on_invalid_request"`
+	ImagePrice1K                    *money.Amount `json:"image_price_1k"`
+	ImagePrice2K                    *money.Amount `json:"image_price_2k"`
+	ImagePrice4K                    *money.Amount `json:"image_price_4k"`
+	SoraImagePrice360               *money.Amount `json:"sora_image_price_360"`
+	SoraImagePrice540               *money.Amount `json:"sora_image_price_540"`
+	SoraVideoPricePerRequest        *money.Amount `json:"sora_video_price_per_request"`
+	SoraVideoPricePerRequestHD      *money.Amount `json:"sora_video_price_per_request_hd"`
+	AudioPricePerMinute             *money.Amount `json:"audio_price_per_minute"`
+	AudioSpeechPricePer1MChars      *money.Amount `json:"audio_speech_price_per_1m_chars"`
+	ClaudeCodeOnly                  *bool         `json:"claude_code_only"`
+	FallbackGroupID                 *int64        `json:"fallback_group_id"`
+	FallbackGroupIDOnInvalidRequest *int64        `json:"fallback_group_id_on_invalid_request"`
 	// 模型路由配置（仅 anthropic 平台使用）
 	ModelRouting        map[string][]int64 `json:"model_routing"`
 	ModelRoutingEnabled *bool              `json:"model_routing_enabled"`
@@ -195,6 +200,8 @@ func (h *GroupHandler) Create(c *gin.Context) {
 		SoraImagePrice540:               req.SoraImagePrice540,
 		SoraVideoPricePerRequest:        req.SoraVideoPricePerRequest,
 		SoraVideoPricePerRequestHD:      req.SoraVideoPricePerRequestHD,
+		AudioPricePerMinute:             req.AudioPricePerMinute,
+		AudioSpeechPricePer1MChars:      req.AudioSpeechPricePer1MChars,
 		ClaudeCodeOnly:                  req.ClaudeCodeOnly,
 		FallbackGroupID:                 req.FallbackGroupID,
 		FallbackGroupIDOnInvalidRequest: req.FallbackGroupIDOnInvalidRequest,
@@ -246,6 +253,8 @@ func (h *GroupHandler) Update(c *gin.Context) {
 		SoraImagePrice540:               req.SoraImagePrice540,
 		SoraVideoPricePerRequest:        req.SoraVideoPricePerRequest,
 		SoraVideoPricePerRequestHD:      req.SoraVideoPricePerRequestHD,
+		AudioPricePerMinute:             req.AudioPricePerMinute,
+		AudioSpeechPricePer1MChars:      req.AudioSpeechPricePer1MChars,
 		ClaudeCodeOnly:                  req.ClaudeCodeOnly,
 		FallbackGroupID:                 req.FallbackGroupID,
 		FallbackGroupIDOnInvalidRequest: req.FallbackGroupIDOnInvalidRequest,
@@ -23,6 +23,7 @@ var validOpsAlertMetricTypes = []string{
 	"cpu_usage_percent",
 	"memory_usage_percent",
 	"concurrency_queue_depth",
+	"balance_ledger_mismatch_count",
 }
 
 var validOpsAlertMetricTypeSet = func() map[string]struct{} {
@@ -0,0 +1,195 @@
+package admin
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Wei-Shaw/sub2api/internal/pkg/response"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// ListNotificationChannels returns all ops notification channels (secrets redacted).
+// GET /api/v1/admin/ops/notification-channels
+func (h *OpsHandler) ListNotificationChannels(c *gin.Context) {
+	if h.opsService == nil {
+		response.Error(c, http.StatusServiceUnavailable, "Ops service not available")
+		return
+	}
+	if err := h.opsService.RequireMonitoringEnabled(c.Request.Context()); err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+
+	channels, err := h.opsService.ListNotificationChannels(c.Request.Context())
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, channels)
+}
+
+// CreateNotificationChannel creates an ops notification channel.
+// POST /api/v1/admin/ops/notification-channels
+func (h *OpsHandler) CreateNotificationChannel(c *gin.Context) {
+	if h.opsService == nil {
+		response.Error(c, http.StatusServiceUnavailable, "Ops service not available")
+		return
+	}
+	if err := h.opsService.RequireMonitoringEnabled(c.Request.Context()); err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+
+	var ch service.OpsNotificationChannel
+	if err := c.ShouldBindJSON(&ch); err != nil {
+		response.BadRequest(c, "Invalid request body")
+		return
+	}
+	ch.ID = 0
+
+	created, err := h.opsService.CreateNotificationChannel(c.Request.Context(), &ch)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, created)
+}
+
+// UpdateNotificationChannel updates an ops notification channel. An empty secret keeps the stored one.
+// PUT /api/v1/admin/ops/notification-channels/:id
+func (h *OpsHandler) UpdateNotificationChannel(c *gin.Context) {
+	if h.opsService == nil {
+		response.Error(c, http.StatusServiceUnavailable, "Ops service not available")
+		return
+	}
+	if err := h.opsService.RequireMonitoringEnabled(c.Request.Context()); err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		response.BadRequest(c, "Invalid channel ID")
+		return
+	}
+
+	var ch service.OpsNotificationChannel
+	if err := c.ShouldBindJSON(&ch); err != nil {
+		response.BadRequest(c, "Invalid request body")
+		return
+	}
+	ch.ID = id
+
+	updated, err := h.opsService.UpdateNotificationChannel(c.Request.Context(), &ch)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, updated)
+}
+
+// DeleteNotificationChannel deletes an ops notification channel.
+// DELETE /api/v1/admin/ops/notification-channels/:id
+func (h *OpsHandler) DeleteNotificationChannel(c *gin.Context) {
+	if h.opsService == nil {
+		response.Error(c, http.StatusServiceUnavailable, "Ops service not available")
+		return
+	}
+	if err := h.opsService.RequireMonitoringEnabled(c.Request.Context()); err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		response.BadRequest(c, "Invalid channel ID")
+		return
+	}
+
+	if err := h.opsService.DeleteNotificationChannel(c.Request.Context(), id); err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, gin.H{"deleted": true})
+}
+
+// TestNotificationChannel sends a test message through the channel and returns the delivery result.
+// POST /api/v1/admin/ops/notification-channels/:id/test
+func (h *OpsHandler) TestNotificationChannel(c *gin.Context) {
+	if h.opsService == nil {
+		response.Error(c, http.StatusServiceUnavailable, "Ops service not available")
+		return
+	}
+	if err := h.opsService.RequireMonitoringEnabled(c.Request.Context()); err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		response.BadRequest(c, "Invalid channel ID")
+		return
+	}
+
+	delivery, err := h.opsService.TestNotificationChannel(c.Request.Context(), id)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, delivery)
+}
+
+// ListNotificationDeliveries lists recent notification delivery logs.
+// GET /api/v1/admin/ops/notification-deliveries
+func (h *OpsHandler) ListNotificationDeliveries(c *gin.Context) {
+	if h.opsService == nil {
+		response.Error(c, http.StatusServiceUnavailable, "Ops service not available")
+		return
+	}
+	if err := h.opsService.RequireMonitoringEnabled(c.Request.Context()); err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+
+	filter := &service.OpsNotificationDeliveryFilter{Limit: 50}
+	if raw := strings.TrimSpace(c.Query("limit")); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n <= 0 {
+			response.BadRequest(c, "Invalid limit")
+			return
+		}
+		filter.Limit = n
+	}
+	if raw := strings.TrimSpace(c.Query("channel_id")); raw != "" {
+		n, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || n <= 0 {
+			response.BadRequest(c, "Invalid channel_id")
+			return
+		}
+		filter.ChannelID = &n
+	}
+	if raw := strings.TrimSpace(c.Query("event_id")); raw != "" {
+		n, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || n <= 0 {
+			response.BadRequest(c, "Invalid event_id")
+			return
+		}
+		filter.EventID = &n
+	}
+	switch status := strings.TrimSpace(c.Query("status")); status {
+	case "", service.OpsNotificationDeliverySuccess, service.OpsNotificationDeliveryFailed:
+		filter.Status = status
+	default:
+		response.BadRequest(c, "Invalid status")
+		return
+	}
+
+	deliveries, err := h.opsService.ListNotificationDeliveries(c.Request.Context(), filter)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, deliveries)
+}
@@ -0,0 +1,330 @@
+package admin
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/handler/dto"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/response"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/timezone"
+	middleware2 "github.com/Wei-Shaw/sub2api/internal/server/middleware"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PaymentHandler handles payment product, order and reconciliation management
+type PaymentHandler struct {
+	paymentService *service.PaymentService
+}
+
+// NewPaymentHandler creates a new admin payment handler
+func NewPaymentHandler(paymentService *service.PaymentService) *PaymentHandler {
+	return &PaymentHandler{paymentService: paymentService}
+}
+
+// PaymentProductRequest represents create/update payment product request
+type PaymentProductRequest struct {
+	Name          string  `json:"name" binding:"required,max=100"`
+	Description   string  `json:"description"`
+	Kind          string  `json:"kind" binding:"required,oneof=balance subscription"`
+	PriceCents    int64   `json:"price_cents" binding:"required,min=1"`
+	Currency      string  `json:"currency"`
+	BalanceAmount float64 `json:"balance_amount" binding:"min=0"`
+	GroupID       *int64  `json:"group_id"`
+	ValidityDays  int     `json:"validity_days" binding:"min=0"`
+	Enabled       bool    `json:"enabled"`
+	SortOrder     int     `json:"sort_order"`
+}
+
+// RefundPaymentOrderRequest represents refund order request
+type RefundPaymentOrderRequest struct {
+	AmountCents     int64  `json:"amount_cents" binding:"min=0"` // 0 = full refund
+	Reason          string `json:"reason"`
+	ReclaimBenefits bool   `json:"reclaim_benefits"`
+	Offline         bool   `json:"offline"` // already refunded outside the provider API
+}
+
+func (r *PaymentProductRequest) toProduct(id int64) *service.PaymentProduct {
+	return &service.PaymentProduct{
+		ID:            id,
+		Name:          r.Name,
+		Description:   r.Description,
+		Kind:          r.Kind,
+		PriceCents:    r.PriceCents,
+		Currency:      r.Currency,
+		BalanceAmount: money.FromFloat(r.BalanceAmount),
+		GroupID:       r.GroupID,
+		ValidityDays:  r.ValidityDays,
+		Enabled:       r.Enabled,
+		SortOrder:     r.SortOrder,
+	}
+}
+
+// ListProducts lists all payment products
+// GET /api/v1/admin/payment/products
+func (h *PaymentHandler) ListProducts(c *gin.Context) {
+	products, err := h.paymentService.ListProducts(c.Request.Context(), false)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+
+	out := make([]dto.AdminPaymentProduct, 0, len(products))
+	for i := range products {
+		out = append(out, *dto.AdminPaymentProductFromService(&products[i]))
+	}
+	response.Success(c, gin.H{
+		"products":  out,
+		"providers": dto.PaymentProvidersFromService(h.paymentService.ListProviders()),
+	})
+}
+
+// CreateProduct creates a payment product
+// POST /api/v1/admin/payment/products
+func (h *PaymentHandler) CreateProduct(c *gin.Context) {
+	var req PaymentProductRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	product := req.toProduct(0)
+	if err := h.paymentService.CreateProduct(c.Request.Context(), product); err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, dto.AdminPaymentProductFromService(product))
+}
+
+// UpdateProduct replaces a payment product; existing orders keep their snapshot
+// PUT /api/v1/admin/payment/products/:id
+func (h *PaymentHandler) UpdateProduct(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "Invalid product ID")
+		return
+	}
+
+	var req PaymentProductRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	if err := h.paymentService.UpdateProduct(c.Request.Context(), req.toProduct(id)); err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	product, err := h.paymentService.GetProduct(c.Request.Context(), id)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, dto.AdminPaymentProductFromService(product))
+}
+
+// DeleteProduct deletes a payment product
+// DELETE /api/v1/admin/payment/products/:id
+func (h *PaymentHandler) DeleteProduct(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "Invalid product ID")
+		return
+	}
+
+	if err := h.paymentService.DeleteProduct(c.Request.Context(), id); err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, gin.H{"message": "Payment product deleted successfully"})
+}
+
+// ListOrders lists payment orders
+// GET /api/v1/admin/payment/orders
+// Query params:
+//   - user_id: exact match
+//   - status: pending, paid, fulfilled, closed, refunded
+//   - provider: epay, alipay, wechat, stripe
+//   - search: order_no or provider_trade_no (exact match)
+//   - start_date / end_date: YYYY-MM-DD (created date) in the given timezone
+func (h *PaymentHandler) ListOrders(c *gin.Context) {
+	page, pageSize := response.ParsePagination(c)
+
+	filter := service.PaymentOrderFilter{
+		Status:   strings.TrimSpace(c.Query("status")),
+		Provider: strings.TrimSpace(c.Query("provider")),
+		Search:   strings.TrimSpace(c.Query("search")),
+	}
+	if userIDStr := c.Query("user_id"); userIDStr != "" {
+		id, err := strconv.ParseInt(userIDStr, 10, 64)
+		if err != nil || id <= 0 {
+			response.BadRequest(c, "Invalid user_id")
+			return
+		}
+		filter.UserID = id
+	}
+
+	userTZ := c.Query("timezone")
+	if startDateStr := c.Query("start_date"); startDateStr != "" {
+		t, err := timezone.ParseInUserLocation("2006-01-02", startDateStr, userTZ)
+		if err != nil {
+			response.BadRequest(c, "Invalid start_date format, use YYYY-MM-DD")
+			return
+		}
+		filter.StartTime = &t
+	}
+	if endDateStr := c.Query("end_date"); endDateStr != "" {
+		t, err := timezone.ParseInUserLocation("2006-01-02", endDateStr, userTZ)
+		if err != nil {
+			response.BadRequest(c, "Invalid end_date format, use YYYY-MM-DD")
+			return
+		}
+		t = t.Add(24 * time.Hour)
+		filter.EndTime = &t
+	}
+
+	params := pagination.PaginationParams{Page: page, PageSize: pageSize}
+	orders, result, err := h.paymentService.ListOrders(c.Request.Context(), params, filter)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+
+	out := make([]dto.AdminPaymentOrder, 0, len(orders))
+	for i := range orders {
+		out = append(out, *dto.AdminPaymentOrderFromService(&orders[i]))
+	}
+	response.Paginated(c, out, result.Total, page, pageSize)
+}
+
+// GetOrder gets a payment order
+// GET /api/v1/admin/payment/orders/:id
+func (h *PaymentHandler) GetOrder(c *gin.Context) {
+	id, ok := parsePaymentOrderID(c)
+	if !ok {
+		return
+	}
+
+	order, err := h.paymentService.GetOrder(c.Request.Context(), id)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, dto.AdminPaymentOrderFromService(order))
+}
+
+// CloseOrder closes an unpaid order
+// POST /api/v1/admin/payment/orders/:id/close
+func (h *PaymentHandler) CloseOrder(c *gin.Context) {
+	id, ok := parsePaymentOrderID(c)
+	if !ok {
+		return
+	}
+
+	order, err := h.paymentService.CloseOrder(c.Request.Context(), id)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, dto.AdminPaymentOrderFromService(order))
+}
+
+// RetryFulfill retries granting benefits for a paid order whose fulfillment failed
+// POST /api/v1/admin/payment/orders/:id/fulfill
+func (h *PaymentHandler) RetryFulfill(c *gin.Context) {
+	id, ok := parsePaymentOrderID(c)
+	if !ok {
+		return
+	}
+
+	order, err := h.paymentService.RetryFulfill(c.Request.Context(), id)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, dto.AdminPaymentOrderFromService(order))
+}
+
+// SyncOrder queries the provider for the payment result of an unpaid order
+// POST /api/v1/admin/payment/orders/:id/sync
+func (h *PaymentHandler) SyncOrder(c *gin.Context) {
+	id, ok := parsePaymentOrderID(c)
+	if !ok {
+		return
+	}
+
+	order, err := h.paymentService.SyncOrder(c.Request.Context(), id)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, dto.AdminPaymentOrderFromService(order))
+}
+
+// RefundOrder refunds a paid order, optionally reclaiming the granted benefits
+// POST /api/v1/admin/payment/orders/:id/refund
+func (h *PaymentHandler) RefundOrder(c *gin.Context) {
+	id, ok := parsePaymentOrderID(c)
+	if !ok {
+		return
+	}
+
+	var req RefundPaymentOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request: "+err.Error())
+		return
+	}
+	var operatorID int64
+	if subject, ok := middleware2.GetAuthSubjectFromContext(c); ok {
+		operatorID = subject.UserID
+	}
+
+	payload := struct {
+		ID int64 `json:"id"`
+		RefundPaymentOrderRequest
+	}{ID: id, RefundPaymentOrderRequest: req}
+	executeAdminIdempotentJSON(c, "admin.payment.orders.refund", payload, service.DefaultWriteIdempotencyTTL(), func(ctx context.Context) (any, error) {
+		order, err := h.paymentService.RefundOrder(ctx, id, &service.PaymentRefundInput{
+			AmountCents:     req.AmountCents,
+			Reason:          req.Reason,
+			ReclaimBenefits: req.ReclaimBenefits,
+			Offline:         req.Offline,
+		}, operatorID)
+		if err != nil {
+			return nil, err
+		}
+		return dto.AdminPaymentOrderFromService(order), nil
+	})
+}
+
+// Reconciliation returns paid/refunded totals per day, provider and currency
+// GET /api/v1/admin/payment/reconciliation
+// Query params: start_date, end_date (YYYY-MM-DD, default last 7 days), timezone, provider
+func (h *PaymentHandler) Reconciliation(c *gin.Context) {
+	startTime, endTime := parseTimeRange(c)
+	report, err := h.paymentService.Reconciliation(c.Request.Context(), service.PaymentReconciliationFilter{
+		StartTime: startTime,
+		EndTime:   endTime,
+		Provider:  strings.TrimSpace(c.Query("provider")),
+		Timezone:  c.Query("timezone"),
+	})
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, dto.PaymentReconciliationFromService(report))
+}
+
+func parsePaymentOrderID(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "Invalid order ID")
+		return 0, false
+	}
+	return id, true
+}
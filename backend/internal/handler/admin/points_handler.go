@@ -0,0 +1,233 @@
+package admin
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/Wei-Shaw/sub2api/internal/handler/dto"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/response"
+	middleware2 "github.com/Wei-Shaw/sub2api/internal/server/middleware"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PointsHandler handles points shop items, exchange records and points adjustments
+type PointsHandler struct {
+	pointsService *service.PointsService
+}
+
+// NewPointsHandler creates a new admin points handler
+func NewPointsHandler(pointsService *service.PointsService) *PointsHandler {
+	return &PointsHandler{pointsService: pointsService}
+}
+
+// PointsShopItemRequest represents create/update points shop item request
+type PointsShopItemRequest struct {
+	Name          string  `json:"name" binding:"required,max=100"`
+	Description   string  `json:"description"`
+	Kind          string  `json:"kind" binding:"required,oneof=balance subscription redeem_code"`
+	PointsCost    int64   `json:"points_cost" binding:"required,min=1"`
+	BalanceAmount float64 `json:"balance_amount" binding:"min=0"`
+	GroupID       *int64  `json:"group_id"`
+	ValidityDays  int     `json:"validity_days" binding:"min=0"`
+	Stock         int     `json:"stock" binding:"min=-1"` // -1 = unlimited
+	PerUserLimit  int     `json:"per_user_limit" binding:"min=0"`
+	Enabled       bool    `json:"enabled"`
+	SortOrder     int     `json:"sort_order"`
+}
+
+func (r *PointsShopItemRequest) toItem(id int64) *service.PointsShopItem {
+	return &service.PointsShopItem{
+		ID:            id,
+		Name:          r.Name,
+		Description:   r.Description,
+		Kind:          r.Kind,
+		PointsCost:    r.PointsCost,
+		BalanceAmount: money.FromFloat(r.BalanceAmount),
+		GroupID:       r.GroupID,
+		ValidityDays:  r.ValidityDays,
+		Stock:         r.Stock,
+		PerUserLimit:  r.PerUserLimit,
+		Enabled:       r.Enabled,
+		SortOrder:     r.SortOrder,
+	}
+}
+
+// AdjustPointsRequest represents the points adjustment request; a negative delta deducts points
+type AdjustPointsRequest struct {
+	UserID int64  `json:"user_id" binding:"required,min=1"`
+	Delta  int64  `json:"delta" binding:"required"`
+	Notes  string `json:"notes"`
+}
+
+// ListItems lists all points shop items
+// GET /api/v1/admin/points/items
+func (h *PointsHandler) ListItems(c *gin.Context) {
+	items, err := h.pointsService.ListItems(c.Request.Context(), false)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+
+	out := make([]dto.AdminPointsShopItem, 0, len(items))
+	for i := range items {
+		out = append(out, *dto.AdminPointsShopItemFromService(&items[i]))
+	}
+	response.Success(c, out)
+}
+
+// CreateItem creates a points shop item
+// POST /api/v1/admin/points/items
+func (h *PointsHandler) CreateItem(c *gin.Context) {
+	var req PointsShopItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	item := req.toItem(0)
+	if err := h.pointsService.CreateItem(c.Request.Context(), item); err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, dto.AdminPointsShopItemFromService(item))
+}
+
+// UpdateItem replaces a points shop item; past exchanges keep their snapshot
+// PUT /api/v1/admin/points/items/:id
+func (h *PointsHandler) UpdateItem(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "Invalid item ID")
+		return
+	}
+
+	var req PointsShopItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	if err := h.pointsService.UpdateItem(c.Request.Context(), req.toItem(id)); err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	item, err := h.pointsService.GetItem(c.Request.Context(), id)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, dto.AdminPointsShopItemFromService(item))
+}
+
+// DeleteItem deletes a points shop item
+// DELETE /api/v1/admin/points/items/:id
+func (h *PointsHandler) DeleteItem(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "Invalid item ID")
+		return
+	}
+
+	if err := h.pointsService.DeleteItem(c.Request.Context(), id); err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, gin.H{"message": "Points shop item deleted successfully"})
+}
+
+// ListExchanges lists points exchange records
+// GET /api/v1/admin/points/exchanges
+// Query params:
+//   - user_id / item_id: exact match
+func (h *PointsHandler) ListExchanges(c *gin.Context) {
+	page, pageSize := response.ParsePagination(c)
+
+	var filter service.PointsExchangeFilter
+	var ok bool
+	if filter.UserID, ok = parseOptionalIDQuery(c, "user_id"); !ok {
+		return
+	}
+	if filter.ItemID, ok = parseOptionalIDQuery(c, "item_id"); !ok {
+		return
+	}
+
+	params := pagination.PaginationParams{Page: page, PageSize: pageSize}
+	exchanges, result, err := h.pointsService.ListExchanges(c.Request.Context(), params, filter)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+
+	out := make([]dto.AdminPointsExchange, 0, len(exchanges))
+	for i := range exchanges {
+		out = append(out, *dto.AdminPointsExchangeFromService(&exchanges[i]))
+	}
+	response.Paginated(c, out, result.Total, page, pageSize)
+}
+
+// GetUserPoints returns a user's points account
+// GET /api/v1/admin/points/users/:id
+func (h *PointsHandler) GetUserPoints(c *gin.Context) {
+	userID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "Invalid user ID")
+		return
+	}
+
+	account, err := h.pointsService.GetAccount(c.Request.Context(), userID)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, dto.PointsAccountFromService(account))
+}
+
+// ListUserLedger lists a user's points history
+// GET /api/v1/admin/points/users/:id/ledger
+func (h *PointsHandler) ListUserLedger(c *gin.Context) {
+	userID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "Invalid user ID")
+		return
+	}
+
+	page, pageSize := response.ParsePagination(c)
+	params := pagination.PaginationParams{Page: page, PageSize: pageSize}
+	entries, result, err := h.pointsService.ListLedger(c.Request.Context(), userID, params)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+
+	out := make([]dto.AdminPointsLedgerEntry, 0, len(entries))
+	for i := range entries {
+		out = append(out, *dto.AdminPointsLedgerEntryFromService(&entries[i]))
+	}
+	response.Paginated(c, out, result.Total, page, pageSize)
+}
+
+// Adjust adds or deducts a user's points; deductions beyond the balance are rejected
+// POST /api/v1/admin/points/adjust
+func (h *PointsHandler) Adjust(c *gin.Context) {
+	var req AdjustPointsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request: "+err.Error())
+		return
+	}
+	var operatorID int64
+	if subject, ok := middleware2.GetAuthSubjectFromContext(c); ok {
+		operatorID = subject.UserID
+	}
+
+	executeAdminIdempotentJSON(c, "admin.points.adjust", req, service.DefaultWriteIdempotencyTTL(), func(ctx context.Context) (any, error) {
+		entry, err := h.pointsService.AdminAdjust(ctx, req.UserID, req.Delta, req.Notes, operatorID)
+		if err != nil {
+			return nil, err
+		}
+		return dto.AdminPointsLedgerEntryFromService(entry), nil
+	})
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/Wei-Shaw/sub2api/internal/handler/dto"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
 	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
 	"github.com/Wei-Shaw/sub2api/internal/pkg/response"
 	"github.com/Wei-Shaw/sub2api/internal/service"
@@ -27,21 +28,41 @@ func NewPromoHandler(promoService *service.PromoService) *PromoHandler {
 
 // CreatePromoCodeRequest represents create promo code request
 type CreatePromoCodeRequest struct {
-	Code        string  `json:"code"`                                  // 可选，为空则自动生成
-	BonusAmount float64 `json:"bonus_amount" binding:"required,min=0"` // 赠送余额
-	MaxUses     int     `json:"max_uses" binding:"min=0"`              // 最大使用次数，0=无限
-	ExpiresAt   *int64  `json:"expires_at"`                            // 过期时间戳（秒）
-	Notes       string  `json:"notes"`                                 // 备注
+	Code        string       `json:"code"`                                  // 可选，为空则自动生成
+	BonusAmount money.Amount `json:"bonus_amount" binding:"required,min=0"` // 赠送余额
+	MaxUses     int          `json:"max_uses" binding:"min=0"`              // 最大使用次数，0=无限
+	ExpiresAt   *int64       `json:"expires_at"`                            // 过期时间戳（秒）
+	Notes       string       `json:"notes"`                                 // 备注
+
+	TopupBonusPercent   float64           `json:"topup_bonus_percent" binding:"min=0,max=1000"` // 首次充值赠送比例（百分比）
+	TopupBonusMax       money.Amount      `json:"topup_bonus_max" binding:"min=0"`              // 充值赠送上限，0=不封顶
+	MinTopupAmount      money.Amount      `json:"min_topup_amount" binding:"min=0"`             // 触发充值赠送的最低充值金额
+	PerUserLimit        *int              `json:"per_user_limit" binding:"omitempty,min=0"`     // 每用户使用次数，默认 1，0=无限
+	AllowedEmailDomains []string          `json:"allowed_email_domains"`                        // 允许的邮箱域名
+	RequiredAttributes  map[string]string `json:"required_attributes"`                          // 要求的用户属性取值
+	Stackable           bool              `json:"stackable"`                                    // 是否可叠加
+	TrialGroupID        *int64            `json:"trial_group_id"`                               // 试用订阅分组
+	TrialDays           int               `json:"trial_days" binding:"min=0"`                   // 试用天数
 }
 
 // UpdatePromoCodeRequest represents update promo code request
 type UpdatePromoCodeRequest struct {
-	Code        *string  `json:"code"`
-	BonusAmount *float64 `json:"bonus_amount" binding:"omitempty,min=0"`
-	MaxUses     *int     `json:"max_uses" binding:"omitempty,min=0"`
-	Status      *string  `json:"status" binding:"omitempty,oneof=active disabled"`
-	ExpiresAt   *int64   `json:"expires_at"`
-	Notes       *string  `json:"notes"`
+	Code        *string       `json:"code"`
+	BonusAmount *money.Amount `json:"bonus_amount" binding:"omitempty,min=0"`
+	MaxUses     *int          `json:"max_uses" binding:"omitempty,min=0"`
+	Status      *string       `json:"status" binding:"omitempty,oneof=active disabled"`
+	ExpiresAt   *int64        `json:"expires_at"`
+	Notes       *string       `json:"notes"`
+
+	TopupBonusPercent   *float64           `json:"topup_bonus_percent" binding:"omitempty,min=0,max=1000"`
+	TopupBonusMax       *money.Amount      `json:"topup_bonus_max" binding:"omitempty,min=0"`
+	MinTopupAmount      *money.Amount      `json:"min_topup_amount" binding:"omitempty,min=0"`
+	PerUserLimit        *int               `json:"per_user_limit" binding:"omitempty,min=0"`
+	AllowedEmailDomains *[]string          `json:"allowed_email_domains"`
+	RequiredAttributes  *map[string]string `json:"required_attributes"`
+	Stackable           *bool              `json:"stackable"`
+	TrialGroupID        *int64             `json:"trial_group_id"` // 0 表示清除
+	TrialDays           *int               `json:"trial_days" binding:"omitempty,min=0"`
 }
 
 // List handles listing all promo codes with pagination
@@ -104,6 +125,19 @@ func (h *PromoHandler) Create(c *gin.Context) {
 		BonusAmount: req.BonusAmount,
 		MaxUses:     req.MaxUses,
 		Notes:       req.Notes,
+
+		TopupBonusPercent:   req.TopupBonusPercent,
+		TopupBonusMax:       req.TopupBonusMax,
+		MinTopupAmount:      req.MinTopupAmount,
+		PerUserLimit:        1,
+		AllowedEmailDomains: req.AllowedEmailDomains,
+		RequiredAttributes:  req.RequiredAttributes,
+		Stackable:           req.Stackable,
+		TrialGroupID:        req.TrialGroupID,
+		TrialDays:           req.TrialDays,
+	}
+	if req.PerUserLimit != nil {
+		input.PerUserLimit = *req.PerUserLimit
 	}
 
 	if req.ExpiresAt != nil {
@@ -141,6 +175,16 @@ func (h *PromoHandler) Update(c *gin.Context) {
 		MaxUses:     req.MaxUses,
 		Status:      req.Status,
 		Notes:       req.Notes,
+
+		TopupBonusPercent:   req.TopupBonusPercent,
+		TopupBonusMax:       req.TopupBonusMax,
+		MinTopupAmount:      req.MinTopupAmount,
+		PerUserLimit:        req.PerUserLimit,
+		AllowedEmailDomains: req.AllowedEmailDomains,
+		RequiredAttributes:  req.RequiredAttributes,
+		Stackable:           req.Stackable,
+		TrialGroupID:        req.TrialGroupID,
+		TrialDays:           req.TrialDays,
 	}
 
 	if req.ExpiresAt != nil {
@@ -11,6 +11,7 @@ import (
 
 	"github.com/Wei-Shaw/sub2api/internal/handler/dto"
 	infraerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
 	"github.com/Wei-Shaw/sub2api/internal/pkg/response"
 	"github.com/Wei-Shaw/sub2api/internal/service"
 
@@ -33,20 +34,20 @@ func NewRedeemHandler(adminService service.AdminService, redeemService *service.
 
 // GenerateRedeemCodesRequest represents generate redeem codes request
 type GenerateRedeemCodesRequest struct {
-	Count        int     `json:"count" binding:"required,min=1,max=100"`
-	Type         string  `json:"type" binding:"required,oneof=balance concurrency subscription invitation"`
-	Value        float64 `json:"value" binding:"min=0"`
-	GroupID      *int64  `json:"group_id"`                                    // 订阅类型必填
-	ValidityDays int     `json:"validity_days" binding:"omitempty,max=36500"` // 订阅类型使用，默认30天，最大100年
+	Count        int          `json:"count" binding:"required,min=1,max=100"`
+	Type         string       `json:"type" binding:"required,oneof=balance concurrency subscription invitation"`
+	Value        money.Amount `json:"value" binding:"min=0"`
+	GroupID      *int64       `json:"group_id"`                                    // 订阅类型必填
+	ValidityDays int          `json:"validity_days" binding:"omitempty,max=36500"` // 订阅类型使用，默认30天，最大100年
 }
 
 // CreateAndRedeemCodeRequest represents creating a fixed code and redeeming it for a target user.
 type CreateAndRedeemCodeRequest struct {
-	Code   string  `json:"code" binding:"required,min=3,max=128"`
-	Type   string  `json:"type" binding:"required,oneof=balance concurrency subscription invitation"`
-	Value  float64 `json:"value" binding:"required,gt=0"`
-	UserID int64   `json:"user_id" binding:"required,gt=0"`
-	Notes  string  `json:"notes"`
+	Code   string       `json:"code" binding:"required,min=3,max=128"`
+	Type   string       `json:"type" binding:"required,oneof=balance concurrency subscription invitation"`
+	Value  money.Amount `json:"value" binding:"required,gt=0"`
+	UserID int64        `json:"user_id" binding:"required,gt=0"`
+	Notes  string       `json:"notes"`
 }
 
 // List handles listing all redeem codes with pagination
@@ -147,12 +148,14 @@ func (h *RedeemHandler) CreateAndRedeem(c *gin.Context) {
 			return nil, err
 		}
 
+		// Codes created here are top-ups from the external payment integration; tag them as payment so they count as paid recharges.
 		createErr := h.redeemService.CreateCode(ctx, &service.RedeemCode{
-			Code:   req.Code,
-			Type:   req.Type,
-			Value:  req.Value,
-			Status: service.StatusUnused,
-			Notes:  req.Notes,
+			Code:     req.Code,
+			Type:     req.Type,
+			Value:    req.Value,
+			Status:   service.StatusUnused,
+			Notes:    req.Notes,
+			Category: service.PaymentRedeemCategory,
 		})
 		if createErr != nil {
 			// Unique code race: if code now exists, use idempotent semantics by used_by.
@@ -332,7 +335,7 @@ func (h *RedeemHandler) Export(c *gin.Context) {
 			fmt.Sprintf("%d", code.ID),
 			code.Code,
 			code.Type,
-			fmt.Sprintf("%.2f", code.Value),
+			fmt.Sprintf("%.2f", code.Value.Float64()),
 			code.Status,
 			usedBy,
 			usedByEmail,
@@ -0,0 +1,194 @@
+package admin
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/Wei-Shaw/sub2api/internal/handler/dto"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/response"
+	middleware2 "github.com/Wei-Shaw/sub2api/internal/server/middleware"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ReferralHandler handles referral relation review, commissions and withdrawals
+type ReferralHandler struct {
+	referralService *service.ReferralService
+}
+
+// NewReferralHandler creates a new admin referral handler
+func NewReferralHandler(referralService *service.ReferralService) *ReferralHandler {
+	return &ReferralHandler{referralService: referralService}
+}
+
+// UpdateReferralStatusRequest represents the relation review request
+type UpdateReferralStatusRequest struct {
+	Status string `json:"status" binding:"required,oneof=active flagged revoked"`
+}
+
+// ProcessReferralWithdrawalRequest represents the withdrawal review request
+type ProcessReferralWithdrawalRequest struct {
+	Approve bool   `json:"approve"`
+	Notes   string `json:"notes"`
+}
+
+// ListRelations lists referral relations
+// GET /api/v1/admin/referrals
+// Query params:
+//   - referrer_id / referee_id: exact match
+//   - status: active, flagged, revoked
+func (h *ReferralHandler) ListRelations(c *gin.Context) {
+	page, pageSize := response.ParsePagination(c)
+
+	filter := service.ReferralRelationFilter{Status: strings.TrimSpace(c.Query("status"))}
+	var ok bool
+	if filter.ReferrerID, ok = parseOptionalIDQuery(c, "referrer_id"); !ok {
+		return
+	}
+	if filter.RefereeID, ok = parseOptionalIDQuery(c, "referee_id"); !ok {
+		return
+	}
+
+	params := pagination.PaginationParams{Page: page, PageSize: pageSize}
+	relations, result, err := h.referralService.ListRelations(c.Request.Context(), params, filter)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+
+	out := make([]dto.AdminReferralRelation, 0, len(relations))
+	for i := range relations {
+		out = append(out, *dto.AdminReferralRelationFromService(&relations[i]))
+	}
+	response.Paginated(c, out, result.Total, page, pageSize)
+}
+
+// UpdateRelationStatus approves, flags or revokes a referral relation.
+// Approving a flagged relation lets the next settlement pay what it has accrued.
+// PUT /api/v1/admin/referrals/:id/status
+func (h *ReferralHandler) UpdateRelationStatus(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "Invalid referral ID")
+		return
+	}
+
+	var req UpdateReferralStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	rel, err := h.referralService.UpdateRelationStatus(c.Request.Context(), id, req.Status)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, dto.AdminReferralRelationFromService(rel))
+}
+
+// ListCommissions lists commission entries
+// GET /api/v1/admin/referrals/commissions
+// Query params:
+//   - referrer_id: exact match
+//   - kind: bonus, recharge, usage
+func (h *ReferralHandler) ListCommissions(c *gin.Context) {
+	page, pageSize := response.ParsePagination(c)
+
+	filter := service.ReferralCommissionFilter{Kind: strings.TrimSpace(c.Query("kind"))}
+	var ok bool
+	if filter.ReferrerID, ok = parseOptionalIDQuery(c, "referrer_id"); !ok {
+		return
+	}
+
+	params := pagination.PaginationParams{Page: page, PageSize: pageSize}
+	commissions, result, err := h.referralService.ListCommissions(c.Request.Context(), params, filter)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+
+	out := make([]dto.AdminReferralCommission, 0, len(commissions))
+	for i := range commissions {
+		out = append(out, *dto.AdminReferralCommissionFromService(&commissions[i]))
+	}
+	response.Paginated(c, out, result.Total, page, pageSize)
+}
+
+// ListWithdrawals lists commission wallet withdrawals
+// GET /api/v1/admin/referrals/withdrawals
+// Query params:
+//   - user_id: exact match
+//   - status: pending, completed, rejected
+func (h *ReferralHandler) ListWithdrawals(c *gin.Context) {
+	page, pageSize := response.ParsePagination(c)
+
+	filter := service.ReferralWithdrawalFilter{Status: strings.TrimSpace(c.Query("status"))}
+	var ok bool
+	if filter.UserID, ok = parseOptionalIDQuery(c, "user_id"); !ok {
+		return
+	}
+
+	params := pagination.PaginationParams{Page: page, PageSize: pageSize}
+	withdrawals, result, err := h.referralService.ListWithdrawals(c.Request.Context(), params, filter)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+
+	out := make([]dto.AdminReferralWithdrawal, 0, len(withdrawals))
+	for i := range withdrawals {
+		out = append(out, *dto.AdminReferralWithdrawalFromService(&withdrawals[i]))
+	}
+	response.Paginated(c, out, result.Total, page, pageSize)
+}
+
+// ProcessWithdrawal approves (paid out) or rejects (refunded to the wallet) a pending manual withdrawal
+// POST /api/v1/admin/referrals/withdrawals/:id/process
+func (h *ReferralHandler) ProcessWithdrawal(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "Invalid withdrawal ID")
+		return
+	}
+
+	var req ProcessReferralWithdrawalRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request: "+err.Error())
+		return
+	}
+	var operatorID int64
+	if subject, ok := middleware2.GetAuthSubjectFromContext(c); ok {
+		operatorID = subject.UserID
+	}
+
+	payload := struct {
+		ID int64 `json:"id"`
+		ProcessReferralWithdrawalRequest
+	}{ID: id, ProcessReferralWithdrawalRequest: req}
+	executeAdminIdempotentJSON(c, "admin.referrals.withdrawals.process", payload, service.DefaultWriteIdempotencyTTL(), func(ctx context.Context) (any, error) {
+		w, err := h.referralService.ProcessWithdrawal(ctx, id, req.Approve, req.Notes, operatorID)
+		if err != nil {
+			return nil, err
+		}
+		return dto.AdminReferralWithdrawalFromService(w), nil
+	})
+}
+
+// parseOptionalIDQuery parses an optional positive ID query param; on failure it
+// writes the error response and returns false.
+func parseOptionalIDQuery(c *gin.Context, key string) (int64, bool) {
+	raw := c.Query(key)
+	if raw == "" {
+		return 0, true
+	}
+	id, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil || id <= 0 {
+		response.BadRequest(c, "Invalid "+key)
+		return 0, false
+	}
+	return id, true
+}
@@ -0,0 +1,252 @@
+package admin
+
+import (
+	"strconv"
+
+	"github.com/Wei-Shaw/sub2api/internal/pkg/response"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SSOProviderHandler handles OIDC/OAuth2 single sign-on provider management
+type SSOProviderHandler struct {
+	ssoService *service.SSOService
+}
+
+// NewSSOProviderHandler creates a new handler
+func NewSSOProviderHandler(ssoService *service.SSOService) *SSOProviderHandler {
+	return &SSOProviderHandler{ssoService: ssoService}
+}
+
+// --- Request/Response DTOs ---
+
+// SSOProviderRequest represents create/update SSO provider request.
+// Updates replace the whole provider; an empty client_secret keeps the stored secret.
+type SSOProviderRequest struct {
+	Slug                string            `json:"slug" binding:"required,min=1,max=64"`
+	Name                string            `json:"name" binding:"required,min=1,max=100"`
+	Type                string            `json:"type"`
+	Enabled             bool              `json:"enabled"`
+	IssuerURL           string            `json:"issuer_url"`
+	AuthorizeURL        string            `json:"authorize_url"`
+	TokenURL            string            `json:"token_url"`
+	UserInfoURL         string            `json:"userinfo_url"`
+	JWKSURL             string            `json:"jwks_url"`
+	ClientID            string            `json:"client_id" binding:"required"`
+	ClientSecret        string            `json:"client_secret"`
+	Scopes              string            `json:"scopes"`
+	TokenAuthMethod     string            `json:"token_auth_method"`
+	UsePKCE             bool              `json:"use_pkce"`
+	RedirectURL         string            `json:"redirect_url"`
+	FrontendRedirectURL string            `json:"frontend_redirect_url"`
+	SubjectClaim        string            `json:"subject_claim"`
+	EmailClaim          string            `json:"email_claim"`
+	EmailVerifiedClaim  string            `json:"email_verified_claim"`
+	TrustEmail          bool              `json:"trust_email"`
+	UsernameClaim       string            `json:"username_claim"`
+	AttributeMappings   map[string]string `json:"attribute_mappings"`
+	AllowedEmailDomains []string          `json:"allowed_email_domains"`
+	AutoProvision       bool              `json:"auto_provision"`
+	DefaultGroupIDs     []int64           `json:"default_group_ids"`
+	LinkExistingByEmail bool              `json:"link_existing_by_email"`
+	EnforceSSO          bool              `json:"enforce_sso"`
+	DisplayOrder        int               `json:"display_order"`
+}
+
+// SSOProviderResponse represents SSO provider response (the client secret is never returned)
+type SSOProviderResponse struct {
+	ID                     int64             `json:"id"`
+	Slug                   string            `json:"slug"`
+	Name                   string            `json:"name"`
+	Type                   string            `json:"type"`
+	Enabled                bool              `json:"enabled"`
+	IssuerURL              string            `json:"issuer_url"`
+	AuthorizeURL           string            `json:"authorize_url"`
+	TokenURL               string            `json:"token_url"`
+	UserInfoURL            string            `json:"userinfo_url"`
+	JWKSURL                string            `json:"jwks_url"`
+	ClientID               string            `json:"client_id"`
+	ClientSecretConfigured bool              `json:"client_secret_configured"`
+	Scopes                 string            `json:"scopes"`
+	TokenAuthMethod        string            `json:"token_auth_method"`
+	UsePKCE                bool              `json:"use_pkce"`
+	RedirectURL            string            `json:"redirect_url"`
+	FrontendRedirectURL    string            `json:"frontend_redirect_url"`
+	SubjectClaim           string            `json:"subject_claim"`
+	EmailClaim             string            `json:"email_claim"`
+	EmailVerifiedClaim     string            `json:"email_verified_claim"`
+	TrustEmail             bool              `json:"trust_email"`
+	UsernameClaim          string            `json:"username_claim"`
+	AttributeMappings      map[string]string `json:"attribute_mappings"`
+	AllowedEmailDomains    []string          `json:"allowed_email_domains"`
+	AutoProvision          bool              `json:"auto_provision"`
+	DefaultGroupIDs        []int64           `json:"default_group_ids"`
+	LinkExistingByEmail    bool              `json:"link_existing_by_email"`
+	EnforceSSO             bool              `json:"enforce_sso"`
+	DisplayOrder           int               `json:"display_order"`
+	CreatedAt              string            `json:"created_at"`
+	UpdatedAt              string            `json:"updated_at"`
+}
+
+// --- Helpers ---
+
+func ssoProviderToResponse(p *service.SSOProvider) *SSOProviderResponse {
+	return &SSOProviderResponse{
+		ID:                     p.ID,
+		Slug:                   p.Slug,
+		Name:                   p.Name,
+		Type:                   p.Type,
+		Enabled:                p.Enabled,
+		IssuerURL:              p.IssuerURL,
+		AuthorizeURL:           p.AuthorizeURL,
+		TokenURL:               p.TokenURL,
+		UserInfoURL:            p.UserInfoURL,
+		JWKSURL:                p.JWKSURL,
+		ClientID:               p.ClientID,
+		ClientSecretConfigured: p.ClientSecretEncrypted != "",
+		Scopes:                 p.Scopes,
+		TokenAuthMethod:        p.TokenAuthMethod,
+		UsePKCE:                p.UsePKCE,
+		RedirectURL:            p.RedirectURL,
+		FrontendRedirectURL:    p.FrontendRedirectURL,
+		SubjectClaim:           p.SubjectClaim,
+		EmailClaim:             p.EmailClaim,
+		EmailVerifiedClaim:     p.EmailVerifiedClaim,
+		TrustEmail:             p.TrustEmail,
+		UsernameClaim:          p.UsernameClaim,
+		AttributeMappings:      p.AttributeMappings,
+		AllowedEmailDomains:    p.AllowedEmailDomains,
+		AutoProvision:          p.AutoProvision,
+		DefaultGroupIDs:        p.DefaultGroupIDs,
+		LinkExistingByEmail:    p.LinkExistingByEmail,
+		EnforceSSO:             p.EnforceSSO,
+		DisplayOrder:           p.DisplayOrder,
+		CreatedAt:              p.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		UpdatedAt:              p.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+}
+
+func (r *SSOProviderRequest) toInput() *service.SSOProviderInput {
+	return &service.SSOProviderInput{
+		Slug:                r.Slug,
+		Name:                r.Name,
+		Type:                r.Type,
+		Enabled:             r.Enabled,
+		IssuerURL:           r.IssuerURL,
+		AuthorizeURL:        r.AuthorizeURL,
+		TokenURL:            r.TokenURL,
+		UserInfoURL:         r.UserInfoURL,
+		JWKSURL:             r.JWKSURL,
+		ClientID:            r.ClientID,
+		ClientSecret:        r.ClientSecret,
+		Scopes:              r.Scopes,
+		TokenAuthMethod:     r.TokenAuthMethod,
+		UsePKCE:             r.UsePKCE,
+		RedirectURL:         r.RedirectURL,
+		FrontendRedirectURL: r.FrontendRedirectURL,
+		SubjectClaim:        r.SubjectClaim,
+		EmailClaim:          r.EmailClaim,
+		EmailVerifiedClaim:  r.EmailVerifiedClaim,
+		TrustEmail:          r.TrustEmail,
+		UsernameClaim:       r.UsernameClaim,
+		AttributeMappings:   r.AttributeMappings,
+		AllowedEmailDomains: r.AllowedEmailDomains,
+		AutoProvision:       r.AutoProvision,
+		DefaultGroupIDs:     r.DefaultGroupIDs,
+		LinkExistingByEmail: r.LinkExistingByEmail,
+		EnforceSSO:          r.EnforceSSO,
+		DisplayOrder:        r.DisplayOrder,
+	}
+}
+
+// --- Handlers ---
+
+// List lists all SSO providers
+// GET /admin/sso-providers
+func (h *SSOProviderHandler) List(c *gin.Context) {
+	providers, err := h.ssoService.ListProviders(c.Request.Context())
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+
+	out := make([]*SSOProviderResponse, 0, len(providers))
+	for i := range providers {
+		out = append(out, ssoProviderToResponse(&providers[i]))
+	}
+	response.Success(c, out)
+}
+
+// Get gets an SSO provider
+// GET /admin/sso-providers/:id
+func (h *SSOProviderHandler) Get(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "Invalid provider ID")
+		return
+	}
+
+	provider, err := h.ssoService.GetProvider(c.Request.Context(), id)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, ssoProviderToResponse(provider))
+}
+
+// Create creates an SSO provider
+// POST /admin/sso-providers
+func (h *SSOProviderHandler) Create(c *gin.Context) {
+	var req SSOProviderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	provider, err := h.ssoService.CreateProvider(c.Request.Context(), req.toInput())
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, ssoProviderToResponse(provider))
+}
+
+// Update replaces an SSO provider's configuration
+// PUT /admin/sso-providers/:id
+func (h *SSOProviderHandler) Update(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "Invalid provider ID")
+		return
+	}
+
+	var req SSOProviderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	provider, err := h.ssoService.UpdateProvider(c.Request.Context(), id, req.toInput())
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, ssoProviderToResponse(provider))
+}
+
+// Delete deletes an SSO provider and all identities linked through it
+// DELETE /admin/sso-providers/:id
+func (h *SSOProviderHandler) Delete(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "Invalid provider ID")
+		return
+	}
+
+	if err := h.ssoService.DeleteProvider(c.Request.Context(), id); err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, gin.H{"message": "SSO provider deleted successfully"})
+}
@@ -0,0 +1,104 @@
+package admin
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Wei-Shaw/sub2api/internal/pkg/logger"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/response"
+	"github.com/Wei-Shaw/sub2api/internal/server/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+// GetJournalStats returns durable usage journal lag, pending and dead-letter counters.
+// GET /api/v1/admin/usage/journal
+func (h *UsageHandler) GetJournalStats(c *gin.Context) {
+	if h.journalService == nil {
+		response.Error(c, http.StatusServiceUnavailable, "Usage journal service unavailable")
+		return
+	}
+	stats, err := h.journalService.GetStats(c.Request.Context())
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, stats)
+}
+
+// ListJournalPending lists entries delivered to consumers but not yet acknowledged.
+// GET /api/v1/admin/usage/journal/pending
+func (h *UsageHandler) ListJournalPending(c *gin.Context) {
+	if h.journalService == nil {
+		response.Error(c, http.StatusServiceUnavailable, "Usage journal service unavailable")
+		return
+	}
+	limit, ok := parseJournalLimit(c)
+	if !ok {
+		return
+	}
+	records, err := h.journalService.ListPending(c.Request.Context(), limit)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, records)
+}
+
+// ListJournalDeadLetters lists entries moved to the dead-letter stream.
+// GET /api/v1/admin/usage/journal/dead-letters
+func (h *UsageHandler) ListJournalDeadLetters(c *gin.Context) {
+	if h.journalService == nil {
+		response.Error(c, http.StatusServiceUnavailable, "Usage journal service unavailable")
+		return
+	}
+	limit, ok := parseJournalLimit(c)
+	if !ok {
+		return
+	}
+	records, err := h.journalService.ListDeadLetters(c.Request.Context(), limit)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, records)
+}
+
+// RequeueJournalDeadLetter appends a dead-letter entry back to the journal.
+// POST /api/v1/admin/usage/journal/dead-letters/:id/requeue
+func (h *UsageHandler) RequeueJournalDeadLetter(c *gin.Context) {
+	if h.journalService == nil {
+		response.Error(c, http.StatusServiceUnavailable, "Usage journal service unavailable")
+		return
+	}
+	id := strings.TrimSpace(c.Param("id"))
+	if id == "" {
+		response.BadRequest(c, "Invalid entry ID")
+		return
+	}
+	operator := int64(0)
+	if subject, ok := middleware.GetAuthSubjectFromContext(c); ok {
+		operator = subject.UserID
+	}
+
+	newID, err := h.journalService.RequeueDeadLetter(c.Request.Context(), id)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	logger.LegacyPrintf("handler.admin.usage", "[UsageJournal] 死信重新入队: operator=%d dead_id=%s new_id=%s", operator, id, newID)
+	response.Success(c, gin.H{"id": newID})
+}
+
+func parseJournalLimit(c *gin.Context) (int, bool) {
+	raw := strings.TrimSpace(c.Query("limit"))
+	if raw == "" {
+		return 0, true
+	}
+	n, err := strconv.Atoi(raw)
+	if err != nil || n <= 0 {
+		response.BadRequest(c, "Invalid limit")
+		return 0, false
+	}
+	return n, true
+}
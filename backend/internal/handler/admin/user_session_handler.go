@@ -0,0 +1,163 @@
+package admin
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/handler/dto"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/response"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/timezone"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// UserSessionHandler handles admin management of user login sessions and login history
+type UserSessionHandler struct {
+	authService         *service.AuthService
+	loginHistoryService *service.LoginHistoryService
+}
+
+// NewUserSessionHandler creates a new admin user session handler
+func NewUserSessionHandler(authService *service.AuthService, loginHistoryService *service.LoginHistoryService) *UserSessionHandler {
+	return &UserSessionHandler{
+		authService:         authService,
+		loginHistoryService: loginHistoryService,
+	}
+}
+
+// ListSessions lists a user's active login sessions
+// GET /api/v1/admin/users/:id/sessions
+func (h *UserSessionHandler) ListSessions(c *gin.Context) {
+	userID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "Invalid user ID")
+		return
+	}
+
+	sessions, err := h.authService.ListUserSessions(c.Request.Context(), userID)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+
+	out := make([]dto.UserSession, 0, len(sessions))
+	for i := range sessions {
+		out = append(out, *dto.UserSessionFromService(&sessions[i], ""))
+	}
+	response.Success(c, out)
+}
+
+// RevokeSession signs out a single login session of a user
+// DELETE /api/v1/admin/users/:id/sessions/:session_id
+func (h *UserSessionHandler) RevokeSession(c *gin.Context) {
+	userID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "Invalid user ID")
+		return
+	}
+
+	if err := h.authService.RevokeUserSession(c.Request.Context(), userID, c.Param("session_id")); err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, gin.H{"message": "Session revoked successfully"})
+}
+
+// RevokeAllSessions signs out every login session of a user
+// DELETE /api/v1/admin/users/:id/sessions
+func (h *UserSessionHandler) RevokeAllSessions(c *gin.Context) {
+	userID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "Invalid user ID")
+		return
+	}
+
+	if err := h.authService.RevokeAllUserSessions(c.Request.Context(), userID); err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, gin.H{"message": "All sessions revoked successfully"})
+}
+
+// ListUserLoginHistory lists a user's login attempts
+// GET /api/v1/admin/users/:id/login-history
+func (h *UserSessionHandler) ListUserLoginHistory(c *gin.Context) {
+	userID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "Invalid user ID")
+		return
+	}
+	h.searchLoginHistory(c, service.LoginHistoryFilter{UserID: userID})
+}
+
+// SearchLoginHistory searches login attempts across all users
+// GET /api/v1/admin/login-history
+// Query params:
+//   - user_id: exact match
+//   - email: attempted email (case-insensitive), also matches failures for unknown emails
+//   - ip: exact match
+//   - success: true / false
+//   - start_date / end_date: YYYY-MM-DD in the given timezone
+func (h *UserSessionHandler) SearchLoginHistory(c *gin.Context) {
+	filter := service.LoginHistoryFilter{
+		Email: strings.TrimSpace(c.Query("email")),
+		IP:    strings.TrimSpace(c.Query("ip")),
+	}
+	if userIDStr := c.Query("user_id"); userIDStr != "" {
+		id, err := strconv.ParseInt(userIDStr, 10, 64)
+		if err != nil || id <= 0 {
+			response.BadRequest(c, "Invalid user_id")
+			return
+		}
+		filter.UserID = id
+	}
+	h.searchLoginHistory(c, filter)
+}
+
+func (h *UserSessionHandler) searchLoginHistory(c *gin.Context, filter service.LoginHistoryFilter) {
+	page, pageSize := response.ParsePagination(c)
+
+	if successStr := c.Query("success"); successStr != "" {
+		success, err := strconv.ParseBool(successStr)
+		if err != nil {
+			response.BadRequest(c, "Invalid success")
+			return
+		}
+		filter.Success = &success
+	}
+
+	userTZ := c.Query("timezone")
+	if startDateStr := c.Query("start_date"); startDateStr != "" {
+		t, err := timezone.ParseInUserLocation("2006-01-02", startDateStr, userTZ)
+		if err != nil {
+			response.BadRequest(c, "Invalid start_date format, use YYYY-MM-DD")
+			return
+		}
+		filter.StartTime = &t
+	}
+	if endDateStr := c.Query("end_date"); endDateStr != "" {
+		t, err := timezone.ParseInUserLocation("2006-01-02", endDateStr, userTZ)
+		if err != nil {
+			response.BadRequest(c, "Invalid end_date format, use YYYY-MM-DD")
+			return
+		}
+		t = t.Add(24 * time.Hour)
+		filter.EndTime = &t
+	}
+
+	params := pagination.PaginationParams{Page: page, PageSize: pageSize}
+	entries, result, err := h.loginHistoryService.Search(c.Request.Context(), params, filter)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+
+	out := make([]dto.AdminLoginHistory, 0, len(entries))
+	for i := range entries {
+		out = append(out, *dto.LoginHistoryFromServiceAdmin(&entries[i]))
+	}
+	response.Paginated(c, out, result.Total, page, pageSize)
+}
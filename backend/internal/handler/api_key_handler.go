@@ -3,6 +3,7 @@ package handler
 
 import (
 	"context"
+	"fmt"
 	"strconv"
 	"time"
 
@@ -41,6 +42,15 @@ type CreateAPIKeyRequest struct {
 	RateLimit5h *float64 `json:"rate_limit_5h"`
 	RateLimit1d *float64 `json:"rate_limit_1d"`
 	RateLimit7d *float64 `json:"rate_limit_7d"`
+
+	// Model restriction fields (glob patterns, e.g. "claude-*-haiku-*")
+	ModelAllowlist []string `json:"model_allowlist"`
+	ModelDenylist  []string `json:"model_denylist"`
+
+	// Traffic limit fields (0 = unlimited)
+	RPMLimit       *int `json:"rpm_limit"`
+	TPMLimit       *int `json:"tpm_limit"`
+	MaxConcurrency *int `json:"max_concurrency"`
 }
 
 // UpdateAPIKeyRequest represents the update API key request payload
@@ -59,6 +69,93 @@ type UpdateAPIKeyRequest struct {
 	RateLimit1d         *float64 `json:"rate_limit_1d"`
 	RateLimit7d         *float64 `json:"rate_limit_7d"`
 	ResetRateLimitUsage *bool    `json:"reset_rate_limit_usage"` // 重置限速用量
+
+	// Model restriction fields (nil = no change, empty array = clear)
+	ModelAllowlist *[]string `json:"model_allowlist"`
+	ModelDenylist  *[]string `json:"model_denylist"`
+
+	// Traffic limit fields (nil = no change, 0 = unlimited)
+	RPMLimit       *int `json:"rpm_limit"`
+	TPMLimit       *int `json:"tpm_limit"`
+	MaxConcurrency *int `json:"max_concurrency"`
+}
+
+// toService converts the create payload into a service request
+func (req *CreateAPIKeyRequest) toService() service.CreateAPIKeyRequest {
+	svcReq := service.CreateAPIKeyRequest{
+		Name:          req.Name,
+		GroupID:       req.GroupID,
+		CustomKey:     req.CustomKey,
+		IPWhitelist:   req.IPWhitelist,
+		IPBlacklist:   req.IPBlacklist,
+		ExpiresInDays: req.ExpiresInDays,
+
+		ModelAllowlist: req.ModelAllowlist,
+		ModelDenylist:  req.ModelDenylist,
+	}
+	if req.Quota != nil {
+		svcReq.Quota = *req.Quota
+	}
+	if req.RateLimit5h != nil {
+		svcReq.RateLimit5h = *req.RateLimit5h
+	}
+	if req.RateLimit1d != nil {
+		svcReq.RateLimit1d = *req.RateLimit1d
+	}
+	if req.RateLimit7d != nil {
+		svcReq.RateLimit7d = *req.RateLimit7d
+	}
+	if req.RPMLimit != nil {
+		svcReq.RPMLimit = *req.RPMLimit
+	}
+	if req.TPMLimit != nil {
+		svcReq.TPMLimit = *req.TPMLimit
+	}
+	if req.MaxConcurrency != nil {
+		svcReq.MaxConcurrency = *req.MaxConcurrency
+	}
+	return svcReq
+}
+
+// toService converts the update payload into a service request
+func (req *UpdateAPIKeyRequest) toService() (service.UpdateAPIKeyRequest, error) {
+	svcReq := service.UpdateAPIKeyRequest{
+		IPWhitelist:         req.IPWhitelist,
+		IPBlacklist:         req.IPBlacklist,
+		Quota:               req.Quota,
+		ResetQuota:          req.ResetQuota,
+		RateLimit5h:         req.RateLimit5h,
+		RateLimit1d:         req.RateLimit1d,
+		RateLimit7d:         req.RateLimit7d,
+		ResetRateLimitUsage: req.ResetRateLimitUsage,
+		ModelAllowlist:      req.ModelAllowlist,
+		ModelDenylist:       req.ModelDenylist,
+		RPMLimit:            req.RPMLimit,
+		TPMLimit:            req.TPMLimit,
+		MaxConcurrency:      req.MaxConcurrency,
+	}
+	if req.Name != "" {
+		svcReq.Name = &req.Name
+	}
+	svcReq.GroupID = req.GroupID
+	if req.Status != "" {
+		svcReq.Status = &req.Status
+	}
+	// Parse expires_at if provided
+	if req.ExpiresAt != nil {
+		if *req.ExpiresAt == "" {
+			// Empty string means clear expiration
+			svcReq.ExpiresAt = nil
+			svcReq.ClearExpiration = true
+		} else {
+			t, err := time.Parse(time.RFC3339, *req.ExpiresAt)
+			if err != nil {
+				return svcReq, fmt.Errorf("invalid expires_at format: %w", err)
+			}
+			svcReq.ExpiresAt = &t
+		}
+	}
+	return svcReq, nil
 }
 
 // List handles listing user's API keys with pagination
@@ -131,26 +228,7 @@ func (h *APIKeyHandler) Create(c *gin.Context) {
 		return
 	}
 
-	svcReq := service.CreateAPIKeyRequest{
-		Name:          req.Name,
-		GroupID:       req.GroupID,
-		CustomKey:     req.CustomKey,
-		IPWhitelist:   req.IPWhitelist,
-		IPBlacklist:   req.IPBlacklist,
-		ExpiresInDays: req.ExpiresInDays,
-	}
-	if req.Quota != nil {
-		svcReq.Quota = *req.Quota
-	}
-	if req.RateLimit5h != nil {
-		svcReq.RateLimit5h = *req.RateLimit5h
-	}
-	if req.RateLimit1d != nil {
-		svcReq.RateLimit1d = *req.RateLimit1d
-	}
-	if req.RateLimit7d != nil {
-		svcReq.RateLimit7d = *req.RateLimit7d
-	}
+	svcReq := req.toService()
 
 	executeUserIdempotentJSON(c, "user.api_keys.create", req, service.DefaultWriteIdempotencyTTL(), func(ctx context.Context) (any, error) {
 		key, err := h.apiKeyService.Create(ctx, subject.UserID, svcReq)
@@ -182,37 +260,10 @@ func (h *APIKeyHandler) Update(c *gin.Context) {
 		return
 	}
 
-	svcReq := service.UpdateAPIKeyRequest{
-		IPWhitelist:         req.IPWhitelist,
-		IPBlacklist:         req.IPBlacklist,
-		Quota:               req.Quota,
-		ResetQuota:          req.ResetQuota,
-		RateLimit5h:         req.RateLimit5h,
-		RateLimit1d:         req.RateLimit1d,
-		RateLimit7d:         req.RateLimit7d,
-		ResetRateLimitUsage: req.ResetRateLimitUsage,
-	}
-	if req.Name != "" {
-		svcReq.Name = &req.Name
-	}
-	svcReq.GroupID = req.GroupID
-	if req.Status != "" {
-		svcReq.Status = &req.Status
-	}
-	// Parse expires_at if provided
-	if req.ExpiresAt != nil {
-		if *req.ExpiresAt == "" {
-			// Empty string means clear expiration
-			svcReq.ExpiresAt = nil
-			svcReq.ClearExpiration = true
-		} else {
-			t, err := time.Parse(time.RFC3339, *req.ExpiresAt)
-			if err != nil {
-				response.BadRequest(c, "Invalid expires_at format: "+err.Error())
-				return
-			}
-			svcReq.ExpiresAt = &t
-		}
+	svcReq, err := req.toService()
+	if err != nil {
+		response.BadRequest(c, "Invalid request: "+err.Error())
+		return
 	}
 
 	key, err := h.apiKeyService.Update(c.Request.Context(), keyID, subject.UserID, svcReq)
@@ -0,0 +1,74 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/Wei-Shaw/sub2api/internal/pkg/antigravity"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/gemini"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/openai"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+	"github.com/tidwall/gjson"
+)
+
+// apiKeyModelDeniedMessage 模型被 API Key 的模型白名单/黑名单拒绝时的错误信息
+func apiKeyModelDeniedMessage(model string) string {
+	return fmt.Sprintf("model %q is not allowed for this API key", model)
+}
+
+// filterModelsForAPIKey 按 API Key 的模型限制过滤模型列表（/v1/models 等列表接口）
+func filterModelsForAPIKey[T any](apiKey *service.APIKey, models []T, modelID func(T) string) []T {
+	if apiKey == nil || !apiKey.HasModelRestrictions() {
+		return models
+	}
+	out := make([]T, 0, len(models))
+	for _, m := range models {
+		if apiKey.IsModelAllowed(modelID(m)) {
+			out = append(out, m)
+		}
+	}
+	return out
+}
+
+// filterGeminiModelsListBody 过滤上游 /v1beta/models 响应中不允许的模型，
+// 保留 nextPageToken 等其余字段；无法解析时原样返回。
+func filterGeminiModelsListBody(apiKey *service.APIKey, body []byte) []byte {
+	if apiKey == nil || !apiKey.HasModelRestrictions() {
+		return body
+	}
+	var payload map[string]json.RawMessage
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return body
+	}
+	var models []json.RawMessage
+	if raw, ok := payload["models"]; !ok || json.Unmarshal(raw, &models) != nil {
+		return body
+	}
+	models = filterModelsForAPIKey(apiKey, models, func(m json.RawMessage) string {
+		return gjson.GetBytes(m, "name").String()
+	})
+	filtered, err := json.Marshal(models)
+	if err != nil {
+		return body
+	}
+	payload["models"] = filtered
+	out, err := json.Marshal(payload)
+	if err != nil {
+		return body
+	}
+	return out
+}
+
+func filterGeminiModelsList(apiKey *service.APIKey, list gemini.ModelsListResponse) gemini.ModelsListResponse {
+	list.Models = filterModelsForAPIKey(apiKey, list.Models, func(m gemini.Model) string { return m.Name })
+	return list
+}
+
+func filterAntigravityGeminiModelsList(apiKey *service.APIKey, list antigravity.GeminiModelsListResponse) antigravity.GeminiModelsListResponse {
+	list.Models = filterModelsForAPIKey(apiKey, list.Models, func(m antigravity.GeminiModel) string { return m.Name })
+	return list
+}
+
+func openAIModelID(m openai.Model) string {
+	return m.ID
+}
@@ -0,0 +1,94 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Wei-Shaw/sub2api/internal/pkg/claude"
+	middleware2 "github.com/Wei-Shaw/sub2api/internal/server/middleware"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+func newModelRestrictedContext(t *testing.T, method, path string, body []byte, apiKey *service.APIKey) (*gin.Context, *httptest.ResponseRecorder) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	rec := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(rec)
+	c.Request = httptest.NewRequest(method, path, bytes.NewReader(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Set(string(middleware2.ContextKeyAPIKey), apiKey)
+	c.Set(string(middleware2.ContextKeyUser), middleware2.AuthSubject{UserID: apiKey.UserID, Concurrency: 1})
+	return c, rec
+}
+
+func TestGatewayMessages_ModelDeniedByAPIKey(t *testing.T) {
+	apiKey := &service.APIKey{ID: 1, UserID: 2, ModelDenylist: []string{"*opus*"}}
+	body := []byte(`{"model":"claude-opus-4-6","max_tokens":16,"messages":[{"role":"user","content":"hi"}]}`)
+	c, rec := newModelRestrictedContext(t, http.MethodPost, "/v1/messages", body, apiKey)
+
+	(&GatewayHandler{}).Messages(c)
+
+	require.Equal(t, http.StatusForbidden, rec.Code)
+	var resp map[string]any
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	require.Equal(t, "error", resp["type"])
+	errObj := resp["error"].(map[string]any)
+	require.Equal(t, "permission_error", errObj["type"])
+	require.Contains(t, errObj["message"], "claude-opus-4-6")
+}
+
+func TestGeminiV1BetaModels_ModelDeniedByAPIKey(t *testing.T) {
+	apiKey := &service.APIKey{
+		ID:             1,
+		UserID:         2,
+		Group:          &service.Group{ID: 3, Platform: service.PlatformGemini},
+		ModelAllowlist: []string{"gemini-2.5-flash*"},
+	}
+	body := []byte(`{"contents":[{"role":"user","parts":[{"text":"hi"}]}]}`)
+	c, rec := newModelRestrictedContext(t, http.MethodPost, "/v1beta/models/gemini-2.5-pro:generateContent", body, apiKey)
+	c.Params = gin.Params{{Key: "modelAction", Value: "/gemini-2.5-pro:generateContent"}}
+
+	(&GatewayHandler{}).GeminiV1BetaModels(c)
+
+	require.Equal(t, http.StatusForbidden, rec.Code)
+	var resp map[string]any
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	errObj := resp["error"].(map[string]any)
+	require.Equal(t, "PERMISSION_DENIED", errObj["status"])
+}
+
+func TestFilterModelsForAPIKey(t *testing.T) {
+	apiKey := &service.APIKey{ModelAllowlist: []string{"claude-*"}, ModelDenylist: []string{"*opus*"}}
+	models := []claude.Model{{ID: "claude-opus-4-6"}, {ID: "claude-haiku-4-5"}, {ID: "gpt-4o"}}
+
+	got := filterModelsForAPIKey(apiKey, models, func(m claude.Model) string { return m.ID })
+	require.Equal(t, []claude.Model{{ID: "claude-haiku-4-5"}}, got)
+
+	unrestricted := filterModelsForAPIKey(&service.APIKey{}, models, func(m claude.Model) string { return m.ID })
+	require.Len(t, unrestricted, 3)
+}
+
+func TestFilterGeminiModelsListBody(t *testing.T) {
+	apiKey := &service.APIKey{ModelDenylist: []string{"gemini-*-pro*"}}
+	body := []byte(`{"models":[{"name":"models/gemini-2.5-pro"},{"name":"models/gemini-2.5-flash"}],"nextPageToken":"abc"}`)
+
+	out := filterGeminiModelsListBody(apiKey, body)
+
+	var resp struct {
+		Models []struct {
+			Name string `json:"name"`
+		} `json:"models"`
+		NextPageToken string `json:"nextPageToken"`
+	}
+	require.NoError(t, json.Unmarshal(out, &resp))
+	require.Len(t, resp.Models, 1)
+	require.Equal(t, "models/gemini-2.5-flash", resp.Models[0].Name)
+	require.Equal(t, "abc", resp.NextPageToken)
+
+	require.Equal(t, []byte("not json"), filterGeminiModelsListBody(apiKey, []byte("not json")))
+}
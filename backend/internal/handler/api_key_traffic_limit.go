@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// rateLimitHeaderStyle API Key 限流响应头的协议风格
+type rateLimitHeaderStyle int
+
+const (
+	// rateLimitHeadersOpenAI x-ratelimit-{limit,remaining,reset}-{requests,tokens}，reset 为时长（如 "12s"）。
+	// Gemini 原生协议没有约定的限流头，同样使用该风格。
+	rateLimitHeadersOpenAI rateLimitHeaderStyle = iota
+	// rateLimitHeadersAnthropic anthropic-ratelimit-{requests,tokens}-{limit,remaining,reset}，reset 为 RFC 3339 时间。
+	rateLimitHeadersAnthropic
+)
+
+// admitAPIKeyTraffic 执行 API Key 的 RPM/TPM 准入检查，并按协议风格写入限流响应头。
+// 返回错误时已写入 Retry-After，调用方按各自协议格式响应 429。
+func admitAPIKeyTraffic(c *gin.Context, svc *service.APIKeyTrafficLimitService, apiKey *service.APIKey, style rateLimitHeaderStyle) error {
+	state, err := svc.Admit(c.Request.Context(), apiKey)
+	if state == nil {
+		return err
+	}
+	writeAPIKeyRateLimitHeaders(c.Writer.Header(), state, style, time.Now())
+	if err != nil {
+		c.Header("Retry-After", strconv.Itoa(ceilSeconds(state.ResetAfter)))
+	}
+	return err
+}
+
+// writeAPIKeyRateLimitHeaders 写入 API Key 的限流响应头（仅包含已配置的维度）
+func writeAPIKeyRateLimitHeaders(h http.Header, state *service.APIKeyTrafficState, style rateLimitHeaderStyle, now time.Time) {
+	write := func(dimension string, limit, remaining int) {
+		if limit <= 0 {
+			return
+		}
+		switch style {
+		case rateLimitHeadersAnthropic:
+			prefix := "anthropic-ratelimit-" + dimension
+			h.Set(prefix+"-limit", strconv.Itoa(limit))
+			h.Set(prefix+"-remaining", strconv.Itoa(remaining))
+			h.Set(prefix+"-reset", now.Add(state.ResetAfter).UTC().Truncate(time.Second).Format(time.RFC3339))
+		default:
+			h.Set("x-ratelimit-limit-"+dimension, strconv.Itoa(limit))
+			h.Set("x-ratelimit-remaining-"+dimension, strconv.Itoa(remaining))
+			h.Set("x-ratelimit-reset-"+dimension, (time.Duration(ceilSeconds(state.ResetAfter)) * time.Second).String())
+		}
+	}
+	write("requests", state.RequestLimit, state.RequestRemaining)
+	write("tokens", state.TokenLimit, state.TokenRemaining)
+}
+
+// ceilSeconds 向上取整到秒，至少为 1 秒
+func ceilSeconds(d time.Duration) int {
+	seconds := int((d + time.Second - 1) / time.Second)
+	if seconds < 1 {
+		return 1
+	}
+	return seconds
+}
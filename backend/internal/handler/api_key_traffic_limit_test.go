@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+type apiKeyTrafficCacheStub struct {
+	window *service.APIKeyTrafficWindow
+}
+
+func (s *apiKeyTrafficCacheStub) Admit(context.Context, int64, int, int) (*service.APIKeyTrafficWindow, error) {
+	return s.window, nil
+}
+
+func (s *apiKeyTrafficCacheStub) AddTokens(context.Context, int64, int) error {
+	return nil
+}
+
+func TestAdmitAPIKeyTraffic_RejectedSetsRetryAfter(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	rec := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(rec)
+	c.Request = httptest.NewRequest(http.MethodPost, "/v1/messages", nil)
+
+	svc := service.NewAPIKeyTrafficLimitService(&apiKeyTrafficCacheStub{
+		window: &service.APIKeyTrafficWindow{Requests: 5, ResetAfter: 12300 * time.Millisecond},
+	})
+	err := admitAPIKeyTraffic(c, svc, &service.APIKey{ID: 1, RPMLimit: 5}, rateLimitHeadersAnthropic)
+
+	require.ErrorIs(t, err, service.ErrAPIKeyRPMExceeded)
+	require.Equal(t, "13", rec.Header().Get("Retry-After"))
+	require.Equal(t, "5", rec.Header().Get("anthropic-ratelimit-requests-limit"))
+	require.Equal(t, "0", rec.Header().Get("anthropic-ratelimit-requests-remaining"))
+}
+
+func TestAdmitAPIKeyTraffic_UnlimitedKeyWritesNoHeaders(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	rec := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(rec)
+	c.Request = httptest.NewRequest(http.MethodPost, "/v1/responses", nil)
+
+	err := admitAPIKeyTraffic(c, nil, &service.APIKey{ID: 1}, rateLimitHeadersOpenAI)
+
+	require.NoError(t, err)
+	require.Empty(t, rec.Header())
+}
+
+func TestWriteAPIKeyRateLimitHeaders_OpenAIStyle(t *testing.T) {
+	h := http.Header{}
+	state := &service.APIKeyTrafficState{RequestLimit: 60, RequestRemaining: 59, ResetAfter: 1500 * time.Millisecond}
+
+	writeAPIKeyRateLimitHeaders(h, state, rateLimitHeadersOpenAI, time.Now())
+
+	require.Equal(t, "60", h.Get("x-ratelimit-limit-requests"))
+	require.Equal(t, "59", h.Get("x-ratelimit-remaining-requests"))
+	require.Equal(t, "2s", h.Get("x-ratelimit-reset-requests"))
+	require.Empty(t, h.Get("x-ratelimit-limit-tokens"), "unconfigured dimensions are omitted")
+}
+
+func TestWriteAPIKeyRateLimitHeaders_AnthropicStyle(t *testing.T) {
+	h := http.Header{}
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	state := &service.APIKeyTrafficState{TokenLimit: 1000, TokenRemaining: 0, ResetAfter: 55 * time.Second}
+
+	writeAPIKeyRateLimitHeaders(h, state, rateLimitHeadersAnthropic, now)
+
+	require.Equal(t, "1000", h.Get("anthropic-ratelimit-tokens-limit"))
+	require.Equal(t, "0", h.Get("anthropic-ratelimit-tokens-remaining"))
+	require.Equal(t, "2026-01-02T03:05:00Z", h.Get("anthropic-ratelimit-tokens-reset"))
+	require.Empty(t, h.Get("anthropic-ratelimit-requests-limit"))
+}
+
+func TestCeilSeconds(t *testing.T) {
+	require.Equal(t, 1, ceilSeconds(0))
+	require.Equal(t, 1, ceilSeconds(200*time.Millisecond))
+	require.Equal(t, 30, ceilSeconds(30*time.Second))
+	require.Equal(t, 31, ceilSeconds(30*time.Second+time.Millisecond))
+}
@@ -2,6 +2,7 @@ package handler
 
 import (
 	"context"
+	"encoding/json"
 	"log/slog"
 	"net/url"
 	"strings"
@@ -25,10 +26,14 @@ type AuthHandler struct {
 	promoService  *service.PromoService
 	redeemService *service.RedeemService
 	totpService   *service.TotpService
+	webAuthn      *service.WebAuthnService
+	recoveryCodes *service.RecoveryCodeService
+	loginHistory  *service.LoginHistoryService
+	referrals     *service.ReferralService
 }
 
 // NewAuthHandler creates a new AuthHandler
-func NewAuthHandler(cfg *config.Config, authService *service.AuthService, userService *service.UserService, settingService *service.SettingService, promoService *service.PromoService, redeemService *service.RedeemService, totpService *service.TotpService) *AuthHandler {
+func NewAuthHandler(cfg *config.Config, authService *service.AuthService, userService *service.UserService, settingService *service.SettingService, promoService *service.PromoService, redeemService *service.RedeemService, totpService *service.TotpService, webAuthnService *service.WebAuthnService, recoveryCodeService *service.RecoveryCodeService, loginHistoryService *service.LoginHistoryService, referralService *service.ReferralService) *AuthHandler {
 	return &AuthHandler{
 		cfg:           cfg,
 		authService:   authService,
@@ -37,6 +42,10 @@ func NewAuthHandler(cfg *config.Config, authService *service.AuthService, userSe
 		promoService:  promoService,
 		redeemService: redeemService,
 		totpService:   totpService,
+		webAuthn:      webAuthnService,
+		recoveryCodes: recoveryCodeService,
+		loginHistory:  loginHistoryService,
+		referrals:     referralService,
 	}
 }
 
@@ -48,6 +57,7 @@ type RegisterRequest struct {
 	TurnstileToken string `json:"turnstile_token"`
 	PromoCode      string `json:"promo_code"`      // 注册优惠码
 	InvitationCode string `json:"invitation_code"` // 邀请码
+	ReferralCode   string `json:"referral_code"`   // 推荐码（推荐返佣）
 }
 
 // SendVerifyCodeRequest 发送验证码请求
@@ -154,6 +164,13 @@ func (h *AuthHandler) Register(c *gin.Context) {
 		return
 	}
 
+	// 推荐关系绑定失败不影响注册结果
+	if req.ReferralCode != "" && h.referrals != nil && h.referrals.Enabled() {
+		if _, err := h.referrals.BindOnRegister(c.Request.Context(), user.ID, req.ReferralCode); err != nil {
+			slog.Warn("failed to bind referral", "user_id", user.ID, "error", err)
+		}
+	}
+
 	h.respondWithTokenPair(c, user)
 }
 
@@ -201,13 +218,14 @@ func (h *AuthHandler) Login(c *gin.Context) {
 
 	token, user, err := h.authService.Login(c.Request.Context(), req.Email, req.Password)
 	if err != nil {
+		h.loginHistory.RecordFailure(c.Request.Context(), 0, req.Email, service.LoginMethodPassword, err)
 		response.ErrorFrom(c, err)
 		return
 	}
 	_ = token // token 由 authService.Login 返回但此处由 respondWithTokenPair 重新生成
 
-	// Check if TOTP 2FA is enabled for this user
-	if h.totpService != nil && h.settingSvc.IsTotpEnabled(c.Request.Context()) && user.TotpEnabled {
+	// Check if a second factor (TOTP or passkey) is enabled for this user
+	if methods := h.secondFactorMethods(c.Request.Context(), user); len(methods) > 0 {
 		// Create a temporary login session for 2FA
 		tempToken, err := h.totpService.CreateLoginSession(c.Request.Context(), user.ID, user.Email)
 		if err != nil {
@@ -219,24 +237,61 @@ func (h *AuthHandler) Login(c *gin.Context) {
 			Requires2FA:     true,
 			TempToken:       tempToken,
 			UserEmailMasked: service.MaskEmail(user.Email),
+			Methods:         methods,
 		})
 		return
 	}
 
+	h.loginHistory.RecordSuccess(c.Request.Context(), user, service.LoginMethodPassword)
 	h.respondWithTokenPair(c, user)
 }
 
 // TotpLoginResponse represents the response when 2FA is required
 type TotpLoginResponse struct {
-	Requires2FA     bool   `json:"requires_2fa"`
-	TempToken       string `json:"temp_token,omitempty"`
-	UserEmailMasked string `json:"user_email_masked,omitempty"`
+	Requires2FA     bool     `json:"requires_2fa"`
+	TempToken       string   `json:"temp_token,omitempty"`
+	UserEmailMasked string   `json:"user_email_masked,omitempty"`
+	Methods         []string `json:"methods,omitempty"` // totp / webauthn / recovery_code
 }
 
-// Login2FARequest represents the 2FA login request
+// Second factor methods accepted by /auth/login/2fa
+const (
+	secondFactorTotp         = "totp"
+	secondFactorWebAuthn     = "webauthn"
+	secondFactorRecoveryCode = "recovery_code"
+)
+
+// secondFactorMethods returns the second factors available to the user.
+// Recovery codes are only offered alongside TOTP or a passkey and never trigger 2FA on their own.
+func (h *AuthHandler) secondFactorMethods(ctx context.Context, user *service.User) []string {
+	var methods []string
+	if h.totpService != nil && h.settingSvc.IsTotpEnabled(ctx) && user.TotpEnabled {
+		methods = append(methods, secondFactorTotp)
+	}
+	if h.webAuthn != nil {
+		hasPasskey, err := h.webAuthn.HasCredentials(ctx, user.ID)
+		if err != nil {
+			slog.Error("failed to check passkeys", "error", err, "user_id", user.ID)
+		}
+		if hasPasskey {
+			methods = append(methods, secondFactorWebAuthn)
+		}
+	}
+	if len(methods) > 0 && h.recoveryCodes != nil {
+		if remaining, err := h.recoveryCodes.Remaining(ctx, user.ID); err == nil && remaining > 0 {
+			methods = append(methods, secondFactorRecoveryCode)
+		}
+	}
+	return methods
+}
+
+// Login2FARequest represents the 2FA login request; exactly one of
+// totp_code, recovery_code or webauthn must be provided.
 type Login2FARequest struct {
-	TempToken string `json:"temp_token" binding:"required"`
-	TotpCode  string `json:"totp_code" binding:"required,len=6"`
+	TempToken    string          `json:"temp_token" binding:"required"`
+	TotpCode     string          `json:"totp_code" binding:"omitempty,len=6"`
+	RecoveryCode string          `json:"recovery_code"`
+	WebAuthn     json.RawMessage `json:"webauthn"` // PublicKeyCredential.toJSON() of the assertion
 }
 
 // Login2FA completes the login with 2FA verification
@@ -248,9 +303,22 @@ func (h *AuthHandler) Login2FA(c *gin.Context) {
 		return
 	}
 
+	provided := 0
+	for _, present := range []bool{req.TotpCode != "", req.RecoveryCode != "", len(req.WebAuthn) > 0} {
+		if present {
+			provided++
+		}
+	}
+	if provided != 1 {
+		response.BadRequest(c, "Exactly one of totp_code, recovery_code or webauthn is required")
+		return
+	}
+
 	slog.Debug("login_2fa_request",
 		"temp_token_len", len(req.TempToken),
-		"totp_code_len", len(req.TotpCode))
+		"totp_code_len", len(req.TotpCode),
+		"recovery_code", req.RecoveryCode != "",
+		"webauthn", len(req.WebAuthn) > 0)
 
 	// Get the login session
 	session, err := h.totpService.GetLoginSession(c.Request.Context(), req.TempToken)
@@ -270,12 +338,33 @@ func (h *AuthHandler) Login2FA(c *gin.Context) {
 		"user_id", session.UserID,
 		"email", session.Email)
 
-	// Verify the TOTP code
-	if err := h.totpService.VerifyCode(c.Request.Context(), session.UserID, req.TotpCode); err != nil {
+	// Verify the second factor
+	var verifyErr error
+	method := service.LoginMethodTotp
+	switch {
+	case req.RecoveryCode != "":
+		method = service.LoginMethodRecoveryCode
+		if h.recoveryCodes == nil {
+			response.BadRequest(c, "Recovery codes are not available")
+			return
+		}
+		verifyErr = h.recoveryCodes.Consume(c.Request.Context(), session.UserID, req.RecoveryCode)
+	case len(req.WebAuthn) > 0:
+		method = service.LoginMethodWebAuthn
+		if h.webAuthn == nil {
+			response.ErrorFrom(c, service.ErrWebAuthnNotConfigured)
+			return
+		}
+		verifyErr = h.webAuthn.VerifySecondFactor(c.Request.Context(), req.TempToken, session.UserID, req.WebAuthn)
+	default:
+		verifyErr = h.totpService.VerifyCode(c.Request.Context(), session.UserID, req.TotpCode)
+	}
+	if verifyErr != nil {
 		slog.Debug("login_2fa_verify_failed",
 			"user_id", session.UserID,
-			"error", err)
-		response.ErrorFrom(c, err)
+			"error", verifyErr)
+		h.loginHistory.RecordFailure(c.Request.Context(), session.UserID, session.Email, method, verifyErr)
+		response.ErrorFrom(c, verifyErr)
 		return
 	}
 
@@ -289,6 +378,7 @@ func (h *AuthHandler) Login2FA(c *gin.Context) {
 		return
 	}
 
+	h.loginHistory.RecordSuccess(c.Request.Context(), user, method)
 	h.respondWithTokenPair(c, user)
 }
 
@@ -327,10 +417,12 @@ type ValidatePromoCodeRequest struct {
 
 // ValidatePromoCodeResponse 验证优惠码响应
 type ValidatePromoCodeResponse struct {
-	Valid       bool    `json:"valid"`
-	BonusAmount float64 `json:"bonus_amount,omitempty"`
-	ErrorCode   string  `json:"error_code,omitempty"`
-	Message     string  `json:"message,omitempty"`
+	Valid             bool    `json:"valid"`
+	BonusAmount       float64 `json:"bonus_amount,omitempty"`
+	TopupBonusPercent float64 `json:"topup_bonus_percent,omitempty"`
+	TrialDays         int     `json:"trial_days,omitempty"`
+	ErrorCode         string  `json:"error_code,omitempty"`
+	Message           string  `json:"message,omitempty"`
 }
 
 // ValidatePromoCode 验证优惠码（公开接口，注册前调用）
@@ -383,10 +475,15 @@ func (h *AuthHandler) ValidatePromoCode(c *gin.Context) {
 		return
 	}
 
-	response.Success(c, ValidatePromoCodeResponse{
-		Valid:       true,
-		BonusAmount: promoCode.BonusAmount,
-	})
+	resp := ValidatePromoCodeResponse{
+		Valid:             true,
+		BonusAmount:       promoCode.BonusAmount.Float64(),
+		TopupBonusPercent: promoCode.TopupBonusPercent,
+	}
+	if promoCode.HasTrial() {
+		resp.TrialDays = promoCode.TrialDays
+	}
+	response.Success(c, resp)
 }
 
 // ValidateInvitationCodeRequest 验证邀请码请求
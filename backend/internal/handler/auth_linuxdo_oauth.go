@@ -211,13 +211,16 @@ func (h *AuthHandler) LinuxDoOAuthCallback(c *gin.Context) {
 		email = linuxDoSyntheticEmail(subject)
 	}
 
-	tokenPair, _, err := h.authService.LoginOrRegisterOAuthWithTokenPair(c.Request.Context(), email, username)
+	tokenPair, user, err := h.authService.LoginOrRegisterOAuthWithTokenPair(c.Request.Context(), email, username)
 	if err != nil {
+		h.loginHistory.RecordFailure(c.Request.Context(), 0, email, service.LoginMethodLinuxDo, err)
 		// 避免把内部细节泄露给客户端；给前端保留结构化原因与提示信息即可。
 		redirectOAuthError(c, frontendCallback, "login_failed", infraerrors.Reason(err), infraerrors.Message(err))
 		return
 	}
 
+	h.loginHistory.RecordSuccess(c.Request.Context(), user, service.LoginMethodLinuxDo)
+
 	fragment := url.Values{}
 	fragment.Set("access_token", tokenPair.AccessToken)
 	fragment.Set("refresh_token", tokenPair.RefreshToken)
@@ -598,10 +601,18 @@ func readCookieDecoded(c *gin.Context, name string) (string, error) {
 }
 
 func setCookie(c *gin.Context, name string, value string, maxAgeSec int, secure bool) {
+	setCookieAtPath(c, linuxDoOAuthCookiePath, name, value, maxAgeSec, secure)
+}
+
+func clearCookie(c *gin.Context, name string, secure bool) {
+	clearCookieAtPath(c, linuxDoOAuthCookiePath, name, secure)
+}
+
+func setCookieAtPath(c *gin.Context, path string, name string, value string, maxAgeSec int, secure bool) {
 	http.SetCookie(c.Writer, &http.Cookie{
 		Name:     name,
 		Value:    value,
-		Path:     linuxDoOAuthCookiePath,
+		Path:     path,
 		MaxAge:   maxAgeSec,
 		HttpOnly: true,
 		Secure:   secure,
@@ -609,11 +620,11 @@ func setCookie(c *gin.Context, name string, value string, maxAgeSec int, secure
 	})
 }
 
-func clearCookie(c *gin.Context, name string, secure bool) {
+func clearCookieAtPath(c *gin.Context, path string, name string, secure bool) {
 	http.SetCookie(c.Writer, &http.Cookie{
 		Name:     name,
 		Value:    "",
-		Path:     linuxDoOAuthCookiePath,
+		Path:     path,
 		MaxAge:   -1,
 		HttpOnly: true,
 		Secure:   secure,
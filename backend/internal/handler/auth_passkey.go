@@ -0,0 +1,98 @@
+package handler
+
+import (
+	"encoding/json"
+
+	"github.com/Wei-Shaw/sub2api/internal/pkg/response"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/webauthn"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PasskeyLoginOptionsResponse 无密码登录选项
+type PasskeyLoginOptionsResponse struct {
+	SessionID string                   `json:"session_id"`
+	PublicKey *webauthn.RequestOptions `json:"public_key"`
+}
+
+// PasskeyRequestOptionsResponse 2FA 阶段的 Passkey 认证选项
+type PasskeyRequestOptionsResponse struct {
+	PublicKey *webauthn.RequestOptions `json:"public_key"`
+}
+
+// PasskeyLoginRequest 无密码登录请求
+type PasskeyLoginRequest struct {
+	SessionID  string          `json:"session_id" binding:"required"`
+	Credential json.RawMessage `json:"credential" binding:"required"` // PublicKeyCredential.toJSON()
+}
+
+// Login2FAWebAuthnOptionsRequest 2FA 阶段获取 Passkey 认证选项
+type Login2FAWebAuthnOptionsRequest struct {
+	TempToken string `json:"temp_token" binding:"required"`
+}
+
+// PasskeyLoginOptions starts a passwordless passkey login
+// POST /api/v1/auth/passkey/options
+func (h *AuthHandler) PasskeyLoginOptions(c *gin.Context) {
+	if h.webAuthn == nil {
+		response.ErrorFrom(c, service.ErrWebAuthnNotConfigured)
+		return
+	}
+	sessionID, options, err := h.webAuthn.BeginLogin(c.Request.Context())
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, PasskeyLoginOptionsResponse{SessionID: sessionID, PublicKey: options})
+}
+
+// PasskeyLogin completes a passwordless passkey login
+// POST /api/v1/auth/passkey/login
+func (h *AuthHandler) PasskeyLogin(c *gin.Context) {
+	var req PasskeyLoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request: "+err.Error())
+		return
+	}
+	if h.webAuthn == nil {
+		response.ErrorFrom(c, service.ErrWebAuthnNotConfigured)
+		return
+	}
+
+	user, err := h.webAuthn.FinishLogin(c.Request.Context(), req.SessionID, req.Credential)
+	if err != nil {
+		h.loginHistory.RecordFailure(c.Request.Context(), 0, "", service.LoginMethodPasskey, err)
+		response.ErrorFrom(c, err)
+		return
+	}
+	h.loginHistory.RecordSuccess(c.Request.Context(), user, service.LoginMethodPasskey)
+	h.respondWithTokenPair(c, user)
+}
+
+// Login2FAWebAuthnOptions returns passkey request options for a pending 2FA login
+// POST /api/v1/auth/login/2fa/webauthn/options
+func (h *AuthHandler) Login2FAWebAuthnOptions(c *gin.Context) {
+	var req Login2FAWebAuthnOptionsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request: "+err.Error())
+		return
+	}
+	if h.webAuthn == nil {
+		response.ErrorFrom(c, service.ErrWebAuthnNotConfigured)
+		return
+	}
+
+	session, err := h.totpService.GetLoginSession(c.Request.Context(), req.TempToken)
+	if err != nil || session == nil {
+		response.BadRequest(c, "Invalid or expired 2FA session")
+		return
+	}
+
+	options, err := h.webAuthn.BeginSecondFactor(c.Request.Context(), req.TempToken, session.UserID)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, PasskeyRequestOptionsResponse{PublicKey: options})
+}
@@ -0,0 +1,528 @@
+package handler
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	infraerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
+	pkghttputil "github.com/Wei-Shaw/sub2api/internal/pkg/httputil"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/ip"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/logger"
+	middleware2 "github.com/Wei-Shaw/sub2api/internal/server/middleware"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// BatchHandler 处理异步批处理接口：OpenAI /v1/files + /v1/batches 与 Anthropic /v1/messages/batches。
+type BatchHandler struct {
+	batchService *service.BatchService
+}
+
+// NewBatchHandler creates a new BatchHandler
+func NewBatchHandler(batchService *service.BatchService) *BatchHandler {
+	return &BatchHandler{batchService: batchService}
+}
+
+// ==================== OpenAI Files ====================
+
+// UploadFile handles POST /v1/files (multipart/form-data: file, purpose)
+func (h *BatchHandler) UploadFile(c *gin.Context) {
+	apiKey, ok := h.openAIAPIKey(c)
+	if !ok {
+		return
+	}
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		if maxErr, ok := extractMaxBytesError(err); ok {
+			openAIBatchError(c, http.StatusRequestEntityTooLarge, "invalid_request_error", buildBodyTooLargeMessage(maxErr.Limit))
+			return
+		}
+		openAIBatchError(c, http.StatusBadRequest, "invalid_request_error", "file is required")
+		return
+	}
+	f, err := fileHeader.Open()
+	if err != nil {
+		openAIBatchError(c, http.StatusBadRequest, "invalid_request_error", "Failed to read uploaded file")
+		return
+	}
+	defer func() { _ = f.Close() }()
+	content, err := io.ReadAll(f)
+	if err != nil {
+		openAIBatchError(c, http.StatusBadRequest, "invalid_request_error", "Failed to read uploaded file")
+		return
+	}
+
+	file, err := h.batchService.UploadFile(c.Request.Context(), apiKey, c.PostForm("purpose"), fileHeader.Filename, content)
+	if err != nil {
+		h.openAIServiceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, openAIFileObject(file))
+}
+
+// ListFiles handles GET /v1/files
+func (h *BatchHandler) ListFiles(c *gin.Context) {
+	apiKey, ok := h.openAIAPIKey(c)
+	if !ok {
+		return
+	}
+	limit := parseBatchListLimit(c.Query("limit"))
+	files, err := h.batchService.ListFiles(c.Request.Context(), apiKey.UserID, c.Query("purpose"), c.Query("after"), limit+1)
+	if err != nil {
+		h.openAIServiceError(c, err)
+		return
+	}
+	hasMore := len(files) > limit
+	if hasMore {
+		files = files[:limit]
+	}
+	data := make([]gin.H, 0, len(files))
+	for _, file := range files {
+		data = append(data, openAIFileObject(file))
+	}
+	resp := gin.H{"object": "list", "data": data, "has_more": hasMore}
+	if len(files) > 0 {
+		resp["first_id"] = files[0].FileID
+		resp["last_id"] = files[len(files)-1].FileID
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// GetFile handles GET /v1/files/:file_id
+func (h *BatchHandler) GetFile(c *gin.Context) {
+	apiKey, ok := h.openAIAPIKey(c)
+	if !ok {
+		return
+	}
+	file, err := h.batchService.GetFile(c.Request.Context(), apiKey.UserID, c.Param("file_id"))
+	if err != nil {
+		h.openAIServiceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, openAIFileObject(file))
+}
+
+// GetFileContent handles GET /v1/files/:file_id/content
+func (h *BatchHandler) GetFileContent(c *gin.Context) {
+	apiKey, ok := h.openAIAPIKey(c)
+	if !ok {
+		return
+	}
+	file, err := h.batchService.GetFileContent(c.Request.Context(), apiKey.UserID, c.Param("file_id"))
+	if err != nil {
+		h.openAIServiceError(c, err)
+		return
+	}
+	c.Data(http.StatusOK, "application/jsonl", file.Content)
+}
+
+// DeleteFile handles DELETE /v1/files/:file_id
+func (h *BatchHandler) DeleteFile(c *gin.Context) {
+	apiKey, ok := h.openAIAPIKey(c)
+	if !ok {
+		return
+	}
+	fileID := strings.TrimSpace(c.Param("file_id"))
+	if err := h.batchService.DeleteFile(c.Request.Context(), apiKey.UserID, fileID); err != nil {
+		h.openAIServiceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"id": fileID, "object": "file", "deleted": true})
+}
+
+// ==================== OpenAI Batches ====================
+
+type createOpenAIBatchRequest struct {
+	InputFileID      string            `json:"input_file_id"`
+	Endpoint         string            `json:"endpoint"`
+	CompletionWindow string            `json:"completion_window"`
+	Metadata         map[string]string `json:"metadata"`
+}
+
+// CreateBatch handles POST /v1/batches
+func (h *BatchHandler) CreateBatch(c *gin.Context) {
+	apiKey, ok := h.openAIAPIKey(c)
+	if !ok {
+		return
+	}
+	var req createOpenAIBatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		openAIBatchError(c, http.StatusBadRequest, "invalid_request_error", "Failed to parse request body")
+		return
+	}
+	subscription, _ := middleware2.GetSubscriptionFromContext(c)
+	job, err := h.batchService.CreateOpenAIBatch(c.Request.Context(), apiKey, ip.GetClientIP(c), service.CreateOpenAIBatchInput{
+		InputFileID:      req.InputFileID,
+		Endpoint:         req.Endpoint,
+		CompletionWindow: req.CompletionWindow,
+		Metadata:         req.Metadata,
+		Subscription:     subscription,
+	})
+	if err != nil {
+		h.openAIServiceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, openAIBatchObject(job))
+}
+
+// ListBatches handles GET /v1/batches
+func (h *BatchHandler) ListBatches(c *gin.Context) {
+	apiKey, ok := h.openAIAPIKey(c)
+	if !ok {
+		return
+	}
+	limit := parseBatchListLimit(c.Query("limit"))
+	jobs, err := h.batchService.ListBatches(c.Request.Context(), apiKey.UserID, service.BatchFormatOpenAI, c.Query("after"), limit+1)
+	if err != nil {
+		h.openAIServiceError(c, err)
+		return
+	}
+	hasMore := len(jobs) > limit
+	if hasMore {
+		jobs = jobs[:limit]
+	}
+	data := make([]gin.H, 0, len(jobs))
+	for _, job := range jobs {
+		data = append(data, openAIBatchObject(job))
+	}
+	resp := gin.H{"object": "list", "data": data, "has_more": hasMore}
+	if len(jobs) > 0 {
+		resp["first_id"] = jobs[0].BatchID
+		resp["last_id"] = jobs[len(jobs)-1].BatchID
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// GetBatch handles GET /v1/batches/:batch_id
+func (h *BatchHandler) GetBatch(c *gin.Context) {
+	apiKey, ok := h.openAIAPIKey(c)
+	if !ok {
+		return
+	}
+	job, err := h.batchService.GetBatch(c.Request.Context(), apiKey.UserID, service.BatchFormatOpenAI, c.Param("batch_id"))
+	if err != nil {
+		h.openAIServiceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, openAIBatchObject(job))
+}
+
+// CancelBatch handles POST /v1/batches/:batch_id/cancel
+func (h *BatchHandler) CancelBatch(c *gin.Context) {
+	apiKey, ok := h.openAIAPIKey(c)
+	if !ok {
+		return
+	}
+	job, err := h.batchService.CancelBatch(c.Request.Context(), apiKey.UserID, service.BatchFormatOpenAI, c.Param("batch_id"))
+	if err != nil {
+		h.openAIServiceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, openAIBatchObject(job))
+}
+
+// ==================== Anthropic Message Batches ====================
+
+// CreateMessageBatch handles POST /v1/messages/batches
+func (h *BatchHandler) CreateMessageBatch(c *gin.Context) {
+	apiKey, ok := h.anthropicAPIKey(c)
+	if !ok {
+		return
+	}
+	body, err := pkghttputil.ReadRequestBodyWithPrealloc(c.Request)
+	if err != nil {
+		if maxErr, ok := extractMaxBytesError(err); ok {
+			anthropicBatchError(c, http.StatusRequestEntityTooLarge, "request_too_large", buildBodyTooLargeMessage(maxErr.Limit))
+			return
+		}
+		anthropicBatchError(c, http.StatusBadRequest, "invalid_request_error", "Failed to read request body")
+		return
+	}
+	subscription, _ := middleware2.GetSubscriptionFromContext(c)
+	job, err := h.batchService.CreateAnthropicBatch(c.Request.Context(), apiKey, subscription, ip.GetClientIP(c), body)
+	if err != nil {
+		h.anthropicServiceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, anthropicBatchObject(job))
+}
+
+// ListMessageBatches handles GET /v1/messages/batches
+func (h *BatchHandler) ListMessageBatches(c *gin.Context) {
+	apiKey, ok := h.anthropicAPIKey(c)
+	if !ok {
+		return
+	}
+	limit := parseBatchListLimit(c.Query("limit"))
+	jobs, err := h.batchService.ListBatches(c.Request.Context(), apiKey.UserID, service.BatchFormatAnthropic, c.Query("after_id"), limit+1)
+	if err != nil {
+		h.anthropicServiceError(c, err)
+		return
+	}
+	hasMore := len(jobs) > limit
+	if hasMore {
+		jobs = jobs[:limit]
+	}
+	data := make([]gin.H, 0, len(jobs))
+	for _, job := range jobs {
+		data = append(data, anthropicBatchObject(job))
+	}
+	resp := gin.H{"data": data, "has_more": hasMore, "first_id": nil, "last_id": nil}
+	if len(jobs) > 0 {
+		resp["first_id"] = jobs[0].BatchID
+		resp["last_id"] = jobs[len(jobs)-1].BatchID
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// GetMessageBatch handles GET /v1/messages/batches/:batch_id
+func (h *BatchHandler) GetMessageBatch(c *gin.Context) {
+	apiKey, ok := h.anthropicAPIKey(c)
+	if !ok {
+		return
+	}
+	job, err := h.batchService.GetBatch(c.Request.Context(), apiKey.UserID, service.BatchFormatAnthropic, c.Param("batch_id"))
+	if err != nil {
+		h.anthropicServiceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, anthropicBatchObject(job))
+}
+
+// CancelMessageBatch handles POST /v1/messages/batches/:batch_id/cancel
+func (h *BatchHandler) CancelMessageBatch(c *gin.Context) {
+	apiKey, ok := h.anthropicAPIKey(c)
+	if !ok {
+		return
+	}
+	job, err := h.batchService.CancelBatch(c.Request.Context(), apiKey.UserID, service.BatchFormatAnthropic, c.Param("batch_id"))
+	if err != nil {
+		h.anthropicServiceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, anthropicBatchObject(job))
+}
+
+// GetMessageBatchResults handles GET /v1/messages/batches/:batch_id/results
+func (h *BatchHandler) GetMessageBatchResults(c *gin.Context) {
+	apiKey, ok := h.anthropicAPIKey(c)
+	if !ok {
+		return
+	}
+	file, err := h.batchService.GetResults(c.Request.Context(), apiKey.UserID, service.BatchFormatAnthropic, c.Param("batch_id"))
+	if err != nil {
+		h.anthropicServiceError(c, err)
+		return
+	}
+	c.Data(http.StatusOK, "application/binary", file.Content)
+}
+
+// ==================== Helpers ====================
+
+func (h *BatchHandler) openAIAPIKey(c *gin.Context) (*service.APIKey, bool) {
+	apiKey, ok := middleware2.GetAPIKeyFromContext(c)
+	if !ok {
+		openAIBatchError(c, http.StatusUnauthorized, "authentication_error", "Invalid API key")
+		return nil, false
+	}
+	return apiKey, true
+}
+
+func (h *BatchHandler) anthropicAPIKey(c *gin.Context) (*service.APIKey, bool) {
+	apiKey, ok := middleware2.GetAPIKeyFromContext(c)
+	if !ok {
+		anthropicBatchError(c, http.StatusUnauthorized, "authentication_error", "Invalid API key")
+		return nil, false
+	}
+	return apiKey, true
+}
+
+func (h *BatchHandler) openAIServiceError(c *gin.Context, err error) {
+	status, message := batchServiceErrorDetails(c, err)
+	errType := "invalid_request_error"
+	switch {
+	case status == http.StatusUnauthorized:
+		errType = "authentication_error"
+	case status >= 500:
+		errType = "api_error"
+	}
+	openAIBatchError(c, status, errType, message)
+}
+
+func (h *BatchHandler) anthropicServiceError(c *gin.Context, err error) {
+	status, message := batchServiceErrorDetails(c, err)
+	errType := "invalid_request_error"
+	switch {
+	case status == http.StatusUnauthorized:
+		errType = "authentication_error"
+	case status == http.StatusNotFound:
+		errType = "not_found_error"
+	case status == http.StatusRequestEntityTooLarge:
+		errType = "request_too_large"
+	case status >= 500:
+		errType = "api_error"
+	}
+	anthropicBatchError(c, status, errType, message)
+}
+
+func batchServiceErrorDetails(c *gin.Context, err error) (int, string) {
+	status := infraerrors.Code(err)
+	if status == http.StatusInternalServerError {
+		logger.L().With(zap.String("component", "handler.batch")).Error("batch.request_failed",
+			zap.String("path", c.Request.URL.Path),
+			zap.Error(err),
+		)
+		return status, "Internal server error"
+	}
+	return status, infraerrors.Message(err)
+}
+
+func openAIBatchError(c *gin.Context, status int, errType, message string) {
+	c.JSON(status, gin.H{
+		"error": gin.H{
+			"type":    errType,
+			"message": message,
+		},
+	})
+}
+
+func anthropicBatchError(c *gin.Context, status int, errType, message string) {
+	c.JSON(status, gin.H{
+		"type": "error",
+		"error": gin.H{
+			"type":    errType,
+			"message": message,
+		},
+	})
+}
+
+func parseBatchListLimit(raw string) int {
+	limit, err := strconv.Atoi(strings.TrimSpace(raw))
+	if err != nil || limit <= 0 {
+		return 20
+	}
+	if limit > 100 {
+		return 100
+	}
+	return limit
+}
+
+func unixOrNil(t *time.Time) any {
+	if t == nil {
+		return nil
+	}
+	return t.Unix()
+}
+
+func rfc3339OrNil(t *time.Time) any {
+	if t == nil {
+		return nil
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+func openAIFileObject(file *service.BatchFile) gin.H {
+	return gin.H{
+		"id":         file.FileID,
+		"object":     "file",
+		"bytes":      file.Bytes,
+		"created_at": file.CreatedAt.Unix(),
+		"expires_at": unixOrNil(file.ExpiresAt),
+		"filename":   file.Filename,
+		"purpose":    file.Purpose,
+		"status":     "processed",
+	}
+}
+
+func openAIBatchObject(job *service.BatchJob) gin.H {
+	var outputFileID, errorFileID any
+	if job.OutputFileID != "" {
+		outputFileID = job.OutputFileID
+	}
+	if job.ErrorFileID != "" {
+		errorFileID = job.ErrorFileID
+	}
+	var metadata any
+	if len(job.Metadata) > 0 {
+		metadata = job.Metadata
+	}
+	var batchErrors any
+	if job.ErrorMessage != "" {
+		batchErrors = gin.H{
+			"object": "list",
+			"data":   []gin.H{{"code": "batch_error", "message": job.ErrorMessage}},
+		}
+	}
+	return gin.H{
+		"id":                job.BatchID,
+		"object":            "batch",
+		"endpoint":          job.Endpoint,
+		"errors":            batchErrors,
+		"input_file_id":     job.InputFileID,
+		"completion_window": job.CompletionWindow,
+		"status":            job.Status,
+		"output_file_id":    outputFileID,
+		"error_file_id":     errorFileID,
+		"created_at":        job.CreatedAt.Unix(),
+		"in_progress_at":    unixOrNil(job.InProgressAt),
+		"expires_at":        job.ExpiresAt.Unix(),
+		"finalizing_at":     unixOrNil(job.FinalizingAt),
+		"completed_at":      unixOrNil(job.CompletedAt),
+		"failed_at":         nil,
+		"expired_at":        unixOrNil(job.ExpiredAt),
+		"cancelling_at":     unixOrNil(job.CancellingAt),
+		"cancelled_at":      unixOrNil(job.CancelledAt),
+		"request_counts": gin.H{
+			"total":     job.Counts.Total,
+			"completed": job.Counts.Succeeded,
+			"failed":    job.Counts.Failed + job.Counts.Cancelled + job.Counts.Expired,
+		},
+		"metadata": metadata,
+	}
+}
+
+func anthropicBatchObject(job *service.BatchJob) gin.H {
+	processingStatus := "in_progress"
+	switch job.Status {
+	case service.BatchStatusCancelling:
+		processingStatus = "canceling"
+	case service.BatchStatusCompleted, service.BatchStatusExpired, service.BatchStatusCancelled:
+		processingStatus = "ended"
+	}
+	var endedAt *time.Time
+	switch {
+	case job.CompletedAt != nil:
+		endedAt = job.CompletedAt
+	case job.ExpiredAt != nil:
+		endedAt = job.ExpiredAt
+	case job.CancelledAt != nil:
+		endedAt = job.CancelledAt
+	}
+	var resultsURL any
+	if processingStatus == "ended" {
+		resultsURL = "/v1/messages/batches/" + job.BatchID + "/results"
+	}
+	return gin.H{
+		"id":                  job.BatchID,
+		"type":                "message_batch",
+		"processing_status":   processingStatus,
+		"created_at":          job.CreatedAt.UTC().Format(time.RFC3339),
+		"expires_at":          job.ExpiresAt.UTC().Format(time.RFC3339),
+		"ended_at":            rfc3339OrNil(endedAt),
+		"cancel_initiated_at": rfc3339OrNil(job.CancellingAt),
+		"archived_at":         nil,
+		"results_url":         resultsURL,
+		"request_counts": gin.H{
+			"processing": job.Counts.Processing(),
+			"succeeded":  job.Counts.Succeeded,
+			"errored":    job.Counts.Failed,
+			"canceled":   job.Counts.Cancelled,
+			"expired":    job.Counts.Expired,
+		},
+	}
+}
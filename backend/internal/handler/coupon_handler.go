@@ -0,0 +1,84 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/Wei-Shaw/sub2api/internal/handler/dto"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/response"
+	middleware2 "github.com/Wei-Shaw/sub2api/internal/server/middleware"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CouponHandler handles the user's coupons
+type CouponHandler struct {
+	couponService  *service.CouponService
+	paymentService *service.PaymentService
+}
+
+// NewCouponHandler creates a new CouponHandler
+func NewCouponHandler(couponService *service.CouponService, paymentService *service.PaymentService) *CouponHandler {
+	return &CouponHandler{
+		couponService:  couponService,
+		paymentService: paymentService,
+	}
+}
+
+// ListCoupons lists the current user's coupons
+// GET /api/v1/coupons
+// Query params:
+//   - status: unused / locked / used / expired (default: all)
+func (h *CouponHandler) ListCoupons(c *gin.Context) {
+	subject, ok := middleware2.GetAuthSubjectFromContext(c)
+	if !ok {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	page, pageSize := response.ParsePagination(c)
+	params := pagination.PaginationParams{Page: page, PageSize: pageSize}
+	coupons, result, err := h.couponService.ListUserCoupons(c.Request.Context(), params, service.UserCouponFilter{
+		UserID: subject.UserID,
+		Status: c.Query("status"),
+	})
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+
+	out := make([]dto.UserCoupon, 0, len(coupons))
+	for i := range coupons {
+		out = append(out, *dto.UserCouponFromService(&coupons[i]))
+	}
+	response.Paginated(c, out, result.Total, page, pageSize)
+}
+
+// Quote returns the discounted price of a product when paying with the coupon
+// GET /api/v1/coupons/:id/quote?product_id=
+func (h *CouponHandler) Quote(c *gin.Context) {
+	subject, ok := middleware2.GetAuthSubjectFromContext(c)
+	if !ok {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	couponID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "Invalid coupon ID")
+		return
+	}
+	productID, err := strconv.ParseInt(c.Query("product_id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "Invalid product ID")
+		return
+	}
+
+	quote, err := h.paymentService.QuoteCoupon(c.Request.Context(), subject.UserID, couponID, productID)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, dto.CouponQuoteFromService(quote))
+}
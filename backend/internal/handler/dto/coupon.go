@@ -0,0 +1,137 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/service"
+)
+
+type Coupon struct {
+	ID             int64      `json:"id"`
+	Name           string     `json:"name"`
+	Description    string     `json:"description"`
+	DiscountType   string     `json:"discount_type"`    // percent / fixed
+	PercentOff     int        `json:"percent_off"`      // percent: 1-99
+	AmountOffCents int64      `json:"amount_off_cents"` // fixed: in the currency's minor unit
+	Currency       string     `json:"currency"`         // fixed: only applies to products priced in this currency
+	MinAmountCents int64      `json:"min_amount_cents"`
+	Scope          string     `json:"scope"` // all / balance / subscription / product / group
+	ScopeID        *int64     `json:"scope_id"`
+	ExpiresAt      *time.Time `json:"expires_at"`
+}
+
+// AdminCoupon adds management-only fields to Coupon
+type AdminCoupon struct {
+	Coupon
+	ValidDays   int       `json:"valid_days"` // 0 = no per-issue limit
+	Enabled     bool      `json:"enabled"`
+	IssuedCount int64     `json:"issued_count"`
+	UsedCount   int64     `json:"used_count"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type UserCoupon struct {
+	ID        int64      `json:"id"`
+	Status    string     `json:"status"` // unused / locked / used / expired
+	Source    string     `json:"source"`
+	ExpiresAt *time.Time `json:"expires_at"`
+	OrderNo   string     `json:"order_no"`
+	UsedAt    *time.Time `json:"used_at"`
+	CreatedAt time.Time  `json:"created_at"`
+	Coupon    *Coupon    `json:"coupon"`
+}
+
+// AdminUserCoupon adds the owner and issue reference to UserCoupon
+type AdminUserCoupon struct {
+	UserCoupon
+	CouponID  int64  `json:"coupon_id"`
+	UserID    int64  `json:"user_id"`
+	UserEmail string `json:"user_email"`
+	SourceRef string `json:"source_ref"`
+}
+
+// CouponQuote is the price of a product after applying a user coupon
+type CouponQuote struct {
+	UserCouponID  int64  `json:"user_coupon_id"`
+	OriginalCents int64  `json:"original_cents"`
+	DiscountCents int64  `json:"discount_cents"`
+	AmountCents   int64  `json:"amount_cents"`
+	Currency      string `json:"currency"`
+}
+
+func CouponFromService(c *service.Coupon) *Coupon {
+	if c == nil {
+		return nil
+	}
+	return &Coupon{
+		ID:             c.ID,
+		Name:           c.Name,
+		Description:    c.Description,
+		DiscountType:   c.DiscountType,
+		PercentOff:     c.PercentOff,
+		AmountOffCents: c.AmountOffCents,
+		Currency:       c.Currency,
+		MinAmountCents: c.MinAmountCents,
+		Scope:          c.Scope,
+		ScopeID:        c.ScopeID,
+		ExpiresAt:      c.ExpiresAt,
+	}
+}
+
+func AdminCouponFromService(c *service.Coupon) *AdminCoupon {
+	if c == nil {
+		return nil
+	}
+	return &AdminCoupon{
+		Coupon:      *CouponFromService(c),
+		ValidDays:   c.ValidDays,
+		Enabled:     c.Enabled,
+		IssuedCount: c.IssuedCount,
+		UsedCount:   c.UsedCount,
+		CreatedAt:   c.CreatedAt,
+		UpdatedAt:   c.UpdatedAt,
+	}
+}
+
+func UserCouponFromService(uc *service.UserCoupon) *UserCoupon {
+	if uc == nil {
+		return nil
+	}
+	return &UserCoupon{
+		ID:        uc.ID,
+		Status:    uc.DisplayStatus(time.Now()),
+		Source:    uc.Source,
+		ExpiresAt: uc.ExpiresAt,
+		OrderNo:   uc.OrderNo,
+		UsedAt:    uc.UsedAt,
+		CreatedAt: uc.CreatedAt,
+		Coupon:    CouponFromService(uc.Coupon),
+	}
+}
+
+func AdminUserCouponFromService(uc *service.UserCoupon) *AdminUserCoupon {
+	if uc == nil {
+		return nil
+	}
+	return &AdminUserCoupon{
+		UserCoupon: *UserCouponFromService(uc),
+		CouponID:   uc.CouponID,
+		UserID:     uc.UserID,
+		UserEmail:  uc.UserEmail,
+		SourceRef:  uc.SourceRef,
+	}
+}
+
+func CouponQuoteFromService(q *service.CouponQuote) *CouponQuote {
+	if q == nil {
+		return nil
+	}
+	return &CouponQuote{
+		UserCouponID:  q.UserCoupon.ID,
+		OriginalCents: q.OriginalCents,
+		DiscountCents: q.DiscountCents,
+		AmountCents:   q.AmountCents,
+		Currency:      q.Currency,
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
 	"github.com/Wei-Shaw/sub2api/internal/service"
 )
 
@@ -17,7 +18,7 @@ func UserFromServiceShallow(u *service.User) *User {
 		Email:         u.Email,
 		Username:      u.Username,
 		Role:          u.Role,
-		Balance:       u.Balance,
+		Balance:       u.Balance.Float64(),
 		Concurrency:   u.Concurrency,
 		Status:        u.Status,
 		AllowedGroups: u.AllowedGroups,
@@ -72,31 +73,37 @@ func APIKeyFromService(k *service.APIKey) *APIKey {
 		return nil
 	}
 	return &APIKey{
-		ID:            k.ID,
-		UserID:        k.UserID,
-		Key:           k.Key,
-		Name:          k.Name,
-		GroupID:       k.GroupID,
-		Status:        k.Status,
-		IPWhitelist:   k.IPWhitelist,
-		IPBlacklist:   k.IPBlacklist,
-		LastUsedAt:    k.LastUsedAt,
-		Quota:         k.Quota,
-		QuotaUsed:     k.QuotaUsed,
-		ExpiresAt:     k.ExpiresAt,
-		CreatedAt:     k.CreatedAt,
-		UpdatedAt:     k.UpdatedAt,
-		RateLimit5h:   k.RateLimit5h,
-		RateLimit1d:   k.RateLimit1d,
-		RateLimit7d:   k.RateLimit7d,
-		Usage5h:       k.Usage5h,
-		Usage1d:       k.Usage1d,
-		Usage7d:       k.Usage7d,
-		Window5hStart: k.Window5hStart,
-		Window1dStart: k.Window1dStart,
-		Window7dStart: k.Window7dStart,
-		User:          UserFromServiceShallow(k.User),
-		Group:         GroupFromServiceShallow(k.Group),
+		ID:              k.ID,
+		UserID:          k.UserID,
+		Key:             k.Key,
+		Name:            k.Name,
+		GroupID:         k.GroupID,
+		Status:          k.Status,
+		IPWhitelist:     k.IPWhitelist,
+		IPBlacklist:     k.IPBlacklist,
+		LastUsedAt:      k.LastUsedAt,
+		Quota:           k.Quota.Float64(),
+		QuotaUsed:       k.QuotaUsed.Float64(),
+		ExpiresAt:       k.ExpiresAt,
+		CreatedAt:       k.CreatedAt,
+		UpdatedAt:       k.UpdatedAt,
+		RateLimit5h:     k.RateLimit5h.Float64(),
+		RateLimit1d:     k.RateLimit1d.Float64(),
+		RateLimit7d:     k.RateLimit7d.Float64(),
+		Usage5h:         k.Usage5h.Float64(),
+		Usage1d:         k.Usage1d.Float64(),
+		Usage7d:         k.Usage7d.Float64(),
+		Window5hStart:   k.Window5hStart,
+		Window1dStart:   k.Window1dStart,
+		Window7dStart:   k.Window7dStart,
+		ModelAllowlist:  k.ModelAllowlist,
+		ModelDenylist:   k.ModelDenylist,
+		RPMLimit:        k.RPMLimit,
+		TPMLimit:        k.TPMLimit,
+		MaxConcurrency:  k.MaxConcurrency,
+		CreatedByUserID: k.CreatedByUserID,
+		User:            UserFromServiceShallow(k.User),
+		Group:           GroupFromServiceShallow(k.Group),
 	}
 }
 
@@ -150,16 +157,18 @@ func groupFromServiceBase(g *service.Group) Group {
 		IsExclusive:                     g.IsExclusive,
 		Status:                          g.Status,
 		SubscriptionType:                g.SubscriptionType,
-		DailyLimitUSD:                   g.DailyLimitUSD,
-		WeeklyLimitUSD:                  g.WeeklyLimitUSD,
-		MonthlyLimitUSD:                 g.MonthlyLimitUSD,
-		ImagePrice1K:                    g.ImagePrice1K,
-		ImagePrice2K:                    g.ImagePrice2K,
-		ImagePrice4K:                    g.ImagePrice4K,
-		SoraImagePrice360:               g.SoraImagePrice360,
-		SoraImagePrice540:               g.SoraImagePrice540,
-		SoraVideoPricePerRequest:        g.SoraVideoPricePerRequest,
-		SoraVideoPricePerRequestHD:      g.SoraVideoPricePerRequestHD,
+		DailyLimitUSD:                   amountToFloat64(g.DailyLimitUSD),
+		WeeklyLimitUSD:                  amountToFloat64(g.WeeklyLimitUSD),
+		MonthlyLimitUSD:                 amountToFloat64(g.MonthlyLimitUSD),
+		ImagePrice1K:                    amountToFloat64(g.ImagePrice1K),
+		ImagePrice2K:                    amountToFloat64(g.ImagePrice2K),
+		ImagePrice4K:                    amountToFloat64(g.ImagePrice4K),
+		SoraImagePrice360:               amountToFloat64(g.SoraImagePrice360),
+		SoraImagePrice540:               amountToFloat64(g.SoraImagePrice540),
+		SoraVideoPricePerRequest:        amountToFloat64(g.SoraVideoPricePerRequest),
+		SoraVideoPricePerRequestHD:      amountToFloat64(g.SoraVideoPricePerRequestHD),
+		AudioPricePerMinute:             amountToFloat64(g.AudioPricePerMinute),
+		AudioSpeechPricePer1MChars:      amountToFloat64(g.AudioSpeechPricePer1MChars),
 		ClaudeCodeOnly:                  g.ClaudeCodeOnly,
 		FallbackGroupID:                 g.FallbackGroupID,
 		FallbackGroupIDOnInvalidRequest: g.FallbackGroupIDOnInvalidRequest,
@@ -286,6 +295,14 @@ func timeToUnixSeconds(value *time.Time) *int64 {
 	return &ts
 }
 
+func amountToFloat64(value *money.Amount) *float64 {
+	if value == nil {
+		return nil
+	}
+	f := value.Float64()
+	return &f
+}
+
 func AccountGroupFromService(ag *service.AccountGroup) *AccountGroup {
 	if ag == nil {
 		return nil
@@ -423,7 +440,7 @@ func redeemCodeFromServiceBase(rc *service.RedeemCode) RedeemCode {
 		ID:           rc.ID,
 		Code:         rc.Code,
 		Type:         rc.Type,
-		Value:        rc.Value,
+		Value:        rc.Value.Float64(),
 		Status:       rc.Status,
 		UsedBy:       rc.UsedBy,
 		UsedAt:       rc.UsedAt,
@@ -475,12 +492,12 @@ func usageLogFromServiceUser(l *service.UsageLog) UsageLog {
 		CacheReadTokens:       l.CacheReadTokens,
 		CacheCreation5mTokens: l.CacheCreation5mTokens,
 		CacheCreation1hTokens: l.CacheCreation1hTokens,
-		InputCost:             l.InputCost,
-		OutputCost:            l.OutputCost,
-		CacheCreationCost:     l.CacheCreationCost,
-		CacheReadCost:         l.CacheReadCost,
-		TotalCost:             l.TotalCost,
-		ActualCost:            l.ActualCost,
+		InputCost:             l.InputCost.Float64(),
+		OutputCost:            l.OutputCost.Float64(),
+		CacheCreationCost:     l.CacheCreationCost.Float64(),
+		CacheReadCost:         l.CacheReadCost.Float64(),
+		TotalCost:             l.TotalCost.Float64(),
+		ActualCost:            l.ActualCost.Float64(),
 		RateMultiplier:        l.RateMultiplier,
 		BillingType:           l.BillingType,
 		RequestType:           requestType.String(),
@@ -491,6 +508,8 @@ func usageLogFromServiceUser(l *service.UsageLog) UsageLog {
 		ImageCount:            l.ImageCount,
 		ImageSize:             l.ImageSize,
 		MediaType:             l.MediaType,
+		AudioDurationSeconds:  l.AudioDurationSeconds,
+		AudioCharacters:       l.AudioCharacters,
 		UserAgent:             l.UserAgent,
 		CacheTTLOverridden:    l.CacheTTLOverridden,
 		CreatedAt:             l.CreatedAt,
@@ -610,9 +629,9 @@ func userSubscriptionFromServiceBase(sub *service.UserSubscription) UserSubscrip
 		DailyWindowStart:   sub.DailyWindowStart,
 		WeeklyWindowStart:  sub.WeeklyWindowStart,
 		MonthlyWindowStart: sub.MonthlyWindowStart,
-		DailyUsageUSD:      sub.DailyUsageUSD,
-		WeeklyUsageUSD:     sub.WeeklyUsageUSD,
-		MonthlyUsageUSD:    sub.MonthlyUsageUSD,
+		DailyUsageUSD:      sub.DailyUsageUSD.Float64(),
+		WeeklyUsageUSD:     sub.WeeklyUsageUSD.Float64(),
+		MonthlyUsageUSD:    sub.MonthlyUsageUSD.Float64(),
 		CreatedAt:          sub.CreatedAt,
 		UpdatedAt:          sub.UpdatedAt,
 		User:               UserFromServiceShallow(sub.User),
@@ -650,7 +669,7 @@ func PromoCodeFromService(pc *service.PromoCode) *PromoCode {
 	return &PromoCode{
 		ID:          pc.ID,
 		Code:        pc.Code,
-		BonusAmount: pc.BonusAmount,
+		BonusAmount: pc.BonusAmount.Float64(),
 		MaxUses:     pc.MaxUses,
 		UsedCount:   pc.UsedCount,
 		Status:      pc.Status,
@@ -658,6 +677,16 @@ func PromoCodeFromService(pc *service.PromoCode) *PromoCode {
 		Notes:       pc.Notes,
 		CreatedAt:   pc.CreatedAt,
 		UpdatedAt:   pc.UpdatedAt,
+
+		TopupBonusPercent:   pc.TopupBonusPercent,
+		TopupBonusMax:       pc.TopupBonusMax.Float64(),
+		MinTopupAmount:      pc.MinTopupAmount.Float64(),
+		PerUserLimit:        pc.PerUserLimit,
+		AllowedEmailDomains: pc.AllowedEmailDomains,
+		RequiredAttributes:  pc.RequiredAttributes,
+		Stackable:           pc.Stackable,
+		TrialGroupID:        pc.TrialGroupID,
+		TrialDays:           pc.TrialDays,
 	}
 }
 
@@ -669,8 +698,73 @@ func PromoCodeUsageFromService(u *service.PromoCodeUsage) *PromoCodeUsage {
 		ID:          u.ID,
 		PromoCodeID: u.PromoCodeID,
 		UserID:      u.UserID,
-		BonusAmount: u.BonusAmount,
+		BonusAmount: u.BonusAmount.Float64(),
 		UsedAt:      u.UsedAt,
 		User:        UserFromServiceShallow(u.User),
+
+		TopupBonusStatus: u.TopupBonusStatus,
+		TopupBonusAmount: u.TopupBonusAmount.Float64(),
+		TopupGrantedAt:   u.TopupGrantedAt,
+	}
+}
+
+func UserPromoCodeUsageFromService(u *service.PromoCodeUsage) *UserPromoCodeUsage {
+	if u == nil {
+		return nil
+	}
+	out := &UserPromoCodeUsage{
+		ID:               u.ID,
+		BonusAmount:      u.BonusAmount.Float64(),
+		TopupBonusStatus: u.TopupBonusStatus,
+		TopupBonusAmount: u.TopupBonusAmount.Float64(),
+		TopupGrantedAt:   u.TopupGrantedAt,
+		UsedAt:           u.UsedAt,
+	}
+	if pc := u.PromoCode; pc != nil {
+		out.Code = pc.Code
+		out.TopupBonusPercent = pc.TopupBonusPercent
+		out.TopupBonusMax = pc.TopupBonusMax.Float64()
+		out.MinTopupAmount = pc.MinTopupAmount.Float64()
+		out.TrialGroupID = pc.TrialGroupID
+		out.TrialDays = pc.TrialDays
+	}
+	return out
+}
+
+func BalanceTransactionFromService(e *service.BalanceLedgerEntry) *BalanceTransaction {
+	if e == nil {
+		return nil
 	}
+	out := balanceTransactionFromServiceBase(e)
+	return &out
+}
+
+// BalanceTransactionFromServiceAdmin converts a ledger entry to DTO for admin users.
+// It includes user, operator and notes - user-facing endpoints must not use this.
+func BalanceTransactionFromServiceAdmin(e *service.BalanceLedgerEntry) *AdminBalanceTransaction {
+	if e == nil {
+		return nil
+	}
+	return &AdminBalanceTransaction{
+		BalanceTransaction: balanceTransactionFromServiceBase(e),
+		UserID:             e.UserID,
+		OperatorID:         e.OperatorID,
+		Notes:              e.Notes,
+	}
+}
+
+func balanceTransactionFromServiceBase(e *service.BalanceLedgerEntry) BalanceTransaction {
+	out := BalanceTransaction{
+		ID:            e.ID,
+		SourceType:    e.SourceType,
+		ReferenceID:   e.ReferenceID,
+		Amount:        e.Amount.Float64(),
+		BalanceBefore: e.BalanceBefore.Float64(),
+		BalanceAfter:  e.BalanceAfter.Float64(),
+		CreatedAt:     e.CreatedAt,
+	}
+	if e.SourceType == service.BalanceLedgerSourceAdminAdjust && e.Notes != "" {
+		out.Notes = &e.Notes
+	}
+	return out
 }
@@ -0,0 +1,176 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/service"
+)
+
+type Organization struct {
+	ID        int64     `json:"id"`
+	Name      string    `json:"name"`
+	Role      string    `json:"role"` // current user's role
+	CreatedBy int64     `json:"created_by"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type OrganizationDetail struct {
+	Organization
+	AccountUserID int64              `json:"account_user_id"`
+	Balance       float64            `json:"balance"`
+	Concurrency   int                `json:"concurrency"`
+	Subscriptions []UserSubscription `json:"subscriptions"`
+}
+
+type OrganizationMember struct {
+	UserID            int64     `json:"user_id"`
+	Email             string    `json:"email"`
+	Username          string    `json:"username"`
+	Role              string    `json:"role"`
+	MonthlySpendLimit float64   `json:"monthly_spend_limit"` // USD per calendar month, 0 = unlimited
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+type OrganizationInvitation struct {
+	ID        int64     `json:"id"`
+	Email     string    `json:"email"`
+	Role      string    `json:"role"`
+	InvitedBy int64     `json:"invited_by"`
+	Status    string    `json:"status"`
+	ExpiresAt time.Time `json:"expires_at"`
+	CreatedAt time.Time `json:"created_at"`
+
+	// Token is only returned once, when the invitation is created
+	Token string `json:"token,omitempty"`
+}
+
+type OrganizationMemberUsage struct {
+	UserID              int64   `json:"user_id"`
+	Email               string  `json:"email"`
+	Username            string  `json:"username"`
+	Role                string  `json:"role"`
+	APIKeyCount         int64   `json:"api_key_count"`
+	TotalRequests       int64   `json:"total_requests"`
+	InputTokens         int64   `json:"input_tokens"`
+	OutputTokens        int64   `json:"output_tokens"`
+	CacheCreationTokens int64   `json:"cache_creation_tokens"`
+	CacheReadTokens     int64   `json:"cache_read_tokens"`
+	TotalCost           float64 `json:"total_cost"`
+	ActualCost          float64 `json:"actual_cost"`
+}
+
+type OrganizationDailyUsage struct {
+	Date          string  `json:"date"`
+	TotalRequests int64   `json:"total_requests"`
+	TotalTokens   int64   `json:"total_tokens"`
+	TotalCost     float64 `json:"total_cost"`
+	ActualCost    float64 `json:"actual_cost"`
+}
+
+type OrganizationUsage struct {
+	StartDate string                    `json:"start_date"`
+	EndDate   string                    `json:"end_date"`
+	Members   []OrganizationMemberUsage `json:"members"`
+	Daily     []OrganizationDailyUsage  `json:"daily"`
+}
+
+func OrganizationFromService(org *service.Organization, role string) *Organization {
+	if org == nil {
+		return nil
+	}
+	return &Organization{
+		ID:        org.ID,
+		Name:      org.Name,
+		Role:      role,
+		CreatedBy: org.CreatedBy,
+		CreatedAt: org.CreatedAt,
+		UpdatedAt: org.UpdatedAt,
+	}
+}
+
+func OrganizationDetailFromService(d *service.OrganizationDetail) *OrganizationDetail {
+	if d == nil {
+		return nil
+	}
+	subs := make([]UserSubscription, 0, len(d.Subscriptions))
+	for i := range d.Subscriptions {
+		subs = append(subs, *UserSubscriptionFromService(&d.Subscriptions[i]))
+	}
+	return &OrganizationDetail{
+		Organization:  *OrganizationFromService(&d.Organization, d.Role),
+		AccountUserID: d.AccountUserID,
+		Balance:       d.Balance.Float64(),
+		Concurrency:   d.Concurrency,
+		Subscriptions: subs,
+	}
+}
+
+func OrganizationMemberFromService(m *service.OrganizationMember) *OrganizationMember {
+	if m == nil {
+		return nil
+	}
+	return &OrganizationMember{
+		UserID:            m.UserID,
+		Email:             m.Email,
+		Username:          m.Username,
+		Role:              m.Role,
+		MonthlySpendLimit: m.MonthlySpendLimit.Float64(),
+		CreatedAt:         m.CreatedAt,
+		UpdatedAt:         m.UpdatedAt,
+	}
+}
+
+func OrganizationInvitationFromService(inv *service.OrganizationInvitation) *OrganizationInvitation {
+	if inv == nil {
+		return nil
+	}
+	return &OrganizationInvitation{
+		ID:        inv.ID,
+		Email:     inv.Email,
+		Role:      inv.Role,
+		InvitedBy: inv.InvitedBy,
+		Status:    inv.Status,
+		ExpiresAt: inv.ExpiresAt,
+		CreatedAt: inv.CreatedAt,
+	}
+}
+
+func OrganizationUsageFromService(u *service.OrganizationUsage) *OrganizationUsage {
+	if u == nil {
+		return nil
+	}
+	out := &OrganizationUsage{
+		StartDate: u.StartDate,
+		EndDate:   u.EndDate,
+		Members:   make([]OrganizationMemberUsage, 0, len(u.Members)),
+		Daily:     make([]OrganizationDailyUsage, 0, len(u.Daily)),
+	}
+	for _, m := range u.Members {
+		out.Members = append(out.Members, OrganizationMemberUsage{
+			UserID:              m.UserID,
+			Email:               m.Email,
+			Username:            m.Username,
+			Role:                m.Role,
+			APIKeyCount:         m.APIKeyCount,
+			TotalRequests:       m.TotalRequests,
+			InputTokens:         m.InputTokens,
+			OutputTokens:        m.OutputTokens,
+			CacheCreationTokens: m.CacheCreationTokens,
+			CacheReadTokens:     m.CacheReadTokens,
+			TotalCost:           m.TotalCost.Float64(),
+			ActualCost:          m.ActualCost.Float64(),
+		})
+	}
+	for _, d := range u.Daily {
+		out.Daily = append(out.Daily, OrganizationDailyUsage{
+			Date:          d.Date,
+			TotalRequests: d.TotalRequests,
+			TotalTokens:   d.TotalTokens,
+			TotalCost:     d.TotalCost.Float64(),
+			ActualCost:    d.ActualCost.Float64(),
+		})
+	}
+	return out
+}
@@ -0,0 +1,201 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/service"
+)
+
+type PaymentProduct struct {
+	ID            int64   `json:"id"`
+	Name          string  `json:"name"`
+	Description   string  `json:"description"`
+	Kind          string  `json:"kind"`
+	PriceCents    int64   `json:"price_cents"` // price in the currency's minor unit
+	Currency      string  `json:"currency"`
+	BalanceAmount float64 `json:"balance_amount"` // USD credited by top-up products
+	GroupID       *int64  `json:"group_id"`
+	ValidityDays  int     `json:"validity_days"`
+}
+
+// AdminPaymentProduct adds management-only fields to PaymentProduct
+type AdminPaymentProduct struct {
+	PaymentProduct
+	Enabled   bool      `json:"enabled"`
+	SortOrder int       `json:"sort_order"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type PaymentProvider struct {
+	Name    string   `json:"name"`
+	Methods []string `json:"methods"`
+}
+
+type PaymentOrder struct {
+	OrderNo       string     `json:"order_no"`
+	ProductID     *int64     `json:"product_id"`
+	ProductName   string     `json:"product_name"`
+	Kind          string     `json:"kind"`
+	BalanceAmount float64    `json:"balance_amount"`
+	GroupID       *int64     `json:"group_id"`
+	ValidityDays  int        `json:"validity_days"`
+	AmountCents   int64      `json:"amount_cents"` // amount to pay after the coupon discount
+	OriginalCents int64      `json:"original_cents"`
+	DiscountCents int64      `json:"discount_cents"`
+	UserCouponID  *int64     `json:"user_coupon_id"`
+	Currency      string     `json:"currency"`
+	Provider      string     `json:"provider"`
+	Method        string     `json:"method"`
+	Status        string     `json:"status"`
+	RefundedCents int64      `json:"refunded_cents"`
+	ExpiresAt     time.Time  `json:"expires_at"`
+	PaidAt        *time.Time `json:"paid_at"`
+	FulfilledAt   *time.Time `json:"fulfilled_at"`
+	RefundedAt    *time.Time `json:"refunded_at"`
+	ClosedAt      *time.Time `json:"closed_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+// PaymentCheckout is returned when an order is created; the client redirects to
+// pay_url or renders qr_code.
+type PaymentCheckout struct {
+	Order  PaymentOrder `json:"order"`
+	PayURL string       `json:"pay_url,omitempty"`
+	QRCode string       `json:"qr_code,omitempty"`
+}
+
+// AdminPaymentOrder adds provider and fulfillment details to PaymentOrder
+type AdminPaymentOrder struct {
+	PaymentOrder
+	ID              int64     `json:"id"`
+	UserID          int64     `json:"user_id"`
+	UserEmail       string    `json:"user_email"`
+	ProviderTradeNo string    `json:"provider_trade_no"`
+	RedeemCodeID    *int64    `json:"redeem_code_id"`
+	FulfillError    string    `json:"fulfill_error"`
+	RefundReason    string    `json:"refund_reason"`
+	ClientIP        string    `json:"client_ip"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+type PaymentReconciliation struct {
+	Rows        []service.PaymentReconciliationRow `json:"rows"`
+	Unfulfilled []AdminPaymentOrder                `json:"unfulfilled"`
+}
+
+func PaymentProductFromService(p *service.PaymentProduct) *PaymentProduct {
+	if p == nil {
+		return nil
+	}
+	return &PaymentProduct{
+		ID:            p.ID,
+		Name:          p.Name,
+		Description:   p.Description,
+		Kind:          p.Kind,
+		PriceCents:    p.PriceCents,
+		Currency:      p.Currency,
+		BalanceAmount: p.BalanceAmount.Float64(),
+		GroupID:       p.GroupID,
+		ValidityDays:  p.ValidityDays,
+	}
+}
+
+func AdminPaymentProductFromService(p *service.PaymentProduct) *AdminPaymentProduct {
+	if p == nil {
+		return nil
+	}
+	return &AdminPaymentProduct{
+		PaymentProduct: *PaymentProductFromService(p),
+		Enabled:        p.Enabled,
+		SortOrder:      p.SortOrder,
+		CreatedAt:      p.CreatedAt,
+		UpdatedAt:      p.UpdatedAt,
+	}
+}
+
+func PaymentProvidersFromService(providers []service.PaymentProviderInfo) []PaymentProvider {
+	out := make([]PaymentProvider, 0, len(providers))
+	for _, p := range providers {
+		methods := p.Methods
+		if methods == nil {
+			methods = []string{}
+		}
+		out = append(out, PaymentProvider{Name: p.Name, Methods: methods})
+	}
+	return out
+}
+
+func PaymentOrderFromService(o *service.PaymentOrder) *PaymentOrder {
+	if o == nil {
+		return nil
+	}
+	return &PaymentOrder{
+		OrderNo:       o.OrderNo,
+		ProductID:     o.ProductID,
+		ProductName:   o.ProductName,
+		Kind:          o.Kind,
+		BalanceAmount: o.BalanceAmount.Float64(),
+		GroupID:       o.GroupID,
+		ValidityDays:  o.ValidityDays,
+		AmountCents:   o.AmountCents,
+		OriginalCents: o.OriginalCents,
+		DiscountCents: o.DiscountCents,
+		UserCouponID:  o.UserCouponID,
+		Currency:      o.Currency,
+		Provider:      o.Provider,
+		Method:        o.Method,
+		Status:        o.Status,
+		RefundedCents: o.RefundedCents,
+		ExpiresAt:     o.ExpiresAt,
+		PaidAt:        o.PaidAt,
+		FulfilledAt:   o.FulfilledAt,
+		RefundedAt:    o.RefundedAt,
+		ClosedAt:      o.ClosedAt,
+		CreatedAt:     o.CreatedAt,
+	}
+}
+
+func AdminPaymentOrderFromService(o *service.PaymentOrder) *AdminPaymentOrder {
+	if o == nil {
+		return nil
+	}
+	return &AdminPaymentOrder{
+		PaymentOrder:    *PaymentOrderFromService(o),
+		ID:              o.ID,
+		UserID:          o.UserID,
+		UserEmail:       o.UserEmail,
+		ProviderTradeNo: o.ProviderTradeNo,
+		RedeemCodeID:    o.RedeemCodeID,
+		FulfillError:    o.FulfillError,
+		RefundReason:    o.RefundReason,
+		ClientIP:        o.ClientIP,
+		UpdatedAt:       o.UpdatedAt,
+	}
+}
+
+func PaymentCheckoutFromService(o *service.PaymentOrder, r *service.PaymentCreateResult) *PaymentCheckout {
+	out := &PaymentCheckout{Order: *PaymentOrderFromService(o)}
+	if r != nil {
+		out.PayURL = r.PayURL
+		out.QRCode = r.QRCode
+	}
+	return out
+}
+
+func PaymentReconciliationFromService(r *service.PaymentReconciliationReport) *PaymentReconciliation {
+	if r == nil {
+		return nil
+	}
+	out := &PaymentReconciliation{
+		Rows:        r.Rows,
+		Unfulfilled: make([]AdminPaymentOrder, 0, len(r.Unfulfilled)),
+	}
+	if out.Rows == nil {
+		out.Rows = []service.PaymentReconciliationRow{}
+	}
+	for i := range r.Unfulfilled {
+		out.Unfulfilled = append(out.Unfulfilled, *AdminPaymentOrderFromService(&r.Unfulfilled[i]))
+	}
+	return out
+}
@@ -0,0 +1,163 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/service"
+)
+
+type PointsAccount struct {
+	Balance     int64 `json:"balance"`
+	TotalEarned int64 `json:"total_earned"`
+	TotalSpent  int64 `json:"total_spent"`
+}
+
+type PointsLedgerEntry struct {
+	ID           int64     `json:"id"`
+	Delta        int64     `json:"delta"`
+	BalanceAfter int64     `json:"balance_after"`
+	SourceType   string    `json:"source_type"` // activity_reward / shop_exchange / admin_adjust
+	ReferenceID  string    `json:"reference_id"`
+	Notes        string    `json:"notes"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// AdminPointsLedgerEntry adds the owner and operator to PointsLedgerEntry
+type AdminPointsLedgerEntry struct {
+	PointsLedgerEntry
+	UserID     int64  `json:"user_id"`
+	OperatorID *int64 `json:"operator_id"`
+}
+
+type PointsShopItem struct {
+	ID            int64   `json:"id"`
+	Name          string  `json:"name"`
+	Description   string  `json:"description"`
+	Kind          string  `json:"kind"` // balance / subscription / redeem_code
+	PointsCost    int64   `json:"points_cost"`
+	BalanceAmount float64 `json:"balance_amount"`
+	GroupID       *int64  `json:"group_id"`
+	ValidityDays  int     `json:"validity_days"`
+	Stock         int     `json:"stock"`          // -1 = unlimited
+	PerUserLimit  int     `json:"per_user_limit"` // 0 = unlimited
+}
+
+// AdminPointsShopItem adds management-only fields to PointsShopItem
+type AdminPointsShopItem struct {
+	PointsShopItem
+	Enabled   bool      `json:"enabled"`
+	SortOrder int       `json:"sort_order"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type PointsExchange struct {
+	ID         int64     `json:"id"`
+	ItemID     *int64    `json:"item_id"`
+	ItemName   string    `json:"item_name"`
+	Kind       string    `json:"kind"`
+	PointsCost int64     `json:"points_cost"`
+	RedeemCode string    `json:"redeem_code,omitempty"` // set for redeem_code items
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// AdminPointsExchange adds the exchanging user to PointsExchange
+type AdminPointsExchange struct {
+	PointsExchange
+	UserID    int64  `json:"user_id"`
+	UserEmail string `json:"user_email"`
+}
+
+func PointsAccountFromService(a *service.PointsAccount) *PointsAccount {
+	if a == nil {
+		return nil
+	}
+	return &PointsAccount{
+		Balance:     a.Balance,
+		TotalEarned: a.TotalEarned,
+		TotalSpent:  a.TotalSpent,
+	}
+}
+
+func PointsLedgerEntryFromService(e *service.PointsLedgerEntry) *PointsLedgerEntry {
+	if e == nil {
+		return nil
+	}
+	return &PointsLedgerEntry{
+		ID:           e.ID,
+		Delta:        e.Delta,
+		BalanceAfter: e.BalanceAfter,
+		SourceType:   e.SourceType,
+		ReferenceID:  e.ReferenceID,
+		Notes:        e.Notes,
+		CreatedAt:    e.CreatedAt,
+	}
+}
+
+func AdminPointsLedgerEntryFromService(e *service.PointsLedgerEntry) *AdminPointsLedgerEntry {
+	if e == nil {
+		return nil
+	}
+	return &AdminPointsLedgerEntry{
+		PointsLedgerEntry: *PointsLedgerEntryFromService(e),
+		UserID:            e.UserID,
+		OperatorID:        e.OperatorID,
+	}
+}
+
+func PointsShopItemFromService(item *service.PointsShopItem) *PointsShopItem {
+	if item == nil {
+		return nil
+	}
+	return &PointsShopItem{
+		ID:            item.ID,
+		Name:          item.Name,
+		Description:   item.Description,
+		Kind:          item.Kind,
+		PointsCost:    item.PointsCost,
+		BalanceAmount: item.BalanceAmount.Float64(),
+		GroupID:       item.GroupID,
+		ValidityDays:  item.ValidityDays,
+		Stock:         item.Stock,
+		PerUserLimit:  item.PerUserLimit,
+	}
+}
+
+func AdminPointsShopItemFromService(item *service.PointsShopItem) *AdminPointsShopItem {
+	if item == nil {
+		return nil
+	}
+	return &AdminPointsShopItem{
+		PointsShopItem: *PointsShopItemFromService(item),
+		Enabled:        item.Enabled,
+		SortOrder:      item.SortOrder,
+		CreatedAt:      item.CreatedAt,
+		UpdatedAt:      item.UpdatedAt,
+	}
+}
+
+func PointsExchangeFromService(ex *service.PointsExchange) *PointsExchange {
+	if ex == nil {
+		return nil
+	}
+	return &PointsExchange{
+		ID:         ex.ID,
+		ItemID:     ex.ItemID,
+		ItemName:   ex.ItemName,
+		Kind:       ex.Kind,
+		PointsCost: ex.PointsCost,
+		RedeemCode: ex.RedeemCode,
+		CreatedAt:  ex.CreatedAt,
+	}
+}
+
+func AdminPointsExchangeFromService(ex *service.PointsExchange) *AdminPointsExchange {
+	if ex == nil {
+		return nil
+	}
+	return &AdminPointsExchange{
+		PointsExchange: *PointsExchangeFromService(ex),
+		UserID:         ex.UserID,
+		UserEmail:      ex.UserEmail,
+	}
+}
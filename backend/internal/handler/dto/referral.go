@@ -0,0 +1,255 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/service"
+)
+
+type ReferralTerms struct {
+	BonusAmount      float64 `json:"bonus_amount"`
+	BonusMinRecharge float64 `json:"bonus_min_recharge"`
+	CommissionBasis  string  `json:"commission_basis"` // recharge / usage
+	CommissionRate   float64 `json:"commission_rate"`
+	CommissionDays   int     `json:"commission_days"` // 0 = forever
+	Payout           string  `json:"payout"`          // balance / wallet
+	MinWithdrawal    float64 `json:"min_withdrawal"`
+}
+
+type ReferralWallet struct {
+	Balance        float64 `json:"balance"`
+	TotalEarned    float64 `json:"total_earned"`
+	TotalWithdrawn float64 `json:"total_withdrawn"`
+}
+
+type ReferralOverview struct {
+	Code             string         `json:"code"`
+	Link             string         `json:"link"`
+	Terms            ReferralTerms  `json:"terms"`
+	TotalReferrals   int64          `json:"total_referrals"`
+	ActiveReferrals  int64          `json:"active_referrals"`
+	FlaggedReferrals int64          `json:"flagged_referrals"`
+	TotalCommission  float64        `json:"total_commission"`
+	Wallet           ReferralWallet `json:"wallet"`
+}
+
+// ReferralReferee is a referred user as seen by the referrer (email masked)
+type ReferralReferee struct {
+	ID              int64      `json:"id"`
+	Email           string     `json:"email"`
+	Status          string     `json:"status"`
+	CommissionUntil *time.Time `json:"commission_until"`
+	BonusPaidAt     *time.Time `json:"bonus_paid_at"`
+	Recharge        float64    `json:"recharge"`
+	Usage           float64    `json:"usage"`
+	Commission      float64    `json:"commission"`
+	CreatedAt       time.Time  `json:"created_at"`
+}
+
+// AdminReferralRelation adds referrer, anti-abuse and settlement details to ReferralReferee
+type AdminReferralRelation struct {
+	ReferralReferee
+	ReferrerID        int64     `json:"referrer_id"`
+	ReferrerEmail     string    `json:"referrer_email"`
+	RefereeID         int64     `json:"referee_id"`
+	Code              string    `json:"code"`
+	FlagReason        string    `json:"flag_reason"`
+	RegisterIP        string    `json:"register_ip"`
+	RegisterUserAgent string    `json:"register_user_agent"`
+	SettledUntil      time.Time `json:"settled_until"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+type ReferralCommission struct {
+	ID           int64     `json:"id"`
+	RefereeEmail string    `json:"referee_email"`
+	Kind         string    `json:"kind"` // bonus / recharge / usage
+	BaseAmount   float64   `json:"base_amount"`
+	Rate         float64   `json:"rate"`
+	Amount       float64   `json:"amount"`
+	Payout       string    `json:"payout"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// AdminReferralCommission adds identifiers and the source reference to ReferralCommission
+type AdminReferralCommission struct {
+	ReferralCommission
+	RelationID int64  `json:"relation_id"`
+	ReferrerID int64  `json:"referrer_id"`
+	RefereeID  int64  `json:"referee_id"`
+	SourceRef  string `json:"source_ref"`
+}
+
+type ReferralWithdrawal struct {
+	ID          int64      `json:"id"`
+	Amount      float64    `json:"amount"`
+	Method      string     `json:"method"` // balance / manual
+	Account     string     `json:"account"`
+	Status      string     `json:"status"`
+	AdminNotes  string     `json:"admin_notes"`
+	ProcessedAt *time.Time `json:"processed_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// AdminReferralWithdrawal adds the requesting user and reviewer to ReferralWithdrawal
+type AdminReferralWithdrawal struct {
+	ReferralWithdrawal
+	UserID     int64  `json:"user_id"`
+	UserEmail  string `json:"user_email"`
+	OperatorID *int64 `json:"operator_id"`
+}
+
+type ReferralDailyStat struct {
+	Date       string  `json:"date"`
+	Signups    int64   `json:"signups"`
+	Recharge   float64 `json:"recharge"`
+	Usage      float64 `json:"usage"`
+	Commission float64 `json:"commission"`
+}
+
+type ReferralDashboard struct {
+	StartDate string              `json:"start_date"`
+	EndDate   string              `json:"end_date"`
+	Daily     []ReferralDailyStat `json:"daily"`
+}
+
+func ReferralOverviewFromService(o *service.ReferralOverview) *ReferralOverview {
+	if o == nil {
+		return nil
+	}
+	return &ReferralOverview{
+		Code: o.Code,
+		Link: o.Link,
+		Terms: ReferralTerms{
+			BonusAmount:      o.Terms.BonusAmount.Float64(),
+			BonusMinRecharge: o.Terms.BonusMinRecharge.Float64(),
+			CommissionBasis:  o.Terms.CommissionBasis,
+			CommissionRate:   o.Terms.CommissionRate,
+			CommissionDays:   o.Terms.CommissionDays,
+			Payout:           o.Terms.Payout,
+			MinWithdrawal:    o.Terms.MinWithdrawal.Float64(),
+		},
+		TotalReferrals:   o.Summary.TotalReferrals,
+		ActiveReferrals:  o.Summary.ActiveReferrals,
+		FlaggedReferrals: o.Summary.FlaggedReferrals,
+		TotalCommission:  o.Summary.TotalCommission.Float64(),
+		Wallet: ReferralWallet{
+			Balance:        o.Wallet.Balance.Float64(),
+			TotalEarned:    o.Wallet.TotalEarned.Float64(),
+			TotalWithdrawn: o.Wallet.TotalWithdrawn.Float64(),
+		},
+	}
+}
+
+func ReferralRefereeFromService(r *service.ReferralRelation) *ReferralReferee {
+	if r == nil {
+		return nil
+	}
+	return &ReferralReferee{
+		ID:              r.ID,
+		Email:           service.MaskEmail(r.RefereeEmail),
+		Status:          r.Status,
+		CommissionUntil: r.CommissionUntil,
+		BonusPaidAt:     r.BonusPaidAt,
+		Recharge:        r.RefereeRecharge.Float64(),
+		Usage:           r.RefereeUsage.Float64(),
+		Commission:      r.Commission.Float64(),
+		CreatedAt:       r.CreatedAt,
+	}
+}
+
+func AdminReferralRelationFromService(r *service.ReferralRelation) *AdminReferralRelation {
+	if r == nil {
+		return nil
+	}
+	referee := ReferralRefereeFromService(r)
+	referee.Email = r.RefereeEmail
+	return &AdminReferralRelation{
+		ReferralReferee:   *referee,
+		ReferrerID:        r.ReferrerID,
+		ReferrerEmail:     r.ReferrerEmail,
+		RefereeID:         r.RefereeID,
+		Code:              r.Code,
+		FlagReason:        r.FlagReason,
+		RegisterIP:        r.RegisterIP,
+		RegisterUserAgent: r.RegisterUserAgent,
+		SettledUntil:      r.SettledUntil,
+		UpdatedAt:         r.UpdatedAt,
+	}
+}
+
+func ReferralCommissionFromService(c *service.ReferralCommission) *ReferralCommission {
+	if c == nil {
+		return nil
+	}
+	return &ReferralCommission{
+		ID:           c.ID,
+		RefereeEmail: service.MaskEmail(c.RefereeEmail),
+		Kind:         c.Kind,
+		BaseAmount:   c.BaseAmount.Float64(),
+		Rate:         c.Rate,
+		Amount:       c.Amount.Float64(),
+		Payout:       c.Payout,
+		CreatedAt:    c.CreatedAt,
+	}
+}
+
+func AdminReferralCommissionFromService(c *service.ReferralCommission) *AdminReferralCommission {
+	if c == nil {
+		return nil
+	}
+	out := &AdminReferralCommission{
+		ReferralCommission: *ReferralCommissionFromService(c),
+		RelationID:         c.RelationID,
+		ReferrerID:         c.ReferrerID,
+		RefereeID:          c.RefereeID,
+		SourceRef:          c.SourceRef,
+	}
+	out.RefereeEmail = c.RefereeEmail
+	return out
+}
+
+func ReferralWithdrawalFromService(w *service.ReferralWithdrawal) *ReferralWithdrawal {
+	if w == nil {
+		return nil
+	}
+	return &ReferralWithdrawal{
+		ID:          w.ID,
+		Amount:      w.Amount.Float64(),
+		Method:      w.Method,
+		Account:     w.Account,
+		Status:      w.Status,
+		AdminNotes:  w.AdminNotes,
+		ProcessedAt: w.ProcessedAt,
+		CreatedAt:   w.CreatedAt,
+	}
+}
+
+func AdminReferralWithdrawalFromService(w *service.ReferralWithdrawal) *AdminReferralWithdrawal {
+	if w == nil {
+		return nil
+	}
+	return &AdminReferralWithdrawal{
+		ReferralWithdrawal: *ReferralWithdrawalFromService(w),
+		UserID:             w.UserID,
+		UserEmail:          w.UserEmail,
+		OperatorID:         w.OperatorID,
+	}
+}
+
+func ReferralDashboardFromService(d *service.ReferralDashboard) *ReferralDashboard {
+	if d == nil {
+		return nil
+	}
+	daily := make([]ReferralDailyStat, 0, len(d.Daily))
+	for _, s := range d.Daily {
+		daily = append(daily, ReferralDailyStat{
+			Date:       s.Date,
+			Signups:    s.Signups,
+			Recharge:   s.RefereeRecharge.Float64(),
+			Usage:      s.RefereeUsage.Float64(),
+			Commission: s.Commission.Float64(),
+		})
+	}
+	return &ReferralDashboard{StartDate: d.StartDate, EndDate: d.EndDate, Daily: daily}
+}
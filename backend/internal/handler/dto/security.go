@@ -0,0 +1,79 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/service"
+)
+
+type UserSession struct {
+	ID         string    `json:"id"`
+	IP         string    `json:"ip"`
+	UserAgent  string    `json:"user_agent"`
+	Device     string    `json:"device"`
+	Current    bool      `json:"current"` // session of the access token making this request
+	CreatedAt  time.Time `json:"created_at"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+type LoginHistory struct {
+	ID            int64     `json:"id"`
+	Success       bool      `json:"success"`
+	FailureReason string    `json:"failure_reason,omitempty"`
+	Method        string    `json:"method"`
+	IP            string    `json:"ip"`
+	UserAgent     string    `json:"user_agent"`
+	Device        string    `json:"device"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// AdminLoginHistory 是管理员接口使用的登录记录 DTO（包含用户与尝试登录的邮箱）。
+type AdminLoginHistory struct {
+	LoginHistory
+	UserID *int64 `json:"user_id"`
+	Email  string `json:"email"`
+}
+
+func UserSessionFromService(s *service.UserSession, currentSessionID string) *UserSession {
+	if s == nil {
+		return nil
+	}
+	return &UserSession{
+		ID:         s.ID,
+		IP:         s.IP,
+		UserAgent:  s.UserAgent,
+		Device:     s.Device,
+		Current:    currentSessionID != "" && s.ID == currentSessionID,
+		CreatedAt:  s.CreatedAt,
+		LastSeenAt: s.LastSeenAt,
+		ExpiresAt:  s.ExpiresAt,
+	}
+}
+
+func LoginHistoryFromService(e *service.LoginHistoryEntry) *LoginHistory {
+	if e == nil {
+		return nil
+	}
+	return &LoginHistory{
+		ID:            e.ID,
+		Success:       e.Success,
+		FailureReason: e.FailureReason,
+		Method:        e.Method,
+		IP:            e.IP,
+		UserAgent:     e.UserAgent,
+		Device:        e.Device,
+		CreatedAt:     e.CreatedAt,
+	}
+}
+
+func LoginHistoryFromServiceAdmin(e *service.LoginHistoryEntry) *AdminLoginHistory {
+	if e == nil {
+		return nil
+	}
+	return &AdminLoginHistory{
+		LoginHistory: *LoginHistoryFromService(e),
+		UserID:       e.UserID,
+		Email:        e.Email,
+	}
+}
@@ -60,6 +60,18 @@ type APIKey struct {
 	Window1dStart *time.Time `json:"window_1d_start"`
 	Window7dStart *time.Time `json:"window_7d_start"`
 
+	// Model restriction fields (glob patterns)
+	ModelAllowlist []string `json:"model_allowlist"`
+	ModelDenylist  []string `json:"model_denylist"`
+
+	// Traffic limit fields (0 = unlimited)
+	RPMLimit       int `json:"rpm_limit"`
+	TPMLimit       int `json:"tpm_limit"`
+	MaxConcurrency int `json:"max_concurrency"`
+
+	// Organization member who created the key (nil = personal key)
+	CreatedByUserID *int64 `json:"created_by_user_id,omitempty"`
+
 	User  *User  `json:"user,omitempty"`
 	Group *Group `json:"group,omitempty"`
 }
@@ -89,6 +101,10 @@ type Group struct {
 	SoraVideoPricePerRequest   *float64 `json:"sora_video_price_per_request"`
 	SoraVideoPricePerRequestHD *float64 `json:"sora_video_price_per_request_hd"`
 
+	// 音频计费配置
+	AudioPricePerMinute        *float64 `json:"audio_price_per_minute"`
+	AudioSpeechPricePer1MChars *float64 `json:"audio_speech_price_per_1m_chars"`
+
 	// Claude Code 客户端限制
 	ClaudeCodeOnly  bool   `json:"claude_code_only"`
 	FallbackGroupID *int64 `json:"fallback_group_id"`
@@ -347,6 +363,10 @@ type UsageLog struct {
 	ImageSize  *string `json:"image_size"`
 	MediaType  *string `json:"media_type"`
 
+	// 音频计量字段
+	AudioDurationSeconds *float64 `json:"audio_duration_seconds"`
+	AudioCharacters      *int     `json:"audio_characters"`
+
 	// User-Agent
 	UserAgent *string `json:"user_agent"`
 
@@ -475,6 +495,16 @@ type PromoCode struct {
 	Notes       string     `json:"notes"`
 	CreatedAt   time.Time  `json:"created_at"`
 	UpdatedAt   time.Time  `json:"updated_at"`
+
+	TopupBonusPercent   float64           `json:"topup_bonus_percent"`
+	TopupBonusMax       float64           `json:"topup_bonus_max"`
+	MinTopupAmount      float64           `json:"min_topup_amount"`
+	PerUserLimit        int               `json:"per_user_limit"`
+	AllowedEmailDomains []string          `json:"allowed_email_domains"`
+	RequiredAttributes  map[string]string `json:"required_attributes"`
+	Stackable           bool              `json:"stackable"`
+	TrialGroupID        *int64            `json:"trial_group_id"`
+	TrialDays           int               `json:"trial_days"`
 }
 
 // PromoCodeUsage 优惠码使用记录
@@ -485,5 +515,48 @@ type PromoCodeUsage struct {
 	BonusAmount float64   `json:"bonus_amount"`
 	UsedAt      time.Time `json:"used_at"`
 
+	TopupBonusStatus string     `json:"topup_bonus_status"`
+	TopupBonusAmount float64    `json:"topup_bonus_amount"`
+	TopupGrantedAt   *time.Time `json:"topup_granted_at"`
+
 	User *User `json:"user,omitempty"`
 }
+
+// UserPromoCodeUsage 用户可见的优惠码使用记录，不含使用限制等规则配置
+type UserPromoCodeUsage struct {
+	ID                int64      `json:"id"`
+	Code              string     `json:"code"`
+	BonusAmount       float64    `json:"bonus_amount"`
+	TopupBonusPercent float64    `json:"topup_bonus_percent"`
+	TopupBonusMax     float64    `json:"topup_bonus_max"`
+	MinTopupAmount    float64    `json:"min_topup_amount"`
+	TopupBonusStatus  string     `json:"topup_bonus_status"`
+	TopupBonusAmount  float64    `json:"topup_bonus_amount"`
+	TopupGrantedAt    *time.Time `json:"topup_granted_at"`
+	TrialGroupID      *int64     `json:"trial_group_id"`
+	TrialDays         int        `json:"trial_days"`
+	UsedAt            time.Time  `json:"used_at"`
+}
+
+// BalanceTransaction 用户可见的余额流水
+type BalanceTransaction struct {
+	ID            int64     `json:"id"`
+	SourceType    string    `json:"source_type"`
+	ReferenceID   string    `json:"reference_id"`
+	Amount        float64   `json:"amount"`
+	BalanceBefore float64   `json:"balance_before"`
+	BalanceAfter  float64   `json:"balance_after"`
+	CreatedAt     time.Time `json:"created_at"`
+
+	// Notes is only populated for admin_adjust entries so users can see
+	// why they were charged or credited by admin
+	Notes *string `json:"notes,omitempty"`
+}
+
+// AdminBalanceTransaction 是管理员接口使用的余额流水 DTO（包含用户、操作人与备注）。
+type AdminBalanceTransaction struct {
+	BalanceTransaction
+	UserID     int64  `json:"user_id"`
+	OperatorID *int64 `json:"operator_id"`
+	Notes      string `json:"notes"`
+}
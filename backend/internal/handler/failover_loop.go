@@ -6,7 +6,10 @@ import (
 	"time"
 
 	"github.com/Wei-Shaw/sub2api/internal/pkg/logger"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/tracing"
 	"github.com/Wei-Shaw/sub2api/internal/service"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 )
 
@@ -28,6 +31,20 @@ const (
 	FailoverCanceled
 )
 
+// String 返回动作名称（用于链路追踪属性）
+func (a FailoverAction) String() string {
+	switch a {
+	case FailoverContinue:
+		return "continue"
+	case FailoverExhausted:
+		return "exhausted"
+	case FailoverCanceled:
+		return "canceled"
+	default:
+		return "unknown"
+	}
+}
+
 const (
 	// maxSameAccountRetries 同账号重试次数上限（针对 RetryableOnSameAccount 错误）
 	maxSameAccountRetries = 2
@@ -68,7 +85,22 @@ func (s *FailoverState) HandleFailoverError(
 	accountID int64,
 	platform string,
 	failoverErr *service.UpstreamFailoverError,
-) FailoverAction {
+) (action FailoverAction) {
+	ctx, span := tracing.Start(ctx, "gateway.failover", trace.WithAttributes(
+		attribute.Int64("account.id", accountID),
+		attribute.String("platform", platform),
+		attribute.Int("upstream.status_code", failoverErr.StatusCode),
+		attribute.Bool("failover.retryable_on_same_account", failoverErr.RetryableOnSameAccount),
+	))
+	defer func() {
+		span.SetAttributes(
+			attribute.String("failover.action", action.String()),
+			attribute.Int("failover.switch_count", s.SwitchCount),
+			attribute.Int("failover.same_account_retry_count", s.SameAccountRetryCount[accountID]),
+		)
+		span.End()
+	}()
+
 	s.LastFailoverErr = failoverErr
 
 	// 缓存计费判断
@@ -131,7 +163,16 @@ func (s *FailoverState) HandleFailoverError(
 // 返回 FailoverContinue 时，调用方应设置 SingleAccountRetry context 并 continue。
 // 返回 FailoverExhausted 时，调用方应返回错误响应。
 // 返回 FailoverCanceled 时，调用方应直接 return。
-func (s *FailoverState) HandleSelectionExhausted(ctx context.Context) FailoverAction {
+func (s *FailoverState) HandleSelectionExhausted(ctx context.Context) (action FailoverAction) {
+	ctx, span := tracing.Start(ctx, "gateway.failover_selection_exhausted")
+	defer func() {
+		span.SetAttributes(
+			attribute.String("failover.action", action.String()),
+			attribute.Int("failover.switch_count", s.SwitchCount),
+		)
+		span.End()
+	}()
+
 	if s.LastFailoverErr != nil &&
 		s.LastFailoverErr.StatusCode == http.StatusServiceUnavailable &&
 		s.SwitchCount <= s.MaxSwitches {
@@ -160,6 +201,15 @@ func needForceCacheBilling(hasBoundSession bool, failoverErr *service.UpstreamFa
 	return hasBoundSession || (failoverErr != nil && failoverErr.ForceCacheBilling)
 }
 
+// recordFailoverSwitch 在当前请求 Span 上记录一次换号事件，供未使用 FailoverState 的处理器调用。
+func recordFailoverSwitch(ctx context.Context, accountID int64, upstreamStatus int, switchCount int) {
+	trace.SpanFromContext(ctx).AddEvent("gateway.failover_switch_account", trace.WithAttributes(
+		attribute.Int64("account.id", accountID),
+		attribute.Int("upstream.status_code", upstreamStatus),
+		attribute.Int("failover.switch_count", switchCount),
+	))
+}
+
 // sleepWithContext 等待指定时长，返回 false 表示 context 已取消。
 func sleepWithContext(ctx context.Context, d time.Duration) bool {
 	if d <= 0 {
@@ -0,0 +1,176 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/Wei-Shaw/sub2api/internal/pkg/apicompat"
+	pkghttputil "github.com/Wei-Shaw/sub2api/internal/pkg/httputil"
+	middleware2 "github.com/Wei-Shaw/sub2api/internal/server/middleware"
+	"github.com/gin-gonic/gin"
+	"github.com/tidwall/gjson"
+)
+
+// ChatCompletions handles OpenAI Chat Completions requests routed to
+// Anthropic-compatible groups.
+// POST /v1/chat/completions (when group platform is not OpenAI)
+//
+// The request is converted to Anthropic Messages format and served by the
+// regular Messages pipeline (scheduling, failover, billing); the response is
+// translated back on the fly by chatCompletionsConverter. Gemini models on
+// Gemini/Antigravity groups are instead translated straight to
+// generateContent (see useGeminiNativeCompat).
+func (h *GatewayHandler) ChatCompletions(c *gin.Context) {
+	body, err := pkghttputil.ReadRequestBodyWithPrealloc(c.Request)
+	if err != nil {
+		if maxErr, ok := extractMaxBytesError(err); ok {
+			h.openAIErrorResponse(c, http.StatusRequestEntityTooLarge, "invalid_request_error", buildBodyTooLargeMessage(maxErr.Limit))
+			return
+		}
+		h.openAIErrorResponse(c, http.StatusBadRequest, "invalid_request_error", "Failed to read request body")
+		return
+	}
+	if len(body) == 0 {
+		h.openAIErrorResponse(c, http.StatusBadRequest, "invalid_request_error", "Request body is empty")
+		return
+	}
+
+	var chatReq apicompat.ChatCompletionsRequest
+	if err := json.Unmarshal(body, &chatReq); err != nil {
+		h.openAIErrorResponse(c, http.StatusBadRequest, "invalid_request_error", "Failed to parse request body")
+		return
+	}
+	if chatReq.Model == "" {
+		h.openAIErrorResponse(c, http.StatusBadRequest, "invalid_request_error", "model is required")
+		return
+	}
+	if len(chatReq.Messages) == 0 {
+		h.openAIErrorResponse(c, http.StatusBadRequest, "invalid_request_error", "messages is required")
+		return
+	}
+
+	includeUsage := chatReq.StreamOptions != nil && chatReq.StreamOptions.IncludeUsage
+	apiKey, _ := middleware2.GetAPIKeyFromContext(c)
+	if useGeminiNativeCompat(c, apiKey, chatReq.Model) {
+		geminiReq, err := apicompat.ChatCompletionsToGemini(&chatReq)
+		if err != nil {
+			h.openAIErrorResponse(c, http.StatusBadRequest, "invalid_request_error", "Failed to convert request: "+err.Error())
+			return
+		}
+		originalWriter := c.Writer
+		conv := newGeminiCompatConverter(newChatCompletionsConverter(chatReq.Model, includeUsage), chatReq.Model)
+		w := newAnthropicCompatWriter(originalWriter, conv)
+		c.Writer = w
+		defer func() {
+			w.finish()
+			if c.Writer == w {
+				c.Writer = originalWriter
+			}
+		}()
+
+		h.forwardGeminiCompat(c, chatReq.Model, chatReq.Stream, geminiReq)
+		return
+	}
+
+	anthropicReq, err := apicompat.ChatCompletionsToAnthropic(&chatReq)
+	if err != nil {
+		h.openAIErrorResponse(c, http.StatusBadRequest, "invalid_request_error", "Failed to convert request: "+err.Error())
+		return
+	}
+	converted, err := json.Marshal(anthropicReq)
+	if err != nil {
+		h.openAIErrorResponse(c, http.StatusInternalServerError, "api_error", "Failed to convert request")
+		return
+	}
+
+	replaceRequestBody(c, converted)
+
+	originalWriter := c.Writer
+	w := newChatCompletionsWriter(originalWriter, chatReq.Model, includeUsage)
+	c.Writer = w
+	defer func() {
+		w.finish()
+		if c.Writer == w {
+			c.Writer = originalWriter
+		}
+	}()
+
+	h.Messages(c)
+}
+
+// chatCompletionsConverter translates Anthropic Messages output (SSE events,
+// JSON bodies and errors) into Chat Completions format.
+type chatCompletionsConverter struct {
+	state *apicompat.AnthropicEventToChatState
+	// streamFailed is set once an error event has been relayed, so the
+	// stream is not terminated with a synthetic finish chunk and [DONE].
+	streamFailed bool
+}
+
+func newChatCompletionsConverter(model string, includeUsage bool) *chatCompletionsConverter {
+	state := apicompat.NewAnthropicEventToChatState()
+	state.Model = model
+	state.IncludeUsage = includeUsage
+	return &chatCompletionsConverter{state: state}
+}
+
+func newChatCompletionsWriter(rw gin.ResponseWriter, model string, includeUsage bool) *anthropicCompatWriter {
+	return newAnthropicCompatWriter(rw, newChatCompletionsConverter(model, includeUsage))
+}
+
+func (cv *chatCompletionsConverter) streamPayload(payload string) string {
+	switch gjson.Get(payload, "type").String() {
+	case "ping":
+		return string(SSEPingFormatComment)
+	case "error":
+		cv.streamFailed = true
+		errJSON, _ := json.Marshal(anthropicErrorToOpenAI([]byte(payload)))
+		return "data: " + string(errJSON) + "\n\n"
+	}
+
+	var evt apicompat.AnthropicStreamEvent
+	if err := json.Unmarshal([]byte(payload), &evt); err != nil {
+		return ""
+	}
+	return chatChunksToSSE(apicompat.AnthropicEventToChatChunks(&evt, cv.state))
+}
+
+func (cv *chatCompletionsConverter) streamEnd() string {
+	if cv.streamFailed {
+		return ""
+	}
+	out := chatChunksToSSE(apicompat.FinalizeAnthropicChatStream(cv.state))
+	if cv.state.Finished {
+		out += "data: [DONE]\n\n"
+	}
+	return out
+}
+
+func (cv *chatCompletionsConverter) convertBody(status int, body []byte) []byte {
+	if status >= http.StatusBadRequest {
+		if converted, err := json.Marshal(anthropicErrorToOpenAI(body)); err == nil {
+			return converted
+		}
+		return body
+	}
+	var resp apicompat.AnthropicResponse
+	if err := json.Unmarshal(body, &resp); err == nil && resp.Type == "message" {
+		if converted, err := json.Marshal(apicompat.AnthropicToChatCompletions(&resp, cv.state.Model)); err == nil {
+			return converted
+		}
+	}
+	return body
+}
+
+func chatChunksToSSE(chunks []apicompat.ChatCompletionsChunk) string {
+	var out strings.Builder
+	for _, chunk := range chunks {
+		sse, err := apicompat.ChatCompletionsChunkToSSE(chunk)
+		if err != nil {
+			continue
+		}
+		out.WriteString(sse)
+	}
+	return out.String()
+}
@@ -0,0 +1,109 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+func newChatCompletionsWriterTestContext(includeUsage bool) (*gin.Context, *httptest.ResponseRecorder, *anthropicCompatWriter) {
+	gin.SetMode(gin.TestMode)
+	rec := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(rec)
+	c.Request = httptest.NewRequest(http.MethodPost, "/v1/chat/completions", nil)
+	w := newChatCompletionsWriter(c.Writer, "claude-sonnet-4-5", includeUsage)
+	c.Writer = w
+	return c, rec, w
+}
+
+func TestChatCompletionsWriter_StreamConversion(t *testing.T) {
+	c, rec, w := newChatCompletionsWriterTestContext(true)
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Writer.WriteHeader(http.StatusOK)
+	events := []string{
+		"event: message_start\ndata: {\"type\":\"message_start\",\"message\":{\"id\":\"msg_1\",\"model\":\"claude-sonnet-4-5\",\"usage\":{\"input_tokens\":12,\"output_tokens\":1}}}\n\n",
+		"data: {\"type\": \"ping\"}\n\n",
+		"event: content_block_start\ndata: {\"type\":\"content_block_start\",\"index\":0,\"content_block\":{\"type\":\"text\",\"text\":\"\"}}\n\n",
+		// Split a line across writes to exercise partial-line buffering.
+		"event: content_block_delta\ndata: {\"type\":\"content_block_delta\",\"index\":0,",
+		"\"delta\":{\"type\":\"text_delta\",\"text\":\"Hi\"}}\n\n",
+		"event: message_delta\ndata: {\"type\":\"message_delta\",\"delta\":{\"stop_reason\":\"end_turn\"},\"usage\":{\"output_tokens\":4}}\n\n",
+		"event: message_stop\ndata: {\"type\":\"message_stop\"}\n\n",
+	}
+	for _, e := range events {
+		_, err := c.Writer.WriteString(e)
+		require.NoError(t, err)
+		c.Writer.Flush()
+	}
+	w.finish()
+
+	body := rec.Body.String()
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.NotContains(t, body, "event:")
+	require.Contains(t, body, ":\n\n")
+	require.Contains(t, body, `"role":"assistant"`)
+	require.Contains(t, body, `"content":"Hi"`)
+	require.Contains(t, body, `"finish_reason":"stop"`)
+	require.Contains(t, body, `"prompt_tokens":12`)
+	require.Contains(t, body, `"completion_tokens":4`)
+	require.True(t, strings.HasSuffix(body, "data: [DONE]\n\n"))
+}
+
+func TestChatCompletionsWriter_StreamErrorEvent(t *testing.T) {
+	c, rec, w := newChatCompletionsWriterTestContext(false)
+
+	c.Header("Content-Type", "text/event-stream")
+	_, _ = c.Writer.WriteString("data: {\"type\": \"ping\"}\n\n")
+	_, _ = c.Writer.WriteString(`data: {"type":"error","error":{"type":"overloaded_error","message":"busy"}}` + "\n\n")
+	w.finish()
+
+	body := rec.Body.String()
+	require.Contains(t, body, `data: {"error":{"message":"busy","type":"overloaded_error"}}`)
+	require.NotContains(t, body, "[DONE]")
+}
+
+func TestChatCompletionsWriter_NonStreamConversion(t *testing.T) {
+	c, rec, w := newChatCompletionsWriterTestContext(false)
+
+	c.JSON(http.StatusOK, gin.H{
+		"id":          "msg_2",
+		"type":        "message",
+		"role":        "assistant",
+		"model":       "claude-sonnet-4-5-20250929",
+		"content":     []gin.H{{"type": "text", "text": "Hello"}},
+		"stop_reason": "end_turn",
+		"usage":       gin.H{"input_tokens": 3, "output_tokens": 2},
+	})
+	require.True(t, c.Writer.Written())
+	require.Equal(t, 0, rec.Body.Len())
+	w.finish()
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	var resp map[string]any
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	require.Equal(t, "chat.completion", resp["object"])
+	require.Equal(t, "claude-sonnet-4-5", resp["model"])
+	choice := resp["choices"].([]any)[0].(map[string]any)
+	require.Equal(t, "Hello", choice["message"].(map[string]any)["content"])
+	require.Equal(t, "stop", choice["finish_reason"])
+}
+
+func TestChatCompletionsWriter_ErrorConversion(t *testing.T) {
+	c, rec, w := newChatCompletionsWriterTestContext(false)
+
+	c.JSON(http.StatusTooManyRequests, gin.H{
+		"type":  "error",
+		"error": gin.H{"type": "rate_limit_error", "message": "slow down"},
+	})
+	require.Equal(t, http.StatusTooManyRequests, c.Writer.Status())
+	w.finish()
+
+	require.Equal(t, http.StatusTooManyRequests, rec.Code)
+	require.JSONEq(t, `{"error":{"type":"rate_limit_error","message":"slow down"}}`, rec.Body.String())
+}
@@ -0,0 +1,229 @@
+package handler
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tidwall/gjson"
+)
+
+// anthropicCompatConverter translates Anthropic Messages output into another
+// API's wire format. It is driven by anthropicCompatWriter.
+type anthropicCompatConverter interface {
+	// streamPayload converts one SSE data payload (ping and error events
+	// included) and returns the bytes to emit, if any.
+	streamPayload(payload string) string
+	// streamEnd returns trailing output once the pipeline has returned.
+	streamEnd() string
+	// convertBody converts a buffered (non-SSE) response body.
+	convertBody(status int, body []byte) []byte
+}
+
+type anthropicCompatWriterMode int
+
+const (
+	anthropicCompatWriterPending anthropicCompatWriterMode = iota
+	anthropicCompatWriterStream
+	anthropicCompatWriterBuffered
+)
+
+// anthropicCompatWriter wraps the gin writer so OpenAI-style endpoints can be
+// served by the regular Messages pipeline (scheduling, failover, billing).
+// SSE responses are converted line by line as they are written; everything
+// else is buffered and converted once the handler returns.
+//
+// When detached is set (the connection was hijacked for a WebSocket), nothing
+// is ever written to the underlying writer; the converter delivers output
+// itself.
+type anthropicCompatWriter struct {
+	gin.ResponseWriter
+	conv     anthropicCompatConverter
+	detached bool
+	mode     anthropicCompatWriterMode
+	status   int
+	size     int
+	buf      bytes.Buffer
+}
+
+func newAnthropicCompatWriter(rw gin.ResponseWriter, conv anthropicCompatConverter) *anthropicCompatWriter {
+	return &anthropicCompatWriter{
+		ResponseWriter: rw,
+		conv:           conv,
+		status:         http.StatusOK,
+	}
+}
+
+func (w *anthropicCompatWriter) WriteHeader(code int) {
+	if w.mode == anthropicCompatWriterPending && code > 0 {
+		w.status = code
+	}
+}
+
+func (w *anthropicCompatWriter) WriteHeaderNow() {
+	w.decideMode()
+}
+
+func (w *anthropicCompatWriter) Status() int {
+	return w.status
+}
+
+func (w *anthropicCompatWriter) Size() int {
+	if w.mode == anthropicCompatWriterPending {
+		return -1
+	}
+	return w.size
+}
+
+func (w *anthropicCompatWriter) Written() bool {
+	return w.mode != anthropicCompatWriterPending
+}
+
+func (w *anthropicCompatWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+func (w *anthropicCompatWriter) Write(b []byte) (int, error) {
+	w.decideMode()
+	w.size += len(b)
+	w.buf.Write(b)
+	if w.mode != anthropicCompatWriterStream {
+		return len(b), nil
+	}
+	if err := w.convertBufferedLines(); err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
+
+func (w *anthropicCompatWriter) Flush() {
+	if w.mode == anthropicCompatWriterPending && w.isEventStream() {
+		w.decideMode()
+	}
+	if w.mode == anthropicCompatWriterStream && !w.detached {
+		w.ResponseWriter.Flush()
+	}
+}
+
+func (w *anthropicCompatWriter) isEventStream() bool {
+	return w.status < http.StatusBadRequest &&
+		strings.HasPrefix(w.Header().Get("Content-Type"), "text/event-stream")
+}
+
+// decideMode picks streaming or buffered conversion on the first write, based
+// on the status code and Content-Type set by the Messages pipeline.
+func (w *anthropicCompatWriter) decideMode() {
+	if w.mode != anthropicCompatWriterPending {
+		return
+	}
+	if !w.isEventStream() {
+		w.mode = anthropicCompatWriterBuffered
+		return
+	}
+	w.mode = anthropicCompatWriterStream
+	w.size = 0
+	if w.detached {
+		return
+	}
+	w.Header().Del("Content-Length")
+	w.ResponseWriter.WriteHeader(w.status)
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+// convertBufferedLines converts every complete SSE line in the buffer and
+// keeps any trailing partial line for the next write.
+func (w *anthropicCompatWriter) convertBufferedLines() error {
+	var out strings.Builder
+	for {
+		data := w.buf.Bytes()
+		i := bytes.IndexByte(data, '\n')
+		if i < 0 {
+			break
+		}
+		line := strings.TrimRight(string(data[:i]), "\r")
+		w.buf.Next(i + 1)
+		payload, ok := strings.CutPrefix(line, "data:")
+		if !ok {
+			continue
+		}
+		payload = strings.TrimSpace(payload)
+		if payload == "" || payload == "[DONE]" {
+			continue
+		}
+		out.WriteString(w.conv.streamPayload(payload))
+	}
+	return w.emit(out.String())
+}
+
+func (w *anthropicCompatWriter) emit(s string) error {
+	if s == "" || w.detached {
+		return nil
+	}
+	_, err := w.ResponseWriter.WriteString(s)
+	return err
+}
+
+// finish flushes the converted response once the Messages pipeline returns.
+func (w *anthropicCompatWriter) finish() {
+	switch w.mode {
+	case anthropicCompatWriterStream:
+		w.buf.WriteByte('\n')
+		if err := w.convertBufferedLines(); err != nil {
+			return
+		}
+		_ = w.emit(w.conv.streamEnd())
+		if !w.detached {
+			w.ResponseWriter.Flush()
+		}
+
+	case anthropicCompatWriterBuffered:
+		body := w.conv.convertBody(w.status, w.buf.Bytes())
+		if w.detached {
+			return
+		}
+		w.Header().Del("Content-Length")
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.ResponseWriter.WriteHeader(w.status)
+		_, _ = w.ResponseWriter.Write(body)
+	}
+}
+
+// openAIErrorResponse writes an error in OpenAI API format.
+func (h *GatewayHandler) openAIErrorResponse(c *gin.Context, status int, errType, message string) {
+	c.JSON(status, gin.H{
+		"error": gin.H{
+			"type":    errType,
+			"message": message,
+		},
+	})
+}
+
+// replaceRequestBody swaps in a converted request body before handing the
+// request to the Messages pipeline.
+func replaceRequestBody(c *gin.Context, body []byte) {
+	c.Request.Body = io.NopCloser(bytes.NewReader(body))
+	c.Request.ContentLength = int64(len(body))
+	c.Request.Header.Set("Content-Length", strconv.Itoa(len(body)))
+}
+
+// anthropicErrorToOpenAI rewrites an Anthropic error payload
+// ({"type":"error","error":{...}}) into the OpenAI error shape.
+func anthropicErrorToOpenAI(body []byte) gin.H {
+	errType := gjson.GetBytes(body, "error.type").String()
+	message := gjson.GetBytes(body, "error.message").String()
+	if errType == "" {
+		errType = "api_error"
+	}
+	if message == "" {
+		message = strings.TrimSpace(string(body))
+	}
+	return gin.H{
+		"error": gin.H{
+			"type":    errType,
+			"message": message,
+		},
+	}
+}
@@ -0,0 +1,178 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/Wei-Shaw/sub2api/internal/pkg/antigravity"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/apicompat"
+	middleware2 "github.com/Wei-Shaw/sub2api/internal/server/middleware"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tidwall/gjson"
+	"go.uber.org/zap"
+)
+
+// useGeminiNativeCompat reports whether an OpenAI-format request for model
+// is translated straight to Gemini generateContent instead of going through
+// the Anthropic Messages pipeline: always on Gemini groups, and for gemini-*
+// models on Antigravity groups (or the /antigravity routes). Claude models on
+// Antigravity keep the Messages path.
+func useGeminiNativeCompat(c *gin.Context, apiKey *service.APIKey, model string) bool {
+	platform, ok := middleware2.GetForcePlatformFromContext(c)
+	if !ok && apiKey != nil && apiKey.Group != nil {
+		platform = apiKey.Group.Platform
+	}
+	switch platform {
+	case service.PlatformGemini:
+		return true
+	case service.PlatformAntigravity:
+		return strings.HasPrefix(strings.ToLower(strings.TrimSpace(model)), "gemini")
+	default:
+		return false
+	}
+}
+
+// forwardGeminiCompat serves a converted Gemini request through the native
+// generateContent pipeline (scheduling, failover, UsageRecordTask). The
+// caller has wrapped c.Writer with a geminiCompatConverter, so Google-format
+// errors and responses reach the client in OpenAI format.
+func (h *GatewayHandler) forwardGeminiCompat(c *gin.Context, model string, stream bool, geminiReq *antigravity.GeminiRequest) {
+	apiKey, ok := middleware2.GetAPIKeyFromContext(c)
+	if !ok || apiKey == nil {
+		googleError(c, http.StatusUnauthorized, "Invalid API key")
+		return
+	}
+	authSubject, ok := middleware2.GetAuthSubjectFromContext(c)
+	if !ok {
+		googleError(c, http.StatusInternalServerError, "User context not found")
+		return
+	}
+	body, err := json.Marshal(geminiReq)
+	if err != nil {
+		googleError(c, http.StatusInternalServerError, "Failed to convert request")
+		return
+	}
+	reqLog := requestLogger(
+		c,
+		"handler.gateway.gemini_compat",
+		zap.Int64("user_id", authSubject.UserID),
+		zap.Int64("api_key_id", apiKey.ID),
+		zap.Any("group_id", apiKey.GroupID),
+	)
+
+	action := "generateContent"
+	if stream {
+		action = "streamGenerateContent"
+	}
+	h.forwardGeminiGenerateContent(c, apiKey, authSubject, reqLog, model, action, body)
+}
+
+// geminiCompatConverter translates native Gemini output into Anthropic
+// Messages events and hands them to an OpenAI-side converter, so Chat
+// Completions and Responses share one implementation for every upstream.
+type geminiCompatConverter struct {
+	inner  anthropicCompatConverter
+	state  *apicompat.GeminiEventToAnthropicState
+	failed bool
+}
+
+func newGeminiCompatConverter(inner anthropicCompatConverter, model string) *geminiCompatConverter {
+	state := apicompat.NewGeminiEventToAnthropicState()
+	state.Model = model
+	return &geminiCompatConverter{inner: inner, state: state}
+}
+
+func (cv *geminiCompatConverter) streamPayload(payload string) string {
+	if gjson.Get(payload, "error").Exists() {
+		cv.failed = true
+		return cv.inner.streamPayload(string(geminiErrorToAnthropic(0, []byte(payload))))
+	}
+	var resp antigravity.GeminiResponse
+	if err := json.Unmarshal([]byte(payload), &resp); err != nil {
+		return ""
+	}
+	return cv.relay(apicompat.GeminiChunkToAnthropicEvents(&resp, cv.state))
+}
+
+func (cv *geminiCompatConverter) streamEnd() string {
+	var out string
+	if !cv.failed {
+		out = cv.relay(apicompat.FinalizeGeminiAnthropicStream(cv.state))
+	}
+	return out + cv.inner.streamEnd()
+}
+
+func (cv *geminiCompatConverter) convertBody(status int, body []byte) []byte {
+	if status >= http.StatusBadRequest {
+		return cv.inner.convertBody(status, geminiErrorToAnthropic(status, body))
+	}
+	var resp antigravity.GeminiResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return body
+	}
+	converted, err := json.Marshal(apicompat.GeminiToAnthropicResponse(&resp, cv.state.Model))
+	if err != nil {
+		return body
+	}
+	return cv.inner.convertBody(status, converted)
+}
+
+func (cv *geminiCompatConverter) relay(events []apicompat.AnthropicStreamEvent) string {
+	var out strings.Builder
+	for _, evt := range events {
+		payload, err := json.Marshal(evt)
+		if err != nil {
+			continue
+		}
+		out.WriteString(cv.inner.streamPayload(string(payload)))
+	}
+	return out.String()
+}
+
+// geminiErrorToAnthropic rewrites a Google API error
+// ({"error":{"code","message","status"}}) into the Anthropic error shape
+// expected by the OpenAI-side converters. status=0 takes the code from the
+// payload (stream errors).
+func geminiErrorToAnthropic(status int, body []byte) []byte {
+	errObj := gjson.GetBytes(body, "error")
+	if status == 0 {
+		status = int(errObj.Get("code").Int())
+	}
+	errType := errObj.Get("type").String()
+	if errType == "" {
+		errType = openAIErrorTypeForStatus(status)
+	}
+	message := errObj.Get("message").String()
+	if message == "" {
+		message = strings.TrimSpace(string(body))
+	}
+	data, _ := json.Marshal(gin.H{
+		"type": "error",
+		"error": gin.H{
+			"type":    errType,
+			"message": message,
+		},
+	})
+	return data
+}
+
+// openAIErrorTypeForStatus picks the OpenAI error type for an HTTP status.
+func openAIErrorTypeForStatus(status int) string {
+	switch status {
+	case http.StatusBadRequest, http.StatusRequestEntityTooLarge:
+		return "invalid_request_error"
+	case http.StatusUnauthorized:
+		return "authentication_error"
+	case http.StatusForbidden:
+		return "permission_error"
+	case http.StatusNotFound:
+		return "not_found_error"
+	case http.StatusTooManyRequests:
+		return "rate_limit_error"
+	default:
+		return "api_error"
+	}
+}
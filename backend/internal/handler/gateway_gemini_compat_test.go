@@ -0,0 +1,112 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Wei-Shaw/sub2api/internal/server/middleware"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+)
+
+func newGeminiChatWriterTestContext() (*gin.Context, *httptest.ResponseRecorder, *anthropicCompatWriter) {
+	gin.SetMode(gin.TestMode)
+	rec := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(rec)
+	c.Request = httptest.NewRequest(http.MethodPost, "/v1/chat/completions", nil)
+	conv := newGeminiCompatConverter(newChatCompletionsConverter("gemini-2.5-pro", true), "gemini-2.5-pro")
+	w := newAnthropicCompatWriter(c.Writer, conv)
+	c.Writer = w
+	return c, rec, w
+}
+
+func TestGeminiCompatWriter_ChatStream(t *testing.T) {
+	c, rec, w := newGeminiChatWriterTestContext()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Status(http.StatusOK)
+	chunks := []string{
+		"data: {\"responseId\":\"r1\",\"candidates\":[{\"content\":{\"role\":\"model\",\"parts\":[{\"text\":\"Hi\"}]}}]}\n\n",
+		"data: {\"candidates\":[{\"content\":{\"role\":\"model\",\"parts\":[{\"functionCall\":{\"name\":\"ls\",\"args\":{}}}]},\"finishReason\":\"STOP\"}],\"usageMetadata\":{\"promptTokenCount\":7,\"candidatesTokenCount\":2}}\n\n",
+	}
+	for _, chunk := range chunks {
+		_, err := c.Writer.WriteString(chunk)
+		require.NoError(t, err)
+		c.Writer.Flush()
+	}
+	w.finish()
+
+	body := rec.Body.String()
+	require.Contains(t, body, `"content":"Hi"`)
+	require.Contains(t, body, `"name":"ls"`)
+	require.Contains(t, body, `"finish_reason":"tool_calls"`)
+	require.Contains(t, body, `"prompt_tokens":7`)
+	require.Contains(t, body, "data: [DONE]\n\n")
+	require.NotContains(t, body, "candidates")
+}
+
+func TestGeminiCompatWriter_ChatNonStream(t *testing.T) {
+	c, rec, w := newGeminiChatWriterTestContext()
+
+	c.Data(http.StatusOK, "application/json", []byte(`{"candidates":[{"content":{"parts":[{"text":"{\"a\":1}"}]},"finishReason":"STOP"}],"usageMetadata":{"promptTokenCount":3,"candidatesTokenCount":4}}`))
+	w.finish()
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, "chat.completion", gjson.Get(rec.Body.String(), "object").String())
+	require.Equal(t, `{"a":1}`, gjson.Get(rec.Body.String(), "choices.0.message.content").String())
+	require.Equal(t, int64(7), gjson.Get(rec.Body.String(), "usage.total_tokens").Int())
+}
+
+func TestGeminiCompatWriter_GoogleError(t *testing.T) {
+	c, rec, w := newGeminiChatWriterTestContext()
+
+	googleError(c, http.StatusTooManyRequests, "quota exhausted")
+	w.finish()
+
+	require.Equal(t, http.StatusTooManyRequests, rec.Code)
+	require.JSONEq(t, `{"error":{"type":"rate_limit_error","message":"quota exhausted"}}`, rec.Body.String())
+}
+
+func TestGeminiCompatWriter_StreamError(t *testing.T) {
+	c, rec, w := newGeminiChatWriterTestContext()
+
+	c.Header("Content-Type", "text/event-stream")
+	_, _ = c.Writer.WriteString("data: {\"candidates\":[{\"content\":{\"parts\":[{\"text\":\"Hi\"}]}}]}\n\n")
+	_, _ = c.Writer.WriteString("data: {\"error\":{\"code\":503,\"message\":\"overloaded\",\"status\":\"UNAVAILABLE\"}}\n\n")
+	w.finish()
+
+	body := rec.Body.String()
+	require.Contains(t, body, `"message":"overloaded"`)
+	require.NotContains(t, body, "[DONE]")
+}
+
+func TestUseGeminiNativeCompat(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	gemini := &service.APIKey{Group: &service.Group{Platform: service.PlatformGemini}}
+	antigravity := &service.APIKey{Group: &service.Group{Platform: service.PlatformAntigravity}}
+	anthropic := &service.APIKey{Group: &service.Group{Platform: service.PlatformAnthropic}}
+
+	require.True(t, useGeminiNativeCompat(c, gemini, "gemini-2.5-pro"))
+	require.True(t, useGeminiNativeCompat(c, antigravity, "gemini-3-pro-preview"))
+	require.False(t, useGeminiNativeCompat(c, antigravity, "claude-sonnet-4-5"))
+	require.False(t, useGeminiNativeCompat(c, anthropic, "gemini-2.5-pro"))
+
+	c.Set(string(middleware.ContextKeyForcePlatform), service.PlatformAntigravity)
+	require.True(t, useGeminiNativeCompat(c, anthropic, "gemini-2.5-pro"))
+}
+
+func TestBuildResponsesTurn_Gemini(t *testing.T) {
+	h := &GatewayHandler{}
+	turn, turnErr := h.buildResponsesTurn(t.Context(), 1,
+		[]byte(`{"model":"gemini-2.5-pro","instructions":"Be brief.","input":"hi","text":{"format":{"type":"json_object"}}}`), nil, true)
+	require.Nil(t, turnErr)
+	require.Nil(t, turn.body)
+	require.NotNil(t, turn.gemini)
+	require.Equal(t, "Be brief.", turn.gemini.SystemInstruction.Parts[0].Text)
+	require.Equal(t, "hi", turn.gemini.Contents[0].Parts[0].Text)
+	require.Equal(t, "application/json", turn.gemini.GenerationConfig.ResponseMimeType)
+}
@@ -41,6 +41,8 @@ type GatewayHandler struct {
 	antigravityGatewayService *service.AntigravityGatewayService
 	userService               *service.UserService
 	billingCacheService       *service.BillingCacheService
+	billingHoldService        *service.BillingHoldService
+	apiKeyTrafficService      *service.APIKeyTrafficLimitService
 	usageService              *service.UsageService
 	apiKeyService             *service.APIKeyService
 	usageRecordWorkerPool     *service.UsageRecordWorkerPool
@@ -51,6 +53,7 @@ type GatewayHandler struct {
 	maxAccountSwitchesGemini  int
 	cfg                       *config.Config
 	settingService            *service.SettingService
+	responsesConversations    *service.ResponsesConversationService
 }
 
 // NewGatewayHandler creates a new GatewayHandler
@@ -61,6 +64,8 @@ func NewGatewayHandler(
 	userService *service.UserService,
 	concurrencyService *service.ConcurrencyService,
 	billingCacheService *service.BillingCacheService,
+	billingHoldService *service.BillingHoldService,
+	apiKeyTrafficService *service.APIKeyTrafficLimitService,
 	usageService *service.UsageService,
 	apiKeyService *service.APIKeyService,
 	usageRecordWorkerPool *service.UsageRecordWorkerPool,
@@ -68,6 +73,7 @@ func NewGatewayHandler(
 	userMsgQueueService *service.UserMessageQueueService,
 	cfg *config.Config,
 	settingService *service.SettingService,
+	responsesConversations *service.ResponsesConversationService,
 ) *GatewayHandler {
 	pingInterval := time.Duration(0)
 	maxAccountSwitches := 10
@@ -94,6 +100,8 @@ func NewGatewayHandler(
 		antigravityGatewayService: antigravityGatewayService,
 		userService:               userService,
 		billingCacheService:       billingCacheService,
+		billingHoldService:        billingHoldService,
+		apiKeyTrafficService:      apiKeyTrafficService,
 		usageService:              usageService,
 		apiKeyService:             apiKeyService,
 		usageRecordWorkerPool:     usageRecordWorkerPool,
@@ -104,6 +112,7 @@ func NewGatewayHandler(
 		maxAccountSwitchesGemini:  maxAccountSwitchesGemini,
 		cfg:                       cfg,
 		settingService:            settingService,
+		responsesConversations:    responsesConversations,
 	}
 }
 
@@ -185,6 +194,13 @@ func (h *GatewayHandler) Messages(c *gin.Context) {
 		return
 	}
 
+	// 检查 API Key 模型白名单/黑名单（在调度账号之前拒绝）
+	if !apiKey.IsModelAllowed(reqModel) {
+		reqLog.Info("gateway.model_denied_by_api_key")
+		h.errorResponse(c, http.StatusForbidden, "permission_error", apiKeyModelDeniedMessage(reqModel))
+		return
+	}
+
 	// Track if we've started streaming (for error handling)
 	streamStarted := false
 
@@ -196,6 +212,22 @@ func (h *GatewayHandler) Messages(c *gin.Context) {
 	// 获取订阅信息（可能为nil）- 提前获取用于后续检查
 	subscription, _ := middleware2.GetSubscriptionFromContext(c)
 
+	// API Key 级限流：RPM/TPM 准入与 Key 级并发槽位（先于用户槽位，避免单个 Key 占满用户并发）
+	if err := admitAPIKeyTraffic(c, h.apiKeyTrafficService, apiKey, rateLimitHeadersAnthropic); err != nil {
+		reqLog.Info("gateway.api_key_traffic_limited", zap.Error(err))
+		h.errorResponse(c, http.StatusTooManyRequests, "rate_limit_error", pkgerrors.Message(err))
+		return
+	}
+	apiKeyReleaseFunc, err := h.concurrencyHelper.AcquireAPIKeySlotWithWait(c, apiKey.ID, apiKey.MaxConcurrency, reqStream, &streamStarted)
+	if err != nil {
+		reqLog.Warn("gateway.api_key_slot_acquire_failed", zap.Error(err))
+		h.handleConcurrencyError(c, err, "api_key", streamStarted)
+		return
+	}
+	if apiKeyReleaseFunc != nil {
+		defer apiKeyReleaseFunc()
+	}
+
 	// 0. 检查wait队列是否已满
 	maxWait := service.CalculateMaxWait(subject.Concurrency)
 	canWait, err := h.concurrencyHelper.IncrementWaitCount(c.Request.Context(), subject.UserID, maxWait)
@@ -244,6 +276,23 @@ func (h *GatewayHandler) Messages(c *gin.Context) {
 		return
 	}
 
+	// 按预估最大费用冻结额度；成功转发后交由使用量记录结算，其余退出路径释放
+	billingHold, err := h.billingHoldService.Reserve(c.Request.Context(), service.BillingHoldRequest{
+		User:         apiKey.User,
+		APIKey:       apiKey,
+		Group:        apiKey.Group,
+		Subscription: subscription,
+		Model:        reqModel,
+		Body:         body,
+	})
+	if err != nil {
+		reqLog.Info("gateway.billing_hold_rejected", zap.Error(err))
+		status, code, message := billingErrorDetails(err)
+		h.handleStreamingAwareError(c, status, code, message, streamStarted)
+		return
+	}
+	defer func() { billingHold.Release() }()
+
 	// 计算粘性会话hash
 	parsedReq.SessionContext = &service.SessionContext{
 		ClientIP:  ip.GetClientIP(c),
@@ -435,8 +484,12 @@ func (h *GatewayHandler) Messages(c *gin.Context) {
 			userAgent := c.GetHeader("User-Agent")
 			clientIP := ip.GetClientIP(c)
 
+			// 冻结交由使用量记录结算
+			usageHold := billingHold
+			billingHold = nil
+
 			// 使用量记录通过有界 worker 池提交，避免请求热路径创建无界 goroutine。
-			h.submitUsageRecordTask(func(ctx context.Context) {
+			h.submitUsageRecordTask(c.Request.Context(), func(ctx context.Context) {
 				if err := h.gatewayService.RecordUsage(ctx, &service.RecordUsageInput{
 					Result:            result,
 					APIKey:            apiKey,
@@ -447,6 +500,7 @@ func (h *GatewayHandler) Messages(c *gin.Context) {
 					IPAddress:         clientIP,
 					ForceCacheBilling: fs.ForceCacheBilling,
 					APIKeyService:     h.apiKeyService,
+					BillingHold:       usageHold,
 				}); err != nil {
 					logger.L().With(
 						zap.String("component", "handler.gateway.messages"),
@@ -730,8 +784,12 @@ func (h *GatewayHandler) Messages(c *gin.Context) {
 			userAgent := c.GetHeader("User-Agent")
 			clientIP := ip.GetClientIP(c)
 
+			// 冻结交由使用量记录结算
+			usageHold := billingHold
+			billingHold = nil
+
 			// 使用量记录通过有界 worker 池提交，避免请求热路径创建无界 goroutine。
-			h.submitUsageRecordTask(func(ctx context.Context) {
+			h.submitUsageRecordTask(c.Request.Context(), func(ctx context.Context) {
 				if err := h.gatewayService.RecordUsage(ctx, &service.RecordUsageInput{
 					Result:            result,
 					APIKey:            currentAPIKey,
@@ -742,6 +800,7 @@ func (h *GatewayHandler) Messages(c *gin.Context) {
 					IPAddress:         clientIP,
 					ForceCacheBilling: fs.ForceCacheBilling,
 					APIKeyService:     h.apiKeyService,
+					BillingHold:       usageHold,
 				}); err != nil {
 					logger.L().With(
 						zap.String("component", "handler.gateway.messages"),
@@ -782,13 +841,16 @@ func (h *GatewayHandler) Models(c *gin.Context) {
 	if platform == service.PlatformSora {
 		c.JSON(http.StatusOK, gin.H{
 			"object": "list",
-			"data":   service.DefaultSoraModels(h.cfg),
+			"data":   filterModelsForAPIKey(apiKey, service.DefaultSoraModels(h.cfg), openAIModelID),
 		})
 		return
 	}
 
 	// Get available models from account configurations (without platform filter)
 	availableModels := h.gatewayService.GetAvailableModels(c.Request.Context(), groupID, "")
+	if len(availableModels) > 0 {
+		availableModels = filterModelsForAPIKey(apiKey, availableModels, func(id string) string { return id })
+	}
 
 	if len(availableModels) > 0 {
 		// Build model list from whitelist
@@ -812,7 +874,7 @@ func (h *GatewayHandler) Models(c *gin.Context) {
 	if platform == "openai" {
 		c.JSON(http.StatusOK, gin.H{
 			"object": "list",
-			"data":   openai.DefaultModels,
+			"data":   filterModelsForAPIKey(apiKey, openai.DefaultModels, openAIModelID),
 		})
 		return
 	}
@@ -820,23 +882,24 @@ func (h *GatewayHandler) Models(c *gin.Context) {
 	if platform == service.PlatformNanoBanana {
 		c.JSON(http.StatusOK, gin.H{
 			"object": "list",
-			"data":   service.DefaultNanoBananaModels(),
+			"data":   filterModelsForAPIKey(apiKey, service.DefaultNanoBananaModels(), openAIModelID),
 		})
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"object": "list",
-		"data":   claude.DefaultModels,
+		"data":   filterModelsForAPIKey(apiKey, claude.DefaultModels, func(m claude.Model) string { return m.ID }),
 	})
 }
 
 // AntigravityModels 返回 Antigravity 支持的全部模型
 // GET /antigravity/models
 func (h *GatewayHandler) AntigravityModels(c *gin.Context) {
+	apiKey, _ := middleware2.GetAPIKeyFromContext(c)
 	c.JSON(http.StatusOK, gin.H{
 		"object": "list",
-		"data":   antigravity.DefaultModels(),
+		"data":   filterModelsForAPIKey(apiKey, antigravity.DefaultModels(), func(m antigravity.ClaudeModel) string { return m.ID }),
 	})
 }
 
@@ -1100,7 +1163,7 @@ func (h *GatewayHandler) calculateSubscriptionRemaining(group *service.Group, su
 
 	// 检查日限额
 	if group.HasDailyLimit() {
-		remaining := *group.DailyLimitUSD - sub.DailyUsageUSD
+		remaining := (*group.DailyLimitUSD - sub.DailyUsageUSD).Float64()
 		if remaining <= 0 {
 			return 0
 		}
@@ -1109,7 +1172,7 @@ func (h *GatewayHandler) calculateSubscriptionRemaining(group *service.Group, su
 
 	// 检查周限额
 	if group.HasWeeklyLimit() {
-		remaining := *group.WeeklyLimitUSD - sub.WeeklyUsageUSD
+		remaining := (*group.WeeklyLimitUSD - sub.WeeklyUsageUSD).Float64()
 		if remaining <= 0 {
 			return 0
 		}
@@ -1118,7 +1181,7 @@ func (h *GatewayHandler) calculateSubscriptionRemaining(group *service.Group, su
 
 	// 检查月限额
 	if group.HasMonthlyLimit() {
-		remaining := *group.MonthlyLimitUSD - sub.MonthlyUsageUSD
+		remaining := (*group.MonthlyLimitUSD - sub.MonthlyUsageUSD).Float64()
 		if remaining <= 0 {
 			return 0
 		}
@@ -1335,6 +1398,10 @@ func (h *GatewayHandler) CountTokens(c *gin.Context) {
 		h.errorResponse(c, http.StatusBadRequest, "invalid_request_error", "model is required")
 		return
 	}
+	if !apiKey.IsModelAllowed(parsedReq.Model) {
+		h.errorResponse(c, http.StatusForbidden, "permission_error", apiKeyModelDeniedMessage(parsedReq.Model))
+		return
+	}
 
 	setOpsRequestContext(c, parsedReq.Model, parsedReq.Stream, body)
 
@@ -1607,6 +1674,10 @@ func billingErrorDetails(err error) (status int, code, message string) {
 		msg := pkgerrors.Message(err)
 		return http.StatusTooManyRequests, "rate_limit_exceeded", msg
 	}
+	if errors.Is(err, service.ErrOrganizationMemberSpendLimitExceeded) {
+		msg := pkgerrors.Message(err)
+		return http.StatusTooManyRequests, "rate_limit_exceeded", msg
+	}
 	msg := pkgerrors.Message(err)
 	if msg == "" {
 		logger.L().With(
@@ -1642,10 +1713,11 @@ func (h *GatewayHandler) maybeLogCompatibilityFallbackMetrics(reqLog *zap.Logger
 	)
 }
 
-func (h *GatewayHandler) submitUsageRecordTask(task service.UsageRecordTask) {
+func (h *GatewayHandler) submitUsageRecordTask(reqCtx context.Context, task service.UsageRecordTask) {
 	if task == nil {
 		return
 	}
+	task = withRequestSpan(reqCtx, task)
 	if h.usageRecordWorkerPool != nil {
 		h.usageRecordWorkerPool.Submit(task)
 		return
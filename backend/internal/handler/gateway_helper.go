@@ -10,9 +10,12 @@ import (
 	"sync"
 	"time"
 
+	"github.com/Wei-Shaw/sub2api/internal/pkg/tracing"
 	"github.com/Wei-Shaw/sub2api/internal/service"
 
 	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // claudeCodeValidator is a singleton validator for Claude Code client detection
@@ -228,6 +231,19 @@ func (h *ConcurrencyHelper) TryAcquireUserSlot(ctx context.Context, userID int64
 	return result.ReleaseFunc, true, nil
 }
 
+// TryAcquireAPIKeySlot 尝试立即获取 API Key 并发槽位（未配置上限时直接成功）。
+// 返回值: (releaseFunc, acquired, error)
+func (h *ConcurrencyHelper) TryAcquireAPIKeySlot(ctx context.Context, apiKeyID int64, maxConcurrency int) (func(), bool, error) {
+	result, err := h.concurrencyService.AcquireAPIKeySlot(ctx, apiKeyID, maxConcurrency)
+	if err != nil {
+		return nil, false, err
+	}
+	if !result.Acquired {
+		return nil, false, nil
+	}
+	return result.ReleaseFunc, true, nil
+}
+
 // TryAcquireAccountSlot 尝试立即获取账号并发槽位。
 // 返回值: (releaseFunc, acquired, error)
 func (h *ConcurrencyHelper) TryAcquireAccountSlot(ctx context.Context, accountID int64, maxConcurrency int) (func(), bool, error) {
@@ -281,6 +297,39 @@ func (h *ConcurrencyHelper) AcquireAccountSlotWithWait(c *gin.Context, accountID
 	return h.waitForSlotWithPing(c, "account", accountID, maxConcurrency, isStream, streamStarted)
 }
 
+// AcquireAPIKeySlotWithWait acquires an API key concurrency slot, waiting if necessary.
+// Keys without max_concurrency are not limited and get a nil release func.
+// The per-key wait queue is bounded like the user queue; a full queue returns *ConcurrencyError.
+// 调用方应在获取用户槽位之前调用，避免单个 Key 排队时占满用户并发。
+func (h *ConcurrencyHelper) AcquireAPIKeySlotWithWait(c *gin.Context, apiKeyID int64, maxConcurrency int, isStream bool, streamStarted *bool) (func(), error) {
+	if maxConcurrency <= 0 {
+		return nil, nil
+	}
+	ctx := c.Request.Context()
+
+	result, err := h.concurrencyService.AcquireAPIKeySlot(ctx, apiKeyID, maxConcurrency)
+	if err != nil {
+		return nil, err
+	}
+	if result.Acquired {
+		return wrapReleaseOnDone(ctx, result.ReleaseFunc), nil
+	}
+
+	canWait, err := h.concurrencyService.IncrementAPIKeyWaitCount(ctx, apiKeyID, service.CalculateMaxWait(maxConcurrency))
+	if err == nil && !canWait {
+		return nil, &ConcurrencyError{SlotType: "api_key"}
+	}
+	if err == nil {
+		defer h.concurrencyService.DecrementAPIKeyWaitCount(ctx, apiKeyID)
+	}
+
+	releaseFunc, err := h.waitForSlotWithPing(c, "api_key", apiKeyID, maxConcurrency, isStream, streamStarted)
+	if err != nil {
+		return nil, err
+	}
+	return wrapReleaseOnDone(ctx, releaseFunc), nil
+}
+
 // waitForSlotWithPing waits for a concurrency slot, sending ping events for streaming requests.
 // streamStarted pointer is updated when streaming begins (for proper error handling by caller).
 func (h *ConcurrencyHelper) waitForSlotWithPing(c *gin.Context, slotType string, id int64, maxConcurrency int, isStream bool, streamStarted *bool) (func(), error) {
@@ -288,13 +337,23 @@ func (h *ConcurrencyHelper) waitForSlotWithPing(c *gin.Context, slotType string,
 }
 
 // waitForSlotWithPingTimeout waits for a concurrency slot with a custom timeout.
-func (h *ConcurrencyHelper) waitForSlotWithPingTimeout(c *gin.Context, slotType string, id int64, maxConcurrency int, timeout time.Duration, isStream bool, streamStarted *bool, tryImmediate bool) (func(), error) {
-	ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+func (h *ConcurrencyHelper) waitForSlotWithPingTimeout(c *gin.Context, slotType string, id int64, maxConcurrency int, timeout time.Duration, isStream bool, streamStarted *bool, tryImmediate bool) (release func(), err error) {
+	spanCtx, span := tracing.Start(c.Request.Context(), "concurrency.wait_slot", trace.WithAttributes(
+		attribute.String("concurrency.slot_type", slotType),
+		attribute.Int64("concurrency.id", id),
+		attribute.Int("concurrency.max", maxConcurrency),
+	))
+	defer func() { tracing.End(span, err) }()
+
+	ctx, cancel := context.WithTimeout(spanCtx, timeout)
 	defer cancel()
 
 	acquireSlot := func() (*service.AcquireResult, error) {
-		if slotType == "user" {
+		switch slotType {
+		case "user":
 			return h.concurrencyService.AcquireUserSlot(ctx, id, maxConcurrency)
+		case "api_key":
+			return h.concurrencyService.AcquireAPIKeySlot(ctx, id, maxConcurrency)
 		}
 		return h.concurrencyService.AcquireAccountSlot(ctx, id, maxConcurrency)
 	}
@@ -398,3 +457,13 @@ func nextBackoff(current time.Duration) time.Duration {
 	}
 	return jittered
 }
+
+// withRequestSpan 让异步执行的用量记录任务挂在请求链路下（任务上下文不随请求取消）。
+func withRequestSpan(reqCtx context.Context, task service.UsageRecordTask) service.UsageRecordTask {
+	if !trace.SpanContextFromContext(reqCtx).IsValid() {
+		return task
+	}
+	return func(ctx context.Context) {
+		task(tracing.ContextWithSpanFrom(ctx, reqCtx))
+	}
+}
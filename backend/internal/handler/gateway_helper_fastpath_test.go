@@ -77,6 +77,22 @@ func (m *concurrencyCacheMock) DecrementWaitCount(ctx context.Context, userID in
 	return nil
 }
 
+func (m *concurrencyCacheMock) AcquireAPIKeySlot(ctx context.Context, apiKeyID int64, maxConcurrency int, requestID string) (bool, error) {
+	return true, nil
+}
+
+func (m *concurrencyCacheMock) ReleaseAPIKeySlot(ctx context.Context, apiKeyID int64, requestID string) error {
+	return nil
+}
+
+func (m *concurrencyCacheMock) IncrementAPIKeyWaitCount(ctx context.Context, apiKeyID int64, maxWait int) (bool, error) {
+	return true, nil
+}
+
+func (m *concurrencyCacheMock) DecrementAPIKeyWaitCount(ctx context.Context, apiKeyID int64) error {
+	return nil
+}
+
 func (m *concurrencyCacheMock) GetAccountsLoadBatch(ctx context.Context, accounts []service.AccountWithConcurrency) (map[int64]*service.AccountLoadInfo, error) {
 	return map[int64]*service.AccountLoadInfo{}, nil
 }
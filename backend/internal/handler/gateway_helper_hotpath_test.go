@@ -100,6 +100,22 @@ func (s *helperConcurrencyCacheStub) DecrementWaitCount(ctx context.Context, use
 	return nil
 }
 
+func (s *helperConcurrencyCacheStub) AcquireAPIKeySlot(ctx context.Context, apiKeyID int64, maxConcurrency int, requestID string) (bool, error) {
+	return true, nil
+}
+
+func (s *helperConcurrencyCacheStub) ReleaseAPIKeySlot(ctx context.Context, apiKeyID int64, requestID string) error {
+	return nil
+}
+
+func (s *helperConcurrencyCacheStub) IncrementAPIKeyWaitCount(ctx context.Context, apiKeyID int64, maxWait int) (bool, error) {
+	return true, nil
+}
+
+func (s *helperConcurrencyCacheStub) DecrementAPIKeyWaitCount(ctx context.Context, apiKeyID int64) error {
+	return nil
+}
+
 func (s *helperConcurrencyCacheStub) GetAccountsLoadBatch(ctx context.Context, accounts []service.AccountWithConcurrency) (map[int64]*service.AccountLoadInfo, error) {
 	out := make(map[int64]*service.AccountLoadInfo, len(accounts))
 	for _, acc := range accounts {
@@ -0,0 +1,36 @@
+package handler
+
+import (
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/pkg/metrics"
+	middleware2 "github.com/Wei-Shaw/sub2api/internal/server/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+// GatewayMetricsMiddleware 按平台/分组/模型/状态码记录网关请求数与耗时（Prometheus）。
+// 需放在 API Key 鉴权之前，以便鉴权失败的请求同样计入；未启用指标时直接放行。
+func GatewayMetricsMiddleware(enabled bool) gin.HandlerFunc {
+	if !enabled {
+		return func(c *gin.Context) { c.Next() }
+	}
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		apiKey, _ := middleware2.GetAPIKeyFromContext(c)
+		fallbackPlatform, ok := middleware2.GetForcePlatformFromContext(c)
+		if !ok {
+			fallbackPlatform = guessPlatformFromPath(c.Request.URL.Path)
+		}
+		platform := resolveOpsPlatform(apiKey, fallbackPlatform)
+		group := ""
+		if apiKey != nil && apiKey.Group != nil {
+			group = apiKey.Group.Name
+		}
+		model, _ := c.Get(opsModelKey)
+		modelName, _ := model.(string)
+
+		metrics.ObserveGatewayRequest(platform, group, modelName, c.Writer.Status(), time.Since(start))
+	}
+}
@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Wei-Shaw/sub2api/internal/pkg/metrics"
+	middleware2 "github.com/Wei-Shaw/sub2api/internal/server/middleware"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+func scrapeMetricsForTest(t *testing.T) string {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	metrics.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body, err := io.ReadAll(rec.Body)
+	require.NoError(t, err)
+	return string(body)
+}
+
+func TestGatewayMetricsMiddleware_LabelsFromAPIKeyAndModel(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(GatewayMetricsMiddleware(true))
+	r.Use(func(c *gin.Context) {
+		c.Set(string(middleware2.ContextKeyAPIKey), &service.APIKey{
+			Group: &service.Group{Name: "metrics-test-group", Platform: service.PlatformGemini},
+		})
+		c.Next()
+	})
+	r.POST("/v1/messages", func(c *gin.Context) {
+		setOpsRequestContext(c, "metrics-test-model", false, nil)
+		c.Status(http.StatusTooManyRequests)
+	})
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/v1/messages", nil))
+	require.Equal(t, http.StatusTooManyRequests, rec.Code)
+
+	require.Contains(t, scrapeMetricsForTest(t),
+		`sub2api_gateway_requests_total{group="metrics-test-group",model="metrics-test-model",platform="gemini",status="429"} 1`)
+}
+
+func TestGatewayMetricsMiddleware_ForcedPlatformWithoutAPIKey(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(GatewayMetricsMiddleware(true))
+	r.Use(middleware2.ForcePlatform(service.PlatformSora))
+	r.POST("/sora/v1/chat/completions", func(c *gin.Context) {
+		c.Status(http.StatusUnauthorized)
+	})
+
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/sora/v1/chat/completions", nil))
+
+	require.Contains(t, scrapeMetricsForTest(t),
+		`sub2api_gateway_requests_total{group="unknown",model="other",platform="sora",status="401"}`)
+}
@@ -0,0 +1,494 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/pkg/antigravity"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/apicompat"
+	pkghttputil "github.com/Wei-Shaw/sub2api/internal/pkg/httputil"
+	middleware2 "github.com/Wei-Shaw/sub2api/internal/server/middleware"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+
+	coderws "github.com/coder/websocket"
+	"github.com/gin-gonic/gin"
+	"github.com/tidwall/gjson"
+	"github.com/tidwall/sjson"
+	"go.uber.org/zap"
+)
+
+// responsesWSWriteTimeout bounds a single WebSocket event write to the client.
+const responsesWSWriteTimeout = 30 * time.Second
+
+// Responses handles OpenAI Responses API requests routed to Claude, Gemini or
+// Antigravity groups.
+// POST /v1/responses (when group platform is not OpenAI)
+//
+// The request is converted to Anthropic Messages format and served by the
+// regular Messages pipeline (Gemini models go straight to generateContent,
+// see useGeminiNativeCompat); the reply is translated back by
+// responsesConverter. Upstreams are stateless, so previous_response_id is
+// resolved from conversation state kept by the gateway.
+func (h *GatewayHandler) Responses(c *gin.Context) {
+	apiKey, ok := middleware2.GetAPIKeyFromContext(c)
+	if !ok {
+		h.openAIErrorResponse(c, http.StatusUnauthorized, "authentication_error", "Invalid API key")
+		return
+	}
+
+	body, err := pkghttputil.ReadRequestBodyWithPrealloc(c.Request)
+	if err != nil {
+		if maxErr, ok := extractMaxBytesError(err); ok {
+			h.openAIErrorResponse(c, http.StatusRequestEntityTooLarge, "invalid_request_error", buildBodyTooLargeMessage(maxErr.Limit))
+			return
+		}
+		h.openAIErrorResponse(c, http.StatusBadRequest, "invalid_request_error", "Failed to read request body")
+		return
+	}
+	if len(body) == 0 {
+		h.openAIErrorResponse(c, http.StatusBadRequest, "invalid_request_error", "Request body is empty")
+		return
+	}
+
+	native := useGeminiNativeCompat(c, apiKey, gjson.GetBytes(body, "model").String())
+	turn, turnErr := h.buildResponsesTurn(c.Request.Context(), apiKey.ID, body, nil, native)
+	if turnErr != nil {
+		h.openAIErrorResponse(c, turnErr.status, turnErr.errType, turnErr.message)
+		return
+	}
+
+	conv := newResponsesConverter(turn.req.Model, nil)
+	originalWriter := c.Writer
+	w := newAnthropicCompatWriter(originalWriter, turn.converter(conv))
+	c.Writer = w
+	h.serveResponsesTurn(c, turn)
+	w.finish()
+	if c.Writer == w {
+		c.Writer = originalWriter
+	}
+
+	h.saveResponsesTurn(c, apiKey.ID, turn, conv.result(), false)
+}
+
+// ResponsesWebSocket handles the Responses WebSocket mode for Claude, Gemini
+// or Antigravity groups.
+// GET /v1/responses (when group platform is not OpenAI)
+//
+// Every response.create message is served as one streaming turn through the
+// Messages pipeline, with events sent back as individual WebSocket messages.
+// The last turn is also kept on the connection, so previous_response_id keeps
+// working when the client sets store=false.
+func (h *GatewayHandler) ResponsesWebSocket(c *gin.Context) {
+	if !isOpenAIWSUpgradeRequest(c.Request) {
+		h.openAIErrorResponse(c, http.StatusUpgradeRequired, "invalid_request_error", "WebSocket upgrade required (Upgrade: websocket)")
+		return
+	}
+	apiKey, ok := middleware2.GetAPIKeyFromContext(c)
+	if !ok {
+		h.openAIErrorResponse(c, http.StatusUnauthorized, "authentication_error", "Invalid API key")
+		return
+	}
+	reqLog := requestLogger(
+		c,
+		"handler.gateway.responses_ws",
+		zap.Int64("api_key_id", apiKey.ID),
+		zap.Any("group_id", apiKey.GroupID),
+	)
+
+	wsConn, err := coderws.Accept(c.Writer, c.Request, &coderws.AcceptOptions{
+		CompressionMode: coderws.CompressionContextTakeover,
+	})
+	if err != nil {
+		reqLog.Warn("gateway.responses_ws_accept_failed", zap.Error(err))
+		return
+	}
+	defer func() {
+		_ = wsConn.CloseNow()
+	}()
+	wsConn.SetReadLimit(16 * 1024 * 1024)
+
+	ctx := c.Request.Context()
+	baseRequest := c.Request
+	hijackedWriter := c.Writer
+	defer func() {
+		c.Request = baseRequest
+		c.Writer = hijackedWriter
+	}()
+
+	send := func(payload []byte) error {
+		writeCtx, cancel := context.WithTimeout(ctx, responsesWSWriteTimeout)
+		defer cancel()
+		return wsConn.Write(writeCtx, coderws.MessageText, payload)
+	}
+	sendError := func(status int, errType, message string) error {
+		payload, _ := json.Marshal(responsesWSErrorEvent(status, errType, message))
+		return send(payload)
+	}
+
+	var last *service.ResponsesConversation
+	var lastID string
+	for {
+		msgType, msg, err := wsConn.Read(ctx)
+		if err != nil {
+			closeStatus, closeReason := summarizeWSCloseErrorForLog(err)
+			reqLog.Debug("gateway.responses_ws_closed",
+				zap.Error(err),
+				zap.String("close_status", closeStatus),
+				zap.String("close_reason", closeReason),
+			)
+			return
+		}
+		if msgType != coderws.MessageText && msgType != coderws.MessageBinary {
+			closeOpenAIClientWS(wsConn, coderws.StatusPolicyViolation, "unsupported websocket message type")
+			return
+		}
+
+		payload, err := responsesWSCreatePayload(msg)
+		if err != nil {
+			if sendError(http.StatusBadRequest, "invalid_request_error", err.Error()) != nil {
+				return
+			}
+			continue
+		}
+
+		var local *service.ResponsesConversation
+		if lastID != "" && gjson.GetBytes(payload, "previous_response_id").String() == lastID {
+			local = last
+		}
+		native := useGeminiNativeCompat(c, apiKey, gjson.GetBytes(payload, "model").String())
+		turn, turnErr := h.buildResponsesTurn(ctx, apiKey.ID, payload, local, native)
+		if turnErr != nil {
+			if sendError(turnErr.status, turnErr.errType, turnErr.message) != nil {
+				return
+			}
+			continue
+		}
+
+		c.Request = baseRequest.WithContext(ctx)
+		conv := newResponsesConverter(turn.req.Model, send)
+		w := newAnthropicCompatWriter(hijackedWriter, turn.converter(conv))
+		w.detached = true
+		c.Writer = w
+		h.serveResponsesTurn(c, turn)
+		w.finish()
+		c.Writer = hijackedWriter
+
+		if conv.sendErr != nil {
+			reqLog.Debug("gateway.responses_ws_send_failed", zap.Error(conv.sendErr))
+			return
+		}
+		if result := conv.result(); result != nil {
+			lastID = result.ID
+			last = h.saveResponsesTurn(c, apiKey.ID, turn, result, true)
+		}
+	}
+}
+
+// responsesWSCreatePayload extracts the Responses request from a WebSocket
+// response.create message. Both the flat form and the legacy form with a
+// nested "response" object are accepted; streaming is always on.
+func responsesWSCreatePayload(msg []byte) ([]byte, error) {
+	if !gjson.ValidBytes(msg) {
+		return nil, fmt.Errorf("invalid JSON payload")
+	}
+	if msgType := gjson.GetBytes(msg, "type").String(); msgType != "response.create" {
+		return nil, fmt.Errorf("unsupported message type %q, expected response.create", msgType)
+	}
+	payload := msg
+	if nested := gjson.GetBytes(msg, "response"); nested.IsObject() {
+		payload = []byte(nested.Raw)
+	} else {
+		var err error
+		if payload, err = sjson.DeleteBytes(msg, "type"); err != nil {
+			return nil, err
+		}
+	}
+	return sjson.SetBytes(payload, "stream", true)
+}
+
+func responsesWSErrorEvent(status int, errType, message string) gin.H {
+	return gin.H{
+		"type":   "error",
+		"status": status,
+		"error": gin.H{
+			"type":    errType,
+			"message": message,
+		},
+	}
+}
+
+// responsesTurn is one Responses request prepared for the Messages pipeline,
+// or for the native Gemini pipeline when gemini is set.
+type responsesTurn struct {
+	req    apicompat.ResponsesRequest
+	items  []apicompat.ResponsesInputItem // full conversation input, history included
+	body   []byte                         // converted Anthropic Messages request
+	gemini *antigravity.GeminiRequest     // converted generateContent request
+}
+
+// converter wraps conv for the pipeline the turn is served by.
+func (turn *responsesTurn) converter(conv *responsesConverter) anthropicCompatConverter {
+	if turn.gemini != nil {
+		return newGeminiCompatConverter(conv, turn.req.Model)
+	}
+	return conv
+}
+
+// serveResponsesTurn runs a prepared turn; output goes to c.Writer, which the
+// caller has wrapped with turn.converter.
+func (h *GatewayHandler) serveResponsesTurn(c *gin.Context, turn *responsesTurn) {
+	if turn.gemini != nil {
+		h.forwardGeminiCompat(c, turn.req.Model, turn.req.Stream, turn.gemini)
+		return
+	}
+	replaceRequestBody(c, turn.body)
+	h.Messages(c)
+}
+
+type responsesTurnError struct {
+	status  int
+	errType string
+	message string
+}
+
+// buildResponsesTurn parses a Responses request, prepends the stored
+// conversation for previous_response_id and converts it to Anthropic format,
+// or to Gemini generateContent format when gemini is set.
+// local, when non-nil, is used instead of the shared store (WebSocket mode).
+func (h *GatewayHandler) buildResponsesTurn(ctx context.Context, apiKeyID int64, body []byte, local *service.ResponsesConversation, gemini bool) (*responsesTurn, *responsesTurnError) {
+	turn := &responsesTurn{}
+	if err := json.Unmarshal(body, &turn.req); err != nil {
+		return nil, &responsesTurnError{http.StatusBadRequest, "invalid_request_error", "Failed to parse request body"}
+	}
+	if strings.TrimSpace(turn.req.Model) == "" {
+		return nil, &responsesTurnError{http.StatusBadRequest, "invalid_request_error", "model is required"}
+	}
+	items, err := apicompat.ParseResponsesInput(turn.req.Input)
+	if err != nil {
+		return nil, &responsesTurnError{http.StatusBadRequest, "invalid_request_error", "Failed to parse input: " + err.Error()}
+	}
+
+	if prevID := strings.TrimSpace(turn.req.PreviousResponseID); prevID != "" {
+		history := local
+		if history == nil {
+			history, err = h.responsesConversations.Load(ctx, apiKeyID, prevID)
+			if err != nil {
+				if service.IsResponsesConversationNotFound(err) {
+					return nil, &responsesTurnError{http.StatusBadRequest, "invalid_request_error",
+						fmt.Sprintf("Previous response with id '%s' not found.", prevID)}
+				}
+				return nil, &responsesTurnError{http.StatusInternalServerError, "api_error", "Failed to load previous response"}
+			}
+		}
+		merged := make([]apicompat.ResponsesInputItem, 0, len(history.Items)+len(items))
+		items = append(append(merged, history.Items...), items...)
+	}
+	if len(items) == 0 {
+		return nil, &responsesTurnError{http.StatusBadRequest, "invalid_request_error", "input is required"}
+	}
+	turn.items = items
+
+	req := turn.req
+	req.Input, err = json.Marshal(items)
+	if err != nil {
+		return nil, &responsesTurnError{http.StatusInternalServerError, "api_error", "Failed to convert request"}
+	}
+	if gemini {
+		chatReq, err := apicompat.ResponsesRequestToChatCompletions(&req)
+		if err == nil {
+			turn.gemini, err = apicompat.ChatCompletionsToGemini(chatReq)
+		}
+		if err != nil {
+			return nil, &responsesTurnError{http.StatusBadRequest, "invalid_request_error", "Failed to convert request: " + err.Error()}
+		}
+		return turn, nil
+	}
+	anthropicReq, err := apicompat.ResponsesRequestToAnthropic(&req)
+	if err != nil {
+		return nil, &responsesTurnError{http.StatusBadRequest, "invalid_request_error", "Failed to convert request: " + err.Error()}
+	}
+	if turn.body, err = json.Marshal(anthropicReq); err != nil {
+		return nil, &responsesTurnError{http.StatusInternalServerError, "api_error", "Failed to convert request"}
+	}
+	return turn, nil
+}
+
+// saveResponsesTurn records the conversation as of result so it can be
+// continued with previous_response_id. store=false requests are only kept
+// on the WebSocket connection (returned to the caller), never persisted.
+func (h *GatewayHandler) saveResponsesTurn(c *gin.Context, apiKeyID int64, turn *responsesTurn, result *apicompat.ResponsesResponse, keepLocal bool) *service.ResponsesConversation {
+	if result == nil || result.Status == "failed" {
+		return nil
+	}
+	items := make([]apicompat.ResponsesInputItem, 0, len(turn.items)+len(result.Output))
+	items = append(items, turn.items...)
+	items = append(items, apicompat.ResponsesOutputToInputItems(result.Output)...)
+	conv := &service.ResponsesConversation{Model: turn.req.Model, Items: items}
+
+	if turn.req.Store == nil || *turn.req.Store {
+		if err := h.responsesConversations.Save(c.Request.Context(), apiKeyID, result.ID, conv); err != nil {
+			requestLogger(c, "handler.gateway.responses").Warn("gateway.responses_conversation_save_failed",
+				zap.Int64("api_key_id", apiKeyID),
+				zap.String("response_id", result.ID),
+				zap.Error(err),
+			)
+		}
+	}
+	if !keepLocal {
+		return nil
+	}
+	return conv
+}
+
+// responsesConverter translates Anthropic Messages output into the Responses
+// API. With send set (WebSocket mode) every event is delivered as its own
+// message instead of SSE text.
+type responsesConverter struct {
+	state   *apicompat.AnthropicEventToResponsesState
+	send    func(payload []byte) error
+	sendErr error
+	// failed is set once an error has been relayed; the stream is then not
+	// finalized with a synthetic response.completed.
+	failed bool
+	final  *apicompat.ResponsesResponse
+}
+
+func newResponsesConverter(model string, send func(payload []byte) error) *responsesConverter {
+	state := apicompat.NewAnthropicEventToResponsesState()
+	state.Model = model
+	return &responsesConverter{state: state, send: send}
+}
+
+// result returns the finished response, or nil if the turn failed.
+func (cv *responsesConverter) result() *apicompat.ResponsesResponse {
+	if cv.failed {
+		return nil
+	}
+	if cv.final != nil {
+		return cv.final
+	}
+	return cv.state.Response
+}
+
+func (cv *responsesConverter) streamPayload(payload string) string {
+	switch gjson.Get(payload, "type").String() {
+	case "ping":
+		if cv.send != nil {
+			return ""
+		}
+		return string(SSEPingFormatComment)
+	case "error":
+		cv.failed = true
+		return cv.emit([]apicompat.ResponsesStreamEvent{cv.failedEvent([]byte(payload))})
+	}
+
+	var evt apicompat.AnthropicStreamEvent
+	if err := json.Unmarshal([]byte(payload), &evt); err != nil {
+		return ""
+	}
+	return cv.emit(apicompat.AnthropicEventToResponsesEvents(&evt, cv.state))
+}
+
+func (cv *responsesConverter) streamEnd() string {
+	if cv.failed {
+		return ""
+	}
+	return cv.emit(apicompat.FinalizeAnthropicResponsesStream(cv.state))
+}
+
+func (cv *responsesConverter) convertBody(status int, body []byte) []byte {
+	if status >= http.StatusBadRequest {
+		cv.failed = true
+		converted := anthropicErrorToOpenAI(body)
+		if cv.send != nil {
+			errObj, _ := converted["error"].(gin.H)
+			errType, _ := errObj["type"].(string)
+			message, _ := errObj["message"].(string)
+			cv.sendPayload(responsesWSErrorEvent(status, errType, message))
+			return body
+		}
+		if data, err := json.Marshal(converted); err == nil {
+			return data
+		}
+		return body
+	}
+
+	var resp apicompat.AnthropicResponse
+	if err := json.Unmarshal(body, &resp); err != nil || resp.Type != "message" {
+		return body
+	}
+	cv.final = apicompat.AnthropicResponseToResponses(&resp, cv.state.Model)
+	if cv.send != nil {
+		evtType := "response.completed"
+		if cv.final.Status == "incomplete" {
+			evtType = "response.incomplete"
+		}
+		cv.sendPayload(apicompat.ResponsesStreamEvent{Type: evtType, Response: cv.final})
+		return body
+	}
+	if data, err := json.Marshal(cv.final); err == nil {
+		return data
+	}
+	return body
+}
+
+// failedEvent builds a response.failed event from an Anthropic stream error.
+func (cv *responsesConverter) failedEvent(payload []byte) apicompat.ResponsesStreamEvent {
+	converted := anthropicErrorToOpenAI(payload)
+	errObj, _ := converted["error"].(gin.H)
+	code, _ := errObj["type"].(string)
+	message, _ := errObj["message"].(string)
+	if code == "rate_limit_error" {
+		code = "rate_limit_exceeded"
+	}
+
+	state := cv.state
+	if state.ResponseID == "" {
+		state.ResponseID = fmt.Sprintf("resp_%d", time.Now().UnixNano())
+	}
+	return apicompat.ResponsesStreamEvent{
+		Type: "response.failed",
+		Response: &apicompat.ResponsesResponse{
+			ID:        state.ResponseID,
+			Object:    "response",
+			CreatedAt: state.CreatedAt,
+			Model:     state.Model,
+			Status:    "failed",
+			Output:    append([]apicompat.ResponsesOutput{}, state.Output...),
+			Error:     &apicompat.ResponsesError{Code: code, Message: message},
+		},
+		SequenceNumber: state.SequenceNumber,
+	}
+}
+
+// emit encodes events as SSE text, or sends them over the WebSocket.
+func (cv *responsesConverter) emit(events []apicompat.ResponsesStreamEvent) string {
+	var out strings.Builder
+	for _, evt := range events {
+		if cv.send != nil {
+			cv.sendPayload(evt)
+			continue
+		}
+		sse, err := apicompat.ResponsesEventToSSE(evt)
+		if err != nil {
+			continue
+		}
+		out.WriteString(sse)
+	}
+	return out.String()
+}
+
+func (cv *responsesConverter) sendPayload(v any) {
+	if cv.sendErr != nil {
+		return
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return
+	}
+	cv.sendErr = cv.send(data)
+}
@@ -0,0 +1,171 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Wei-Shaw/sub2api/internal/pkg/apicompat"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+)
+
+func newResponsesWriterTestContext(send func([]byte) error) (*gin.Context, *httptest.ResponseRecorder, *anthropicCompatWriter, *responsesConverter) {
+	gin.SetMode(gin.TestMode)
+	rec := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(rec)
+	c.Request = httptest.NewRequest(http.MethodPost, "/v1/responses", nil)
+	conv := newResponsesConverter("claude-sonnet-4-5", send)
+	w := newAnthropicCompatWriter(c.Writer, conv)
+	w.detached = send != nil
+	c.Writer = w
+	return c, rec, w, conv
+}
+
+var responsesWriterTestStream = []string{
+	"event: message_start\ndata: {\"type\":\"message_start\",\"message\":{\"id\":\"msg_1\",\"model\":\"claude-sonnet-4-5\",\"usage\":{\"input_tokens\":12,\"output_tokens\":1}}}\n\n",
+	"data: {\"type\": \"ping\"}\n\n",
+	"event: content_block_start\ndata: {\"type\":\"content_block_start\",\"index\":0,\"content_block\":{\"type\":\"text\",\"text\":\"\"}}\n\n",
+	"event: content_block_delta\ndata: {\"type\":\"content_block_delta\",\"index\":0,\"delta\":{\"type\":\"text_delta\",\"text\":\"Hi\"}}\n\n",
+	"event: content_block_stop\ndata: {\"type\":\"content_block_stop\",\"index\":0}\n\n",
+	"event: message_delta\ndata: {\"type\":\"message_delta\",\"delta\":{\"stop_reason\":\"end_turn\"},\"usage\":{\"output_tokens\":4}}\n\n",
+	"event: message_stop\ndata: {\"type\":\"message_stop\"}\n\n",
+}
+
+func TestResponsesWriter_StreamConversion(t *testing.T) {
+	c, rec, w, conv := newResponsesWriterTestContext(nil)
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Writer.WriteHeader(http.StatusOK)
+	for _, e := range responsesWriterTestStream {
+		_, err := c.Writer.WriteString(e)
+		require.NoError(t, err)
+		c.Writer.Flush()
+	}
+	w.finish()
+
+	body := rec.Body.String()
+	require.Contains(t, body, "event: response.created\n")
+	require.Contains(t, body, "event: response.output_text.delta\n")
+	require.Contains(t, body, `"delta":"Hi"`)
+	require.Contains(t, body, "event: response.completed\n")
+	require.NotContains(t, body, "message_start")
+
+	result := conv.result()
+	require.NotNil(t, result)
+	require.Equal(t, "resp_1", result.ID)
+	require.Equal(t, "Hi", result.Output[0].Content[0].Text)
+}
+
+func TestResponsesWriter_StreamErrorEvent(t *testing.T) {
+	c, rec, w, conv := newResponsesWriterTestContext(nil)
+
+	c.Header("Content-Type", "text/event-stream")
+	_, _ = c.Writer.WriteString(responsesWriterTestStream[0])
+	_, _ = c.Writer.WriteString(`data: {"type":"error","error":{"type":"overloaded_error","message":"busy"}}` + "\n\n")
+	w.finish()
+
+	body := rec.Body.String()
+	require.Contains(t, body, "event: response.failed\n")
+	require.Contains(t, body, `"message":"busy"`)
+	require.NotContains(t, body, "response.completed")
+	require.Nil(t, conv.result())
+}
+
+func TestResponsesWriter_NonStreamConversion(t *testing.T) {
+	c, rec, w, conv := newResponsesWriterTestContext(nil)
+
+	c.JSON(http.StatusOK, gin.H{
+		"id":          "msg_2",
+		"type":        "message",
+		"role":        "assistant",
+		"content":     []gin.H{{"type": "tool_use", "id": "toolu_1", "name": "ls", "input": gin.H{}}},
+		"stop_reason": "tool_use",
+		"usage":       gin.H{"input_tokens": 3, "output_tokens": 2},
+	})
+	w.finish()
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, "response", gjson.Get(rec.Body.String(), "object").String())
+	require.Equal(t, "function_call", gjson.Get(rec.Body.String(), "output.0.type").String())
+	require.Equal(t, "toolu_1", gjson.Get(rec.Body.String(), "output.0.call_id").String())
+	require.NotNil(t, conv.result())
+}
+
+func TestResponsesWriter_WebSocketSink(t *testing.T) {
+	var sent []string
+	c, rec, w, _ := newResponsesWriterTestContext(func(payload []byte) error {
+		sent = append(sent, gjson.GetBytes(payload, "type").String())
+		return nil
+	})
+
+	c.Header("Content-Type", "text/event-stream")
+	for _, e := range responsesWriterTestStream {
+		_, _ = c.Writer.WriteString(e)
+	}
+	w.finish()
+
+	require.Equal(t, 0, rec.Body.Len())
+	require.Equal(t, "response.created", sent[0])
+	require.Equal(t, "response.completed", sent[len(sent)-1])
+	require.NotContains(t, sent, "ping")
+}
+
+func TestResponsesWriter_WebSocketError(t *testing.T) {
+	var sent [][]byte
+	c, rec, w, conv := newResponsesWriterTestContext(func(payload []byte) error {
+		sent = append(sent, payload)
+		return nil
+	})
+
+	c.JSON(http.StatusTooManyRequests, gin.H{
+		"type":  "error",
+		"error": gin.H{"type": "rate_limit_error", "message": "slow down"},
+	})
+	w.finish()
+
+	require.Equal(t, 0, rec.Body.Len())
+	require.Len(t, sent, 1)
+	require.JSONEq(t, `{"type":"error","status":429,"error":{"type":"rate_limit_error","message":"slow down"}}`, string(sent[0]))
+	require.Nil(t, conv.result())
+}
+
+func TestResponsesWSCreatePayload(t *testing.T) {
+	payload, err := responsesWSCreatePayload([]byte(`{"type":"response.create","model":"m","input":"hi"}`))
+	require.NoError(t, err)
+	require.JSONEq(t, `{"model":"m","input":"hi","stream":true}`, string(payload))
+
+	payload, err = responsesWSCreatePayload([]byte(`{"type":"response.create","response":{"model":"m"}}`))
+	require.NoError(t, err)
+	require.JSONEq(t, `{"model":"m","stream":true}`, string(payload))
+
+	_, err = responsesWSCreatePayload([]byte(`{"type":"session.update"}`))
+	require.Error(t, err)
+}
+
+func TestBuildResponsesTurn_PreviousResponseID(t *testing.T) {
+	h := &GatewayHandler{}
+	history := &service.ResponsesConversation{
+		Model: "claude-sonnet-4-5",
+		Items: []apicompat.ResponsesInputItem{
+			{Role: "user", Content: json.RawMessage(`"first"`)},
+			{Type: "message", Role: "assistant", Content: json.RawMessage(`[{"type":"output_text","text":"answer"}]`)},
+		},
+	}
+
+	turn, turnErr := h.buildResponsesTurn(t.Context(), 1,
+		[]byte(`{"model":"claude-sonnet-4-5","previous_response_id":"resp_1","input":"second"}`), history, false)
+	require.Nil(t, turnErr)
+	require.Len(t, turn.items, 3)
+	require.Equal(t, int64(3), gjson.GetBytes(turn.body, "messages.#").Int())
+	require.Equal(t, "second", gjson.GetBytes(turn.body, "messages.2.content.0.text").String())
+
+	_, turnErr = h.buildResponsesTurn(t.Context(), 1,
+		[]byte(`{"model":"claude-sonnet-4-5","previous_response_id":"resp_missing","input":"x"}`), nil, false)
+	require.NotNil(t, turnErr)
+	require.Equal(t, http.StatusBadRequest, turnErr.status)
+	require.Contains(t, turnErr.message, "resp_missing")
+}
@@ -13,6 +13,7 @@ import (
 
 	"github.com/Wei-Shaw/sub2api/internal/domain"
 	"github.com/Wei-Shaw/sub2api/internal/pkg/antigravity"
+	pkgerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
 	"github.com/Wei-Shaw/sub2api/internal/pkg/gemini"
 	"github.com/Wei-Shaw/sub2api/internal/pkg/googleapi"
 	pkghttputil "github.com/Wei-Shaw/sub2api/internal/pkg/httputil"
@@ -47,7 +48,7 @@ func (h *GatewayHandler) GeminiV1BetaListModels(c *gin.Context) {
 
 	// 强制 antigravity 模式：返回 antigravity 支持的模型列表
 	if forcePlatform == service.PlatformAntigravity {
-		c.JSON(http.StatusOK, antigravity.FallbackGeminiModelsList())
+		c.JSON(http.StatusOK, filterAntigravityGeminiModelsList(apiKey, antigravity.FallbackGeminiModelsList()))
 		return
 	}
 
@@ -57,7 +58,7 @@ func (h *GatewayHandler) GeminiV1BetaListModels(c *gin.Context) {
 		hasAntigravity, _ := h.geminiCompatService.HasAntigravityAccounts(c.Request.Context(), apiKey.GroupID)
 		if hasAntigravity {
 			// antigravity 账户使用静态模型列表
-			c.JSON(http.StatusOK, gemini.FallbackModelsList())
+			c.JSON(http.StatusOK, filterGeminiModelsList(apiKey, gemini.FallbackModelsList()))
 			return
 		}
 		googleError(c, http.StatusServiceUnavailable, "No available Gemini accounts: "+err.Error())
@@ -70,9 +71,12 @@ func (h *GatewayHandler) GeminiV1BetaListModels(c *gin.Context) {
 		return
 	}
 	if shouldFallbackGeminiModels(res) {
-		c.JSON(http.StatusOK, gemini.FallbackModelsList())
+		c.JSON(http.StatusOK, filterGeminiModelsList(apiKey, gemini.FallbackModelsList()))
 		return
 	}
+	if res.StatusCode == http.StatusOK {
+		res.Body = filterGeminiModelsListBody(apiKey, res.Body)
+	}
 	writeUpstreamResponse(c, res)
 }
 
@@ -96,6 +100,10 @@ func (h *GatewayHandler) GeminiV1BetaGetModel(c *gin.Context) {
 		googleError(c, http.StatusBadRequest, "Missing model in URL")
 		return
 	}
+	if !apiKey.IsModelAllowed(modelName) {
+		googleError(c, http.StatusNotFound, "models/"+strings.TrimPrefix(modelName, "models/")+" is not found")
+		return
+	}
 
 	// 强制 antigravity 模式：返回 antigravity 模型信息
 	if forcePlatform == service.PlatformAntigravity {
@@ -164,9 +172,6 @@ func (h *GatewayHandler) GeminiV1BetaModels(c *gin.Context) {
 		return
 	}
 
-	stream := action == "streamGenerateContent"
-	reqLog = reqLog.With(zap.String("model", modelName), zap.String("action", action), zap.Bool("stream", stream))
-
 	body, err := pkghttputil.ReadRequestBodyWithPrealloc(c.Request)
 	if err != nil {
 		if maxErr, ok := extractMaxBytesError(err); ok {
@@ -181,14 +186,49 @@ func (h *GatewayHandler) GeminiV1BetaModels(c *gin.Context) {
 		return
 	}
 
+	h.forwardGeminiGenerateContent(c, apiKey, authSubject, reqLog, modelName, action, body)
+}
+
+// forwardGeminiGenerateContent serves a native generateContent /
+// streamGenerateContent request body: scheduling, failover, forwarding and
+// usage recording. Callers have already checked the group platform; errors
+// are written in Google API format.
+func (h *GatewayHandler) forwardGeminiGenerateContent(c *gin.Context, apiKey *service.APIKey, authSubject middleware.AuthSubject, reqLog *zap.Logger, modelName, action string, body []byte) {
+	stream := action == "streamGenerateContent"
+	reqLog = reqLog.With(zap.String("model", modelName), zap.String("action", action), zap.Bool("stream", stream))
+
 	setOpsRequestContext(c, modelName, stream, body)
 
+	// 检查 API Key 模型白名单/黑名单（在调度账号之前拒绝）
+	if !apiKey.IsModelAllowed(modelName) {
+		reqLog.Info("gemini.model_denied_by_api_key")
+		googleError(c, http.StatusForbidden, apiKeyModelDeniedMessage(modelName))
+		return
+	}
+
 	// Get subscription (may be nil)
 	subscription, _ := middleware.GetSubscriptionFromContext(c)
 
 	// For Gemini native API, do not send Claude-style ping frames.
 	geminiConcurrency := NewConcurrencyHelper(h.concurrencyHelper.concurrencyService, SSEPingFormatNone, 0)
 
+	// API key traffic limits (RPM/TPM + per-key concurrency), before the user slot
+	streamStarted := false
+	if err := admitAPIKeyTraffic(c, h.apiKeyTrafficService, apiKey, rateLimitHeadersOpenAI); err != nil {
+		reqLog.Info("gemini.api_key_traffic_limited", zap.Error(err))
+		googleError(c, http.StatusTooManyRequests, pkgerrors.Message(err))
+		return
+	}
+	apiKeyReleaseFunc, err := geminiConcurrency.AcquireAPIKeySlotWithWait(c, apiKey.ID, apiKey.MaxConcurrency, stream, &streamStarted)
+	if err != nil {
+		reqLog.Warn("gemini.api_key_slot_acquire_failed", zap.Error(err))
+		googleError(c, http.StatusTooManyRequests, err.Error())
+		return
+	}
+	if apiKeyReleaseFunc != nil {
+		defer apiKeyReleaseFunc()
+	}
+
 	// 0) wait queue check
 	maxWait := service.CalculateMaxWait(authSubject.Concurrency)
 	canWait, err := geminiConcurrency.IncrementWaitCount(c.Request.Context(), authSubject.UserID, maxWait)
@@ -210,7 +250,6 @@ func (h *GatewayHandler) GeminiV1BetaModels(c *gin.Context) {
 	}()
 
 	// 1) user concurrency slot
-	streamStarted := false
 	if h.errorPassthroughService != nil {
 		service.BindErrorPassthroughService(c, h.errorPassthroughService)
 	}
@@ -238,6 +277,23 @@ func (h *GatewayHandler) GeminiV1BetaModels(c *gin.Context) {
 		return
 	}
 
+	// 按预估最大费用冻结额度；成功转发后交由使用量记录结算，其余退出路径释放
+	billingHold, err := h.billingHoldService.Reserve(c.Request.Context(), service.BillingHoldRequest{
+		User:         apiKey.User,
+		APIKey:       apiKey,
+		Group:        apiKey.Group,
+		Subscription: subscription,
+		Model:        modelName,
+		Body:         body,
+	})
+	if err != nil {
+		reqLog.Info("gemini.billing_hold_rejected", zap.Error(err))
+		status, _, message := billingErrorDetails(err)
+		googleError(c, status, message)
+		return
+	}
+	defer func() { billingHold.Release() }()
+
 	// 3) select account (sticky session based on request body)
 	// 优先使用 Gemini CLI 的会话标识（privileged-user-id + tmp 目录哈希）
 	sessionHash := extractGeminiCLISessionHash(c, body)
@@ -502,8 +558,12 @@ func (h *GatewayHandler) GeminiV1BetaModels(c *gin.Context) {
 			}
 		}
 
+		// 冻结交由使用量记录结算
+		usageHold := billingHold
+		billingHold = nil
+
 		// 使用量记录通过有界 worker 池提交，避免请求热路径创建无界 goroutine。
-		h.submitUsageRecordTask(func(ctx context.Context) {
+		h.submitUsageRecordTask(c.Request.Context(), func(ctx context.Context) {
 			if err := h.gatewayService.RecordUsageWithLongContext(ctx, &service.RecordUsageLongContextInput{
 				Result:                result,
 				APIKey:                apiKey,
@@ -516,6 +576,7 @@ func (h *GatewayHandler) GeminiV1BetaModels(c *gin.Context) {
 				LongContextMultiplier: 2.0,    // 超出部分双倍计费
 				ForceCacheBilling:     fs.ForceCacheBilling,
 				APIKeyService:         h.apiKeyService,
+				BillingHold:           usageHold,
 			}); err != nil {
 				logger.L().With(
 					zap.String("component", "handler.gemini_v1beta.models"),
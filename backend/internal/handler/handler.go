@@ -28,16 +28,31 @@ type AdminHandlers struct {
 	UserAttribute    *admin.UserAttributeHandler
 	ErrorPassthrough *admin.ErrorPassthroughHandler
 	APIKey           *admin.AdminAPIKeyHandler
+	BalanceLedger    *admin.BalanceLedgerHandler
+	SSOProvider      *admin.SSOProviderHandler
+	UserSession      *admin.UserSessionHandler
+	Payment          *admin.PaymentHandler
+	Referral         *admin.ReferralHandler
+	Points           *admin.PointsHandler
+	Coupon           *admin.CouponHandler
 }
 
 // Handlers contains all HTTP handlers
 type Handlers struct {
 	Auth          *AuthHandler
+	SSO           *SSOHandler
 	User          *UserHandler
 	APIKey        *APIKeyHandler
 	Usage         *UsageHandler
 	Voice         *VoiceHandler
 	Redeem        *RedeemHandler
+	Organization  *OrganizationHandler
+	Payment       *PaymentHandler
+	Referral      *ReferralHandler
+	Points        *PointsHandler
+	Coupon        *CouponHandler
+	Promo         *PromoHandler
+	Activity      *ActivityHandler
 	Subscription  *SubscriptionHandler
 	Announcement  *AnnouncementHandler
 	Distributor   *DistributorHandler
@@ -46,8 +61,10 @@ type Handlers struct {
 	OpenAIGateway *OpenAIGatewayHandler
 	SoraGateway   *SoraGatewayHandler
 	SoraClient    *SoraClientHandler
+	Batch         *BatchHandler
 	Setting       *SettingHandler
 	Totp          *TotpHandler
+	Security      *SecurityHandler
 }
 
 // BuildInfo contains build-time information
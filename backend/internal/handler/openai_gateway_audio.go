@@ -0,0 +1,279 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	pkghttputil "github.com/Wei-Shaw/sub2api/internal/pkg/httputil"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/ip"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/logger"
+	middleware2 "github.com/Wei-Shaw/sub2api/internal/server/middleware"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tidwall/gjson"
+	"go.uber.org/zap"
+)
+
+// audioMultipartMaxMemory 与 ImageEdits 保持一致：32MB 以内驻留内存，超出部分落盘
+const audioMultipartMaxMemory = 32 << 20
+
+type openAIAudioForwardFunc func(ctx context.Context, c *gin.Context, account *service.Account) (*service.OpenAIForwardResult, error)
+
+// AudioTranscriptions handles OpenAI /v1/audio/transcriptions (multipart/form-data).
+func (h *OpenAIGatewayHandler) AudioTranscriptions(c *gin.Context) {
+	h.handleAudioMultipart(c, "handler.openai_gateway.audio_transcriptions", service.OpenAIAudioEndpointTranscriptions)
+}
+
+// AudioTranslations handles OpenAI /v1/audio/translations (multipart/form-data).
+func (h *OpenAIGatewayHandler) AudioTranslations(c *gin.Context) {
+	h.handleAudioMultipart(c, "handler.openai_gateway.audio_translations", service.OpenAIAudioEndpointTranslations)
+}
+
+func (h *OpenAIGatewayHandler) handleAudioMultipart(c *gin.Context, component string, endpoint string) {
+	setOpenAIClientTransportHTTP(c)
+
+	if err := c.Request.ParseMultipartForm(audioMultipartMaxMemory); err != nil {
+		if maxErr, ok := extractMaxBytesError(err); ok {
+			h.errorResponse(c, http.StatusRequestEntityTooLarge, "invalid_request_error", buildBodyTooLargeMessage(maxErr.Limit))
+			return
+		}
+		h.errorResponse(c, http.StatusBadRequest, "invalid_request_error", "Failed to parse multipart form")
+		return
+	}
+	reqModel := strings.TrimSpace(c.Request.FormValue("model"))
+	if reqModel == "" {
+		h.errorResponse(c, http.StatusBadRequest, "invalid_request_error", "model is required")
+		return
+	}
+	if c.Request.MultipartForm == nil || len(c.Request.MultipartForm.File["file"]) == 0 {
+		h.errorResponse(c, http.StatusBadRequest, "invalid_request_error", "file is required")
+		return
+	}
+	stream := strings.EqualFold(strings.TrimSpace(c.Request.FormValue("stream")), "true")
+	hold := service.BillingHoldRequest{AudioSeconds: service.EstimateAudioFileDurationSeconds(c.Request.MultipartForm.File["file"][0])}
+
+	h.forwardAudio(c, component, reqModel, stream, nil, hold, func(ctx context.Context, c *gin.Context, account *service.Account) (*service.OpenAIForwardResult, error) {
+		return h.gatewayService.ForwardAudioTranscription(ctx, c, account, endpoint)
+	})
+}
+
+// AudioSpeech handles OpenAI /v1/audio/speech (JSON in, binary audio out).
+func (h *OpenAIGatewayHandler) AudioSpeech(c *gin.Context) {
+	setOpenAIClientTransportHTTP(c)
+
+	body, err := pkghttputil.ReadRequestBodyWithPrealloc(c.Request)
+	if err != nil {
+		if maxErr, ok := extractMaxBytesError(err); ok {
+			h.errorResponse(c, http.StatusRequestEntityTooLarge, "invalid_request_error", buildBodyTooLargeMessage(maxErr.Limit))
+			return
+		}
+		h.errorResponse(c, http.StatusBadRequest, "invalid_request_error", "Failed to read request body")
+		return
+	}
+	if len(body) == 0 || !gjson.ValidBytes(body) {
+		h.errorResponse(c, http.StatusBadRequest, "invalid_request_error", "Failed to parse request body")
+		return
+	}
+	reqModel := strings.TrimSpace(gjson.GetBytes(body, "model").String())
+	if reqModel == "" {
+		h.errorResponse(c, http.StatusBadRequest, "invalid_request_error", "model is required")
+		return
+	}
+	input := gjson.GetBytes(body, "input").String()
+	if strings.TrimSpace(input) == "" {
+		h.errorResponse(c, http.StatusBadRequest, "invalid_request_error", "input is required")
+		return
+	}
+	hold := service.BillingHoldRequest{AudioCharacters: utf8.RuneCountInString(input)}
+
+	h.forwardAudio(c, "handler.openai_gateway.audio_speech", reqModel, true, body, hold, func(ctx context.Context, c *gin.Context, account *service.Account) (*service.OpenAIForwardResult, error) {
+		return h.gatewayService.ForwardAudioSpeech(ctx, c, account, body)
+	})
+}
+
+// forwardAudio 复用 Responses 的调度、并发槽位与 failover 流程；OAuth 账号没有音频接口，调度时跳过。
+// hold 携带按音频时长或字符数估算冻结所需的字段，其余字段在此补齐。
+func (h *OpenAIGatewayHandler) forwardAudio(c *gin.Context, component string, reqModel string, stream bool, body []byte, hold service.BillingHoldRequest, forward openAIAudioForwardFunc) {
+	apiKey, ok := middleware2.GetAPIKeyFromContext(c)
+	if !ok {
+		h.errorResponse(c, http.StatusUnauthorized, "authentication_error", "Invalid API key")
+		return
+	}
+	subject, ok := middleware2.GetAuthSubjectFromContext(c)
+	if !ok {
+		h.errorResponse(c, http.StatusInternalServerError, "api_error", "User context not found")
+		return
+	}
+	reqLog := requestLogger(
+		c,
+		component,
+		zap.Int64("user_id", subject.UserID),
+		zap.Int64("api_key_id", apiKey.ID),
+		zap.Any("group_id", apiKey.GroupID),
+		zap.String("model", reqModel),
+	)
+	if !h.ensureResponsesDependencies(c, reqLog) {
+		return
+	}
+	if !apiKey.IsModelAllowed(reqModel) {
+		h.errorResponse(c, http.StatusForbidden, "permission_error", apiKeyModelDeniedMessage(reqModel))
+		return
+	}
+	setOpsRequestContext(c, reqModel, stream, body)
+
+	if h.errorPassthroughService != nil {
+		service.BindErrorPassthroughService(c, h.errorPassthroughService)
+	}
+	subscription, _ := middleware2.GetSubscriptionFromContext(c)
+
+	streamStarted := false
+	apiKeyReleaseFunc, acquired := h.acquireAPIKeyTrafficSlot(c, apiKey, rateLimitHeadersOpenAI, false, &streamStarted, reqLog)
+	if !acquired {
+		return
+	}
+	if apiKeyReleaseFunc != nil {
+		defer apiKeyReleaseFunc()
+	}
+	userReleaseFunc, acquired := h.acquireResponsesUserSlot(c, subject.UserID, subject.Concurrency, false, &streamStarted, reqLog)
+	if !acquired {
+		return
+	}
+	if userReleaseFunc != nil {
+		defer userReleaseFunc()
+	}
+
+	if err := h.billingCacheService.CheckBillingEligibility(c.Request.Context(), apiKey.User, apiKey, apiKey.Group, subscription); err != nil {
+		reqLog.Info("openai.billing_eligibility_check_failed", zap.Error(err))
+		status, code, message := billingErrorDetails(err)
+		h.errorResponse(c, status, code, message)
+		return
+	}
+
+	// Hold the estimated audio cost; handed to usage recording on success, released on every other exit
+	hold.User = apiKey.User
+	hold.APIKey = apiKey
+	hold.Group = apiKey.Group
+	hold.Subscription = subscription
+	hold.Model = reqModel
+	billingHold, err := h.billingHoldService.Reserve(c.Request.Context(), hold)
+	if err != nil {
+		reqLog.Info("openai.billing_hold_rejected", zap.Error(err))
+		status, code, message := billingErrorDetails(err)
+		h.errorResponse(c, status, code, message)
+		return
+	}
+	defer func() { billingHold.Release() }()
+
+	switchCount := 0
+	failedAccountIDs := make(map[int64]struct{})
+	var lastFailoverErr *service.UpstreamFailoverError
+
+	for {
+		selection, _, err := h.gatewayService.SelectAccountWithScheduler(
+			c.Request.Context(),
+			apiKey.GroupID,
+			"",
+			"",
+			reqModel,
+			failedAccountIDs,
+			service.OpenAIUpstreamTransportAny,
+		)
+		if err != nil {
+			reqLog.Warn("openai.account_select_failed",
+				zap.Error(err),
+				zap.Int("excluded_account_count", len(failedAccountIDs)),
+			)
+			if lastFailoverErr != nil {
+				h.handleFailoverExhausted(c, lastFailoverErr, false)
+				return
+			}
+			h.errorResponse(c, http.StatusServiceUnavailable, "api_error", "No available accounts support audio")
+			return
+		}
+		if selection == nil || selection.Account == nil {
+			h.errorResponse(c, http.StatusServiceUnavailable, "api_error", "No available accounts")
+			return
+		}
+		account := selection.Account
+		if account.IsOpenAIOAuth() {
+			// ChatGPT OAuth 账号没有音频接口，直接排除且不计入切换次数
+			if selection.Acquired && selection.ReleaseFunc != nil {
+				selection.ReleaseFunc()
+			}
+			failedAccountIDs[account.ID] = struct{}{}
+			continue
+		}
+		setOpsSelectedAccount(c, account.ID, account.Platform)
+
+		accountReleaseFunc, acquired := h.acquireResponsesAccountSlot(c, apiKey.GroupID, "", selection, false, &streamStarted, reqLog)
+		if !acquired {
+			return
+		}
+		result, err := forward(c.Request.Context(), c, account)
+		if accountReleaseFunc != nil {
+			accountReleaseFunc()
+		}
+		if err != nil {
+			var failoverErr *service.UpstreamFailoverError
+			if errors.As(err, &failoverErr) {
+				h.gatewayService.ReportOpenAIAccountScheduleResult(account.ID, false, nil)
+				h.gatewayService.RecordOpenAIAccountSwitch()
+				failedAccountIDs[account.ID] = struct{}{}
+				lastFailoverErr = failoverErr
+				if switchCount >= h.maxAccountSwitches {
+					h.handleFailoverExhausted(c, failoverErr, false)
+					return
+				}
+				switchCount++
+				recordFailoverSwitch(c.Request.Context(), account.ID, failoverErr.StatusCode, switchCount)
+				reqLog.Warn("openai.upstream_failover_switching",
+					zap.Int64("account_id", account.ID),
+					zap.Int("upstream_status", failoverErr.StatusCode),
+					zap.Int("switch_count", switchCount),
+				)
+				continue
+			}
+			h.gatewayService.ReportOpenAIAccountScheduleResult(account.ID, false, nil)
+			wroteFallback := h.ensureForwardErrorResponse(c, false)
+			reqLog.Warn("openai.audio_forward_failed",
+				zap.Int64("account_id", account.ID),
+				zap.Bool("fallback_error_response_written", wroteFallback),
+				zap.Error(err),
+			)
+			return
+		}
+		h.gatewayService.ReportOpenAIAccountScheduleResult(account.ID, true, result.FirstTokenMs)
+
+		userAgent := c.GetHeader("User-Agent")
+		clientIP := ip.GetClientIP(c)
+		usageHold := billingHold
+		billingHold = nil
+		h.submitUsageRecordTask(c.Request.Context(), func(ctx context.Context) {
+			if err := h.gatewayService.RecordUsage(ctx, &service.OpenAIRecordUsageInput{
+				Result:        result,
+				APIKey:        apiKey,
+				User:          apiKey.User,
+				Account:       account,
+				Subscription:  subscription,
+				UserAgent:     userAgent,
+				IPAddress:     clientIP,
+				APIKeyService: h.apiKeyService,
+				BillingHold:   usageHold,
+			}); err != nil {
+				logger.L().With(
+					zap.String("component", component),
+					zap.Int64("user_id", subject.UserID),
+					zap.Int64("api_key_id", apiKey.ID),
+					zap.Any("group_id", apiKey.GroupID),
+					zap.String("model", reqModel),
+					zap.Int64("account_id", account.ID),
+				).Error("openai.record_usage_failed", zap.Error(err))
+			}
+		})
+		return
+	}
+}
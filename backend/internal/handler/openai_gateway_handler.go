@@ -15,6 +15,7 @@ import (
 	"time"
 
 	"github.com/Wei-Shaw/sub2api/internal/config"
+	pkgerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
 	pkghttputil "github.com/Wei-Shaw/sub2api/internal/pkg/httputil"
 	"github.com/Wei-Shaw/sub2api/internal/pkg/ip"
 	"github.com/Wei-Shaw/sub2api/internal/pkg/logger"
@@ -31,6 +32,8 @@ import (
 type OpenAIGatewayHandler struct {
 	gatewayService          *service.OpenAIGatewayService
 	billingCacheService     *service.BillingCacheService
+	billingHoldService      *service.BillingHoldService
+	apiKeyTrafficService    *service.APIKeyTrafficLimitService
 	apiKeyService           *service.APIKeyService
 	usageRecordWorkerPool   *service.UsageRecordWorkerPool
 	errorPassthroughService *service.ErrorPassthroughService
@@ -58,6 +61,8 @@ func NewOpenAIGatewayHandler(
 	gatewayService *service.OpenAIGatewayService,
 	concurrencyService *service.ConcurrencyService,
 	billingCacheService *service.BillingCacheService,
+	billingHoldService *service.BillingHoldService,
+	apiKeyTrafficService *service.APIKeyTrafficLimitService,
 	apiKeyService *service.APIKeyService,
 	usageRecordWorkerPool *service.UsageRecordWorkerPool,
 	errorPassthroughService *service.ErrorPassthroughService,
@@ -74,6 +79,8 @@ func NewOpenAIGatewayHandler(
 	return &OpenAIGatewayHandler{
 		gatewayService:          gatewayService,
 		billingCacheService:     billingCacheService,
+		billingHoldService:      billingHoldService,
+		apiKeyTrafficService:    apiKeyTrafficService,
 		apiKeyService:           apiKeyService,
 		usageRecordWorkerPool:   usageRecordWorkerPool,
 		errorPassthroughService: errorPassthroughService,
@@ -153,6 +160,12 @@ func (h *OpenAIGatewayHandler) Responses(c *gin.Context) {
 		return
 	}
 	reqModel := modelResult.String()
+	// 检查 API Key 模型白名单/黑名单（在调度账号之前拒绝）
+	if !apiKey.IsModelAllowed(reqModel) {
+		reqLog.Info("openai.model_denied_by_api_key", zap.String("model", reqModel))
+		h.errorResponse(c, http.StatusForbidden, "permission_error", apiKeyModelDeniedMessage(reqModel))
+		return
+	}
 
 	streamResult := gjson.GetBytes(body, "stream")
 	if streamResult.Exists() && streamResult.Type != gjson.True && streamResult.Type != gjson.False {
@@ -196,6 +209,14 @@ func (h *OpenAIGatewayHandler) Responses(c *gin.Context) {
 	service.SetOpsLatencyMs(c, service.OpsAuthLatencyMsKey, time.Since(requestStart).Milliseconds())
 	routingStart := time.Now()
 
+	apiKeyReleaseFunc, acquired := h.acquireAPIKeyTrafficSlot(c, apiKey, rateLimitHeadersOpenAI, reqStream, &streamStarted, reqLog)
+	if !acquired {
+		return
+	}
+	if apiKeyReleaseFunc != nil {
+		defer apiKeyReleaseFunc()
+	}
+
 	userReleaseFunc, acquired := h.acquireResponsesUserSlot(c, subject.UserID, subject.Concurrency, reqStream, &streamStarted, reqLog)
 	if !acquired {
 		return
@@ -213,6 +234,23 @@ func (h *OpenAIGatewayHandler) Responses(c *gin.Context) {
 		return
 	}
 
+	// Hold the estimated max cost; handed to usage recording on success, released on every other exit
+	billingHold, err := h.billingHoldService.Reserve(c.Request.Context(), service.BillingHoldRequest{
+		User:         apiKey.User,
+		APIKey:       apiKey,
+		Group:        apiKey.Group,
+		Subscription: subscription,
+		Model:        reqModel,
+		Body:         body,
+	})
+	if err != nil {
+		reqLog.Info("openai.billing_hold_rejected", zap.Error(err))
+		status, code, message := billingErrorDetails(err)
+		h.handleStreamingAwareError(c, status, code, message, streamStarted)
+		return
+	}
+	defer func() { billingHold.Release() }()
+
 	// Generate session hash (header first; fallback to prompt_cache_key)
 	sessionHash := h.gatewayService.GenerateSessionHash(c, body)
 
@@ -303,6 +341,7 @@ func (h *OpenAIGatewayHandler) Responses(c *gin.Context) {
 					return
 				}
 				switchCount++
+				recordFailoverSwitch(c.Request.Context(), account.ID, failoverErr.StatusCode, switchCount)
 				reqLog.Warn("openai.upstream_failover_switching",
 					zap.Int64("account_id", account.ID),
 					zap.Int("upstream_status", failoverErr.StatusCode),
@@ -336,7 +375,11 @@ func (h *OpenAIGatewayHandler) Responses(c *gin.Context) {
 		clientIP := ip.GetClientIP(c)
 
 		// 使用量记录通过有界 worker 池提交，避免请求热路径创建无界 goroutine。
-		h.submitUsageRecordTask(func(ctx context.Context) {
+		// Hand the hold over to usage recording for settlement
+		usageHold := billingHold
+		billingHold = nil
+
+		h.submitUsageRecordTask(c.Request.Context(), func(ctx context.Context) {
 			if err := h.gatewayService.RecordUsage(ctx, &service.OpenAIRecordUsageInput{
 				Result:        result,
 				APIKey:        apiKey,
@@ -346,6 +389,7 @@ func (h *OpenAIGatewayHandler) Responses(c *gin.Context) {
 				UserAgent:     userAgent,
 				IPAddress:     clientIP,
 				APIKeyService: h.apiKeyService,
+				BillingHold:   usageHold,
 			}); err != nil {
 				logger.L().With(
 					zap.String("component", "handler.openai_gateway.responses"),
@@ -403,6 +447,10 @@ func (h *OpenAIGatewayHandler) ImageGenerations(c *gin.Context) {
 		h.errorResponse(c, http.StatusBadRequest, "invalid_request_error", "model is required")
 		return
 	}
+	if !apiKey.IsModelAllowed(model) {
+		h.errorResponse(c, http.StatusForbidden, "permission_error", apiKeyModelDeniedMessage(model))
+		return
+	}
 	isNanoBanana := service.IsNanoBananaModel(model)
 	subscription, _ := middleware2.GetSubscriptionFromContext(c)
 	failedAccountIDs := make(map[int64]struct{})
@@ -438,7 +486,7 @@ func (h *OpenAIGatewayHandler) ImageGenerations(c *gin.Context) {
 		}
 		userAgent := c.GetHeader("User-Agent")
 		clientIP := ip.GetClientIP(c)
-		h.submitUsageRecordTask(func(ctx context.Context) {
+		h.submitUsageRecordTask(c.Request.Context(), func(ctx context.Context) {
 			_ = h.gatewayService.RecordUsage(ctx, &service.OpenAIRecordUsageInput{
 				Result:        result,
 				APIKey:        apiKey,
@@ -478,6 +526,10 @@ func (h *OpenAIGatewayHandler) NanoBananaDraw(c *gin.Context) {
 		h.errorResponse(c, http.StatusBadRequest, "invalid_request_error", "model is required")
 		return
 	}
+	if !apiKey.IsModelAllowed(model) {
+		h.errorResponse(c, http.StatusForbidden, "permission_error", apiKeyModelDeniedMessage(model))
+		return
+	}
 	if !service.IsNanoBananaModel(model) {
 		h.errorResponse(c, http.StatusBadRequest, "invalid_request_error", "model must be a nano-banana series model")
 		return
@@ -603,7 +655,7 @@ func (h *OpenAIGatewayHandler) maybeRecordNanoBananaTask(ctx context.Context, ta
 		result.ImageSize = "1K"
 	}
 	pending.Recorded = true
-	h.submitUsageRecordTask(func(taskCtx context.Context) {
+	h.submitUsageRecordTask(ctx, func(taskCtx context.Context) {
 		_ = h.gatewayService.RecordUsage(taskCtx, &service.OpenAIRecordUsageInput{
 			Result:        result,
 			APIKey:        pending.APIKey,
@@ -708,6 +760,11 @@ func (h *OpenAIGatewayHandler) Messages(c *gin.Context) {
 		return
 	}
 	reqModel := modelResult.String()
+	if !apiKey.IsModelAllowed(reqModel) {
+		reqLog.Info("openai_messages.model_denied_by_api_key", zap.String("model", reqModel))
+		h.anthropicErrorResponse(c, http.StatusForbidden, "permission_error", apiKeyModelDeniedMessage(reqModel))
+		return
+	}
 	reqStream := gjson.GetBytes(body, "stream").Bool()
 
 	reqLog = reqLog.With(zap.String("model", reqModel), zap.Bool("stream", reqStream))
@@ -719,6 +776,14 @@ func (h *OpenAIGatewayHandler) Messages(c *gin.Context) {
 	service.SetOpsLatencyMs(c, service.OpsAuthLatencyMsKey, time.Since(requestStart).Milliseconds())
 	routingStart := time.Now()
 
+	apiKeyReleaseFunc, acquired := h.acquireAPIKeyTrafficSlot(c, apiKey, rateLimitHeadersAnthropic, reqStream, &streamStarted, reqLog)
+	if !acquired {
+		return
+	}
+	if apiKeyReleaseFunc != nil {
+		defer apiKeyReleaseFunc()
+	}
+
 	userReleaseFunc, acquired := h.acquireResponsesUserSlot(c, subject.UserID, subject.Concurrency, reqStream, &streamStarted, reqLog)
 	if !acquired {
 		return
@@ -734,6 +799,23 @@ func (h *OpenAIGatewayHandler) Messages(c *gin.Context) {
 		return
 	}
 
+	// Hold the estimated max cost; handed to usage recording on success, released on every other exit
+	billingHold, err := h.billingHoldService.Reserve(c.Request.Context(), service.BillingHoldRequest{
+		User:         apiKey.User,
+		APIKey:       apiKey,
+		Group:        apiKey.Group,
+		Subscription: subscription,
+		Model:        reqModel,
+		Body:         body,
+	})
+	if err != nil {
+		reqLog.Info("openai_messages.billing_hold_rejected", zap.Error(err))
+		status, code, message := billingErrorDetails(err)
+		h.anthropicStreamingAwareError(c, status, code, message, streamStarted)
+		return
+	}
+	defer func() { billingHold.Release() }()
+
 	sessionHash := h.gatewayService.GenerateSessionHash(c, body)
 	promptCacheKey := h.gatewayService.ExtractSessionID(c, body)
 
@@ -813,6 +895,7 @@ func (h *OpenAIGatewayHandler) Messages(c *gin.Context) {
 					return
 				}
 				switchCount++
+				recordFailoverSwitch(c.Request.Context(), account.ID, failoverErr.StatusCode, switchCount)
 				reqLog.Warn("openai_messages.upstream_failover_switching",
 					zap.Int64("account_id", account.ID),
 					zap.Int("upstream_status", failoverErr.StatusCode),
@@ -839,7 +922,11 @@ func (h *OpenAIGatewayHandler) Messages(c *gin.Context) {
 		userAgent := c.GetHeader("User-Agent")
 		clientIP := ip.GetClientIP(c)
 
-		h.submitUsageRecordTask(func(ctx context.Context) {
+		// Hand the hold over to usage recording for settlement
+		usageHold := billingHold
+		billingHold = nil
+
+		h.submitUsageRecordTask(c.Request.Context(), func(ctx context.Context) {
 			if err := h.gatewayService.RecordUsage(ctx, &service.OpenAIRecordUsageInput{
 				Result:        result,
 				APIKey:        apiKey,
@@ -849,6 +936,7 @@ func (h *OpenAIGatewayHandler) Messages(c *gin.Context) {
 				UserAgent:     userAgent,
 				IPAddress:     clientIP,
 				APIKeyService: h.apiKeyService,
+				BillingHold:   usageHold,
 			}); err != nil {
 				logger.L().With(
 					zap.String("component", "handler.openai_gateway.messages"),
@@ -1007,6 +1095,51 @@ func (h *OpenAIGatewayHandler) acquireResponsesUserSlot(
 	return wrapReleaseOnDone(ctx, userReleaseFunc), true
 }
 
+// acquireAPIKeyTrafficSlot enforces the API key's RPM/TPM limits and concurrency slot.
+// It must run before the user slot so a key queued on its own limit does not hold user concurrency.
+func (h *OpenAIGatewayHandler) acquireAPIKeyTrafficSlot(
+	c *gin.Context,
+	apiKey *service.APIKey,
+	style rateLimitHeaderStyle,
+	reqStream bool,
+	streamStarted *bool,
+	reqLog *zap.Logger,
+) (func(), bool) {
+	if err := admitAPIKeyTraffic(c, h.apiKeyTrafficService, apiKey, style); err != nil {
+		reqLog.Info("openai.api_key_traffic_limited", zap.Error(err))
+		if style == rateLimitHeadersAnthropic {
+			h.anthropicErrorResponse(c, http.StatusTooManyRequests, "rate_limit_error", pkgerrors.Message(err))
+		} else {
+			h.errorResponse(c, http.StatusTooManyRequests, "rate_limit_error", pkgerrors.Message(err))
+		}
+		return nil, false
+	}
+
+	releaseFunc, err := h.concurrencyHelper.AcquireAPIKeySlotWithWait(c, apiKey.ID, apiKey.MaxConcurrency, reqStream, streamStarted)
+	if err != nil {
+		reqLog.Warn("openai.api_key_slot_acquire_failed", zap.Error(err))
+		h.handleConcurrencyError(c, err, "api_key", *streamStarted)
+		return nil, false
+	}
+	return releaseFunc, true
+}
+
+// acquireWSAPIKeyTurn applies the API key's RPM/TPM and concurrency limits to one WebSocket turn.
+// Each turn counts as a request; failures are returned as client close errors.
+func (h *OpenAIGatewayHandler) acquireWSAPIKeyTurn(ctx context.Context, apiKey *service.APIKey) (func(), error) {
+	if _, err := h.apiKeyTrafficService.Admit(ctx, apiKey); err != nil {
+		return nil, service.NewOpenAIWSClientCloseError(coderws.StatusTryAgainLater, pkgerrors.Message(err), err)
+	}
+	releaseFunc, acquired, err := h.concurrencyHelper.TryAcquireAPIKeySlot(ctx, apiKey.ID, apiKey.MaxConcurrency)
+	if err != nil {
+		return nil, service.NewOpenAIWSClientCloseError(coderws.StatusInternalError, "failed to acquire api key concurrency slot", err)
+	}
+	if !acquired {
+		return nil, service.NewOpenAIWSClientCloseError(coderws.StatusTryAgainLater, "too many concurrent requests for this api key, please retry later", nil)
+	}
+	return wrapReleaseOnDone(ctx, releaseFunc), nil
+}
+
 func (h *OpenAIGatewayHandler) acquireResponsesAccountSlot(
 	c *gin.Context,
 	groupID *int64,
@@ -1176,6 +1309,10 @@ func (h *OpenAIGatewayHandler) ResponsesWebSocket(c *gin.Context) {
 		closeOpenAIClientWS(wsConn, coderws.StatusPolicyViolation, "model is required in first response.create payload")
 		return
 	}
+	if !apiKey.IsModelAllowed(reqModel) {
+		closeOpenAIClientWS(wsConn, coderws.StatusPolicyViolation, apiKeyModelDeniedMessage(reqModel))
+		return
+	}
 	previousResponseID := strings.TrimSpace(gjson.GetBytes(firstMessage, "previous_response_id").String())
 	previousResponseIDKind := service.ClassifyOpenAIPreviousResponseIDKind(previousResponseID)
 	if previousResponseID != "" && previousResponseIDKind == service.OpenAIPreviousResponseIDKindMessageID {
@@ -1190,6 +1327,7 @@ func (h *OpenAIGatewayHandler) ResponsesWebSocket(c *gin.Context) {
 	)
 	setOpsRequestContext(c, reqModel, true, firstMessage)
 
+	var currentAPIKeyRelease func()
 	var currentUserRelease func()
 	var currentAccountRelease func()
 	releaseTurnSlots := func() {
@@ -1201,10 +1339,25 @@ func (h *OpenAIGatewayHandler) ResponsesWebSocket(c *gin.Context) {
 			currentUserRelease()
 			currentUserRelease = nil
 		}
+		if currentAPIKeyRelease != nil {
+			currentAPIKeyRelease()
+			currentAPIKeyRelease = nil
+		}
 	}
 	// 必须尽早注册，确保任何 early return 都能释放已获取的并发槽位。
 	defer releaseTurnSlots()
 
+	apiKeyReleaseFunc, err := h.acquireWSAPIKeyTurn(ctx, apiKey)
+	if err != nil {
+		reqLog.Info("openai.websocket_api_key_limited", zap.Error(err))
+		var closeErr *service.OpenAIWSClientCloseError
+		if errors.As(err, &closeErr) {
+			closeOpenAIClientWS(wsConn, closeErr.StatusCode(), closeErr.Reason())
+		}
+		return
+	}
+	currentAPIKeyRelease = apiKeyReleaseFunc
+
 	userReleaseFunc, userAcquired, err := h.concurrencyHelper.TryAcquireUserSlot(ctx, subject.UserID, subject.Concurrency)
 	if err != nil {
 		reqLog.Warn("openai.websocket_user_slot_acquire_failed", zap.Error(err))
@@ -1302,11 +1455,17 @@ func (h *OpenAIGatewayHandler) ResponsesWebSocket(c *gin.Context) {
 			// 防御式清理：避免异常路径下旧槽位覆盖导致泄漏。
 			releaseTurnSlots()
 			// 非首轮 turn 需要重新抢占并发槽位，避免长连接空闲占槽。
+			apiKeyReleaseFunc, err := h.acquireWSAPIKeyTurn(ctx, apiKey)
+			if err != nil {
+				return err
+			}
 			userReleaseFunc, userAcquired, err := h.concurrencyHelper.TryAcquireUserSlot(ctx, subject.UserID, subject.Concurrency)
 			if err != nil {
+				apiKeyReleaseFunc()
 				return service.NewOpenAIWSClientCloseError(coderws.StatusInternalError, "failed to acquire user concurrency slot", err)
 			}
 			if !userAcquired {
+				apiKeyReleaseFunc()
 				return service.NewOpenAIWSClientCloseError(coderws.StatusTryAgainLater, "too many concurrent requests, please retry later", nil)
 			}
 			accountReleaseFunc, accountAcquired, err := h.concurrencyHelper.TryAcquireAccountSlot(ctx, account.ID, accountMaxConcurrency)
@@ -1314,14 +1473,17 @@ func (h *OpenAIGatewayHandler) ResponsesWebSocket(c *gin.Context) {
 				if userReleaseFunc != nil {
 					userReleaseFunc()
 				}
+				apiKeyReleaseFunc()
 				return service.NewOpenAIWSClientCloseError(coderws.StatusInternalError, "failed to acquire account concurrency slot", err)
 			}
 			if !accountAcquired {
 				if userReleaseFunc != nil {
 					userReleaseFunc()
 				}
+				apiKeyReleaseFunc()
 				return service.NewOpenAIWSClientCloseError(coderws.StatusTryAgainLater, "account is busy, please retry later", nil)
 			}
+			currentAPIKeyRelease = apiKeyReleaseFunc
 			currentUserRelease = wrapReleaseOnDone(ctx, userReleaseFunc)
 			currentAccountRelease = wrapReleaseOnDone(ctx, accountReleaseFunc)
 			return nil
@@ -1332,7 +1494,7 @@ func (h *OpenAIGatewayHandler) ResponsesWebSocket(c *gin.Context) {
 				return
 			}
 			h.gatewayService.ReportOpenAIAccountScheduleResult(account.ID, true, result.FirstTokenMs)
-			h.submitUsageRecordTask(func(taskCtx context.Context) {
+			h.submitUsageRecordTask(ctx, func(taskCtx context.Context) {
 				if err := h.gatewayService.RecordUsage(taskCtx, &service.OpenAIRecordUsageInput{
 					Result:        result,
 					APIKey:        apiKey,
@@ -1476,10 +1638,11 @@ func getContextInt64(c *gin.Context, key string) (int64, bool) {
 	}
 }
 
-func (h *OpenAIGatewayHandler) submitUsageRecordTask(task service.UsageRecordTask) {
+func (h *OpenAIGatewayHandler) submitUsageRecordTask(reqCtx context.Context, task service.UsageRecordTask) {
 	if task == nil {
 		return
 	}
+	task = withRequestSpan(reqCtx, task)
 	if h.usageRecordWorkerPool != nil {
 		h.usageRecordWorkerPool.Submit(task)
 		return
@@ -1693,6 +1856,10 @@ func (h *OpenAIGatewayHandler) ImageEdits(c *gin.Context) {
 		h.errorResponse(c, http.StatusBadRequest, "invalid_request_error", "model is required")
 		return
 	}
+	if !apiKey.IsModelAllowed(model) {
+		h.errorResponse(c, http.StatusForbidden, "permission_error", apiKeyModelDeniedMessage(model))
+		return
+	}
 	isNanoBanana := service.IsNanoBananaModel(model)
 
 	subscription, _ := middleware2.GetSubscriptionFromContext(c)
@@ -1729,7 +1896,7 @@ func (h *OpenAIGatewayHandler) ImageEdits(c *gin.Context) {
 		}
 		userAgent := c.GetHeader("User-Agent")
 		clientIP := ip.GetClientIP(c)
-		h.submitUsageRecordTask(func(ctx context.Context) {
+		h.submitUsageRecordTask(c.Request.Context(), func(ctx context.Context) {
 			_ = h.gatewayService.RecordUsage(ctx, &service.OpenAIRecordUsageInput{
 				Result:        result,
 				APIKey:        apiKey,
@@ -1774,6 +1941,10 @@ func (h *OpenAIGatewayHandler) VideoGenerations(c *gin.Context) {
 		h.errorResponse(c, http.StatusBadRequest, "invalid_request_error", "model is required")
 		return
 	}
+	if !apiKey.IsModelAllowed(model) {
+		h.errorResponse(c, http.StatusForbidden, "permission_error", apiKeyModelDeniedMessage(model))
+		return
+	}
 	subscription, _ := middleware2.GetSubscriptionFromContext(c)
 	failedAccountIDs := make(map[int64]struct{})
 	for switchCount := 0; switchCount <= h.maxAccountSwitches; switchCount++ {
@@ -1796,7 +1967,7 @@ func (h *OpenAIGatewayHandler) VideoGenerations(c *gin.Context) {
 		}
 		userAgent := c.GetHeader("User-Agent")
 		clientIP := ip.GetClientIP(c)
-		h.submitUsageRecordTask(func(ctx context.Context) {
+		h.submitUsageRecordTask(c.Request.Context(), func(ctx context.Context) {
 			_ = h.gatewayService.RecordUsage(ctx, &service.OpenAIRecordUsageInput{
 				Result:        result,
 				APIKey:        apiKey,
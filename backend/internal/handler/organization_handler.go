@@ -0,0 +1,524 @@
+package handler
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/Wei-Shaw/sub2api/internal/handler/dto"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/response"
+	middleware2 "github.com/Wei-Shaw/sub2api/internal/server/middleware"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// OrganizationHandler handles organization (team) requests
+type OrganizationHandler struct {
+	organizationService *service.OrganizationService
+}
+
+// NewOrganizationHandler creates a new OrganizationHandler
+func NewOrganizationHandler(organizationService *service.OrganizationService) *OrganizationHandler {
+	return &OrganizationHandler{
+		organizationService: organizationService,
+	}
+}
+
+// CreateOrganizationRequest represents the create/rename organization request payload
+type CreateOrganizationRequest struct {
+	Name string `json:"name" binding:"required"`
+}
+
+// UpdateOrganizationMemberRequest represents the update member request payload
+type UpdateOrganizationMemberRequest struct {
+	Role              *string  `json:"role"`
+	MonthlySpendLimit *float64 `json:"monthly_spend_limit" binding:"omitempty,min=0"` // USD, 0 = unlimited
+}
+
+// TransferOrganizationOwnershipRequest represents the transfer ownership request payload
+type TransferOrganizationOwnershipRequest struct {
+	UserID int64 `json:"user_id" binding:"required"`
+}
+
+// InviteOrganizationMemberRequest represents the invite member request payload
+type InviteOrganizationMemberRequest struct {
+	Email string `json:"email" binding:"required,email"`
+	Role  string `json:"role" binding:"required"`
+}
+
+// AcceptOrganizationInvitationRequest represents the accept invitation request payload
+type AcceptOrganizationInvitationRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// List handles listing the organizations the current user belongs to
+// GET /api/v1/organizations
+func (h *OrganizationHandler) List(c *gin.Context) {
+	subject, ok := middleware2.GetAuthSubjectFromContext(c)
+	if !ok {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	memberships, err := h.organizationService.ListMine(c.Request.Context(), subject.UserID)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+
+	out := make([]dto.Organization, 0, len(memberships))
+	for i := range memberships {
+		out = append(out, *dto.OrganizationFromService(&memberships[i].Organization, memberships[i].Role))
+	}
+	response.Success(c, out)
+}
+
+// Create handles creating a new organization owned by the current user
+// POST /api/v1/organizations
+func (h *OrganizationHandler) Create(c *gin.Context) {
+	subject, ok := middleware2.GetAuthSubjectFromContext(c)
+	if !ok {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	var req CreateOrganizationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	executeUserIdempotentJSON(c, "user.organizations.create", req, service.DefaultWriteIdempotencyTTL(), func(ctx context.Context) (any, error) {
+		detail, err := h.organizationService.Create(ctx, subject.UserID, req.Name)
+		if err != nil {
+			return nil, err
+		}
+		return dto.OrganizationDetailFromService(detail), nil
+	})
+}
+
+// Get handles getting an organization with its shared balance and subscriptions
+// GET /api/v1/organizations/:id
+func (h *OrganizationHandler) Get(c *gin.Context) {
+	subject, orgID, ok := organizationRequestContext(c)
+	if !ok {
+		return
+	}
+
+	detail, err := h.organizationService.Get(c.Request.Context(), orgID, subject.UserID)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+
+	response.Success(c, dto.OrganizationDetailFromService(detail))
+}
+
+// Update handles renaming an organization
+// PUT /api/v1/organizations/:id
+func (h *OrganizationHandler) Update(c *gin.Context) {
+	subject, orgID, ok := organizationRequestContext(c)
+	if !ok {
+		return
+	}
+
+	var req CreateOrganizationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	if err := h.organizationService.UpdateName(c.Request.Context(), orgID, subject.UserID, req.Name); err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+
+	response.Success(c, gin.H{"message": "Organization updated successfully"})
+}
+
+// ListMembers handles listing organization members
+// GET /api/v1/organizations/:id/members
+func (h *OrganizationHandler) ListMembers(c *gin.Context) {
+	subject, orgID, ok := organizationRequestContext(c)
+	if !ok {
+		return
+	}
+
+	members, err := h.organizationService.ListMembers(c.Request.Context(), orgID, subject.UserID)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+
+	out := make([]dto.OrganizationMember, 0, len(members))
+	for i := range members {
+		out = append(out, *dto.OrganizationMemberFromService(&members[i]))
+	}
+	response.Success(c, out)
+}
+
+// UpdateMember handles changing a member's role or monthly spend limit
+// PUT /api/v1/organizations/:id/members/:user_id
+func (h *OrganizationHandler) UpdateMember(c *gin.Context) {
+	subject, orgID, ok := organizationRequestContext(c)
+	if !ok {
+		return
+	}
+
+	targetID, err := strconv.ParseInt(c.Param("user_id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "Invalid user ID")
+		return
+	}
+
+	var req UpdateOrganizationMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	member, err := h.organizationService.UpdateMember(c.Request.Context(), orgID, subject.UserID, targetID, service.UpdateOrganizationMemberRequest{
+		Role:              req.Role,
+		MonthlySpendLimit: req.MonthlySpendLimit,
+	})
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+
+	response.Success(c, dto.OrganizationMemberFromService(member))
+}
+
+// RemoveMember handles removing a member (or leaving the organization when user_id is self)
+// DELETE /api/v1/organizations/:id/members/:user_id
+func (h *OrganizationHandler) RemoveMember(c *gin.Context) {
+	subject, orgID, ok := organizationRequestContext(c)
+	if !ok {
+		return
+	}
+
+	targetID, err := strconv.ParseInt(c.Param("user_id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "Invalid user ID")
+		return
+	}
+
+	if err := h.organizationService.RemoveMember(c.Request.Context(), orgID, subject.UserID, targetID); err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+
+	response.Success(c, gin.H{"message": "Member removed successfully"})
+}
+
+// TransferOwnership handles transferring organization ownership to another member
+// POST /api/v1/organizations/:id/transfer-ownership
+func (h *OrganizationHandler) TransferOwnership(c *gin.Context) {
+	subject, orgID, ok := organizationRequestContext(c)
+	if !ok {
+		return
+	}
+
+	var req TransferOrganizationOwnershipRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	if err := h.organizationService.TransferOwnership(c.Request.Context(), orgID, subject.UserID, req.UserID); err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+
+	response.Success(c, gin.H{"message": "Ownership transferred successfully"})
+}
+
+// ListInvitations handles listing pending invitations
+// GET /api/v1/organizations/:id/invitations
+func (h *OrganizationHandler) ListInvitations(c *gin.Context) {
+	subject, orgID, ok := organizationRequestContext(c)
+	if !ok {
+		return
+	}
+
+	invitations, err := h.organizationService.ListInvitations(c.Request.Context(), orgID, subject.UserID)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+
+	out := make([]dto.OrganizationInvitation, 0, len(invitations))
+	for i := range invitations {
+		out = append(out, *dto.OrganizationInvitationFromService(&invitations[i]))
+	}
+	response.Success(c, out)
+}
+
+// Invite handles inviting a user by email; the invitation token is only returned here
+// POST /api/v1/organizations/:id/invitations
+func (h *OrganizationHandler) Invite(c *gin.Context) {
+	subject, orgID, ok := organizationRequestContext(c)
+	if !ok {
+		return
+	}
+
+	var req InviteOrganizationMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	inv, token, err := h.organizationService.InviteMember(c.Request.Context(), orgID, subject.UserID, req.Email, req.Role)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+
+	out := dto.OrganizationInvitationFromService(inv)
+	out.Token = token
+	response.Success(c, out)
+}
+
+// RevokeInvitation handles revoking a pending invitation
+// DELETE /api/v1/organizations/:id/invitations/:invitation_id
+func (h *OrganizationHandler) RevokeInvitation(c *gin.Context) {
+	subject, orgID, ok := organizationRequestContext(c)
+	if !ok {
+		return
+	}
+
+	invitationID, err := strconv.ParseInt(c.Param("invitation_id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "Invalid invitation ID")
+		return
+	}
+
+	if err := h.organizationService.RevokeInvitation(c.Request.Context(), orgID, subject.UserID, invitationID); err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+
+	response.Success(c, gin.H{"message": "Invitation revoked successfully"})
+}
+
+// AcceptInvitation handles accepting an invitation with its token
+// POST /api/v1/organizations/invitations/accept
+func (h *OrganizationHandler) AcceptInvitation(c *gin.Context) {
+	subject, ok := middleware2.GetAuthSubjectFromContext(c)
+	if !ok {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	var req AcceptOrganizationInvitationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	membership, err := h.organizationService.AcceptInvitation(c.Request.Context(), subject.UserID, req.Token)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+
+	response.Success(c, dto.OrganizationFromService(&membership.Organization, membership.Role))
+}
+
+// ListAPIKeys handles listing organization API keys visible to the current member
+// GET /api/v1/organizations/:id/keys
+func (h *OrganizationHandler) ListAPIKeys(c *gin.Context) {
+	subject, orgID, ok := organizationRequestContext(c)
+	if !ok {
+		return
+	}
+
+	page, pageSize := response.ParsePagination(c)
+	params := pagination.PaginationParams{Page: page, PageSize: pageSize}
+
+	keys, result, err := h.organizationService.ListAPIKeys(c.Request.Context(), orgID, subject.UserID, params)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+
+	out := make([]dto.APIKey, 0, len(keys))
+	for i := range keys {
+		out = append(out, *dto.APIKeyFromService(&keys[i]))
+	}
+	response.Paginated(c, out, result.Total, page, pageSize)
+}
+
+// CreateAPIKey handles creating an API key billed to the organization
+// POST /api/v1/organizations/:id/keys
+func (h *OrganizationHandler) CreateAPIKey(c *gin.Context) {
+	subject, orgID, ok := organizationRequestContext(c)
+	if !ok {
+		return
+	}
+
+	var req CreateAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	svcReq := req.toService()
+
+	executeUserIdempotentJSON(c, "user.organizations.api_keys.create", req, service.DefaultWriteIdempotencyTTL(), func(ctx context.Context) (any, error) {
+		key, err := h.organizationService.CreateAPIKey(ctx, orgID, subject.UserID, svcReq)
+		if err != nil {
+			return nil, err
+		}
+		return dto.APIKeyFromService(key), nil
+	})
+}
+
+// UpdateAPIKey handles updating an organization API key
+// PUT /api/v1/organizations/:id/keys/:key_id
+func (h *OrganizationHandler) UpdateAPIKey(c *gin.Context) {
+	subject, orgID, ok := organizationRequestContext(c)
+	if !ok {
+		return
+	}
+
+	keyID, err := strconv.ParseInt(c.Param("key_id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "Invalid key ID")
+		return
+	}
+
+	var req UpdateAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	svcReq, err := req.toService()
+	if err != nil {
+		response.BadRequest(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	key, err := h.organizationService.UpdateAPIKey(c.Request.Context(), orgID, subject.UserID, keyID, svcReq)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+
+	response.Success(c, dto.APIKeyFromService(key))
+}
+
+// DeleteAPIKey handles deleting an organization API key
+// DELETE /api/v1/organizations/:id/keys/:key_id
+func (h *OrganizationHandler) DeleteAPIKey(c *gin.Context) {
+	subject, orgID, ok := organizationRequestContext(c)
+	if !ok {
+		return
+	}
+
+	keyID, err := strconv.ParseInt(c.Param("key_id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "Invalid key ID")
+		return
+	}
+
+	if err := h.organizationService.DeleteAPIKey(c.Request.Context(), orgID, subject.UserID, keyID); err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+
+	response.Success(c, gin.H{"message": "API key deleted successfully"})
+}
+
+// Redeem handles redeeming a code into the organization wallet
+// POST /api/v1/organizations/:id/redeem
+func (h *OrganizationHandler) Redeem(c *gin.Context) {
+	subject, orgID, ok := organizationRequestContext(c)
+	if !ok {
+		return
+	}
+
+	var req RedeemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	result, err := h.organizationService.Redeem(c.Request.Context(), orgID, subject.UserID, req.Code)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+
+	response.Success(c, dto.RedeemCodeFromService(result))
+}
+
+// ListTransactions handles listing the organization wallet's balance ledger
+// GET /api/v1/organizations/:id/transactions
+// Query params: same as GET /api/v1/user/balance/transactions
+func (h *OrganizationHandler) ListTransactions(c *gin.Context) {
+	subject, orgID, ok := organizationRequestContext(c)
+	if !ok {
+		return
+	}
+
+	page, pageSize := response.ParsePagination(c)
+
+	filter, ok := parseBalanceTransactionFilter(c)
+	if !ok {
+		return
+	}
+
+	params := pagination.PaginationParams{Page: page, PageSize: pageSize}
+	entries, result, err := h.organizationService.ListTransactions(c.Request.Context(), orgID, subject.UserID, params, filter)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+
+	out := make([]dto.BalanceTransaction, 0, len(entries))
+	for i := range entries {
+		out = append(out, *dto.BalanceTransactionFromService(&entries[i]))
+	}
+	response.Paginated(c, out, result.Total, page, pageSize)
+}
+
+// GetUsage handles the organization usage dashboard (per-member totals and daily trend)
+// GET /api/v1/organizations/:id/usage
+// Query params:
+//   - start_date / end_date: YYYY-MM-DD in the user's timezone (default: last 7 days)
+func (h *OrganizationHandler) GetUsage(c *gin.Context) {
+	subject, orgID, ok := organizationRequestContext(c)
+	if !ok {
+		return
+	}
+
+	startTime, endTime := parseUserTimeRange(c)
+
+	usage, err := h.organizationService.GetUsage(c.Request.Context(), orgID, subject.UserID, startTime, endTime)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+
+	response.Success(c, dto.OrganizationUsageFromService(usage))
+}
+
+// organizationRequestContext resolves the auth subject and the :id organization path param;
+// on failure it writes the error response and returns false.
+func organizationRequestContext(c *gin.Context) (middleware2.AuthSubject, int64, bool) {
+	subject, ok := middleware2.GetAuthSubjectFromContext(c)
+	if !ok {
+		response.Unauthorized(c, "User not authenticated")
+		return subject, 0, false
+	}
+
+	orgID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "Invalid organization ID")
+		return subject, 0, false
+	}
+	return subject, orgID, true
+}
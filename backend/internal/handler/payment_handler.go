@@ -0,0 +1,185 @@
+package handler
+
+import (
+	"context"
+	"io"
+	"net/http"
+
+	"github.com/Wei-Shaw/sub2api/internal/handler/dto"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/logger"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/response"
+	middleware2 "github.com/Wei-Shaw/sub2api/internal/server/middleware"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// paymentNotifyMaxBodyBytes caps provider callback bodies
+const paymentNotifyMaxBodyBytes = 1 << 20
+
+// PaymentHandler handles built-in payment order requests
+type PaymentHandler struct {
+	paymentService *service.PaymentService
+}
+
+// NewPaymentHandler creates a new PaymentHandler
+func NewPaymentHandler(paymentService *service.PaymentService) *PaymentHandler {
+	return &PaymentHandler{
+		paymentService: paymentService,
+	}
+}
+
+// CreatePaymentOrderRequest represents the create order request payload
+type CreatePaymentOrderRequest struct {
+	ProductID int64  `json:"product_id" binding:"required"`
+	Provider  string `json:"provider" binding:"required"`
+	Method    string `json:"method"`
+	// CouponID is the user coupon to apply; omit or 0 for none
+	CouponID int64 `json:"coupon_id"`
+}
+
+// ListProducts handles listing purchasable products and enabled payment providers
+// GET /api/v1/payment/products
+func (h *PaymentHandler) ListProducts(c *gin.Context) {
+	if !h.paymentService.Enabled() {
+		response.Success(c, gin.H{"enabled": false, "products": []dto.PaymentProduct{}, "providers": []dto.PaymentProvider{}})
+		return
+	}
+
+	products, err := h.paymentService.ListProducts(c.Request.Context(), true)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+
+	out := make([]dto.PaymentProduct, 0, len(products))
+	for i := range products {
+		out = append(out, *dto.PaymentProductFromService(&products[i]))
+	}
+	response.Success(c, gin.H{
+		"enabled":   true,
+		"products":  out,
+		"providers": dto.PaymentProvidersFromService(h.paymentService.ListProviders()),
+	})
+}
+
+// CreateOrder handles creating a payment order and returns where to pay
+// POST /api/v1/payment/orders
+func (h *PaymentHandler) CreateOrder(c *gin.Context) {
+	subject, ok := middleware2.GetAuthSubjectFromContext(c)
+	if !ok {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	var req CreatePaymentOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	executeUserIdempotentJSON(c, "user.payment.orders.create", req, service.DefaultWriteIdempotencyTTL(), func(ctx context.Context) (any, error) {
+		order, result, err := h.paymentService.CreateOrder(ctx, subject.UserID, &service.CreatePaymentOrderInput{
+			ProductID: req.ProductID,
+			Provider:  req.Provider,
+			Method:    req.Method,
+			CouponID:  req.CouponID,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return dto.PaymentCheckoutFromService(order, result), nil
+	})
+}
+
+// ListOrders handles listing the current user's payment orders
+// GET /api/v1/payment/orders
+func (h *PaymentHandler) ListOrders(c *gin.Context) {
+	subject, ok := middleware2.GetAuthSubjectFromContext(c)
+	if !ok {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	page, pageSize := response.ParsePagination(c)
+	params := pagination.PaginationParams{Page: page, PageSize: pageSize}
+
+	orders, result, err := h.paymentService.ListUserOrders(c.Request.Context(), subject.UserID, params, c.Query("status"))
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+
+	out := make([]dto.PaymentOrder, 0, len(orders))
+	for i := range orders {
+		out = append(out, *dto.PaymentOrderFromService(&orders[i]))
+	}
+	response.Paginated(c, out, result.Total, page, pageSize)
+}
+
+// GetOrder handles getting one of the current user's payment orders
+// GET /api/v1/payment/orders/:order_no
+func (h *PaymentHandler) GetOrder(c *gin.Context) {
+	subject, ok := middleware2.GetAuthSubjectFromContext(c)
+	if !ok {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	order, err := h.paymentService.GetUserOrder(c.Request.Context(), subject.UserID, c.Param("order_no"))
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, dto.PaymentOrderFromService(order))
+}
+
+// CancelOrder handles cancelling an unpaid order
+// POST /api/v1/payment/orders/:order_no/cancel
+func (h *PaymentHandler) CancelOrder(c *gin.Context) {
+	subject, ok := middleware2.GetAuthSubjectFromContext(c)
+	if !ok {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	order, err := h.paymentService.CancelOrder(c.Request.Context(), subject.UserID, c.Param("order_no"))
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, dto.PaymentOrderFromService(order))
+}
+
+// Notify handles asynchronous payment callbacks from providers (unauthenticated;
+// each provider verifies its own signature). The provider-specific ack is written
+// back verbatim.
+// GET/POST /api/v1/payment/notify/:provider
+func (h *PaymentHandler) Notify(c *gin.Context) {
+	provider := c.Param("provider")
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, paymentNotifyMaxBodyBytes))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+
+	ack, err := h.paymentService.HandleNotification(c.Request.Context(), provider, &service.PaymentNotifyRequest{
+		Method: c.Request.Method,
+		Query:  c.Request.URL.Query(),
+		Header: c.Request.Header,
+		Body:   body,
+	})
+	if err != nil {
+		logger.LegacyPrintf("handler.payment", "[Payment] notify rejected provider=%s err=%v", provider, err)
+	}
+	if ack == nil {
+		c.Status(http.StatusNotFound)
+		return
+	}
+	if len(ack.Body) == 0 {
+		c.Status(ack.StatusCode)
+		return
+	}
+	c.Data(ack.StatusCode, ack.ContentType, ack.Body)
+}
@@ -0,0 +1,131 @@
+package handler
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/Wei-Shaw/sub2api/internal/handler/dto"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/response"
+	middleware2 "github.com/Wei-Shaw/sub2api/internal/server/middleware"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PointsHandler handles the user's points wallet and the points shop
+type PointsHandler struct {
+	pointsService *service.PointsService
+}
+
+// NewPointsHandler creates a new PointsHandler
+func NewPointsHandler(pointsService *service.PointsService) *PointsHandler {
+	return &PointsHandler{
+		pointsService: pointsService,
+	}
+}
+
+// GetAccount returns the current user's points balance and totals
+// GET /api/v1/points
+func (h *PointsHandler) GetAccount(c *gin.Context) {
+	subject, ok := middleware2.GetAuthSubjectFromContext(c)
+	if !ok {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	account, err := h.pointsService.GetAccount(c.Request.Context(), subject.UserID)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, dto.PointsAccountFromService(account))
+}
+
+// ListLedger lists the current user's points history
+// GET /api/v1/points/ledger
+func (h *PointsHandler) ListLedger(c *gin.Context) {
+	subject, ok := middleware2.GetAuthSubjectFromContext(c)
+	if !ok {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	page, pageSize := response.ParsePagination(c)
+	params := pagination.PaginationParams{Page: page, PageSize: pageSize}
+	entries, result, err := h.pointsService.ListLedger(c.Request.Context(), subject.UserID, params)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+
+	out := make([]dto.PointsLedgerEntry, 0, len(entries))
+	for i := range entries {
+		out = append(out, *dto.PointsLedgerEntryFromService(&entries[i]))
+	}
+	response.Paginated(c, out, result.Total, page, pageSize)
+}
+
+// ListShopItems lists the items available in the points shop
+// GET /api/v1/points/shop
+func (h *PointsHandler) ListShopItems(c *gin.Context) {
+	items, err := h.pointsService.ListItems(c.Request.Context(), true)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+
+	out := make([]dto.PointsShopItem, 0, len(items))
+	for i := range items {
+		out = append(out, *dto.PointsShopItemFromService(&items[i]))
+	}
+	response.Success(c, out)
+}
+
+// Exchange spends points on a shop item
+// POST /api/v1/points/shop/:id/exchange
+func (h *PointsHandler) Exchange(c *gin.Context) {
+	subject, ok := middleware2.GetAuthSubjectFromContext(c)
+	if !ok {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	itemID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "Invalid item ID")
+		return
+	}
+
+	executeUserIdempotentJSON(c, "user.points.exchange", gin.H{"item_id": itemID}, service.DefaultWriteIdempotencyTTL(), func(ctx context.Context) (any, error) {
+		ex, err := h.pointsService.Exchange(ctx, subject.UserID, itemID)
+		if err != nil {
+			return nil, err
+		}
+		return dto.PointsExchangeFromService(ex), nil
+	})
+}
+
+// ListExchanges lists the current user's exchange history, including redeem codes obtained
+// GET /api/v1/points/exchanges
+func (h *PointsHandler) ListExchanges(c *gin.Context) {
+	subject, ok := middleware2.GetAuthSubjectFromContext(c)
+	if !ok {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	page, pageSize := response.ParsePagination(c)
+	params := pagination.PaginationParams{Page: page, PageSize: pageSize}
+	exchanges, result, err := h.pointsService.ListUserExchanges(c.Request.Context(), subject.UserID, params)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+
+	out := make([]dto.PointsExchange, 0, len(exchanges))
+	for i := range exchanges {
+		out = append(out, *dto.PointsExchangeFromService(&exchanges[i]))
+	}
+	response.Paginated(c, out, result.Total, page, pageSize)
+}
@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/Wei-Shaw/sub2api/internal/handler/dto"
+	infraerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/response"
+	middleware2 "github.com/Wei-Shaw/sub2api/internal/server/middleware"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PromoHandler handles promo codes applied after signup
+type PromoHandler struct {
+	promoService   *service.PromoService
+	settingService *service.SettingService
+}
+
+// NewPromoHandler creates a new PromoHandler
+func NewPromoHandler(promoService *service.PromoService, settingService *service.SettingService) *PromoHandler {
+	return &PromoHandler{
+		promoService:   promoService,
+		settingService: settingService,
+	}
+}
+
+// ApplyPromoCodeRequest represents the request to apply a promo code
+type ApplyPromoCodeRequest struct {
+	Code string `json:"code" binding:"required,max=32"`
+}
+
+// Apply applies a promo code to the current user
+// POST /api/v1/user/promo-codes
+func (h *PromoHandler) Apply(c *gin.Context) {
+	subject, ok := middleware2.GetAuthSubjectFromContext(c)
+	if !ok {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	if h.settingService != nil && !h.settingService.IsPromoCodeEnabled(c.Request.Context()) {
+		response.ErrorFrom(c, infraerrors.Forbidden("PROMO_CODE_DISABLED", "promo codes are disabled"))
+		return
+	}
+
+	var req ApplyPromoCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	executeUserIdempotentJSON(c, "user.promo_codes.apply", req, service.DefaultWriteIdempotencyTTL(), func(ctx context.Context) (any, error) {
+		usage, err := h.promoService.ApplyPromoCode(ctx, subject.UserID, req.Code)
+		if err != nil {
+			return nil, err
+		}
+		if usage == nil {
+			return nil, service.ErrPromoCodeInvalid
+		}
+		return dto.UserPromoCodeUsageFromService(usage), nil
+	})
+}
+
+// ListUsages lists the promo codes the current user has applied, with top-up bonus status
+// GET /api/v1/user/promo-codes
+func (h *PromoHandler) ListUsages(c *gin.Context) {
+	subject, ok := middleware2.GetAuthSubjectFromContext(c)
+	if !ok {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	usages, err := h.promoService.ListUserUsages(c.Request.Context(), subject.UserID)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+
+	out := make([]dto.UserPromoCodeUsage, 0, len(usages))
+	for i := range usages {
+		out = append(out, *dto.UserPromoCodeUsageFromService(&usages[i]))
+	}
+	response.Success(c, out)
+}
@@ -0,0 +1,170 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/Wei-Shaw/sub2api/internal/handler/dto"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/response"
+	middleware2 "github.com/Wei-Shaw/sub2api/internal/server/middleware"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ReferralHandler handles the referrer's referral link, dashboards and commission wallet
+type ReferralHandler struct {
+	referralService *service.ReferralService
+}
+
+// NewReferralHandler creates a new ReferralHandler
+func NewReferralHandler(referralService *service.ReferralService) *ReferralHandler {
+	return &ReferralHandler{
+		referralService: referralService,
+	}
+}
+
+// ReferralWithdrawRequest represents the commission wallet withdrawal request payload
+type ReferralWithdrawRequest struct {
+	Amount  float64 `json:"amount" binding:"required,gt=0"`
+	Method  string  `json:"method" binding:"required,oneof=balance manual"`
+	Account string  `json:"account"`
+}
+
+// GetOverview returns the current user's referral code, link, program terms, totals and wallet
+// GET /api/v1/referral
+func (h *ReferralHandler) GetOverview(c *gin.Context) {
+	subject, ok := middleware2.GetAuthSubjectFromContext(c)
+	if !ok {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	overview, err := h.referralService.GetOverview(c.Request.Context(), subject.UserID)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, dto.ReferralOverviewFromService(overview))
+}
+
+// ListReferees lists users referred by the current user with their recharge, usage and commission totals
+// GET /api/v1/referral/referees
+func (h *ReferralHandler) ListReferees(c *gin.Context) {
+	subject, ok := middleware2.GetAuthSubjectFromContext(c)
+	if !ok {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	page, pageSize := response.ParsePagination(c)
+	params := pagination.PaginationParams{Page: page, PageSize: pageSize}
+	relations, result, err := h.referralService.ListReferees(c.Request.Context(), subject.UserID, params)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+
+	out := make([]dto.ReferralReferee, 0, len(relations))
+	for i := range relations {
+		out = append(out, *dto.ReferralRefereeFromService(&relations[i]))
+	}
+	response.Paginated(c, out, result.Total, page, pageSize)
+}
+
+// ListCommissions lists the current user's commission entries
+// GET /api/v1/referral/commissions
+func (h *ReferralHandler) ListCommissions(c *gin.Context) {
+	subject, ok := middleware2.GetAuthSubjectFromContext(c)
+	if !ok {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	page, pageSize := response.ParsePagination(c)
+	params := pagination.PaginationParams{Page: page, PageSize: pageSize}
+	commissions, result, err := h.referralService.ListUserCommissions(c.Request.Context(), subject.UserID, params)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+
+	out := make([]dto.ReferralCommission, 0, len(commissions))
+	for i := range commissions {
+		out = append(out, *dto.ReferralCommissionFromService(&commissions[i]))
+	}
+	response.Paginated(c, out, result.Total, page, pageSize)
+}
+
+// GetDashboard returns the daily trend of signups, referee recharges, referee usage and commission
+// GET /api/v1/referral/dashboard
+// Query params:
+//   - start_date / end_date: YYYY-MM-DD in the user's timezone (default: last 7 days)
+func (h *ReferralHandler) GetDashboard(c *gin.Context) {
+	subject, ok := middleware2.GetAuthSubjectFromContext(c)
+	if !ok {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	startTime, endTime := parseUserTimeRange(c)
+	dashboard, err := h.referralService.GetDashboard(c.Request.Context(), subject.UserID, startTime, endTime)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, dto.ReferralDashboardFromService(dashboard))
+}
+
+// Withdraw moves commission wallet funds to the account balance or requests a manual payout
+// POST /api/v1/referral/withdrawals
+func (h *ReferralHandler) Withdraw(c *gin.Context) {
+	subject, ok := middleware2.GetAuthSubjectFromContext(c)
+	if !ok {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	var req ReferralWithdrawRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	executeUserIdempotentJSON(c, "user.referral.withdrawals.create", req, service.DefaultWriteIdempotencyTTL(), func(ctx context.Context) (any, error) {
+		w, err := h.referralService.Withdraw(ctx, subject.UserID, &service.ReferralWithdrawInput{
+			Amount:  money.FromFloat(req.Amount),
+			Method:  req.Method,
+			Account: req.Account,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return dto.ReferralWithdrawalFromService(w), nil
+	})
+}
+
+// ListWithdrawals lists the current user's withdrawals
+// GET /api/v1/referral/withdrawals
+func (h *ReferralHandler) ListWithdrawals(c *gin.Context) {
+	subject, ok := middleware2.GetAuthSubjectFromContext(c)
+	if !ok {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	page, pageSize := response.ParsePagination(c)
+	params := pagination.PaginationParams{Page: page, PageSize: pageSize}
+	withdrawals, result, err := h.referralService.ListUserWithdrawals(c.Request.Context(), subject.UserID, params)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+
+	out := make([]dto.ReferralWithdrawal, 0, len(withdrawals))
+	for i := range withdrawals {
+		out = append(out, *dto.ReferralWithdrawalFromService(&withdrawals[i]))
+	}
+	response.Paginated(c, out, result.Total, page, pageSize)
+}
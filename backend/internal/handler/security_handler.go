@@ -0,0 +1,262 @@
+package handler
+
+import (
+	"encoding/json"
+	"strconv"
+
+	"github.com/Wei-Shaw/sub2api/internal/pkg/response"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/webauthn"
+	middleware2 "github.com/Wei-Shaw/sub2api/internal/server/middleware"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SecurityHandler handles passkeys, recovery codes, login sessions and login history for the current user
+type SecurityHandler struct {
+	webAuthnService     *service.WebAuthnService
+	recoveryCodeService *service.RecoveryCodeService
+	authService         *service.AuthService
+	loginHistoryService *service.LoginHistoryService
+}
+
+// NewSecurityHandler creates a new SecurityHandler
+func NewSecurityHandler(webAuthnService *service.WebAuthnService, recoveryCodeService *service.RecoveryCodeService, authService *service.AuthService, loginHistoryService *service.LoginHistoryService) *SecurityHandler {
+	return &SecurityHandler{
+		webAuthnService:     webAuthnService,
+		recoveryCodeService: recoveryCodeService,
+		authService:         authService,
+		loginHistoryService: loginHistoryService,
+	}
+}
+
+// SecurityVerifyRequest re-authenticates the user for sensitive changes
+// (email_code when email verification is enabled, otherwise password)
+type SecurityVerifyRequest struct {
+	EmailCode string `json:"email_code"`
+	Password  string `json:"password"`
+}
+
+// PasskeyResponse represents a registered passkey
+type PasskeyResponse struct {
+	ID             int64    `json:"id"`
+	Name           string   `json:"name"`
+	Transports     []string `json:"transports"`
+	BackupEligible bool     `json:"backup_eligible"`
+	BackedUp       bool     `json:"backed_up"`
+	LastUsedAt     *int64   `json:"last_used_at,omitempty"` // Unix timestamp
+	CreatedAt      int64    `json:"created_at"`             // Unix timestamp
+}
+
+// PasskeyCreationOptionsResponse carries options for navigator.credentials.create()
+type PasskeyCreationOptionsResponse struct {
+	PublicKey *webauthn.CreationOptions `json:"public_key"`
+}
+
+// PasskeyRegisterRequest completes passkey registration
+type PasskeyRegisterRequest struct {
+	Name       string          `json:"name"`
+	Credential json.RawMessage `json:"credential" binding:"required"` // PublicKeyCredential.toJSON()
+}
+
+// PasskeyRenameRequest renames a passkey
+type PasskeyRenameRequest struct {
+	Name string `json:"name" binding:"required"`
+}
+
+// RecoveryCodeStatusResponse represents the recovery code status
+type RecoveryCodeStatusResponse struct {
+	Total       int    `json:"total"`
+	Remaining   int    `json:"remaining"`
+	GeneratedAt *int64 `json:"generated_at,omitempty"` // Unix timestamp
+}
+
+func passkeyToResponse(c *service.WebAuthnCredential) PasskeyResponse {
+	resp := PasskeyResponse{
+		ID:             c.ID,
+		Name:           c.Name,
+		Transports:     c.Transports,
+		BackupEligible: c.BackupEligible,
+		BackedUp:       c.BackedUp,
+		CreatedAt:      c.CreatedAt.Unix(),
+	}
+	if resp.Transports == nil {
+		resp.Transports = []string{}
+	}
+	if c.LastUsedAt != nil {
+		ts := c.LastUsedAt.Unix()
+		resp.LastUsedAt = &ts
+	}
+	return resp
+}
+
+// ListPasskeys lists the current user's passkeys
+// GET /api/v1/user/security/passkeys
+func (h *SecurityHandler) ListPasskeys(c *gin.Context) {
+	subject, ok := middleware2.GetAuthSubjectFromContext(c)
+	if !ok {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	creds, err := h.webAuthnService.ListCredentials(c.Request.Context(), subject.UserID)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	out := make([]PasskeyResponse, 0, len(creds))
+	for i := range creds {
+		out = append(out, passkeyToResponse(&creds[i]))
+	}
+	response.Success(c, gin.H{
+		"enabled":  h.webAuthnService.Enabled(),
+		"passkeys": out,
+	})
+}
+
+// BeginPasskeyRegistration returns creation options for a new passkey
+// POST /api/v1/user/security/passkeys/options
+func (h *SecurityHandler) BeginPasskeyRegistration(c *gin.Context) {
+	subject, ok := middleware2.GetAuthSubjectFromContext(c)
+	if !ok {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	var req SecurityVerifyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	options, err := h.webAuthnService.BeginRegistration(c.Request.Context(), subject.UserID, req.EmailCode, req.Password)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, PasskeyCreationOptionsResponse{PublicKey: options})
+}
+
+// RegisterPasskey verifies the attestation and stores the passkey
+// POST /api/v1/user/security/passkeys
+func (h *SecurityHandler) RegisterPasskey(c *gin.Context) {
+	subject, ok := middleware2.GetAuthSubjectFromContext(c)
+	if !ok {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	var req PasskeyRegisterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	cred, err := h.webAuthnService.FinishRegistration(c.Request.Context(), subject.UserID, req.Name, req.Credential)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, passkeyToResponse(cred))
+}
+
+// RenamePasskey renames a passkey
+// PUT /api/v1/user/security/passkeys/:id
+func (h *SecurityHandler) RenamePasskey(c *gin.Context) {
+	subject, ok := middleware2.GetAuthSubjectFromContext(c)
+	if !ok {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "Invalid passkey ID")
+		return
+	}
+
+	var req PasskeyRenameRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	if err := h.webAuthnService.RenameCredential(c.Request.Context(), subject.UserID, id, req.Name); err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, gin.H{"message": "Passkey renamed successfully"})
+}
+
+// DeletePasskey removes a passkey (requires re-authentication in the request body)
+// DELETE /api/v1/user/security/passkeys/:id
+func (h *SecurityHandler) DeletePasskey(c *gin.Context) {
+	subject, ok := middleware2.GetAuthSubjectFromContext(c)
+	if !ok {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "Invalid passkey ID")
+		return
+	}
+
+	var req SecurityVerifyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	if err := h.webAuthnService.DeleteCredential(c.Request.Context(), subject.UserID, id, req.EmailCode, req.Password); err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, gin.H{"message": "Passkey deleted successfully"})
+}
+
+// GetRecoveryCodeStatus returns how many recovery codes remain
+// GET /api/v1/user/security/recovery-codes
+func (h *SecurityHandler) GetRecoveryCodeStatus(c *gin.Context) {
+	subject, ok := middleware2.GetAuthSubjectFromContext(c)
+	if !ok {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	status, err := h.recoveryCodeService.GetStatus(c.Request.Context(), subject.UserID)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	resp := RecoveryCodeStatusResponse{Total: status.Total, Remaining: status.Remaining}
+	if status.GeneratedAt != nil {
+		ts := status.GeneratedAt.Unix()
+		resp.GeneratedAt = &ts
+	}
+	response.Success(c, resp)
+}
+
+// RegenerateRecoveryCodes replaces all recovery codes; the plaintext codes are only returned here
+// POST /api/v1/user/security/recovery-codes
+func (h *SecurityHandler) RegenerateRecoveryCodes(c *gin.Context) {
+	subject, ok := middleware2.GetAuthSubjectFromContext(c)
+	if !ok {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	var req SecurityVerifyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	codes, err := h.recoveryCodeService.Regenerate(c.Request.Context(), subject.UserID, req.EmailCode, req.Password)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, gin.H{"codes": codes})
+}
@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"github.com/Wei-Shaw/sub2api/internal/handler/dto"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/response"
+	middleware2 "github.com/Wei-Shaw/sub2api/internal/server/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListSessions lists the current user's active login sessions
+// GET /api/v1/user/security/sessions
+func (h *SecurityHandler) ListSessions(c *gin.Context) {
+	subject, ok := middleware2.GetAuthSubjectFromContext(c)
+	if !ok {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	sessions, err := h.authService.ListUserSessions(c.Request.Context(), subject.UserID)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+
+	currentSessionID := middleware2.GetSessionIDFromContext(c)
+	out := make([]dto.UserSession, 0, len(sessions))
+	for i := range sessions {
+		out = append(out, *dto.UserSessionFromService(&sessions[i], currentSessionID))
+	}
+	response.Success(c, out)
+}
+
+// RevokeSession signs out a single login session of the current user
+// DELETE /api/v1/user/security/sessions/:id
+func (h *SecurityHandler) RevokeSession(c *gin.Context) {
+	subject, ok := middleware2.GetAuthSubjectFromContext(c)
+	if !ok {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	if err := h.authService.RevokeUserSession(c.Request.Context(), subject.UserID, c.Param("id")); err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, gin.H{"message": "Session revoked successfully"})
+}
+
+// ListLoginHistory lists the current user's login attempts, newest first
+// GET /api/v1/user/security/login-history
+func (h *SecurityHandler) ListLoginHistory(c *gin.Context) {
+	subject, ok := middleware2.GetAuthSubjectFromContext(c)
+	if !ok {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	page, pageSize := response.ParsePagination(c)
+	params := pagination.PaginationParams{Page: page, PageSize: pageSize}
+	entries, result, err := h.loginHistoryService.ListUserHistory(c.Request.Context(), subject.UserID, params)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+
+	out := make([]dto.LoginHistory, 0, len(entries))
+	for i := range entries {
+		out = append(out, *dto.LoginHistoryFromService(&entries[i]))
+	}
+	response.Paginated(c, out, result.Total, page, pageSize)
+}
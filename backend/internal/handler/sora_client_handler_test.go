@@ -16,6 +16,7 @@ import (
 	"time"
 
 	"github.com/Wei-Shaw/sub2api/internal/config"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
 	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
 	middleware2 "github.com/Wei-Shaw/sub2api/internal/server/middleware"
 	"github.com/Wei-Shaw/sub2api/internal/service"
@@ -933,8 +934,12 @@ func (r *stubUserRepoForHandler) List(context.Context, pagination.PaginationPara
 func (r *stubUserRepoForHandler) ListWithFilters(context.Context, pagination.PaginationParams, service.UserListFilters) ([]service.User, *pagination.PaginationResult, error) {
 	return nil, nil, nil
 }
-func (r *stubUserRepoForHandler) UpdateBalance(context.Context, int64, float64) error { return nil }
-func (r *stubUserRepoForHandler) DeductBalance(context.Context, int64, float64) error { return nil }
+func (r *stubUserRepoForHandler) UpdateBalance(context.Context, int64, money.Amount, service.BalanceLedgerSource) error {
+	return nil
+}
+func (r *stubUserRepoForHandler) DeductBalance(context.Context, int64, money.Amount, service.BalanceLedgerSource) error {
+	return nil
+}
 func (r *stubUserRepoForHandler) UpdateConcurrency(context.Context, int64, int) error { return nil }
 func (r *stubUserRepoForHandler) ExistsByEmail(context.Context, string) (bool, error) {
 	return false, nil
@@ -999,6 +1004,9 @@ func (r *stubAPIKeyRepoForHandler) Delete(context.Context, int64) error
 func (r *stubAPIKeyRepoForHandler) ListByUserID(_ context.Context, _ int64, _ pagination.PaginationParams) ([]service.APIKey, *pagination.PaginationResult, error) {
 	return nil, nil, nil
 }
+func (r *stubAPIKeyRepoForHandler) ListByCreator(_ context.Context, _ int64, _ int64, _ pagination.PaginationParams) ([]service.APIKey, *pagination.PaginationResult, error) {
+	return nil, nil, nil
+}
 func (r *stubAPIKeyRepoForHandler) VerifyOwnership(context.Context, int64, []int64) ([]int64, error) {
 	return nil, nil
 }
@@ -1026,13 +1034,13 @@ func (r *stubAPIKeyRepoForHandler) ListKeysByUserID(context.Context, int64) ([]s
 func (r *stubAPIKeyRepoForHandler) ListKeysByGroupID(context.Context, int64) ([]string, error) {
 	return nil, nil
 }
-func (r *stubAPIKeyRepoForHandler) IncrementQuotaUsed(_ context.Context, _ int64, _ float64) (float64, error) {
+func (r *stubAPIKeyRepoForHandler) IncrementQuotaUsed(_ context.Context, _ int64, _ money.Amount) (money.Amount, error) {
 	return 0, nil
 }
 func (r *stubAPIKeyRepoForHandler) UpdateLastUsed(context.Context, int64, time.Time) error {
 	return nil
 }
-func (r *stubAPIKeyRepoForHandler) IncrementRateLimitUsage(context.Context, int64, float64) error {
+func (r *stubAPIKeyRepoForHandler) IncrementRateLimitUsage(context.Context, int64, money.Amount) error {
 	return nil
 }
 func (r *stubAPIKeyRepoForHandler) ResetRateLimitWindows(context.Context, int64) error {
@@ -2199,7 +2207,7 @@ func (s *stubSoraClientForHandler) GetVideoTask(_ context.Context, _ *service.Ac
 func newMinimalGatewayService(accountRepo service.AccountRepository) *service.GatewayService {
 	return service.NewGatewayService(
 		accountRepo, nil, nil, nil, nil, nil, nil, nil,
-		nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+		nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
 	)
 }
 
@@ -16,6 +16,7 @@ import (
 	"time"
 
 	"github.com/Wei-Shaw/sub2api/internal/config"
+	pkgerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
 	pkghttputil "github.com/Wei-Shaw/sub2api/internal/pkg/httputil"
 	"github.com/Wei-Shaw/sub2api/internal/pkg/ip"
 	"github.com/Wei-Shaw/sub2api/internal/pkg/logger"
@@ -34,6 +35,7 @@ type SoraGatewayHandler struct {
 	gatewayService        *service.GatewayService
 	soraGatewayService    *service.SoraGatewayService
 	billingCacheService   *service.BillingCacheService
+	apiKeyTrafficService  *service.APIKeyTrafficLimitService
 	usageRecordWorkerPool *service.UsageRecordWorkerPool
 	concurrencyHelper     *ConcurrencyHelper
 	maxAccountSwitches    int
@@ -49,6 +51,7 @@ func NewSoraGatewayHandler(
 	soraGatewayService *service.SoraGatewayService,
 	concurrencyService *service.ConcurrencyService,
 	billingCacheService *service.BillingCacheService,
+	apiKeyTrafficService *service.APIKeyTrafficLimitService,
 	usageRecordWorkerPool *service.UsageRecordWorkerPool,
 	cfg *config.Config,
 ) *SoraGatewayHandler {
@@ -76,6 +79,7 @@ func NewSoraGatewayHandler(
 		gatewayService:        gatewayService,
 		soraGatewayService:    soraGatewayService,
 		billingCacheService:   billingCacheService,
+		apiKeyTrafficService:  apiKeyTrafficService,
 		usageRecordWorkerPool: usageRecordWorkerPool,
 		concurrencyHelper:     NewConcurrencyHelper(concurrencyService, SSEPingFormatComment, pingInterval),
 		maxAccountSwitches:    maxAccountSwitches,
@@ -136,6 +140,10 @@ func (h *SoraGatewayHandler) ChatCompletions(c *gin.Context) {
 		return
 	}
 	reqModel := modelResult.String()
+	if !apiKey.IsModelAllowed(reqModel) {
+		h.errorResponse(c, http.StatusForbidden, "permission_error", apiKeyModelDeniedMessage(reqModel))
+		return
+	}
 
 	msgsResult := gjson.GetBytes(body, "messages")
 	if !msgsResult.IsArray() || len(msgsResult.Array()) == 0 {
@@ -174,6 +182,22 @@ func (h *SoraGatewayHandler) ChatCompletions(c *gin.Context) {
 	streamStarted := false
 	subscription, _ := middleware2.GetSubscriptionFromContext(c)
 
+	// API Key 级 RPM/TPM 与并发限制（先于用户槽位，避免排队时占用用户并发）
+	if err := admitAPIKeyTraffic(c, h.apiKeyTrafficService, apiKey, rateLimitHeadersOpenAI); err != nil {
+		reqLog.Info("sora.api_key_traffic_limited", zap.Error(err))
+		h.errorResponse(c, http.StatusTooManyRequests, "rate_limit_error", pkgerrors.Message(err))
+		return
+	}
+	apiKeyReleaseFunc, err := h.concurrencyHelper.AcquireAPIKeySlotWithWait(c, apiKey.ID, apiKey.MaxConcurrency, clientStream, &streamStarted)
+	if err != nil {
+		reqLog.Warn("sora.api_key_slot_acquire_failed", zap.Error(err))
+		h.handleConcurrencyError(c, err, "api_key", streamStarted)
+		return
+	}
+	if apiKeyReleaseFunc != nil {
+		defer apiKeyReleaseFunc()
+	}
+
 	maxWait := service.CalculateMaxWait(subject.Concurrency)
 	canWait, err := h.concurrencyHelper.IncrementWaitCount(c.Request.Context(), subject.UserID, maxWait)
 	waitCounted := false
@@ -362,6 +386,7 @@ func (h *SoraGatewayHandler) ChatCompletions(c *gin.Context) {
 				lastFailoverHeaders = cloneHTTPHeaders(failoverErr.ResponseHeaders)
 				lastFailoverBody = failoverErr.ResponseBody
 				switchCount++
+				recordFailoverSwitch(c.Request.Context(), account.ID, failoverErr.StatusCode, switchCount)
 				upstreamErrCode, upstreamErrMsg := extractUpstreamErrorCodeAndMessage(lastFailoverBody)
 				rayID, mitigated, contentType := extractSoraFailoverHeaderInsights(lastFailoverHeaders, lastFailoverBody)
 				fields := []zap.Field{
@@ -401,7 +426,7 @@ func (h *SoraGatewayHandler) ChatCompletions(c *gin.Context) {
 		clientIP := ip.GetClientIP(c)
 
 		// 使用量记录通过有界 worker 池提交，避免请求热路径创建无界 goroutine。
-		h.submitUsageRecordTask(func(ctx context.Context) {
+		h.submitUsageRecordTask(c.Request.Context(), func(ctx context.Context) {
 			if err := h.gatewayService.RecordUsage(ctx, &service.RecordUsageInput{
 				Result:       result,
 				APIKey:       apiKey,
@@ -450,10 +475,11 @@ func generateOpenAISessionHash(c *gin.Context, body []byte) string {
 	return hex.EncodeToString(hash[:])
 }
 
-func (h *SoraGatewayHandler) submitUsageRecordTask(task service.UsageRecordTask) {
+func (h *SoraGatewayHandler) submitUsageRecordTask(reqCtx context.Context, task service.UsageRecordTask) {
 	if task == nil {
 		return
 	}
+	task = withRequestSpan(reqCtx, task)
 	if h.usageRecordWorkerPool != nil {
 		h.usageRecordWorkerPool.Submit(task)
 		return
@@ -0,0 +1,196 @@
+package handler
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+
+	infraerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/response"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	ssoOAuthCookiePathPrefix = "/api/v1/auth/oauth/sso/"
+	ssoOAuthStateCookieName  = "sso_oauth_state"
+	ssoOAuthNonceCookieName  = "sso_oauth_nonce"
+	ssoOAuthVerifierCookie   = "sso_oauth_verifier"
+	ssoOAuthRedirectCookie   = "sso_oauth_redirect"
+	// 与 LinuxDo 登录共用前端回调页：两者都通过 URL fragment 下发令牌
+	ssoOAuthDefaultFrontendCB = linuxDoOAuthDefaultFrontendCB
+)
+
+// SSOHandler 处理通用 OIDC/OAuth2 单点登录
+type SSOHandler struct {
+	ssoService   *service.SSOService
+	loginHistory *service.LoginHistoryService
+}
+
+// NewSSOHandler creates a new SSOHandler
+func NewSSOHandler(ssoService *service.SSOService, loginHistoryService *service.LoginHistoryService) *SSOHandler {
+	return &SSOHandler{ssoService: ssoService, loginHistory: loginHistoryService}
+}
+
+// SSOLoginProvider 登录页展示的提供方
+type SSOLoginProvider struct {
+	Slug     string `json:"slug"`
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	StartURL string `json:"start_url"`
+}
+
+// ListProviders returns enabled SSO providers for the login page
+// GET /api/v1/auth/oauth/sso/providers
+func (h *SSOHandler) ListProviders(c *gin.Context) {
+	providers, err := h.ssoService.ListLoginProviders(c.Request.Context())
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	out := make([]SSOLoginProvider, 0, len(providers))
+	for _, p := range providers {
+		out = append(out, SSOLoginProvider{
+			Slug:     p.Slug,
+			Name:     p.Name,
+			Type:     p.Type,
+			StartURL: ssoOAuthCookiePathPrefix + p.Slug + "/start",
+		})
+	}
+	response.Success(c, out)
+}
+
+// Start redirects the browser to the identity provider
+// GET /api/v1/auth/oauth/sso/:slug/start?redirect=/dashboard
+func (h *SSOHandler) Start(c *gin.Context) {
+	ctx := c.Request.Context()
+	provider, err := h.ssoService.GetLoginProvider(ctx, c.Param("slug"))
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+
+	login, err := h.ssoService.BeginLogin(ctx, provider, h.ssoService.RedirectURI(provider, requestOrigin(c)))
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+
+	redirectTo := sanitizeFrontendRedirectPath(c.Query("redirect"))
+	if redirectTo == "" {
+		redirectTo = linuxDoOAuthDefaultRedirectTo
+	}
+
+	// Cookie 限定在该提供方路径下，避免并发发起的多个提供方登录互相覆盖 state
+	cookiePath := ssoOAuthCookiePathPrefix + provider.Slug
+	secureCookie := isRequestHTTPS(c)
+	setCookieAtPath(c, cookiePath, ssoOAuthStateCookieName, encodeCookieValue(login.State), linuxDoOAuthCookieMaxAgeSec, secureCookie)
+	setCookieAtPath(c, cookiePath, ssoOAuthRedirectCookie, encodeCookieValue(redirectTo), linuxDoOAuthCookieMaxAgeSec, secureCookie)
+	if login.Nonce != "" {
+		setCookieAtPath(c, cookiePath, ssoOAuthNonceCookieName, encodeCookieValue(login.Nonce), linuxDoOAuthCookieMaxAgeSec, secureCookie)
+	}
+	if login.CodeVerifier != "" {
+		setCookieAtPath(c, cookiePath, ssoOAuthVerifierCookie, encodeCookieValue(login.CodeVerifier), linuxDoOAuthCookieMaxAgeSec, secureCookie)
+	}
+
+	c.Redirect(http.StatusFound, login.AuthURL)
+}
+
+// Callback completes the login and redirects to the frontend with tokens in the URL fragment
+// GET /api/v1/auth/oauth/sso/:slug/callback?code=...&state=...
+func (h *SSOHandler) Callback(c *gin.Context) {
+	ctx := c.Request.Context()
+	provider, err := h.ssoService.GetLoginProvider(ctx, c.Param("slug"))
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+
+	frontendCallback := provider.FrontendRedirectURL
+	if frontendCallback == "" {
+		frontendCallback = ssoOAuthDefaultFrontendCB
+	}
+
+	if providerErr := strings.TrimSpace(c.Query("error")); providerErr != "" {
+		redirectOAuthError(c, frontendCallback, "provider_error", providerErr, c.Query("error_description"))
+		return
+	}
+
+	code := strings.TrimSpace(c.Query("code"))
+	state := strings.TrimSpace(c.Query("state"))
+	if code == "" || state == "" {
+		redirectOAuthError(c, frontendCallback, "missing_params", "missing code/state", "")
+		return
+	}
+
+	cookiePath := ssoOAuthCookiePathPrefix + provider.Slug
+	secureCookie := isRequestHTTPS(c)
+	defer func() {
+		clearCookieAtPath(c, cookiePath, ssoOAuthStateCookieName, secureCookie)
+		clearCookieAtPath(c, cookiePath, ssoOAuthNonceCookieName, secureCookie)
+		clearCookieAtPath(c, cookiePath, ssoOAuthVerifierCookie, secureCookie)
+		clearCookieAtPath(c, cookiePath, ssoOAuthRedirectCookie, secureCookie)
+	}()
+
+	expectedState, err := readCookieDecoded(c, ssoOAuthStateCookieName)
+	if err != nil || expectedState == "" || state != expectedState {
+		redirectOAuthError(c, frontendCallback, "invalid_state", "invalid oauth state", "")
+		return
+	}
+
+	redirectTo, _ := readCookieDecoded(c, ssoOAuthRedirectCookie)
+	redirectTo = sanitizeFrontendRedirectPath(redirectTo)
+	if redirectTo == "" {
+		redirectTo = linuxDoOAuthDefaultRedirectTo
+	}
+
+	nonce, _ := readCookieDecoded(c, ssoOAuthNonceCookieName)
+	if provider.Type == service.SSOProviderTypeOIDC && nonce == "" {
+		redirectOAuthError(c, frontendCallback, "missing_nonce", "missing oidc nonce", "")
+		return
+	}
+	codeVerifier := ""
+	if provider.UsePKCE {
+		codeVerifier, _ = readCookieDecoded(c, ssoOAuthVerifierCookie)
+		if codeVerifier == "" {
+			redirectOAuthError(c, frontendCallback, "missing_verifier", "missing pkce verifier", "")
+			return
+		}
+	}
+
+	tokenPair, user, err := h.ssoService.CompleteLogin(ctx, provider, &service.SSOCallbackInput{
+		Code:         code,
+		Nonce:        nonce,
+		CodeVerifier: codeVerifier,
+		RedirectURI:  h.ssoService.RedirectURI(provider, requestOrigin(c)),
+	})
+	if err != nil {
+		log.Printf("[SSO] login failed: provider=%s err=%v", provider.Slug, err)
+		h.loginHistory.RecordFailure(ctx, 0, "", service.LoginMethodSSO(provider.Slug), err)
+		// 避免把内部细节泄露给客户端；给前端保留结构化原因与提示信息即可。
+		redirectOAuthError(c, frontendCallback, "login_failed", infraerrors.Reason(err), infraerrors.Message(err))
+		return
+	}
+
+	h.loginHistory.RecordSuccess(ctx, user, service.LoginMethodSSO(provider.Slug))
+
+	fragment := url.Values{}
+	fragment.Set("access_token", tokenPair.AccessToken)
+	fragment.Set("refresh_token", tokenPair.RefreshToken)
+	fragment.Set("expires_in", fmt.Sprintf("%d", tokenPair.ExpiresIn))
+	fragment.Set("token_type", "Bearer")
+	fragment.Set("redirect", redirectTo)
+	redirectWithFragment(c, frontendCallback, fragment)
+}
+
+// requestOrigin 按请求推导站点来源（未配置 server.frontend_url 时用于拼接回调地址）
+func requestOrigin(c *gin.Context) string {
+	scheme := "http"
+	if isRequestHTTPS(c) {
+		scheme = "https"
+	}
+	return scheme + "://" + c.Request.Host
+}
@@ -2,6 +2,7 @@ package handler
 
 import (
 	"github.com/Wei-Shaw/sub2api/internal/handler/dto"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
 	"github.com/Wei-Shaw/sub2api/internal/pkg/response"
 	middleware2 "github.com/Wei-Shaw/sub2api/internal/server/middleware"
 	"github.com/Wei-Shaw/sub2api/internal/service"
@@ -135,7 +136,7 @@ func (h *SubscriptionHandler) GetSummary(c *gin.Context) {
 		return
 	}
 
-	var totalUsed float64
+	var totalUsed money.Amount
 	items := make([]SubscriptionSummaryItem, 0, len(subscriptions))
 
 	for _, sub := range subscriptions {
@@ -143,22 +144,22 @@ func (h *SubscriptionHandler) GetSummary(c *gin.Context) {
 			ID:             sub.ID,
 			GroupID:        sub.GroupID,
 			Status:         sub.Status,
-			DailyUsedUSD:   sub.DailyUsageUSD,
-			WeeklyUsedUSD:  sub.WeeklyUsageUSD,
-			MonthlyUsedUSD: sub.MonthlyUsageUSD,
+			DailyUsedUSD:   sub.DailyUsageUSD.Float64(),
+			WeeklyUsedUSD:  sub.WeeklyUsageUSD.Float64(),
+			MonthlyUsedUSD: sub.MonthlyUsageUSD.Float64(),
 		}
 
 		// Add group info if preloaded
 		if sub.Group != nil {
 			item.GroupName = sub.Group.Name
 			if sub.Group.DailyLimitUSD != nil {
-				item.DailyLimitUSD = *sub.Group.DailyLimitUSD
+				item.DailyLimitUSD = sub.Group.DailyLimitUSD.Float64()
 			}
 			if sub.Group.WeeklyLimitUSD != nil {
-				item.WeeklyLimitUSD = *sub.Group.WeeklyLimitUSD
+				item.WeeklyLimitUSD = sub.Group.WeeklyLimitUSD.Float64()
 			}
 			if sub.Group.MonthlyLimitUSD != nil {
-				item.MonthlyLimitUSD = *sub.Group.MonthlyLimitUSD
+				item.MonthlyLimitUSD = sub.Group.MonthlyLimitUSD.Float64()
 			}
 		}
 
@@ -180,7 +181,7 @@ func (h *SubscriptionHandler) GetSummary(c *gin.Context) {
 		Subscriptions []SubscriptionSummaryItem `json:"subscriptions"`
 	}{
 		ActiveCount:   len(subscriptions),
-		TotalUsedUSD:  totalUsed,
+		TotalUsedUSD:  totalUsed.Float64(),
 		Subscriptions: items,
 	}
 
@@ -1,8 +1,13 @@
 package handler
 
 import (
+	"strings"
+	"time"
+
 	"github.com/Wei-Shaw/sub2api/internal/handler/dto"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
 	"github.com/Wei-Shaw/sub2api/internal/pkg/response"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/timezone"
 	middleware2 "github.com/Wei-Shaw/sub2api/internal/server/middleware"
 	"github.com/Wei-Shaw/sub2api/internal/service"
 
@@ -11,13 +16,15 @@ import (
 
 // UserHandler handles user-related requests
 type UserHandler struct {
-	userService *service.UserService
+	userService          *service.UserService
+	balanceLedgerService *service.BalanceLedgerService
 }
 
 // NewUserHandler creates a new UserHandler
-func NewUserHandler(userService *service.UserService) *UserHandler {
+func NewUserHandler(userService *service.UserService, balanceLedgerService *service.BalanceLedgerService) *UserHandler {
 	return &UserHandler{
-		userService: userService,
+		userService:          userService,
+		balanceLedgerService: balanceLedgerService,
 	}
 }
 
@@ -104,3 +111,64 @@ func (h *UserHandler) UpdateProfile(c *gin.Context) {
 
 	response.Success(c, dto.UserFromService(updatedUser))
 }
+
+// ListBalanceTransactions handles listing the current user's balance ledger
+// GET /api/v1/user/balance/transactions
+// Query params:
+//   - source_type: filter by source (redeem, promo_code, admin_adjust, usage, ...)
+//   - start_date / end_date: YYYY-MM-DD in the user's timezone
+func (h *UserHandler) ListBalanceTransactions(c *gin.Context) {
+	subject, ok := middleware2.GetAuthSubjectFromContext(c)
+	if !ok {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	page, pageSize := response.ParsePagination(c)
+
+	filter, ok := parseBalanceTransactionFilter(c)
+	if !ok {
+		return
+	}
+
+	params := pagination.PaginationParams{Page: page, PageSize: pageSize}
+	entries, result, err := h.balanceLedgerService.ListUserTransactions(c.Request.Context(), subject.UserID, params, filter)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+
+	out := make([]dto.BalanceTransaction, 0, len(entries))
+	for i := range entries {
+		out = append(out, *dto.BalanceTransactionFromService(&entries[i]))
+	}
+	response.Paginated(c, out, result.Total, page, pageSize)
+}
+
+// parseBalanceTransactionFilter parses source_type/start_date/end_date query params;
+// on invalid input it writes a 400 response and returns false.
+func parseBalanceTransactionFilter(c *gin.Context) (service.BalanceLedgerFilter, bool) {
+	filter := service.BalanceLedgerFilter{
+		SourceType: strings.TrimSpace(c.Query("source_type")),
+	}
+	userTZ := c.Query("timezone")
+	if startDateStr := c.Query("start_date"); startDateStr != "" {
+		t, err := timezone.ParseInUserLocation("2006-01-02", startDateStr, userTZ)
+		if err != nil {
+			response.BadRequest(c, "Invalid start_date format, use YYYY-MM-DD")
+			return filter, false
+		}
+		filter.StartTime = &t
+	}
+	if endDateStr := c.Query("end_date"); endDateStr != "" {
+		t, err := timezone.ParseInUserLocation("2006-01-02", endDateStr, userTZ)
+		if err != nil {
+			response.BadRequest(c, "Invalid end_date format, use YYYY-MM-DD")
+			return filter, false
+		}
+		// end_date 当天整天都包含在内
+		t = t.Add(24 * time.Hour)
+		filter.EndTime = &t
+	}
+	return filter, true
+}
@@ -31,6 +31,13 @@ func ProvideAdminHandlers(
 	userAttributeHandler *admin.UserAttributeHandler,
 	errorPassthroughHandler *admin.ErrorPassthroughHandler,
 	apiKeyHandler *admin.AdminAPIKeyHandler,
+	balanceLedgerHandler *admin.BalanceLedgerHandler,
+	ssoProviderHandler *admin.SSOProviderHandler,
+	userSessionHandler *admin.UserSessionHandler,
+	paymentHandler *admin.PaymentHandler,
+	referralHandler *admin.ReferralHandler,
+	pointsHandler *admin.PointsHandler,
+	couponHandler *admin.CouponHandler,
 ) *AdminHandlers {
 	return &AdminHandlers{
 		Dashboard:        dashboardHandler,
@@ -55,6 +62,13 @@ func ProvideAdminHandlers(
 		UserAttribute:    userAttributeHandler,
 		ErrorPassthrough: errorPassthroughHandler,
 		APIKey:           apiKeyHandler,
+		BalanceLedger:    balanceLedgerHandler,
+		SSOProvider:      ssoProviderHandler,
+		UserSession:      userSessionHandler,
+		Payment:          paymentHandler,
+		Referral:         referralHandler,
+		Points:           pointsHandler,
+		Coupon:           couponHandler,
 	}
 }
 
@@ -71,11 +85,19 @@ func ProvideSettingHandler(settingService *service.SettingService, buildInfo Bui
 // ProvideHandlers creates the Handlers struct
 func ProvideHandlers(
 	authHandler *AuthHandler,
+	ssoHandler *SSOHandler,
 	userHandler *UserHandler,
 	apiKeyHandler *APIKeyHandler,
 	usageHandler *UsageHandler,
 	voiceHandler *VoiceHandler,
 	redeemHandler *RedeemHandler,
+	organizationHandler *OrganizationHandler,
+	paymentHandler *PaymentHandler,
+	referralHandler *ReferralHandler,
+	pointsHandler *PointsHandler,
+	couponHandler *CouponHandler,
+	promoHandler *PromoHandler,
+	activityHandler *ActivityHandler,
 	subscriptionHandler *SubscriptionHandler,
 	announcementHandler *AnnouncementHandler,
 	distributorHandler *DistributorHandler,
@@ -84,18 +106,28 @@ func ProvideHandlers(
 	openaiGatewayHandler *OpenAIGatewayHandler,
 	soraGatewayHandler *SoraGatewayHandler,
 	soraClientHandler *SoraClientHandler,
+	batchHandler *BatchHandler,
 	settingHandler *SettingHandler,
 	totpHandler *TotpHandler,
+	securityHandler *SecurityHandler,
 	_ *service.IdempotencyCoordinator,
 	_ *service.IdempotencyCleanupService,
 ) *Handlers {
 	return &Handlers{
 		Auth:          authHandler,
+		SSO:           ssoHandler,
 		User:          userHandler,
 		APIKey:        apiKeyHandler,
 		Usage:         usageHandler,
 		Voice:         voiceHandler,
 		Redeem:        redeemHandler,
+		Organization:  organizationHandler,
+		Payment:       paymentHandler,
+		Referral:      referralHandler,
+		Points:        pointsHandler,
+		Coupon:        couponHandler,
+		Promo:         promoHandler,
+		Activity:      activityHandler,
 		Subscription:  subscriptionHandler,
 		Announcement:  announcementHandler,
 		Distributor:   distributorHandler,
@@ -104,8 +136,10 @@ func ProvideHandlers(
 		OpenAIGateway: openaiGatewayHandler,
 		SoraGateway:   soraGatewayHandler,
 		SoraClient:    soraClientHandler,
+		Batch:         batchHandler,
 		Setting:       settingHandler,
 		Totp:          totpHandler,
+		Security:      securityHandler,
 	}
 }
 
@@ -113,11 +147,19 @@ func ProvideHandlers(
 var ProviderSet = wire.NewSet(
 	// Top-level handlers
 	NewAuthHandler,
+	NewSSOHandler,
 	NewUserHandler,
 	NewAPIKeyHandler,
 	NewUsageHandler,
 	NewVoiceHandler,
 	NewRedeemHandler,
+	NewOrganizationHandler,
+	NewPaymentHandler,
+	NewReferralHandler,
+	NewPointsHandler,
+	NewCouponHandler,
+	NewPromoHandler,
+	NewActivityHandler,
 	NewSubscriptionHandler,
 	NewAnnouncementHandler,
 	NewDistributorHandler,
@@ -125,7 +167,9 @@ var ProviderSet = wire.NewSet(
 	NewOpenAIGatewayHandler,
 	NewSoraGatewayHandler,
 	NewSoraClientHandler,
+	NewBatchHandler,
 	NewTotpHandler,
+	NewSecurityHandler,
 	ProvideSettingHandler,
 
 	// Admin handlers
@@ -151,6 +195,13 @@ var ProviderSet = wire.NewSet(
 	admin.NewUserAttributeHandler,
 	admin.NewErrorPassthroughHandler,
 	admin.NewAdminAPIKeyHandler,
+	admin.NewBalanceLedgerHandler,
+	admin.NewUserSessionHandler,
+	admin.NewSSOProviderHandler,
+	admin.NewPaymentHandler,
+	admin.NewReferralHandler,
+	admin.NewPointsHandler,
+	admin.NewCouponHandler,
 
 	// AdminHandlers and Handlers constructors
 	ProvideAdminHandlers,
@@ -0,0 +1,335 @@
+package apicompat
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ---------------------------------------------------------------------------
+// ChatCompletionsToAnthropic tests
+// ---------------------------------------------------------------------------
+
+func TestChatCompletionsToAnthropic_BasicText(t *testing.T) {
+	req := &ChatCompletionsRequest{
+		Model:  "claude-sonnet-4-5",
+		Stream: true,
+		Messages: []ChatMessage{
+			{Role: "system", Content: json.RawMessage(`"Be brief."`)},
+			{Role: "developer", Content: json.RawMessage(`[{"type":"text","text":"No emojis."}]`)},
+			{Role: "user", Content: json.RawMessage(`"Hello"`)},
+		},
+	}
+
+	out, err := ChatCompletionsToAnthropic(req)
+	require.NoError(t, err)
+	assert.Equal(t, "claude-sonnet-4-5", out.Model)
+	assert.True(t, out.Stream)
+	assert.Equal(t, defaultAnthropicMaxTokens, out.MaxTokens)
+
+	var system string
+	require.NoError(t, json.Unmarshal(out.System, &system))
+	assert.Equal(t, "Be brief.\n\nNo emojis.", system)
+
+	require.Len(t, out.Messages, 1)
+	assert.Equal(t, "user", out.Messages[0].Role)
+	var blocks []AnthropicContentBlock
+	require.NoError(t, json.Unmarshal(out.Messages[0].Content, &blocks))
+	require.Len(t, blocks, 1)
+	assert.Equal(t, "Hello", blocks[0].Text)
+}
+
+func TestChatCompletionsToAnthropic_MaxTokensAndStop(t *testing.T) {
+	maxTokens := 100
+	maxCompletion := 200
+	req := &ChatCompletionsRequest{
+		Model:               "claude-haiku-4-5",
+		MaxTokens:           &maxTokens,
+		MaxCompletionTokens: &maxCompletion,
+		Stop:                json.RawMessage(`"END"`),
+		Messages:            []ChatMessage{{Role: "user", Content: json.RawMessage(`"Hi"`)}},
+	}
+	out, err := ChatCompletionsToAnthropic(req)
+	require.NoError(t, err)
+	assert.Equal(t, 200, out.MaxTokens)
+	assert.Equal(t, []string{"END"}, out.StopSeqs)
+
+	req.Stop = json.RawMessage(`["a","b"]`)
+	out, err = ChatCompletionsToAnthropic(req)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, out.StopSeqs)
+}
+
+func TestChatCompletionsToAnthropic_Images(t *testing.T) {
+	req := &ChatCompletionsRequest{
+		Model: "claude-sonnet-4-5",
+		Messages: []ChatMessage{{
+			Role: "user",
+			Content: json.RawMessage(`[
+				{"type":"text","text":"What is this?"},
+				{"type":"image_url","image_url":{"url":"data:image/png;base64,iVBORw0KGgo="}},
+				{"type":"image_url","image_url":{"url":"https://example.com/cat.jpg"}}
+			]`),
+		}},
+	}
+	out, err := ChatCompletionsToAnthropic(req)
+	require.NoError(t, err)
+
+	var blocks []AnthropicContentBlock
+	require.NoError(t, json.Unmarshal(out.Messages[0].Content, &blocks))
+	require.Len(t, blocks, 3)
+	assert.Equal(t, "image", blocks[1].Type)
+	require.NotNil(t, blocks[1].Source)
+	assert.Equal(t, "base64", blocks[1].Source.Type)
+	assert.Equal(t, "image/png", blocks[1].Source.MediaType)
+	assert.Equal(t, "iVBORw0KGgo=", blocks[1].Source.Data)
+	assert.Equal(t, "url", blocks[2].Source.Type)
+	assert.Equal(t, "https://example.com/cat.jpg", blocks[2].Source.URL)
+}
+
+func TestChatCompletionsToAnthropic_ToolRoundTrip(t *testing.T) {
+	parallel := false
+	req := &ChatCompletionsRequest{
+		Model: "claude-sonnet-4-5",
+		Tools: []ChatTool{{
+			Type: "function",
+			Function: &ChatFunction{
+				Name:        "get_weather",
+				Description: "Get weather",
+				Parameters:  json.RawMessage(`{"type":"object","properties":{"city":{"type":"string"}}}`),
+			},
+		}},
+		ToolChoice:        json.RawMessage(`"required"`),
+		ParallelToolCalls: &parallel,
+		Messages: []ChatMessage{
+			{Role: "user", Content: json.RawMessage(`"Weather in Paris and Rome?"`)},
+			{Role: "assistant", Content: json.RawMessage(`null`), ToolCalls: []ChatToolCall{
+				{ID: "call_1", Type: "function", Function: ChatFunctionCall{Name: "get_weather", Arguments: `{"city":"Paris"}`}},
+				{ID: "call_2", Type: "function", Function: ChatFunctionCall{Name: "get_weather", Arguments: `not json`}},
+			}},
+			{Role: "tool", ToolCallID: "call_1", Content: json.RawMessage(`"Sunny"`)},
+			{Role: "tool", ToolCallID: "call_2", Content: json.RawMessage(`"Rainy"`)},
+		},
+	}
+
+	out, err := ChatCompletionsToAnthropic(req)
+	require.NoError(t, err)
+
+	require.Len(t, out.Tools, 1)
+	assert.Equal(t, "get_weather", out.Tools[0].Name)
+	assert.JSONEq(t, `{"type":"any","disable_parallel_tool_use":true}`, string(out.ToolChoice))
+
+	require.Len(t, out.Messages, 3)
+	assert.Equal(t, "assistant", out.Messages[1].Role)
+	var assistant []AnthropicContentBlock
+	require.NoError(t, json.Unmarshal(out.Messages[1].Content, &assistant))
+	require.Len(t, assistant, 2)
+	assert.Equal(t, "tool_use", assistant[0].Type)
+	assert.Equal(t, "call_1", assistant[0].ID)
+	assert.JSONEq(t, `{"city":"Paris"}`, string(assistant[0].Input))
+	assert.JSONEq(t, `{}`, string(assistant[1].Input))
+
+	// Consecutive tool messages are merged into a single user turn.
+	assert.Equal(t, "user", out.Messages[2].Role)
+	var results []AnthropicContentBlock
+	require.NoError(t, json.Unmarshal(out.Messages[2].Content, &results))
+	require.Len(t, results, 2)
+	assert.Equal(t, "tool_result", results[0].Type)
+	assert.Equal(t, "call_1", results[0].ToolUseID)
+	assert.Equal(t, "call_2", results[1].ToolUseID)
+}
+
+func TestChatCompletionsToAnthropic_ToolChoice(t *testing.T) {
+	tools := []ChatTool{{Type: "function", Function: &ChatFunction{Name: "f"}}}
+	tests := []struct {
+		name   string
+		choice string
+		want   string
+	}{
+		{"auto", `"auto"`, `{"type":"auto"}`},
+		{"none", `"none"`, `{"type":"none"}`},
+		{"named", `{"type":"function","function":{"name":"f"}}`, `{"type":"tool","name":"f"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := ChatCompletionsToAnthropic(&ChatCompletionsRequest{
+				Model:      "m",
+				Tools:      tools,
+				ToolChoice: json.RawMessage(tt.choice),
+				Messages:   []ChatMessage{{Role: "user", Content: json.RawMessage(`"x"`)}},
+			})
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.want, string(out.ToolChoice))
+			assert.JSONEq(t, `{"type":"object","properties":{}}`, string(out.Tools[0].InputSchema))
+		})
+	}
+}
+
+func TestChatCompletionsToAnthropic_ReasoningEffort(t *testing.T) {
+	maxTokens := 1000
+	temp := 0.2
+	out, err := ChatCompletionsToAnthropic(&ChatCompletionsRequest{
+		Model:           "claude-sonnet-4-5",
+		MaxTokens:       &maxTokens,
+		Temperature:     &temp,
+		ReasoningEffort: "medium",
+		Messages:        []ChatMessage{{Role: "user", Content: json.RawMessage(`"Think"`)}},
+	})
+	require.NoError(t, err)
+	require.NotNil(t, out.Thinking)
+	assert.Equal(t, "enabled", out.Thinking.Type)
+	assert.Equal(t, 4096, out.Thinking.BudgetTokens)
+	assert.Equal(t, 5096, out.MaxTokens)
+	assert.Nil(t, out.Temperature)
+}
+
+// ---------------------------------------------------------------------------
+// AnthropicToChatCompletions tests
+// ---------------------------------------------------------------------------
+
+func TestAnthropicToChatCompletions_TextAndThinking(t *testing.T) {
+	resp := &AnthropicResponse{
+		ID:   "msg_123",
+		Type: "message",
+		Role: "assistant",
+		Content: []AnthropicContentBlock{
+			{Type: "thinking", Thinking: "Let me think."},
+			{Type: "text", Text: "Hello!"},
+		},
+		StopReason: "end_turn",
+		Usage:      AnthropicUsage{InputTokens: 10, OutputTokens: 5, CacheReadInputTokens: 20},
+	}
+
+	out := AnthropicToChatCompletions(resp, "claude-sonnet-4-5")
+	assert.Equal(t, "chatcmpl-msg_123", out.ID)
+	assert.Equal(t, "chat.completion", out.Object)
+	assert.Equal(t, "claude-sonnet-4-5", out.Model)
+	require.Len(t, out.Choices, 1)
+	assert.Equal(t, "stop", out.Choices[0].FinishReason)
+	assert.JSONEq(t, `"Hello!"`, string(out.Choices[0].Message.Content))
+	assert.Equal(t, "Let me think.", out.Choices[0].Message.ReasoningContent)
+
+	require.NotNil(t, out.Usage)
+	assert.Equal(t, 30, out.Usage.PromptTokens)
+	assert.Equal(t, 5, out.Usage.CompletionTokens)
+	assert.Equal(t, 35, out.Usage.TotalTokens)
+	require.NotNil(t, out.Usage.PromptTokensDetails)
+	assert.Equal(t, 20, out.Usage.PromptTokensDetails.CachedTokens)
+}
+
+func TestAnthropicToChatCompletions_ToolUse(t *testing.T) {
+	resp := &AnthropicResponse{
+		ID: "msg_1",
+		Content: []AnthropicContentBlock{
+			{Type: "tool_use", ID: "toolu_1", Name: "get_weather", Input: json.RawMessage(`{"city":"Paris"}`)},
+		},
+		StopReason: "tool_use",
+	}
+	out := AnthropicToChatCompletions(resp, "m")
+	assert.Equal(t, "tool_calls", out.Choices[0].FinishReason)
+	assert.Equal(t, "null", string(out.Choices[0].Message.Content))
+	require.Len(t, out.Choices[0].Message.ToolCalls, 1)
+	tc := out.Choices[0].Message.ToolCalls[0]
+	assert.Nil(t, tc.Index)
+	assert.Equal(t, "toolu_1", tc.ID)
+	assert.Equal(t, "function", tc.Type)
+	assert.Equal(t, "get_weather", tc.Function.Name)
+	assert.JSONEq(t, `{"city":"Paris"}`, tc.Function.Arguments)
+}
+
+func TestAnthropicStopReasonToChat(t *testing.T) {
+	assert.Equal(t, "stop", anthropicStopReasonToChat("end_turn"))
+	assert.Equal(t, "stop", anthropicStopReasonToChat("stop_sequence"))
+	assert.Equal(t, "length", anthropicStopReasonToChat("max_tokens"))
+	assert.Equal(t, "tool_calls", anthropicStopReasonToChat("tool_use"))
+	assert.Equal(t, "content_filter", anthropicStopReasonToChat("refusal"))
+}
+
+// ---------------------------------------------------------------------------
+// AnthropicEventToChatChunks tests
+// ---------------------------------------------------------------------------
+
+func TestAnthropicEventToChatChunks_TextStreamWithUsage(t *testing.T) {
+	state := NewAnthropicEventToChatState()
+	state.Model = "client-model"
+	state.IncludeUsage = true
+
+	var chunks []ChatCompletionsChunk
+	idx := 0
+	for _, evt := range []AnthropicStreamEvent{
+		{Type: "message_start", Message: &AnthropicResponse{ID: "msg_1", Model: "upstream", Usage: AnthropicUsage{InputTokens: 7}}},
+		{Type: "content_block_start", Index: &idx, ContentBlock: &AnthropicContentBlock{Type: "text"}},
+		{Type: "content_block_delta", Index: &idx, Delta: &AnthropicDelta{Type: "text_delta", Text: "Hel"}},
+		{Type: "content_block_delta", Index: &idx, Delta: &AnthropicDelta{Type: "text_delta", Text: "lo"}},
+		{Type: "content_block_stop", Index: &idx},
+		{Type: "message_delta", Delta: &AnthropicDelta{StopReason: "max_tokens"}, Usage: &AnthropicUsage{OutputTokens: 3}},
+		{Type: "message_stop"},
+	} {
+		chunks = append(chunks, AnthropicEventToChatChunks(&evt, state)...)
+	}
+
+	require.Len(t, chunks, 5)
+	assert.Equal(t, "assistant", chunks[0].Choices[0].Delta.Role)
+	assert.Equal(t, "chatcmpl-msg_1", chunks[0].ID)
+	assert.Equal(t, "client-model", chunks[0].Model)
+	assert.Equal(t, "Hel", *chunks[1].Choices[0].Delta.Content)
+	assert.Equal(t, "lo", *chunks[2].Choices[0].Delta.Content)
+	require.NotNil(t, chunks[3].Choices[0].FinishReason)
+	assert.Equal(t, "length", *chunks[3].Choices[0].FinishReason)
+	assert.Empty(t, chunks[4].Choices)
+	require.NotNil(t, chunks[4].Usage)
+	assert.Equal(t, 7, chunks[4].Usage.PromptTokens)
+	assert.Equal(t, 3, chunks[4].Usage.CompletionTokens)
+
+	// Finalize after a proper stop is a no-op.
+	assert.Nil(t, FinalizeAnthropicChatStream(state))
+}
+
+func TestAnthropicEventToChatChunks_ToolCallsAndThinking(t *testing.T) {
+	state := NewAnthropicEventToChatState()
+	var chunks []ChatCompletionsChunk
+	i0, i1, i2 := 0, 1, 2
+	for _, evt := range []AnthropicStreamEvent{
+		{Type: "message_start", Message: &AnthropicResponse{ID: "msg_2"}},
+		{Type: "content_block_start", Index: &i0, ContentBlock: &AnthropicContentBlock{Type: "thinking"}},
+		{Type: "content_block_delta", Index: &i0, Delta: &AnthropicDelta{Type: "thinking_delta", Thinking: "hmm"}},
+		{Type: "content_block_delta", Index: &i0, Delta: &AnthropicDelta{Type: "signature_delta", Signature: "sig"}},
+		{Type: "content_block_start", Index: &i1, ContentBlock: &AnthropicContentBlock{Type: "tool_use", ID: "toolu_a", Name: "f"}},
+		{Type: "content_block_delta", Index: &i1, Delta: &AnthropicDelta{Type: "input_json_delta", PartialJSON: `{"a":`}},
+		{Type: "content_block_delta", Index: &i1, Delta: &AnthropicDelta{Type: "input_json_delta", PartialJSON: `1}`}},
+		{Type: "content_block_start", Index: &i2, ContentBlock: &AnthropicContentBlock{Type: "tool_use", ID: "toolu_b", Name: "g"}},
+		{Type: "message_delta", Delta: &AnthropicDelta{StopReason: "tool_use"}},
+	} {
+		chunks = append(chunks, AnthropicEventToChatChunks(&evt, state)...)
+	}
+	// Stream cut before message_stop: finalize emits the finish chunk.
+	chunks = append(chunks, FinalizeAnthropicChatStream(state)...)
+
+	require.Len(t, chunks, 7)
+	assert.Equal(t, "hmm", *chunks[1].Choices[0].Delta.ReasoningContent)
+
+	first := chunks[2].Choices[0].Delta.ToolCalls[0]
+	assert.Equal(t, 0, *first.Index)
+	assert.Equal(t, "toolu_a", first.ID)
+	assert.Equal(t, "f", first.Function.Name)
+	assert.Equal(t, `{"a":`, chunks[3].Choices[0].Delta.ToolCalls[0].Function.Arguments)
+	assert.Equal(t, `1}`, chunks[4].Choices[0].Delta.ToolCalls[0].Function.Arguments)
+	assert.Equal(t, 1, *chunks[5].Choices[0].Delta.ToolCalls[0].Index)
+	assert.Equal(t, "tool_calls", *chunks[6].Choices[0].FinishReason)
+	assert.Nil(t, chunks[6].Usage)
+}
+
+func TestChatCompletionsChunkToSSE(t *testing.T) {
+	reason := "stop"
+	sse, err := ChatCompletionsChunkToSSE(ChatCompletionsChunk{
+		ID:      "chatcmpl-1",
+		Object:  "chat.completion.chunk",
+		Model:   "m",
+		Choices: []ChatChunkChoice{{Delta: ChatDelta{}, FinishReason: &reason}},
+	})
+	require.NoError(t, err)
+	assert.Contains(t, sse, `"finish_reason":"stop"`)
+	assert.Regexp(t, `^data: \{.*\}\n\n$`, sse)
+}
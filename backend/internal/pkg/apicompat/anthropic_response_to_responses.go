@@ -0,0 +1,561 @@
+package apicompat
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ---------------------------------------------------------------------------
+// Non-streaming: AnthropicResponse → ResponsesResponse
+// ---------------------------------------------------------------------------
+
+// AnthropicResponseToResponses converts an Anthropic Messages response into a
+// Responses API response. Thinking blocks become reasoning items (with the
+// signature packed into encrypted_content so they can be replayed), text
+// blocks become a message item and tool_use blocks become function_call
+// items.
+func AnthropicResponseToResponses(resp *AnthropicResponse, model string) *ResponsesResponse {
+	if model == "" {
+		model = resp.Model
+	}
+	id := toResponsesResponseID(resp.ID)
+
+	var output []ResponsesOutput
+	var texts []string
+	flushText := func() {
+		if len(texts) == 0 {
+			return
+		}
+		output = append(output, newResponsesMessageOutput(id, len(output), strings.Join(texts, "")))
+		texts = nil
+	}
+	for _, b := range resp.Content {
+		switch b.Type {
+		case "text":
+			if b.Text != "" {
+				texts = append(texts, b.Text)
+			}
+		case "thinking":
+			flushText()
+			output = append(output, newResponsesReasoningOutput(id, len(output), b.Thinking, b.Signature))
+		case "tool_use":
+			flushText()
+			args := "{}"
+			if len(b.Input) > 0 {
+				args = string(b.Input)
+			}
+			output = append(output, newResponsesFunctionCallOutput(b.ID, b.Name, args))
+		}
+	}
+	flushText()
+	if output == nil {
+		output = []ResponsesOutput{}
+	}
+
+	out := &ResponsesResponse{
+		ID:        id,
+		Object:    "response",
+		CreatedAt: time.Now().Unix(),
+		Model:     model,
+		Output:    output,
+		Usage: anthropicUsageToResponses(resp.Usage.InputTokens, resp.Usage.OutputTokens,
+			resp.Usage.CacheReadInputTokens, resp.Usage.CacheCreationInputTokens),
+	}
+	out.Status, out.IncompleteDetails = anthropicStopReasonToResponsesStatus(resp.StopReason)
+	return out
+}
+
+// ResponsesOutputToInputItems converts response output items back into input
+// items, so a finished turn can be appended to the stored conversation and
+// replayed for previous_response_id.
+func ResponsesOutputToInputItems(output []ResponsesOutput) []ResponsesInputItem {
+	items := make([]ResponsesInputItem, 0, len(output))
+	for _, o := range output {
+		switch o.Type {
+		case "message":
+			content, err := json.Marshal(o.Content)
+			if err != nil {
+				continue
+			}
+			items = append(items, ResponsesInputItem{Type: "message", Role: "assistant", Content: content})
+		case "reasoning":
+			items = append(items, ResponsesInputItem{
+				Type:             "reasoning",
+				ID:               o.ID,
+				EncryptedContent: o.EncryptedContent,
+				Summary:          o.Summary,
+			})
+		case "function_call":
+			items = append(items, ResponsesInputItem{
+				Type:      "function_call",
+				ID:        o.ID,
+				CallID:    o.CallID,
+				Name:      o.Name,
+				Arguments: o.Arguments,
+			})
+		}
+	}
+	return items
+}
+
+// anthropicStopReasonToResponsesStatus maps an Anthropic stop_reason to a
+// Responses status and optional incomplete_details.
+func anthropicStopReasonToResponsesStatus(reason string) (string, *ResponsesIncompleteDetails) {
+	switch reason {
+	case "max_tokens", "model_context_window_exceeded":
+		return "incomplete", &ResponsesIncompleteDetails{Reason: "max_output_tokens"}
+	case "refusal":
+		return "incomplete", &ResponsesIncompleteDetails{Reason: "content_filter"}
+	default:
+		return "completed", nil
+	}
+}
+
+// anthropicUsageToResponses builds Responses usage. Like Chat Completions,
+// the Responses API counts cached tokens inside input_tokens.
+func anthropicUsageToResponses(input, output, cacheRead, cacheCreation int) *ResponsesUsage {
+	total := input + cacheRead + cacheCreation
+	usage := &ResponsesUsage{
+		InputTokens:  total,
+		OutputTokens: output,
+		TotalTokens:  total + output,
+	}
+	if cacheRead > 0 {
+		usage.InputTokensDetails = &ResponsesInputTokensDetails{CachedTokens: cacheRead}
+	}
+	return usage
+}
+
+// toResponsesResponseID turns an Anthropic message ID into a "resp_" ID.
+func toResponsesResponseID(id string) string {
+	if id == "" {
+		return fmt.Sprintf("resp_%d", time.Now().UnixNano())
+	}
+	if strings.HasPrefix(id, "resp_") {
+		return id
+	}
+	return "resp_" + strings.TrimPrefix(id, "msg_")
+}
+
+func responsesItemID(prefix, responseID string, outputIndex int) string {
+	return fmt.Sprintf("%s_%s_%d", prefix, strings.TrimPrefix(responseID, "resp_"), outputIndex)
+}
+
+func newResponsesMessageOutput(responseID string, outputIndex int, text string) ResponsesOutput {
+	return ResponsesOutput{
+		Type:    "message",
+		ID:      responsesItemID("msg", responseID, outputIndex),
+		Role:    "assistant",
+		Status:  "completed",
+		Content: []ResponsesContentPart{{Type: "output_text", Text: text}},
+	}
+}
+
+func newResponsesReasoningOutput(responseID string, outputIndex int, thinking, signature string) ResponsesOutput {
+	return ResponsesOutput{
+		Type:             "reasoning",
+		ID:               responsesItemID("rs", responseID, outputIndex),
+		Summary:          []ResponsesSummary{{Type: "summary_text", Text: thinking}},
+		EncryptedContent: encodeResponsesReasoning(thinking, signature),
+	}
+}
+
+func newResponsesFunctionCallOutput(toolUseID, name, args string) ResponsesOutput {
+	return ResponsesOutput{
+		Type:      "function_call",
+		ID:        toResponsesCallID(toolUseID),
+		CallID:    toolUseID,
+		Name:      name,
+		Arguments: args,
+		Status:    "completed",
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Streaming: AnthropicStreamEvent → []ResponsesStreamEvent (stateful converter)
+// ---------------------------------------------------------------------------
+
+// AnthropicEventToResponsesState tracks state for converting a sequence of
+// Anthropic SSE events into Responses SSE events. Once the stream finishes,
+// Response holds the final response object (output items included).
+type AnthropicEventToResponsesState struct {
+	Started  bool
+	Finished bool
+
+	// Blocks maps Anthropic content block index → open output item.
+	Blocks map[int]*AnthropicResponsesBlock
+
+	Output []ResponsesOutput
+
+	StopReason string
+
+	InputTokens              int
+	OutputTokens             int
+	CacheReadInputTokens     int
+	CacheCreationInputTokens int
+
+	ResponseID     string
+	Model          string
+	CreatedAt      int64
+	SequenceNumber int
+
+	// Response is set once response.completed / response.incomplete is emitted.
+	Response *ResponsesResponse
+}
+
+// AnthropicResponsesBlock accumulates one content block while it streams.
+type AnthropicResponsesBlock struct {
+	OutputIndex int
+	Item        ResponsesOutput
+	Text        strings.Builder
+	Signature   string
+}
+
+// NewAnthropicEventToResponsesState returns an initialised stream state.
+func NewAnthropicEventToResponsesState() *AnthropicEventToResponsesState {
+	return &AnthropicEventToResponsesState{
+		Blocks:    make(map[int]*AnthropicResponsesBlock),
+		CreatedAt: time.Now().Unix(),
+	}
+}
+
+// AnthropicEventToResponsesEvents converts a single Anthropic SSE event into
+// zero or more Responses SSE events, updating state as it goes.
+func AnthropicEventToResponsesEvents(evt *AnthropicStreamEvent, state *AnthropicEventToResponsesState) []ResponsesStreamEvent {
+	switch evt.Type {
+	case "message_start":
+		return anthToResHandleMessageStart(evt, state)
+	case "content_block_start":
+		return anthToResHandleBlockStart(evt, state)
+	case "content_block_delta":
+		return anthToResHandleBlockDelta(evt, state)
+	case "content_block_stop":
+		return anthToResHandleBlockStop(evt, state)
+	case "message_delta":
+		return anthToResHandleMessageDelta(evt, state)
+	case "message_stop":
+		return anthToResHandleMessageStop(state)
+	default:
+		return nil
+	}
+}
+
+// FinalizeAnthropicResponsesStream closes any open items and emits the final
+// response event if the stream ended without message_stop.
+func FinalizeAnthropicResponsesStream(state *AnthropicEventToResponsesState) []ResponsesStreamEvent {
+	if !state.Started || state.Finished {
+		return nil
+	}
+	return anthToResHandleMessageStop(state)
+}
+
+// ResponsesEventToSSE formats a ResponsesStreamEvent as an SSE line pair.
+func ResponsesEventToSSE(evt ResponsesStreamEvent) (string, error) {
+	data, err := json.Marshal(evt)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("event: %s\ndata: %s\n\n", evt.Type, data), nil
+}
+
+// --- internal handlers ---
+
+func anthToResHandleMessageStart(evt *AnthropicStreamEvent, state *AnthropicEventToResponsesState) []ResponsesStreamEvent {
+	if evt.Message != nil {
+		state.ResponseID = toResponsesResponseID(evt.Message.ID)
+		if state.Model == "" {
+			state.Model = evt.Message.Model
+		}
+		state.InputTokens = evt.Message.Usage.InputTokens
+		state.OutputTokens = evt.Message.Usage.OutputTokens
+		state.CacheReadInputTokens = evt.Message.Usage.CacheReadInputTokens
+		state.CacheCreationInputTokens = evt.Message.Usage.CacheCreationInputTokens
+	}
+	return anthToResEnsureStarted(state)
+}
+
+func anthToResEnsureStarted(state *AnthropicEventToResponsesState) []ResponsesStreamEvent {
+	if state.Started {
+		return nil
+	}
+	state.Started = true
+	if state.ResponseID == "" {
+		state.ResponseID = toResponsesResponseID("")
+	}
+	resp := state.snapshot("in_progress")
+	return []ResponsesStreamEvent{
+		state.event(ResponsesStreamEvent{Type: "response.created", Response: resp}),
+		state.event(ResponsesStreamEvent{Type: "response.in_progress", Response: resp}),
+	}
+}
+
+func anthToResHandleBlockStart(evt *AnthropicStreamEvent, state *AnthropicEventToResponsesState) []ResponsesStreamEvent {
+	if evt.ContentBlock == nil || evt.Index == nil {
+		return nil
+	}
+	events := anthToResEnsureStarted(state)
+
+	outputIndex := len(state.Output) + len(state.Blocks)
+	block := &AnthropicResponsesBlock{OutputIndex: outputIndex}
+
+	switch evt.ContentBlock.Type {
+	case "text":
+		block.Item = ResponsesOutput{
+			Type:    "message",
+			ID:      responsesItemID("msg", state.ResponseID, outputIndex),
+			Role:    "assistant",
+			Status:  "in_progress",
+			Content: []ResponsesContentPart{},
+		}
+		item := block.Item
+		events = append(events,
+			state.event(ResponsesStreamEvent{Type: "response.output_item.added", OutputIndex: outputIndex, Item: &item}),
+			state.event(ResponsesStreamEvent{
+				Type:        "response.content_part.added",
+				OutputIndex: outputIndex,
+				ItemID:      block.Item.ID,
+				Part:        &ResponsesContentPart{Type: "output_text"},
+			}),
+		)
+	case "thinking":
+		block.Item = ResponsesOutput{
+			Type:    "reasoning",
+			ID:      responsesItemID("rs", state.ResponseID, outputIndex),
+			Summary: []ResponsesSummary{},
+		}
+		item := block.Item
+		events = append(events,
+			state.event(ResponsesStreamEvent{Type: "response.output_item.added", OutputIndex: outputIndex, Item: &item}),
+			state.event(ResponsesStreamEvent{
+				Type:        "response.reasoning_summary_part.added",
+				OutputIndex: outputIndex,
+				ItemID:      block.Item.ID,
+				Part:        &ResponsesContentPart{Type: "summary_text"},
+			}),
+		)
+	case "tool_use":
+		block.Item = ResponsesOutput{
+			Type:   "function_call",
+			ID:     toResponsesCallID(evt.ContentBlock.ID),
+			CallID: evt.ContentBlock.ID,
+			Name:   evt.ContentBlock.Name,
+			Status: "in_progress",
+		}
+		item := block.Item
+		events = append(events,
+			state.event(ResponsesStreamEvent{Type: "response.output_item.added", OutputIndex: outputIndex, Item: &item}),
+		)
+	default:
+		// redacted_thinking and server tool blocks have no Responses equivalent.
+		return events
+	}
+
+	state.Blocks[*evt.Index] = block
+	return events
+}
+
+func anthToResHandleBlockDelta(evt *AnthropicStreamEvent, state *AnthropicEventToResponsesState) []ResponsesStreamEvent {
+	if evt.Delta == nil || evt.Index == nil {
+		return nil
+	}
+	block, ok := state.Blocks[*evt.Index]
+	if !ok {
+		return nil
+	}
+
+	switch evt.Delta.Type {
+	case "text_delta":
+		if evt.Delta.Text == "" {
+			return nil
+		}
+		block.Text.WriteString(evt.Delta.Text)
+		return []ResponsesStreamEvent{state.event(ResponsesStreamEvent{
+			Type:        "response.output_text.delta",
+			OutputIndex: block.OutputIndex,
+			ItemID:      block.Item.ID,
+			Delta:       evt.Delta.Text,
+		})}
+	case "thinking_delta":
+		if evt.Delta.Thinking == "" {
+			return nil
+		}
+		block.Text.WriteString(evt.Delta.Thinking)
+		return []ResponsesStreamEvent{state.event(ResponsesStreamEvent{
+			Type:        "response.reasoning_summary_text.delta",
+			OutputIndex: block.OutputIndex,
+			ItemID:      block.Item.ID,
+			Delta:       evt.Delta.Thinking,
+		})}
+	case "signature_delta":
+		block.Signature += evt.Delta.Signature
+	case "input_json_delta":
+		if evt.Delta.PartialJSON == "" {
+			return nil
+		}
+		block.Text.WriteString(evt.Delta.PartialJSON)
+		return []ResponsesStreamEvent{state.event(ResponsesStreamEvent{
+			Type:        "response.function_call_arguments.delta",
+			OutputIndex: block.OutputIndex,
+			ItemID:      block.Item.ID,
+			Delta:       evt.Delta.PartialJSON,
+		})}
+	}
+	return nil
+}
+
+func anthToResHandleBlockStop(evt *AnthropicStreamEvent, state *AnthropicEventToResponsesState) []ResponsesStreamEvent {
+	if evt.Index == nil {
+		return nil
+	}
+	block, ok := state.Blocks[*evt.Index]
+	if !ok {
+		return nil
+	}
+	delete(state.Blocks, *evt.Index)
+	return state.closeBlock(block)
+}
+
+func anthToResHandleMessageDelta(evt *AnthropicStreamEvent, state *AnthropicEventToResponsesState) []ResponsesStreamEvent {
+	if evt.Delta != nil && evt.Delta.StopReason != "" {
+		state.StopReason = evt.Delta.StopReason
+	}
+	if evt.Usage != nil {
+		// message_delta usage is cumulative; only override non-zero values
+		// so a sparse delta does not erase counts from message_start.
+		if evt.Usage.InputTokens > 0 {
+			state.InputTokens = evt.Usage.InputTokens
+		}
+		if evt.Usage.OutputTokens > 0 {
+			state.OutputTokens = evt.Usage.OutputTokens
+		}
+		if evt.Usage.CacheReadInputTokens > 0 {
+			state.CacheReadInputTokens = evt.Usage.CacheReadInputTokens
+		}
+		if evt.Usage.CacheCreationInputTokens > 0 {
+			state.CacheCreationInputTokens = evt.Usage.CacheCreationInputTokens
+		}
+	}
+	return nil
+}
+
+func anthToResHandleMessageStop(state *AnthropicEventToResponsesState) []ResponsesStreamEvent {
+	if state.Finished {
+		return nil
+	}
+	events := anthToResEnsureStarted(state)
+
+	// Close blocks that never received content_block_stop, in output order.
+	for len(state.Blocks) > 0 {
+		minIdx, minOut := -1, -1
+		for idx, b := range state.Blocks {
+			if minOut < 0 || b.OutputIndex < minOut {
+				minIdx, minOut = idx, b.OutputIndex
+			}
+		}
+		block := state.Blocks[minIdx]
+		delete(state.Blocks, minIdx)
+		events = append(events, state.closeBlock(block)...)
+	}
+
+	state.Finished = true
+	status, details := anthropicStopReasonToResponsesStatus(state.StopReason)
+	resp := state.snapshot(status)
+	resp.IncompleteDetails = details
+	resp.Usage = anthropicUsageToResponses(state.InputTokens, state.OutputTokens,
+		state.CacheReadInputTokens, state.CacheCreationInputTokens)
+	state.Response = resp
+
+	evtType := "response.completed"
+	if status == "incomplete" {
+		evtType = "response.incomplete"
+	}
+	return append(events, state.event(ResponsesStreamEvent{Type: evtType, Response: resp}))
+}
+
+// closeBlock emits the *.done events for a finished block and records the
+// completed item in state.Output.
+func (state *AnthropicEventToResponsesState) closeBlock(block *AnthropicResponsesBlock) []ResponsesStreamEvent {
+	var events []ResponsesStreamEvent
+	item := block.Item
+	text := block.Text.String()
+
+	switch item.Type {
+	case "message":
+		part := ResponsesContentPart{Type: "output_text", Text: text}
+		item.Status = "completed"
+		item.Content = []ResponsesContentPart{part}
+		events = append(events,
+			state.event(ResponsesStreamEvent{
+				Type:        "response.output_text.done",
+				OutputIndex: block.OutputIndex,
+				ItemID:      item.ID,
+				Text:        text,
+			}),
+			state.event(ResponsesStreamEvent{
+				Type:        "response.content_part.done",
+				OutputIndex: block.OutputIndex,
+				ItemID:      item.ID,
+				Part:        &part,
+			}),
+		)
+	case "reasoning":
+		part := ResponsesContentPart{Type: "summary_text", Text: text}
+		item.Summary = []ResponsesSummary{{Type: "summary_text", Text: text}}
+		item.EncryptedContent = encodeResponsesReasoning(text, block.Signature)
+		events = append(events,
+			state.event(ResponsesStreamEvent{
+				Type:        "response.reasoning_summary_text.done",
+				OutputIndex: block.OutputIndex,
+				ItemID:      item.ID,
+				Text:        text,
+			}),
+			state.event(ResponsesStreamEvent{
+				Type:        "response.reasoning_summary_part.done",
+				OutputIndex: block.OutputIndex,
+				ItemID:      item.ID,
+				Part:        &part,
+			}),
+		)
+	case "function_call":
+		if text == "" {
+			text = "{}"
+		}
+		item.Status = "completed"
+		item.Arguments = text
+		events = append(events, state.event(ResponsesStreamEvent{
+			Type:        "response.function_call_arguments.done",
+			OutputIndex: block.OutputIndex,
+			ItemID:      item.ID,
+			Arguments:   text,
+		}))
+	}
+
+	state.Output = append(state.Output, item)
+	done := item
+	return append(events, state.event(ResponsesStreamEvent{
+		Type:        "response.output_item.done",
+		OutputIndex: block.OutputIndex,
+		Item:        &done,
+	}))
+}
+
+func (state *AnthropicEventToResponsesState) snapshot(status string) *ResponsesResponse {
+	output := make([]ResponsesOutput, len(state.Output))
+	copy(output, state.Output)
+	return &ResponsesResponse{
+		ID:        state.ResponseID,
+		Object:    "response",
+		CreatedAt: state.CreatedAt,
+		Model:     state.Model,
+		Status:    status,
+		Output:    output,
+	}
+}
+
+func (state *AnthropicEventToResponsesState) event(evt ResponsesStreamEvent) ResponsesStreamEvent {
+	evt.SequenceNumber = state.SequenceNumber
+	state.SequenceNumber++
+	return evt
+}
@@ -0,0 +1,342 @@
+package apicompat
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ---------------------------------------------------------------------------
+// Non-streaming: AnthropicResponse → ChatCompletionsResponse
+// ---------------------------------------------------------------------------
+
+// AnthropicToChatCompletions converts an Anthropic Messages response into a
+// Chat Completions response. Thinking blocks are surfaced as
+// reasoning_content; tool_use blocks become tool_calls.
+func AnthropicToChatCompletions(resp *AnthropicResponse, model string) *ChatCompletionsResponse {
+	if model == "" {
+		model = resp.Model
+	}
+
+	var texts, reasoning []string
+	var toolCalls []ChatToolCall
+	for _, b := range resp.Content {
+		switch b.Type {
+		case "text":
+			if b.Text != "" {
+				texts = append(texts, b.Text)
+			}
+		case "thinking":
+			if b.Thinking != "" {
+				reasoning = append(reasoning, b.Thinking)
+			}
+		case "tool_use":
+			args := "{}"
+			if len(b.Input) > 0 {
+				args = string(b.Input)
+			}
+			toolCalls = append(toolCalls, ChatToolCall{
+				ID:       b.ID,
+				Type:     "function",
+				Function: ChatFunctionCall{Name: b.Name, Arguments: args},
+			})
+		}
+	}
+
+	msg := ChatMessage{
+		Role:             "assistant",
+		ToolCalls:        toolCalls,
+		ReasoningContent: strings.Join(reasoning, ""),
+	}
+	if len(texts) > 0 || len(toolCalls) == 0 {
+		msg.Content, _ = json.Marshal(strings.Join(texts, ""))
+	} else {
+		msg.Content = json.RawMessage("null")
+	}
+
+	usage := anthropicUsageToChat(resp.Usage.InputTokens, resp.Usage.OutputTokens,
+		resp.Usage.CacheReadInputTokens, resp.Usage.CacheCreationInputTokens)
+
+	return &ChatCompletionsResponse{
+		ID:      toChatCompletionID(resp.ID),
+		Object:  "chat.completion",
+		Created: time.Now().Unix(),
+		Model:   model,
+		Choices: []ChatChoice{{
+			Index:        0,
+			Message:      msg,
+			FinishReason: anthropicStopReasonToChat(resp.StopReason),
+		}},
+		Usage: &usage,
+	}
+}
+
+// anthropicStopReasonToChat maps an Anthropic stop_reason to a Chat
+// Completions finish_reason.
+func anthropicStopReasonToChat(reason string) string {
+	switch reason {
+	case "max_tokens", "model_context_window_exceeded":
+		return "length"
+	case "tool_use":
+		return "tool_calls"
+	case "refusal":
+		return "content_filter"
+	default:
+		return "stop"
+	}
+}
+
+// anthropicUsageToChat builds Chat Completions usage. OpenAI counts cached
+// prompt tokens inside prompt_tokens, whereas Anthropic reports them
+// separately, so they are folded back in.
+func anthropicUsageToChat(input, output, cacheRead, cacheCreation int) ChatUsage {
+	prompt := input + cacheRead + cacheCreation
+	usage := ChatUsage{
+		PromptTokens:     prompt,
+		CompletionTokens: output,
+		TotalTokens:      prompt + output,
+	}
+	if cacheRead > 0 {
+		usage.PromptTokensDetails = &ChatPromptTokensDetails{CachedTokens: cacheRead}
+	}
+	return usage
+}
+
+// toChatCompletionID gives an Anthropic message ID the "chatcmpl-" prefix
+// OpenAI SDKs expect.
+func toChatCompletionID(id string) string {
+	if id == "" {
+		return fmt.Sprintf("chatcmpl-%d", time.Now().UnixNano())
+	}
+	if strings.HasPrefix(id, "chatcmpl-") {
+		return id
+	}
+	return "chatcmpl-" + id
+}
+
+// ---------------------------------------------------------------------------
+// Streaming: AnthropicStreamEvent → []ChatCompletionsChunk (stateful converter)
+// ---------------------------------------------------------------------------
+
+// AnthropicEventToChatState tracks state for converting a sequence of
+// Anthropic SSE events into Chat Completions chunks.
+type AnthropicEventToChatState struct {
+	RoleSent bool
+	Finished bool
+
+	// IncludeUsage mirrors stream_options.include_usage: when set, a final
+	// chunk with empty choices and the usage object is emitted.
+	IncludeUsage bool
+
+	// BlockToToolIndex maps Anthropic content block index → tool_calls index.
+	BlockToToolIndex map[int]int
+	NextToolIndex    int
+
+	FinishReason string
+
+	InputTokens              int
+	OutputTokens             int
+	CacheReadInputTokens     int
+	CacheCreationInputTokens int
+
+	ID      string
+	Model   string
+	Created int64
+}
+
+// NewAnthropicEventToChatState returns an initialised stream state.
+func NewAnthropicEventToChatState() *AnthropicEventToChatState {
+	return &AnthropicEventToChatState{
+		BlockToToolIndex: make(map[int]int),
+		Created:          time.Now().Unix(),
+	}
+}
+
+// AnthropicEventToChatChunks converts a single Anthropic SSE event into zero
+// or more Chat Completions chunks, updating state as it goes.
+func AnthropicEventToChatChunks(evt *AnthropicStreamEvent, state *AnthropicEventToChatState) []ChatCompletionsChunk {
+	switch evt.Type {
+	case "message_start":
+		return anthToChatHandleMessageStart(evt, state)
+	case "content_block_start":
+		return anthToChatHandleBlockStart(evt, state)
+	case "content_block_delta":
+		return anthToChatHandleBlockDelta(evt, state)
+	case "message_delta":
+		return anthToChatHandleMessageDelta(evt, state)
+	case "message_stop":
+		return anthToChatHandleMessageStop(state)
+	default:
+		return nil
+	}
+}
+
+// FinalizeAnthropicChatStream emits a synthetic finish chunk (and usage chunk
+// when requested) if the stream ended without message_stop.
+func FinalizeAnthropicChatStream(state *AnthropicEventToChatState) []ChatCompletionsChunk {
+	if !state.RoleSent || state.Finished {
+		return nil
+	}
+	return anthToChatHandleMessageStop(state)
+}
+
+// ChatCompletionsChunkToSSE formats a ChatCompletionsChunk as an SSE data line.
+func ChatCompletionsChunkToSSE(chunk ChatCompletionsChunk) (string, error) {
+	data, err := json.Marshal(chunk)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("data: %s\n\n", data), nil
+}
+
+// --- internal handlers ---
+
+func anthToChatHandleMessageStart(evt *AnthropicStreamEvent, state *AnthropicEventToChatState) []ChatCompletionsChunk {
+	if evt.Message != nil {
+		state.ID = toChatCompletionID(evt.Message.ID)
+		if state.Model == "" {
+			state.Model = evt.Message.Model
+		}
+		state.InputTokens = evt.Message.Usage.InputTokens
+		state.CacheReadInputTokens = evt.Message.Usage.CacheReadInputTokens
+		state.CacheCreationInputTokens = evt.Message.Usage.CacheCreationInputTokens
+		state.OutputTokens = evt.Message.Usage.OutputTokens
+	}
+	return anthToChatEnsureRole(state)
+}
+
+func anthToChatEnsureRole(state *AnthropicEventToChatState) []ChatCompletionsChunk {
+	if state.RoleSent {
+		return nil
+	}
+	state.RoleSent = true
+	if state.ID == "" {
+		state.ID = toChatCompletionID("")
+	}
+	empty := ""
+	return []ChatCompletionsChunk{state.chunk(ChatDelta{Role: "assistant", Content: &empty}, nil)}
+}
+
+func anthToChatHandleBlockStart(evt *AnthropicStreamEvent, state *AnthropicEventToChatState) []ChatCompletionsChunk {
+	if evt.ContentBlock == nil || evt.Index == nil || evt.ContentBlock.Type != "tool_use" {
+		return nil
+	}
+	chunks := anthToChatEnsureRole(state)
+
+	toolIdx := state.NextToolIndex
+	state.NextToolIndex++
+	state.BlockToToolIndex[*evt.Index] = toolIdx
+
+	chunks = append(chunks, state.chunk(ChatDelta{
+		ToolCalls: []ChatToolCall{{
+			Index:    &toolIdx,
+			ID:       evt.ContentBlock.ID,
+			Type:     "function",
+			Function: ChatFunctionCall{Name: evt.ContentBlock.Name, Arguments: ""},
+		}},
+	}, nil))
+	return chunks
+}
+
+func anthToChatHandleBlockDelta(evt *AnthropicStreamEvent, state *AnthropicEventToChatState) []ChatCompletionsChunk {
+	if evt.Delta == nil {
+		return nil
+	}
+	chunks := anthToChatEnsureRole(state)
+
+	switch evt.Delta.Type {
+	case "text_delta":
+		if evt.Delta.Text == "" {
+			return chunks
+		}
+		text := evt.Delta.Text
+		chunks = append(chunks, state.chunk(ChatDelta{Content: &text}, nil))
+	case "thinking_delta":
+		if evt.Delta.Thinking == "" {
+			return chunks
+		}
+		thinking := evt.Delta.Thinking
+		chunks = append(chunks, state.chunk(ChatDelta{ReasoningContent: &thinking}, nil))
+	case "input_json_delta":
+		if evt.Delta.PartialJSON == "" || evt.Index == nil {
+			return chunks
+		}
+		toolIdx, ok := state.BlockToToolIndex[*evt.Index]
+		if !ok {
+			return chunks
+		}
+		chunks = append(chunks, state.chunk(ChatDelta{
+			ToolCalls: []ChatToolCall{{
+				Index:    &toolIdx,
+				Function: ChatFunctionCall{Arguments: evt.Delta.PartialJSON},
+			}},
+		}, nil))
+	}
+	return chunks
+}
+
+func anthToChatHandleMessageDelta(evt *AnthropicStreamEvent, state *AnthropicEventToChatState) []ChatCompletionsChunk {
+	if evt.Delta != nil && evt.Delta.StopReason != "" {
+		state.FinishReason = anthropicStopReasonToChat(evt.Delta.StopReason)
+	}
+	if evt.Usage != nil {
+		// message_delta usage is cumulative; only override non-zero values
+		// so a sparse delta does not erase counts from message_start.
+		if evt.Usage.InputTokens > 0 {
+			state.InputTokens = evt.Usage.InputTokens
+		}
+		if evt.Usage.OutputTokens > 0 {
+			state.OutputTokens = evt.Usage.OutputTokens
+		}
+		if evt.Usage.CacheReadInputTokens > 0 {
+			state.CacheReadInputTokens = evt.Usage.CacheReadInputTokens
+		}
+		if evt.Usage.CacheCreationInputTokens > 0 {
+			state.CacheCreationInputTokens = evt.Usage.CacheCreationInputTokens
+		}
+	}
+	return nil
+}
+
+func anthToChatHandleMessageStop(state *AnthropicEventToChatState) []ChatCompletionsChunk {
+	if state.Finished {
+		return nil
+	}
+	chunks := anthToChatEnsureRole(state)
+	state.Finished = true
+
+	reason := state.FinishReason
+	if reason == "" {
+		reason = "stop"
+	}
+	chunks = append(chunks, state.chunk(ChatDelta{}, &reason))
+
+	if state.IncludeUsage {
+		usage := anthropicUsageToChat(state.InputTokens, state.OutputTokens,
+			state.CacheReadInputTokens, state.CacheCreationInputTokens)
+		chunks = append(chunks, ChatCompletionsChunk{
+			ID:      state.ID,
+			Object:  "chat.completion.chunk",
+			Created: state.Created,
+			Model:   state.Model,
+			Choices: []ChatChunkChoice{},
+			Usage:   &usage,
+		})
+	}
+	return chunks
+}
+
+func (state *AnthropicEventToChatState) chunk(delta ChatDelta, finishReason *string) ChatCompletionsChunk {
+	return ChatCompletionsChunk{
+		ID:      state.ID,
+		Object:  "chat.completion.chunk",
+		Created: state.Created,
+		Model:   state.Model,
+		Choices: []ChatChunkChoice{{
+			Index:        0,
+			Delta:        delta,
+			FinishReason: finishReason,
+		}},
+	}
+}
@@ -0,0 +1,365 @@
+package apicompat
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// ChatCompletionsToAnthropic converts an OpenAI Chat Completions request into
+// an Anthropic Messages request. System/developer messages are hoisted into
+// the system prompt, tool messages become tool_result blocks, and consecutive
+// messages with the same role are merged to satisfy Anthropic's alternation
+// rule.
+func ChatCompletionsToAnthropic(req *ChatCompletionsRequest) (*AnthropicRequest, error) {
+	system, msgs, err := convertChatMessagesToAnthropic(req.Messages)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &AnthropicRequest{
+		Model:       req.Model,
+		Messages:    msgs,
+		Stream:      req.Stream,
+		Temperature: req.Temperature,
+		TopP:        req.TopP,
+	}
+
+	if system != "" {
+		sysJSON, err := json.Marshal(system)
+		if err != nil {
+			return nil, err
+		}
+		out.System = sysJSON
+	}
+
+	switch {
+	case req.MaxCompletionTokens != nil && *req.MaxCompletionTokens > 0:
+		out.MaxTokens = *req.MaxCompletionTokens
+	case req.MaxTokens != nil && *req.MaxTokens > 0:
+		out.MaxTokens = *req.MaxTokens
+	default:
+		out.MaxTokens = defaultAnthropicMaxTokens
+	}
+
+	if len(req.Stop) > 0 {
+		stops, err := parseChatStop(req.Stop)
+		if err != nil {
+			return nil, fmt.Errorf("parse stop: %w", err)
+		}
+		out.StopSeqs = stops
+	}
+
+	if len(req.Tools) > 0 {
+		out.Tools = convertChatToolsToAnthropic(req.Tools)
+	}
+
+	if len(req.ToolChoice) > 0 || req.ParallelToolCalls != nil {
+		tc, err := convertChatToolChoiceToAnthropic(req.ToolChoice, req.ParallelToolCalls, len(out.Tools) > 0)
+		if err != nil {
+			return nil, fmt.Errorf("convert tool_choice: %w", err)
+		}
+		out.ToolChoice = tc
+	}
+
+	// Convert reasoning_effort → thinking. Anthropic requires budget_tokens
+	// to be below max_tokens, so the client's max_tokens is treated as the
+	// visible-output allowance and the budget is added on top. Sampling
+	// parameters are not allowed together with extended thinking.
+	if budget := reasoningEffortToThinkingBudget(req.ReasoningEffort); budget > 0 {
+		out.Thinking = &AnthropicThinking{Type: "enabled", BudgetTokens: budget}
+		if out.MaxTokens <= budget {
+			out.MaxTokens += budget
+		}
+		out.Temperature = nil
+		out.TopP = nil
+	}
+
+	return out, nil
+}
+
+// reasoningEffortToThinkingBudget maps an OpenAI reasoning effort level to an
+// Anthropic thinking budget. Unknown or empty values disable thinking.
+func reasoningEffortToThinkingBudget(effort string) int {
+	switch strings.ToLower(strings.TrimSpace(effort)) {
+	case "minimal", "low":
+		return 1024
+	case "medium":
+		return 4096
+	case "high":
+		return 16384
+	default:
+		return 0
+	}
+}
+
+// parseChatStop handles the Chat Completions stop field which can be a
+// string or an array of strings.
+func parseChatStop(raw json.RawMessage) ([]string, error) {
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		if s == "" {
+			return nil, nil
+		}
+		return []string{s}, nil
+	}
+	var arr []string
+	if err := json.Unmarshal(raw, &arr); err != nil {
+		return nil, err
+	}
+	return arr, nil
+}
+
+// convertChatToolsToAnthropic maps Chat Completions function tools to
+// Anthropic tool definitions (parameters → input_schema). Non-function tools
+// are dropped since Anthropic has no equivalent.
+func convertChatToolsToAnthropic(tools []ChatTool) []AnthropicTool {
+	out := make([]AnthropicTool, 0, len(tools))
+	for _, t := range tools {
+		if t.Type != "function" || t.Function == nil || t.Function.Name == "" {
+			continue
+		}
+		schema := t.Function.Parameters
+		if len(schema) == 0 || string(schema) == "null" {
+			schema = json.RawMessage(`{"type":"object","properties":{}}`)
+		}
+		out = append(out, AnthropicTool{
+			Name:        t.Function.Name,
+			Description: t.Function.Description,
+			InputSchema: schema,
+		})
+	}
+	return out
+}
+
+// convertChatToolChoiceToAnthropic maps Chat Completions tool_choice to
+// Anthropic format.
+//
+//	"auto"                                    → {"type":"auto"}
+//	"required"                                → {"type":"any"}
+//	"none"                                    → {"type":"none"}
+//	{"type":"function","function":{"name":X}} → {"type":"tool","name":X}
+//
+// parallel_tool_calls=false is expressed as disable_parallel_tool_use.
+func convertChatToolChoiceToAnthropic(raw json.RawMessage, parallel *bool, hasTools bool) (json.RawMessage, error) {
+	if !hasTools {
+		return nil, nil
+	}
+
+	choice := map[string]any{"type": "auto"}
+	if len(raw) > 0 && string(raw) != "null" {
+		var s string
+		if err := json.Unmarshal(raw, &s); err == nil {
+			switch s {
+			case "required":
+				choice["type"] = "any"
+			case "none":
+				choice["type"] = "none"
+			default:
+				choice["type"] = "auto"
+			}
+		} else {
+			var obj struct {
+				Type     string `json:"type"`
+				Function struct {
+					Name string `json:"name"`
+				} `json:"function"`
+			}
+			if err := json.Unmarshal(raw, &obj); err != nil {
+				return nil, err
+			}
+			if obj.Function.Name != "" {
+				choice["type"] = "tool"
+				choice["name"] = obj.Function.Name
+			}
+		}
+	}
+
+	if parallel != nil && !*parallel && choice["type"] != "none" {
+		choice["disable_parallel_tool_use"] = true
+	}
+	return json.Marshal(choice)
+}
+
+// convertChatMessagesToAnthropic splits Chat Completions messages into the
+// Anthropic system prompt and a role-alternating message list.
+func convertChatMessagesToAnthropic(msgs []ChatMessage) (string, []AnthropicMessage, error) {
+	var systemParts []string
+	var turns anthropicTurns
+
+	for _, m := range msgs {
+		switch m.Role {
+		case "system", "developer":
+			text, err := extractChatText(m.Content)
+			if err != nil {
+				return "", nil, err
+			}
+			if text != "" {
+				systemParts = append(systemParts, text)
+			}
+		case "assistant":
+			blocks, err := chatAssistantToAnthropicBlocks(m)
+			if err != nil {
+				return "", nil, err
+			}
+			turns.append("assistant", blocks)
+		case "tool", "function":
+			text, err := extractChatText(m.Content)
+			if err != nil {
+				return "", nil, err
+			}
+			content, _ := json.Marshal(text)
+			turns.append("user", []AnthropicContentBlock{{
+				Type:      "tool_result",
+				ToolUseID: m.ToolCallID,
+				Content:   content,
+			}})
+		default:
+			blocks, err := chatUserToAnthropicBlocks(m.Content)
+			if err != nil {
+				return "", nil, err
+			}
+			turns.append("user", blocks)
+		}
+	}
+
+	out, err := turns.messages()
+	if err != nil {
+		return "", nil, err
+	}
+	return strings.Join(systemParts, "\n\n"), out, nil
+}
+
+// anthropicTurns accumulates content blocks per role, merging consecutive
+// blocks with the same role into a single message.
+type anthropicTurns []anthropicTurn
+
+type anthropicTurn struct {
+	role   string
+	blocks []AnthropicContentBlock
+}
+
+func (t *anthropicTurns) append(role string, blocks []AnthropicContentBlock) {
+	if len(blocks) == 0 {
+		return
+	}
+	if n := len(*t); n > 0 && (*t)[n-1].role == role {
+		(*t)[n-1].blocks = append((*t)[n-1].blocks, blocks...)
+		return
+	}
+	*t = append(*t, anthropicTurn{role: role, blocks: blocks})
+}
+
+func (t anthropicTurns) messages() ([]AnthropicMessage, error) {
+	out := make([]AnthropicMessage, 0, len(t))
+	for _, turn := range t {
+		content, err := json.Marshal(turn.blocks)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, AnthropicMessage{Role: turn.role, Content: content})
+	}
+	return out, nil
+}
+
+// chatUserToAnthropicBlocks converts user content (string or parts) into
+// Anthropic text/image blocks.
+func chatUserToAnthropicBlocks(raw json.RawMessage) ([]AnthropicContentBlock, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, nil
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		if s == "" {
+			return nil, nil
+		}
+		return []AnthropicContentBlock{{Type: "text", Text: s}}, nil
+	}
+
+	var parts []ChatContentPart
+	if err := json.Unmarshal(raw, &parts); err != nil {
+		return nil, err
+	}
+	var blocks []AnthropicContentBlock
+	for _, p := range parts {
+		switch p.Type {
+		case "text", "input_text":
+			if p.Text != "" {
+				blocks = append(blocks, AnthropicContentBlock{Type: "text", Text: p.Text})
+			}
+		case "image_url":
+			if p.ImageURL == nil || p.ImageURL.URL == "" {
+				continue
+			}
+			blocks = append(blocks, AnthropicContentBlock{
+				Type:   "image",
+				Source: chatImageURLToAnthropicSource(p.ImageURL.URL),
+			})
+		}
+	}
+	return blocks, nil
+}
+
+// chatImageURLToAnthropicSource turns a data URI into a base64 source and
+// anything else into a url source.
+func chatImageURLToAnthropicSource(url string) *AnthropicImageSource {
+	if rest, ok := strings.CutPrefix(url, "data:"); ok {
+		if meta, data, found := strings.Cut(rest, ","); found {
+			mediaType, isBase64 := strings.CutSuffix(meta, ";base64")
+			if isBase64 {
+				return &AnthropicImageSource{Type: "base64", MediaType: mediaType, Data: data}
+			}
+		}
+	}
+	return &AnthropicImageSource{Type: "url", URL: url}
+}
+
+// chatAssistantToAnthropicBlocks converts an assistant message. Text content
+// becomes a text block and tool_calls become tool_use blocks. Reasoning
+// content is dropped because Anthropic only accepts signed thinking blocks.
+func chatAssistantToAnthropicBlocks(m ChatMessage) ([]AnthropicContentBlock, error) {
+	var blocks []AnthropicContentBlock
+	text, err := extractChatText(m.Content)
+	if err != nil {
+		return nil, err
+	}
+	if text != "" {
+		blocks = append(blocks, AnthropicContentBlock{Type: "text", Text: text})
+	}
+	for _, tc := range m.ToolCalls {
+		input := json.RawMessage(tc.Function.Arguments)
+		if !json.Valid(input) {
+			input = json.RawMessage("{}")
+		}
+		blocks = append(blocks, AnthropicContentBlock{
+			Type:  "tool_use",
+			ID:    tc.ID,
+			Name:  tc.Function.Name,
+			Input: input,
+		})
+	}
+	return blocks, nil
+}
+
+// extractChatText returns the concatenated text of a Chat Completions
+// content field (string, null or parts array).
+func extractChatText(raw json.RawMessage) (string, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return "", nil
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s, nil
+	}
+	var parts []ChatContentPart
+	if err := json.Unmarshal(raw, &parts); err != nil {
+		return "", err
+	}
+	var texts []string
+	for _, p := range parts {
+		if (p.Type == "text" || p.Type == "input_text" || p.Type == "output_text") && p.Text != "" {
+			texts = append(texts, p.Text)
+		}
+	}
+	return strings.Join(texts, "\n\n"), nil
+}
@@ -0,0 +1,324 @@
+package apicompat
+
+import (
+	"encoding/json"
+	"fmt"
+	"mime"
+	"path"
+	"strings"
+
+	"github.com/Wei-Shaw/sub2api/internal/pkg/antigravity"
+)
+
+// ChatCompletionsToGemini converts an OpenAI Chat Completions request into a
+// Gemini generateContent request. System/developer messages become the
+// systemInstruction, assistant tool_calls become functionCall parts and tool
+// messages become functionResponse parts; response_format is mapped to
+// Gemini's JSON mode (responseMimeType / responseSchema).
+//
+// Gemini pairs function responses with calls by name, so the name of each
+// tool_call_id is remembered from the preceding assistant messages.
+func ChatCompletionsToGemini(req *ChatCompletionsRequest) (*antigravity.GeminiRequest, error) {
+	system, contents, err := convertChatMessagesToGemini(req.Messages)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &antigravity.GeminiRequest{Contents: contents}
+	if system != "" {
+		out.SystemInstruction = &antigravity.GeminiContent{
+			Role:  "user",
+			Parts: []antigravity.GeminiPart{{Text: system}},
+		}
+	}
+
+	cfg := &antigravity.GeminiGenerationConfig{
+		Temperature: req.Temperature,
+		TopP:        req.TopP,
+	}
+	switch {
+	case req.MaxCompletionTokens != nil && *req.MaxCompletionTokens > 0:
+		cfg.MaxOutputTokens = *req.MaxCompletionTokens
+	case req.MaxTokens != nil && *req.MaxTokens > 0:
+		cfg.MaxOutputTokens = *req.MaxTokens
+	}
+	if len(req.Stop) > 0 {
+		stops, err := parseChatStop(req.Stop)
+		if err != nil {
+			return nil, fmt.Errorf("parse stop: %w", err)
+		}
+		cfg.StopSequences = stops
+	}
+	if budget := reasoningEffortToThinkingBudget(req.ReasoningEffort); budget > 0 {
+		cfg.ThinkingConfig = &antigravity.GeminiThinkingConfig{
+			IncludeThoughts: true,
+			ThinkingBudget:  budget,
+		}
+	}
+	if err := applyChatResponseFormatToGemini(req.ResponseFormat, cfg); err != nil {
+		return nil, fmt.Errorf("convert response_format: %w", err)
+	}
+	out.GenerationConfig = cfg
+
+	if len(req.Tools) > 0 {
+		decls, err := convertChatToolsToGemini(req.Tools)
+		if err != nil {
+			return nil, err
+		}
+		if len(decls) > 0 {
+			out.Tools = []antigravity.GeminiToolDeclaration{{FunctionDeclarations: decls}}
+			toolConfig, err := convertChatToolChoiceToGemini(req.ToolChoice)
+			if err != nil {
+				return nil, fmt.Errorf("convert tool_choice: %w", err)
+			}
+			out.ToolConfig = toolConfig
+		}
+	}
+
+	return out, nil
+}
+
+// applyChatResponseFormatToGemini maps response_format onto the generation
+// config. json_object only switches the MIME type; json_schema also passes
+// the schema, cleaned of keywords Gemini rejects.
+func applyChatResponseFormatToGemini(format *ChatResponseFormat, cfg *antigravity.GeminiGenerationConfig) error {
+	if format == nil {
+		return nil
+	}
+	switch format.Type {
+	case "json_object":
+		cfg.ResponseMimeType = "application/json"
+	case "json_schema":
+		cfg.ResponseMimeType = "application/json"
+		if format.JSONSchema == nil || len(format.JSONSchema.Schema) == 0 || string(format.JSONSchema.Schema) == "null" {
+			return nil
+		}
+		var schema map[string]any
+		if err := json.Unmarshal(format.JSONSchema.Schema, &schema); err != nil {
+			return err
+		}
+		cfg.ResponseSchema = antigravity.CleanJSONSchema(schema)
+	}
+	return nil
+}
+
+// convertChatToolsToGemini maps Chat Completions function tools to Gemini
+// function declarations. Non-function tools are dropped.
+func convertChatToolsToGemini(tools []ChatTool) ([]antigravity.GeminiFunctionDecl, error) {
+	out := make([]antigravity.GeminiFunctionDecl, 0, len(tools))
+	for _, t := range tools {
+		if t.Type != "function" || t.Function == nil || t.Function.Name == "" {
+			continue
+		}
+		decl := antigravity.GeminiFunctionDecl{
+			Name:        t.Function.Name,
+			Description: t.Function.Description,
+		}
+		if len(t.Function.Parameters) > 0 && string(t.Function.Parameters) != "null" {
+			var schema map[string]any
+			if err := json.Unmarshal(t.Function.Parameters, &schema); err != nil {
+				return nil, fmt.Errorf("parse parameters of tool %q: %w", t.Function.Name, err)
+			}
+			decl.Parameters = antigravity.CleanJSONSchema(schema)
+		}
+		out = append(out, decl)
+	}
+	return out, nil
+}
+
+// convertChatToolChoiceToGemini maps Chat Completions tool_choice to a
+// Gemini function calling mode.
+//
+//	"auto"                                    → AUTO
+//	"required"                                → ANY
+//	"none"                                    → NONE
+//	{"type":"function","function":{"name":X}} → ANY, allowedFunctionNames=[X]
+func convertChatToolChoiceToGemini(raw json.RawMessage) (*antigravity.GeminiToolConfig, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, nil
+	}
+	cfg := &antigravity.GeminiFunctionCallingConfig{}
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		switch s {
+		case "required":
+			cfg.Mode = "ANY"
+		case "none":
+			cfg.Mode = "NONE"
+		default:
+			cfg.Mode = "AUTO"
+		}
+	} else {
+		var obj struct {
+			Function struct {
+				Name string `json:"name"`
+			} `json:"function"`
+		}
+		if err := json.Unmarshal(raw, &obj); err != nil {
+			return nil, err
+		}
+		if obj.Function.Name == "" {
+			return nil, nil
+		}
+		cfg.Mode = "ANY"
+		cfg.AllowedFunctionNames = []string{obj.Function.Name}
+	}
+	return &antigravity.GeminiToolConfig{FunctionCallingConfig: cfg}, nil
+}
+
+// convertChatMessagesToGemini splits Chat Completions messages into the
+// Gemini system instruction and a role-alternating contents list.
+func convertChatMessagesToGemini(msgs []ChatMessage) (string, []antigravity.GeminiContent, error) {
+	var systemParts []string
+	var turns geminiTurns
+	toolNames := make(map[string]string)
+
+	for _, m := range msgs {
+		switch m.Role {
+		case "system", "developer":
+			text, err := extractChatText(m.Content)
+			if err != nil {
+				return "", nil, err
+			}
+			if text != "" {
+				systemParts = append(systemParts, text)
+			}
+		case "assistant":
+			parts, err := chatAssistantToGeminiParts(m, toolNames)
+			if err != nil {
+				return "", nil, err
+			}
+			turns.append("model", parts)
+		case "tool", "function":
+			text, err := extractChatText(m.Content)
+			if err != nil {
+				return "", nil, err
+			}
+			name := toolNames[m.ToolCallID]
+			if name == "" {
+				name = m.Name
+			}
+			if name == "" {
+				name = m.ToolCallID
+			}
+			turns.append("user", []antigravity.GeminiPart{{
+				FunctionResponse: &antigravity.GeminiFunctionResponse{
+					Name:     name,
+					Response: map[string]any{"result": text},
+					ID:       m.ToolCallID,
+				},
+			}})
+		default:
+			parts, err := chatUserToGeminiParts(m.Content)
+			if err != nil {
+				return "", nil, err
+			}
+			turns.append("user", parts)
+		}
+	}
+
+	return strings.Join(systemParts, "\n\n"), []antigravity.GeminiContent(turns), nil
+}
+
+// geminiTurns accumulates parts per role, merging consecutive parts with the
+// same role into a single content (Gemini expects all function responses of
+// a turn in one content).
+type geminiTurns []antigravity.GeminiContent
+
+func (t *geminiTurns) append(role string, parts []antigravity.GeminiPart) {
+	if len(parts) == 0 {
+		return
+	}
+	if n := len(*t); n > 0 && (*t)[n-1].Role == role {
+		(*t)[n-1].Parts = append((*t)[n-1].Parts, parts...)
+		return
+	}
+	*t = append(*t, antigravity.GeminiContent{Role: role, Parts: parts})
+}
+
+// chatUserToGeminiParts converts user content (string or parts) into Gemini
+// text and image parts.
+func chatUserToGeminiParts(raw json.RawMessage) ([]antigravity.GeminiPart, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, nil
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		if s == "" {
+			return nil, nil
+		}
+		return []antigravity.GeminiPart{{Text: s}}, nil
+	}
+
+	var parts []ChatContentPart
+	if err := json.Unmarshal(raw, &parts); err != nil {
+		return nil, err
+	}
+	var out []antigravity.GeminiPart
+	for _, p := range parts {
+		switch p.Type {
+		case "text", "input_text":
+			if p.Text != "" {
+				out = append(out, antigravity.GeminiPart{Text: p.Text})
+			}
+		case "image_url":
+			if p.ImageURL == nil || p.ImageURL.URL == "" {
+				continue
+			}
+			out = append(out, chatImageURLToGeminiPart(p.ImageURL.URL))
+		}
+	}
+	return out, nil
+}
+
+// chatImageURLToGeminiPart turns a base64 data URI into inlineData and
+// anything else into a fileData reference.
+func chatImageURLToGeminiPart(url string) antigravity.GeminiPart {
+	if src := chatImageURLToAnthropicSource(url); src.Type == "base64" {
+		return antigravity.GeminiPart{InlineData: &antigravity.GeminiInlineData{
+			MimeType: src.MediaType,
+			Data:     src.Data,
+		}}
+	}
+	mimeType := ""
+	if ext := path.Ext(strings.SplitN(url, "?", 2)[0]); ext != "" {
+		mimeType, _, _ = strings.Cut(mime.TypeByExtension(ext), ";")
+	}
+	return antigravity.GeminiPart{FileData: &antigravity.GeminiFileData{
+		MimeType: mimeType,
+		FileURI:  url,
+	}}
+}
+
+// chatAssistantToGeminiParts converts an assistant message. tool_calls
+// become functionCall parts carrying the dummy thought signature, since
+// OpenAI clients never return Gemini's real signatures. Reasoning content is
+// dropped for the same reason.
+func chatAssistantToGeminiParts(m ChatMessage, toolNames map[string]string) ([]antigravity.GeminiPart, error) {
+	var parts []antigravity.GeminiPart
+	text, err := extractChatText(m.Content)
+	if err != nil {
+		return nil, err
+	}
+	if text != "" {
+		parts = append(parts, antigravity.GeminiPart{Text: text})
+	}
+	for _, tc := range m.ToolCalls {
+		args := map[string]any{}
+		if tc.Function.Arguments != "" {
+			_ = json.Unmarshal([]byte(tc.Function.Arguments), &args)
+		}
+		if tc.ID != "" {
+			toolNames[tc.ID] = tc.Function.Name
+		}
+		parts = append(parts, antigravity.GeminiPart{
+			FunctionCall: &antigravity.GeminiFunctionCall{
+				Name: tc.Function.Name,
+				Args: args,
+				ID:   tc.ID,
+			},
+			ThoughtSignature: antigravity.DummyThoughtSignature,
+		})
+	}
+	return parts, nil
+}
@@ -0,0 +1,234 @@
+package apicompat
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/Wei-Shaw/sub2api/internal/pkg/antigravity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ---------------------------------------------------------------------------
+// ChatCompletionsToGemini tests
+// ---------------------------------------------------------------------------
+
+func TestChatCompletionsToGemini_MessagesAndTools(t *testing.T) {
+	maxTokens := 512
+	req := &ChatCompletionsRequest{
+		Model: "gemini-2.5-pro",
+		Messages: []ChatMessage{
+			{Role: "system", Content: json.RawMessage(`"Be brief."`)},
+			{Role: "user", Content: json.RawMessage(`[
+				{"type":"text","text":"What's in the image and the weather?"},
+				{"type":"image_url","image_url":{"url":"data:image/png;base64,AAAA"}},
+				{"type":"image_url","image_url":{"url":"https://example.com/cat.jpg"}}
+			]`)},
+			{Role: "assistant", ToolCalls: []ChatToolCall{
+				{ID: "call_1", Type: "function", Function: ChatFunctionCall{Name: "get_weather", Arguments: `{"city":"Paris"}`}},
+				{ID: "call_2", Type: "function", Function: ChatFunctionCall{Name: "get_time", Arguments: `{}`}},
+			}},
+			{Role: "tool", ToolCallID: "call_1", Content: json.RawMessage(`"sunny"`)},
+			{Role: "tool", ToolCallID: "call_2", Content: json.RawMessage(`"noon"`)},
+		},
+		MaxTokens:       &maxTokens,
+		Stop:            json.RawMessage(`"END"`),
+		ReasoningEffort: "medium",
+		Tools: []ChatTool{{Type: "function", Function: &ChatFunction{
+			Name:       "get_weather",
+			Parameters: json.RawMessage(`{"type":"object","properties":{"city":{"type":"string"}},"additionalProperties":false}`),
+		}}},
+		ToolChoice: json.RawMessage(`{"type":"function","function":{"name":"get_weather"}}`),
+	}
+
+	out, err := ChatCompletionsToGemini(req)
+	require.NoError(t, err)
+
+	require.NotNil(t, out.SystemInstruction)
+	assert.Equal(t, "Be brief.", out.SystemInstruction.Parts[0].Text)
+
+	require.Len(t, out.Contents, 3)
+	user := out.Contents[0]
+	assert.Equal(t, "user", user.Role)
+	require.Len(t, user.Parts, 3)
+	assert.Equal(t, &antigravity.GeminiInlineData{MimeType: "image/png", Data: "AAAA"}, user.Parts[1].InlineData)
+	assert.Equal(t, &antigravity.GeminiFileData{MimeType: "image/jpeg", FileURI: "https://example.com/cat.jpg"}, user.Parts[2].FileData)
+
+	model := out.Contents[1]
+	assert.Equal(t, "model", model.Role)
+	require.Len(t, model.Parts, 2)
+	assert.Equal(t, "get_weather", model.Parts[0].FunctionCall.Name)
+	assert.Equal(t, map[string]any{"city": "Paris"}, model.Parts[0].FunctionCall.Args)
+	assert.Equal(t, antigravity.DummyThoughtSignature, model.Parts[0].ThoughtSignature)
+
+	// Both tool results land in one user turn, paired by function name.
+	results := out.Contents[2]
+	assert.Equal(t, "user", results.Role)
+	require.Len(t, results.Parts, 2)
+	assert.Equal(t, "get_weather", results.Parts[0].FunctionResponse.Name)
+	assert.Equal(t, map[string]any{"result": "sunny"}, results.Parts[0].FunctionResponse.Response)
+	assert.Equal(t, "get_time", results.Parts[1].FunctionResponse.Name)
+
+	cfg := out.GenerationConfig
+	require.NotNil(t, cfg)
+	assert.Equal(t, 512, cfg.MaxOutputTokens)
+	assert.Equal(t, []string{"END"}, cfg.StopSequences)
+	require.NotNil(t, cfg.ThinkingConfig)
+	assert.True(t, cfg.ThinkingConfig.IncludeThoughts)
+	assert.Equal(t, 4096, cfg.ThinkingConfig.ThinkingBudget)
+
+	require.Len(t, out.Tools, 1)
+	require.Len(t, out.Tools[0].FunctionDeclarations, 1)
+	assert.NotContains(t, out.Tools[0].FunctionDeclarations[0].Parameters, "additionalProperties")
+	require.NotNil(t, out.ToolConfig)
+	assert.Equal(t, "ANY", out.ToolConfig.FunctionCallingConfig.Mode)
+	assert.Equal(t, []string{"get_weather"}, out.ToolConfig.FunctionCallingConfig.AllowedFunctionNames)
+}
+
+func TestChatCompletionsToGemini_JSONMode(t *testing.T) {
+	req := &ChatCompletionsRequest{
+		Model:          "gemini-2.5-flash",
+		Messages:       []ChatMessage{{Role: "user", Content: json.RawMessage(`"list colors"`)}},
+		ResponseFormat: &ChatResponseFormat{Type: "json_object"},
+	}
+	out, err := ChatCompletionsToGemini(req)
+	require.NoError(t, err)
+	assert.Equal(t, "application/json", out.GenerationConfig.ResponseMimeType)
+	assert.Nil(t, out.GenerationConfig.ResponseSchema)
+
+	req.ResponseFormat = &ChatResponseFormat{Type: "json_schema", JSONSchema: &ChatJSONSchema{
+		Name:   "colors",
+		Schema: json.RawMessage(`{"type":"object","properties":{"colors":{"type":"array","items":{"type":"string"}}},"required":["colors"]}`),
+	}}
+	out, err = ChatCompletionsToGemini(req)
+	require.NoError(t, err)
+	assert.Equal(t, "application/json", out.GenerationConfig.ResponseMimeType)
+	require.NotNil(t, out.GenerationConfig.ResponseSchema)
+	assert.Contains(t, out.GenerationConfig.ResponseSchema, "properties")
+}
+
+func TestChatCompletionsToGemini_ToolChoiceModes(t *testing.T) {
+	tools := []ChatTool{{Type: "function", Function: &ChatFunction{Name: "ls"}}}
+	for choice, mode := range map[string]string{`"auto"`: "AUTO", `"required"`: "ANY", `"none"`: "NONE"} {
+		out, err := ChatCompletionsToGemini(&ChatCompletionsRequest{
+			Model:      "gemini-2.5-pro",
+			Messages:   []ChatMessage{{Role: "user", Content: json.RawMessage(`"hi"`)}},
+			Tools:      tools,
+			ToolChoice: json.RawMessage(choice),
+		})
+		require.NoError(t, err)
+		assert.Equal(t, mode, out.ToolConfig.FunctionCallingConfig.Mode, choice)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// ResponsesRequestToChatCompletions tests
+// ---------------------------------------------------------------------------
+
+func TestResponsesRequestToChatCompletions(t *testing.T) {
+	maxOut := 256
+	req := &ResponsesRequest{
+		Model:        "gemini-2.5-pro",
+		Instructions: "Be brief.",
+		Input: json.RawMessage(`[
+			{"type":"message","role":"user","content":[
+				{"type":"input_text","text":"Weather?"},
+				{"type":"input_image","image_url":"data:image/png;base64,AAAA"}
+			]},
+			{"type":"reasoning","summary":[{"type":"summary_text","text":"thinking"}]},
+			{"type":"message","role":"assistant","content":[{"type":"output_text","text":"Checking."}]},
+			{"type":"function_call","call_id":"call_1","name":"get_weather","arguments":"{}"},
+			{"type":"function_call_output","call_id":"call_1","output":"sunny"}
+		]`),
+		MaxOutputTokens: &maxOut,
+		Reasoning:       &ResponsesReasoning{Effort: "high"},
+		Tools:           []ResponsesTool{{Type: "function", Name: "get_weather"}, {Type: "web_search"}},
+		ToolChoice:      json.RawMessage(`{"type":"function","name":"get_weather"}`),
+		Text: &ResponsesText{Format: &ResponsesTextFormat{
+			Type:   "json_schema",
+			Name:   "weather",
+			Schema: json.RawMessage(`{"type":"object"}`),
+		}},
+	}
+
+	out, err := ResponsesRequestToChatCompletions(req)
+	require.NoError(t, err)
+	assert.Equal(t, &maxOut, out.MaxCompletionTokens)
+	assert.Equal(t, "high", out.ReasoningEffort)
+
+	require.Len(t, out.Messages, 4)
+	assert.Equal(t, "system", out.Messages[0].Role)
+	assert.Equal(t, "user", out.Messages[1].Role)
+	assert.JSONEq(t, `[{"type":"text","text":"Weather?"},{"type":"image_url","image_url":{"url":"data:image/png;base64,AAAA"}}]`, string(out.Messages[1].Content))
+	assert.Equal(t, "assistant", out.Messages[2].Role)
+	require.Len(t, out.Messages[2].ToolCalls, 1)
+	assert.Equal(t, "call_1", out.Messages[2].ToolCalls[0].ID)
+	assert.Equal(t, "tool", out.Messages[3].Role)
+	assert.Equal(t, "call_1", out.Messages[3].ToolCallID)
+
+	require.Len(t, out.Tools, 1)
+	assert.JSONEq(t, `{"type":"function","function":{"name":"get_weather"}}`, string(out.ToolChoice))
+	require.NotNil(t, out.ResponseFormat)
+	assert.Equal(t, "json_schema", out.ResponseFormat.Type)
+	assert.Equal(t, "weather", out.ResponseFormat.JSONSchema.Name)
+}
+
+// ---------------------------------------------------------------------------
+// Gemini → Anthropic tests
+// ---------------------------------------------------------------------------
+
+func TestGeminiChunkToAnthropicEvents_Stream(t *testing.T) {
+	chunks := []string{
+		`{"responseId":"r1","candidates":[{"content":{"role":"model","parts":[{"text":"plan","thought":true},{"thoughtSignature":"sig","thought":true}]}}]}`,
+		`{"candidates":[{"content":{"role":"model","parts":[{"text":"Hel"}]}}]}`,
+		`{"candidates":[{"content":{"role":"model","parts":[{"text":"lo"},{"functionCall":{"name":"ls","args":{"dir":"/"}}}]},"finishReason":"STOP"}],"usageMetadata":{"promptTokenCount":10,"cachedContentTokenCount":4,"candidatesTokenCount":3,"thoughtsTokenCount":2}}`,
+	}
+	state := NewGeminiEventToAnthropicState()
+	state.Model = "gemini-2.5-pro"
+
+	var events []AnthropicStreamEvent
+	for _, raw := range chunks {
+		var resp antigravity.GeminiResponse
+		require.NoError(t, json.Unmarshal([]byte(raw), &resp))
+		events = append(events, GeminiChunkToAnthropicEvents(&resp, state)...)
+	}
+	events = append(events, FinalizeGeminiAnthropicStream(state)...)
+
+	var types []string
+	for _, evt := range events {
+		types = append(types, evt.Type)
+	}
+	assert.Equal(t, []string{
+		"message_start",
+		"content_block_start", "content_block_delta", "content_block_delta", // thinking + signature
+		"content_block_stop", "content_block_start", "content_block_delta", "content_block_delta", // text
+		"content_block_stop", "content_block_start", "content_block_delta", "content_block_stop", // tool_use
+		"message_delta", "message_stop",
+	}, types)
+	assert.Equal(t, "msg_r1", events[0].Message.ID)
+	assert.Equal(t, "tool_use", events[12].Delta.StopReason)
+	assert.Equal(t, AnthropicUsage{InputTokens: 6, OutputTokens: 5, CacheReadInputTokens: 4}, *events[12].Usage)
+	assert.Nil(t, FinalizeGeminiAnthropicStream(state))
+
+	msg := state.Message()
+	require.Len(t, msg.Content, 3)
+	assert.Equal(t, "plan", msg.Content[0].Thinking)
+	assert.Equal(t, "sig", msg.Content[0].Signature)
+	assert.Equal(t, "Hello", msg.Content[1].Text)
+	assert.JSONEq(t, `{"dir":"/"}`, string(msg.Content[2].Input))
+}
+
+func TestGeminiToAnthropicResponse_MaxTokens(t *testing.T) {
+	var resp antigravity.GeminiResponse
+	require.NoError(t, json.Unmarshal([]byte(`{"candidates":[{"content":{"parts":[{"text":"cut"}]},"finishReason":"MAX_TOKENS"}]}`), &resp))
+
+	out := GeminiToAnthropicResponse(&resp, "gemini-2.5-flash")
+	assert.Equal(t, "message", out.Type)
+	assert.Equal(t, "gemini-2.5-flash", out.Model)
+	assert.Equal(t, "max_tokens", out.StopReason)
+	require.Len(t, out.Content, 1)
+	assert.Equal(t, "cut", out.Content[0].Text)
+
+	chat := AnthropicToChatCompletions(out, "gemini-2.5-flash")
+	assert.Equal(t, "length", chat.Choices[0].FinishReason)
+}
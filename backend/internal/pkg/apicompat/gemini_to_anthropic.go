@@ -0,0 +1,282 @@
+package apicompat
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/pkg/antigravity"
+)
+
+// Gemini generateContent output is translated into Anthropic Messages form,
+// which every OpenAI-side converter in this package already consumes. That
+// keeps a single Anthropic → Chat Completions / Responses implementation for
+// all upstreams.
+
+// GeminiToAnthropicResponse converts a non-streaming Gemini response into an
+// Anthropic Messages response.
+func GeminiToAnthropicResponse(resp *antigravity.GeminiResponse, model string) *AnthropicResponse {
+	state := NewGeminiEventToAnthropicState()
+	state.Model = model
+	// Only the accumulated blocks matter here; the events are discarded.
+	_ = GeminiChunkToAnthropicEvents(resp, state)
+	_ = FinalizeGeminiAnthropicStream(state)
+	return state.Message()
+}
+
+// ---------------------------------------------------------------------------
+// Streaming: GeminiResponse chunks → []AnthropicStreamEvent (stateful converter)
+// ---------------------------------------------------------------------------
+
+// GeminiEventToAnthropicState tracks state for converting a sequence of
+// Gemini streamGenerateContent chunks into Anthropic SSE events. The blocks
+// seen so far are also accumulated, so the same state yields the complete
+// message for non-streaming responses.
+type GeminiEventToAnthropicState struct {
+	Started  bool
+	Finished bool
+
+	MessageID string
+	Model     string
+
+	// Blocks holds the content blocks emitted so far; OpenIndex is the index
+	// of the text/thinking block still receiving deltas, or -1.
+	Blocks    []AnthropicContentBlock
+	OpenIndex int
+
+	FinishReason string
+	Usage        AnthropicUsage
+}
+
+// NewGeminiEventToAnthropicState returns an initialised stream state.
+func NewGeminiEventToAnthropicState() *GeminiEventToAnthropicState {
+	return &GeminiEventToAnthropicState{OpenIndex: -1}
+}
+
+// GeminiChunkToAnthropicEvents converts one Gemini response chunk into zero
+// or more Anthropic SSE events, updating state as it goes.
+func GeminiChunkToAnthropicEvents(resp *antigravity.GeminiResponse, state *GeminiEventToAnthropicState) []AnthropicStreamEvent {
+	if state.Finished {
+		return nil
+	}
+	var events []AnthropicStreamEvent
+	if !state.Started {
+		state.Started = true
+		if state.MessageID == "" {
+			state.MessageID = toAnthropicMessageID(resp.ResponseID)
+		}
+		if state.Model == "" {
+			state.Model = resp.ModelVersion
+		}
+		events = append(events, AnthropicStreamEvent{
+			Type: "message_start",
+			Message: &AnthropicResponse{
+				ID:      state.MessageID,
+				Type:    "message",
+				Role:    "assistant",
+				Content: []AnthropicContentBlock{},
+				Model:   state.Model,
+			},
+		})
+	}
+
+	if resp.UsageMetadata != nil {
+		state.Usage = geminiUsageToAnthropic(resp.UsageMetadata)
+	}
+	if len(resp.Candidates) == 0 {
+		return events
+	}
+
+	cand := resp.Candidates[0]
+	if cand.Content != nil {
+		for i := range cand.Content.Parts {
+			events = append(events, gemToAnthHandlePart(&cand.Content.Parts[i], state)...)
+		}
+	}
+	if cand.FinishReason != "" {
+		state.FinishReason = cand.FinishReason
+	}
+	return events
+}
+
+// FinalizeGeminiAnthropicStream closes the open block and emits message_delta
+// and message_stop. Gemini has no explicit end-of-stream event, so this is
+// called once the upstream body is exhausted.
+func FinalizeGeminiAnthropicStream(state *GeminiEventToAnthropicState) []AnthropicStreamEvent {
+	if !state.Started || state.Finished {
+		return nil
+	}
+	events := state.closeOpenBlock()
+	state.Finished = true
+	usage := state.Usage
+	events = append(events,
+		AnthropicStreamEvent{
+			Type:  "message_delta",
+			Delta: &AnthropicDelta{StopReason: state.stopReason()},
+			Usage: &usage,
+		},
+		AnthropicStreamEvent{Type: "message_stop"},
+	)
+	return events
+}
+
+// Message returns the accumulated message as a non-streaming response.
+func (state *GeminiEventToAnthropicState) Message() *AnthropicResponse {
+	content := state.Blocks
+	if content == nil {
+		content = []AnthropicContentBlock{}
+	}
+	return &AnthropicResponse{
+		ID:         state.MessageID,
+		Type:       "message",
+		Role:       "assistant",
+		Content:    content,
+		Model:      state.Model,
+		StopReason: state.stopReason(),
+		Usage:      state.Usage,
+	}
+}
+
+// geminiFinishReasonToAnthropic maps a Gemini finishReason onto an Anthropic
+// stop_reason. Safety-style stops surface as refusals.
+func geminiFinishReasonToAnthropic(reason string, toolUsed bool) string {
+	switch reason {
+	case "MAX_TOKENS":
+		return "max_tokens"
+	case "SAFETY", "RECITATION", "BLOCKLIST", "PROHIBITED_CONTENT", "SPII", "IMAGE_SAFETY":
+		return "refusal"
+	}
+	if toolUsed {
+		return "tool_use"
+	}
+	return "end_turn"
+}
+
+// geminiUsageToAnthropic converts usage metadata. Thinking tokens are billed
+// as output; cached tokens are reported separately like Anthropic does.
+func geminiUsageToAnthropic(u *antigravity.GeminiUsageMetadata) AnthropicUsage {
+	input := u.PromptTokenCount - u.CachedContentTokenCount
+	if input < 0 {
+		input = 0
+	}
+	return AnthropicUsage{
+		InputTokens:          input,
+		OutputTokens:         u.CandidatesTokenCount + u.ThoughtsTokenCount,
+		CacheReadInputTokens: u.CachedContentTokenCount,
+	}
+}
+
+// toAnthropicMessageID gives a Gemini responseId the "msg_" prefix.
+func toAnthropicMessageID(id string) string {
+	if id == "" {
+		return fmt.Sprintf("msg_%d", time.Now().UnixNano())
+	}
+	return "msg_" + id
+}
+
+// --- internal handlers ---
+
+func gemToAnthHandlePart(part *antigravity.GeminiPart, state *GeminiEventToAnthropicState) []AnthropicStreamEvent {
+	switch {
+	case part.FunctionCall != nil:
+		events := state.closeOpenBlock()
+		id := part.FunctionCall.ID
+		if id == "" {
+			id = fmt.Sprintf("toolu_%s_%d", state.MessageID, len(state.Blocks))
+		}
+		args, err := json.Marshal(part.FunctionCall.Args)
+		if err != nil || string(args) == "null" {
+			args = []byte("{}")
+		}
+		idx := len(state.Blocks)
+		state.Blocks = append(state.Blocks, AnthropicContentBlock{
+			Type:  "tool_use",
+			ID:    id,
+			Name:  part.FunctionCall.Name,
+			Input: args,
+		})
+		return append(events,
+			AnthropicStreamEvent{
+				Type:         "content_block_start",
+				Index:        &idx,
+				ContentBlock: &AnthropicContentBlock{Type: "tool_use", ID: id, Name: part.FunctionCall.Name, Input: json.RawMessage("{}")},
+			},
+			AnthropicStreamEvent{
+				Type:  "content_block_delta",
+				Index: &idx,
+				Delta: &AnthropicDelta{Type: "input_json_delta", PartialJSON: string(args)},
+			},
+			AnthropicStreamEvent{Type: "content_block_stop", Index: &idx},
+		)
+
+	case part.Thought:
+		events := state.openBlock("thinking")
+		idx := state.OpenIndex
+		block := &state.Blocks[idx]
+		if part.Text != "" {
+			block.Thinking += part.Text
+			events = append(events, AnthropicStreamEvent{
+				Type:  "content_block_delta",
+				Index: &idx,
+				Delta: &AnthropicDelta{Type: "thinking_delta", Thinking: part.Text},
+			})
+		}
+		if part.ThoughtSignature != "" {
+			block.Signature = part.ThoughtSignature
+			events = append(events, AnthropicStreamEvent{
+				Type:  "content_block_delta",
+				Index: &idx,
+				Delta: &AnthropicDelta{Type: "signature_delta", Signature: part.ThoughtSignature},
+			})
+		}
+		return events
+
+	case part.Text != "":
+		events := state.openBlock("text")
+		idx := state.OpenIndex
+		state.Blocks[idx].Text += part.Text
+		return append(events, AnthropicStreamEvent{
+			Type:  "content_block_delta",
+			Index: &idx,
+			Delta: &AnthropicDelta{Type: "text_delta", Text: part.Text},
+		})
+	}
+	return nil
+}
+
+// openBlock makes sure a block of blockType is open, closing any other one.
+func (state *GeminiEventToAnthropicState) openBlock(blockType string) []AnthropicStreamEvent {
+	if state.OpenIndex >= 0 && state.Blocks[state.OpenIndex].Type == blockType {
+		return nil
+	}
+	events := state.closeOpenBlock()
+	idx := len(state.Blocks)
+	state.Blocks = append(state.Blocks, AnthropicContentBlock{Type: blockType})
+	state.OpenIndex = idx
+	start := AnthropicContentBlock{Type: blockType}
+	return append(events, AnthropicStreamEvent{
+		Type:         "content_block_start",
+		Index:        &idx,
+		ContentBlock: &start,
+	})
+}
+
+func (state *GeminiEventToAnthropicState) closeOpenBlock() []AnthropicStreamEvent {
+	if state.OpenIndex < 0 {
+		return nil
+	}
+	idx := state.OpenIndex
+	state.OpenIndex = -1
+	return []AnthropicStreamEvent{{Type: "content_block_stop", Index: &idx}}
+}
+
+func (state *GeminiEventToAnthropicState) stopReason() string {
+	toolUsed := false
+	for _, b := range state.Blocks {
+		if b.Type == "tool_use" {
+			toolUsed = true
+			break
+		}
+	}
+	return geminiFinishReasonToAnthropic(state.FinishReason, toolUsed)
+}
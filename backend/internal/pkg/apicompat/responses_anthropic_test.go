@@ -0,0 +1,320 @@
+package apicompat
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ---------------------------------------------------------------------------
+// ResponsesRequestToAnthropic tests
+// ---------------------------------------------------------------------------
+
+func TestResponsesRequestToAnthropic_StringInput(t *testing.T) {
+	req := &ResponsesRequest{
+		Model:        "claude-sonnet-4-5",
+		Instructions: "Be brief.",
+		Input:        json.RawMessage(`"Hello"`),
+		Stream:       true,
+	}
+
+	out, err := ResponsesRequestToAnthropic(req)
+	require.NoError(t, err)
+	assert.Equal(t, "claude-sonnet-4-5", out.Model)
+	assert.True(t, out.Stream)
+	assert.Equal(t, defaultAnthropicMaxTokens, out.MaxTokens)
+	assert.JSONEq(t, `"Be brief."`, string(out.System))
+	require.Len(t, out.Messages, 1)
+	assert.Equal(t, "user", out.Messages[0].Role)
+	assert.JSONEq(t, `[{"type":"text","text":"Hello"}]`, string(out.Messages[0].Content))
+}
+
+func TestResponsesRequestToAnthropic_ItemsAndTools(t *testing.T) {
+	maxOut := 2048
+	req := &ResponsesRequest{
+		Model: "claude-sonnet-4-5",
+		Input: json.RawMessage(`[
+			{"type":"message","role":"developer","content":[{"type":"input_text","text":"dev rules"}]},
+			{"type":"message","role":"user","content":[
+				{"type":"input_text","text":"What's the weather?"},
+				{"type":"input_image","image_url":"data:image/png;base64,AAAA"}
+			]},
+			{"type":"message","role":"assistant","content":[{"type":"output_text","text":"Checking."}]},
+			{"type":"function_call","id":"fc_toolu_1","call_id":"toolu_1","name":"get_weather","arguments":"{\"city\":\"Paris\"}"},
+			{"type":"function_call_output","call_id":"toolu_1","output":"sunny"}
+		]`),
+		MaxOutputTokens: &maxOut,
+		Tools: []ResponsesTool{
+			{Type: "function", Name: "get_weather", Parameters: json.RawMessage(`{"type":"object"}`)},
+			{Type: "web_search"},
+		},
+		ToolChoice: json.RawMessage(`{"type":"function","name":"get_weather"}`),
+	}
+
+	out, err := ResponsesRequestToAnthropic(req)
+	require.NoError(t, err)
+	assert.Equal(t, 2048, out.MaxTokens)
+	assert.JSONEq(t, `"dev rules"`, string(out.System))
+
+	require.Len(t, out.Tools, 1)
+	assert.Equal(t, "get_weather", out.Tools[0].Name)
+	assert.JSONEq(t, `{"type":"tool","name":"get_weather"}`, string(out.ToolChoice))
+
+	require.Len(t, out.Messages, 3)
+	assert.Equal(t, "user", out.Messages[0].Role)
+	assert.JSONEq(t, `[
+		{"type":"text","text":"What's the weather?"},
+		{"type":"image","source":{"type":"base64","media_type":"image/png","data":"AAAA"}}
+	]`, string(out.Messages[0].Content))
+
+	assert.Equal(t, "assistant", out.Messages[1].Role)
+	assert.JSONEq(t, `[
+		{"type":"text","text":"Checking."},
+		{"type":"tool_use","id":"toolu_1","name":"get_weather","input":{"city":"Paris"}}
+	]`, string(out.Messages[1].Content))
+
+	assert.Equal(t, "user", out.Messages[2].Role)
+	assert.JSONEq(t, `[{"type":"tool_result","tool_use_id":"toolu_1","content":"sunny"}]`, string(out.Messages[2].Content))
+}
+
+func TestResponsesRequestToAnthropic_ReasoningReplay(t *testing.T) {
+	encrypted := encodeResponsesReasoning("let me think", "sig-1")
+	input, err := json.Marshal([]ResponsesInputItem{
+		{Role: "user", Content: json.RawMessage(`"hi"`)},
+		{Type: "reasoning", EncryptedContent: encrypted},
+		{Type: "reasoning", EncryptedContent: "gAAAA-openai-blob"},
+		{Type: "function_call", CallID: "toolu_1", Name: "ls", Arguments: "{}"},
+		{Type: "function_call_output", CallID: "toolu_1", Output: "ok"},
+	})
+	require.NoError(t, err)
+	temp := 0.5
+
+	t.Run("thinking enabled", func(t *testing.T) {
+		out, err := ResponsesRequestToAnthropic(&ResponsesRequest{
+			Model:       "claude-sonnet-4-5",
+			Input:       input,
+			Temperature: &temp,
+			Reasoning:   &ResponsesReasoning{Effort: "high"},
+		})
+		require.NoError(t, err)
+		require.NotNil(t, out.Thinking)
+		assert.Equal(t, 16384, out.Thinking.BudgetTokens)
+		assert.Greater(t, out.MaxTokens, 16384)
+		assert.Nil(t, out.Temperature)
+
+		require.Len(t, out.Messages, 3)
+		var blocks []AnthropicContentBlock
+		require.NoError(t, json.Unmarshal(out.Messages[1].Content, &blocks))
+		require.Len(t, blocks, 2)
+		assert.Equal(t, "thinking", blocks[0].Type)
+		assert.Equal(t, "let me think", blocks[0].Thinking)
+		assert.Equal(t, "sig-1", blocks[0].Signature)
+		assert.Equal(t, "tool_use", blocks[1].Type)
+	})
+
+	t.Run("thinking disabled", func(t *testing.T) {
+		out, err := ResponsesRequestToAnthropic(&ResponsesRequest{Model: "claude-sonnet-4-5", Input: input})
+		require.NoError(t, err)
+		assert.Nil(t, out.Thinking)
+		var blocks []AnthropicContentBlock
+		require.NoError(t, json.Unmarshal(out.Messages[1].Content, &blocks))
+		require.Len(t, blocks, 1)
+		assert.Equal(t, "tool_use", blocks[0].Type)
+	})
+}
+
+func TestResponsesRequestToAnthropic_ToolChoiceRequiredSerial(t *testing.T) {
+	parallel := false
+	out, err := ResponsesRequestToAnthropic(&ResponsesRequest{
+		Model:             "claude-sonnet-4-5",
+		Input:             json.RawMessage(`"hi"`),
+		Tools:             []ResponsesTool{{Type: "function", Name: "ls"}},
+		ToolChoice:        json.RawMessage(`"required"`),
+		ParallelToolCalls: &parallel,
+	})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"type":"any","disable_parallel_tool_use":true}`, string(out.ToolChoice))
+	assert.JSONEq(t, `{"type":"object","properties":{}}`, string(out.Tools[0].InputSchema))
+}
+
+// ---------------------------------------------------------------------------
+// AnthropicResponseToResponses tests
+// ---------------------------------------------------------------------------
+
+func TestAnthropicResponseToResponses(t *testing.T) {
+	resp := &AnthropicResponse{
+		ID:   "msg_123",
+		Type: "message",
+		Content: []AnthropicContentBlock{
+			{Type: "thinking", Thinking: "hmm", Signature: "sig"},
+			{Type: "text", Text: "Let me check."},
+			{Type: "tool_use", ID: "toolu_9", Name: "ls", Input: json.RawMessage(`{"path":"."}`)},
+		},
+		StopReason: "tool_use",
+		Usage:      AnthropicUsage{InputTokens: 10, OutputTokens: 5, CacheReadInputTokens: 20},
+	}
+
+	out := AnthropicResponseToResponses(resp, "claude-sonnet-4-5")
+	assert.Equal(t, "resp_123", out.ID)
+	assert.Equal(t, "response", out.Object)
+	assert.Equal(t, "claude-sonnet-4-5", out.Model)
+	assert.Equal(t, "completed", out.Status)
+	require.Len(t, out.Output, 3)
+
+	assert.Equal(t, "reasoning", out.Output[0].Type)
+	assert.Equal(t, "hmm", out.Output[0].Summary[0].Text)
+	block, ok := decodeResponsesReasoning(out.Output[0].EncryptedContent)
+	require.True(t, ok)
+	assert.Equal(t, "sig", block.Signature)
+
+	assert.Equal(t, "message", out.Output[1].Type)
+	assert.Equal(t, "Let me check.", out.Output[1].Content[0].Text)
+
+	assert.Equal(t, "function_call", out.Output[2].Type)
+	assert.Equal(t, "fc_toolu_9", out.Output[2].ID)
+	assert.Equal(t, "toolu_9", out.Output[2].CallID)
+	assert.Equal(t, `{"path":"."}`, out.Output[2].Arguments)
+
+	require.NotNil(t, out.Usage)
+	assert.Equal(t, 30, out.Usage.InputTokens)
+	assert.Equal(t, 35, out.Usage.TotalTokens)
+	assert.Equal(t, 20, out.Usage.InputTokensDetails.CachedTokens)
+
+	items := ResponsesOutputToInputItems(out.Output)
+	require.Len(t, items, 3)
+	assert.Equal(t, "reasoning", items[0].Type)
+	assert.Equal(t, "assistant", items[1].Role)
+	assert.Equal(t, "toolu_9", items[2].CallID)
+}
+
+func TestAnthropicResponseToResponses_MaxTokens(t *testing.T) {
+	out := AnthropicResponseToResponses(&AnthropicResponse{
+		ID:         "msg_1",
+		Content:    []AnthropicContentBlock{{Type: "text", Text: "partial"}},
+		StopReason: "max_tokens",
+	}, "")
+	assert.Equal(t, "incomplete", out.Status)
+	require.NotNil(t, out.IncompleteDetails)
+	assert.Equal(t, "max_output_tokens", out.IncompleteDetails.Reason)
+}
+
+// ---------------------------------------------------------------------------
+// Streaming: AnthropicEventToResponsesEvents tests
+// ---------------------------------------------------------------------------
+
+func runAnthropicToResponsesStream(t *testing.T, raw []string) ([]ResponsesStreamEvent, *AnthropicEventToResponsesState) {
+	t.Helper()
+	state := NewAnthropicEventToResponsesState()
+	var events []ResponsesStreamEvent
+	for _, r := range raw {
+		var evt AnthropicStreamEvent
+		require.NoError(t, json.Unmarshal([]byte(r), &evt))
+		events = append(events, AnthropicEventToResponsesEvents(&evt, state)...)
+	}
+	return events, state
+}
+
+func responsesEventTypes(events []ResponsesStreamEvent) []string {
+	types := make([]string, len(events))
+	for i, e := range events {
+		types[i] = e.Type
+	}
+	return types
+}
+
+func TestAnthropicEventToResponses_TextAndTool(t *testing.T) {
+	events, state := runAnthropicToResponsesStream(t, []string{
+		`{"type":"message_start","message":{"id":"msg_s1","model":"claude-sonnet-4-5","usage":{"input_tokens":7,"output_tokens":1}}}`,
+		`{"type":"content_block_start","index":0,"content_block":{"type":"text","text":""}}`,
+		`{"type":"content_block_delta","index":0,"delta":{"type":"text_delta","text":"Hel"}}`,
+		`{"type":"content_block_delta","index":0,"delta":{"type":"text_delta","text":"lo"}}`,
+		`{"type":"content_block_stop","index":0}`,
+		`{"type":"content_block_start","index":1,"content_block":{"type":"tool_use","id":"toolu_1","name":"ls"}}`,
+		`{"type":"content_block_delta","index":1,"delta":{"type":"input_json_delta","partial_json":"{\"a\":"}}`,
+		`{"type":"content_block_delta","index":1,"delta":{"type":"input_json_delta","partial_json":"1}"}}`,
+		`{"type":"content_block_stop","index":1}`,
+		`{"type":"message_delta","delta":{"stop_reason":"tool_use"},"usage":{"output_tokens":9}}`,
+		`{"type":"message_stop"}`,
+	})
+
+	assert.Equal(t, []string{
+		"response.created",
+		"response.in_progress",
+		"response.output_item.added",
+		"response.content_part.added",
+		"response.output_text.delta",
+		"response.output_text.delta",
+		"response.output_text.done",
+		"response.content_part.done",
+		"response.output_item.done",
+		"response.output_item.added",
+		"response.function_call_arguments.delta",
+		"response.function_call_arguments.delta",
+		"response.function_call_arguments.done",
+		"response.output_item.done",
+		"response.completed",
+	}, responsesEventTypes(events))
+
+	for i, e := range events {
+		assert.Equal(t, i, e.SequenceNumber)
+	}
+	assert.Equal(t, "Hello", events[6].Text)
+	assert.Equal(t, `{"a":1}`, events[12].Arguments)
+	assert.Equal(t, 1, events[13].OutputIndex)
+
+	require.True(t, state.Finished)
+	final := events[len(events)-1].Response
+	require.NotNil(t, final)
+	assert.Equal(t, "resp_s1", final.ID)
+	assert.Equal(t, "completed", final.Status)
+	require.Len(t, final.Output, 2)
+	assert.Equal(t, "Hello", final.Output[0].Content[0].Text)
+	assert.Equal(t, "toolu_1", final.Output[1].CallID)
+	assert.Equal(t, 7, final.Usage.InputTokens)
+	assert.Equal(t, 9, final.Usage.OutputTokens)
+	assert.Same(t, final, state.Response)
+}
+
+func TestAnthropicEventToResponses_ThinkingSignature(t *testing.T) {
+	events, state := runAnthropicToResponsesStream(t, []string{
+		`{"type":"message_start","message":{"id":"msg_t","usage":{"input_tokens":1}}}`,
+		`{"type":"content_block_start","index":0,"content_block":{"type":"thinking","thinking":""}}`,
+		`{"type":"content_block_delta","index":0,"delta":{"type":"thinking_delta","thinking":"plan"}}`,
+		`{"type":"content_block_delta","index":0,"delta":{"type":"signature_delta","signature":"abc"}}`,
+		`{"type":"content_block_stop","index":0}`,
+	})
+	assert.Contains(t, responsesEventTypes(events), "response.reasoning_summary_text.delta")
+	require.Len(t, state.Output, 1)
+	block, ok := decodeResponsesReasoning(state.Output[0].EncryptedContent)
+	require.True(t, ok)
+	assert.Equal(t, "plan", block.Thinking)
+	assert.Equal(t, "abc", block.Signature)
+}
+
+func TestFinalizeAnthropicResponsesStream(t *testing.T) {
+	t.Run("never started", func(t *testing.T) {
+		assert.Nil(t, FinalizeAnthropicResponsesStream(NewAnthropicEventToResponsesState()))
+	})
+
+	t.Run("truncated stream", func(t *testing.T) {
+		_, state := runAnthropicToResponsesStream(t, []string{
+			`{"type":"message_start","message":{"id":"msg_x"}}`,
+			`{"type":"content_block_start","index":0,"content_block":{"type":"text","text":""}}`,
+			`{"type":"content_block_delta","index":0,"delta":{"type":"text_delta","text":"abc"}}`,
+		})
+		events := FinalizeAnthropicResponsesStream(state)
+		types := responsesEventTypes(events)
+		assert.Equal(t, "response.completed", types[len(types)-1])
+		assert.Contains(t, types, "response.output_item.done")
+		assert.Equal(t, "abc", state.Response.Output[0].Content[0].Text)
+		assert.Nil(t, FinalizeAnthropicResponsesStream(state))
+	})
+}
+
+func TestResponsesEventToSSE(t *testing.T) {
+	sse, err := ResponsesEventToSSE(ResponsesStreamEvent{Type: "response.output_text.delta", Delta: "hi"})
+	require.NoError(t, err)
+	assert.Equal(t, "event: response.output_text.delta\ndata: {\"type\":\"response.output_text.delta\",\"delta\":\"hi\"}\n\n", sse)
+}
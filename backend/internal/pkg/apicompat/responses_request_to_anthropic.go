@@ -0,0 +1,351 @@
+package apicompat
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// ResponsesRequestToAnthropic converts an OpenAI Responses API request into an
+// Anthropic Messages request. It is the inverse of AnthropicToResponses and is
+// used when a Responses client (e.g. Codex CLI) talks to a Claude, Gemini or
+// Antigravity group.
+//
+// Instructions and system/developer items are hoisted into the system prompt,
+// function_call/function_call_output items become tool_use/tool_result
+// blocks, and reasoning items are replayed as thinking blocks when they carry
+// a signature produced by AnthropicResponseToResponses.
+func ResponsesRequestToAnthropic(req *ResponsesRequest) (*AnthropicRequest, error) {
+	items, err := ParseResponsesInput(req.Input)
+	if err != nil {
+		return nil, fmt.Errorf("parse input: %w", err)
+	}
+
+	budget := 0
+	if req.Reasoning != nil {
+		budget = reasoningEffortToThinkingBudget(req.Reasoning.Effort)
+	}
+
+	system, msgs, err := convertResponsesInputToAnthropic(req.Instructions, items, budget > 0)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &AnthropicRequest{
+		Model:       req.Model,
+		Messages:    msgs,
+		Stream:      req.Stream,
+		Temperature: req.Temperature,
+		TopP:        req.TopP,
+	}
+
+	if system != "" {
+		sysJSON, err := json.Marshal(system)
+		if err != nil {
+			return nil, err
+		}
+		out.System = sysJSON
+	}
+
+	if req.MaxOutputTokens != nil && *req.MaxOutputTokens > 0 {
+		out.MaxTokens = *req.MaxOutputTokens
+	} else {
+		out.MaxTokens = defaultAnthropicMaxTokens
+	}
+
+	if len(req.Tools) > 0 {
+		out.Tools = convertResponsesToolsToAnthropic(req.Tools)
+	}
+
+	if len(req.ToolChoice) > 0 || req.ParallelToolCalls != nil {
+		tc, err := convertResponsesToolChoiceToAnthropic(req.ToolChoice, req.ParallelToolCalls, len(out.Tools) > 0)
+		if err != nil {
+			return nil, fmt.Errorf("convert tool_choice: %w", err)
+		}
+		out.ToolChoice = tc
+	}
+
+	// Same budget handling as ChatCompletionsToAnthropic: budget_tokens must
+	// stay below max_tokens and sampling parameters are not allowed together
+	// with extended thinking.
+	if budget > 0 {
+		out.Thinking = &AnthropicThinking{Type: "enabled", BudgetTokens: budget}
+		if out.MaxTokens <= budget {
+			out.MaxTokens += budget
+		}
+		out.Temperature = nil
+		out.TopP = nil
+	}
+
+	return out, nil
+}
+
+// ParseResponsesInput decodes the Responses input field, which is either a
+// plain string (a single user message) or an array of input items.
+func ParseResponsesInput(raw json.RawMessage) ([]ResponsesInputItem, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, nil
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		content, _ := json.Marshal(s)
+		return []ResponsesInputItem{{Role: "user", Content: content}}, nil
+	}
+	var items []ResponsesInputItem
+	if err := json.Unmarshal(raw, &items); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// convertResponsesInputToAnthropic splits Responses input items into the
+// Anthropic system prompt and a role-alternating message list.
+func convertResponsesInputToAnthropic(instructions string, items []ResponsesInputItem, thinking bool) (string, []AnthropicMessage, error) {
+	var systemParts []string
+	if strings.TrimSpace(instructions) != "" {
+		systemParts = append(systemParts, instructions)
+	}
+	var turns anthropicTurns
+
+	for _, item := range items {
+		switch item.Type {
+		case "", "message":
+			switch item.Role {
+			case "system", "developer":
+				text, err := extractResponsesText(item.Content)
+				if err != nil {
+					return "", nil, err
+				}
+				if text != "" {
+					systemParts = append(systemParts, text)
+				}
+			case "assistant":
+				text, err := extractResponsesText(item.Content)
+				if err != nil {
+					return "", nil, err
+				}
+				if text != "" {
+					turns.append("assistant", []AnthropicContentBlock{{Type: "text", Text: text}})
+				}
+			default:
+				blocks, err := responsesUserToAnthropicBlocks(item.Content)
+				if err != nil {
+					return "", nil, err
+				}
+				turns.append("user", blocks)
+			}
+
+		case "function_call":
+			input := json.RawMessage(item.Arguments)
+			if !json.Valid(input) {
+				input = json.RawMessage("{}")
+			}
+			turns.append("assistant", []AnthropicContentBlock{{
+				Type:  "tool_use",
+				ID:    responsesCallIDToToolUseID(item),
+				Name:  item.Name,
+				Input: input,
+			}})
+
+		case "function_call_output":
+			content, _ := json.Marshal(item.Output)
+			turns.append("user", []AnthropicContentBlock{{
+				Type:      "tool_result",
+				ToolUseID: item.CallID,
+				Content:   content,
+			}})
+
+		case "reasoning":
+			// Anthropic only accepts thinking blocks it signed itself, and
+			// rejects them entirely when thinking is disabled.
+			if !thinking {
+				continue
+			}
+			if block, ok := decodeResponsesReasoning(item.EncryptedContent); ok {
+				turns.append("assistant", []AnthropicContentBlock{block})
+			}
+		}
+	}
+
+	out, err := turns.messages()
+	if err != nil {
+		return "", nil, err
+	}
+	return strings.Join(systemParts, "\n\n"), out, nil
+}
+
+// responsesCallIDToToolUseID picks the ID used to pair tool_use with
+// tool_result. function_call_output only references call_id, so that is the
+// canonical key; id is a fallback for clients that omit call_id.
+func responsesCallIDToToolUseID(item ResponsesInputItem) string {
+	if item.CallID != "" {
+		return item.CallID
+	}
+	return item.ID
+}
+
+// responsesUserToAnthropicBlocks converts user content (string or parts) into
+// Anthropic text/image blocks.
+func responsesUserToAnthropicBlocks(raw json.RawMessage) ([]AnthropicContentBlock, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, nil
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		if s == "" {
+			return nil, nil
+		}
+		return []AnthropicContentBlock{{Type: "text", Text: s}}, nil
+	}
+
+	var parts []ResponsesContentPart
+	if err := json.Unmarshal(raw, &parts); err != nil {
+		return nil, err
+	}
+	var blocks []AnthropicContentBlock
+	for _, p := range parts {
+		switch p.Type {
+		case "input_text", "output_text", "text":
+			if p.Text != "" {
+				blocks = append(blocks, AnthropicContentBlock{Type: "text", Text: p.Text})
+			}
+		case "input_image":
+			if p.ImageURL == "" {
+				continue
+			}
+			blocks = append(blocks, AnthropicContentBlock{
+				Type:   "image",
+				Source: chatImageURLToAnthropicSource(p.ImageURL),
+			})
+		}
+	}
+	return blocks, nil
+}
+
+// extractResponsesText returns the concatenated text of a Responses content
+// field (string or parts array).
+func extractResponsesText(raw json.RawMessage) (string, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return "", nil
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s, nil
+	}
+	var parts []ResponsesContentPart
+	if err := json.Unmarshal(raw, &parts); err != nil {
+		return "", err
+	}
+	var texts []string
+	for _, p := range parts {
+		if (p.Type == "input_text" || p.Type == "output_text" || p.Type == "text") && p.Text != "" {
+			texts = append(texts, p.Text)
+		}
+	}
+	return strings.Join(texts, "\n\n"), nil
+}
+
+// convertResponsesToolsToAnthropic maps Responses function tools to Anthropic
+// tool definitions. Built-in tools (web_search, local_shell, ...) have no
+// Anthropic equivalent and are dropped.
+func convertResponsesToolsToAnthropic(tools []ResponsesTool) []AnthropicTool {
+	out := make([]AnthropicTool, 0, len(tools))
+	for _, t := range tools {
+		if t.Type != "function" || t.Name == "" {
+			continue
+		}
+		schema := t.Parameters
+		if len(schema) == 0 || string(schema) == "null" {
+			schema = json.RawMessage(`{"type":"object","properties":{}}`)
+		}
+		out = append(out, AnthropicTool{
+			Name:        t.Name,
+			Description: t.Description,
+			InputSchema: schema,
+		})
+	}
+	return out
+}
+
+// convertResponsesToolChoiceToAnthropic maps Responses tool_choice to
+// Anthropic format.
+//
+//	"auto"                          → {"type":"auto"}
+//	"required"                      → {"type":"any"}
+//	"none"                          → {"type":"none"}
+//	{"type":"function","name":X}    → {"type":"tool","name":X}
+func convertResponsesToolChoiceToAnthropic(raw json.RawMessage, parallel *bool, hasTools bool) (json.RawMessage, error) {
+	chatChoice, err := responsesToolChoiceToChat(raw)
+	if err != nil {
+		return nil, err
+	}
+	return convertChatToolChoiceToAnthropic(chatChoice, parallel, hasTools)
+}
+
+// responsesToolChoiceToChat re-shapes a Responses tool_choice into the Chat
+// Completions form ({"type":"function","function":{"name":X}}) so the shared
+// mappers can handle it. String choices are already identical.
+func responsesToolChoiceToChat(raw json.RawMessage) (json.RawMessage, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, nil
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return raw, nil
+	}
+	var obj struct {
+		Type string `json:"type"`
+		Name string `json:"name"`
+	}
+	if err := json.Unmarshal(raw, &obj); err != nil {
+		return nil, err
+	}
+	if obj.Name == "" {
+		return nil, nil
+	}
+	return json.Marshal(map[string]any{
+		"type":     "function",
+		"function": map[string]string{"name": obj.Name},
+	})
+}
+
+// responsesReasoningPrefix marks encrypted_content values minted by this
+// package, so reasoning items from other providers are not mistaken for
+// replayable Anthropic thinking blocks.
+const responsesReasoningPrefix = "anthropic.thinking:"
+
+type responsesReasoningEnvelope struct {
+	Thinking  string `json:"thinking"`
+	Signature string `json:"signature"`
+}
+
+// encodeResponsesReasoning packs a signed thinking block into an opaque
+// encrypted_content string that round-trips through the client.
+func encodeResponsesReasoning(thinking, signature string) string {
+	if signature == "" {
+		return ""
+	}
+	data, err := json.Marshal(responsesReasoningEnvelope{Thinking: thinking, Signature: signature})
+	if err != nil {
+		return ""
+	}
+	return responsesReasoningPrefix + base64.StdEncoding.EncodeToString(data)
+}
+
+// decodeResponsesReasoning reverses encodeResponsesReasoning.
+func decodeResponsesReasoning(s string) (AnthropicContentBlock, bool) {
+	encoded, ok := strings.CutPrefix(s, responsesReasoningPrefix)
+	if !ok {
+		return AnthropicContentBlock{}, false
+	}
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return AnthropicContentBlock{}, false
+	}
+	var env responsesReasoningEnvelope
+	if err := json.Unmarshal(data, &env); err != nil || env.Signature == "" {
+		return AnthropicContentBlock{}, false
+	}
+	return AnthropicContentBlock{Type: "thinking", Thinking: env.Thinking, Signature: env.Signature}, true
+}
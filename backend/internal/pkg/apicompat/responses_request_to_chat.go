@@ -0,0 +1,166 @@
+package apicompat
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// ResponsesRequestToChatCompletions converts an OpenAI Responses API request
+// into a Chat Completions request. It is used to reach upstreams that only
+// have a Chat Completions translation (e.g. Gemini generateContent).
+//
+// function_call items are attached to the preceding assistant message as
+// tool_calls and function_call_output items become tool messages. Reasoning
+// items are dropped: Chat Completions has no way to replay them.
+func ResponsesRequestToChatCompletions(req *ResponsesRequest) (*ChatCompletionsRequest, error) {
+	items, err := ParseResponsesInput(req.Input)
+	if err != nil {
+		return nil, fmt.Errorf("parse input: %w", err)
+	}
+	msgs, err := convertResponsesInputToChat(req.Instructions, items)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &ChatCompletionsRequest{
+		Model:               req.Model,
+		Messages:            msgs,
+		MaxCompletionTokens: req.MaxOutputTokens,
+		Temperature:         req.Temperature,
+		TopP:                req.TopP,
+		Stream:              req.Stream,
+		ParallelToolCalls:   req.ParallelToolCalls,
+	}
+	if req.Reasoning != nil {
+		out.ReasoningEffort = req.Reasoning.Effort
+	}
+
+	for _, t := range req.Tools {
+		if t.Type != "function" || t.Name == "" {
+			continue
+		}
+		out.Tools = append(out.Tools, ChatTool{
+			Type: "function",
+			Function: &ChatFunction{
+				Name:        t.Name,
+				Description: t.Description,
+				Parameters:  t.Parameters,
+				Strict:      t.Strict,
+			},
+		})
+	}
+	if out.ToolChoice, err = responsesToolChoiceToChat(req.ToolChoice); err != nil {
+		return nil, fmt.Errorf("convert tool_choice: %w", err)
+	}
+
+	if req.Text != nil && req.Text.Format != nil {
+		f := req.Text.Format
+		out.ResponseFormat = &ChatResponseFormat{Type: f.Type}
+		if f.Type == "json_schema" {
+			out.ResponseFormat.JSONSchema = &ChatJSONSchema{
+				Name:        f.Name,
+				Description: f.Description,
+				Schema:      f.Schema,
+				Strict:      f.Strict,
+			}
+		}
+	}
+
+	return out, nil
+}
+
+// convertResponsesInputToChat maps Responses input items onto Chat
+// Completions messages.
+func convertResponsesInputToChat(instructions string, items []ResponsesInputItem) ([]ChatMessage, error) {
+	var msgs []ChatMessage
+	if strings.TrimSpace(instructions) != "" {
+		content, _ := json.Marshal(instructions)
+		msgs = append(msgs, ChatMessage{Role: "system", Content: content})
+	}
+
+	for _, item := range items {
+		switch item.Type {
+		case "", "message":
+			switch item.Role {
+			case "system", "developer", "assistant":
+				text, err := extractResponsesText(item.Content)
+				if err != nil {
+					return nil, err
+				}
+				if text == "" {
+					continue
+				}
+				content, _ := json.Marshal(text)
+				msgs = append(msgs, ChatMessage{Role: item.Role, Content: content})
+			default:
+				content, err := responsesUserToChatContent(item.Content)
+				if err != nil {
+					return nil, err
+				}
+				if content == nil {
+					continue
+				}
+				msgs = append(msgs, ChatMessage{Role: "user", Content: content})
+			}
+
+		case "function_call":
+			call := ChatToolCall{
+				ID:   responsesCallIDToToolUseID(item),
+				Type: "function",
+				Function: ChatFunctionCall{
+					Name:      item.Name,
+					Arguments: item.Arguments,
+				},
+			}
+			if n := len(msgs); n > 0 && msgs[n-1].Role == "assistant" {
+				msgs[n-1].ToolCalls = append(msgs[n-1].ToolCalls, call)
+				continue
+			}
+			msgs = append(msgs, ChatMessage{Role: "assistant", ToolCalls: []ChatToolCall{call}})
+
+		case "function_call_output":
+			content, _ := json.Marshal(item.Output)
+			msgs = append(msgs, ChatMessage{Role: "tool", ToolCallID: item.CallID, Content: content})
+		}
+	}
+	return msgs, nil
+}
+
+// responsesUserToChatContent converts user content (string or parts) into
+// Chat Completions content parts. It returns nil when there is nothing to
+// send.
+func responsesUserToChatContent(raw json.RawMessage) (json.RawMessage, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, nil
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		if s == "" {
+			return nil, nil
+		}
+		return raw, nil
+	}
+
+	var parts []ResponsesContentPart
+	if err := json.Unmarshal(raw, &parts); err != nil {
+		return nil, err
+	}
+	var out []ChatContentPart
+	for _, p := range parts {
+		switch p.Type {
+		case "input_text", "output_text", "text":
+			if p.Text != "" {
+				out = append(out, ChatContentPart{Type: "text", Text: p.Text})
+			}
+		case "input_image":
+			if p.ImageURL != "" {
+				out = append(out, ChatContentPart{Type: "image_url", ImageURL: &ChatImageURL{URL: p.ImageURL}})
+			}
+		}
+	}
+	if len(out) == 0 {
+		return nil, nil
+	}
+	return json.Marshal(out)
+}
@@ -53,6 +53,13 @@ const (
 	// Service 层仅在分组匹配时复用 PrefetchedStickyAccountID，避免分组切换重试误用旧 sticky。
 	PrefetchedStickyGroupID Key = "ctx_prefetched_sticky_group_id"
 
+	// BatchJobID 标识由批处理执行器在进程内回放的请求所属任务 ID（int64）。
+	// 仅由 BatchService 设置，客户端无法通过请求头伪造。
+	BatchJobID Key = "ctx_batch_job_id"
+
+	// ClientInfo 登录相关接口的客户端 IP 与 User-Agent（service.ClientInfo），由 middleware.ClientInfo 设置。
+	ClientInfo Key = "ctx_client_info"
+
 	// ClaudeCodeVersion stores the extracted Claude Code version from User-Agent (e.g. "2.1.22")
 	ClaudeCodeVersion Key = "ctx_claude_code_version"
 )
@@ -0,0 +1,157 @@
+// Package metrics 提供 Prometheus 指标注册表与网关请求指标。
+//
+// 网关请求、首 token 延迟等事件型指标由本包直接维护；账号状态、并发槽位、调度器、
+// 用量记录工作池等状态型指标由 service 层在抓取时通过 Register 注册的 Collector 提供。
+package metrics
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Namespace 所有指标名称的前缀
+const Namespace = "sub2api"
+
+const (
+	// maxModelLabelValues 模型标签的去重上限：模型名来自客户端请求，需防止任意字符串撑爆时间序列
+	maxModelLabelValues = 512
+	maxLabelLength      = 128
+
+	// OtherLabelValue 超出上限或为空时使用的标签值
+	OtherLabelValue = "other"
+	// UnknownLabelValue 无法识别平台/分组时使用的标签值
+	UnknownLabelValue = "unknown"
+)
+
+var (
+	registry = prometheus.NewRegistry()
+
+	gatewayRequestsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Subsystem: "gateway",
+		Name:      "requests_total",
+		Help:      "Gateway requests by platform, group, model and HTTP status.",
+	}, []string{"platform", "group", "model", "status"})
+
+	gatewayRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: Namespace,
+		Subsystem: "gateway",
+		Name:      "request_duration_seconds",
+		Help:      "Gateway request latency (including streaming) by platform, group, model and HTTP status.",
+		Buckets:   []float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10, 20, 30, 60, 120, 300, 600},
+	}, []string{"platform", "group", "model", "status"})
+
+	gatewayTimeToFirstToken = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: Namespace,
+		Subsystem: "gateway",
+		Name:      "time_to_first_token_seconds",
+		Help:      "Time to first token of successful streaming requests by platform, group and model.",
+		Buckets:   []float64{0.1, 0.25, 0.5, 1, 1.5, 2, 3, 5, 8, 13, 20, 30, 60},
+	}, []string{"platform", "group", "model"})
+
+	modelLabels = newLabelLimiter(maxModelLabelValues)
+)
+
+func init() {
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		gatewayRequestsTotal,
+		gatewayRequestDuration,
+		gatewayTimeToFirstToken,
+	)
+}
+
+// ObserveGatewayRequest 记录一次网关请求的结果与耗时
+func ObserveGatewayRequest(platform, group, model string, status int, duration time.Duration) {
+	platform, group, model = normalizeLabel(platform), normalizeLabel(group), modelLabels.value(model)
+	statusLabel := strconv.Itoa(status)
+	gatewayRequestsTotal.WithLabelValues(platform, group, model, statusLabel).Inc()
+	gatewayRequestDuration.WithLabelValues(platform, group, model, statusLabel).Observe(duration.Seconds())
+}
+
+// ObserveTimeToFirstToken 记录一次流式请求的首 token 延迟
+func ObserveTimeToFirstToken(platform, group, model string, ttft time.Duration) {
+	if ttft < 0 {
+		return
+	}
+	gatewayTimeToFirstToken.WithLabelValues(normalizeLabel(platform), normalizeLabel(group), modelLabels.value(model)).Observe(ttft.Seconds())
+}
+
+// Register 注册额外的 Collector；重复注册同一 Collector 不视为错误
+func Register(c prometheus.Collector) error {
+	if err := registry.Register(c); err != nil {
+		var already prometheus.AlreadyRegisteredError
+		if errors.As(err, &already) {
+			return nil
+		}
+		return err
+	}
+	return nil
+}
+
+// Unregister 注销通过 Register 注册的 Collector
+func Unregister(c prometheus.Collector) bool {
+	return registry.Unregister(c)
+}
+
+// Handler 返回导出全部指标的 HTTP 处理器
+func Handler() http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+}
+
+func normalizeLabel(v string) string {
+	v = strings.TrimSpace(v)
+	if v == "" {
+		return UnknownLabelValue
+	}
+	if len(v) > maxLabelLength {
+		v = v[:maxLabelLength]
+	}
+	return v
+}
+
+// labelLimiter 为取值不受控的标签设置基数上限，超出上限的新值统一归入 OtherLabelValue
+type labelLimiter struct {
+	mu    sync.RWMutex
+	limit int
+	seen  map[string]struct{}
+}
+
+func newLabelLimiter(limit int) *labelLimiter {
+	return &labelLimiter{limit: limit, seen: make(map[string]struct{})}
+}
+
+func (l *labelLimiter) value(v string) string {
+	v = strings.TrimSpace(v)
+	if v == "" {
+		return OtherLabelValue
+	}
+	if len(v) > maxLabelLength {
+		v = v[:maxLabelLength]
+	}
+	l.mu.RLock()
+	_, ok := l.seen[v]
+	l.mu.RUnlock()
+	if ok {
+		return v
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if _, ok := l.seen[v]; ok {
+		return v
+	}
+	if len(l.seen) >= l.limit {
+		return OtherLabelValue
+	}
+	l.seen[v] = struct{}{}
+	return v
+}
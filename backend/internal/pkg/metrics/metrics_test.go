@@ -0,0 +1,58 @@
+package metrics
+
+import (
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestObserveGatewayRequest(t *testing.T) {
+	ObserveGatewayRequest("anthropic", "team-a", "claude-sonnet-4-5", 200, 1500*time.Millisecond)
+	ObserveGatewayRequest("anthropic", "team-a", "claude-sonnet-4-5", 200, 500*time.Millisecond)
+	ObserveGatewayRequest("", " ", "", 401, time.Millisecond)
+
+	require.Equal(t, 2.0, testutil.ToFloat64(gatewayRequestsTotal.WithLabelValues("anthropic", "team-a", "claude-sonnet-4-5", "200")))
+	require.Equal(t, 1.0, testutil.ToFloat64(gatewayRequestsTotal.WithLabelValues(UnknownLabelValue, UnknownLabelValue, OtherLabelValue, "401")))
+
+	ObserveTimeToFirstToken("openai", "team-b", "gpt-5", 800*time.Millisecond)
+	ObserveTimeToFirstToken("openai", "team-b", "gpt-5", -time.Second)
+	require.Equal(t, 1, testutil.CollectAndCount(gatewayTimeToFirstToken))
+}
+
+func TestLabelLimiter_CapsCardinality(t *testing.T) {
+	l := newLabelLimiter(2)
+	require.Equal(t, "a", l.value("a"))
+	require.Equal(t, "b", l.value(" b "))
+	require.Equal(t, OtherLabelValue, l.value("c"))
+	require.Equal(t, "a", l.value("a"), "known values keep their label after the cap is reached")
+	require.Equal(t, OtherLabelValue, l.value(""))
+	require.Len(t, newLabelLimiter(2).value(strings.Repeat("x", 300)), maxLabelLength)
+}
+
+type stubCollector struct {
+	desc *prometheus.Desc
+}
+
+func (s stubCollector) Describe(ch chan<- *prometheus.Desc) { ch <- s.desc }
+func (s stubCollector) Collect(ch chan<- prometheus.Metric) {
+	ch <- prometheus.MustNewConstMetric(s.desc, prometheus.GaugeValue, 7)
+}
+
+func TestRegisterAndHandler(t *testing.T) {
+	c := stubCollector{desc: prometheus.NewDesc("sub2api_test_stub", "stub", nil, nil)}
+	require.NoError(t, Register(c))
+	require.NoError(t, Register(c), "duplicate registration is ignored")
+	defer Unregister(c)
+
+	rec := httptest.NewRecorder()
+	Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	body, _ := io.ReadAll(rec.Body)
+	require.Contains(t, string(body), "sub2api_test_stub 7")
+	require.Contains(t, string(body), "go_goroutines")
+}
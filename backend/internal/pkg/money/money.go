@@ -0,0 +1,244 @@
+// Package money 提供计费使用的定点金额类型。
+//
+// 金额以 1e-10 USD 为最小单位保存在 int64 中，与 usage_logs 成本列 DECIMAL(20,10) 精度一致，
+// 单值上限约 9.2 亿美元。加减均为整数运算；与单价、倍率相乘时按十进制精确计算乘积，
+// 仅在结果处做一次四舍五入（远离零），避免 float64 在海量小额扣费上累积的分位级漂移。
+//
+// 数据库中金额列保持 DECIMAL 类型：Value 输出精确的十进制字符串，Scan 按十进制文本解析，
+// 因此 SQL 聚合与报表无需换算单位。
+package money
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Amount 定点金额，单位 1e-10 USD
+type Amount int64
+
+const (
+	// Scale 小数位数
+	Scale = 10
+	// Unit 最小单位（1e-10 USD）
+	Unit Amount = 1
+	// USD 1 美元
+	USD Amount = 10_000_000_000
+)
+
+var (
+	bigUSD   = big.NewInt(int64(USD))
+	bigTwo   = big.NewInt(2)
+	maxInt64 = big.NewInt(math.MaxInt64)
+	minInt64 = big.NewInt(math.MinInt64)
+)
+
+// FromFloat 将以 USD 为单位的浮点数转换为定点金额
+//
+// 按浮点数的最短十进制表示换算（0.1 视为 0.1 而非其二进制近似值），超出精度部分四舍五入。
+func FromFloat(f float64) Amount {
+	r, ok := ratFromFloat(f)
+	if !ok {
+		return 0
+	}
+	return fromRat(r)
+}
+
+// Parse 解析十进制金额字符串（USD），如 "12.5"、"-0.0000123"、"1e-6"
+func Parse(s string) (Amount, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, fmt.Errorf("money: empty amount")
+	}
+	r, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return 0, fmt.Errorf("money: invalid amount %q", s)
+	}
+	return fromRat(r), nil
+}
+
+// MustParse 解析金额字符串，失败时 panic（仅用于常量与测试）
+func MustParse(s string) Amount {
+	a, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return a
+}
+
+// Price 按单价（USD）与数量计算金额，精确计算乘积后仅舍入一次
+func Price(unitPrice float64, quantity float64) Amount {
+	price, ok := ratFromFloat(unitPrice)
+	if !ok {
+		return 0
+	}
+	qty, ok := ratFromFloat(quantity)
+	if !ok {
+		return 0
+	}
+	return fromRat(price.Mul(price, qty))
+}
+
+// Sum 求和
+func Sum(amounts ...Amount) Amount {
+	var total Amount
+	for _, a := range amounts {
+		total += a
+	}
+	return total
+}
+
+// Mul 乘以倍率，精确计算乘积后仅舍入一次
+func (a Amount) Mul(rate float64) Amount {
+	if a == 0 || rate == 1 {
+		return a
+	}
+	r, ok := ratFromFloat(rate)
+	if !ok {
+		return 0
+	}
+	return fromRat(r.Mul(r, new(big.Rat).SetFrac(big.NewInt(int64(a)), bigUSD)))
+}
+
+// Div 除以正整数（如按分钟、按百万字符计价时换算单价），结果仅舍入一次
+func (a Amount) Div(n int64) Amount {
+	if n <= 0 || n == 1 {
+		return a
+	}
+	return fromRat(new(big.Rat).SetFrac(big.NewInt(int64(a)), new(big.Int).Mul(bigUSD, big.NewInt(n))))
+}
+
+// Float64 转换为以 USD 为单位的浮点数（仅用于展示与兼容旧接口）
+func (a Amount) Float64() float64 {
+	f, _ := strconv.ParseFloat(a.String(), 64)
+	return f
+}
+
+// IsZero 是否为零
+func (a Amount) IsZero() bool { return a == 0 }
+
+// IsPositive 是否大于零
+func (a Amount) IsPositive() bool { return a > 0 }
+
+// IsNegative 是否小于零
+func (a Amount) IsNegative() bool { return a < 0 }
+
+// Neg 取相反数
+func (a Amount) Neg() Amount { return -a }
+
+// String 输出去除末尾零的十进制字符串（USD），如 "12.5"、"-0.0000123"、"0"
+func (a Amount) String() string {
+	if a == 0 {
+		return "0"
+	}
+	neg := a < 0
+	u := uint64(a)
+	if neg {
+		u = uint64(-a)
+	}
+	whole := u / uint64(USD)
+	frac := u % uint64(USD)
+
+	var b strings.Builder
+	if neg {
+		b.WriteByte('-')
+	}
+	b.WriteString(strconv.FormatUint(whole, 10))
+	if frac > 0 {
+		fracStr := strconv.FormatUint(frac, 10)
+		b.WriteByte('.')
+		b.WriteString(strings.Repeat("0", Scale-len(fracStr)))
+		b.WriteString(strings.TrimRight(fracStr, "0"))
+	}
+	return b.String()
+}
+
+// Value 实现 driver.Valuer：输出精确十进制字符串，由数据库按 DECIMAL 列类型解析
+func (a Amount) Value() (driver.Value, error) {
+	return a.String(), nil
+}
+
+// Scan 实现 sql.Scanner：DECIMAL 列以十进制文本返回，按文本精确解析
+func (a *Amount) Scan(src any) error {
+	switch v := src.(type) {
+	case nil:
+		*a = 0
+		return nil
+	case []byte:
+		parsed, err := Parse(string(v))
+		if err != nil {
+			return err
+		}
+		*a = parsed
+		return nil
+	case string:
+		parsed, err := Parse(v)
+		if err != nil {
+			return err
+		}
+		*a = parsed
+		return nil
+	case int64:
+		*a = Amount(v) * USD
+		return nil
+	case float64:
+		*a = FromFloat(v)
+		return nil
+	default:
+		return fmt.Errorf("money: cannot scan %T into Amount", src)
+	}
+}
+
+// MarshalJSON 以 JSON 数字输出（USD），保持与原 float64 字段相同的接口格式
+func (a Amount) MarshalJSON() ([]byte, error) {
+	return []byte(a.String()), nil
+}
+
+// UnmarshalJSON 接受 JSON 数字或数字字符串
+func (a *Amount) UnmarshalJSON(data []byte) error {
+	s := strings.TrimSpace(string(data))
+	if s == "null" {
+		return nil
+	}
+	s = strings.Trim(s, `"`)
+	parsed, err := Parse(s)
+	if err != nil {
+		return err
+	}
+	*a = parsed
+	return nil
+}
+
+func ratFromFloat(f float64) (*big.Rat, bool) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return nil, false
+	}
+	return new(big.Rat).SetString(strconv.FormatFloat(f, 'g', -1, 64))
+}
+
+// fromRat 将 USD 有理数换算为最小单位并四舍五入（远离零），超出 int64 范围时饱和
+func fromRat(r *big.Rat) Amount {
+	num := new(big.Int).Mul(r.Num(), bigUSD)
+	den := r.Denom()
+	quo, rem := new(big.Int).QuoRem(num, den, new(big.Int))
+	if rem.Sign() != 0 {
+		twice := new(big.Int).Mul(new(big.Int).Abs(rem), bigTwo)
+		if twice.Cmp(den) >= 0 {
+			if num.Sign() < 0 {
+				quo.Sub(quo, big.NewInt(1))
+			} else {
+				quo.Add(quo, big.NewInt(1))
+			}
+		}
+	}
+	if quo.Cmp(maxInt64) > 0 {
+		return Amount(math.MaxInt64)
+	}
+	if quo.Cmp(minInt64) < 0 {
+		return Amount(math.MinInt64)
+	}
+	return Amount(quo.Int64())
+}
@@ -0,0 +1,92 @@
+package money
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromFloatUsesShortestDecimal(t *testing.T) {
+	require.Equal(t, Amount(1_000_000_000), FromFloat(0.1))
+	require.Equal(t, Amount(3_000_000_000), FromFloat(0.1+0.2), "0.30000000000000004 rounds to 0.3")
+	require.Equal(t, Amount(-123_000), FromFloat(-0.0000123))
+	require.Equal(t, Amount(0), FromFloat(0.00000000004))
+	require.Equal(t, Amount(1), FromFloat(0.00000000005), "half rounds away from zero")
+	require.Equal(t, Amount(-1), FromFloat(-0.00000000005))
+}
+
+func TestPriceIsExactProduct(t *testing.T) {
+	// $3.75 / MTok × 1 token，浮点相乘会得到 3.7499999999999996e-06
+	require.Equal(t, MustParse("0.00000375"), Price(3.75e-6, 1))
+	require.Equal(t, MustParse("3.75"), Price(3.75e-6, 1_000_000))
+	require.Equal(t, MustParse("0.0000000375"), Price(0.0375e-6, 1))
+}
+
+func TestMulRoundsOnce(t *testing.T) {
+	a := MustParse("0.0000012345")
+	require.Equal(t, MustParse("0.0000018518"), a.Mul(1.5), "0.00000185175 rounds half away from zero")
+	require.Equal(t, a, a.Mul(1))
+	require.Equal(t, Amount(0), a.Mul(0))
+}
+
+func TestDivRoundsOnce(t *testing.T) {
+	// $0.006/min × 7s：先精确相乘再除以 60
+	require.Equal(t, MustParse("0.0007"), Price(0.006, 7).Div(60))
+	require.Equal(t, Amount(3), Amount(10).Div(3), "3.33.. units rounds down")
+	require.Equal(t, MustParse("-0.0000000001"), Amount(-1).Div(2), "half rounds away from zero")
+}
+
+func TestManyTinyChargesDoNotDrift(t *testing.T) {
+	charge := Price(0.25e-6, 37).Mul(1.1)
+	var total Amount
+	floatTotal := 0.0
+	for i := 0; i < 1_000_000; i++ {
+		total += charge
+		floatTotal += 0.25e-6 * 37 * 1.1
+	}
+	require.Equal(t, charge*1_000_000, total)
+	require.Equal(t, "10.175", total.String())
+	require.NotEqual(t, "10.175", FromFloat(floatTotal).String(), "float accumulation drifts")
+}
+
+func TestStringAndParseRoundTrip(t *testing.T) {
+	for _, s := range []string{"0", "12.5", "-0.0000123", "1", "922337203.6854775807", "0.0000000001"} {
+		a, err := Parse(s)
+		require.NoError(t, err)
+		require.Equal(t, s, a.String())
+	}
+	_, err := Parse("abc")
+	require.Error(t, err)
+}
+
+func TestSQLValueAndScan(t *testing.T) {
+	v, err := MustParse("12.3456789012").Value()
+	require.NoError(t, err)
+	require.Equal(t, "12.3456789012", v)
+
+	var a Amount
+	require.NoError(t, a.Scan([]byte("0.0000012300")))
+	require.Equal(t, MustParse("0.00000123"), a)
+	require.NoError(t, a.Scan(int64(3)))
+	require.Equal(t, 3*USD, a)
+	require.NoError(t, a.Scan(nil))
+	require.Zero(t, a)
+}
+
+func TestJSONKeepsNumberFormat(t *testing.T) {
+	payload := struct {
+		Balance Amount `json:"balance"`
+	}{Balance: MustParse("10.25")}
+	raw, err := json.Marshal(payload)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"balance":10.25}`, string(raw))
+
+	var decoded struct {
+		Balance Amount `json:"balance"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(`{"balance":0.1}`), &decoded))
+	require.Equal(t, MustParse("0.1"), decoded.Balance)
+	require.NoError(t, json.Unmarshal([]byte(`{"balance":"0.2"}`), &decoded))
+	require.Equal(t, MustParse("0.2"), decoded.Balance)
+}
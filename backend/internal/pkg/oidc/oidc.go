@@ -0,0 +1,259 @@
+// Package oidc 提供 OpenID Connect 登录所需的发现文档、JWKS 解析与 ID Token 校验。
+package oidc
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// DiscoveryPath 发现文档相对 issuer 的路径
+const DiscoveryPath = "/.well-known/openid-configuration"
+
+// DefaultLeeway ID Token 时间类声明（exp/iat/nbf）允许的时钟偏差
+const DefaultLeeway = 2 * time.Minute
+
+var (
+	// ErrKeyNotFound JWKS 中没有与 ID Token 头部 kid 匹配的公钥（通常意味着 IdP 已轮换密钥，需要刷新 JWKS）
+	ErrKeyNotFound = errors.New("oidc: signing key not found")
+	// ErrNonceMismatch ID Token 的 nonce 与登录发起时生成的不一致
+	ErrNonceMismatch = errors.New("oidc: nonce mismatch")
+	// ErrAuthorizedPartyMismatch 多 audience 时 azp 不是本客户端
+	ErrAuthorizedPartyMismatch = errors.New("oidc: authorized party mismatch")
+)
+
+// 只接受非对称签名算法：HS* 会让知道 client_secret 的任何一方伪造 ID Token，none 则完全不签名
+var supportedSigningMethods = []string{
+	jwt.SigningMethodRS256.Name, jwt.SigningMethodRS384.Name, jwt.SigningMethodRS512.Name,
+	jwt.SigningMethodPS256.Name, jwt.SigningMethodPS384.Name, jwt.SigningMethodPS512.Name,
+	jwt.SigningMethodES256.Name, jwt.SigningMethodES384.Name, jwt.SigningMethodES512.Name,
+}
+
+// Discovery OpenID Provider 发现文档中登录流程用到的字段
+type Discovery struct {
+	Issuer                        string   `json:"issuer"`
+	AuthorizationEndpoint         string   `json:"authorization_endpoint"`
+	TokenEndpoint                 string   `json:"token_endpoint"`
+	UserInfoEndpoint              string   `json:"userinfo_endpoint"`
+	JWKSURI                       string   `json:"jwks_uri"`
+	CodeChallengeMethodsSupported []string `json:"code_challenge_methods_supported,omitempty"`
+}
+
+// DiscoveryURL 返回 issuer 的发现文档地址
+func DiscoveryURL(issuer string) string {
+	return strings.TrimRight(strings.TrimSpace(issuer), "/") + DiscoveryPath
+}
+
+// ParseDiscovery 解析发现文档，并校验其 issuer 与配置一致（防止混用其他 IdP 的文档）
+func ParseDiscovery(data []byte, expectedIssuer string) (*Discovery, error) {
+	var d Discovery
+	if err := json.Unmarshal(data, &d); err != nil {
+		return nil, fmt.Errorf("oidc: decode discovery document: %w", err)
+	}
+	if !sameIssuer(d.Issuer, expectedIssuer) {
+		return nil, fmt.Errorf("oidc: discovery issuer %q does not match configured issuer %q", d.Issuer, expectedIssuer)
+	}
+	if d.AuthorizationEndpoint == "" || d.TokenEndpoint == "" || d.JWKSURI == "" {
+		return nil, errors.New("oidc: discovery document missing authorization_endpoint/token_endpoint/jwks_uri")
+	}
+	return &d, nil
+}
+
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+type publicKey struct {
+	kid string
+	alg string
+	key crypto.PublicKey
+}
+
+// KeySet 已解析的 JWKS 公钥集合
+type KeySet struct {
+	keys []publicKey
+}
+
+// ParseKeySet 解析 JWKS 文档，忽略非签名用途与不支持类型的密钥
+func ParseKeySet(data []byte) (*KeySet, error) {
+	var doc struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("oidc: decode jwks: %w", err)
+	}
+	ks := &KeySet{}
+	for _, jwk := range doc.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, err := jwk.publicKey()
+		if err != nil {
+			continue
+		}
+		ks.keys = append(ks.keys, publicKey{kid: jwk.Kid, alg: jwk.Alg, key: key})
+	}
+	if len(ks.keys) == 0 {
+		return nil, errors.New("oidc: jwks contains no usable signing keys")
+	}
+	return ks, nil
+}
+
+func (k jsonWebKey) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		if !e.IsInt64() || e.Int64() < 3 || n.BitLen() < 2048 {
+			return nil, errors.New("oidc: weak rsa key")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("oidc: unsupported curve %q", k.Crv)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		if !curve.IsOnCurve(x, y) {
+			return nil, errors.New("oidc: ec point is not on curve")
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	}
+	return nil, fmt.Errorf("oidc: unsupported key type %q", k.Kty)
+}
+
+// lookup 按 kid 查找公钥；ID Token 未携带 kid 时仅在 JWKS 只有一把密钥时使用该密钥
+func (ks *KeySet) lookup(kid, alg string) (crypto.PublicKey, bool) {
+	if ks == nil {
+		return nil, false
+	}
+	if kid == "" {
+		if len(ks.keys) == 1 && (ks.keys[0].alg == "" || ks.keys[0].alg == alg) {
+			return ks.keys[0].key, true
+		}
+		return nil, false
+	}
+	for _, k := range ks.keys {
+		if k.kid == kid && (k.alg == "" || k.alg == alg) {
+			return k.key, true
+		}
+	}
+	return nil, false
+}
+
+// VerifyOptions ID Token 校验参数
+type VerifyOptions struct {
+	Issuer   string
+	ClientID string
+	// Nonce 非空时要求 ID Token 的 nonce 声明与之相等
+	Nonce  string
+	Now    func() time.Time
+	Leeway time.Duration
+}
+
+// VerifyIDToken 校验 ID Token 的签名、iss、aud、azp、exp/iat/nbf 与 nonce，返回全部声明。
+// 找不到匹配公钥时返回的错误满足 errors.Is(err, ErrKeyNotFound)。
+func VerifyIDToken(raw string, keys *KeySet, opts VerifyOptions) (map[string]any, error) {
+	leeway := opts.Leeway
+	if leeway <= 0 {
+		leeway = DefaultLeeway
+	}
+	parserOpts := []jwt.ParserOption{
+		jwt.WithValidMethods(supportedSigningMethods),
+		jwt.WithAudience(opts.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(leeway),
+	}
+	if opts.Now != nil {
+		parserOpts = append(parserOpts, jwt.WithTimeFunc(opts.Now))
+	}
+
+	claims := jwt.MapClaims{}
+	_, err := jwt.NewParser(parserOpts...).ParseWithClaims(strings.TrimSpace(raw), claims, func(token *jwt.Token) (any, error) {
+		kid, _ := token.Header["kid"].(string)
+		key, ok := keys.lookup(kid, token.Method.Alg())
+		if !ok {
+			return nil, ErrKeyNotFound
+		}
+		return key, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("oidc: verify id token: %w", err)
+	}
+
+	// 部分 IdP（如 Azure AD 多租户）的 iss 带或不带结尾斜杠，这里按规范化后比较
+	iss, _ := claims["iss"].(string)
+	if !sameIssuer(iss, opts.Issuer) {
+		return nil, fmt.Errorf("oidc: id token issuer %q does not match %q", iss, opts.Issuer)
+	}
+	if aud, err := claims.GetAudience(); err == nil && len(aud) > 1 {
+		if azp, _ := claims["azp"].(string); azp != opts.ClientID {
+			return nil, ErrAuthorizedPartyMismatch
+		}
+	}
+	if opts.Nonce != "" {
+		if nonce, _ := claims["nonce"].(string); nonce != opts.Nonce {
+			return nil, ErrNonceMismatch
+		}
+	}
+	if sub, _ := claims["sub"].(string); strings.TrimSpace(sub) == "" {
+		return nil, errors.New("oidc: id token missing sub claim")
+	}
+	return claims, nil
+}
+
+func sameIssuer(a, b string) bool {
+	a = strings.TrimRight(strings.TrimSpace(a), "/")
+	b = strings.TrimRight(strings.TrimSpace(b), "/")
+	return a != "" && a == b
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+	if err != nil {
+		return nil, fmt.Errorf("oidc: decode key component: %w", err)
+	}
+	if len(raw) == 0 {
+		return nil, errors.New("oidc: empty key component")
+	}
+	return new(big.Int).SetBytes(raw), nil
+}
@@ -0,0 +1,166 @@
+package oidc
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testIssuer   = "https://idp.example.com/realms/corp"
+	testClientID = "sub2api"
+)
+
+func b64(b []byte) string { return base64.RawURLEncoding.EncodeToString(b) }
+
+func rsaJWKS(t *testing.T, kid string, key *rsa.PrivateKey) []byte {
+	t.Helper()
+	doc := map[string]any{"keys": []map[string]string{{
+		"kty": "RSA",
+		"kid": kid,
+		"use": "sig",
+		"alg": "RS256",
+		"n":   b64(key.N.Bytes()),
+		"e":   b64(big.NewInt(int64(key.E)).Bytes()),
+	}}}
+	data, err := json.Marshal(doc)
+	require.NoError(t, err)
+	return data
+}
+
+func signRS256(t *testing.T, kid string, key *rsa.PrivateKey, claims jwt.MapClaims) string {
+	t.Helper()
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = kid
+	raw, err := token.SignedString(key)
+	require.NoError(t, err)
+	return raw
+}
+
+func baseClaims(now time.Time) jwt.MapClaims {
+	return jwt.MapClaims{
+		"iss":   testIssuer,
+		"aud":   testClientID,
+		"sub":   "user-123",
+		"email": "alice@corp.example.com",
+		"nonce": "n-1",
+		"iat":   now.Unix(),
+		"exp":   now.Add(5 * time.Minute).Unix(),
+	}
+}
+
+func TestVerifyIDToken_RSA(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ks, err := ParseKeySet(rsaJWKS(t, "k1", key))
+	require.NoError(t, err)
+
+	now := time.Now()
+	opts := VerifyOptions{Issuer: testIssuer + "/", ClientID: testClientID, Nonce: "n-1"}
+
+	claims, err := VerifyIDToken(signRS256(t, "k1", key, baseClaims(now)), ks, opts)
+	require.NoError(t, err)
+	require.Equal(t, "user-123", claims["sub"])
+	require.Equal(t, "alice@corp.example.com", claims["email"])
+
+	// nonce 不一致
+	_, err = VerifyIDToken(signRS256(t, "k1", key, baseClaims(now)), ks, VerifyOptions{Issuer: testIssuer, ClientID: testClientID, Nonce: "other"})
+	require.ErrorIs(t, err, ErrNonceMismatch)
+
+	// 未知 kid：调用方应刷新 JWKS
+	_, err = VerifyIDToken(signRS256(t, "k2", key, baseClaims(now)), ks, opts)
+	require.ErrorIs(t, err, ErrKeyNotFound)
+
+	// audience 不是本客户端
+	wrongAud := baseClaims(now)
+	wrongAud["aud"] = "someone-else"
+	_, err = VerifyIDToken(signRS256(t, "k1", key, wrongAud), ks, opts)
+	require.Error(t, err)
+
+	// 多 audience 时必须 azp == client_id
+	multiAud := baseClaims(now)
+	multiAud["aud"] = []string{testClientID, "other"}
+	multiAud["azp"] = "other"
+	_, err = VerifyIDToken(signRS256(t, "k1", key, multiAud), ks, opts)
+	require.ErrorIs(t, err, ErrAuthorizedPartyMismatch)
+
+	// 其他 issuer
+	wrongIss := baseClaims(now)
+	wrongIss["iss"] = "https://evil.example.com"
+	_, err = VerifyIDToken(signRS256(t, "k1", key, wrongIss), ks, opts)
+	require.Error(t, err)
+
+	// 已过期（超出时钟偏差）
+	expired := baseClaims(now.Add(-time.Hour))
+	_, err = VerifyIDToken(signRS256(t, "k1", key, expired), ks, opts)
+	require.True(t, errors.Is(err, jwt.ErrTokenExpired))
+}
+
+func TestVerifyIDToken_RejectsSymmetricAlgorithms(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ks, err := ParseKeySet(rsaJWKS(t, "k1", key))
+	require.NoError(t, err)
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, baseClaims(time.Now()))
+	token.Header["kid"] = "k1"
+	raw, err := token.SignedString([]byte("client-secret"))
+	require.NoError(t, err)
+
+	_, err = VerifyIDToken(raw, ks, VerifyOptions{Issuer: testIssuer, ClientID: testClientID})
+	require.Error(t, err)
+}
+
+func TestVerifyIDToken_ECDSAWithoutKid(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	jwks, err := json.Marshal(map[string]any{"keys": []map[string]string{{
+		"kty": "EC",
+		"crv": "P-256",
+		"x":   b64(key.X.FillBytes(make([]byte, 32))),
+		"y":   b64(key.Y.FillBytes(make([]byte, 32))),
+	}}})
+	require.NoError(t, err)
+	ks, err := ParseKeySet(jwks)
+	require.NoError(t, err)
+
+	raw, err := jwt.NewWithClaims(jwt.SigningMethodES256, baseClaims(time.Now())).SignedString(key)
+	require.NoError(t, err)
+
+	claims, err := VerifyIDToken(raw, ks, VerifyOptions{Issuer: testIssuer, ClientID: testClientID})
+	require.NoError(t, err)
+	require.Equal(t, "user-123", claims["sub"])
+}
+
+func TestParseKeySet_SkipsUnusableKeys(t *testing.T) {
+	_, err := ParseKeySet([]byte(`{"keys":[{"kty":"oct","k":"c2VjcmV0"},{"kty":"RSA","use":"enc","n":"AQAB","e":"AQAB"}]}`))
+	require.Error(t, err)
+}
+
+func TestParseDiscovery(t *testing.T) {
+	doc := []byte(`{
+		"issuer": "https://idp.example.com/realms/corp",
+		"authorization_endpoint": "https://idp.example.com/auth",
+		"token_endpoint": "https://idp.example.com/token",
+		"userinfo_endpoint": "https://idp.example.com/userinfo",
+		"jwks_uri": "https://idp.example.com/certs"
+	}`)
+
+	d, err := ParseDiscovery(doc, "https://idp.example.com/realms/corp/")
+	require.NoError(t, err)
+	require.Equal(t, "https://idp.example.com/token", d.TokenEndpoint)
+	require.Equal(t, "https://idp.example.com/realms/corp/.well-known/openid-configuration", DiscoveryURL("https://idp.example.com/realms/corp/"))
+
+	_, err = ParseDiscovery(doc, "https://other.example.com")
+	require.Error(t, err)
+}
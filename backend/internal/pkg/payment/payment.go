@@ -0,0 +1,312 @@
+// Package payment 提供各支付渠道的签名、验签与回调报文解密工具。
+//
+// 本包只做纯计算，不发起网络请求；下单、查单、退款等 HTTP 调用由 repository 层的渠道客户端完成。
+package payment
+
+import (
+	"crypto"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/subtle"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrInvalidSignature 回调签名校验失败
+	ErrInvalidSignature = errors.New("payment: invalid signature")
+	// ErrSignatureExpired 回调时间戳超出允许的偏差（防重放）
+	ErrSignatureExpired = errors.New("payment: signature timestamp out of tolerance")
+)
+
+// SortedQuery 按参数名 ASCII 升序拼接 k=v&k=v（值不做 URL 编码），跳过空值与 exclude 中的字段。
+// 易支付与支付宝的待签名串均采用此格式。
+func SortedQuery(params url.Values, exclude ...string) string {
+	keys := make([]string, 0, len(params))
+	for k := range params {
+		skip := false
+		for _, ex := range exclude {
+			if k == ex {
+				skip = true
+				break
+			}
+		}
+		if skip || params.Get(k) == "" {
+			continue
+		}
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	for i, k := range keys {
+		if i > 0 {
+			b.WriteByte('&')
+		}
+		b.WriteString(k)
+		b.WriteByte('=')
+		b.WriteString(params.Get(k))
+	}
+	return b.String()
+}
+
+// EPaySign 计算易支付（彩虹易支付等兼容实现）MD5 签名：md5(排序参数串 + 商户密钥)，小写十六进制。
+func EPaySign(params url.Values, key string) string {
+	sum := md5.Sum([]byte(SortedQuery(params, "sign", "sign_type") + key))
+	return hex.EncodeToString(sum[:])
+}
+
+// EPayVerify 校验易支付回调签名
+func EPayVerify(params url.Values, key string) error {
+	expected := EPaySign(params, key)
+	got := strings.ToLower(strings.TrimSpace(params.Get("sign")))
+	if got == "" || subtle.ConstantTimeCompare([]byte(expected), []byte(got)) != 1 {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// ParseRSAPrivateKey 解析 RSA 私钥，支持 PEM（PKCS#1 / PKCS#8）或支付宝开放平台导出的裸 Base64。
+func ParseRSAPrivateKey(raw string) (*rsa.PrivateKey, error) {
+	der, err := decodeKeyMaterial(raw)
+	if err != nil {
+		return nil, err
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(der); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return nil, fmt.Errorf("payment: parse rsa private key: %w", err)
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("payment: private key is not RSA")
+	}
+	return key, nil
+}
+
+// ParseRSAPublicKey 解析 RSA 公钥，支持 PEM（PKIX / PKCS#1 / 证书）或裸 Base64。
+func ParseRSAPublicKey(raw string) (*rsa.PublicKey, error) {
+	der, err := decodeKeyMaterial(raw)
+	if err != nil {
+		return nil, err
+	}
+	if parsed, err := x509.ParsePKIXPublicKey(der); err == nil {
+		if key, ok := parsed.(*rsa.PublicKey); ok {
+			return key, nil
+		}
+		return nil, errors.New("payment: public key is not RSA")
+	}
+	if key, err := x509.ParsePKCS1PublicKey(der); err == nil {
+		return key, nil
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, fmt.Errorf("payment: parse rsa public key: %w", err)
+	}
+	key, ok := cert.PublicKey.(*rsa.PublicKey)
+	if !ok {
+		return nil, errors.New("payment: certificate key is not RSA")
+	}
+	return key, nil
+}
+
+func decodeKeyMaterial(raw string) ([]byte, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil, errors.New("payment: empty key")
+	}
+	if block, _ := pem.Decode([]byte(raw)); block != nil {
+		return block.Bytes, nil
+	}
+	der, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(raw), ""))
+	if err != nil {
+		return nil, fmt.Errorf("payment: decode key: %w", err)
+	}
+	return der, nil
+}
+
+// RSASignSHA256 使用 SHA256WithRSA（PKCS#1 v1.5）签名，返回标准 Base64。支付宝 RSA2 与微信支付 v3 均使用该算法。
+func RSASignSHA256(key *rsa.PrivateKey, content string) (string, error) {
+	digest := sha256.Sum256([]byte(content))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("payment: rsa sign: %w", err)
+	}
+	return base64.StdEncoding.EncodeToString(sig), nil
+}
+
+// RSAVerifySHA256 校验 SHA256WithRSA 签名（标准 Base64）
+func RSAVerifySHA256(key *rsa.PublicKey, content, signature string) error {
+	sig, err := base64.StdEncoding.DecodeString(strings.TrimSpace(signature))
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	digest := sha256.Sum256([]byte(content))
+	if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], sig); err != nil {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// AlipayVerify 校验支付宝异步通知签名（sign 与 sign_type 不参与签名）
+func AlipayVerify(params url.Values, key *rsa.PublicKey) error {
+	return RSAVerifySHA256(key, SortedQuery(params, "sign", "sign_type"), params.Get("sign"))
+}
+
+// AlipaySign 为支付宝开放平台请求参数签名（仅 sign 不参与签名）
+func AlipaySign(params url.Values, key *rsa.PrivateKey) (string, error) {
+	return RSASignSHA256(key, SortedQuery(params, "sign"))
+}
+
+// WechatSignMessage 拼接微信支付 v3 待签名串：每个字段后追加换行符。
+// 请求签名为 (method, url, timestamp, nonce, body)，应答/回调验签为 (timestamp, nonce, body)。
+func WechatSignMessage(parts ...string) string {
+	var b strings.Builder
+	for _, p := range parts {
+		b.WriteString(p)
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// WechatAuthorization 构造微信支付 v3 请求的 Authorization 头
+func WechatAuthorization(mchID, serialNo, nonce string, timestamp int64, signature string) string {
+	return fmt.Sprintf(`WECHATPAY2-SHA256-RSA2048 mchid="%s",nonce_str="%s",signature="%s",timestamp="%d",serial_no="%s"`,
+		mchID, nonce, signature, timestamp, serialNo)
+}
+
+// WechatVerify 校验微信支付 v3 回调或应答签名，tolerance > 0 时同时检查时间戳偏差。
+func WechatVerify(key *rsa.PublicKey, timestamp, nonce string, body []byte, signature string, tolerance time.Duration, now time.Time) error {
+	if tolerance > 0 {
+		ts, err := strconv.ParseInt(strings.TrimSpace(timestamp), 10, 64)
+		if err != nil {
+			return ErrInvalidSignature
+		}
+		if d := now.Sub(time.Unix(ts, 0)); d > tolerance || d < -tolerance {
+			return ErrSignatureExpired
+		}
+	}
+	return RSAVerifySHA256(key, WechatSignMessage(timestamp, nonce, string(body)), signature)
+}
+
+// WechatDecryptResource 使用 APIv3 密钥（AES-256-GCM）解密回调通知中的 resource 字段
+func WechatDecryptResource(apiV3Key, associatedData, nonce, ciphertext string) ([]byte, error) {
+	if len(apiV3Key) != 32 {
+		return nil, errors.New("payment: wechat api v3 key must be 32 bytes")
+	}
+	data, err := base64.StdEncoding.DecodeString(ciphertext)
+	if err != nil {
+		return nil, fmt.Errorf("payment: decode ciphertext: %w", err)
+	}
+	block, err := aes.NewCipher([]byte(apiV3Key))
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCMWithNonceSize(block, len(nonce))
+	if err != nil {
+		return nil, err
+	}
+	plain, err := gcm.Open(nil, []byte(nonce), data, []byte(associatedData))
+	if err != nil {
+		return nil, ErrInvalidSignature
+	}
+	return plain, nil
+}
+
+// StripeSignature 计算 Stripe Webhook 签名头（t=...,v1=...），用于测试与本地联调。
+func StripeSignature(payload []byte, secret string, timestamp time.Time) string {
+	ts := strconv.FormatInt(timestamp.Unix(), 10)
+	return "t=" + ts + ",v1=" + stripeHMAC(payload, secret, ts)
+}
+
+// StripeVerify 校验 Stripe-Signature 头：HMAC-SHA256(secret, "t.payload")，任一 v1 匹配即通过。
+func StripeVerify(payload []byte, header, secret string, tolerance time.Duration, now time.Time) error {
+	var ts string
+	var sigs []string
+	for _, part := range strings.Split(header, ",") {
+		k, v, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			continue
+		}
+		switch k {
+		case "t":
+			ts = v
+		case "v1":
+			sigs = append(sigs, v)
+		}
+	}
+	if ts == "" || len(sigs) == 0 {
+		return ErrInvalidSignature
+	}
+	unix, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	if tolerance > 0 {
+		if d := now.Sub(time.Unix(unix, 0)); d > tolerance || d < -tolerance {
+			return ErrSignatureExpired
+		}
+	}
+	expected := stripeHMAC(payload, secret, ts)
+	for _, sig := range sigs {
+		if hmac.Equal([]byte(expected), []byte(sig)) {
+			return nil
+		}
+	}
+	return ErrInvalidSignature
+}
+
+func stripeHMAC(payload []byte, secret, ts string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(ts))
+	mac.Write([]byte{'.'})
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// FormatYuan 将分转换为两位小数的元字符串（如 1234 -> "12.34"）
+func FormatYuan(cents int64) string {
+	sign := ""
+	if cents < 0 {
+		sign = "-"
+		cents = -cents
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, cents/100, cents%100)
+}
+
+// ParseYuan 将元字符串解析为分，最多两位小数
+func ParseYuan(s string) (int64, error) {
+	s = strings.TrimSpace(s)
+	whole, frac, _ := strings.Cut(s, ".")
+	if whole == "" || len(frac) > 2 {
+		return 0, fmt.Errorf("payment: invalid amount %q", s)
+	}
+	w, err := strconv.ParseInt(whole, 10, 64)
+	if err != nil || w < 0 {
+		return 0, fmt.Errorf("payment: invalid amount %q", s)
+	}
+	for len(frac) < 2 {
+		frac += "0"
+	}
+	f, err := strconv.ParseInt(frac, 10, 64)
+	if err != nil || f < 0 {
+		return 0, fmt.Errorf("payment: invalid amount %q", s)
+	}
+	return w*100 + f, nil
+}
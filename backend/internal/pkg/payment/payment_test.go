@@ -0,0 +1,141 @@
+package payment
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSortedQuery(t *testing.T) {
+	params := url.Values{
+		"out_trade_no": {"P123"},
+		"money":        {"1.00"},
+		"sign":         {"ignored"},
+		"sign_type":    {"MD5"},
+		"empty":        {""},
+		"name":         {"VIP 月卡"},
+	}
+	require.Equal(t, "money=1.00&name=VIP 月卡&out_trade_no=P123", SortedQuery(params, "sign", "sign_type"))
+}
+
+func TestEPaySignAndVerify(t *testing.T) {
+	params := url.Values{
+		"pid":          {"1001"},
+		"trade_no":     {"2024010112345"},
+		"out_trade_no": {"P123"},
+		"type":         {"alipay"},
+		"name":         {"top-up"},
+		"money":        {"10.00"},
+		"trade_status": {"TRADE_SUCCESS"},
+		"sign_type":    {"MD5"},
+	}
+	params.Set("sign", EPaySign(params, "merchant-key"))
+	require.Len(t, params.Get("sign"), 32)
+	require.NoError(t, EPayVerify(params, "merchant-key"))
+	require.ErrorIs(t, EPayVerify(params, "other-key"), ErrInvalidSignature)
+
+	params.Set("money", "100.00")
+	require.ErrorIs(t, EPayVerify(params, "merchant-key"), ErrInvalidSignature)
+}
+
+func TestRSAKeysAndAlipayVerify(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	// 支付宝开放平台导出的密钥为裸 Base64（PKCS#8 / PKIX）
+	pkcs8, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	priv, err := ParseRSAPrivateKey(base64.StdEncoding.EncodeToString(pkcs8))
+	require.NoError(t, err)
+	pkcs1PEM := string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}))
+	_, err = ParseRSAPrivateKey(pkcs1PEM)
+	require.NoError(t, err)
+
+	pkix, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+	pub, err := ParseRSAPublicKey(base64.StdEncoding.EncodeToString(pkix))
+	require.NoError(t, err)
+
+	params := url.Values{
+		"out_trade_no": {"P123"},
+		"trade_no":     {"2024010122001"},
+		"trade_status": {"TRADE_SUCCESS"},
+		"total_amount": {"10.00"},
+		"sign_type":    {"RSA2"},
+	}
+	sig, err := RSASignSHA256(priv, SortedQuery(params, "sign", "sign_type"))
+	require.NoError(t, err)
+	params.Set("sign", sig)
+	require.NoError(t, AlipayVerify(params, pub))
+
+	params.Set("total_amount", "0.01")
+	require.ErrorIs(t, AlipayVerify(params, pub), ErrInvalidSignature)
+}
+
+func TestWechatVerifyAndDecrypt(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	now := time.Unix(1_700_000_000, 0)
+	body := []byte(`{"id":"evt-1"}`)
+	sig, err := RSASignSHA256(key, WechatSignMessage("1700000000", "nonce-1", string(body)))
+	require.NoError(t, err)
+
+	require.NoError(t, WechatVerify(&key.PublicKey, "1700000000", "nonce-1", body, sig, 5*time.Minute, now))
+	require.ErrorIs(t, WechatVerify(&key.PublicKey, "1700000000", "nonce-2", body, sig, 5*time.Minute, now), ErrInvalidSignature)
+	require.ErrorIs(t, WechatVerify(&key.PublicKey, "1700000000", "nonce-1", body, sig, 5*time.Minute, now.Add(time.Hour)), ErrSignatureExpired)
+
+	apiV3Key := "0123456789abcdef0123456789abcdef"
+	block, err := aes.NewCipher([]byte(apiV3Key))
+	require.NoError(t, err)
+	gcm, err := cipher.NewGCM(block)
+	require.NoError(t, err)
+	nonce := "abcdefghijkl"
+	ciphertext := base64.StdEncoding.EncodeToString(gcm.Seal(nil, []byte(nonce), []byte(`{"trade_state":"SUCCESS"}`), []byte("transaction")))
+
+	plain, err := WechatDecryptResource(apiV3Key, "transaction", nonce, ciphertext)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"trade_state":"SUCCESS"}`, string(plain))
+
+	_, err = WechatDecryptResource(apiV3Key, "tampered", nonce, ciphertext)
+	require.ErrorIs(t, err, ErrInvalidSignature)
+}
+
+func TestStripeVerify(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	payload := []byte(`{"id":"evt_1","type":"checkout.session.completed"}`)
+	header := StripeSignature(payload, "whsec_test", now)
+
+	require.NoError(t, StripeVerify(payload, header, "whsec_test", 5*time.Minute, now))
+	// 轮换密钥期间 Stripe 会同时携带多个 v1 签名
+	require.NoError(t, StripeVerify(payload, "v1=deadbeef,"+header, "whsec_test", 5*time.Minute, now))
+	require.ErrorIs(t, StripeVerify(payload, header, "whsec_other", 5*time.Minute, now), ErrInvalidSignature)
+	require.ErrorIs(t, StripeVerify([]byte(`{}`), header, "whsec_test", 5*time.Minute, now), ErrInvalidSignature)
+	require.ErrorIs(t, StripeVerify(payload, header, "whsec_test", 5*time.Minute, now.Add(10*time.Minute)), ErrSignatureExpired)
+	require.ErrorIs(t, StripeVerify(payload, "garbage", "whsec_test", 5*time.Minute, now), ErrInvalidSignature)
+}
+
+func TestYuanConversion(t *testing.T) {
+	require.Equal(t, "12.34", FormatYuan(1234))
+	require.Equal(t, "0.05", FormatYuan(5))
+	require.Equal(t, "-1.00", FormatYuan(-100))
+
+	for in, want := range map[string]int64{"12.34": 1234, "0.5": 50, "7": 700, "0.01": 1} {
+		got, err := ParseYuan(in)
+		require.NoError(t, err, in)
+		require.Equal(t, want, got, in)
+	}
+	for _, in := range []string{"", "1.234", "-1", "abc", ".5"} {
+		_, err := ParseYuan(in)
+		require.Error(t, err, in)
+	}
+}
@@ -0,0 +1,218 @@
+// Package tracing 封装 OpenTelemetry 链路追踪的初始化与常用 Span 辅助函数。
+//
+// 未启用时全局 TracerProvider 保持 OpenTelemetry 默认的 noop 实现，Start 返回的 Span
+// 不会记录任何数据，调用方无需额外判断。
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/config"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// InstrumentationName 本服务创建 Span 使用的 Tracer 名称
+const InstrumentationName = "github.com/Wei-Shaw/sub2api"
+
+const defaultServiceName = "sub2api"
+
+var enabled atomic.Bool
+
+// Options 链路追踪初始化参数
+type Options struct {
+	Enabled       bool
+	Exporter      string
+	Endpoint      string
+	Insecure      bool
+	Headers       map[string]string
+	SampleRatio   float64
+	ServiceName   string
+	Version       string
+	ExportTimeout time.Duration
+}
+
+// OptionsFromConfig 从应用配置构造初始化参数；service.name 为空时沿用日志配置中的服务名
+func OptionsFromConfig(cfg *config.Config, version string) Options {
+	serviceName := strings.TrimSpace(cfg.Tracing.ServiceName)
+	if serviceName == "" {
+		serviceName = strings.TrimSpace(cfg.Log.ServiceName)
+	}
+	return Options{
+		Enabled:       cfg.Tracing.Enabled,
+		Exporter:      cfg.Tracing.Exporter,
+		Endpoint:      cfg.Tracing.Endpoint,
+		Insecure:      cfg.Tracing.Insecure,
+		Headers:       cfg.Tracing.Headers,
+		SampleRatio:   cfg.Tracing.SampleRatio,
+		ServiceName:   serviceName,
+		Version:       version,
+		ExportTimeout: time.Duration(cfg.Tracing.ExportTimeoutSeconds) * time.Second,
+	}
+}
+
+// Init 初始化全局 TracerProvider 与 W3C TraceContext/Baggage 传播器，返回用于刷新并关闭导出器的函数。
+// 未启用时仅设置传播器并返回空操作的关闭函数。
+func Init(ctx context.Context, opts Options) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	if !opts.Enabled {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	exporter, err := newExporter(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(newResource(opts)),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(opts.SampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+	enabled.Store(true)
+
+	return func(ctx context.Context) error {
+		enabled.Store(false)
+		return provider.Shutdown(ctx)
+	}, nil
+}
+
+func newExporter(ctx context.Context, opts Options) (*otlptrace.Exporter, error) {
+	endpoint := strings.TrimSpace(opts.Endpoint)
+	hasScheme := strings.Contains(endpoint, "://")
+	switch opts.Exporter {
+	case config.TracingExporterOTLPGRPC:
+		clientOpts := []otlptracegrpc.Option{otlptracegrpc.WithHeaders(opts.Headers)}
+		if hasScheme {
+			clientOpts = append(clientOpts, otlptracegrpc.WithEndpointURL(endpoint))
+		} else {
+			clientOpts = append(clientOpts, otlptracegrpc.WithEndpoint(endpoint))
+		}
+		if opts.Insecure {
+			clientOpts = append(clientOpts, otlptracegrpc.WithInsecure())
+		}
+		if opts.ExportTimeout > 0 {
+			clientOpts = append(clientOpts, otlptracegrpc.WithTimeout(opts.ExportTimeout))
+		}
+		return otlptracegrpc.New(ctx, clientOpts...)
+	case config.TracingExporterOTLPHTTP:
+		clientOpts := []otlptracehttp.Option{otlptracehttp.WithHeaders(opts.Headers)}
+		if hasScheme {
+			clientOpts = append(clientOpts, otlptracehttp.WithEndpointURL(endpoint))
+		} else {
+			clientOpts = append(clientOpts, otlptracehttp.WithEndpoint(endpoint))
+		}
+		if opts.Insecure {
+			clientOpts = append(clientOpts, otlptracehttp.WithInsecure())
+		}
+		if opts.ExportTimeout > 0 {
+			clientOpts = append(clientOpts, otlptracehttp.WithTimeout(opts.ExportTimeout))
+		}
+		return otlptracehttp.New(ctx, clientOpts...)
+	default:
+		return nil, fmt.Errorf("unsupported tracing exporter: %q", opts.Exporter)
+	}
+}
+
+func newResource(opts Options) *resource.Resource {
+	serviceName := opts.ServiceName
+	if serviceName == "" {
+		serviceName = defaultServiceName
+	}
+	attrs := []attribute.KeyValue{attribute.String("service.name", serviceName)}
+	if opts.Version != "" {
+		attrs = append(attrs, attribute.String("service.version", opts.Version))
+	}
+	return resource.NewSchemaless(attrs...)
+}
+
+// UseTracerProvider 以指定的 TracerProvider 启用追踪（用于测试），返回恢复原状态的函数
+func UseTracerProvider(provider trace.TracerProvider) func() {
+	prevProvider := otel.GetTracerProvider()
+	prevPropagator := otel.GetTextMapPropagator()
+	prevEnabled := enabled.Load()
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	enabled.Store(true)
+	return func() {
+		otel.SetTracerProvider(prevProvider)
+		otel.SetTextMapPropagator(prevPropagator)
+		enabled.Store(prevEnabled)
+	}
+}
+
+// Enabled 返回是否已启用链路追踪导出
+func Enabled() bool {
+	return enabled.Load()
+}
+
+// Tracer 返回本服务使用的 Tracer
+func Tracer() trace.Tracer {
+	return otel.Tracer(InstrumentationName)
+}
+
+// Start 以 ctx 中的 Span 为父节点创建子 Span
+func Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return Tracer().Start(ctx, name, opts...)
+}
+
+// End 结束 Span；err 非空（且不是 context.Canceled）时记录错误并标记 Span 状态
+func End(span trace.Span, err error) {
+	if span == nil {
+		return
+	}
+	RecordError(span, err)
+	span.End()
+}
+
+// RecordError 记录错误并标记 Span 状态；客户端主动取消不视为错误
+func RecordError(span trace.Span, err error) {
+	if span == nil || err == nil || errors.Is(err, context.Canceled) {
+		return
+	}
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
+
+// ContextWithSpanFrom 将 src 中的 Span 挂到 dst 上，用于请求结束后仍需继续执行的异步任务
+func ContextWithSpanFrom(dst, src context.Context) context.Context {
+	if dst == nil {
+		dst = context.Background()
+	}
+	if src == nil {
+		return dst
+	}
+	spanCtx := trace.SpanContextFromContext(src)
+	if !spanCtx.IsValid() {
+		return dst
+	}
+	return trace.ContextWithSpanContext(dst, spanCtx)
+}
+
+// TraceID 返回 ctx 中 Span 的 TraceID；不存在时返回空字符串
+func TraceID(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	spanCtx := trace.SpanContextFromContext(ctx)
+	if !spanCtx.HasTraceID() {
+		return ""
+	}
+	return spanCtx.TraceID().String()
+}
@@ -0,0 +1,85 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Wei-Shaw/sub2api/internal/config"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func installRecorder(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	restore := UseTracerProvider(provider)
+	t.Cleanup(func() {
+		restore()
+		_ = provider.Shutdown(context.Background())
+	})
+	return recorder
+}
+
+func TestEnd_RecordsErrorButIgnoresCanceled(t *testing.T) {
+	recorder := installRecorder(t)
+
+	_, failed := Start(context.Background(), "failed")
+	End(failed, errors.New("boom"))
+	_, canceled := Start(context.Background(), "canceled")
+	End(canceled, context.Canceled)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	require.Equal(t, codes.Error, spans[0].Status().Code)
+	require.Equal(t, "boom", spans[0].Status().Description)
+	require.Equal(t, codes.Unset, spans[1].Status().Code)
+}
+
+func TestContextWithSpanFrom_DetachesCancellation(t *testing.T) {
+	installRecorder(t)
+
+	reqCtx, cancel := context.WithCancel(context.Background())
+	reqCtx, span := Start(reqCtx, "request")
+	defer span.End()
+
+	taskCtx := ContextWithSpanFrom(context.Background(), reqCtx)
+	cancel()
+
+	require.NoError(t, taskCtx.Err())
+	require.Equal(t, span.SpanContext().TraceID(), trace.SpanContextFromContext(taskCtx).TraceID())
+	require.Equal(t, TraceID(reqCtx), TraceID(taskCtx))
+
+	plain := ContextWithSpanFrom(context.Background(), context.Background())
+	require.Empty(t, TraceID(plain))
+}
+
+func TestInit_DisabledIsNoop(t *testing.T) {
+	shutdown, err := Init(context.Background(), Options{Enabled: false})
+	require.NoError(t, err)
+	require.False(t, Enabled())
+	require.NoError(t, shutdown(context.Background()))
+}
+
+func TestInit_RejectsUnknownExporter(t *testing.T) {
+	_, err := Init(context.Background(), Options{Enabled: true, Exporter: "zipkin", Endpoint: "localhost:9411"})
+	require.Error(t, err)
+	require.False(t, Enabled())
+}
+
+func TestOptionsFromConfig_FallsBackToLogServiceName(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Log.ServiceName = "sub2api-prod"
+	cfg.Tracing.ExportTimeoutSeconds = 5
+	opts := OptionsFromConfig(cfg, "1.2.3")
+	require.Equal(t, "sub2api-prod", opts.ServiceName)
+	require.Equal(t, "1.2.3", opts.Version)
+	require.Equal(t, float64(5), opts.ExportTimeout.Seconds())
+
+	cfg.Tracing.ServiceName = "gateway"
+	require.Equal(t, "gateway", OptionsFromConfig(cfg, "").ServiceName)
+}
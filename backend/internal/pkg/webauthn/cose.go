@@ -0,0 +1,134 @@
+package webauthn
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// COSE 算法标识（RFC 9053 / IANA COSE Algorithms）
+const (
+	AlgES256 int64 = -7
+	AlgEdDSA int64 = -8
+	AlgRS256 int64 = -257
+)
+
+// SupportedAlgorithms 注册时声明的可接受算法，按偏好排序
+var SupportedAlgorithms = []int64{AlgES256, AlgEdDSA, AlgRS256}
+
+// COSE Key 通用参数与各密钥类型参数（RFC 9052 第 7 节、RFC 9053 第 7 节）
+const (
+	coseKeyKty = 1
+	coseKeyAlg = 3
+
+	coseKtyOKP = 1
+	coseKtyEC2 = 2
+	coseKtyRSA = 3
+
+	coseCrvP256    = 1
+	coseCrvEd25519 = 6
+
+	coseEC2Crv = -1
+	coseEC2X   = -2
+	coseEC2Y   = -3
+	coseOKPCrv = -1
+	coseOKPX   = -2
+	coseRSAN   = -1
+	coseRSAE   = -2
+)
+
+// PublicKey 已解析的凭据公钥
+type PublicKey struct {
+	Algorithm int64
+	key       crypto.PublicKey
+}
+
+// ParsePublicKey 解析 COSE_Key 编码的凭据公钥
+func ParsePublicKey(coseKey []byte) (*PublicKey, error) {
+	item, rest, err := decodeCBOR(coseKey)
+	if err != nil {
+		return nil, fmt.Errorf("decode cose key: %w", err)
+	}
+	if len(rest) != 0 {
+		return nil, errors.New("cose key has trailing data")
+	}
+	m, ok := item.(map[any]any)
+	if !ok {
+		return nil, errors.New("cose key is not a map")
+	}
+	return publicKeyFromMap(m)
+}
+
+func publicKeyFromMap(m map[any]any) (*PublicKey, error) {
+	kty, _ := m[int64(coseKeyKty)].(int64)
+	alg, ok := m[int64(coseKeyAlg)].(int64)
+	if !ok {
+		return nil, errors.New("cose key missing alg")
+	}
+
+	switch alg {
+	case AlgES256:
+		crv, _ := m[int64(coseEC2Crv)].(int64)
+		x, _ := m[int64(coseEC2X)].([]byte)
+		y, _ := m[int64(coseEC2Y)].([]byte)
+		if kty != coseKtyEC2 || crv != coseCrvP256 || len(x) != 32 || len(y) != 32 {
+			return nil, errors.New("invalid ES256 cose key")
+		}
+		// 通过未压缩点编码校验点确实在曲线上
+		uncompressed := append(append([]byte{0x04}, x...), y...)
+		pub, err := ecdsa.ParseUncompressedPublicKey(elliptic.P256(), uncompressed)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ES256 public key: %w", err)
+		}
+		return &PublicKey{Algorithm: alg, key: pub}, nil
+	case AlgEdDSA:
+		crv, _ := m[int64(coseOKPCrv)].(int64)
+		x, _ := m[int64(coseOKPX)].([]byte)
+		if kty != coseKtyOKP || crv != coseCrvEd25519 || len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("invalid EdDSA cose key")
+		}
+		return &PublicKey{Algorithm: alg, key: ed25519.PublicKey(x)}, nil
+	case AlgRS256:
+		n, _ := m[int64(coseRSAN)].([]byte)
+		e, _ := m[int64(coseRSAE)].([]byte)
+		if kty != coseKtyRSA || len(n) == 0 || len(e) == 0 || len(e) > 4 {
+			return nil, errors.New("invalid RS256 cose key")
+		}
+		pub := &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+		if pub.N.BitLen() < 2048 {
+			return nil, errors.New("RS256 key is shorter than 2048 bits")
+		}
+		return &PublicKey{Algorithm: alg, key: pub}, nil
+	default:
+		return nil, fmt.Errorf("unsupported cose algorithm %d", alg)
+	}
+}
+
+// Verify 校验 signature 是否为 message 的有效签名
+func (k *PublicKey) Verify(message, signature []byte) error {
+	switch pub := k.key.(type) {
+	case *ecdsa.PublicKey:
+		digest := sha256.Sum256(message)
+		if !ecdsa.VerifyASN1(pub, digest[:], signature) {
+			return ErrInvalidSignature
+		}
+	case ed25519.PublicKey:
+		if !ed25519.Verify(pub, message, signature) {
+			return ErrInvalidSignature
+		}
+	case *rsa.PublicKey:
+		digest := sha256.Sum256(message)
+		if err := rsa.VerifyPKCS1v15(pub, crypto.SHA256, digest[:], signature); err != nil {
+			return ErrInvalidSignature
+		}
+	default:
+		return fmt.Errorf("unsupported public key type %T", k.key)
+	}
+	return nil
+}
@@ -0,0 +1,517 @@
+// Package webauthn 实现服务端 WebAuthn（Passkey）注册与认证仪式的校验。
+//
+// 只覆盖本项目需要的部分：注册时请求 attestation "none"，不校验证明声明与认证器来源，
+// 仅解析 authenticatorData 中的凭据公钥；认证时校验客户端数据、RP ID 哈希、用户在场/验证标志、
+// 签名与签名计数器。规范参见 https://www.w3.org/TR/webauthn-3/ 第 7 节。
+package webauthn
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+const (
+	// ChallengeSize 挑战随机字节数（规范要求至少 16 字节）
+	ChallengeSize = 32
+	// MaxCredentialIDLength 凭据 ID 最大长度（规范第 5.8.3 节）
+	MaxCredentialIDLength = 1023
+
+	ceremonyCreate = "webauthn.create"
+	ceremonyGet    = "webauthn.get"
+)
+
+// authenticatorData 标志位
+const (
+	FlagUserPresent            byte = 0x01
+	FlagUserVerified           byte = 0x04
+	FlagBackupEligible         byte = 0x08
+	FlagBackedUp               byte = 0x10
+	FlagAttestedCredentialData byte = 0x40
+	FlagExtensionData          byte = 0x80
+)
+
+var (
+	ErrInvalidSignature  = errors.New("webauthn: invalid signature")
+	ErrChallengeMismatch = errors.New("webauthn: challenge mismatch")
+	ErrOriginMismatch    = errors.New("webauthn: origin not allowed")
+	ErrRPIDMismatch      = errors.New("webauthn: rp id hash mismatch")
+	ErrUserNotPresent    = errors.New("webauthn: user presence flag not set")
+	ErrUserNotVerified   = errors.New("webauthn: user verification flag not set")
+	ErrSignCountReplay   = errors.New("webauthn: signature counter did not increase")
+)
+
+// RelyingParty 依赖方配置
+type RelyingParty struct {
+	ID      string   // RP ID，通常为站点注册域名，如 example.com
+	Name    string   // 展示名称
+	Origins []string // 允许的来源，如 https://example.com
+}
+
+// Validate 校验 RP 配置：每个来源的主机必须等于 RP ID 或为其子域名
+func (rp *RelyingParty) Validate() error {
+	if rp.ID == "" {
+		return errors.New("rp id is required")
+	}
+	if len(rp.Origins) == 0 {
+		return errors.New("at least one origin is required")
+	}
+	for _, origin := range rp.Origins {
+		u, err := url.Parse(origin)
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			return fmt.Errorf("invalid origin %q", origin)
+		}
+		if u.Path != "" && u.Path != "/" {
+			return fmt.Errorf("origin %q must not include a path", origin)
+		}
+		host := u.Hostname()
+		if u.Scheme != "https" && host != "localhost" {
+			return fmt.Errorf("origin %q must use https", origin)
+		}
+		if host != rp.ID && !strings.HasSuffix(host, "."+rp.ID) {
+			return fmt.Errorf("origin %q is not within rp id %q", origin, rp.ID)
+		}
+	}
+	return nil
+}
+
+// --- 下发给浏览器的选项（与 PublicKeyCredential.parse*OptionsFromJSON 的输入格式一致） ---
+
+// RPEntity PublicKeyCredentialRpEntity
+type RPEntity struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// UserEntity PublicKeyCredentialUserEntityJSON
+type UserEntity struct {
+	ID          string `json:"id"` // base64url 编码的 user handle
+	Name        string `json:"name"`
+	DisplayName string `json:"displayName"`
+}
+
+// CredentialParameter PublicKeyCredentialParameters
+type CredentialParameter struct {
+	Type string `json:"type"`
+	Alg  int64  `json:"alg"`
+}
+
+// CredentialDescriptor PublicKeyCredentialDescriptorJSON
+type CredentialDescriptor struct {
+	Type       string   `json:"type"`
+	ID         string   `json:"id"` // base64url
+	Transports []string `json:"transports,omitempty"`
+}
+
+// AuthenticatorSelection AuthenticatorSelectionCriteria
+type AuthenticatorSelection struct {
+	ResidentKey      string `json:"residentKey,omitempty"`
+	UserVerification string `json:"userVerification,omitempty"`
+}
+
+// CreationOptions PublicKeyCredentialCreationOptionsJSON
+type CreationOptions struct {
+	RP                     RPEntity               `json:"rp"`
+	User                   UserEntity             `json:"user"`
+	Challenge              string                 `json:"challenge"`
+	PubKeyCredParams       []CredentialParameter  `json:"pubKeyCredParams"`
+	Timeout                int64                  `json:"timeout,omitempty"`
+	ExcludeCredentials     []CredentialDescriptor `json:"excludeCredentials"`
+	AuthenticatorSelection AuthenticatorSelection `json:"authenticatorSelection"`
+	Attestation            string                 `json:"attestation"`
+}
+
+// RequestOptions PublicKeyCredentialRequestOptionsJSON
+type RequestOptions struct {
+	Challenge        string                 `json:"challenge"`
+	Timeout          int64                  `json:"timeout,omitempty"`
+	RPID             string                 `json:"rpId"`
+	AllowCredentials []CredentialDescriptor `json:"allowCredentials"`
+	UserVerification string                 `json:"userVerification"`
+}
+
+// NewCreationOptions 生成注册选项：要求可发现凭据（passkey），不请求证明
+func (rp *RelyingParty) NewCreationOptions(challenge, userHandle []byte, userName, displayName string, exclude []CredentialDescriptor, timeoutMs int64) *CreationOptions {
+	params := make([]CredentialParameter, 0, len(SupportedAlgorithms))
+	for _, alg := range SupportedAlgorithms {
+		params = append(params, CredentialParameter{Type: "public-key", Alg: alg})
+	}
+	if exclude == nil {
+		exclude = []CredentialDescriptor{}
+	}
+	return &CreationOptions{
+		RP: RPEntity{ID: rp.ID, Name: rp.Name},
+		User: UserEntity{
+			ID:          EncodeBase64URL(userHandle),
+			Name:        userName,
+			DisplayName: displayName,
+		},
+		Challenge:          EncodeBase64URL(challenge),
+		PubKeyCredParams:   params,
+		Timeout:            timeoutMs,
+		ExcludeCredentials: exclude,
+		AuthenticatorSelection: AuthenticatorSelection{
+			ResidentKey:      "required",
+			UserVerification: "preferred",
+		},
+		Attestation: "none",
+	}
+}
+
+// NewRequestOptions 生成认证选项；allow 为空时由浏览器展示可发现凭据（无用户名登录）
+func (rp *RelyingParty) NewRequestOptions(challenge []byte, allow []CredentialDescriptor, userVerification string, timeoutMs int64) *RequestOptions {
+	if allow == nil {
+		allow = []CredentialDescriptor{}
+	}
+	return &RequestOptions{
+		Challenge:        EncodeBase64URL(challenge),
+		Timeout:          timeoutMs,
+		RPID:             rp.ID,
+		AllowCredentials: allow,
+		UserVerification: userVerification,
+	}
+}
+
+// --- 浏览器回传的凭据（PublicKeyCredential.toJSON() 的输出格式） ---
+
+// RegistrationResponse RegistrationResponseJSON
+type RegistrationResponse struct {
+	ID       string `json:"id"`
+	RawID    string `json:"rawId"`
+	Type     string `json:"type"`
+	Response struct {
+		ClientDataJSON    string   `json:"clientDataJSON"`
+		AttestationObject string   `json:"attestationObject"`
+		Transports        []string `json:"transports"`
+	} `json:"response"`
+}
+
+// AuthenticationResponse AuthenticationResponseJSON
+type AuthenticationResponse struct {
+	ID       string `json:"id"`
+	RawID    string `json:"rawId"`
+	Type     string `json:"type"`
+	Response struct {
+		ClientDataJSON    string `json:"clientDataJSON"`
+		AuthenticatorData string `json:"authenticatorData"`
+		Signature         string `json:"signature"`
+		UserHandle        string `json:"userHandle"`
+	} `json:"response"`
+}
+
+// ParseRegistrationResponse 解析注册响应 JSON
+func ParseRegistrationResponse(raw []byte) (*RegistrationResponse, error) {
+	var resp RegistrationResponse
+	if err := json.Unmarshal(raw, &resp); err != nil {
+		return nil, fmt.Errorf("decode registration response: %w", err)
+	}
+	if resp.Type != "public-key" {
+		return nil, fmt.Errorf("unexpected credential type %q", resp.Type)
+	}
+	return &resp, nil
+}
+
+// ParseAuthenticationResponse 解析认证响应 JSON
+func ParseAuthenticationResponse(raw []byte) (*AuthenticationResponse, error) {
+	var resp AuthenticationResponse
+	if err := json.Unmarshal(raw, &resp); err != nil {
+		return nil, fmt.Errorf("decode authentication response: %w", err)
+	}
+	if resp.Type != "public-key" {
+		return nil, fmt.Errorf("unexpected credential type %q", resp.Type)
+	}
+	return &resp, nil
+}
+
+// CredentialID 返回解码后的凭据 ID（优先 rawId）
+func (r *AuthenticationResponse) CredentialID() ([]byte, error) {
+	return decodeCredentialID(r.RawID, r.ID)
+}
+
+// UserHandleBytes 返回解码后的 user handle；不可发现凭据可能为空
+func (r *AuthenticationResponse) UserHandleBytes() ([]byte, error) {
+	if r.Response.UserHandle == "" {
+		return nil, nil
+	}
+	return DecodeBase64URL(r.Response.UserHandle)
+}
+
+func decodeCredentialID(rawID, id string) ([]byte, error) {
+	encoded := rawID
+	if encoded == "" {
+		encoded = id
+	}
+	b, err := DecodeBase64URL(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("decode credential id: %w", err)
+	}
+	if len(b) == 0 || len(b) > MaxCredentialIDLength {
+		return nil, errors.New("invalid credential id length")
+	}
+	return b, nil
+}
+
+// --- 校验 ---
+
+// Credential 注册成功后需持久化的凭据信息
+type Credential struct {
+	ID             []byte
+	PublicKey      []byte // COSE_Key 原始编码
+	Algorithm      int64
+	SignCount      uint32
+	AAGUID         []byte
+	Transports     []string
+	BackupEligible bool
+	BackedUp       bool
+	UserVerified   bool
+}
+
+// AssertionResult 认证成功后的结果
+type AssertionResult struct {
+	SignCount    uint32
+	UserVerified bool
+	BackedUp     bool
+}
+
+// VerifyRegistration 校验注册响应（规范第 7.1 节）
+func (rp *RelyingParty) VerifyRegistration(resp *RegistrationResponse, challenge []byte, requireUserVerification bool) (*Credential, error) {
+	clientDataJSON, err := DecodeBase64URL(resp.Response.ClientDataJSON)
+	if err != nil {
+		return nil, fmt.Errorf("decode clientDataJSON: %w", err)
+	}
+	if err := rp.verifyClientData(clientDataJSON, ceremonyCreate, challenge); err != nil {
+		return nil, err
+	}
+
+	attObjRaw, err := DecodeBase64URL(resp.Response.AttestationObject)
+	if err != nil {
+		return nil, fmt.Errorf("decode attestationObject: %w", err)
+	}
+	item, rest, err := decodeCBOR(attObjRaw)
+	if err != nil {
+		return nil, fmt.Errorf("decode attestationObject: %w", err)
+	}
+	attObj, ok := item.(map[any]any)
+	if !ok || len(rest) != 0 {
+		return nil, errors.New("malformed attestationObject")
+	}
+	authDataRaw, ok := attObj["authData"].([]byte)
+	if !ok {
+		return nil, errors.New("attestationObject missing authData")
+	}
+
+	authData, err := ParseAuthenticatorData(authDataRaw)
+	if err != nil {
+		return nil, err
+	}
+	if err := rp.verifyAuthenticatorFlags(authData, requireUserVerification); err != nil {
+		return nil, err
+	}
+	if authData.AttestedCredential == nil {
+		return nil, errors.New("authenticator data missing attested credential")
+	}
+
+	attested := authData.AttestedCredential
+	credID, err := decodeCredentialID(resp.RawID, resp.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(credID, attested.CredentialID) {
+		return nil, errors.New("credential id does not match authenticator data")
+	}
+
+	pub, err := ParsePublicKey(attested.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Credential{
+		ID:             attested.CredentialID,
+		PublicKey:      attested.PublicKey,
+		Algorithm:      pub.Algorithm,
+		SignCount:      authData.SignCount,
+		AAGUID:         attested.AAGUID,
+		Transports:     resp.Response.Transports,
+		BackupEligible: authData.Flags&FlagBackupEligible != 0,
+		BackedUp:       authData.Flags&FlagBackedUp != 0,
+		UserVerified:   authData.Flags&FlagUserVerified != 0,
+	}, nil
+}
+
+// VerifyAssertion 校验认证响应（规范第 7.2 节）。
+// storedSignCount 为上次记录的计数器；认证器支持计数器时新值必须严格增大，否则视为凭据被克隆。
+func (rp *RelyingParty) VerifyAssertion(resp *AuthenticationResponse, challenge, publicKey []byte, storedSignCount uint32, requireUserVerification bool) (*AssertionResult, error) {
+	clientDataJSON, err := DecodeBase64URL(resp.Response.ClientDataJSON)
+	if err != nil {
+		return nil, fmt.Errorf("decode clientDataJSON: %w", err)
+	}
+	if err := rp.verifyClientData(clientDataJSON, ceremonyGet, challenge); err != nil {
+		return nil, err
+	}
+
+	authDataRaw, err := DecodeBase64URL(resp.Response.AuthenticatorData)
+	if err != nil {
+		return nil, fmt.Errorf("decode authenticatorData: %w", err)
+	}
+	authData, err := ParseAuthenticatorData(authDataRaw)
+	if err != nil {
+		return nil, err
+	}
+	if err := rp.verifyAuthenticatorFlags(authData, requireUserVerification); err != nil {
+		return nil, err
+	}
+
+	signature, err := DecodeBase64URL(resp.Response.Signature)
+	if err != nil {
+		return nil, fmt.Errorf("decode signature: %w", err)
+	}
+	pub, err := ParsePublicKey(publicKey)
+	if err != nil {
+		return nil, err
+	}
+	clientDataHash := sha256.Sum256(clientDataJSON)
+	signed := make([]byte, 0, len(authDataRaw)+len(clientDataHash))
+	signed = append(signed, authDataRaw...)
+	signed = append(signed, clientDataHash[:]...)
+	if err := pub.Verify(signed, signature); err != nil {
+		return nil, err
+	}
+
+	if (authData.SignCount != 0 || storedSignCount != 0) && authData.SignCount <= storedSignCount {
+		return nil, ErrSignCountReplay
+	}
+
+	return &AssertionResult{
+		SignCount:    authData.SignCount,
+		UserVerified: authData.Flags&FlagUserVerified != 0,
+		BackedUp:     authData.Flags&FlagBackedUp != 0,
+	}, nil
+}
+
+// collectedClientData CollectedClientData
+type collectedClientData struct {
+	Type        string `json:"type"`
+	Challenge   string `json:"challenge"`
+	Origin      string `json:"origin"`
+	CrossOrigin bool   `json:"crossOrigin"`
+}
+
+func (rp *RelyingParty) verifyClientData(raw []byte, ceremony string, challenge []byte) error {
+	var cd collectedClientData
+	if err := json.Unmarshal(raw, &cd); err != nil {
+		return fmt.Errorf("decode clientDataJSON: %w", err)
+	}
+	if cd.Type != ceremony {
+		return fmt.Errorf("unexpected client data type %q", cd.Type)
+	}
+	got, err := DecodeBase64URL(cd.Challenge)
+	if err != nil || len(challenge) == 0 || subtle.ConstantTimeCompare(got, challenge) != 1 {
+		return ErrChallengeMismatch
+	}
+	if cd.CrossOrigin || !slices.Contains(rp.Origins, cd.Origin) {
+		return ErrOriginMismatch
+	}
+	return nil
+}
+
+func (rp *RelyingParty) verifyAuthenticatorFlags(authData *AuthenticatorData, requireUserVerification bool) error {
+	expected := sha256.Sum256([]byte(rp.ID))
+	if subtle.ConstantTimeCompare(authData.RPIDHash, expected[:]) != 1 {
+		return ErrRPIDMismatch
+	}
+	if authData.Flags&FlagUserPresent == 0 {
+		return ErrUserNotPresent
+	}
+	if requireUserVerification && authData.Flags&FlagUserVerified == 0 {
+		return ErrUserNotVerified
+	}
+	return nil
+}
+
+// AttestedCredentialData authenticatorData 中携带的新凭据
+type AttestedCredentialData struct {
+	AAGUID       []byte
+	CredentialID []byte
+	PublicKey    []byte
+}
+
+// AuthenticatorData 解析后的 authenticatorData（规范第 6.1 节）
+type AuthenticatorData struct {
+	RPIDHash           []byte
+	Flags              byte
+	SignCount          uint32
+	AttestedCredential *AttestedCredentialData
+}
+
+// ParseAuthenticatorData 解析 authenticatorData 二进制结构
+func ParseAuthenticatorData(b []byte) (*AuthenticatorData, error) {
+	const headerLen = 32 + 1 + 4
+	if len(b) < headerLen {
+		return nil, errors.New("authenticator data too short")
+	}
+	ad := &AuthenticatorData{
+		RPIDHash:  b[:32],
+		Flags:     b[32],
+		SignCount: binary.BigEndian.Uint32(b[33:37]),
+	}
+	rest := b[headerLen:]
+
+	if ad.Flags&FlagAttestedCredentialData != 0 {
+		if len(rest) < 16+2 {
+			return nil, errors.New("attested credential data too short")
+		}
+		aaguid := rest[:16]
+		idLen := int(binary.BigEndian.Uint16(rest[16:18]))
+		rest = rest[18:]
+		if idLen == 0 || idLen > MaxCredentialIDLength || len(rest) < idLen {
+			return nil, errors.New("invalid credential id length")
+		}
+		credID := rest[:idLen]
+		rest = rest[idLen:]
+
+		// COSE 公钥后可能紧跟扩展数据，按 CBOR 数据项边界切分
+		_, after, err := decodeCBOR(rest)
+		if err != nil {
+			return nil, fmt.Errorf("decode credential public key: %w", err)
+		}
+		ad.AttestedCredential = &AttestedCredentialData{
+			AAGUID:       aaguid,
+			CredentialID: credID,
+			PublicKey:    rest[:len(rest)-len(after)],
+		}
+		rest = after
+	}
+
+	if ad.Flags&FlagExtensionData != 0 {
+		ext, after, err := decodeCBOR(rest)
+		if err != nil {
+			return nil, fmt.Errorf("decode extensions: %w", err)
+		}
+		if _, ok := ext.(map[any]any); !ok {
+			return nil, errors.New("extensions is not a map")
+		}
+		rest = after
+	}
+	if len(rest) != 0 {
+		return nil, errors.New("authenticator data has trailing bytes")
+	}
+	return ad, nil
+}
+
+// EncodeBase64URL 无填充 base64url 编码
+func EncodeBase64URL(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// DecodeBase64URL 解码 base64url，兼容带填充的输入
+func DecodeBase64URL(s string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+}
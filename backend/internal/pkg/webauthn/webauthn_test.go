@@ -0,0 +1,270 @@
+package webauthn
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// --- 测试用最小 CBOR 编码器 ---
+
+func cborHead(major byte, n uint64) []byte {
+	switch {
+	case n < 24:
+		return []byte{major<<5 | byte(n)}
+	case n <= 0xff:
+		return []byte{major<<5 | 24, byte(n)}
+	case n <= 0xffff:
+		b := []byte{major<<5 | 25, 0, 0}
+		binary.BigEndian.PutUint16(b[1:], uint16(n))
+		return b
+	default:
+		b := []byte{major<<5 | 26, 0, 0, 0, 0}
+		binary.BigEndian.PutUint32(b[1:], uint32(n))
+		return b
+	}
+}
+
+func cborEncode(t *testing.T, v any) []byte {
+	t.Helper()
+	switch x := v.(type) {
+	case int:
+		if x >= 0 {
+			return cborHead(0, uint64(x))
+		}
+		return cborHead(1, uint64(-1-x))
+	case int64:
+		return cborEncode(t, int(x))
+	case []byte:
+		return append(cborHead(2, uint64(len(x))), x...)
+	case string:
+		return append(cborHead(3, uint64(len(x))), x...)
+	case map[int]any:
+		keys := make([]int, 0, len(x))
+		for k := range x {
+			keys = append(keys, k)
+		}
+		sort.Ints(keys)
+		out := cborHead(5, uint64(len(x)))
+		for _, k := range keys {
+			out = append(out, cborEncode(t, k)...)
+			out = append(out, cborEncode(t, x[k])...)
+		}
+		return out
+	case map[string]any:
+		keys := make([]string, 0, len(x))
+		for k := range x {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		out := cborHead(5, uint64(len(x)))
+		for _, k := range keys {
+			out = append(out, cborEncode(t, k)...)
+			out = append(out, cborEncode(t, x[k])...)
+		}
+		return out
+	default:
+		t.Fatalf("unsupported cbor test value %T", v)
+		return nil
+	}
+}
+
+// --- 模拟认证器 ---
+
+type testAuthenticator struct {
+	t      *testing.T
+	credID []byte
+	key    *ecdsa.PrivateKey
+	count  uint32
+}
+
+func newTestAuthenticator(t *testing.T) *testAuthenticator {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	credID := make([]byte, 16)
+	_, _ = rand.Read(credID)
+	return &testAuthenticator{t: t, credID: credID, key: key}
+}
+
+func (a *testAuthenticator) coseKey() []byte {
+	pub, err := a.key.PublicKey.Bytes()
+	require.NoError(a.t, err)
+	return cborEncode(a.t, map[int]any{
+		coseKeyKty: coseKtyEC2,
+		coseKeyAlg: int(AlgES256),
+		coseEC2Crv: coseCrvP256,
+		coseEC2X:   pub[1:33],
+		coseEC2Y:   pub[33:65],
+	})
+}
+
+func (a *testAuthenticator) authData(rpID string, flags byte, attested bool) []byte {
+	rpHash := sha256.Sum256([]byte(rpID))
+	out := append([]byte{}, rpHash[:]...)
+	out = append(out, flags)
+	out = binary.BigEndian.AppendUint32(out, a.count)
+	if attested {
+		out = append(out, make([]byte, 16)...)
+		out = binary.BigEndian.AppendUint16(out, uint16(len(a.credID)))
+		out = append(out, a.credID...)
+		out = append(out, a.coseKey()...)
+	}
+	return out
+}
+
+func clientData(t *testing.T, typ string, challenge []byte, origin string) []byte {
+	raw, err := json.Marshal(map[string]any{
+		"type":      typ,
+		"challenge": EncodeBase64URL(challenge),
+		"origin":    origin,
+	})
+	require.NoError(t, err)
+	return raw
+}
+
+func (a *testAuthenticator) register(rpID, origin string, challenge []byte) *RegistrationResponse {
+	attObj := cborEncode(a.t, map[string]any{
+		"fmt":      "none",
+		"attStmt":  map[string]any{},
+		"authData": a.authData(rpID, FlagUserPresent|FlagUserVerified|FlagAttestedCredentialData, true),
+	})
+	resp := &RegistrationResponse{ID: EncodeBase64URL(a.credID), RawID: EncodeBase64URL(a.credID), Type: "public-key"}
+	resp.Response.ClientDataJSON = EncodeBase64URL(clientData(a.t, ceremonyCreate, challenge, origin))
+	resp.Response.AttestationObject = EncodeBase64URL(attObj)
+	resp.Response.Transports = []string{"internal"}
+	return resp
+}
+
+func (a *testAuthenticator) assert(rpID, origin string, challenge []byte, flags byte) *AuthenticationResponse {
+	a.count++
+	authData := a.authData(rpID, flags, false)
+	cd := clientData(a.t, ceremonyGet, challenge, origin)
+	cdHash := sha256.Sum256(cd)
+	digest := sha256.Sum256(append(append([]byte{}, authData...), cdHash[:]...))
+	sig, err := ecdsa.SignASN1(rand.Reader, a.key, digest[:])
+	require.NoError(a.t, err)
+
+	resp := &AuthenticationResponse{ID: EncodeBase64URL(a.credID), RawID: EncodeBase64URL(a.credID), Type: "public-key"}
+	resp.Response.ClientDataJSON = EncodeBase64URL(cd)
+	resp.Response.AuthenticatorData = EncodeBase64URL(authData)
+	resp.Response.Signature = EncodeBase64URL(sig)
+	resp.Response.UserHandle = EncodeBase64URL([]byte("user-handle"))
+	return resp
+}
+
+func testRP() *RelyingParty {
+	return &RelyingParty{ID: "example.com", Name: "Example", Origins: []string{"https://example.com"}}
+}
+
+func TestRegistrationAndAssertion(t *testing.T) {
+	rp := testRP()
+	auth := newTestAuthenticator(t)
+	challenge := []byte("registration-challenge-0123456789")
+
+	cred, err := rp.VerifyRegistration(auth.register(rp.ID, "https://example.com", challenge), challenge, true)
+	require.NoError(t, err)
+	require.Equal(t, auth.credID, cred.ID)
+	require.Equal(t, AlgES256, cred.Algorithm)
+	require.True(t, cred.UserVerified)
+	require.Equal(t, []string{"internal"}, cred.Transports)
+
+	loginChallenge := []byte("login-challenge-0123456789abcdef")
+	resp := auth.assert(rp.ID, "https://example.com", loginChallenge, FlagUserPresent|FlagUserVerified)
+	result, err := rp.VerifyAssertion(resp, loginChallenge, cred.PublicKey, cred.SignCount, true)
+	require.NoError(t, err)
+	require.Equal(t, uint32(1), result.SignCount)
+
+	handle, err := resp.UserHandleBytes()
+	require.NoError(t, err)
+	require.Equal(t, []byte("user-handle"), handle)
+
+	// 计数器未增长视为重放/克隆
+	_, err = rp.VerifyAssertion(resp, loginChallenge, cred.PublicKey, result.SignCount, true)
+	require.ErrorIs(t, err, ErrSignCountReplay)
+}
+
+func TestVerifyRegistration_Rejects(t *testing.T) {
+	rp := testRP()
+	auth := newTestAuthenticator(t)
+	challenge := []byte("registration-challenge-0123456789")
+
+	_, err := rp.VerifyRegistration(auth.register(rp.ID, "https://example.com", challenge), []byte("other-challenge"), true)
+	require.ErrorIs(t, err, ErrChallengeMismatch)
+
+	_, err = rp.VerifyRegistration(auth.register(rp.ID, "https://evil.example.net", challenge), challenge, true)
+	require.ErrorIs(t, err, ErrOriginMismatch)
+
+	_, err = rp.VerifyRegistration(auth.register("evil.example.net", "https://example.com", challenge), challenge, true)
+	require.ErrorIs(t, err, ErrRPIDMismatch)
+}
+
+func TestVerifyAssertion_Rejects(t *testing.T) {
+	rp := testRP()
+	auth := newTestAuthenticator(t)
+	challenge := []byte("registration-challenge-0123456789")
+	cred, err := rp.VerifyRegistration(auth.register(rp.ID, "https://example.com", challenge), challenge, false)
+	require.NoError(t, err)
+
+	loginChallenge := []byte("login-challenge-0123456789abcdef")
+
+	_, err = rp.VerifyAssertion(auth.assert(rp.ID, "https://example.com", loginChallenge, FlagUserPresent), loginChallenge, cred.PublicKey, 0, true)
+	require.ErrorIs(t, err, ErrUserNotVerified)
+
+	resp := auth.assert(rp.ID, "https://example.com", loginChallenge, FlagUserPresent|FlagUserVerified)
+	resp.Response.Signature = EncodeBase64URL([]byte("not-a-signature"))
+	_, err = rp.VerifyAssertion(resp, loginChallenge, cred.PublicKey, 0, true)
+	require.ErrorIs(t, err, ErrInvalidSignature)
+
+	other := newTestAuthenticator(t)
+	_, err = rp.VerifyAssertion(auth.assert(rp.ID, "https://example.com", loginChallenge, FlagUserPresent), loginChallenge, other.coseKey(), 0, false)
+	require.ErrorIs(t, err, ErrInvalidSignature)
+}
+
+func TestParsePublicKey_EdDSA(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	key, err := ParsePublicKey(cborEncode(t, map[int]any{
+		coseKeyKty: coseKtyOKP,
+		coseKeyAlg: int(AlgEdDSA),
+		coseOKPCrv: coseCrvEd25519,
+		coseOKPX:   []byte(pub),
+	}))
+	require.NoError(t, err)
+	msg := []byte("hello")
+	require.NoError(t, key.Verify(msg, ed25519.Sign(priv, msg)))
+	require.True(t, errors.Is(key.Verify(msg, make([]byte, ed25519.SignatureSize)), ErrInvalidSignature))
+}
+
+func TestDecodeCBOR_RejectsMalformed(t *testing.T) {
+	cases := map[string][]byte{
+		"truncated bytes":  {0x45, 0x01},
+		"indefinite array": {0x9f, 0x01, 0xff},
+		"float":            {0xfa, 0x00, 0x00, 0x00, 0x00},
+		"duplicate key":    {0xa2, 0x01, 0x01, 0x01, 0x02},
+		"array too long":   {0x9a, 0xff, 0xff, 0xff, 0xff},
+	}
+	for name, data := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, _, err := decodeCBOR(data)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestRelyingPartyValidate(t *testing.T) {
+	require.NoError(t, testRP().Validate())
+	require.NoError(t, (&RelyingParty{ID: "example.com", Origins: []string{"https://app.example.com"}}).Validate())
+	require.NoError(t, (&RelyingParty{ID: "localhost", Origins: []string{"http://localhost:3000"}}).Validate())
+	require.Error(t, (&RelyingParty{ID: "example.com", Origins: []string{"https://example.net"}}).Validate())
+	require.Error(t, (&RelyingParty{ID: "example.com", Origins: []string{"http://example.com"}}).Validate())
+	require.Error(t, (&RelyingParty{ID: "example.com"}).Validate())
+}
@@ -1373,15 +1373,7 @@ func (r *accountRepository) BulkUpdate(ctx context.Context, ids []int64, updates
 		idx++
 	}
 	// JSONB 需要合并而非覆盖，使用 raw SQL 保持旧行为。
-	if len(updates.Credentials) > 0 {
-		payload, err := json.Marshal(updates.Credentials)
-		if err != nil {
-			return 0, err
-		}
-		setClauses = append(setClauses, "credentials = COALESCE(credentials, '{}'::jsonb) || $"+itoa(idx)+"::jsonb")
-		args = append(args, payload)
-		idx++
-	}
+	// credentials 可能经过信封加密，无法在 SQL 中合并，见下方 mergeCredentials。
 	if len(updates.Extra) > 0 {
 		payload, err := json.Marshal(updates.Extra)
 		if err != nil {
@@ -1392,22 +1384,34 @@ func (r *accountRepository) BulkUpdate(ctx context.Context, ids []int64, updates
 		idx++
 	}
 
-	if len(setClauses) == 0 {
+	if len(setClauses) == 0 && len(updates.Credentials) == 0 {
 		return 0, nil
 	}
 
-	setClauses = append(setClauses, "updated_at = NOW()")
+	var rows int64
+	if len(setClauses) > 0 {
+		setClauses = append(setClauses, "updated_at = NOW()")
 
-	query := "UPDATE accounts SET " + joinClauses(setClauses, ", ") + " WHERE id = ANY($" + itoa(idx) + ") AND deleted_at IS NULL"
-	args = append(args, pq.Array(ids))
+		query := "UPDATE accounts SET " + joinClauses(setClauses, ", ") + " WHERE id = ANY($" + itoa(idx) + ") AND deleted_at IS NULL"
+		args = append(args, pq.Array(ids))
 
-	result, err := r.sql.ExecContext(ctx, query, args...)
-	if err != nil {
-		return 0, err
+		result, err := r.sql.ExecContext(ctx, query, args...)
+		if err != nil {
+			return 0, err
+		}
+		rows, err = result.RowsAffected()
+		if err != nil {
+			return 0, err
+		}
 	}
-	rows, err := result.RowsAffected()
-	if err != nil {
-		return 0, err
+	if len(updates.Credentials) > 0 {
+		merged, err := r.mergeCredentials(ctx, ids, updates.Credentials)
+		if err != nil {
+			return 0, err
+		}
+		if len(setClauses) == 0 {
+			rows = merged
+		}
 	}
 	if rows > 0 {
 		payload := map[string]any{"account_ids": ids}
@@ -1428,6 +1432,51 @@ func (r *accountRepository) BulkUpdate(ctx context.Context, ids []int64, updates
 	return rows, nil
 }
 
+// mergeCredentials 将 patch 合并进每个账号的 credentials。
+// 通过 ent 读取并写回，由凭证加密 hook 透明完成解密/加密；行锁保证与并发的 token 刷新互不覆盖。
+func (r *accountRepository) mergeCredentials(ctx context.Context, ids []int64, patch map[string]any) (int64, error) {
+	tx, err := r.client.Tx(ctx)
+	if err != nil && !errors.Is(err, dbent.ErrTxStarted) {
+		return 0, err
+	}
+
+	var txClient *dbent.Client
+	if err == nil {
+		defer func() { _ = tx.Rollback() }()
+		txClient = tx.Client()
+	} else {
+		// 已处于外部事务中（ErrTxStarted），复用当前 client
+		txClient = r.client
+	}
+
+	accounts, err := txClient.Account.Query().
+		Where(dbaccount.IDIn(ids...)).
+		ForUpdate().
+		All(ctx)
+	if err != nil {
+		return 0, err
+	}
+	for _, acc := range accounts {
+		merged := make(map[string]any, len(acc.Credentials)+len(patch))
+		for k, v := range acc.Credentials {
+			merged[k] = v
+		}
+		for k, v := range patch {
+			merged[k] = v
+		}
+		if err := txClient.Account.UpdateOneID(acc.ID).SetCredentials(merged).Exec(ctx); err != nil {
+			return 0, err
+		}
+	}
+
+	if tx != nil {
+		if err := tx.Commit(); err != nil {
+			return 0, err
+		}
+	}
+	return int64(len(accounts)), nil
+}
+
 type accountGroupQueryOptions struct {
 	status      string
 	schedulable bool
@@ -0,0 +1,226 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	dbent "github.com/Wei-Shaw/sub2api/ent"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+)
+
+const activityTaskUsageCursor = "usage_logs"
+
+type activityTaskRepository struct {
+	sql sqlExecutor
+}
+
+// NewActivityTaskRepository 创建任务活动进度仓储
+func NewActivityTaskRepository(sqlDB *sql.DB) service.ActivityTaskRepository {
+	return &activityTaskRepository{sql: sqlDB}
+}
+
+// q 返回当前上下文的执行器：在事务上下文中与游标推进同事务提交
+func (r *activityTaskRepository) q(ctx context.Context) sqlExecutor {
+	if tx := dbent.TxFromContext(ctx); tx != nil {
+		return tx.Client()
+	}
+	return r.sql
+}
+
+// ---------- 使用记录游标 ----------
+
+func (r *activityTaskRepository) LockUsageCursor(ctx context.Context) (int64, error) {
+	var lastID int64
+	err := scanSingleRow(ctx, r.q(ctx), `
+		SELECT last_id FROM activity_task_cursors WHERE name = $1 FOR UPDATE
+	`, []any{activityTaskUsageCursor}, &lastID)
+	if !errors.Is(err, sql.ErrNoRows) {
+		return lastID, err
+	}
+
+	// 游标缺失时从当前最新记录开始，不回放历史
+	if _, err := r.q(ctx).ExecContext(ctx, `
+		INSERT INTO activity_task_cursors (name, last_id)
+		SELECT $1, COALESCE(MAX(id), 0) FROM usage_logs
+		ON CONFLICT (name) DO NOTHING
+	`, activityTaskUsageCursor); err != nil {
+		return 0, err
+	}
+	err = scanSingleRow(ctx, r.q(ctx), `
+		SELECT last_id FROM activity_task_cursors WHERE name = $1 FOR UPDATE
+	`, []any{activityTaskUsageCursor}, &lastID)
+	return lastID, err
+}
+
+func (r *activityTaskRepository) SaveUsageCursor(ctx context.Context, lastID int64) error {
+	_, err := r.q(ctx).ExecContext(ctx, `
+		UPDATE activity_task_cursors SET last_id = $2, updated_at = NOW() WHERE name = $1
+	`, activityTaskUsageCursor, lastID)
+	return err
+}
+
+// ListUsageEvents 遇到创建时间不早于 before 的记录即停止，保证游标不会越过它
+func (r *activityTaskRepository) ListUsageEvents(ctx context.Context, afterID int64, before time.Time, limit int) ([]service.ActivityUsageEvent, error) {
+	rows, err := r.q(ctx).QueryContext(ctx, `
+		SELECT id, user_id, model, actual_cost, created_at
+		FROM usage_logs
+		WHERE id > $1
+		ORDER BY id
+		LIMIT $2
+	`, afterID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var out []service.ActivityUsageEvent
+	for rows.Next() {
+		var ev service.ActivityUsageEvent
+		if err := rows.Scan(&ev.ID, &ev.UserID, &ev.Model, &ev.ActualCost, &ev.CreatedAt); err != nil {
+			return nil, err
+		}
+		if !ev.CreatedAt.Before(before) {
+			break
+		}
+		out = append(out, ev)
+	}
+	return out, rows.Err()
+}
+
+// ---------- 目标进度 ----------
+
+func (r *activityTaskRepository) AddProgress(ctx context.Context, key service.ActivityTaskKey, goalIndex int, delta float64) error {
+	_, err := r.q(ctx).ExecContext(ctx, `
+		INSERT INTO activity_task_progress (activity_id, user_id, period_key, goal_index, value)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (activity_id, user_id, period_key, goal_index) DO UPDATE
+		SET value = activity_task_progress.value + EXCLUDED.value, updated_at = NOW()
+	`, key.ActivityID, key.UserID, key.PeriodKey, goalIndex, delta)
+	return err
+}
+
+func (r *activityTaskRepository) TouchStreak(ctx context.Context, key service.ActivityTaskKey, goalIndex int, day time.Time) error {
+	_, err := r.q(ctx).ExecContext(ctx, `
+		INSERT INTO activity_task_progress (activity_id, user_id, period_key, goal_index, value, last_active_date)
+		VALUES ($1, $2, $3, $4, 1, $5::date)
+		ON CONFLICT (activity_id, user_id, period_key, goal_index) DO UPDATE
+		SET value = CASE
+				WHEN activity_task_progress.last_active_date IS NULL THEN 1
+				WHEN EXCLUDED.last_active_date <= activity_task_progress.last_active_date THEN activity_task_progress.value
+				WHEN EXCLUDED.last_active_date = activity_task_progress.last_active_date + 1 THEN activity_task_progress.value + 1
+				ELSE 1
+			END,
+			last_active_date = GREATEST(activity_task_progress.last_active_date, EXCLUDED.last_active_date),
+			updated_at = NOW()
+	`, key.ActivityID, key.UserID, key.PeriodKey, goalIndex, day.UTC().Format("2006-01-02"))
+	return err
+}
+
+func (r *activityTaskRepository) SetProgress(ctx context.Context, key service.ActivityTaskKey, goalIndex int, value float64) error {
+	_, err := r.q(ctx).ExecContext(ctx, `
+		INSERT INTO activity_task_progress (activity_id, user_id, period_key, goal_index, value)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (activity_id, user_id, period_key, goal_index) DO UPDATE
+		SET value = GREATEST(activity_task_progress.value, EXCLUDED.value), updated_at = NOW()
+	`, key.ActivityID, key.UserID, key.PeriodKey, goalIndex, value)
+	return err
+}
+
+func (r *activityTaskRepository) ListProgress(ctx context.Context, key service.ActivityTaskKey) ([]service.ActivityTaskProgress, error) {
+	rows, err := r.q(ctx).QueryContext(ctx, `
+		SELECT goal_index, value, last_active_date, updated_at
+		FROM activity_task_progress
+		WHERE activity_id = $1 AND user_id = $2 AND period_key = $3
+		ORDER BY goal_index
+	`, key.ActivityID, key.UserID, key.PeriodKey)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var out []service.ActivityTaskProgress
+	for rows.Next() {
+		var p service.ActivityTaskProgress
+		var lastActive sql.NullTime
+		if err := rows.Scan(&p.GoalIndex, &p.Value, &lastActive, &p.UpdatedAt); err != nil {
+			return nil, err
+		}
+		p.LastActiveDate = nullTimePtr(lastActive)
+		out = append(out, p)
+	}
+	return out, rows.Err()
+}
+
+// ---------- 完成记录 ----------
+
+func (r *activityTaskRepository) CreateCompletion(ctx context.Context, key service.ActivityTaskKey) (bool, error) {
+	result, err := r.q(ctx).ExecContext(ctx, `
+		INSERT INTO activity_task_completions (activity_id, user_id, period_key, status)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (activity_id, user_id, period_key) DO NOTHING
+	`, key.ActivityID, key.UserID, key.PeriodKey, service.ActivityTaskCompletionPending)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+func (r *activityTaskRepository) GetCompletion(ctx context.Context, key service.ActivityTaskKey) (*service.ActivityTaskCompletion, error) {
+	c := service.ActivityTaskCompletion{ActivityTaskKey: key}
+	var participationID sql.NullInt64
+	var claimedAt sql.NullTime
+	err := scanSingleRow(ctx, r.q(ctx), `
+		SELECT id, status, participation_id, completed_at, claimed_at
+		FROM activity_task_completions
+		WHERE activity_id = $1 AND user_id = $2 AND period_key = $3
+	`, []any{key.ActivityID, key.UserID, key.PeriodKey}, &c.ID, &c.Status, &participationID, &c.CompletedAt, &claimedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	c.ParticipationID = nullInt64Value(participationID)
+	c.ClaimedAt = nullTimePtr(claimedAt)
+	return &c, nil
+}
+
+func (r *activityTaskRepository) ClaimCompletion(ctx context.Context, key service.ActivityTaskKey) (bool, error) {
+	result, err := r.q(ctx).ExecContext(ctx, `
+		UPDATE activity_task_completions
+		SET status = $4, claimed_at = NOW()
+		WHERE activity_id = $1 AND user_id = $2 AND period_key = $3 AND status = $5
+	`, key.ActivityID, key.UserID, key.PeriodKey, service.ActivityTaskCompletionClaimed, service.ActivityTaskCompletionPending)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+func (r *activityTaskRepository) ReleaseCompletion(ctx context.Context, key service.ActivityTaskKey) error {
+	_, err := r.q(ctx).ExecContext(ctx, `
+		UPDATE activity_task_completions
+		SET status = $4, claimed_at = NULL
+		WHERE activity_id = $1 AND user_id = $2 AND period_key = $3
+	`, key.ActivityID, key.UserID, key.PeriodKey, service.ActivityTaskCompletionPending)
+	return err
+}
+
+func (r *activityTaskRepository) SetCompletionParticipation(ctx context.Context, key service.ActivityTaskKey, participationID int64) error {
+	_, err := r.q(ctx).ExecContext(ctx, `
+		UPDATE activity_task_completions
+		SET participation_id = $4
+		WHERE activity_id = $1 AND user_id = $2 AND period_key = $3
+	`, key.ActivityID, key.UserID, key.PeriodKey, participationID)
+	return err
+}
@@ -0,0 +1,121 @@
+//go:build integration
+
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	dbent "github.com/Wei-Shaw/sub2api/ent"
+	"github.com/Wei-Shaw/sub2api/ent/activity"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+	"github.com/stretchr/testify/require"
+)
+
+func createActivityTaskTestKey(t *testing.T, ctx context.Context) service.ActivityTaskKey {
+	t.Helper()
+	user := createReferralTestUser(t, ctx, "activity-task")
+	act, err := testEntClient(t).Activity.Create().
+		SetName(uniqueTestValue(t, "task")).
+		SetType(activity.TypeTask).
+		Save(ctx)
+	require.NoError(t, err)
+	return service.ActivityTaskKey{ActivityID: act.ID, UserID: user.ID, PeriodKey: "2026-10"}
+}
+
+func TestActivityTaskRepository_Progress(t *testing.T) {
+	ctx := context.Background()
+	repo := NewActivityTaskRepository(integrationDB)
+	key := createActivityTaskTestKey(t, ctx)
+
+	require.NoError(t, repo.AddProgress(ctx, key, 0, 1))
+	require.NoError(t, repo.AddProgress(ctx, key, 0, 2.5))
+	require.NoError(t, repo.SetProgress(ctx, key, 2, 1))
+	require.NoError(t, repo.SetProgress(ctx, key, 2, 0))
+
+	day := time.Date(2026, 10, 15, 0, 0, 0, 0, time.UTC)
+	require.NoError(t, repo.TouchStreak(ctx, key, 1, day))
+	require.NoError(t, repo.TouchStreak(ctx, key, 1, day))
+	require.NoError(t, repo.TouchStreak(ctx, key, 1, day.AddDate(0, 0, 1)))
+
+	progress, err := repo.ListProgress(ctx, key)
+	require.NoError(t, err)
+	require.Len(t, progress, 3)
+	require.InDelta(t, 3.5, progress[0].Value, 1e-9)
+	require.Equal(t, float64(2), progress[1].Value)
+	require.NotNil(t, progress[1].LastActiveDate)
+	require.Equal(t, "2026-10-16", progress[1].LastActiveDate.Format("2006-01-02"))
+	require.Equal(t, float64(1), progress[2].Value, "state goals never decrease")
+
+	// 中断一天后重新计数
+	require.NoError(t, repo.TouchStreak(ctx, key, 1, day.AddDate(0, 0, 3)))
+	progress, err = repo.ListProgress(ctx, key)
+	require.NoError(t, err)
+	require.Equal(t, float64(1), progress[1].Value)
+}
+
+func TestActivityTaskRepository_Completion(t *testing.T) {
+	ctx := context.Background()
+	repo := NewActivityTaskRepository(integrationDB)
+	key := createActivityTaskTestKey(t, ctx)
+
+	got, err := repo.GetCompletion(ctx, key)
+	require.NoError(t, err)
+	require.Nil(t, got)
+
+	ok, err := repo.ClaimCompletion(ctx, key)
+	require.NoError(t, err)
+	require.False(t, ok, "nothing to claim before completion")
+
+	ok, err = repo.CreateCompletion(ctx, key)
+	require.NoError(t, err)
+	require.True(t, ok)
+	ok, err = repo.CreateCompletion(ctx, key)
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	ok, err = repo.ClaimCompletion(ctx, key)
+	require.NoError(t, err)
+	require.True(t, ok)
+	ok, err = repo.ClaimCompletion(ctx, key)
+	require.NoError(t, err)
+	require.False(t, ok, "claim is idempotent")
+
+	require.NoError(t, repo.ReleaseCompletion(ctx, key))
+	got, err = repo.GetCompletion(ctx, key)
+	require.NoError(t, err)
+	require.Equal(t, service.ActivityTaskCompletionPending, got.Status)
+	require.Nil(t, got.ClaimedAt)
+
+	ok, err = repo.ClaimCompletion(ctx, key)
+	require.NoError(t, err)
+	require.True(t, ok)
+	got, err = repo.GetCompletion(ctx, key)
+	require.NoError(t, err)
+	require.Equal(t, service.ActivityTaskCompletionClaimed, got.Status)
+	require.NotNil(t, got.ClaimedAt)
+}
+
+func TestActivityTaskRepository_UsageCursor(t *testing.T) {
+	ctx := context.Background()
+	repo := NewActivityTaskRepository(integrationDB)
+
+	tx, err := testEntClient(t).Tx(ctx)
+	require.NoError(t, err)
+	defer func() { _ = tx.Rollback() }()
+	txCtx := dbent.NewTxContext(ctx, tx)
+
+	lastID, err := repo.LockUsageCursor(txCtx)
+	require.NoError(t, err)
+	require.NoError(t, repo.SaveUsageCursor(txCtx, lastID+5))
+	got, err := repo.LockUsageCursor(txCtx)
+	require.NoError(t, err)
+	require.Equal(t, lastID+5, got)
+
+	events, err := repo.ListUsageEvents(txCtx, lastID, time.Now().Add(-time.Hour), 10)
+	require.NoError(t, err)
+	for _, ev := range events {
+		require.Greater(t, ev.ID, lastID)
+	}
+}
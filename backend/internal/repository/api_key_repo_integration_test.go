@@ -9,6 +9,7 @@ import (
 	"time"
 
 	dbent "github.com/Wei-Shaw/sub2api/ent"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
 	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
 	"github.com/Wei-Shaw/sub2api/internal/service"
 	"github.com/stretchr/testify/require"
@@ -132,6 +133,61 @@ func (s *APIKeyRepoSuite) TestUpdate_ClearGroupID() {
 	s.Require().Nil(got.GroupID, "expected GroupID to be cleared")
 }
 
+func (s *APIKeyRepoSuite) TestUpdate_ModelRestrictions() {
+	user := s.mustCreateUser("modelrestrict@test.com")
+	key := &service.APIKey{
+		UserID:         user.ID,
+		Key:            "sk-model-restrict",
+		Name:           "Contractor",
+		Status:         service.StatusActive,
+		ModelAllowlist: []string{"claude-*-haiku-*"},
+		ModelDenylist:  []string{"*opus*"},
+	}
+	s.Require().NoError(s.repo.Create(s.ctx, key))
+
+	got, err := s.repo.GetByKeyForAuth(s.ctx, key.Key)
+	s.Require().NoError(err)
+	s.Require().Equal([]string{"claude-*-haiku-*"}, got.ModelAllowlist)
+	s.Require().Equal([]string{"*opus*"}, got.ModelDenylist)
+
+	key.ModelAllowlist = nil
+	s.Require().NoError(s.repo.Update(s.ctx, key))
+
+	got, err = s.repo.GetByID(s.ctx, key.ID)
+	s.Require().NoError(err)
+	s.Require().Empty(got.ModelAllowlist, "expected allowlist to be cleared")
+	s.Require().Equal([]string{"*opus*"}, got.ModelDenylist)
+}
+
+func (s *APIKeyRepoSuite) TestUpdate_TrafficLimits() {
+	user := s.mustCreateUser("trafficlimit@test.com")
+	key := &service.APIKey{
+		UserID:         user.ID,
+		Key:            "sk-traffic-limit",
+		Name:           "CI",
+		Status:         service.StatusActive,
+		RPMLimit:       60,
+		TPMLimit:       100000,
+		MaxConcurrency: 2,
+	}
+	s.Require().NoError(s.repo.Create(s.ctx, key))
+
+	got, err := s.repo.GetByKeyForAuth(s.ctx, key.Key)
+	s.Require().NoError(err)
+	s.Require().Equal(60, got.RPMLimit)
+	s.Require().Equal(100000, got.TPMLimit)
+	s.Require().Equal(2, got.MaxConcurrency)
+
+	key.TPMLimit = 0
+	s.Require().NoError(s.repo.Update(s.ctx, key))
+
+	got, err = s.repo.GetByID(s.ctx, key.ID)
+	s.Require().NoError(err)
+	s.Require().Equal(60, got.RPMLimit)
+	s.Require().Zero(got.TPMLimit, "expected tpm limit to be cleared")
+	s.Require().Equal(2, got.MaxConcurrency)
+}
+
 // --- Delete ---
 
 func (s *APIKeyRepoSuite) TestDelete() {
@@ -393,17 +449,17 @@ func (s *APIKeyRepoSuite) TestIncrementQuotaUsed_Basic() {
 	user := s.mustCreateUser("incr-basic@test.com")
 	key := s.mustCreateApiKey(user.ID, "sk-incr-basic", "Incr", nil)
 
-	newQuota, err := s.repo.IncrementQuotaUsed(s.ctx, key.ID, 1.5)
+	newQuota, err := s.repo.IncrementQuotaUsed(s.ctx, key.ID, money.MustParse("1.5"))
 	s.Require().NoError(err, "IncrementQuotaUsed")
-	s.Require().Equal(1.5, newQuota, "第一次递增后应为 1.5")
+	s.Require().Equal(money.MustParse("1.5"), newQuota, "第一次递增后应为 1.5")
 
-	newQuota, err = s.repo.IncrementQuotaUsed(s.ctx, key.ID, 2.5)
+	newQuota, err = s.repo.IncrementQuotaUsed(s.ctx, key.ID, money.MustParse("2.5"))
 	s.Require().NoError(err, "IncrementQuotaUsed second")
-	s.Require().Equal(4.0, newQuota, "第二次递增后应为 4.0")
+	s.Require().Equal(4*money.USD, newQuota, "第二次递增后应为 4.0")
 }
 
 func (s *APIKeyRepoSuite) TestIncrementQuotaUsed_NotFound() {
-	_, err := s.repo.IncrementQuotaUsed(s.ctx, 999999, 1.0)
+	_, err := s.repo.IncrementQuotaUsed(s.ctx, 999999, money.USD)
 	s.Require().ErrorIs(err, service.ErrAPIKeyNotFound, "不存在的 key 应返回 ErrAPIKeyNotFound")
 }
 
@@ -413,7 +469,7 @@ func (s *APIKeyRepoSuite) TestIncrementQuotaUsed_DeletedKey() {
 
 	s.Require().NoError(s.repo.Delete(s.ctx, key.ID), "Delete")
 
-	_, err := s.repo.IncrementQuotaUsed(s.ctx, key.ID, 1.0)
+	_, err := s.repo.IncrementQuotaUsed(s.ctx, key.ID, money.USD)
 	s.Require().ErrorIs(err, service.ErrAPIKeyNotFound, "已删除的 key 应返回 ErrAPIKeyNotFound")
 }
 
@@ -447,7 +503,7 @@ func TestIncrementQuotaUsed_Concurrent(t *testing.T) {
 
 	// 10 个 goroutine 各递增 1.0，总计应为 10.0
 	const goroutines = 10
-	const increment = 1.0
+	const increment = money.USD
 	var wg sync.WaitGroup
 	errs := make([]error, goroutines)
 
@@ -467,6 +523,6 @@ func TestIncrementQuotaUsed_Concurrent(t *testing.T) {
 	// 验证最终结果
 	got, err := repo.GetByID(ctx, k.ID)
 	require.NoError(t, err, "GetByID")
-	require.Equal(t, float64(goroutines)*increment, got.QuotaUsed,
-		"并发递增后总和应为 %v，实际为 %v", float64(goroutines)*increment, got.QuotaUsed)
+	require.Equal(t, goroutines*increment, got.QuotaUsed,
+		"并发递增后总和应为 %v，实际为 %v", goroutines*increment, got.QuotaUsed)
 }
@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/service"
+	"github.com/redis/go-redis/v9"
+)
+
+// API Key 流量计数缓存常量定义
+//
+// 设计说明：
+// 每个 API Key 每分钟一个哈希键，字段 requests/tokens 分别记录请求数与 token 数：
+// - Key: api_key_traffic:{apiKeyID}:{minuteTimestamp}
+// - TTL: 120 秒（覆盖当前分钟 + 冗余）
+//
+// 与账号 RPM 计数一致，分钟时间戳由 rdb.Time() 获取的服务端时间计算，避免多实例时钟偏差；
+// 键名在客户端拼好后传入 KEYS，Lua 内不动态拼 key，兼容 Redis Cluster。
+const (
+	apiKeyTrafficKeyPrefix = "api_key_traffic:"
+	apiKeyTrafficKeyTTL    = 120 * time.Second
+)
+
+// apiKeyTrafficAdmitScript 原子检查 RPM/TPM 上限并递增请求数
+// KEYS[1] = api_key_traffic:{apiKeyID}:{minute}
+// ARGV[1] = rpmLimit（0 = 不限制）
+// ARGV[2] = tpmLimit（0 = 不限制）
+// ARGV[3] = TTL（秒）
+// 返回 {requests, tokens, admitted}
+var apiKeyTrafficAdmitScript = redis.NewScript(`
+	local key = KEYS[1]
+	local rpmLimit = tonumber(ARGV[1])
+	local tpmLimit = tonumber(ARGV[2])
+
+	local requests = tonumber(redis.call('HGET', key, 'requests') or '0')
+	local tokens = tonumber(redis.call('HGET', key, 'tokens') or '0')
+
+	if tpmLimit > 0 and tokens >= tpmLimit then
+		return {requests, tokens, 0}
+	end
+	if rpmLimit > 0 and requests >= rpmLimit then
+		return {requests, tokens, 0}
+	end
+
+	requests = redis.call('HINCRBY', key, 'requests', 1)
+	redis.call('EXPIRE', key, ARGV[3])
+	return {requests, tokens, 1}
+`)
+
+type apiKeyTrafficCache struct {
+	rdb *redis.Client
+}
+
+// NewAPIKeyTrafficCache 创建 API Key 流量计数缓存
+func NewAPIKeyTrafficCache(rdb *redis.Client) service.APIKeyTrafficCache {
+	return &apiKeyTrafficCache{rdb: rdb}
+}
+
+// currentWindow 返回当前分钟窗口的键与距重置的剩余时间
+func (c *apiKeyTrafficCache) currentWindow(ctx context.Context, apiKeyID int64) (string, time.Duration, error) {
+	serverTime, err := c.rdb.Time(ctx).Result()
+	if err != nil {
+		return "", 0, fmt.Errorf("redis TIME: %w", err)
+	}
+	minuteTS := serverTime.Unix() / 60
+	resetAt := time.Unix((minuteTS+1)*60, 0)
+	return fmt.Sprintf("%s%d:%d", apiKeyTrafficKeyPrefix, apiKeyID, minuteTS), resetAt.Sub(serverTime), nil
+}
+
+func (c *apiKeyTrafficCache) Admit(ctx context.Context, apiKeyID int64, rpmLimit, tpmLimit int) (*service.APIKeyTrafficWindow, error) {
+	key, resetAfter, err := c.currentWindow(ctx, apiKeyID)
+	if err != nil {
+		return nil, fmt.Errorf("api key traffic admit: %w", err)
+	}
+
+	values, err := apiKeyTrafficAdmitScript.Run(ctx, c.rdb, []string{key}, rpmLimit, tpmLimit, int(apiKeyTrafficKeyTTL.Seconds())).Int64Slice()
+	if err != nil {
+		return nil, fmt.Errorf("api key traffic admit: %w", err)
+	}
+	if len(values) != 3 {
+		return nil, fmt.Errorf("api key traffic admit: unexpected script result %v", values)
+	}
+	return &service.APIKeyTrafficWindow{
+		Requests:   int(values[0]),
+		Tokens:     int(values[1]),
+		ResetAfter: resetAfter,
+		Admitted:   values[2] == 1,
+	}, nil
+}
+
+// AddTokens 使用 TxPipeline (MULTI/EXEC) 执行 HINCRBY + EXPIRE，保证原子性
+func (c *apiKeyTrafficCache) AddTokens(ctx context.Context, apiKeyID int64, tokens int) error {
+	key, _, err := c.currentWindow(ctx, apiKeyID)
+	if err != nil {
+		return fmt.Errorf("api key traffic add tokens: %w", err)
+	}
+
+	pipe := c.rdb.TxPipeline()
+	pipe.HIncrBy(ctx, key, "tokens", int64(tokens))
+	pipe.Expire(ctx, key, apiKeyTrafficKeyTTL)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("api key traffic add tokens: %w", err)
+	}
+	return nil
+}
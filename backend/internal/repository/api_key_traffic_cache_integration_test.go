@@ -0,0 +1,79 @@
+//go:build integration
+
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/service"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type APIKeyTrafficCacheSuite struct {
+	IntegrationRedisSuite
+	cache service.APIKeyTrafficCache
+}
+
+func (s *APIKeyTrafficCacheSuite) SetupTest() {
+	s.IntegrationRedisSuite.SetupTest()
+	s.cache = NewAPIKeyTrafficCache(s.rdb)
+}
+
+func (s *APIKeyTrafficCacheSuite) TestAdmitRejectsAtRPMLimit() {
+	apiKeyID := int64(10)
+
+	for i := 1; i <= 2; i++ {
+		window, err := s.cache.Admit(s.ctx, apiKeyID, 2, 0)
+		s.RequireNoError(err)
+		require.True(s.T(), window.Admitted, "request %d should be admitted", i)
+		require.Equal(s.T(), i, window.Requests)
+	}
+
+	window, err := s.cache.Admit(s.ctx, apiKeyID, 2, 0)
+	s.RequireNoError(err)
+	require.False(s.T(), window.Admitted, "third request exceeds rpm limit")
+	require.Equal(s.T(), 2, window.Requests, "rejected requests are not counted")
+	require.Greater(s.T(), window.ResetAfter, time.Duration(0))
+	require.LessOrEqual(s.T(), window.ResetAfter, time.Minute)
+}
+
+func (s *APIKeyTrafficCacheSuite) TestAdmitRejectsOnceTPMReached() {
+	apiKeyID := int64(11)
+
+	window, err := s.cache.Admit(s.ctx, apiKeyID, 0, 1000)
+	s.RequireNoError(err)
+	require.True(s.T(), window.Admitted)
+
+	s.RequireNoError(s.cache.AddTokens(s.ctx, apiKeyID, 600))
+	window, err = s.cache.Admit(s.ctx, apiKeyID, 0, 1000)
+	s.RequireNoError(err)
+	require.True(s.T(), window.Admitted, "still under tpm limit")
+	require.Equal(s.T(), 600, window.Tokens)
+
+	s.RequireNoError(s.cache.AddTokens(s.ctx, apiKeyID, 400))
+	window, err = s.cache.Admit(s.ctx, apiKeyID, 0, 1000)
+	s.RequireNoError(err)
+	require.False(s.T(), window.Admitted, "tpm limit reached")
+	require.Equal(s.T(), 1000, window.Tokens)
+}
+
+func (s *APIKeyTrafficCacheSuite) TestWindowKeyHasTTL() {
+	apiKeyID := int64(12)
+
+	_, err := s.cache.Admit(s.ctx, apiKeyID, 5, 0)
+	s.RequireNoError(err)
+
+	keys, err := s.rdb.Keys(s.ctx, apiKeyTrafficKeyPrefix+"12:*").Result()
+	s.RequireNoError(err)
+	require.Len(s.T(), keys, 1)
+
+	ttl, err := s.rdb.TTL(s.ctx, keys[0]).Result()
+	s.RequireNoError(err)
+	s.AssertTTLWithin(ttl, 1*time.Second, apiKeyTrafficKeyTTL)
+}
+
+func TestAPIKeyTrafficCacheSuite(t *testing.T) {
+	suite.Run(t, new(APIKeyTrafficCacheSuite))
+}
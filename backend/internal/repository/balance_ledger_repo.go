@@ -0,0 +1,214 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+)
+
+// balanceLedgerReconcileLockID 对账任务的 advisory lock ID（事务级，事务结束自动释放）
+const balanceLedgerReconcileLockID int64 = 0x62616c6c6564 // "balled"
+
+type balanceLedgerRepository struct {
+	db *sql.DB
+}
+
+func NewBalanceLedgerRepository(sqlDB *sql.DB) service.BalanceLedgerRepository {
+	return &balanceLedgerRepository{db: sqlDB}
+}
+
+func (r *balanceLedgerRepository) List(ctx context.Context, params pagination.PaginationParams, filter service.BalanceLedgerFilter) ([]service.BalanceLedgerEntry, *pagination.PaginationResult, error) {
+	where, args := buildBalanceLedgerWhere(filter)
+
+	var total int64
+	if err := scanSingleRow(ctx, r.db, "SELECT COUNT(*) FROM balance_ledger "+where, args, &total); err != nil {
+		return nil, nil, err
+	}
+	if total == 0 {
+		return []service.BalanceLedgerEntry{}, paginationResultFromTotal(0, params), nil
+	}
+
+	query := fmt.Sprintf(`
+		SELECT id, user_id, source_type, reference_id, operator_id, amount,
+			balance_before, balance_after, notes, created_at
+		FROM balance_ledger
+		%s
+		ORDER BY id DESC
+		LIMIT $%d OFFSET $%d
+	`, where, len(args)+1, len(args)+2)
+	rows, err := r.db.QueryContext(ctx, query, append(args, params.Limit(), params.Offset())...)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	entries := make([]service.BalanceLedgerEntry, 0)
+	for rows.Next() {
+		var entry service.BalanceLedgerEntry
+		var operatorID sql.NullInt64
+		if err := rows.Scan(
+			&entry.ID,
+			&entry.UserID,
+			&entry.SourceType,
+			&entry.ReferenceID,
+			&operatorID,
+			&entry.Amount,
+			&entry.BalanceBefore,
+			&entry.BalanceAfter,
+			&entry.Notes,
+			&entry.CreatedAt,
+		); err != nil {
+			return nil, nil, err
+		}
+		if operatorID.Valid {
+			v := operatorID.Int64
+			entry.OperatorID = &v
+		}
+		entries = append(entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+	return entries, paginationResultFromTotal(total, params), nil
+}
+
+func buildBalanceLedgerWhere(filter service.BalanceLedgerFilter) (string, []any) {
+	conditions := make([]string, 0, 6)
+	args := make([]any, 0, 6)
+	add := func(cond string, arg any) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(cond, len(args)))
+	}
+
+	if filter.UserID > 0 {
+		add("user_id = $%d", filter.UserID)
+	}
+	if v := strings.TrimSpace(filter.SourceType); v != "" {
+		add("source_type = $%d", v)
+	}
+	if v := strings.TrimSpace(filter.ReferenceID); v != "" {
+		add("reference_id = $%d", v)
+	}
+	if filter.OperatorID != nil {
+		add("operator_id = $%d", *filter.OperatorID)
+	}
+	if filter.StartTime != nil {
+		add("created_at >= $%d", *filter.StartTime)
+	}
+	if filter.EndTime != nil {
+		add("created_at < $%d", *filter.EndTime)
+	}
+
+	if len(conditions) == 0 {
+		return "", args
+	}
+	return "WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// Reconcile 在单条查询的快照内比对余额与流水合计：
+// 余额变更与流水写入是同一条语句，快照中两者总是同时可见或同时不可见，不会产生误报。
+// COUNT(*) OVER () 在 LIMIT 之前计算，返回的是不一致用户总数，样本只取前 sampleLimit 个。
+func (r *balanceLedgerRepository) Reconcile(ctx context.Context, sampleLimit int) (*service.BalanceLedgerReconcileRun, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var locked bool
+	if err := scanSingleRow(ctx, tx, "SELECT pg_try_advisory_xact_lock($1)", []any{balanceLedgerReconcileLockID}, &locked); err != nil {
+		return nil, err
+	}
+	if !locked {
+		return nil, service.ErrBalanceLedgerReconcileBusy
+	}
+
+	run := &service.BalanceLedgerReconcileRun{
+		StartedAt: time.Now(),
+		Samples:   []service.BalanceLedgerMismatch{},
+	}
+
+	if err := scanSingleRow(ctx, tx, "SELECT COUNT(*) FROM users WHERE deleted_at IS NULL", nil, &run.CheckedUsers); err != nil {
+		return nil, err
+	}
+
+	if sampleLimit <= 0 {
+		sampleLimit = 1
+	}
+	rows, err := tx.QueryContext(ctx, `
+		SELECT u.id, u.balance, COALESCE(l.total, 0), COUNT(*) OVER ()
+		FROM users u
+		LEFT JOIN (
+			SELECT user_id, SUM(amount) AS total
+			FROM balance_ledger
+			GROUP BY user_id
+		) l ON l.user_id = u.id
+		WHERE u.deleted_at IS NULL
+		  AND u.balance <> COALESCE(l.total, 0)
+		ORDER BY u.id
+		LIMIT $1
+	`, sampleLimit)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var m service.BalanceLedgerMismatch
+		if err := rows.Scan(&m.UserID, &m.Balance, &m.LedgerSum, &run.MismatchCount); err != nil {
+			_ = rows.Close()
+			return nil, err
+		}
+		run.Samples = append(run.Samples, m)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	samplesJSON, err := json.Marshal(run.Samples)
+	if err != nil {
+		return nil, fmt.Errorf("marshal reconcile samples: %w", err)
+	}
+	run.FinishedAt = time.Now()
+	if err := scanSingleRow(ctx, tx, `
+		INSERT INTO balance_ledger_reconcile_runs (started_at, finished_at, checked_users, mismatch_count, samples)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id
+	`, []any{run.StartedAt, run.FinishedAt, run.CheckedUsers, run.MismatchCount, samplesJSON}, &run.ID); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return run, nil
+}
+
+func (r *balanceLedgerRepository) LatestReconcileRun(ctx context.Context) (*service.BalanceLedgerReconcileRun, error) {
+	run := &service.BalanceLedgerReconcileRun{}
+	var samplesJSON []byte
+	err := scanSingleRow(ctx, r.db, `
+		SELECT id, started_at, finished_at, checked_users, mismatch_count, samples
+		FROM balance_ledger_reconcile_runs
+		ORDER BY id DESC
+		LIMIT 1
+	`, nil, &run.ID, &run.StartedAt, &run.FinishedAt, &run.CheckedUsers, &run.MismatchCount, &samplesJSON)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(samplesJSON, &run.Samples); err != nil {
+		return nil, fmt.Errorf("parse reconcile samples: %w", err)
+	}
+	return run, nil
+}
@@ -0,0 +1,158 @@
+//go:build integration
+
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	dbent "github.com/Wei-Shaw/sub2api/ent"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+	"github.com/stretchr/testify/require"
+)
+
+func createLedgerTestUser(t *testing.T, repo *userRepository, balance money.Amount) *service.User {
+	t.Helper()
+	u := &service.User{
+		Email:        uniqueTestValue(t, "ledger") + "-" + time.Now().Format("150405.000000000") + "@example.com",
+		PasswordHash: "test-password-hash",
+		Role:         service.RoleUser,
+		Status:       service.StatusActive,
+		Concurrency:  5,
+		Balance:      balance,
+	}
+	require.NoError(t, repo.Create(context.Background(), u))
+	return u
+}
+
+func TestBalanceLedger_RecordsEveryBalanceChange(t *testing.T) {
+	ctx := context.Background()
+	userRepo := newUserRepositoryWithSQL(testEntClient(t), integrationDB)
+	ledgerRepo := NewBalanceLedgerRepository(integrationDB)
+
+	user := createLedgerTestUser(t, userRepo, 10*money.USD)
+	operatorID := int64(42)
+	require.NoError(t, userRepo.UpdateBalance(ctx, user.ID, money.MustParse("2.5"), service.BalanceLedgerSource{
+		Type:        service.BalanceLedgerSourceAdminAdjust,
+		ReferenceID: "ADJ-1",
+		OperatorID:  &operatorID,
+		Notes:       "manual credit",
+	}))
+	require.NoError(t, userRepo.DeductBalance(ctx, user.ID, money.MustParse("0.0000001234"), service.BalanceLedgerSource{
+		Type:        service.BalanceLedgerSourceUsage,
+		ReferenceID: "req-1",
+	}))
+
+	// Update 不再写 balance 列，避免覆盖并发扣费
+	got, err := userRepo.GetByID(ctx, user.ID)
+	require.NoError(t, err)
+	got.Balance = 999 * money.USD
+	require.NoError(t, userRepo.Update(ctx, got))
+
+	entries, page, err := ledgerRepo.List(ctx, pagination.PaginationParams{Page: 1, PageSize: 10}, service.BalanceLedgerFilter{UserID: user.ID})
+	require.NoError(t, err)
+	require.Equal(t, int64(3), page.Total)
+	require.Len(t, entries, 3)
+
+	usage, adjust, initial := entries[0], entries[1], entries[2]
+	require.Equal(t, service.BalanceLedgerSourceInitial, initial.SourceType)
+	require.Equal(t, 10*money.USD, initial.Amount)
+	require.Zero(t, initial.BalanceBefore)
+	require.Equal(t, 10*money.USD, initial.BalanceAfter)
+
+	require.Equal(t, service.BalanceLedgerSourceAdminAdjust, adjust.SourceType)
+	require.Equal(t, "ADJ-1", adjust.ReferenceID)
+	require.NotNil(t, adjust.OperatorID)
+	require.Equal(t, operatorID, *adjust.OperatorID)
+	require.Equal(t, "manual credit", adjust.Notes)
+	require.Equal(t, 10*money.USD, adjust.BalanceBefore)
+	require.Equal(t, money.MustParse("12.5"), adjust.BalanceAfter)
+
+	require.Equal(t, service.BalanceLedgerSourceUsage, usage.SourceType)
+	require.Equal(t, "req-1", usage.ReferenceID)
+	require.Nil(t, usage.OperatorID)
+	require.Equal(t, -money.MustParse("0.0000001234"), usage.Amount)
+	require.Equal(t, money.MustParse("12.4999998766"), usage.BalanceAfter)
+
+	final, err := userRepo.GetByID(ctx, user.ID)
+	require.NoError(t, err)
+	require.Equal(t, usage.BalanceAfter, final.Balance)
+
+	filtered, _, err := ledgerRepo.List(ctx, pagination.PaginationParams{Page: 1, PageSize: 10}, service.BalanceLedgerFilter{
+		UserID:     user.ID,
+		OperatorID: &operatorID,
+	})
+	require.NoError(t, err)
+	require.Len(t, filtered, 1)
+	require.Equal(t, adjust.ID, filtered[0].ID)
+
+	_, err = integrationDB.ExecContext(ctx, "UPDATE balance_ledger SET amount = 0 WHERE id = $1", usage.ID)
+	require.Error(t, err, "ledger rows are append-only")
+	_, err = integrationDB.ExecContext(ctx, "DELETE FROM balance_ledger WHERE id = $1", usage.ID)
+	require.Error(t, err, "ledger rows are append-only")
+}
+
+func TestBalanceLedger_RollsBackWithTransaction(t *testing.T) {
+	ctx := context.Background()
+	client := testEntClient(t)
+	userRepo := newUserRepositoryWithSQL(client, integrationDB)
+	ledgerRepo := NewBalanceLedgerRepository(integrationDB)
+	user := createLedgerTestUser(t, userRepo, 5*money.USD)
+
+	tx, err := client.Tx(ctx)
+	require.NoError(t, err)
+	txCtx := dbent.NewTxContext(ctx, tx)
+	require.NoError(t, userRepo.DeductBalance(txCtx, user.ID, money.USD, service.BalanceLedgerSource{Type: service.BalanceLedgerSourceUsage, ReferenceID: "req-rollback"}))
+	require.NoError(t, tx.Rollback())
+
+	entries, _, err := ledgerRepo.List(ctx, pagination.PaginationParams{Page: 1, PageSize: 10}, service.BalanceLedgerFilter{ReferenceID: "req-rollback"})
+	require.NoError(t, err)
+	require.Empty(t, entries)
+
+	got, err := userRepo.GetByID(ctx, user.ID)
+	require.NoError(t, err)
+	require.Equal(t, 5*money.USD, got.Balance)
+}
+
+func TestBalanceLedger_UnknownUser(t *testing.T) {
+	userRepo := newUserRepositoryWithSQL(testEntClient(t), integrationDB)
+	err := userRepo.UpdateBalance(context.Background(), 987654321, money.USD, service.BalanceLedgerSource{Type: service.BalanceLedgerSourceRedeem})
+	require.ErrorIs(t, err, service.ErrUserNotFound)
+}
+
+func TestBalanceLedger_ReconcileDetectsMismatch(t *testing.T) {
+	ctx := context.Background()
+	userRepo := newUserRepositoryWithSQL(testEntClient(t), integrationDB)
+	ledgerRepo := NewBalanceLedgerRepository(integrationDB)
+
+	consistent := createLedgerTestUser(t, userRepo, 3*money.USD)
+	drifted := createLedgerTestUser(t, userRepo, 3*money.USD)
+	// 绕过仓储直接改余额，模拟未记流水的变更
+	_, err := integrationDB.ExecContext(ctx, "UPDATE users SET balance = balance + 1 WHERE id = $1", drifted.ID)
+	require.NoError(t, err)
+
+	run, err := ledgerRepo.Reconcile(ctx, 10000)
+	require.NoError(t, err)
+	require.NotZero(t, run.ID)
+	require.Positive(t, run.CheckedUsers)
+	require.Positive(t, run.MismatchCount)
+
+	samples := make(map[int64]service.BalanceLedgerMismatch, len(run.Samples))
+	for _, m := range run.Samples {
+		samples[m.UserID] = m
+	}
+	require.NotContains(t, samples, consistent.ID)
+	require.Contains(t, samples, drifted.ID)
+	require.Equal(t, 4*money.USD, samples[drifted.ID].Balance)
+	require.Equal(t, 3*money.USD, samples[drifted.ID].LedgerSum)
+
+	latest, err := ledgerRepo.LatestReconcileRun(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, latest)
+	require.Equal(t, run.ID, latest.ID)
+	require.Equal(t, run.MismatchCount, latest.MismatchCount)
+	require.Len(t, latest.Samples, len(run.Samples))
+}
@@ -0,0 +1,532 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/service"
+	"github.com/lib/pq"
+)
+
+// batchItemInsertChunk 单条 INSERT 写入的请求行数（6 个参数/行，避免超出 PostgreSQL 65535 参数上限）
+const batchItemInsertChunk = 1000
+
+// batchStatusTimestampColumns 任务状态对应的时间戳列
+var batchStatusTimestampColumns = map[string]string{
+	service.BatchStatusInProgress: "in_progress_at",
+	service.BatchStatusFinalizing: "finalizing_at",
+	service.BatchStatusCompleted:  "completed_at",
+	service.BatchStatusExpired:    "expired_at",
+	service.BatchStatusCancelling: "cancelling_at",
+	service.BatchStatusCancelled:  "cancelled_at",
+}
+
+const batchJobSelectColumns = `
+	id, batch_id, format, user_id, api_key_id, endpoint, input_file_id, output_file_id, error_file_id,
+	completion_window, metadata, client_ip, status, total_count, completed_count, failed_count,
+	cancelled_count, expired_count, error_message, created_at, updated_at, expires_at,
+	in_progress_at, finalizing_at, completed_at, expired_at, cancelling_at, cancelled_at`
+
+// batchRepository 实现 service.BatchRepository 接口。
+// 使用原生 SQL 操作 batch_files / batch_jobs / batch_job_items 表。
+type batchRepository struct {
+	sql *sql.DB
+}
+
+// NewBatchRepository 创建批处理仓储实例。
+func NewBatchRepository(sqlDB *sql.DB) service.BatchRepository {
+	return &batchRepository{sql: sqlDB}
+}
+
+// ==================== Files ====================
+
+func (r *batchRepository) CreateFile(ctx context.Context, file *service.BatchFile) error {
+	return scanSingleRow(ctx, r.sql, `
+		INSERT INTO batch_files (file_id, user_id, api_key_id, purpose, filename, bytes, content, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, created_at
+	`, []any{
+		file.FileID, file.UserID, file.APIKeyID, file.Purpose, file.Filename, file.Bytes, file.Content, file.ExpiresAt,
+	}, &file.ID, &file.CreatedAt)
+}
+
+func (r *batchRepository) GetFile(ctx context.Context, userID int64, fileID string, withContent bool) (*service.BatchFile, error) {
+	contentColumn := "NULL::bytea"
+	if withContent {
+		contentColumn = "content"
+	}
+	query := fmt.Sprintf(`
+		SELECT id, file_id, user_id, api_key_id, purpose, filename, bytes, %s, created_at, expires_at
+		FROM batch_files
+		WHERE user_id = $1 AND file_id = $2
+	`, contentColumn)
+	rows, err := r.sql.QueryContext(ctx, query, userID, fileID)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+	if !rows.Next() {
+		return nil, rows.Err()
+	}
+	return scanBatchFile(rows)
+}
+
+func (r *batchRepository) ListFiles(ctx context.Context, userID int64, purpose string, after string, limit int) ([]*service.BatchFile, error) {
+	args := []any{userID}
+	where := []string{"user_id = $1"}
+	if purpose != "" {
+		args = append(args, purpose)
+		where = append(where, fmt.Sprintf("purpose = $%d", len(args)))
+	}
+	if after != "" {
+		args = append(args, after)
+		where = append(where, fmt.Sprintf("id < (SELECT id FROM batch_files WHERE user_id = $1 AND file_id = $%d)", len(args)))
+	}
+	args = append(args, limit)
+	query := fmt.Sprintf(`
+		SELECT id, file_id, user_id, api_key_id, purpose, filename, bytes, NULL::bytea, created_at, expires_at
+		FROM batch_files
+		WHERE %s
+		ORDER BY id DESC
+		LIMIT $%d
+	`, strings.Join(where, " AND "), len(args))
+
+	rows, err := r.sql.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	files := make([]*service.BatchFile, 0)
+	for rows.Next() {
+		file, err := scanBatchFile(rows)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, file)
+	}
+	return files, rows.Err()
+}
+
+func (r *batchRepository) DeleteFile(ctx context.Context, userID int64, fileID string) (bool, error) {
+	result, err := r.sql.ExecContext(ctx, `DELETE FROM batch_files WHERE user_id = $1 AND file_id = $2`, userID, fileID)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+func (r *batchRepository) DeleteExpiredFiles(ctx context.Context, now time.Time) (int64, error) {
+	result, err := r.sql.ExecContext(ctx, `DELETE FROM batch_files WHERE expires_at IS NOT NULL AND expires_at <= $1`, now)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func scanBatchFile(rows *sql.Rows) (*service.BatchFile, error) {
+	file := &service.BatchFile{}
+	var apiKeyID sql.NullInt64
+	var expiresAt sql.NullTime
+	if err := rows.Scan(
+		&file.ID,
+		&file.FileID,
+		&file.UserID,
+		&apiKeyID,
+		&file.Purpose,
+		&file.Filename,
+		&file.Bytes,
+		&file.Content,
+		&file.CreatedAt,
+		&expiresAt,
+	); err != nil {
+		return nil, err
+	}
+	if apiKeyID.Valid {
+		v := apiKeyID.Int64
+		file.APIKeyID = &v
+	}
+	if expiresAt.Valid {
+		v := expiresAt.Time
+		file.ExpiresAt = &v
+	}
+	return file, nil
+}
+
+// ==================== Jobs ====================
+
+func (r *batchRepository) CreateJob(ctx context.Context, job *service.BatchJob, items []*service.BatchJobItem) error {
+	if job == nil {
+		return fmt.Errorf("batch job is nil")
+	}
+	var metadata []byte
+	if len(job.Metadata) > 0 {
+		encoded, err := json.Marshal(job.Metadata)
+		if err != nil {
+			return fmt.Errorf("marshal batch metadata: %w", err)
+		}
+		metadata = encoded
+	}
+
+	tx, err := r.sql.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err := tx.QueryRowContext(ctx, `
+		INSERT INTO batch_jobs (
+			batch_id, format, user_id, api_key_id, endpoint, input_file_id, completion_window,
+			metadata, client_ip, status, total_count, expires_at, in_progress_at, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $14)
+		RETURNING id
+	`,
+		job.BatchID, job.Format, job.UserID, job.APIKeyID, job.Endpoint, job.InputFileID, job.CompletionWindow,
+		metadata, job.ClientIP, job.Status, len(items), job.ExpiresAt, job.InProgressAt, job.CreatedAt,
+	).Scan(&job.ID); err != nil {
+		return err
+	}
+	job.UpdatedAt = job.CreatedAt
+	job.Counts = service.BatchRequestCounts{Total: len(items)}
+
+	for start := 0; start < len(items); start += batchItemInsertChunk {
+		end := start + batchItemInsertChunk
+		if end > len(items) {
+			end = len(items)
+		}
+		chunk := items[start:end]
+		placeholders := make([]string, 0, len(chunk))
+		args := make([]any, 0, len(chunk)*6)
+		for i, item := range chunk {
+			base := i * 6
+			placeholders = append(placeholders, fmt.Sprintf("($%d, $%d, $%d, $%d, $%d, $%d)", base+1, base+2, base+3, base+4, base+5, base+6))
+			args = append(args, job.ID, item.LineNo, item.CustomID, item.URL, item.Body, service.BatchItemStatusPending)
+		}
+		query := `INSERT INTO batch_job_items (job_id, line_no, custom_id, url, body, status) VALUES ` + strings.Join(placeholders, ", ")
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (r *batchRepository) GetJob(ctx context.Context, userID int64, format string, batchID string) (*service.BatchJob, error) {
+	rows, err := r.sql.QueryContext(ctx, `SELECT `+batchJobSelectColumns+`
+		FROM batch_jobs
+		WHERE user_id = $1 AND format = $2 AND batch_id = $3
+	`, userID, format, batchID)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+	if !rows.Next() {
+		return nil, rows.Err()
+	}
+	return scanBatchJob(rows)
+}
+
+func (r *batchRepository) ListJobs(ctx context.Context, userID int64, format string, after string, limit int) ([]*service.BatchJob, error) {
+	args := []any{userID, format}
+	where := "user_id = $1 AND format = $2"
+	if after != "" {
+		args = append(args, after)
+		where += fmt.Sprintf(" AND id < (SELECT id FROM batch_jobs WHERE user_id = $1 AND batch_id = $%d)", len(args))
+	}
+	args = append(args, limit)
+	query := fmt.Sprintf(`SELECT %s
+		FROM batch_jobs
+		WHERE %s
+		ORDER BY id DESC
+		LIMIT $%d
+	`, batchJobSelectColumns, where, len(args))
+	return r.queryJobs(ctx, r.sql, query, args...)
+}
+
+func (r *batchRepository) UpdateJobStatus(ctx context.Context, jobID int64, fromStatuses []string, toStatus string) (bool, error) {
+	column, ok := batchStatusTimestampColumns[toStatus]
+	if !ok {
+		return false, fmt.Errorf("unknown batch status: %s", toStatus)
+	}
+	query := fmt.Sprintf(`
+		UPDATE batch_jobs
+		SET status = $2, %s = NOW(), updated_at = NOW()
+		WHERE id = $1 AND status = ANY($3)
+	`, column)
+	result, err := r.sql.ExecContext(ctx, query, jobID, toStatus, pq.Array(fromStatuses))
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+func (r *batchRepository) UpdateJobCounts(ctx context.Context, jobID int64, counts service.BatchRequestCounts) error {
+	_, err := r.sql.ExecContext(ctx, `
+		UPDATE batch_jobs
+		SET completed_count = $2, failed_count = $3, cancelled_count = $4, expired_count = $5, updated_at = NOW()
+		WHERE id = $1
+	`, jobID, counts.Succeeded, counts.Failed, counts.Cancelled, counts.Expired)
+	return err
+}
+
+func (r *batchRepository) FinalizeJob(ctx context.Context, job *service.BatchJob) error {
+	column, ok := batchStatusTimestampColumns[job.Status]
+	if !ok {
+		return fmt.Errorf("unknown batch status: %s", job.Status)
+	}
+	query := fmt.Sprintf(`
+		UPDATE batch_jobs
+		SET status = $2, output_file_id = $3, error_file_id = $4,
+			total_count = $5, completed_count = $6, failed_count = $7, cancelled_count = $8, expired_count = $9,
+			%s = NOW(), updated_at = NOW(), locked_until = NULL
+		WHERE id = $1
+	`, column)
+	_, err := r.sql.ExecContext(ctx, query,
+		job.ID, job.Status, job.OutputFileID, job.ErrorFileID,
+		job.Counts.Total, job.Counts.Succeeded, job.Counts.Failed, job.Counts.Cancelled, job.Counts.Expired,
+	)
+	return err
+}
+
+func (r *batchRepository) ClaimActiveJobs(ctx context.Context, lease time.Duration, limit int) ([]*service.BatchJob, error) {
+	if limit <= 0 {
+		limit = 1
+	}
+	query := fmt.Sprintf(`
+		UPDATE batch_jobs
+		SET locked_until = NOW() + ($1 * interval '1 second')
+		WHERE id IN (
+			SELECT id FROM batch_jobs
+			WHERE status = ANY($2)
+				AND (locked_until IS NULL OR locked_until <= NOW())
+			ORDER BY updated_at ASC, id ASC
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING %s
+	`, batchJobSelectColumns)
+	activeStatuses := []string{service.BatchStatusInProgress, service.BatchStatusFinalizing, service.BatchStatusCancelling}
+	return r.queryJobs(ctx, r.sql, query, int64(lease.Seconds()), pq.Array(activeStatuses), limit)
+}
+
+func (r *batchRepository) ReleaseJob(ctx context.Context, jobID int64) error {
+	_, err := r.sql.ExecContext(ctx, `UPDATE batch_jobs SET locked_until = NULL, updated_at = NOW() WHERE id = $1`, jobID)
+	return err
+}
+
+func (r *batchRepository) queryJobs(ctx context.Context, q sqlQueryer, query string, args ...any) ([]*service.BatchJob, error) {
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	jobs := make([]*service.BatchJob, 0)
+	for rows.Next() {
+		job, err := scanBatchJob(rows)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, rows.Err()
+}
+
+func scanBatchJob(rows *sql.Rows) (*service.BatchJob, error) {
+	job := &service.BatchJob{}
+	var metadata []byte
+	var inProgressAt, finalizingAt, completedAt, expiredAt, cancellingAt, cancelledAt sql.NullTime
+	if err := rows.Scan(
+		&job.ID,
+		&job.BatchID,
+		&job.Format,
+		&job.UserID,
+		&job.APIKeyID,
+		&job.Endpoint,
+		&job.InputFileID,
+		&job.OutputFileID,
+		&job.ErrorFileID,
+		&job.CompletionWindow,
+		&metadata,
+		&job.ClientIP,
+		&job.Status,
+		&job.Counts.Total,
+		&job.Counts.Succeeded,
+		&job.Counts.Failed,
+		&job.Counts.Cancelled,
+		&job.Counts.Expired,
+		&job.ErrorMessage,
+		&job.CreatedAt,
+		&job.UpdatedAt,
+		&job.ExpiresAt,
+		&inProgressAt,
+		&finalizingAt,
+		&completedAt,
+		&expiredAt,
+		&cancellingAt,
+		&cancelledAt,
+	); err != nil {
+		return nil, err
+	}
+	if len(metadata) > 0 {
+		if err := json.Unmarshal(metadata, &job.Metadata); err != nil {
+			return nil, fmt.Errorf("unmarshal batch metadata: %w", err)
+		}
+	}
+	job.InProgressAt = nullTimePtr(inProgressAt)
+	job.FinalizingAt = nullTimePtr(finalizingAt)
+	job.CompletedAt = nullTimePtr(completedAt)
+	job.ExpiredAt = nullTimePtr(expiredAt)
+	job.CancellingAt = nullTimePtr(cancellingAt)
+	job.CancelledAt = nullTimePtr(cancelledAt)
+	return job, nil
+}
+
+// ==================== Items ====================
+
+func (r *batchRepository) ClaimPendingItems(ctx context.Context, jobID int64, staleBefore time.Time, limit int) ([]*service.BatchJobItem, error) {
+	if limit <= 0 {
+		return nil, nil
+	}
+	rows, err := r.sql.QueryContext(ctx, `
+		UPDATE batch_job_items
+		SET status = $2, started_at = NOW()
+		WHERE id IN (
+			SELECT id FROM batch_job_items
+			WHERE job_id = $1
+				AND (status = $3 OR (status = $2 AND started_at < $4))
+			ORDER BY line_no ASC
+			LIMIT $5
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, job_id, line_no, custom_id, url, body, status, started_at
+	`, jobID, service.BatchItemStatusRunning, service.BatchItemStatusPending, staleBefore, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	items := make([]*service.BatchJobItem, 0, limit)
+	for rows.Next() {
+		item := &service.BatchJobItem{}
+		var startedAt sql.NullTime
+		if err := rows.Scan(&item.ID, &item.JobID, &item.LineNo, &item.CustomID, &item.URL, &item.Body, &item.Status, &startedAt); err != nil {
+			return nil, err
+		}
+		item.StartedAt = nullTimePtr(startedAt)
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+func (r *batchRepository) CompleteItem(ctx context.Context, item *service.BatchJobItem) error {
+	if item == nil {
+		return errors.New("batch item is nil")
+	}
+	_, err := r.sql.ExecContext(ctx, `
+		UPDATE batch_job_items
+		SET status = $2, response_status = $3, response_body = $4, request_id = $5, error_message = $6, finished_at = $7
+		WHERE id = $1 AND status = $8
+	`, item.ID, item.Status, item.ResponseStatus, item.ResponseBody, item.RequestID, item.ErrorMessage, item.FinishedAt, service.BatchItemStatusRunning)
+	return err
+}
+
+func (r *batchRepository) CloseRemainingItems(ctx context.Context, jobID int64, status string, errorMessage string) (int64, error) {
+	result, err := r.sql.ExecContext(ctx, `
+		UPDATE batch_job_items
+		SET status = $2, error_message = $3, finished_at = NOW()
+		WHERE job_id = $1 AND status = ANY($4)
+	`, jobID, status, errorMessage, pq.Array([]string{service.BatchItemStatusPending, service.BatchItemStatusRunning}))
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func (r *batchRepository) CountItems(ctx context.Context, jobID int64) (service.BatchRequestCounts, error) {
+	counts := service.BatchRequestCounts{}
+	rows, err := r.sql.QueryContext(ctx, `SELECT status, COUNT(*) FROM batch_job_items WHERE job_id = $1 GROUP BY status`, jobID)
+	if err != nil {
+		return counts, err
+	}
+	defer func() { _ = rows.Close() }()
+	for rows.Next() {
+		var status string
+		var n int
+		if err := rows.Scan(&status, &n); err != nil {
+			return counts, err
+		}
+		counts.Total += n
+		switch status {
+		case service.BatchItemStatusSucceeded:
+			counts.Succeeded += n
+		case service.BatchItemStatusFailed:
+			counts.Failed += n
+		case service.BatchItemStatusCancelled:
+			counts.Cancelled += n
+		case service.BatchItemStatusExpired:
+			counts.Expired += n
+		}
+	}
+	return counts, rows.Err()
+}
+
+func (r *batchRepository) ListItems(ctx context.Context, jobID int64) ([]*service.BatchJobItem, error) {
+	rows, err := r.sql.QueryContext(ctx, `
+		SELECT id, job_id, line_no, custom_id, url, status, response_status, response_body,
+			request_id, error_message, started_at, finished_at
+		FROM batch_job_items
+		WHERE job_id = $1
+		ORDER BY line_no ASC
+	`, jobID)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	items := make([]*service.BatchJobItem, 0)
+	for rows.Next() {
+		item := &service.BatchJobItem{}
+		var startedAt, finishedAt sql.NullTime
+		if err := rows.Scan(
+			&item.ID,
+			&item.JobID,
+			&item.LineNo,
+			&item.CustomID,
+			&item.URL,
+			&item.Status,
+			&item.ResponseStatus,
+			&item.ResponseBody,
+			&item.RequestID,
+			&item.ErrorMessage,
+			&startedAt,
+			&finishedAt,
+		); err != nil {
+			return nil, err
+		}
+		item.StartedAt = nullTimePtr(startedAt)
+		item.FinishedAt = nullTimePtr(finishedAt)
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+func nullTimePtr(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	v := t.Time
+	return &v
+}
@@ -9,14 +9,18 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
 	"github.com/Wei-Shaw/sub2api/internal/service"
 	"github.com/redis/go-redis/v9"
 )
 
+// 金额以 money.Amount 最小单位（1e-10 USD）整数存储，扣减/累加使用 DECRBY/HINCRBY 保证精确；
+// key 前缀带 v2 版本号，与旧版浮点格式的缓存隔离（旧 key 随 TTL 自然过期）。
 const (
-	billingBalanceKeyPrefix   = "billing:balance:"
-	billingSubKeyPrefix       = "billing:sub:"
-	billingRateLimitKeyPrefix = "apikey:rate:"
+	billingBalanceKeyPrefix   = "billing:balance:v2:"
+	billingSubKeyPrefix       = "billing:sub:v2:"
+	billingRateLimitKeyPrefix = "apikey:rate:v2:"
+	billingOrgMemberKeyPrefix = "billing:org_member:"
 	billingCacheTTL           = 5 * time.Minute
 	billingCacheJitter        = 30 * time.Second
 	rateLimitCacheTTL         = 7 * 24 * time.Hour // 7 days matches the longest window
@@ -56,6 +60,18 @@ func billingRateLimitKey(keyID int64) string {
 	return fmt.Sprintf("%s%d", billingRateLimitKeyPrefix, keyID)
 }
 
+// billingOrgMemberKey generates the Redis key for organization member spend cache.
+func billingOrgMemberKey(accountUserID, memberID int64) string {
+	return fmt.Sprintf("%s%d:%d", billingOrgMemberKeyPrefix, accountUserID, memberID)
+}
+
+const (
+	orgMemberFieldMonth  = "month"
+	orgMemberFieldActive = "active"
+	orgMemberFieldLimit  = "limit"
+	orgMemberFieldSpent  = "spent"
+)
+
 const (
 	rateLimitFieldUsage5h  = "usage_5h"
 	rateLimitFieldUsage1d  = "usage_1d"
@@ -67,12 +83,10 @@ const (
 
 var (
 	deductBalanceScript = redis.NewScript(`
-		local current = redis.call('GET', KEYS[1])
-		if current == false then
+		if redis.call('EXISTS', KEYS[1]) == 0 then
 			return 0
 		end
-		local newVal = tonumber(current) - tonumber(ARGV[1])
-		redis.call('SET', KEYS[1], newVal)
+		redis.call('DECRBY', KEYS[1], ARGV[1])
 		redis.call('EXPIRE', KEYS[1], ARGV[2])
 		return 1
 	`)
@@ -82,10 +96,9 @@ var (
 		if exists == 0 then
 			return 0
 		end
-		local cost = tonumber(ARGV[1])
-		redis.call('HINCRBYFLOAT', KEYS[1], 'daily_usage', cost)
-		redis.call('HINCRBYFLOAT', KEYS[1], 'weekly_usage', cost)
-		redis.call('HINCRBYFLOAT', KEYS[1], 'monthly_usage', cost)
+		redis.call('HINCRBY', KEYS[1], 'daily_usage', ARGV[1])
+		redis.call('HINCRBY', KEYS[1], 'weekly_usage', ARGV[1])
+		redis.call('HINCRBY', KEYS[1], 'monthly_usage', ARGV[1])
 		redis.call('EXPIRE', KEYS[1], ARGV[2])
 		return 1
 	`)
@@ -97,13 +110,22 @@ var (
 		if exists == 0 then
 			return 0
 		end
-		local cost = tonumber(ARGV[1])
-		redis.call('HINCRBYFLOAT', KEYS[1], 'usage_5h', cost)
-		redis.call('HINCRBYFLOAT', KEYS[1], 'usage_1d', cost)
-		redis.call('HINCRBYFLOAT', KEYS[1], 'usage_7d', cost)
+		redis.call('HINCRBY', KEYS[1], 'usage_5h', ARGV[1])
+		redis.call('HINCRBY', KEYS[1], 'usage_1d', ARGV[1])
+		redis.call('HINCRBY', KEYS[1], 'usage_7d', ARGV[1])
 		redis.call('EXPIRE', KEYS[1], ARGV[2])
 		return 1
 	`)
+
+	// addOrgMemberSpendScript 仅在缓存存在且属于同一自然月时累加消费，跨月的旧缓存由下次检查重新加载。
+	// 不刷新 TTL：缓存过期后从 usage_logs 重新汇总，定期纠正异步累加的误差。
+	addOrgMemberSpendScript = redis.NewScript(`
+		if redis.call('HGET', KEYS[1], 'month') ~= ARGV[1] then
+			return 0
+		end
+		redis.call('HINCRBY', KEYS[1], 'spent', ARGV[2])
+		return 1
+	`)
 )
 
 type billingCache struct {
@@ -114,23 +136,23 @@ func NewBillingCache(rdb *redis.Client) service.BillingCache {
 	return &billingCache{rdb: rdb}
 }
 
-func (c *billingCache) GetUserBalance(ctx context.Context, userID int64) (float64, error) {
+func (c *billingCache) GetUserBalance(ctx context.Context, userID int64) (money.Amount, error) {
 	key := billingBalanceKey(userID)
-	val, err := c.rdb.Get(ctx, key).Result()
+	val, err := c.rdb.Get(ctx, key).Int64()
 	if err != nil {
 		return 0, err
 	}
-	return strconv.ParseFloat(val, 64)
+	return money.Amount(val), nil
 }
 
-func (c *billingCache) SetUserBalance(ctx context.Context, userID int64, balance float64) error {
+func (c *billingCache) SetUserBalance(ctx context.Context, userID int64, balance money.Amount) error {
 	key := billingBalanceKey(userID)
-	return c.rdb.Set(ctx, key, balance, jitteredTTL()).Err()
+	return c.rdb.Set(ctx, key, int64(balance), jitteredTTL()).Err()
 }
 
-func (c *billingCache) DeductUserBalance(ctx context.Context, userID int64, amount float64) error {
+func (c *billingCache) DeductUserBalance(ctx context.Context, userID int64, amount money.Amount) error {
 	key := billingBalanceKey(userID)
-	_, err := deductBalanceScript.Run(ctx, c.rdb, []string{key}, amount, int(jitteredTTL().Seconds())).Result()
+	_, err := deductBalanceScript.Run(ctx, c.rdb, []string{key}, int64(amount), int(jitteredTTL().Seconds())).Result()
 	if err != nil && !errors.Is(err, redis.Nil) {
 		log.Printf("Warning: deduct balance cache failed for user %d: %v", userID, err)
 		return err
@@ -171,15 +193,15 @@ func (c *billingCache) parseSubscriptionCache(data map[string]string) (*service.
 	}
 
 	if dailyStr, ok := data[subFieldDailyUsage]; ok {
-		result.DailyUsage, _ = strconv.ParseFloat(dailyStr, 64)
+		result.DailyUsage = parseCachedAmount(dailyStr)
 	}
 
 	if weeklyStr, ok := data[subFieldWeeklyUsage]; ok {
-		result.WeeklyUsage, _ = strconv.ParseFloat(weeklyStr, 64)
+		result.WeeklyUsage = parseCachedAmount(weeklyStr)
 	}
 
 	if monthlyStr, ok := data[subFieldMonthlyUsage]; ok {
-		result.MonthlyUsage, _ = strconv.ParseFloat(monthlyStr, 64)
+		result.MonthlyUsage = parseCachedAmount(monthlyStr)
 	}
 
 	if versionStr, ok := data[subFieldVersion]; ok {
@@ -199,9 +221,9 @@ func (c *billingCache) SetSubscriptionCache(ctx context.Context, userID, groupID
 	fields := map[string]any{
 		subFieldStatus:       data.Status,
 		subFieldExpiresAt:    data.ExpiresAt.Unix(),
-		subFieldDailyUsage:   data.DailyUsage,
-		subFieldWeeklyUsage:  data.WeeklyUsage,
-		subFieldMonthlyUsage: data.MonthlyUsage,
+		subFieldDailyUsage:   int64(data.DailyUsage),
+		subFieldWeeklyUsage:  int64(data.WeeklyUsage),
+		subFieldMonthlyUsage: int64(data.MonthlyUsage),
 		subFieldVersion:      data.Version,
 	}
 
@@ -212,9 +234,9 @@ func (c *billingCache) SetSubscriptionCache(ctx context.Context, userID, groupID
 	return err
 }
 
-func (c *billingCache) UpdateSubscriptionUsage(ctx context.Context, userID, groupID int64, cost float64) error {
+func (c *billingCache) UpdateSubscriptionUsage(ctx context.Context, userID, groupID int64, cost money.Amount) error {
 	key := billingSubKey(userID, groupID)
-	_, err := updateSubUsageScript.Run(ctx, c.rdb, []string{key}, cost, int(jitteredTTL().Seconds())).Result()
+	_, err := updateSubUsageScript.Run(ctx, c.rdb, []string{key}, int64(cost), int(jitteredTTL().Seconds())).Result()
 	if err != nil && !errors.Is(err, redis.Nil) {
 		log.Printf("Warning: update subscription usage cache failed for user %d group %d: %v", userID, groupID, err)
 		return err
@@ -238,13 +260,13 @@ func (c *billingCache) GetAPIKeyRateLimit(ctx context.Context, keyID int64) (*se
 	}
 	data := &service.APIKeyRateLimitCacheData{}
 	if v, ok := result[rateLimitFieldUsage5h]; ok {
-		data.Usage5h, _ = strconv.ParseFloat(v, 64)
+		data.Usage5h = parseCachedAmount(v)
 	}
 	if v, ok := result[rateLimitFieldUsage1d]; ok {
-		data.Usage1d, _ = strconv.ParseFloat(v, 64)
+		data.Usage1d = parseCachedAmount(v)
 	}
 	if v, ok := result[rateLimitFieldUsage7d]; ok {
-		data.Usage7d, _ = strconv.ParseFloat(v, 64)
+		data.Usage7d = parseCachedAmount(v)
 	}
 	if v, ok := result[rateLimitFieldWindow5h]; ok {
 		data.Window5h, _ = strconv.ParseInt(v, 10, 64)
@@ -264,9 +286,9 @@ func (c *billingCache) SetAPIKeyRateLimit(ctx context.Context, keyID int64, data
 	}
 	key := billingRateLimitKey(keyID)
 	fields := map[string]any{
-		rateLimitFieldUsage5h:  data.Usage5h,
-		rateLimitFieldUsage1d:  data.Usage1d,
-		rateLimitFieldUsage7d:  data.Usage7d,
+		rateLimitFieldUsage5h:  int64(data.Usage5h),
+		rateLimitFieldUsage1d:  int64(data.Usage1d),
+		rateLimitFieldUsage7d:  int64(data.Usage7d),
 		rateLimitFieldWindow5h: data.Window5h,
 		rateLimitFieldWindow1d: data.Window1d,
 		rateLimitFieldWindow7d: data.Window7d,
@@ -278,9 +300,9 @@ func (c *billingCache) SetAPIKeyRateLimit(ctx context.Context, keyID int64, data
 	return err
 }
 
-func (c *billingCache) UpdateAPIKeyRateLimitUsage(ctx context.Context, keyID int64, cost float64) error {
+func (c *billingCache) UpdateAPIKeyRateLimitUsage(ctx context.Context, keyID int64, cost money.Amount) error {
 	key := billingRateLimitKey(keyID)
-	_, err := updateRateLimitUsageScript.Run(ctx, c.rdb, []string{key}, cost, int(rateLimitCacheTTL.Seconds())).Result()
+	_, err := updateRateLimitUsageScript.Run(ctx, c.rdb, []string{key}, int64(cost), int(rateLimitCacheTTL.Seconds())).Result()
 	if err != nil && !errors.Is(err, redis.Nil) {
 		log.Printf("Warning: update rate limit usage cache failed for api key %d: %v", keyID, err)
 		return err
@@ -292,3 +314,62 @@ func (c *billingCache) InvalidateAPIKeyRateLimit(ctx context.Context, keyID int6
 	key := billingRateLimitKey(keyID)
 	return c.rdb.Del(ctx, key).Err()
 }
+
+func (c *billingCache) GetOrgMemberSpend(ctx context.Context, accountUserID, memberID int64) (*service.OrgMemberSpendCacheData, error) {
+	key := billingOrgMemberKey(accountUserID, memberID)
+	result, err := c.rdb.HGetAll(ctx, key).Result()
+	if err != nil {
+		return nil, err
+	}
+	if len(result) == 0 {
+		return nil, redis.Nil
+	}
+	return &service.OrgMemberSpendCacheData{
+		Month:  result[orgMemberFieldMonth],
+		Active: result[orgMemberFieldActive] == "1",
+		Limit:  parseCachedAmount(result[orgMemberFieldLimit]),
+		Spent:  parseCachedAmount(result[orgMemberFieldSpent]),
+	}, nil
+}
+
+func (c *billingCache) SetOrgMemberSpend(ctx context.Context, accountUserID, memberID int64, data *service.OrgMemberSpendCacheData) error {
+	if data == nil {
+		return nil
+	}
+	key := billingOrgMemberKey(accountUserID, memberID)
+	active := 0
+	if data.Active {
+		active = 1
+	}
+	fields := map[string]any{
+		orgMemberFieldMonth:  data.Month,
+		orgMemberFieldActive: active,
+		orgMemberFieldLimit:  int64(data.Limit),
+		orgMemberFieldSpent:  int64(data.Spent),
+	}
+	pipe := c.rdb.Pipeline()
+	pipe.HSet(ctx, key, fields)
+	pipe.Expire(ctx, key, jitteredTTL())
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+func (c *billingCache) AddOrgMemberSpend(ctx context.Context, accountUserID, memberID int64, month string, cost money.Amount) error {
+	key := billingOrgMemberKey(accountUserID, memberID)
+	_, err := addOrgMemberSpendScript.Run(ctx, c.rdb, []string{key}, month, int64(cost)).Result()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return err
+	}
+	return nil
+}
+
+func (c *billingCache) InvalidateOrgMemberSpend(ctx context.Context, accountUserID, memberID int64) error {
+	key := billingOrgMemberKey(accountUserID, memberID)
+	return c.rdb.Del(ctx, key).Err()
+}
+
+// parseCachedAmount 解析缓存中以最小单位存储的整数金额
+func parseCachedAmount(v string) money.Amount {
+	n, _ := strconv.ParseInt(v, 10, 64)
+	return money.Amount(n)
+}
@@ -8,6 +8,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
 	"github.com/Wei-Shaw/sub2api/internal/service"
 	"github.com/redis/go-redis/v9"
 	"github.com/stretchr/testify/require"
@@ -36,7 +37,7 @@ func (s *BillingCacheSuite) TestUserBalance() {
 				userID := int64(1)
 				balanceKey := fmt.Sprintf("%s%d", billingBalanceKeyPrefix, userID)
 
-				require.NoError(s.T(), cache.DeductUserBalance(ctx, userID, 1), "DeductUserBalance should not error")
+				require.NoError(s.T(), cache.DeductUserBalance(ctx, userID, money.USD), "DeductUserBalance should not error")
 
 				_, err := rdb.Get(ctx, balanceKey).Result()
 				require.ErrorIs(s.T(), err, redis.Nil, "expected missing key after deduct on non-existent")
@@ -48,11 +49,11 @@ func (s *BillingCacheSuite) TestUserBalance() {
 				userID := int64(2)
 				balanceKey := fmt.Sprintf("%s%d", billingBalanceKeyPrefix, userID)
 
-				require.NoError(s.T(), cache.SetUserBalance(ctx, userID, 10.5), "SetUserBalance")
+				require.NoError(s.T(), cache.SetUserBalance(ctx, userID, money.MustParse("10.5")), "SetUserBalance")
 
 				got, err := cache.GetUserBalance(ctx, userID)
 				require.NoError(s.T(), err, "GetUserBalance")
-				require.Equal(s.T(), 10.5, got, "balance mismatch")
+				require.Equal(s.T(), money.MustParse("10.5"), got, "balance mismatch")
 
 				ttl, err := rdb.TTL(ctx, balanceKey).Result()
 				require.NoError(s.T(), err, "TTL")
@@ -64,12 +65,12 @@ func (s *BillingCacheSuite) TestUserBalance() {
 			fn: func(ctx context.Context, rdb *redis.Client, cache service.BillingCache) {
 				userID := int64(3)
 
-				require.NoError(s.T(), cache.SetUserBalance(ctx, userID, 10.5), "SetUserBalance")
-				require.NoError(s.T(), cache.DeductUserBalance(ctx, userID, 2.25), "DeductUserBalance")
+				require.NoError(s.T(), cache.SetUserBalance(ctx, userID, money.MustParse("10.5")), "SetUserBalance")
+				require.NoError(s.T(), cache.DeductUserBalance(ctx, userID, money.MustParse("2.25")), "DeductUserBalance")
 
 				got, err := cache.GetUserBalance(ctx, userID)
 				require.NoError(s.T(), err, "GetUserBalance after deduct")
-				require.Equal(s.T(), 8.25, got, "deduct mismatch")
+				require.Equal(s.T(), money.MustParse("8.25"), got, "deduct mismatch")
 			},
 		},
 		{
@@ -78,7 +79,7 @@ func (s *BillingCacheSuite) TestUserBalance() {
 				userID := int64(100)
 				balanceKey := fmt.Sprintf("%s%d", billingBalanceKeyPrefix, userID)
 
-				require.NoError(s.T(), cache.SetUserBalance(ctx, userID, 50.0), "SetUserBalance")
+				require.NoError(s.T(), cache.SetUserBalance(ctx, userID, 50*money.USD), "SetUserBalance")
 
 				exists, err := rdb.Exists(ctx, balanceKey).Result()
 				require.NoError(s.T(), err, "Exists")
@@ -100,17 +101,17 @@ func (s *BillingCacheSuite) TestUserBalance() {
 				userID := int64(103)
 				balanceKey := fmt.Sprintf("%s%d", billingBalanceKeyPrefix, userID)
 
-				require.NoError(s.T(), cache.SetUserBalance(ctx, userID, 100.0), "SetUserBalance")
+				require.NoError(s.T(), cache.SetUserBalance(ctx, userID, 100*money.USD), "SetUserBalance")
 
 				ttl1, err := rdb.TTL(ctx, balanceKey).Result()
 				require.NoError(s.T(), err, "TTL before deduct")
 				s.AssertTTLWithin(ttl1, 1*time.Second, billingCacheTTL)
 
-				require.NoError(s.T(), cache.DeductUserBalance(ctx, userID, 25.0), "DeductUserBalance")
+				require.NoError(s.T(), cache.DeductUserBalance(ctx, userID, 25*money.USD), "DeductUserBalance")
 
 				balance, err := cache.GetUserBalance(ctx, userID)
 				require.NoError(s.T(), err, "GetUserBalance")
-				require.Equal(s.T(), 75.0, balance, "expected balance 75.0")
+				require.Equal(s.T(), 75*money.USD, balance, "expected balance 75.0")
 
 				ttl2, err := rdb.TTL(ctx, balanceKey).Result()
 				require.NoError(s.T(), err, "TTL after deduct")
@@ -152,7 +153,7 @@ func (s *BillingCacheSuite) TestSubscriptionCache() {
 				groupID := int64(21)
 				subKey := fmt.Sprintf("%s%d:%d", billingSubKeyPrefix, userID, groupID)
 
-				require.NoError(s.T(), cache.UpdateSubscriptionUsage(ctx, userID, groupID, 1.0), "UpdateSubscriptionUsage should not error")
+				require.NoError(s.T(), cache.UpdateSubscriptionUsage(ctx, userID, groupID, money.USD), "UpdateSubscriptionUsage should not error")
 
 				exists, err := rdb.Exists(ctx, subKey).Result()
 				require.NoError(s.T(), err, "Exists")
@@ -169,9 +170,9 @@ func (s *BillingCacheSuite) TestSubscriptionCache() {
 				data := &service.SubscriptionCacheData{
 					Status:       "active",
 					ExpiresAt:    time.Now().Add(1 * time.Hour),
-					DailyUsage:   1.0,
-					WeeklyUsage:  2.0,
-					MonthlyUsage: 3.0,
+					DailyUsage:   money.USD,
+					WeeklyUsage:  2 * money.USD,
+					MonthlyUsage: 3 * money.USD,
 					Version:      7,
 				}
 				require.NoError(s.T(), cache.SetSubscriptionCache(ctx, userID, groupID, data), "SetSubscriptionCache")
@@ -180,7 +181,7 @@ func (s *BillingCacheSuite) TestSubscriptionCache() {
 				require.NoError(s.T(), err, "GetSubscriptionCache")
 				require.Equal(s.T(), "active", gotSub.Status)
 				require.Equal(s.T(), int64(7), gotSub.Version)
-				require.Equal(s.T(), 1.0, gotSub.DailyUsage)
+				require.Equal(s.T(), money.USD, gotSub.DailyUsage)
 
 				ttl, err := rdb.TTL(ctx, subKey).Result()
 				require.NoError(s.T(), err, "TTL subKey")
@@ -196,20 +197,20 @@ func (s *BillingCacheSuite) TestSubscriptionCache() {
 				data := &service.SubscriptionCacheData{
 					Status:       "active",
 					ExpiresAt:    time.Now().Add(1 * time.Hour),
-					DailyUsage:   1.0,
-					WeeklyUsage:  2.0,
-					MonthlyUsage: 3.0,
+					DailyUsage:   money.USD,
+					WeeklyUsage:  2 * money.USD,
+					MonthlyUsage: 3 * money.USD,
 					Version:      1,
 				}
 				require.NoError(s.T(), cache.SetSubscriptionCache(ctx, userID, groupID, data), "SetSubscriptionCache")
 
-				require.NoError(s.T(), cache.UpdateSubscriptionUsage(ctx, userID, groupID, 0.5), "UpdateSubscriptionUsage")
+				require.NoError(s.T(), cache.UpdateSubscriptionUsage(ctx, userID, groupID, money.MustParse("0.5")), "UpdateSubscriptionUsage")
 
 				gotSub, err := cache.GetSubscriptionCache(ctx, userID, groupID)
 				require.NoError(s.T(), err, "GetSubscriptionCache after update")
-				require.Equal(s.T(), 1.5, gotSub.DailyUsage)
-				require.Equal(s.T(), 2.5, gotSub.WeeklyUsage)
-				require.Equal(s.T(), 3.5, gotSub.MonthlyUsage)
+				require.Equal(s.T(), money.MustParse("1.5"), gotSub.DailyUsage)
+				require.Equal(s.T(), money.MustParse("2.5"), gotSub.WeeklyUsage)
+				require.Equal(s.T(), money.MustParse("3.5"), gotSub.MonthlyUsage)
 			},
 		},
 		{
@@ -222,9 +223,9 @@ func (s *BillingCacheSuite) TestSubscriptionCache() {
 				data := &service.SubscriptionCacheData{
 					Status:       "active",
 					ExpiresAt:    time.Now().Add(1 * time.Hour),
-					DailyUsage:   1.0,
-					WeeklyUsage:  2.0,
-					MonthlyUsage: 3.0,
+					DailyUsage:   money.USD,
+					WeeklyUsage:  2 * money.USD,
+					MonthlyUsage: 3 * money.USD,
 					Version:      1,
 				}
 				require.NoError(s.T(), cache.SetSubscriptionCache(ctx, userID, groupID, data), "SetSubscriptionCache")
@@ -290,31 +291,31 @@ func (s *BillingCacheSuite) TestDeductUserBalance_ErrorPropagation() {
 			name: "key_not_exists_returns_nil",
 			fn: func(ctx context.Context, cache service.BillingCache) {
 				// key 不存在时，Lua 脚本返回 0（redis.Nil），应返回 nil 而非错误
-				err := cache.DeductUserBalance(ctx, 99999, 1.0)
+				err := cache.DeductUserBalance(ctx, 99999, money.USD)
 				require.NoError(s.T(), err, "DeductUserBalance on non-existent key should return nil")
 			},
 		},
 		{
 			name: "existing_key_deducts_successfully",
 			fn: func(ctx context.Context, cache service.BillingCache) {
-				require.NoError(s.T(), cache.SetUserBalance(ctx, 200, 50.0))
-				err := cache.DeductUserBalance(ctx, 200, 10.0)
+				require.NoError(s.T(), cache.SetUserBalance(ctx, 200, 50*money.USD))
+				err := cache.DeductUserBalance(ctx, 200, 10*money.USD)
 				require.NoError(s.T(), err, "DeductUserBalance should succeed")
 
 				bal, err := cache.GetUserBalance(ctx, 200)
 				require.NoError(s.T(), err)
-				require.Equal(s.T(), 40.0, bal, "余额应为 40.0")
+				require.Equal(s.T(), 40*money.USD, bal, "余额应为 40.0")
 			},
 		},
 		{
 			name: "cancelled_context_propagates_error",
 			fn: func(ctx context.Context, cache service.BillingCache) {
-				require.NoError(s.T(), cache.SetUserBalance(ctx, 201, 50.0))
+				require.NoError(s.T(), cache.SetUserBalance(ctx, 201, 50*money.USD))
 
 				cancelCtx, cancel := context.WithCancel(ctx)
 				cancel() // 立即取消
 
-				err := cache.DeductUserBalance(cancelCtx, 201, 10.0)
+				err := cache.DeductUserBalance(cancelCtx, 201, 10*money.USD)
 				require.Error(s.T(), err, "cancelled context should propagate error")
 			},
 		},
@@ -338,7 +339,7 @@ func (s *BillingCacheSuite) TestUpdateSubscriptionUsage_ErrorPropagation() {
 		cache := NewBillingCache(rdb)
 		ctx := context.Background()
 
-		err := cache.UpdateSubscriptionUsage(ctx, 88888, 77777, 1.0)
+		err := cache.UpdateSubscriptionUsage(ctx, 88888, 77777, money.USD)
 		require.NoError(s.T(), err, "UpdateSubscriptionUsage on non-existent key should return nil")
 	})
 
@@ -357,7 +358,7 @@ func (s *BillingCacheSuite) TestUpdateSubscriptionUsage_ErrorPropagation() {
 		cancelCtx, cancel := context.WithCancel(ctx)
 		cancel()
 
-		err := cache.UpdateSubscriptionUsage(cancelCtx, 301, 401, 1.0)
+		err := cache.UpdateSubscriptionUsage(cancelCtx, 301, 401, money.USD)
 		require.Error(s.T(), err, "cancelled context should propagate error")
 	})
 }
@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/service"
+	"github.com/redis/go-redis/v9"
+)
+
+// 预授权冻结 Redis 实现
+//
+// 每个冻结维度一个 Hash：billing:hold:v2:{scope}，字段为 holdID，值为 "金额|过期时间(ms)"，
+// 金额为 money.Amount 最小单位整数（Lua 数值为双精度，单维度冻结总额在 2^53 单位即约 90 万美元内可精确比较）。
+// 读取已冻结总额时顺带清理过期字段，因此进程崩溃遗留的冻结会在 TTL 后自动失效。
+const billingHoldKeyPrefix = "billing:hold:v2:"
+
+var (
+	// reserveBillingHoldScript 检查所有维度后一次性写入冻结
+	// KEYS: 各维度 key；ARGV[1]=holdID, ARGV[2]=now(ms), ARGV[3]=ttl(ms), 之后每个维度依次为 amount, available
+	// 返回首个额度不足的维度序号（从 1 开始），0 表示冻结成功
+	reserveBillingHoldScript = redis.NewScript(`
+		local holdID = ARGV[1]
+		local now = tonumber(ARGV[2])
+		local ttl = tonumber(ARGV[3])
+		local expireAt = now + ttl
+
+		local function heldAmount(key)
+			local total = 0
+			local entries = redis.call('HGETALL', key)
+			for i = 1, #entries, 2 do
+				local value = entries[i + 1]
+				local sep = string.find(value, '|', 1, true)
+				local amount = tonumber(string.sub(value, 1, sep - 1))
+				local exp = tonumber(string.sub(value, sep + 1))
+				if exp <= now then
+					redis.call('HDEL', key, entries[i])
+				else
+					total = total + amount
+				end
+			end
+			return total
+		end
+
+		for i, key in ipairs(KEYS) do
+			local amount = tonumber(ARGV[2 + i * 2])
+			local available = tonumber(ARGV[3 + i * 2])
+			if heldAmount(key) + amount > available then
+				return i
+			end
+		end
+
+		for i, key in ipairs(KEYS) do
+			redis.call('HSET', key, holdID, ARGV[2 + i * 2] .. '|' .. expireAt)
+			if redis.call('PTTL', key) < ttl then
+				redis.call('PEXPIRE', key, ttl)
+			end
+		end
+		return 0
+	`)
+
+	// settleBillingHoldScript 将仍存在的冻结改为实际金额并重设过期时间
+	// KEYS: 各维度 key；ARGV[1]=holdID, ARGV[2]=now(ms), ARGV[3]=ttl(ms), 之后每个维度一个 amount
+	settleBillingHoldScript = redis.NewScript(`
+		local holdID = ARGV[1]
+		local expireAt = tonumber(ARGV[2]) + tonumber(ARGV[3])
+		for i, key in ipairs(KEYS) do
+			if redis.call('HEXISTS', key, holdID) == 1 then
+				redis.call('HSET', key, holdID, ARGV[3 + i] .. '|' .. expireAt)
+			end
+		end
+		return 1
+	`)
+)
+
+type billingHoldCache struct {
+	rdb *redis.Client
+}
+
+// NewBillingHoldCache 创建预授权冻结缓存
+func NewBillingHoldCache(rdb *redis.Client) service.BillingHoldCache {
+	return &billingHoldCache{rdb: rdb}
+}
+
+func billingHoldKey(scope string) string {
+	return billingHoldKeyPrefix + scope
+}
+
+func (c *billingHoldCache) Reserve(ctx context.Context, holdID string, lines []service.BillingHoldLine, ttl time.Duration) (int, error) {
+	if len(lines) == 0 {
+		return -1, nil
+	}
+	keys := make([]string, len(lines))
+	args := make([]any, 0, 3+len(lines)*2)
+	args = append(args, holdID, time.Now().UnixMilli(), ttl.Milliseconds())
+	for i, line := range lines {
+		keys[i] = billingHoldKey(line.Scope)
+		args = append(args, int64(line.Amount), int64(line.Available))
+	}
+	rejected, err := reserveBillingHoldScript.Run(ctx, c.rdb, keys, args...).Int()
+	if err != nil {
+		return -1, err
+	}
+	return rejected - 1, nil
+}
+
+func (c *billingHoldCache) Settle(ctx context.Context, holdID string, lines []service.BillingHoldLine, ttl time.Duration) error {
+	if len(lines) == 0 {
+		return nil
+	}
+	keys := make([]string, len(lines))
+	args := make([]any, 0, 3+len(lines))
+	args = append(args, holdID, time.Now().UnixMilli(), ttl.Milliseconds())
+	for i, line := range lines {
+		keys[i] = billingHoldKey(line.Scope)
+		args = append(args, int64(line.Amount))
+	}
+	return settleBillingHoldScript.Run(ctx, c.rdb, keys, args...).Err()
+}
+
+func (c *billingHoldCache) Release(ctx context.Context, holdID string, scopes []string) error {
+	if len(scopes) == 0 {
+		return nil
+	}
+	pipe := c.rdb.Pipeline()
+	for _, scope := range scopes {
+		pipe.HDel(ctx, billingHoldKey(scope), holdID)
+	}
+	_, err := pipe.Exec(ctx)
+	return err
+}
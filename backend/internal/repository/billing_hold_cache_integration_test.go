@@ -0,0 +1,96 @@
+//go:build integration
+
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type BillingHoldCacheSuite struct {
+	IntegrationRedisSuite
+	cache service.BillingHoldCache
+}
+
+func (s *BillingHoldCacheSuite) SetupTest() {
+	s.IntegrationRedisSuite.SetupTest()
+	s.cache = NewBillingHoldCache(s.rdb)
+}
+
+func (s *BillingHoldCacheSuite) TestReserveRejectsWhenHeldExceedsAvailable() {
+	line := service.BillingHoldLine{Scope: "balance:1", Amount: money.MustParse("0.06"), Available: money.MustParse("0.1")}
+
+	rejected, err := s.cache.Reserve(s.ctx, "h1", []service.BillingHoldLine{line}, time.Minute)
+	s.RequireNoError(err)
+	require.Equal(s.T(), -1, rejected)
+
+	rejected, err = s.cache.Reserve(s.ctx, "h2", []service.BillingHoldLine{line}, time.Minute)
+	s.RequireNoError(err)
+	require.Equal(s.T(), 0, rejected, "second hold exceeds available balance")
+
+	s.RequireNoError(s.cache.Release(s.ctx, "h1", []string{line.Scope}))
+	rejected, err = s.cache.Reserve(s.ctx, "h2", []service.BillingHoldLine{line}, time.Minute)
+	s.RequireNoError(err)
+	require.Equal(s.T(), -1, rejected, "released hold frees the balance")
+}
+
+func (s *BillingHoldCacheSuite) TestReserveIsAllOrNothing() {
+	lines := []service.BillingHoldLine{
+		{Scope: "balance:2", Amount: money.USD, Available: 10 * money.USD},
+		{Scope: "quota:3", Amount: money.USD, Available: money.MustParse("0.5")},
+	}
+	rejected, err := s.cache.Reserve(s.ctx, "h1", lines, time.Minute)
+	s.RequireNoError(err)
+	require.Equal(s.T(), 1, rejected)
+
+	held, err := s.rdb.HLen(s.ctx, billingHoldKey("balance:2")).Result()
+	s.RequireNoError(err)
+	require.Zero(s.T(), held, "rejected reserve must not leave partial holds")
+}
+
+func (s *BillingHoldCacheSuite) TestSettleShrinksHoldAndExpiredHoldsAreIgnored() {
+	line := service.BillingHoldLine{Scope: "balance:4", Amount: money.MustParse("0.08"), Available: money.MustParse("0.1")}
+	rejected, err := s.cache.Reserve(s.ctx, "h1", []service.BillingHoldLine{line}, time.Minute)
+	s.RequireNoError(err)
+	require.Equal(s.T(), -1, rejected)
+
+	settled := line
+	settled.Amount = money.MustParse("0.01")
+	s.RequireNoError(s.cache.Settle(s.ctx, "h1", []service.BillingHoldLine{settled}, 50*time.Millisecond))
+
+	rejected, err = s.cache.Reserve(s.ctx, "h2", []service.BillingHoldLine{line}, time.Minute)
+	s.RequireNoError(err)
+	require.Equal(s.T(), -1, rejected, "settled hold only keeps the actual cost")
+
+	// 结算宽限期过后 h1 过期，只剩 h2 的 0.08
+	time.Sleep(100 * time.Millisecond)
+	small := service.BillingHoldLine{Scope: line.Scope, Amount: money.MustParse("0.02"), Available: money.MustParse("0.1")}
+	rejected, err = s.cache.Reserve(s.ctx, "h3", []service.BillingHoldLine{small}, time.Minute)
+	s.RequireNoError(err)
+	require.Equal(s.T(), -1, rejected)
+
+	exists, err := s.rdb.HExists(s.ctx, billingHoldKey(line.Scope), "h1").Result()
+	s.RequireNoError(err)
+	require.False(s.T(), exists, "expired hold is purged on reserve")
+}
+
+func (s *BillingHoldCacheSuite) TestSettleAfterReleaseIsNoop() {
+	line := service.BillingHoldLine{Scope: "quota:5", Amount: money.USD, Available: money.USD}
+	_, err := s.cache.Reserve(s.ctx, "h1", []service.BillingHoldLine{line}, time.Minute)
+	s.RequireNoError(err)
+	s.RequireNoError(s.cache.Release(s.ctx, "h1", []string{line.Scope}))
+	s.RequireNoError(s.cache.Settle(s.ctx, "h1", []service.BillingHoldLine{line}, time.Minute))
+
+	exists, err := s.rdb.HExists(s.ctx, billingHoldKey(line.Scope), "h1").Result()
+	s.RequireNoError(err)
+	require.False(s.T(), exists)
+}
+
+func TestBillingHoldCacheSuite(t *testing.T) {
+	suite.Run(t, new(BillingHoldCacheSuite))
+}
@@ -142,6 +142,28 @@ func (s *ConcurrencyCacheSuite) TestUserSlot_AcquireAndRelease() {
 	require.Equal(s.T(), 0, cur, "expected concurrency=0 after release")
 }
 
+func (s *ConcurrencyCacheSuite) TestAPIKeySlot_AcquireAndRelease() {
+	apiKeyID := int64(77)
+
+	ok, err := s.cache.AcquireAPIKeySlot(s.ctx, apiKeyID, 1, "req1")
+	require.NoError(s.T(), err, "AcquireAPIKeySlot")
+	require.True(s.T(), ok)
+
+	ok, err = s.cache.AcquireAPIKeySlot(s.ctx, apiKeyID, 1, "req2")
+	require.NoError(s.T(), err, "AcquireAPIKeySlot 2")
+	require.False(s.T(), ok, "expected second acquire to fail at max=1")
+
+	// API Key 与用户槽位使用独立的键空间
+	ok, err = s.cache.AcquireUserSlot(s.ctx, apiKeyID, 1, "req2")
+	require.NoError(s.T(), err, "AcquireUserSlot")
+	require.True(s.T(), ok)
+
+	require.NoError(s.T(), s.cache.ReleaseAPIKeySlot(s.ctx, apiKeyID, "req1"), "ReleaseAPIKeySlot")
+	ok, err = s.cache.AcquireAPIKeySlot(s.ctx, apiKeyID, 1, "req3")
+	require.NoError(s.T(), err, "AcquireAPIKeySlot after release")
+	require.True(s.T(), ok)
+}
+
 func (s *ConcurrencyCacheSuite) TestUserSlot_TTL() {
 	userID := int64(200)
 	reqID := "req_ttl_test"
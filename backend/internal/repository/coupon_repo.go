@@ -0,0 +1,306 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	dbent "github.com/Wei-Shaw/sub2api/ent"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+)
+
+type couponRepository struct {
+	sql sqlExecutor
+}
+
+// NewCouponRepository 创建优惠券仓储
+func NewCouponRepository(sqlDB *sql.DB) service.CouponRepository {
+	return &couponRepository{sql: sqlDB}
+}
+
+// q 返回当前上下文的执行器：在事务上下文中加入该事务
+func (r *couponRepository) q(ctx context.Context) sqlExecutor {
+	if tx := dbent.TxFromContext(ctx); tx != nil {
+		return tx.Client()
+	}
+	return r.sql
+}
+
+// ---------- 优惠券模板 ----------
+
+const couponColumns = `c.id, c.name, c.description, c.discount_type, c.percent_off, c.amount_off_cents, c.currency,
+	c.min_amount_cents, c.scope, c.scope_id, c.valid_days, c.expires_at, c.enabled, c.created_at, c.updated_at`
+
+func scanCoupon(scan func(dest ...any) error, extra ...any) (*service.Coupon, error) {
+	var c service.Coupon
+	var scopeID sql.NullInt64
+	var expiresAt sql.NullTime
+	dest := []any{
+		&c.ID, &c.Name, &c.Description, &c.DiscountType, &c.PercentOff, &c.AmountOffCents, &c.Currency,
+		&c.MinAmountCents, &c.Scope, &scopeID, &c.ValidDays, &expiresAt, &c.Enabled, &c.CreatedAt, &c.UpdatedAt,
+	}
+	if err := scan(append(dest, extra...)...); err != nil {
+		return nil, err
+	}
+	c.ScopeID = nullInt64Value(scopeID)
+	c.ExpiresAt = nullTimePtr(expiresAt)
+	return &c, nil
+}
+
+func (r *couponRepository) Create(ctx context.Context, c *service.Coupon) error {
+	return scanSingleRow(ctx, r.q(ctx), `
+		INSERT INTO coupons
+			(name, description, discount_type, percent_off, amount_off_cents, currency, min_amount_cents, scope, scope_id, valid_days, expires_at, enabled)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		RETURNING id, created_at, updated_at
+	`, []any{
+		c.Name, c.Description, c.DiscountType, c.PercentOff, c.AmountOffCents, c.Currency, c.MinAmountCents,
+		c.Scope, nullInt64(c.ScopeID), c.ValidDays, c.ExpiresAt, c.Enabled,
+	}, &c.ID, &c.CreatedAt, &c.UpdatedAt)
+}
+
+func (r *couponRepository) Update(ctx context.Context, c *service.Coupon) error {
+	err := scanSingleRow(ctx, r.q(ctx), `
+		UPDATE coupons
+		SET name = $2, description = $3, discount_type = $4, percent_off = $5, amount_off_cents = $6, currency = $7,
+			min_amount_cents = $8, scope = $9, scope_id = $10, valid_days = $11, expires_at = $12, enabled = $13, updated_at = NOW()
+		WHERE id = $1
+		RETURNING created_at, updated_at
+	`, []any{
+		c.ID, c.Name, c.Description, c.DiscountType, c.PercentOff, c.AmountOffCents, c.Currency, c.MinAmountCents,
+		c.Scope, nullInt64(c.ScopeID), c.ValidDays, c.ExpiresAt, c.Enabled,
+	}, &c.CreatedAt, &c.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return service.ErrCouponNotFound
+	}
+	return err
+}
+
+func (r *couponRepository) Delete(ctx context.Context, id int64) error {
+	result, err := r.q(ctx).ExecContext(ctx, `DELETE FROM coupons WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return service.ErrCouponNotFound
+	}
+	return nil
+}
+
+func (r *couponRepository) GetByID(ctx context.Context, id int64) (*service.Coupon, error) {
+	rows, err := r.q(ctx).QueryContext(ctx, `SELECT `+couponColumns+` FROM coupons c WHERE c.id = $1`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+		return nil, service.ErrCouponNotFound
+	}
+	c, err := scanCoupon(rows.Scan)
+	if err != nil {
+		return nil, err
+	}
+	return c, rows.Err()
+}
+
+// List 管理端列表，附带已发放与已使用数量
+func (r *couponRepository) List(ctx context.Context, params pagination.PaginationParams) ([]service.Coupon, *pagination.PaginationResult, error) {
+	var total int64
+	if err := scanSingleRow(ctx, r.q(ctx), `SELECT COUNT(*) FROM coupons`, nil, &total); err != nil {
+		return nil, nil, err
+	}
+	if total == 0 {
+		return []service.Coupon{}, paginationResultFromTotal(0, params), nil
+	}
+
+	rows, err := r.q(ctx).QueryContext(ctx, `
+		SELECT `+couponColumns+`,
+			(SELECT COUNT(*) FROM user_coupons uc WHERE uc.coupon_id = c.id),
+			(SELECT COUNT(*) FROM user_coupons uc WHERE uc.coupon_id = c.id AND uc.status = $1)
+		FROM coupons c
+		ORDER BY c.id DESC
+		LIMIT $2 OFFSET $3
+	`, service.UserCouponStatusUsed, params.Limit(), params.Offset())
+	if err != nil {
+		return nil, nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	out := make([]service.Coupon, 0)
+	for rows.Next() {
+		var issued, used int64
+		c, err := scanCoupon(rows.Scan, &issued, &used)
+		if err != nil {
+			return nil, nil, err
+		}
+		c.IssuedCount = issued
+		c.UsedCount = used
+		out = append(out, *c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+	return out, paginationResultFromTotal(total, params), nil
+}
+
+// ---------- 用户优惠券 ----------
+
+const userCouponColumns = `uc.id, uc.coupon_id, uc.user_id, uc.status, uc.source, uc.source_ref, uc.expires_at,
+	uc.order_no, uc.used_at, uc.created_at`
+
+func (r *couponRepository) Issue(ctx context.Context, uc *service.UserCoupon) error {
+	return scanSingleRow(ctx, r.q(ctx), `
+		INSERT INTO user_coupons (coupon_id, user_id, status, source, source_ref, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at
+	`, []any{
+		uc.CouponID, uc.UserID, uc.Status, uc.Source, uc.SourceRef, uc.ExpiresAt,
+	}, &uc.ID, &uc.CreatedAt)
+}
+
+func (r *couponRepository) queryUserCoupons(ctx context.Context, where string, args ...any) ([]service.UserCoupon, error) {
+	rows, err := r.q(ctx).QueryContext(ctx, `
+		SELECT `+userCouponColumns+`, `+couponColumns+`, COALESCE(u.email, '')
+		FROM user_coupons uc
+		JOIN coupons c ON c.id = uc.coupon_id
+		LEFT JOIN users u ON u.id = uc.user_id
+		`+where, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	out := make([]service.UserCoupon, 0)
+	for rows.Next() {
+		var uc service.UserCoupon
+		var expiresAt, usedAt sql.NullTime
+		userDest := []any{
+			&uc.ID, &uc.CouponID, &uc.UserID, &uc.Status, &uc.Source, &uc.SourceRef, &expiresAt,
+			&uc.OrderNo, &usedAt, &uc.CreatedAt,
+		}
+		coupon, err := scanCoupon(func(couponDest ...any) error {
+			return rows.Scan(append(userDest, couponDest...)...)
+		}, &uc.UserEmail)
+		if err != nil {
+			return nil, err
+		}
+		uc.ExpiresAt = nullTimePtr(expiresAt)
+		uc.UsedAt = nullTimePtr(usedAt)
+		uc.Coupon = coupon
+		out = append(out, uc)
+	}
+	return out, rows.Err()
+}
+
+func (r *couponRepository) GetUserCoupon(ctx context.Context, id int64) (*service.UserCoupon, error) {
+	items, err := r.queryUserCoupons(ctx, `WHERE uc.id = $1`, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(items) == 0 {
+		return nil, service.ErrUserCouponNotFound
+	}
+	return &items[0], nil
+}
+
+// ListUserCoupons 未使用的券按过期时间分为 unused 与 expired
+func (r *couponRepository) ListUserCoupons(ctx context.Context, params pagination.PaginationParams, filter service.UserCouponFilter) ([]service.UserCoupon, *pagination.PaginationResult, error) {
+	conds := []string{"1=1"}
+	args := []any{}
+	if filter.UserID > 0 {
+		args = append(args, filter.UserID)
+		conds = append(conds, fmt.Sprintf("uc.user_id = $%d", len(args)))
+	}
+	if filter.CouponID > 0 {
+		args = append(args, filter.CouponID)
+		conds = append(conds, fmt.Sprintf("uc.coupon_id = $%d", len(args)))
+	}
+	switch filter.Status {
+	case "":
+	case service.UserCouponStatusUnused:
+		args = append(args, service.UserCouponStatusUnused)
+		conds = append(conds, fmt.Sprintf("uc.status = $%d AND (uc.expires_at IS NULL OR uc.expires_at > NOW())", len(args)))
+	case service.UserCouponStatusExpired:
+		args = append(args, service.UserCouponStatusUnused)
+		conds = append(conds, fmt.Sprintf("uc.status = $%d AND uc.expires_at <= NOW()", len(args)))
+	default:
+		args = append(args, filter.Status)
+		conds = append(conds, fmt.Sprintf("uc.status = $%d", len(args)))
+	}
+	where := strings.Join(conds, " AND ")
+
+	var total int64
+	if err := scanSingleRow(ctx, r.q(ctx), `SELECT COUNT(*) FROM user_coupons uc WHERE `+where, args, &total); err != nil {
+		return nil, nil, err
+	}
+	if total == 0 {
+		return []service.UserCoupon{}, paginationResultFromTotal(0, params), nil
+	}
+
+	items, err := r.queryUserCoupons(ctx, fmt.Sprintf(`
+		WHERE %s
+		ORDER BY uc.id DESC
+		LIMIT $%d OFFSET $%d
+	`, where, len(args)+1, len(args)+2), append(args, params.Limit(), params.Offset())...)
+	if err != nil {
+		return nil, nil, err
+	}
+	return items, paginationResultFromTotal(total, params), nil
+}
+
+func (r *couponRepository) LockForOrder(ctx context.Context, id, userID int64, orderNo string, now time.Time) (bool, error) {
+	result, err := r.q(ctx).ExecContext(ctx, `
+		UPDATE user_coupons SET status = $4, order_no = $3
+		WHERE id = $1 AND user_id = $2 AND status = $5 AND (expires_at IS NULL OR expires_at > $6)
+	`, id, userID, orderNo, service.UserCouponStatusLocked, service.UserCouponStatusUnused, now)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+func (r *couponRepository) ReleaseOrder(ctx context.Context, orderNo string) error {
+	_, err := r.q(ctx).ExecContext(ctx, `
+		UPDATE user_coupons SET status = $2, order_no = ''
+		WHERE order_no = $1 AND status = $3
+	`, orderNo, service.UserCouponStatusUnused, service.UserCouponStatusLocked)
+	return err
+}
+
+// ConsumeForOrder 已由该订单核销时视为成功，重复发放不会失败
+func (r *couponRepository) ConsumeForOrder(ctx context.Context, id int64, orderNo string) (bool, error) {
+	var status string
+	err := scanSingleRow(ctx, r.q(ctx), `
+		UPDATE user_coupons
+		SET status = $3, order_no = $2, used_at = COALESCE(used_at, NOW())
+		WHERE id = $1 AND (
+			status = $4
+			OR (status IN ($5, $3) AND order_no = $2)
+		)
+		RETURNING status
+	`, []any{
+		id, orderNo, service.UserCouponStatusUsed, service.UserCouponStatusUnused, service.UserCouponStatusLocked,
+	}, &status)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
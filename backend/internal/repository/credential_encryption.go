@@ -0,0 +1,292 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+
+	dbent "github.com/Wei-Shaw/sub2api/ent"
+	"github.com/Wei-Shaw/sub2api/ent/hook"
+)
+
+// credentialResealBatchSize 迁移/轮换时每批扫描的行数
+const credentialResealBatchSize = 200
+
+// CredentialResealStats 汇总一次加密迁移或主密钥轮换的结果
+type CredentialResealStats struct {
+	AccountsUpdated int // 被加密或重新包装的账号数
+	ProxiesUpdated  int // 被加密或重新包装的代理数
+	Conflicts       int // 处理期间被并发修改而跳过的行（可重新执行）
+	Failed          int // 无法解密的行（通常是缺少对应的旧主密钥）
+}
+
+// registerCredentialEncryption 为 Account/Proxy 注册 ent hook 与拦截器：
+// 写入前加密 credentials / password，查询结果返回前解密，对仓储与业务层透明。
+// 未配置主密钥时 enc 为 nil：写入保持明文，读到密文会返回错误而不是把密文当凭证使用。
+func registerCredentialEncryption(client *dbent.Client, enc *CredentialEncryptor) {
+	client.Account.Use(func(next dbent.Mutator) dbent.Mutator {
+		return hook.AccountFunc(func(ctx context.Context, m *dbent.AccountMutation) (dbent.Value, error) {
+			plain, ok := m.Credentials()
+			if !ok || !enc.Enabled() {
+				return next.Mutate(ctx, m)
+			}
+			sealed, err := enc.EncryptCredentials(plain)
+			if err != nil {
+				return nil, fmt.Errorf("encrypt account credentials: %w", err)
+			}
+			m.SetCredentials(sealed)
+			v, err := next.Mutate(ctx, m)
+			// 返回给调用方的实体保持明文，与查询结果一致
+			if acc, ok := v.(*dbent.Account); ok && acc != nil {
+				acc.Credentials = plain
+			}
+			return v, err
+		})
+	})
+	client.Account.Intercept(dbent.InterceptFunc(func(next dbent.Querier) dbent.Querier {
+		return dbent.QuerierFunc(func(ctx context.Context, q dbent.Query) (dbent.Value, error) {
+			v, err := next.Query(ctx, q)
+			if err != nil {
+				return v, err
+			}
+			if accounts, ok := v.([]*dbent.Account); ok {
+				for _, acc := range accounts {
+					plain, err := enc.DecryptCredentials(acc.Credentials)
+					if err != nil {
+						return nil, fmt.Errorf("decrypt credentials of account %d: %w", acc.ID, err)
+					}
+					acc.Credentials = plain
+				}
+			}
+			return v, nil
+		})
+	}))
+
+	client.Proxy.Use(func(next dbent.Mutator) dbent.Mutator {
+		return hook.ProxyFunc(func(ctx context.Context, m *dbent.ProxyMutation) (dbent.Value, error) {
+			plain, ok := m.Password()
+			if !ok || plain == "" || !enc.Enabled() {
+				return next.Mutate(ctx, m)
+			}
+			sealed, err := enc.EncryptString(plain)
+			if err != nil {
+				return nil, fmt.Errorf("encrypt proxy password: %w", err)
+			}
+			m.SetPassword(sealed)
+			v, err := next.Mutate(ctx, m)
+			if p, ok := v.(*dbent.Proxy); ok && p != nil {
+				p.Password = &plain
+			}
+			return v, err
+		})
+	})
+	client.Proxy.Intercept(dbent.InterceptFunc(func(next dbent.Querier) dbent.Querier {
+		return dbent.QuerierFunc(func(ctx context.Context, q dbent.Query) (dbent.Value, error) {
+			v, err := next.Query(ctx, q)
+			if err != nil {
+				return v, err
+			}
+			if proxies, ok := v.([]*dbent.Proxy); ok {
+				for _, p := range proxies {
+					if p.Password == nil {
+						continue
+					}
+					plain, err := enc.DecryptString(*p.Password)
+					if err != nil {
+						return nil, fmt.Errorf("decrypt password of proxy %d: %w", p.ID, err)
+					}
+					p.Password = &plain
+				}
+			}
+			return v, nil
+		})
+	}))
+}
+
+// ensureCredentialsEncrypted 是启动阶段的数据迁移：
+//   - 配置了主密钥：把历史明文凭证/代理密码加密（包括软删除的行）
+//   - 未配置主密钥但库中已有密文：直接报错，避免服务带着无法解密的账号启动
+func ensureCredentialsEncrypted(ctx context.Context, db *sql.DB, enc *CredentialEncryptor) error {
+	if !enc.Enabled() {
+		var encrypted bool
+		err := db.QueryRowContext(ctx, `
+			SELECT EXISTS (SELECT 1 FROM accounts WHERE credentials ? $1)
+				OR EXISTS (SELECT 1 FROM proxies WHERE password LIKE $2)
+		`, credentialEnvelopeField, encryptedStringPrefix+"%").Scan(&encrypted)
+		if err != nil {
+			return fmt.Errorf("check encrypted credentials: %w", err)
+		}
+		if encrypted {
+			return errors.New("database contains encrypted credentials but security.credential_encryption.master_key is not configured")
+		}
+		return nil
+	}
+
+	stats, err := resealStoredCredentials(ctx, db, enc, false)
+	if err != nil {
+		return fmt.Errorf("encrypt stored credentials: %w", err)
+	}
+	if stats.AccountsUpdated > 0 || stats.ProxiesUpdated > 0 {
+		log.Printf("Credential encryption: encrypted %d account(s) and %d proxy password(s)", stats.AccountsUpdated, stats.ProxiesUpdated)
+	}
+	if stats.Failed > 0 {
+		log.Printf("Warning: %d credential row(s) could not be decrypted with the configured keys", stats.Failed)
+	}
+	return nil
+}
+
+// RotateCredentialEncryption 用当前主密钥重新包装所有数据密钥（同时加密残留的明文）。
+// 仅更新 DEK 的包装，不改变明文内容；每行使用 compare-and-swap 写回，可在服务运行期间执行。
+func RotateCredentialEncryption(ctx context.Context, db *sql.DB, enc *CredentialEncryptor) (CredentialResealStats, error) {
+	if !enc.Enabled() {
+		return CredentialResealStats{}, ErrCredentialKeyMissing
+	}
+	return resealStoredCredentials(ctx, db, enc, true)
+}
+
+func resealStoredCredentials(ctx context.Context, db *sql.DB, enc *CredentialEncryptor, rewrap bool) (CredentialResealStats, error) {
+	var stats CredentialResealStats
+	if err := resealAccountCredentials(ctx, db, enc, rewrap, &stats); err != nil {
+		return stats, err
+	}
+	if err := resealProxyPasswords(ctx, db, enc, rewrap, &stats); err != nil {
+		return stats, err
+	}
+	return stats, nil
+}
+
+func resealAccountCredentials(ctx context.Context, db *sql.DB, enc *CredentialEncryptor, rewrap bool, stats *CredentialResealStats) error {
+	type row struct {
+		id  int64
+		raw []byte
+	}
+	var lastID int64
+	for {
+		rows, err := db.QueryContext(ctx, `
+			SELECT id, credentials FROM accounts
+			WHERE id > $1
+			ORDER BY id
+			LIMIT $2
+		`, lastID, credentialResealBatchSize)
+		if err != nil {
+			return err
+		}
+		batch := make([]row, 0, credentialResealBatchSize)
+		for rows.Next() {
+			var r row
+			if err := rows.Scan(&r.id, &r.raw); err != nil {
+				_ = rows.Close()
+				return err
+			}
+			batch = append(batch, r)
+		}
+		if err := rows.Close(); err != nil {
+			return err
+		}
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		if len(batch) == 0 {
+			return nil
+		}
+
+		for _, r := range batch {
+			lastID = r.id
+			var stored map[string]any
+			if len(r.raw) > 0 {
+				if err := json.Unmarshal(r.raw, &stored); err != nil {
+					return fmt.Errorf("parse credentials of account %d: %w", r.id, err)
+				}
+			}
+			sealed, changed, err := enc.resealCredentials(stored, rewrap)
+			if err != nil {
+				log.Printf("Warning: reseal credentials of account %d failed: %v", r.id, err)
+				stats.Failed++
+				continue
+			}
+			if !changed {
+				continue
+			}
+			payload, err := json.Marshal(sealed)
+			if err != nil {
+				return err
+			}
+			// compare-and-swap：期间被服务改写过的行跳过，下一次执行再处理
+			res, err := db.ExecContext(ctx,
+				"UPDATE accounts SET credentials = $1::jsonb WHERE id = $2 AND credentials = $3::jsonb",
+				string(payload), r.id, string(r.raw))
+			if err != nil {
+				return err
+			}
+			if n, _ := res.RowsAffected(); n > 0 {
+				stats.AccountsUpdated++
+			} else {
+				stats.Conflicts++
+			}
+		}
+	}
+}
+
+func resealProxyPasswords(ctx context.Context, db *sql.DB, enc *CredentialEncryptor, rewrap bool, stats *CredentialResealStats) error {
+	type row struct {
+		id       int64
+		password string
+	}
+	var lastID int64
+	for {
+		rows, err := db.QueryContext(ctx, `
+			SELECT id, password FROM proxies
+			WHERE id > $1 AND password IS NOT NULL AND password <> ''
+			ORDER BY id
+			LIMIT $2
+		`, lastID, credentialResealBatchSize)
+		if err != nil {
+			return err
+		}
+		batch := make([]row, 0, credentialResealBatchSize)
+		for rows.Next() {
+			var r row
+			if err := rows.Scan(&r.id, &r.password); err != nil {
+				_ = rows.Close()
+				return err
+			}
+			batch = append(batch, r)
+		}
+		if err := rows.Close(); err != nil {
+			return err
+		}
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		if len(batch) == 0 {
+			return nil
+		}
+
+		for _, r := range batch {
+			lastID = r.id
+			sealed, changed, err := enc.resealString(r.password, rewrap)
+			if err != nil {
+				log.Printf("Warning: reseal password of proxy %d failed: %v", r.id, err)
+				stats.Failed++
+				continue
+			}
+			if !changed {
+				continue
+			}
+			res, err := db.ExecContext(ctx,
+				"UPDATE proxies SET password = $1 WHERE id = $2 AND password = $3",
+				sealed, r.id, r.password)
+			if err != nil {
+				return err
+			}
+			if n, _ := res.RowsAffected(); n > 0 {
+				stats.ProxiesUpdated++
+			} else {
+				stats.Conflicts++
+			}
+		}
+	}
+}
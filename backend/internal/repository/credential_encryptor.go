@@ -0,0 +1,470 @@
+package repository
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/Wei-Shaw/sub2api/internal/config"
+)
+
+const (
+	// credentialEnvelopeField 敏感字段加密后存放在该字段中，其余字段保持明文
+	credentialEnvelopeField = "__envelope"
+	// credentialEnvelopeVersion 信封格式版本：v2 只加密敏感字段
+	credentialEnvelopeVersion = 2
+	// credentialEnvelopeVersionFull 历史格式：整个 credentials 都在信封内，重新加密时拆分为 v2
+	credentialEnvelopeVersionFull = 1
+	// encryptedStringPrefix 加密后的字符串（如代理密码）前缀，格式：enc:v1:<kid>:<dek>:<data>
+	encryptedStringPrefix = "enc:v1:"
+)
+
+var (
+	// ErrCredentialKeyMissing 数据已加密，但当前配置中没有对应的主密钥
+	ErrCredentialKeyMissing = errors.New("credential encryption key not configured")
+	// ErrCredentialEnvelopeInvalid 信封格式损坏
+	ErrCredentialEnvelopeInvalid = errors.New("invalid credential envelope")
+)
+
+// CredentialEncryptor 使用信封加密保护账号凭证与代理密码。
+//
+// 每次加密都会生成随机数据密钥（DEK），用 AES-256-GCM 加密明文；
+// DEK 再由主密钥（KEK）加密后与密文一起存储，并记录主密钥指纹（kid）。
+// 轮换主密钥时只需用新主密钥重新包装 DEK，无需重新加密数据本身。
+//
+// nil 表示未启用加密：写入保持明文，读取遇到密文时返回 ErrCredentialKeyMissing。
+type CredentialEncryptor struct {
+	activeKeyID string
+	keys        map[string][]byte
+}
+
+type credentialEnvelope struct {
+	Version int
+	KeyID   string
+	DEK     []byte // nonce + 被主密钥加密的 DEK
+	Data    []byte // nonce + 被 DEK 加密的明文
+}
+
+// credentialSecretKeys 明确属于敏感信息的凭证字段
+var credentialSecretKeys = map[string]struct{}{
+	"access_token":      {},
+	"refresh_token":     {},
+	"id_token":          {},
+	"api_key":           {},
+	"session_key":       {},
+	"session_token":     {},
+	"token":             {},
+	"client_secret":     {},
+	"private_key":       {},
+	"password":          {},
+	"cookie":            {},
+	"cookies":           {},
+	"secret":            {},
+	"secret_access_key": {},
+	"function_key":      {},
+}
+
+// credentialPlainKeys 命中敏感后缀但不含机密的字段
+var credentialPlainKeys = map[string]struct{}{
+	"token_type":     {},
+	"_token_version": {},
+}
+
+// isSecretCredentialKey 判断凭证字段是否需要加密。
+// model_mapping、base_url 等非敏感字段保持明文，SQL 数据迁移仍可直接修改。
+func isSecretCredentialKey(key string) bool {
+	k := strings.ToLower(key)
+	if _, ok := credentialPlainKeys[k]; ok {
+		return false
+	}
+	if _, ok := credentialSecretKeys[k]; ok {
+		return true
+	}
+	for _, suffix := range []string{"_token", "_secret", "_key", "_password"} {
+		if strings.HasSuffix(k, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// NewCredentialEncryptor 根据配置创建凭证加密器；未配置主密钥时返回 nil。
+func NewCredentialEncryptor(cfg *config.Config) (*CredentialEncryptor, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+	encCfg := cfg.Security.CredentialEncryption
+	if strings.TrimSpace(encCfg.MasterKey) == "" {
+		return nil, nil
+	}
+
+	e := &CredentialEncryptor{keys: make(map[string][]byte, 1+len(encCfg.PreviousKeys))}
+	activeID, err := e.addKey(encCfg.MasterKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid credential master key: %w", err)
+	}
+	e.activeKeyID = activeID
+	for i, previous := range encCfg.PreviousKeys {
+		if _, err := e.addKey(previous); err != nil {
+			return nil, fmt.Errorf("invalid credential previous key #%d: %w", i, err)
+		}
+	}
+	return e, nil
+}
+
+func (e *CredentialEncryptor) addKey(hexKey string) (string, error) {
+	key, err := hex.DecodeString(strings.TrimSpace(hexKey))
+	if err != nil {
+		return "", err
+	}
+	if len(key) != 32 {
+		return "", fmt.Errorf("key must be 32 bytes (64 hex chars), got %d bytes", len(key))
+	}
+	id := credentialKeyID(key)
+	e.keys[id] = key
+	return id, nil
+}
+
+// credentialKeyID 使用主密钥的 SHA-256 指纹作为 kid，无需额外配置即可区分新旧密钥
+func credentialKeyID(key []byte) string {
+	sum := sha256.Sum256(key)
+	return hex.EncodeToString(sum[:8])
+}
+
+// Enabled 是否配置了主密钥
+func (e *CredentialEncryptor) Enabled() bool {
+	return e != nil && e.activeKeyID != ""
+}
+
+// ActiveKeyID 返回当前主密钥的指纹
+func (e *CredentialEncryptor) ActiveKeyID() string {
+	if e == nil {
+		return ""
+	}
+	return e.activeKeyID
+}
+
+// EncryptCredentials 将敏感字段加密进信封，非敏感字段保持明文；
+// 未启用加密、已是密文或不含敏感字段时原样返回。
+func (e *CredentialEncryptor) EncryptCredentials(credentials map[string]any) (map[string]any, error) {
+	if !e.Enabled() || isEncryptedCredentials(credentials) {
+		return credentials, nil
+	}
+	secrets := make(map[string]any)
+	out := make(map[string]any, len(credentials))
+	for k, v := range credentials {
+		if isSecretCredentialKey(k) {
+			secrets[k] = v
+		} else {
+			out[k] = v
+		}
+	}
+	if len(secrets) == 0 {
+		return credentials, nil
+	}
+	plaintext, err := json.Marshal(secrets)
+	if err != nil {
+		return nil, fmt.Errorf("marshal credentials: %w", err)
+	}
+	env, err := e.seal(plaintext)
+	if err != nil {
+		return nil, err
+	}
+	out[credentialEnvelopeField] = env.toValue()
+	return out, nil
+}
+
+// DecryptCredentials 解密信封 JSON；明文（历史数据）原样返回。
+func (e *CredentialEncryptor) DecryptCredentials(stored map[string]any) (map[string]any, error) {
+	if !isEncryptedCredentials(stored) {
+		return stored, nil
+	}
+	env, err := envelopeFromMap(stored)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := e.open(env)
+	if err != nil {
+		return nil, err
+	}
+	var secrets map[string]any
+	if err := json.Unmarshal(plaintext, &secrets); err != nil {
+		return nil, fmt.Errorf("unmarshal credentials: %w", err)
+	}
+	out := make(map[string]any, len(stored)+len(secrets))
+	for k, v := range stored {
+		if k != credentialEnvelopeField {
+			out[k] = v
+		}
+	}
+	for k, v := range secrets {
+		out[k] = v
+	}
+	return out, nil
+}
+
+// EncryptString 加密字符串（代理密码）；空串、未启用加密或已是密文时原样返回。
+func (e *CredentialEncryptor) EncryptString(value string) (string, error) {
+	if value == "" || !e.Enabled() || isEncryptedString(value) {
+		return value, nil
+	}
+	env, err := e.seal([]byte(value))
+	if err != nil {
+		return "", err
+	}
+	return env.toString(), nil
+}
+
+// DecryptString 解密字符串；明文原样返回。
+func (e *CredentialEncryptor) DecryptString(value string) (string, error) {
+	if !isEncryptedString(value) {
+		return value, nil
+	}
+	env, err := envelopeFromString(value)
+	if err != nil {
+		return "", err
+	}
+	plaintext, err := e.open(env)
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+// resealCredentials 供迁移/轮换使用：明文中的敏感字段会被加密，历史 v1 信封会拆分为 v2；
+// rewrap=true 时，由旧主密钥包装的 DEK 会改用当前主密钥重新包装。返回值 changed 表示是否需要写回。
+func (e *CredentialEncryptor) resealCredentials(stored map[string]any, rewrap bool) (map[string]any, bool, error) {
+	if !isEncryptedCredentials(stored) {
+		sealed, err := e.EncryptCredentials(stored)
+		return sealed, err == nil && isEncryptedCredentials(sealed), err
+	}
+	env, err := envelopeFromMap(stored)
+	if err != nil {
+		return nil, false, err
+	}
+	if env.Version == credentialEnvelopeVersionFull {
+		if !e.Enabled() {
+			return nil, false, ErrCredentialKeyMissing
+		}
+		plain, err := e.DecryptCredentials(stored)
+		if err != nil {
+			return nil, false, err
+		}
+		sealed, err := e.EncryptCredentials(plain)
+		return sealed, err == nil, err
+	}
+	if !rewrap {
+		return stored, false, nil
+	}
+	changed, err := e.rewrap(env)
+	if err != nil || !changed {
+		return stored, false, err
+	}
+	out := make(map[string]any, len(stored))
+	for k, v := range stored {
+		out[k] = v
+	}
+	out[credentialEnvelopeField] = env.toValue()
+	return out, true, nil
+}
+
+// resealString 与 resealCredentials 相同，用于字符串密文
+func (e *CredentialEncryptor) resealString(value string, rewrap bool) (string, bool, error) {
+	if value == "" {
+		return value, false, nil
+	}
+	if !isEncryptedString(value) {
+		sealed, err := e.EncryptString(value)
+		return sealed, err == nil && e.Enabled(), err
+	}
+	if !rewrap {
+		return value, false, nil
+	}
+	env, err := envelopeFromString(value)
+	if err != nil {
+		return "", false, err
+	}
+	changed, err := e.rewrap(env)
+	if err != nil || !changed {
+		return value, false, err
+	}
+	return env.toString(), true, nil
+}
+
+func (e *CredentialEncryptor) seal(plaintext []byte) (*credentialEnvelope, error) {
+	dek := make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, dek); err != nil {
+		return nil, fmt.Errorf("generate data key: %w", err)
+	}
+	data, err := aesGCMSeal(dek, plaintext)
+	if err != nil {
+		return nil, err
+	}
+	wrapped, err := aesGCMSeal(e.keys[e.activeKeyID], dek)
+	if err != nil {
+		return nil, err
+	}
+	return &credentialEnvelope{Version: credentialEnvelopeVersion, KeyID: e.activeKeyID, DEK: wrapped, Data: data}, nil
+}
+
+func (e *CredentialEncryptor) unwrapDEK(env *credentialEnvelope) ([]byte, error) {
+	if e == nil {
+		return nil, ErrCredentialKeyMissing
+	}
+	kek, ok := e.keys[env.KeyID]
+	if !ok {
+		return nil, fmt.Errorf("%w: kid=%s", ErrCredentialKeyMissing, env.KeyID)
+	}
+	dek, err := aesGCMOpen(kek, env.DEK)
+	if err != nil {
+		return nil, fmt.Errorf("unwrap data key: %w", err)
+	}
+	return dek, nil
+}
+
+func (e *CredentialEncryptor) open(env *credentialEnvelope) ([]byte, error) {
+	dek, err := e.unwrapDEK(env)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := aesGCMOpen(dek, env.Data)
+	if err != nil {
+		return nil, fmt.Errorf("decrypt credentials: %w", err)
+	}
+	return plaintext, nil
+}
+
+// rewrap 用当前主密钥重新包装 DEK，数据密文保持不变
+func (e *CredentialEncryptor) rewrap(env *credentialEnvelope) (bool, error) {
+	if !e.Enabled() {
+		return false, ErrCredentialKeyMissing
+	}
+	if env.KeyID == e.activeKeyID {
+		return false, nil
+	}
+	dek, err := e.unwrapDEK(env)
+	if err != nil {
+		return false, err
+	}
+	wrapped, err := aesGCMSeal(e.keys[e.activeKeyID], dek)
+	if err != nil {
+		return false, err
+	}
+	env.KeyID = e.activeKeyID
+	env.DEK = wrapped
+	return true, nil
+}
+
+func isEncryptedCredentials(stored map[string]any) bool {
+	_, ok := stored[credentialEnvelopeField]
+	return ok
+}
+
+func isEncryptedString(value string) bool {
+	return strings.HasPrefix(value, encryptedStringPrefix)
+}
+
+func (env *credentialEnvelope) toValue() map[string]any {
+	return map[string]any{
+		"v":    env.Version,
+		"kid":  env.KeyID,
+		"dek":  base64.StdEncoding.EncodeToString(env.DEK),
+		"data": base64.StdEncoding.EncodeToString(env.Data),
+	}
+}
+
+func (env *credentialEnvelope) toString() string {
+	return encryptedStringPrefix + env.KeyID + ":" +
+		base64.StdEncoding.EncodeToString(env.DEK) + ":" +
+		base64.StdEncoding.EncodeToString(env.Data)
+}
+
+func envelopeFromMap(stored map[string]any) (*credentialEnvelope, error) {
+	raw, ok := stored[credentialEnvelopeField].(map[string]any)
+	if !ok {
+		return nil, ErrCredentialEnvelopeInvalid
+	}
+	version := 0
+	switch v := raw["v"].(type) {
+	case float64: // 从数据库读出的 JSON 数字
+		version = int(v)
+	case int:
+		version = v
+	}
+	if version != credentialEnvelopeVersion && version != credentialEnvelopeVersionFull {
+		return nil, fmt.Errorf("%w: unsupported version %v", ErrCredentialEnvelopeInvalid, raw["v"])
+	}
+	kid, _ := raw["kid"].(string)
+	dek, _ := raw["dek"].(string)
+	data, _ := raw["data"].(string)
+	env, err := decodeEnvelope(kid, dek, data)
+	if err != nil {
+		return nil, err
+	}
+	env.Version = version
+	return env, nil
+}
+
+func envelopeFromString(value string) (*credentialEnvelope, error) {
+	parts := strings.Split(strings.TrimPrefix(value, encryptedStringPrefix), ":")
+	if len(parts) != 3 {
+		return nil, ErrCredentialEnvelopeInvalid
+	}
+	return decodeEnvelope(parts[0], parts[1], parts[2])
+}
+
+func decodeEnvelope(kid, dek, data string) (*credentialEnvelope, error) {
+	if kid == "" || dek == "" || data == "" {
+		return nil, ErrCredentialEnvelopeInvalid
+	}
+	dekBytes, err := base64.StdEncoding.DecodeString(dek)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrCredentialEnvelopeInvalid, err)
+	}
+	dataBytes, err := base64.StdEncoding.DecodeString(data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrCredentialEnvelopeInvalid, err)
+	}
+	return &credentialEnvelope{KeyID: kid, DEK: dekBytes, Data: dataBytes}, nil
+}
+
+// aesGCMSeal 输出 nonce + ciphertext + tag
+func aesGCMSeal(key, plaintext []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("create cipher: %w", err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("create gcm: %w", err)
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, fmt.Errorf("generate nonce: %w", err)
+	}
+	return gcm.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+func aesGCMOpen(key, data []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("create cipher: %w", err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("create gcm: %w", err)
+	}
+	if len(data) < gcm.NonceSize() {
+		return nil, fmt.Errorf("ciphertext too short")
+	}
+	nonce, ciphertext := data[:gcm.NonceSize()], data[gcm.NonceSize():]
+	return gcm.Open(nil, nonce, ciphertext, nil)
+}
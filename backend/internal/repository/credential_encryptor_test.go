@@ -0,0 +1,293 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	dbent "github.com/Wei-Shaw/sub2api/ent"
+	"github.com/Wei-Shaw/sub2api/ent/enttest"
+	"github.com/Wei-Shaw/sub2api/internal/config"
+	"github.com/stretchr/testify/require"
+
+	"entgo.io/ent/dialect"
+	entsql "entgo.io/ent/dialect/sql"
+	_ "modernc.org/sqlite"
+)
+
+const (
+	testCredentialKeyA = "0000000000000000000000000000000000000000000000000000000000000001"
+	testCredentialKeyB = "0000000000000000000000000000000000000000000000000000000000000002"
+)
+
+func newTestCredentialEncryptor(t *testing.T, master string, previous ...string) *CredentialEncryptor {
+	t.Helper()
+	cfg := &config.Config{}
+	cfg.Security.CredentialEncryption.MasterKey = master
+	cfg.Security.CredentialEncryption.PreviousKeys = previous
+	enc, err := NewCredentialEncryptor(cfg)
+	require.NoError(t, err)
+	return enc
+}
+
+// roundTripJSON 模拟写入 JSONB 再读出的过程（数字会变成 float64）
+func roundTripJSON(t *testing.T, in map[string]any) map[string]any {
+	t.Helper()
+	raw, err := json.Marshal(in)
+	require.NoError(t, err)
+	var out map[string]any
+	require.NoError(t, json.Unmarshal(raw, &out))
+	return out
+}
+
+func TestNewCredentialEncryptor(t *testing.T) {
+	enc, err := NewCredentialEncryptor(&config.Config{})
+	require.NoError(t, err)
+	require.Nil(t, enc)
+	require.False(t, enc.Enabled())
+
+	cfg := &config.Config{}
+	cfg.Security.CredentialEncryption.MasterKey = "abcd"
+	_, err = NewCredentialEncryptor(cfg)
+	require.Error(t, err)
+
+	enc = newTestCredentialEncryptor(t, testCredentialKeyA)
+	require.True(t, enc.Enabled())
+	require.Len(t, enc.ActiveKeyID(), 16)
+}
+
+func TestCredentialEncryptor_CredentialsRoundTrip(t *testing.T) {
+	enc := newTestCredentialEncryptor(t, testCredentialKeyA)
+	plain := map[string]any{
+		"refresh_token": "rt-secret",
+		"token_type":    "Bearer",
+		"expires_at":    float64(123),
+		"model_mapping": map[string]any{"a": "b"},
+	}
+
+	sealed, err := enc.EncryptCredentials(plain)
+	require.NoError(t, err)
+	require.True(t, isEncryptedCredentials(sealed))
+	stored := roundTripJSON(t, sealed)
+	raw, _ := json.Marshal(stored)
+	require.NotContains(t, string(raw), "rt-secret")
+	require.NotContains(t, stored, "refresh_token")
+	// 非敏感字段保持明文，SQL 数据迁移仍可直接修改
+	require.Equal(t, map[string]any{"a": "b"}, stored["model_mapping"])
+	require.Equal(t, float64(123), stored["expires_at"])
+	require.Equal(t, "Bearer", stored["token_type"])
+
+	again, err := enc.EncryptCredentials(stored)
+	require.NoError(t, err)
+	require.Equal(t, stored, again, "already encrypted credentials must not be double-encrypted")
+
+	out, err := enc.DecryptCredentials(stored)
+	require.NoError(t, err)
+	require.Equal(t, plain, out)
+
+	// 历史明文原样返回
+	legacy := map[string]any{"api_key": "sk-legacy"}
+	out, err = enc.DecryptCredentials(legacy)
+	require.NoError(t, err)
+	require.Equal(t, legacy, out)
+
+	// 不含敏感字段时无需加密
+	nothingSecret := map[string]any{"base_url": "https://example.com"}
+	out, err = enc.EncryptCredentials(nothingSecret)
+	require.NoError(t, err)
+	require.Equal(t, nothingSecret, out)
+	_, changed, err := enc.resealCredentials(nothingSecret, false)
+	require.NoError(t, err)
+	require.False(t, changed)
+}
+
+func TestCredentialEncryptor_SplitsLegacyFullEnvelope(t *testing.T) {
+	enc := newTestCredentialEncryptor(t, testCredentialKeyA)
+	plain := map[string]any{"api_key": "sk-secret", "model_mapping": map[string]any{"a": "b"}}
+
+	// 构造 v1 历史格式：整个 credentials 都在信封内
+	payload, err := json.Marshal(plain)
+	require.NoError(t, err)
+	env, err := enc.seal(payload)
+	require.NoError(t, err)
+	env.Version = credentialEnvelopeVersionFull
+	legacy := roundTripJSON(t, map[string]any{credentialEnvelopeField: env.toValue()})
+
+	out, err := enc.DecryptCredentials(legacy)
+	require.NoError(t, err)
+	require.Equal(t, plain, out)
+
+	split, changed, err := enc.resealCredentials(legacy, false)
+	require.NoError(t, err)
+	require.True(t, changed)
+	stored := roundTripJSON(t, split)
+	require.Equal(t, map[string]any{"a": "b"}, stored["model_mapping"])
+	require.NotContains(t, stored, "api_key")
+
+	out, err = enc.DecryptCredentials(stored)
+	require.NoError(t, err)
+	require.Equal(t, plain, out)
+
+	_, changed, err = enc.resealCredentials(stored, false)
+	require.NoError(t, err)
+	require.False(t, changed)
+}
+
+func TestCredentialEncryptor_StringRoundTrip(t *testing.T) {
+	enc := newTestCredentialEncryptor(t, testCredentialKeyA)
+
+	sealed, err := enc.EncryptString("proxy-pass")
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(sealed, encryptedStringPrefix))
+	out, err := enc.DecryptString(sealed)
+	require.NoError(t, err)
+	require.Equal(t, "proxy-pass", out)
+
+	empty, err := enc.EncryptString("")
+	require.NoError(t, err)
+	require.Empty(t, empty)
+
+	_, err = enc.DecryptString(encryptedStringPrefix + "broken")
+	require.ErrorIs(t, err, ErrCredentialEnvelopeInvalid)
+}
+
+func TestCredentialEncryptor_MissingKey(t *testing.T) {
+	sealed, err := newTestCredentialEncryptor(t, testCredentialKeyA).EncryptCredentials(map[string]any{"api_key": "v"})
+	require.NoError(t, err)
+	stored := roundTripJSON(t, sealed)
+
+	var disabled *CredentialEncryptor
+	_, err = disabled.DecryptCredentials(stored)
+	require.ErrorIs(t, err, ErrCredentialKeyMissing)
+
+	_, err = newTestCredentialEncryptor(t, testCredentialKeyB).DecryptCredentials(stored)
+	require.ErrorIs(t, err, ErrCredentialKeyMissing)
+
+	// 未启用加密时写入保持明文
+	plain := map[string]any{"api_key": "v"}
+	out, err := disabled.EncryptCredentials(plain)
+	require.NoError(t, err)
+	require.Equal(t, plain, out)
+}
+
+func TestCredentialEncryptor_RewrapWithNewMasterKey(t *testing.T) {
+	oldEnc := newTestCredentialEncryptor(t, testCredentialKeyA)
+	sealed, err := oldEnc.EncryptCredentials(map[string]any{"session_key": "sess"})
+	require.NoError(t, err)
+	stored := roundTripJSON(t, sealed)
+	sealedPassword, err := oldEnc.EncryptString("pw")
+	require.NoError(t, err)
+
+	rotating := newTestCredentialEncryptor(t, testCredentialKeyB, testCredentialKeyA)
+
+	// 不轮换时已加密数据保持不变
+	_, changed, err := rotating.resealCredentials(stored, false)
+	require.NoError(t, err)
+	require.False(t, changed)
+
+	rewrapped, changed, err := rotating.resealCredentials(stored, true)
+	require.NoError(t, err)
+	require.True(t, changed)
+	rewrappedPassword, changed, err := rotating.resealString(sealedPassword, true)
+	require.NoError(t, err)
+	require.True(t, changed)
+
+	// 轮换完成后移除旧密钥，仍可解密
+	newOnly := newTestCredentialEncryptor(t, testCredentialKeyB)
+	out, err := newOnly.DecryptCredentials(roundTripJSON(t, rewrapped))
+	require.NoError(t, err)
+	require.Equal(t, "sess", out["session_key"])
+	password, err := newOnly.DecryptString(rewrappedPassword)
+	require.NoError(t, err)
+	require.Equal(t, "pw", password)
+
+	// 已由当前主密钥包装的数据无需再次写回
+	_, changed, err = newOnly.resealCredentials(roundTripJSON(t, rewrapped), true)
+	require.NoError(t, err)
+	require.False(t, changed)
+}
+
+func newCredentialEncryptionTestClient(t *testing.T, enc *CredentialEncryptor) (*dbent.Client, *sql.DB) {
+	t.Helper()
+	name := strings.ReplaceAll(t.Name(), "/", "_")
+	db, err := sql.Open("sqlite", fmt.Sprintf("file:%s?mode=memory&cache=shared&_fk=1", name))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+	db.SetMaxOpenConns(1)
+	_, err = db.Exec("PRAGMA foreign_keys = ON")
+	require.NoError(t, err)
+
+	drv := entsql.OpenDB(dialect.SQLite, db)
+	client := enttest.NewClient(t, enttest.WithOptions(dbent.Driver(drv)))
+	t.Cleanup(func() { _ = client.Close() })
+	registerCredentialEncryption(client, enc)
+	return client, db
+}
+
+func TestRegisterCredentialEncryption_TransparentForEnt(t *testing.T) {
+	ctx := context.Background()
+	enc := newTestCredentialEncryptor(t, testCredentialKeyA)
+	client, db := newCredentialEncryptionTestClient(t, enc)
+
+	created, err := client.Account.Create().
+		SetName("acc").
+		SetPlatform("anthropic").
+		SetType("oauth").
+		SetCredentials(map[string]any{"refresh_token": "rt-secret"}).
+		Save(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "rt-secret", created.Credentials["refresh_token"])
+
+	var raw string
+	require.NoError(t, db.QueryRowContext(ctx, "SELECT credentials FROM accounts WHERE id = ?", created.ID).Scan(&raw))
+	require.NotContains(t, raw, "rt-secret")
+	require.Contains(t, raw, credentialEnvelopeField)
+
+	got, err := client.Account.Get(ctx, created.ID)
+	require.NoError(t, err)
+	require.Equal(t, "rt-secret", got.Credentials["refresh_token"])
+
+	p, err := client.Proxy.Create().
+		SetName("p").
+		SetProtocol("http").
+		SetHost("127.0.0.1").
+		SetPort(8080).
+		SetPassword("proxy-pass").
+		Save(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "proxy-pass", *p.Password)
+
+	require.NoError(t, db.QueryRowContext(ctx, "SELECT password FROM proxies WHERE id = ?", p.ID).Scan(&raw))
+	require.True(t, strings.HasPrefix(raw, encryptedStringPrefix))
+
+	exists, err := newProxyRepositoryWithSQL(client, db).ExistsByHostPortAuth(ctx, "127.0.0.1", 8080, "", "proxy-pass")
+	require.NoError(t, err)
+	require.True(t, exists)
+
+	withProxy, err := client.Account.UpdateOneID(created.ID).SetProxyID(p.ID).Save(ctx)
+	require.NoError(t, err)
+	loaded, err := client.Account.Query().WithProxy().Only(ctx)
+	require.NoError(t, err)
+	require.Equal(t, withProxy.ID, loaded.ID)
+	require.Equal(t, "proxy-pass", *loaded.Edges.Proxy.Password)
+}
+
+func TestRegisterCredentialEncryption_UnknownKeyFailsRead(t *testing.T) {
+	ctx := context.Background()
+	writer, db := newCredentialEncryptionTestClient(t, newTestCredentialEncryptor(t, testCredentialKeyA))
+	created, err := writer.Account.Create().
+		SetName("acc").
+		SetPlatform("anthropic").
+		SetType("apikey").
+		SetCredentials(map[string]any{"api_key": "sk"}).
+		Save(ctx)
+	require.NoError(t, err)
+
+	reader := enttest.NewClient(t, enttest.WithOptions(dbent.Driver(entsql.OpenDB(dialect.SQLite, db))))
+	registerCredentialEncryption(reader, nil)
+	_, err = reader.Account.Get(ctx, created.ID)
+	require.ErrorIs(t, err, ErrCredentialKeyMissing)
+}
@@ -24,7 +24,7 @@ import (
 //  1. 初始化全局时区设置，确保时间处理一致性
 //  2. 建立 PostgreSQL 数据库连接
 //  3. 自动执行数据库迁移，确保 schema 与代码同步
-//  4. 创建并返回 Ent 客户端实例
+//  4. 创建 Ent 客户端实例，并注册账号凭证/代理密码的透明加解密
 //
 // 重要提示：调用者必须负责关闭返回的 ent.Client（关闭时会自动关闭底层的 driver/db）。
 //
@@ -79,6 +79,18 @@ func InitEnt(cfg *config.Config) (*ent.Client, *sql.DB, error) {
 		return nil, nil, fmt.Errorf("validate config after secret bootstrap: %w", err)
 	}
 
+	// 账号凭证与代理密码的静态加密：注册透明加解密 hook，并加密历史明文数据。
+	credentialEncryptor, err := NewCredentialEncryptor(cfg)
+	if err != nil {
+		_ = client.Close()
+		return nil, nil, err
+	}
+	registerCredentialEncryption(client, credentialEncryptor)
+	if err := ensureCredentialsEncrypted(migrationCtx, drv.DB(), credentialEncryptor); err != nil {
+		_ = client.Close()
+		return nil, nil, err
+	}
+
 	// SIMPLE 模式：启动时补齐各平台默认分组。
 	// - anthropic/openai/gemini: 确保存在 <platform>-default
 	// - antigravity: 仅要求存在 >=2 个未软删除分组（用于 claude/gemini 混合调度场景）
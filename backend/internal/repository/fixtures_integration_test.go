@@ -265,6 +265,9 @@ func mustCreateApiKey(t *testing.T, client *dbent.Client, k *service.APIKey) *se
 	if k.GroupID != nil {
 		create.SetGroupID(*k.GroupID)
 	}
+	if k.CreatedByUserID != nil {
+		create.SetCreatedByUserID(*k.CreatedByUserID)
+	}
 	if !k.CreatedAt.IsZero() {
 		create.SetCreatedAt(k.CreatedAt)
 	}
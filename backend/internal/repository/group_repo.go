@@ -55,6 +55,8 @@ func (r *groupRepository) Create(ctx context.Context, groupIn *service.Group) er
 		SetNillableSoraVideoPricePerRequestHd(groupIn.SoraVideoPricePerRequestHD).
 		SetNillableVideoPricePerRequest(groupIn.VideoPricePerRequest).
 		SetNillableVideoPricePerRequestHd(groupIn.VideoPricePerRequestHD).
+		SetNillableAudioPricePerMinute(groupIn.AudioPricePerMinute).
+		SetNillableAudioSpeechPricePer1mChars(groupIn.AudioSpeechPricePer1MChars).
 		SetDefaultValidityDays(groupIn.DefaultValidityDays).
 		SetClaudeCodeOnly(groupIn.ClaudeCodeOnly).
 		SetNillableFallbackGroupID(groupIn.FallbackGroupID).
@@ -125,6 +127,8 @@ func (r *groupRepository) Update(ctx context.Context, groupIn *service.Group) er
 		SetNillableSoraVideoPricePerRequestHd(groupIn.SoraVideoPricePerRequestHD).
 		SetNillableVideoPricePerRequest(groupIn.VideoPricePerRequest).
 		SetNillableVideoPricePerRequestHd(groupIn.VideoPricePerRequestHD).
+		SetNillableAudioPricePerMinute(groupIn.AudioPricePerMinute).
+		SetNillableAudioSpeechPricePer1mChars(groupIn.AudioSpeechPricePer1MChars).
 		SetDefaultValidityDays(groupIn.DefaultValidityDays).
 		SetClaudeCodeOnly(groupIn.ClaudeCodeOnly).
 		SetModelRoutingEnabled(groupIn.ModelRoutingEnabled).
@@ -162,6 +166,16 @@ func (r *groupRepository) Update(ctx context.Context, groupIn *service.Group) er
 	} else {
 		builder = builder.ClearImagePrice4k()
 	}
+	if groupIn.AudioPricePerMinute != nil {
+		builder = builder.SetAudioPricePerMinute(*groupIn.AudioPricePerMinute)
+	} else {
+		builder = builder.ClearAudioPricePerMinute()
+	}
+	if groupIn.AudioSpeechPricePer1MChars != nil {
+		builder = builder.SetAudioSpeechPricePer1mChars(*groupIn.AudioSpeechPricePer1MChars)
+	} else {
+		builder = builder.ClearAudioSpeechPricePer1mChars()
+	}
 
 	// 处理 FallbackGroupID：nil 时清除，否则设置
 	if groupIn.FallbackGroupID != nil {
@@ -124,6 +124,12 @@ func NewHTTPUpstream(cfg *config.Config) service.HTTPUpstream {
 //   - 调用方必须关闭 resp.Body，否则会导致 inFlight 计数泄漏
 //   - inFlight > 0 的客户端不会被淘汰，确保活跃请求不被中断
 func (s *httpUpstreamService) Do(req *http.Request, proxyURL string, accountID int64, accountConcurrency int) (*http.Response, error) {
+	return traceUpstreamRequest(req, proxyURL, accountID, false, func() (*http.Response, error) {
+		return s.do(req, proxyURL, accountID, accountConcurrency)
+	})
+}
+
+func (s *httpUpstreamService) do(req *http.Request, proxyURL string, accountID int64, accountConcurrency int) (*http.Response, error) {
 	if err := s.validateRequestHost(req); err != nil {
 		return nil, err
 	}
@@ -168,9 +174,15 @@ func (s *httpUpstreamService) Do(req *http.Request, proxyURL string, accountID i
 //   - 指纹模板根据 accountID % len(profiles) 自动选择
 //   - 支持直连、HTTP/HTTPS 代理、SOCKS5 代理三种场景
 func (s *httpUpstreamService) DoWithTLS(req *http.Request, proxyURL string, accountID int64, accountConcurrency int, enableTLSFingerprint bool) (*http.Response, error) {
+	return traceUpstreamRequest(req, proxyURL, accountID, enableTLSFingerprint, func() (*http.Response, error) {
+		return s.doWithTLS(req, proxyURL, accountID, accountConcurrency, enableTLSFingerprint)
+	})
+}
+
+func (s *httpUpstreamService) doWithTLS(req *http.Request, proxyURL string, accountID int64, accountConcurrency int, enableTLSFingerprint bool) (*http.Response, error) {
 	// 如果未启用 TLS 指纹，直接使用标准请求路径
 	if !enableTLSFingerprint {
-		return s.Do(req, proxyURL, accountID, accountConcurrency)
+		return s.do(req, proxyURL, accountID, accountConcurrency)
 	}
 
 	// TLS 指纹已启用，记录调试日志
@@ -194,7 +206,7 @@ func (s *httpUpstreamService) DoWithTLS(req *http.Request, proxyURL string, acco
 	if profile == nil {
 		// 如果获取不到 profile，回退到普通请求
 		slog.Debug("tls_fingerprint_no_profile", "account_id", accountID, "fallback", "standard_request")
-		return s.Do(req, proxyURL, accountID, accountConcurrency)
+		return s.do(req, proxyURL, accountID, accountConcurrency)
 	}
 
 	slog.Debug("tls_fingerprint_using_profile", "account_id", accountID, "profile", profile.Name, "grease", profile.EnableGREASE)
@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/Wei-Shaw/sub2api/internal/pkg/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// traceUpstreamRequest 为一次上游请求创建 upstream.http Span（到收到响应头为止），
+// 流式响应额外创建 upstream.stream_relay Span，在响应体关闭时结束并记录转发字节数。
+//
+// 不向上游注入 traceparent：上游为第三方 API，链路信息仅在本服务内部使用。
+func traceUpstreamRequest(req *http.Request, proxyURL string, accountID int64, tlsFingerprint bool, do func() (*http.Response, error)) (*http.Response, error) {
+	if req == nil || !tracing.Enabled() {
+		return do()
+	}
+
+	attrs := []attribute.KeyValue{
+		attribute.String("http.request.method", req.Method),
+		attribute.Int64("account.id", accountID),
+		attribute.Bool("upstream.via_proxy", strings.TrimSpace(proxyURL) != ""),
+		attribute.Bool("upstream.tls_fingerprint", tlsFingerprint),
+	}
+	if req.URL != nil {
+		attrs = append(attrs,
+			attribute.String("server.address", req.URL.Hostname()),
+			attribute.String("url.path", req.URL.Path),
+		)
+	}
+	_, span := tracing.Start(req.Context(), "upstream.http",
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...),
+	)
+	if !span.IsRecording() {
+		span.End()
+		return do()
+	}
+
+	resp, err := do()
+	if err != nil {
+		tracing.End(span, err)
+		return nil, err
+	}
+
+	span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
+	if resp.StatusCode >= http.StatusBadRequest {
+		span.SetStatus(codes.Error, http.StatusText(resp.StatusCode))
+	}
+	contentType := resp.Header.Get("Content-Type")
+	span.AddEvent("response_headers", trace.WithAttributes(attribute.String("http.response.content_type", contentType)))
+	span.End()
+
+	if resp.Body != nil && strings.Contains(strings.ToLower(contentType), "text/event-stream") {
+		_, relaySpan := tracing.Start(req.Context(), "upstream.stream_relay", trace.WithAttributes(attribute.Int64("account.id", accountID)))
+		resp.Body = &tracedStreamBody{ReadCloser: resp.Body, span: relaySpan}
+	}
+	return resp, nil
+}
+
+// tracedStreamBody 统计流式响应的转发字节数，关闭时结束 stream_relay Span
+type tracedStreamBody struct {
+	io.ReadCloser
+	span  trace.Span
+	bytes atomic.Int64
+	once  sync.Once
+
+	mu      sync.Mutex
+	readErr error
+}
+
+func (b *tracedStreamBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.bytes.Add(int64(n))
+	if err != nil && err != io.EOF {
+		b.mu.Lock()
+		if b.readErr == nil {
+			b.readErr = err
+		}
+		b.mu.Unlock()
+	}
+	return n, err
+}
+
+func (b *tracedStreamBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(func() {
+		b.span.SetAttributes(attribute.Int64("upstream.response_bytes", b.bytes.Load()))
+		b.mu.Lock()
+		readErr := b.readErr
+		b.mu.Unlock()
+		tracing.End(b.span, readErr)
+	})
+	return err
+}
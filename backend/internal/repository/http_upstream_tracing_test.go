@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Wei-Shaw/sub2api/internal/config"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/tracing"
+	"github.com/stretchr/testify/require"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func newTracingTestUpstream(t *testing.T) (*httpUpstreamService, *tracetest.SpanRecorder) {
+	t.Helper()
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	restore := tracing.UseTracerProvider(provider)
+	t.Cleanup(func() {
+		restore()
+		_ = provider.Shutdown(context.Background())
+	})
+	cfg := &config.Config{Security: config.SecurityConfig{URLAllowlist: config.URLAllowlistConfig{AllowPrivateHosts: true}}}
+	svc, ok := NewHTTPUpstream(cfg).(*httpUpstreamService)
+	require.True(t, ok)
+	return svc, recorder
+}
+
+func spanAttrs(span sdktrace.ReadOnlySpan) map[string]any {
+	attrs := map[string]any{}
+	for _, kv := range span.Attributes() {
+		attrs[string(kv.Key)] = kv.Value.AsInterface()
+	}
+	return attrs
+}
+
+func TestHTTPUpstreamTracing_StreamRelaySpan(t *testing.T) {
+	svc, recorder := newTracingTestUpstream(t)
+
+	var gotTraceparent string
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotTraceparent = r.Header.Get("traceparent")
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = io.WriteString(w, "data: hello\n\n")
+	}))
+	defer upstream.Close()
+
+	ctx, parent := tracing.Start(context.Background(), "request")
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, upstream.URL+"/v1/messages?beta=true", nil)
+	require.NoError(t, err)
+
+	resp, err := svc.Do(req, "", 42, 1)
+	require.NoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	parent.End()
+
+	require.Empty(t, gotTraceparent, "trace context must not leak to third-party upstreams")
+
+	spans := map[string]sdktrace.ReadOnlySpan{}
+	for _, span := range recorder.Ended() {
+		spans[span.Name()] = span
+	}
+	httpSpan := spans["upstream.http"]
+	relaySpan := spans["upstream.stream_relay"]
+	require.NotNil(t, httpSpan)
+	require.NotNil(t, relaySpan)
+	require.Equal(t, trace.SpanKindClient, httpSpan.SpanKind())
+	require.Equal(t, parent.SpanContext().SpanID(), httpSpan.Parent().SpanID())
+	require.Equal(t, parent.SpanContext().SpanID(), relaySpan.Parent().SpanID())
+
+	attrs := spanAttrs(httpSpan)
+	require.Equal(t, "/v1/messages", attrs["url.path"])
+	require.Equal(t, int64(42), attrs["account.id"])
+	require.Equal(t, int64(http.StatusOK), attrs["http.response.status_code"])
+	require.Equal(t, int64(len(body)), spanAttrs(relaySpan)["upstream.response_bytes"])
+}
+
+func TestHTTPUpstreamTracing_ErrorStatusWithoutRelay(t *testing.T) {
+	svc, recorder := newTracingTestUpstream(t)
+
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusTooManyRequests)
+		_, _ = io.WriteString(w, `{"error":"rate_limited"}`)
+	}))
+	defer upstream.Close()
+
+	req, err := http.NewRequest(http.MethodPost, upstream.URL+"/v1/chat/completions", nil)
+	require.NoError(t, err)
+	resp, err := svc.DoWithTLS(req, "", 7, 1, false)
+	require.NoError(t, err)
+	_, _ = io.Copy(io.Discard, resp.Body)
+	require.NoError(t, resp.Body.Close())
+
+	ended := recorder.Ended()
+	require.Len(t, ended, 1)
+	require.Equal(t, "upstream.http", ended[0].Name())
+	require.Equal(t, "Error", ended[0].Status().Code.String())
+}
@@ -0,0 +1,157 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+)
+
+type loginHistoryRepository struct {
+	sql sqlExecutor
+}
+
+// NewLoginHistoryRepository 创建登录记录仓储
+func NewLoginHistoryRepository(sqlDB *sql.DB) service.LoginHistoryRepository {
+	return &loginHistoryRepository{sql: sqlDB}
+}
+
+func (r *loginHistoryRepository) Create(ctx context.Context, entry *service.LoginHistoryEntry) error {
+	var userID sql.NullInt64
+	if entry.UserID != nil {
+		userID = sql.NullInt64{Int64: *entry.UserID, Valid: true}
+	}
+	return scanSingleRow(ctx, r.sql, `
+		INSERT INTO login_history (user_id, email, success, failure_reason, method, ip, user_agent, device)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, created_at
+	`, []any{
+		userID, entry.Email, entry.Success, entry.FailureReason, entry.Method, entry.IP, entry.UserAgent, entry.Device,
+	}, &entry.ID, &entry.CreatedAt)
+}
+
+func (r *loginHistoryRepository) List(ctx context.Context, params pagination.PaginationParams, filter service.LoginHistoryFilter) ([]service.LoginHistoryEntry, *pagination.PaginationResult, error) {
+	where, args := buildLoginHistoryWhere(filter)
+
+	var total int64
+	if err := scanSingleRow(ctx, r.sql, "SELECT COUNT(*) FROM login_history "+where, args, &total); err != nil {
+		return nil, nil, err
+	}
+	if total == 0 {
+		return []service.LoginHistoryEntry{}, paginationResultFromTotal(0, params), nil
+	}
+
+	query := fmt.Sprintf(`
+		SELECT id, user_id, email, success, failure_reason, method, ip, user_agent, device, created_at
+		FROM login_history
+		%s
+		ORDER BY id DESC
+		LIMIT $%d OFFSET $%d
+	`, where, len(args)+1, len(args)+2)
+	rows, err := r.sql.QueryContext(ctx, query, append(args, params.Limit(), params.Offset())...)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	entries := make([]service.LoginHistoryEntry, 0)
+	for rows.Next() {
+		var entry service.LoginHistoryEntry
+		var userID sql.NullInt64
+		if err := rows.Scan(
+			&entry.ID,
+			&userID,
+			&entry.Email,
+			&entry.Success,
+			&entry.FailureReason,
+			&entry.Method,
+			&entry.IP,
+			&entry.UserAgent,
+			&entry.Device,
+			&entry.CreatedAt,
+		); err != nil {
+			return nil, nil, err
+		}
+		if userID.Valid {
+			v := userID.Int64
+			entry.UserID = &v
+		}
+		entries = append(entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+	return entries, paginationResultFromTotal(total, params), nil
+}
+
+func buildLoginHistoryWhere(filter service.LoginHistoryFilter) (string, []any) {
+	conditions := make([]string, 0, 6)
+	args := make([]any, 0, 6)
+	add := func(cond string, arg any) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(cond, len(args)))
+	}
+
+	if filter.UserID > 0 {
+		add("user_id = $%d", filter.UserID)
+	}
+	if v := strings.TrimSpace(filter.Email); v != "" {
+		add("LOWER(email) = LOWER($%d)", v)
+	}
+	if v := strings.TrimSpace(filter.IP); v != "" {
+		add("ip = $%d", v)
+	}
+	if filter.Success != nil {
+		add("success = $%d", *filter.Success)
+	}
+	if filter.StartTime != nil {
+		add("created_at >= $%d", *filter.StartTime)
+	}
+	if filter.EndTime != nil {
+		add("created_at < $%d", *filter.EndTime)
+	}
+
+	if len(conditions) == 0 {
+		return "", args
+	}
+	return "WHERE " + strings.Join(conditions, " AND "), args
+}
+
+func (r *loginHistoryRepository) GetLoginOrigins(ctx context.Context, userID int64, ip, device string) (*service.LoginOrigins, error) {
+	var origins service.LoginOrigins
+	err := scanSingleRow(ctx, r.sql, `
+		SELECT
+			EXISTS (SELECT 1 FROM login_history WHERE user_id = $1 AND success),
+			EXISTS (SELECT 1 FROM login_history WHERE user_id = $1 AND success AND ip = $2),
+			EXISTS (SELECT 1 FROM login_history WHERE user_id = $1 AND success AND device = $3)
+	`, []any{userID, ip, device}, &origins.HasHistory, &origins.IPSeen, &origins.DeviceSeen)
+	if err != nil {
+		return nil, err
+	}
+	return &origins, nil
+}
+
+func (r *loginHistoryRepository) DeleteBefore(ctx context.Context, cutoff time.Time, limit int) (int64, error) {
+	if limit <= 0 {
+		limit = 1000
+	}
+	res, err := r.sql.ExecContext(ctx, `
+		WITH victims AS (
+			SELECT id
+			FROM login_history
+			WHERE created_at < $1
+			ORDER BY id ASC
+			LIMIT $2
+		)
+		DELETE FROM login_history
+		WHERE id IN (SELECT id FROM victims)
+	`, cutoff, limit)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
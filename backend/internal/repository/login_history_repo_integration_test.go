@@ -0,0 +1,114 @@
+//go:build integration
+
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoginHistoryRepository_CreateListAndOrigins(t *testing.T) {
+	ctx := context.Background()
+	userRepo := newUserRepositoryWithSQL(testEntClient(t), integrationDB)
+	repo := NewLoginHistoryRepository(integrationDB)
+
+	user := &service.User{
+		Email:        uniqueTestValue(t, "login-history") + "@example.com",
+		PasswordHash: "test-password-hash",
+		Role:         service.RoleUser,
+		Status:       service.StatusActive,
+	}
+	require.NoError(t, userRepo.Create(ctx, user))
+
+	origins, err := repo.GetLoginOrigins(ctx, user.ID, "203.0.113.10", "Chrome on macOS")
+	require.NoError(t, err)
+	require.False(t, origins.HasHistory)
+
+	failed := &service.LoginHistoryEntry{
+		UserID:        &user.ID,
+		Email:         user.Email,
+		FailureReason: "INVALID_CREDENTIALS",
+		Method:        service.LoginMethodPassword,
+		IP:            "198.51.100.20",
+		Device:        "Firefox on Linux",
+	}
+	require.NoError(t, repo.Create(ctx, failed))
+	ok := &service.LoginHistoryEntry{
+		UserID:  &user.ID,
+		Email:   user.Email,
+		Success: true,
+		Method:  service.LoginMethodPassword,
+		IP:      "203.0.113.10",
+		Device:  "Chrome on macOS",
+	}
+	require.NoError(t, repo.Create(ctx, ok))
+	require.NotZero(t, ok.ID)
+	require.False(t, ok.CreatedAt.IsZero())
+
+	// 失败记录不计入已知来源
+	origins, err = repo.GetLoginOrigins(ctx, user.ID, "198.51.100.20", "Firefox on Linux")
+	require.NoError(t, err)
+	require.True(t, origins.HasHistory)
+	require.False(t, origins.IPSeen)
+	require.False(t, origins.DeviceSeen)
+
+	origins, err = repo.GetLoginOrigins(ctx, user.ID, "203.0.113.10", "Chrome on macOS")
+	require.NoError(t, err)
+	require.True(t, origins.IPSeen)
+	require.True(t, origins.DeviceSeen)
+
+	params := pagination.PaginationParams{Page: 1, PageSize: 10}
+	entries, page, err := repo.List(ctx, params, service.LoginHistoryFilter{UserID: user.ID})
+	require.NoError(t, err)
+	require.Equal(t, int64(2), page.Total)
+	require.Equal(t, ok.ID, entries[0].ID)
+	require.NotNil(t, entries[0].UserID)
+	require.Equal(t, user.ID, *entries[0].UserID)
+
+	success := false
+	entries, page, err = repo.List(ctx, params, service.LoginHistoryFilter{Email: user.Email, Success: &success})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), page.Total)
+	require.Equal(t, "INVALID_CREDENTIALS", entries[0].FailureReason)
+
+	// 未知邮箱的失败记录不关联用户
+	unknownEmail := uniqueTestValue(t, "login-history-unknown") + "@example.com"
+	require.NoError(t, repo.Create(ctx, &service.LoginHistoryEntry{Email: unknownEmail, Method: service.LoginMethodPassword, FailureReason: "INVALID_CREDENTIALS"}))
+	entries, _, err = repo.List(ctx, params, service.LoginHistoryFilter{Email: unknownEmail})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.Nil(t, entries[0].UserID)
+}
+
+func TestLoginHistoryRepository_DeleteBefore(t *testing.T) {
+	ctx := context.Background()
+	repo := NewLoginHistoryRepository(integrationDB)
+
+	email := uniqueTestValue(t, "login-history-cleanup") + "@example.com"
+	for range 3 {
+		require.NoError(t, repo.Create(ctx, &service.LoginHistoryEntry{Email: email, Method: service.LoginMethodPassword}))
+	}
+
+	deleted, err := repo.DeleteBefore(ctx, time.Now().Add(-time.Hour), 10)
+	require.NoError(t, err)
+	entries, _, err := repo.List(ctx, pagination.PaginationParams{Page: 1, PageSize: 10}, service.LoginHistoryFilter{Email: email})
+	require.NoError(t, err)
+	require.Len(t, entries, 3, "recent records should be kept (deleted=%d)", deleted)
+
+	// 分批删除直到不足一批
+	for {
+		deleted, err = repo.DeleteBefore(ctx, time.Now().Add(time.Hour), 2)
+		require.NoError(t, err)
+		if deleted < 2 {
+			break
+		}
+	}
+	entries, _, err = repo.List(ctx, pagination.PaginationParams{Page: 1, PageSize: 10}, service.LoginHistoryFilter{Email: email})
+	require.NoError(t, err)
+	require.Empty(t, entries)
+}
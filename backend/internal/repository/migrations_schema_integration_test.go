@@ -73,6 +73,98 @@ func TestMigrationsRunner_IsIdempotent_AndSchemaIsUpToDate(t *testing.T) {
 
 	// user_allowed_groups: created_at should be timestamptz
 	requireColumn(t, tx, "user_allowed_groups", "created_at", "timestamp with time zone", 0, false)
+
+	// balance_ledger: append-only balance ledger (migration 086)
+	requireColumn(t, tx, "balance_ledger", "source_type", "character varying", 32, false)
+	requireColumn(t, tx, "balance_ledger", "reference_id", "character varying", 128, false)
+	requireColumn(t, tx, "balance_ledger", "operator_id", "bigint", 0, true)
+	requireColumn(t, tx, "balance_ledger", "balance_after", "numeric", 0, false)
+	var reconcileRunsRegclass sql.NullString
+	require.NoError(t, tx.QueryRowContext(context.Background(), "SELECT to_regclass('public.balance_ledger_reconcile_runs')").Scan(&reconcileRunsRegclass))
+	require.True(t, reconcileRunsRegclass.Valid, "expected balance_ledger_reconcile_runs table to exist")
+
+	// api_keys: model allow/deny glob patterns (migration 087)
+	requireColumn(t, tx, "api_keys", "model_allowlist", "jsonb", 0, true)
+	requireColumn(t, tx, "api_keys", "model_denylist", "jsonb", 0, true)
+
+	// api_keys: per-key traffic limits (migration 088)
+	requireColumn(t, tx, "api_keys", "rpm_limit", "integer", 0, false)
+	requireColumn(t, tx, "api_keys", "tpm_limit", "integer", 0, false)
+	requireColumn(t, tx, "api_keys", "max_concurrency", "integer", 0, false)
+
+	// organizations: shared wallets, members and invitations (migration 089)
+	requireColumn(t, tx, "organizations", "account_user_id", "bigint", 0, false)
+	requireColumn(t, tx, "organization_members", "role", "character varying", 20, false)
+	requireColumn(t, tx, "organization_members", "monthly_spend_limit", "numeric", 0, false)
+	requireColumn(t, tx, "organization_invitations", "token_hash", "character varying", 64, false)
+	requireColumn(t, tx, "api_keys", "created_by_user_id", "bigint", 0, true)
+	requireColumn(t, tx, "usage_dashboard_daily_api_keys", "actual_cost", "numeric", 0, false)
+
+	// sso_providers / user_sso_identities: generic OIDC/OAuth2 single sign-on (migration 090)
+	requireColumn(t, tx, "sso_providers", "slug", "character varying", 64, false)
+	requireColumn(t, tx, "sso_providers", "attribute_mappings", "jsonb", 0, false)
+	requireColumn(t, tx, "sso_providers", "enforce_sso", "boolean", 0, false)
+	requireColumn(t, tx, "user_sso_identities", "subject", "character varying", 255, false)
+	requireColumn(t, tx, "user_sso_identities", "last_login_at", "timestamp with time zone", 0, true)
+
+	// user_webauthn_credentials / user_recovery_codes: passkeys and 2FA recovery codes (migration 091)
+	requireColumn(t, tx, "user_webauthn_credentials", "credential_id", "bytea", 0, false)
+	requireColumn(t, tx, "user_webauthn_credentials", "sign_count", "bigint", 0, false)
+	requireColumn(t, tx, "user_webauthn_credentials", "last_used_at", "timestamp with time zone", 0, true)
+	requireColumn(t, tx, "user_recovery_codes", "code_hash_encrypted", "text", 0, false)
+	requireColumn(t, tx, "user_recovery_codes", "used_at", "timestamp with time zone", 0, true)
+
+	// login_history: login attempts and their origin (migration 092)
+	requireColumn(t, tx, "login_history", "user_id", "bigint", 0, true)
+	requireColumn(t, tx, "login_history", "success", "boolean", 0, false)
+	requireColumn(t, tx, "login_history", "ip", "character varying", 64, false)
+	requireColumn(t, tx, "login_history", "device", "character varying", 64, false)
+
+	// payment_products / payment_orders: built-in payment orders (migration 093)
+	requireColumn(t, tx, "payment_products", "price_cents", "bigint", 0, false)
+	requireColumn(t, tx, "payment_products", "balance_amount", "numeric", 0, false)
+	requireColumn(t, tx, "payment_orders", "order_no", "character varying", 32, false)
+	requireColumn(t, tx, "payment_orders", "product_id", "bigint", 0, true)
+	requireColumn(t, tx, "payment_orders", "status", "character varying", 20, false)
+	requireColumn(t, tx, "payment_orders", "paid_at", "timestamp with time zone", 0, true)
+
+	// referral_*: referral program and commission wallet (migration 094)
+	requireColumn(t, tx, "referral_codes", "code", "character varying", 16, false)
+	requireColumn(t, tx, "referral_relations", "status", "character varying", 20, false)
+	requireColumn(t, tx, "referral_relations", "commission_until", "timestamp with time zone", 0, true)
+	requireColumn(t, tx, "referral_relations", "settled_until", "timestamp with time zone", 0, false)
+	requireColumn(t, tx, "referral_commissions", "source_ref", "character varying", 64, false)
+	requireColumn(t, tx, "referral_wallets", "balance", "numeric", 0, false)
+	requireColumn(t, tx, "referral_withdrawals", "processed_at", "timestamp with time zone", 0, true)
+
+	// activity_task_*: task activity progress and completions (migration 095)
+	requireColumn(t, tx, "activity_task_progress", "period_key", "character varying", 16, false)
+	requireColumn(t, tx, "activity_task_progress", "value", "numeric", 0, false)
+	requireColumn(t, tx, "activity_task_progress", "last_active_date", "date", 0, true)
+	requireColumn(t, tx, "activity_task_completions", "status", "character varying", 20, false)
+	requireColumn(t, tx, "activity_task_completions", "participation_id", "bigint", 0, true)
+	requireColumn(t, tx, "activity_task_cursors", "last_id", "bigint", 0, false)
+
+	// points_* / coupons / user_coupons: points wallet, points shop and coupons (migration 096)
+	requireColumn(t, tx, "points_accounts", "balance", "bigint", 0, false)
+	requireColumn(t, tx, "points_ledger", "source_type", "character varying", 32, false)
+	requireColumn(t, tx, "points_shop_items", "stock", "integer", 0, false)
+	requireColumn(t, tx, "points_shop_items", "group_id", "bigint", 0, true)
+	requireColumn(t, tx, "points_exchanges", "redeem_code", "character varying", 64, false)
+	requireColumn(t, tx, "coupons", "discount_type", "character varying", 20, false)
+	requireColumn(t, tx, "coupons", "expires_at", "timestamp with time zone", 0, true)
+	requireColumn(t, tx, "user_coupons", "order_no", "character varying", 32, false)
+	requireColumn(t, tx, "payment_orders", "user_coupon_id", "bigint", 0, true)
+	requireColumn(t, tx, "payment_orders", "discount_cents", "bigint", 0, false)
+
+	// promo_codes / promo_code_usages: rule-based promo codes (migration 097)
+	requireColumn(t, tx, "promo_codes", "topup_bonus_percent", "numeric", 0, false)
+	requireColumn(t, tx, "promo_codes", "per_user_limit", "integer", 0, false)
+	requireColumn(t, tx, "promo_codes", "allowed_email_domains", "jsonb", 0, true)
+	requireColumn(t, tx, "promo_codes", "stackable", "boolean", 0, false)
+	requireColumn(t, tx, "promo_codes", "trial_group_id", "bigint", 0, true)
+	requireColumn(t, tx, "promo_code_usages", "topup_bonus_status", "character varying", 20, false)
+	requireColumn(t, tx, "promo_code_usages", "topup_granted_at", "timestamp with time zone", 0, true)
 }
 
 func requireColumn(t *testing.T, tx *sql.Tx, table, column, dataType string, maxLen int, nullable bool) {
@@ -0,0 +1,369 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/Wei-Shaw/sub2api/internal/service"
+)
+
+const opsNotificationChannelColumns = `
+  id,
+  name,
+  channel_type,
+  enabled,
+  webhook_url,
+  secret,
+  telegram_chat_id,
+  min_severity,
+  rule_ids,
+  notify_resolved,
+  send_reports,
+  template,
+  created_at,
+  updated_at`
+
+func (r *opsRepository) ListNotificationChannels(ctx context.Context) ([]*service.OpsNotificationChannel, error) {
+	if r == nil || r.db == nil {
+		return nil, fmt.Errorf("nil ops repository")
+	}
+
+	rows, err := r.db.QueryContext(ctx, "SELECT"+opsNotificationChannelColumns+"\nFROM ops_notification_channels\nORDER BY id ASC")
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	out := []*service.OpsNotificationChannel{}
+	for rows.Next() {
+		ch, err := scanOpsNotificationChannel(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, ch)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (r *opsRepository) GetNotificationChannelByID(ctx context.Context, id int64) (*service.OpsNotificationChannel, error) {
+	if r == nil || r.db == nil {
+		return nil, fmt.Errorf("nil ops repository")
+	}
+	if id <= 0 {
+		return nil, fmt.Errorf("invalid id")
+	}
+
+	row := r.db.QueryRowContext(ctx, "SELECT"+opsNotificationChannelColumns+"\nFROM ops_notification_channels\nWHERE id = $1", id)
+	return scanOpsNotificationChannel(row)
+}
+
+func (r *opsRepository) CreateNotificationChannel(ctx context.Context, input *service.OpsNotificationChannel) (*service.OpsNotificationChannel, error) {
+	if r == nil || r.db == nil {
+		return nil, fmt.Errorf("nil ops repository")
+	}
+	if input == nil {
+		return nil, fmt.Errorf("nil input")
+	}
+
+	ruleIDsArg, err := opsNullJSONInt64s(input.RuleIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	q := `
+INSERT INTO ops_notification_channels (
+  name,
+  channel_type,
+  enabled,
+  webhook_url,
+  secret,
+  telegram_chat_id,
+  min_severity,
+  rule_ids,
+  notify_resolved,
+  send_reports,
+  template,
+  created_at,
+  updated_at
+) VALUES (
+  $1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,NOW(),NOW()
+)
+RETURNING` + opsNotificationChannelColumns
+
+	row := r.db.QueryRowContext(
+		ctx,
+		q,
+		strings.TrimSpace(input.Name),
+		strings.TrimSpace(input.Type),
+		input.Enabled,
+		strings.TrimSpace(input.WebhookURL),
+		strings.TrimSpace(input.Secret),
+		strings.TrimSpace(input.TelegramChatID),
+		strings.TrimSpace(input.MinSeverity),
+		ruleIDsArg,
+		input.NotifyResolved,
+		input.SendReports,
+		input.Template,
+	)
+	return scanOpsNotificationChannel(row)
+}
+
+// UpdateNotificationChannel 更新渠道配置；Secret 为空时保留原有密钥。
+func (r *opsRepository) UpdateNotificationChannel(ctx context.Context, input *service.OpsNotificationChannel) (*service.OpsNotificationChannel, error) {
+	if r == nil || r.db == nil {
+		return nil, fmt.Errorf("nil ops repository")
+	}
+	if input == nil {
+		return nil, fmt.Errorf("nil input")
+	}
+	if input.ID <= 0 {
+		return nil, fmt.Errorf("invalid id")
+	}
+
+	ruleIDsArg, err := opsNullJSONInt64s(input.RuleIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	q := `
+UPDATE ops_notification_channels
+SET
+  name = $2,
+  channel_type = $3,
+  enabled = $4,
+  webhook_url = $5,
+  secret = CASE WHEN $6 = '' THEN secret ELSE $6 END,
+  telegram_chat_id = $7,
+  min_severity = $8,
+  rule_ids = $9,
+  notify_resolved = $10,
+  send_reports = $11,
+  template = $12,
+  updated_at = NOW()
+WHERE id = $1
+RETURNING` + opsNotificationChannelColumns
+
+	row := r.db.QueryRowContext(
+		ctx,
+		q,
+		input.ID,
+		strings.TrimSpace(input.Name),
+		strings.TrimSpace(input.Type),
+		input.Enabled,
+		strings.TrimSpace(input.WebhookURL),
+		strings.TrimSpace(input.Secret),
+		strings.TrimSpace(input.TelegramChatID),
+		strings.TrimSpace(input.MinSeverity),
+		ruleIDsArg,
+		input.NotifyResolved,
+		input.SendReports,
+		input.Template,
+	)
+	return scanOpsNotificationChannel(row)
+}
+
+func (r *opsRepository) DeleteNotificationChannel(ctx context.Context, id int64) error {
+	if r == nil || r.db == nil {
+		return fmt.Errorf("nil ops repository")
+	}
+	if id <= 0 {
+		return fmt.Errorf("invalid id")
+	}
+
+	res, err := r.db.ExecContext(ctx, "DELETE FROM ops_notification_channels WHERE id = $1", id)
+	if err != nil {
+		return err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *opsRepository) InsertNotificationDelivery(ctx context.Context, input *service.OpsNotificationDelivery) error {
+	if r == nil || r.db == nil {
+		return fmt.Errorf("nil ops repository")
+	}
+	if input == nil {
+		return fmt.Errorf("nil input")
+	}
+
+	q := `
+INSERT INTO ops_notification_deliveries (
+  channel_id,
+  channel_type,
+  event_id,
+  kind,
+  status,
+  attempts,
+  http_status,
+  error_message,
+  created_at
+) VALUES (
+  $1,$2,$3,$4,$5,$6,$7,$8,NOW()
+)`
+
+	_, err := r.db.ExecContext(
+		ctx,
+		q,
+		input.ChannelID,
+		strings.TrimSpace(input.ChannelType),
+		opsNullInt64(input.EventID),
+		strings.TrimSpace(input.Kind),
+		strings.TrimSpace(input.Status),
+		input.Attempts,
+		opsNullInt(input.HTTPStatus),
+		opsNullString(input.ErrorMessage),
+	)
+	return err
+}
+
+func (r *opsRepository) ListNotificationDeliveries(ctx context.Context, filter *service.OpsNotificationDeliveryFilter) ([]*service.OpsNotificationDelivery, error) {
+	if r == nil || r.db == nil {
+		return nil, fmt.Errorf("nil ops repository")
+	}
+	if filter == nil {
+		filter = &service.OpsNotificationDeliveryFilter{}
+	}
+
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = 100
+	}
+	if limit > 500 {
+		limit = 500
+	}
+
+	conditions := []string{}
+	args := []any{}
+	if filter.ChannelID != nil && *filter.ChannelID > 0 {
+		args = append(args, *filter.ChannelID)
+		conditions = append(conditions, fmt.Sprintf("channel_id = $%d", len(args)))
+	}
+	if filter.EventID != nil && *filter.EventID > 0 {
+		args = append(args, *filter.EventID)
+		conditions = append(conditions, fmt.Sprintf("event_id = $%d", len(args)))
+	}
+	if status := strings.TrimSpace(filter.Status); status != "" {
+		args = append(args, status)
+		conditions = append(conditions, fmt.Sprintf("status = $%d", len(args)))
+	}
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+	args = append(args, limit)
+
+	q := fmt.Sprintf(`
+SELECT
+  id,
+  channel_id,
+  channel_type,
+  event_id,
+  kind,
+  status,
+  attempts,
+  http_status,
+  COALESCE(error_message, ''),
+  created_at
+FROM ops_notification_deliveries
+%s
+ORDER BY created_at DESC, id DESC
+LIMIT $%d`, where, len(args))
+
+	rows, err := r.db.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	out := []*service.OpsNotificationDelivery{}
+	for rows.Next() {
+		var d service.OpsNotificationDelivery
+		var eventID sql.NullInt64
+		var httpStatus sql.NullInt64
+		if err := rows.Scan(
+			&d.ID,
+			&d.ChannelID,
+			&d.ChannelType,
+			&eventID,
+			&d.Kind,
+			&d.Status,
+			&d.Attempts,
+			&httpStatus,
+			&d.ErrorMessage,
+			&d.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		if eventID.Valid {
+			v := eventID.Int64
+			d.EventID = &v
+		}
+		if httpStatus.Valid {
+			v := int(httpStatus.Int64)
+			d.HTTPStatus = &v
+		}
+		out = append(out, &d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+type opsRowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanOpsNotificationChannel(row opsRowScanner) (*service.OpsNotificationChannel, error) {
+	var ch service.OpsNotificationChannel
+	var ruleIDsRaw []byte
+	if err := row.Scan(
+		&ch.ID,
+		&ch.Name,
+		&ch.Type,
+		&ch.Enabled,
+		&ch.WebhookURL,
+		&ch.Secret,
+		&ch.TelegramChatID,
+		&ch.MinSeverity,
+		&ruleIDsRaw,
+		&ch.NotifyResolved,
+		&ch.SendReports,
+		&ch.Template,
+		&ch.CreatedAt,
+		&ch.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	ch.SecretConfigured = ch.Secret != ""
+	if len(ruleIDsRaw) > 0 && string(ruleIDsRaw) != "null" {
+		var decoded []int64
+		if err := json.Unmarshal(ruleIDsRaw, &decoded); err == nil {
+			ch.RuleIDs = decoded
+		}
+	}
+	return &ch, nil
+}
+
+func opsNullJSONInt64s(v []int64) (any, error) {
+	if len(v) == 0 {
+		return sql.NullString{}, nil
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return sql.NullString{String: string(b), Valid: true}, nil
+}
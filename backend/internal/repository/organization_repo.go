@@ -0,0 +1,471 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	dbent "github.com/Wei-Shaw/sub2api/ent"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/timezone"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+)
+
+type organizationRepository struct {
+	sql sqlExecutor
+}
+
+// NewOrganizationRepository 创建组织仓储
+func NewOrganizationRepository(sqlDB *sql.DB) service.OrganizationRepository {
+	return &organizationRepository{sql: sqlDB}
+}
+
+// q 返回当前上下文的执行器：在事务上下文中与用户创建、成员变更同事务提交
+func (r *organizationRepository) q(ctx context.Context) sqlExecutor {
+	if tx := dbent.TxFromContext(ctx); tx != nil {
+		return tx.Client()
+	}
+	return r.sql
+}
+
+func (r *organizationRepository) Create(ctx context.Context, org *service.Organization) error {
+	return scanSingleRow(ctx, r.q(ctx), `
+		INSERT INTO organizations (name, account_user_id, created_by)
+		VALUES ($1, $2, $3)
+		RETURNING id, created_at, updated_at
+	`, []any{org.Name, org.AccountUserID, org.CreatedBy}, &org.ID, &org.CreatedAt, &org.UpdatedAt)
+}
+
+func (r *organizationRepository) GetByID(ctx context.Context, id int64) (*service.Organization, error) {
+	var org service.Organization
+	err := scanSingleRow(ctx, r.q(ctx), `
+		SELECT id, name, account_user_id, created_by, created_at, updated_at
+		FROM organizations
+		WHERE id = $1
+	`, []any{id}, &org.ID, &org.Name, &org.AccountUserID, &org.CreatedBy, &org.CreatedAt, &org.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, service.ErrOrganizationNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &org, nil
+}
+
+func (r *organizationRepository) UpdateName(ctx context.Context, id int64, name string) error {
+	result, err := r.q(ctx).ExecContext(ctx, `UPDATE organizations SET name = $2, updated_at = NOW() WHERE id = $1`, id, name)
+	if err != nil {
+		return err
+	}
+	return requireAffected(result, service.ErrOrganizationNotFound)
+}
+
+func (r *organizationRepository) ListByMember(ctx context.Context, userID int64) ([]service.OrganizationMembership, error) {
+	rows, err := r.q(ctx).QueryContext(ctx, `
+		SELECT o.id, o.name, o.account_user_id, o.created_by, o.created_at, o.updated_at, m.role
+		FROM organization_members m
+		JOIN organizations o ON o.id = m.organization_id
+		WHERE m.user_id = $1
+		ORDER BY o.id
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	out := make([]service.OrganizationMembership, 0)
+	for rows.Next() {
+		var m service.OrganizationMembership
+		if err := rows.Scan(
+			&m.Organization.ID,
+			&m.Organization.Name,
+			&m.Organization.AccountUserID,
+			&m.Organization.CreatedBy,
+			&m.Organization.CreatedAt,
+			&m.Organization.UpdatedAt,
+			&m.Role,
+		); err != nil {
+			return nil, err
+		}
+		out = append(out, m)
+	}
+	return out, rows.Err()
+}
+
+func (r *organizationRepository) AddMember(ctx context.Context, member *service.OrganizationMember) error {
+	err := scanSingleRow(ctx, r.q(ctx), `
+		INSERT INTO organization_members (organization_id, user_id, role, monthly_spend_limit)
+		VALUES ($1, $2, $3, $4)
+		RETURNING created_at, updated_at
+	`, []any{member.OrganizationID, member.UserID, member.Role, member.MonthlySpendLimit}, &member.CreatedAt, &member.UpdatedAt)
+	if isUniqueConstraintViolation(err) {
+		return service.ErrOrganizationMemberExists
+	}
+	return err
+}
+
+const organizationMemberColumns = `
+	m.organization_id, m.user_id, m.role, m.monthly_spend_limit, m.created_at, m.updated_at,
+	COALESCE(u.email, ''), COALESCE(u.username, '')
+`
+
+func scanOrganizationMember(scan func(dest ...any) error) (*service.OrganizationMember, error) {
+	var m service.OrganizationMember
+	if err := scan(
+		&m.OrganizationID,
+		&m.UserID,
+		&m.Role,
+		&m.MonthlySpendLimit,
+		&m.CreatedAt,
+		&m.UpdatedAt,
+		&m.Email,
+		&m.Username,
+	); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+func (r *organizationRepository) GetMember(ctx context.Context, orgID, userID int64) (*service.OrganizationMember, error) {
+	rows, err := r.q(ctx).QueryContext(ctx, `
+		SELECT `+organizationMemberColumns+`
+		FROM organization_members m
+		LEFT JOIN users u ON u.id = m.user_id
+		WHERE m.organization_id = $1 AND m.user_id = $2
+	`, orgID, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+		return nil, service.ErrOrganizationMemberNotFound
+	}
+	return scanOrganizationMember(rows.Scan)
+}
+
+func (r *organizationRepository) ListMembers(ctx context.Context, orgID int64) ([]service.OrganizationMember, error) {
+	rows, err := r.q(ctx).QueryContext(ctx, `
+		SELECT `+organizationMemberColumns+`
+		FROM organization_members m
+		LEFT JOIN users u ON u.id = m.user_id
+		WHERE m.organization_id = $1
+		ORDER BY m.created_at, m.user_id
+	`, orgID)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	out := make([]service.OrganizationMember, 0)
+	for rows.Next() {
+		m, err := scanOrganizationMember(rows.Scan)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *m)
+	}
+	return out, rows.Err()
+}
+
+func (r *organizationRepository) UpdateMemberRole(ctx context.Context, orgID, userID int64, role string) error {
+	result, err := r.q(ctx).ExecContext(ctx, `
+		UPDATE organization_members SET role = $3, updated_at = NOW()
+		WHERE organization_id = $1 AND user_id = $2
+	`, orgID, userID, role)
+	if err != nil {
+		return err
+	}
+	return requireAffected(result, service.ErrOrganizationMemberNotFound)
+}
+
+func (r *organizationRepository) UpdateMemberSpendLimit(ctx context.Context, orgID, userID int64, limit money.Amount) error {
+	result, err := r.q(ctx).ExecContext(ctx, `
+		UPDATE organization_members SET monthly_spend_limit = $3, updated_at = NOW()
+		WHERE organization_id = $1 AND user_id = $2
+	`, orgID, userID, limit)
+	if err != nil {
+		return err
+	}
+	return requireAffected(result, service.ErrOrganizationMemberNotFound)
+}
+
+func (r *organizationRepository) RemoveMember(ctx context.Context, orgID, userID int64) error {
+	result, err := r.q(ctx).ExecContext(ctx, `DELETE FROM organization_members WHERE organization_id = $1 AND user_id = $2`, orgID, userID)
+	if err != nil {
+		return err
+	}
+	return requireAffected(result, service.ErrOrganizationMemberNotFound)
+}
+
+func (r *organizationRepository) DisableMemberAPIKeys(ctx context.Context, accountUserID, memberID int64) ([]string, error) {
+	rows, err := r.q(ctx).QueryContext(ctx, `
+		UPDATE api_keys SET status = $3, updated_at = NOW()
+		WHERE user_id = $1 AND created_by_user_id = $2 AND deleted_at IS NULL AND status <> $3
+		RETURNING key
+	`, accountUserID, memberID, service.StatusAPIKeyDisabled)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	keys := make([]string, 0)
+	for rows.Next() {
+		var key string
+		if err := rows.Scan(&key); err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, rows.Err()
+}
+
+func (r *organizationRepository) CreateInvitation(ctx context.Context, inv *service.OrganizationInvitation) error {
+	err := scanSingleRow(ctx, r.q(ctx), `
+		INSERT INTO organization_invitations (organization_id, email, role, token_hash, invited_by, status, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, created_at
+	`, []any{inv.OrganizationID, inv.Email, inv.Role, inv.TokenHash, inv.InvitedBy, inv.Status, inv.ExpiresAt}, &inv.ID, &inv.CreatedAt)
+	if isUniqueConstraintViolation(err) {
+		return service.ErrOrganizationInvitationExists
+	}
+	return err
+}
+
+const organizationInvitationColumns = `
+	id, organization_id, email, role, token_hash, invited_by, status, expires_at, accepted_by, accepted_at, created_at
+`
+
+func scanOrganizationInvitation(scan func(dest ...any) error) (*service.OrganizationInvitation, error) {
+	var (
+		inv        service.OrganizationInvitation
+		acceptedBy sql.NullInt64
+		acceptedAt sql.NullTime
+	)
+	if err := scan(
+		&inv.ID,
+		&inv.OrganizationID,
+		&inv.Email,
+		&inv.Role,
+		&inv.TokenHash,
+		&inv.InvitedBy,
+		&inv.Status,
+		&inv.ExpiresAt,
+		&acceptedBy,
+		&acceptedAt,
+		&inv.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+	if acceptedBy.Valid {
+		v := acceptedBy.Int64
+		inv.AcceptedBy = &v
+	}
+	if acceptedAt.Valid {
+		v := acceptedAt.Time
+		inv.AcceptedAt = &v
+	}
+	return &inv, nil
+}
+
+func (r *organizationRepository) GetInvitationByTokenHash(ctx context.Context, tokenHash string) (*service.OrganizationInvitation, error) {
+	rows, err := r.q(ctx).QueryContext(ctx, `
+		SELECT `+organizationInvitationColumns+`
+		FROM organization_invitations
+		WHERE token_hash = $1
+	`, tokenHash)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+		return nil, service.ErrOrganizationInvitationInvalid
+	}
+	return scanOrganizationInvitation(rows.Scan)
+}
+
+func (r *organizationRepository) ListPendingInvitations(ctx context.Context, orgID int64) ([]service.OrganizationInvitation, error) {
+	rows, err := r.q(ctx).QueryContext(ctx, `
+		SELECT `+organizationInvitationColumns+`
+		FROM organization_invitations
+		WHERE organization_id = $1 AND status = $2
+		ORDER BY created_at DESC, id DESC
+	`, orgID, service.OrgInvitationStatusPending)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	out := make([]service.OrganizationInvitation, 0)
+	for rows.Next() {
+		inv, err := scanOrganizationInvitation(rows.Scan)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *inv)
+	}
+	return out, rows.Err()
+}
+
+func (r *organizationRepository) RevokeInvitation(ctx context.Context, orgID, invitationID int64) error {
+	result, err := r.q(ctx).ExecContext(ctx, `
+		UPDATE organization_invitations SET status = $3
+		WHERE organization_id = $1 AND id = $2 AND status = $4
+	`, orgID, invitationID, service.OrgInvitationStatusRevoked, service.OrgInvitationStatusPending)
+	if err != nil {
+		return err
+	}
+	return requireAffected(result, service.ErrOrganizationInvitationMissing)
+}
+
+func (r *organizationRepository) MarkInvitationAccepted(ctx context.Context, invitationID, userID int64, acceptedAt time.Time) (bool, error) {
+	result, err := r.q(ctx).ExecContext(ctx, `
+		UPDATE organization_invitations SET status = $2, accepted_by = $3, accepted_at = $4
+		WHERE id = $1 AND status = $5
+	`, invitationID, service.OrgInvitationStatusAccepted, userID, acceptedAt, service.OrgInvitationStatusPending)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+// GetMemberSpendStatus 成员已离开组织时返回 Active=false。
+// 消费按 usage_logs 实时汇总（包含已删除的 Key），结果由计费缓存按月缓存。
+func (r *organizationRepository) GetMemberSpendStatus(ctx context.Context, accountUserID, memberID int64, since time.Time) (*service.OrganizationMemberSpendStatus, error) {
+	var status service.OrganizationMemberSpendStatus
+	err := scanSingleRow(ctx, r.q(ctx), `
+		SELECT
+			(u.status = $4 AND u.deleted_at IS NULL) AS active,
+			m.monthly_spend_limit,
+			COALESCE((
+				SELECT SUM(ul.actual_cost)
+				FROM usage_logs ul
+				WHERE ul.user_id = $1
+					AND ul.created_at >= $3
+					AND ul.api_key_id IN (SELECT k.id FROM api_keys k WHERE k.user_id = $1 AND k.created_by_user_id = $2)
+			), 0) AS spent
+		FROM organizations o
+		JOIN organization_members m ON m.organization_id = o.id AND m.user_id = $2
+		JOIN users u ON u.id = m.user_id
+		WHERE o.account_user_id = $1
+	`, []any{accountUserID, memberID, since, service.StatusActive}, &status.Active, &status.Limit, &status.Spent)
+	if errors.Is(err, sql.ErrNoRows) {
+		return &service.OrganizationMemberSpendStatus{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+func (r *organizationRepository) ListMemberUsage(ctx context.Context, orgID int64, start, end time.Time) ([]service.OrganizationMemberUsage, error) {
+	rows, err := r.q(ctx).QueryContext(ctx, `
+		SELECT
+			m.user_id,
+			COALESCE(u.email, ''),
+			COALESCE(u.username, ''),
+			m.role,
+			COUNT(DISTINCT k.id) FILTER (WHERE k.deleted_at IS NULL) AS api_key_count,
+			COALESCE(SUM(d.total_requests), 0),
+			COALESCE(SUM(d.input_tokens), 0),
+			COALESCE(SUM(d.output_tokens), 0),
+			COALESCE(SUM(d.cache_creation_tokens), 0),
+			COALESCE(SUM(d.cache_read_tokens), 0),
+			COALESCE(SUM(d.total_cost), 0),
+			COALESCE(SUM(d.actual_cost), 0) AS actual_cost
+		FROM organization_members m
+		JOIN organizations o ON o.id = m.organization_id
+		LEFT JOIN users u ON u.id = m.user_id
+		LEFT JOIN api_keys k ON k.user_id = o.account_user_id AND k.created_by_user_id = m.user_id
+		LEFT JOIN usage_dashboard_daily_api_keys d
+			ON d.api_key_id = k.id AND d.bucket_date >= $2::date AND d.bucket_date < $3::date
+		WHERE m.organization_id = $1
+		GROUP BY m.user_id, u.email, u.username, m.role
+		ORDER BY actual_cost DESC, m.user_id
+	`, orgID, dashboardBucketDate(start), dashboardBucketDate(end))
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	out := make([]service.OrganizationMemberUsage, 0)
+	for rows.Next() {
+		var u service.OrganizationMemberUsage
+		if err := rows.Scan(
+			&u.UserID,
+			&u.Email,
+			&u.Username,
+			&u.Role,
+			&u.APIKeyCount,
+			&u.TotalRequests,
+			&u.InputTokens,
+			&u.OutputTokens,
+			&u.CacheCreationTokens,
+			&u.CacheReadTokens,
+			&u.TotalCost,
+			&u.ActualCost,
+		); err != nil {
+			return nil, err
+		}
+		out = append(out, u)
+	}
+	return out, rows.Err()
+}
+
+func (r *organizationRepository) ListDailyUsage(ctx context.Context, accountUserID int64, start, end time.Time) ([]service.OrganizationDailyUsage, error) {
+	rows, err := r.q(ctx).QueryContext(ctx, `
+		SELECT
+			TO_CHAR(bucket_date, 'YYYY-MM-DD'),
+			COALESCE(SUM(total_requests), 0),
+			COALESCE(SUM(input_tokens + output_tokens + cache_creation_tokens + cache_read_tokens), 0),
+			COALESCE(SUM(total_cost), 0),
+			COALESCE(SUM(actual_cost), 0)
+		FROM usage_dashboard_daily_api_keys
+		WHERE user_id = $1 AND bucket_date >= $2::date AND bucket_date < $3::date
+		GROUP BY bucket_date
+		ORDER BY bucket_date
+	`, accountUserID, dashboardBucketDate(start), dashboardBucketDate(end))
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	out := make([]service.OrganizationDailyUsage, 0)
+	for rows.Next() {
+		var d service.OrganizationDailyUsage
+		if err := rows.Scan(&d.Date, &d.TotalRequests, &d.TotalTokens, &d.TotalCost, &d.ActualCost); err != nil {
+			return nil, err
+		}
+		out = append(out, d)
+	}
+	return out, rows.Err()
+}
+
+// dashboardBucketDate 将时间转换为预聚合表使用的 bucket_date（应用时区的日期）
+func dashboardBucketDate(t time.Time) string {
+	return t.In(timezone.Location()).Format("2006-01-02")
+}
+
+// requireAffected 在未影响任何行时返回 notFound
+func requireAffected(result sql.Result, notFound error) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return notFound
+	}
+	return nil
+}
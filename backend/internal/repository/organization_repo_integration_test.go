@@ -0,0 +1,187 @@
+//go:build integration
+
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	dbent "github.com/Wei-Shaw/sub2api/ent"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+)
+
+type OrganizationRepoSuite struct {
+	suite.Suite
+	ctx      context.Context
+	client   *dbent.Client
+	repo     *organizationRepository
+	usageLog *usageLogRepository
+}
+
+func (s *OrganizationRepoSuite) SetupTest() {
+	s.ctx = context.Background()
+	tx := testEntTx(s.T())
+	s.client = tx.Client()
+	s.repo = &organizationRepository{sql: tx}
+	s.usageLog = newUsageLogRepositoryWithSQL(s.client, tx)
+}
+
+func TestOrganizationRepoSuite(t *testing.T) {
+	suite.Run(t, new(OrganizationRepoSuite))
+}
+
+func (s *OrganizationRepoSuite) createOrg(ownerEmail string) (*service.Organization, *service.User, *service.User) {
+	owner := mustCreateUser(s.T(), s.client, &service.User{Email: ownerEmail})
+	account := mustCreateUser(s.T(), s.client, &service.User{
+		Email: "org-" + uuid.NewString()[:8] + service.OrganizationSyntheticEmailDomain,
+		Role:  service.RoleOrganization,
+	})
+	org := &service.Organization{Name: "Acme", AccountUserID: account.ID, CreatedBy: owner.ID}
+	s.Require().NoError(s.repo.Create(s.ctx, org))
+	s.Require().NoError(s.repo.AddMember(s.ctx, &service.OrganizationMember{
+		OrganizationID: org.ID,
+		UserID:         owner.ID,
+		Role:           service.OrgRoleOwner,
+	}))
+	return org, owner, account
+}
+
+func (s *OrganizationRepoSuite) TestMembersLifecycle() {
+	org, owner, _ := s.createOrg("org-owner@test.com")
+	member := mustCreateUser(s.T(), s.client, &service.User{Email: "org-member@test.com", Username: "bob"})
+	s.Require().NoError(s.repo.AddMember(s.ctx, &service.OrganizationMember{
+		OrganizationID: org.ID,
+		UserID:         member.ID,
+		Role:           service.OrgRoleMember,
+	}))
+
+	memberships, err := s.repo.ListByMember(s.ctx, member.ID)
+	s.Require().NoError(err)
+	s.Require().Len(memberships, 1)
+	s.Require().Equal(org.ID, memberships[0].Organization.ID)
+	s.Require().Equal(service.OrgRoleMember, memberships[0].Role)
+
+	s.Require().NoError(s.repo.UpdateMemberRole(s.ctx, org.ID, member.ID, service.OrgRoleBilling))
+	s.Require().NoError(s.repo.UpdateMemberSpendLimit(s.ctx, org.ID, member.ID, money.MustParse("12.5")))
+	got, err := s.repo.GetMember(s.ctx, org.ID, member.ID)
+	s.Require().NoError(err)
+	s.Require().Equal(service.OrgRoleBilling, got.Role)
+	s.Require().Equal(money.MustParse("12.5"), got.MonthlySpendLimit)
+
+	members, err := s.repo.ListMembers(s.ctx, org.ID)
+	s.Require().NoError(err)
+	s.Require().Len(members, 2)
+	s.Require().Equal(owner.ID, members[0].UserID, "owner should be listed first")
+	s.Require().Equal("org-member@test.com", members[1].Email)
+	s.Require().Equal("bob", members[1].Username)
+
+	s.Require().NoError(s.repo.RemoveMember(s.ctx, org.ID, member.ID))
+	_, err = s.repo.GetMember(s.ctx, org.ID, member.ID)
+	s.Require().ErrorIs(err, service.ErrOrganizationMemberNotFound)
+	s.Require().ErrorIs(s.repo.RemoveMember(s.ctx, org.ID, member.ID), service.ErrOrganizationMemberNotFound)
+}
+
+func (s *OrganizationRepoSuite) TestDisableMemberAPIKeys_OnlyTouchesMemberKeys() {
+	org, owner, account := s.createOrg("org-keys-owner@test.com")
+	member := mustCreateUser(s.T(), s.client, &service.User{Email: "org-keys-member@test.com"})
+
+	memberKey := mustCreateApiKey(s.T(), s.client, &service.APIKey{UserID: account.ID, Key: "sk-org-member", CreatedByUserID: &member.ID})
+	ownerKey := mustCreateApiKey(s.T(), s.client, &service.APIKey{UserID: account.ID, Key: "sk-org-owner", CreatedByUserID: &owner.ID})
+
+	keys, err := s.repo.DisableMemberAPIKeys(s.ctx, org.AccountUserID, member.ID)
+	s.Require().NoError(err)
+	s.Require().Equal([]string{memberKey.Key}, keys)
+
+	gotMember, err := s.client.APIKey.Get(s.ctx, memberKey.ID)
+	s.Require().NoError(err)
+	s.Require().Equal(service.StatusAPIKeyDisabled, gotMember.Status)
+	gotOwner, err := s.client.APIKey.Get(s.ctx, ownerKey.ID)
+	s.Require().NoError(err)
+	s.Require().Equal(service.StatusActive, gotOwner.Status)
+
+	// 已停用的 Key 不再重复返回
+	keys, err = s.repo.DisableMemberAPIKeys(s.ctx, org.AccountUserID, member.ID)
+	s.Require().NoError(err)
+	s.Require().Empty(keys)
+}
+
+func (s *OrganizationRepoSuite) TestInvitations() {
+	org, owner, _ := s.createOrg("org-inv-owner@test.com")
+	invitee := mustCreateUser(s.T(), s.client, &service.User{Email: "org-invitee@test.com"})
+
+	inv := &service.OrganizationInvitation{
+		OrganizationID: org.ID,
+		Email:          "Org-Invitee@test.com",
+		Role:           service.OrgRoleMember,
+		TokenHash:      "hash-" + uuid.NewString(),
+		InvitedBy:      owner.ID,
+		Status:         service.OrgInvitationStatusPending,
+		ExpiresAt:      time.Now().Add(time.Hour),
+	}
+	s.Require().NoError(s.repo.CreateInvitation(s.ctx, inv))
+	s.Require().NotZero(inv.ID)
+
+	got, err := s.repo.GetInvitationByTokenHash(s.ctx, inv.TokenHash)
+	s.Require().NoError(err)
+	s.Require().Equal(inv.ID, got.ID)
+
+	pending, err := s.repo.ListPendingInvitations(s.ctx, org.ID)
+	s.Require().NoError(err)
+	s.Require().Len(pending, 1)
+
+	accepted, err := s.repo.MarkInvitationAccepted(s.ctx, inv.ID, invitee.ID, time.Now())
+	s.Require().NoError(err)
+	s.Require().True(accepted)
+	accepted, err = s.repo.MarkInvitationAccepted(s.ctx, inv.ID, invitee.ID, time.Now())
+	s.Require().NoError(err)
+	s.Require().False(accepted, "an invitation can only be accepted once")
+
+	pending, err = s.repo.ListPendingInvitations(s.ctx, org.ID)
+	s.Require().NoError(err)
+	s.Require().Empty(pending)
+	s.Require().ErrorIs(s.repo.RevokeInvitation(s.ctx, org.ID, inv.ID), service.ErrOrganizationInvitationMissing)
+}
+
+func (s *OrganizationRepoSuite) TestGetMemberSpendStatus() {
+	org, _, account := s.createOrg("org-spend-owner@test.com")
+	member := mustCreateUser(s.T(), s.client, &service.User{Email: "org-spend-member@test.com"})
+	s.Require().NoError(s.repo.AddMember(s.ctx, &service.OrganizationMember{
+		OrganizationID:    org.ID,
+		UserID:            member.ID,
+		Role:              service.OrgRoleMember,
+		MonthlySpendLimit: 5 * money.USD,
+	}))
+	key := mustCreateApiKey(s.T(), s.client, &service.APIKey{UserID: account.ID, Key: "sk-org-spend", CreatedByUserID: &member.ID})
+	upstream := mustCreateAccount(s.T(), s.client, &service.Account{Name: "acc-org-spend"})
+
+	now := time.Now()
+	for _, at := range []time.Time{now.Add(-time.Hour), now.Add(-40 * 24 * time.Hour)} {
+		_, err := s.usageLog.Create(s.ctx, &service.UsageLog{
+			UserID:     account.ID,
+			APIKeyID:   key.ID,
+			AccountID:  upstream.ID,
+			RequestID:  uuid.NewString(),
+			Model:      "claude-3",
+			TotalCost:  2 * money.USD,
+			ActualCost: 2 * money.USD,
+			CreatedAt:  at,
+		})
+		s.Require().NoError(err)
+	}
+
+	status, err := s.repo.GetMemberSpendStatus(s.ctx, account.ID, member.ID, now.Add(-24*time.Hour))
+	s.Require().NoError(err)
+	s.Require().True(status.Active)
+	s.Require().Equal(5*money.USD, status.Limit)
+	s.Require().Equal(2*money.USD, status.Spent)
+
+	// 已离开的成员
+	s.Require().NoError(s.repo.RemoveMember(s.ctx, org.ID, member.ID))
+	status, err = s.repo.GetMemberSpendStatus(s.ctx, account.ID, member.ID, now.Add(-24*time.Hour))
+	s.Require().NoError(err)
+	s.Require().False(status.Active)
+}
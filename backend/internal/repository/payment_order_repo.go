@@ -0,0 +1,291 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/timezone"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+)
+
+type paymentOrderRepository struct {
+	sql sqlExecutor
+}
+
+// NewPaymentOrderRepository 创建支付订单仓储
+func NewPaymentOrderRepository(sqlDB *sql.DB) service.PaymentOrderRepository {
+	return &paymentOrderRepository{sql: sqlDB}
+}
+
+const paymentOrderColumns = `
+	o.id, o.order_no, o.user_id, o.product_id, o.product_name, o.kind, o.balance_amount, o.group_id, o.validity_days,
+	o.amount_cents, o.original_cents, o.discount_cents, o.user_coupon_id, o.currency, o.provider, o.method, o.provider_trade_no, o.status, o.redeem_code_id, o.fulfill_error,
+	o.refunded_cents, o.refund_reason, o.client_ip, o.expires_at, o.paid_at, o.fulfilled_at, o.refunded_at, o.closed_at,
+	o.created_at, o.updated_at, COALESCE(u.email, '')`
+
+const paymentOrderFrom = ` FROM payment_orders o LEFT JOIN users u ON u.id = o.user_id `
+
+func (r *paymentOrderRepository) Create(ctx context.Context, o *service.PaymentOrder) error {
+	return scanSingleRow(ctx, r.sql, `
+		INSERT INTO payment_orders (
+			order_no, user_id, product_id, product_name, kind, balance_amount, group_id, validity_days,
+			amount_cents, original_cents, discount_cents, user_coupon_id, currency, provider, method, status, client_ip, expires_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18)
+		RETURNING id, created_at, updated_at
+	`, []any{
+		o.OrderNo, o.UserID, nullInt64(o.ProductID), o.ProductName, o.Kind, o.BalanceAmount, nullInt64(o.GroupID), o.ValidityDays,
+		o.AmountCents, o.OriginalCents, o.DiscountCents, nullInt64(o.UserCouponID), o.Currency, o.Provider, o.Method, o.Status, o.ClientIP, o.ExpiresAt,
+	}, &o.ID, &o.CreatedAt, &o.UpdatedAt)
+}
+
+func (r *paymentOrderRepository) GetByID(ctx context.Context, id int64) (*service.PaymentOrder, error) {
+	return r.getOne(ctx, `o.id = $1`, id)
+}
+
+func (r *paymentOrderRepository) GetByOrderNo(ctx context.Context, orderNo string) (*service.PaymentOrder, error) {
+	return r.getOne(ctx, `o.order_no = $1`, orderNo)
+}
+
+func (r *paymentOrderRepository) getOne(ctx context.Context, where string, arg any) (*service.PaymentOrder, error) {
+	orders, err := r.query(ctx, `SELECT `+paymentOrderColumns+paymentOrderFrom+`WHERE `+where, arg)
+	if err != nil {
+		return nil, err
+	}
+	if len(orders) == 0 {
+		return nil, service.ErrPaymentOrderNotFound
+	}
+	return &orders[0], nil
+}
+
+func (r *paymentOrderRepository) List(ctx context.Context, params pagination.PaginationParams, filter service.PaymentOrderFilter) ([]service.PaymentOrder, *pagination.PaginationResult, error) {
+	where, args := buildPaymentOrderWhere(filter)
+
+	var total int64
+	if err := scanSingleRow(ctx, r.sql, "SELECT COUNT(*) FROM payment_orders o "+where, args, &total); err != nil {
+		return nil, nil, err
+	}
+	if total == 0 {
+		return []service.PaymentOrder{}, paginationResultFromTotal(0, params), nil
+	}
+
+	query := fmt.Sprintf(`SELECT %s %s %s ORDER BY o.id DESC LIMIT $%d OFFSET $%d`,
+		paymentOrderColumns, paymentOrderFrom, where, len(args)+1, len(args)+2)
+	orders, err := r.query(ctx, query, append(args, params.Limit(), params.Offset())...)
+	if err != nil {
+		return nil, nil, err
+	}
+	return orders, paginationResultFromTotal(total, params), nil
+}
+
+func buildPaymentOrderWhere(filter service.PaymentOrderFilter) (string, []any) {
+	conditions := make([]string, 0, 6)
+	args := make([]any, 0, 6)
+	add := func(cond string, arg any) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(cond, len(args)))
+	}
+
+	if filter.UserID > 0 {
+		add("o.user_id = $%d", filter.UserID)
+	}
+	if v := strings.TrimSpace(filter.Status); v != "" {
+		add("o.status = $%d", v)
+	}
+	if v := strings.TrimSpace(filter.Provider); v != "" {
+		add("o.provider = $%d", v)
+	}
+	if v := strings.TrimSpace(filter.Search); v != "" {
+		args = append(args, v)
+		conditions = append(conditions, fmt.Sprintf("(o.order_no = $%d OR o.provider_trade_no = $%d)", len(args), len(args)))
+	}
+	if filter.StartTime != nil {
+		add("o.created_at >= $%d", *filter.StartTime)
+	}
+	if filter.EndTime != nil {
+		add("o.created_at < $%d", *filter.EndTime)
+	}
+
+	if len(conditions) == 0 {
+		return "", args
+	}
+	return "WHERE " + strings.Join(conditions, " AND "), args
+}
+
+func (r *paymentOrderRepository) CountPendingByUser(ctx context.Context, userID int64) (int64, error) {
+	var count int64
+	err := scanSingleRow(ctx, r.sql, `
+		SELECT COUNT(*) FROM payment_orders WHERE user_id = $1 AND status = $2 AND expires_at > NOW()
+	`, []any{userID, service.PaymentOrderStatusPending}, &count)
+	return count, err
+}
+
+func (r *paymentOrderRepository) SetProviderTradeNo(ctx context.Context, id int64, providerTradeNo string) error {
+	_, err := r.sql.ExecContext(ctx, `UPDATE payment_orders SET provider_trade_no = $2, updated_at = NOW() WHERE id = $1`, id, providerTradeNo)
+	return err
+}
+
+func (r *paymentOrderRepository) MarkPaid(ctx context.Context, id int64, providerTradeNo string, paidAt time.Time) (bool, error) {
+	return r.transition(ctx, `
+		UPDATE payment_orders
+		SET status = $2,
+			provider_trade_no = CASE WHEN $3 <> '' THEN $3 ELSE provider_trade_no END,
+			paid_at = $4,
+			updated_at = NOW()
+		WHERE id = $1 AND status IN ($5, $6)
+	`, id, service.PaymentOrderStatusPaid, providerTradeNo, paidAt, service.PaymentOrderStatusPending, service.PaymentOrderStatusClosed)
+}
+
+func (r *paymentOrderRepository) MarkFulfilled(ctx context.Context, id int64, redeemCodeID int64) (bool, error) {
+	return r.transition(ctx, `
+		UPDATE payment_orders
+		SET status = $2, redeem_code_id = $3, fulfill_error = '', fulfilled_at = NOW(), updated_at = NOW()
+		WHERE id = $1 AND status = $4
+	`, id, service.PaymentOrderStatusFulfilled, redeemCodeID, service.PaymentOrderStatusPaid)
+}
+
+func (r *paymentOrderRepository) MarkClosed(ctx context.Context, id int64) (bool, error) {
+	return r.transition(ctx, `
+		UPDATE payment_orders
+		SET status = $2, closed_at = NOW(), updated_at = NOW()
+		WHERE id = $1 AND status = $3
+	`, id, service.PaymentOrderStatusClosed, service.PaymentOrderStatusPending)
+}
+
+func (r *paymentOrderRepository) MarkRefunded(ctx context.Context, id int64, refundedCents int64, reason string) (bool, error) {
+	return r.transition(ctx, `
+		UPDATE payment_orders
+		SET status = $2, refunded_cents = $3, refund_reason = $4, refunded_at = NOW(), updated_at = NOW()
+		WHERE id = $1 AND status IN ($5, $6)
+	`, id, service.PaymentOrderStatusRefunded, refundedCents, reason, service.PaymentOrderStatusPaid, service.PaymentOrderStatusFulfilled)
+}
+
+func (r *paymentOrderRepository) transition(ctx context.Context, query string, args ...any) (bool, error) {
+	result, err := r.sql.ExecContext(ctx, query, args...)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+func (r *paymentOrderRepository) SetFulfillError(ctx context.Context, id int64, message string) error {
+	_, err := r.sql.ExecContext(ctx, `UPDATE payment_orders SET fulfill_error = $2, updated_at = NOW() WHERE id = $1`, id, message)
+	return err
+}
+
+func (r *paymentOrderRepository) ListExpiredPending(ctx context.Context, now time.Time, limit int) ([]service.PaymentOrder, error) {
+	return r.query(ctx, `SELECT `+paymentOrderColumns+paymentOrderFrom+`
+		WHERE o.status = $1 AND o.expires_at < $2
+		ORDER BY o.expires_at ASC
+		LIMIT $3
+	`, service.PaymentOrderStatusPending, now, limit)
+}
+
+func (r *paymentOrderRepository) ListUnfulfilledPaid(ctx context.Context, paidBefore time.Time, limit int) ([]service.PaymentOrder, error) {
+	return r.query(ctx, `SELECT `+paymentOrderColumns+paymentOrderFrom+`
+		WHERE o.status = $1 AND o.paid_at < $2
+		ORDER BY o.paid_at ASC
+		LIMIT $3
+	`, service.PaymentOrderStatusPaid, paidBefore, limit)
+}
+
+// Reconcile 按支付日期汇总；退款计入原订单的支付日期，便于与渠道账单逐笔核对
+func (r *paymentOrderRepository) Reconcile(ctx context.Context, filter service.PaymentReconciliationFilter) ([]service.PaymentReconciliationRow, error) {
+	tz := strings.TrimSpace(filter.Timezone)
+	if _, err := time.LoadLocation(tz); tz == "" || err != nil {
+		tz = timezone.Name()
+	}
+	args := []any{filter.StartTime, filter.EndTime, tz, service.PaymentOrderStatusPaid, service.PaymentOrderStatusRefunded}
+	providerCond := ""
+	if v := strings.TrimSpace(filter.Provider); v != "" {
+		args = append(args, v)
+		providerCond = fmt.Sprintf(" AND provider = $%d", len(args))
+	}
+
+	rows, err := r.sql.QueryContext(ctx, `
+		SELECT
+			to_char((paid_at AT TIME ZONE $3)::date, 'YYYY-MM-DD') AS paid_date,
+			provider,
+			currency,
+			COUNT(*),
+			COALESCE(SUM(amount_cents), 0),
+			COUNT(*) FILTER (WHERE fulfilled_at IS NOT NULL),
+			COUNT(*) FILTER (WHERE status = $4),
+			COUNT(*) FILTER (WHERE status = $5),
+			COALESCE(SUM(refunded_cents), 0)
+		FROM payment_orders
+		WHERE paid_at IS NOT NULL AND paid_at >= $1 AND paid_at < $2`+providerCond+`
+		GROUP BY 1, 2, 3
+		ORDER BY 1 DESC, 2 ASC, 3 ASC
+	`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	out := make([]service.PaymentReconciliationRow, 0)
+	for rows.Next() {
+		var row service.PaymentReconciliationRow
+		if err := rows.Scan(
+			&row.Date, &row.Provider, &row.Currency, &row.PaidOrders, &row.PaidCents,
+			&row.FulfilledCount, &row.PendingFulfill, &row.RefundedOrders, &row.RefundedCents,
+		); err != nil {
+			return nil, err
+		}
+		row.NetCents = row.PaidCents - row.RefundedCents
+		out = append(out, row)
+	}
+	return out, rows.Err()
+}
+
+func (r *paymentOrderRepository) query(ctx context.Context, query string, args ...any) ([]service.PaymentOrder, error) {
+	rows, err := r.sql.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	orders := make([]service.PaymentOrder, 0)
+	for rows.Next() {
+		var o service.PaymentOrder
+		var productID, groupID, userCouponID, redeemCodeID sql.NullInt64
+		var paidAt, fulfilledAt, refundedAt, closedAt sql.NullTime
+		if err := rows.Scan(
+			&o.ID, &o.OrderNo, &o.UserID, &productID, &o.ProductName, &o.Kind, &o.BalanceAmount, &groupID, &o.ValidityDays,
+			&o.AmountCents, &o.OriginalCents, &o.DiscountCents, &userCouponID, &o.Currency, &o.Provider, &o.Method, &o.ProviderTradeNo, &o.Status, &redeemCodeID, &o.FulfillError,
+			&o.RefundedCents, &o.RefundReason, &o.ClientIP, &o.ExpiresAt, &paidAt, &fulfilledAt, &refundedAt, &closedAt,
+			&o.CreatedAt, &o.UpdatedAt, &o.UserEmail,
+		); err != nil {
+			return nil, err
+		}
+		o.ProductID = nullInt64Value(productID)
+		o.GroupID = nullInt64Value(groupID)
+		o.UserCouponID = nullInt64Value(userCouponID)
+		o.RedeemCodeID = nullInt64Value(redeemCodeID)
+		o.PaidAt = nullTimePtr(paidAt)
+		o.FulfilledAt = nullTimePtr(fulfilledAt)
+		o.RefundedAt = nullTimePtr(refundedAt)
+		o.ClosedAt = nullTimePtr(closedAt)
+		orders = append(orders, o)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return orders, nil
+}
+
+func nullInt64Value(v sql.NullInt64) *int64 {
+	if !v.Valid {
+		return nil
+	}
+	out := v.Int64
+	return &out
+}
@@ -0,0 +1,180 @@
+//go:build integration
+
+package repository
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPaymentProductRepository_CRUD(t *testing.T) {
+	ctx := context.Background()
+	repo := NewPaymentProductRepository(integrationDB)
+
+	product := &service.PaymentProduct{
+		Name:          uniqueTestValue(t, "pack"),
+		Kind:          service.PaymentProductKindBalance,
+		PriceCents:    990,
+		Currency:      "CNY",
+		BalanceAmount: money.FromFloat(1.5),
+		Enabled:       true,
+	}
+	require.NoError(t, repo.Create(ctx, product))
+	require.NotZero(t, product.ID)
+
+	product.Enabled = false
+	product.PriceCents = 1990
+	require.NoError(t, repo.Update(ctx, product))
+
+	got, err := repo.GetByID(ctx, product.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(1990), got.PriceCents)
+	require.Equal(t, money.FromFloat(1.5), got.BalanceAmount)
+	require.Nil(t, got.GroupID)
+
+	enabled, err := repo.List(ctx, true)
+	require.NoError(t, err)
+	for _, p := range enabled {
+		require.NotEqual(t, product.ID, p.ID)
+	}
+
+	require.NoError(t, repo.Delete(ctx, product.ID))
+	_, err = repo.GetByID(ctx, product.ID)
+	require.ErrorIs(t, err, service.ErrPaymentProductNotFound)
+	require.ErrorIs(t, repo.Delete(ctx, product.ID), service.ErrPaymentProductNotFound)
+}
+
+func TestPaymentOrderRepository_LifecycleAndReconcile(t *testing.T) {
+	ctx := context.Background()
+	userRepo := newUserRepositoryWithSQL(testEntClient(t), integrationDB)
+	repo := NewPaymentOrderRepository(integrationDB)
+
+	user := &service.User{
+		Email:        uniqueTestValue(t, "payment") + "@example.com",
+		PasswordHash: "test-password-hash",
+		Role:         service.RoleUser,
+		Status:       service.StatusActive,
+	}
+	require.NoError(t, userRepo.Create(ctx, user))
+
+	// 使用唯一渠道名隔离对账统计
+	provider := fmt.Sprintf("t%d", time.Now().UnixNano()%1_000_000_000_000)
+	newOrder := func(suffix string, expiresAt time.Time) *service.PaymentOrder {
+		o := &service.PaymentOrder{
+			OrderNo:       provider + suffix,
+			UserID:        user.ID,
+			ProductName:   "Top-up",
+			Kind:          service.PaymentProductKindBalance,
+			BalanceAmount: 10 * money.USD,
+			AmountCents:   7000,
+			Currency:      "CNY",
+			Provider:      provider,
+			Status:        service.PaymentOrderStatusPending,
+			ExpiresAt:     expiresAt,
+		}
+		require.NoError(t, repo.Create(ctx, o))
+		require.NotZero(t, o.ID)
+		return o
+	}
+
+	now := time.Now()
+	paid := newOrder("a", now.Add(30*time.Minute))
+	expired := newOrder("b", now.Add(-time.Minute))
+
+	count, err := repo.CountPendingByUser(ctx, user.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), count)
+
+	expiredList, err := repo.ListExpiredPending(ctx, now, 1000)
+	require.NoError(t, err)
+	require.Contains(t, paymentOrderIDs(expiredList), expired.ID)
+	require.NotContains(t, paymentOrderIDs(expiredList), paid.ID)
+
+	// pending -> paid -> fulfilled -> refunded；重复迁移返回 false
+	paidAt := now.Add(-2 * time.Minute)
+	ok, err := repo.MarkPaid(ctx, paid.ID, "TRADE-1", paidAt)
+	require.NoError(t, err)
+	require.True(t, ok)
+	ok, err = repo.MarkPaid(ctx, paid.ID, "TRADE-2", paidAt)
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	unfulfilled, err := repo.ListUnfulfilledPaid(ctx, now, 1000)
+	require.NoError(t, err)
+	require.Contains(t, paymentOrderIDs(unfulfilled), paid.ID)
+
+	require.NoError(t, repo.SetFulfillError(ctx, paid.ID, "boom"))
+	ok, err = repo.MarkFulfilled(ctx, paid.ID, 42)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	got, err := repo.GetByOrderNo(ctx, paid.OrderNo)
+	require.NoError(t, err)
+	require.Equal(t, service.PaymentOrderStatusFulfilled, got.Status)
+	require.Equal(t, "TRADE-1", got.ProviderTradeNo)
+	require.Empty(t, got.FulfillError)
+	require.NotNil(t, got.RedeemCodeID)
+	require.Equal(t, int64(42), *got.RedeemCodeID)
+	require.Equal(t, user.Email, got.UserEmail)
+	require.Equal(t, 10*money.USD, got.BalanceAmount)
+
+	ok, err = repo.MarkRefunded(ctx, paid.ID, 2000, "partial")
+	require.NoError(t, err)
+	require.True(t, ok)
+	ok, err = repo.MarkRefunded(ctx, paid.ID, 2000, "again")
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	// 关闭后仍可标记为已支付（迟到的支付通知）
+	ok, err = repo.MarkClosed(ctx, expired.ID)
+	require.NoError(t, err)
+	require.True(t, ok)
+	ok, err = repo.MarkClosed(ctx, expired.ID)
+	require.NoError(t, err)
+	require.False(t, ok)
+	ok, err = repo.MarkPaid(ctx, expired.ID, "TRADE-3", paidAt)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	orders, page, err := repo.List(ctx, pagination.PaginationParams{Page: 1, PageSize: 10}, service.PaymentOrderFilter{UserID: user.ID, Status: service.PaymentOrderStatusPaid})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), page.Total)
+	require.Equal(t, expired.ID, orders[0].ID)
+
+	orders, _, err = repo.List(ctx, pagination.PaginationParams{Page: 1, PageSize: 10}, service.PaymentOrderFilter{Search: "TRADE-1"})
+	require.NoError(t, err)
+	require.Len(t, orders, 1)
+	require.Equal(t, paid.ID, orders[0].ID)
+
+	rows, err := repo.Reconcile(ctx, service.PaymentReconciliationFilter{
+		StartTime: now.Add(-time.Hour),
+		EndTime:   now.Add(time.Hour),
+		Provider:  provider,
+		Timezone:  "UTC",
+	})
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	require.Equal(t, paidAt.UTC().Format("2006-01-02"), rows[0].Date)
+	require.Equal(t, int64(2), rows[0].PaidOrders)
+	require.Equal(t, int64(14000), rows[0].PaidCents)
+	require.Equal(t, int64(1), rows[0].FulfilledCount)
+	require.Equal(t, int64(1), rows[0].PendingFulfill)
+	require.Equal(t, int64(1), rows[0].RefundedOrders)
+	require.Equal(t, int64(2000), rows[0].RefundedCents)
+	require.Equal(t, int64(12000), rows[0].NetCents)
+}
+
+func paymentOrderIDs(orders []service.PaymentOrder) []int64 {
+	ids := make([]int64, 0, len(orders))
+	for _, o := range orders {
+		ids = append(ids, o.ID)
+	}
+	return ids
+}
@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/Wei-Shaw/sub2api/internal/service"
+)
+
+type paymentProductRepository struct {
+	sql sqlExecutor
+}
+
+// NewPaymentProductRepository 创建支付商品仓储
+func NewPaymentProductRepository(sqlDB *sql.DB) service.PaymentProductRepository {
+	return &paymentProductRepository{sql: sqlDB}
+}
+
+const paymentProductColumns = `id, name, description, kind, price_cents, currency, balance_amount, group_id, validity_days, enabled, sort_order, created_at, updated_at`
+
+func (r *paymentProductRepository) Create(ctx context.Context, p *service.PaymentProduct) error {
+	return scanSingleRow(ctx, r.sql, `
+		INSERT INTO payment_products (name, description, kind, price_cents, currency, balance_amount, group_id, validity_days, enabled, sort_order)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING id, created_at, updated_at
+	`, []any{
+		p.Name, p.Description, p.Kind, p.PriceCents, p.Currency, p.BalanceAmount, nullInt64(p.GroupID), p.ValidityDays, p.Enabled, p.SortOrder,
+	}, &p.ID, &p.CreatedAt, &p.UpdatedAt)
+}
+
+func (r *paymentProductRepository) Update(ctx context.Context, p *service.PaymentProduct) error {
+	err := scanSingleRow(ctx, r.sql, `
+		UPDATE payment_products
+		SET name = $2, description = $3, kind = $4, price_cents = $5, currency = $6, balance_amount = $7,
+			group_id = $8, validity_days = $9, enabled = $10, sort_order = $11, updated_at = NOW()
+		WHERE id = $1
+		RETURNING created_at, updated_at
+	`, []any{
+		p.ID, p.Name, p.Description, p.Kind, p.PriceCents, p.Currency, p.BalanceAmount, nullInt64(p.GroupID), p.ValidityDays, p.Enabled, p.SortOrder,
+	}, &p.CreatedAt, &p.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return service.ErrPaymentProductNotFound
+	}
+	return err
+}
+
+func (r *paymentProductRepository) Delete(ctx context.Context, id int64) error {
+	result, err := r.sql.ExecContext(ctx, `DELETE FROM payment_products WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return service.ErrPaymentProductNotFound
+	}
+	return nil
+}
+
+func (r *paymentProductRepository) GetByID(ctx context.Context, id int64) (*service.PaymentProduct, error) {
+	rows, err := r.sql.QueryContext(ctx, `SELECT `+paymentProductColumns+` FROM payment_products WHERE id = $1`, id)
+	if err != nil {
+		return nil, err
+	}
+	products, err := scanPaymentProducts(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(products) == 0 {
+		return nil, service.ErrPaymentProductNotFound
+	}
+	return &products[0], nil
+}
+
+func (r *paymentProductRepository) List(ctx context.Context, enabledOnly bool) ([]service.PaymentProduct, error) {
+	query := `SELECT ` + paymentProductColumns + ` FROM payment_products`
+	if enabledOnly {
+		query += ` WHERE enabled`
+	}
+	rows, err := r.sql.QueryContext(ctx, query+` ORDER BY sort_order ASC, id ASC`)
+	if err != nil {
+		return nil, err
+	}
+	return scanPaymentProducts(rows)
+}
+
+func scanPaymentProducts(rows *sql.Rows) ([]service.PaymentProduct, error) {
+	defer func() { _ = rows.Close() }()
+
+	products := make([]service.PaymentProduct, 0)
+	for rows.Next() {
+		var p service.PaymentProduct
+		var groupID sql.NullInt64
+		if err := rows.Scan(
+			&p.ID, &p.Name, &p.Description, &p.Kind, &p.PriceCents, &p.Currency, &p.BalanceAmount,
+			&groupID, &p.ValidityDays, &p.Enabled, &p.SortOrder, &p.CreatedAt, &p.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		p.GroupID = nullInt64Value(groupID)
+		products = append(products, p)
+	}
+	return products, rows.Err()
+}
@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/config"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/httpclient"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+)
+
+const (
+	paymentProviderRequestTimeout = 15 * time.Second
+	paymentProviderMaxBodyBytes   = 1 << 20
+	// paymentNotifyTolerance 带时间戳的回调（微信支付、Stripe）允许的时钟偏差
+	paymentNotifyTolerance = 5 * time.Minute
+)
+
+// cnyOnly 国内渠道仅支持人民币
+func cnyOnly(currency string) bool {
+	return strings.EqualFold(currency, "CNY")
+}
+
+// NewPaymentProviders 按配置创建已启用的支付渠道；密钥无效的渠道记录错误后跳过，不影响其他渠道。
+func NewPaymentProviders(cfg *config.Config) service.PaymentProviders {
+	if cfg == nil || !cfg.Payment.Enabled {
+		return nil
+	}
+	client := newPaymentHTTPClient(cfg)
+	pay := cfg.Payment
+
+	var providers service.PaymentProviders
+	if pay.EPay.Enabled {
+		providers = append(providers, newEPayProvider(pay.EPay, client))
+	}
+	if pay.Alipay.Enabled {
+		p, err := newAlipayProvider(pay.Alipay, client)
+		if err != nil {
+			slog.Error("payment provider disabled: invalid alipay config", "error", err)
+		} else {
+			providers = append(providers, p)
+		}
+	}
+	if pay.Wechat.Enabled {
+		p, err := newWechatPayProvider(pay.Wechat, client)
+		if err != nil {
+			slog.Error("payment provider disabled: invalid wechat config", "error", err)
+		} else {
+			providers = append(providers, p)
+		}
+	}
+	if pay.Stripe.Enabled {
+		providers = append(providers, newStripeProvider(pay.Stripe, client))
+	}
+	return providers
+}
+
+func newPaymentHTTPClient(cfg *config.Config) *http.Client {
+	client, err := httpclient.GetClient(httpclient.Options{
+		Timeout:            paymentProviderRequestTimeout,
+		ValidateResolvedIP: true,
+		AllowPrivateHosts:  cfg.Security.URLAllowlist.AllowPrivateHosts,
+	})
+	if err != nil {
+		return &http.Client{Timeout: paymentProviderRequestTimeout}
+	}
+	return client
+}
+
+// doPaymentRequest 发送请求并读取响应；非 2xx 状态不视为错误，由调用方按渠道协议解析错误体
+func doPaymentRequest(client *http.Client, req *http.Request) (*http.Response, []byte, error) {
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, nil, fmt.Errorf("send request: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, paymentProviderMaxBodyBytes))
+	if err != nil {
+		return nil, nil, fmt.Errorf("read response: %w", err)
+	}
+	return resp, body, nil
+}
+
+func truncatePaymentErrorBody(body []byte) string {
+	const maxLen = 512
+	s := strings.TrimSpace(string(body))
+	if len(s) > maxLen {
+		s = s[:maxLen]
+	}
+	return s
+}
+
+func textAck(success bool) *service.PaymentNotifyAck {
+	body := "fail"
+	if success {
+		body = "success"
+	}
+	return &service.PaymentNotifyAck{StatusCode: http.StatusOK, ContentType: "text/plain; charset=utf-8", Body: []byte(body)}
+}
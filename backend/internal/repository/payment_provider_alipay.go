@@ -0,0 +1,235 @@
+package repository
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/config"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/payment"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+	"github.com/tidwall/gjson"
+)
+
+const (
+	alipayMethodPage = "page" // 电脑网站支付
+	alipayMethodWap  = "wap"  // 手机网站支付
+
+	alipayTimeLayout = "2006-01-02 15:04:05"
+)
+
+// alipayZone 支付宝开放平台的时间参数均为北京时间
+var alipayZone = time.FixedZone("CST", 8*3600)
+
+// alipayProvider 支付宝开放平台（RSA2）：网站支付跳转，异步通知验签，主动查单与退款
+type alipayProvider struct {
+	appID      string
+	gatewayURL string
+	privateKey *rsa.PrivateKey
+	publicKey  *rsa.PublicKey // 支付宝公钥
+	client     *http.Client
+}
+
+func newAlipayProvider(cfg config.AlipayConfig, client *http.Client) (*alipayProvider, error) {
+	priv, err := payment.ParseRSAPrivateKey(cfg.PrivateKey)
+	if err != nil {
+		return nil, fmt.Errorf("alipay private_key: %w", err)
+	}
+	pub, err := payment.ParseRSAPublicKey(cfg.PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("alipay public_key: %w", err)
+	}
+	return &alipayProvider{
+		appID:      strings.TrimSpace(cfg.AppID),
+		gatewayURL: strings.TrimSpace(cfg.GatewayURL),
+		privateKey: priv,
+		publicKey:  pub,
+		client:     client,
+	}, nil
+}
+
+func (p *alipayProvider) Name() string                          { return service.PaymentProviderAlipay }
+func (p *alipayProvider) Methods() []string                     { return []string{alipayMethodPage, alipayMethodWap} }
+func (p *alipayProvider) SupportsCurrency(currency string) bool { return cnyOnly(currency) }
+func (p *alipayProvider) Ack(success bool) *service.PaymentNotifyAck {
+	return textAck(success)
+}
+
+func (p *alipayProvider) CreatePayment(_ context.Context, req *service.PaymentCreateRequest) (*service.PaymentCreateResult, error) {
+	apiMethod, productCode := "alipay.trade.page.pay", "FAST_INSTANT_TRADE_PAY"
+	if req.Method == alipayMethodWap {
+		apiMethod, productCode = "alipay.trade.wap.pay", "QUICK_WAP_WAY"
+	}
+	biz := map[string]any{
+		"out_trade_no": req.OrderNo,
+		"total_amount": payment.FormatYuan(req.AmountCents),
+		"subject":      req.Subject,
+		"product_code": productCode,
+	}
+	if !req.ExpiresAt.IsZero() {
+		biz["time_expire"] = req.ExpiresAt.In(alipayZone).Format(alipayTimeLayout)
+	}
+	params, err := p.commonParams(apiMethod, biz)
+	if err != nil {
+		return nil, err
+	}
+	params.Set("notify_url", req.NotifyURL)
+	if req.ReturnURL != "" {
+		params.Set("return_url", req.ReturnURL)
+	}
+	if err := p.sign(params); err != nil {
+		return nil, err
+	}
+	return &service.PaymentCreateResult{PayURL: p.gatewayURL + "?" + params.Encode()}, nil
+}
+
+// ParseNotification 支付宝以 POST 表单回调
+func (p *alipayProvider) ParseNotification(_ context.Context, req *service.PaymentNotifyRequest) (*service.PaymentNotification, error) {
+	params, err := url.ParseQuery(string(req.Body))
+	if err != nil {
+		return nil, fmt.Errorf("parse alipay notify form: %w", err)
+	}
+	if err := payment.AlipayVerify(params, p.publicKey); err != nil {
+		return nil, err
+	}
+	if params.Get("app_id") != p.appID {
+		return nil, fmt.Errorf("alipay notify app_id mismatch: %q", params.Get("app_id"))
+	}
+	amount, err := payment.ParseYuan(params.Get("total_amount"))
+	if err != nil {
+		return nil, fmt.Errorf("parse alipay total_amount: %w", err)
+	}
+	return &service.PaymentNotification{
+		OrderNo:         params.Get("out_trade_no"),
+		ProviderTradeNo: params.Get("trade_no"),
+		Paid:            alipayTradePaid(params.Get("trade_status")),
+		AmountCents:     amount,
+		Currency:        "CNY",
+	}, nil
+}
+
+func (p *alipayProvider) QueryPayment(ctx context.Context, order *service.PaymentOrder) (*service.PaymentNotification, error) {
+	result, err := p.call(ctx, "alipay.trade.query", map[string]any{"out_trade_no": order.OrderNo})
+	if err != nil {
+		return nil, err
+	}
+	n := &service.PaymentNotification{OrderNo: order.OrderNo, Currency: "CNY"}
+	if result.Get("code").String() != "10000" {
+		// 用户未扫码时交易尚未创建
+		if result.Get("sub_code").String() == "ACQ.TRADE_NOT_EXIST" {
+			return n, nil
+		}
+		return nil, alipayBusinessError(result)
+	}
+	n.ProviderTradeNo = result.Get("trade_no").String()
+	n.Paid = alipayTradePaid(result.Get("trade_status").String())
+	if amount := result.Get("total_amount").String(); amount != "" {
+		if n.AmountCents, err = payment.ParseYuan(amount); err != nil {
+			return nil, fmt.Errorf("parse alipay total_amount: %w", err)
+		}
+	}
+	return n, nil
+}
+
+func (p *alipayProvider) Refund(ctx context.Context, req *service.PaymentRefundRequest) error {
+	biz := map[string]any{
+		"out_trade_no":   req.OrderNo,
+		"refund_amount":  payment.FormatYuan(req.AmountCents),
+		"out_request_no": req.RefundNo,
+	}
+	if req.Reason != "" {
+		biz["refund_reason"] = req.Reason
+	}
+	result, err := p.call(ctx, "alipay.trade.refund", biz)
+	if err != nil {
+		return err
+	}
+	if result.Get("code").String() != "10000" {
+		return alipayBusinessError(result)
+	}
+	return nil
+}
+
+func alipayTradePaid(status string) bool {
+	return status == "TRADE_SUCCESS" || status == "TRADE_FINISHED"
+}
+
+func alipayBusinessError(result gjson.Result) error {
+	return fmt.Errorf("alipay error %s/%s: %s", result.Get("code").String(), result.Get("sub_code").String(), result.Get("sub_msg").String())
+}
+
+func (p *alipayProvider) commonParams(apiMethod string, biz map[string]any) (url.Values, error) {
+	bizContent, err := json.Marshal(biz)
+	if err != nil {
+		return nil, err
+	}
+	return url.Values{
+		"app_id":      {p.appID},
+		"method":      {apiMethod},
+		"format":      {"JSON"},
+		"charset":     {"utf-8"},
+		"sign_type":   {"RSA2"},
+		"timestamp":   {time.Now().In(alipayZone).Format(alipayTimeLayout)},
+		"version":     {"1.0"},
+		"biz_content": {string(bizContent)},
+	}, nil
+}
+
+func (p *alipayProvider) sign(params url.Values) error {
+	sig, err := payment.AlipaySign(params, p.privateKey)
+	if err != nil {
+		return err
+	}
+	params.Set("sign", sig)
+	return nil
+}
+
+// call 调用开放平台接口并校验应答签名，返回 {method}_response 节点
+func (p *alipayProvider) call(ctx context.Context, apiMethod string, biz map[string]any) (gjson.Result, error) {
+	params, err := p.commonParams(apiMethod, biz)
+	if err != nil {
+		return gjson.Result{}, err
+	}
+	if err := p.sign(params); err != nil {
+		return gjson.Result{}, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.gatewayURL, strings.NewReader(params.Encode()))
+	if err != nil {
+		return gjson.Result{}, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded;charset=utf-8")
+
+	resp, body, err := doPaymentRequest(p.client, req)
+	if err != nil {
+		return gjson.Result{}, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return gjson.Result{}, fmt.Errorf("status %d: %s", resp.StatusCode, truncatePaymentErrorBody(body))
+	}
+	if !gjson.ValidBytes(body) {
+		return gjson.Result{}, errors.New("alipay returned invalid json")
+	}
+	root := gjson.ParseBytes(body)
+	result := root.Get(strings.ReplaceAll(apiMethod, ".", "_") + "_response")
+	if !result.Exists() {
+		return gjson.Result{}, fmt.Errorf("alipay response missing: %s", truncatePaymentErrorBody(body))
+	}
+	// 网关级错误（如签名错误）不带 sign；未签名的应答只能作为失败结果使用
+	sig := root.Get("sign").String()
+	if sig == "" {
+		if result.Get("code").String() == "10000" {
+			return gjson.Result{}, errors.New("alipay response is not signed")
+		}
+		return result, nil
+	}
+	if err := payment.RSAVerifySHA256(p.publicKey, result.Raw, sig); err != nil {
+		return gjson.Result{}, fmt.Errorf("verify alipay response: %w", err)
+	}
+	return result, nil
+}
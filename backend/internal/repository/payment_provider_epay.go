@@ -0,0 +1,161 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/Wei-Shaw/sub2api/internal/config"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/payment"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+	"github.com/tidwall/gjson"
+)
+
+// epayProvider 易支付（彩虹易支付等兼容接口）：页面跳转 submit.php，MD5 签名回调，api.php 查单与退款
+type epayProvider struct {
+	gatewayURL string
+	pid        string
+	key        string
+	methods    []string
+	client     *http.Client
+}
+
+func newEPayProvider(cfg config.EPayConfig, client *http.Client) *epayProvider {
+	methods := make([]string, 0, len(cfg.Methods))
+	for _, m := range cfg.Methods {
+		if m = strings.TrimSpace(m); m != "" {
+			methods = append(methods, m)
+		}
+	}
+	return &epayProvider{
+		gatewayURL: strings.TrimRight(strings.TrimSpace(cfg.GatewayURL), "/"),
+		pid:        strings.TrimSpace(cfg.PID),
+		key:        cfg.Key,
+		methods:    methods,
+		client:     client,
+	}
+}
+
+func (p *epayProvider) Name() string                          { return service.PaymentProviderEPay }
+func (p *epayProvider) Methods() []string                     { return p.methods }
+func (p *epayProvider) SupportsCurrency(currency string) bool { return cnyOnly(currency) }
+func (p *epayProvider) Ack(success bool) *service.PaymentNotifyAck {
+	return textAck(success)
+}
+
+func (p *epayProvider) CreatePayment(_ context.Context, req *service.PaymentCreateRequest) (*service.PaymentCreateResult, error) {
+	params := url.Values{
+		"pid":          {p.pid},
+		"type":         {req.Method},
+		"out_trade_no": {req.OrderNo},
+		"notify_url":   {req.NotifyURL},
+		"name":         {req.Subject},
+		"money":        {payment.FormatYuan(req.AmountCents)},
+		"sign_type":    {"MD5"},
+	}
+	if req.ReturnURL != "" {
+		params.Set("return_url", req.ReturnURL)
+	}
+	params.Set("sign", payment.EPaySign(params, p.key))
+	return &service.PaymentCreateResult{PayURL: p.gatewayURL + "/submit.php?" + params.Encode()}, nil
+}
+
+// ParseNotification 易支付以 GET 查询串回调，部分实现使用 POST 表单
+func (p *epayProvider) ParseNotification(_ context.Context, req *service.PaymentNotifyRequest) (*service.PaymentNotification, error) {
+	params := req.Query
+	if req.Method == http.MethodPost && len(req.Body) > 0 {
+		form, err := url.ParseQuery(string(req.Body))
+		if err != nil {
+			return nil, fmt.Errorf("parse epay notify form: %w", err)
+		}
+		params = form
+	}
+	if err := payment.EPayVerify(params, p.key); err != nil {
+		return nil, err
+	}
+	if params.Get("pid") != p.pid {
+		return nil, fmt.Errorf("epay notify pid mismatch: %q", params.Get("pid"))
+	}
+	amount, err := payment.ParseYuan(params.Get("money"))
+	if err != nil {
+		return nil, fmt.Errorf("parse epay money: %w", err)
+	}
+	return &service.PaymentNotification{
+		OrderNo:         params.Get("out_trade_no"),
+		ProviderTradeNo: params.Get("trade_no"),
+		Paid:            params.Get("trade_status") == "TRADE_SUCCESS",
+		AmountCents:     amount,
+		Currency:        "CNY",
+	}, nil
+}
+
+func (p *epayProvider) QueryPayment(ctx context.Context, order *service.PaymentOrder) (*service.PaymentNotification, error) {
+	q := url.Values{
+		"act":          {"order"},
+		"pid":          {p.pid},
+		"key":          {p.key},
+		"out_trade_no": {order.OrderNo},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.gatewayURL+"/api.php?"+q.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	result, err := p.doAPI(req)
+	if err != nil {
+		return nil, err
+	}
+
+	n := &service.PaymentNotification{
+		OrderNo:         order.OrderNo,
+		ProviderTradeNo: result.Get("trade_no").String(),
+		Paid:            result.Get("status").Int() == 1,
+		Currency:        "CNY",
+	}
+	if money := result.Get("money").String(); money != "" {
+		if n.AmountCents, err = payment.ParseYuan(money); err != nil {
+			return nil, fmt.Errorf("parse epay money: %w", err)
+		}
+	}
+	return n, nil
+}
+
+func (p *epayProvider) Refund(ctx context.Context, req *service.PaymentRefundRequest) error {
+	form := url.Values{
+		"pid":          {p.pid},
+		"key":          {p.key},
+		"out_trade_no": {req.OrderNo},
+		"money":        {payment.FormatYuan(req.AmountCents)},
+	}
+	if req.ProviderTradeNo != "" {
+		form.Set("trade_no", req.ProviderTradeNo)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, p.gatewayURL+"/api.php?act=refund", strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	httpReq.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	_, err = p.doAPI(httpReq)
+	return err
+}
+
+// doAPI 调用 api.php；业务成功时 code 为 1
+func (p *epayProvider) doAPI(req *http.Request) (gjson.Result, error) {
+	resp, body, err := doPaymentRequest(p.client, req)
+	if err != nil {
+		return gjson.Result{}, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return gjson.Result{}, fmt.Errorf("status %d: %s", resp.StatusCode, truncatePaymentErrorBody(body))
+	}
+	if !gjson.ValidBytes(body) {
+		return gjson.Result{}, errors.New("epay api returned invalid json")
+	}
+	result := gjson.ParseBytes(body)
+	if result.Get("code").Int() != 1 {
+		return gjson.Result{}, fmt.Errorf("epay api error: %s", result.Get("msg").String())
+	}
+	return result, nil
+}
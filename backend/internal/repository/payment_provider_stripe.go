@@ -0,0 +1,175 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/config"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/payment"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+	"github.com/tidwall/gjson"
+)
+
+// stripeMinSessionTTL Checkout Session 的 expires_at 至少为创建后 30 分钟
+const stripeMinSessionTTL = 30 * time.Minute
+
+// stripeProvider Stripe Checkout：托管收银台跳转，Webhook 签名校验，查询 Session 与退款
+type stripeProvider struct {
+	secretKey     string
+	webhookSecret string
+	baseURL       string
+	client        *http.Client
+}
+
+func newStripeProvider(cfg config.StripePaymentConfig, client *http.Client) *stripeProvider {
+	return &stripeProvider{
+		secretKey:     cfg.SecretKey,
+		webhookSecret: cfg.WebhookSecret,
+		baseURL:       strings.TrimRight(strings.TrimSpace(cfg.APIBaseURL), "/"),
+		client:        client,
+	}
+}
+
+func (p *stripeProvider) Name() string                   { return service.PaymentProviderStripe }
+func (p *stripeProvider) Methods() []string              { return nil }
+func (p *stripeProvider) SupportsCurrency(_ string) bool { return true }
+
+func (p *stripeProvider) Ack(success bool) *service.PaymentNotifyAck {
+	if success {
+		return &service.PaymentNotifyAck{StatusCode: http.StatusOK, ContentType: "application/json", Body: []byte(`{"received":true}`)}
+	}
+	return &service.PaymentNotifyAck{StatusCode: http.StatusBadRequest, ContentType: "application/json", Body: []byte(`{"received":false}`)}
+}
+
+func (p *stripeProvider) CreatePayment(ctx context.Context, req *service.PaymentCreateRequest) (*service.PaymentCreateResult, error) {
+	if req.ReturnURL == "" {
+		return nil, errors.New("stripe checkout requires payment.return_url or server.frontend_url")
+	}
+	form := url.Values{
+		"mode":                                   {"payment"},
+		"success_url":                            {req.ReturnURL},
+		"cancel_url":                             {req.ReturnURL},
+		"client_reference_id":                    {req.OrderNo},
+		"metadata[order_no]":                     {req.OrderNo},
+		"line_items[0][quantity]":                {"1"},
+		"line_items[0][price_data][currency]":    {strings.ToLower(req.Currency)},
+		"line_items[0][price_data][unit_amount]": {strconv.FormatInt(req.AmountCents, 10)},
+		"line_items[0][price_data][product_data][name]": {req.Subject},
+		"payment_intent_data[metadata][order_no]":       {req.OrderNo},
+	}
+	if !req.ExpiresAt.IsZero() && time.Until(req.ExpiresAt) > stripeMinSessionTTL {
+		form.Set("expires_at", strconv.FormatInt(req.ExpiresAt.Unix(), 10))
+	}
+	session, err := p.call(ctx, http.MethodPost, "/v1/checkout/sessions", form, "checkout-"+req.OrderNo)
+	if err != nil {
+		return nil, err
+	}
+	return &service.PaymentCreateResult{
+		PayURL:          session.Get("url").String(),
+		ProviderTradeNo: session.Get("id").String(),
+	}, nil
+}
+
+func (p *stripeProvider) ParseNotification(_ context.Context, req *service.PaymentNotifyRequest) (*service.PaymentNotification, error) {
+	if err := payment.StripeVerify(req.Body, req.Header.Get("Stripe-Signature"), p.webhookSecret, paymentNotifyTolerance, time.Now()); err != nil {
+		return nil, err
+	}
+	if !gjson.ValidBytes(req.Body) {
+		return nil, errors.New("stripe webhook body is not valid json")
+	}
+	event := gjson.ParseBytes(req.Body)
+	switch event.Get("type").String() {
+	case "checkout.session.completed", "checkout.session.async_payment_succeeded":
+		return stripeSessionNotification(event.Get("data.object")), nil
+	default:
+		return &service.PaymentNotification{}, nil
+	}
+}
+
+func (p *stripeProvider) QueryPayment(ctx context.Context, order *service.PaymentOrder) (*service.PaymentNotification, error) {
+	if order.ProviderTradeNo == "" {
+		return &service.PaymentNotification{OrderNo: order.OrderNo}, nil
+	}
+	session, err := p.call(ctx, http.MethodGet, "/v1/checkout/sessions/"+url.PathEscape(order.ProviderTradeNo), nil, "")
+	if err != nil {
+		return nil, err
+	}
+	n := stripeSessionNotification(session)
+	if n.OrderNo != order.OrderNo {
+		return nil, fmt.Errorf("stripe session %s belongs to order %q", order.ProviderTradeNo, n.OrderNo)
+	}
+	return n, nil
+}
+
+func (p *stripeProvider) Refund(ctx context.Context, req *service.PaymentRefundRequest) error {
+	if req.ProviderTradeNo == "" {
+		return errors.New("stripe checkout session id is missing")
+	}
+	session, err := p.call(ctx, http.MethodGet, "/v1/checkout/sessions/"+url.PathEscape(req.ProviderTradeNo), nil, "")
+	if err != nil {
+		return err
+	}
+	paymentIntent := session.Get("payment_intent").String()
+	if paymentIntent == "" {
+		return errors.New("stripe checkout session has no payment_intent")
+	}
+	form := url.Values{
+		"payment_intent":     {paymentIntent},
+		"amount":             {strconv.FormatInt(req.AmountCents, 10)},
+		"metadata[order_no]": {req.OrderNo},
+	}
+	if req.Reason != "" {
+		form.Set("metadata[reason]", req.Reason)
+	}
+	_, err = p.call(ctx, http.MethodPost, "/v1/refunds", form, req.RefundNo)
+	return err
+}
+
+// stripeSessionNotification 从 Checkout Session 对象提取支付结果；订单号取 client_reference_id，缺失时取 metadata
+func stripeSessionNotification(session gjson.Result) *service.PaymentNotification {
+	orderNo := session.Get("client_reference_id").String()
+	if orderNo == "" {
+		orderNo = session.Get("metadata.order_no").String()
+	}
+	return &service.PaymentNotification{
+		OrderNo:         orderNo,
+		ProviderTradeNo: session.Get("id").String(),
+		Paid:            session.Get("payment_status").String() == "paid",
+		AmountCents:     session.Get("amount_total").Int(),
+		Currency:        strings.ToUpper(session.Get("currency").String()),
+	}
+}
+
+// call 调用 Stripe API（表单编码请求，JSON 应答）；idempotencyKey 非空时防止重复创建
+func (p *stripeProvider) call(ctx context.Context, method, path string, form url.Values, idempotencyKey string) (gjson.Result, error) {
+	req, err := http.NewRequestWithContext(ctx, method, p.baseURL+path, strings.NewReader(form.Encode()))
+	if err != nil {
+		return gjson.Result{}, err
+	}
+	req.Header.Set("Authorization", "Bearer "+p.secretKey)
+	if form != nil {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+	if idempotencyKey != "" {
+		req.Header.Set("Idempotency-Key", idempotencyKey)
+	}
+
+	resp, respBody, err := doPaymentRequest(p.client, req)
+	if err != nil {
+		return gjson.Result{}, err
+	}
+	if !gjson.ValidBytes(respBody) {
+		return gjson.Result{}, fmt.Errorf("status %d: %s", resp.StatusCode, truncatePaymentErrorBody(respBody))
+	}
+	result := gjson.ParseBytes(respBody)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return gjson.Result{}, fmt.Errorf("stripe error status %d: %s", resp.StatusCode, result.Get("error.message").String())
+	}
+	return result, nil
+}
@@ -0,0 +1,251 @@
+package repository
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/config"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/payment"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEPayProvider_CreateNotifyAndQuery(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/api.php", r.URL.Path)
+		require.Equal(t, "order", r.URL.Query().Get("act"))
+		require.Equal(t, "P1", r.URL.Query().Get("out_trade_no"))
+		_, _ = io.WriteString(w, `{"code":1,"trade_no":"E100","money":"70.00","status":1}`)
+	}))
+	defer srv.Close()
+
+	p := newEPayProvider(config.EPayConfig{GatewayURL: srv.URL + "/", PID: "1001", Key: "secret", Methods: []string{"alipay", " "}}, srv.Client())
+	require.Equal(t, []string{"alipay"}, p.Methods())
+	require.True(t, p.SupportsCurrency("cny"))
+	require.False(t, p.SupportsCurrency("USD"))
+
+	result, err := p.CreatePayment(context.Background(), &service.PaymentCreateRequest{
+		OrderNo: "P1", Subject: "Top-up", AmountCents: 7000, Method: "alipay", NotifyURL: "https://api.example.com/notify",
+	})
+	require.NoError(t, err)
+	u, err := url.Parse(result.PayURL)
+	require.NoError(t, err)
+	require.Equal(t, "/submit.php", u.Path)
+	require.NoError(t, payment.EPayVerify(u.Query(), "secret"))
+	require.Equal(t, "70.00", u.Query().Get("money"))
+
+	params := url.Values{
+		"pid": {"1001"}, "trade_no": {"E100"}, "out_trade_no": {"P1"}, "type": {"alipay"},
+		"money": {"70.00"}, "trade_status": {"TRADE_SUCCESS"}, "sign_type": {"MD5"},
+	}
+	params.Set("sign", payment.EPaySign(params, "secret"))
+	n, err := p.ParseNotification(context.Background(), &service.PaymentNotifyRequest{Method: http.MethodGet, Query: params})
+	require.NoError(t, err)
+	require.Equal(t, &service.PaymentNotification{OrderNo: "P1", ProviderTradeNo: "E100", Paid: true, AmountCents: 7000, Currency: "CNY"}, n)
+
+	params.Set("money", "0.01")
+	_, err = p.ParseNotification(context.Background(), &service.PaymentNotifyRequest{Method: http.MethodGet, Query: params})
+	require.ErrorIs(t, err, payment.ErrInvalidSignature)
+
+	n, err = p.QueryPayment(context.Background(), &service.PaymentOrder{OrderNo: "P1"})
+	require.NoError(t, err)
+	require.True(t, n.Paid)
+	require.Equal(t, int64(7000), n.AmountCents)
+	require.Equal(t, "success", string(p.Ack(true).Body))
+}
+
+func TestAlipayProvider_CreateAndNotify(t *testing.T) {
+	merchantKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	alipayKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	p, err := newAlipayProvider(config.AlipayConfig{
+		AppID:      "2021000",
+		PrivateKey: base64.StdEncoding.EncodeToString(mustPKCS8(t, merchantKey)),
+		PublicKey:  base64.StdEncoding.EncodeToString(mustPKIX(t, &alipayKey.PublicKey)),
+		GatewayURL: "https://openapi.alipay.com/gateway.do",
+	}, http.DefaultClient)
+	require.NoError(t, err)
+
+	result, err := p.CreatePayment(context.Background(), &service.PaymentCreateRequest{
+		OrderNo: "P2", Subject: "Pro", AmountCents: 990, Method: alipayMethodWap, NotifyURL: "https://api.example.com/notify",
+	})
+	require.NoError(t, err)
+	u, err := url.Parse(result.PayURL)
+	require.NoError(t, err)
+	q := u.Query()
+	require.Equal(t, "alipay.trade.wap.pay", q.Get("method"))
+	require.Contains(t, q.Get("biz_content"), `"total_amount":"9.90"`)
+	require.NoError(t, payment.RSAVerifySHA256(&merchantKey.PublicKey, payment.SortedQuery(q, "sign"), q.Get("sign")))
+
+	form := url.Values{
+		"app_id": {"2021000"}, "out_trade_no": {"P2"}, "trade_no": {"A200"},
+		"trade_status": {"TRADE_SUCCESS"}, "total_amount": {"9.90"}, "sign_type": {"RSA2"},
+	}
+	sig, err := payment.RSASignSHA256(alipayKey, payment.SortedQuery(form, "sign", "sign_type"))
+	require.NoError(t, err)
+	form.Set("sign", sig)
+	n, err := p.ParseNotification(context.Background(), &service.PaymentNotifyRequest{Method: http.MethodPost, Body: []byte(form.Encode())})
+	require.NoError(t, err)
+	require.Equal(t, &service.PaymentNotification{OrderNo: "P2", ProviderTradeNo: "A200", Paid: true, AmountCents: 990, Currency: "CNY"}, n)
+
+	// 签名正确但 app_id 不属于本商户
+	form.Set("app_id", "other")
+	sig, err = payment.RSASignSHA256(alipayKey, payment.SortedQuery(form, "sign", "sign_type"))
+	require.NoError(t, err)
+	form.Set("sign", sig)
+	_, err = p.ParseNotification(context.Background(), &service.PaymentNotifyRequest{Method: http.MethodPost, Body: []byte(form.Encode())})
+	require.Error(t, err)
+}
+
+func TestWechatPayProvider_Notify(t *testing.T) {
+	merchantKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	platformKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	apiV3Key := "0123456789abcdef0123456789abcdef"
+
+	p, err := newWechatPayProvider(config.WechatPayConfig{
+		AppID:             "wx1",
+		MchID:             "m1",
+		SerialNo:          "SERIAL",
+		PrivateKey:        base64.StdEncoding.EncodeToString(mustPKCS8(t, merchantKey)),
+		APIv3Key:          apiV3Key,
+		PlatformPublicKey: base64.StdEncoding.EncodeToString(mustPKIX(t, &platformKey.PublicKey)),
+		APIBaseURL:        "https://api.mch.weixin.qq.com",
+	}, http.DefaultClient)
+	require.NoError(t, err)
+
+	notify := func(eventType string, tx map[string]any) *service.PaymentNotifyRequest {
+		plain, err := json.Marshal(tx)
+		require.NoError(t, err)
+		block, err := aes.NewCipher([]byte(apiV3Key))
+		require.NoError(t, err)
+		gcm, err := cipher.NewGCM(block)
+		require.NoError(t, err)
+		nonce := "0123456789ab"
+		body, err := json.Marshal(map[string]any{
+			"id":         "evt",
+			"event_type": eventType,
+			"resource": map[string]any{
+				"algorithm":       "AEAD_AES_256_GCM",
+				"associated_data": "transaction",
+				"nonce":           nonce,
+				"ciphertext":      base64.StdEncoding.EncodeToString(gcm.Seal(nil, []byte(nonce), plain, []byte("transaction"))),
+			},
+		})
+		require.NoError(t, err)
+		ts := fmt.Sprint(time.Now().Unix())
+		sig, err := payment.RSASignSHA256(platformKey, payment.WechatSignMessage(ts, "n1", string(body)))
+		require.NoError(t, err)
+		header := http.Header{}
+		header.Set("Wechatpay-Timestamp", ts)
+		header.Set("Wechatpay-Nonce", "n1")
+		header.Set("Wechatpay-Signature", sig)
+		return &service.PaymentNotifyRequest{Method: http.MethodPost, Header: header, Body: body}
+	}
+
+	tx := map[string]any{
+		"appid": "wx1", "mchid": "m1", "out_trade_no": "P3", "transaction_id": "W300",
+		"trade_state": "SUCCESS", "amount": map[string]any{"total": 7000, "currency": "CNY"},
+	}
+	n, err := p.ParseNotification(context.Background(), notify("TRANSACTION.SUCCESS", tx))
+	require.NoError(t, err)
+	require.Equal(t, &service.PaymentNotification{OrderNo: "P3", ProviderTradeNo: "W300", Paid: true, AmountCents: 7000, Currency: "CNY"}, n)
+
+	n, err = p.ParseNotification(context.Background(), notify("REFUND.SUCCESS", tx))
+	require.NoError(t, err)
+	require.False(t, n.Paid)
+
+	req := notify("TRANSACTION.SUCCESS", tx)
+	req.Body = []byte(strings.Replace(string(req.Body), "evt", "evx", 1))
+	_, err = p.ParseNotification(context.Background(), req)
+	require.ErrorIs(t, err, payment.ErrInvalidSignature)
+
+	tx["mchid"] = "m2"
+	_, err = p.ParseNotification(context.Background(), notify("TRANSACTION.SUCCESS", tx))
+	require.Error(t, err)
+
+	require.Equal(t, http.StatusNoContent, p.Ack(true).StatusCode)
+	require.Equal(t, http.StatusInternalServerError, p.Ack(false).StatusCode)
+}
+
+func TestStripeProvider_NotifyAndRefund(t *testing.T) {
+	var refundForm url.Values
+	var refundKey string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "Bearer sk_test", r.Header.Get("Authorization"))
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/v1/checkout/sessions/cs_1":
+			_, _ = io.WriteString(w, `{"id":"cs_1","client_reference_id":"P4","payment_status":"paid","amount_total":1000,"currency":"usd","payment_intent":"pi_1"}`)
+		case r.Method == http.MethodPost && r.URL.Path == "/v1/refunds":
+			require.NoError(t, r.ParseForm())
+			refundForm = r.PostForm
+			refundKey = r.Header.Get("Idempotency-Key")
+			_, _ = io.WriteString(w, `{"id":"re_1","status":"succeeded"}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = io.WriteString(w, `{"error":{"message":"not found"}}`)
+		}
+	}))
+	defer srv.Close()
+
+	p := newStripeProvider(config.StripePaymentConfig{SecretKey: "sk_test", WebhookSecret: "whsec_test", APIBaseURL: srv.URL}, srv.Client())
+	require.True(t, p.SupportsCurrency("EUR"))
+
+	payload := []byte(`{"id":"evt_1","type":"checkout.session.completed","data":{"object":{"id":"cs_1","client_reference_id":"P4","payment_status":"paid","amount_total":1000,"currency":"usd"}}}`)
+	header := http.Header{}
+	header.Set("Stripe-Signature", payment.StripeSignature(payload, "whsec_test", time.Now()))
+	n, err := p.ParseNotification(context.Background(), &service.PaymentNotifyRequest{Method: http.MethodPost, Header: header, Body: payload})
+	require.NoError(t, err)
+	require.Equal(t, &service.PaymentNotification{OrderNo: "P4", ProviderTradeNo: "cs_1", Paid: true, AmountCents: 1000, Currency: "USD"}, n)
+
+	header.Set("Stripe-Signature", payment.StripeSignature(payload, "whsec_other", time.Now()))
+	_, err = p.ParseNotification(context.Background(), &service.PaymentNotifyRequest{Method: http.MethodPost, Header: header, Body: payload})
+	require.ErrorIs(t, err, payment.ErrInvalidSignature)
+
+	n, err = p.QueryPayment(context.Background(), &service.PaymentOrder{OrderNo: "P4", ProviderTradeNo: "cs_1"})
+	require.NoError(t, err)
+	require.True(t, n.Paid)
+	_, err = p.QueryPayment(context.Background(), &service.PaymentOrder{OrderNo: "P5", ProviderTradeNo: "cs_1"})
+	require.Error(t, err)
+
+	require.NoError(t, p.Refund(context.Background(), &service.PaymentRefundRequest{
+		OrderNo: "P4", ProviderTradeNo: "cs_1", RefundNo: "RP4", AmountCents: 500, TotalCents: 1000, Currency: "USD",
+	}))
+	require.Equal(t, "pi_1", refundForm.Get("payment_intent"))
+	require.Equal(t, "500", refundForm.Get("amount"))
+	require.Equal(t, "RP4", refundKey)
+
+	err = p.Refund(context.Background(), &service.PaymentRefundRequest{OrderNo: "P6", ProviderTradeNo: "cs_missing", RefundNo: "RP6", AmountCents: 1})
+	require.ErrorContains(t, err, "not found")
+}
+
+func mustPKCS8(t *testing.T, key *rsa.PrivateKey) []byte {
+	t.Helper()
+	b, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	return b
+}
+
+func mustPKIX(t *testing.T, key *rsa.PublicKey) []byte {
+	t.Helper()
+	b, err := x509.MarshalPKIXPublicKey(key)
+	require.NoError(t, err)
+	return b
+}
@@ -0,0 +1,239 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/config"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/payment"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+	"github.com/tidwall/gjson"
+)
+
+// wechatPayProvider 微信支付 APIv3：Native 扫码支付，回调验签并解密资源，主动查单与退款
+type wechatPayProvider struct {
+	appID       string
+	mchID       string
+	serialNo    string
+	apiV3Key    string
+	baseURL     string
+	privateKey  *rsa.PrivateKey // 商户 API 私钥
+	platformKey *rsa.PublicKey  // 微信支付平台公钥
+	client      *http.Client
+}
+
+func newWechatPayProvider(cfg config.WechatPayConfig, client *http.Client) (*wechatPayProvider, error) {
+	priv, err := payment.ParseRSAPrivateKey(cfg.PrivateKey)
+	if err != nil {
+		return nil, fmt.Errorf("wechat private_key: %w", err)
+	}
+	pub, err := payment.ParseRSAPublicKey(cfg.PlatformPublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("wechat platform_public_key: %w", err)
+	}
+	return &wechatPayProvider{
+		appID:       strings.TrimSpace(cfg.AppID),
+		mchID:       strings.TrimSpace(cfg.MchID),
+		serialNo:    strings.TrimSpace(cfg.SerialNo),
+		apiV3Key:    cfg.APIv3Key,
+		baseURL:     strings.TrimRight(strings.TrimSpace(cfg.APIBaseURL), "/"),
+		privateKey:  priv,
+		platformKey: pub,
+		client:      client,
+	}, nil
+}
+
+func (p *wechatPayProvider) Name() string                          { return service.PaymentProviderWechat }
+func (p *wechatPayProvider) Methods() []string                     { return nil }
+func (p *wechatPayProvider) SupportsCurrency(currency string) bool { return cnyOnly(currency) }
+
+// Ack 微信支付要求成功时返回 2xx，失败时返回非 2xx 及错误体
+func (p *wechatPayProvider) Ack(success bool) *service.PaymentNotifyAck {
+	if success {
+		return &service.PaymentNotifyAck{StatusCode: http.StatusNoContent}
+	}
+	return &service.PaymentNotifyAck{
+		StatusCode:  http.StatusInternalServerError,
+		ContentType: "application/json",
+		Body:        []byte(`{"code":"FAIL","message":"FAIL"}`),
+	}
+}
+
+func (p *wechatPayProvider) CreatePayment(ctx context.Context, req *service.PaymentCreateRequest) (*service.PaymentCreateResult, error) {
+	body := map[string]any{
+		"appid":        p.appID,
+		"mchid":        p.mchID,
+		"description":  req.Subject,
+		"out_trade_no": req.OrderNo,
+		"notify_url":   req.NotifyURL,
+		"amount":       map[string]any{"total": req.AmountCents, "currency": "CNY"},
+	}
+	if !req.ExpiresAt.IsZero() {
+		body["time_expire"] = req.ExpiresAt.Format(time.RFC3339)
+	}
+	status, result, err := p.call(ctx, http.MethodPost, "/v3/pay/transactions/native", body)
+	if err != nil {
+		return nil, err
+	}
+	if status != http.StatusOK {
+		return nil, wechatPayError(status, result)
+	}
+	codeURL := result.Get("code_url").String()
+	if codeURL == "" {
+		return nil, errors.New("wechat pay response missing code_url")
+	}
+	return &service.PaymentCreateResult{QRCode: codeURL}, nil
+}
+
+func (p *wechatPayProvider) ParseNotification(_ context.Context, req *service.PaymentNotifyRequest) (*service.PaymentNotification, error) {
+	if err := payment.WechatVerify(
+		p.platformKey,
+		req.Header.Get("Wechatpay-Timestamp"),
+		req.Header.Get("Wechatpay-Nonce"),
+		req.Body,
+		req.Header.Get("Wechatpay-Signature"),
+		paymentNotifyTolerance,
+		time.Now(),
+	); err != nil {
+		return nil, err
+	}
+	if !gjson.ValidBytes(req.Body) {
+		return nil, errors.New("wechat notify body is not valid json")
+	}
+	event := gjson.ParseBytes(req.Body)
+	resource := event.Get("resource")
+	plain, err := payment.WechatDecryptResource(
+		p.apiV3Key,
+		resource.Get("associated_data").String(),
+		resource.Get("nonce").String(),
+		resource.Get("ciphertext").String(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("decrypt wechat notify resource: %w", err)
+	}
+	if !strings.HasPrefix(event.Get("event_type").String(), "TRANSACTION.") {
+		// 退款等其他事件只需确认收到
+		return &service.PaymentNotification{}, nil
+	}
+	return p.parseTransaction(gjson.ParseBytes(plain))
+}
+
+func (p *wechatPayProvider) QueryPayment(ctx context.Context, order *service.PaymentOrder) (*service.PaymentNotification, error) {
+	path := "/v3/pay/transactions/out-trade-no/" + url.PathEscape(order.OrderNo) + "?mchid=" + url.QueryEscape(p.mchID)
+	status, result, err := p.call(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	if status == http.StatusNotFound && result.Get("code").String() == "ORDER_NOT_EXIST" {
+		return &service.PaymentNotification{OrderNo: order.OrderNo}, nil
+	}
+	if status != http.StatusOK {
+		return nil, wechatPayError(status, result)
+	}
+	return p.parseTransaction(result)
+}
+
+func (p *wechatPayProvider) Refund(ctx context.Context, req *service.PaymentRefundRequest) error {
+	body := map[string]any{
+		"out_trade_no":  req.OrderNo,
+		"out_refund_no": req.RefundNo,
+		"amount":        map[string]any{"refund": req.AmountCents, "total": req.TotalCents, "currency": "CNY"},
+	}
+	if req.Reason != "" {
+		body["reason"] = req.Reason
+	}
+	status, result, err := p.call(ctx, http.MethodPost, "/v3/refund/domestic/refunds", body)
+	if err != nil {
+		return err
+	}
+	if status != http.StatusOK {
+		return wechatPayError(status, result)
+	}
+	return nil
+}
+
+func (p *wechatPayProvider) parseTransaction(tx gjson.Result) (*service.PaymentNotification, error) {
+	if tx.Get("mchid").String() != p.mchID || tx.Get("appid").String() != p.appID {
+		return nil, fmt.Errorf("wechat transaction merchant mismatch: mchid=%q appid=%q", tx.Get("mchid").String(), tx.Get("appid").String())
+	}
+	return &service.PaymentNotification{
+		OrderNo:         tx.Get("out_trade_no").String(),
+		ProviderTradeNo: tx.Get("transaction_id").String(),
+		Paid:            tx.Get("trade_state").String() == "SUCCESS",
+		AmountCents:     tx.Get("amount.total").Int(),
+		Currency:        tx.Get("amount.currency").String(),
+	}, nil
+}
+
+func wechatPayError(status int, result gjson.Result) error {
+	return fmt.Errorf("wechat pay error status %d %s: %s", status, result.Get("code").String(), result.Get("message").String())
+}
+
+// call 发送签名请求并校验应答签名；path 含查询串
+func (p *wechatPayProvider) call(ctx context.Context, method, path string, payload any) (int, gjson.Result, error) {
+	var body []byte
+	if payload != nil {
+		var err error
+		if body, err = json.Marshal(payload); err != nil {
+			return 0, gjson.Result{}, err
+		}
+	}
+	req, err := http.NewRequestWithContext(ctx, method, p.baseURL+path, bytes.NewReader(body))
+	if err != nil {
+		return 0, gjson.Result{}, err
+	}
+	nonce, err := wechatNonce()
+	if err != nil {
+		return 0, gjson.Result{}, err
+	}
+	timestamp := time.Now().Unix()
+	signature, err := payment.RSASignSHA256(p.privateKey, payment.WechatSignMessage(method, path, fmt.Sprint(timestamp), nonce, string(body)))
+	if err != nil {
+		return 0, gjson.Result{}, err
+	}
+	req.Header.Set("Authorization", payment.WechatAuthorization(p.mchID, p.serialNo, nonce, timestamp, signature))
+	req.Header.Set("Accept", "application/json")
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, respBody, err := doPaymentRequest(p.client, req)
+	if err != nil {
+		return 0, gjson.Result{}, err
+	}
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		if err := payment.WechatVerify(
+			p.platformKey,
+			resp.Header.Get("Wechatpay-Timestamp"),
+			resp.Header.Get("Wechatpay-Nonce"),
+			respBody,
+			resp.Header.Get("Wechatpay-Signature"),
+			0,
+			time.Now(),
+		); err != nil {
+			return 0, gjson.Result{}, fmt.Errorf("verify wechat pay response: %w", err)
+		}
+	}
+	if len(respBody) > 0 && !gjson.ValidBytes(respBody) {
+		return 0, gjson.Result{}, fmt.Errorf("status %d: %s", resp.StatusCode, truncatePaymentErrorBody(respBody))
+	}
+	return resp.StatusCode, gjson.ParseBytes(respBody), nil
+}
+
+func wechatNonce() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
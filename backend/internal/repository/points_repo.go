@@ -0,0 +1,329 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	dbent "github.com/Wei-Shaw/sub2api/ent"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+)
+
+type pointsRepository struct {
+	sql sqlExecutor
+}
+
+// NewPointsRepository 创建积分仓储
+func NewPointsRepository(sqlDB *sql.DB) service.PointsRepository {
+	return &pointsRepository{sql: sqlDB}
+}
+
+// q 返回当前上下文的执行器：兑换时与发放余额、订阅在同一事务内提交
+func (r *pointsRepository) q(ctx context.Context) sqlExecutor {
+	if tx := dbent.TxFromContext(ctx); tx != nil {
+		return tx.Client()
+	}
+	return r.sql
+}
+
+// ---------- 积分账户 ----------
+
+func (r *pointsRepository) GetAccount(ctx context.Context, userID int64) (*service.PointsAccount, error) {
+	account := &service.PointsAccount{UserID: userID}
+	err := scanSingleRow(ctx, r.q(ctx), `
+		SELECT balance, total_earned, total_spent, updated_at FROM points_accounts WHERE user_id = $1
+	`, []any{userID}, &account.Balance, &account.TotalEarned, &account.TotalSpent, &account.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return account, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return account, nil
+}
+
+// ApplyDelta 在一条语句内更新账户并写入流水：获得积分时按需创建账户，扣减时仅在余额充足时更新
+func (r *pointsRepository) ApplyDelta(ctx context.Context, userID, delta int64, src service.PointsLedgerSource) (*service.PointsLedgerEntry, bool, error) {
+	var account string
+	if delta > 0 {
+		account = `
+			INSERT INTO points_accounts (user_id, balance, total_earned)
+			VALUES ($1, $2, $2)
+			ON CONFLICT (user_id) DO UPDATE
+			SET balance = points_accounts.balance + EXCLUDED.balance,
+				total_earned = points_accounts.total_earned + EXCLUDED.total_earned,
+				updated_at = NOW()
+			RETURNING balance`
+	} else {
+		account = `
+			UPDATE points_accounts
+			SET balance = balance + $2, total_spent = total_spent - $2, updated_at = NOW()
+			WHERE user_id = $1 AND balance + $2 >= 0
+			RETURNING balance`
+	}
+
+	entry := &service.PointsLedgerEntry{
+		UserID:      userID,
+		Delta:       delta,
+		SourceType:  src.Type,
+		ReferenceID: src.ReferenceID,
+		OperatorID:  src.OperatorID,
+		Notes:       src.Notes,
+	}
+	err := scanSingleRow(ctx, r.q(ctx), `
+		WITH acct AS (`+account+`
+		)
+		INSERT INTO points_ledger (user_id, delta, balance_after, source_type, reference_id, operator_id, notes)
+		SELECT $1, $2, acct.balance, $3, $4, $5, $6 FROM acct
+		RETURNING id, balance_after, created_at
+	`, []any{
+		userID, delta, src.Type, src.ReferenceID, nullInt64(src.OperatorID), src.Notes,
+	}, &entry.ID, &entry.BalanceAfter, &entry.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return entry, true, nil
+}
+
+func (r *pointsRepository) ListLedger(ctx context.Context, userID int64, params pagination.PaginationParams) ([]service.PointsLedgerEntry, *pagination.PaginationResult, error) {
+	var total int64
+	if err := scanSingleRow(ctx, r.q(ctx), `SELECT COUNT(*) FROM points_ledger WHERE user_id = $1`, []any{userID}, &total); err != nil {
+		return nil, nil, err
+	}
+	if total == 0 {
+		return []service.PointsLedgerEntry{}, paginationResultFromTotal(0, params), nil
+	}
+
+	rows, err := r.q(ctx).QueryContext(ctx, `
+		SELECT id, user_id, delta, balance_after, source_type, reference_id, operator_id, notes, created_at
+		FROM points_ledger
+		WHERE user_id = $1
+		ORDER BY id DESC
+		LIMIT $2 OFFSET $3
+	`, userID, params.Limit(), params.Offset())
+	if err != nil {
+		return nil, nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	entries := make([]service.PointsLedgerEntry, 0)
+	for rows.Next() {
+		var entry service.PointsLedgerEntry
+		var operatorID sql.NullInt64
+		if err := rows.Scan(
+			&entry.ID, &entry.UserID, &entry.Delta, &entry.BalanceAfter, &entry.SourceType,
+			&entry.ReferenceID, &operatorID, &entry.Notes, &entry.CreatedAt,
+		); err != nil {
+			return nil, nil, err
+		}
+		entry.OperatorID = nullInt64Value(operatorID)
+		entries = append(entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+	return entries, paginationResultFromTotal(total, params), nil
+}
+
+// ---------- 积分商城商品 ----------
+
+const pointsShopItemColumns = `id, name, description, kind, points_cost, balance_amount, group_id, validity_days,
+	stock, per_user_limit, enabled, sort_order, created_at, updated_at`
+
+func (r *pointsRepository) CreateItem(ctx context.Context, item *service.PointsShopItem) error {
+	return scanSingleRow(ctx, r.q(ctx), `
+		INSERT INTO points_shop_items
+			(name, description, kind, points_cost, balance_amount, group_id, validity_days, stock, per_user_limit, enabled, sort_order)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		RETURNING id, created_at, updated_at
+	`, []any{
+		item.Name, item.Description, item.Kind, item.PointsCost, item.BalanceAmount, nullInt64(item.GroupID),
+		item.ValidityDays, item.Stock, item.PerUserLimit, item.Enabled, item.SortOrder,
+	}, &item.ID, &item.CreatedAt, &item.UpdatedAt)
+}
+
+func (r *pointsRepository) UpdateItem(ctx context.Context, item *service.PointsShopItem) error {
+	err := scanSingleRow(ctx, r.q(ctx), `
+		UPDATE points_shop_items
+		SET name = $2, description = $3, kind = $4, points_cost = $5, balance_amount = $6, group_id = $7,
+			validity_days = $8, stock = $9, per_user_limit = $10, enabled = $11, sort_order = $12, updated_at = NOW()
+		WHERE id = $1
+		RETURNING created_at, updated_at
+	`, []any{
+		item.ID, item.Name, item.Description, item.Kind, item.PointsCost, item.BalanceAmount, nullInt64(item.GroupID),
+		item.ValidityDays, item.Stock, item.PerUserLimit, item.Enabled, item.SortOrder,
+	}, &item.CreatedAt, &item.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return service.ErrPointsShopItemNotFound
+	}
+	return err
+}
+
+func (r *pointsRepository) DeleteItem(ctx context.Context, id int64) error {
+	result, err := r.q(ctx).ExecContext(ctx, `DELETE FROM points_shop_items WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return service.ErrPointsShopItemNotFound
+	}
+	return nil
+}
+
+func (r *pointsRepository) getItem(ctx context.Context, id int64, forUpdate bool) (*service.PointsShopItem, error) {
+	query := `SELECT ` + pointsShopItemColumns + ` FROM points_shop_items WHERE id = $1`
+	if forUpdate {
+		query += ` FOR UPDATE`
+	}
+	rows, err := r.q(ctx).QueryContext(ctx, query, id)
+	if err != nil {
+		return nil, err
+	}
+	items, err := scanPointsShopItems(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(items) == 0 {
+		return nil, service.ErrPointsShopItemNotFound
+	}
+	return &items[0], nil
+}
+
+func (r *pointsRepository) GetItem(ctx context.Context, id int64) (*service.PointsShopItem, error) {
+	return r.getItem(ctx, id, false)
+}
+
+func (r *pointsRepository) GetItemForUpdate(ctx context.Context, id int64) (*service.PointsShopItem, error) {
+	return r.getItem(ctx, id, true)
+}
+
+func (r *pointsRepository) ListItems(ctx context.Context, enabledOnly bool) ([]service.PointsShopItem, error) {
+	query := `SELECT ` + pointsShopItemColumns + ` FROM points_shop_items`
+	if enabledOnly {
+		query += ` WHERE enabled`
+	}
+	rows, err := r.q(ctx).QueryContext(ctx, query+` ORDER BY sort_order ASC, id ASC`)
+	if err != nil {
+		return nil, err
+	}
+	return scanPointsShopItems(rows)
+}
+
+func (r *pointsRepository) DecrementStock(ctx context.Context, id int64) error {
+	result, err := r.q(ctx).ExecContext(ctx, `
+		UPDATE points_shop_items SET stock = stock - 1, updated_at = NOW() WHERE id = $1 AND stock > 0
+	`, id)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return service.ErrPointsShopOutOfStock
+	}
+	return nil
+}
+
+func scanPointsShopItems(rows *sql.Rows) ([]service.PointsShopItem, error) {
+	defer func() { _ = rows.Close() }()
+
+	items := make([]service.PointsShopItem, 0)
+	for rows.Next() {
+		var item service.PointsShopItem
+		var groupID sql.NullInt64
+		if err := rows.Scan(
+			&item.ID, &item.Name, &item.Description, &item.Kind, &item.PointsCost, &item.BalanceAmount, &groupID,
+			&item.ValidityDays, &item.Stock, &item.PerUserLimit, &item.Enabled, &item.SortOrder, &item.CreatedAt, &item.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		item.GroupID = nullInt64Value(groupID)
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// ---------- 兑换记录 ----------
+
+func (r *pointsRepository) CreateExchange(ctx context.Context, ex *service.PointsExchange) error {
+	return scanSingleRow(ctx, r.q(ctx), `
+		INSERT INTO points_exchanges (user_id, item_id, item_name, kind, points_cost, redeem_code)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at
+	`, []any{
+		ex.UserID, nullInt64(ex.ItemID), ex.ItemName, ex.Kind, ex.PointsCost, ex.RedeemCode,
+	}, &ex.ID, &ex.CreatedAt)
+}
+
+func (r *pointsRepository) CountUserExchanges(ctx context.Context, userID, itemID int64) (int, error) {
+	var count int
+	err := scanSingleRow(ctx, r.q(ctx), `
+		SELECT COUNT(*) FROM points_exchanges WHERE user_id = $1 AND item_id = $2
+	`, []any{userID, itemID}, &count)
+	return count, err
+}
+
+func (r *pointsRepository) ListExchanges(ctx context.Context, params pagination.PaginationParams, filter service.PointsExchangeFilter) ([]service.PointsExchange, *pagination.PaginationResult, error) {
+	conds := []string{"1=1"}
+	args := []any{}
+	if filter.UserID > 0 {
+		args = append(args, filter.UserID)
+		conds = append(conds, fmt.Sprintf("pe.user_id = $%d", len(args)))
+	}
+	if filter.ItemID > 0 {
+		args = append(args, filter.ItemID)
+		conds = append(conds, fmt.Sprintf("pe.item_id = $%d", len(args)))
+	}
+	where := strings.Join(conds, " AND ")
+
+	var total int64
+	if err := scanSingleRow(ctx, r.q(ctx), `SELECT COUNT(*) FROM points_exchanges pe WHERE `+where, args, &total); err != nil {
+		return nil, nil, err
+	}
+	if total == 0 {
+		return []service.PointsExchange{}, paginationResultFromTotal(0, params), nil
+	}
+
+	rows, err := r.q(ctx).QueryContext(ctx, fmt.Sprintf(`
+		SELECT pe.id, pe.user_id, pe.item_id, pe.item_name, pe.kind, pe.points_cost, pe.redeem_code, pe.created_at,
+			COALESCE(u.email, '')
+		FROM points_exchanges pe
+		LEFT JOIN users u ON u.id = pe.user_id
+		WHERE %s
+		ORDER BY pe.id DESC
+		LIMIT $%d OFFSET $%d
+	`, where, len(args)+1, len(args)+2), append(args, params.Limit(), params.Offset())...)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	out := make([]service.PointsExchange, 0)
+	for rows.Next() {
+		var ex service.PointsExchange
+		var itemID sql.NullInt64
+		if err := rows.Scan(
+			&ex.ID, &ex.UserID, &itemID, &ex.ItemName, &ex.Kind, &ex.PointsCost, &ex.RedeemCode, &ex.CreatedAt, &ex.UserEmail,
+		); err != nil {
+			return nil, nil, err
+		}
+		ex.ItemID = nullInt64Value(itemID)
+		out = append(out, ex)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+	return out, paginationResultFromTotal(total, params), nil
+}
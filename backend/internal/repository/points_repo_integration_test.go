@@ -0,0 +1,176 @@
+//go:build integration
+
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPointsRepository_ApplyDeltaAndLedger(t *testing.T) {
+	ctx := context.Background()
+	repo := NewPointsRepository(integrationDB)
+	user := createReferralTestUser(t, ctx, "points")
+
+	account, err := repo.GetAccount(ctx, user.ID)
+	require.NoError(t, err)
+	require.Zero(t, account.Balance)
+
+	_, ok, err := repo.ApplyDelta(ctx, user.ID, -1, service.PointsLedgerSource{Type: service.PointsSourceShopExchange})
+	require.NoError(t, err)
+	require.False(t, ok, "no account yet")
+
+	entry, ok, err := repo.ApplyDelta(ctx, user.ID, 120, service.PointsLedgerSource{Type: service.PointsSourceActivityReward, ReferenceID: "7"})
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, int64(120), entry.BalanceAfter)
+
+	operatorID := user.ID
+	entry, ok, err = repo.ApplyDelta(ctx, user.ID, -100, service.PointsLedgerSource{Type: service.PointsSourceAdminAdjust, OperatorID: &operatorID, Notes: "fix"})
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, int64(20), entry.BalanceAfter)
+
+	_, ok, err = repo.ApplyDelta(ctx, user.ID, -21, service.PointsLedgerSource{Type: service.PointsSourceShopExchange})
+	require.NoError(t, err)
+	require.False(t, ok, "balance would go negative")
+
+	account, err = repo.GetAccount(ctx, user.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(20), account.Balance)
+	require.Equal(t, int64(120), account.TotalEarned)
+	require.Equal(t, int64(100), account.TotalSpent)
+
+	entries, page, err := repo.ListLedger(ctx, user.ID, pagination.PaginationParams{Page: 1, PageSize: 10})
+	require.NoError(t, err)
+	require.Equal(t, int64(2), page.Total)
+	require.Equal(t, int64(-100), entries[0].Delta)
+	require.NotNil(t, entries[0].OperatorID)
+	require.Equal(t, "7", entries[1].ReferenceID)
+}
+
+func TestPointsRepository_ShopItemsAndExchanges(t *testing.T) {
+	ctx := context.Background()
+	repo := NewPointsRepository(integrationDB)
+	user := createReferralTestUser(t, ctx, "points-shop")
+
+	item := &service.PointsShopItem{
+		Name:          uniqueTestValue(t, "item"),
+		Kind:          service.PointsShopKindBalance,
+		PointsCost:    50,
+		BalanceAmount: money.FromFloat(2.5),
+		Stock:         1,
+		Enabled:       true,
+	}
+	require.NoError(t, repo.CreateItem(ctx, item))
+	require.NotZero(t, item.ID)
+
+	got, err := repo.GetItemForUpdate(ctx, item.ID)
+	require.NoError(t, err)
+	require.Equal(t, money.FromFloat(2.5), got.BalanceAmount)
+	require.Nil(t, got.GroupID)
+
+	require.NoError(t, repo.DecrementStock(ctx, item.ID))
+	require.ErrorIs(t, repo.DecrementStock(ctx, item.ID), service.ErrPointsShopOutOfStock)
+
+	ex := &service.PointsExchange{UserID: user.ID, ItemID: &item.ID, ItemName: item.Name, Kind: item.Kind, PointsCost: item.PointsCost}
+	require.NoError(t, repo.CreateExchange(ctx, ex))
+	count, err := repo.CountUserExchanges(ctx, user.ID, item.ID)
+	require.NoError(t, err)
+	require.Equal(t, 1, count)
+
+	exchanges, page, err := repo.ListExchanges(ctx, pagination.PaginationParams{Page: 1, PageSize: 10}, service.PointsExchangeFilter{UserID: user.ID})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), page.Total)
+	require.Equal(t, user.Email, exchanges[0].UserEmail)
+
+	// 删除商品后兑换记录保留快照
+	require.NoError(t, repo.DeleteItem(ctx, item.ID))
+	_, err = repo.GetItem(ctx, item.ID)
+	require.ErrorIs(t, err, service.ErrPointsShopItemNotFound)
+	exchanges, _, err = repo.ListExchanges(ctx, pagination.PaginationParams{Page: 1, PageSize: 10}, service.PointsExchangeFilter{UserID: user.ID})
+	require.NoError(t, err)
+	require.Nil(t, exchanges[0].ItemID)
+	require.Equal(t, item.Name, exchanges[0].ItemName)
+}
+
+func TestCouponRepository_Lifecycle(t *testing.T) {
+	ctx := context.Background()
+	repo := NewCouponRepository(integrationDB)
+	user := createReferralTestUser(t, ctx, "coupon")
+
+	coupon := &service.Coupon{
+		Name:         uniqueTestValue(t, "coupon"),
+		DiscountType: service.CouponDiscountPercent,
+		PercentOff:   10,
+		Scope:        service.CouponScopeAll,
+		Enabled:      true,
+	}
+	require.NoError(t, repo.Create(ctx, coupon))
+
+	expired := time.Now().Add(-time.Hour)
+	stale := &service.UserCoupon{CouponID: coupon.ID, UserID: user.ID, Status: service.UserCouponStatusUnused, ExpiresAt: &expired}
+	require.NoError(t, repo.Issue(ctx, stale))
+	uc := &service.UserCoupon{CouponID: coupon.ID, UserID: user.ID, Status: service.UserCouponStatusUnused, Source: service.CouponSourceAdmin}
+	require.NoError(t, repo.Issue(ctx, uc))
+
+	got, err := repo.GetUserCoupon(ctx, uc.ID)
+	require.NoError(t, err)
+	require.Equal(t, coupon.Name, got.Coupon.Name)
+
+	params := pagination.PaginationParams{Page: 1, PageSize: 10}
+	list, _, err := repo.ListUserCoupons(ctx, params, service.UserCouponFilter{UserID: user.ID, Status: service.UserCouponStatusExpired})
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	require.Equal(t, stale.ID, list[0].ID)
+
+	ok, err := repo.LockForOrder(ctx, stale.ID, user.ID, "ORDER-STALE", time.Now())
+	require.NoError(t, err)
+	require.False(t, ok, "expired coupons cannot be locked")
+
+	orderNo := uniqueTestValue(t, "o")
+	if len(orderNo) > 32 {
+		orderNo = orderNo[len(orderNo)-32:]
+	}
+	ok, err = repo.LockForOrder(ctx, uc.ID, user.ID, orderNo, time.Now())
+	require.NoError(t, err)
+	require.True(t, ok)
+	ok, err = repo.LockForOrder(ctx, uc.ID, user.ID, orderNo+"X", time.Now())
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	require.NoError(t, repo.ReleaseOrder(ctx, orderNo))
+	got, err = repo.GetUserCoupon(ctx, uc.ID)
+	require.NoError(t, err)
+	require.Equal(t, service.UserCouponStatusUnused, got.Status)
+	require.Empty(t, got.OrderNo)
+
+	ok, err = repo.ConsumeForOrder(ctx, uc.ID, orderNo)
+	require.NoError(t, err)
+	require.True(t, ok, "released coupon can still be consumed by its late-paid order")
+	ok, err = repo.ConsumeForOrder(ctx, uc.ID, orderNo)
+	require.NoError(t, err)
+	require.True(t, ok, "consume is idempotent for the same order")
+	ok, err = repo.ConsumeForOrder(ctx, uc.ID, orderNo+"X")
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	coupons, _, err := repo.List(ctx, pagination.PaginationParams{Page: 1, PageSize: 100})
+	require.NoError(t, err)
+	for _, c := range coupons {
+		if c.ID == coupon.ID {
+			require.Equal(t, int64(2), c.IssuedCount)
+			require.Equal(t, int64(1), c.UsedCount)
+		}
+	}
+
+	require.NoError(t, repo.Delete(ctx, coupon.ID))
+	_, err = repo.GetUserCoupon(ctx, uc.ID)
+	require.ErrorIs(t, err, service.ErrUserCouponNotFound)
+}
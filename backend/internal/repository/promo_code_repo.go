@@ -2,10 +2,12 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	dbent "github.com/Wei-Shaw/sub2api/ent"
 	"github.com/Wei-Shaw/sub2api/ent/promocode"
 	"github.com/Wei-Shaw/sub2api/ent/promocodeusage"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
 	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
 	"github.com/Wei-Shaw/sub2api/internal/service"
 )
@@ -26,11 +28,24 @@ func (r *promoCodeRepository) Create(ctx context.Context, code *service.PromoCod
 		SetMaxUses(code.MaxUses).
 		SetUsedCount(code.UsedCount).
 		SetStatus(code.Status).
-		SetNotes(code.Notes)
+		SetNotes(code.Notes).
+		SetTopupBonusPercent(code.TopupBonusPercent).
+		SetTopupBonusMax(code.TopupBonusMax).
+		SetMinTopupAmount(code.MinTopupAmount).
+		SetPerUserLimit(code.PerUserLimit).
+		SetStackable(code.Stackable).
+		SetNillableTrialGroupID(code.TrialGroupID).
+		SetTrialDays(code.TrialDays)
 
 	if code.ExpiresAt != nil {
 		builder.SetExpiresAt(*code.ExpiresAt)
 	}
+	if len(code.AllowedEmailDomains) > 0 {
+		builder.SetAllowedEmailDomains(code.AllowedEmailDomains)
+	}
+	if len(code.RequiredAttributes) > 0 {
+		builder.SetRequiredAttributes(code.RequiredAttributes)
+	}
 
 	created, err := builder.Save(ctx)
 	if err != nil {
@@ -92,13 +107,34 @@ func (r *promoCodeRepository) Update(ctx context.Context, code *service.PromoCod
 		SetMaxUses(code.MaxUses).
 		SetUsedCount(code.UsedCount).
 		SetStatus(code.Status).
-		SetNotes(code.Notes)
+		SetNotes(code.Notes).
+		SetTopupBonusPercent(code.TopupBonusPercent).
+		SetTopupBonusMax(code.TopupBonusMax).
+		SetMinTopupAmount(code.MinTopupAmount).
+		SetPerUserLimit(code.PerUserLimit).
+		SetStackable(code.Stackable).
+		SetTrialDays(code.TrialDays)
 
 	if code.ExpiresAt != nil {
 		builder.SetExpiresAt(*code.ExpiresAt)
 	} else {
 		builder.ClearExpiresAt()
 	}
+	if code.TrialGroupID != nil {
+		builder.SetTrialGroupID(*code.TrialGroupID)
+	} else {
+		builder.ClearTrialGroupID()
+	}
+	if len(code.AllowedEmailDomains) > 0 {
+		builder.SetAllowedEmailDomains(code.AllowedEmailDomains)
+	} else {
+		builder.ClearAllowedEmailDomains()
+	}
+	if len(code.RequiredAttributes) > 0 {
+		builder.SetRequiredAttributes(code.RequiredAttributes)
+	} else {
+		builder.ClearRequiredAttributes()
+	}
 
 	updated, err := builder.Save(ctx)
 	if err != nil {
@@ -157,6 +193,7 @@ func (r *promoCodeRepository) CreateUsage(ctx context.Context, usage *service.Pr
 		SetPromoCodeID(usage.PromoCodeID).
 		SetUserID(usage.UserID).
 		SetBonusAmount(usage.BonusAmount).
+		SetTopupBonusStatus(usage.TopupBonusStatus).
 		SetUsedAt(usage.UsedAt).
 		Save(ctx)
 	if err != nil {
@@ -167,20 +204,54 @@ func (r *promoCodeRepository) CreateUsage(ctx context.Context, usage *service.Pr
 	return nil
 }
 
-func (r *promoCodeRepository) GetUsageByPromoCodeAndUser(ctx context.Context, promoCodeID, userID int64) (*service.PromoCodeUsage, error) {
-	m, err := r.client.PromoCodeUsage.Query().
+func (r *promoCodeRepository) CountUsagesByPromoCodeAndUser(ctx context.Context, promoCodeID, userID int64) (int, error) {
+	client := clientFromContext(ctx, r.client)
+	return client.PromoCodeUsage.Query().
 		Where(
 			promocodeusage.PromoCodeIDEQ(promoCodeID),
 			promocodeusage.UserIDEQ(userID),
 		).
-		Only(ctx)
+		Count(ctx)
+}
+
+func (r *promoCodeRepository) ListUsagesByUser(ctx context.Context, userID int64) ([]service.PromoCodeUsage, error) {
+	client := clientFromContext(ctx, r.client)
+	usages, err := client.PromoCodeUsage.Query().
+		Where(promocodeusage.UserIDEQ(userID)).
+		WithPromoCode().
+		Order(dbent.Asc(promocodeusage.FieldID)).
+		All(ctx)
 	if err != nil {
-		if dbent.IsNotFound(err) {
-			return nil, nil
-		}
 		return nil, err
 	}
-	return promoCodeUsageEntityToService(m), nil
+	return promoCodeUsageEntitiesToService(usages), nil
+}
+
+func (r *promoCodeRepository) ListPendingTopupUsagesForUpdate(ctx context.Context, userID int64) ([]service.PromoCodeUsage, error) {
+	client := clientFromContext(ctx, r.client)
+	usages, err := client.PromoCodeUsage.Query().
+		Where(
+			promocodeusage.UserIDEQ(userID),
+			promocodeusage.TopupBonusStatusEQ(service.PromoTopupBonusStatusPending),
+		).
+		WithPromoCode().
+		Order(dbent.Asc(promocodeusage.FieldUsedAt), dbent.Asc(promocodeusage.FieldID)).
+		ForUpdate().
+		All(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return promoCodeUsageEntitiesToService(usages), nil
+}
+
+func (r *promoCodeRepository) MarkTopupBonusGranted(ctx context.Context, usageID int64, amount money.Amount, grantedAt time.Time) error {
+	client := clientFromContext(ctx, r.client)
+	_, err := client.PromoCodeUsage.UpdateOneID(usageID).
+		SetTopupBonusStatus(service.PromoTopupBonusStatusGranted).
+		SetTopupBonusAmount(amount).
+		SetTopupGrantedAt(grantedAt).
+		Save(ctx)
+	return err
 }
 
 func (r *promoCodeRepository) GetLatestUsageByUser(ctx context.Context, userID int64) (*service.PromoCodeUsage, error) {
@@ -247,6 +318,16 @@ func promoCodeEntityToService(m *dbent.PromoCode) *service.PromoCode {
 		Notes:       derefString(m.Notes),
 		CreatedAt:   m.CreatedAt,
 		UpdatedAt:   m.UpdatedAt,
+
+		TopupBonusPercent:   m.TopupBonusPercent,
+		TopupBonusMax:       m.TopupBonusMax,
+		MinTopupAmount:      m.MinTopupAmount,
+		PerUserLimit:        m.PerUserLimit,
+		AllowedEmailDomains: m.AllowedEmailDomains,
+		RequiredAttributes:  m.RequiredAttributes,
+		Stackable:           m.Stackable,
+		TrialGroupID:        m.TrialGroupID,
+		TrialDays:           m.TrialDays,
 	}
 }
 
@@ -270,6 +351,10 @@ func promoCodeUsageEntityToService(m *dbent.PromoCodeUsage) *service.PromoCodeUs
 		UserID:      m.UserID,
 		BonusAmount: m.BonusAmount,
 		UsedAt:      m.UsedAt,
+
+		TopupBonusStatus: m.TopupBonusStatus,
+		TopupBonusAmount: m.TopupBonusAmount,
+		TopupGrantedAt:   m.TopupGrantedAt,
 	}
 	if m.Edges.User != nil {
 		out.User = userEntityToService(m.Edges.User)
@@ -0,0 +1,93 @@
+//go:build integration
+
+package repository
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	dbent "github.com/Wei-Shaw/sub2api/ent"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPromoCodeRepository_RulesAndTopupUsages(t *testing.T) {
+	ctx := context.Background()
+	client := testEntClient(t)
+	repo := NewPromoCodeRepository(client)
+	user := createReferralTestUser(t, ctx, "promo-rules")
+
+	code := strings.ToUpper(uniqueTestValue(t, "p"))
+	if len(code) > 32 {
+		code = code[len(code)-32:]
+	}
+	promo := &service.PromoCode{
+		Code:                code,
+		Status:              service.PromoCodeStatusActive,
+		TopupBonusPercent:   12.5,
+		TopupBonusMax:       5 * money.USD,
+		MinTopupAmount:      10 * money.USD,
+		PerUserLimit:        2,
+		AllowedEmailDomains: []string{"example.com"},
+		RequiredAttributes:  map[string]string{"tier": "vip"},
+		Stackable:           true,
+	}
+	require.NoError(t, repo.Create(ctx, promo))
+	t.Cleanup(func() { _ = repo.Delete(context.Background(), promo.ID) })
+
+	got, err := repo.GetByCode(ctx, strings.ToLower(code))
+	require.NoError(t, err)
+	require.Equal(t, 12.5, got.TopupBonusPercent)
+	require.Equal(t, 2, got.PerUserLimit)
+	require.Equal(t, []string{"example.com"}, got.AllowedEmailDomains)
+	require.Equal(t, map[string]string{"tier": "vip"}, got.RequiredAttributes)
+	require.True(t, got.Stackable)
+	require.Nil(t, got.TrialGroupID)
+
+	// 同一用户可多次使用（唯一约束已移除）
+	for i := 0; i < 2; i++ {
+		require.NoError(t, repo.CreateUsage(ctx, &service.PromoCodeUsage{
+			PromoCodeID:      promo.ID,
+			UserID:           user.ID,
+			UsedAt:           time.Now(),
+			TopupBonusStatus: service.PromoTopupBonusStatusPending,
+		}))
+	}
+	count, err := repo.CountUsagesByPromoCodeAndUser(ctx, promo.ID, user.ID)
+	require.NoError(t, err)
+	require.Equal(t, 2, count)
+
+	tx, err := client.Tx(ctx)
+	require.NoError(t, err)
+	defer func() { _ = tx.Rollback() }()
+	txCtx := dbent.NewTxContext(ctx, tx)
+
+	pending, err := repo.ListPendingTopupUsagesForUpdate(txCtx, user.ID)
+	require.NoError(t, err)
+	require.Len(t, pending, 2)
+	require.NotNil(t, pending[0].PromoCode)
+	require.Equal(t, promo.ID, pending[0].PromoCode.ID)
+
+	require.NoError(t, repo.MarkTopupBonusGranted(txCtx, pending[0].ID, 5*money.USD, time.Now()))
+	require.NoError(t, tx.Commit())
+
+	usages, err := repo.ListUsagesByUser(ctx, user.ID)
+	require.NoError(t, err)
+	require.Len(t, usages, 2)
+	require.Equal(t, service.PromoTopupBonusStatusGranted, usages[0].TopupBonusStatus)
+	require.Equal(t, 5*money.USD, usages[0].TopupBonusAmount)
+	require.NotNil(t, usages[0].TopupGrantedAt)
+	require.Equal(t, service.PromoTopupBonusStatusPending, usages[1].TopupBonusStatus)
+
+	// 清空规则字段
+	got.AllowedEmailDomains = nil
+	got.RequiredAttributes = nil
+	require.NoError(t, repo.Update(ctx, got))
+	got, err = repo.GetByID(ctx, promo.ID)
+	require.NoError(t, err)
+	require.Empty(t, got.AllowedEmailDomains)
+	require.Empty(t, got.RequiredAttributes)
+}
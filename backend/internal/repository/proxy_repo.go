@@ -227,12 +227,21 @@ func (r *proxyRepository) ExistsByHostPortAuth(ctx context.Context, host string,
 	}
 	if password == "" {
 		q = q.Where(proxy.Or(proxy.PasswordIsNil(), proxy.PasswordEQ("")))
-	} else {
-		q = q.Where(proxy.PasswordEQ(password))
+		count, err := q.Count(ctx)
+		return count > 0, err
 	}
 
-	count, err := q.Count(ctx)
-	return count > 0, err
+	// 密码可能已加密存储（随机 nonce，无法在 SQL 中等值比较），查询结果已被透明解密，在内存中比对
+	proxies, err := q.Where(proxy.PasswordNotNil()).All(ctx)
+	if err != nil {
+		return false, err
+	}
+	for _, p := range proxies {
+		if p.Password != nil && *p.Password == password {
+			return true, nil
+		}
+	}
+	return false, nil
 }
 
 // CountAccountsByProxyID returns the number of accounts using a specific proxy
@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/service"
+	"github.com/lib/pq"
+)
+
+type recoveryCodeRepository struct {
+	sql sqlExecutor
+}
+
+// NewRecoveryCodeRepository 创建恢复码仓储
+func NewRecoveryCodeRepository(sqlDB *sql.DB) service.RecoveryCodeRepository {
+	return &recoveryCodeRepository{sql: sqlDB}
+}
+
+// Replace 在同一条语句内删除旧码并写入新码，避免出现新旧码并存或全部丢失的中间状态
+func (r *recoveryCodeRepository) Replace(ctx context.Context, userID int64, codeHashesEncrypted []string) error {
+	_, err := r.sql.ExecContext(ctx, `
+		WITH deleted AS (
+			DELETE FROM user_recovery_codes WHERE user_id = $1
+		)
+		INSERT INTO user_recovery_codes (user_id, code_hash_encrypted)
+		SELECT $1, code FROM unnest($2::text[]) AS code
+	`, userID, pq.Array(codeHashesEncrypted))
+	return err
+}
+
+func (r *recoveryCodeRepository) ListByUser(ctx context.Context, userID int64) ([]service.RecoveryCode, error) {
+	rows, err := r.sql.QueryContext(ctx, `
+		SELECT id, user_id, code_hash_encrypted, used_at, created_at
+		FROM user_recovery_codes
+		WHERE user_id = $1
+		ORDER BY id
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	out := make([]service.RecoveryCode, 0)
+	for rows.Next() {
+		var (
+			code   service.RecoveryCode
+			usedAt sql.NullTime
+		)
+		if err := rows.Scan(&code.ID, &code.UserID, &code.CodeHashEncrypted, &usedAt, &code.CreatedAt); err != nil {
+			return nil, err
+		}
+		if usedAt.Valid {
+			code.UsedAt = &usedAt.Time
+		}
+		out = append(out, code)
+	}
+	return out, rows.Err()
+}
+
+func (r *recoveryCodeRepository) MarkUsed(ctx context.Context, id int64, usedAt time.Time) error {
+	result, err := r.sql.ExecContext(ctx, `
+		UPDATE user_recovery_codes SET used_at = $2 WHERE id = $1 AND used_at IS NULL
+	`, id, usedAt)
+	if err != nil {
+		return err
+	}
+	return requireAffected(result, service.ErrRecoveryCodeInvalid)
+}
@@ -9,6 +9,7 @@ import (
 	dbent "github.com/Wei-Shaw/sub2api/ent"
 	"github.com/Wei-Shaw/sub2api/ent/redeemcode"
 	"github.com/Wei-Shaw/sub2api/ent/user"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
 	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
 	"github.com/Wei-Shaw/sub2api/internal/service"
 )
@@ -247,9 +248,9 @@ func (r *redeemCodeRepository) ListByUserPaginated(ctx context.Context, userID i
 }
 
 // SumPositiveBalanceByUser returns total recharged amount (sum of value > 0 where type is balance/admin_balance).
-func (r *redeemCodeRepository) SumPositiveBalanceByUser(ctx context.Context, userID int64) (float64, error) {
+func (r *redeemCodeRepository) SumPositiveBalanceByUser(ctx context.Context, userID int64) (money.Amount, error) {
 	var result []struct {
-		Sum float64 `json:"sum"`
+		Sum money.Amount `json:"sum"`
 	}
 	err := r.client.RedeemCode.Query().
 		Where(
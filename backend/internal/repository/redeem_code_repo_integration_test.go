@@ -8,6 +8,7 @@ import (
 	"time"
 
 	dbent "github.com/Wei-Shaw/sub2api/ent"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
 	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
 	"github.com/Wei-Shaw/sub2api/internal/service"
 	"github.com/stretchr/testify/suite"
@@ -54,7 +55,7 @@ func (s *RedeemCodeRepoSuite) TestCreate() {
 	code := &service.RedeemCode{
 		Code:   "TEST-CREATE",
 		Type:   service.RedeemTypeBalance,
-		Value:  100,
+		Value:  100 * money.USD,
 		Status: service.StatusUnused,
 	}
 
@@ -69,8 +70,8 @@ func (s *RedeemCodeRepoSuite) TestCreate() {
 
 func (s *RedeemCodeRepoSuite) TestCreateBatch() {
 	codes := []service.RedeemCode{
-		{Code: "BATCH-1", Type: service.RedeemTypeBalance, Value: 10, Status: service.StatusUnused},
-		{Code: "BATCH-2", Type: service.RedeemTypeBalance, Value: 20, Status: service.StatusUnused},
+		{Code: "BATCH-1", Type: service.RedeemTypeBalance, Value: 10 * money.USD, Status: service.StatusUnused},
+		{Code: "BATCH-2", Type: service.RedeemTypeBalance, Value: 20 * money.USD, Status: service.StatusUnused},
 	}
 
 	err := s.repo.CreateBatch(s.ctx, codes)
@@ -78,11 +79,11 @@ func (s *RedeemCodeRepoSuite) TestCreateBatch() {
 
 	got1, err := s.repo.GetByCode(s.ctx, "BATCH-1")
 	s.Require().NoError(err)
-	s.Require().Equal(float64(10), got1.Value)
+	s.Require().Equal(10*money.USD, got1.Value)
 
 	got2, err := s.repo.GetByCode(s.ctx, "BATCH-2")
 	s.Require().NoError(err)
-	s.Require().Equal(float64(20), got2.Value)
+	s.Require().Equal(20*money.USD, got2.Value)
 }
 
 func (s *RedeemCodeRepoSuite) TestGetByID_NotFound() {
@@ -202,18 +203,18 @@ func (s *RedeemCodeRepoSuite) TestUpdate() {
 	code := &service.RedeemCode{
 		Code:   "UPDATE-ME",
 		Type:   service.RedeemTypeBalance,
-		Value:  10,
+		Value:  10 * money.USD,
 		Status: service.StatusUnused,
 	}
 	s.Require().NoError(s.repo.Create(s.ctx, code))
 
-	code.Value = 50
+	code.Value = 50 * money.USD
 	err := s.repo.Update(s.ctx, code)
 	s.Require().NoError(err, "Update")
 
 	got, err := s.repo.GetByID(s.ctx, code.ID)
 	s.Require().NoError(err)
-	s.Require().Equal(float64(50), got.Value)
+	s.Require().Equal(50*money.USD, got.Value)
 }
 
 // --- Use ---
@@ -350,7 +351,7 @@ func (s *RedeemCodeRepoSuite) TestCreateBatch_Filters_Use_Idempotency_ListByUser
 	groupID := group.ID
 
 	codes := []service.RedeemCode{
-		{Code: "CODEA", Type: service.RedeemTypeBalance, Value: 1, Status: service.StatusUnused, Notes: ""},
+		{Code: "CODEA", Type: service.RedeemTypeBalance, Value: money.USD, Status: service.StatusUnused, Notes: ""},
 		{Code: "CODEB", Type: service.RedeemTypeSubscription, Value: 0, Status: service.StatusUnused, Notes: "", GroupID: &groupID, ValidityDays: 7},
 	}
 	s.Require().NoError(s.repo.CreateBatch(s.ctx, codes), "CreateBatch")
@@ -0,0 +1,631 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	dbent "github.com/Wei-Shaw/sub2api/ent"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/timezone"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+)
+
+type referralRepository struct {
+	sql sqlExecutor
+}
+
+// NewReferralRepository 创建推荐返佣仓储
+func NewReferralRepository(sqlDB *sql.DB) service.ReferralRepository {
+	return &referralRepository{sql: sqlDB}
+}
+
+// q 返回当前上下文的执行器：在事务上下文中与余额变更同事务提交
+func (r *referralRepository) q(ctx context.Context) sqlExecutor {
+	if tx := dbent.TxFromContext(ctx); tx != nil {
+		return tx.Client()
+	}
+	return r.sql
+}
+
+// ---------- 推荐码 ----------
+
+func (r *referralRepository) GetCodeByUserID(ctx context.Context, userID int64) (string, error) {
+	var code string
+	err := scanSingleRow(ctx, r.q(ctx), `SELECT code FROM referral_codes WHERE user_id = $1`, []any{userID}, &code)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", service.ErrReferralCodeNotFound
+	}
+	return code, err
+}
+
+func (r *referralRepository) CreateCode(ctx context.Context, userID int64, code string) (bool, error) {
+	result, err := r.q(ctx).ExecContext(ctx, `
+		INSERT INTO referral_codes (user_id, code) VALUES ($1, $2)
+		ON CONFLICT DO NOTHING
+	`, userID, code)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+func (r *referralRepository) GetUserIDByCode(ctx context.Context, code string) (int64, error) {
+	var userID int64
+	err := scanSingleRow(ctx, r.q(ctx), `SELECT user_id FROM referral_codes WHERE code = $1`, []any{code}, &userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, service.ErrReferralCodeNotFound
+	}
+	return userID, err
+}
+
+// ---------- 推荐关系 ----------
+
+const referralRelationColumns = `
+	rr.id, rr.referrer_id, rr.referee_id, rr.code, rr.status, rr.flag_reason, rr.register_ip, rr.register_user_agent,
+	rr.commission_until, rr.settled_until, rr.bonus_paid_at, rr.created_at, rr.updated_at`
+
+func scanReferralRelation(scan func(dest ...any) error, extra ...any) (*service.ReferralRelation, error) {
+	var rel service.ReferralRelation
+	var commissionUntil, bonusPaidAt sql.NullTime
+	dest := []any{
+		&rel.ID, &rel.ReferrerID, &rel.RefereeID, &rel.Code, &rel.Status, &rel.FlagReason, &rel.RegisterIP, &rel.RegisterUserAgent,
+		&commissionUntil, &rel.SettledUntil, &bonusPaidAt, &rel.CreatedAt, &rel.UpdatedAt,
+	}
+	if err := scan(append(dest, extra...)...); err != nil {
+		return nil, err
+	}
+	rel.CommissionUntil = nullTimePtr(commissionUntil)
+	rel.BonusPaidAt = nullTimePtr(bonusPaidAt)
+	return &rel, nil
+}
+
+func (r *referralRepository) CreateRelation(ctx context.Context, rel *service.ReferralRelation) error {
+	err := scanSingleRow(ctx, r.q(ctx), `
+		INSERT INTO referral_relations
+			(referrer_id, referee_id, code, status, flag_reason, register_ip, register_user_agent, commission_until, settled_until)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (referee_id) DO NOTHING
+		RETURNING id, created_at, updated_at
+	`, []any{
+		rel.ReferrerID, rel.RefereeID, rel.Code, rel.Status, rel.FlagReason, rel.RegisterIP, rel.RegisterUserAgent,
+		rel.CommissionUntil, rel.SettledUntil,
+	}, &rel.ID, &rel.CreatedAt, &rel.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return service.ErrReferralAlreadyBound
+	}
+	return err
+}
+
+func (r *referralRepository) getRelation(ctx context.Context, id int64, forUpdate bool) (*service.ReferralRelation, error) {
+	query := `SELECT ` + referralRelationColumns + ` FROM referral_relations rr WHERE rr.id = $1`
+	if forUpdate {
+		query += ` FOR UPDATE`
+	}
+	rows, err := r.q(ctx).QueryContext(ctx, query, id)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+		return nil, service.ErrReferralRelationNotFound
+	}
+	rel, err := scanReferralRelation(rows.Scan)
+	if err != nil {
+		return nil, err
+	}
+	return rel, rows.Err()
+}
+
+func (r *referralRepository) GetRelation(ctx context.Context, id int64) (*service.ReferralRelation, error) {
+	return r.getRelation(ctx, id, false)
+}
+
+func (r *referralRepository) GetRelationForUpdate(ctx context.Context, id int64) (*service.ReferralRelation, error) {
+	return r.getRelation(ctx, id, true)
+}
+
+// ListRelations 列表附带被推荐人的累计充值（余额兑换码）、消费（按天预聚合表）与返佣合计
+func (r *referralRepository) ListRelations(ctx context.Context, params pagination.PaginationParams, filter service.ReferralRelationFilter) ([]service.ReferralRelation, *pagination.PaginationResult, error) {
+	conds := []string{"1=1"}
+	args := []any{}
+	if filter.ReferrerID > 0 {
+		args = append(args, filter.ReferrerID)
+		conds = append(conds, fmt.Sprintf("rr.referrer_id = $%d", len(args)))
+	}
+	if filter.RefereeID > 0 {
+		args = append(args, filter.RefereeID)
+		conds = append(conds, fmt.Sprintf("rr.referee_id = $%d", len(args)))
+	}
+	if filter.Status != "" {
+		args = append(args, filter.Status)
+		conds = append(conds, fmt.Sprintf("rr.status = $%d", len(args)))
+	}
+	where := strings.Join(conds, " AND ")
+
+	var total int64
+	if err := scanSingleRow(ctx, r.q(ctx), `SELECT COUNT(*) FROM referral_relations rr WHERE `+where, args, &total); err != nil {
+		return nil, nil, err
+	}
+
+	args = append(args, service.RedeemTypeBalance, params.Limit(), params.Offset())
+	n := len(args)
+	rows, err := r.q(ctx).QueryContext(ctx, `
+		SELECT `+referralRelationColumns+`,
+			COALESCE(ur.email, ''),
+			COALESCE(ue.email, ''),
+			COALESCE((
+				SELECT SUM(rc.value) FROM redeem_codes rc
+				WHERE rc.used_by = rr.referee_id AND rc.type = $`+fmt.Sprint(n-2)+` AND rc.value > 0
+			), 0),
+			COALESCE((
+				SELECT SUM(d.actual_cost) FROM usage_dashboard_daily_api_keys d WHERE d.user_id = rr.referee_id
+			), 0),
+			COALESCE((
+				SELECT SUM(c.amount) FROM referral_commissions c WHERE c.relation_id = rr.id
+			), 0)
+		FROM referral_relations rr
+		LEFT JOIN users ur ON ur.id = rr.referrer_id
+		LEFT JOIN users ue ON ue.id = rr.referee_id
+		WHERE `+where+`
+		ORDER BY rr.id DESC
+		LIMIT $`+fmt.Sprint(n-1)+` OFFSET $`+fmt.Sprint(n),
+		args...)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	out := make([]service.ReferralRelation, 0)
+	for rows.Next() {
+		var referrerEmail, refereeEmail string
+		var recharge, usage, commission money.Amount
+		rel, err := scanReferralRelation(rows.Scan, &referrerEmail, &refereeEmail, &recharge, &usage, &commission)
+		if err != nil {
+			return nil, nil, err
+		}
+		rel.ReferrerEmail = referrerEmail
+		rel.RefereeEmail = refereeEmail
+		rel.RefereeRecharge = recharge
+		rel.RefereeUsage = usage
+		rel.Commission = commission
+		out = append(out, *rel)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+	return out, paginationResultFromTotal(total, params), nil
+}
+
+func (r *referralRepository) UpdateRelationStatus(ctx context.Context, id int64, status, flagReason string) error {
+	result, err := r.q(ctx).ExecContext(ctx, `
+		UPDATE referral_relations SET status = $2, flag_reason = $3, updated_at = NOW() WHERE id = $1
+	`, id, status, flagReason)
+	if err != nil {
+		return err
+	}
+	return requireAffected(result, service.ErrReferralRelationNotFound)
+}
+
+func (r *referralRepository) ListSettleDue(ctx context.Context, before time.Time, limit int) ([]service.ReferralRelation, error) {
+	rows, err := r.q(ctx).QueryContext(ctx, `
+		SELECT `+referralRelationColumns+`
+		FROM referral_relations rr
+		WHERE rr.status = $1
+			AND rr.settled_until < $2
+			AND (rr.commission_until IS NULL OR rr.settled_until < rr.commission_until)
+		ORDER BY rr.settled_until ASC, rr.id ASC
+		LIMIT $3
+	`, service.ReferralStatusActive, before, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	out := make([]service.ReferralRelation, 0)
+	for rows.Next() {
+		rel, err := scanReferralRelation(rows.Scan)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *rel)
+	}
+	return out, rows.Err()
+}
+
+func (r *referralRepository) AdvanceSettlement(ctx context.Context, id int64, settledUntil time.Time, bonusPaid bool) error {
+	result, err := r.q(ctx).ExecContext(ctx, `
+		UPDATE referral_relations
+		SET settled_until = GREATEST(settled_until, $2),
+			bonus_paid_at = CASE WHEN $3 THEN COALESCE(bonus_paid_at, NOW()) ELSE bonus_paid_at END,
+			updated_at = NOW()
+		WHERE id = $1
+	`, id, settledUntil, bonusPaid)
+	if err != nil {
+		return err
+	}
+	return requireAffected(result, service.ErrReferralRelationNotFound)
+}
+
+// CheckOrigin 与推荐人的成功登录记录比对注册来源，并统计同注册 IP 的已有被推荐人
+func (r *referralRepository) CheckOrigin(ctx context.Context, referrerID int64, ip, userAgent string) (*service.ReferralOriginMatch, error) {
+	var m service.ReferralOriginMatch
+	err := scanSingleRow(ctx, r.q(ctx), `
+		SELECT
+			$2 <> '' AND EXISTS (SELECT 1 FROM login_history WHERE user_id = $1 AND success AND ip = $2),
+			$3 <> '' AND EXISTS (SELECT 1 FROM login_history WHERE user_id = $1 AND success AND user_agent = $3),
+			CASE WHEN $2 = '' THEN 0 ELSE (SELECT COUNT(*) FROM referral_relations WHERE referrer_id = $1 AND register_ip = $2) END
+	`, []any{referrerID, ip, userAgent}, &m.SameIP, &m.SameDevice, &m.SameIPReferrals)
+	if err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// ---------- 结算来源 ----------
+
+// paidRechargeFilter 只统计 payment 分类的余额兑换码（内置支付订单与外部支付对接发放），
+// 积分商城、后台生成或赠送的余额兑换码不算充值；判定与 RedeemCode.IsPaidTopup 一致
+const paidRechargeFilter = `used_by = $1 AND type = $2 AND value > 0 AND used_at >= $3 AND used_at < $4
+		AND notes LIKE $5`
+
+func paidRechargeArgs(refereeID int64, start, end time.Time) []any {
+	return []any{
+		refereeID, service.RedeemTypeBalance, start, end,
+		service.EncodeRedeemNotes("", service.PaymentRedeemCategory) + "%",
+	}
+}
+
+func (r *referralRepository) ListRechargeSources(ctx context.Context, refereeID int64, start, end time.Time) ([]service.ReferralSettleSource, error) {
+	rows, err := r.q(ctx).QueryContext(ctx, `
+		SELECT id, value
+		FROM redeem_codes
+		WHERE `+paidRechargeFilter+`
+		ORDER BY id
+	`, paidRechargeArgs(refereeID, start, end)...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	out := make([]service.ReferralSettleSource, 0)
+	for rows.Next() {
+		var id int64
+		var src service.ReferralSettleSource
+		if err := rows.Scan(&id, &src.Amount); err != nil {
+			return nil, err
+		}
+		src.Ref = fmt.Sprintf("redeem:%d", id)
+		out = append(out, src)
+	}
+	return out, rows.Err()
+}
+
+func (r *referralRepository) SumRecharge(ctx context.Context, refereeID int64, start, end time.Time) (money.Amount, error) {
+	var total money.Amount
+	err := scanSingleRow(ctx, r.q(ctx), `
+		SELECT COALESCE(SUM(value), 0)
+		FROM redeem_codes
+		WHERE `+paidRechargeFilter+`
+	`, paidRechargeArgs(refereeID, start, end), &total)
+	return total, err
+}
+
+func (r *referralRepository) SumUsage(ctx context.Context, refereeID int64, start, end time.Time) (money.Amount, error) {
+	var total money.Amount
+	err := scanSingleRow(ctx, r.q(ctx), `
+		SELECT COALESCE(SUM(actual_cost), 0)
+		FROM usage_logs
+		WHERE user_id = $1 AND billing_type = $2 AND created_at >= $3 AND created_at < $4
+	`, []any{refereeID, service.BillingTypeBalance, start, end}, &total)
+	return total, err
+}
+
+// ---------- 返佣记录 ----------
+
+func (r *referralRepository) CreateCommission(ctx context.Context, c *service.ReferralCommission) (bool, error) {
+	err := scanSingleRow(ctx, r.q(ctx), `
+		INSERT INTO referral_commissions
+			(relation_id, referrer_id, referee_id, kind, source_ref, base_amount, rate, amount, payout)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (relation_id, source_ref) DO NOTHING
+		RETURNING id, created_at
+	`, []any{c.RelationID, c.ReferrerID, c.RefereeID, c.Kind, c.SourceRef, c.BaseAmount, c.Rate, c.Amount, c.Payout}, &c.ID, &c.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (r *referralRepository) ListCommissions(ctx context.Context, params pagination.PaginationParams, filter service.ReferralCommissionFilter) ([]service.ReferralCommission, *pagination.PaginationResult, error) {
+	conds := []string{"1=1"}
+	args := []any{}
+	if filter.ReferrerID > 0 {
+		args = append(args, filter.ReferrerID)
+		conds = append(conds, fmt.Sprintf("c.referrer_id = $%d", len(args)))
+	}
+	if filter.Kind != "" {
+		args = append(args, filter.Kind)
+		conds = append(conds, fmt.Sprintf("c.kind = $%d", len(args)))
+	}
+	where := strings.Join(conds, " AND ")
+
+	var total int64
+	if err := scanSingleRow(ctx, r.q(ctx), `SELECT COUNT(*) FROM referral_commissions c WHERE `+where, args, &total); err != nil {
+		return nil, nil, err
+	}
+
+	args = append(args, params.Limit(), params.Offset())
+	rows, err := r.q(ctx).QueryContext(ctx, fmt.Sprintf(`
+		SELECT c.id, c.relation_id, c.referrer_id, c.referee_id, c.kind, c.source_ref, c.base_amount, c.rate, c.amount, c.payout, c.created_at,
+			COALESCE(u.email, '')
+		FROM referral_commissions c
+		LEFT JOIN users u ON u.id = c.referee_id
+		WHERE %s
+		ORDER BY c.id DESC
+		LIMIT $%d OFFSET $%d
+	`, where, len(args)-1, len(args)), args...)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	out := make([]service.ReferralCommission, 0)
+	for rows.Next() {
+		var c service.ReferralCommission
+		if err := rows.Scan(
+			&c.ID, &c.RelationID, &c.ReferrerID, &c.RefereeID, &c.Kind, &c.SourceRef, &c.BaseAmount, &c.Rate, &c.Amount, &c.Payout, &c.CreatedAt,
+			&c.RefereeEmail,
+		); err != nil {
+			return nil, nil, err
+		}
+		out = append(out, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+	return out, paginationResultFromTotal(total, params), nil
+}
+
+func (r *referralRepository) GetSummary(ctx context.Context, referrerID int64) (*service.ReferralSummary, error) {
+	var s service.ReferralSummary
+	err := scanSingleRow(ctx, r.q(ctx), `
+		SELECT
+			COUNT(*),
+			COUNT(*) FILTER (WHERE status = $2),
+			COUNT(*) FILTER (WHERE status = $3),
+			COALESCE((SELECT SUM(amount) FROM referral_commissions WHERE referrer_id = $1), 0)
+		FROM referral_relations
+		WHERE referrer_id = $1
+	`, []any{referrerID, service.ReferralStatusActive, service.ReferralStatusFlagged},
+		&s.TotalReferrals, &s.ActiveReferrals, &s.FlaggedReferrals, &s.TotalCommission)
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// DailyStats 按应用时区的自然日汇总：新增被推荐人、被推荐人充值（兑换记录）、消费（按天预聚合表）与返佣
+func (r *referralRepository) DailyStats(ctx context.Context, referrerID int64, start, end time.Time) ([]service.ReferralDailyStat, error) {
+	rows, err := r.q(ctx).QueryContext(ctx, `
+		WITH referees AS (
+			SELECT referee_id FROM referral_relations WHERE referrer_id = $1
+		), buckets AS (
+			SELECT (created_at AT TIME ZONE $6)::date AS d, 1::numeric AS signups, 0::numeric AS recharge, 0::numeric AS usage, 0::numeric AS commission
+			FROM referral_relations
+			WHERE referrer_id = $1 AND created_at >= $2 AND created_at < $3
+			UNION ALL
+			SELECT (used_at AT TIME ZONE $6)::date, 0, value, 0, 0
+			FROM redeem_codes
+			WHERE used_by IN (SELECT referee_id FROM referees) AND type = $7 AND value > 0 AND used_at >= $2 AND used_at < $3
+			UNION ALL
+			SELECT bucket_date, 0, 0, actual_cost, 0
+			FROM usage_dashboard_daily_api_keys
+			WHERE user_id IN (SELECT referee_id FROM referees) AND bucket_date >= $4::date AND bucket_date < $5::date
+			UNION ALL
+			SELECT (created_at AT TIME ZONE $6)::date, 0, 0, 0, amount
+			FROM referral_commissions
+			WHERE referrer_id = $1 AND created_at >= $2 AND created_at < $3
+		)
+		SELECT TO_CHAR(d, 'YYYY-MM-DD'), SUM(signups)::bigint, SUM(recharge), SUM(usage), SUM(commission)
+		FROM buckets
+		GROUP BY d
+		ORDER BY d
+	`, referrerID, start, end, dashboardBucketDate(start), dashboardBucketDate(end), timezone.Name(), service.RedeemTypeBalance)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	out := make([]service.ReferralDailyStat, 0)
+	for rows.Next() {
+		var d service.ReferralDailyStat
+		if err := rows.Scan(&d.Date, &d.Signups, &d.RefereeRecharge, &d.RefereeUsage, &d.Commission); err != nil {
+			return nil, err
+		}
+		out = append(out, d)
+	}
+	return out, rows.Err()
+}
+
+// ---------- 佣金钱包与提现 ----------
+
+func (r *referralRepository) GetWallet(ctx context.Context, userID int64) (*service.ReferralWallet, error) {
+	w := service.ReferralWallet{UserID: userID}
+	err := scanSingleRow(ctx, r.q(ctx), `
+		SELECT balance, total_earned, total_withdrawn, updated_at FROM referral_wallets WHERE user_id = $1
+	`, []any{userID}, &w.Balance, &w.TotalEarned, &w.TotalWithdrawn, &w.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return &w, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &w, nil
+}
+
+func (r *referralRepository) CreditWallet(ctx context.Context, userID int64, amount money.Amount) error {
+	_, err := r.q(ctx).ExecContext(ctx, `
+		INSERT INTO referral_wallets (user_id, balance, total_earned) VALUES ($1, $2, $2)
+		ON CONFLICT (user_id) DO UPDATE
+		SET balance = referral_wallets.balance + EXCLUDED.balance,
+			total_earned = referral_wallets.total_earned + EXCLUDED.total_earned,
+			updated_at = NOW()
+	`, userID, amount)
+	return err
+}
+
+func (r *referralRepository) DebitWallet(ctx context.Context, userID int64, amount money.Amount) (bool, error) {
+	result, err := r.q(ctx).ExecContext(ctx, `
+		UPDATE referral_wallets
+		SET balance = balance - $2, total_withdrawn = total_withdrawn + $2, updated_at = NOW()
+		WHERE user_id = $1 AND balance >= $2
+	`, userID, amount)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+func (r *referralRepository) RestoreWallet(ctx context.Context, userID int64, amount money.Amount) error {
+	result, err := r.q(ctx).ExecContext(ctx, `
+		UPDATE referral_wallets
+		SET balance = balance + $2, total_withdrawn = total_withdrawn - $2, updated_at = NOW()
+		WHERE user_id = $1
+	`, userID, amount)
+	if err != nil {
+		return err
+	}
+	return requireAffected(result, service.ErrReferralWithdrawalNotFound)
+}
+
+func (r *referralRepository) CreateWithdrawal(ctx context.Context, w *service.ReferralWithdrawal) error {
+	var processedAt sql.NullTime
+	err := scanSingleRow(ctx, r.q(ctx), `
+		INSERT INTO referral_withdrawals (user_id, amount, method, account, status, processed_at)
+		VALUES ($1, $2, $3, $4, $5, CASE WHEN $5 = $6 THEN NULL ELSE NOW() END)
+		RETURNING id, processed_at, created_at
+	`, []any{w.UserID, w.Amount, w.Method, w.Account, w.Status, service.ReferralWithdrawalStatusPending}, &w.ID, &processedAt, &w.CreatedAt)
+	if err != nil {
+		return err
+	}
+	w.ProcessedAt = nullTimePtr(processedAt)
+	return nil
+}
+
+const referralWithdrawalColumns = `
+	w.id, w.user_id, w.amount, w.method, w.account, w.status, w.admin_notes, w.operator_id, w.processed_at, w.created_at`
+
+func scanReferralWithdrawal(scan func(dest ...any) error, extra ...any) (*service.ReferralWithdrawal, error) {
+	var w service.ReferralWithdrawal
+	var operatorID sql.NullInt64
+	var processedAt sql.NullTime
+	dest := []any{&w.ID, &w.UserID, &w.Amount, &w.Method, &w.Account, &w.Status, &w.AdminNotes, &operatorID, &processedAt, &w.CreatedAt}
+	if err := scan(append(dest, extra...)...); err != nil {
+		return nil, err
+	}
+	w.OperatorID = nullInt64Value(operatorID)
+	w.ProcessedAt = nullTimePtr(processedAt)
+	return &w, nil
+}
+
+func (r *referralRepository) GetWithdrawalForUpdate(ctx context.Context, id int64) (*service.ReferralWithdrawal, error) {
+	rows, err := r.q(ctx).QueryContext(ctx, `SELECT `+referralWithdrawalColumns+` FROM referral_withdrawals w WHERE w.id = $1 FOR UPDATE`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+		return nil, service.ErrReferralWithdrawalNotFound
+	}
+	w, err := scanReferralWithdrawal(rows.Scan)
+	if err != nil {
+		return nil, err
+	}
+	return w, rows.Err()
+}
+
+func (r *referralRepository) ListWithdrawals(ctx context.Context, params pagination.PaginationParams, filter service.ReferralWithdrawalFilter) ([]service.ReferralWithdrawal, *pagination.PaginationResult, error) {
+	conds := []string{"1=1"}
+	args := []any{}
+	if filter.UserID > 0 {
+		args = append(args, filter.UserID)
+		conds = append(conds, fmt.Sprintf("w.user_id = $%d", len(args)))
+	}
+	if filter.Status != "" {
+		args = append(args, filter.Status)
+		conds = append(conds, fmt.Sprintf("w.status = $%d", len(args)))
+	}
+	where := strings.Join(conds, " AND ")
+
+	var total int64
+	if err := scanSingleRow(ctx, r.q(ctx), `SELECT COUNT(*) FROM referral_withdrawals w WHERE `+where, args, &total); err != nil {
+		return nil, nil, err
+	}
+
+	args = append(args, params.Limit(), params.Offset())
+	rows, err := r.q(ctx).QueryContext(ctx, fmt.Sprintf(`
+		SELECT %s, COALESCE(u.email, '')
+		FROM referral_withdrawals w
+		LEFT JOIN users u ON u.id = w.user_id
+		WHERE %s
+		ORDER BY w.id DESC
+		LIMIT $%d OFFSET $%d
+	`, referralWithdrawalColumns, where, len(args)-1, len(args)), args...)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	out := make([]service.ReferralWithdrawal, 0)
+	for rows.Next() {
+		var email string
+		w, err := scanReferralWithdrawal(rows.Scan, &email)
+		if err != nil {
+			return nil, nil, err
+		}
+		w.UserEmail = email
+		out = append(out, *w)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+	return out, paginationResultFromTotal(total, params), nil
+}
+
+func (r *referralRepository) FinishWithdrawal(ctx context.Context, id int64, status, notes string, operatorID int64) (bool, error) {
+	result, err := r.q(ctx).ExecContext(ctx, `
+		UPDATE referral_withdrawals
+		SET status = $2, admin_notes = $3, operator_id = $4, processed_at = NOW()
+		WHERE id = $1 AND status = $5
+	`, id, status, notes, operatorID, service.ReferralWithdrawalStatusPending)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
@@ -0,0 +1,217 @@
+//go:build integration
+
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+	"github.com/stretchr/testify/require"
+)
+
+func createReferralTestUser(t *testing.T, ctx context.Context, prefix string) *service.User {
+	t.Helper()
+	userRepo := newUserRepositoryWithSQL(testEntClient(t), integrationDB)
+	user := &service.User{
+		Email:        uniqueTestValue(t, prefix) + "@example.com",
+		PasswordHash: "test-password-hash",
+		Role:         service.RoleUser,
+		Status:       service.StatusActive,
+	}
+	require.NoError(t, userRepo.Create(ctx, user))
+	return user
+}
+
+func TestReferralRepository_CodesAndRelations(t *testing.T) {
+	ctx := context.Background()
+	repo := NewReferralRepository(integrationDB)
+	referrer := createReferralTestUser(t, ctx, "referrer")
+	referee := createReferralTestUser(t, ctx, "referee")
+
+	code := strings.ToUpper(uniqueTestValue(t, "r"))
+	if len(code) > 16 {
+		code = code[len(code)-16:]
+	}
+	ok, err := repo.CreateCode(ctx, referrer.ID, code)
+	require.NoError(t, err)
+	require.True(t, ok)
+	ok, err = repo.CreateCode(ctx, referrer.ID, code+"X")
+	require.NoError(t, err)
+	require.False(t, ok, "user already has a code")
+
+	got, err := repo.GetCodeByUserID(ctx, referrer.ID)
+	require.NoError(t, err)
+	require.Equal(t, code, got)
+	userID, err := repo.GetUserIDByCode(ctx, code)
+	require.NoError(t, err)
+	require.Equal(t, referrer.ID, userID)
+
+	now := time.Now().Truncate(time.Microsecond)
+	rel := &service.ReferralRelation{
+		ReferrerID:   referrer.ID,
+		RefereeID:    referee.ID,
+		Code:         code,
+		Status:       service.ReferralStatusFlagged,
+		FlagReason:   service.ReferralFlagSameIP,
+		RegisterIP:   "203.0.113.9",
+		SettledUntil: now.Add(-time.Hour),
+	}
+	require.NoError(t, repo.CreateRelation(ctx, rel))
+	require.NotZero(t, rel.ID)
+	require.ErrorIs(t, repo.CreateRelation(ctx, &service.ReferralRelation{
+		ReferrerID: referrer.ID, RefereeID: referee.ID, Code: code, Status: service.ReferralStatusActive, SettledUntil: now,
+	}), service.ErrReferralAlreadyBound)
+
+	match, err := repo.CheckOrigin(ctx, referrer.ID, "203.0.113.9", "")
+	require.NoError(t, err)
+	require.Equal(t, int64(1), match.SameIPReferrals)
+
+	due, err := repo.ListSettleDue(ctx, now, 100)
+	require.NoError(t, err)
+	for _, d := range due {
+		require.NotEqual(t, rel.ID, d.ID, "flagged relations are not settled")
+	}
+
+	require.NoError(t, repo.UpdateRelationStatus(ctx, rel.ID, service.ReferralStatusActive, ""))
+	require.NoError(t, repo.AdvanceSettlement(ctx, rel.ID, now, true))
+	stored, err := repo.GetRelation(ctx, rel.ID)
+	require.NoError(t, err)
+	require.Equal(t, service.ReferralStatusActive, stored.Status)
+	require.True(t, stored.SettledUntil.Equal(now))
+	require.NotNil(t, stored.BonusPaidAt)
+
+	list, result, err := repo.ListRelations(ctx, pagination.PaginationParams{Page: 1, PageSize: 10}, service.ReferralRelationFilter{ReferrerID: referrer.ID})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), result.Total)
+	require.Equal(t, referee.Email, list[0].RefereeEmail)
+
+	_, err = repo.GetRelation(ctx, rel.ID+1_000_000)
+	require.ErrorIs(t, err, service.ErrReferralRelationNotFound)
+}
+
+func TestReferralRepository_CommissionsAndWallet(t *testing.T) {
+	ctx := context.Background()
+	repo := NewReferralRepository(integrationDB)
+	referrer := createReferralTestUser(t, ctx, "referrer")
+	referee := createReferralTestUser(t, ctx, "referee")
+
+	rel := &service.ReferralRelation{
+		ReferrerID:   referrer.ID,
+		RefereeID:    referee.ID,
+		Code:         "TESTCODE",
+		Status:       service.ReferralStatusActive,
+		SettledUntil: time.Now(),
+	}
+	require.NoError(t, repo.CreateRelation(ctx, rel))
+
+	entry := &service.ReferralCommission{
+		RelationID: rel.ID,
+		ReferrerID: referrer.ID,
+		RefereeID:  referee.ID,
+		Kind:       service.ReferralCommissionKindRecharge,
+		SourceRef:  "redeem:1",
+		BaseAmount: 10 * money.USD,
+		Rate:       0.1,
+		Amount:     1 * money.USD,
+		Payout:     service.ReferralPayoutWallet,
+	}
+	created, err := repo.CreateCommission(ctx, entry)
+	require.NoError(t, err)
+	require.True(t, created)
+	require.NotZero(t, entry.ID)
+	dup := *entry
+	created, err = repo.CreateCommission(ctx, &dup)
+	require.NoError(t, err)
+	require.False(t, created, "same source is only paid once")
+
+	summary, err := repo.GetSummary(ctx, referrer.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), summary.TotalReferrals)
+	require.Equal(t, 1*money.USD, summary.TotalCommission)
+
+	require.NoError(t, repo.CreditWallet(ctx, referrer.ID, 20*money.USD))
+	ok, err := repo.DebitWallet(ctx, referrer.ID, 50*money.USD)
+	require.NoError(t, err)
+	require.False(t, ok)
+	ok, err = repo.DebitWallet(ctx, referrer.ID, 15*money.USD)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	w := &service.ReferralWithdrawal{
+		UserID:  referrer.ID,
+		Amount:  15 * money.USD,
+		Method:  service.ReferralWithdrawalMethodManual,
+		Account: "bank:123",
+		Status:  service.ReferralWithdrawalStatusPending,
+	}
+	require.NoError(t, repo.CreateWithdrawal(ctx, w))
+	require.Nil(t, w.ProcessedAt)
+
+	ok, err = repo.FinishWithdrawal(ctx, w.ID, service.ReferralWithdrawalStatusRejected, "invalid account", referrer.ID)
+	require.NoError(t, err)
+	require.True(t, ok)
+	ok, err = repo.FinishWithdrawal(ctx, w.ID, service.ReferralWithdrawalStatusCompleted, "", referrer.ID)
+	require.NoError(t, err)
+	require.False(t, ok, "only pending withdrawals can be finished")
+	require.NoError(t, repo.RestoreWallet(ctx, referrer.ID, w.Amount))
+
+	wallet, err := repo.GetWallet(ctx, referrer.ID)
+	require.NoError(t, err)
+	require.Equal(t, 20*money.USD, wallet.Balance)
+	require.Equal(t, 20*money.USD, wallet.TotalEarned)
+	require.Zero(t, wallet.TotalWithdrawn)
+
+	processed, err := repo.GetWithdrawalForUpdate(ctx, w.ID)
+	require.NoError(t, err)
+	require.Equal(t, service.ReferralWithdrawalStatusRejected, processed.Status)
+	require.NotNil(t, processed.ProcessedAt)
+	require.NotNil(t, processed.OperatorID)
+}
+
+func TestReferralRepository_RechargeOnlyCountsPaidCodes(t *testing.T) {
+	ctx := context.Background()
+	repo := NewReferralRepository(integrationDB)
+	codeRepo := NewRedeemCodeRepository(testEntClient(t))
+	referee := createReferralTestUser(t, ctx, "recharge")
+	start := time.Now().Add(-time.Minute)
+	newCode := func(prefix string) string {
+		code, err := service.GenerateRedeemCode()
+		require.NoError(t, err)
+		return (prefix + code)[:32]
+	}
+
+	codes := []*service.RedeemCode{
+		{Code: newCode(service.PaymentRedeemCodePrefix), Category: service.PaymentRedeemCategory, Value: 10 * money.USD},
+		{Code: newCode(""), Category: "points_shop", Value: 5 * money.USD},
+		{Code: newCode(""), Value: 3 * money.USD},
+		// 后台生成的兑换码即使带有 PAY- 前缀也不算付费充值
+		{Code: newCode(service.PaymentRedeemCodePrefix), Value: 7 * money.USD},
+		// 外部支付对接（create-and-redeem）创建的兑换码带 payment 分类，计入充值
+		{Code: newCode("s2p_"), Category: service.PaymentRedeemCategory, Value: 4 * money.USD},
+	}
+	for _, code := range codes {
+		code.Type = service.RedeemTypeBalance
+		code.Status = service.StatusUnused
+		require.NoError(t, codeRepo.Create(ctx, code))
+		require.NoError(t, codeRepo.Use(ctx, code.ID, referee.ID))
+	}
+	end := time.Now().Add(time.Minute)
+
+	sources, err := repo.ListRechargeSources(ctx, referee.ID, start, end)
+	require.NoError(t, err)
+	require.Len(t, sources, 2)
+	require.Equal(t, fmt.Sprintf("redeem:%d", codes[0].ID), sources[0].Ref)
+	require.Equal(t, 10*money.USD, sources[0].Amount)
+	require.Equal(t, fmt.Sprintf("redeem:%d", codes[4].ID), sources[1].Ref)
+	require.Equal(t, 4*money.USD, sources[1].Amount)
+
+	total, err := repo.SumRecharge(ctx, referee.ID, start, end)
+	require.NoError(t, err)
+	require.Equal(t, 14*money.USD, total)
+}
@@ -65,6 +65,33 @@ func (c *refreshTokenCache) GetRefreshToken(ctx context.Context, tokenHash strin
 	return &data, nil
 }
 
+func (c *refreshTokenCache) GetRefreshTokens(ctx context.Context, tokenHashes []string) (map[string]*service.RefreshTokenData, error) {
+	result := make(map[string]*service.RefreshTokenData, len(tokenHashes))
+	if len(tokenHashes) == 0 {
+		return result, nil
+	}
+	keys := make([]string, 0, len(tokenHashes))
+	for _, hash := range tokenHashes {
+		keys = append(keys, refreshTokenKey(hash))
+	}
+	vals, err := c.rdb.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, err
+	}
+	for i, val := range vals {
+		raw, ok := val.(string)
+		if !ok {
+			continue
+		}
+		var data service.RefreshTokenData
+		if err := json.Unmarshal([]byte(raw), &data); err != nil {
+			return nil, fmt.Errorf("unmarshal refresh token data: %w", err)
+		}
+		result[tokenHashes[i]] = &data
+	}
+	return result, nil
+}
+
 func (c *refreshTokenCache) DeleteRefreshToken(ctx context.Context, tokenHash string) error {
 	key := refreshTokenKey(tokenHash)
 	return c.rdb.Del(ctx, key).Err()
@@ -147,6 +174,17 @@ func (c *refreshTokenCache) GetUserTokenHashes(ctx context.Context, userID int64
 	return c.rdb.SMembers(ctx, key).Result()
 }
 
+func (c *refreshTokenCache) RemoveFromUserTokenSet(ctx context.Context, userID int64, tokenHashes ...string) error {
+	if len(tokenHashes) == 0 {
+		return nil
+	}
+	members := make([]any, 0, len(tokenHashes))
+	for _, hash := range tokenHashes {
+		members = append(members, hash)
+	}
+	return c.rdb.SRem(ctx, userRefreshTokensKey(userID), members...).Err()
+}
+
 func (c *refreshTokenCache) GetFamilyTokenHashes(ctx context.Context, familyID string) ([]string, error) {
 	key := tokenFamilyKey(familyID)
 	return c.rdb.SMembers(ctx, key).Result()
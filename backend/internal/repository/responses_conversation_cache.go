@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/service"
+	"github.com/redis/go-redis/v9"
+)
+
+const responsesConversationKeyPrefix = "responses:conv:"
+
+// responsesConversationKey generates the Redis key for a stored conversation.
+func responsesConversationKey(apiKeyID int64, responseID string) string {
+	return fmt.Sprintf("%s%d:%s", responsesConversationKeyPrefix, apiKeyID, responseID)
+}
+
+type responsesConversationCache struct {
+	rdb *redis.Client
+}
+
+// NewResponsesConversationCache creates a new ResponsesConversationCache implementation.
+func NewResponsesConversationCache(rdb *redis.Client) service.ResponsesConversationCache {
+	return &responsesConversationCache{rdb: rdb}
+}
+
+func (c *responsesConversationCache) GetConversation(ctx context.Context, apiKeyID int64, responseID string) (*service.ResponsesConversation, error) {
+	data, err := c.rdb.Get(ctx, responsesConversationKey(apiKeyID, responseID)).Bytes()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, service.ErrResponsesConversationNotFound
+		}
+		return nil, fmt.Errorf("get responses conversation: %w", err)
+	}
+	var conv service.ResponsesConversation
+	if err := json.Unmarshal(data, &conv); err != nil {
+		return nil, fmt.Errorf("unmarshal responses conversation: %w", err)
+	}
+	return &conv, nil
+}
+
+func (c *responsesConversationCache) SetConversation(ctx context.Context, apiKeyID int64, responseID string, conv *service.ResponsesConversation, ttl time.Duration) error {
+	data, err := json.Marshal(conv)
+	if err != nil {
+		return fmt.Errorf("marshal responses conversation: %w", err)
+	}
+	return c.rdb.Set(ctx, responsesConversationKey(apiKeyID, responseID), data, ttl).Err()
+}
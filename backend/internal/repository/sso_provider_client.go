@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/config"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/httpclient"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+)
+
+const (
+	ssoProviderRequestTimeout = 15 * time.Second
+	ssoProviderMaxBodyBytes   = 1 << 20
+)
+
+type ssoProviderClient struct {
+	httpClient *http.Client
+}
+
+// NewSSOProviderClient 创建身份提供方 HTTP 客户端。
+// 企业内网 IdP（Keycloak、Authentik 等）常解析到私有地址，是否放行由 security.url_allowlist.allow_private_hosts 控制。
+func NewSSOProviderClient(cfg *config.Config) service.SSOProviderClient {
+	allowPrivate := false
+	if cfg != nil {
+		allowPrivate = cfg.Security.URLAllowlist.AllowPrivateHosts
+	}
+	sharedClient, err := httpclient.GetClient(httpclient.Options{
+		Timeout:            ssoProviderRequestTimeout,
+		ValidateResolvedIP: true,
+		AllowPrivateHosts:  allowPrivate,
+	})
+	if err != nil {
+		sharedClient = &http.Client{Timeout: ssoProviderRequestTimeout}
+	}
+	return &ssoProviderClient{httpClient: sharedClient}
+}
+
+func (c *ssoProviderClient) GetJSON(ctx context.Context, rawURL, bearerToken string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if bearerToken != "" {
+		req.Header.Set("Authorization", "Bearer "+bearerToken)
+	}
+	return c.do(req)
+}
+
+func (c *ssoProviderClient) ExchangeCode(ctx context.Context, in *service.SSOTokenRequest) (*service.SSOTokenResponse, error) {
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("client_id", in.ClientID)
+	form.Set("code", in.Code)
+	form.Set("redirect_uri", in.RedirectURI)
+	if in.CodeVerifier != "" {
+		form.Set("code_verifier", in.CodeVerifier)
+	}
+	if in.AuthMethod == service.SSOTokenAuthClientSecretPost {
+		form.Set("client_secret", in.ClientSecret)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, in.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if in.AuthMethod == service.SSOTokenAuthClientSecretBasic {
+		// RFC 6749 2.3.1：client_id/secret 需先做 form 编码
+		req.SetBasicAuth(url.QueryEscape(in.ClientID), url.QueryEscape(in.ClientSecret))
+	}
+
+	body, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	var payload struct {
+		AccessToken string `json:"access_token"`
+		TokenType   string `json:"token_type"`
+		IDToken     string `json:"id_token"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("decode token response: %w", err)
+	}
+	if payload.AccessToken == "" {
+		return nil, fmt.Errorf("token response missing access_token")
+	}
+	return &service.SSOTokenResponse{
+		AccessToken: payload.AccessToken,
+		TokenType:   payload.TokenType,
+		IDToken:     payload.IDToken,
+		ExpiresIn:   payload.ExpiresIn,
+	}, nil
+}
+
+func (c *ssoProviderClient) do(req *http.Request) ([]byte, error) {
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("send request: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, ssoProviderMaxBodyBytes))
+	if err != nil {
+		return nil, fmt.Errorf("read response: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("status %d: %s", resp.StatusCode, truncateSSOErrorBody(body))
+	}
+	return body, nil
+}
+
+func truncateSSOErrorBody(body []byte) string {
+	const maxLen = 512
+	s := strings.TrimSpace(string(body))
+	if len(s) > maxLen {
+		s = s[:maxLen]
+	}
+	return s
+}
@@ -0,0 +1,265 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	dbent "github.com/Wei-Shaw/sub2api/ent"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+)
+
+type ssoProviderRepository struct {
+	sql sqlExecutor
+}
+
+// NewSSOProviderRepository 创建 SSO 提供方仓储
+func NewSSOProviderRepository(sqlDB *sql.DB) service.SSOProviderRepository {
+	return &ssoProviderRepository{sql: sqlDB}
+}
+
+// q 返回当前上下文的执行器：自动开通时身份绑定与用户创建同事务提交
+func (r *ssoProviderRepository) q(ctx context.Context) sqlExecutor {
+	if tx := dbent.TxFromContext(ctx); tx != nil {
+		return tx.Client()
+	}
+	return r.sql
+}
+
+const ssoProviderColumns = `
+	id, slug, name, type, enabled,
+	issuer_url, authorize_url, token_url, userinfo_url, jwks_url,
+	client_id, client_secret_encrypted, scopes, token_auth_method, use_pkce, redirect_url, frontend_redirect_url,
+	subject_claim, email_claim, email_verified_claim, trust_email, username_claim, attribute_mappings,
+	allowed_email_domains, auto_provision, default_group_ids, link_existing_by_email, enforce_sso,
+	display_order, created_at, updated_at
+`
+
+// ssoProviderJSONColumns 将 JSONB 字段编码为 SQL 参数
+func ssoProviderJSONColumns(p *service.SSOProvider) (mappings, domains, groupIDs []byte, err error) {
+	attrs := p.AttributeMappings
+	if attrs == nil {
+		attrs = map[string]string{}
+	}
+	if mappings, err = json.Marshal(attrs); err != nil {
+		return nil, nil, nil, fmt.Errorf("marshal attribute_mappings: %w", err)
+	}
+	allowed := p.AllowedEmailDomains
+	if allowed == nil {
+		allowed = []string{}
+	}
+	if domains, err = json.Marshal(allowed); err != nil {
+		return nil, nil, nil, fmt.Errorf("marshal allowed_email_domains: %w", err)
+	}
+	groups := p.DefaultGroupIDs
+	if groups == nil {
+		groups = []int64{}
+	}
+	if groupIDs, err = json.Marshal(groups); err != nil {
+		return nil, nil, nil, fmt.Errorf("marshal default_group_ids: %w", err)
+	}
+	return mappings, domains, groupIDs, nil
+}
+
+func scanSSOProvider(scan func(dest ...any) error) (*service.SSOProvider, error) {
+	var (
+		p                           service.SSOProvider
+		mappings, domains, groupIDs []byte
+	)
+	if err := scan(
+		&p.ID, &p.Slug, &p.Name, &p.Type, &p.Enabled,
+		&p.IssuerURL, &p.AuthorizeURL, &p.TokenURL, &p.UserInfoURL, &p.JWKSURL,
+		&p.ClientID, &p.ClientSecretEncrypted, &p.Scopes, &p.TokenAuthMethod, &p.UsePKCE, &p.RedirectURL, &p.FrontendRedirectURL,
+		&p.SubjectClaim, &p.EmailClaim, &p.EmailVerifiedClaim, &p.TrustEmail, &p.UsernameClaim, &mappings,
+		&domains, &p.AutoProvision, &groupIDs, &p.LinkExistingByEmail, &p.EnforceSSO,
+		&p.DisplayOrder, &p.CreatedAt, &p.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(mappings, &p.AttributeMappings); err != nil {
+		return nil, fmt.Errorf("unmarshal attribute_mappings: %w", err)
+	}
+	if err := json.Unmarshal(domains, &p.AllowedEmailDomains); err != nil {
+		return nil, fmt.Errorf("unmarshal allowed_email_domains: %w", err)
+	}
+	if err := json.Unmarshal(groupIDs, &p.DefaultGroupIDs); err != nil {
+		return nil, fmt.Errorf("unmarshal default_group_ids: %w", err)
+	}
+	return &p, nil
+}
+
+func (r *ssoProviderRepository) Create(ctx context.Context, p *service.SSOProvider) error {
+	mappings, domains, groupIDs, err := ssoProviderJSONColumns(p)
+	if err != nil {
+		return err
+	}
+	err = scanSingleRow(ctx, r.q(ctx), `
+		INSERT INTO sso_providers (
+			slug, name, type, enabled,
+			issuer_url, authorize_url, token_url, userinfo_url, jwks_url,
+			client_id, client_secret_encrypted, scopes, token_auth_method, use_pkce, redirect_url, frontend_redirect_url,
+			subject_claim, email_claim, email_verified_claim, trust_email, username_claim, attribute_mappings,
+			allowed_email_domains, auto_provision, default_group_ids, link_existing_by_email, enforce_sso,
+			display_order
+		) VALUES (
+			$1, $2, $3, $4,
+			$5, $6, $7, $8, $9,
+			$10, $11, $12, $13, $14, $15, $16,
+			$17, $18, $19, $20, $21, $22,
+			$23, $24, $25, $26, $27,
+			$28
+		)
+		RETURNING id, created_at, updated_at
+	`, []any{
+		p.Slug, p.Name, p.Type, p.Enabled,
+		p.IssuerURL, p.AuthorizeURL, p.TokenURL, p.UserInfoURL, p.JWKSURL,
+		p.ClientID, p.ClientSecretEncrypted, p.Scopes, p.TokenAuthMethod, p.UsePKCE, p.RedirectURL, p.FrontendRedirectURL,
+		p.SubjectClaim, p.EmailClaim, p.EmailVerifiedClaim, p.TrustEmail, p.UsernameClaim, mappings,
+		domains, p.AutoProvision, groupIDs, p.LinkExistingByEmail, p.EnforceSSO,
+		p.DisplayOrder,
+	}, &p.ID, &p.CreatedAt, &p.UpdatedAt)
+	if isUniqueConstraintViolation(err) {
+		return service.ErrSSOProviderSlugExists
+	}
+	return err
+}
+
+func (r *ssoProviderRepository) Update(ctx context.Context, p *service.SSOProvider) error {
+	mappings, domains, groupIDs, err := ssoProviderJSONColumns(p)
+	if err != nil {
+		return err
+	}
+	err = scanSingleRow(ctx, r.q(ctx), `
+		UPDATE sso_providers SET
+			slug = $2, name = $3, type = $4, enabled = $5,
+			issuer_url = $6, authorize_url = $7, token_url = $8, userinfo_url = $9, jwks_url = $10,
+			client_id = $11, client_secret_encrypted = $12, scopes = $13, token_auth_method = $14, use_pkce = $15,
+			redirect_url = $16, frontend_redirect_url = $17,
+			subject_claim = $18, email_claim = $19, email_verified_claim = $20, trust_email = $21, username_claim = $22,
+			attribute_mappings = $23, allowed_email_domains = $24, auto_provision = $25, default_group_ids = $26,
+			link_existing_by_email = $27, enforce_sso = $28, display_order = $29,
+			updated_at = NOW()
+		WHERE id = $1
+		RETURNING updated_at
+	`, []any{
+		p.ID, p.Slug, p.Name, p.Type, p.Enabled,
+		p.IssuerURL, p.AuthorizeURL, p.TokenURL, p.UserInfoURL, p.JWKSURL,
+		p.ClientID, p.ClientSecretEncrypted, p.Scopes, p.TokenAuthMethod, p.UsePKCE,
+		p.RedirectURL, p.FrontendRedirectURL,
+		p.SubjectClaim, p.EmailClaim, p.EmailVerifiedClaim, p.TrustEmail, p.UsernameClaim,
+		mappings, domains, p.AutoProvision, groupIDs,
+		p.LinkExistingByEmail, p.EnforceSSO, p.DisplayOrder,
+	}, &p.UpdatedAt)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return service.ErrSSOProviderNotFound
+	case isUniqueConstraintViolation(err):
+		return service.ErrSSOProviderSlugExists
+	}
+	return err
+}
+
+func (r *ssoProviderRepository) Delete(ctx context.Context, id int64) error {
+	result, err := r.q(ctx).ExecContext(ctx, `DELETE FROM sso_providers WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	return requireAffected(result, service.ErrSSOProviderNotFound)
+}
+
+func (r *ssoProviderRepository) getOne(ctx context.Context, where string, arg any) (*service.SSOProvider, error) {
+	rows, err := r.q(ctx).QueryContext(ctx, `SELECT `+ssoProviderColumns+` FROM sso_providers WHERE `+where, arg)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+		return nil, service.ErrSSOProviderNotFound
+	}
+	return scanSSOProvider(rows.Scan)
+}
+
+func (r *ssoProviderRepository) GetByID(ctx context.Context, id int64) (*service.SSOProvider, error) {
+	return r.getOne(ctx, "id = $1", id)
+}
+
+func (r *ssoProviderRepository) GetBySlug(ctx context.Context, slug string) (*service.SSOProvider, error) {
+	return r.getOne(ctx, "slug = $1", slug)
+}
+
+func (r *ssoProviderRepository) List(ctx context.Context, enabledOnly bool) ([]service.SSOProvider, error) {
+	rows, err := r.q(ctx).QueryContext(ctx, `
+		SELECT `+ssoProviderColumns+`
+		FROM sso_providers
+		WHERE ($1 = FALSE OR enabled = TRUE)
+		ORDER BY display_order, id
+	`, enabledOnly)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	out := make([]service.SSOProvider, 0)
+	for rows.Next() {
+		p, err := scanSSOProvider(rows.Scan)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *p)
+	}
+	return out, rows.Err()
+}
+
+func (r *ssoProviderRepository) GetIdentity(ctx context.Context, providerID int64, subject string) (*service.SSOIdentity, error) {
+	var (
+		identity    service.SSOIdentity
+		lastLoginAt sql.NullTime
+	)
+	err := scanSingleRow(ctx, r.q(ctx), `
+		SELECT id, provider_id, subject, user_id, email, last_login_at, created_at
+		FROM user_sso_identities
+		WHERE provider_id = $1 AND subject = $2
+	`, []any{providerID, subject},
+		&identity.ID, &identity.ProviderID, &identity.Subject, &identity.UserID, &identity.Email, &lastLoginAt, &identity.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, service.ErrSSOIdentityNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if lastLoginAt.Valid {
+		identity.LastLoginAt = &lastLoginAt.Time
+	}
+	return &identity, nil
+}
+
+func (r *ssoProviderRepository) CreateIdentity(ctx context.Context, identity *service.SSOIdentity) error {
+	err := scanSingleRow(ctx, r.q(ctx), `
+		INSERT INTO user_sso_identities (provider_id, subject, user_id, email, last_login_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at
+	`, []any{identity.ProviderID, identity.Subject, identity.UserID, identity.Email, identity.LastLoginAt},
+		&identity.ID, &identity.CreatedAt)
+	if isUniqueConstraintViolation(err) {
+		return service.ErrSSOIdentityExists
+	}
+	return err
+}
+
+func (r *ssoProviderRepository) TouchIdentity(ctx context.Context, id int64, email string, at time.Time) error {
+	result, err := r.q(ctx).ExecContext(ctx, `
+		UPDATE user_sso_identities
+		SET last_login_at = $2, email = CASE WHEN $3 = '' THEN email ELSE $3 END
+		WHERE id = $1
+	`, id, at, email)
+	if err != nil {
+		return err
+	}
+	return requireAffected(result, service.ErrSSOIdentityNotFound)
+}
@@ -0,0 +1,116 @@
+//go:build integration
+
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	dbent "github.com/Wei-Shaw/sub2api/ent"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+	"github.com/stretchr/testify/suite"
+)
+
+type SSOProviderRepoSuite struct {
+	suite.Suite
+	ctx    context.Context
+	client *dbent.Client
+	repo   *ssoProviderRepository
+}
+
+func (s *SSOProviderRepoSuite) SetupTest() {
+	s.ctx = context.Background()
+	tx := testEntTx(s.T())
+	s.client = tx.Client()
+	s.repo = &ssoProviderRepository{sql: tx}
+}
+
+func TestSSOProviderRepoSuite(t *testing.T) {
+	suite.Run(t, new(SSOProviderRepoSuite))
+}
+
+func (s *SSOProviderRepoSuite) newProvider(slug string, enabled bool) *service.SSOProvider {
+	p := &service.SSOProvider{
+		Slug:                  slug,
+		Name:                  "Provider " + slug,
+		Type:                  service.SSOProviderTypeOIDC,
+		Enabled:               enabled,
+		IssuerURL:             "https://idp.example.com/realms/" + slug,
+		ClientID:              "client-" + slug,
+		ClientSecretEncrypted: "encrypted",
+		Scopes:                "openid email",
+		TokenAuthMethod:       service.SSOTokenAuthClientSecretBasic,
+		UsePKCE:               true,
+		AttributeMappings:     map[string]string{"department": "dept"},
+		AllowedEmailDomains:   []string{"example.com"},
+		AutoProvision:         true,
+		DefaultGroupIDs:       []int64{3, 4},
+	}
+	s.Require().NoError(s.repo.Create(s.ctx, p))
+	return p
+}
+
+func (s *SSOProviderRepoSuite) TestProviderCRUD() {
+	p := s.newProvider("corp", true)
+	s.Require().NotZero(p.ID)
+	s.Require().ErrorIs(s.repo.Create(s.ctx, &service.SSOProvider{Slug: "corp", Name: "dup", ClientID: "x"}), service.ErrSSOProviderSlugExists)
+
+	got, err := s.repo.GetBySlug(s.ctx, "corp")
+	s.Require().NoError(err)
+	s.Require().Equal(p.ID, got.ID)
+	s.Require().Equal(map[string]string{"department": "dept"}, got.AttributeMappings)
+	s.Require().Equal([]string{"example.com"}, got.AllowedEmailDomains)
+	s.Require().Equal([]int64{3, 4}, got.DefaultGroupIDs)
+	s.Require().Equal(service.SSOTokenAuthClientSecretBasic, got.TokenAuthMethod)
+
+	got.Enabled = false
+	got.EnforceSSO = true
+	got.DefaultGroupIDs = nil
+	s.Require().NoError(s.repo.Update(s.ctx, got))
+	updated, err := s.repo.GetByID(s.ctx, p.ID)
+	s.Require().NoError(err)
+	s.Require().False(updated.Enabled)
+	s.Require().True(updated.EnforceSSO)
+	s.Require().Empty(updated.DefaultGroupIDs)
+
+	s.newProvider("other", true)
+	enabled, err := s.repo.List(s.ctx, true)
+	s.Require().NoError(err)
+	s.Require().Len(enabled, 1)
+	s.Require().Equal("other", enabled[0].Slug)
+	all, err := s.repo.List(s.ctx, false)
+	s.Require().NoError(err)
+	s.Require().Len(all, 2)
+
+	s.Require().NoError(s.repo.Delete(s.ctx, p.ID))
+	_, err = s.repo.GetByID(s.ctx, p.ID)
+	s.Require().ErrorIs(err, service.ErrSSOProviderNotFound)
+	s.Require().ErrorIs(s.repo.Delete(s.ctx, p.ID), service.ErrSSOProviderNotFound)
+}
+
+func (s *SSOProviderRepoSuite) TestIdentities() {
+	p := s.newProvider("ident", true)
+	user := mustCreateUser(s.T(), s.client, &service.User{Email: "sso-ident@example.com"})
+	other := mustCreateUser(s.T(), s.client, &service.User{Email: "sso-other@example.com"})
+
+	_, err := s.repo.GetIdentity(s.ctx, p.ID, "subject-1")
+	s.Require().ErrorIs(err, service.ErrSSOIdentityNotFound)
+
+	identity := &service.SSOIdentity{ProviderID: p.ID, Subject: "subject-1", UserID: user.ID, Email: "a@example.com"}
+	s.Require().NoError(s.repo.CreateIdentity(s.ctx, identity))
+	s.Require().NotZero(identity.ID)
+
+	// 同一提供方下 subject 与用户都只能绑定一次
+	s.Require().ErrorIs(s.repo.CreateIdentity(s.ctx, &service.SSOIdentity{ProviderID: p.ID, Subject: "subject-1", UserID: other.ID}), service.ErrSSOIdentityExists)
+	s.Require().ErrorIs(s.repo.CreateIdentity(s.ctx, &service.SSOIdentity{ProviderID: p.ID, Subject: "subject-2", UserID: user.ID}), service.ErrSSOIdentityExists)
+
+	at := time.Now().Truncate(time.Second)
+	s.Require().NoError(s.repo.TouchIdentity(s.ctx, identity.ID, "", at))
+	got, err := s.repo.GetIdentity(s.ctx, p.ID, "subject-1")
+	s.Require().NoError(err)
+	s.Require().Equal(user.ID, got.UserID)
+	s.Require().Equal("a@example.com", got.Email, "empty email keeps the stored one")
+	s.Require().NotNil(got.LastLoginAt)
+	s.Require().WithinDuration(at, *got.LastLoginAt, time.Second)
+}
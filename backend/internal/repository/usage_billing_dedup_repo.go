@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	dbent "github.com/Wei-Shaw/sub2api/ent"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+)
+
+type usageBillingDedupRepository struct {
+	sql sqlExecutor
+}
+
+// NewUsageBillingDedupRepository 创建账务幂等键仓储
+func NewUsageBillingDedupRepository(sqlDB *sql.DB) service.UsageBillingDedupRepository {
+	return &usageBillingDedupRepository{sql: sqlDB}
+}
+
+func (r *usageBillingDedupRepository) Claim(ctx context.Context, requestID string, apiKeyID int64, fingerprint string) (bool, error) {
+	// 在事务上下文中与使用日志写入、扣费同事务提交；事务回滚时幂等键一并回滚。
+	sqlq := r.sql
+	if tx := dbent.TxFromContext(ctx); tx != nil {
+		sqlq = tx.Client()
+	}
+
+	// 冷归档表中的键同样视为已处理，避免长周期重放时重复扣费。
+	result, err := sqlq.ExecContext(ctx, `
+		INSERT INTO usage_billing_dedup (request_id, api_key_id, request_fingerprint)
+		SELECT $1::varchar, $2::bigint, $3::varchar
+		WHERE NOT EXISTS (
+			SELECT 1 FROM usage_billing_dedup_archive
+			WHERE request_id = $1::varchar AND api_key_id = $2::bigint
+		)
+		ON CONFLICT (request_id, api_key_id) DO NOTHING
+	`, requestID, apiKeyID, fingerprint)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
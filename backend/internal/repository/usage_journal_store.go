@@ -0,0 +1,333 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	infraerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+	"github.com/redis/go-redis/v9"
+)
+
+// 使用量持久化日志 Redis Stream 实现
+//
+// 设计说明：
+//   - 主流 {stream}：每条记录一个字段 payload（UsageBillingCommand JSON）；
+//   - 消费者组 {group}：所有节点共享，XREADGROUP 分发新条目，PEL 记录已投递未确认的条目；
+//   - 条目落库后 XACK + XDEL，主流中只保留“未投递 + 待确认”的条目，因此 XLEN - pending 即为积压；
+//   - 死信流 {stream}:dead：超过最大投递次数或无法解析的条目，保留原始 payload 供排查与重新入队。
+const (
+	usageJournalPayloadField    = "payload"
+	usageJournalSourceIDField   = "source_id"
+	usageJournalReasonField     = "reason"
+	usageJournalDeliveriesField = "deliveries"
+	usageJournalDeadAtField     = "dead_at"
+	usageJournalDeadSuffix      = ":dead"
+)
+
+type usageJournalStore struct {
+	rdb     *redis.Client
+	stream  string
+	deadLtr string
+	group   string
+}
+
+// NewUsageJournalStore 创建使用量持久化日志存储
+func NewUsageJournalStore(rdb *redis.Client, stream, group string) service.UsageJournalStore {
+	return &usageJournalStore{
+		rdb:     rdb,
+		stream:  stream,
+		deadLtr: stream + usageJournalDeadSuffix,
+		group:   group,
+	}
+}
+
+func (s *usageJournalStore) Append(ctx context.Context, payload []byte) (string, error) {
+	return s.rdb.XAdd(ctx, &redis.XAddArgs{
+		Stream: s.stream,
+		Values: map[string]any{usageJournalPayloadField: payload},
+	}).Result()
+}
+
+func (s *usageJournalStore) EnsureGroup(ctx context.Context) error {
+	err := s.rdb.XGroupCreateMkStream(ctx, s.stream, s.group, "0").Err()
+	if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return err
+	}
+	return nil
+}
+
+func (s *usageJournalStore) ReadNew(ctx context.Context, consumer string, count int, block time.Duration) ([]service.UsageJournalEntry, error) {
+	streams, err := s.rdb.XReadGroup(ctx, &redis.XReadGroupArgs{
+		Group:    s.group,
+		Consumer: consumer,
+		Streams:  []string{s.stream, ">"},
+		Count:    int64(count),
+		Block:    block,
+	}).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var entries []service.UsageJournalEntry
+	for _, stream := range streams {
+		for _, msg := range stream.Messages {
+			entries = append(entries, service.UsageJournalEntry{
+				ID:         msg.ID,
+				Payload:    usageJournalPayload(msg.Values),
+				Deliveries: 1,
+			})
+		}
+	}
+	return entries, nil
+}
+
+func (s *usageJournalStore) ClaimPending(ctx context.Context, consumer string, opts service.UsageJournalClaimOptions) ([]service.UsageJournalEntry, error) {
+	start := "-"
+	if opts.AfterID != "" {
+		start = "(" + opts.AfterID
+	}
+	args := &redis.XPendingExtArgs{
+		Stream: s.stream,
+		Group:  s.group,
+		Idle:   opts.MinIdle,
+		Start:  start,
+		End:    "+",
+		Count:  int64(opts.Count),
+	}
+	if opts.OwnOnly {
+		args.Consumer = consumer
+	}
+	pending, err := s.rdb.XPendingExt(ctx, args).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if len(pending) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]string, 0, len(pending))
+	retries := make(map[string]int64, len(pending))
+	for _, p := range pending {
+		ids = append(ids, p.ID)
+		retries[p.ID] = p.RetryCount
+	}
+
+	// XCLAIM 会再次校验 MinIdle，避免与其他节点并发领取同一条目；投递计数随之加一。
+	msgs, err := s.rdb.XClaim(ctx, &redis.XClaimArgs{
+		Stream:   s.stream,
+		Group:    s.group,
+		Consumer: consumer,
+		MinIdle:  opts.MinIdle,
+		Messages: ids,
+	}).Result()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return nil, err
+	}
+
+	entries := make([]service.UsageJournalEntry, 0, len(msgs))
+	for _, msg := range msgs {
+		entries = append(entries, service.UsageJournalEntry{
+			ID:         msg.ID,
+			Payload:    usageJournalPayload(msg.Values),
+			Deliveries: retries[msg.ID] + 1,
+		})
+	}
+	return entries, nil
+}
+
+func (s *usageJournalStore) Ack(ctx context.Context, ids ...string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	pipe := s.rdb.Pipeline()
+	pipe.XAck(ctx, s.stream, s.group, ids...)
+	pipe.XDel(ctx, s.stream, ids...)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+func (s *usageJournalStore) DeadLetter(ctx context.Context, entry service.UsageJournalEntry, reason string) error {
+	// 先写死信再确认：中途失败时条目仍在主流 PEL 中，最多在死信流中重复一份。
+	if err := s.rdb.XAdd(ctx, &redis.XAddArgs{
+		Stream: s.deadLtr,
+		Values: map[string]any{
+			usageJournalPayloadField:    entry.Payload,
+			usageJournalSourceIDField:   entry.ID,
+			usageJournalReasonField:     reason,
+			usageJournalDeliveriesField: entry.Deliveries,
+			usageJournalDeadAtField:     time.Now().UTC().Format(time.RFC3339),
+		},
+	}).Err(); err != nil {
+		return fmt.Errorf("xadd dead-letter: %w", err)
+	}
+	return s.Ack(ctx, entry.ID)
+}
+
+func (s *usageJournalStore) Stats(ctx context.Context) (*service.UsageJournalStats, error) {
+	stats := &service.UsageJournalStats{
+		StreamKey:     s.stream,
+		ConsumerGroup: s.group,
+		Consumers:     []*service.UsageJournalConsumerStats{},
+	}
+
+	pipe := s.rdb.Pipeline()
+	lenCmd := pipe.XLen(ctx, s.stream)
+	deadLenCmd := pipe.XLen(ctx, s.deadLtr)
+	if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) {
+		return nil, err
+	}
+	stats.StreamLength = lenCmd.Val()
+	stats.DeadLetterLength = deadLenCmd.Val()
+
+	summary, err := s.rdb.XPending(ctx, s.stream, s.group).Result()
+	if err != nil {
+		// 消费者组尚未创建（消费者未启动）：全部条目视为积压
+		if isRedisNoGroupError(err) {
+			stats.Lag = stats.StreamLength
+			return stats, nil
+		}
+		return nil, err
+	}
+	stats.PendingCount = summary.Count
+	stats.Lag = stats.StreamLength - stats.PendingCount
+	if stats.Lag < 0 {
+		stats.Lag = 0
+	}
+
+	consumers, err := s.rdb.XInfoConsumers(ctx, s.stream, s.group).Result()
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range consumers {
+		stats.Consumers = append(stats.Consumers, &service.UsageJournalConsumerStats{
+			Name:    c.Name,
+			Pending: c.Pending,
+			IdleMs:  c.Idle.Milliseconds(),
+		})
+	}
+	return stats, nil
+}
+
+func (s *usageJournalStore) ListPending(ctx context.Context, count int) ([]*service.UsageJournalRecord, error) {
+	pending, err := s.rdb.XPendingExt(ctx, &redis.XPendingExtArgs{
+		Stream: s.stream,
+		Group:  s.group,
+		Start:  "-",
+		End:    "+",
+		Count:  int64(count),
+	}).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) || isRedisNoGroupError(err) {
+			return []*service.UsageJournalRecord{}, nil
+		}
+		return nil, err
+	}
+	if len(pending) == 0 {
+		return []*service.UsageJournalRecord{}, nil
+	}
+
+	pipe := s.rdb.Pipeline()
+	cmds := make([]*redis.XMessageSliceCmd, len(pending))
+	for i, p := range pending {
+		cmds[i] = pipe.XRangeN(ctx, s.stream, p.ID, p.ID, 1)
+	}
+	if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) {
+		return nil, err
+	}
+
+	records := make([]*service.UsageJournalRecord, 0, len(pending))
+	for i, p := range pending {
+		rec := &service.UsageJournalRecord{
+			ID:         p.ID,
+			Consumer:   p.Consumer,
+			IdleMs:     p.Idle.Milliseconds(),
+			Deliveries: p.RetryCount,
+		}
+		if msgs := cmds[i].Val(); len(msgs) > 0 {
+			rec.Payload = usageJournalPayload(msgs[0].Values)
+		}
+		records = append(records, rec)
+	}
+	return records, nil
+}
+
+func (s *usageJournalStore) ListDeadLetters(ctx context.Context, count int) ([]*service.UsageJournalRecord, error) {
+	msgs, err := s.rdb.XRevRangeN(ctx, s.deadLtr, "+", "-", int64(count)).Result()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return nil, err
+	}
+	records := make([]*service.UsageJournalRecord, 0, len(msgs))
+	for _, msg := range msgs {
+		deliveries, _ := strconv.ParseInt(usageJournalField(msg.Values, usageJournalDeliveriesField), 10, 64)
+		records = append(records, &service.UsageJournalRecord{
+			ID:         msg.ID,
+			Deliveries: deliveries,
+			Reason:     usageJournalField(msg.Values, usageJournalReasonField),
+			SourceID:   usageJournalField(msg.Values, usageJournalSourceIDField),
+			Payload:    usageJournalPayload(msg.Values),
+		})
+	}
+	return records, nil
+}
+
+func (s *usageJournalStore) RequeueDeadLetter(ctx context.Context, id string) (string, error) {
+	msgs, err := s.rdb.XRangeN(ctx, s.deadLtr, id, id, 1).Result()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return "", err
+	}
+	if len(msgs) == 0 {
+		return "", infraerrors.NotFound("USAGE_JOURNAL_DEAD_LETTER_NOT_FOUND", "dead-letter entry not found")
+	}
+	payload := usageJournalPayload(msgs[0].Values)
+	if len(payload) == 0 {
+		return "", infraerrors.BadRequest("USAGE_JOURNAL_DEAD_LETTER_EMPTY", "dead-letter entry has no payload")
+	}
+
+	newID, err := s.Append(ctx, payload)
+	if err != nil {
+		return "", err
+	}
+	if err := s.rdb.XDel(ctx, s.deadLtr, id).Err(); err != nil {
+		return "", err
+	}
+	return newID, nil
+}
+
+func usageJournalPayload(values map[string]any) []byte {
+	raw := usageJournalField(values, usageJournalPayloadField)
+	if raw == "" {
+		return nil
+	}
+	return []byte(raw)
+}
+
+func usageJournalField(values map[string]any, key string) string {
+	if values == nil {
+		return ""
+	}
+	switch v := values[key].(type) {
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	case nil:
+		return ""
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func isRedisNoGroupError(err error) bool {
+	return err != nil && strings.HasPrefix(err.Error(), "NOGROUP")
+}
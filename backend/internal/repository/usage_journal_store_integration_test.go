@@ -0,0 +1,109 @@
+//go:build integration
+
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/service"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type UsageJournalStoreSuite struct {
+	IntegrationRedisSuite
+	store service.UsageJournalStore
+}
+
+func (s *UsageJournalStoreSuite) SetupTest() {
+	s.IntegrationRedisSuite.SetupTest()
+	// Stream 命令不经过测试前缀 hook，使用按用例区分的键名隔离数据
+	stream := "usage:journal:" + sanitizeRedisNamespace(s.T().Name())
+	s.store = NewUsageJournalStore(s.rdb, stream, "usage-billing")
+	s.RequireNoError(s.store.EnsureGroup(s.ctx))
+	s.RequireNoError(s.store.EnsureGroup(s.ctx), "EnsureGroup should be idempotent")
+}
+
+func (s *UsageJournalStoreSuite) TestAppendReadAck() {
+	id, err := s.store.Append(s.ctx, []byte(`{"a":1}`))
+	s.RequireNoError(err)
+
+	entries, err := s.store.ReadNew(s.ctx, "node-a", 10, 10*time.Millisecond)
+	s.RequireNoError(err)
+	require.Len(s.T(), entries, 1)
+	require.Equal(s.T(), id, entries[0].ID)
+	require.Equal(s.T(), `{"a":1}`, string(entries[0].Payload))
+	require.Equal(s.T(), int64(1), entries[0].Deliveries)
+
+	stats, err := s.store.Stats(s.ctx)
+	s.RequireNoError(err)
+	require.Equal(s.T(), int64(1), stats.StreamLength)
+	require.Equal(s.T(), int64(1), stats.PendingCount)
+	require.Equal(s.T(), int64(0), stats.Lag)
+
+	s.RequireNoError(s.store.Ack(s.ctx, id))
+	stats, err = s.store.Stats(s.ctx)
+	s.RequireNoError(err)
+	require.Equal(s.T(), int64(0), stats.StreamLength)
+	require.Equal(s.T(), int64(0), stats.PendingCount)
+}
+
+func (s *UsageJournalStoreSuite) TestClaimPendingIncrementsDeliveries() {
+	_, err := s.store.Append(s.ctx, []byte(`{"a":1}`))
+	s.RequireNoError(err)
+	_, err = s.store.ReadNew(s.ctx, "node-a", 10, 10*time.Millisecond)
+	s.RequireNoError(err)
+
+	// 其他节点：条目尚未空闲超时，不可接管
+	claimed, err := s.store.ClaimPending(s.ctx, "node-b", service.UsageJournalClaimOptions{MinIdle: time.Hour, Count: 10})
+	s.RequireNoError(err)
+	require.Empty(s.T(), claimed)
+
+	// 本节点重启重放
+	claimed, err = s.store.ClaimPending(s.ctx, "node-a", service.UsageJournalClaimOptions{OwnOnly: true, Count: 10})
+	s.RequireNoError(err)
+	require.Len(s.T(), claimed, 1)
+	require.Equal(s.T(), int64(2), claimed[0].Deliveries)
+
+	pending, err := s.store.ListPending(s.ctx, 10)
+	s.RequireNoError(err)
+	require.Len(s.T(), pending, 1)
+	require.Equal(s.T(), "node-a", pending[0].Consumer)
+	require.Equal(s.T(), `{"a":1}`, string(pending[0].Payload))
+}
+
+func (s *UsageJournalStoreSuite) TestDeadLetterAndRequeue() {
+	_, err := s.store.Append(s.ctx, []byte(`{"a":1}`))
+	s.RequireNoError(err)
+	entries, err := s.store.ReadNew(s.ctx, "node-a", 10, 10*time.Millisecond)
+	s.RequireNoError(err)
+	require.Len(s.T(), entries, 1)
+
+	s.RequireNoError(s.store.DeadLetter(s.ctx, entries[0], "boom"))
+
+	dead, err := s.store.ListDeadLetters(s.ctx, 10)
+	s.RequireNoError(err)
+	require.Len(s.T(), dead, 1)
+	require.Equal(s.T(), "boom", dead[0].Reason)
+	require.Equal(s.T(), entries[0].ID, dead[0].SourceID)
+
+	stats, err := s.store.Stats(s.ctx)
+	s.RequireNoError(err)
+	require.Equal(s.T(), int64(0), stats.StreamLength)
+	require.Equal(s.T(), int64(1), stats.DeadLetterLength)
+
+	newID, err := s.store.RequeueDeadLetter(s.ctx, dead[0].ID)
+	s.RequireNoError(err)
+	require.NotEmpty(s.T(), newID)
+
+	stats, err = s.store.Stats(s.ctx)
+	s.RequireNoError(err)
+	require.Equal(s.T(), int64(1), stats.StreamLength)
+	require.Equal(s.T(), int64(1), stats.Lag)
+	require.Equal(s.T(), int64(0), stats.DeadLetterLength)
+}
+
+func TestUsageJournalStoreSuite(t *testing.T) {
+	suite.Run(t, new(UsageJournalStoreSuite))
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/google/uuid"
 
 	dbent "github.com/Wei-Shaw/sub2api/ent"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
 	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
 	"github.com/Wei-Shaw/sub2api/internal/pkg/timezone"
 	"github.com/Wei-Shaw/sub2api/internal/pkg/usagestats"
@@ -52,8 +53,8 @@ func (s *UsageLogRepoSuite) createUsageLog(user *service.User, apiKey *service.A
 		Model:        "claude-3",
 		InputTokens:  inputTokens,
 		OutputTokens: outputTokens,
-		TotalCost:    cost,
-		ActualCost:   cost,
+		TotalCost:    money.FromFloat(cost),
+		ActualCost:   money.FromFloat(cost),
 		CreatedAt:    createdAt,
 	}
 	_, err := s.repo.Create(s.ctx, log)
@@ -75,8 +76,8 @@ func (s *UsageLogRepoSuite) TestCreate() {
 		Model:        "claude-3",
 		InputTokens:  10,
 		OutputTokens: 20,
-		TotalCost:    0.5,
-		ActualCost:   0.4,
+		TotalCost:    money.MustParse("0.5"),
+		ActualCost:   money.MustParse("0.4"),
 	}
 
 	_, err := s.repo.Create(s.ctx, log)
@@ -116,8 +117,8 @@ func (s *UsageLogRepoSuite) TestGetByID_ReturnsAccountRateMultiplier() {
 		Model:                 "claude-3",
 		InputTokens:           10,
 		OutputTokens:          20,
-		TotalCost:             1.0,
-		ActualCost:            2.0,
+		TotalCost:             money.USD,
+		ActualCost:            2 * money.USD,
 		AccountRateMultiplier: &m,
 		CreatedAt:             timezone.Today().Add(2 * time.Hour),
 	}
@@ -143,8 +144,8 @@ func (s *UsageLogRepoSuite) TestGetByID_ReturnsOpenAIWSMode() {
 		Model:        "gpt-5.3-codex",
 		InputTokens:  10,
 		OutputTokens: 20,
-		TotalCost:    1.0,
-		ActualCost:   1.0,
+		TotalCost:    money.USD,
+		ActualCost:   money.USD,
 		OpenAIWSMode: true,
 		CreatedAt:    timezone.Today().Add(3 * time.Hour),
 	}
@@ -172,8 +173,8 @@ func (s *UsageLogRepoSuite) TestGetByID_ReturnsRequestTypeAndLegacyFallback() {
 		OpenAIWSMode: false,
 		InputTokens:  10,
 		OutputTokens: 20,
-		TotalCost:    1.0,
-		ActualCost:   1.0,
+		TotalCost:    money.USD,
+		ActualCost:   money.USD,
 		CreatedAt:    timezone.Today().Add(4 * time.Hour),
 	}
 	_, err := s.repo.Create(s.ctx, log)
@@ -325,8 +326,8 @@ func (s *UsageLogRepoSuite) TestDashboardStats_TodayTotalsAndPerformance() {
 		OutputTokens:        20,
 		CacheCreationTokens: 3,
 		CacheReadTokens:     4,
-		TotalCost:           1.5,
-		ActualCost:          1.2,
+		TotalCost:           money.MustParse("1.5"),
+		ActualCost:          money.MustParse("1.2"),
 		DurationMs:          &d1,
 		CreatedAt:           testMaxTime(todayStart.Add(2*time.Minute), now.Add(-2*time.Minute)),
 	}
@@ -340,8 +341,8 @@ func (s *UsageLogRepoSuite) TestDashboardStats_TodayTotalsAndPerformance() {
 		Model:        "claude-3",
 		InputTokens:  5,
 		OutputTokens: 6,
-		TotalCost:    0.7,
-		ActualCost:   0.7,
+		TotalCost:    money.MustParse("0.7"),
+		ActualCost:   money.MustParse("0.7"),
 		DurationMs:   &d2,
 		CreatedAt:    todayStart.Add(-1 * time.Hour),
 	}
@@ -355,8 +356,8 @@ func (s *UsageLogRepoSuite) TestDashboardStats_TodayTotalsAndPerformance() {
 		Model:        "claude-3",
 		InputTokens:  1,
 		OutputTokens: 2,
-		TotalCost:    0.1,
-		ActualCost:   0.1,
+		TotalCost:    money.MustParse("0.1"),
+		ActualCost:   money.MustParse("0.1"),
 		DurationMs:   &d3,
 		CreatedAt:    now.Add(-30 * time.Second),
 	}
@@ -418,8 +419,8 @@ func (s *UsageLogRepoSuite) TestDashboardStatsWithRange_Fallback() {
 		Model:        "claude-3",
 		InputTokens:  7,
 		OutputTokens: 8,
-		TotalCost:    0.8,
-		ActualCost:   0.7,
+		TotalCost:    money.MustParse("0.8"),
+		ActualCost:   money.MustParse("0.7"),
 		DurationMs:   &d3,
 		CreatedAt:    rangeStart.Add(-1 * time.Hour),
 	}
@@ -435,8 +436,8 @@ func (s *UsageLogRepoSuite) TestDashboardStatsWithRange_Fallback() {
 		OutputTokens:        20,
 		CacheCreationTokens: 1,
 		CacheReadTokens:     2,
-		TotalCost:           1.0,
-		ActualCost:          0.9,
+		TotalCost:           money.USD,
+		ActualCost:          money.MustParse("0.9"),
 		DurationMs:          &d1,
 		CreatedAt:           rangeStart.Add(2 * time.Hour),
 	}
@@ -451,8 +452,8 @@ func (s *UsageLogRepoSuite) TestDashboardStatsWithRange_Fallback() {
 		InputTokens:     5,
 		OutputTokens:    6,
 		CacheReadTokens: 1,
-		TotalCost:       0.5,
-		ActualCost:      0.5,
+		TotalCost:       money.MustParse("0.5"),
+		ActualCost:      money.MustParse("0.5"),
 		DurationMs:      &d2,
 		CreatedAt:       now,
 	}
@@ -506,8 +507,8 @@ func (s *UsageLogRepoSuite) TestGetAccountTodayStats() {
 		Model:                 "claude-3",
 		InputTokens:           10,
 		OutputTokens:          20,
-		TotalCost:             1.0,
-		ActualCost:            2.0,
+		TotalCost:             money.USD,
+		ActualCost:            2 * money.USD,
 		AccountRateMultiplier: &m1,
 		CreatedAt:             createdAt,
 	})
@@ -520,8 +521,8 @@ func (s *UsageLogRepoSuite) TestGetAccountTodayStats() {
 		Model:                 "claude-3",
 		InputTokens:           5,
 		OutputTokens:          5,
-		TotalCost:             0.5,
-		ActualCost:            1.0,
+		TotalCost:             money.MustParse("0.5"),
+		ActualCost:            money.USD,
 		AccountRateMultiplier: &m2,
 		CreatedAt:             createdAt,
 	})
@@ -569,8 +570,8 @@ func (s *UsageLogRepoSuite) TestDashboardAggregationConsistency() {
 		OutputTokens:        20,
 		CacheCreationTokens: 2,
 		CacheReadTokens:     1,
-		TotalCost:           1.0,
-		ActualCost:          0.9,
+		TotalCost:           money.USD,
+		ActualCost:          money.MustParse("0.9"),
 		DurationMs:          &d1,
 		CreatedAt:           hour1.Add(5 * time.Minute),
 	}
@@ -584,8 +585,8 @@ func (s *UsageLogRepoSuite) TestDashboardAggregationConsistency() {
 		Model:        "claude-3",
 		InputTokens:  5,
 		OutputTokens: 5,
-		TotalCost:    0.5,
-		ActualCost:   0.5,
+		TotalCost:    money.MustParse("0.5"),
+		ActualCost:   money.MustParse("0.5"),
 		DurationMs:   &d2,
 		CreatedAt:    hour1.Add(20 * time.Minute),
 	}
@@ -599,8 +600,8 @@ func (s *UsageLogRepoSuite) TestDashboardAggregationConsistency() {
 		Model:        "claude-3",
 		InputTokens:  7,
 		OutputTokens: 8,
-		TotalCost:    0.7,
-		ActualCost:   0.7,
+		TotalCost:    money.MustParse("0.7"),
+		ActualCost:   money.MustParse("0.7"),
 		DurationMs:   &d3,
 		CreatedAt:    hour2.Add(10 * time.Minute),
 	}
@@ -838,8 +839,8 @@ func (s *UsageLogRepoSuite) TestListByModelAndTimeRange() {
 		Model:        "claude-3-opus",
 		InputTokens:  10,
 		OutputTokens: 20,
-		TotalCost:    0.5,
-		ActualCost:   0.5,
+		TotalCost:    money.MustParse("0.5"),
+		ActualCost:   money.MustParse("0.5"),
 		CreatedAt:    base,
 	}
 	_, err := s.repo.Create(s.ctx, log1)
@@ -852,8 +853,8 @@ func (s *UsageLogRepoSuite) TestListByModelAndTimeRange() {
 		Model:        "claude-3-opus",
 		InputTokens:  15,
 		OutputTokens: 25,
-		TotalCost:    0.6,
-		ActualCost:   0.6,
+		TotalCost:    money.MustParse("0.6"),
+		ActualCost:   money.MustParse("0.6"),
 		CreatedAt:    base.Add(30 * time.Minute),
 	}
 	_, err = s.repo.Create(s.ctx, log2)
@@ -866,8 +867,8 @@ func (s *UsageLogRepoSuite) TestListByModelAndTimeRange() {
 		Model:        "claude-3-sonnet",
 		InputTokens:  20,
 		OutputTokens: 30,
-		TotalCost:    0.7,
-		ActualCost:   0.7,
+		TotalCost:    money.MustParse("0.7"),
+		ActualCost:   money.MustParse("0.7"),
 		CreatedAt:    base.Add(1 * time.Hour),
 	}
 	_, err = s.repo.Create(s.ctx, log3)
@@ -953,8 +954,8 @@ func (s *UsageLogRepoSuite) TestGetUserModelStats() {
 		Model:        "claude-3-opus",
 		InputTokens:  100,
 		OutputTokens: 200,
-		TotalCost:    0.5,
-		ActualCost:   0.5,
+		TotalCost:    money.MustParse("0.5"),
+		ActualCost:   money.MustParse("0.5"),
 		CreatedAt:    base,
 	}
 	_, err := s.repo.Create(s.ctx, log1)
@@ -967,8 +968,8 @@ func (s *UsageLogRepoSuite) TestGetUserModelStats() {
 		Model:        "claude-3-sonnet",
 		InputTokens:  50,
 		OutputTokens: 100,
-		TotalCost:    0.2,
-		ActualCost:   0.2,
+		TotalCost:    money.MustParse("0.2"),
+		ActualCost:   money.MustParse("0.2"),
 		CreatedAt:    base.Add(1 * time.Hour),
 	}
 	_, err = s.repo.Create(s.ctx, log2)
@@ -1048,8 +1049,8 @@ func (s *UsageLogRepoSuite) TestGetModelStatsWithFilters() {
 		Model:        "claude-3-opus",
 		InputTokens:  100,
 		OutputTokens: 200,
-		TotalCost:    0.5,
-		ActualCost:   0.5,
+		TotalCost:    money.MustParse("0.5"),
+		ActualCost:   money.MustParse("0.5"),
 		CreatedAt:    base,
 	}
 	_, err := s.repo.Create(s.ctx, log1)
@@ -1062,8 +1063,8 @@ func (s *UsageLogRepoSuite) TestGetModelStatsWithFilters() {
 		Model:        "claude-3-sonnet",
 		InputTokens:  50,
 		OutputTokens: 100,
-		TotalCost:    0.2,
-		ActualCost:   0.2,
+		TotalCost:    money.MustParse("0.2"),
+		ActualCost:   money.MustParse("0.2"),
 		CreatedAt:    base.Add(1 * time.Hour),
 	}
 	_, err = s.repo.Create(s.ctx, log2)
@@ -1105,8 +1106,8 @@ func (s *UsageLogRepoSuite) TestGetAccountUsageStats() {
 		Model:        "claude-3-opus",
 		InputTokens:  100,
 		OutputTokens: 200,
-		TotalCost:    0.5,
-		ActualCost:   0.4,
+		TotalCost:    money.MustParse("0.5"),
+		ActualCost:   money.MustParse("0.4"),
 		CreatedAt:    base.Add(12 * time.Hour),
 	}
 	_, err := s.repo.Create(s.ctx, log1)
@@ -1119,8 +1120,8 @@ func (s *UsageLogRepoSuite) TestGetAccountUsageStats() {
 		Model:        "claude-3-sonnet",
 		InputTokens:  50,
 		OutputTokens: 100,
-		TotalCost:    0.2,
-		ActualCost:   0.15,
+		TotalCost:    money.MustParse("0.2"),
+		ActualCost:   money.MustParse("0.15"),
 		CreatedAt:    base.Add(36 * time.Hour), // next day
 	}
 	_, err = s.repo.Create(s.ctx, log2)
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
 	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
 	"github.com/Wei-Shaw/sub2api/internal/pkg/usagestats"
 	"github.com/Wei-Shaw/sub2api/internal/service"
@@ -28,8 +29,8 @@ func TestUsageLogRepositoryCreateSyncRequestTypeAndLegacyFields(t *testing.T) {
 		Model:        "gpt-5",
 		InputTokens:  10,
 		OutputTokens: 20,
-		TotalCost:    1,
-		ActualCost:   1,
+		TotalCost:    money.USD,
+		ActualCost:   money.USD,
 		BillingType:  service.BillingTypeBalance,
 		RequestType:  service.RequestTypeWSV2,
 		Stream:       false,
@@ -71,6 +72,8 @@ func TestUsageLogRepositoryCreateSyncRequestTypeAndLegacyFields(t *testing.T) {
 			log.ImageCount,
 			sqlmock.AnyArg(), // image_size
 			sqlmock.AnyArg(), // media_type
+			sqlmock.AnyArg(), // audio_duration_seconds
+			sqlmock.AnyArg(), // audio_characters
 			sqlmock.AnyArg(), // reasoning_effort
 			log.CacheTTLOverridden,
 			createdAt,
@@ -251,23 +254,23 @@ func TestScanUsageLogRequestTypeAndLegacyFallback(t *testing.T) {
 			int64(20), // api_key_id
 			int64(30), // account_id
 			sql.NullString{Valid: true, String: "req-1"},
-			"gpt-5",           // model
-			sql.NullInt64{},   // group_id
-			sql.NullInt64{},   // subscription_id
-			1,                 // input_tokens
-			2,                 // output_tokens
-			3,                 // cache_creation_tokens
-			4,                 // cache_read_tokens
-			5,                 // cache_creation_5m_tokens
-			6,                 // cache_creation_1h_tokens
-			0.1,               // input_cost
-			0.2,               // output_cost
-			0.3,               // cache_creation_cost
-			0.4,               // cache_read_cost
-			1.0,               // total_cost
-			0.9,               // actual_cost
-			1.0,               // rate_multiplier
-			sql.NullFloat64{}, // account_rate_multiplier
+			"gpt-5",                // model
+			sql.NullInt64{},        // group_id
+			sql.NullInt64{},        // subscription_id
+			1,                      // input_tokens
+			2,                      // output_tokens
+			3,                      // cache_creation_tokens
+			4,                      // cache_read_tokens
+			5,                      // cache_creation_5m_tokens
+			6,                      // cache_creation_1h_tokens
+			money.MustParse("0.1"), // input_cost
+			money.MustParse("0.2"), // output_cost
+			money.MustParse("0.3"), // cache_creation_cost
+			money.MustParse("0.4"), // cache_read_cost
+			money.USD,              // total_cost
+			money.MustParse("0.9"), // actual_cost
+			1.0,                    // rate_multiplier
+			sql.NullFloat64{},      // account_rate_multiplier
 			int16(service.BillingTypeBalance),
 			int16(service.RequestTypeWSV2),
 			false, // legacy stream
@@ -279,6 +282,8 @@ func TestScanUsageLogRequestTypeAndLegacyFallback(t *testing.T) {
 			0,
 			sql.NullString{},
 			sql.NullString{},
+			sql.NullFloat64{},
+			sql.NullInt64{},
 			sql.NullString{},
 			false,
 			now,
@@ -301,7 +306,7 @@ func TestScanUsageLogRequestTypeAndLegacyFallback(t *testing.T) {
 			sql.NullInt64{},
 			sql.NullInt64{},
 			1, 2, 3, 4, 5, 6,
-			0.1, 0.2, 0.3, 0.4, 1.0, 0.9,
+			money.MustParse("0.1"), money.MustParse("0.2"), money.MustParse("0.3"), money.MustParse("0.4"), money.USD, money.MustParse("0.9"),
 			1.0,
 			sql.NullFloat64{},
 			int16(service.BillingTypeBalance),
@@ -315,6 +320,8 @@ func TestScanUsageLogRequestTypeAndLegacyFallback(t *testing.T) {
 			0,
 			sql.NullString{},
 			sql.NullString{},
+			sql.NullFloat64{},
+			sql.NullInt64{},
 			sql.NullString{},
 			false,
 			now,
@@ -14,6 +14,7 @@ import (
 	dbuser "github.com/Wei-Shaw/sub2api/ent/user"
 	"github.com/Wei-Shaw/sub2api/ent/userallowedgroup"
 	"github.com/Wei-Shaw/sub2api/ent/usersubscription"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
 	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
 	"github.com/Wei-Shaw/sub2api/internal/service"
 )
@@ -38,7 +39,8 @@ func (r *userRepository) Create(ctx context.Context, userIn *service.User) error
 
 	// 统一使用 ent 的事务：保证用户与允许分组的更新原子化，
 	// 并避免基于 *sql.Tx 手动构造 ent client 导致的 ExecQuerier 断言错误。
-	tx, err := r.client.Tx(ctx)
+	// 上下文中已有事务时（如创建组织账户）加入该事务。
+	tx, err := clientFromContext(ctx, r.client).Tx(ctx)
 	if err != nil && !errors.Is(err, dbent.ErrTxStarted) {
 		return err
 	}
@@ -49,7 +51,7 @@ func (r *userRepository) Create(ctx context.Context, userIn *service.User) error
 		txClient = tx.Client()
 	} else {
 		// 已处于外部事务中（ErrTxStarted），复用当前 client 并由调用方负责提交/回滚。
-		txClient = r.client
+		txClient = clientFromContext(ctx, r.client)
 	}
 
 	created, err := txClient.User.Create().
@@ -71,6 +73,16 @@ func (r *userRepository) Create(ctx context.Context, userIn *service.User) error
 		return err
 	}
 
+	// 初始余额同样记入流水，保证流水合计与余额一致
+	if created.Balance != 0 {
+		if _, err := txClient.ExecContext(ctx, `
+			INSERT INTO balance_ledger (user_id, source_type, amount, balance_before, balance_after)
+			VALUES ($1, $2, $3, 0, $3)
+		`, created.ID, service.BalanceLedgerSourceInitial, created.Balance); err != nil {
+			return fmt.Errorf("insert initial balance ledger: %w", err)
+		}
+	}
+
 	if tx != nil {
 		if err := tx.Commit(); err != nil {
 			return err
@@ -141,7 +153,6 @@ func (r *userRepository) Update(ctx context.Context, userIn *service.User) error
 		SetNotes(userIn.Notes).
 		SetPasswordHash(userIn.PasswordHash).
 		SetRole(userIn.Role).
-		SetBalance(userIn.Balance).
 		SetConcurrency(userIn.Concurrency).
 		SetStatus(userIn.Status).
 		SetSoraStorageQuotaBytes(userIn.SoraStorageQuotaBytes).
@@ -324,33 +335,43 @@ func (r *userRepository) filterUsersByAttributes(ctx context.Context, attrs map[
 	return result, nil
 }
 
-func (r *userRepository) UpdateBalance(ctx context.Context, id int64, amount float64) error {
-	client := clientFromContext(ctx, r.client)
-	n, err := client.User.Update().Where(dbuser.IDEQ(id)).AddBalance(amount).Save(ctx)
-	if err != nil {
-		return translatePersistenceError(err, service.ErrUserNotFound, nil)
-	}
-	if n == 0 {
-		return service.ErrUserNotFound
-	}
-	return nil
+// UpdateBalance 增减用户余额（amount 可为负），并在同一条语句中追加一条余额流水。
+// 余额只能通过 UpdateBalance/DeductBalance 变更，Update 不会写 balance 列。
+func (r *userRepository) UpdateBalance(ctx context.Context, id int64, amount money.Amount, src service.BalanceLedgerSource) error {
+	return r.applyBalanceChange(ctx, id, amount, src)
 }
 
 // DeductBalance 扣除用户余额
 // 透支策略：允许余额变为负数，确保当前请求能够完成
 // 中间件会阻止余额 <= 0 的用户发起后续请求
-func (r *userRepository) DeductBalance(ctx context.Context, id int64, amount float64) error {
+func (r *userRepository) DeductBalance(ctx context.Context, id int64, amount money.Amount, src service.BalanceLedgerSource) error {
+	return r.applyBalanceChange(ctx, id, -amount, src)
+}
+
+// applyBalanceChange 用一条 CTE 语句同时更新余额并写入流水：
+// 流水中的 balance_before/balance_after 取自 UPDATE 返回的行，并发变更下同样准确。
+// 调用方处于事务中时（ctx 携带 ent Tx）随事务一起提交或回滚。
+func (r *userRepository) applyBalanceChange(ctx context.Context, id int64, delta money.Amount, src service.BalanceLedgerSource) error {
 	client := clientFromContext(ctx, r.client)
-	n, err := client.User.Update().
-		Where(dbuser.IDEQ(id)).
-		AddBalance(-amount).
-		Save(ctx)
+	query := `
+		WITH updated AS (
+			UPDATE users SET balance = balance + $2, updated_at = NOW()
+			WHERE id = $1 AND deleted_at IS NULL
+			RETURNING balance
+		)
+		INSERT INTO balance_ledger (user_id, source_type, reference_id, operator_id, amount, balance_before, balance_after, notes)
+		SELECT $1, $3::VARCHAR, $4::VARCHAR, $5::BIGINT, $2, updated.balance - $2, updated.balance, $6::TEXT
+		FROM updated
+		RETURNING id
+	`
+	var ledgerID int64
+	err := scanSingleRow(ctx, client, query, []any{id, delta, src.Type, src.ReferenceID, nullInt64(src.OperatorID), src.Notes}, &ledgerID)
 	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return service.ErrUserNotFound
+		}
 		return err
 	}
-	if n == 0 {
-		return service.ErrUserNotFound
-	}
 	return nil
 }
 
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	dbent "github.com/Wei-Shaw/sub2api/ent"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
 	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
 	"github.com/Wei-Shaw/sub2api/internal/service"
 	"github.com/stretchr/testify/suite"
@@ -230,14 +231,14 @@ func (s *UserRepoSuite) TestListWithFilters_CombinedFilters() {
 		Username: "Alice",
 		Role:     service.RoleUser,
 		Status:   service.StatusActive,
-		Balance:  10,
+		Balance:  10 * money.USD,
 	})
 	target := s.mustCreateUser(&service.User{
 		Email:    "b@example.com",
 		Username: "Bob",
 		Role:     service.RoleAdmin,
 		Status:   service.StatusActive,
-		Balance:  1,
+		Balance:  money.USD,
 	})
 	s.mustCreateUser(&service.User{
 		Email:  "c@example.com",
@@ -255,73 +256,73 @@ func (s *UserRepoSuite) TestListWithFilters_CombinedFilters() {
 // --- Balance operations ---
 
 func (s *UserRepoSuite) TestUpdateBalance() {
-	user := s.mustCreateUser(&service.User{Email: "bal@test.com", Balance: 10})
+	user := s.mustCreateUser(&service.User{Email: "bal@test.com", Balance: 10 * money.USD})
 
-	err := s.repo.UpdateBalance(s.ctx, user.ID, 2.5)
+	err := s.repo.UpdateBalance(s.ctx, user.ID, money.MustParse("2.5"), service.BalanceLedgerSource{Type: service.BalanceLedgerSourceAdminAdjust})
 	s.Require().NoError(err, "UpdateBalance")
 
 	got, err := s.repo.GetByID(s.ctx, user.ID)
 	s.Require().NoError(err)
-	s.Require().InDelta(12.5, got.Balance, 1e-6)
+	s.Require().InDelta(12.5, got.Balance.Float64(), 1e-6)
 }
 
 func (s *UserRepoSuite) TestUpdateBalance_Negative() {
-	user := s.mustCreateUser(&service.User{Email: "balneg@test.com", Balance: 10})
+	user := s.mustCreateUser(&service.User{Email: "balneg@test.com", Balance: 10 * money.USD})
 
-	err := s.repo.UpdateBalance(s.ctx, user.ID, -3)
+	err := s.repo.UpdateBalance(s.ctx, user.ID, -3*money.USD, service.BalanceLedgerSource{Type: service.BalanceLedgerSourceAdminAdjust})
 	s.Require().NoError(err, "UpdateBalance with negative")
 
 	got, err := s.repo.GetByID(s.ctx, user.ID)
 	s.Require().NoError(err)
-	s.Require().InDelta(7.0, got.Balance, 1e-6)
+	s.Require().InDelta(7.0, got.Balance.Float64(), 1e-6)
 }
 
 func (s *UserRepoSuite) TestDeductBalance() {
-	user := s.mustCreateUser(&service.User{Email: "deduct@test.com", Balance: 10})
+	user := s.mustCreateUser(&service.User{Email: "deduct@test.com", Balance: 10 * money.USD})
 
-	err := s.repo.DeductBalance(s.ctx, user.ID, 5)
+	err := s.repo.DeductBalance(s.ctx, user.ID, 5*money.USD, service.BalanceLedgerSource{Type: service.BalanceLedgerSourceAdminAdjust})
 	s.Require().NoError(err, "DeductBalance")
 
 	got, err := s.repo.GetByID(s.ctx, user.ID)
 	s.Require().NoError(err)
-	s.Require().InDelta(5.0, got.Balance, 1e-6)
+	s.Require().InDelta(5.0, got.Balance.Float64(), 1e-6)
 }
 
 func (s *UserRepoSuite) TestDeductBalance_InsufficientFunds() {
-	user := s.mustCreateUser(&service.User{Email: "insuf@test.com", Balance: 5})
+	user := s.mustCreateUser(&service.User{Email: "insuf@test.com", Balance: 5 * money.USD})
 
 	// 透支策略：允许扣除超过余额的金额
-	err := s.repo.DeductBalance(s.ctx, user.ID, 999)
+	err := s.repo.DeductBalance(s.ctx, user.ID, 999*money.USD, service.BalanceLedgerSource{Type: service.BalanceLedgerSourceAdminAdjust})
 	s.Require().NoError(err, "DeductBalance should allow overdraft")
 
 	// 验证余额变为负数
 	got, err := s.repo.GetByID(s.ctx, user.ID)
 	s.Require().NoError(err)
-	s.Require().InDelta(-994.0, got.Balance, 1e-6, "Balance should be negative after overdraft")
+	s.Require().InDelta(-994.0, got.Balance.Float64(), 1e-6, "Balance should be negative after overdraft")
 }
 
 func (s *UserRepoSuite) TestDeductBalance_ExactAmount() {
-	user := s.mustCreateUser(&service.User{Email: "exact@test.com", Balance: 10})
+	user := s.mustCreateUser(&service.User{Email: "exact@test.com", Balance: 10 * money.USD})
 
-	err := s.repo.DeductBalance(s.ctx, user.ID, 10)
+	err := s.repo.DeductBalance(s.ctx, user.ID, 10*money.USD, service.BalanceLedgerSource{Type: service.BalanceLedgerSourceAdminAdjust})
 	s.Require().NoError(err, "DeductBalance exact amount")
 
 	got, err := s.repo.GetByID(s.ctx, user.ID)
 	s.Require().NoError(err)
-	s.Require().InDelta(0.0, got.Balance, 1e-6)
+	s.Require().InDelta(0.0, got.Balance.Float64(), 1e-6)
 }
 
 func (s *UserRepoSuite) TestDeductBalance_AllowsOverdraft() {
-	user := s.mustCreateUser(&service.User{Email: "overdraft@test.com", Balance: 5.0})
+	user := s.mustCreateUser(&service.User{Email: "overdraft@test.com", Balance: 5 * money.USD})
 
 	// 扣除超过余额的金额 - 应该成功
-	err := s.repo.DeductBalance(s.ctx, user.ID, 10.0)
+	err := s.repo.DeductBalance(s.ctx, user.ID, 10*money.USD, service.BalanceLedgerSource{Type: service.BalanceLedgerSourceAdminAdjust})
 	s.Require().NoError(err, "DeductBalance should allow overdraft")
 
 	// 验证余额为负
 	got, err := s.repo.GetByID(s.ctx, user.ID)
 	s.Require().NoError(err)
-	s.Require().InDelta(-5.0, got.Balance, 1e-6, "Balance should be -5.0 after overdraft")
+	s.Require().InDelta(-5.0, got.Balance.Float64(), 1e-6, "Balance should be -5.0 after overdraft")
 }
 
 // --- Concurrency ---
@@ -456,14 +457,14 @@ func (s *UserRepoSuite) TestCRUD_And_Filters_And_AtomicUpdates() {
 		Username: "Alice",
 		Role:     service.RoleUser,
 		Status:   service.StatusActive,
-		Balance:  10,
+		Balance:  10 * money.USD,
 	})
 	user2 := s.mustCreateUser(&service.User{
 		Email:    "b@example.com",
 		Username: "Bob",
 		Role:     service.RoleAdmin,
 		Status:   service.StatusActive,
-		Balance:  1,
+		Balance:  money.USD,
 	})
 	s.mustCreateUser(&service.User{
 		Email:  "c@example.com",
@@ -485,22 +486,22 @@ func (s *UserRepoSuite) TestCRUD_And_Filters_And_AtomicUpdates() {
 	s.Require().NoError(err, "GetByID after update")
 	s.Require().Equal("Alice2", got2.Username, "Update did not persist")
 
-	s.Require().NoError(s.repo.UpdateBalance(s.ctx, user1.ID, 2.5), "UpdateBalance")
+	s.Require().NoError(s.repo.UpdateBalance(s.ctx, user1.ID, money.MustParse("2.5"), service.BalanceLedgerSource{Type: service.BalanceLedgerSourceAdminAdjust}), "UpdateBalance")
 	got3, err := s.repo.GetByID(s.ctx, user1.ID)
 	s.Require().NoError(err, "GetByID after UpdateBalance")
-	s.Require().InDelta(12.5, got3.Balance, 1e-6)
+	s.Require().InDelta(12.5, got3.Balance.Float64(), 1e-6)
 
-	s.Require().NoError(s.repo.DeductBalance(s.ctx, user1.ID, 5), "DeductBalance")
+	s.Require().NoError(s.repo.DeductBalance(s.ctx, user1.ID, 5*money.USD, service.BalanceLedgerSource{Type: service.BalanceLedgerSourceAdminAdjust}), "DeductBalance")
 	got4, err := s.repo.GetByID(s.ctx, user1.ID)
 	s.Require().NoError(err, "GetByID after DeductBalance")
-	s.Require().InDelta(7.5, got4.Balance, 1e-6)
+	s.Require().InDelta(7.5, got4.Balance.Float64(), 1e-6)
 
 	// 透支策略：允许扣除超过余额的金额
-	err = s.repo.DeductBalance(s.ctx, user1.ID, 999)
+	err = s.repo.DeductBalance(s.ctx, user1.ID, 999*money.USD, service.BalanceLedgerSource{Type: service.BalanceLedgerSourceAdminAdjust})
 	s.Require().NoError(err, "DeductBalance should allow overdraft")
 	gotOverdraft, err := s.repo.GetByID(s.ctx, user1.ID)
 	s.Require().NoError(err, "GetByID after overdraft")
-	s.Require().Less(gotOverdraft.Balance, 0.0, "Balance should be negative after overdraft")
+	s.Require().Less(gotOverdraft.Balance.Float64(), 0.0, "Balance should be negative after overdraft")
 
 	s.Require().NoError(s.repo.UpdateConcurrency(s.ctx, user1.ID, 3), "UpdateConcurrency")
 	got5, err := s.repo.GetByID(s.ctx, user1.ID)
@@ -518,7 +519,7 @@ func (s *UserRepoSuite) TestCRUD_And_Filters_And_AtomicUpdates() {
 // --- UpdateBalance/UpdateConcurrency 影响行数校验测试 ---
 
 func (s *UserRepoSuite) TestUpdateBalance_NotFound() {
-	err := s.repo.UpdateBalance(s.ctx, 999999, 10.0)
+	err := s.repo.UpdateBalance(s.ctx, 999999, 10*money.USD, service.BalanceLedgerSource{Type: service.BalanceLedgerSourceAdminAdjust})
 	s.Require().Error(err, "expected error for non-existent user")
 	s.Require().ErrorIs(err, service.ErrUserNotFound)
 }
@@ -530,7 +531,7 @@ func (s *UserRepoSuite) TestUpdateConcurrency_NotFound() {
 }
 
 func (s *UserRepoSuite) TestDeductBalance_NotFound() {
-	err := s.repo.DeductBalance(s.ctx, 999999, 5)
+	err := s.repo.DeductBalance(s.ctx, 999999, 5*money.USD, service.BalanceLedgerSource{Type: service.BalanceLedgerSourceAdminAdjust})
 	s.Require().Error(err, "expected error for non-existent user")
 	// DeductBalance 在用户不存在时返回 ErrUserNotFound
 	s.Require().ErrorIs(err, service.ErrUserNotFound)
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	dbent "github.com/Wei-Shaw/sub2api/ent"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
 	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
 	"github.com/Wei-Shaw/sub2api/internal/service"
 	"github.com/stretchr/testify/suite"
@@ -333,14 +334,14 @@ func (s *UserSubscriptionRepoSuite) TestIncrementUsage() {
 	group := s.mustCreateGroup("g-usage")
 	sub := s.mustCreateSubscription(user.ID, group.ID, nil)
 
-	err := s.repo.IncrementUsage(s.ctx, sub.ID, 1.25)
+	err := s.repo.IncrementUsage(s.ctx, sub.ID, money.MustParse("1.25"))
 	s.Require().NoError(err, "IncrementUsage")
 
 	got, err := s.repo.GetByID(s.ctx, sub.ID)
 	s.Require().NoError(err)
-	s.Require().InDelta(1.25, got.DailyUsageUSD, 1e-6)
-	s.Require().InDelta(1.25, got.WeeklyUsageUSD, 1e-6)
-	s.Require().InDelta(1.25, got.MonthlyUsageUSD, 1e-6)
+	s.Require().InDelta(1.25, got.DailyUsageUSD.Float64(), 1e-6)
+	s.Require().InDelta(1.25, got.WeeklyUsageUSD.Float64(), 1e-6)
+	s.Require().InDelta(1.25, got.MonthlyUsageUSD.Float64(), 1e-6)
 }
 
 func (s *UserSubscriptionRepoSuite) TestIncrementUsage_Accumulates() {
@@ -348,12 +349,12 @@ func (s *UserSubscriptionRepoSuite) TestIncrementUsage_Accumulates() {
 	group := s.mustCreateGroup("g-accum")
 	sub := s.mustCreateSubscription(user.ID, group.ID, nil)
 
-	s.Require().NoError(s.repo.IncrementUsage(s.ctx, sub.ID, 1.0))
-	s.Require().NoError(s.repo.IncrementUsage(s.ctx, sub.ID, 2.5))
+	s.Require().NoError(s.repo.IncrementUsage(s.ctx, sub.ID, money.USD))
+	s.Require().NoError(s.repo.IncrementUsage(s.ctx, sub.ID, money.MustParse("2.5")))
 
 	got, err := s.repo.GetByID(s.ctx, sub.ID)
 	s.Require().NoError(err)
-	s.Require().InDelta(3.5, got.DailyUsageUSD, 1e-6)
+	s.Require().InDelta(3.5, got.DailyUsageUSD.Float64(), 1e-6)
 }
 
 func (s *UserSubscriptionRepoSuite) TestActivateWindows() {
@@ -387,8 +388,8 @@ func (s *UserSubscriptionRepoSuite) TestResetDailyUsage() {
 
 	got, err := s.repo.GetByID(s.ctx, sub.ID)
 	s.Require().NoError(err)
-	s.Require().InDelta(0.0, got.DailyUsageUSD, 1e-6)
-	s.Require().InDelta(20.0, got.WeeklyUsageUSD, 1e-6)
+	s.Require().InDelta(0.0, got.DailyUsageUSD.Float64(), 1e-6)
+	s.Require().InDelta(20.0, got.WeeklyUsageUSD.Float64(), 1e-6)
 	s.Require().NotNil(got.DailyWindowStart)
 	s.Require().WithinDuration(resetAt, *got.DailyWindowStart, time.Microsecond)
 }
@@ -407,8 +408,8 @@ func (s *UserSubscriptionRepoSuite) TestResetWeeklyUsage() {
 
 	got, err := s.repo.GetByID(s.ctx, sub.ID)
 	s.Require().NoError(err)
-	s.Require().InDelta(0.0, got.WeeklyUsageUSD, 1e-6)
-	s.Require().InDelta(30.0, got.MonthlyUsageUSD, 1e-6)
+	s.Require().InDelta(0.0, got.WeeklyUsageUSD.Float64(), 1e-6)
+	s.Require().InDelta(30.0, got.MonthlyUsageUSD.Float64(), 1e-6)
 	s.Require().NotNil(got.WeeklyWindowStart)
 	s.Require().WithinDuration(resetAt, *got.WeeklyWindowStart, time.Microsecond)
 }
@@ -426,7 +427,7 @@ func (s *UserSubscriptionRepoSuite) TestResetMonthlyUsage() {
 
 	got, err := s.repo.GetByID(s.ctx, sub.ID)
 	s.Require().NoError(err)
-	s.Require().InDelta(0.0, got.MonthlyUsageUSD, 1e-6)
+	s.Require().InDelta(0.0, got.MonthlyUsageUSD.Float64(), 1e-6)
 	s.Require().NotNil(got.MonthlyWindowStart)
 	s.Require().WithinDuration(resetAt, *got.MonthlyWindowStart, time.Microsecond)
 }
@@ -605,13 +606,13 @@ func (s *UserSubscriptionRepoSuite) TestActiveExpiredBoundaries_UsageAndReset_Ba
 
 	activateAt := time.Now().Add(-25 * time.Hour)
 	s.Require().NoError(s.repo.ActivateWindows(s.ctx, active.ID, activateAt), "ActivateWindows")
-	s.Require().NoError(s.repo.IncrementUsage(s.ctx, active.ID, 1.25), "IncrementUsage")
+	s.Require().NoError(s.repo.IncrementUsage(s.ctx, active.ID, money.MustParse("1.25")), "IncrementUsage")
 
 	after, err := s.repo.GetByID(s.ctx, active.ID)
 	s.Require().NoError(err, "GetByID")
-	s.Require().InDelta(1.25, after.DailyUsageUSD, 1e-6)
-	s.Require().InDelta(1.25, after.WeeklyUsageUSD, 1e-6)
-	s.Require().InDelta(1.25, after.MonthlyUsageUSD, 1e-6)
+	s.Require().InDelta(1.25, after.DailyUsageUSD.Float64(), 1e-6)
+	s.Require().InDelta(1.25, after.WeeklyUsageUSD.Float64(), 1e-6)
+	s.Require().InDelta(1.25, after.MonthlyUsageUSD.Float64(), 1e-6)
 	s.Require().NotNil(after.DailyWindowStart, "expected DailyWindowStart activated")
 	s.Require().NotNil(after.WeeklyWindowStart, "expected WeeklyWindowStart activated")
 	s.Require().NotNil(after.MonthlyWindowStart, "expected MonthlyWindowStart activated")
@@ -620,7 +621,7 @@ func (s *UserSubscriptionRepoSuite) TestActiveExpiredBoundaries_UsageAndReset_Ba
 	s.Require().NoError(s.repo.ResetDailyUsage(s.ctx, active.ID, resetAt), "ResetDailyUsage")
 	afterReset, err := s.repo.GetByID(s.ctx, active.ID)
 	s.Require().NoError(err, "GetByID after reset")
-	s.Require().InDelta(0.0, afterReset.DailyUsageUSD, 1e-6)
+	s.Require().InDelta(0.0, afterReset.DailyUsageUSD.Float64(), 1e-6)
 	s.Require().NotNil(afterReset.DailyWindowStart)
 	s.Require().WithinDuration(resetAt, *afterReset.DailyWindowStart, time.Microsecond)
 
@@ -645,13 +646,13 @@ func (s *UserSubscriptionRepoSuite) TestIncrementUsage_SoftDeletedGroup() {
 	s.Require().NoError(err, "soft delete group")
 
 	// IncrementUsage 应该失败，因为分组已软删除
-	err = s.repo.IncrementUsage(s.ctx, sub.ID, 1.0)
+	err = s.repo.IncrementUsage(s.ctx, sub.ID, money.USD)
 	s.Require().Error(err, "should fail for soft-deleted group")
 	s.Require().ErrorIs(err, service.ErrSubscriptionNotFound)
 }
 
 func (s *UserSubscriptionRepoSuite) TestIncrementUsage_NotFound() {
-	err := s.repo.IncrementUsage(s.ctx, 999999, 1.0)
+	err := s.repo.IncrementUsage(s.ctx, 999999, money.USD)
 	s.Require().Error(err, "should fail for non-existent subscription")
 	s.Require().ErrorIs(err, service.ErrSubscriptionNotFound)
 }
@@ -678,7 +679,7 @@ func (s *UserSubscriptionRepoSuite) TestIncrementUsage_Concurrent() {
 	sub := s.mustCreateSubscription(user.ID, group.ID, nil)
 
 	const numGoroutines = 10
-	const incrementPerGoroutine = 1.5
+	incrementPerGoroutine := money.MustParse("1.5")
 
 	// 启动多个 goroutine 并发调用 IncrementUsage
 	errCh := make(chan error, numGoroutines)
@@ -697,10 +698,10 @@ func (s *UserSubscriptionRepoSuite) TestIncrementUsage_Concurrent() {
 	// 验证累加结果正确
 	got, err := s.repo.GetByID(s.ctx, sub.ID)
 	s.Require().NoError(err)
-	expectedUsage := float64(numGoroutines) * incrementPerGoroutine
-	s.Require().InDelta(expectedUsage, got.DailyUsageUSD, 1e-6, "daily usage should be correctly accumulated")
-	s.Require().InDelta(expectedUsage, got.WeeklyUsageUSD, 1e-6, "weekly usage should be correctly accumulated")
-	s.Require().InDelta(expectedUsage, got.MonthlyUsageUSD, 1e-6, "monthly usage should be correctly accumulated")
+	expectedUsage := (incrementPerGoroutine * numGoroutines).Float64()
+	s.Require().InDelta(expectedUsage, got.DailyUsageUSD.Float64(), 1e-6, "daily usage should be correctly accumulated")
+	s.Require().InDelta(expectedUsage, got.WeeklyUsageUSD.Float64(), 1e-6, "weekly usage should be correctly accumulated")
+	s.Require().InDelta(expectedUsage, got.MonthlyUsageUSD.Float64(), 1e-6, "monthly usage should be correctly accumulated")
 }
 
 func (s *UserSubscriptionRepoSuite) TestTxContext_RollbackIsolation() {
@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/Wei-Shaw/sub2api/internal/service"
+)
+
+const webAuthnCeremonyKeyPrefix = "webauthn:ceremony:"
+
+// WebAuthnCache implements service.WebAuthnCache using Redis
+type WebAuthnCache struct {
+	rdb *redis.Client
+}
+
+// NewWebAuthnCache creates a new WebAuthn ceremony cache
+func NewWebAuthnCache(rdb *redis.Client) service.WebAuthnCache {
+	return &WebAuthnCache{rdb: rdb}
+}
+
+// SetCeremony stores a registration/authentication challenge
+func (c *WebAuthnCache) SetCeremony(ctx context.Context, key string, ceremony *service.WebAuthnCeremony, ttl time.Duration) error {
+	data, err := json.Marshal(ceremony)
+	if err != nil {
+		return fmt.Errorf("marshal webauthn ceremony: %w", err)
+	}
+	if err := c.rdb.Set(ctx, webAuthnCeremonyKeyPrefix+key, data, ttl).Err(); err != nil {
+		return fmt.Errorf("set webauthn ceremony: %w", err)
+	}
+	return nil
+}
+
+// TakeCeremony atomically reads and deletes a challenge so it can only be used once
+func (c *WebAuthnCache) TakeCeremony(ctx context.Context, key string) (*service.WebAuthnCeremony, error) {
+	data, err := c.rdb.GetDel(ctx, webAuthnCeremonyKeyPrefix+key).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get webauthn ceremony: %w", err)
+	}
+
+	var ceremony service.WebAuthnCeremony
+	if err := json.Unmarshal(data, &ceremony); err != nil {
+		return nil, fmt.Errorf("unmarshal webauthn ceremony: %w", err)
+	}
+	return &ceremony, nil
+}
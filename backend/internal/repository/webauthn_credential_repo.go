@@ -0,0 +1,151 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/service"
+)
+
+type webAuthnCredentialRepository struct {
+	sql sqlExecutor
+}
+
+// NewWebAuthnCredentialRepository 创建 Passkey 凭据仓储
+func NewWebAuthnCredentialRepository(sqlDB *sql.DB) service.WebAuthnCredentialRepository {
+	return &webAuthnCredentialRepository{sql: sqlDB}
+}
+
+const webAuthnCredentialColumns = `
+	id, user_id, name, credential_id, public_key, algorithm, sign_count, transports,
+	aaguid, backup_eligible, backed_up, last_used_at, created_at
+`
+
+func scanWebAuthnCredential(scan func(dest ...any) error) (*service.WebAuthnCredential, error) {
+	var (
+		c          service.WebAuthnCredential
+		signCount  int64
+		transports []byte
+		lastUsedAt sql.NullTime
+	)
+	if err := scan(
+		&c.ID, &c.UserID, &c.Name, &c.CredentialID, &c.PublicKey, &c.Algorithm, &signCount, &transports,
+		&c.AAGUID, &c.BackupEligible, &c.BackedUp, &lastUsedAt, &c.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+	c.SignCount = uint32(signCount)
+	if err := json.Unmarshal(transports, &c.Transports); err != nil {
+		return nil, fmt.Errorf("unmarshal transports: %w", err)
+	}
+	if lastUsedAt.Valid {
+		c.LastUsedAt = &lastUsedAt.Time
+	}
+	return &c, nil
+}
+
+func (r *webAuthnCredentialRepository) Create(ctx context.Context, c *service.WebAuthnCredential) error {
+	transports := c.Transports
+	if transports == nil {
+		transports = []string{}
+	}
+	transportsJSON, err := json.Marshal(transports)
+	if err != nil {
+		return fmt.Errorf("marshal transports: %w", err)
+	}
+	err = scanSingleRow(ctx, r.sql, `
+		INSERT INTO user_webauthn_credentials (
+			user_id, name, credential_id, public_key, algorithm, sign_count, transports,
+			aaguid, backup_eligible, backed_up
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING id, created_at
+	`, []any{
+		c.UserID, c.Name, c.CredentialID, c.PublicKey, c.Algorithm, int64(c.SignCount), transportsJSON,
+		c.AAGUID, c.BackupEligible, c.BackedUp,
+	}, &c.ID, &c.CreatedAt)
+	if isUniqueConstraintViolation(err) {
+		return service.ErrWebAuthnCredentialExists
+	}
+	return err
+}
+
+func (r *webAuthnCredentialRepository) GetByCredentialID(ctx context.Context, credentialID []byte) (*service.WebAuthnCredential, error) {
+	rows, err := r.sql.QueryContext(ctx, `SELECT `+webAuthnCredentialColumns+` FROM user_webauthn_credentials WHERE credential_id = $1`, credentialID)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+		return nil, service.ErrWebAuthnCredentialNotFound
+	}
+	return scanWebAuthnCredential(rows.Scan)
+}
+
+func (r *webAuthnCredentialRepository) ListByUser(ctx context.Context, userID int64) ([]service.WebAuthnCredential, error) {
+	rows, err := r.sql.QueryContext(ctx, `
+		SELECT `+webAuthnCredentialColumns+`
+		FROM user_webauthn_credentials
+		WHERE user_id = $1
+		ORDER BY id
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	out := make([]service.WebAuthnCredential, 0)
+	for rows.Next() {
+		c, err := scanWebAuthnCredential(rows.Scan)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *c)
+	}
+	return out, rows.Err()
+}
+
+func (r *webAuthnCredentialRepository) CountByUser(ctx context.Context, userID int64) (int, error) {
+	var count int
+	err := scanSingleRow(ctx, r.sql, `SELECT COUNT(*) FROM user_webauthn_credentials WHERE user_id = $1`, []any{userID}, &count)
+	return count, err
+}
+
+func (r *webAuthnCredentialRepository) Rename(ctx context.Context, userID, id int64, name string) error {
+	result, err := r.sql.ExecContext(ctx, `
+		UPDATE user_webauthn_credentials SET name = $3 WHERE id = $1 AND user_id = $2
+	`, id, userID, name)
+	if err != nil {
+		return err
+	}
+	return requireAffected(result, service.ErrWebAuthnCredentialNotFound)
+}
+
+func (r *webAuthnCredentialRepository) Delete(ctx context.Context, userID, id int64) error {
+	result, err := r.sql.ExecContext(ctx, `DELETE FROM user_webauthn_credentials WHERE id = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		return err
+	}
+	return requireAffected(result, service.ErrWebAuthnCredentialNotFound)
+}
+
+// UpdateUsage 记录认证结果；计数器条件更新，防止并发断言使计数器回退
+func (r *webAuthnCredentialRepository) UpdateUsage(ctx context.Context, id int64, signCount uint32, backedUp bool, usedAt time.Time) error {
+	var updatedID int64
+	err := scanSingleRow(ctx, r.sql, `
+		UPDATE user_webauthn_credentials
+		SET sign_count = $2, backed_up = $3, last_used_at = $4
+		WHERE id = $1 AND (sign_count < $2 OR $2 = 0)
+		RETURNING id
+	`, []any{id, int64(signCount), backedUp, usedAt}, &updatedID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return service.ErrWebAuthnVerificationFailed
+	}
+	return err
+}
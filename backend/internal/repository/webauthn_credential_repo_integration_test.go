@@ -0,0 +1,106 @@
+//go:build integration
+
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	dbent "github.com/Wei-Shaw/sub2api/ent"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+	"github.com/stretchr/testify/suite"
+)
+
+type WebAuthnCredentialRepoSuite struct {
+	suite.Suite
+	ctx      context.Context
+	client   *dbent.Client
+	repo     *webAuthnCredentialRepository
+	recovery *recoveryCodeRepository
+}
+
+func (s *WebAuthnCredentialRepoSuite) SetupTest() {
+	s.ctx = context.Background()
+	tx := testEntTx(s.T())
+	s.client = tx.Client()
+	s.repo = &webAuthnCredentialRepository{sql: tx}
+	s.recovery = &recoveryCodeRepository{sql: tx}
+}
+
+func TestWebAuthnCredentialRepoSuite(t *testing.T) {
+	suite.Run(t, new(WebAuthnCredentialRepoSuite))
+}
+
+func (s *WebAuthnCredentialRepoSuite) TestCredentialLifecycle() {
+	user := mustCreateUser(s.T(), s.client, &service.User{Email: "passkey@example.com"})
+	other := mustCreateUser(s.T(), s.client, &service.User{Email: "passkey-other@example.com"})
+
+	cred := &service.WebAuthnCredential{
+		UserID:         user.ID,
+		Name:           "MacBook",
+		CredentialID:   []byte{0x01, 0x02, 0x03},
+		PublicKey:      []byte{0xa5, 0x01},
+		Algorithm:      -7,
+		SignCount:      5,
+		Transports:     []string{"internal", "hybrid"},
+		BackupEligible: true,
+	}
+	s.Require().NoError(s.repo.Create(s.ctx, cred))
+	s.Require().NotZero(cred.ID)
+	s.Require().ErrorIs(s.repo.Create(s.ctx, &service.WebAuthnCredential{
+		UserID: other.ID, Name: "dup", CredentialID: []byte{0x01, 0x02, 0x03}, PublicKey: []byte{0xa5}, Algorithm: -7,
+	}), service.ErrWebAuthnCredentialExists)
+
+	got, err := s.repo.GetByCredentialID(s.ctx, []byte{0x01, 0x02, 0x03})
+	s.Require().NoError(err)
+	s.Require().Equal(user.ID, got.UserID)
+	s.Require().Equal(uint32(5), got.SignCount)
+	s.Require().Equal([]string{"internal", "hybrid"}, got.Transports)
+	s.Require().Nil(got.LastUsedAt)
+
+	count, err := s.repo.CountByUser(s.ctx, user.ID)
+	s.Require().NoError(err)
+	s.Require().Equal(1, count)
+
+	// 只能操作自己的凭据
+	s.Require().ErrorIs(s.repo.Rename(s.ctx, other.ID, cred.ID, "stolen"), service.ErrWebAuthnCredentialNotFound)
+	s.Require().NoError(s.repo.Rename(s.ctx, user.ID, cred.ID, "Work laptop"))
+
+	// 计数器只能前进
+	now := time.Now()
+	s.Require().NoError(s.repo.UpdateUsage(s.ctx, cred.ID, 6, true, now))
+	s.Require().ErrorIs(s.repo.UpdateUsage(s.ctx, cred.ID, 6, true, now), service.ErrWebAuthnVerificationFailed)
+
+	list, err := s.repo.ListByUser(s.ctx, user.ID)
+	s.Require().NoError(err)
+	s.Require().Len(list, 1)
+	s.Require().Equal("Work laptop", list[0].Name)
+	s.Require().True(list[0].BackedUp)
+	s.Require().NotNil(list[0].LastUsedAt)
+
+	s.Require().ErrorIs(s.repo.Delete(s.ctx, other.ID, cred.ID), service.ErrWebAuthnCredentialNotFound)
+	s.Require().NoError(s.repo.Delete(s.ctx, user.ID, cred.ID))
+	_, err = s.repo.GetByCredentialID(s.ctx, []byte{0x01, 0x02, 0x03})
+	s.Require().ErrorIs(err, service.ErrWebAuthnCredentialNotFound)
+}
+
+func (s *WebAuthnCredentialRepoSuite) TestRecoveryCodes() {
+	user := mustCreateUser(s.T(), s.client, &service.User{Email: "recovery@example.com"})
+
+	s.Require().NoError(s.recovery.Replace(s.ctx, user.ID, []string{"a", "b"}))
+	s.Require().NoError(s.recovery.Replace(s.ctx, user.ID, []string{"c", "d", "e"}))
+
+	codes, err := s.recovery.ListByUser(s.ctx, user.ID)
+	s.Require().NoError(err)
+	s.Require().Len(codes, 3)
+	s.Require().Equal("c", codes[0].CodeHashEncrypted)
+
+	s.Require().NoError(s.recovery.MarkUsed(s.ctx, codes[0].ID, time.Now()))
+	s.Require().ErrorIs(s.recovery.MarkUsed(s.ctx, codes[0].ID, time.Now()), service.ErrRecoveryCodeInvalid)
+
+	codes, err = s.recovery.ListByUser(s.ctx, user.ID)
+	s.Require().NoError(err)
+	s.Require().NotNil(codes[0].UsedAt)
+	s.Require().Nil(codes[1].UsedAt)
+}
@@ -3,6 +3,7 @@ package repository
 import (
 	"database/sql"
 	"errors"
+	"strings"
 
 	entsql "entgo.io/ent/dialect/sql"
 	"github.com/Wei-Shaw/sub2api/ent"
@@ -47,6 +48,21 @@ func ProvideSessionLimitCache(rdb *redis.Client, cfg *config.Config) service.Ses
 	return NewSessionLimitCache(rdb, defaultIdleTimeoutMinutes)
 }
 
+// ProvideUsageJournalStore 创建使用量持久化日志存储，从配置读取 Stream 键名与消费者组
+func ProvideUsageJournalStore(rdb *redis.Client, cfg *config.Config) service.UsageJournalStore {
+	stream := "usage:journal"
+	group := "usage-billing"
+	if cfg != nil {
+		if v := strings.TrimSpace(cfg.Gateway.UsageRecord.Durable.StreamKey); v != "" {
+			stream = v
+		}
+		if v := strings.TrimSpace(cfg.Gateway.UsageRecord.Durable.ConsumerGroup); v != "" {
+			group = v
+		}
+	}
+	return NewUsageJournalStore(rdb, stream, group)
+}
+
 // ProviderSet is the Wire provider set for all repositories
 var ProviderSet = wire.NewSet(
 	NewUserRepository,
@@ -56,10 +72,23 @@ var ProviderSet = wire.NewSet(
 	NewSoraAccountRepository, // Sora 账号扩展表仓储
 	NewProxyRepository,
 	NewRedeemCodeRepository,
+	NewOrganizationRepository,
+	NewSSOProviderRepository,
+	NewWebAuthnCredentialRepository,
+	NewRecoveryCodeRepository,
+	NewLoginHistoryRepository,
+	NewPaymentProductRepository,
+	NewPaymentOrderRepository,
+	NewPointsRepository,
+	NewCouponRepository,
+	NewReferralRepository,
+	NewActivityTaskRepository,
 	NewPromoCodeRepository,
 	NewAnnouncementRepository,
 	NewAnnouncementReadRepository,
 	NewUsageLogRepository,
+	NewUsageBillingDedupRepository,
+	NewBalanceLedgerRepository,
 	NewIdempotencyRepository,
 	NewUsageCleanupRepository,
 	NewDashboardAggregationRepository,
@@ -67,6 +96,7 @@ var ProviderSet = wire.NewSet(
 	NewOpsRepository,
 	NewSecurityChatRepository,
 	NewSoraGenerationRepository,
+	NewBatchRepository,
 	NewUserSubscriptionRepository,
 	NewUserAttributeDefinitionRepository,
 	NewUserAttributeValueRepository,
@@ -76,12 +106,14 @@ var ProviderSet = wire.NewSet(
 	// Cache implementations
 	NewGatewayCache,
 	NewBillingCache,
+	NewBillingHoldCache,
 	NewAPIKeyCache,
 	NewTempUnschedCache,
 	NewTimeoutCounterCache,
 	ProvideConcurrencyCache,
 	ProvideSessionLimitCache,
 	NewRPMCache,
+	NewAPIKeyTrafficCache,
 	NewUserMsgQueueCache,
 	NewDashboardCache,
 	NewEmailCache,
@@ -93,14 +125,19 @@ var ProviderSet = wire.NewSet(
 	NewSchedulerOutboxRepository,
 	NewProxyLatencyCache,
 	NewTotpCache,
+	NewWebAuthnCache,
 	NewRefreshTokenCache,
 	NewErrorPassthroughCache,
+	NewResponsesConversationCache,
+	ProvideUsageJournalStore,
 
 	// Encryptors
 	NewAESEncryptor,
 
 	// HTTP service ports (DI Strategy A: return interface directly)
 	NewTurnstileVerifier,
+	NewSSOProviderClient,
+	NewPaymentProviders,
 	ProvidePricingRemoteClient,
 	ProvideGitHubReleaseClient,
 	NewProxyExitInfoProber,
@@ -17,6 +17,7 @@ import (
 	"github.com/Wei-Shaw/sub2api/internal/config"
 	"github.com/Wei-Shaw/sub2api/internal/handler"
 	adminhandler "github.com/Wei-Shaw/sub2api/internal/handler/admin"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
 	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
 	"github.com/Wei-Shaw/sub2api/internal/pkg/usagestats"
 	"github.com/Wei-Shaw/sub2api/internal/server/middleware"
@@ -95,6 +96,11 @@ func TestAPIContracts(t *testing.T) {
 					"window_5h_start": null,
 					"window_1d_start": null,
 					"window_7d_start": null,
+					"model_allowlist": null,
+					"model_denylist": null,
+					"rpm_limit": 0,
+					"tpm_limit": 0,
+					"max_concurrency": 0,
 					"expires_at": null,
 					"created_at": "2025-01-02T03:04:05Z",
 					"updated_at": "2025-01-02T03:04:05Z"
@@ -144,6 +150,11 @@ func TestAPIContracts(t *testing.T) {
 							"window_5h_start": null,
 							"window_1d_start": null,
 							"window_7d_start": null,
+							"model_allowlist": null,
+							"model_denylist": null,
+							"rpm_limit": 0,
+							"tpm_limit": 0,
+							"max_concurrency": 0,
 							"expires_at": null,
 							"created_at": "2025-01-02T03:04:05Z",
 							"updated_at": "2025-01-02T03:04:05Z"
@@ -209,6 +220,8 @@ func TestAPIContracts(t *testing.T) {
 							"sora_storage_quota_bytes": 0,
 							"sora_video_price_per_request": null,
 							"sora_video_price_per_request_hd": null,
+							"audio_price_per_minute": null,
+							"audio_speech_price_per_1m_chars": null,
 							"claude_code_only": false,
 						"fallback_group_id": null,
 						"fallback_group_id_on_invalid_request": null,
@@ -231,9 +244,9 @@ func TestAPIContracts(t *testing.T) {
 						StartsAt:        deps.now,
 						ExpiresAt:       time.Date(2099, 1, 2, 3, 4, 5, 0, time.UTC), // 使用未来日期避免 normalizeSubscriptionStatus 标记为过期
 						Status:          service.SubscriptionStatusActive,
-						DailyUsageUSD:   1.23,
-						WeeklyUsageUSD:  2.34,
-						MonthlyUsageUSD: 3.45,
+						DailyUsageUSD:   money.MustParse("1.23"),
+						WeeklyUsageUSD:  money.MustParse("2.34"),
+						MonthlyUsageUSD: money.MustParse("3.45"),
 						AssignedBy:      ptr(int64(999)),
 						AssignedAt:      deps.now,
 						Notes:           "admin-note",
@@ -278,7 +291,7 @@ func TestAPIContracts(t *testing.T) {
 						ID:        900,
 						Code:      "CODE-123",
 						Type:      service.RedeemTypeBalance,
-						Value:     1.25,
+						Value:     money.MustParse("1.25"),
 						Status:    service.StatusUsed,
 						UsedBy:    ptr(int64(1)),
 						UsedAt:    ptr(deps.now),
@@ -324,8 +337,8 @@ func TestAPIContracts(t *testing.T) {
 						OutputTokens:        20,
 						CacheCreationTokens: 1,
 						CacheReadTokens:     2,
-						TotalCost:           0.5,
-						ActualCost:          0.5,
+						TotalCost:           money.MustParse("0.5"),
+						ActualCost:          money.MustParse("0.5"),
 						DurationMs:          ptr(100),
 						CreatedAt:           deps.now,
 					},
@@ -337,8 +350,8 @@ func TestAPIContracts(t *testing.T) {
 						Model:        "claude-3",
 						InputTokens:  5,
 						OutputTokens: 15,
-						TotalCost:    0.25,
-						ActualCost:   0.25,
+						TotalCost:    money.MustParse("0.25"),
+						ActualCost:   money.MustParse("0.25"),
 						DurationMs:   ptr(300),
 						CreatedAt:    deps.now,
 					},
@@ -379,8 +392,8 @@ func TestAPIContracts(t *testing.T) {
 						OutputTokens:          20,
 						CacheCreationTokens:   1,
 						CacheReadTokens:       2,
-						TotalCost:             0.5,
-						ActualCost:            0.5,
+						TotalCost:             money.MustParse("0.5"),
+						ActualCost:            money.MustParse("0.5"),
 						RateMultiplier:        1,
 						BillingType:           service.BillingTypeBalance,
 						Stream:                true,
@@ -429,6 +442,8 @@ func TestAPIContracts(t *testing.T) {
 							"image_count": 0,
 							"image_size": null,
 							"media_type": null,
+							"audio_duration_seconds": null,
+							"audio_characters": null,
 							"cache_ttl_overridden": false,
 							"created_at": "2025-01-02T03:04:05Z",
 							"user_agent": null
@@ -601,7 +616,7 @@ func newContractDeps(t *testing.T) *contractDeps {
 				Username:      "alice",
 				Notes:         "hello",
 				Role:          service.RoleUser,
-				Balance:       12.5,
+				Balance:       money.MustParse("12.5"),
 				Concurrency:   5,
 				Status:        service.StatusActive,
 				AllowedGroups: nil,
@@ -642,7 +657,7 @@ func newContractDeps(t *testing.T) *contractDeps {
 	settingService := service.NewSettingService(settingRepo, cfg)
 
 	adminService := service.NewAdminService(userRepo, groupRepo, &accountRepo, nil, proxyRepo, apiKeyRepo, redeemRepo, nil, nil, nil, nil, nil, nil, nil, nil)
-	authHandler := handler.NewAuthHandler(cfg, nil, userService, settingService, nil, redeemService, nil)
+	authHandler := handler.NewAuthHandler(cfg, nil, userService, settingService, nil, redeemService, nil, nil, nil, nil, nil)
 	apiKeyHandler := handler.NewAPIKeyHandler(apiKeyService)
 	usageHandler := handler.NewUsageHandler(usageService, apiKeyService, nil, nil)
 	adminSettingHandler := adminhandler.NewSettingHandler(settingService, nil, nil, nil, nil)
@@ -781,11 +796,11 @@ func (r *stubUserRepo) ListWithFilters(ctx context.Context, params pagination.Pa
 	return nil, nil, errors.New("not implemented")
 }
 
-func (r *stubUserRepo) UpdateBalance(ctx context.Context, id int64, amount float64) error {
+func (r *stubUserRepo) UpdateBalance(ctx context.Context, id int64, amount money.Amount, _ service.BalanceLedgerSource) error {
 	return errors.New("not implemented")
 }
 
-func (r *stubUserRepo) DeductBalance(ctx context.Context, id int64, amount float64) error {
+func (r *stubUserRepo) DeductBalance(ctx context.Context, id int64, amount money.Amount, _ service.BalanceLedgerSource) error {
 	return errors.New("not implemented")
 }
 
@@ -1219,7 +1234,7 @@ func (stubRedeemCodeRepo) ListByUserPaginated(ctx context.Context, userID int64,
 	return nil, nil, errors.New("not implemented")
 }
 
-func (stubRedeemCodeRepo) SumPositiveBalanceByUser(ctx context.Context, userID int64) (float64, error) {
+func (stubRedeemCodeRepo) SumPositiveBalanceByUser(ctx context.Context, userID int64) (money.Amount, error) {
 	return 0, errors.New("not implemented")
 }
 
@@ -1302,7 +1317,7 @@ func (stubUserSubscriptionRepo) ResetWeeklyUsage(ctx context.Context, id int64,
 func (stubUserSubscriptionRepo) ResetMonthlyUsage(ctx context.Context, id int64, newWindowStart time.Time) error {
 	return errors.New("not implemented")
 }
-func (stubUserSubscriptionRepo) IncrementUsage(ctx context.Context, id int64, costUSD float64) error {
+func (stubUserSubscriptionRepo) IncrementUsage(ctx context.Context, id int64, costUSD money.Amount) error {
 	return errors.New("not implemented")
 }
 func (stubUserSubscriptionRepo) BatchUpdateExpiredStatus(ctx context.Context) (int64, error) {
@@ -1449,6 +1464,10 @@ func (r *stubApiKeyRepo) ListByUserID(ctx context.Context, userID int64, params
 	}, nil
 }
 
+func (r *stubApiKeyRepo) ListByCreator(ctx context.Context, userID, creatorID int64, params pagination.PaginationParams) ([]service.APIKey, *pagination.PaginationResult, error) {
+	return nil, nil, errors.New("not implemented")
+}
+
 func (r *stubApiKeyRepo) VerifyOwnership(ctx context.Context, userID int64, apiKeyIDs []int64) ([]int64, error) {
 	if len(apiKeyIDs) == 0 {
 		return []int64{}, nil
@@ -1507,7 +1526,7 @@ func (r *stubApiKeyRepo) ListKeysByGroupID(ctx context.Context, groupID int64) (
 	return nil, errors.New("not implemented")
 }
 
-func (r *stubApiKeyRepo) IncrementQuotaUsed(ctx context.Context, id int64, amount float64) (float64, error) {
+func (r *stubApiKeyRepo) IncrementQuotaUsed(ctx context.Context, id int64, amount money.Amount) (money.Amount, error) {
 	return 0, errors.New("not implemented")
 }
 
@@ -1525,7 +1544,7 @@ func (r *stubApiKeyRepo) UpdateLastUsed(ctx context.Context, id int64, usedAt ti
 	return nil
 }
 
-func (r *stubApiKeyRepo) IncrementRateLimitUsage(ctx context.Context, id int64, cost float64) error {
+func (r *stubApiKeyRepo) IncrementRateLimitUsage(ctx context.Context, id int64, cost money.Amount) error {
 	return nil
 }
 func (r *stubApiKeyRepo) ResetRateLimitWindows(ctx context.Context, id int64) error {
@@ -1643,8 +1662,8 @@ func (r *stubUsageLogRepo) GetUserStatsAggregated(ctx context.Context, userID in
 		totalInputTokens += int64(log.InputTokens)
 		totalOutputTokens += int64(log.OutputTokens)
 		totalCacheTokens += int64(log.CacheCreationTokens + log.CacheReadTokens)
-		totalCost += log.TotalCost
-		totalActualCost += log.ActualCost
+		totalCost += log.TotalCost.Float64()
+		totalActualCost += log.ActualCost.Float64()
 		if log.DurationMs != nil {
 			totalDuration += int64(*log.DurationMs)
 			durationCount++
@@ -4,6 +4,7 @@ package server
 import (
 	"log"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/Wei-Shaw/sub2api/internal/config"
@@ -22,6 +23,7 @@ import (
 var ProviderSet = wire.NewSet(
 	ProvideRouter,
 	ProvideHTTPServer,
+	ProvideMetricsServer,
 )
 
 // ProvideRouter 提供路由器
@@ -35,6 +37,7 @@ func ProvideRouter(
 	subscriptionService *service.SubscriptionService,
 	opsService *service.OpsService,
 	settingService *service.SettingService,
+	batchService *service.BatchService,
 	redisClient *redis.Client,
 ) *gin.Engine {
 	if cfg.Server.Mode == "release" {
@@ -56,7 +59,15 @@ func ProvideRouter(
 		}
 	}
 
-	return SetupRouter(r, handlers, jwtAuth, adminAuth, apiKeyAuth, apiKeyService, subscriptionService, opsService, settingService, cfg, redisClient)
+	// 未配置独立监听地址时，指标端点挂载在主端口上（不经过全局中间件）
+	if cfg.Metrics.Enabled && strings.TrimSpace(cfg.Metrics.ListenAddr) == "" {
+		r.GET(cfg.Metrics.Path, gin.WrapH(newMetricsHandler(cfg)))
+	}
+
+	router := SetupRouter(r, handlers, jwtAuth, adminAuth, apiKeyAuth, apiKeyService, subscriptionService, opsService, settingService, cfg, redisClient)
+	// 批处理执行器通过网关路由在进程内回放请求行，复用完整的认证/调度/计费链路
+	batchService.SetRequestHandler(router)
+	return router
 }
 
 // ProvideHTTPServer 提供 HTTP 服务器
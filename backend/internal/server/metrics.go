@@ -0,0 +1,69 @@
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/config"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/metrics"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+)
+
+// MetricsServer 在独立地址上暴露 Prometheus 指标（metrics.listen_addr 非空时启用）
+type MetricsServer struct {
+	srv *http.Server
+}
+
+// ProvideMetricsServer 创建并启动独立的指标服务器；未启用或挂载在主端口时返回空实现。
+// 依赖 PrometheusMetricsCollector 以确保抓取前采集器已注册。
+func ProvideMetricsServer(cfg *config.Config, _ *service.PrometheusMetricsCollector) *MetricsServer {
+	if !cfg.Metrics.Enabled || strings.TrimSpace(cfg.Metrics.ListenAddr) == "" {
+		return &MetricsServer{}
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(cfg.Metrics.Path, newMetricsHandler(cfg))
+	s := &MetricsServer{srv: &http.Server{
+		Addr:              cfg.Metrics.ListenAddr,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}}
+	go func() {
+		log.Printf("Metrics server listening on %s%s", cfg.Metrics.ListenAddr, cfg.Metrics.Path)
+		if err := s.srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Printf("Metrics server error: %v", err)
+		}
+	}()
+	return s
+}
+
+// Stop 关闭独立指标服务器
+func (s *MetricsServer) Stop(ctx context.Context) error {
+	if s == nil || s.srv == nil {
+		return nil
+	}
+	return s.srv.Shutdown(ctx)
+}
+
+// newMetricsHandler 返回指标处理器；配置了 bearer_token 时要求抓取方携带 Authorization: Bearer <token>
+func newMetricsHandler(cfg *config.Config) http.Handler {
+	handler := metrics.Handler()
+	token := strings.TrimSpace(cfg.Metrics.BearerToken)
+	if token == "" {
+		return handler
+	}
+	expected := []byte("Bearer " + token)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), expected) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="metrics"`)
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	})
+}
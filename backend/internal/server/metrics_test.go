@@ -0,0 +1,50 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Wei-Shaw/sub2api/internal/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewMetricsHandler_BearerToken(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Metrics.BearerToken = "scrape-secret"
+	h := newMetricsHandler(cfg)
+
+	cases := map[string]int{
+		"":                     http.StatusUnauthorized,
+		"Bearer wrong":         http.StatusUnauthorized,
+		"scrape-secret":        http.StatusUnauthorized,
+		"Bearer scrape-secret": http.StatusOK,
+	}
+	for header, want := range cases {
+		req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+		if header != "" {
+			req.Header.Set("Authorization", header)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		require.Equal(t, want, rec.Code, "Authorization=%q", header)
+	}
+}
+
+func TestNewMetricsHandler_NoToken(t *testing.T) {
+	rec := httptest.NewRecorder()
+	newMetricsHandler(&config.Config{}).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Contains(t, rec.Body.String(), "go_goroutines")
+}
+
+func TestProvideMetricsServer_DisabledOrShared(t *testing.T) {
+	cfg := &config.Config{}
+	require.NoError(t, ProvideMetricsServer(cfg, nil).Stop(context.Background()))
+
+	cfg.Metrics.Enabled = true
+	s := ProvideMetricsServer(cfg, nil)
+	require.Nil(t, s.srv, "metrics are served by the main router when listen_addr is empty")
+	require.NoError(t, s.Stop(context.Background()))
+}
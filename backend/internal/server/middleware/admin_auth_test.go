@@ -9,6 +9,7 @@ import (
 	"testing"
 
 	"github.com/Wei-Shaw/sub2api/internal/config"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
 	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
 	"github.com/Wei-Shaw/sub2api/internal/service"
 	"github.com/gin-gonic/gin"
@@ -161,11 +162,11 @@ func (s *stubUserRepo) ListWithFilters(ctx context.Context, params pagination.Pa
 	panic("unexpected ListWithFilters call")
 }
 
-func (s *stubUserRepo) UpdateBalance(ctx context.Context, id int64, amount float64) error {
+func (s *stubUserRepo) UpdateBalance(ctx context.Context, id int64, amount money.Amount, _ service.BalanceLedgerSource) error {
 	panic("unexpected UpdateBalance call")
 }
 
-func (s *stubUserRepo) DeductBalance(ctx context.Context, id int64, amount float64) error {
+func (s *stubUserRepo) DeductBalance(ctx context.Context, id int64, amount money.Amount, _ service.BalanceLedgerSource) error {
 	panic("unexpected DeductBalance call")
 }
 
@@ -12,6 +12,7 @@ import (
 
 	"github.com/Wei-Shaw/sub2api/internal/config"
 	"github.com/Wei-Shaw/sub2api/internal/pkg/ctxkey"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
 	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
 	"github.com/Wei-Shaw/sub2api/internal/service"
 	"github.com/gin-gonic/gin"
@@ -34,7 +35,7 @@ func TestSimpleModeBypassesQuotaCheck(t *testing.T) {
 		ID:          7,
 		Role:        service.RoleUser,
 		Status:      service.StatusActive,
-		Balance:     10,
+		Balance:     10 * money.USD,
 		Concurrency: 3,
 	}
 	apiKey := &service.APIKey{
@@ -148,7 +149,7 @@ func TestSimpleModeBypassesQuotaCheck(t *testing.T) {
 			Status:           service.SubscriptionStatusActive,
 			ExpiresAt:        now.Add(24 * time.Hour),
 			DailyWindowStart: &now,
-			DailyUsageUSD:    10,
+			DailyUsageUSD:    10 * money.USD,
 		}
 		subscriptionRepo := &stubUserSubscriptionRepo{
 			getActive: func(ctx context.Context, userID, groupID int64) (*service.UserSubscription, error) {
@@ -191,7 +192,7 @@ func TestAPIKeyAuthSetsGroupContext(t *testing.T) {
 		ID:          7,
 		Role:        service.RoleUser,
 		Status:      service.StatusActive,
-		Balance:     10,
+		Balance:     10 * money.USD,
 		Concurrency: 3,
 	}
 	apiKey := &service.APIKey{
@@ -235,6 +236,41 @@ func TestAPIKeyAuthSetsGroupContext(t *testing.T) {
 	require.Equal(t, http.StatusOK, w.Code)
 }
 
+func TestAPIKeyAuthMarksBatchRunnerRequests(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	user := &service.User{ID: 7, Role: service.RoleUser, Status: service.StatusActive, Balance: 10 * money.USD, Concurrency: 3}
+	apiKey := &service.APIKey{ID: 100, UserID: user.ID, Key: "test-key", Status: service.StatusActive, User: user}
+	apiKeyRepo := &stubApiKeyRepo{
+		getByKey: func(ctx context.Context, key string) (*service.APIKey, error) {
+			clone := *apiKey
+			return &clone, nil
+		},
+	}
+
+	cfg := &config.Config{RunMode: config.RunModeSimple}
+	apiKeyService := service.NewAPIKeyService(apiKeyRepo, nil, nil, nil, nil, nil, cfg)
+	router := gin.New()
+	router.Use(gin.HandlerFunc(NewAPIKeyAuthMiddleware(apiKeyService, nil, cfg)))
+	router.GET("/t", func(c *gin.Context) {
+		key, _ := GetAPIKeyFromContext(c)
+		c.JSON(http.StatusOK, gin.H{"batch_job_id": key.BatchJobID})
+	})
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/t", nil)
+	req.Header.Set("x-api-key", apiKey.Key)
+	router.ServeHTTP(w, req)
+	require.JSONEq(t, `{"batch_job_id":0}`, w.Body.String())
+
+	w = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodGet, "/t", nil)
+	req = req.WithContext(context.WithValue(req.Context(), ctxkey.BatchJobID, int64(42)))
+	req.Header.Set("x-api-key", apiKey.Key)
+	router.ServeHTTP(w, req)
+	require.JSONEq(t, `{"batch_job_id":42}`, w.Body.String())
+}
+
 func TestAPIKeyAuthOverwritesInvalidContextGroup(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
@@ -249,7 +285,7 @@ func TestAPIKeyAuthOverwritesInvalidContextGroup(t *testing.T) {
 		ID:          7,
 		Role:        service.RoleUser,
 		Status:      service.StatusActive,
-		Balance:     10,
+		Balance:     10 * money.USD,
 		Concurrency: 3,
 	}
 	apiKey := &service.APIKey{
@@ -307,7 +343,7 @@ func TestAPIKeyAuthIPRestrictionDoesNotTrustSpoofedForwardHeaders(t *testing.T)
 		ID:          7,
 		Role:        service.RoleUser,
 		Status:      service.StatusActive,
-		Balance:     10,
+		Balance:     10 * money.USD,
 		Concurrency: 3,
 	}
 	apiKey := &service.APIKey{
@@ -358,7 +394,7 @@ func TestAPIKeyAuthTouchesLastUsedOnSuccess(t *testing.T) {
 		ID:          7,
 		Role:        service.RoleUser,
 		Status:      service.StatusActive,
-		Balance:     10,
+		Balance:     10 * money.USD,
 		Concurrency: 3,
 	}
 	apiKey := &service.APIKey{
@@ -407,7 +443,7 @@ func TestAPIKeyAuthTouchLastUsedFailureDoesNotBlock(t *testing.T) {
 		ID:          8,
 		Role:        service.RoleUser,
 		Status:      service.StatusActive,
-		Balance:     10,
+		Balance:     10 * money.USD,
 		Concurrency: 3,
 	}
 	apiKey := &service.APIKey{
@@ -453,7 +489,7 @@ func TestAPIKeyAuthTouchesLastUsedInStandardMode(t *testing.T) {
 		ID:          9,
 		Role:        service.RoleUser,
 		Status:      service.StatusActive,
-		Balance:     10,
+		Balance:     10 * money.USD,
 		Concurrency: 3,
 	}
 	apiKey := &service.APIKey{
@@ -541,6 +577,10 @@ func (r *stubApiKeyRepo) ListByUserID(ctx context.Context, userID int64, params
 	return nil, nil, errors.New("not implemented")
 }
 
+func (r *stubApiKeyRepo) ListByCreator(ctx context.Context, userID, creatorID int64, params pagination.PaginationParams) ([]service.APIKey, *pagination.PaginationResult, error) {
+	return nil, nil, errors.New("not implemented")
+}
+
 func (r *stubApiKeyRepo) VerifyOwnership(ctx context.Context, userID int64, apiKeyIDs []int64) ([]int64, error) {
 	return nil, errors.New("not implemented")
 }
@@ -577,7 +617,7 @@ func (r *stubApiKeyRepo) ListKeysByGroupID(ctx context.Context, groupID int64) (
 	return nil, errors.New("not implemented")
 }
 
-func (r *stubApiKeyRepo) IncrementQuotaUsed(ctx context.Context, id int64, amount float64) (float64, error) {
+func (r *stubApiKeyRepo) IncrementQuotaUsed(ctx context.Context, id int64, amount money.Amount) (money.Amount, error) {
 	return 0, errors.New("not implemented")
 }
 
@@ -588,7 +628,7 @@ func (r *stubApiKeyRepo) UpdateLastUsed(ctx context.Context, id int64, usedAt ti
 	return nil
 }
 
-func (r *stubApiKeyRepo) IncrementRateLimitUsage(ctx context.Context, id int64, cost float64) error {
+func (r *stubApiKeyRepo) IncrementRateLimitUsage(ctx context.Context, id int64, cost money.Amount) error {
 	return nil
 }
 func (r *stubApiKeyRepo) ResetRateLimitWindows(ctx context.Context, id int64) error {
@@ -697,7 +737,7 @@ func (r *stubUserSubscriptionRepo) ResetMonthlyUsage(ctx context.Context, id int
 	return errors.New("not implemented")
 }
 
-func (r *stubUserSubscriptionRepo) IncrementUsage(ctx context.Context, id int64, costUSD float64) error {
+func (r *stubUserSubscriptionRepo) IncrementUsage(ctx context.Context, id int64, costUSD money.Amount) error {
 	return errors.New("not implemented")
 }
 
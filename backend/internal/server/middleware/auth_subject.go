@@ -26,3 +26,11 @@ func GetUserRoleFromContext(c *gin.Context) (string, bool) {
 	role, ok := value.(string)
 	return role, ok
 }
+
+// GetSessionIDFromContext returns the login session (refresh token family) of the
+// current access token. Tokens issued without a refresh token carry no session ID.
+func GetSessionIDFromContext(c *gin.Context) string {
+	value, _ := c.Get(string(ContextKeySessionID))
+	sessionID, _ := value.(string)
+	return sessionID
+}
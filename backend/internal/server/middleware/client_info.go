@@ -0,0 +1,20 @@
+package middleware
+
+import (
+	"github.com/Wei-Shaw/sub2api/internal/pkg/ip"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// ClientInfo 将客户端 IP 与 User-Agent 写入 request.Context()，
+// 供登录会话与登录记录使用。
+func ClientInfo() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := service.WithClientInfo(c.Request.Context(), service.ClientInfo{
+			IP:        ip.GetClientIP(c),
+			UserAgent: c.Request.UserAgent(),
+		})
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
@@ -73,6 +73,7 @@ func jwtAuth(authService *service.AuthService, userService *service.UserService)
 			Concurrency: user.Concurrency,
 		})
 		c.Set(string(ContextKeyUserRole), user.Role)
+		c.Set(string(ContextKeySessionID), claims.SessionID)
 
 		c.Next()
 	}
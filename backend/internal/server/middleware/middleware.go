@@ -24,6 +24,8 @@ const (
 	ContextKeySubscription ContextKey = "subscription"
 	// ContextKeyForcePlatform 强制平台（用于 /antigravity 路由）
 	ContextKeyForcePlatform ContextKey = "force_platform"
+	// ContextKeySessionID 当前 Access Token 所属的登录会话 ID（string，可能为空）
+	ContextKeySessionID ContextKey = "session_id"
 )
 
 // ForcePlatform 返回设置强制平台的中间件
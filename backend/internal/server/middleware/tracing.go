@@ -0,0 +1,113 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/Wei-Shaw/sub2api/internal/pkg/ctxkey"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/logger"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/tracing"
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+const traceIDHeader = "X-Trace-Id"
+
+// Tracing 为每个请求创建根 Span，并沿用客户端 W3C traceparent 头中的上游链路。
+// 需放在 RequestLogger 之后，以便把 trace_id 写入 request-scoped logger；未启用追踪时直接放行。
+func Tracing() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request == nil || !tracing.Enabled() {
+			c.Next()
+			return
+		}
+
+		ctx := otel.GetTextMapPropagator().Extract(c.Request.Context(), propagation.HeaderCarrier(c.Request.Header))
+		route := c.FullPath()
+		spanName := c.Request.Method
+		if route != "" {
+			spanName += " " + route
+		}
+		ctx, span := tracing.Start(ctx, spanName,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				attribute.String("http.request.method", c.Request.Method),
+				attribute.String("http.route", route),
+				attribute.String("url.path", c.Request.URL.Path),
+				attribute.String("client.address", c.ClientIP()),
+				attribute.String("user_agent.original", c.Request.UserAgent()),
+			),
+		)
+		defer span.End()
+
+		if traceID := tracing.TraceID(ctx); traceID != "" && span.SpanContext().IsSampled() {
+			c.Header(traceIDHeader, traceID)
+			ctx = logger.IntoContext(ctx, logger.FromContext(ctx).With(zap.String("trace_id", traceID)))
+		}
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+
+		status := c.Writer.Status()
+		span.SetAttributes(attribute.Int("http.response.status_code", status))
+		reqCtx := c.Request.Context()
+		if v, ok := reqCtx.Value(ctxkey.RequestID).(string); ok && v != "" {
+			span.SetAttributes(attribute.String("request_id", v))
+		}
+		if v, ok := reqCtx.Value(ctxkey.ClientRequestID).(string); ok && strings.TrimSpace(v) != "" {
+			span.SetAttributes(attribute.String("client_request_id", v))
+		}
+		if v, ok := reqCtx.Value(ctxkey.Model).(string); ok && v != "" {
+			span.SetAttributes(attribute.String("gen_ai.request.model", v))
+		}
+		if apiKey, ok := GetAPIKeyFromContext(c); ok && apiKey != nil {
+			span.SetAttributes(attribute.Int64("api_key.id", apiKey.ID), attribute.Int64("user.id", apiKey.UserID))
+			if apiKey.GroupID != nil {
+				span.SetAttributes(attribute.Int64("group.id", *apiKey.GroupID))
+			}
+		}
+		if status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(status))
+		}
+	}
+}
+
+// startAuthSpan 为鉴权阶段创建子 Span 并挂到请求上下文。返回的结束函数可重复调用：
+// 记录鉴权结果后结束 Span，并把请求上下文中的当前 Span 恢复为父 Span，
+// 使后续处理器创建的 Span 与鉴权 Span 平级而不是挂在其下。
+func startAuthSpan(c *gin.Context, name string) func() {
+	parent := trace.SpanFromContext(c.Request.Context())
+	ctx, span := tracing.Start(c.Request.Context(), name)
+	if !span.IsRecording() {
+		return func() {}
+	}
+	c.Request = c.Request.WithContext(ctx)
+
+	ended := false
+	return func() {
+		if ended {
+			return
+		}
+		ended = true
+		if apiKey, ok := GetAPIKeyFromContext(c); ok && apiKey != nil {
+			span.SetAttributes(attribute.Int64("api_key.id", apiKey.ID), attribute.Int64("user.id", apiKey.UserID))
+			if apiKey.GroupID != nil {
+				span.SetAttributes(attribute.Int64("group.id", *apiKey.GroupID))
+			}
+		}
+		if c.IsAborted() {
+			status := c.Writer.Status()
+			span.SetAttributes(attribute.Bool("auth.rejected", true), attribute.Int("http.response.status_code", status))
+			if status >= http.StatusInternalServerError {
+				span.SetStatus(codes.Error, http.StatusText(status))
+			}
+		}
+		span.End()
+		c.Request = c.Request.WithContext(trace.ContextWithSpan(c.Request.Context(), parent))
+	}
+}
@@ -0,0 +1,146 @@
+//go:build unit
+
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Wei-Shaw/sub2api/internal/pkg/tracing"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func installTracingRecorder(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	restore := tracing.UseTracerProvider(provider)
+	t.Cleanup(func() {
+		restore()
+		_ = provider.Shutdown(context.Background())
+	})
+	return recorder
+}
+
+func findEndedSpan(t *testing.T, recorder *tracetest.SpanRecorder, name string) sdktrace.ReadOnlySpan {
+	t.Helper()
+	for _, span := range recorder.Ended() {
+		if span.Name() == name {
+			return span
+		}
+	}
+	t.Fatalf("span %q not found", name)
+	return nil
+}
+
+func TestTracing_ContinuesClientTraceparent(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	recorder := installTracingRecorder(t)
+
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	const parentSpanID = "00f067aa0ba902b7"
+
+	var handlerTraceID string
+	r := gin.New()
+	r.Use(Tracing())
+	r.GET("/v1/models/:id", func(c *gin.Context) {
+		handlerTraceID = tracing.TraceID(c.Request.Context())
+		c.Status(http.StatusBadGateway)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/models/abc", nil)
+	req.Header.Set("traceparent", "00-"+traceID+"-"+parentSpanID+"-01")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	require.Equal(t, traceID, handlerTraceID)
+	require.Equal(t, traceID, w.Header().Get(traceIDHeader))
+
+	root := findEndedSpan(t, recorder, "GET /v1/models/:id")
+	require.Equal(t, traceID, root.SpanContext().TraceID().String())
+	require.Equal(t, parentSpanID, root.Parent().SpanID().String())
+	require.True(t, root.Parent().IsRemote())
+	require.Equal(t, trace.SpanKindServer, root.SpanKind())
+	require.Equal(t, "Error", root.Status().Code.String())
+}
+
+func TestTracing_DisabledPassesThrough(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.Use(Tracing())
+	r.GET("/ping", func(c *gin.Context) {
+		require.False(t, trace.SpanContextFromContext(c.Request.Context()).IsValid())
+		c.Status(http.StatusOK)
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ping", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Empty(t, w.Header().Get(traceIDHeader))
+}
+
+func TestStartAuthSpan_RestoresParentForHandlers(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	recorder := installTracingRecorder(t)
+
+	groupID := int64(9)
+	r := gin.New()
+	r.Use(Tracing())
+	r.Use(func(c *gin.Context) {
+		end := startAuthSpan(c, "auth.api_key")
+		defer end()
+		c.Set(string(ContextKeyAPIKey), &service.APIKey{ID: 3, UserID: 5, GroupID: &groupID})
+		end()
+		c.Next()
+	})
+	r.GET("/v1/messages", func(c *gin.Context) {
+		_, span := tracing.Start(c.Request.Context(), "handler")
+		span.End()
+		c.Status(http.StatusOK)
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/messages", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	root := findEndedSpan(t, recorder, "GET /v1/messages")
+	auth := findEndedSpan(t, recorder, "auth.api_key")
+	handler := findEndedSpan(t, recorder, "handler")
+	require.Equal(t, root.SpanContext().SpanID(), auth.Parent().SpanID())
+	require.Equal(t, root.SpanContext().SpanID(), handler.Parent().SpanID())
+	require.Len(t, recorder.Ended(), 3)
+}
+
+func TestStartAuthSpan_RecordsRejection(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	recorder := installTracingRecorder(t)
+
+	r := gin.New()
+	r.Use(Tracing())
+	r.Use(func(c *gin.Context) {
+		end := startAuthSpan(c, "auth.api_key")
+		defer end()
+		AbortWithError(c, http.StatusUnauthorized, "INVALID_API_KEY", "Invalid API key")
+	})
+	r.GET("/v1/messages", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/messages", nil))
+	require.Equal(t, http.StatusUnauthorized, w.Code)
+
+	auth := findEndedSpan(t, recorder, "auth.api_key")
+	attrs := map[string]any{}
+	for _, kv := range auth.Attributes() {
+		attrs[string(kv.Key)] = kv.Value.AsInterface()
+	}
+	require.Equal(t, true, attrs["auth.rejected"])
+	require.Equal(t, int64(http.StatusUnauthorized), attrs["http.response.status_code"])
+}
@@ -23,6 +23,12 @@ func RegisterAdminRoutes(
 		// 用户管理
 		registerUserManagementRoutes(admin, h)
 
+		// 余额流水
+		registerBalanceLedgerRoutes(admin, h)
+
+		// 登录记录
+		registerLoginHistoryRoutes(admin, h)
+
 		// 分组管理
 		registerGroupRoutes(admin, h)
 
@@ -52,6 +58,18 @@ func RegisterAdminRoutes(
 		// 分销管理
 		registerDistributorRoutes(admin, h)
 
+		// 支付商品、订单与对账
+		registerPaymentRoutes(admin, h)
+
+		// 推荐返佣
+		registerReferralRoutes(admin, h)
+
+		// 积分商城与积分调整
+		registerPointsRoutes(admin, h)
+
+		// 优惠券
+		registerCouponRoutes(admin, h)
+
 		// 优惠码管理
 		registerPromoCodeRoutes(admin, h)
 
@@ -76,6 +94,9 @@ func RegisterAdminRoutes(
 		// 用户属性管理
 		registerUserAttributeRoutes(admin, h)
 
+		// SSO 提供方管理
+		registerSSOProviderRoutes(admin, h)
+
 		// 错误透传规则管理
 		registerErrorPassthroughRoutes(admin, h)
 
@@ -110,6 +131,14 @@ func registerOpsRoutes(admin *gin.RouterGroup, h *handler.Handlers) {
 		ops.PUT("/alert-events/:id/status", h.Admin.Ops.UpdateAlertEventStatus)
 		ops.POST("/alert-silences", h.Admin.Ops.CreateAlertSilence)
 
+		// Notification channels (webhook / IM)
+		ops.GET("/notification-channels", h.Admin.Ops.ListNotificationChannels)
+		ops.POST("/notification-channels", h.Admin.Ops.CreateNotificationChannel)
+		ops.PUT("/notification-channels/:id", h.Admin.Ops.UpdateNotificationChannel)
+		ops.DELETE("/notification-channels/:id", h.Admin.Ops.DeleteNotificationChannel)
+		ops.POST("/notification-channels/:id/test", h.Admin.Ops.TestNotificationChannel)
+		ops.GET("/notification-deliveries", h.Admin.Ops.ListNotificationDeliveries)
+
 		// Email notification config (DB-backed)
 		ops.GET("/email-notification/config", h.Admin.Ops.GetEmailNotificationConfig)
 		ops.PUT("/email-notification/config", h.Admin.Ops.UpdateEmailNotificationConfig)
@@ -209,12 +238,31 @@ func registerUserManagementRoutes(admin *gin.RouterGroup, h *handler.Handlers) {
 		users.GET("/:id/usage", h.Admin.User.GetUserUsage)
 		users.GET("/:id/balance-history", h.Admin.User.GetBalanceHistory)
 
+		// 登录会话与登录记录
+		users.GET("/:id/sessions", h.Admin.UserSession.ListSessions)
+		users.DELETE("/:id/sessions", h.Admin.UserSession.RevokeAllSessions)
+		users.DELETE("/:id/sessions/:session_id", h.Admin.UserSession.RevokeSession)
+		users.GET("/:id/login-history", h.Admin.UserSession.ListUserLoginHistory)
+
 		// User attribute values
 		users.GET("/:id/attributes", h.Admin.UserAttribute.GetUserAttributes)
 		users.PUT("/:id/attributes", h.Admin.UserAttribute.UpdateUserAttributes)
 	}
 }
 
+func registerLoginHistoryRoutes(admin *gin.RouterGroup, h *handler.Handlers) {
+	admin.GET("/login-history", h.Admin.UserSession.SearchLoginHistory)
+}
+
+func registerBalanceLedgerRoutes(admin *gin.RouterGroup, h *handler.Handlers) {
+	ledger := admin.Group("/balance-ledger")
+	{
+		ledger.GET("", h.Admin.BalanceLedger.Search)
+		ledger.GET("/reconcile", h.Admin.BalanceLedger.GetReconcile)
+		ledger.POST("/reconcile", h.Admin.BalanceLedger.RunReconcile)
+	}
+}
+
 func registerGroupRoutes(admin *gin.RouterGroup, h *handler.Handlers) {
 	groups := admin.Group("/groups")
 	{
@@ -487,6 +535,10 @@ func registerUsageRoutes(admin *gin.RouterGroup, h *handler.Handlers) {
 		usage.GET("/cleanup-tasks", h.Admin.Usage.ListCleanupTasks)
 		usage.POST("/cleanup-tasks", h.Admin.Usage.CreateCleanupTask)
 		usage.POST("/cleanup-tasks/:id/cancel", h.Admin.Usage.CancelCleanupTask)
+		usage.GET("/journal", h.Admin.Usage.GetJournalStats)
+		usage.GET("/journal/pending", h.Admin.Usage.ListJournalPending)
+		usage.GET("/journal/dead-letters", h.Admin.Usage.ListJournalDeadLetters)
+		usage.POST("/journal/dead-letters/:id/requeue", h.Admin.Usage.RequeueJournalDeadLetter)
 	}
 }
 
@@ -502,6 +554,71 @@ func registerUserAttributeRoutes(admin *gin.RouterGroup, h *handler.Handlers) {
 	}
 }
 
+func registerPaymentRoutes(admin *gin.RouterGroup, h *handler.Handlers) {
+	payment := admin.Group("/payment")
+	{
+		payment.GET("/products", h.Admin.Payment.ListProducts)
+		payment.POST("/products", h.Admin.Payment.CreateProduct)
+		payment.PUT("/products/:id", h.Admin.Payment.UpdateProduct)
+		payment.DELETE("/products/:id", h.Admin.Payment.DeleteProduct)
+		payment.GET("/orders", h.Admin.Payment.ListOrders)
+		payment.GET("/orders/:id", h.Admin.Payment.GetOrder)
+		payment.POST("/orders/:id/close", h.Admin.Payment.CloseOrder)
+		payment.POST("/orders/:id/fulfill", h.Admin.Payment.RetryFulfill)
+		payment.POST("/orders/:id/sync", h.Admin.Payment.SyncOrder)
+		payment.POST("/orders/:id/refund", h.Admin.Payment.RefundOrder)
+		payment.GET("/reconciliation", h.Admin.Payment.Reconciliation)
+	}
+}
+
+func registerReferralRoutes(admin *gin.RouterGroup, h *handler.Handlers) {
+	referrals := admin.Group("/referrals")
+	{
+		referrals.GET("", h.Admin.Referral.ListRelations)
+		referrals.PUT("/:id/status", h.Admin.Referral.UpdateRelationStatus)
+		referrals.GET("/commissions", h.Admin.Referral.ListCommissions)
+		referrals.GET("/withdrawals", h.Admin.Referral.ListWithdrawals)
+		referrals.POST("/withdrawals/:id/process", h.Admin.Referral.ProcessWithdrawal)
+	}
+}
+
+func registerPointsRoutes(admin *gin.RouterGroup, h *handler.Handlers) {
+	points := admin.Group("/points")
+	{
+		points.GET("/items", h.Admin.Points.ListItems)
+		points.POST("/items", h.Admin.Points.CreateItem)
+		points.PUT("/items/:id", h.Admin.Points.UpdateItem)
+		points.DELETE("/items/:id", h.Admin.Points.DeleteItem)
+		points.GET("/exchanges", h.Admin.Points.ListExchanges)
+		points.GET("/users/:id", h.Admin.Points.GetUserPoints)
+		points.GET("/users/:id/ledger", h.Admin.Points.ListUserLedger)
+		points.POST("/adjust", h.Admin.Points.Adjust)
+	}
+}
+
+func registerCouponRoutes(admin *gin.RouterGroup, h *handler.Handlers) {
+	coupons := admin.Group("/coupons")
+	{
+		coupons.GET("", h.Admin.Coupon.ListCoupons)
+		coupons.POST("", h.Admin.Coupon.CreateCoupon)
+		coupons.GET("/issued", h.Admin.Coupon.ListIssued)
+		coupons.PUT("/:id", h.Admin.Coupon.UpdateCoupon)
+		coupons.DELETE("/:id", h.Admin.Coupon.DeleteCoupon)
+		coupons.POST("/:id/issue", h.Admin.Coupon.IssueCoupon)
+	}
+}
+
+func registerSSOProviderRoutes(admin *gin.RouterGroup, h *handler.Handlers) {
+	providers := admin.Group("/sso-providers")
+	{
+		providers.GET("", h.Admin.SSOProvider.List)
+		providers.POST("", h.Admin.SSOProvider.Create)
+		providers.GET("/:id", h.Admin.SSOProvider.Get)
+		providers.PUT("/:id", h.Admin.SSOProvider.Update)
+		providers.DELETE("/:id", h.Admin.SSOProvider.Delete)
+	}
+}
+
 func registerErrorPassthroughRoutes(admin *gin.RouterGroup, h *handler.Handlers) {
 	rules := admin.Group("/error-passthrough-rules")
 	{
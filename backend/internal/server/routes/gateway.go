@@ -30,6 +30,7 @@ func RegisterGatewayRoutes(
 	soraBodyLimit := middleware.RequestBodyLimit(soraMaxBodySize)
 	clientRequestID := middleware.ClientRequestID()
 	opsErrorLogger := handler.OpsErrorLoggerMiddleware(opsService)
+	gatewayMetrics := handler.GatewayMetricsMiddleware(cfg.Metrics.Enabled)
 
 	// 未分组 Key 拦截中间件（按协议格式区分错误响应）
 	requireGroupAnthropic := middleware.RequireGroupAssignment(settingService, middleware.AnthropicErrorWriter)
@@ -39,6 +40,7 @@ func RegisterGatewayRoutes(
 	gateway := r.Group("/v1")
 	gateway.Use(bodyLimit)
 	gateway.Use(clientRequestID)
+	gateway.Use(gatewayMetrics)
 	gateway.Use(opsErrorLogger)
 	gateway.Use(gin.HandlerFunc(apiKeyAuth))
 	gateway.Use(requireGroupAnthropic)
@@ -68,8 +70,10 @@ func RegisterGatewayRoutes(
 		gateway.GET("/models", h.Gateway.Models)
 		gateway.GET("/usage", h.Gateway.Usage)
 		// OpenAI Responses API
-		gateway.POST("/responses", h.OpenAIGateway.Responses)
-		gateway.GET("/responses", h.OpenAIGateway.ResponsesWebSocket)
+		gateway.POST("/responses", openAICompatRoute(h.Gateway.Responses, h.OpenAIGateway.Responses))
+		gateway.GET("/responses", openAICompatRoute(h.Gateway.ResponsesWebSocket, h.OpenAIGateway.ResponsesWebSocket))
+		// OpenAI Embeddings API
+		gateway.POST("/embeddings", h.OpenAIGateway.Embeddings)
 		// OpenAI Image APIs
 		gateway.POST("/images/generations", h.OpenAIGateway.ImageGenerations)
 		gateway.POST("/images/edits", h.OpenAIGateway.ImageEdits)
@@ -78,15 +82,49 @@ func RegisterGatewayRoutes(
 		gateway.POST("/draw/result", h.OpenAIGateway.NanoBananaResult)
 		// OpenAI Video API
 		gateway.POST("/videos", h.OpenAIGateway.VideoGenerations)
+		// OpenAI Audio APIs
+		gateway.POST("/audio/transcriptions", h.OpenAIGateway.AudioTranscriptions)
+		gateway.POST("/audio/translations", h.OpenAIGateway.AudioTranslations)
+		gateway.POST("/audio/speech", h.OpenAIGateway.AudioSpeech)
+		// OpenAI Chat Completions
+		gateway.POST("/chat/completions", openAICompatRoute(h.Gateway.ChatCompletions, h.OpenAIGateway.ChatCompletions))
 		// OpenAI legacy endpoints (compat)
-		gateway.POST("/chat/completions", h.OpenAIGateway.ChatCompletions)
 		gateway.POST("/completions", h.OpenAIGateway.Completions)
 	}
 
+	// 异步批处理 API（OpenAI Files/Batches + Anthropic Message Batches）
+	// 输入文件可能远大于单次请求，使用独立的请求体上限
+	if h.Batch != nil && cfg.Batch.Enabled {
+		batchBodyLimit := middleware.RequestBodyLimit(int64(cfg.Batch.MaxFileSizeMB)<<20 + 1<<20)
+		batches := r.Group("/v1")
+		batches.Use(batchBodyLimit)
+		batches.Use(clientRequestID)
+		batches.Use(opsErrorLogger)
+		batches.Use(gin.HandlerFunc(apiKeyAuth))
+		batches.Use(requireGroupAnthropic)
+		{
+			batches.POST("/files", h.Batch.UploadFile)
+			batches.GET("/files", h.Batch.ListFiles)
+			batches.GET("/files/:file_id", h.Batch.GetFile)
+			batches.GET("/files/:file_id/content", h.Batch.GetFileContent)
+			batches.DELETE("/files/:file_id", h.Batch.DeleteFile)
+			batches.POST("/batches", h.Batch.CreateBatch)
+			batches.GET("/batches", h.Batch.ListBatches)
+			batches.GET("/batches/:batch_id", h.Batch.GetBatch)
+			batches.POST("/batches/:batch_id/cancel", h.Batch.CancelBatch)
+			batches.POST("/messages/batches", h.Batch.CreateMessageBatch)
+			batches.GET("/messages/batches", h.Batch.ListMessageBatches)
+			batches.GET("/messages/batches/:batch_id", h.Batch.GetMessageBatch)
+			batches.POST("/messages/batches/:batch_id/cancel", h.Batch.CancelMessageBatch)
+			batches.GET("/messages/batches/:batch_id/results", h.Batch.GetMessageBatchResults)
+		}
+	}
+
 	// Gemini 原生 API 兼容层（Gemini SDK/CLI 直连）
 	gemini := r.Group("/v1beta")
 	gemini.Use(bodyLimit)
 	gemini.Use(clientRequestID)
+	gemini.Use(gatewayMetrics)
 	gemini.Use(opsErrorLogger)
 	gemini.Use(middleware.APIKeyAuthWithSubscriptionGoogle(apiKeyService, subscriptionService, cfg))
 	gemini.Use(requireGroupGoogle)
@@ -98,8 +136,8 @@ func RegisterGatewayRoutes(
 	}
 
 	// OpenAI Responses API（不带v1前缀的别名）
-	r.POST("/responses", bodyLimit, clientRequestID, opsErrorLogger, gin.HandlerFunc(apiKeyAuth), requireGroupAnthropic, h.OpenAIGateway.Responses)
-	r.GET("/responses", bodyLimit, clientRequestID, opsErrorLogger, gin.HandlerFunc(apiKeyAuth), requireGroupAnthropic, h.OpenAIGateway.ResponsesWebSocket)
+	r.POST("/responses", bodyLimit, clientRequestID, gatewayMetrics, opsErrorLogger, gin.HandlerFunc(apiKeyAuth), requireGroupAnthropic, openAICompatRoute(h.Gateway.Responses, h.OpenAIGateway.Responses))
+	r.GET("/responses", bodyLimit, clientRequestID, gatewayMetrics, opsErrorLogger, gin.HandlerFunc(apiKeyAuth), requireGroupAnthropic, openAICompatRoute(h.Gateway.ResponsesWebSocket, h.OpenAIGateway.ResponsesWebSocket))
 
 	// Antigravity 模型列表
 	r.GET("/antigravity/models", gin.HandlerFunc(apiKeyAuth), requireGroupAnthropic, h.Gateway.AntigravityModels)
@@ -108,6 +146,7 @@ func RegisterGatewayRoutes(
 	antigravityV1 := r.Group("/antigravity/v1")
 	antigravityV1.Use(bodyLimit)
 	antigravityV1.Use(clientRequestID)
+	antigravityV1.Use(gatewayMetrics)
 	antigravityV1.Use(opsErrorLogger)
 	antigravityV1.Use(middleware.ForcePlatform(service.PlatformAntigravity))
 	antigravityV1.Use(gin.HandlerFunc(apiKeyAuth))
@@ -115,6 +154,9 @@ func RegisterGatewayRoutes(
 	{
 		antigravityV1.POST("/messages", h.Gateway.Messages)
 		antigravityV1.POST("/messages/count_tokens", h.Gateway.CountTokens)
+		antigravityV1.POST("/chat/completions", h.Gateway.ChatCompletions)
+		antigravityV1.POST("/responses", h.Gateway.Responses)
+		antigravityV1.GET("/responses", h.Gateway.ResponsesWebSocket)
 		antigravityV1.GET("/models", h.Gateway.AntigravityModels)
 		antigravityV1.GET("/usage", h.Gateway.Usage)
 	}
@@ -122,6 +164,7 @@ func RegisterGatewayRoutes(
 	antigravityV1Beta := r.Group("/antigravity/v1beta")
 	antigravityV1Beta.Use(bodyLimit)
 	antigravityV1Beta.Use(clientRequestID)
+	antigravityV1Beta.Use(gatewayMetrics)
 	antigravityV1Beta.Use(opsErrorLogger)
 	antigravityV1Beta.Use(middleware.ForcePlatform(service.PlatformAntigravity))
 	antigravityV1Beta.Use(middleware.APIKeyAuthWithSubscriptionGoogle(apiKeyService, subscriptionService, cfg))
@@ -136,6 +179,7 @@ func RegisterGatewayRoutes(
 	soraV1 := r.Group("/sora/v1")
 	soraV1.Use(soraBodyLimit)
 	soraV1.Use(clientRequestID)
+	soraV1.Use(gatewayMetrics)
 	soraV1.Use(opsErrorLogger)
 	soraV1.Use(middleware.ForcePlatform(service.PlatformSora))
 	soraV1.Use(gin.HandlerFunc(apiKeyAuth))
@@ -155,6 +199,20 @@ func RegisterGatewayRoutes(
 	r.GET("/sora/media-signed/*filepath", h.SoraGateway.MediaProxySigned)
 }
 
+// openAICompatRoute serves an OpenAI-format endpoint by group platform:
+// Claude/Gemini/Antigravity groups are translated to Anthropic Messages by
+// the Gateway handler, everything else stays on the OpenAI gateway.
+func openAICompatRoute(anthropicCompat, openAI gin.HandlerFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		switch getGroupPlatform(c) {
+		case service.PlatformAnthropic, service.PlatformGemini, service.PlatformAntigravity:
+			anthropicCompat(c)
+		default:
+			openAI(c)
+		}
+	}
+}
+
 // getGroupPlatform extracts the group platform from the API Key stored in context.
 func getGroupPlatform(c *gin.Context) string {
 	apiKey, ok := middleware.GetAPIKeyFromContext(c)
@@ -0,0 +1,30 @@
+package routes
+
+import (
+	"github.com/Wei-Shaw/sub2api/internal/handler"
+	"github.com/Wei-Shaw/sub2api/internal/server/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterPaymentRoutes 注册支付路由：商品与订单需要用户认证，渠道异步通知为公开接口（由各渠道验签）。
+func RegisterPaymentRoutes(
+	v1 *gin.RouterGroup,
+	h *handler.Handlers,
+	jwtAuth middleware.JWTAuthMiddleware,
+) {
+	payment := v1.Group("/payment")
+
+	payment.GET("/notify/:provider", h.Payment.Notify)
+	payment.POST("/notify/:provider", h.Payment.Notify)
+
+	authenticated := payment.Group("")
+	authenticated.Use(gin.HandlerFunc(jwtAuth), middleware.ClientInfo())
+	{
+		authenticated.GET("/products", h.Payment.ListProducts)
+		authenticated.GET("/orders", h.Payment.ListOrders)
+		authenticated.POST("/orders", h.Payment.CreateOrder)
+		authenticated.GET("/orders/:order_no", h.Payment.GetOrder)
+		authenticated.POST("/orders/:order_no/cancel", h.Payment.CancelOrder)
+	}
+}
@@ -0,0 +1,33 @@
+package routes
+
+import (
+	"github.com/Wei-Shaw/sub2api/internal/handler"
+	"github.com/Wei-Shaw/sub2api/internal/server/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterPointsRoutes 注册积分与优惠券路由：积分账户、积分商城兑换与用户优惠券（需要用户认证）。
+// 优惠券在创建支付订单时通过 coupon_id 使用。
+func RegisterPointsRoutes(
+	v1 *gin.RouterGroup,
+	h *handler.Handlers,
+	jwtAuth middleware.JWTAuthMiddleware,
+) {
+	points := v1.Group("/points")
+	points.Use(gin.HandlerFunc(jwtAuth))
+	{
+		points.GET("", h.Points.GetAccount)
+		points.GET("/ledger", h.Points.ListLedger)
+		points.GET("/shop", h.Points.ListShopItems)
+		points.POST("/shop/:id/exchange", h.Points.Exchange)
+		points.GET("/exchanges", h.Points.ListExchanges)
+	}
+
+	coupons := v1.Group("/coupons")
+	coupons.Use(gin.HandlerFunc(jwtAuth))
+	{
+		coupons.GET("", h.Coupon.ListCoupons)
+		coupons.GET("/:id/quote", h.Coupon.Quote)
+	}
+}
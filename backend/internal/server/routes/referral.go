@@ -0,0 +1,27 @@
+package routes
+
+import (
+	"github.com/Wei-Shaw/sub2api/internal/handler"
+	"github.com/Wei-Shaw/sub2api/internal/server/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterReferralRoutes 注册推荐返佣路由：推荐链接、仪表盘与佣金钱包（需要用户认证）。
+// 推荐关系在注册接口中通过 referral_code 建立。
+func RegisterReferralRoutes(
+	v1 *gin.RouterGroup,
+	h *handler.Handlers,
+	jwtAuth middleware.JWTAuthMiddleware,
+) {
+	referral := v1.Group("/referral")
+	referral.Use(gin.HandlerFunc(jwtAuth))
+	{
+		referral.GET("", h.Referral.GetOverview)
+		referral.GET("/referees", h.Referral.ListReferees)
+		referral.GET("/commissions", h.Referral.ListCommissions)
+		referral.GET("/dashboard", h.Referral.GetDashboard)
+		referral.GET("/withdrawals", h.Referral.ListWithdrawals)
+		referral.POST("/withdrawals", h.Referral.Withdraw)
+	}
+}
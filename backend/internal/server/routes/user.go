@@ -23,6 +23,13 @@ func RegisterUserRoutes(
 			user.PUT("/password", h.User.ChangePassword)
 			user.PUT("", h.User.UpdateProfile)
 
+			// 余额流水
+			user.GET("/balance/transactions", h.User.ListBalanceTransactions)
+
+			// 注册后使用优惠码
+			user.GET("/promo-codes", h.Promo.ListUsages)
+			user.POST("/promo-codes", h.Promo.Apply)
+
 			// TOTP 双因素认证
 			totp := user.Group("/totp")
 			{
@@ -33,6 +40,22 @@ func RegisterUserRoutes(
 				totp.POST("/enable", h.Totp.Enable)
 				totp.POST("/disable", h.Totp.Disable)
 			}
+
+			// Passkey 与双因素恢复码
+			security := user.Group("/security")
+			{
+				security.GET("/passkeys", h.Security.ListPasskeys)
+				security.POST("/passkeys/options", h.Security.BeginPasskeyRegistration)
+				security.POST("/passkeys", h.Security.RegisterPasskey)
+				security.PUT("/passkeys/:id", h.Security.RenamePasskey)
+				security.DELETE("/passkeys/:id", h.Security.DeletePasskey)
+				security.GET("/recovery-codes", h.Security.GetRecoveryCodeStatus)
+				security.POST("/recovery-codes", h.Security.RegenerateRecoveryCodes)
+				// 登录会话与登录记录
+				security.GET("/sessions", h.Security.ListSessions)
+				security.DELETE("/sessions/:id", h.Security.RevokeSession)
+				security.GET("/login-history", h.Security.ListLoginHistory)
+			}
 		}
 
 		// API Key管理
@@ -45,6 +68,30 @@ func RegisterUserRoutes(
 			keys.DELETE("/:id", h.APIKey.Delete)
 		}
 
+		// 组织/团队
+		organizations := authenticated.Group("/organizations")
+		{
+			organizations.GET("", h.Organization.List)
+			organizations.POST("", h.Organization.Create)
+			organizations.POST("/invitations/accept", h.Organization.AcceptInvitation)
+			organizations.GET("/:id", h.Organization.Get)
+			organizations.PUT("/:id", h.Organization.Update)
+			organizations.GET("/:id/members", h.Organization.ListMembers)
+			organizations.PUT("/:id/members/:user_id", h.Organization.UpdateMember)
+			organizations.DELETE("/:id/members/:user_id", h.Organization.RemoveMember)
+			organizations.POST("/:id/transfer-ownership", h.Organization.TransferOwnership)
+			organizations.GET("/:id/invitations", h.Organization.ListInvitations)
+			organizations.POST("/:id/invitations", h.Organization.Invite)
+			organizations.DELETE("/:id/invitations/:invitation_id", h.Organization.RevokeInvitation)
+			organizations.GET("/:id/keys", h.Organization.ListAPIKeys)
+			organizations.POST("/:id/keys", h.Organization.CreateAPIKey)
+			organizations.PUT("/:id/keys/:key_id", h.Organization.UpdateAPIKey)
+			organizations.DELETE("/:id/keys/:key_id", h.Organization.DeleteAPIKey)
+			organizations.POST("/:id/redeem", h.Organization.Redeem)
+			organizations.GET("/:id/transactions", h.Organization.ListTransactions)
+			organizations.GET("/:id/usage", h.Organization.GetUsage)
+		}
+
 		// 用户可用分组（非管理员接口）
 		groups := authenticated.Group("/groups")
 		{
@@ -88,6 +135,16 @@ func RegisterUserRoutes(
 			redeem.POST("/checkin", h.Redeem.DailyCheckin)
 		}
 
+		// 活动（签到、抽奖、兑换、任务、新手礼包）
+		activities := authenticated.Group("/activities")
+		{
+			activities.GET("", h.Activity.ListActivities)
+			activities.GET("/participations", h.Activity.GetUserParticipations)
+			activities.GET("/:id", h.Activity.GetActivity)
+			activities.GET("/:id/progress", h.Activity.GetTaskProgress)
+			activities.POST("/:id/participate", h.Activity.ParticipateInActivity)
+		}
+
 		// 用户订阅
 		subscriptions := authenticated.Group("/subscriptions")
 		{
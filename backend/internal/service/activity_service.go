@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"math/rand"
+	"strconv"
+	"sync"
 	"time"
 
 	"github.com/Wei-Shaw/sub2api/ent"
@@ -14,16 +16,32 @@ import (
 	"github.com/Wei-Shaw/sub2api/ent/user"
 	"github.com/Wei-Shaw/sub2api/ent/usersubscription"
 	"github.com/Wei-Shaw/sub2api/internal/domain"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
 )
 
 // ActivityService 活动服务
 type ActivityService struct {
-	client *ent.Client
+	client        *ent.Client
+	userRepo      UserRepository
+	taskRepo      ActivityTaskRepository
+	pointsService *PointsService
+	couponService *CouponService
+
+	startOnce sync.Once
+	stopOnce  sync.Once
+	stopCh    chan struct{}
 }
 
 // NewActivityService 创建活动服务
-func NewActivityService(client *ent.Client) *ActivityService {
-	return &ActivityService{client: client}
+func NewActivityService(client *ent.Client, userRepo UserRepository, taskRepo ActivityTaskRepository, pointsService *PointsService, couponService *CouponService) *ActivityService {
+	return &ActivityService{
+		client:        client,
+		userRepo:      userRepo,
+		taskRepo:      taskRepo,
+		pointsService: pointsService,
+		couponService: couponService,
+		stopCh:        make(chan struct{}),
+	}
 }
 
 // ===== 活动查询 =====
@@ -86,7 +104,7 @@ func (s *ActivityService) isActivityVisibleToUser(act *ent.Activity, u *ent.User
 	rules := act.VisibilityRules
 
 	// 检查最小余额
-	if minBalance, ok := rules["min_balance"].(float64); ok && u.Balance < minBalance {
+	if minBalance, ok := rules["min_balance"].(float64); ok && u.Balance < money.FromFloat(minBalance) {
 		return false
 	}
 
@@ -160,7 +178,7 @@ func (s *ActivityService) ParticipateInActivity(ctx context.Context, userID, act
 		return s.handleLottery(ctx, userID, activityID, act, ipAddress, userAgent)
 	case activity.TypeRedeem:
 		return s.handleRedeem(ctx, userID, activityID, act, ipAddress, userAgent)
-	case activity.TypeTask:
+	case activity.TypeTask, activity.TypeLimitedTime:
 		return s.handleTask(ctx, userID, activityID, act, ipAddress, userAgent)
 	case activity.TypeNewbie:
 		return s.handleNewbie(ctx, userID, activityID, act, ipAddress, userAgent)
@@ -498,14 +516,16 @@ func (s *ActivityService) handleRedeem(ctx context.Context, userID, activityID i
 		if err != nil {
 			return nil, err
 		}
-		if u.Balance < costBalance {
+		cost := money.FromFloat(costBalance)
+		if u.Balance < cost {
 			return nil, fmt.Errorf("insufficient balance")
 		}
 
-		// 扣除余额
-		_, err = s.client.User.UpdateOneID(userID).
-			AddBalance(-costBalance).
-			Save(ctx)
+		// 扣除余额（经 UserRepository 记入余额流水）
+		err = s.userRepo.DeductBalance(ctx, userID, cost, BalanceLedgerSource{
+			Type:        BalanceLedgerSourceActivityCost,
+			ReferenceID: strconv.FormatInt(activityID, 10),
+		})
 		if err != nil {
 			return nil, fmt.Errorf("failed to deduct balance: %w", err)
 		}
@@ -559,12 +579,6 @@ func (s *ActivityService) handleRedeem(ctx context.Context, userID, activityID i
 	}, nil
 }
 
-// handleTask 处理任务活动
-func (s *ActivityService) handleTask(ctx context.Context, userID, activityID int64, act *ent.Activity, ipAddress, userAgent string) (*ParticipationResult, error) {
-	// TODO: 实现任务系统
-	return nil, fmt.Errorf("task activity not implemented yet")
-}
-
 // handleNewbie 处理新手礼包
 func (s *ActivityService) handleNewbie(ctx context.Context, userID, activityID int64, act *ent.Activity, ipAddress, userAgent string) (*ParticipationResult, error) {
 	// 新手礼包只能领取一次
@@ -645,9 +659,10 @@ func (s *ActivityService) distributeRewards(ctx context.Context, userID int64, r
 		case activityreward.RewardTypeBalance:
 			// 发放余额
 			if amount, ok := rewardValue["amount"].(float64); ok {
-				_, err := s.client.User.UpdateOneID(userID).
-					AddBalance(amount).
-					Save(ctx)
+				err := s.userRepo.UpdateBalance(ctx, userID, money.FromFloat(amount), BalanceLedgerSource{
+					Type:        BalanceLedgerSourceActivityReward,
+					ReferenceID: strconv.FormatInt(reward.ActivityID, 10),
+				})
 				if err != nil {
 					return nil, fmt.Errorf("failed to add balance: %w", err)
 				}
@@ -673,9 +688,31 @@ func (s *ActivityService) distributeRewards(ctx context.Context, userID int64, r
 			}
 
 		case activityreward.RewardTypeCoupon:
-			// TODO: 实现优惠券系统
+			// 发放优惠券
+			if couponID, ok := rewardValue["coupon_id"].(float64); ok {
+				if s.couponService == nil {
+					return nil, fmt.Errorf("coupon service not available")
+				}
+				_, err := s.couponService.Issue(ctx, int64(couponID), userID, CouponSourceActivity, strconv.FormatInt(reward.ActivityID, 10))
+				if err != nil {
+					return nil, fmt.Errorf("failed to issue coupon: %w", err)
+				}
+			}
+
 		case activityreward.RewardTypePoints:
-			// TODO: 实现积分系统
+			// 发放积分
+			if points, ok := rewardValue["points"].(float64); ok && points >= 1 {
+				if s.pointsService == nil {
+					return nil, fmt.Errorf("points service not available")
+				}
+				_, err := s.pointsService.Credit(ctx, userID, int64(points), PointsLedgerSource{
+					Type:        PointsSourceActivityReward,
+					ReferenceID: strconv.FormatInt(reward.ActivityID, 10),
+				})
+				if err != nil {
+					return nil, fmt.Errorf("failed to add points: %w", err)
+				}
+			}
 		}
 
 		rewardInfos = append(rewardInfos, &RewardInfo{
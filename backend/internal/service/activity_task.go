@@ -0,0 +1,291 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/ent"
+	"github.com/Wei-Shaw/sub2api/ent/activity"
+	infraerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
+)
+
+// 任务目标类型
+const (
+	ActivityGoalRequestCount    = "request_count"    // 请求次数，可按模型过滤
+	ActivityGoalSpend           = "spend"            // 实际消费金额（USD），可按模型过滤
+	ActivityGoalConsecutiveDays = "consecutive_days" // 连续使用 API 的天数（UTC 自然日）
+	ActivityGoalBind2FA         = "bind_2fa"         // 绑定两步验证
+)
+
+// 任务进度重置周期
+const (
+	ActivityTaskResetNone    = ""
+	ActivityTaskResetDaily   = "daily"
+	ActivityTaskResetWeekly  = "weekly"
+	ActivityTaskResetMonthly = "monthly"
+)
+
+// 任务完成记录状态
+const (
+	ActivityTaskCompletionPending = "pending" // 已完成，奖励待领取
+	ActivityTaskCompletionClaimed = "claimed" // 奖励已发放
+)
+
+var (
+	ErrActivityNotTask             = infraerrors.BadRequest("ACTIVITY_NOT_TASK", "activity is not a task activity")
+	ErrActivityTaskInvalidConfig   = infraerrors.BadRequest("ACTIVITY_TASK_INVALID_CONFIG", "task activity has no valid goals")
+	ErrActivityLimitedTimeNoEnd    = infraerrors.BadRequest("ACTIVITY_LIMITED_TIME_NO_END", "limited-time activity must have an end time")
+	ErrActivityTaskIncomplete      = infraerrors.BadRequest("ACTIVITY_TASK_INCOMPLETE", "task goals are not completed yet")
+	ErrActivityTaskAlreadyClaimed  = infraerrors.Conflict("ACTIVITY_TASK_ALREADY_CLAIMED", "task reward has already been claimed")
+	ErrActivityTaskNotEligible     = infraerrors.Forbidden("ACTIVITY_TASK_NOT_ELIGIBLE", "user is not eligible for this activity")
+	ErrActivityTaskRewardsNotFound = infraerrors.BadRequest("ACTIVITY_TASK_NO_REWARDS", "no rewards configured for task activity")
+)
+
+// ActivityTaskGoal 任务目标，来自 activity_config.goals
+type ActivityTaskGoal struct {
+	Type   string  `json:"type"`
+	Target float64 `json:"target"`
+	// Model 仅统计模型名包含该子串（不区分大小写）的请求，如 "claude"
+	Model string `json:"model,omitempty"`
+	Name  string `json:"name,omitempty"`
+}
+
+// ActivityTaskConfig 任务活动配置，示例：
+//
+//	{"goals": [{"type": "request_count", "target": 100}, {"type": "spend", "target": 5, "model": "claude"}],
+//	 "reset": "daily", "auto_reward": true}
+//
+// auto_reward 缺省时 limited_time 活动自动发放，task 活动需用户领取。
+type ActivityTaskConfig struct {
+	Goals      []ActivityTaskGoal `json:"goals"`
+	Reset      string             `json:"reset"`
+	AutoReward bool               `json:"auto_reward"`
+}
+
+// ActivityTaskKey 一个用户在任务活动某个周期内的进度
+type ActivityTaskKey struct {
+	ActivityID int64
+	UserID     int64
+	PeriodKey  string
+}
+
+// ActivityUsageEvent 计入任务进度的一条使用记录
+type ActivityUsageEvent struct {
+	ID         int64
+	UserID     int64
+	Model      string
+	ActualCost money.Amount
+	CreatedAt  time.Time
+}
+
+// ActivityTaskProgress 单个目标的进度
+type ActivityTaskProgress struct {
+	GoalIndex      int
+	Value          float64
+	LastActiveDate *time.Time
+	UpdatedAt      time.Time
+}
+
+// ActivityTaskCompletion 任务完成记录
+type ActivityTaskCompletion struct {
+	ID              int64
+	ActivityTaskKey ActivityTaskKey
+	Status          string
+	ParticipationID *int64
+	CompletedAt     time.Time
+	ClaimedAt       *time.Time
+}
+
+// ActivityTaskGoalStatus 单个目标的当前进度（展示用）
+type ActivityTaskGoalStatus struct {
+	ActivityTaskGoal
+	Value float64 `json:"value"`
+	Done  bool    `json:"done"`
+}
+
+// ActivityTaskStatus 用户在任务活动当前周期的进度
+type ActivityTaskStatus struct {
+	ActivityID  int64                    `json:"activity_id"`
+	PeriodKey   string                   `json:"period_key"`
+	AutoReward  bool                     `json:"auto_reward"`
+	Goals       []ActivityTaskGoalStatus `json:"goals"`
+	Completed   bool                     `json:"completed"`
+	Claimed     bool                     `json:"claimed"`
+	CompletedAt *time.Time               `json:"completed_at"`
+	ClaimedAt   *time.Time               `json:"claimed_at"`
+}
+
+// ActivityTaskRepository 任务进度、完成记录与使用记录游标存储。
+// 在事务上下文中调用时加入该事务。
+type ActivityTaskRepository interface {
+	// LockUsageCursor 锁定并返回已累计到的 usage_logs.id，需在事务内调用
+	LockUsageCursor(ctx context.Context) (int64, error)
+	SaveUsageCursor(ctx context.Context, lastID int64) error
+	// ListUsageEvents 返回 id > afterID 且创建时间早于 before 的使用记录，按 id 升序
+	ListUsageEvents(ctx context.Context, afterID int64, before time.Time, limit int) ([]ActivityUsageEvent, error)
+
+	AddProgress(ctx context.Context, key ActivityTaskKey, goalIndex int, delta float64) error
+	// TouchStreak 记录 day 有使用：紧接上次使用日期时连续天数 +1，中断后重置为 1，同一天或更早的日期不变
+	TouchStreak(ctx context.Context, key ActivityTaskKey, goalIndex int, day time.Time) error
+	// SetProgress 写入状态类目标（如绑定 2FA）的进度，只增不减
+	SetProgress(ctx context.Context, key ActivityTaskKey, goalIndex int, value float64) error
+	ListProgress(ctx context.Context, key ActivityTaskKey) ([]ActivityTaskProgress, error)
+
+	// CreateCompletion 该周期已完成过时返回 false
+	CreateCompletion(ctx context.Context, key ActivityTaskKey) (bool, error)
+	// GetCompletion 未完成时返回 nil
+	GetCompletion(ctx context.Context, key ActivityTaskKey) (*ActivityTaskCompletion, error)
+	// ClaimCompletion 将 pending 记录标记为已领取，未完成或已领取时返回 false
+	ClaimCompletion(ctx context.Context, key ActivityTaskKey) (bool, error)
+	// ReleaseCompletion 奖励发放失败时退回 pending，允许重新领取
+	ReleaseCompletion(ctx context.Context, key ActivityTaskKey) error
+	SetCompletionParticipation(ctx context.Context, key ActivityTaskKey, participationID int64) error
+}
+
+// activityTask 已解析配置的任务活动
+type activityTask struct {
+	act *ent.Activity
+	cfg *ActivityTaskConfig
+}
+
+// isTaskActivityType 是否由任务引擎处理
+func isTaskActivityType(t activity.Type) bool {
+	return t == activity.TypeTask || t == activity.TypeLimitedTime
+}
+
+// parseActivityTask 解析并校验任务活动配置
+func parseActivityTask(act *ent.Activity) (*activityTask, error) {
+	if !isTaskActivityType(act.Type) {
+		return nil, ErrActivityNotTask
+	}
+	if act.Type == activity.TypeLimitedTime && act.EndsAt == nil {
+		return nil, ErrActivityLimitedTimeNoEnd
+	}
+	if act.ActivityConfig == nil {
+		return nil, ErrActivityTaskInvalidConfig
+	}
+
+	raw, err := json.Marshal(act.ActivityConfig)
+	if err != nil {
+		return nil, fmt.Errorf("marshal activity config: %w", err)
+	}
+	var cfg ActivityTaskConfig
+	if err := json.Unmarshal(raw, &cfg); err != nil {
+		return nil, ErrActivityTaskInvalidConfig.WithCause(err)
+	}
+	if _, ok := act.ActivityConfig["auto_reward"]; !ok {
+		cfg.AutoReward = act.Type == activity.TypeLimitedTime
+	}
+
+	switch cfg.Reset {
+	case ActivityTaskResetNone, ActivityTaskResetDaily, ActivityTaskResetWeekly, ActivityTaskResetMonthly:
+	default:
+		return nil, ErrActivityTaskInvalidConfig
+	}
+	if len(cfg.Goals) == 0 {
+		return nil, ErrActivityTaskInvalidConfig
+	}
+	for i := range cfg.Goals {
+		g := &cfg.Goals[i]
+		g.Model = strings.ToLower(strings.TrimSpace(g.Model))
+		switch g.Type {
+		case ActivityGoalBind2FA:
+			g.Target = 1
+		case ActivityGoalRequestCount, ActivityGoalSpend, ActivityGoalConsecutiveDays:
+			if g.Target <= 0 {
+				return nil, ErrActivityTaskInvalidConfig
+			}
+		default:
+			return nil, ErrActivityTaskInvalidConfig
+		}
+	}
+	return &activityTask{act: act, cfg: &cfg}, nil
+}
+
+// activeAt 时间点是否落在活动时间范围内
+func (t *activityTask) activeAt(at time.Time) bool {
+	if t.act.StartsAt != nil && at.Before(*t.act.StartsAt) {
+		return false
+	}
+	if t.act.EndsAt != nil && at.After(*t.act.EndsAt) {
+		return false
+	}
+	return true
+}
+
+func (t *activityTask) key(userID int64, at time.Time) ActivityTaskKey {
+	return ActivityTaskKey{
+		ActivityID: t.act.ID,
+		UserID:     userID,
+		PeriodKey:  activityTaskPeriodKey(t.cfg.Reset, at),
+	}
+}
+
+func (t *activityTask) hasGoal(goalType string) bool {
+	for _, g := range t.cfg.Goals {
+		if g.Type == goalType {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesModel 目标未限定模型时匹配所有请求
+func (g *ActivityTaskGoal) matchesModel(model string) bool {
+	return g.Model == "" || strings.Contains(strings.ToLower(model), g.Model)
+}
+
+// activityTaskPeriodKey 进度所属周期（UTC，与参与记录窗口一致）
+func activityTaskPeriodKey(reset string, at time.Time) string {
+	at = at.UTC()
+	switch reset {
+	case ActivityTaskResetDaily:
+		return at.Format("2006-01-02")
+	case ActivityTaskResetWeekly:
+		year, week := at.ISOWeek()
+		return fmt.Sprintf("%d-W%02d", year, week)
+	case ActivityTaskResetMonthly:
+		return at.Format("2006-01")
+	}
+	return ""
+}
+
+// activityTaskDay 使用记录所属的 UTC 自然日
+func activityTaskDay(at time.Time) time.Time {
+	at = at.UTC()
+	return time.Date(at.Year(), at.Month(), at.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// goalStatuses 按目标汇总进度；连续天数在中断后（最近使用早于昨天）显示为 0
+func (t *activityTask) goalStatuses(progress []ActivityTaskProgress, now time.Time) []ActivityTaskGoalStatus {
+	byIndex := make(map[int]ActivityTaskProgress, len(progress))
+	for _, p := range progress {
+		byIndex[p.GoalIndex] = p
+	}
+	yesterday := activityTaskDay(now).AddDate(0, 0, -1)
+
+	out := make([]ActivityTaskGoalStatus, 0, len(t.cfg.Goals))
+	for i, g := range t.cfg.Goals {
+		p := byIndex[i]
+		value := p.Value
+		done := value >= g.Target
+		if g.Type == ActivityGoalConsecutiveDays && !done && p.LastActiveDate != nil && p.LastActiveDate.Before(yesterday) {
+			value = 0
+		}
+		out = append(out, ActivityTaskGoalStatus{ActivityTaskGoal: g, Value: value, Done: done})
+	}
+	return out
+}
+
+func allActivityGoalsDone(goals []ActivityTaskGoalStatus) bool {
+	for _, g := range goals {
+		if !g.Done {
+			return false
+		}
+	}
+	return len(goals) > 0
+}
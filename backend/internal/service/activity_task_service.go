@@ -0,0 +1,476 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/ent"
+	"github.com/Wei-Shaw/sub2api/ent/activity"
+	"github.com/Wei-Shaw/sub2api/ent/activityparticipation"
+	"github.com/Wei-Shaw/sub2api/ent/activityreward"
+	"github.com/Wei-Shaw/sub2api/ent/user"
+	"github.com/Wei-Shaw/sub2api/ent/usersubscription"
+	"github.com/Wei-Shaw/sub2api/internal/domain"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/logger"
+)
+
+const (
+	activityTaskPollInterval = 30 * time.Second
+	// activityTaskUsageLag 只累计创建已超过该时长的使用记录，给并发事务提交留出时间，避免游标越过尚未可见的记录
+	activityTaskUsageLag    = 30 * time.Second
+	activityTaskBatchSize   = 1000
+	activityTaskMaxBatches  = 20
+	activityTaskLoopTimeout = 2 * time.Minute
+)
+
+// ===== 任务活动 =====
+
+// handleTask 领取任务活动（task / limited_time）奖励：全部目标达成后发放一次，按 reset 周期可重复完成
+func (s *ActivityService) handleTask(ctx context.Context, userID, activityID int64, act *ent.Activity, ipAddress, userAgent string) (*ParticipationResult, error) {
+	task, err := parseActivityTask(act)
+	if err != nil {
+		return nil, err
+	}
+	key := task.key(userID, time.Now())
+	if err := s.refreshStateGoals(ctx, task, key); err != nil {
+		return nil, err
+	}
+	if err := s.completeIfDone(ctx, task, key); err != nil {
+		return nil, err
+	}
+	return s.claimTask(ctx, task, key, ipAddress, userAgent)
+}
+
+// GetTaskProgress 用户在任务活动当前周期的进度；目标已达成且为自动发放时顺带发放奖励
+func (s *ActivityService) GetTaskProgress(ctx context.Context, userID, activityID int64) (*ActivityTaskStatus, error) {
+	act, err := s.GetActivityDetail(ctx, activityID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get activity: %w", err)
+	}
+	task, err := parseActivityTask(act)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	key := task.key(userID, now)
+	if act.Status == activity.StatusActive && task.activeAt(now) {
+		if err := s.refreshStateGoals(ctx, task, key); err != nil {
+			return nil, err
+		}
+		if err := s.evaluateTask(ctx, task, key); err != nil {
+			return nil, err
+		}
+	}
+
+	progress, err := s.taskRepo.ListProgress(ctx, key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list task progress: %w", err)
+	}
+	completion, err := s.taskRepo.GetCompletion(ctx, key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get task completion: %w", err)
+	}
+
+	status := &ActivityTaskStatus{
+		ActivityID: act.ID,
+		PeriodKey:  key.PeriodKey,
+		AutoReward: task.cfg.AutoReward,
+		Goals:      task.goalStatuses(progress, now),
+	}
+	if completion != nil {
+		status.Completed = true
+		status.Claimed = completion.Status == ActivityTaskCompletionClaimed
+		status.CompletedAt = &completion.CompletedAt
+		status.ClaimedAt = completion.ClaimedAt
+	}
+	return status, nil
+}
+
+// OnTotpEnabled 用户启用 2FA 后推进"绑定 2FA"目标
+func (s *ActivityService) OnTotpEnabled(ctx context.Context, userID int64) {
+	now := time.Now()
+	tasks, err := s.loadTaskActivities(ctx)
+	if err != nil {
+		logger.LegacyPrintf("service.activity", "[ActivityTask] load tasks failed: %v", err)
+		return
+	}
+	for _, task := range tasks {
+		if !task.activeAt(now) || !task.hasGoal(ActivityGoalBind2FA) {
+			continue
+		}
+		key := task.key(userID, now)
+		if err := s.setBind2FAProgress(ctx, task, key); err != nil {
+			logger.LegacyPrintf("service.activity", "[ActivityTask] bind_2fa progress failed activity=%d user=%d err=%v", task.act.ID, userID, err)
+			continue
+		}
+		if err := s.evaluateTask(ctx, task, key); err != nil {
+			logger.LegacyPrintf("service.activity", "[ActivityTask] evaluate failed activity=%d user=%d err=%v", task.act.ID, userID, err)
+		}
+	}
+}
+
+// refreshStateGoals 用当前状态补齐状态类目标（活动开始前已绑定 2FA 的用户）
+func (s *ActivityService) refreshStateGoals(ctx context.Context, task *activityTask, key ActivityTaskKey) error {
+	if !task.hasGoal(ActivityGoalBind2FA) {
+		return nil
+	}
+	u, err := s.userRepo.GetByID(ctx, key.UserID)
+	if err != nil {
+		return err
+	}
+	if !u.TotpEnabled {
+		return nil
+	}
+	return s.setBind2FAProgress(ctx, task, key)
+}
+
+func (s *ActivityService) setBind2FAProgress(ctx context.Context, task *activityTask, key ActivityTaskKey) error {
+	for i, g := range task.cfg.Goals {
+		if g.Type != ActivityGoalBind2FA {
+			continue
+		}
+		if err := s.taskRepo.SetProgress(ctx, key, i, 1); err != nil {
+			return fmt.Errorf("set task progress: %w", err)
+		}
+	}
+	return nil
+}
+
+// evaluateTask 目标全部达成时写入完成记录，自动发放的活动随即发放奖励
+func (s *ActivityService) evaluateTask(ctx context.Context, task *activityTask, key ActivityTaskKey) error {
+	if err := s.completeIfDone(ctx, task, key); err != nil {
+		if errors.Is(err, ErrActivityTaskNotEligible) {
+			return nil
+		}
+		return err
+	}
+	if !task.cfg.AutoReward {
+		return nil
+	}
+	completion, err := s.taskRepo.GetCompletion(ctx, key)
+	if err != nil || completion == nil || completion.Status != ActivityTaskCompletionPending {
+		return err
+	}
+	if _, err := s.claimTask(ctx, task, key, "", ""); err != nil && !errors.Is(err, ErrActivityTaskAlreadyClaimed) {
+		return err
+	}
+	return nil
+}
+
+// completeIfDone 目标全部达成且用户符合活动可见性规则时写入完成记录（幂等）
+func (s *ActivityService) completeIfDone(ctx context.Context, task *activityTask, key ActivityTaskKey) error {
+	existing, err := s.taskRepo.GetCompletion(ctx, key)
+	if err != nil {
+		return fmt.Errorf("failed to get task completion: %w", err)
+	}
+	if existing != nil {
+		return nil
+	}
+
+	progress, err := s.taskRepo.ListProgress(ctx, key)
+	if err != nil {
+		return fmt.Errorf("failed to list task progress: %w", err)
+	}
+	if !allActivityGoalsDone(task.goalStatuses(progress, time.Now())) {
+		return nil
+	}
+
+	u, err := s.client.User.Query().
+		Where(user.ID(key.UserID)).
+		WithSubscriptions(func(sq *ent.UserSubscriptionQuery) {
+			sq.Where(usersubscription.StatusEQ(domain.SubscriptionStatusActive))
+		}).
+		Only(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to query user: %w", err)
+	}
+	if !s.isActivityVisibleToUser(task.act, u) {
+		return ErrActivityTaskNotEligible
+	}
+
+	if _, err := s.taskRepo.CreateCompletion(ctx, key); err != nil {
+		return fmt.Errorf("failed to create task completion: %w", err)
+	}
+	return nil
+}
+
+// claimTask 发放已完成任务的奖励：先把完成记录从 pending 改为 claimed 占位，发放失败时退回
+func (s *ActivityService) claimTask(ctx context.Context, task *activityTask, key ActivityTaskKey, ipAddress, userAgent string) (*ParticipationResult, error) {
+	var rewards []*ent.ActivityReward
+	for _, r := range task.act.Edges.Rewards {
+		if r.Status == activityreward.StatusActive {
+			rewards = append(rewards, r)
+		}
+	}
+	if len(rewards) == 0 {
+		return nil, ErrActivityTaskRewardsNotFound
+	}
+
+	claimed, err := s.taskRepo.ClaimCompletion(ctx, key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim task: %w", err)
+	}
+	if !claimed {
+		completion, err := s.taskRepo.GetCompletion(ctx, key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get task completion: %w", err)
+		}
+		if completion == nil {
+			return nil, ErrActivityTaskIncomplete
+		}
+		return nil, ErrActivityTaskAlreadyClaimed
+	}
+
+	rewardInfos, err := s.distributeRewards(ctx, key.UserID, rewards)
+	if err != nil {
+		if releaseErr := s.taskRepo.ReleaseCompletion(ctx, key); releaseErr != nil {
+			logger.LegacyPrintf("service.activity", "[ActivityTask] release completion failed activity=%d user=%d err=%v", key.ActivityID, key.UserID, releaseErr)
+		}
+		return nil, fmt.Errorf("failed to distribute rewards: %w", err)
+	}
+
+	now := time.Now()
+	dailyWindow := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	weekStart := now.AddDate(0, 0, -int(now.Weekday()-time.Monday))
+	weeklyWindow := time.Date(weekStart.Year(), weekStart.Month(), weekStart.Day(), 0, 0, 0, 0, time.UTC)
+	monthlyWindow := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	extra := map[string]interface{}{"period_key": key.PeriodKey}
+	participation, err := s.client.ActivityParticipation.Create().
+		SetUserID(key.UserID).
+		SetActivityID(key.ActivityID).
+		SetParticipatedAt(now).
+		SetDailyWindow(dailyWindow).
+		SetWeeklyWindow(weeklyWindow).
+		SetMonthlyWindow(monthlyWindow).
+		SetResult(activityparticipation.ResultSuccess).
+		SetRewardsReceived(s.rewardInfosToJSON(rewardInfos)).
+		SetExtraData(extra).
+		SetIPAddress(ipAddress).
+		SetUserAgent(userAgent).
+		Save(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create participation: %w", err)
+	}
+	if err := s.taskRepo.SetCompletionParticipation(ctx, key, participation.ID); err != nil {
+		logger.LegacyPrintf("service.activity", "[ActivityTask] link participation failed activity=%d user=%d err=%v", key.ActivityID, key.UserID, err)
+	}
+
+	// 更新活动统计
+	_ = s.client.Activity.UpdateOneID(key.ActivityID).
+		AddTotalParticipations(1).
+		AddTotalRewardsDistributed(int64(len(rewardInfos))).
+		Exec(ctx)
+
+	return &ParticipationResult{
+		Success:       true,
+		Message:       "任务完成，奖励已发放",
+		Rewards:       rewardInfos,
+		ExtraData:     extra,
+		Participation: participation,
+	}, nil
+}
+
+// loadTaskActivities 加载进行中的任务活动；配置无效的活动跳过
+func (s *ActivityService) loadTaskActivities(ctx context.Context) ([]*activityTask, error) {
+	acts, err := s.client.Activity.Query().
+		Where(
+			activity.StatusEQ(activity.StatusActive),
+			activity.TypeIn(activity.TypeTask, activity.TypeLimitedTime),
+		).
+		WithRewards(func(rq *ent.ActivityRewardQuery) {
+			rq.Order(ent.Asc(activityreward.FieldSortOrder))
+		}).
+		All(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query task activities: %w", err)
+	}
+	tasks := make([]*activityTask, 0, len(acts))
+	for _, act := range acts {
+		task, err := parseActivityTask(act)
+		if err != nil {
+			logger.LegacyPrintf("service.activity", "[ActivityTask] skip activity=%d: %v", act.ID, err)
+			continue
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks, nil
+}
+
+// ===== 使用记录增量累计 =====
+
+// ProcessUsageEvents 按游标累计一批使用记录到任务进度，返回处理的记录数
+func (s *ActivityService) ProcessUsageEvents(ctx context.Context) (int, error) {
+	var (
+		processed int
+		tasks     []*activityTask
+		touched   map[ActivityTaskKey]struct{}
+	)
+	err := s.withTx(ctx, func(txCtx context.Context) error {
+		cursor, err := s.taskRepo.LockUsageCursor(txCtx)
+		if err != nil {
+			return fmt.Errorf("lock usage cursor: %w", err)
+		}
+		events, err := s.taskRepo.ListUsageEvents(txCtx, cursor, time.Now().Add(-activityTaskUsageLag), activityTaskBatchSize)
+		if err != nil {
+			return fmt.Errorf("list usage events: %w", err)
+		}
+		if len(events) == 0 {
+			return nil
+		}
+		if tasks, err = s.loadTaskActivities(ctx); err != nil {
+			return err
+		}
+		if touched, err = s.applyUsageEvents(txCtx, tasks, events); err != nil {
+			return err
+		}
+		processed = len(events)
+		return s.taskRepo.SaveUsageCursor(txCtx, events[len(events)-1].ID)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	byID := make(map[int64]*activityTask, len(tasks))
+	for _, task := range tasks {
+		byID[task.act.ID] = task
+	}
+	for key := range touched {
+		if err := s.evaluateTask(ctx, byID[key.ActivityID], key); err != nil {
+			logger.LegacyPrintf("service.activity", "[ActivityTask] evaluate failed activity=%d user=%d err=%v", key.ActivityID, key.UserID, err)
+		}
+	}
+	return processed, nil
+}
+
+// applyUsageEvents 将一批使用记录按 (活动, 用户, 周期, 目标) 聚合后写入进度，返回有变化的进度
+func (s *ActivityService) applyUsageEvents(ctx context.Context, tasks []*activityTask, events []ActivityUsageEvent) (map[ActivityTaskKey]struct{}, error) {
+	type goalKey struct {
+		key  ActivityTaskKey
+		goal int
+	}
+	deltas := make(map[goalKey]float64)
+	days := make(map[goalKey]map[time.Time]struct{})
+	touched := make(map[ActivityTaskKey]struct{})
+
+	for _, ev := range events {
+		for _, task := range tasks {
+			if !task.activeAt(ev.CreatedAt) {
+				continue
+			}
+			key := task.key(ev.UserID, ev.CreatedAt)
+			for i := range task.cfg.Goals {
+				g := &task.cfg.Goals[i]
+				if !g.matchesModel(ev.Model) {
+					continue
+				}
+				gk := goalKey{key: key, goal: i}
+				switch g.Type {
+				case ActivityGoalRequestCount:
+					deltas[gk]++
+				case ActivityGoalSpend:
+					if !ev.ActualCost.IsPositive() {
+						continue
+					}
+					deltas[gk] += ev.ActualCost.Float64()
+				case ActivityGoalConsecutiveDays:
+					if days[gk] == nil {
+						days[gk] = make(map[time.Time]struct{})
+					}
+					days[gk][activityTaskDay(ev.CreatedAt)] = struct{}{}
+				default:
+					continue
+				}
+				touched[key] = struct{}{}
+			}
+		}
+	}
+
+	for gk, delta := range deltas {
+		if err := s.taskRepo.AddProgress(ctx, gk.key, gk.goal, delta); err != nil {
+			return nil, fmt.Errorf("add task progress: %w", err)
+		}
+	}
+	for gk, set := range days {
+		sorted := make([]time.Time, 0, len(set))
+		for day := range set {
+			sorted = append(sorted, day)
+		}
+		sort.Slice(sorted, func(i, j int) bool { return sorted[i].Before(sorted[j]) })
+		for _, day := range sorted {
+			if err := s.taskRepo.TouchStreak(ctx, gk.key, gk.goal, day); err != nil {
+				return nil, fmt.Errorf("touch task streak: %w", err)
+			}
+		}
+	}
+	return touched, nil
+}
+
+// ===== 后台任务 =====
+
+// Start 启动任务进度累计
+func (s *ActivityService) Start() {
+	if s == nil || s.taskRepo == nil {
+		return
+	}
+	s.startOnce.Do(func() {
+		go s.runLoop()
+	})
+}
+
+// Stop 停止任务进度累计
+func (s *ActivityService) Stop() {
+	if s == nil {
+		return
+	}
+	s.stopOnce.Do(func() {
+		close(s.stopCh)
+	})
+}
+
+func (s *ActivityService) runLoop() {
+	ticker := time.NewTicker(activityTaskPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			ctx, cancel := context.WithTimeout(context.Background(), activityTaskLoopTimeout)
+			for i := 0; i < activityTaskMaxBatches; i++ {
+				n, err := s.ProcessUsageEvents(ctx)
+				if err != nil {
+					logger.LegacyPrintf("service.activity", "[ActivityTask] process usage events failed: %v", err)
+					break
+				}
+				if n < activityTaskBatchSize {
+					break
+				}
+			}
+			cancel()
+		case <-s.stopCh:
+			return
+		}
+	}
+}
+
+func (s *ActivityService) withTx(ctx context.Context, fn func(txCtx context.Context) error) error {
+	if ent.TxFromContext(ctx) != nil {
+		return fn(ctx)
+	}
+	tx, err := s.client.Tx(ctx)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err := fn(ent.NewTxContext(ctx, tx)); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+	return nil
+}
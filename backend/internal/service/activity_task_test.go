@@ -0,0 +1,355 @@
+//go:build unit
+
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	dbent "github.com/Wei-Shaw/sub2api/ent"
+	"github.com/Wei-Shaw/sub2api/ent/activity"
+	"github.com/Wei-Shaw/sub2api/ent/activityreward"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
+	"github.com/stretchr/testify/require"
+)
+
+// ---------- fakes ----------
+
+type activityTaskTestProgressKey struct {
+	key  ActivityTaskKey
+	goal int
+}
+
+type activityTaskTestRepo struct {
+	cursor      int64
+	events      []ActivityUsageEvent
+	progress    map[activityTaskTestProgressKey]*ActivityTaskProgress
+	completions map[ActivityTaskKey]*ActivityTaskCompletion
+}
+
+func newActivityTaskTestRepo() *activityTaskTestRepo {
+	return &activityTaskTestRepo{
+		progress:    map[activityTaskTestProgressKey]*ActivityTaskProgress{},
+		completions: map[ActivityTaskKey]*ActivityTaskCompletion{},
+	}
+}
+
+func (r *activityTaskTestRepo) LockUsageCursor(context.Context) (int64, error) { return r.cursor, nil }
+
+func (r *activityTaskTestRepo) SaveUsageCursor(_ context.Context, lastID int64) error {
+	r.cursor = lastID
+	return nil
+}
+
+func (r *activityTaskTestRepo) ListUsageEvents(_ context.Context, afterID int64, before time.Time, limit int) ([]ActivityUsageEvent, error) {
+	var out []ActivityUsageEvent
+	for _, ev := range r.events {
+		if ev.ID <= afterID {
+			continue
+		}
+		if !ev.CreatedAt.Before(before) || len(out) >= limit {
+			break
+		}
+		out = append(out, ev)
+	}
+	return out, nil
+}
+
+func (r *activityTaskTestRepo) row(key ActivityTaskKey, goal int) *ActivityTaskProgress {
+	k := activityTaskTestProgressKey{key: key, goal: goal}
+	p, ok := r.progress[k]
+	if !ok {
+		p = &ActivityTaskProgress{GoalIndex: goal}
+		r.progress[k] = p
+	}
+	return p
+}
+
+func (r *activityTaskTestRepo) AddProgress(_ context.Context, key ActivityTaskKey, goal int, delta float64) error {
+	r.row(key, goal).Value += delta
+	return nil
+}
+
+func (r *activityTaskTestRepo) TouchStreak(_ context.Context, key ActivityTaskKey, goal int, day time.Time) error {
+	p := r.row(key, goal)
+	switch {
+	case p.LastActiveDate == nil:
+		p.Value = 1
+	case !day.After(*p.LastActiveDate):
+		return nil
+	case day.Equal(p.LastActiveDate.AddDate(0, 0, 1)):
+		p.Value++
+	default:
+		p.Value = 1
+	}
+	p.LastActiveDate = &day
+	return nil
+}
+
+func (r *activityTaskTestRepo) SetProgress(_ context.Context, key ActivityTaskKey, goal int, value float64) error {
+	if p := r.row(key, goal); value > p.Value {
+		p.Value = value
+	}
+	return nil
+}
+
+func (r *activityTaskTestRepo) ListProgress(_ context.Context, key ActivityTaskKey) ([]ActivityTaskProgress, error) {
+	var out []ActivityTaskProgress
+	for k, p := range r.progress {
+		if k.key == key {
+			out = append(out, *p)
+		}
+	}
+	return out, nil
+}
+
+func (r *activityTaskTestRepo) CreateCompletion(_ context.Context, key ActivityTaskKey) (bool, error) {
+	if _, ok := r.completions[key]; ok {
+		return false, nil
+	}
+	r.completions[key] = &ActivityTaskCompletion{ActivityTaskKey: key, Status: ActivityTaskCompletionPending, CompletedAt: time.Now()}
+	return true, nil
+}
+
+func (r *activityTaskTestRepo) GetCompletion(_ context.Context, key ActivityTaskKey) (*ActivityTaskCompletion, error) {
+	c, ok := r.completions[key]
+	if !ok {
+		return nil, nil
+	}
+	cp := *c
+	return &cp, nil
+}
+
+func (r *activityTaskTestRepo) ClaimCompletion(_ context.Context, key ActivityTaskKey) (bool, error) {
+	c, ok := r.completions[key]
+	if !ok || c.Status != ActivityTaskCompletionPending {
+		return false, nil
+	}
+	now := time.Now()
+	c.Status = ActivityTaskCompletionClaimed
+	c.ClaimedAt = &now
+	return true, nil
+}
+
+func (r *activityTaskTestRepo) ReleaseCompletion(_ context.Context, key ActivityTaskKey) error {
+	r.completions[key].Status = ActivityTaskCompletionPending
+	return nil
+}
+
+func (r *activityTaskTestRepo) SetCompletionParticipation(_ context.Context, key ActivityTaskKey, participationID int64) error {
+	r.completions[key].ParticipationID = &participationID
+	return nil
+}
+
+// ---------- helpers ----------
+
+type activityTaskTestEnv struct {
+	svc    *ActivityService
+	repo   *activityTaskTestRepo
+	client *dbent.Client
+	users  map[int64]*User
+	userID int64
+}
+
+func newActivityTaskTestEnv(t *testing.T) *activityTaskTestEnv {
+	t.Helper()
+	client := newPromoStatsTestEntClient(t)
+	u, err := client.User.Create().
+		SetEmail("task@example.com").
+		SetPasswordHash("hash").
+		Save(context.Background())
+	require.NoError(t, err)
+
+	users := map[int64]*User{u.ID: {ID: u.ID, Email: u.Email, Status: StatusActive}}
+	repo := newActivityTaskTestRepo()
+	return &activityTaskTestEnv{
+		svc:    NewActivityService(client, newCheckinTestUserRepo(users), repo, nil, nil),
+		repo:   repo,
+		client: client,
+		users:  users,
+		userID: u.ID,
+	}
+}
+
+func (e *activityTaskTestEnv) createTask(t *testing.T, typ activity.Type, config map[string]interface{}, endsAt *time.Time) *dbent.Activity {
+	t.Helper()
+	ctx := context.Background()
+	act, err := e.client.Activity.Create().
+		SetName("task").
+		SetType(typ).
+		SetStatus(activity.StatusActive).
+		SetActivityConfig(config).
+		SetNillableEndsAt(endsAt).
+		Save(ctx)
+	require.NoError(t, err)
+	_, err = e.client.ActivityReward.Create().
+		SetActivityID(act.ID).
+		SetName("bonus").
+		SetRewardType(activityreward.RewardTypeBalance).
+		SetRewardValue(`{"amount": 2}`).
+		Save(ctx)
+	require.NoError(t, err)
+	return act
+}
+
+// ---------- tests ----------
+
+func TestParseActivityTask_ValidatesConfig(t *testing.T) {
+	end := time.Now().Add(time.Hour)
+	cases := []struct {
+		name string
+		act  *dbent.Activity
+		err  error
+	}{
+		{"not a task", &dbent.Activity{Type: activity.TypeLottery}, ErrActivityNotTask},
+		{"no goals", &dbent.Activity{Type: activity.TypeTask, ActivityConfig: map[string]interface{}{}}, ErrActivityTaskInvalidConfig},
+		{"unknown goal", &dbent.Activity{Type: activity.TypeTask, ActivityConfig: map[string]interface{}{
+			"goals": []interface{}{map[string]interface{}{"type": "invite_friends", "target": 1}},
+		}}, ErrActivityTaskInvalidConfig},
+		{"zero target", &dbent.Activity{Type: activity.TypeTask, ActivityConfig: map[string]interface{}{
+			"goals": []interface{}{map[string]interface{}{"type": ActivityGoalSpend}},
+		}}, ErrActivityTaskInvalidConfig},
+		{"bad reset", &dbent.Activity{Type: activity.TypeTask, ActivityConfig: map[string]interface{}{
+			"goals": []interface{}{map[string]interface{}{"type": ActivityGoalBind2FA}}, "reset": "hourly",
+		}}, ErrActivityTaskInvalidConfig},
+		{"limited time without end", &dbent.Activity{Type: activity.TypeLimitedTime, ActivityConfig: map[string]interface{}{
+			"goals": []interface{}{map[string]interface{}{"type": ActivityGoalBind2FA}},
+		}}, ErrActivityLimitedTimeNoEnd},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := parseActivityTask(tc.act)
+			require.ErrorIs(t, err, tc.err)
+		})
+	}
+
+	task, err := parseActivityTask(&dbent.Activity{Type: activity.TypeLimitedTime, EndsAt: &end, ActivityConfig: map[string]interface{}{
+		"goals": []interface{}{map[string]interface{}{"type": ActivityGoalSpend, "target": 5, "model": " Claude "}},
+	}})
+	require.NoError(t, err)
+	require.True(t, task.cfg.AutoReward, "limited_time defaults to automatic rewards")
+	require.True(t, task.cfg.Goals[0].matchesModel("claude-sonnet-4-5"))
+	require.False(t, task.cfg.Goals[0].matchesModel("gpt-5"))
+}
+
+func TestActivityTaskPeriodKey(t *testing.T) {
+	at := time.Date(2026, 10, 17, 23, 30, 0, 0, time.FixedZone("UTC+8", 8*3600))
+	require.Equal(t, "", activityTaskPeriodKey(ActivityTaskResetNone, at))
+	require.Equal(t, "2026-10-17", activityTaskPeriodKey(ActivityTaskResetDaily, at))
+	require.Equal(t, "2026-W42", activityTaskPeriodKey(ActivityTaskResetWeekly, at))
+	require.Equal(t, "2026-10", activityTaskPeriodKey(ActivityTaskResetMonthly, at))
+}
+
+func TestActivityTask_ClaimRequiresCompletedGoals(t *testing.T) {
+	env := newActivityTaskTestEnv(t)
+	ctx := context.Background()
+	act := env.createTask(t, activity.TypeTask, map[string]interface{}{
+		"goals": []interface{}{
+			map[string]interface{}{"type": ActivityGoalRequestCount, "target": 2},
+			map[string]interface{}{"type": ActivityGoalBind2FA},
+		},
+	}, nil)
+
+	_, err := env.svc.ParticipateInActivity(ctx, env.userID, act.ID, "", "")
+	require.ErrorIs(t, err, ErrActivityTaskIncomplete)
+
+	now := time.Now().Add(-time.Minute)
+	env.repo.events = []ActivityUsageEvent{
+		{ID: 1, UserID: env.userID, Model: "claude-sonnet-4-5", CreatedAt: now},
+		{ID: 2, UserID: env.userID, Model: "gpt-5", CreatedAt: now},
+	}
+	n, err := env.svc.ProcessUsageEvents(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 2, n)
+	require.Equal(t, int64(2), env.repo.cursor)
+
+	status, err := env.svc.GetTaskProgress(ctx, env.userID, act.ID)
+	require.NoError(t, err)
+	require.True(t, status.Goals[0].Done)
+	require.False(t, status.Goals[1].Done)
+	require.False(t, status.Completed)
+
+	// 活动开始前已绑定 2FA 的用户在领取时按当前状态补齐
+	env.users[env.userID].TotpEnabled = true
+	result, err := env.svc.ParticipateInActivity(ctx, env.userID, act.ID, "203.0.113.1", "ua")
+	require.NoError(t, err)
+	require.True(t, result.Success)
+	require.Len(t, result.Rewards, 1)
+	require.Equal(t, 2*money.USD, env.users[env.userID].Balance)
+
+	_, err = env.svc.ParticipateInActivity(ctx, env.userID, act.ID, "", "")
+	require.ErrorIs(t, err, ErrActivityTaskAlreadyClaimed)
+	require.Equal(t, 2*money.USD, env.users[env.userID].Balance)
+}
+
+func TestActivityTask_LimitedTimeAutoRewardOnUsage(t *testing.T) {
+	env := newActivityTaskTestEnv(t)
+	ctx := context.Background()
+	end := time.Now().Add(24 * time.Hour)
+	act := env.createTask(t, activity.TypeLimitedTime, map[string]interface{}{
+		"goals": []interface{}{map[string]interface{}{"type": ActivityGoalSpend, "target": 5, "model": "claude"}},
+	}, &end)
+
+	now := time.Now().Add(-time.Minute)
+	env.repo.events = []ActivityUsageEvent{
+		{ID: 10, UserID: env.userID, Model: "claude-opus-4", ActualCost: 3 * money.USD, CreatedAt: now},
+		{ID: 11, UserID: env.userID, Model: "gpt-5", ActualCost: 9 * money.USD, CreatedAt: now},
+		{ID: 12, UserID: env.userID, Model: "claude-haiku", ActualCost: 2 * money.USD, CreatedAt: now},
+		// 尚在延迟窗口内的记录不处理，游标停在它之前
+		{ID: 13, UserID: env.userID, Model: "claude-haiku", ActualCost: 2 * money.USD, CreatedAt: time.Now()},
+	}
+	_, err := env.svc.ProcessUsageEvents(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(12), env.repo.cursor)
+
+	key := ActivityTaskKey{ActivityID: act.ID, UserID: env.userID}
+	completion := env.repo.completions[key]
+	require.NotNil(t, completion)
+	require.Equal(t, ActivityTaskCompletionClaimed, completion.Status)
+	require.NotNil(t, completion.ParticipationID)
+	require.Equal(t, 2*money.USD, env.users[env.userID].Balance)
+}
+
+func TestActivityTask_ConsecutiveDaysAndTotpEvent(t *testing.T) {
+	env := newActivityTaskTestEnv(t)
+	ctx := context.Background()
+	act := env.createTask(t, activity.TypeTask, map[string]interface{}{
+		"goals": []interface{}{
+			map[string]interface{}{"type": ActivityGoalConsecutiveDays, "target": 3},
+			map[string]interface{}{"type": ActivityGoalBind2FA},
+		},
+		"auto_reward": true,
+	}, nil)
+
+	today := time.Now().Add(-time.Minute)
+	env.repo.events = []ActivityUsageEvent{
+		{ID: 1, UserID: env.userID, CreatedAt: today.AddDate(0, 0, -2)},
+		{ID: 2, UserID: env.userID, CreatedAt: today.AddDate(0, 0, -1)},
+		{ID: 3, UserID: env.userID, CreatedAt: today.AddDate(0, 0, -1)},
+		{ID: 4, UserID: env.userID, CreatedAt: today},
+	}
+	_, err := env.svc.ProcessUsageEvents(ctx)
+	require.NoError(t, err)
+
+	key := ActivityTaskKey{ActivityID: act.ID, UserID: env.userID}
+	require.Equal(t, float64(3), env.repo.row(key, 0).Value)
+	require.Nil(t, env.repo.completions[key], "2FA goal still open")
+
+	env.svc.OnTotpEnabled(ctx, env.userID)
+	require.Equal(t, ActivityTaskCompletionClaimed, env.repo.completions[key].Status)
+	require.Equal(t, 2*money.USD, env.users[env.userID].Balance)
+}
+
+func TestActivityTaskGoalStatuses_BrokenStreakShowsZero(t *testing.T) {
+	task := &activityTask{cfg: &ActivityTaskConfig{Goals: []ActivityTaskGoal{{Type: ActivityGoalConsecutiveDays, Target: 7}}}}
+	now := time.Now()
+	last := activityTaskDay(now).AddDate(0, 0, -3)
+	statuses := task.goalStatuses([]ActivityTaskProgress{{GoalIndex: 0, Value: 4, LastActiveDate: &last}}, now)
+	require.Zero(t, statuses[0].Value)
+	require.False(t, statuses[0].Done)
+
+	last = activityTaskDay(now).AddDate(0, 0, -1)
+	statuses = task.goalStatuses([]ActivityTaskProgress{{GoalIndex: 0, Value: 4, LastActiveDate: &last}}, now)
+	require.Equal(t, float64(4), statuses[0].Value)
+}
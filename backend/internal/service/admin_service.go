@@ -13,6 +13,7 @@ import (
 	infraerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
 	"github.com/Wei-Shaw/sub2api/internal/pkg/httpclient"
 	"github.com/Wei-Shaw/sub2api/internal/pkg/logger"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
 	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
 	"github.com/Wei-Shaw/sub2api/internal/util/soraerror"
 )
@@ -25,13 +26,14 @@ type AdminService interface {
 	CreateUser(ctx context.Context, input *CreateUserInput) (*User, error)
 	UpdateUser(ctx context.Context, id int64, input *UpdateUserInput) (*User, error)
 	DeleteUser(ctx context.Context, id int64) error
-	UpdateUserBalance(ctx context.Context, userID int64, balance float64, operation string, notes string) (*User, error)
+	// UpdateUserBalance 调整用户余额，operatorID 为执行调整的管理员（记入余额流水）
+	UpdateUserBalance(ctx context.Context, userID int64, balance float64, operation string, notes string, operatorID int64) (*User, error)
 	GetUserAPIKeys(ctx context.Context, userID int64, page, pageSize int) ([]APIKey, int64, error)
 	GetUserUsageStats(ctx context.Context, userID int64, period string) (any, error)
 	// GetUserBalanceHistory returns paginated balance/concurrency change records for a user.
 	// codeType is optional - pass empty string to return all types.
 	// Also returns totalRecharged (sum of all positive balance top-ups).
-	GetUserBalanceHistory(ctx context.Context, userID int64, page, pageSize int, codeType string) ([]RedeemCode, int64, float64, error)
+	GetUserBalanceHistory(ctx context.Context, userID int64, page, pageSize int, codeType string) ([]RedeemCode, int64, money.Amount, error)
 
 	// Group management
 	ListGroups(ctx context.Context, page, pageSize int, platform, status, search string, isExclusive *bool) ([]Group, int64, error)
@@ -121,19 +123,22 @@ type CreateGroupInput struct {
 	Platform         string
 	RateMultiplier   float64
 	IsExclusive      bool
-	SubscriptionType string   // standard/subscription
-	DailyLimitUSD    *float64 // 日限额 (USD)
-	WeeklyLimitUSD   *float64 // 周限额 (USD)
-	MonthlyLimitUSD  *float64 // 月限额 (USD)
+	SubscriptionType string        // standard/subscription
+	DailyLimitUSD    *money.Amount // 日限额 (USD)
+	WeeklyLimitUSD   *money.Amount // 周限额 (USD)
+	MonthlyLimitUSD  *money.Amount // 月限额 (USD)
 	// 图片生成计费配置（仅 antigravity 平台使用）
-	ImagePrice1K *float64
-	ImagePrice2K *float64
-	ImagePrice4K *float64
+	ImagePrice1K *money.Amount
+	ImagePrice2K *money.Amount
+	ImagePrice4K *money.Amount
 	// Sora 按次计费配置
-	SoraImagePrice360          *float64
-	SoraImagePrice540          *float64
-	SoraVideoPricePerRequest   *float64
-	SoraVideoPricePerRequestHD *float64
+	SoraImagePrice360          *money.Amount
+	SoraImagePrice540          *money.Amount
+	SoraVideoPricePerRequest   *money.Amount
+	SoraVideoPricePerRequestHD *money.Amount
+	// 音频计费配置（OpenAI 兼容平台）
+	AudioPricePerMinute        *money.Amount
+	AudioSpeechPricePer1MChars *money.Amount
 	ClaudeCodeOnly             bool   // 仅允许 Claude Code 客户端
 	FallbackGroupID            *int64 // 降级分组 ID
 	// 无效请求兜底分组 ID（仅 anthropic 平台使用）
@@ -157,19 +162,22 @@ type UpdateGroupInput struct {
 	RateMultiplier   *float64 // 使用指针以支持设置为0
 	IsExclusive      *bool
 	Status           string
-	SubscriptionType string   // standard/subscription
-	DailyLimitUSD    *float64 // 日限额 (USD)
-	WeeklyLimitUSD   *float64 // 周限额 (USD)
-	MonthlyLimitUSD  *float64 // 月限额 (USD)
+	SubscriptionType string        // standard/subscription
+	DailyLimitUSD    *money.Amount // 日限额 (USD)
+	WeeklyLimitUSD   *money.Amount // 周限额 (USD)
+	MonthlyLimitUSD  *money.Amount // 月限额 (USD)
 	// 图片生成计费配置（仅 antigravity 平台使用）
-	ImagePrice1K *float64
-	ImagePrice2K *float64
-	ImagePrice4K *float64
+	ImagePrice1K *money.Amount
+	ImagePrice2K *money.Amount
+	ImagePrice4K *money.Amount
 	// Sora 按次计费配置
-	SoraImagePrice360          *float64
-	SoraImagePrice540          *float64
-	SoraVideoPricePerRequest   *float64
-	SoraVideoPricePerRequestHD *float64
+	SoraImagePrice360          *money.Amount
+	SoraImagePrice540          *money.Amount
+	SoraVideoPricePerRequest   *money.Amount
+	SoraVideoPricePerRequestHD *money.Amount
+	// 音频计费配置（OpenAI 兼容平台）
+	AudioPricePerMinute        *money.Amount
+	AudioSpeechPricePer1MChars *money.Amount
 	ClaudeCodeOnly             *bool  // 仅允许 Claude Code 客户端
 	FallbackGroupID            *int64 // 降级分组 ID
 	// 无效请求兜底分组 ID（仅 anthropic 平台使用）
@@ -289,7 +297,7 @@ type UpdateProxyInput struct {
 type GenerateRedeemCodesInput struct {
 	Count        int
 	Type         string
-	Value        float64
+	Value        money.Amount
 	Notes        string
 	Category     string
 	GroupID      *int64 // 订阅类型专用：关联的分组ID
@@ -301,19 +309,19 @@ type RedeemCodeStats struct {
 	ActiveCodes           int64                 `json:"active_codes"`
 	UsedCodes             int64                 `json:"used_codes"`
 	ExpiredCodes          int64                 `json:"expired_codes"`
-	TotalValueDistributed float64               `json:"total_value_distributed"`
+	TotalValueDistributed money.Amount          `json:"total_value_distributed"`
 	ByType                map[string]int64      `json:"by_type"`
 	ByCategory            []RedeemCategoryStats `json:"by_category"`
 }
 
 type RedeemCategoryStats struct {
-	Category   string  `json:"category"`
-	Total      int64   `json:"total"`
-	Unused     int64   `json:"unused"`
-	Used       int64   `json:"used"`
-	Expired    int64   `json:"expired"`
-	TotalValue float64 `json:"total_value"`
-	UsedValue  float64 `json:"used_value"`
+	Category   string       `json:"category"`
+	Total      int64        `json:"total"`
+	Unused     int64        `json:"unused"`
+	Used       int64        `json:"used"`
+	Expired    int64        `json:"expired"`
+	TotalValue money.Amount `json:"total_value"`
+	UsedValue  money.Amount `json:"used_value"`
 }
 
 type ProxyBatchDeleteResult struct {
@@ -563,7 +571,7 @@ func (s *adminServiceImpl) CreateUser(ctx context.Context, input *CreateUserInpu
 		Username:              input.Username,
 		Notes:                 input.Notes,
 		Role:                  RoleUser, // Always create as regular user, never admin
-		Balance:               input.Balance,
+		Balance:               money.FromFloat(input.Balance),
 		Concurrency:           input.Concurrency,
 		Status:                StatusActive,
 		AllowedGroups:         input.AllowedGroups,
@@ -670,7 +678,7 @@ func (s *adminServiceImpl) UpdateUser(ctx context.Context, id int64, input *Upda
 		adjustmentRecord := &RedeemCode{
 			Code:   code,
 			Type:   AdjustmentTypeAdminConcurrency,
-			Value:  float64(concurrencyDiff),
+			Value:  redeemCountValue(concurrencyDiff),
 			Status: StatusUsed,
 			UsedBy: &user.ID,
 		}
@@ -703,32 +711,59 @@ func (s *adminServiceImpl) DeleteUser(ctx context.Context, id int64) error {
 	return nil
 }
 
-func (s *adminServiceImpl) UpdateUserBalance(ctx context.Context, userID int64, balance float64, operation string, notes string) (*User, error) {
+func (s *adminServiceImpl) UpdateUserBalance(ctx context.Context, userID int64, balance float64, operation string, notes string, operatorID int64) (*User, error) {
 	user, err := s.userRepo.GetByID(ctx, userID)
 	if err != nil {
 		return nil, err
 	}
 
 	oldBalance := user.Balance
+	amount := money.FromFloat(balance)
 
+	newBalance := oldBalance
 	switch operation {
 	case "set":
-		user.Balance = balance
+		newBalance = amount
 	case "add":
-		user.Balance += balance
+		newBalance += amount
 	case "subtract":
-		user.Balance -= balance
+		newBalance -= amount
 	}
 
-	if user.Balance < 0 {
-		return nil, fmt.Errorf("balance cannot be negative, current balance: %.2f, requested operation would result in: %.2f", oldBalance, user.Balance)
+	if newBalance < 0 {
+		return nil, fmt.Errorf("balance cannot be negative, current balance: %.2f, requested operation would result in: %.2f", oldBalance.Float64(), newBalance.Float64())
 	}
 
-	if err := s.userRepo.Update(ctx, user); err != nil {
+	balanceDiff := newBalance - oldBalance
+	if balanceDiff == 0 {
+		return user, nil
+	}
+
+	// 调整记录对应的兑换码同时作为余额流水的 reference_id，便于两边互查
+	code, codeErr := GenerateRedeemCode()
+	if codeErr != nil {
+		logger.LegacyPrintf("service.admin", "failed to generate adjustment redeem code: %v", codeErr)
+	}
+
+	// 按差额增减而不是覆盖写入，避免覆盖读取之后发生的扣费
+	var operator *int64
+	if operatorID > 0 {
+		operator = &operatorID
+	}
+	if err := s.userRepo.UpdateBalance(ctx, userID, balanceDiff, BalanceLedgerSource{
+		Type:        BalanceLedgerSourceAdminAdjust,
+		ReferenceID: code,
+		OperatorID:  operator,
+		Notes:       notes,
+	}); err != nil {
 		return nil, err
 	}
-	balanceDiff := user.Balance - oldBalance
-	if s.authCacheInvalidator != nil && balanceDiff != 0 {
+	user, err = s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.authCacheInvalidator != nil {
 		s.authCacheInvalidator.InvalidateAuthCacheByUserID(ctx, userID)
 	}
 
@@ -742,27 +777,23 @@ func (s *adminServiceImpl) UpdateUserBalance(ctx context.Context, userID int64,
 		}()
 	}
 
-	if balanceDiff != 0 {
-		code, err := GenerateRedeemCode()
-		if err != nil {
-			logger.LegacyPrintf("service.admin", "failed to generate adjustment redeem code: %v", err)
-			return user, nil
-		}
+	if codeErr != nil {
+		return user, nil
+	}
 
-		adjustmentRecord := &RedeemCode{
-			Code:   code,
-			Type:   AdjustmentTypeAdminBalance,
-			Value:  balanceDiff,
-			Status: StatusUsed,
-			UsedBy: &user.ID,
-			Notes:  notes,
-		}
-		now := time.Now()
-		adjustmentRecord.UsedAt = &now
+	adjustmentRecord := &RedeemCode{
+		Code:   code,
+		Type:   AdjustmentTypeAdminBalance,
+		Value:  balanceDiff,
+		Status: StatusUsed,
+		UsedBy: &user.ID,
+		Notes:  notes,
+	}
+	now := time.Now()
+	adjustmentRecord.UsedAt = &now
 
-		if err := s.redeemCodeRepo.Create(ctx, adjustmentRecord); err != nil {
-			logger.LegacyPrintf("service.admin", "failed to create balance adjustment redeem code: %v", err)
-		}
+	if err := s.redeemCodeRepo.Create(ctx, adjustmentRecord); err != nil {
+		logger.LegacyPrintf("service.admin", "failed to create balance adjustment redeem code: %v", err)
 	}
 
 	return user, nil
@@ -789,7 +820,7 @@ func (s *adminServiceImpl) GetUserUsageStats(ctx context.Context, userID int64,
 }
 
 // GetUserBalanceHistory returns paginated balance/concurrency change records for a user.
-func (s *adminServiceImpl) GetUserBalanceHistory(ctx context.Context, userID int64, page, pageSize int, codeType string) ([]RedeemCode, int64, float64, error) {
+func (s *adminServiceImpl) GetUserBalanceHistory(ctx context.Context, userID int64, page, pageSize int, codeType string) ([]RedeemCode, int64, money.Amount, error) {
 	params := pagination.PaginationParams{Page: page, PageSize: pageSize}
 	codes, result, err := s.redeemCodeRepo.ListByUserPaginated(ctx, userID, params, codeType)
 	if err != nil {
@@ -849,6 +880,8 @@ func (s *adminServiceImpl) CreateGroup(ctx context.Context, input *CreateGroupIn
 	soraImagePrice540 := normalizePrice(input.SoraImagePrice540)
 	soraVideoPrice := normalizePrice(input.SoraVideoPricePerRequest)
 	soraVideoPriceHD := normalizePrice(input.SoraVideoPricePerRequestHD)
+	audioPricePerMinute := normalizePrice(input.AudioPricePerMinute)
+	audioSpeechPrice := normalizePrice(input.AudioSpeechPricePer1MChars)
 
 	// 校验降级分组
 	if input.FallbackGroupID != nil {
@@ -923,6 +956,8 @@ func (s *adminServiceImpl) CreateGroup(ctx context.Context, input *CreateGroupIn
 		SoraImagePrice540:               soraImagePrice540,
 		SoraVideoPricePerRequest:        soraVideoPrice,
 		SoraVideoPricePerRequestHD:      soraVideoPriceHD,
+		AudioPricePerMinute:             audioPricePerMinute,
+		AudioSpeechPricePer1MChars:      audioSpeechPrice,
 		ClaudeCodeOnly:                  input.ClaudeCodeOnly,
 		FallbackGroupID:                 input.FallbackGroupID,
 		FallbackGroupIDOnInvalidRequest: fallbackOnInvalidRequest,
@@ -947,16 +982,16 @@ func (s *adminServiceImpl) CreateGroup(ctx context.Context, input *CreateGroupIn
 }
 
 // normalizeLimit 将 0 或负数转换为 nil（表示无限制）
-func normalizeLimit(limit *float64) *float64 {
-	if limit == nil || *limit <= 0 {
+func normalizeLimit(limit *money.Amount) *money.Amount {
+	if limit == nil || !limit.IsPositive() {
 		return nil
 	}
 	return limit
 }
 
 // normalizePrice 将负数转换为 nil（表示使用默认价格），0 保留（表示免费）
-func normalizePrice(price *float64) *float64 {
-	if price == nil || *price < 0 {
+func normalizePrice(price *money.Amount) *money.Amount {
+	if price == nil || price.IsNegative() {
 		return nil
 	}
 	return price
@@ -1092,6 +1127,12 @@ func (s *adminServiceImpl) UpdateGroup(ctx context.Context, id int64, input *Upd
 	if input.SoraVideoPricePerRequestHD != nil {
 		group.SoraVideoPricePerRequestHD = normalizePrice(input.SoraVideoPricePerRequestHD)
 	}
+	if input.AudioPricePerMinute != nil {
+		group.AudioPricePerMinute = normalizePrice(input.AudioPricePerMinute)
+	}
+	if input.AudioSpeechPricePer1MChars != nil {
+		group.AudioSpeechPricePer1MChars = normalizePrice(input.AudioSpeechPricePer1MChars)
+	}
 	if input.SoraStorageQuotaBytes != nil {
 		group.SoraStorageQuotaBytes = *input.SoraStorageQuotaBytes
 	}
@@ -8,6 +8,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
 	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
 	"github.com/stretchr/testify/require"
 )
@@ -70,11 +71,11 @@ func (s *userRepoStub) ListWithFilters(ctx context.Context, params pagination.Pa
 	panic("unexpected ListWithFilters call")
 }
 
-func (s *userRepoStub) UpdateBalance(ctx context.Context, id int64, amount float64) error {
+func (s *userRepoStub) UpdateBalance(ctx context.Context, id int64, amount money.Amount, _ BalanceLedgerSource) error {
 	panic("unexpected UpdateBalance call")
 }
 
-func (s *userRepoStub) DeductBalance(ctx context.Context, id int64, amount float64) error {
+func (s *userRepoStub) DeductBalance(ctx context.Context, id int64, amount money.Amount, _ BalanceLedgerSource) error {
 	panic("unexpected DeductBalance call")
 }
 
@@ -298,7 +299,7 @@ func (s *redeemRepoStub) ListByUserPaginated(ctx context.Context, userID int64,
 	panic("unexpected ListByUserPaginated call")
 }
 
-func (s *redeemRepoStub) SumPositiveBalanceByUser(ctx context.Context, userID int64) (float64, error) {
+func (s *redeemRepoStub) SumPositiveBalanceByUser(ctx context.Context, userID int64) (money.Amount, error) {
 	panic("unexpected SumPositiveBalanceByUser call")
 }
 
@@ -315,15 +316,15 @@ func newBillingCacheStub(buffer int) *billingCacheStub {
 	return &billingCacheStub{invalidations: make(chan subscriptionInvalidateCall, buffer)}
 }
 
-func (s *billingCacheStub) GetUserBalance(ctx context.Context, userID int64) (float64, error) {
+func (s *billingCacheStub) GetUserBalance(ctx context.Context, userID int64) (money.Amount, error) {
 	panic("unexpected GetUserBalance call")
 }
 
-func (s *billingCacheStub) SetUserBalance(ctx context.Context, userID int64, balance float64) error {
+func (s *billingCacheStub) SetUserBalance(ctx context.Context, userID int64, balance money.Amount) error {
 	panic("unexpected SetUserBalance call")
 }
 
-func (s *billingCacheStub) DeductUserBalance(ctx context.Context, userID int64, amount float64) error {
+func (s *billingCacheStub) DeductUserBalance(ctx context.Context, userID int64, amount money.Amount) error {
 	panic("unexpected DeductUserBalance call")
 }
 
@@ -339,7 +340,7 @@ func (s *billingCacheStub) SetSubscriptionCache(ctx context.Context, userID, gro
 	panic("unexpected SetSubscriptionCache call")
 }
 
-func (s *billingCacheStub) UpdateSubscriptionUsage(ctx context.Context, userID, groupID int64, cost float64) error {
+func (s *billingCacheStub) UpdateSubscriptionUsage(ctx context.Context, userID, groupID int64, cost money.Amount) error {
 	panic("unexpected UpdateSubscriptionUsage call")
 }
 
@@ -354,13 +355,29 @@ func (s *billingCacheStub) GetAPIKeyRateLimit(ctx context.Context, keyID int64)
 func (s *billingCacheStub) SetAPIKeyRateLimit(ctx context.Context, keyID int64, data *APIKeyRateLimitCacheData) error {
 	panic("unexpected SetAPIKeyRateLimit call")
 }
-func (s *billingCacheStub) UpdateAPIKeyRateLimitUsage(ctx context.Context, keyID int64, cost float64) error {
+func (s *billingCacheStub) UpdateAPIKeyRateLimitUsage(ctx context.Context, keyID int64, cost money.Amount) error {
 	panic("unexpected UpdateAPIKeyRateLimitUsage call")
 }
 func (s *billingCacheStub) InvalidateAPIKeyRateLimit(ctx context.Context, keyID int64) error {
 	panic("unexpected InvalidateAPIKeyRateLimit call")
 }
 
+func (s *billingCacheStub) GetOrgMemberSpend(ctx context.Context, accountUserID, memberID int64) (*OrgMemberSpendCacheData, error) {
+	panic("unexpected GetOrgMemberSpend call")
+}
+
+func (s *billingCacheStub) SetOrgMemberSpend(ctx context.Context, accountUserID, memberID int64, data *OrgMemberSpendCacheData) error {
+	panic("unexpected SetOrgMemberSpend call")
+}
+
+func (s *billingCacheStub) AddOrgMemberSpend(ctx context.Context, accountUserID, memberID int64, month string, cost money.Amount) error {
+	panic("unexpected AddOrgMemberSpend call")
+}
+
+func (s *billingCacheStub) InvalidateOrgMemberSpend(ctx context.Context, accountUserID, memberID int64) error {
+	panic("unexpected InvalidateOrgMemberSpend call")
+}
+
 func waitForInvalidations(t *testing.T, ch <-chan subscriptionInvalidateCall, expected int) []subscriptionInvalidateCall {
 	t.Helper()
 	calls := make([]subscriptionInvalidateCall, 0, expected)
@@ -6,6 +6,7 @@ import (
 	"context"
 	"testing"
 
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
 	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
 	"github.com/stretchr/testify/require"
 )
@@ -125,9 +126,9 @@ func TestAdminService_CreateGroup_WithImagePricing(t *testing.T) {
 	repo := &groupRepoStubForAdmin{}
 	svc := &adminServiceImpl{groupRepo: repo}
 
-	price1K := 0.10
-	price2K := 0.15
-	price4K := 0.30
+	price1K := money.MustParse("0.10")
+	price2K := money.MustParse("0.15")
+	price4K := money.MustParse("0.30")
 
 	input := &CreateGroupInput{
 		Name:           "test-group",
@@ -148,9 +149,9 @@ func TestAdminService_CreateGroup_WithImagePricing(t *testing.T) {
 	require.NotNil(t, repo.created.ImagePrice1K)
 	require.NotNil(t, repo.created.ImagePrice2K)
 	require.NotNil(t, repo.created.ImagePrice4K)
-	require.InDelta(t, 0.10, *repo.created.ImagePrice1K, 0.0001)
-	require.InDelta(t, 0.15, *repo.created.ImagePrice2K, 0.0001)
-	require.InDelta(t, 0.30, *repo.created.ImagePrice4K, 0.0001)
+	require.InDelta(t, 0.10, repo.created.ImagePrice1K.Float64(), 0.0001)
+	require.InDelta(t, 0.15, repo.created.ImagePrice2K.Float64(), 0.0001)
+	require.InDelta(t, 0.30, repo.created.ImagePrice4K.Float64(), 0.0001)
 }
 
 // TestAdminService_CreateGroup_NilImagePricing 测试 ImagePrice 为 nil 时正常创建
@@ -188,9 +189,9 @@ func TestAdminService_UpdateGroup_WithImagePricing(t *testing.T) {
 	repo := &groupRepoStubForAdmin{getByID: existingGroup}
 	svc := &adminServiceImpl{groupRepo: repo}
 
-	price1K := 0.12
-	price2K := 0.18
-	price4K := 0.36
+	price1K := money.MustParse("0.12")
+	price2K := money.MustParse("0.18")
+	price4K := money.MustParse("0.36")
 
 	input := &UpdateGroupInput{
 		ImagePrice1K: &price1K,
@@ -207,14 +208,14 @@ func TestAdminService_UpdateGroup_WithImagePricing(t *testing.T) {
 	require.NotNil(t, repo.updated.ImagePrice1K)
 	require.NotNil(t, repo.updated.ImagePrice2K)
 	require.NotNil(t, repo.updated.ImagePrice4K)
-	require.InDelta(t, 0.12, *repo.updated.ImagePrice1K, 0.0001)
-	require.InDelta(t, 0.18, *repo.updated.ImagePrice2K, 0.0001)
-	require.InDelta(t, 0.36, *repo.updated.ImagePrice4K, 0.0001)
+	require.InDelta(t, 0.12, repo.updated.ImagePrice1K.Float64(), 0.0001)
+	require.InDelta(t, 0.18, repo.updated.ImagePrice2K.Float64(), 0.0001)
+	require.InDelta(t, 0.36, repo.updated.ImagePrice4K.Float64(), 0.0001)
 }
 
 // TestAdminService_UpdateGroup_PartialImagePricing 测试仅更新部分 ImagePrice 字段
 func TestAdminService_UpdateGroup_PartialImagePricing(t *testing.T) {
-	oldPrice2K := 0.15
+	oldPrice2K := money.MustParse("0.15")
 	existingGroup := &Group{
 		ID:           1,
 		Name:         "existing-group",
@@ -226,7 +227,7 @@ func TestAdminService_UpdateGroup_PartialImagePricing(t *testing.T) {
 	svc := &adminServiceImpl{groupRepo: repo}
 
 	// 只更新 1K 价格
-	price1K := 0.10
+	price1K := money.MustParse("0.10")
 	input := &UpdateGroupInput{
 		ImagePrice1K: &price1K,
 		// ImagePrice2K 和 ImagePrice4K 为 nil，不更新
@@ -239,9 +240,9 @@ func TestAdminService_UpdateGroup_PartialImagePricing(t *testing.T) {
 	// 验证：1K 被更新，2K 保持原值，4K 仍为 nil
 	require.NotNil(t, repo.updated)
 	require.NotNil(t, repo.updated.ImagePrice1K)
-	require.InDelta(t, 0.10, *repo.updated.ImagePrice1K, 0.0001)
+	require.InDelta(t, 0.10, repo.updated.ImagePrice1K.Float64(), 0.0001)
 	require.NotNil(t, repo.updated.ImagePrice2K)
-	require.InDelta(t, 0.15, *repo.updated.ImagePrice2K, 0.0001) // 原值保持
+	require.InDelta(t, 0.15, repo.updated.ImagePrice2K.Float64(), 0.0001) // 原值保持
 	require.Nil(t, repo.updated.ImagePrice4K)
 }
 
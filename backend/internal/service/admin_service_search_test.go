@@ -6,6 +6,7 @@ import (
 	"context"
 	"testing"
 
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
 	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
 	"github.com/stretchr/testify/require"
 )
@@ -158,7 +159,7 @@ func (s *redeemRepoStubForAdminList) ListByUserPaginated(_ context.Context, user
 	panic("unexpected ListByUserPaginated call")
 }
 
-func (s *redeemRepoStubForAdminList) SumPositiveBalanceByUser(_ context.Context, userID int64) (float64, error) {
+func (s *redeemRepoStubForAdminList) SumPositiveBalanceByUser(_ context.Context, userID int64) (money.Amount, error) {
 	panic("unexpected SumPositiveBalanceByUser call")
 }
 
@@ -7,24 +7,26 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/require"
+
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
 )
 
 type balanceUserRepoStub struct {
 	*userRepoStub
 	updateErr error
-	updated   []*User
+	deltas    []money.Amount
+	sources   []BalanceLedgerSource
 }
 
-func (s *balanceUserRepoStub) Update(ctx context.Context, user *User) error {
+func (s *balanceUserRepoStub) UpdateBalance(ctx context.Context, id int64, amount money.Amount, src BalanceLedgerSource) error {
 	if s.updateErr != nil {
 		return s.updateErr
 	}
-	if user == nil {
-		return nil
-	}
-	clone := *user
-	s.updated = append(s.updated, &clone)
-	if s.userRepoStub != nil {
+	s.deltas = append(s.deltas, amount)
+	s.sources = append(s.sources, src)
+	if s.userRepoStub != nil && s.userRepoStub.user != nil {
+		clone := *s.userRepoStub.user
+		clone.Balance += amount
 		s.userRepoStub.user = &clone
 	}
 	return nil
@@ -63,7 +65,7 @@ func (s *authCacheInvalidatorStub) InvalidateAuthCacheByGroupID(ctx context.Cont
 }
 
 func TestAdminService_UpdateUserBalance_InvalidatesAuthCache(t *testing.T) {
-	baseRepo := &userRepoStub{user: &User{ID: 7, Balance: 10}}
+	baseRepo := &userRepoStub{user: &User{ID: 7, Balance: 10 * money.USD}}
 	repo := &balanceUserRepoStub{userRepoStub: baseRepo}
 	redeemRepo := &balanceRedeemRepoStub{redeemRepoStub: &redeemRepoStub{}}
 	invalidator := &authCacheInvalidatorStub{}
@@ -73,14 +75,14 @@ func TestAdminService_UpdateUserBalance_InvalidatesAuthCache(t *testing.T) {
 		authCacheInvalidator: invalidator,
 	}
 
-	_, err := svc.UpdateUserBalance(context.Background(), 7, 5, "add", "")
+	_, err := svc.UpdateUserBalance(context.Background(), 7, 5, "add", "", 1)
 	require.NoError(t, err)
 	require.Equal(t, []int64{7}, invalidator.userIDs)
 	require.Len(t, redeemRepo.created, 1)
 }
 
 func TestAdminService_UpdateUserBalance_NoChangeNoInvalidate(t *testing.T) {
-	baseRepo := &userRepoStub{user: &User{ID: 7, Balance: 10}}
+	baseRepo := &userRepoStub{user: &User{ID: 7, Balance: 10 * money.USD}}
 	repo := &balanceUserRepoStub{userRepoStub: baseRepo}
 	redeemRepo := &balanceRedeemRepoStub{redeemRepoStub: &redeemRepoStub{}}
 	invalidator := &authCacheInvalidatorStub{}
@@ -90,8 +92,43 @@ func TestAdminService_UpdateUserBalance_NoChangeNoInvalidate(t *testing.T) {
 		authCacheInvalidator: invalidator,
 	}
 
-	_, err := svc.UpdateUserBalance(context.Background(), 7, 10, "set", "")
+	_, err := svc.UpdateUserBalance(context.Background(), 7, 10, "set", "", 1)
 	require.NoError(t, err)
 	require.Empty(t, invalidator.userIDs)
 	require.Empty(t, redeemRepo.created)
+	require.Empty(t, repo.deltas)
+}
+
+func TestAdminService_UpdateUserBalance_SetRecordsLedgerDiff(t *testing.T) {
+	baseRepo := &userRepoStub{user: &User{ID: 7, Balance: 10 * money.USD}}
+	repo := &balanceUserRepoStub{userRepoStub: baseRepo}
+	redeemRepo := &balanceRedeemRepoStub{redeemRepoStub: &redeemRepoStub{}}
+	svc := &adminServiceImpl{
+		userRepo:       repo,
+		redeemCodeRepo: redeemRepo,
+	}
+
+	user, err := svc.UpdateUserBalance(context.Background(), 7, 4, "set", "refund", 99)
+	require.NoError(t, err)
+	require.Equal(t, 4*money.USD, user.Balance)
+	require.Equal(t, []money.Amount{-6 * money.USD}, repo.deltas, "set is applied as a delta, not an overwrite")
+
+	require.Len(t, repo.sources, 1)
+	src := repo.sources[0]
+	require.Equal(t, BalanceLedgerSourceAdminAdjust, src.Type)
+	require.NotNil(t, src.OperatorID)
+	require.Equal(t, int64(99), *src.OperatorID)
+	require.Equal(t, "refund", src.Notes)
+	require.Len(t, redeemRepo.created, 1)
+	require.Equal(t, redeemRepo.created[0].Code, src.ReferenceID)
+}
+
+func TestAdminService_UpdateUserBalance_RejectsNegativeResult(t *testing.T) {
+	baseRepo := &userRepoStub{user: &User{ID: 7, Balance: 10 * money.USD}}
+	repo := &balanceUserRepoStub{userRepoStub: baseRepo}
+	svc := &adminServiceImpl{userRepo: repo}
+
+	_, err := svc.UpdateUserBalance(context.Background(), 7, 11, "subtract", "", 1)
+	require.Error(t, err)
+	require.Empty(t, repo.deltas)
 }
@@ -224,7 +224,7 @@ func (s *AnnouncementService) ListForUser(ctx context.Context, userID int64, unr
 		if !a.IsActiveAt(now) {
 			continue
 		}
-		if !a.Targeting.Matches(user.Balance, activeGroupIDs) {
+		if !a.Targeting.Matches(user.Balance.Float64(), activeGroupIDs) {
 			continue
 		}
 		visible = append(visible, a)
@@ -296,7 +296,7 @@ func (s *AnnouncementService) MarkRead(ctx context.Context, userID, announcement
 		activeGroupIDs[activeSubs[i].GroupID] = struct{}{}
 	}
 
-	if !a.Targeting.Matches(user.Balance, activeGroupIDs) {
+	if !a.Targeting.Matches(user.Balance.Float64(), activeGroupIDs) {
 		return ErrAnnouncementNotFound
 	}
 
@@ -359,8 +359,8 @@ func (s *AnnouncementService) ListUserReadStatus(
 			UserID:   u.ID,
 			Email:    u.Email,
 			Username: u.Username,
-			Balance:  u.Balance,
-			Eligible: domain.AnnouncementTargeting(ann.Targeting).Matches(u.Balance, activeGroupIDs),
+			Balance:  u.Balance.Float64(),
+			Eligible: domain.AnnouncementTargeting(ann.Targeting).Matches(u.Balance.Float64(), activeGroupIDs),
 			ReadAt:   ptr,
 		})
 	}
@@ -1,9 +1,12 @@
 package service
 
 import (
+	"path"
+	"strings"
 	"time"
 
 	"github.com/Wei-Shaw/sub2api/internal/pkg/ip"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
 )
 
 // API Key status constants
@@ -26,27 +29,41 @@ type APIKey struct {
 	// 预编译的 IP 规则，用于认证热路径避免重复 ParseIP/ParseCIDR。
 	CompiledIPWhitelist *ip.CompiledIPRules `json:"-"`
 	CompiledIPBlacklist *ip.CompiledIPRules `json:"-"`
-	LastUsedAt          *time.Time
-	CreatedAt           time.Time
-	UpdatedAt           time.Time
-	User                *User
-	Group               *Group
+	// 模型限制（glob 模式，大小写不敏感）：先匹配黑名单，白名单为空表示不限制
+	ModelAllowlist []string
+	ModelDenylist  []string
+	LastUsedAt     *time.Time
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	User           *User
+	Group          *Group
 
 	// Quota fields
-	Quota     float64    // Quota limit in USD (0 = unlimited)
-	QuotaUsed float64    // Used quota amount
-	ExpiresAt *time.Time // Expiration time (nil = never expires)
+	Quota     money.Amount // Quota limit in USD (0 = unlimited)
+	QuotaUsed money.Amount // Used quota amount
+	ExpiresAt *time.Time   // Expiration time (nil = never expires)
 
 	// Rate limit fields
-	RateLimit5h   float64    // Rate limit in USD per 5h (0 = unlimited)
-	RateLimit1d   float64    // Rate limit in USD per 1d (0 = unlimited)
-	RateLimit7d   float64    // Rate limit in USD per 7d (0 = unlimited)
-	Usage5h       float64    // Used amount in current 5h window
-	Usage1d       float64    // Used amount in current 1d window
-	Usage7d       float64    // Used amount in current 7d window
-	Window5hStart *time.Time // Start of current 5h window
-	Window1dStart *time.Time // Start of current 1d window
-	Window7dStart *time.Time // Start of current 7d window
+	RateLimit5h   money.Amount // Rate limit in USD per 5h (0 = unlimited)
+	RateLimit1d   money.Amount // Rate limit in USD per 1d (0 = unlimited)
+	RateLimit7d   money.Amount // Rate limit in USD per 7d (0 = unlimited)
+	Usage5h       money.Amount // Used amount in current 5h window
+	Usage1d       money.Amount // Used amount in current 1d window
+	Usage7d       money.Amount // Used amount in current 7d window
+	Window5hStart *time.Time   // Start of current 5h window
+	Window1dStart *time.Time   // Start of current 1d window
+	Window7dStart *time.Time   // Start of current 7d window
+
+	// Traffic limit fields (enforced in Redis, 0 = unlimited)
+	RPMLimit       int // Max requests per minute
+	TPMLimit       int // Max tokens per minute
+	MaxConcurrency int // Max in-flight requests
+
+	// CreatedByUserID 组织 Key 的创建成员（UserID 为组织账户）；个人 Key 为 nil
+	CreatedByUserID *int64
+
+	// BatchJobID 非持久化字段：批处理执行器回放请求时由认证中间件写入，用于批处理折扣计费（0 表示普通请求）
+	BatchJobID int64 `json:"-"`
 }
 
 func (k *APIKey) IsActive() bool {
@@ -58,6 +75,16 @@ func (k *APIKey) HasRateLimits() bool {
 	return k.RateLimit5h > 0 || k.RateLimit1d > 0 || k.RateLimit7d > 0
 }
 
+// HasTrafficLimits returns true if a per-minute request or token limit is configured
+func (k *APIKey) HasTrafficLimits() bool {
+	return k != nil && (k.RPMLimit > 0 || k.TPMLimit > 0)
+}
+
+// IsOrganizationKey 是否为组织 Key（归属组织账户，由成员创建）
+func (k *APIKey) IsOrganizationKey() bool {
+	return k != nil && k.CreatedByUserID != nil
+}
+
 // IsExpired checks if the API key has expired
 func (k *APIKey) IsExpired() bool {
 	if k.ExpiresAt == nil {
@@ -75,7 +102,7 @@ func (k *APIKey) IsQuotaExhausted() bool {
 }
 
 // GetQuotaRemaining returns remaining quota (-1 for unlimited)
-func (k *APIKey) GetQuotaRemaining() float64 {
+func (k *APIKey) GetQuotaRemaining() money.Amount {
 	if k.Quota <= 0 {
 		return -1 // unlimited
 	}
@@ -97,3 +124,69 @@ func (k *APIKey) GetDaysUntilExpiry() int {
 	}
 	return int(duration.Hours() / 24)
 }
+
+// HasModelRestrictions returns true if the key restricts which models may be used
+func (k *APIKey) HasModelRestrictions() bool {
+	return len(k.ModelAllowlist) > 0 || len(k.ModelDenylist) > 0
+}
+
+// IsModelAllowed 检查该 Key 是否允许调用指定模型：
+// 命中黑名单即拒绝；配置了白名单时必须命中其中之一。
+func (k *APIKey) IsModelAllowed(model string) bool {
+	if k == nil || !k.HasModelRestrictions() {
+		return true
+	}
+	name := normalizeModelForPattern(model)
+	if name == "" {
+		return len(k.ModelAllowlist) == 0
+	}
+	for _, pattern := range k.ModelDenylist {
+		if matchModelGlob(pattern, name) {
+			return false
+		}
+	}
+	if len(k.ModelAllowlist) == 0 {
+		return true
+	}
+	for _, pattern := range k.ModelAllowlist {
+		if matchModelGlob(pattern, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeModelForPattern 去掉 Gemini 原生路径的 models/ 前缀并转小写
+func normalizeModelForPattern(model string) string {
+	model = strings.TrimSpace(model)
+	model = strings.TrimPrefix(model, "models/")
+	return strings.ToLower(model)
+}
+
+// matchModelGlob glob 匹配（path.Match 语义：支持 * ? [...]，大小写不敏感）
+func matchModelGlob(pattern, name string) bool {
+	pattern = strings.ToLower(strings.TrimSpace(pattern))
+	if pattern == "" {
+		return false
+	}
+	matched, err := path.Match(pattern, name)
+	return err == nil && matched
+}
+
+// ValidateModelPatterns 校验模型 glob 模式，返回无效的模式列表
+func ValidateModelPatterns(patterns []string) []string {
+	var invalid []string
+	for _, p := range patterns {
+		trimmed := strings.TrimSpace(p)
+		if trimmed == "" || len(trimmed) > maxModelPatternLength {
+			invalid = append(invalid, p)
+			continue
+		}
+		if _, err := path.Match(strings.ToLower(trimmed), ""); err != nil {
+			invalid = append(invalid, p)
+		}
+	}
+	return invalid
+}
+
+const maxModelPatternLength = 128
@@ -1,61 +1,77 @@
 package service
 
-import "time"
+import (
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
+)
 
 // APIKeyAuthSnapshot API Key 认证缓存快照（仅包含认证所需字段）
 type APIKeyAuthSnapshot struct {
-	APIKeyID    int64                    `json:"api_key_id"`
-	UserID      int64                    `json:"user_id"`
-	GroupID     *int64                   `json:"group_id,omitempty"`
-	Status      string                   `json:"status"`
-	IPWhitelist []string                 `json:"ip_whitelist,omitempty"`
-	IPBlacklist []string                 `json:"ip_blacklist,omitempty"`
-	User        APIKeyAuthUserSnapshot   `json:"user"`
-	Group       *APIKeyAuthGroupSnapshot `json:"group,omitempty"`
+	APIKeyID       int64                    `json:"api_key_id"`
+	UserID         int64                    `json:"user_id"`
+	GroupID        *int64                   `json:"group_id,omitempty"`
+	Status         string                   `json:"status"`
+	IPWhitelist    []string                 `json:"ip_whitelist,omitempty"`
+	IPBlacklist    []string                 `json:"ip_blacklist,omitempty"`
+	ModelAllowlist []string                 `json:"model_allowlist,omitempty"`
+	ModelDenylist  []string                 `json:"model_denylist,omitempty"`
+	User           APIKeyAuthUserSnapshot   `json:"user"`
+	Group          *APIKeyAuthGroupSnapshot `json:"group,omitempty"`
 
 	// Quota fields for API Key independent quota feature
-	Quota     float64 `json:"quota"`      // Quota limit in USD (0 = unlimited)
-	QuotaUsed float64 `json:"quota_used"` // Used quota amount
+	Quota     money.Amount `json:"quota"`      // Quota limit in USD (0 = unlimited)
+	QuotaUsed money.Amount `json:"quota_used"` // Used quota amount
 
 	// Expiration field for API Key expiration feature
 	ExpiresAt *time.Time `json:"expires_at,omitempty"` // Expiration time (nil = never expires)
 
 	// Rate limit configuration (only limits, not usage - usage read from Redis at check time)
-	RateLimit5h float64 `json:"rate_limit_5h"`
-	RateLimit1d float64 `json:"rate_limit_1d"`
-	RateLimit7d float64 `json:"rate_limit_7d"`
+	RateLimit5h money.Amount `json:"rate_limit_5h"`
+	RateLimit1d money.Amount `json:"rate_limit_1d"`
+	RateLimit7d money.Amount `json:"rate_limit_7d"`
+
+	// Traffic limit configuration (counters live in Redis)
+	RPMLimit       int `json:"rpm_limit,omitempty"`
+	TPMLimit       int `json:"tpm_limit,omitempty"`
+	MaxConcurrency int `json:"max_concurrency,omitempty"`
+
+	// 组织 Key 的创建成员（用于成员消费上限）
+	CreatedByUserID *int64 `json:"created_by_user_id,omitempty"`
 }
 
 // APIKeyAuthUserSnapshot 用户快照
 type APIKeyAuthUserSnapshot struct {
-	ID          int64   `json:"id"`
-	Status      string  `json:"status"`
-	Role        string  `json:"role"`
-	Balance     float64 `json:"balance"`
-	Concurrency int     `json:"concurrency"`
+	ID          int64        `json:"id"`
+	Status      string       `json:"status"`
+	Role        string       `json:"role"`
+	Balance     money.Amount `json:"balance"`
+	Concurrency int          `json:"concurrency"`
 }
 
 // APIKeyAuthGroupSnapshot 分组快照
 type APIKeyAuthGroupSnapshot struct {
-	ID                              int64    `json:"id"`
-	Name                            string   `json:"name"`
-	Platform                        string   `json:"platform"`
-	Status                          string   `json:"status"`
-	SubscriptionType                string   `json:"subscription_type"`
-	RateMultiplier                  float64  `json:"rate_multiplier"`
-	DailyLimitUSD                   *float64 `json:"daily_limit_usd,omitempty"`
-	WeeklyLimitUSD                  *float64 `json:"weekly_limit_usd,omitempty"`
-	MonthlyLimitUSD                 *float64 `json:"monthly_limit_usd,omitempty"`
-	ImagePrice1K                    *float64 `json:"image_price_1k,omitempty"`
-	ImagePrice2K                    *float64 `json:"image_price_2k,omitempty"`
-	ImagePrice4K                    *float64 `json:"image_price_4k,omitempty"`
-	SoraImagePrice360               *float64 `json:"sora_image_price_360,omitempty"`
-	SoraImagePrice540               *float64 `json:"sora_image_price_540,omitempty"`
-	SoraVideoPricePerRequest        *float64 `json:"sora_video_price_per_request,omitempty"`
-	SoraVideoPricePerRequestHD      *float64 `json:"sora_video_price_per_request_hd,omitempty"`
-	ClaudeCodeOnly                  bool     `json:"claude_code_only"`
-	FallbackGroupID                 *int64   `json:"fallback_group_id,omitempty"`
-	FallbackGroupIDOnInvalidRequest *int64   `json:"fallback_group_id_on_invalid_request,omitempty"`
+	ID                              int64         `json:"id"`
+	Name                            string        `json:"name"`
+	Platform                        string        `json:"platform"`
+	Status                          string        `json:"status"`
+	SubscriptionType                string        `json:"subscription_type"`
+	RateMultiplier                  float64       `json:"rate_multiplier"`
+	DailyLimitUSD                   *money.Amount `json:"daily_limit_usd,omitempty"`
+	WeeklyLimitUSD                  *money.Amount `json:"weekly_limit_usd,omitempty"`
+	MonthlyLimitUSD                 *money.Amount `json:"monthly_limit_usd,omitempty"`
+	ImagePrice1K                    *money.Amount `json:"image_price_1k,omitempty"`
+	ImagePrice2K                    *money.Amount `json:"image_price_2k,omitempty"`
+	ImagePrice4K                    *money.Amount `json:"image_price_4k,omitempty"`
+	SoraImagePrice360               *money.Amount `json:"sora_image_price_360,omitempty"`
+	SoraImagePrice540               *money.Amount `json:"sora_image_price_540,omitempty"`
+	SoraVideoPricePerRequest        *money.Amount `json:"sora_video_price_per_request,omitempty"`
+	SoraVideoPricePerRequestHD      *money.Amount `json:"sora_video_price_per_request_hd,omitempty"`
+	AudioPricePerMinute             *money.Amount `json:"audio_price_per_minute,omitempty"`
+	AudioSpeechPricePer1MChars      *money.Amount `json:"audio_speech_price_per_1m_chars,omitempty"`
+	ClaudeCodeOnly                  bool          `json:"claude_code_only"`
+	FallbackGroupID                 *int64        `json:"fallback_group_id,omitempty"`
+	FallbackGroupIDOnInvalidRequest *int64        `json:"fallback_group_id_on_invalid_request,omitempty"`
 
 	// Model routing is used by gateway account selection, so it must be part of auth cache snapshot.
 	// Only anthropic groups use these fields; others may leave them empty.
@@ -200,18 +200,24 @@ func (s *APIKeyService) snapshotFromAPIKey(apiKey *APIKey) *APIKeyAuthSnapshot {
 		return nil
 	}
 	snapshot := &APIKeyAuthSnapshot{
-		APIKeyID:    apiKey.ID,
-		UserID:      apiKey.UserID,
-		GroupID:     apiKey.GroupID,
-		Status:      apiKey.Status,
-		IPWhitelist: apiKey.IPWhitelist,
-		IPBlacklist: apiKey.IPBlacklist,
-		Quota:       apiKey.Quota,
-		QuotaUsed:   apiKey.QuotaUsed,
-		ExpiresAt:   apiKey.ExpiresAt,
-		RateLimit5h: apiKey.RateLimit5h,
-		RateLimit1d: apiKey.RateLimit1d,
-		RateLimit7d: apiKey.RateLimit7d,
+		APIKeyID:        apiKey.ID,
+		UserID:          apiKey.UserID,
+		GroupID:         apiKey.GroupID,
+		Status:          apiKey.Status,
+		IPWhitelist:     apiKey.IPWhitelist,
+		IPBlacklist:     apiKey.IPBlacklist,
+		ModelAllowlist:  apiKey.ModelAllowlist,
+		ModelDenylist:   apiKey.ModelDenylist,
+		Quota:           apiKey.Quota,
+		QuotaUsed:       apiKey.QuotaUsed,
+		ExpiresAt:       apiKey.ExpiresAt,
+		RateLimit5h:     apiKey.RateLimit5h,
+		RateLimit1d:     apiKey.RateLimit1d,
+		RateLimit7d:     apiKey.RateLimit7d,
+		RPMLimit:        apiKey.RPMLimit,
+		TPMLimit:        apiKey.TPMLimit,
+		MaxConcurrency:  apiKey.MaxConcurrency,
+		CreatedByUserID: apiKey.CreatedByUserID,
 		User: APIKeyAuthUserSnapshot{
 			ID:          apiKey.User.ID,
 			Status:      apiKey.User.Status,
@@ -238,6 +244,8 @@ func (s *APIKeyService) snapshotFromAPIKey(apiKey *APIKey) *APIKeyAuthSnapshot {
 			SoraImagePrice540:               apiKey.Group.SoraImagePrice540,
 			SoraVideoPricePerRequest:        apiKey.Group.SoraVideoPricePerRequest,
 			SoraVideoPricePerRequestHD:      apiKey.Group.SoraVideoPricePerRequestHD,
+			AudioPricePerMinute:             apiKey.Group.AudioPricePerMinute,
+			AudioSpeechPricePer1MChars:      apiKey.Group.AudioSpeechPricePer1MChars,
 			ClaudeCodeOnly:                  apiKey.Group.ClaudeCodeOnly,
 			FallbackGroupID:                 apiKey.Group.FallbackGroupID,
 			FallbackGroupIDOnInvalidRequest: apiKey.Group.FallbackGroupIDOnInvalidRequest,
@@ -255,19 +263,25 @@ func (s *APIKeyService) snapshotToAPIKey(key string, snapshot *APIKeyAuthSnapsho
 		return nil
 	}
 	apiKey := &APIKey{
-		ID:          snapshot.APIKeyID,
-		UserID:      snapshot.UserID,
-		GroupID:     snapshot.GroupID,
-		Key:         key,
-		Status:      snapshot.Status,
-		IPWhitelist: snapshot.IPWhitelist,
-		IPBlacklist: snapshot.IPBlacklist,
-		Quota:       snapshot.Quota,
-		QuotaUsed:   snapshot.QuotaUsed,
-		ExpiresAt:   snapshot.ExpiresAt,
-		RateLimit5h: snapshot.RateLimit5h,
-		RateLimit1d: snapshot.RateLimit1d,
-		RateLimit7d: snapshot.RateLimit7d,
+		ID:              snapshot.APIKeyID,
+		UserID:          snapshot.UserID,
+		GroupID:         snapshot.GroupID,
+		Key:             key,
+		Status:          snapshot.Status,
+		IPWhitelist:     snapshot.IPWhitelist,
+		IPBlacklist:     snapshot.IPBlacklist,
+		ModelAllowlist:  snapshot.ModelAllowlist,
+		ModelDenylist:   snapshot.ModelDenylist,
+		Quota:           snapshot.Quota,
+		QuotaUsed:       snapshot.QuotaUsed,
+		ExpiresAt:       snapshot.ExpiresAt,
+		RateLimit5h:     snapshot.RateLimit5h,
+		RateLimit1d:     snapshot.RateLimit1d,
+		RateLimit7d:     snapshot.RateLimit7d,
+		RPMLimit:        snapshot.RPMLimit,
+		TPMLimit:        snapshot.TPMLimit,
+		MaxConcurrency:  snapshot.MaxConcurrency,
+		CreatedByUserID: snapshot.CreatedByUserID,
 		User: &User{
 			ID:          snapshot.User.ID,
 			Status:      snapshot.User.Status,
@@ -295,6 +309,8 @@ func (s *APIKeyService) snapshotToAPIKey(key string, snapshot *APIKeyAuthSnapsho
 			SoraImagePrice540:               snapshot.Group.SoraImagePrice540,
 			SoraVideoPricePerRequest:        snapshot.Group.SoraVideoPricePerRequest,
 			SoraVideoPricePerRequestHD:      snapshot.Group.SoraVideoPricePerRequestHD,
+			AudioPricePerMinute:             snapshot.Group.AudioPricePerMinute,
+			AudioSpeechPricePer1MChars:      snapshot.Group.AudioSpeechPricePer1MChars,
 			ClaudeCodeOnly:                  snapshot.Group.ClaudeCodeOnly,
 			FallbackGroupID:                 snapshot.Group.FallbackGroupID,
 			FallbackGroupIDOnInvalidRequest: snapshot.Group.FallbackGroupIDOnInvalidRequest,
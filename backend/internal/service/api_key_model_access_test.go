@@ -0,0 +1,48 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIKey_IsModelAllowed(t *testing.T) {
+	tests := []struct {
+		name     string
+		allow    []string
+		deny     []string
+		model    string
+		expected bool
+	}{
+		{name: "no restrictions", model: "claude-opus-4-6", expected: true},
+		{name: "allowlist match", allow: []string{"claude-*-haiku-*", "claude-haiku-*"}, model: "claude-haiku-4-5", expected: true},
+		{name: "allowlist miss", allow: []string{"claude-haiku-*"}, model: "claude-opus-4-6", expected: false},
+		{name: "denylist match", deny: []string{"*opus*"}, model: "claude-opus-4-6", expected: false},
+		{name: "denylist miss", deny: []string{"*opus*"}, model: "claude-sonnet-4-5", expected: true},
+		{name: "deny wins over allow", allow: []string{"claude-*"}, deny: []string{"claude-opus-*"}, model: "claude-opus-4-6", expected: false},
+		{name: "case insensitive", allow: []string{"GPT-4O*"}, model: "gpt-4o-mini", expected: true},
+		{name: "gemini models prefix", allow: []string{"gemini-2.5-*"}, model: "models/gemini-2.5-pro", expected: true},
+		{name: "character class", allow: []string{"gpt-[45]*"}, model: "gpt-3.5-turbo", expected: false},
+		{name: "empty model with allowlist", allow: []string{"*"}, model: "", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key := &APIKey{ModelAllowlist: tt.allow, ModelDenylist: tt.deny}
+			require.Equal(t, tt.expected, key.IsModelAllowed(tt.model))
+		})
+	}
+
+	var nilKey *APIKey
+	require.True(t, nilKey.IsModelAllowed("claude-opus-4-6"))
+}
+
+func TestValidateModelPatterns(t *testing.T) {
+	require.Empty(t, ValidateModelPatterns([]string{"claude-*", "gpt-4o?", "gemini-[23]*"}))
+	require.Equal(t, []string{"gpt-[4", " "}, ValidateModelPatterns([]string{"gpt-[4", " ", "ok-*"}))
+}
+
+func TestNormalizeModelPatterns(t *testing.T) {
+	require.Nil(t, normalizeModelPatterns(nil))
+	require.Equal(t, []string{"claude-*", "gpt-4o"}, normalizeModelPatterns([]string{" claude-* ", "gpt-4o", "", "claude-*"}))
+}
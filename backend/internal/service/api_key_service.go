@@ -6,12 +6,14 @@ import (
 	"encoding/hex"
 	"fmt"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/Wei-Shaw/sub2api/internal/config"
 	infraerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
 	"github.com/Wei-Shaw/sub2api/internal/pkg/ip"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
 	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
 	"github.com/Wei-Shaw/sub2api/internal/pkg/timezone"
 	"github.com/dgraph-io/ristretto"
@@ -19,13 +21,15 @@ import (
 )
 
 var (
-	ErrAPIKeyNotFound     = infraerrors.NotFound("API_KEY_NOT_FOUND", "api key not found")
-	ErrGroupNotAllowed    = infraerrors.Forbidden("GROUP_NOT_ALLOWED", "user is not allowed to bind this group")
-	ErrAPIKeyExists       = infraerrors.Conflict("API_KEY_EXISTS", "api key already exists")
-	ErrAPIKeyTooShort     = infraerrors.BadRequest("API_KEY_TOO_SHORT", "api key must be at least 16 characters")
-	ErrAPIKeyInvalidChars = infraerrors.BadRequest("API_KEY_INVALID_CHARS", "api key can only contain letters, numbers, underscores, and hyphens")
-	ErrAPIKeyRateLimited  = infraerrors.TooManyRequests("API_KEY_RATE_LIMITED", "too many failed attempts, please try again later")
-	ErrInvalidIPPattern   = infraerrors.BadRequest("INVALID_IP_PATTERN", "invalid IP or CIDR pattern")
+	ErrAPIKeyNotFound      = infraerrors.NotFound("API_KEY_NOT_FOUND", "api key not found")
+	ErrGroupNotAllowed     = infraerrors.Forbidden("GROUP_NOT_ALLOWED", "user is not allowed to bind this group")
+	ErrAPIKeyExists        = infraerrors.Conflict("API_KEY_EXISTS", "api key already exists")
+	ErrAPIKeyTooShort      = infraerrors.BadRequest("API_KEY_TOO_SHORT", "api key must be at least 16 characters")
+	ErrAPIKeyInvalidChars  = infraerrors.BadRequest("API_KEY_INVALID_CHARS", "api key can only contain letters, numbers, underscores, and hyphens")
+	ErrAPIKeyRateLimited   = infraerrors.TooManyRequests("API_KEY_RATE_LIMITED", "too many failed attempts, please try again later")
+	ErrInvalidIPPattern    = infraerrors.BadRequest("INVALID_IP_PATTERN", "invalid IP or CIDR pattern")
+	ErrInvalidModelPattern = infraerrors.BadRequest("INVALID_MODEL_PATTERN", "invalid model pattern")
+	ErrInvalidTrafficLimit = infraerrors.BadRequest("INVALID_TRAFFIC_LIMIT", "rpm_limit, tpm_limit and max_concurrency must not be negative")
 	// ErrAPIKeyExpired        = infraerrors.Forbidden("API_KEY_EXPIRED", "api key has expired")
 	ErrAPIKeyExpired = infraerrors.Forbidden("API_KEY_EXPIRED", "api key 已过期")
 	// ErrAPIKeyQuotaExhausted = infraerrors.TooManyRequests("API_KEY_QUOTA_EXHAUSTED", "api key quota exhausted")
@@ -35,6 +39,10 @@ var (
 	ErrAPIKeyRateLimit5hExceeded = infraerrors.TooManyRequests("API_KEY_RATE_5H_EXCEEDED", "api key 5小时限额已用完")
 	ErrAPIKeyRateLimit1dExceeded = infraerrors.TooManyRequests("API_KEY_RATE_1D_EXCEEDED", "api key 日限额已用完")
 	ErrAPIKeyRateLimit7dExceeded = infraerrors.TooManyRequests("API_KEY_RATE_7D_EXCEEDED", "api key 7天限额已用完")
+
+	// Traffic limit errors
+	ErrAPIKeyRPMExceeded = infraerrors.TooManyRequests("API_KEY_RPM_EXCEEDED", "api key 每分钟请求数已达上限")
+	ErrAPIKeyTPMExceeded = infraerrors.TooManyRequests("API_KEY_TPM_EXCEEDED", "api key 每分钟 token 数已达上限")
 )
 
 const (
@@ -56,6 +64,8 @@ type APIKeyRepository interface {
 	Delete(ctx context.Context, id int64) error
 
 	ListByUserID(ctx context.Context, userID int64, params pagination.PaginationParams) ([]APIKey, *pagination.PaginationResult, error)
+	// ListByCreator 列出归属 userID（组织账户）且由 creatorID 创建的 Key
+	ListByCreator(ctx context.Context, userID, creatorID int64, params pagination.PaginationParams) ([]APIKey, *pagination.PaginationResult, error)
 	VerifyOwnership(ctx context.Context, userID int64, apiKeyIDs []int64) ([]int64, error)
 	CountByUserID(ctx context.Context, userID int64) (int64, error)
 	ExistsByKey(ctx context.Context, key string) (bool, error)
@@ -67,20 +77,20 @@ type APIKeyRepository interface {
 	ListKeysByGroupID(ctx context.Context, groupID int64) ([]string, error)
 
 	// Quota methods
-	IncrementQuotaUsed(ctx context.Context, id int64, amount float64) (float64, error)
+	IncrementQuotaUsed(ctx context.Context, id int64, amount money.Amount) (money.Amount, error)
 	UpdateLastUsed(ctx context.Context, id int64, usedAt time.Time) error
 
 	// Rate limit methods
-	IncrementRateLimitUsage(ctx context.Context, id int64, cost float64) error
+	IncrementRateLimitUsage(ctx context.Context, id int64, cost money.Amount) error
 	ResetRateLimitWindows(ctx context.Context, id int64) error
 	GetRateLimitData(ctx context.Context, id int64) (*APIKeyRateLimitData, error)
 }
 
 // APIKeyRateLimitData holds rate limit usage and window state for an API key.
 type APIKeyRateLimitData struct {
-	Usage5h       float64
-	Usage1d       float64
-	Usage7d       float64
+	Usage5h       money.Amount
+	Usage1d       money.Amount
+	Usage7d       money.Amount
 	Window5hStart *time.Time
 	Window1dStart *time.Time
 	Window7dStart *time.Time
@@ -119,6 +129,10 @@ type CreateAPIKeyRequest struct {
 	IPWhitelist []string `json:"ip_whitelist"` // IP 白名单
 	IPBlacklist []string `json:"ip_blacklist"` // IP 黑名单
 
+	// Model restriction fields (glob patterns)
+	ModelAllowlist []string `json:"model_allowlist"`
+	ModelDenylist  []string `json:"model_denylist"`
+
 	// Quota fields
 	Quota         float64 `json:"quota"`           // Quota limit in USD (0 = unlimited)
 	ExpiresInDays *int    `json:"expires_in_days"` // Days until expiry (nil = never expires)
@@ -127,6 +141,14 @@ type CreateAPIKeyRequest struct {
 	RateLimit5h float64 `json:"rate_limit_5h"`
 	RateLimit1d float64 `json:"rate_limit_1d"`
 	RateLimit7d float64 `json:"rate_limit_7d"`
+
+	// Traffic limit fields (0 = unlimited)
+	RPMLimit       int `json:"rpm_limit"`
+	TPMLimit       int `json:"tpm_limit"`
+	MaxConcurrency int `json:"max_concurrency"`
+
+	// CreatedByUserID 组织 Key 的创建成员，仅由组织服务设置
+	CreatedByUserID *int64 `json:"-"`
 }
 
 // UpdateAPIKeyRequest 更新API Key请求
@@ -137,6 +159,10 @@ type UpdateAPIKeyRequest struct {
 	IPWhitelist []string `json:"ip_whitelist"` // IP 白名单（空数组清空）
 	IPBlacklist []string `json:"ip_blacklist"` // IP 黑名单（空数组清空）
 
+	// Model restriction fields (nil = no change, empty array = clear)
+	ModelAllowlist *[]string `json:"model_allowlist"`
+	ModelDenylist  *[]string `json:"model_denylist"`
+
 	// Quota fields
 	Quota           *float64   `json:"quota"`       // Quota limit in USD (nil = no change, 0 = unlimited)
 	ExpiresAt       *time.Time `json:"expires_at"`  // Expiration time (nil = no change)
@@ -148,6 +174,11 @@ type UpdateAPIKeyRequest struct {
 	RateLimit1d         *float64 `json:"rate_limit_1d"`
 	RateLimit7d         *float64 `json:"rate_limit_7d"`
 	ResetRateLimitUsage *bool    `json:"reset_rate_limit_usage"` // Reset all usage counters to 0
+
+	// Traffic limit fields (nil = no change, 0 = unlimited)
+	RPMLimit       *int `json:"rpm_limit"`
+	TPMLimit       *int `json:"tpm_limit"`
+	MaxConcurrency *int `json:"max_concurrency"`
 }
 
 // APIKeyService API Key服务
@@ -209,6 +240,45 @@ func (s *APIKeyService) compileAPIKeyIPRules(apiKey *APIKey) {
 	apiKey.CompiledIPBlacklist = ip.CompileIPRules(apiKey.IPBlacklist)
 }
 
+// validateModelRestrictions 校验模型白名单/黑名单的 glob 格式
+func validateModelRestrictions(allowlist, denylist []string) error {
+	if invalid := ValidateModelPatterns(allowlist); len(invalid) > 0 {
+		return fmt.Errorf("%w: %v", ErrInvalidModelPattern, invalid)
+	}
+	if invalid := ValidateModelPatterns(denylist); len(invalid) > 0 {
+		return fmt.Errorf("%w: %v", ErrInvalidModelPattern, invalid)
+	}
+	return nil
+}
+
+// validateTrafficLimits 校验 RPM/TPM/并发上限（nil 表示不修改，0 表示不限制）
+func validateTrafficLimits(limits ...*int) error {
+	for _, limit := range limits {
+		if limit != nil && *limit < 0 {
+			return ErrInvalidTrafficLimit
+		}
+	}
+	return nil
+}
+
+// normalizeModelPatterns 去除首尾空白并去重，保持原有顺序
+func normalizeModelPatterns(patterns []string) []string {
+	if len(patterns) == 0 {
+		return nil
+	}
+	seen := make(map[string]struct{}, len(patterns))
+	out := make([]string, 0, len(patterns))
+	for _, p := range patterns {
+		p = strings.TrimSpace(p)
+		if _, ok := seen[p]; ok || p == "" {
+			continue
+		}
+		seen[p] = struct{}{}
+		out = append(out, p)
+	}
+	return out
+}
+
 // GenerateKey 生成随机API Key
 func (s *APIKeyService) GenerateKey() (string, error) {
 	// 生成32字节随机数据
@@ -311,6 +381,16 @@ func (s *APIKeyService) Create(ctx context.Context, userID int64, req CreateAPIK
 		}
 	}
 
+	// 验证模型限制格式
+	if err := validateModelRestrictions(req.ModelAllowlist, req.ModelDenylist); err != nil {
+		return nil, err
+	}
+
+	// 验证流量限制（RPM/TPM/并发）
+	if err := validateTrafficLimits(&req.RPMLimit, &req.TPMLimit, &req.MaxConcurrency); err != nil {
+		return nil, err
+	}
+
 	// 验证分组权限（如果指定了分组）
 	if req.GroupID != nil {
 		group, err := s.groupRepo.GetByID(ctx, *req.GroupID)
@@ -368,11 +448,20 @@ func (s *APIKeyService) Create(ctx context.Context, userID int64, req CreateAPIK
 		Status:      StatusActive,
 		IPWhitelist: req.IPWhitelist,
 		IPBlacklist: req.IPBlacklist,
-		Quota:       req.Quota,
+		Quota:       money.FromFloat(req.Quota),
 		QuotaUsed:   0,
-		RateLimit5h: req.RateLimit5h,
-		RateLimit1d: req.RateLimit1d,
-		RateLimit7d: req.RateLimit7d,
+		RateLimit5h: money.FromFloat(req.RateLimit5h),
+		RateLimit1d: money.FromFloat(req.RateLimit1d),
+		RateLimit7d: money.FromFloat(req.RateLimit7d),
+
+		ModelAllowlist: normalizeModelPatterns(req.ModelAllowlist),
+		ModelDenylist:  normalizeModelPatterns(req.ModelDenylist),
+
+		RPMLimit:       req.RPMLimit,
+		TPMLimit:       req.TPMLimit,
+		MaxConcurrency: req.MaxConcurrency,
+
+		CreatedByUserID: req.CreatedByUserID,
 	}
 
 	// Set expiration time if specified
@@ -400,6 +489,15 @@ func (s *APIKeyService) List(ctx context.Context, userID int64, params paginatio
 	return keys, pagination, nil
 }
 
+// ListByCreator 获取组织账户下由指定成员创建的 API Key 列表
+func (s *APIKeyService) ListByCreator(ctx context.Context, userID, creatorID int64, params pagination.PaginationParams) ([]APIKey, *pagination.PaginationResult, error) {
+	keys, pagination, err := s.apiKeyRepo.ListByCreator(ctx, userID, creatorID, params)
+	if err != nil {
+		return nil, nil, fmt.Errorf("list api keys by creator: %w", err)
+	}
+	return keys, pagination, nil
+}
+
 func (s *APIKeyService) VerifyOwnership(ctx context.Context, userID int64, apiKeyIDs []int64) ([]int64, error) {
 	if len(apiKeyIDs) == 0 {
 		return []int64{}, nil
@@ -500,6 +598,21 @@ func (s *APIKeyService) Update(ctx context.Context, id int64, userID int64, req
 		}
 	}
 
+	// 验证模型限制格式
+	var allowlist, denylist []string
+	if req.ModelAllowlist != nil {
+		allowlist = *req.ModelAllowlist
+	}
+	if req.ModelDenylist != nil {
+		denylist = *req.ModelDenylist
+	}
+	if err := validateModelRestrictions(allowlist, denylist); err != nil {
+		return nil, err
+	}
+	if err := validateTrafficLimits(req.RPMLimit, req.TPMLimit, req.MaxConcurrency); err != nil {
+		return nil, err
+	}
+
 	// 更新字段
 	if req.Name != nil {
 		apiKey.Name = *req.Name
@@ -534,9 +647,9 @@ func (s *APIKeyService) Update(ctx context.Context, id int64, userID int64, req
 
 	// Update quota fields
 	if req.Quota != nil {
-		apiKey.Quota = *req.Quota
+		apiKey.Quota = money.FromFloat(*req.Quota)
 		// If quota is increased and status was quota_exhausted, reactivate
-		if apiKey.Status == StatusAPIKeyQuotaExhausted && *req.Quota > apiKey.QuotaUsed {
+		if apiKey.Status == StatusAPIKeyQuotaExhausted && apiKey.Quota > apiKey.QuotaUsed {
 			apiKey.Status = StatusActive
 		}
 	}
@@ -565,17 +678,36 @@ func (s *APIKeyService) Update(ctx context.Context, id int64, userID int64, req
 	apiKey.IPWhitelist = req.IPWhitelist
 	apiKey.IPBlacklist = req.IPBlacklist
 
+	// 更新模型限制（nil 不修改，空数组清空）
+	if req.ModelAllowlist != nil {
+		apiKey.ModelAllowlist = normalizeModelPatterns(*req.ModelAllowlist)
+	}
+	if req.ModelDenylist != nil {
+		apiKey.ModelDenylist = normalizeModelPatterns(*req.ModelDenylist)
+	}
+
 	// Update rate limit configuration
 	if req.RateLimit5h != nil {
-		apiKey.RateLimit5h = *req.RateLimit5h
+		apiKey.RateLimit5h = money.FromFloat(*req.RateLimit5h)
 	}
 	if req.RateLimit1d != nil {
-		apiKey.RateLimit1d = *req.RateLimit1d
+		apiKey.RateLimit1d = money.FromFloat(*req.RateLimit1d)
 	}
 	if req.RateLimit7d != nil {
-		apiKey.RateLimit7d = *req.RateLimit7d
+		apiKey.RateLimit7d = money.FromFloat(*req.RateLimit7d)
 	}
 	resetRateLimit := req.ResetRateLimitUsage != nil && *req.ResetRateLimitUsage
+
+	// Update traffic limits
+	if req.RPMLimit != nil {
+		apiKey.RPMLimit = *req.RPMLimit
+	}
+	if req.TPMLimit != nil {
+		apiKey.TPMLimit = *req.TPMLimit
+	}
+	if req.MaxConcurrency != nil {
+		apiKey.MaxConcurrency = *req.MaxConcurrency
+	}
 	if resetRateLimit {
 		apiKey.Usage5h = 0
 		apiKey.Usage1d = 0
@@ -788,7 +920,7 @@ func (s *APIKeyService) CheckAPIKeyQuotaAndExpiry(apiKey *APIKey) error {
 
 // UpdateQuotaUsed updates the quota_used field after a request
 // Also checks if quota is exhausted and updates status accordingly
-func (s *APIKeyService) UpdateQuotaUsed(ctx context.Context, apiKeyID int64, cost float64) error {
+func (s *APIKeyService) UpdateQuotaUsed(ctx context.Context, apiKeyID int64, cost money.Amount) error {
 	if cost <= 0 {
 		return nil
 	}
@@ -824,7 +956,7 @@ func (s *APIKeyService) GetRateLimitData(ctx context.Context, id int64) (*APIKey
 }
 
 // UpdateRateLimitUsage atomically increments rate limit usage counters in the DB.
-func (s *APIKeyService) UpdateRateLimitUsage(ctx context.Context, apiKeyID int64, cost float64) error {
+func (s *APIKeyService) UpdateRateLimitUsage(ctx context.Context, apiKeyID int64, cost money.Amount) error {
 	if cost <= 0 {
 		return nil
 	}
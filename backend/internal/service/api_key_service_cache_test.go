@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/Wei-Shaw/sub2api/internal/config"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
 	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
 	"github.com/redis/go-redis/v9"
 	"github.com/stretchr/testify/require"
@@ -57,6 +58,10 @@ func (s *authRepoStub) ListByUserID(ctx context.Context, userID int64, params pa
 	panic("unexpected ListByUserID call")
 }
 
+func (s *authRepoStub) ListByCreator(ctx context.Context, userID, creatorID int64, params pagination.PaginationParams) ([]APIKey, *pagination.PaginationResult, error) {
+	panic("unexpected ListByCreator call")
+}
+
 func (s *authRepoStub) VerifyOwnership(ctx context.Context, userID int64, apiKeyIDs []int64) ([]int64, error) {
 	panic("unexpected VerifyOwnership call")
 }
@@ -99,14 +104,14 @@ func (s *authRepoStub) ListKeysByGroupID(ctx context.Context, groupID int64) ([]
 	return s.listKeysByGroupID(ctx, groupID)
 }
 
-func (s *authRepoStub) IncrementQuotaUsed(ctx context.Context, id int64, amount float64) (float64, error) {
+func (s *authRepoStub) IncrementQuotaUsed(ctx context.Context, id int64, amount money.Amount) (money.Amount, error) {
 	panic("unexpected IncrementQuotaUsed call")
 }
 
 func (s *authRepoStub) UpdateLastUsed(ctx context.Context, id int64, usedAt time.Time) error {
 	panic("unexpected UpdateLastUsed call")
 }
-func (s *authRepoStub) IncrementRateLimitUsage(ctx context.Context, id int64, cost float64) error {
+func (s *authRepoStub) IncrementRateLimitUsage(ctx context.Context, id int64, cost money.Amount) error {
 	panic("unexpected IncrementRateLimitUsage call")
 }
 func (s *authRepoStub) ResetRateLimitWindows(ctx context.Context, id int64) error {
@@ -193,7 +198,7 @@ func TestAPIKeyService_GetByKey_UsesL2Cache(t *testing.T) {
 				ID:          2,
 				Status:      StatusActive,
 				Role:        RoleUser,
-				Balance:     10,
+				Balance:     10 * money.USD,
 				Concurrency: 3,
 			},
 			Group: &APIKeyAuthGroupSnapshot{
@@ -257,7 +262,7 @@ func TestAPIKeyService_GetByKey_CacheMissStoresL2(t *testing.T) {
 					ID:          7,
 					Status:      StatusActive,
 					Role:        RoleUser,
-					Balance:     12,
+					Balance:     12 * money.USD,
 					Concurrency: 2,
 				},
 			}, nil
@@ -294,7 +299,7 @@ func TestAPIKeyService_GetByKey_UsesL1Cache(t *testing.T) {
 					ID:          3,
 					Status:      StatusActive,
 					Role:        RoleUser,
-					Balance:     5,
+					Balance:     5 * money.USD,
 					Concurrency: 2,
 				},
 			}, nil
@@ -413,7 +418,7 @@ func TestAPIKeyService_GetByKey_SingleflightCollapses(t *testing.T) {
 					ID:          2,
 					Status:      StatusActive,
 					Role:        RoleUser,
-					Balance:     1,
+					Balance:     money.USD,
 					Concurrency: 1,
 				},
 			}, nil
@@ -12,6 +12,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
 	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
 	"github.com/stretchr/testify/require"
 )
@@ -85,6 +86,10 @@ func (s *apiKeyRepoStub) ListByUserID(ctx context.Context, userID int64, params
 	panic("unexpected ListByUserID call")
 }
 
+func (s *apiKeyRepoStub) ListByCreator(ctx context.Context, userID, creatorID int64, params pagination.PaginationParams) ([]APIKey, *pagination.PaginationResult, error) {
+	panic("unexpected ListByCreator call")
+}
+
 func (s *apiKeyRepoStub) VerifyOwnership(ctx context.Context, userID int64, apiKeyIDs []int64) ([]int64, error) {
 	panic("unexpected VerifyOwnership call")
 }
@@ -121,7 +126,7 @@ func (s *apiKeyRepoStub) ListKeysByGroupID(ctx context.Context, groupID int64) (
 	panic("unexpected ListKeysByGroupID call")
 }
 
-func (s *apiKeyRepoStub) IncrementQuotaUsed(ctx context.Context, id int64, amount float64) (float64, error) {
+func (s *apiKeyRepoStub) IncrementQuotaUsed(ctx context.Context, id int64, amount money.Amount) (money.Amount, error) {
 	panic("unexpected IncrementQuotaUsed call")
 }
 
@@ -134,7 +139,7 @@ func (s *apiKeyRepoStub) UpdateLastUsed(ctx context.Context, id int64, usedAt ti
 	return nil
 }
 
-func (s *apiKeyRepoStub) IncrementRateLimitUsage(ctx context.Context, id int64, cost float64) error {
+func (s *apiKeyRepoStub) IncrementRateLimitUsage(ctx context.Context, id int64, cost money.Amount) error {
 	panic("unexpected IncrementRateLimitUsage call")
 }
 
@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/pkg/logger"
+)
+
+// APIKeyTrafficWindow API Key 当前分钟窗口的计数快照
+type APIKeyTrafficWindow struct {
+	Requests   int           // 当前分钟已准入的请求数（含本次）
+	Tokens     int           // 当前分钟已记录的 token 数
+	ResetAfter time.Duration // 距窗口重置的剩余时间（按 Redis 服务端时间计算）
+	Admitted   bool          // 本次请求是否被准入（未准入时不计入请求数）
+}
+
+// APIKeyTrafficCache API Key 每分钟请求数/token 数计数缓存
+// 键格式: api_key_traffic:{apiKeyID}:{minuteTimestamp}（哈希，字段 requests/tokens）
+type APIKeyTrafficCache interface {
+	// Admit 原子检查 RPM/TPM 上限，未超限时递增请求数（limit 为 0 表示该维度不限制）
+	Admit(ctx context.Context, apiKeyID int64, rpmLimit, tpmLimit int) (*APIKeyTrafficWindow, error)
+	// AddTokens 累加当前分钟的 token 数
+	AddTokens(ctx context.Context, apiKeyID int64, tokens int) error
+}
+
+// APIKeyTrafficState 准入检查后的限流状态，用于生成各协议的限流响应头
+type APIKeyTrafficState struct {
+	RequestLimit     int // 0 表示未限制
+	RequestRemaining int
+	TokenLimit       int // 0 表示未限制
+	TokenRemaining   int
+	ResetAfter       time.Duration
+}
+
+// APIKeyTrafficLimitService API Key 级 RPM/TPM 限流（固定分钟窗口，计数存储在 Redis）。
+// 并发上限由 ConcurrencyService 的 API Key 槽位负责。
+type APIKeyTrafficLimitService struct {
+	cache APIKeyTrafficCache
+}
+
+// NewAPIKeyTrafficLimitService 创建 API Key 限流服务
+func NewAPIKeyTrafficLimitService(cache APIKeyTrafficCache) *APIKeyTrafficLimitService {
+	return &APIKeyTrafficLimitService{cache: cache}
+}
+
+// Admit 检查 API Key 的 RPM/TPM 并计入本次请求。
+// 未配置限制时返回 (nil, nil)；超限时返回当前状态与 ErrAPIKeyRPMExceeded/ErrAPIKeyTPMExceeded；
+// Redis 异常时放行（fail open），与并发控制的降级语义一致。
+func (s *APIKeyTrafficLimitService) Admit(ctx context.Context, apiKey *APIKey) (*APIKeyTrafficState, error) {
+	if s == nil || s.cache == nil || !apiKey.HasTrafficLimits() {
+		return nil, nil
+	}
+
+	window, err := s.cache.Admit(ctx, apiKey.ID, apiKey.RPMLimit, apiKey.TPMLimit)
+	if err != nil {
+		logger.LegacyPrintf("service.api_key_traffic", "Warning: traffic limit check failed for api key %d: %v", apiKey.ID, err)
+		return nil, nil
+	}
+
+	state := &APIKeyTrafficState{
+		RequestLimit:     apiKey.RPMLimit,
+		RequestRemaining: remainingQuota(apiKey.RPMLimit, window.Requests),
+		TokenLimit:       apiKey.TPMLimit,
+		TokenRemaining:   remainingQuota(apiKey.TPMLimit, window.Tokens),
+		ResetAfter:       window.ResetAfter,
+	}
+	if window.Admitted {
+		return state, nil
+	}
+	if apiKey.TPMLimit > 0 && window.Tokens >= apiKey.TPMLimit {
+		return state, ErrAPIKeyTPMExceeded
+	}
+	return state, ErrAPIKeyRPMExceeded
+}
+
+// RecordUsage 将本次请求的 token 用量计入 TPM 窗口（仅对配置了 tpm_limit 的 Key 生效）。
+// 缓存读取 token 不计入，与上游按输入/输出/缓存写入计量 TPM 的口径一致。
+func (s *APIKeyTrafficLimitService) RecordUsage(ctx context.Context, apiKey *APIKey, usageLog *UsageLog) {
+	if s == nil || s.cache == nil || apiKey == nil || apiKey.TPMLimit <= 0 || usageLog == nil {
+		return
+	}
+	tokens := usageLog.InputTokens + usageLog.OutputTokens + usageLog.CacheCreationTokens
+	if tokens <= 0 {
+		return
+	}
+	if err := s.cache.AddTokens(ctx, apiKey.ID, tokens); err != nil {
+		logger.LegacyPrintf("service.api_key_traffic", "Warning: record tokens failed for api key %d: %v", apiKey.ID, err)
+	}
+}
+
+func remainingQuota(limit, used int) int {
+	if limit <= 0 || used >= limit {
+		return 0
+	}
+	return limit - used
+}
@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type apiKeyTrafficCacheStub struct {
+	window     *APIKeyTrafficWindow
+	admitErr   error
+	admitCalls int
+	addedIDs   []int64
+	added      []int
+}
+
+func (s *apiKeyTrafficCacheStub) Admit(_ context.Context, _ int64, _, _ int) (*APIKeyTrafficWindow, error) {
+	s.admitCalls++
+	if s.admitErr != nil {
+		return nil, s.admitErr
+	}
+	return s.window, nil
+}
+
+func (s *apiKeyTrafficCacheStub) AddTokens(_ context.Context, apiKeyID int64, tokens int) error {
+	s.addedIDs = append(s.addedIDs, apiKeyID)
+	s.added = append(s.added, tokens)
+	return nil
+}
+
+func TestAPIKeyTrafficLimitService_AdmitSkipsUnlimitedKeys(t *testing.T) {
+	cache := &apiKeyTrafficCacheStub{}
+	svc := NewAPIKeyTrafficLimitService(cache)
+
+	state, err := svc.Admit(context.Background(), &APIKey{ID: 1, MaxConcurrency: 3})
+	require.NoError(t, err)
+	require.Nil(t, state)
+	require.Zero(t, cache.admitCalls, "keys without rpm/tpm limits should not touch redis")
+}
+
+func TestAPIKeyTrafficLimitService_AdmitReturnsRemaining(t *testing.T) {
+	cache := &apiKeyTrafficCacheStub{window: &APIKeyTrafficWindow{Requests: 3, Tokens: 400, ResetAfter: 20 * time.Second, Admitted: true}}
+	svc := NewAPIKeyTrafficLimitService(cache)
+
+	state, err := svc.Admit(context.Background(), &APIKey{ID: 1, RPMLimit: 10, TPMLimit: 1000})
+	require.NoError(t, err)
+	require.Equal(t, &APIKeyTrafficState{
+		RequestLimit:     10,
+		RequestRemaining: 7,
+		TokenLimit:       1000,
+		TokenRemaining:   600,
+		ResetAfter:       20 * time.Second,
+	}, state)
+}
+
+func TestAPIKeyTrafficLimitService_AdmitRejections(t *testing.T) {
+	tests := []struct {
+		name    string
+		apiKey  *APIKey
+		window  *APIKeyTrafficWindow
+		wantErr error
+	}{
+		{
+			name:    "rpm exceeded",
+			apiKey:  &APIKey{ID: 1, RPMLimit: 2},
+			window:  &APIKeyTrafficWindow{Requests: 2},
+			wantErr: ErrAPIKeyRPMExceeded,
+		},
+		{
+			name:    "tpm exceeded",
+			apiKey:  &APIKey{ID: 1, RPMLimit: 100, TPMLimit: 500},
+			window:  &APIKeyTrafficWindow{Requests: 5, Tokens: 500},
+			wantErr: ErrAPIKeyTPMExceeded,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := NewAPIKeyTrafficLimitService(&apiKeyTrafficCacheStub{window: tt.window})
+			state, err := svc.Admit(context.Background(), tt.apiKey)
+			require.ErrorIs(t, err, tt.wantErr)
+			require.NotNil(t, state, "rejections still report state for rate limit headers")
+		})
+	}
+}
+
+func TestAPIKeyTrafficLimitService_AdmitFailsOpen(t *testing.T) {
+	svc := NewAPIKeyTrafficLimitService(&apiKeyTrafficCacheStub{admitErr: errors.New("redis down")})
+
+	state, err := svc.Admit(context.Background(), &APIKey{ID: 1, RPMLimit: 1})
+	require.NoError(t, err)
+	require.Nil(t, state)
+}
+
+func TestAPIKeyTrafficLimitService_RecordUsage(t *testing.T) {
+	cache := &apiKeyTrafficCacheStub{}
+	svc := NewAPIKeyTrafficLimitService(cache)
+	usage := &UsageLog{InputTokens: 100, OutputTokens: 50, CacheCreationTokens: 10, CacheReadTokens: 1000}
+
+	svc.RecordUsage(context.Background(), &APIKey{ID: 1, RPMLimit: 10}, usage)
+	require.Empty(t, cache.added, "keys without tpm limit should not record tokens")
+
+	svc.RecordUsage(context.Background(), &APIKey{ID: 2, TPMLimit: 1000}, usage)
+	require.Equal(t, []int64{2}, cache.addedIDs)
+	require.Equal(t, []int{160}, cache.added, "cache read tokens are excluded")
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/Wei-Shaw/sub2api/internal/config"
 	infraerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
 	"github.com/Wei-Shaw/sub2api/internal/pkg/logger"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
 	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -52,6 +53,7 @@ type JWTClaims struct {
 	Email        string `json:"email"`
 	Role         string `json:"role"`
 	TokenVersion int64  `json:"token_version"` // Used to invalidate tokens on password change
+	SessionID    string `json:"sid,omitempty"` // 所属登录会话（Refresh Token 家族 ID），仅 Token 对中的 Access Token 携带
 	jwt.RegisteredClaims
 }
 
@@ -67,12 +69,18 @@ type AuthService struct {
 	emailQueueService  *EmailQueueService
 	promoService       *PromoService
 	defaultSubAssigner DefaultSubscriptionAssigner
+	passwordPolicy     PasswordLoginPolicy
 }
 
 type DefaultSubscriptionAssigner interface {
 	AssignOrExtendSubscription(ctx context.Context, input *AssignSubscriptionInput) (*UserSubscription, bool, error)
 }
 
+// PasswordLoginPolicy 密码登录策略（如强制 SSO 的邮箱域名禁止密码登录）
+type PasswordLoginPolicy interface {
+	CheckPasswordLogin(ctx context.Context, user *User) error
+}
+
 // NewAuthService 创建认证服务实例
 func NewAuthService(
 	userRepo UserRepository,
@@ -100,6 +108,11 @@ func NewAuthService(
 	}
 }
 
+// SetPasswordLoginPolicy 注入密码登录策略（可选）
+func (s *AuthService) SetPasswordLoginPolicy(policy PasswordLoginPolicy) {
+	s.passwordPolicy = policy
+}
+
 // Register 用户注册，返回token和用户
 func (s *AuthService) Register(ctx context.Context, email, password string) (string, *User, error) {
 	return s.RegisterWithVerification(ctx, email, password, "", "", "")
@@ -183,7 +196,7 @@ func (s *AuthService) RegisterWithVerification(ctx context.Context, email, passw
 		Email:        email,
 		PasswordHash: hashedPassword,
 		Role:         RoleUser,
-		Balance:      defaultBalance,
+		Balance:      money.FromFloat(defaultBalance),
 		Concurrency:  defaultConcurrency,
 		Status:       StatusActive,
 	}
@@ -207,7 +220,7 @@ func (s *AuthService) RegisterWithVerification(ctx context.Context, email, passw
 	}
 	// 应用优惠码（如果提供且功能已启用）
 	if promoCode != "" && s.promoService != nil && s.settingService != nil && s.settingService.IsPromoCodeEnabled(ctx) {
-		if err := s.promoService.ApplyPromoCode(ctx, user.ID, promoCode); err != nil {
+		if _, err := s.promoService.ApplyPromoCode(ctx, user.ID, promoCode); err != nil {
 			// 优惠码应用失败不影响注册，只记录日志
 			logger.LegacyPrintf("service.auth", "[Auth] Failed to apply promo code for user %d: %v", user.ID, err)
 		} else {
@@ -432,6 +445,11 @@ func (s *AuthService) Login(ctx context.Context, email, password string) (string
 		return "", nil, ErrServiceUnavailable
 	}
 
+	// 组织账户仅承载共享余额与订阅，不可登录
+	if user.Role == RoleOrganization {
+		return "", nil, ErrInvalidCredentials
+	}
+
 	// 验证密码
 	if !s.CheckPassword(password, user.PasswordHash) {
 		return "", nil, ErrInvalidCredentials
@@ -442,6 +460,13 @@ func (s *AuthService) Login(ctx context.Context, email, password string) (string
 		return "", nil, ErrUserNotActive
 	}
 
+	// 密码校验通过后再检查策略，避免通过错误码探测邮箱是否由 SSO 管理
+	if s.passwordPolicy != nil {
+		if err := s.passwordPolicy.CheckPasswordLogin(ctx, user); err != nil {
+			return "", nil, err
+		}
+	}
+
 	// 生成JWT token
 	token, err := s.GenerateToken(user)
 	if err != nil {
@@ -451,6 +476,21 @@ func (s *AuthService) Login(ctx context.Context, email, password string) (string
 	return token, user, nil
 }
 
+// CheckPasskeyLogin 校验通过 Passkey 无密码登录的用户是否允许登录。
+// 与密码登录保持一致：组织账户、被禁用用户及受 SSO 强制策略约束的用户均不可登录。
+func (s *AuthService) CheckPasskeyLogin(ctx context.Context, user *User) error {
+	if user.Role == RoleOrganization {
+		return ErrInvalidCredentials
+	}
+	if !user.IsActive() {
+		return ErrUserNotActive
+	}
+	if s.passwordPolicy != nil {
+		return s.passwordPolicy.CheckPasswordLogin(ctx, user)
+	}
+	return nil
+}
+
 // LoginOrRegisterOAuth 用于第三方 OAuth/SSO 登录：
 // - 如果邮箱已存在：直接登录（不需要本地密码）
 // - 如果邮箱不存在：创建新用户并登录
@@ -502,7 +542,7 @@ func (s *AuthService) LoginOrRegisterOAuth(ctx context.Context, email, username
 				Username:     username,
 				PasswordHash: hashedPassword,
 				Role:         RoleUser,
-				Balance:      defaultBalance,
+				Balance:      money.FromFloat(defaultBalance),
 				Concurrency:  defaultConcurrency,
 				Status:       StatusActive,
 			}
@@ -599,7 +639,7 @@ func (s *AuthService) LoginOrRegisterOAuthWithTokenPair(ctx context.Context, ema
 				Username:     username,
 				PasswordHash: hashedPassword,
 				Role:         RoleUser,
-				Balance:      defaultBalance,
+				Balance:      money.FromFloat(defaultBalance),
 				Concurrency:  defaultConcurrency,
 				Status:       StatusActive,
 			}
@@ -715,12 +755,18 @@ func randomHexString(byteLength int) (string, error) {
 
 func isReservedEmail(email string) bool {
 	normalized := strings.ToLower(strings.TrimSpace(email))
-	return strings.HasSuffix(normalized, LinuxDoConnectSyntheticEmailDomain)
+	return strings.HasSuffix(normalized, LinuxDoConnectSyntheticEmailDomain) ||
+		strings.HasSuffix(normalized, OrganizationSyntheticEmailDomain) ||
+		strings.HasSuffix(normalized, SSOSyntheticEmailDomain)
 }
 
 // GenerateToken 生成JWT access token
 // 使用新的access_token_expire_minutes配置项（如果配置了），否则回退到expire_hour
 func (s *AuthService) GenerateToken(user *User) (string, error) {
+	return s.generateAccessToken(user, "")
+}
+
+func (s *AuthService) generateAccessToken(user *User, sessionID string) (string, error) {
 	now := time.Now()
 	var expiresAt time.Time
 	if s.cfg.JWT.AccessTokenExpireMinutes > 0 {
@@ -735,6 +781,7 @@ func (s *AuthService) GenerateToken(user *User) (string, error) {
 		Email:        user.Email,
 		Role:         user.Role,
 		TokenVersion: user.TokenVersion,
+		SessionID:    sessionID,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(expiresAt),
 			IssuedAt:  jwt.NewNumericDate(now),
@@ -972,19 +1019,33 @@ type TokenPair struct {
 // GenerateTokenPair 生成Access Token和Refresh Token对
 // familyID: 可选的Token家族ID，用于Token轮转时保持家族关系
 func (s *AuthService) GenerateTokenPair(ctx context.Context, user *User, familyID string) (*TokenPair, error) {
+	return s.generateTokenPair(ctx, user, familyID, nil)
+}
+
+// generateTokenPair 生成Token对；prev 为轮转前的Token数据，用于沿用会话信息
+func (s *AuthService) generateTokenPair(ctx context.Context, user *User, familyID string, prev *RefreshTokenData) (*TokenPair, error) {
 	// 检查 refreshTokenCache 是否可用
 	if s.refreshTokenCache == nil {
 		return nil, errors.New("refresh token cache not configured")
 	}
 
+	// 如果没有提供familyID，生成新的（一个家族即一次登录会话）
+	if familyID == "" {
+		familyBytes := make([]byte, 16)
+		if _, err := rand.Read(familyBytes); err != nil {
+			return nil, fmt.Errorf("generate family id: %w", err)
+		}
+		familyID = hex.EncodeToString(familyBytes)
+	}
+
 	// 生成Access Token
-	accessToken, err := s.GenerateToken(user)
+	accessToken, err := s.generateAccessToken(user, familyID)
 	if err != nil {
 		return nil, fmt.Errorf("generate access token: %w", err)
 	}
 
 	// 生成Refresh Token
-	refreshToken, err := s.generateRefreshToken(ctx, user, familyID)
+	refreshToken, err := s.generateRefreshToken(ctx, user, familyID, prev)
 	if err != nil {
 		return nil, fmt.Errorf("generate refresh token: %w", err)
 	}
@@ -997,7 +1058,7 @@ func (s *AuthService) GenerateTokenPair(ctx context.Context, user *User, familyI
 }
 
 // generateRefreshToken 生成并存储Refresh Token
-func (s *AuthService) generateRefreshToken(ctx context.Context, user *User, familyID string) (string, error) {
+func (s *AuthService) generateRefreshToken(ctx context.Context, user *User, familyID string, prev *RefreshTokenData) (string, error) {
 	// 生成随机Token
 	tokenBytes := make([]byte, 32)
 	if _, err := rand.Read(tokenBytes); err != nil {
@@ -1008,24 +1069,33 @@ func (s *AuthService) generateRefreshToken(ctx context.Context, user *User, fami
 	// 计算Token哈希（存储哈希而非原始Token）
 	tokenHash := hashToken(rawToken)
 
-	// 如果没有提供familyID，生成新的
-	if familyID == "" {
-		familyBytes := make([]byte, 16)
-		if _, err := rand.Read(familyBytes); err != nil {
-			return "", fmt.Errorf("generate family id: %w", err)
-		}
-		familyID = hex.EncodeToString(familyBytes)
-	}
-
 	now := time.Now()
 	ttl := time.Duration(s.cfg.JWT.RefreshTokenExpireDays) * 24 * time.Hour
 
+	client := ClientInfoFromContext(ctx)
 	data := &RefreshTokenData{
-		UserID:       user.ID,
-		TokenVersion: user.TokenVersion,
-		FamilyID:     familyID,
-		CreatedAt:    now,
-		ExpiresAt:    now.Add(ttl),
+		UserID:           user.ID,
+		TokenVersion:     user.TokenVersion,
+		FamilyID:         familyID,
+		CreatedAt:        now,
+		ExpiresAt:        now.Add(ttl),
+		SessionCreatedAt: now,
+		LastSeenAt:       now,
+		IP:               client.IP,
+		UserAgent:        client.UserAgent,
+	}
+	if prev != nil {
+		// 轮转时沿用会话创建时间；旧数据（升级前签发）没有该字段时退回到其签发时间
+		data.SessionCreatedAt = prev.SessionCreatedAt
+		if data.SessionCreatedAt.IsZero() {
+			data.SessionCreatedAt = prev.CreatedAt
+		}
+		if data.IP == "" {
+			data.IP = prev.IP
+		}
+		if data.UserAgent == "" {
+			data.UserAgent = prev.UserAgent
+		}
 	}
 
 	// 存储Token数据
@@ -1113,9 +1183,10 @@ func (s *AuthService) RefreshTokenPair(ctx context.Context, refreshToken string)
 		logger.LegacyPrintf("service.auth", "[Auth] Failed to delete old refresh token: %v", err)
 		// 继续处理，不影响主流程
 	}
+	_ = s.refreshTokenCache.RemoveFromUserTokenSet(ctx, user.ID, tokenHash)
 
-	// 生成新的Token对，保持同一个家族ID
-	return s.GenerateTokenPair(ctx, user, data.FamilyID)
+	// 生成新的Token对，保持同一个家族ID并沿用会话信息
+	return s.generateTokenPair(ctx, user, data.FamilyID, data)
 }
 
 // RevokeRefreshToken 撤销单个Refresh Token
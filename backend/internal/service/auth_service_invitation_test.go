@@ -5,7 +5,9 @@ package service
 import (
 	"context"
 	"testing"
+	"time"
 
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
 	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
 	"github.com/stretchr/testify/require"
 )
@@ -58,7 +60,7 @@ func (s *authInvitationRedeemRepoStub) ListByUserPaginated(_ context.Context, _
 	}
 	return filtered, &pagination.PaginationResult{Total: int64(len(filtered)), Page: 1, PageSize: len(filtered), Pages: 1}, nil
 }
-func (s *authInvitationRedeemRepoStub) SumPositiveBalanceByUser(context.Context, int64) (float64, error) {
+func (s *authInvitationRedeemRepoStub) SumPositiveBalanceByUser(context.Context, int64) (money.Amount, error) {
 	return 0, nil
 }
 
@@ -81,9 +83,18 @@ func (s *authSourcePromoRepoStub) ListWithFilters(context.Context, pagination.Pa
 	return nil, nil, nil
 }
 func (s *authSourcePromoRepoStub) CreateUsage(context.Context, *PromoCodeUsage) error { return nil }
-func (s *authSourcePromoRepoStub) GetUsageByPromoCodeAndUser(context.Context, int64, int64) (*PromoCodeUsage, error) {
+func (s *authSourcePromoRepoStub) CountUsagesByPromoCodeAndUser(context.Context, int64, int64) (int, error) {
+	return 0, nil
+}
+func (s *authSourcePromoRepoStub) ListUsagesByUser(context.Context, int64) ([]PromoCodeUsage, error) {
 	return nil, nil
 }
+func (s *authSourcePromoRepoStub) ListPendingTopupUsagesForUpdate(context.Context, int64) ([]PromoCodeUsage, error) {
+	return nil, nil
+}
+func (s *authSourcePromoRepoStub) MarkTopupBonusGranted(context.Context, int64, money.Amount, time.Time) error {
+	return nil
+}
 func (s *authSourcePromoRepoStub) GetLatestUsageByUser(context.Context, int64) (*PromoCodeUsage, error) {
 	if s.err != nil {
 		return nil, s.err
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/Wei-Shaw/sub2api/internal/config"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
 	"github.com/stretchr/testify/require"
 )
 
@@ -266,7 +267,7 @@ func TestAuthService_Register_Success(t *testing.T) {
 	require.Equal(t, "user@test.com", user.Email)
 	require.Equal(t, RoleUser, user.Role)
 	require.Equal(t, StatusActive, user.Status)
-	require.Equal(t, 3.5, user.Balance)
+	require.Equal(t, money.MustParse("3.5"), user.Balance)
 	require.Equal(t, 2, user.Concurrency)
 	require.Len(t, repo.created, 1)
 	require.True(t, user.CheckPassword("password"))
@@ -0,0 +1,132 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	infraerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/logger"
+)
+
+var ErrSessionNotFound = infraerrors.NotFound("SESSION_NOT_FOUND", "session not found")
+
+// UserSession 一次登录产生的会话（即一个 Refresh Token 家族）
+type UserSession struct {
+	ID         string // Token 家族 ID，与 Access Token 中的 sid 一致
+	IP         string
+	UserAgent  string
+	Device     string
+	CreatedAt  time.Time
+	LastSeenAt time.Time
+	ExpiresAt  time.Time
+}
+
+// ListUserSessions 列出用户当前有效的登录会话，按最近活跃时间倒序。
+// 顺带清理用户 Token 集合中已轮转或已过期的哈希。
+func (s *AuthService) ListUserSessions(ctx context.Context, userID int64) ([]UserSession, error) {
+	if s.refreshTokenCache == nil {
+		return []UserSession{}, nil
+	}
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	hashes, err := s.refreshTokenCache.GetUserTokenHashes(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("get user token hashes: %w", err)
+	}
+	tokens, err := s.refreshTokenCache.GetRefreshTokens(ctx, hashes)
+	if err != nil {
+		return nil, fmt.Errorf("get refresh tokens: %w", err)
+	}
+
+	now := time.Now()
+	byFamily := make(map[string]*UserSession)
+	var stale []string
+	for _, hash := range hashes {
+		data, ok := tokens[hash]
+		if !ok || data.UserID != userID || now.After(data.ExpiresAt) || data.TokenVersion != user.TokenVersion {
+			stale = append(stale, hash)
+			continue
+		}
+		session := sessionFromTokenData(data)
+		// 同一家族理论上只有一个有效 Token，保险起见保留最近活跃的那个
+		if existing, ok := byFamily[data.FamilyID]; ok && !session.LastSeenAt.After(existing.LastSeenAt) {
+			continue
+		}
+		byFamily[data.FamilyID] = &session
+	}
+	if len(stale) > 0 {
+		if err := s.refreshTokenCache.RemoveFromUserTokenSet(ctx, userID, stale...); err != nil {
+			logger.LegacyPrintf("service.auth", "[Auth] Failed to prune user token set: user=%d err=%v", userID, err)
+		}
+	}
+
+	sessions := make([]UserSession, 0, len(byFamily))
+	for _, session := range byFamily {
+		sessions = append(sessions, *session)
+	}
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].LastSeenAt.After(sessions[j].LastSeenAt)
+	})
+	return sessions, nil
+}
+
+// RevokeUserSession 撤销用户的单个登录会话（整个 Refresh Token 家族）。
+// 与 RevokeAllUserSessions 一致，已签发的 Access Token 在过期前仍然有效。
+func (s *AuthService) RevokeUserSession(ctx context.Context, userID int64, sessionID string) error {
+	if s.refreshTokenCache == nil || sessionID == "" {
+		return ErrSessionNotFound
+	}
+	hashes, err := s.refreshTokenCache.GetFamilyTokenHashes(ctx, sessionID)
+	if err != nil {
+		return fmt.Errorf("get family token hashes: %w", err)
+	}
+	tokens, err := s.refreshTokenCache.GetRefreshTokens(ctx, hashes)
+	if err != nil {
+		return fmt.Errorf("get refresh tokens: %w", err)
+	}
+
+	// 只能撤销属于该用户的会话，避免通过猜测家族 ID 越权
+	owned := false
+	for _, data := range tokens {
+		if data.UserID == userID {
+			owned = true
+			break
+		}
+	}
+	if !owned {
+		return ErrSessionNotFound
+	}
+
+	if err := s.refreshTokenCache.DeleteTokenFamily(ctx, sessionID); err != nil {
+		return fmt.Errorf("delete token family: %w", err)
+	}
+	if err := s.refreshTokenCache.RemoveFromUserTokenSet(ctx, userID, hashes...); err != nil {
+		logger.LegacyPrintf("service.auth", "[Auth] Failed to prune user token set: user=%d err=%v", userID, err)
+	}
+	return nil
+}
+
+func sessionFromTokenData(data *RefreshTokenData) UserSession {
+	createdAt := data.SessionCreatedAt
+	if createdAt.IsZero() {
+		createdAt = data.CreatedAt
+	}
+	lastSeenAt := data.LastSeenAt
+	if lastSeenAt.IsZero() {
+		lastSeenAt = data.CreatedAt
+	}
+	return UserSession{
+		ID:         data.FamilyID,
+		IP:         data.IP,
+		UserAgent:  data.UserAgent,
+		Device:     DescribeDevice(data.UserAgent),
+		CreatedAt:  createdAt,
+		LastSeenAt: lastSeenAt,
+		ExpiresAt:  data.ExpiresAt,
+	}
+}
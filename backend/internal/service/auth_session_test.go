@@ -0,0 +1,231 @@
+//go:build unit
+
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/config"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryRefreshTokenCache 内存版 RefreshTokenCache（不处理 TTL）
+type memoryRefreshTokenCache struct {
+	tokens   map[string]*RefreshTokenData
+	users    map[int64]map[string]struct{}
+	families map[string]map[string]struct{}
+}
+
+func newMemoryRefreshTokenCache() *memoryRefreshTokenCache {
+	return &memoryRefreshTokenCache{
+		tokens:   make(map[string]*RefreshTokenData),
+		users:    make(map[int64]map[string]struct{}),
+		families: make(map[string]map[string]struct{}),
+	}
+}
+
+func (c *memoryRefreshTokenCache) StoreRefreshToken(ctx context.Context, tokenHash string, data *RefreshTokenData, ttl time.Duration) error {
+	copied := *data
+	c.tokens[tokenHash] = &copied
+	return nil
+}
+
+func (c *memoryRefreshTokenCache) GetRefreshToken(ctx context.Context, tokenHash string) (*RefreshTokenData, error) {
+	data, ok := c.tokens[tokenHash]
+	if !ok {
+		return nil, ErrRefreshTokenNotFound
+	}
+	copied := *data
+	return &copied, nil
+}
+
+func (c *memoryRefreshTokenCache) GetRefreshTokens(ctx context.Context, tokenHashes []string) (map[string]*RefreshTokenData, error) {
+	out := make(map[string]*RefreshTokenData)
+	for _, hash := range tokenHashes {
+		if data, ok := c.tokens[hash]; ok {
+			copied := *data
+			out[hash] = &copied
+		}
+	}
+	return out, nil
+}
+
+func (c *memoryRefreshTokenCache) DeleteRefreshToken(ctx context.Context, tokenHash string) error {
+	delete(c.tokens, tokenHash)
+	return nil
+}
+
+func (c *memoryRefreshTokenCache) DeleteUserRefreshTokens(ctx context.Context, userID int64) error {
+	for hash := range c.users[userID] {
+		delete(c.tokens, hash)
+	}
+	delete(c.users, userID)
+	return nil
+}
+
+func (c *memoryRefreshTokenCache) DeleteTokenFamily(ctx context.Context, familyID string) error {
+	for hash := range c.families[familyID] {
+		delete(c.tokens, hash)
+	}
+	delete(c.families, familyID)
+	return nil
+}
+
+func (c *memoryRefreshTokenCache) AddToUserTokenSet(ctx context.Context, userID int64, tokenHash string, ttl time.Duration) error {
+	if c.users[userID] == nil {
+		c.users[userID] = make(map[string]struct{})
+	}
+	c.users[userID][tokenHash] = struct{}{}
+	return nil
+}
+
+func (c *memoryRefreshTokenCache) AddToFamilyTokenSet(ctx context.Context, familyID string, tokenHash string, ttl time.Duration) error {
+	if c.families[familyID] == nil {
+		c.families[familyID] = make(map[string]struct{})
+	}
+	c.families[familyID][tokenHash] = struct{}{}
+	return nil
+}
+
+func (c *memoryRefreshTokenCache) RemoveFromUserTokenSet(ctx context.Context, userID int64, tokenHashes ...string) error {
+	for _, hash := range tokenHashes {
+		delete(c.users[userID], hash)
+	}
+	return nil
+}
+
+func (c *memoryRefreshTokenCache) GetUserTokenHashes(ctx context.Context, userID int64) ([]string, error) {
+	var out []string
+	for hash := range c.users[userID] {
+		out = append(out, hash)
+	}
+	return out, nil
+}
+
+func (c *memoryRefreshTokenCache) GetFamilyTokenHashes(ctx context.Context, familyID string) ([]string, error) {
+	var out []string
+	for hash := range c.families[familyID] {
+		out = append(out, hash)
+	}
+	return out, nil
+}
+
+func (c *memoryRefreshTokenCache) IsTokenInFamily(ctx context.Context, familyID string, tokenHash string) (bool, error) {
+	_, ok := c.families[familyID][tokenHash]
+	return ok, nil
+}
+
+func newSessionTestAuthService(users ...*User) (*AuthService, *memoryRefreshTokenCache) {
+	cache := newMemoryRefreshTokenCache()
+	cfg := &config.Config{JWT: config.JWTConfig{Secret: "test-secret", ExpireHour: 1, RefreshTokenExpireDays: 7}}
+	return NewAuthService(newSSOUserRepoFake(users...), nil, cache, cfg, nil, nil, nil, nil, nil, nil), cache
+}
+
+func sessionTestUser() *User {
+	return &User{ID: 7, Email: "alice@example.com", Role: RoleUser, Status: StatusActive}
+}
+
+func TestUserSessions_TrackedPerLoginAndKeptAcrossRotation(t *testing.T) {
+	user := sessionTestUser()
+	svc, _ := newSessionTestAuthService(user)
+
+	laptop := WithClientInfo(context.Background(), ClientInfo{
+		IP:        "203.0.113.10",
+		UserAgent: "Mozilla/5.0 (Macintosh; Intel Mac OS X 14_5) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/126.0 Safari/537.36",
+	})
+	phone := WithClientInfo(context.Background(), ClientInfo{
+		IP:        "198.51.100.20",
+		UserAgent: "Mozilla/5.0 (iPhone; CPU iPhone OS 17_5 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.5 Mobile/15E148 Safari/604.1",
+	})
+
+	first, err := svc.GenerateTokenPair(laptop, user, "")
+	require.NoError(t, err)
+	_, err = svc.GenerateTokenPair(phone, user, "")
+	require.NoError(t, err)
+
+	claims, err := svc.ValidateToken(first.AccessToken)
+	require.NoError(t, err)
+	require.NotEmpty(t, claims.SessionID)
+
+	// 轮转后仍是同一个会话，IP 更新为最近一次刷新的来源
+	moved := WithClientInfo(context.Background(), ClientInfo{IP: "203.0.113.99", UserAgent: ClientInfoFromContext(laptop).UserAgent})
+	rotated, err := svc.RefreshTokenPair(moved, first.RefreshToken)
+	require.NoError(t, err)
+	rotatedClaims, err := svc.ValidateToken(rotated.AccessToken)
+	require.NoError(t, err)
+	require.Equal(t, claims.SessionID, rotatedClaims.SessionID)
+
+	sessions, err := svc.ListUserSessions(context.Background(), user.ID)
+	require.NoError(t, err)
+	require.Len(t, sessions, 2)
+
+	byID := map[string]UserSession{}
+	for _, s := range sessions {
+		byID[s.ID] = s
+	}
+	laptopSession, ok := byID[claims.SessionID]
+	require.True(t, ok)
+	require.Equal(t, "203.0.113.99", laptopSession.IP)
+	require.Equal(t, "Chrome on macOS", laptopSession.Device)
+	require.False(t, laptopSession.LastSeenAt.Before(laptopSession.CreatedAt))
+}
+
+func TestUserSessions_SkipsRevokedAndPrunesStaleHashes(t *testing.T) {
+	user := sessionTestUser()
+	svc, cache := newSessionTestAuthService(user)
+	ctx := context.Background()
+
+	pair, err := svc.GenerateTokenPair(ctx, user, "")
+	require.NoError(t, err)
+	_, err = svc.RefreshTokenPair(ctx, pair.RefreshToken)
+	require.NoError(t, err)
+	require.Len(t, cache.users[user.ID], 1, "rotated token hash should be removed from the user set")
+
+	// 密码修改后旧会话不再列出
+	user.TokenVersion++
+	sessions, err := svc.ListUserSessions(ctx, user.ID)
+	require.NoError(t, err)
+	require.Empty(t, sessions)
+	require.Empty(t, cache.users[user.ID])
+}
+
+func TestRevokeUserSession_OnlyOwnSessions(t *testing.T) {
+	alice := sessionTestUser()
+	bob := &User{ID: 8, Email: "bob@example.com", Role: RoleUser, Status: StatusActive}
+	svc, _ := newSessionTestAuthService(alice, bob)
+	ctx := context.Background()
+
+	alicePair, err := svc.GenerateTokenPair(ctx, alice, "")
+	require.NoError(t, err)
+	_, err = svc.GenerateTokenPair(ctx, alice, "")
+	require.NoError(t, err)
+	claims, err := svc.ValidateToken(alicePair.AccessToken)
+	require.NoError(t, err)
+
+	require.ErrorIs(t, svc.RevokeUserSession(ctx, bob.ID, claims.SessionID), ErrSessionNotFound)
+	require.ErrorIs(t, svc.RevokeUserSession(ctx, alice.ID, "missing"), ErrSessionNotFound)
+
+	require.NoError(t, svc.RevokeUserSession(ctx, alice.ID, claims.SessionID))
+	_, err = svc.RefreshTokenPair(ctx, alicePair.RefreshToken)
+	require.ErrorIs(t, err, ErrRefreshTokenInvalid)
+
+	sessions, err := svc.ListUserSessions(ctx, alice.ID)
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+	require.NotEqual(t, claims.SessionID, sessions[0].ID)
+}
+
+func TestDescribeDevice(t *testing.T) {
+	cases := map[string]string{
+		"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/126.0 Safari/537.36 Edg/126.0": "Edge on Windows",
+		"Mozilla/5.0 (X11; Linux x86_64; rv:127.0) Gecko/20100101 Firefox/127.0":                                                "Firefox on Linux",
+		"Mozilla/5.0 (Linux; Android 14; Pixel 8) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/126.0 Mobile Safari/537.36":     "Chrome on Android",
+		"curl/8.5.0": "curl",
+		"":           "Unknown",
+	}
+	for ua, want := range cases {
+		require.Equal(t, want, DescribeDevice(ua), ua)
+	}
+}
@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
+)
+
+// 余额流水来源类型（balance_ledger.source_type）
+const (
+	BalanceLedgerSourceRedeem          = "redeem"            // 余额兑换码，reference_id 为兑换码 ID
+	BalanceLedgerSourcePromoCode       = "promo_code"        // 优惠码固定赠送，reference_id 为优惠码 ID
+	BalanceLedgerSourcePromoTopupBonus = "promo_topup_bonus" // 优惠码首次充值赠送，reference_id 为优惠码使用记录 ID
+	BalanceLedgerSourceAdminAdjust     = "admin_adjust"      // 管理员调整余额，operator_id 为管理员
+	BalanceLedgerSourceActivityReward  = "activity_reward"   // 活动奖励，reference_id 为活动 ID
+	BalanceLedgerSourceActivityCost    = "activity_cost"     // 活动参与扣费，reference_id 为活动 ID
+	BalanceLedgerSourceCheckin         = "checkin"           // 每日签到奖励，reference_id 为签到记录 ID
+	BalanceLedgerSourcePaymentRefund   = "payment_refund"    // 支付订单退款扣回余额，reference_id 为订单号
+	BalanceLedgerSourceReferral        = "referral"          // 推荐返佣直接计入余额，reference_id 为返佣记录 ID
+	BalanceLedgerSourceReferralWallet  = "referral_wallet"   // 佣金钱包转入余额，reference_id 为提现记录 ID
+	BalanceLedgerSourcePointsExchange  = "points_exchange"   // 积分商城兑换余额，reference_id 为兑换记录 ID
+	BalanceLedgerSourceUsage           = "usage"             // API 使用扣费，reference_id 为 request_id
+	BalanceLedgerSourceInitial         = "initial"           // 创建用户时的初始余额
+	BalanceLedgerSourceOpening         = "opening"           // 启用流水前的存量余额（迁移时写入的期初记录）
+)
+
+// BalanceLedgerSource 描述一次余额变动的来源，随余额更新一并写入流水。
+type BalanceLedgerSource struct {
+	Type        string
+	ReferenceID string
+	// OperatorID 为执行操作的管理员 ID，用户自身触发的变动为 nil
+	OperatorID *int64
+	Notes      string
+}
+
+// BalanceLedgerEntry 余额流水中的一条记录（只追加，不可修改）
+type BalanceLedgerEntry struct {
+	ID            int64
+	UserID        int64
+	SourceType    string
+	ReferenceID   string
+	OperatorID    *int64
+	Amount        money.Amount
+	BalanceBefore money.Amount
+	BalanceAfter  money.Amount
+	Notes         string
+	CreatedAt     time.Time
+}
+
+// BalanceLedgerFilter 余额流水查询条件
+type BalanceLedgerFilter struct {
+	UserID      int64
+	SourceType  string
+	ReferenceID string
+	OperatorID  *int64
+	StartTime   *time.Time
+	EndTime     *time.Time
+}
+
+// BalanceLedgerMismatch 流水合计与用户当前余额不一致的记录
+type BalanceLedgerMismatch struct {
+	UserID    int64        `json:"user_id"`
+	Balance   money.Amount `json:"balance"`
+	LedgerSum money.Amount `json:"ledger_sum"`
+}
+
+// BalanceLedgerReconcileRun 一次对账执行的结果
+type BalanceLedgerReconcileRun struct {
+	ID            int64                   `json:"id"`
+	StartedAt     time.Time               `json:"started_at"`
+	FinishedAt    time.Time               `json:"finished_at"`
+	CheckedUsers  int64                   `json:"checked_users"`
+	MismatchCount int64                   `json:"mismatch_count"`
+	Samples       []BalanceLedgerMismatch `json:"samples"`
+}
+
+// BalanceLedgerRepository 余额流水查询与对账
+//
+// 流水的写入由 UserRepository 的余额变更方法在同一条语句中完成，此处只负责读取与对账。
+type BalanceLedgerRepository interface {
+	List(ctx context.Context, params pagination.PaginationParams, filter BalanceLedgerFilter) ([]BalanceLedgerEntry, *pagination.PaginationResult, error)
+	// Reconcile 比对每个用户的流水合计与 users.balance，并写入一条对账记录。
+	// 多实例同时执行时只有一个实例真正对账，其余返回 ErrBalanceLedgerReconcileBusy。
+	Reconcile(ctx context.Context, sampleLimit int) (*BalanceLedgerReconcileRun, error)
+	LatestReconcileRun(ctx context.Context) (*BalanceLedgerReconcileRun, error)
+}
+
+// usageLedgerSource 返回 API 使用扣费对应的流水来源
+func usageLedgerSource(usageLog *UsageLog) BalanceLedgerSource {
+	if usageLog == nil {
+		return BalanceLedgerSource{Type: BalanceLedgerSourceUsage}
+	}
+	return BalanceLedgerSource{Type: BalanceLedgerSourceUsage, ReferenceID: usageLog.RequestID}
+}
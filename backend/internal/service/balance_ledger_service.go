@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/config"
+	infraerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/logger"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
+)
+
+var (
+	ErrBalanceLedgerReconcileBusy    = infraerrors.Conflict("BALANCE_LEDGER_RECONCILE_BUSY", "balance ledger reconciliation is already running")
+	ErrBalanceLedgerInvalidSource    = infraerrors.BadRequest("BALANCE_LEDGER_INVALID_SOURCE", "invalid balance ledger source type")
+	ErrBalanceLedgerInvalidTimeRange = infraerrors.BadRequest("BALANCE_LEDGER_INVALID_TIME_RANGE", "start_time must be before end_time")
+)
+
+const balanceLedgerReconcileTimeout = 5 * time.Minute
+
+var balanceLedgerSourceTypes = map[string]struct{}{
+	BalanceLedgerSourceRedeem:         {},
+	BalanceLedgerSourcePromoCode:      {},
+	BalanceLedgerSourceAdminAdjust:    {},
+	BalanceLedgerSourceActivityReward: {},
+	BalanceLedgerSourceActivityCost:   {},
+	BalanceLedgerSourceCheckin:        {},
+	BalanceLedgerSourcePaymentRefund:  {},
+	BalanceLedgerSourceReferral:       {},
+	BalanceLedgerSourceReferralWallet: {},
+	BalanceLedgerSourcePointsExchange: {},
+	BalanceLedgerSourceUsage:          {},
+	BalanceLedgerSourceInitial:        {},
+	BalanceLedgerSourceOpening:        {},
+}
+
+// BalanceLedgerService 余额流水查询与定时对账
+type BalanceLedgerService struct {
+	repo BalanceLedgerRepository
+	cfg  config.BillingLedgerReconcileConfig
+
+	stopCh   chan struct{}
+	stopOnce sync.Once
+	wg       sync.WaitGroup
+}
+
+func NewBalanceLedgerService(repo BalanceLedgerRepository, cfg *config.Config) *BalanceLedgerService {
+	svc := &BalanceLedgerService{
+		repo:   repo,
+		stopCh: make(chan struct{}),
+	}
+	if cfg != nil {
+		svc.cfg = cfg.Billing.LedgerReconcile
+	}
+	return svc
+}
+
+// ListUserTransactions 查询用户自己的余额流水
+func (s *BalanceLedgerService) ListUserTransactions(ctx context.Context, userID int64, params pagination.PaginationParams, filter BalanceLedgerFilter) ([]BalanceLedgerEntry, *pagination.PaginationResult, error) {
+	filter.UserID = userID
+	filter.OperatorID = nil
+	return s.Search(ctx, params, filter)
+}
+
+// Search 按条件检索余额流水（管理员）
+func (s *BalanceLedgerService) Search(ctx context.Context, params pagination.PaginationParams, filter BalanceLedgerFilter) ([]BalanceLedgerEntry, *pagination.PaginationResult, error) {
+	if filter.SourceType != "" {
+		if _, ok := balanceLedgerSourceTypes[filter.SourceType]; !ok {
+			return nil, nil, ErrBalanceLedgerInvalidSource
+		}
+	}
+	if filter.StartTime != nil && filter.EndTime != nil && !filter.StartTime.Before(*filter.EndTime) {
+		return nil, nil, ErrBalanceLedgerInvalidTimeRange
+	}
+	return s.repo.List(ctx, params, filter)
+}
+
+// ReconcileNow 立即执行一次对账
+func (s *BalanceLedgerService) ReconcileNow(ctx context.Context) (*BalanceLedgerReconcileRun, error) {
+	sampleLimit := s.cfg.SampleLimit
+	if sampleLimit <= 0 {
+		sampleLimit = 50
+	}
+	run, err := s.repo.Reconcile(ctx, sampleLimit)
+	if err != nil {
+		return nil, err
+	}
+	if run.MismatchCount > 0 {
+		logger.LegacyPrintf("service.balance_ledger", "[BalanceLedger] reconcile found %d mismatched users (checked=%d run_id=%d)", run.MismatchCount, run.CheckedUsers, run.ID)
+	}
+	return run, nil
+}
+
+// LatestReconcile 返回最近一次对账结果，尚未对账时返回 nil
+func (s *BalanceLedgerService) LatestReconcile(ctx context.Context) (*BalanceLedgerReconcileRun, error) {
+	return s.repo.LatestReconcileRun(ctx)
+}
+
+// LatestMismatchCount 返回最近一次对账的不一致用户数（运维告警指标 balance_ledger_mismatch_count）
+func (s *BalanceLedgerService) LatestMismatchCount(ctx context.Context) (int64, bool) {
+	if s == nil || s.repo == nil {
+		return 0, false
+	}
+	run, err := s.repo.LatestReconcileRun(ctx)
+	if err != nil || run == nil {
+		return 0, false
+	}
+	return run.MismatchCount, true
+}
+
+func (s *BalanceLedgerService) Start() {
+	if s == nil || s.repo == nil || !s.cfg.Enabled || s.cfg.IntervalSeconds <= 0 {
+		return
+	}
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		ticker := time.NewTicker(time.Duration(s.cfg.IntervalSeconds) * time.Second)
+		defer ticker.Stop()
+
+		s.runOnce()
+		for {
+			select {
+			case <-ticker.C:
+				s.runOnce()
+			case <-s.stopCh:
+				return
+			}
+		}
+	}()
+}
+
+func (s *BalanceLedgerService) Stop() {
+	if s == nil {
+		return
+	}
+	s.stopOnce.Do(func() {
+		close(s.stopCh)
+	})
+	s.wg.Wait()
+}
+
+func (s *BalanceLedgerService) runOnce() {
+	ctx, cancel := context.WithTimeout(context.Background(), balanceLedgerReconcileTimeout)
+	defer cancel()
+
+	if _, err := s.ReconcileNow(ctx); err != nil {
+		if errors.Is(err, ErrBalanceLedgerReconcileBusy) {
+			return
+		}
+		logger.LegacyPrintf("service.balance_ledger", "[BalanceLedger] reconcile failed: %v", err)
+	}
+}
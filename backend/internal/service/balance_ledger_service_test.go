@@ -0,0 +1,86 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/config"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
+	"github.com/stretchr/testify/require"
+)
+
+type balanceLedgerRepoStub struct {
+	lastFilter  BalanceLedgerFilter
+	listCalls   int
+	sampleLimit int
+	latest      *BalanceLedgerReconcileRun
+}
+
+func (r *balanceLedgerRepoStub) List(_ context.Context, params pagination.PaginationParams, filter BalanceLedgerFilter) ([]BalanceLedgerEntry, *pagination.PaginationResult, error) {
+	r.listCalls++
+	r.lastFilter = filter
+	return []BalanceLedgerEntry{}, &pagination.PaginationResult{Page: params.Page, PageSize: params.PageSize}, nil
+}
+
+func (r *balanceLedgerRepoStub) Reconcile(_ context.Context, sampleLimit int) (*BalanceLedgerReconcileRun, error) {
+	r.sampleLimit = sampleLimit
+	r.latest = &BalanceLedgerReconcileRun{ID: 1, CheckedUsers: 3, MismatchCount: 2}
+	return r.latest, nil
+}
+
+func (r *balanceLedgerRepoStub) LatestReconcileRun(context.Context) (*BalanceLedgerReconcileRun, error) {
+	return r.latest, nil
+}
+
+func TestBalanceLedgerService_ListUserTransactionsScopesToUser(t *testing.T) {
+	repo := &balanceLedgerRepoStub{}
+	svc := NewBalanceLedgerService(repo, nil)
+	operatorID := int64(7)
+
+	_, _, err := svc.ListUserTransactions(context.Background(), 42, pagination.PaginationParams{Page: 1, PageSize: 20}, BalanceLedgerFilter{
+		UserID:     1,
+		OperatorID: &operatorID,
+		SourceType: BalanceLedgerSourceUsage,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(42), repo.lastFilter.UserID)
+	require.Nil(t, repo.lastFilter.OperatorID)
+	require.Equal(t, BalanceLedgerSourceUsage, repo.lastFilter.SourceType)
+}
+
+func TestBalanceLedgerService_SearchValidatesFilter(t *testing.T) {
+	repo := &balanceLedgerRepoStub{}
+	svc := NewBalanceLedgerService(repo, nil)
+	params := pagination.PaginationParams{Page: 1, PageSize: 20}
+
+	_, _, err := svc.Search(context.Background(), params, BalanceLedgerFilter{SourceType: "bogus"})
+	require.ErrorIs(t, err, ErrBalanceLedgerInvalidSource)
+
+	now := time.Now()
+	_, _, err = svc.Search(context.Background(), params, BalanceLedgerFilter{StartTime: &now, EndTime: &now})
+	require.ErrorIs(t, err, ErrBalanceLedgerInvalidTimeRange)
+	require.Zero(t, repo.listCalls)
+}
+
+func TestBalanceLedgerService_LatestMismatchCount(t *testing.T) {
+	repo := &balanceLedgerRepoStub{}
+	cfg := &config.Config{}
+	cfg.Billing.LedgerReconcile.SampleLimit = 5
+	svc := NewBalanceLedgerService(repo, cfg)
+
+	_, ok := svc.LatestMismatchCount(context.Background())
+	require.False(t, ok, "no reconcile run yet")
+
+	_, err := svc.ReconcileNow(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 5, repo.sampleLimit)
+
+	count, ok := svc.LatestMismatchCount(context.Background())
+	require.True(t, ok)
+	require.Equal(t, int64(2), count)
+
+	var nilSvc *BalanceLedgerService
+	_, ok = nilSvc.LatestMismatchCount(context.Background())
+	require.False(t, ok)
+}
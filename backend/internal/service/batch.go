@@ -0,0 +1,160 @@
+package service
+
+import (
+	"context"
+	"time"
+)
+
+// 批处理任务格式
+const (
+	BatchFormatOpenAI    = "openai"    // /v1/files + /v1/batches
+	BatchFormatAnthropic = "anthropic" // /v1/messages/batches
+)
+
+// 批处理任务状态（与 OpenAI Batch 对象的 status 取值保持一致）
+const (
+	BatchStatusInProgress = "in_progress"
+	BatchStatusFinalizing = "finalizing"
+	BatchStatusCompleted  = "completed"
+	BatchStatusExpired    = "expired"
+	BatchStatusCancelling = "cancelling"
+	BatchStatusCancelled  = "cancelled"
+)
+
+// 批处理请求行状态
+const (
+	BatchItemStatusPending   = "pending"
+	BatchItemStatusRunning   = "running"
+	BatchItemStatusSucceeded = "succeeded"
+	BatchItemStatusFailed    = "failed"
+	BatchItemStatusCancelled = "cancelled"
+	BatchItemStatusExpired   = "expired"
+)
+
+// 批处理文件用途
+const (
+	BatchFilePurposeInput  = "batch"
+	BatchFilePurposeOutput = "batch_output"
+)
+
+// BatchFile 表示 /v1/files 上传的输入文件或批处理任务生成的结果文件。
+type BatchFile struct {
+	ID        int64
+	FileID    string
+	UserID    int64
+	APIKeyID  *int64
+	Purpose   string
+	Filename  string
+	Bytes     int64
+	Content   []byte // 列表查询时不加载
+	CreatedAt time.Time
+	ExpiresAt *time.Time
+}
+
+// BatchRequestCounts 批处理任务的逐行统计。
+type BatchRequestCounts struct {
+	Total     int
+	Succeeded int
+	Failed    int
+	Cancelled int
+	Expired   int
+}
+
+// Processing 返回尚未结束的行数。
+func (c BatchRequestCounts) Processing() int {
+	n := c.Total - c.Succeeded - c.Failed - c.Cancelled - c.Expired
+	if n < 0 {
+		return 0
+	}
+	return n
+}
+
+// BatchJob 表示一个异步批处理任务。
+type BatchJob struct {
+	ID               int64
+	BatchID          string
+	Format           string
+	UserID           int64
+	APIKeyID         int64
+	Endpoint         string
+	InputFileID      string
+	OutputFileID     string
+	ErrorFileID      string
+	CompletionWindow string
+	Metadata         map[string]string
+	ClientIP         string
+	Status           string
+	Counts           BatchRequestCounts
+	ErrorMessage     string
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+	ExpiresAt        time.Time
+	InProgressAt     *time.Time
+	FinalizingAt     *time.Time
+	CompletedAt      *time.Time
+	ExpiredAt        *time.Time
+	CancellingAt     *time.Time
+	CancelledAt      *time.Time
+}
+
+// IsTerminal 任务是否已结束（不会再产生新的结果）。
+func (j *BatchJob) IsTerminal() bool {
+	switch j.Status {
+	case BatchStatusCompleted, BatchStatusExpired, BatchStatusCancelled:
+		return true
+	default:
+		return false
+	}
+}
+
+// BatchJobItem 表示批处理任务中的一行请求及其执行结果。
+type BatchJobItem struct {
+	ID             int64
+	JobID          int64
+	LineNo         int
+	CustomID       string
+	URL            string
+	Body           []byte
+	Status         string
+	ResponseStatus int
+	ResponseBody   []byte
+	RequestID      string
+	ErrorMessage   string
+	StartedAt      *time.Time
+	FinishedAt     *time.Time
+}
+
+// BatchRepository 批处理文件、任务与请求行的持久化接口。
+type BatchRepository interface {
+	CreateFile(ctx context.Context, file *BatchFile) error
+	// GetFile 按用户与对外 ID 查询文件，withContent=false 时不加载文件内容；不存在返回 nil, nil
+	GetFile(ctx context.Context, userID int64, fileID string, withContent bool) (*BatchFile, error)
+	// ListFiles 按 ID 倒序列出用户文件，after 为上一页最后一个文件的对外 ID
+	ListFiles(ctx context.Context, userID int64, purpose string, after string, limit int) ([]*BatchFile, error)
+	DeleteFile(ctx context.Context, userID int64, fileID string) (bool, error)
+	DeleteExpiredFiles(ctx context.Context, now time.Time) (int64, error)
+
+	// CreateJob 在同一事务中写入任务与全部请求行
+	CreateJob(ctx context.Context, job *BatchJob, items []*BatchJobItem) error
+	// GetJob 按用户与对外 ID 查询任务；不存在返回 nil, nil
+	GetJob(ctx context.Context, userID int64, format string, batchID string) (*BatchJob, error)
+	ListJobs(ctx context.Context, userID int64, format string, after string, limit int) ([]*BatchJob, error)
+	// UpdateJobStatus 仅当任务处于 fromStatuses 之一时切换状态，并记录对应的时间戳
+	UpdateJobStatus(ctx context.Context, jobID int64, fromStatuses []string, toStatus string) (bool, error)
+	UpdateJobCounts(ctx context.Context, jobID int64, counts BatchRequestCounts) error
+	// FinalizeJob 写入最终状态、结果文件与统计，并释放租约
+	FinalizeJob(ctx context.Context, job *BatchJob) error
+
+	// ClaimActiveJobs 以租约方式领取需要推进的任务（FOR UPDATE SKIP LOCKED，多实例安全）
+	ClaimActiveJobs(ctx context.Context, lease time.Duration, limit int) ([]*BatchJob, error)
+	ReleaseJob(ctx context.Context, jobID int64) error
+
+	// ClaimPendingItems 领取待执行的行；started_at 早于 staleBefore 的 running 行视为执行中断，重新领取
+	ClaimPendingItems(ctx context.Context, jobID int64, staleBefore time.Time, limit int) ([]*BatchJobItem, error)
+	CompleteItem(ctx context.Context, item *BatchJobItem) error
+	// CloseRemainingItems 将未结束的行（pending/running）批量置为指定状态
+	CloseRemainingItems(ctx context.Context, jobID int64, status string, errorMessage string) (int64, error)
+	CountItems(ctx context.Context, jobID int64) (BatchRequestCounts, error)
+	// ListItems 按行号顺序返回全部请求行（含执行结果，不含请求体）
+	ListItems(ctx context.Context, jobID int64) ([]*BatchJobItem, error)
+}
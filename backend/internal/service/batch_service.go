@@ -0,0 +1,1060 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/config"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/ctxkey"
+	infraerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/logger"
+	"github.com/tidwall/gjson"
+	"github.com/tidwall/sjson"
+)
+
+const (
+	batchWorkerName = "batch_worker"
+
+	batchCompletionWindow   = "24h"
+	batchJobsPerRound       = 10
+	batchFileCleanupEvery   = time.Hour
+	batchMaxCustomIDLength  = 255
+	batchUserAgent          = "sub2api-batch/1.0"
+	batchAnthropicEndpoint  = "/v1/messages"
+	batchInternalErrorCode  = "batch_internal_error"
+	batchResultLineIDPrefix = "batch_req_"
+)
+
+// batchOpenAIEndpoints OpenAI 批处理允许的 endpoint（回放时经由网关路由按分组平台分发）
+var batchOpenAIEndpoints = map[string]struct{}{
+	"/v1/chat/completions": {},
+	"/v1/responses":        {},
+	"/v1/embeddings":       {},
+	"/v1/completions":      {},
+	"/v1/messages":         {},
+}
+
+var (
+	ErrBatchDisabled     = infraerrors.ServiceUnavailable("BATCH_DISABLED", "batch api is disabled")
+	ErrBatchFileNotFound = infraerrors.NotFound("BATCH_FILE_NOT_FOUND", "file not found")
+	ErrBatchNotFound     = infraerrors.NotFound("BATCH_NOT_FOUND", "batch not found")
+	ErrBatchNotEnded     = infraerrors.Conflict("BATCH_NOT_ENDED", "batch has not finished processing")
+)
+
+// CreateOpenAIBatchInput /v1/batches 创建参数
+type CreateOpenAIBatchInput struct {
+	InputFileID      string
+	Endpoint         string
+	CompletionWindow string
+	Metadata         map[string]string
+	Subscription     *UserSubscription // 订阅分组下的当前订阅，用于提交时的预授权冻结
+}
+
+// BatchService 负责批处理文件/任务管理，并在后台以低优先级回放请求行。
+//
+// 每行请求在进程内经由网关路由（与普通请求相同的认证、调度、failover 与计费链路）执行，
+// 因此不依赖上游账号原生的 Batch 能力；计费时由 BillingService 叠加批处理折扣。
+type BatchService struct {
+	repo               BatchRepository
+	apiKeyRepo         APIKeyRepository
+	accountRepo        AccountRepository
+	concurrencyService *ConcurrencyService
+	timingWheel        *TimingWheelService
+	billingHoldService *BillingHoldService
+	cfg                *config.Config
+
+	handlerMu sync.RWMutex
+	handler   http.Handler
+
+	running         int32
+	lastFileCleanup atomic.Int64
+	startOnce       sync.Once
+	stopOnce        sync.Once
+
+	workerCtx    context.Context
+	workerCancel context.CancelFunc
+}
+
+func NewBatchService(
+	repo BatchRepository,
+	apiKeyRepo APIKeyRepository,
+	accountRepo AccountRepository,
+	concurrencyService *ConcurrencyService,
+	timingWheel *TimingWheelService,
+	billingHoldService *BillingHoldService,
+	cfg *config.Config,
+) *BatchService {
+	workerCtx, workerCancel := context.WithCancel(context.Background())
+	return &BatchService{
+		repo:               repo,
+		apiKeyRepo:         apiKeyRepo,
+		accountRepo:        accountRepo,
+		concurrencyService: concurrencyService,
+		timingWheel:        timingWheel,
+		billingHoldService: billingHoldService,
+		cfg:                cfg,
+		workerCtx:          workerCtx,
+		workerCancel:       workerCancel,
+	}
+}
+
+// SetRequestHandler 注入网关路由，用于在进程内回放批处理请求行（路由构建完成后调用）。
+func (s *BatchService) SetRequestHandler(h http.Handler) {
+	if s == nil {
+		return
+	}
+	s.handlerMu.Lock()
+	s.handler = h
+	s.handlerMu.Unlock()
+}
+
+func (s *BatchService) requestHandler() http.Handler {
+	s.handlerMu.RLock()
+	defer s.handlerMu.RUnlock()
+	return s.handler
+}
+
+func (s *BatchService) Start() {
+	if s == nil {
+		return
+	}
+	if !s.enabled() {
+		logger.LegacyPrintf("service.batch", "[Batch] not started (disabled)")
+		return
+	}
+	if s.repo == nil || s.timingWheel == nil {
+		logger.LegacyPrintf("service.batch", "[Batch] not started (missing deps)")
+		return
+	}
+
+	interval := s.workerInterval()
+	s.startOnce.Do(func() {
+		s.timingWheel.ScheduleRecurring(batchWorkerName, interval, s.runOnce)
+		logger.LegacyPrintf("service.batch", "[Batch] started (interval=%s concurrency=%d discount_rate=%.4f max_group_load=%d%%)", interval, s.concurrency(), s.cfg.Batch.DiscountRate, s.cfg.Batch.MaxGroupLoadPercent)
+	})
+}
+
+func (s *BatchService) Stop() {
+	if s == nil {
+		return
+	}
+	s.stopOnce.Do(func() {
+		if s.workerCancel != nil {
+			s.workerCancel()
+		}
+		if s.timingWheel != nil {
+			s.timingWheel.Cancel(batchWorkerName)
+		}
+		logger.LegacyPrintf("service.batch", "[Batch] stopped")
+	})
+}
+
+// ==================== Files ====================
+
+// UploadFile 保存 /v1/files 上传的 JSONL 输入文件。
+func (s *BatchService) UploadFile(ctx context.Context, apiKey *APIKey, purpose, filename string, content []byte) (*BatchFile, error) {
+	if !s.enabled() {
+		return nil, ErrBatchDisabled
+	}
+	if apiKey == nil {
+		return nil, infraerrors.Unauthorized("INVALID_API_KEY", "invalid api key")
+	}
+	if strings.TrimSpace(purpose) != BatchFilePurposeInput {
+		return nil, infraerrors.BadRequest("BATCH_FILE_INVALID_PURPOSE", "purpose must be 'batch'")
+	}
+	if len(bytes.TrimSpace(content)) == 0 {
+		return nil, infraerrors.BadRequest("BATCH_FILE_EMPTY", "file is empty")
+	}
+	if maxBytes := s.maxFileBytes(); int64(len(content)) > maxBytes {
+		return nil, infraerrors.Newf(http.StatusRequestEntityTooLarge, "BATCH_FILE_TOO_LARGE", "file exceeds maximum size of %d bytes", maxBytes)
+	}
+
+	keyID := apiKey.ID
+	file := &BatchFile{
+		FileID:    "file-" + randomHex(12),
+		UserID:    apiKey.UserID,
+		APIKeyID:  &keyID,
+		Purpose:   BatchFilePurposeInput,
+		Filename:  strings.TrimSpace(filename),
+		Bytes:     int64(len(content)),
+		Content:   content,
+		ExpiresAt: s.fileExpiresAt(time.Now()),
+	}
+	if err := s.repo.CreateFile(ctx, file); err != nil {
+		return nil, fmt.Errorf("create batch file: %w", err)
+	}
+	return file, nil
+}
+
+// GetFile 查询文件元信息（不含内容）。
+func (s *BatchService) GetFile(ctx context.Context, userID int64, fileID string) (*BatchFile, error) {
+	return s.getFile(ctx, userID, fileID, false)
+}
+
+// GetFileContent 查询文件及其内容。
+func (s *BatchService) GetFileContent(ctx context.Context, userID int64, fileID string) (*BatchFile, error) {
+	return s.getFile(ctx, userID, fileID, true)
+}
+
+func (s *BatchService) getFile(ctx context.Context, userID int64, fileID string, withContent bool) (*BatchFile, error) {
+	if !s.enabled() {
+		return nil, ErrBatchDisabled
+	}
+	file, err := s.repo.GetFile(ctx, userID, strings.TrimSpace(fileID), withContent)
+	if err != nil {
+		return nil, fmt.Errorf("get batch file: %w", err)
+	}
+	if file == nil {
+		return nil, ErrBatchFileNotFound
+	}
+	return file, nil
+}
+
+// ListFiles 列出用户文件（cursor 分页）。
+func (s *BatchService) ListFiles(ctx context.Context, userID int64, purpose, after string, limit int) ([]*BatchFile, error) {
+	if !s.enabled() {
+		return nil, ErrBatchDisabled
+	}
+	return s.repo.ListFiles(ctx, userID, strings.TrimSpace(purpose), strings.TrimSpace(after), normalizeBatchListLimit(limit))
+}
+
+// DeleteFile 删除用户文件。
+func (s *BatchService) DeleteFile(ctx context.Context, userID int64, fileID string) error {
+	if !s.enabled() {
+		return ErrBatchDisabled
+	}
+	deleted, err := s.repo.DeleteFile(ctx, userID, strings.TrimSpace(fileID))
+	if err != nil {
+		return fmt.Errorf("delete batch file: %w", err)
+	}
+	if !deleted {
+		return ErrBatchFileNotFound
+	}
+	return nil
+}
+
+// ==================== Batches ====================
+
+// CreateOpenAIBatch 根据已上传的输入文件创建 /v1/batches 任务。
+func (s *BatchService) CreateOpenAIBatch(ctx context.Context, apiKey *APIKey, clientIP string, input CreateOpenAIBatchInput) (*BatchJob, error) {
+	if !s.enabled() {
+		return nil, ErrBatchDisabled
+	}
+	if apiKey == nil {
+		return nil, infraerrors.Unauthorized("INVALID_API_KEY", "invalid api key")
+	}
+	endpoint := strings.TrimSpace(input.Endpoint)
+	if _, ok := batchOpenAIEndpoints[endpoint]; !ok {
+		return nil, infraerrors.BadRequest("BATCH_INVALID_ENDPOINT", fmt.Sprintf("unsupported endpoint: %s", endpoint))
+	}
+	window := strings.TrimSpace(input.CompletionWindow)
+	if window == "" {
+		window = batchCompletionWindow
+	}
+	if window != batchCompletionWindow {
+		return nil, infraerrors.BadRequest("BATCH_INVALID_COMPLETION_WINDOW", "completion_window must be '24h'")
+	}
+
+	file, err := s.getFile(ctx, apiKey.UserID, input.InputFileID, true)
+	if err != nil {
+		return nil, err
+	}
+	if file.Purpose != BatchFilePurposeInput {
+		return nil, infraerrors.BadRequest("BATCH_FILE_INVALID_PURPOSE", "input file must have purpose 'batch'")
+	}
+	items, err := s.parseOpenAIBatchInput(file.Content, endpoint)
+	if err != nil {
+		return nil, err
+	}
+	hold, err := s.reserveBatchHold(ctx, apiKey, input.Subscription, items)
+	if err != nil {
+		return nil, err
+	}
+	defer hold.Release()
+
+	job := s.newJob(apiKey, clientIP, BatchFormatOpenAI, "batch_"+randomHex(12), endpoint, file.FileID, input.Metadata)
+	if err := s.repo.CreateJob(ctx, job, items); err != nil {
+		return nil, fmt.Errorf("create batch job: %w", err)
+	}
+	logger.LegacyPrintf("service.batch", "[Batch] job created: batch=%s format=%s user=%d api_key=%d endpoint=%s lines=%d", job.BatchID, job.Format, job.UserID, job.APIKeyID, job.Endpoint, len(items))
+	go s.runOnce()
+	return job, nil
+}
+
+// CreateAnthropicBatch 根据 /v1/messages/batches 请求体中的 requests 创建任务。
+// 请求内容同样落为一个输入文件，便于与 OpenAI 格式共用执行与审计链路。
+func (s *BatchService) CreateAnthropicBatch(ctx context.Context, apiKey *APIKey, subscription *UserSubscription, clientIP string, body []byte) (*BatchJob, error) {
+	if !s.enabled() {
+		return nil, ErrBatchDisabled
+	}
+	if apiKey == nil {
+		return nil, infraerrors.Unauthorized("INVALID_API_KEY", "invalid api key")
+	}
+	if maxBytes := s.maxFileBytes(); int64(len(body)) > maxBytes {
+		return nil, infraerrors.Newf(http.StatusRequestEntityTooLarge, "BATCH_FILE_TOO_LARGE", "batch exceeds maximum size of %d bytes", maxBytes)
+	}
+	requests := gjson.GetBytes(body, "requests")
+	if !requests.IsArray() || len(requests.Array()) == 0 {
+		return nil, infraerrors.BadRequest("BATCH_INVALID_REQUESTS", "requests: must be a non-empty array")
+	}
+
+	var content bytes.Buffer
+	for _, req := range requests.Array() {
+		content.WriteString(req.Raw)
+		content.WriteByte('\n')
+	}
+	items, err := s.parseAnthropicBatchInput(content.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	hold, err := s.reserveBatchHold(ctx, apiKey, subscription, items)
+	if err != nil {
+		return nil, err
+	}
+	defer hold.Release()
+
+	keyID := apiKey.ID
+	file := &BatchFile{
+		FileID:    "file-" + randomHex(12),
+		UserID:    apiKey.UserID,
+		APIKeyID:  &keyID,
+		Purpose:   BatchFilePurposeInput,
+		Filename:  "message_batch_requests.jsonl",
+		Bytes:     int64(content.Len()),
+		Content:   content.Bytes(),
+		ExpiresAt: s.fileExpiresAt(time.Now()),
+	}
+	if err := s.repo.CreateFile(ctx, file); err != nil {
+		return nil, fmt.Errorf("create batch file: %w", err)
+	}
+
+	job := s.newJob(apiKey, clientIP, BatchFormatAnthropic, "msgbatch_"+randomHex(12), batchAnthropicEndpoint, file.FileID, nil)
+	if err := s.repo.CreateJob(ctx, job, items); err != nil {
+		return nil, fmt.Errorf("create batch job: %w", err)
+	}
+	logger.LegacyPrintf("service.batch", "[Batch] job created: batch=%s format=%s user=%d api_key=%d endpoint=%s lines=%d", job.BatchID, job.Format, job.UserID, job.APIKeyID, job.Endpoint, len(items))
+	go s.runOnce()
+	return job, nil
+}
+
+// reserveBatchHold 提交时按各行预估最大费用合计冻结，可用额度不足以覆盖整个批次时拒绝创建。
+// 各行回放时仍经由网关各自冻结与结算，因此该冻结在任务创建完成后即释放。
+func (s *BatchService) reserveBatchHold(ctx context.Context, apiKey *APIKey, subscription *UserSubscription, items []*BatchJobItem) (*BillingHold, error) {
+	if !s.billingHoldService.Enabled() {
+		return nil, nil
+	}
+	lines := make([]BillingHoldRequest, 0, len(items))
+	for _, item := range items {
+		lines = append(lines, BillingHoldRequest{
+			Model:     strings.TrimSpace(gjson.GetBytes(item.Body, "model").String()),
+			Body:      item.Body,
+			InputOnly: item.URL == "/v1/embeddings",
+		})
+	}
+	return s.billingHoldService.Reserve(ctx, BillingHoldRequest{
+		User:         apiKey.User,
+		APIKey:       apiKey,
+		Group:        apiKey.Group,
+		Subscription: subscription,
+		Lines:        lines,
+	})
+}
+
+func (s *BatchService) newJob(apiKey *APIKey, clientIP, format, batchID, endpoint, inputFileID string, metadata map[string]string) *BatchJob {
+	now := time.Now()
+	return &BatchJob{
+		BatchID:          batchID,
+		Format:           format,
+		UserID:           apiKey.UserID,
+		APIKeyID:         apiKey.ID,
+		Endpoint:         endpoint,
+		InputFileID:      inputFileID,
+		CompletionWindow: batchCompletionWindow,
+		Metadata:         metadata,
+		ClientIP:         strings.TrimSpace(clientIP),
+		Status:           BatchStatusInProgress,
+		CreatedAt:        now,
+		ExpiresAt:        now.Add(24 * time.Hour),
+		InProgressAt:     &now,
+	}
+}
+
+// GetBatch 查询任务。
+func (s *BatchService) GetBatch(ctx context.Context, userID int64, format, batchID string) (*BatchJob, error) {
+	if !s.enabled() {
+		return nil, ErrBatchDisabled
+	}
+	job, err := s.repo.GetJob(ctx, userID, format, strings.TrimSpace(batchID))
+	if err != nil {
+		return nil, fmt.Errorf("get batch job: %w", err)
+	}
+	if job == nil {
+		return nil, ErrBatchNotFound
+	}
+	return job, nil
+}
+
+// ListBatches 列出用户任务（cursor 分页）。
+func (s *BatchService) ListBatches(ctx context.Context, userID int64, format, after string, limit int) ([]*BatchJob, error) {
+	if !s.enabled() {
+		return nil, ErrBatchDisabled
+	}
+	return s.repo.ListJobs(ctx, userID, format, strings.TrimSpace(after), normalizeBatchListLimit(limit))
+}
+
+// CancelBatch 请求取消任务：已在执行中的行会跑完，其余行由执行器标记为 cancelled。
+func (s *BatchService) CancelBatch(ctx context.Context, userID int64, format, batchID string) (*BatchJob, error) {
+	job, err := s.GetBatch(ctx, userID, format, batchID)
+	if err != nil {
+		return nil, err
+	}
+	if job.IsTerminal() || job.Status == BatchStatusCancelling {
+		return job, nil
+	}
+	if _, err := s.repo.UpdateJobStatus(ctx, job.ID, []string{BatchStatusInProgress}, BatchStatusCancelling); err != nil {
+		return nil, fmt.Errorf("cancel batch job: %w", err)
+	}
+	logger.LegacyPrintf("service.batch", "[Batch] cancel requested: batch=%s user=%d", job.BatchID, userID)
+	go s.runOnce()
+	return s.GetBatch(ctx, userID, format, batchID)
+}
+
+// GetResults 返回已结束任务的结果文件（Anthropic results 接口）。
+func (s *BatchService) GetResults(ctx context.Context, userID int64, format, batchID string) (*BatchFile, error) {
+	job, err := s.GetBatch(ctx, userID, format, batchID)
+	if err != nil {
+		return nil, err
+	}
+	if !job.IsTerminal() || job.OutputFileID == "" {
+		return nil, ErrBatchNotEnded
+	}
+	return s.getFile(ctx, userID, job.OutputFileID, true)
+}
+
+// ==================== Input parsing ====================
+
+func (s *BatchService) parseOpenAIBatchInput(content []byte, endpoint string) ([]*BatchJobItem, error) {
+	return s.parseBatchLines(content, func(lineNo int, line []byte) (*BatchJobItem, error) {
+		if method := strings.ToUpper(strings.TrimSpace(gjson.GetBytes(line, "method").String())); method != http.MethodPost {
+			return nil, fmt.Errorf("line %d: method must be POST", lineNo)
+		}
+		if url := strings.TrimSpace(gjson.GetBytes(line, "url").String()); url != endpoint {
+			return nil, fmt.Errorf("line %d: url %q does not match batch endpoint %q", lineNo, url, endpoint)
+		}
+		body := gjson.GetBytes(line, "body")
+		if !body.IsObject() {
+			return nil, fmt.Errorf("line %d: body must be a JSON object", lineNo)
+		}
+		return &BatchJobItem{URL: endpoint, Body: []byte(body.Raw)}, nil
+	})
+}
+
+func (s *BatchService) parseAnthropicBatchInput(content []byte) ([]*BatchJobItem, error) {
+	return s.parseBatchLines(content, func(lineNo int, line []byte) (*BatchJobItem, error) {
+		params := gjson.GetBytes(line, "params")
+		if !params.IsObject() {
+			return nil, fmt.Errorf("requests.%d.params: must be an object", lineNo-1)
+		}
+		return &BatchJobItem{URL: batchAnthropicEndpoint, Body: []byte(params.Raw)}, nil
+	})
+}
+
+// parseBatchLines 逐行解析 JSONL，校验 custom_id 唯一并强制关闭流式输出。
+func (s *BatchService) parseBatchLines(content []byte, parse func(lineNo int, line []byte) (*BatchJobItem, error)) ([]*BatchJobItem, error) {
+	maxLines := s.maxRequestsPerBatch()
+	seen := make(map[string]struct{})
+	items := make([]*BatchJobItem, 0)
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	scanner.Buffer(make([]byte, 0, 64*1024), len(content)+1)
+	lineNo := 0
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		lineNo++
+		if len(items) >= maxLines {
+			return nil, infraerrors.BadRequest("BATCH_TOO_MANY_REQUESTS", fmt.Sprintf("batch exceeds maximum of %d requests", maxLines))
+		}
+		if !gjson.ValidBytes(line) {
+			return nil, infraerrors.BadRequest("BATCH_INVALID_LINE", fmt.Sprintf("line %d: invalid JSON", lineNo))
+		}
+		customID := strings.TrimSpace(gjson.GetBytes(line, "custom_id").String())
+		if customID == "" || len(customID) > batchMaxCustomIDLength {
+			return nil, infraerrors.BadRequest("BATCH_INVALID_CUSTOM_ID", fmt.Sprintf("line %d: custom_id is required and must be at most %d characters", lineNo, batchMaxCustomIDLength))
+		}
+		if _, dup := seen[customID]; dup {
+			return nil, infraerrors.BadRequest("BATCH_DUPLICATE_CUSTOM_ID", fmt.Sprintf("line %d: duplicate custom_id %q", lineNo, customID))
+		}
+		seen[customID] = struct{}{}
+
+		item, err := parse(lineNo, line)
+		if err != nil {
+			return nil, infraerrors.BadRequest("BATCH_INVALID_LINE", err.Error())
+		}
+		if strings.TrimSpace(gjson.GetBytes(item.Body, "model").String()) == "" {
+			return nil, infraerrors.BadRequest("BATCH_INVALID_LINE", fmt.Sprintf("line %d: body.model is required", lineNo))
+		}
+		// 批处理结果按完整响应落盘，不支持流式
+		if gjson.GetBytes(item.Body, "stream").Exists() {
+			if stripped, err := sjson.DeleteBytes(item.Body, "stream"); err == nil {
+				item.Body = stripped
+			}
+		}
+		item.LineNo = lineNo
+		item.CustomID = customID
+		item.Status = BatchItemStatusPending
+		items = append(items, item)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, infraerrors.BadRequest("BATCH_INVALID_FILE", fmt.Sprintf("failed to read input: %v", err))
+	}
+	if len(items) == 0 {
+		return nil, infraerrors.BadRequest("BATCH_EMPTY", "batch contains no requests")
+	}
+	return items, nil
+}
+
+// ==================== Runner ====================
+
+func (s *BatchService) runOnce() {
+	svc := s
+	if svc == nil || svc.repo == nil || !svc.enabled() {
+		return
+	}
+	if !atomic.CompareAndSwapInt32(&svc.running, 0, 1) {
+		return
+	}
+	defer atomic.StoreInt32(&svc.running, 0)
+
+	parent := context.Background()
+	if svc.workerCtx != nil {
+		parent = svc.workerCtx
+	}
+	svc.cleanupExpiredFiles(parent)
+
+	// 逐个领取任务：租约按 updated_at 轮转，多个任务之间公平推进
+	lease := svc.itemTimeout() + time.Minute
+	seen := make(map[int64]struct{})
+	for i := 0; i < batchJobsPerRound; i++ {
+		if parent.Err() != nil {
+			return
+		}
+		jobs, err := svc.repo.ClaimActiveJobs(parent, lease, 1)
+		if err != nil {
+			logger.LegacyPrintf("service.batch", "[Batch] claim active jobs failed: %v", err)
+			return
+		}
+		if len(jobs) == 0 {
+			slog.Debug("[Batch] run_once done: no_job=true")
+			return
+		}
+		job := jobs[0]
+		if _, dup := seen[job.ID]; dup {
+			_ = svc.repo.ReleaseJob(context.Background(), job.ID)
+			return
+		}
+		seen[job.ID] = struct{}{}
+
+		ctx, cancel := context.WithTimeout(parent, lease)
+		svc.processJob(ctx, job)
+		cancel()
+	}
+}
+
+func (s *BatchService) processJob(ctx context.Context, job *BatchJob) {
+	released := false
+	defer func() {
+		if !released {
+			if err := s.repo.ReleaseJob(context.Background(), job.ID); err != nil {
+				logger.LegacyPrintf("service.batch", "[Batch] release job failed: batch=%s err=%v", job.BatchID, err)
+			}
+		}
+	}()
+
+	if job.Status == BatchStatusCancelling {
+		if _, err := s.repo.CloseRemainingItems(ctx, job.ID, BatchItemStatusCancelled, "batch cancelled"); err != nil {
+			logger.LegacyPrintf("service.batch", "[Batch] cancel items failed: batch=%s err=%v", job.BatchID, err)
+			return
+		}
+		released = s.finalizeJob(ctx, job, BatchStatusCancelled)
+		return
+	}
+	if time.Now().After(job.ExpiresAt) {
+		if _, err := s.repo.CloseRemainingItems(ctx, job.ID, BatchItemStatusExpired, "batch expired before the request could be processed"); err != nil {
+			logger.LegacyPrintf("service.batch", "[Batch] expire items failed: batch=%s err=%v", job.BatchID, err)
+			return
+		}
+		released = s.finalizeJob(ctx, job, BatchStatusExpired)
+		return
+	}
+	if job.Status == BatchStatusFinalizing {
+		released = s.finalizeJob(ctx, job, BatchStatusCompleted)
+		return
+	}
+
+	apiKey, err := s.apiKeyRepo.GetByID(ctx, job.APIKeyID)
+	if err != nil {
+		if errors.Is(err, ErrAPIKeyNotFound) {
+			if _, err := s.repo.CloseRemainingItems(ctx, job.ID, BatchItemStatusFailed, "api key not found"); err != nil {
+				logger.LegacyPrintf("service.batch", "[Batch] fail items failed: batch=%s err=%v", job.BatchID, err)
+				return
+			}
+			released = s.finalizeJob(ctx, job, BatchStatusCompleted)
+			return
+		}
+		logger.LegacyPrintf("service.batch", "[Batch] load api key failed: batch=%s api_key=%d err=%v", job.BatchID, job.APIKeyID, err)
+		return
+	}
+
+	if s.hasSpareCapacity(ctx, apiKey) {
+		items, err := s.repo.ClaimPendingItems(ctx, job.ID, time.Now().Add(-s.itemTimeout()), s.concurrency())
+		if err != nil {
+			logger.LegacyPrintf("service.batch", "[Batch] claim items failed: batch=%s err=%v", job.BatchID, err)
+			return
+		}
+		s.dispatchItems(ctx, job, apiKey.Key, items)
+	}
+
+	counts, err := s.repo.CountItems(ctx, job.ID)
+	if err != nil {
+		logger.LegacyPrintf("service.batch", "[Batch] count items failed: batch=%s err=%v", job.BatchID, err)
+		return
+	}
+	if counts.Processing() == 0 {
+		released = s.finalizeJob(ctx, job, BatchStatusCompleted)
+		return
+	}
+	if err := s.repo.UpdateJobCounts(ctx, job.ID, counts); err != nil {
+		logger.LegacyPrintf("service.batch", "[Batch] update counts failed: batch=%s err=%v", job.BatchID, err)
+	}
+}
+
+// hasSpareCapacity 仅在用户与分组账号的负载低于阈值时回放新行，避免批处理挤占实时流量。
+func (s *BatchService) hasSpareCapacity(ctx context.Context, apiKey *APIKey) bool {
+	threshold := 0
+	if s.cfg != nil {
+		threshold = s.cfg.Batch.MaxGroupLoadPercent
+	}
+	if threshold <= 0 || s.concurrencyService == nil {
+		return true
+	}
+
+	if apiKey.User != nil && apiKey.User.Concurrency > 0 {
+		loads, err := s.concurrencyService.GetUsersLoadBatch(ctx, []UserWithConcurrency{{ID: apiKey.User.ID, MaxConcurrency: apiKey.User.Concurrency}})
+		if err == nil {
+			if load := loads[apiKey.User.ID]; load != nil && load.LoadRate >= threshold {
+				return false
+			}
+		}
+	}
+
+	if apiKey.GroupID == nil || s.accountRepo == nil {
+		return true
+	}
+	accounts, err := s.accountRepo.ListSchedulableByGroupID(ctx, *apiKey.GroupID)
+	if err != nil || len(accounts) == 0 {
+		// 无可用账号时交给网关返回明确错误，避免任务永久停滞
+		return true
+	}
+	batch := make([]AccountWithConcurrency, 0, len(accounts))
+	capacity := 0
+	for i := range accounts {
+		if accounts[i].Concurrency <= 0 {
+			continue
+		}
+		batch = append(batch, AccountWithConcurrency{ID: accounts[i].ID, MaxConcurrency: accounts[i].Concurrency})
+		capacity += accounts[i].Concurrency
+	}
+	if capacity == 0 {
+		return true
+	}
+	loads, err := s.concurrencyService.GetAccountsLoadBatch(ctx, batch)
+	if err != nil {
+		return true
+	}
+	used := 0
+	for _, load := range loads {
+		if load != nil {
+			used += load.CurrentConcurrency + load.WaitingCount
+		}
+	}
+	return used*100 < capacity*threshold
+}
+
+func (s *BatchService) dispatchItems(ctx context.Context, job *BatchJob, rawKey string, items []*BatchJobItem) {
+	if len(items) == 0 {
+		return
+	}
+	var wg sync.WaitGroup
+	for _, item := range items {
+		wg.Add(1)
+		go func(item *BatchJobItem) {
+			defer wg.Done()
+			s.executeItem(ctx, job, rawKey, item)
+			if err := s.repo.CompleteItem(context.Background(), item); err != nil {
+				logger.LegacyPrintf("service.batch", "[Batch] save item result failed: batch=%s line=%d err=%v", job.BatchID, item.LineNo, err)
+			}
+		}(item)
+	}
+	wg.Wait()
+}
+
+// executeItem 在进程内将一行请求交给网关路由执行，并把响应写回 item。
+func (s *BatchService) executeItem(ctx context.Context, job *BatchJob, rawKey string, item *BatchJobItem) {
+	now := time.Now()
+	item.FinishedAt = &now
+	handler := s.requestHandler()
+	if handler == nil {
+		item.Status = BatchItemStatusFailed
+		item.ErrorMessage = "batch runner is not ready"
+		return
+	}
+
+	reqCtx, cancel := context.WithTimeout(context.WithValue(ctx, ctxkey.BatchJobID, job.ID), s.itemTimeout())
+	defer cancel()
+	req, err := http.NewRequestWithContext(reqCtx, http.MethodPost, item.URL, bytes.NewReader(item.Body))
+	if err != nil {
+		item.Status = BatchItemStatusFailed
+		item.ErrorMessage = err.Error()
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+rawKey)
+	req.Header.Set("User-Agent", batchUserAgent)
+	if job.Format == BatchFormatAnthropic || item.URL == batchAnthropicEndpoint {
+		req.Header.Set("anthropic-version", "2023-06-01")
+	}
+	if job.ClientIP != "" {
+		req.RemoteAddr = net.JoinHostPort(job.ClientIP, "0")
+	}
+
+	w := newBatchResponseWriter()
+	handler.ServeHTTP(w, req)
+
+	finished := time.Now()
+	item.FinishedAt = &finished
+	item.ResponseStatus = w.statusCode()
+	item.ResponseBody = w.body.Bytes()
+	item.RequestID = firstNonEmptyHeader(w.header, "x-request-id", "request-id")
+	if item.ResponseStatus >= 200 && item.ResponseStatus < 300 {
+		item.Status = BatchItemStatusSucceeded
+		return
+	}
+	item.Status = BatchItemStatusFailed
+	item.ErrorMessage = strings.TrimSpace(gjson.GetBytes(item.ResponseBody, "error.message").String())
+	if item.ErrorMessage == "" {
+		item.ErrorMessage = http.StatusText(item.ResponseStatus)
+	}
+}
+
+func firstNonEmptyHeader(h http.Header, keys ...string) string {
+	for _, key := range keys {
+		if v := strings.TrimSpace(h.Get(key)); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// finalizeJob 生成结果文件并写入最终状态；返回 true 表示租约已随 FinalizeJob 释放。
+func (s *BatchService) finalizeJob(ctx context.Context, job *BatchJob, finalStatus string) bool {
+	if finalStatus == BatchStatusCompleted && job.Status == BatchStatusInProgress {
+		if _, err := s.repo.UpdateJobStatus(ctx, job.ID, []string{BatchStatusInProgress}, BatchStatusFinalizing); err != nil {
+			logger.LegacyPrintf("service.batch", "[Batch] mark finalizing failed: batch=%s err=%v", job.BatchID, err)
+			return false
+		}
+	}
+
+	items, err := s.repo.ListItems(ctx, job.ID)
+	if err != nil {
+		logger.LegacyPrintf("service.batch", "[Batch] list items failed: batch=%s err=%v", job.BatchID, err)
+		return false
+	}
+	var output, errorsOut []byte
+	if job.Format == BatchFormatAnthropic {
+		output = buildAnthropicBatchResults(items)
+	} else {
+		output, errorsOut = buildOpenAIBatchResults(items)
+	}
+
+	now := time.Now()
+	if len(output) > 0 || job.Format == BatchFormatAnthropic {
+		file, err := s.createOutputFile(ctx, job, job.BatchID+"_output.jsonl", output, now)
+		if err != nil {
+			logger.LegacyPrintf("service.batch", "[Batch] create output file failed: batch=%s err=%v", job.BatchID, err)
+			return false
+		}
+		job.OutputFileID = file.FileID
+	}
+	if len(errorsOut) > 0 {
+		file, err := s.createOutputFile(ctx, job, job.BatchID+"_error.jsonl", errorsOut, now)
+		if err != nil {
+			logger.LegacyPrintf("service.batch", "[Batch] create error file failed: batch=%s err=%v", job.BatchID, err)
+			return false
+		}
+		job.ErrorFileID = file.FileID
+	}
+
+	job.Counts = countBatchItems(items)
+	job.Status = finalStatus
+	if err := s.repo.FinalizeJob(ctx, job); err != nil {
+		logger.LegacyPrintf("service.batch", "[Batch] finalize job failed: batch=%s err=%v", job.BatchID, err)
+		return false
+	}
+	logger.LegacyPrintf("service.batch", "[Batch] job finished: batch=%s status=%s total=%d succeeded=%d failed=%d cancelled=%d expired=%d",
+		job.BatchID, job.Status, job.Counts.Total, job.Counts.Succeeded, job.Counts.Failed, job.Counts.Cancelled, job.Counts.Expired)
+	return true
+}
+
+func (s *BatchService) createOutputFile(ctx context.Context, job *BatchJob, filename string, content []byte, now time.Time) (*BatchFile, error) {
+	keyID := job.APIKeyID
+	file := &BatchFile{
+		FileID:    "file-" + randomHex(12),
+		UserID:    job.UserID,
+		APIKeyID:  &keyID,
+		Purpose:   BatchFilePurposeOutput,
+		Filename:  filename,
+		Bytes:     int64(len(content)),
+		Content:   content,
+		ExpiresAt: s.fileExpiresAt(now),
+	}
+	if file.Content == nil {
+		file.Content = []byte{}
+	}
+	if err := s.repo.CreateFile(ctx, file); err != nil {
+		return nil, err
+	}
+	return file, nil
+}
+
+func (s *BatchService) cleanupExpiredFiles(ctx context.Context) {
+	now := time.Now()
+	last := s.lastFileCleanup.Load()
+	if last > 0 && now.Sub(time.Unix(last, 0)) < batchFileCleanupEvery {
+		return
+	}
+	if !s.lastFileCleanup.CompareAndSwap(last, now.Unix()) {
+		return
+	}
+	deleted, err := s.repo.DeleteExpiredFiles(ctx, now)
+	if err != nil {
+		logger.LegacyPrintf("service.batch", "[Batch] cleanup expired files failed: %v", err)
+		return
+	}
+	if deleted > 0 {
+		logger.LegacyPrintf("service.batch", "[Batch] cleanup expired files: deleted=%d", deleted)
+	}
+}
+
+func countBatchItems(items []*BatchJobItem) BatchRequestCounts {
+	counts := BatchRequestCounts{Total: len(items)}
+	for _, item := range items {
+		switch item.Status {
+		case BatchItemStatusSucceeded:
+			counts.Succeeded++
+		case BatchItemStatusFailed:
+			counts.Failed++
+		case BatchItemStatusCancelled:
+			counts.Cancelled++
+		case BatchItemStatusExpired:
+			counts.Expired++
+		}
+	}
+	return counts
+}
+
+// batchResponseBody 优先按 JSON 原样嵌入响应体，非 JSON 时退化为字符串。
+func batchResponseBody(body []byte) json.RawMessage {
+	trimmed := bytes.TrimSpace(body)
+	if len(trimmed) > 0 && gjson.ValidBytes(trimmed) {
+		return json.RawMessage(trimmed)
+	}
+	encoded, _ := json.Marshal(string(body))
+	return encoded
+}
+
+// buildOpenAIBatchResults 按 OpenAI Batch 输出格式生成结果文件与错误文件内容。
+func buildOpenAIBatchResults(items []*BatchJobItem) (output []byte, errorsOut []byte) {
+	var out, errOut bytes.Buffer
+	for _, item := range items {
+		line := map[string]any{
+			"id":        batchResultLineIDPrefix + randomHex(12),
+			"custom_id": item.CustomID,
+			"response":  nil,
+			"error":     nil,
+		}
+		switch {
+		case item.Status == BatchItemStatusSucceeded:
+			line["response"] = map[string]any{
+				"status_code": item.ResponseStatus,
+				"request_id":  item.RequestID,
+				"body":        batchResponseBody(item.ResponseBody),
+			}
+			writeJSONLine(&out, line)
+			continue
+		case item.Status == BatchItemStatusFailed && item.ResponseStatus > 0:
+			line["response"] = map[string]any{
+				"status_code": item.ResponseStatus,
+				"request_id":  item.RequestID,
+				"body":        batchResponseBody(item.ResponseBody),
+			}
+		case item.Status == BatchItemStatusCancelled:
+			line["error"] = map[string]any{"code": "batch_cancelled", "message": item.ErrorMessage}
+		case item.Status == BatchItemStatusExpired:
+			line["error"] = map[string]any{"code": "batch_expired", "message": item.ErrorMessage}
+		default:
+			line["error"] = map[string]any{"code": batchInternalErrorCode, "message": item.ErrorMessage}
+		}
+		writeJSONLine(&errOut, line)
+	}
+	return out.Bytes(), errOut.Bytes()
+}
+
+// buildAnthropicBatchResults 按 Anthropic Message Batches results 格式生成结果内容。
+func buildAnthropicBatchResults(items []*BatchJobItem) []byte {
+	var out bytes.Buffer
+	for _, item := range items {
+		var result map[string]any
+		switch item.Status {
+		case BatchItemStatusSucceeded:
+			result = map[string]any{"type": "succeeded", "message": batchResponseBody(item.ResponseBody)}
+		case BatchItemStatusCancelled:
+			result = map[string]any{"type": "canceled"}
+		case BatchItemStatusExpired:
+			result = map[string]any{"type": "expired"}
+		default:
+			var errBody any
+			if gjson.GetBytes(item.ResponseBody, "type").String() == "error" {
+				errBody = batchResponseBody(item.ResponseBody)
+			} else {
+				message := item.ErrorMessage
+				if message == "" {
+					message = "request failed"
+				}
+				errBody = map[string]any{
+					"type":  "error",
+					"error": map[string]any{"type": "api_error", "message": message},
+				}
+			}
+			result = map[string]any{"type": "errored", "error": errBody}
+		}
+		writeJSONLine(&out, map[string]any{"custom_id": item.CustomID, "result": result})
+	}
+	return out.Bytes()
+}
+
+func writeJSONLine(buf *bytes.Buffer, v any) {
+	encoded, err := json.Marshal(v)
+	if err != nil {
+		return
+	}
+	buf.Write(encoded)
+	buf.WriteByte('\n')
+}
+
+// batchResponseWriter 收集进程内回放请求的响应（实现 http.Flusher 以兼容流式分支）。
+type batchResponseWriter struct {
+	header http.Header
+	body   bytes.Buffer
+	status int
+}
+
+func newBatchResponseWriter() *batchResponseWriter {
+	return &batchResponseWriter{header: make(http.Header)}
+}
+
+func (w *batchResponseWriter) Header() http.Header { return w.header }
+
+func (w *batchResponseWriter) Write(p []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return w.body.Write(p)
+}
+
+func (w *batchResponseWriter) WriteHeader(statusCode int) {
+	if w.status == 0 {
+		w.status = statusCode
+	}
+}
+
+func (w *batchResponseWriter) Flush() {}
+
+func (w *batchResponseWriter) statusCode() int {
+	if w.status == 0 {
+		return http.StatusOK
+	}
+	return w.status
+}
+
+// ==================== Config helpers ====================
+
+func (s *BatchService) enabled() bool {
+	return s != nil && s.cfg != nil && s.cfg.Batch.Enabled
+}
+
+func (s *BatchService) workerInterval() time.Duration {
+	if s.cfg != nil && s.cfg.Batch.WorkerIntervalSeconds > 0 {
+		return time.Duration(s.cfg.Batch.WorkerIntervalSeconds) * time.Second
+	}
+	return 5 * time.Second
+}
+
+func (s *BatchService) concurrency() int {
+	if s.cfg != nil && s.cfg.Batch.Concurrency > 0 {
+		return s.cfg.Batch.Concurrency
+	}
+	return 4
+}
+
+func (s *BatchService) itemTimeout() time.Duration {
+	if s.cfg != nil && s.cfg.Batch.ItemTimeoutSeconds > 0 {
+		return time.Duration(s.cfg.Batch.ItemTimeoutSeconds) * time.Second
+	}
+	return 10 * time.Minute
+}
+
+func (s *BatchService) maxFileBytes() int64 {
+	if s.cfg != nil && s.cfg.Batch.MaxFileSizeMB > 0 {
+		return int64(s.cfg.Batch.MaxFileSizeMB) << 20
+	}
+	return 100 << 20
+}
+
+func (s *BatchService) maxRequestsPerBatch() int {
+	if s.cfg != nil && s.cfg.Batch.MaxRequestsPerBatch > 0 {
+		return s.cfg.Batch.MaxRequestsPerBatch
+	}
+	return 50000
+}
+
+func (s *BatchService) fileExpiresAt(now time.Time) *time.Time {
+	if s.cfg == nil || s.cfg.Batch.FileRetentionDays <= 0 {
+		return nil
+	}
+	expiresAt := now.AddDate(0, 0, s.cfg.Batch.FileRetentionDays)
+	return &expiresAt
+}
+
+func normalizeBatchListLimit(limit int) int {
+	if limit <= 0 {
+		return 20
+	}
+	if limit > 100 {
+		return 100
+	}
+	return limit
+}
@@ -0,0 +1,189 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/Wei-Shaw/sub2api/internal/config"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/ctxkey"
+	infraerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+)
+
+func newBatchTestService() *BatchService {
+	cfg := &config.Config{}
+	cfg.Batch.Enabled = true
+	cfg.Batch.MaxRequestsPerBatch = 3
+	cfg.Batch.DiscountRate = 0.5
+	return NewBatchService(nil, nil, nil, nil, nil, nil, cfg)
+}
+
+func TestBatchParseOpenAIInput(t *testing.T) {
+	svc := newBatchTestService()
+	content := []byte(`{"custom_id":"a","method":"POST","url":"/v1/chat/completions","body":{"model":"gpt-4o","stream":true,"messages":[]}}
+
+{"custom_id":"b","method":"post","url":"/v1/chat/completions","body":{"model":"gpt-4o","messages":[]}}
+`)
+	items, err := svc.parseOpenAIBatchInput(content, "/v1/chat/completions")
+	require.NoError(t, err)
+	require.Len(t, items, 2)
+	require.Equal(t, "a", items[0].CustomID)
+	require.Equal(t, 1, items[0].LineNo)
+	require.Equal(t, 2, items[1].LineNo)
+	require.False(t, gjson.GetBytes(items[0].Body, "stream").Exists())
+	require.Equal(t, BatchItemStatusPending, items[1].Status)
+}
+
+func TestBatchParseOpenAIInput_Rejects(t *testing.T) {
+	svc := newBatchTestService()
+	cases := map[string]string{
+		"duplicate custom_id": `{"custom_id":"a","method":"POST","url":"/v1/embeddings","body":{"model":"m"}}
+{"custom_id":"a","method":"POST","url":"/v1/embeddings","body":{"model":"m"}}`,
+		"url mismatch":    `{"custom_id":"a","method":"POST","url":"/v1/chat/completions","body":{"model":"m"}}`,
+		"missing model":   `{"custom_id":"a","method":"POST","url":"/v1/embeddings","body":{}}`,
+		"invalid json":    `{"custom_id":`,
+		"too many lines":  strings.Repeat(`{"custom_id":"x","method":"POST","url":"/v1/embeddings","body":{"model":"m"}}`+"\n", 4),
+		"empty":           "\n\n",
+		"missing customs": `{"method":"POST","url":"/v1/embeddings","body":{"model":"m"}}`,
+	}
+	for name, content := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := svc.parseOpenAIBatchInput([]byte(content), "/v1/embeddings")
+			require.Error(t, err)
+			require.True(t, infraerrors.IsBadRequest(err))
+		})
+	}
+}
+
+func TestBatchParseAnthropicInput(t *testing.T) {
+	svc := newBatchTestService()
+	items, err := svc.parseAnthropicBatchInput([]byte(`{"custom_id":"r1","params":{"model":"claude-sonnet-4-5","max_tokens":16,"stream":true,"messages":[]}}`))
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	require.Equal(t, "/v1/messages", items[0].URL)
+	require.False(t, gjson.GetBytes(items[0].Body, "stream").Exists())
+
+	_, err = svc.parseAnthropicBatchInput([]byte(`{"custom_id":"r1"}`))
+	require.Error(t, err)
+}
+
+func TestBatchExecuteItem_ReplaysThroughHandler(t *testing.T) {
+	svc := newBatchTestService()
+	var gotJobID int64
+	var gotAuth, gotPath, gotBody, gotRemote string
+	svc.SetRequestHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotJobID, _ = r.Context().Value(ctxkey.BatchJobID).(int64)
+		gotAuth = r.Header.Get("Authorization")
+		gotPath = r.URL.Path
+		gotRemote = r.RemoteAddr
+		body, _ := io.ReadAll(r.Body)
+		gotBody = string(body)
+		w.Header().Set("x-request-id", "req_1")
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"chatcmpl-1"}`))
+	}))
+
+	job := &BatchJob{ID: 9, Format: BatchFormatOpenAI, ClientIP: "10.0.0.8"}
+	item := &BatchJobItem{ID: 1, CustomID: "a", URL: "/v1/chat/completions", Body: []byte(`{"model":"gpt-4o"}`)}
+	svc.executeItem(context.Background(), job, "sk-user", item)
+
+	require.Equal(t, int64(9), gotJobID)
+	require.Equal(t, "Bearer sk-user", gotAuth)
+	require.Equal(t, "/v1/chat/completions", gotPath)
+	require.Equal(t, `{"model":"gpt-4o"}`, gotBody)
+	require.Equal(t, "10.0.0.8:0", gotRemote)
+	require.Equal(t, BatchItemStatusSucceeded, item.Status)
+	require.Equal(t, http.StatusOK, item.ResponseStatus)
+	require.Equal(t, "req_1", item.RequestID)
+	require.JSONEq(t, `{"id":"chatcmpl-1"}`, string(item.ResponseBody))
+}
+
+func TestBatchExecuteItem_ErrorResponse(t *testing.T) {
+	svc := newBatchTestService()
+	svc.SetRequestHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+		_, _ = w.Write([]byte(`{"error":{"type":"rate_limit_error","message":"slow down"}}`))
+	}))
+
+	item := &BatchJobItem{ID: 1, CustomID: "a", URL: "/v1/messages", Body: []byte(`{}`)}
+	svc.executeItem(context.Background(), &BatchJob{ID: 1, Format: BatchFormatAnthropic}, "sk", item)
+	require.Equal(t, BatchItemStatusFailed, item.Status)
+	require.Equal(t, http.StatusTooManyRequests, item.ResponseStatus)
+	require.Equal(t, "slow down", item.ErrorMessage)
+}
+
+func TestBuildOpenAIBatchResults_SplitsOutputAndErrors(t *testing.T) {
+	items := []*BatchJobItem{
+		{CustomID: "ok", Status: BatchItemStatusSucceeded, ResponseStatus: 200, ResponseBody: []byte(`{"id":"x"}`), RequestID: "req_1"},
+		{CustomID: "bad", Status: BatchItemStatusFailed, ResponseStatus: 400, ResponseBody: []byte(`{"error":{"message":"nope"}}`)},
+		{CustomID: "gone", Status: BatchItemStatusCancelled, ErrorMessage: "batch cancelled"},
+	}
+	output, errorsOut := buildOpenAIBatchResults(items)
+
+	outLines := strings.Split(strings.TrimSpace(string(output)), "\n")
+	require.Len(t, outLines, 1)
+	require.Equal(t, "ok", gjson.Get(outLines[0], "custom_id").String())
+	require.Equal(t, int64(200), gjson.Get(outLines[0], "response.status_code").Int())
+	require.Equal(t, "x", gjson.Get(outLines[0], "response.body.id").String())
+	require.True(t, strings.HasPrefix(gjson.Get(outLines[0], "id").String(), "batch_req_"))
+
+	errLines := strings.Split(strings.TrimSpace(string(errorsOut)), "\n")
+	require.Len(t, errLines, 2)
+	require.Equal(t, int64(400), gjson.Get(errLines[0], "response.status_code").Int())
+	require.Equal(t, "batch_cancelled", gjson.Get(errLines[1], "error.code").String())
+}
+
+func TestBuildAnthropicBatchResults(t *testing.T) {
+	items := []*BatchJobItem{
+		{CustomID: "ok", Status: BatchItemStatusSucceeded, ResponseBody: []byte(`{"id":"msg_1","type":"message"}`)},
+		{CustomID: "bad", Status: BatchItemStatusFailed, ResponseBody: []byte(`{"type":"error","error":{"type":"invalid_request_error","message":"x"}}`)},
+		{CustomID: "raw", Status: BatchItemStatusFailed, ErrorMessage: "api key not found"},
+		{CustomID: "late", Status: BatchItemStatusExpired},
+	}
+	lines := strings.Split(strings.TrimSpace(string(buildAnthropicBatchResults(items))), "\n")
+	require.Len(t, lines, 4)
+	require.Equal(t, "succeeded", gjson.Get(lines[0], "result.type").String())
+	require.Equal(t, "msg_1", gjson.Get(lines[0], "result.message.id").String())
+	require.Equal(t, "errored", gjson.Get(lines[1], "result.type").String())
+	require.Equal(t, "invalid_request_error", gjson.Get(lines[1], "result.error.error.type").String())
+	require.Equal(t, "api key not found", gjson.Get(lines[2], "result.error.error.message").String())
+	require.Equal(t, "expired", gjson.Get(lines[3], "result.type").String())
+}
+
+func TestBatchResponseWriter_DefaultsAndFirstStatusWins(t *testing.T) {
+	w := newBatchResponseWriter()
+	require.Equal(t, http.StatusOK, w.statusCode())
+	w.WriteHeader(http.StatusBadGateway)
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte("x"))
+	w.Flush()
+	require.Equal(t, http.StatusBadGateway, w.statusCode())
+	require.True(t, bytes.Equal([]byte("x"), w.body.Bytes()))
+}
+
+func TestApplyBatchDiscount(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Batch.DiscountRate = 0.5
+	svc := &BillingService{cfg: cfg}
+
+	require.InDelta(t, 1.2, svc.ApplyBatchDiscount(1.2, &APIKey{}), 1e-12)
+	require.InDelta(t, 0.6, svc.ApplyBatchDiscount(1.2, &APIKey{BatchJobID: 3}), 1e-12)
+	require.InDelta(t, 1.2, svc.ApplyBatchDiscount(1.2, nil), 1e-12)
+
+	cfg.Batch.DiscountRate = 0
+	require.InDelta(t, 1.2, svc.ApplyBatchDiscount(1.2, &APIKey{BatchJobID: 3}), 1e-12)
+
+	var nilSvc *BillingService
+	require.InDelta(t, 1.2, nilSvc.ApplyBatchDiscount(1.2, &APIKey{BatchJobID: 3}), 1e-12)
+}
+
+func TestBatchRequestCounts_Processing(t *testing.T) {
+	counts := BatchRequestCounts{Total: 10, Succeeded: 4, Failed: 1, Cancelled: 2}
+	require.Equal(t, 3, counts.Processing())
+	require.Equal(t, 0, BatchRequestCounts{Total: 1, Succeeded: 2}.Processing())
+}
@@ -2,14 +2,16 @@ package service
 
 import (
 	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
 )
 
 // SubscriptionCacheData represents cached subscription data
 type SubscriptionCacheData struct {
 	Status       string
 	ExpiresAt    time.Time
-	DailyUsage   float64
-	WeeklyUsage  float64
-	MonthlyUsage float64
+	DailyUsage   money.Amount
+	WeeklyUsage  money.Amount
+	MonthlyUsage money.Amount
 	Version      int64
 }
@@ -11,6 +11,11 @@ import (
 	"github.com/Wei-Shaw/sub2api/internal/config"
 	infraerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
 	"github.com/Wei-Shaw/sub2api/internal/pkg/logger"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/timezone"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/sync/singleflight"
 )
 
@@ -26,9 +31,9 @@ var (
 type subscriptionCacheData struct {
 	Status       string
 	ExpiresAt    time.Time
-	DailyUsage   float64
-	WeeklyUsage  float64
-	MonthlyUsage float64
+	DailyUsage   money.Amount
+	WeeklyUsage  money.Amount
+	MonthlyUsage money.Amount
 	Version      int64
 }
 
@@ -41,6 +46,7 @@ const (
 	cacheWriteUpdateSubscriptionUsage
 	cacheWriteDeductBalance
 	cacheWriteUpdateRateLimitUsage
+	cacheWriteAddOrgMemberSpend
 )
 
 // 异步缓存写入工作池配置
@@ -70,8 +76,10 @@ type cacheWriteTask struct {
 	userID           int64
 	groupID          int64
 	apiKeyID         int64
-	balance          float64
-	amount           float64
+	memberID         int64
+	month            string
+	balance          money.Amount
+	amount           money.Amount
 	subscriptionData *subscriptionCacheData
 }
 
@@ -80,6 +88,11 @@ type apiKeyRateLimitLoader interface {
 	GetRateLimitData(ctx context.Context, keyID int64) (*APIKeyRateLimitData, error)
 }
 
+// orgMemberSpendLoader 从数据库加载组织成员的当月消费状态
+type orgMemberSpendLoader interface {
+	GetMemberSpendStatus(ctx context.Context, accountUserID, memberID int64, since time.Time) (*OrganizationMemberSpendStatus, error)
+}
+
 // BillingCacheService 计费缓存服务
 // 负责余额和订阅数据的缓存管理，提供高性能的计费资格检查
 type BillingCacheService struct {
@@ -87,6 +100,7 @@ type BillingCacheService struct {
 	userRepo              UserRepository
 	subRepo               UserSubscriptionRepository
 	apiKeyRateLimitLoader apiKeyRateLimitLoader
+	orgMemberSpendLoader  orgMemberSpendLoader
 	cfg                   *config.Config
 	circuitBreaker        *billingCircuitBreaker
 
@@ -104,12 +118,13 @@ type BillingCacheService struct {
 }
 
 // NewBillingCacheService 创建计费缓存服务
-func NewBillingCacheService(cache BillingCache, userRepo UserRepository, subRepo UserSubscriptionRepository, apiKeyRepo APIKeyRepository, cfg *config.Config) *BillingCacheService {
+func NewBillingCacheService(cache BillingCache, userRepo UserRepository, subRepo UserSubscriptionRepository, apiKeyRepo APIKeyRepository, orgRepo OrganizationRepository, cfg *config.Config) *BillingCacheService {
 	svc := &BillingCacheService{
 		cache:                 cache,
 		userRepo:              userRepo,
 		subRepo:               subRepo,
 		apiKeyRateLimitLoader: apiKeyRepo,
+		orgMemberSpendLoader:  orgRepo,
 		cfg:                   cfg,
 	}
 	svc.circuitBreaker = newBillingCircuitBreaker(cfg.Billing.CircuitBreaker)
@@ -203,6 +218,12 @@ func (s *BillingCacheService) cacheWriteWorker(ch <-chan cacheWriteTask) {
 					logger.LegacyPrintf("service.billing_cache", "Warning: update rate limit usage cache failed for api key %d: %v", task.apiKeyID, err)
 				}
 			}
+		case cacheWriteAddOrgMemberSpend:
+			if s.cache != nil {
+				if err := s.cache.AddOrgMemberSpend(ctx, task.userID, task.memberID, task.month, task.amount); err != nil {
+					logger.LegacyPrintf("service.billing_cache", "Warning: add org member spend cache failed for account %d member %d: %v", task.userID, task.memberID, err)
+				}
+			}
 		}
 		cancel()
 	}
@@ -221,6 +242,8 @@ func cacheWriteKindName(kind cacheWriteKind) string {
 		return "deduct_balance"
 	case cacheWriteUpdateRateLimitUsage:
 		return "update_rate_limit_usage"
+	case cacheWriteAddOrgMemberSpend:
+		return "add_org_member_spend"
 	default:
 		return "unknown"
 	}
@@ -271,7 +294,7 @@ func (s *BillingCacheService) logCacheWriteDrop(task cacheWriteTask, reason stri
 // ============================================
 
 // GetUserBalance 获取用户余额（优先从缓存读取）
-func (s *BillingCacheService) GetUserBalance(ctx context.Context, userID int64) (float64, error) {
+func (s *BillingCacheService) GetUserBalance(ctx context.Context, userID int64) (money.Amount, error) {
 	if s.cache == nil {
 		// Redis不可用，直接查询数据库
 		return s.getUserBalanceFromDB(ctx, userID)
@@ -304,7 +327,7 @@ func (s *BillingCacheService) GetUserBalance(ctx context.Context, userID int64)
 	if err != nil {
 		return 0, err
 	}
-	balance, ok := value.(float64)
+	balance, ok := value.(money.Amount)
 	if !ok {
 		return 0, fmt.Errorf("unexpected balance type: %T", value)
 	}
@@ -312,7 +335,7 @@ func (s *BillingCacheService) GetUserBalance(ctx context.Context, userID int64)
 }
 
 // getUserBalanceFromDB 从数据库获取用户余额
-func (s *BillingCacheService) getUserBalanceFromDB(ctx context.Context, userID int64) (float64, error) {
+func (s *BillingCacheService) getUserBalanceFromDB(ctx context.Context, userID int64) (money.Amount, error) {
 	user, err := s.userRepo.GetByID(ctx, userID)
 	if err != nil {
 		return 0, fmt.Errorf("get user balance: %w", err)
@@ -321,7 +344,7 @@ func (s *BillingCacheService) getUserBalanceFromDB(ctx context.Context, userID i
 }
 
 // setBalanceCache 设置余额缓存
-func (s *BillingCacheService) setBalanceCache(ctx context.Context, userID int64, balance float64) {
+func (s *BillingCacheService) setBalanceCache(ctx context.Context, userID int64, balance money.Amount) {
 	if s.cache == nil {
 		return
 	}
@@ -331,7 +354,7 @@ func (s *BillingCacheService) setBalanceCache(ctx context.Context, userID int64,
 }
 
 // DeductBalanceCache 扣减余额缓存（同步调用）
-func (s *BillingCacheService) DeductBalanceCache(ctx context.Context, userID int64, amount float64) error {
+func (s *BillingCacheService) DeductBalanceCache(ctx context.Context, userID int64, amount money.Amount) error {
 	if s.cache == nil {
 		return nil
 	}
@@ -339,7 +362,7 @@ func (s *BillingCacheService) DeductBalanceCache(ctx context.Context, userID int
 }
 
 // QueueDeductBalance 异步扣减余额缓存
-func (s *BillingCacheService) QueueDeductBalance(userID int64, amount float64) {
+func (s *BillingCacheService) QueueDeductBalance(userID int64, amount money.Amount) {
 	if s.cache == nil {
 		return
 	}
@@ -453,7 +476,7 @@ func (s *BillingCacheService) setSubscriptionCache(ctx context.Context, userID,
 }
 
 // UpdateSubscriptionUsage 更新订阅用量缓存（同步调用）
-func (s *BillingCacheService) UpdateSubscriptionUsage(ctx context.Context, userID, groupID int64, costUSD float64) error {
+func (s *BillingCacheService) UpdateSubscriptionUsage(ctx context.Context, userID, groupID int64, costUSD money.Amount) error {
 	if s.cache == nil {
 		return nil
 	}
@@ -461,7 +484,7 @@ func (s *BillingCacheService) UpdateSubscriptionUsage(ctx context.Context, userI
 }
 
 // QueueUpdateSubscriptionUsage 异步更新订阅用量缓存
-func (s *BillingCacheService) QueueUpdateSubscriptionUsage(userID, groupID int64, costUSD float64) {
+func (s *BillingCacheService) QueueUpdateSubscriptionUsage(userID, groupID int64, costUSD money.Amount) {
 	if s.cache == nil {
 		return
 	}
@@ -561,7 +584,7 @@ func (s *BillingCacheService) checkAPIKeyRateLimits(ctx context.Context, apiKey
 }
 
 // evaluateRateLimits checks usage against limits, triggering async resets for expired windows.
-func (s *BillingCacheService) evaluateRateLimits(ctx context.Context, apiKey *APIKey, usage5h, usage1d, usage7d float64, w5h, w1d, w7d *time.Time) error {
+func (s *BillingCacheService) evaluateRateLimits(ctx context.Context, apiKey *APIKey, usage5h, usage1d, usage7d money.Amount, w5h, w1d, w7d *time.Time) error {
 	needsReset := false
 
 	// Reset expired windows in-memory for check purposes
@@ -613,7 +636,7 @@ func (s *BillingCacheService) evaluateRateLimits(ctx context.Context, apiKey *AP
 }
 
 // QueueUpdateAPIKeyRateLimitUsage asynchronously updates rate limit usage in the cache.
-func (s *BillingCacheService) QueueUpdateAPIKeyRateLimitUsage(apiKeyID int64, cost float64) {
+func (s *BillingCacheService) QueueUpdateAPIKeyRateLimitUsage(apiKeyID int64, cost money.Amount) {
 	if s.cache == nil {
 		return
 	}
@@ -624,6 +647,89 @@ func (s *BillingCacheService) QueueUpdateAPIKeyRateLimitUsage(apiKeyID int64, co
 	})
 }
 
+// ============================================
+// 组织成员消费上限缓存方法
+// ============================================
+
+// orgMemberSpendMonth 返回应用时区的当前自然月标识
+func orgMemberSpendMonth(now time.Time) string {
+	return now.Format("2006-01")
+}
+
+// checkOrgMemberSpendLimit 检查组织 Key 创建成员的状态与当月消费上限。
+// 与 API Key 限速一致，数据库错误不阻塞请求。
+func (s *BillingCacheService) checkOrgMemberSpendLimit(ctx context.Context, apiKey *APIKey) error {
+	data, err := s.getOrgMemberSpend(ctx, apiKey.UserID, *apiKey.CreatedByUserID)
+	if err != nil {
+		logger.LegacyPrintf("service.billing_cache", "Warning: load org member spend failed for account %d member %d: %v", apiKey.UserID, *apiKey.CreatedByUserID, err)
+		return nil
+	}
+	if data == nil {
+		return nil
+	}
+	if !data.Active {
+		return ErrOrganizationMemberInactive
+	}
+	if data.Limit > 0 && data.Spent >= data.Limit {
+		return ErrOrganizationMemberSpendLimitExceeded
+	}
+	return nil
+}
+
+// getOrgMemberSpend 读取成员当月消费（缓存未命中或跨月时从数据库汇总并回填缓存）
+func (s *BillingCacheService) getOrgMemberSpend(ctx context.Context, accountUserID, memberID int64) (*OrgMemberSpendCacheData, error) {
+	now := timezone.Now()
+	month := orgMemberSpendMonth(now)
+	if s.cache != nil {
+		if data, err := s.cache.GetOrgMemberSpend(ctx, accountUserID, memberID); err == nil && data != nil && data.Month == month {
+			return data, nil
+		}
+	}
+	if s.orgMemberSpendLoader == nil {
+		return nil, nil
+	}
+	status, err := s.orgMemberSpendLoader.GetMemberSpendStatus(ctx, accountUserID, memberID, timezone.StartOfMonth(now))
+	if err != nil {
+		return nil, err
+	}
+	data := &OrgMemberSpendCacheData{
+		Month:  month,
+		Active: status.Active,
+		Limit:  status.Limit,
+		Spent:  status.Spent,
+	}
+	if s.cache != nil {
+		_ = s.cache.SetOrgMemberSpend(ctx, accountUserID, memberID, data)
+	}
+	return data, nil
+}
+
+// QueueAddOrgMemberSpend 异步累加组织 Key 创建成员的当月消费（个人 Key 忽略）
+func (s *BillingCacheService) QueueAddOrgMemberSpend(apiKey *APIKey, cost money.Amount) {
+	if s == nil || s.cache == nil || !apiKey.IsOrganizationKey() || cost <= 0 {
+		return
+	}
+	s.enqueueCacheWrite(cacheWriteTask{
+		kind:     cacheWriteAddOrgMemberSpend,
+		userID:   apiKey.UserID,
+		memberID: *apiKey.CreatedByUserID,
+		month:    orgMemberSpendMonth(timezone.Now()),
+		amount:   cost,
+	})
+}
+
+// InvalidateOrgMemberSpend 失效成员消费缓存（成员角色、上限变更或移除后调用）
+func (s *BillingCacheService) InvalidateOrgMemberSpend(ctx context.Context, accountUserID, memberID int64) error {
+	if s.cache == nil {
+		return nil
+	}
+	if err := s.cache.InvalidateOrgMemberSpend(ctx, accountUserID, memberID); err != nil {
+		logger.LegacyPrintf("service.billing_cache", "Warning: invalidate org member spend cache failed for account %d member %d: %v", accountUserID, memberID, err)
+		return err
+	}
+	return nil
+}
+
 // ============================================
 // 统一检查方法
 // ============================================
@@ -631,18 +737,24 @@ func (s *BillingCacheService) QueueUpdateAPIKeyRateLimitUsage(apiKeyID int64, co
 // CheckBillingEligibility 检查用户是否有资格发起请求
 // 余额模式：检查缓存余额 > 0
 // 订阅模式：检查缓存用量未超过限额（Group限额从参数传入）
-func (s *BillingCacheService) CheckBillingEligibility(ctx context.Context, user *User, apiKey *APIKey, group *Group, subscription *UserSubscription) error {
+func (s *BillingCacheService) CheckBillingEligibility(ctx context.Context, user *User, apiKey *APIKey, group *Group, subscription *UserSubscription) (err error) {
 	// 简易模式：跳过所有计费检查
 	if s.cfg.RunMode == config.RunModeSimple {
 		return nil
 	}
-	if s.circuitBreaker != nil && !s.circuitBreaker.Allow() {
-		return ErrBillingServiceUnavailable
-	}
 
 	// 判断计费模式
 	isSubscriptionMode := group != nil && group.IsSubscriptionType() && subscription != nil
 
+	ctx, span := tracing.Start(ctx, "billing.check_eligibility", trace.WithAttributes(
+		attribute.Bool("billing.subscription_mode", isSubscriptionMode),
+	))
+	defer func() { tracing.End(span, err) }()
+
+	if s.circuitBreaker != nil && !s.circuitBreaker.Allow() {
+		return ErrBillingServiceUnavailable
+	}
+
 	if isSubscriptionMode {
 		if err := s.checkSubscriptionEligibility(ctx, user.ID, group, subscription); err != nil {
 			return err
@@ -660,6 +772,13 @@ func (s *BillingCacheService) CheckBillingEligibility(ctx context.Context, user
 		}
 	}
 
+	// 组织 Key：检查创建成员的状态与当月消费上限
+	if apiKey.IsOrganizationKey() {
+		if err := s.checkOrgMemberSpendLimit(ctx, apiKey); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/Wei-Shaw/sub2api/internal/config"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
 	"github.com/stretchr/testify/require"
 )
 
@@ -18,16 +19,16 @@ type billingCacheMissStub struct {
 	setBalanceCalls atomic.Int64
 }
 
-func (s *billingCacheMissStub) GetUserBalance(ctx context.Context, userID int64) (float64, error) {
+func (s *billingCacheMissStub) GetUserBalance(ctx context.Context, userID int64) (money.Amount, error) {
 	return 0, errors.New("cache miss")
 }
 
-func (s *billingCacheMissStub) SetUserBalance(ctx context.Context, userID int64, balance float64) error {
+func (s *billingCacheMissStub) SetUserBalance(ctx context.Context, userID int64, balance money.Amount) error {
 	s.setBalanceCalls.Add(1)
 	return nil
 }
 
-func (s *billingCacheMissStub) DeductUserBalance(ctx context.Context, userID int64, amount float64) error {
+func (s *billingCacheMissStub) DeductUserBalance(ctx context.Context, userID int64, amount money.Amount) error {
 	return nil
 }
 
@@ -43,7 +44,7 @@ func (s *billingCacheMissStub) SetSubscriptionCache(ctx context.Context, userID,
 	return nil
 }
 
-func (s *billingCacheMissStub) UpdateSubscriptionUsage(ctx context.Context, userID, groupID int64, cost float64) error {
+func (s *billingCacheMissStub) UpdateSubscriptionUsage(ctx context.Context, userID, groupID int64, cost money.Amount) error {
 	return nil
 }
 
@@ -59,7 +60,7 @@ func (s *billingCacheMissStub) SetAPIKeyRateLimit(ctx context.Context, keyID int
 	return nil
 }
 
-func (s *billingCacheMissStub) UpdateAPIKeyRateLimitUsage(ctx context.Context, keyID int64, cost float64) error {
+func (s *billingCacheMissStub) UpdateAPIKeyRateLimitUsage(ctx context.Context, keyID int64, cost money.Amount) error {
 	return nil
 }
 
@@ -67,11 +68,27 @@ func (s *billingCacheMissStub) InvalidateAPIKeyRateLimit(ctx context.Context, ke
 	return nil
 }
 
+func (s *billingCacheMissStub) GetOrgMemberSpend(ctx context.Context, accountUserID, memberID int64) (*OrgMemberSpendCacheData, error) {
+	return nil, errors.New("cache miss")
+}
+
+func (s *billingCacheMissStub) SetOrgMemberSpend(ctx context.Context, accountUserID, memberID int64, data *OrgMemberSpendCacheData) error {
+	return nil
+}
+
+func (s *billingCacheMissStub) AddOrgMemberSpend(ctx context.Context, accountUserID, memberID int64, month string, cost money.Amount) error {
+	return nil
+}
+
+func (s *billingCacheMissStub) InvalidateOrgMemberSpend(ctx context.Context, accountUserID, memberID int64) error {
+	return nil
+}
+
 type balanceLoadUserRepoStub struct {
 	mockUserRepo
 	calls   atomic.Int64
 	delay   time.Duration
-	balance float64
+	balance money.Amount
 }
 
 func (s *balanceLoadUserRepoStub) GetByID(ctx context.Context, id int64) (*User, error) {
@@ -90,16 +107,16 @@ func TestBillingCacheServiceGetUserBalance_Singleflight(t *testing.T) {
 	cache := &billingCacheMissStub{}
 	userRepo := &balanceLoadUserRepoStub{
 		delay:   80 * time.Millisecond,
-		balance: 12.34,
+		balance: money.MustParse("12.34"),
 	}
-	svc := NewBillingCacheService(cache, userRepo, nil, nil, &config.Config{})
+	svc := NewBillingCacheService(cache, userRepo, nil, nil, nil, &config.Config{})
 	t.Cleanup(svc.Stop)
 
 	const goroutines = 16
 	start := make(chan struct{})
 	var wg sync.WaitGroup
 	errCh := make(chan error, goroutines)
-	balCh := make(chan float64, goroutines)
+	balCh := make(chan money.Amount, goroutines)
 
 	for i := 0; i < goroutines; i++ {
 		wg.Add(1)
@@ -121,7 +138,7 @@ func TestBillingCacheServiceGetUserBalance_Singleflight(t *testing.T) {
 		require.NoError(t, err)
 	}
 	for bal := range balCh {
-		require.Equal(t, 12.34, bal)
+		require.Equal(t, money.MustParse("12.34"), bal)
 	}
 
 	require.Equal(t, int64(1), userRepo.calls.Load(), "并发穿透应被 singleflight 合并")
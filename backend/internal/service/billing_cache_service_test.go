@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/Wei-Shaw/sub2api/internal/config"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
 	"github.com/stretchr/testify/require"
 )
 
@@ -16,16 +17,16 @@ type billingCacheWorkerStub struct {
 	subscriptionUpdates int64
 }
 
-func (b *billingCacheWorkerStub) GetUserBalance(ctx context.Context, userID int64) (float64, error) {
+func (b *billingCacheWorkerStub) GetUserBalance(ctx context.Context, userID int64) (money.Amount, error) {
 	return 0, errors.New("not implemented")
 }
 
-func (b *billingCacheWorkerStub) SetUserBalance(ctx context.Context, userID int64, balance float64) error {
+func (b *billingCacheWorkerStub) SetUserBalance(ctx context.Context, userID int64, balance money.Amount) error {
 	atomic.AddInt64(&b.balanceUpdates, 1)
 	return nil
 }
 
-func (b *billingCacheWorkerStub) DeductUserBalance(ctx context.Context, userID int64, amount float64) error {
+func (b *billingCacheWorkerStub) DeductUserBalance(ctx context.Context, userID int64, amount money.Amount) error {
 	atomic.AddInt64(&b.balanceUpdates, 1)
 	return nil
 }
@@ -43,7 +44,7 @@ func (b *billingCacheWorkerStub) SetSubscriptionCache(ctx context.Context, userI
 	return nil
 }
 
-func (b *billingCacheWorkerStub) UpdateSubscriptionUsage(ctx context.Context, userID, groupID int64, cost float64) error {
+func (b *billingCacheWorkerStub) UpdateSubscriptionUsage(ctx context.Context, userID, groupID int64, cost money.Amount) error {
 	atomic.AddInt64(&b.subscriptionUpdates, 1)
 	return nil
 }
@@ -60,7 +61,7 @@ func (b *billingCacheWorkerStub) SetAPIKeyRateLimit(ctx context.Context, keyID i
 	return nil
 }
 
-func (b *billingCacheWorkerStub) UpdateAPIKeyRateLimitUsage(ctx context.Context, keyID int64, cost float64) error {
+func (b *billingCacheWorkerStub) UpdateAPIKeyRateLimitUsage(ctx context.Context, keyID int64, cost money.Amount) error {
 	return nil
 }
 
@@ -68,9 +69,25 @@ func (b *billingCacheWorkerStub) InvalidateAPIKeyRateLimit(ctx context.Context,
 	return nil
 }
 
+func (b *billingCacheWorkerStub) GetOrgMemberSpend(ctx context.Context, accountUserID, memberID int64) (*OrgMemberSpendCacheData, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (b *billingCacheWorkerStub) SetOrgMemberSpend(ctx context.Context, accountUserID, memberID int64, data *OrgMemberSpendCacheData) error {
+	return nil
+}
+
+func (b *billingCacheWorkerStub) AddOrgMemberSpend(ctx context.Context, accountUserID, memberID int64, month string, cost money.Amount) error {
+	return nil
+}
+
+func (b *billingCacheWorkerStub) InvalidateOrgMemberSpend(ctx context.Context, accountUserID, memberID int64) error {
+	return nil
+}
+
 func TestBillingCacheServiceQueueHighLoad(t *testing.T) {
 	cache := &billingCacheWorkerStub{}
-	svc := NewBillingCacheService(cache, nil, nil, nil, &config.Config{})
+	svc := NewBillingCacheService(cache, nil, nil, nil, nil, &config.Config{})
 	t.Cleanup(svc.Stop)
 
 	start := time.Now()
@@ -79,7 +96,7 @@ func TestBillingCacheServiceQueueHighLoad(t *testing.T) {
 	}
 	require.Less(t, time.Since(start), 2*time.Second)
 
-	svc.QueueUpdateSubscriptionUsage(1, 2, 1.5)
+	svc.QueueUpdateSubscriptionUsage(1, 2, money.MustParse("1.5"))
 
 	require.Eventually(t, func() bool {
 		return atomic.LoadInt64(&cache.balanceUpdates) > 0
@@ -92,7 +109,7 @@ func TestBillingCacheServiceQueueHighLoad(t *testing.T) {
 
 func TestBillingCacheServiceEnqueueAfterStopReturnsFalse(t *testing.T) {
 	cache := &billingCacheWorkerStub{}
-	svc := NewBillingCacheService(cache, nil, nil, nil, &config.Config{})
+	svc := NewBillingCacheService(cache, nil, nil, nil, nil, &config.Config{})
 	svc.Stop()
 
 	enqueued := svc.enqueueCacheWrite(cacheWriteTask{
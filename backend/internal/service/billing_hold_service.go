@@ -0,0 +1,393 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/config"
+	infraerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/logger"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
+	"github.com/google/uuid"
+	"github.com/tidwall/gjson"
+)
+
+// 预授权冻结错误：预估最大费用无法被对应额度覆盖
+var (
+	ErrBillingHoldInsufficientBalance = infraerrors.Forbidden("INSUFFICIENT_BALANCE_FOR_HOLD", "insufficient balance to cover the estimated cost of in-flight requests")
+	ErrBillingHoldQuotaExceeded       = infraerrors.Forbidden("API_KEY_QUOTA_HOLD_EXCEEDED", "api key quota cannot cover the estimated cost of in-flight requests")
+	ErrBillingHoldSubscriptionLimit   = infraerrors.Forbidden("SUBSCRIPTION_LIMIT_HOLD_EXCEEDED", "subscription usage limit cannot cover the estimated cost of in-flight requests")
+)
+
+const billingHoldReleaseTimeout = 2 * time.Second
+
+// 冻结维度
+const (
+	billingHoldKindBalance      = "balance"
+	billingHoldKindQuota        = "quota"
+	billingHoldKindSubscription = "sub"
+)
+
+// BillingHoldLine 单个维度上的冻结
+type BillingHoldLine struct {
+	Scope     string       // 冻结维度标识，如 balance:<user_id> / quota:<api_key_id> / sub:<subscription_id>
+	Amount    money.Amount // 冻结金额
+	Available money.Amount // 冻结前该维度的可用额度（余额、剩余配额或订阅窗口剩余额度）
+}
+
+// BillingHoldCache 预授权冻结存储
+//
+// 每个维度维护一组 holdID -> (金额, 过期时间)，已冻结总额为未过期冻结之和。
+type BillingHoldCache interface {
+	// Reserve 原子地检查每个维度 已冻结 + Amount <= Available，全部满足时写入冻结；
+	// 返回首个不满足的维度下标（从 0 开始），全部满足时返回 -1。
+	Reserve(ctx context.Context, holdID string, lines []BillingHoldLine, ttl time.Duration) (int, error)
+	// Settle 将已存在的冻结改为给定金额并重设过期时间；冻结已释放或过期时忽略。
+	Settle(ctx context.Context, holdID string, lines []BillingHoldLine, ttl time.Duration) error
+	// Release 释放冻结
+	Release(ctx context.Context, holdID string, scopes []string) error
+}
+
+// BillingHoldRequest 冻结请求参数
+type BillingHoldRequest struct {
+	User         *User
+	APIKey       *APIKey
+	Group        *Group
+	Subscription *UserSubscription
+	Model        string
+	Body         []byte // 原始请求体，用于估算输入 token 与读取 max_tokens
+
+	// 非对话接口按各自的计费维度估算
+	InputOnly       bool    // embeddings：仅按 Body 估算的输入 token 计费，不计输出
+	AudioSeconds    float64 // 音频转写/翻译：输入音频时长（秒）
+	AudioCharacters int     // 语音合成：输入字符数
+	// Lines 批处理：逐行估算（使用各行的 Model/Body 等字段）后合计为一笔冻结
+	Lines []BillingHoldRequest
+}
+
+// BillingHoldService 在途请求预授权冻结服务
+//
+// 请求开始时按 输入 token × max_tokens 的预估最大费用在 Redis 中冻结额度
+// （embeddings 仅按输入 token，音频按时长或字符数，批处理按各行合计），
+// 记录使用量时将冻结收敛为实际费用并短暂保留（覆盖异步扣减缓存的窗口），请求失败时释放。
+// 可用额度不足以覆盖 已冻结 + 本次预估 时拒绝请求，避免低余额用户通过并发长请求大幅透支。
+type BillingHoldService struct {
+	cache               BillingHoldCache
+	billingService      *BillingService
+	billingCacheService *BillingCacheService
+	cfg                 *config.Config
+}
+
+// NewBillingHoldService 创建预授权冻结服务
+func NewBillingHoldService(cache BillingHoldCache, billingService *BillingService, billingCacheService *BillingCacheService, cfg *config.Config) *BillingHoldService {
+	return &BillingHoldService{
+		cache:               cache,
+		billingService:      billingService,
+		billingCacheService: billingCacheService,
+		cfg:                 cfg,
+	}
+}
+
+// Enabled 是否启用预授权冻结
+func (s *BillingHoldService) Enabled() bool {
+	return s != nil && s.cache != nil && s.cfg != nil && s.cfg.Billing.Holds.Enabled && s.cfg.RunMode != config.RunModeSimple
+}
+
+// Reserve 按预估最大费用冻结额度
+//
+// 未启用、无法估算费用或存储异常时返回 nil 冻结且不阻断请求（资格检查已确认余额/用量未耗尽）；
+// 仅在可用额度不足以覆盖冻结时返回错误。返回的 *BillingHold 方法均为 nil-safe。
+func (s *BillingHoldService) Reserve(ctx context.Context, req BillingHoldRequest) (*BillingHold, error) {
+	if !s.Enabled() || req.User == nil || req.APIKey == nil {
+		return nil, nil
+	}
+
+	totalCost, actualCost, ok := s.estimateCost(req)
+	if !ok {
+		return nil, nil
+	}
+
+	lines, kinds, err := s.buildLines(ctx, req, totalCost, actualCost)
+	if err != nil {
+		logger.LegacyPrintf("service.billing_hold", "Warning: load available amount failed for user %d: %v", req.User.ID, err)
+		return nil, nil
+	}
+	if len(lines) == 0 {
+		return nil, nil
+	}
+
+	hold := &BillingHold{svc: s, id: uuid.NewString(), lines: lines, kinds: kinds}
+	rejected, err := s.cache.Reserve(ctx, hold.id, lines, s.ttl())
+	if err != nil {
+		logger.LegacyPrintf("service.billing_hold", "Warning: reserve hold failed for user %d: %v", req.User.ID, err)
+		return nil, nil
+	}
+	if rejected >= 0 && rejected < len(kinds) {
+		switch kinds[rejected] {
+		case billingHoldKindSubscription:
+			return nil, ErrBillingHoldSubscriptionLimit
+		case billingHoldKindQuota:
+			return nil, ErrBillingHoldQuotaExceeded
+		default:
+			return nil, ErrBillingHoldInsufficientBalance
+		}
+	}
+	return hold, nil
+}
+
+// estimateCost 估算本次请求的最大费用，返回 (原始费用, 倍率后费用)
+func (s *BillingHoldService) estimateCost(req BillingHoldRequest) (money.Amount, money.Amount, bool) {
+	if s.billingService == nil {
+		return 0, 0, false
+	}
+	var totalCost money.Amount
+	if len(req.Lines) > 0 {
+		for _, line := range req.Lines {
+			line.Group = req.Group
+			if cost, ok := s.estimateRawCost(line); ok {
+				totalCost += cost
+			}
+		}
+	} else if cost, ok := s.estimateRawCost(req); ok {
+		totalCost = cost
+	}
+	if totalCost <= 0 {
+		return 0, 0, false
+	}
+
+	// 按原始费用估算后再套用分组倍率（无分组时使用系统默认倍率）
+	multiplier := s.defaultRateMultiplier()
+	if req.Group != nil {
+		multiplier = req.Group.RateMultiplier
+	}
+	return totalCost, totalCost.Mul(multiplier), true
+}
+
+// estimateRawCost 估算单个请求不含倍率的最大费用
+func (s *BillingHoldService) estimateRawCost(req BillingHoldRequest) (money.Amount, bool) {
+	if req.Model == "" {
+		return 0, false
+	}
+	var audioConfig *AudioPriceConfig
+	if req.Group != nil {
+		audioConfig = &AudioPriceConfig{
+			PricePerMinute:        req.Group.AudioPricePerMinute,
+			SpeechPricePer1MChars: req.Group.AudioSpeechPricePer1MChars,
+		}
+	}
+	switch {
+	case req.AudioSeconds > 0:
+		return s.billingService.CalculateAudioTranscriptionCost(req.Model, req.AudioSeconds, audioConfig, 1).TotalCost, true
+	case req.AudioCharacters > 0:
+		return s.billingService.CalculateAudioSpeechCost(req.Model, req.AudioCharacters, audioConfig, 1).TotalCost, true
+	}
+
+	inputTokens := estimateBillingHoldInputTokens(req.Body)
+	outputTokens := 0
+	if !req.InputOnly {
+		outputTokens = billingHoldMaxOutputTokens(req.Body)
+		if outputTokens <= 0 {
+			outputTokens = s.cfg.Billing.Holds.DefaultMaxOutputTokens
+		}
+	}
+	// GetEstimatedCost 按系统默认倍率计价，这里还原为原始费用
+	estimated, err := s.billingService.GetEstimatedCost(req.Model, inputTokens, outputTokens)
+	if err != nil || estimated <= 0 {
+		return 0, false
+	}
+	return estimated.Mul(1 / s.defaultRateMultiplier()), true
+}
+
+func (s *BillingHoldService) defaultRateMultiplier() float64 {
+	if s.cfg.Default.RateMultiplier <= 0 {
+		return 1.0
+	}
+	return s.cfg.Default.RateMultiplier
+}
+
+// buildLines 计算各维度冻结金额与可用额度
+func (s *BillingHoldService) buildLines(ctx context.Context, req BillingHoldRequest, totalCost, actualCost money.Amount) ([]BillingHoldLine, []string, error) {
+	var lines []BillingHoldLine
+	var kinds []string
+
+	isSubscriptionMode := req.Group != nil && req.Group.IsSubscriptionType() && req.Subscription != nil
+	if isSubscriptionMode {
+		// 订阅模式按原始费用计入用量，可用额度取各窗口剩余额度的最小值
+		if available, limited, err := s.subscriptionAvailable(ctx, req.User.ID, req.Group); err != nil {
+			return nil, nil, err
+		} else if limited && totalCost > 0 {
+			lines = append(lines, BillingHoldLine{
+				Scope:     fmt.Sprintf("%s:%d", billingHoldKindSubscription, req.Subscription.ID),
+				Amount:    totalCost,
+				Available: available,
+			})
+			kinds = append(kinds, billingHoldKindSubscription)
+		}
+	} else if actualCost > 0 {
+		balance, err := s.billingCacheService.GetUserBalance(ctx, req.User.ID)
+		if err != nil {
+			return nil, nil, err
+		}
+		lines = append(lines, BillingHoldLine{
+			Scope:     fmt.Sprintf("%s:%d", billingHoldKindBalance, req.User.ID),
+			Amount:    actualCost,
+			Available: balance,
+		})
+		kinds = append(kinds, billingHoldKindBalance)
+	}
+
+	if req.APIKey.Quota > 0 && actualCost > 0 {
+		lines = append(lines, BillingHoldLine{
+			Scope:     fmt.Sprintf("%s:%d", billingHoldKindQuota, req.APIKey.ID),
+			Amount:    actualCost,
+			Available: req.APIKey.Quota - req.APIKey.QuotaUsed,
+		})
+		kinds = append(kinds, billingHoldKindQuota)
+	}
+	return lines, kinds, nil
+}
+
+func (s *BillingHoldService) subscriptionAvailable(ctx context.Context, userID int64, group *Group) (money.Amount, bool, error) {
+	if !group.HasDailyLimit() && !group.HasWeeklyLimit() && !group.HasMonthlyLimit() {
+		return 0, false, nil
+	}
+	subData, err := s.billingCacheService.GetSubscriptionStatus(ctx, userID, group.ID)
+	if err != nil {
+		return 0, false, err
+	}
+	available := money.Amount(math.MaxInt64)
+	if group.HasDailyLimit() {
+		available = min(available, *group.DailyLimitUSD-subData.DailyUsage)
+	}
+	if group.HasWeeklyLimit() {
+		available = min(available, *group.WeeklyLimitUSD-subData.WeeklyUsage)
+	}
+	if group.HasMonthlyLimit() {
+		available = min(available, *group.MonthlyLimitUSD-subData.MonthlyUsage)
+	}
+	return available, true, nil
+}
+
+func (s *BillingHoldService) ttl() time.Duration {
+	return time.Duration(s.cfg.Billing.Holds.TTLSeconds) * time.Second
+}
+
+func (s *BillingHoldService) settleGrace() time.Duration {
+	return time.Duration(s.cfg.Billing.Holds.SettleGraceSeconds) * time.Second
+}
+
+// BillingHold 单个请求的冻结句柄
+//
+// Settle 与 Release 只有首次调用生效。
+type BillingHold struct {
+	svc   *BillingHoldService
+	id    string
+	lines []BillingHoldLine
+	kinds []string
+	once  sync.Once
+}
+
+// ID 冻结标识
+func (h *BillingHold) ID() string {
+	if h == nil {
+		return ""
+	}
+	return h.id
+}
+
+// Settle 将冻结收敛为实际费用，并在结算宽限期内继续保留
+//
+// 使用量扣费与余额缓存更新均为异步执行，保留实际费用可避免扣减生效前的短暂窗口被并发请求占用。
+func (h *BillingHold) Settle(ctx context.Context, cost *CostBreakdown) {
+	if h == nil {
+		return
+	}
+	h.once.Do(func() {
+		settled := make([]BillingHoldLine, len(h.lines))
+		for i, line := range h.lines {
+			settled[i] = line
+			settled[i].Amount = 0
+			if cost != nil {
+				if h.kinds[i] == billingHoldKindSubscription {
+					settled[i].Amount = cost.TotalCost
+				} else {
+					settled[i].Amount = cost.ActualCost
+				}
+			}
+		}
+		if err := h.svc.cache.Settle(ctx, h.id, settled, h.svc.settleGrace()); err != nil {
+			logger.LegacyPrintf("service.billing_hold", "Warning: settle hold %s failed: %v", h.id, err)
+		}
+	})
+}
+
+// Release 释放冻结（请求失败或未产生使用量时调用）
+func (h *BillingHold) Release() {
+	if h == nil {
+		return
+	}
+	h.once.Do(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), billingHoldReleaseTimeout)
+		defer cancel()
+		scopes := make([]string, len(h.lines))
+		for i, line := range h.lines {
+			scopes[i] = line.Scope
+		}
+		if err := h.svc.cache.Release(ctx, h.id, scopes); err != nil {
+			logger.LegacyPrintf("service.billing_hold", "Warning: release hold %s failed: %v", h.id, err)
+		}
+	})
+}
+
+// billingHoldMaxOutputTokens 读取请求声明的最大输出 token 数（兼容 Anthropic / OpenAI / Gemini 请求格式）
+func billingHoldMaxOutputTokens(body []byte) int {
+	if len(body) == 0 {
+		return 0
+	}
+	for _, path := range []string{"max_tokens", "max_output_tokens", "max_completion_tokens", "generationConfig.maxOutputTokens"} {
+		if v := gjson.GetBytes(body, path); v.Exists() && v.Int() > 0 {
+			return int(v.Int())
+		}
+	}
+	return 0
+}
+
+// billingHoldBinaryKeys 携带 base64 或链接等非文本载荷的字段，不计入输入 token 估算
+var billingHoldBinaryKeys = map[string]struct{}{
+	"data":        {},
+	"file_data":   {},
+	"image_url":   {},
+	"url":         {},
+	"signature":   {},
+	"model":       {},
+	"inline_data": {},
+	"inlineData":  {},
+}
+
+// estimateBillingHoldInputTokens 按请求体中的文本字段估算输入 token 数
+func estimateBillingHoldInputTokens(body []byte) int {
+	if len(body) == 0 || !gjson.ValidBytes(body) {
+		return 0
+	}
+	total := 0
+	var walk func(value gjson.Result)
+	walk = func(value gjson.Result) {
+		value.ForEach(func(key, item gjson.Result) bool {
+			if _, skip := billingHoldBinaryKeys[key.String()]; skip && key.Type == gjson.String {
+				return true
+			}
+			switch {
+			case item.Type == gjson.String:
+				total += estimateTokensForText(item.String())
+			case item.IsObject() || item.IsArray():
+				walk(item)
+			}
+			return true
+		})
+	}
+	walk(gjson.ParseBytes(body))
+	return total
+}
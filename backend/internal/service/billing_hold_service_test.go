@@ -0,0 +1,266 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/config"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
+	"github.com/stretchr/testify/require"
+)
+
+type billingHoldCacheFake struct {
+	mu      sync.Mutex
+	holds   map[string]map[string]money.Amount // scope -> holdID -> amount
+	settled map[string]time.Duration
+}
+
+func newBillingHoldCacheFake() *billingHoldCacheFake {
+	return &billingHoldCacheFake{
+		holds:   make(map[string]map[string]money.Amount),
+		settled: make(map[string]time.Duration),
+	}
+}
+
+func (f *billingHoldCacheFake) Reserve(ctx context.Context, holdID string, lines []BillingHoldLine, ttl time.Duration) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for i, line := range lines {
+		if f.heldLocked(line.Scope)+line.Amount > line.Available {
+			return i, nil
+		}
+	}
+	for _, line := range lines {
+		if f.holds[line.Scope] == nil {
+			f.holds[line.Scope] = make(map[string]money.Amount)
+		}
+		f.holds[line.Scope][holdID] = line.Amount
+	}
+	return -1, nil
+}
+
+func (f *billingHoldCacheFake) Settle(ctx context.Context, holdID string, lines []BillingHoldLine, ttl time.Duration) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, line := range lines {
+		if _, ok := f.holds[line.Scope][holdID]; ok {
+			f.holds[line.Scope][holdID] = line.Amount
+		}
+	}
+	f.settled[holdID] = ttl
+	return nil
+}
+
+func (f *billingHoldCacheFake) Release(ctx context.Context, holdID string, scopes []string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, scope := range scopes {
+		delete(f.holds[scope], holdID)
+	}
+	return nil
+}
+
+func (f *billingHoldCacheFake) held(scope string) float64 {
+	return f.heldAmount(scope).Float64()
+}
+
+func (f *billingHoldCacheFake) heldAmount(scope string) money.Amount {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.heldLocked(scope)
+}
+
+func (f *billingHoldCacheFake) heldLocked(scope string) money.Amount {
+	var total money.Amount
+	for _, amount := range f.holds[scope] {
+		total += amount
+	}
+	return total
+}
+
+type billingHoldBalanceStub struct {
+	billingCacheWorkerStub
+	balance money.Amount
+	sub     *SubscriptionCacheData
+}
+
+func (b *billingHoldBalanceStub) GetUserBalance(ctx context.Context, userID int64) (money.Amount, error) {
+	return b.balance, nil
+}
+
+func (b *billingHoldBalanceStub) GetSubscriptionCache(ctx context.Context, userID, groupID int64) (*SubscriptionCacheData, error) {
+	return b.sub, nil
+}
+
+func newBillingHoldTestService(t *testing.T, stub *billingHoldBalanceStub) (*BillingHoldService, *billingHoldCacheFake) {
+	t.Helper()
+	cfg := &config.Config{}
+	cfg.Default.RateMultiplier = 1
+	cfg.Billing.Holds = config.BillingHoldConfig{
+		Enabled:                true,
+		DefaultMaxOutputTokens: 4096,
+		TTLSeconds:             600,
+		SettleGraceSeconds:     30,
+	}
+	billingCacheSvc := NewBillingCacheService(stub, nil, nil, nil, nil, cfg)
+	t.Cleanup(billingCacheSvc.Stop)
+	holdCache := newBillingHoldCacheFake()
+	return NewBillingHoldService(holdCache, NewBillingService(cfg, nil), billingCacheSvc, cfg), holdCache
+}
+
+// claude-sonnet-4 回退价格 $3/$15 per MTok：max_tokens=1000 的短请求约冻结 $0.015
+const billingHoldTestBody = `{"model":"claude-sonnet-4","max_tokens":1000,"messages":[{"role":"user","content":"hi"}]}`
+
+func TestBillingHoldService_RejectsConcurrentHoldsBeyondBalance(t *testing.T) {
+	svc, holdCache := newBillingHoldTestService(t, &billingHoldBalanceStub{balance: money.MustParse("0.02")})
+	user := &User{ID: 1}
+	req := BillingHoldRequest{User: user, APIKey: &APIKey{ID: 2}, Group: &Group{ID: 3, RateMultiplier: 1}, Model: "claude-sonnet-4", Body: []byte(billingHoldTestBody)}
+
+	first, err := svc.Reserve(context.Background(), req)
+	require.NoError(t, err)
+	require.NotNil(t, first)
+	require.InDelta(t, 0.015, holdCache.held("balance:1"), 1e-4)
+
+	second, err := svc.Reserve(context.Background(), req)
+	require.ErrorIs(t, err, ErrBillingHoldInsufficientBalance)
+	require.Nil(t, second)
+
+	first.Release()
+	require.Zero(t, holdCache.held("balance:1"))
+
+	second, err = svc.Reserve(context.Background(), req)
+	require.NoError(t, err)
+	require.NotNil(t, second)
+}
+
+func TestBillingHoldService_SettleShrinksToActualCost(t *testing.T) {
+	svc, holdCache := newBillingHoldTestService(t, &billingHoldBalanceStub{balance: 10 * money.USD})
+	req := BillingHoldRequest{User: &User{ID: 1}, APIKey: &APIKey{ID: 2, Quota: 5 * money.USD}, Group: &Group{ID: 3, RateMultiplier: 2}, Model: "claude-sonnet-4", Body: []byte(billingHoldTestBody)}
+
+	hold, err := svc.Reserve(context.Background(), req)
+	require.NoError(t, err)
+	require.InDelta(t, 0.03, holdCache.held("balance:1"), 2e-4, "group multiplier applies to the balance hold")
+	require.InDelta(t, 0.03, holdCache.held("quota:2"), 2e-4)
+
+	hold.Settle(context.Background(), &CostBreakdown{TotalCost: money.MustParse("0.002"), ActualCost: money.MustParse("0.004")})
+	require.Equal(t, money.MustParse("0.004"), holdCache.heldAmount("balance:1"))
+	require.Equal(t, money.MustParse("0.004"), holdCache.heldAmount("quota:2"))
+	require.Equal(t, 30*time.Second, holdCache.settled[hold.ID()])
+
+	// Settle 之后 Release 不再生效，实际费用保留至宽限期结束
+	hold.Release()
+	require.Equal(t, money.MustParse("0.004"), holdCache.heldAmount("balance:1"))
+}
+
+func TestBillingHoldService_QuotaHoldRejected(t *testing.T) {
+	svc, _ := newBillingHoldTestService(t, &billingHoldBalanceStub{balance: 10 * money.USD})
+	req := BillingHoldRequest{User: &User{ID: 1}, APIKey: &APIKey{ID: 2, Quota: money.USD, QuotaUsed: money.MustParse("0.99")}, Group: &Group{ID: 3, RateMultiplier: 1}, Model: "claude-sonnet-4", Body: []byte(billingHoldTestBody)}
+
+	hold, err := svc.Reserve(context.Background(), req)
+	require.ErrorIs(t, err, ErrBillingHoldQuotaExceeded)
+	require.Nil(t, hold)
+}
+
+func TestBillingHoldService_SubscriptionWindowLimit(t *testing.T) {
+	daily := 1 * money.USD
+	monthly := 100 * money.USD
+	stub := &billingHoldBalanceStub{sub: &SubscriptionCacheData{
+		Status:       SubscriptionStatusActive,
+		ExpiresAt:    time.Now().Add(time.Hour),
+		DailyUsage:   money.MustParse("0.99"),
+		MonthlyUsage: 10 * money.USD,
+	}}
+	svc, holdCache := newBillingHoldTestService(t, stub)
+	group := &Group{ID: 3, SubscriptionType: SubscriptionTypeSubscription, DailyLimitUSD: &daily, MonthlyLimitUSD: &monthly}
+	req := BillingHoldRequest{User: &User{ID: 1}, APIKey: &APIKey{ID: 2}, Group: group, Subscription: &UserSubscription{ID: 9}, Model: "claude-sonnet-4", Body: []byte(billingHoldTestBody)}
+
+	hold, err := svc.Reserve(context.Background(), req)
+	require.ErrorIs(t, err, ErrBillingHoldSubscriptionLimit)
+	require.Nil(t, hold)
+
+	stub.sub.DailyUsage = 0
+	hold, err = svc.Reserve(context.Background(), req)
+	require.NoError(t, err)
+	require.NotNil(t, hold)
+	require.InDelta(t, 0.015, holdCache.held("sub:9"), 1e-4)
+	require.Zero(t, holdCache.held("balance:1"), "subscription mode does not hold balance")
+}
+
+func TestBillingHoldService_DisabledOrSimpleModeReturnsNilHold(t *testing.T) {
+	svc, _ := newBillingHoldTestService(t, &billingHoldBalanceStub{balance: 0})
+	req := BillingHoldRequest{User: &User{ID: 1}, APIKey: &APIKey{ID: 2}, Model: "claude-sonnet-4", Body: []byte(billingHoldTestBody)}
+
+	svc.cfg.RunMode = config.RunModeSimple
+	hold, err := svc.Reserve(context.Background(), req)
+	require.NoError(t, err)
+	require.Nil(t, hold)
+
+	var nilSvc *BillingHoldService
+	hold, err = nilSvc.Reserve(context.Background(), req)
+	require.NoError(t, err)
+	require.Nil(t, hold)
+
+	// nil 句柄的 Settle/Release 为空操作
+	hold.Settle(context.Background(), &CostBreakdown{})
+	hold.Release()
+}
+
+func TestBillingHoldMaxOutputTokens(t *testing.T) {
+	require.Equal(t, 1000, billingHoldMaxOutputTokens([]byte(`{"max_tokens":1000}`)))
+	require.Equal(t, 2000, billingHoldMaxOutputTokens([]byte(`{"max_output_tokens":2000}`)))
+	require.Equal(t, 3000, billingHoldMaxOutputTokens([]byte(`{"max_completion_tokens":3000}`)))
+	require.Equal(t, 4000, billingHoldMaxOutputTokens([]byte(`{"generationConfig":{"maxOutputTokens":4000}}`)))
+	require.Zero(t, billingHoldMaxOutputTokens([]byte(`{"messages":[]}`)))
+}
+
+func TestEstimateBillingHoldInputTokens_SkipsBinaryPayloads(t *testing.T) {
+	const tmpl = `{"model":"claude-sonnet-4","messages":[{"role":"user","content":[{"type":"text","text":"hello world, this is a prompt"},{"type":"image","source":{"type":"base64","data":"%s"}}]}]}`
+	empty := estimateBillingHoldInputTokens([]byte(fmt.Sprintf(tmpl, "")))
+	require.Positive(t, empty)
+
+	withImage := estimateBillingHoldInputTokens([]byte(fmt.Sprintf(tmpl, strings.Repeat("iVBORw0KGgo", 1000))))
+	require.Equal(t, empty, withImage, "base64 image data must not be counted as text")
+}
+
+func TestBillingHoldService_NonChatEstimates(t *testing.T) {
+	svc, holdCache := newBillingHoldTestService(t, &billingHoldBalanceStub{balance: 10 * money.USD})
+	perMinute := money.MustParse("0.5")
+	base := BillingHoldRequest{User: &User{ID: 1}, APIKey: &APIKey{ID: 2}, Group: &Group{ID: 3, RateMultiplier: 1, AudioPricePerMinute: &perMinute}}
+
+	// embeddings 仅按输入 token 估算，不套用默认最大输出
+	embed := base
+	embed.Model = "claude-sonnet-4"
+	embed.Body = []byte(`{"model":"claude-sonnet-4","input":"hello world"}`)
+	embed.InputOnly = true
+	hold, err := svc.Reserve(context.Background(), embed)
+	require.NoError(t, err)
+	require.NotNil(t, hold)
+	require.Positive(t, holdCache.heldAmount("balance:1"))
+	require.Less(t, holdCache.held("balance:1"), 0.001)
+	hold.Release()
+
+	// 音频转写按时长估算
+	audio := base
+	audio.Model = "whisper-1"
+	audio.AudioSeconds = 60
+	hold, err = svc.Reserve(context.Background(), audio)
+	require.NoError(t, err)
+	require.Equal(t, money.MustParse("0.5"), holdCache.heldAmount("balance:1"))
+	hold.Release()
+
+	// 批处理逐行估算后合计
+	batch := base
+	batch.Lines = []BillingHoldRequest{
+		{Model: "claude-sonnet-4", Body: []byte(billingHoldTestBody)},
+		{Model: "claude-sonnet-4", Body: []byte(billingHoldTestBody)},
+	}
+	hold, err = svc.Reserve(context.Background(), batch)
+	require.NoError(t, err)
+	require.InDelta(t, 0.03, holdCache.held("balance:1"), 2e-4)
+	hold.Release()
+	require.Zero(t, holdCache.held("balance:1"))
+}
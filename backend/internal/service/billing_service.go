@@ -13,38 +13,53 @@ import (
 	"time"
 
 	"github.com/Wei-Shaw/sub2api/internal/config"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
 	"github.com/tidwall/gjson"
 )
 
 // APIKeyRateLimitCacheData holds rate limit usage data cached in Redis.
 type APIKeyRateLimitCacheData struct {
-	Usage5h  float64 `json:"usage_5h"`
-	Usage1d  float64 `json:"usage_1d"`
-	Usage7d  float64 `json:"usage_7d"`
-	Window5h int64   `json:"window_5h"` // unix timestamp, 0 = not started
-	Window1d int64   `json:"window_1d"`
-	Window7d int64   `json:"window_7d"`
+	Usage5h  money.Amount `json:"usage_5h"`
+	Usage1d  money.Amount `json:"usage_1d"`
+	Usage7d  money.Amount `json:"usage_7d"`
+	Window5h int64        `json:"window_5h"` // unix timestamp, 0 = not started
+	Window1d int64        `json:"window_1d"`
+	Window7d int64        `json:"window_7d"`
+}
+
+// OrgMemberSpendCacheData 组织成员当月消费缓存（Month 非当月时视为未命中）
+type OrgMemberSpendCacheData struct {
+	Month  string       `json:"month"` // 应用时区的自然月，格式 2006-01
+	Active bool         `json:"active"`
+	Limit  money.Amount `json:"limit"`
+	Spent  money.Amount `json:"spent"`
 }
 
 // BillingCache defines cache operations for billing service
 type BillingCache interface {
 	// Balance operations
-	GetUserBalance(ctx context.Context, userID int64) (float64, error)
-	SetUserBalance(ctx context.Context, userID int64, balance float64) error
-	DeductUserBalance(ctx context.Context, userID int64, amount float64) error
+	GetUserBalance(ctx context.Context, userID int64) (money.Amount, error)
+	SetUserBalance(ctx context.Context, userID int64, balance money.Amount) error
+	DeductUserBalance(ctx context.Context, userID int64, amount money.Amount) error
 	InvalidateUserBalance(ctx context.Context, userID int64) error
 
 	// Subscription operations
 	GetSubscriptionCache(ctx context.Context, userID, groupID int64) (*SubscriptionCacheData, error)
 	SetSubscriptionCache(ctx context.Context, userID, groupID int64, data *SubscriptionCacheData) error
-	UpdateSubscriptionUsage(ctx context.Context, userID, groupID int64, cost float64) error
+	UpdateSubscriptionUsage(ctx context.Context, userID, groupID int64, cost money.Amount) error
 	InvalidateSubscriptionCache(ctx context.Context, userID, groupID int64) error
 
 	// API Key rate limit operations
 	GetAPIKeyRateLimit(ctx context.Context, keyID int64) (*APIKeyRateLimitCacheData, error)
 	SetAPIKeyRateLimit(ctx context.Context, keyID int64, data *APIKeyRateLimitCacheData) error
-	UpdateAPIKeyRateLimitUsage(ctx context.Context, keyID int64, cost float64) error
+	UpdateAPIKeyRateLimitUsage(ctx context.Context, keyID int64, cost money.Amount) error
 	InvalidateAPIKeyRateLimit(ctx context.Context, keyID int64) error
+
+	// Organization member spend operations
+	GetOrgMemberSpend(ctx context.Context, accountUserID, memberID int64) (*OrgMemberSpendCacheData, error)
+	SetOrgMemberSpend(ctx context.Context, accountUserID, memberID int64, data *OrgMemberSpendCacheData) error
+	AddOrgMemberSpend(ctx context.Context, accountUserID, memberID int64, month string, cost money.Amount) error
+	InvalidateOrgMemberSpend(ctx context.Context, accountUserID, memberID int64) error
 }
 
 // ModelPricing 模型价格配置（per-token价格，与LiteLLM格式一致）
@@ -71,14 +86,14 @@ type UsageTokens struct {
 	CacheCreation1hTokens int
 }
 
-// CostBreakdown 费用明细
+// CostBreakdown 费用明细（定点金额，各分项按单价×数量精确计算，合计为分项精确求和）
 type CostBreakdown struct {
-	InputCost         float64
-	OutputCost        float64
-	CacheCreationCost float64
-	CacheReadCost     float64
-	TotalCost         float64
-	ActualCost        float64 // 应用倍率后的实际费用
+	InputCost         money.Amount
+	OutputCost        money.Amount
+	CacheCreationCost money.Amount
+	CacheReadCost     money.Amount
+	TotalCost         money.Amount
+	ActualCost        money.Amount // 应用倍率后的实际费用
 }
 
 // BillingService 计费服务
@@ -426,37 +441,37 @@ func (s *BillingService) CalculateCost(model string, tokens UsageTokens, rateMul
 	breakdown := &CostBreakdown{}
 
 	// 计算输入token费用（使用per-token价格）
-	breakdown.InputCost = float64(tokens.InputTokens) * pricing.InputPricePerToken
+	breakdown.InputCost = money.Price(pricing.InputPricePerToken, float64(tokens.InputTokens))
 
 	// 计算输出token费用
-	breakdown.OutputCost = float64(tokens.OutputTokens) * pricing.OutputPricePerToken
+	breakdown.OutputCost = money.Price(pricing.OutputPricePerToken, float64(tokens.OutputTokens))
 
 	// 计算缓存费用
 	if pricing.SupportsCacheBreakdown && (pricing.CacheCreation5mPrice > 0 || pricing.CacheCreation1hPrice > 0) {
 		// 支持详细缓存分类的模型（5分钟/1小时缓存，价格为 per-token）
 		if tokens.CacheCreation5mTokens == 0 && tokens.CacheCreation1hTokens == 0 && tokens.CacheCreationTokens > 0 {
 			// API 未返回 ephemeral 明细，回退到全部按 5m 单价计费
-			breakdown.CacheCreationCost = float64(tokens.CacheCreationTokens) * pricing.CacheCreation5mPrice
+			breakdown.CacheCreationCost = money.Price(pricing.CacheCreation5mPrice, float64(tokens.CacheCreationTokens))
 		} else {
-			breakdown.CacheCreationCost = float64(tokens.CacheCreation5mTokens)*pricing.CacheCreation5mPrice +
-				float64(tokens.CacheCreation1hTokens)*pricing.CacheCreation1hPrice
+			breakdown.CacheCreationCost = money.Price(pricing.CacheCreation5mPrice, float64(tokens.CacheCreation5mTokens)) +
+				money.Price(pricing.CacheCreation1hPrice, float64(tokens.CacheCreation1hTokens))
 		}
 	} else {
 		// 标准缓存创建价格（per-token）
-		breakdown.CacheCreationCost = float64(tokens.CacheCreationTokens) * pricing.CacheCreationPricePerToken
+		breakdown.CacheCreationCost = money.Price(pricing.CacheCreationPricePerToken, float64(tokens.CacheCreationTokens))
 	}
 
-	breakdown.CacheReadCost = float64(tokens.CacheReadTokens) * pricing.CacheReadPricePerToken
+	breakdown.CacheReadCost = money.Price(pricing.CacheReadPricePerToken, float64(tokens.CacheReadTokens))
 
 	// 计算总费用
-	breakdown.TotalCost = breakdown.InputCost + breakdown.OutputCost +
-		breakdown.CacheCreationCost + breakdown.CacheReadCost
+	breakdown.TotalCost = money.Sum(breakdown.InputCost, breakdown.OutputCost,
+		breakdown.CacheCreationCost, breakdown.CacheReadCost)
 
 	// 应用倍率计算实际费用
 	if rateMultiplier <= 0 {
 		rateMultiplier = 1.0
 	}
-	breakdown.ActualCost = breakdown.TotalCost * rateMultiplier
+	breakdown.ActualCost = breakdown.TotalCost.Mul(rateMultiplier)
 
 	return breakdown, nil
 }
@@ -542,6 +557,22 @@ func (s *BillingService) CalculateCostWithLongContext(model string, tokens Usage
 	}, nil
 }
 
+// BatchDiscountRate 返回批处理请求叠加的计费倍率（未配置或非法时为 1，即不打折）
+func (s *BillingService) BatchDiscountRate() float64 {
+	if s == nil || s.cfg == nil || s.cfg.Batch.DiscountRate <= 0 {
+		return 1.0
+	}
+	return s.cfg.Batch.DiscountRate
+}
+
+// ApplyBatchDiscount 对批处理执行器回放的请求在原倍率上叠加批处理折扣，普通请求原样返回
+func (s *BillingService) ApplyBatchDiscount(rateMultiplier float64, apiKey *APIKey) float64 {
+	if apiKey == nil || apiKey.BatchJobID <= 0 {
+		return rateMultiplier
+	}
+	return rateMultiplier * s.BatchDiscountRate()
+}
+
 // ListSupportedModels 列出所有支持的模型（现在总是返回true，因为有模糊匹配）
 func (s *BillingService) ListSupportedModels() []string {
 	models := make([]string, 0)
@@ -563,7 +594,7 @@ func (s *BillingService) IsModelSupported(model string) bool {
 }
 
 // GetEstimatedCost 估算费用（用于前端展示）
-func (s *BillingService) GetEstimatedCost(model string, estimatedInputTokens, estimatedOutputTokens int) (float64, error) {
+func (s *BillingService) GetEstimatedCost(model string, estimatedInputTokens, estimatedOutputTokens int) (money.Amount, error) {
 	tokens := UsageTokens{
 		InputTokens:  estimatedInputTokens,
 		OutputTokens: estimatedOutputTokens,
@@ -599,24 +630,36 @@ func (s *BillingService) ForceUpdatePricing() error {
 
 // ImagePriceConfig 图片计费配置
 type ImagePriceConfig struct {
-	Price1K       *float64 // 1K 尺寸价格（nil 表示使用默认值）
-	Price2K       *float64 // 2K 尺寸价格（nil 表示使用默认值）
-	Price4K       *float64 // 4K 尺寸价格（nil 表示使用默认值）
-	PricePerImage *float64 // 单张价格（优先于尺寸价格）
+	Price1K       *money.Amount // 1K 尺寸价格（nil 表示使用默认值）
+	Price2K       *money.Amount // 2K 尺寸价格（nil 表示使用默认值）
+	Price4K       *money.Amount // 4K 尺寸价格（nil 表示使用默认值）
+	PricePerImage *money.Amount // 单张价格（优先于尺寸价格）
 }
 
 // VideoPriceConfig 通用视频生成计费配置（用于 Grok 等 OpenAI 兼容平台）
 type VideoPriceConfig struct {
-	PricePerRequest   *float64 // 标准质量单次请求价格
-	PricePerRequestHD *float64 // 高清质量单次请求价格
+	PricePerRequest   *money.Amount // 标准质量单次请求价格
+	PricePerRequestHD *money.Amount // 高清质量单次请求价格
+}
+
+// AudioPriceConfig 音频计费配置（OpenAI 兼容平台的转写/翻译/语音合成）
+type AudioPriceConfig struct {
+	PricePerMinute        *money.Amount // 转写/翻译每分钟价格
+	SpeechPricePer1MChars *money.Amount // 语音合成每百万字符价格
 }
 
+// 音频默认价格（LiteLLM 与分组均未配置时使用，参考 whisper-1 / tts-1 官方价格）
+const (
+	defaultAudioPricePerMinute        = 0.006
+	defaultAudioSpeechPricePer1MChars = 15.0
+)
+
 // SoraPriceConfig Sora 按次计费配置
 type SoraPriceConfig struct {
-	ImagePrice360          *float64
-	ImagePrice540          *float64
-	VideoPricePerRequest   *float64
-	VideoPricePerRequestHD *float64
+	ImagePrice360          *money.Amount
+	ImagePrice540          *money.Amount
+	VideoPricePerRequest   *money.Amount
+	VideoPricePerRequestHD *money.Amount
 }
 
 // CalculateImageCost 计算图片生成费用
@@ -634,13 +677,13 @@ func (s *BillingService) CalculateImageCost(model string, imageSize string, imag
 	unitPrice := s.getImageUnitPrice(model, imageSize, groupConfig)
 
 	// 计算总费用
-	totalCost := unitPrice * float64(imageCount)
+	totalCost := unitPrice.Mul(float64(imageCount))
 
 	// 应用倍率
 	if rateMultiplier <= 0 {
 		rateMultiplier = 1.0
 	}
-	actualCost := totalCost * rateMultiplier
+	actualCost := totalCost.Mul(rateMultiplier)
 
 	return &CostBreakdown{
 		TotalCost:  totalCost,
@@ -654,7 +697,7 @@ func (s *BillingService) CalculateSoraImageCost(imageSize string, imageCount int
 		return &CostBreakdown{}
 	}
 
-	unitPrice := 0.0
+	var unitPrice money.Amount
 	if groupConfig != nil {
 		switch imageSize {
 		case "540":
@@ -668,11 +711,11 @@ func (s *BillingService) CalculateSoraImageCost(imageSize string, imageCount int
 		}
 	}
 
-	totalCost := unitPrice * float64(imageCount)
+	totalCost := unitPrice.Mul(float64(imageCount))
 	if rateMultiplier <= 0 {
 		rateMultiplier = 1.0
 	}
-	actualCost := totalCost * rateMultiplier
+	actualCost := totalCost.Mul(rateMultiplier)
 
 	return &CostBreakdown{
 		TotalCost:  totalCost,
@@ -691,7 +734,7 @@ func (s *BillingService) CalculateVideoCost(model string, quality string, videoC
 		return &CostBreakdown{}
 	}
 
-	unitPrice := 0.0
+	var unitPrice money.Amount
 	if groupConfig != nil {
 		if quality == "high" && groupConfig.PricePerRequestHD != nil {
 			unitPrice = *groupConfig.PricePerRequestHD
@@ -706,25 +749,25 @@ func (s *BillingService) CalculateVideoCost(model string, quality string, videoC
 		modelLower := strings.ToLower(model)
 		if strings.Contains(modelLower, "grok") && strings.Contains(modelLower, "video") {
 			if quality == "high" {
-				unitPrice = 0.20 // 默认高清价格
+				unitPrice = money.MustParse("0.20") // 默认高清价格
 			} else {
-				unitPrice = 0.10 // 默认标准价格
+				unitPrice = money.MustParse("0.10") // 默认标准价格
 			}
 		} else {
 			// 其他模型的兜底价格
 			if quality == "high" {
-				unitPrice = 0.15
+				unitPrice = money.MustParse("0.15")
 			} else {
-				unitPrice = 0.08
+				unitPrice = money.MustParse("0.08")
 			}
 		}
 	}
 
-	totalCost := unitPrice * float64(videoCount)
+	totalCost := unitPrice.Mul(float64(videoCount))
 	if rateMultiplier <= 0 {
 		rateMultiplier = 1.0
 	}
-	actualCost := totalCost * rateMultiplier
+	actualCost := totalCost.Mul(rateMultiplier)
 
 	return &CostBreakdown{
 		TotalCost:  totalCost,
@@ -734,7 +777,7 @@ func (s *BillingService) CalculateVideoCost(model string, quality string, videoC
 
 // CalculateSoraVideoCost 计算 Sora 视频按次费用
 func (s *BillingService) CalculateSoraVideoCost(model string, groupConfig *SoraPriceConfig, rateMultiplier float64) *CostBreakdown {
-	unitPrice := 0.0
+	var unitPrice money.Amount
 	if groupConfig != nil {
 		modelLower := strings.ToLower(model)
 		if strings.Contains(modelLower, "sora2pro-hd") {
@@ -751,7 +794,7 @@ func (s *BillingService) CalculateSoraVideoCost(model string, groupConfig *SoraP
 	if rateMultiplier <= 0 {
 		rateMultiplier = 1.0
 	}
-	actualCost := totalCost * rateMultiplier
+	actualCost := totalCost.Mul(rateMultiplier)
 
 	return &CostBreakdown{
 		TotalCost:  totalCost,
@@ -759,8 +802,79 @@ func (s *BillingService) CalculateSoraVideoCost(model string, groupConfig *SoraP
 	}
 }
 
+// CalculateAudioTranscriptionCost 计算音频转写/翻译费用（按音频时长计费）
+// durationSeconds: 输入音频时长（秒）
+// groupConfig: 分组配置的价格（可能为 nil，表示使用默认值）
+func (s *BillingService) CalculateAudioTranscriptionCost(model string, durationSeconds float64, groupConfig *AudioPriceConfig, rateMultiplier float64) *CostBreakdown {
+	if durationSeconds <= 0 {
+		return &CostBreakdown{}
+	}
+
+	// 按分钟计价时先按秒数精确相乘再除以 60，避免单价换算引入的浮点误差
+	var totalCost money.Amount
+	if groupConfig != nil && groupConfig.PricePerMinute != nil {
+		totalCost = groupConfig.PricePerMinute.Mul(durationSeconds).Div(60)
+	} else {
+		priced := false
+		if s.pricingService != nil {
+			if pricing := s.pricingService.GetModelPricing(model); pricing != nil && pricing.InputCostPerSecond > 0 {
+				totalCost = money.Price(pricing.InputCostPerSecond, durationSeconds)
+				priced = true
+			}
+		}
+		if !priced {
+			totalCost = money.Price(defaultAudioPricePerMinute, durationSeconds).Div(60)
+		}
+	}
+
+	if rateMultiplier <= 0 {
+		rateMultiplier = 1.0
+	}
+
+	return &CostBreakdown{
+		InputCost:  totalCost,
+		TotalCost:  totalCost,
+		ActualCost: totalCost.Mul(rateMultiplier),
+	}
+}
+
+// CalculateAudioSpeechCost 计算语音合成费用（按输入字符数计费）
+// characters: 输入文本字符数
+// groupConfig: 分组配置的价格（可能为 nil，表示使用默认值）
+func (s *BillingService) CalculateAudioSpeechCost(model string, characters int, groupConfig *AudioPriceConfig, rateMultiplier float64) *CostBreakdown {
+	if characters <= 0 {
+		return &CostBreakdown{}
+	}
+
+	var totalCost money.Amount
+	if groupConfig != nil && groupConfig.SpeechPricePer1MChars != nil {
+		totalCost = groupConfig.SpeechPricePer1MChars.Mul(float64(characters)).Div(1_000_000)
+	} else {
+		priced := false
+		if s.pricingService != nil {
+			if pricing := s.pricingService.GetModelPricing(model); pricing != nil && pricing.InputCostPerCharacter > 0 {
+				totalCost = money.Price(pricing.InputCostPerCharacter, float64(characters))
+				priced = true
+			}
+		}
+		if !priced {
+			totalCost = money.Price(defaultAudioSpeechPricePer1MChars, float64(characters)).Div(1_000_000)
+		}
+	}
+
+	if rateMultiplier <= 0 {
+		rateMultiplier = 1.0
+	}
+
+	return &CostBreakdown{
+		InputCost:  totalCost,
+		TotalCost:  totalCost,
+		ActualCost: totalCost.Mul(rateMultiplier),
+	}
+}
+
 // getImageUnitPrice 获取图片单价
-func (s *BillingService) getImageUnitPrice(model string, imageSize string, groupConfig *ImagePriceConfig) float64 {
+func (s *BillingService) getImageUnitPrice(model string, imageSize string, groupConfig *ImagePriceConfig) money.Amount {
 	// 优先使用分组配置的价格
 	if groupConfig != nil {
 		if groupConfig.PricePerImage != nil {
@@ -783,7 +897,7 @@ func (s *BillingService) getImageUnitPrice(model string, imageSize string, group
 	}
 
 	// 回退到 LiteLLM 默认价格
-	return s.getDefaultImagePrice(model, imageSize)
+	return money.FromFloat(s.getDefaultImagePrice(model, imageSize))
 }
 
 // getDefaultImagePrice 获取 LiteLLM 默认图片价格
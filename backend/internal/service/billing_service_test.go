@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/Wei-Shaw/sub2api/internal/config"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
 	"github.com/stretchr/testify/require"
 )
 
@@ -28,10 +29,10 @@ func TestCalculateCost_BasicComputation(t *testing.T) {
 	// 1000 * 3e-6 = 0.003, 500 * 15e-6 = 0.0075
 	expectedInput := 1000 * 3e-6
 	expectedOutput := 500 * 15e-6
-	require.InDelta(t, expectedInput, cost.InputCost, 1e-10)
-	require.InDelta(t, expectedOutput, cost.OutputCost, 1e-10)
-	require.InDelta(t, expectedInput+expectedOutput, cost.TotalCost, 1e-10)
-	require.InDelta(t, expectedInput+expectedOutput, cost.ActualCost, 1e-10)
+	require.InDelta(t, expectedInput, cost.InputCost.Float64(), 1e-10)
+	require.InDelta(t, expectedOutput, cost.OutputCost.Float64(), 1e-10)
+	require.InDelta(t, expectedInput+expectedOutput, cost.TotalCost.Float64(), 1e-10)
+	require.InDelta(t, expectedInput+expectedOutput, cost.ActualCost.Float64(), 1e-10)
 }
 
 func TestCalculateCost_WithCacheTokens(t *testing.T) {
@@ -48,11 +49,11 @@ func TestCalculateCost_WithCacheTokens(t *testing.T) {
 
 	expectedCacheCreation := 2000 * 3.75e-6
 	expectedCacheRead := 3000 * 0.3e-6
-	require.InDelta(t, expectedCacheCreation, cost.CacheCreationCost, 1e-10)
-	require.InDelta(t, expectedCacheRead, cost.CacheReadCost, 1e-10)
+	require.InDelta(t, expectedCacheCreation, cost.CacheCreationCost.Float64(), 1e-10)
+	require.InDelta(t, expectedCacheRead, cost.CacheReadCost.Float64(), 1e-10)
 
-	expectedTotal := cost.InputCost + cost.OutputCost + expectedCacheCreation + expectedCacheRead
-	require.InDelta(t, expectedTotal, cost.TotalCost, 1e-10)
+	expectedTotal := cost.InputCost.Float64() + cost.OutputCost.Float64() + expectedCacheCreation + expectedCacheRead
+	require.InDelta(t, expectedTotal, cost.TotalCost.Float64(), 1e-10)
 }
 
 func TestCalculateCost_RateMultiplier(t *testing.T) {
@@ -67,8 +68,8 @@ func TestCalculateCost_RateMultiplier(t *testing.T) {
 	require.NoError(t, err)
 
 	// TotalCost 不受倍率影响，ActualCost 翻倍
-	require.InDelta(t, cost1x.TotalCost, cost2x.TotalCost, 1e-10)
-	require.InDelta(t, cost1x.ActualCost*2, cost2x.ActualCost, 1e-10)
+	require.InDelta(t, cost1x.TotalCost.Float64(), cost2x.TotalCost.Float64(), 1e-10)
+	require.InDelta(t, cost1x.ActualCost.Float64()*2, cost2x.ActualCost.Float64(), 1e-10)
 }
 
 func TestCalculateCost_ZeroMultiplierDefaultsToOne(t *testing.T) {
@@ -82,7 +83,7 @@ func TestCalculateCost_ZeroMultiplierDefaultsToOne(t *testing.T) {
 	costOne, err := svc.CalculateCost("claude-sonnet-4", tokens, 1.0)
 	require.NoError(t, err)
 
-	require.InDelta(t, costOne.ActualCost, costZero.ActualCost, 1e-10)
+	require.InDelta(t, costOne.ActualCost.Float64(), costZero.ActualCost.Float64(), 1e-10)
 }
 
 func TestCalculateCost_NegativeMultiplierDefaultsToOne(t *testing.T) {
@@ -96,7 +97,7 @@ func TestCalculateCost_NegativeMultiplierDefaultsToOne(t *testing.T) {
 	costOne, err := svc.CalculateCost("claude-sonnet-4", tokens, 1.0)
 	require.NoError(t, err)
 
-	require.InDelta(t, costOne.ActualCost, costNeg.ActualCost, 1e-10)
+	require.InDelta(t, costOne.ActualCost.Float64(), costNeg.ActualCost.Float64(), 1e-10)
 }
 
 func TestGetModelPricing_FallbackMatchesByFamily(t *testing.T) {
@@ -126,9 +127,9 @@ func TestCalculateCost_DoubaoBuiltinPricing(t *testing.T) {
 	tokens := UsageTokens{InputTokens: 1000, OutputTokens: 1000}
 	cost, err := svc.CalculateCost("doubao-seed-2.0-pro", tokens, 1.0)
 	require.NoError(t, err)
-	require.InDelta(t, 0.0032, cost.InputCost, 1e-10)
-	require.InDelta(t, 0.016, cost.OutputCost, 1e-10)
-	require.Greater(t, cost.ActualCost, 0.0)
+	require.InDelta(t, 0.0032, cost.InputCost.Float64(), 1e-10)
+	require.InDelta(t, 0.016, cost.OutputCost.Float64(), 1e-10)
+	require.Greater(t, cost.ActualCost.Float64(), 0.0)
 }
 
 func TestGetModelPricing_CaseInsensitive(t *testing.T) {
@@ -167,7 +168,7 @@ func TestCalculateCostWithLongContext_BelowThreshold(t *testing.T) {
 	normalCost, err := svc.CalculateCost("claude-sonnet-4", tokens, 1.0)
 	require.NoError(t, err)
 
-	require.InDelta(t, normalCost.ActualCost, cost.ActualCost, 1e-10)
+	require.InDelta(t, normalCost.ActualCost.Float64(), cost.ActualCost.Float64(), 1e-10)
 }
 
 func TestCalculateCostWithLongContext_AboveThreshold_CacheExceedsThreshold(t *testing.T) {
@@ -196,7 +197,7 @@ func TestCalculateCostWithLongContext_AboveThreshold_CacheExceedsThreshold(t *te
 		CacheReadTokens: 10000,
 	}, 2.0)
 
-	require.InDelta(t, inRange.ActualCost+outRange.ActualCost, cost.ActualCost, 1e-10)
+	require.InDelta(t, inRange.ActualCost.Float64()+outRange.ActualCost.Float64(), cost.ActualCost.Float64(), 1e-10)
 }
 
 func TestCalculateCostWithLongContext_AboveThreshold_CacheBelowThreshold(t *testing.T) {
@@ -231,7 +232,7 @@ func TestCalculateCostWithLongContext_DisabledThreshold(t *testing.T) {
 	cost2, err := svc.CalculateCost("claude-sonnet-4", tokens, 1.0)
 	require.NoError(t, err)
 
-	require.InDelta(t, cost2.ActualCost, cost1.ActualCost, 1e-10)
+	require.InDelta(t, cost2.ActualCost.Float64(), cost1.ActualCost.Float64(), 1e-10)
 }
 
 func TestCalculateCostWithLongContext_ExtraMultiplierLessEqualOne(t *testing.T) {
@@ -246,41 +247,41 @@ func TestCalculateCostWithLongContext_ExtraMultiplierLessEqualOne(t *testing.T)
 	normalCost, err := svc.CalculateCost("claude-sonnet-4", tokens, 1.0)
 	require.NoError(t, err)
 
-	require.InDelta(t, normalCost.ActualCost, cost.ActualCost, 1e-10)
+	require.InDelta(t, normalCost.ActualCost.Float64(), cost.ActualCost.Float64(), 1e-10)
 }
 
 func TestCalculateImageCost(t *testing.T) {
 	svc := newTestBillingService()
 
-	price := 0.134
+	price := money.MustParse("0.134")
 	cfg := &ImagePriceConfig{Price1K: &price}
 	cost := svc.CalculateImageCost("gpt-image-1", "1K", 3, cfg, 1.0)
 
-	require.InDelta(t, 0.134*3, cost.TotalCost, 1e-10)
-	require.InDelta(t, 0.134*3, cost.ActualCost, 1e-10)
+	require.InDelta(t, 0.134*3, cost.TotalCost.Float64(), 1e-10)
+	require.InDelta(t, 0.134*3, cost.ActualCost.Float64(), 1e-10)
 }
 
 func TestCalculateSoraVideoCost(t *testing.T) {
 	svc := newTestBillingService()
 
-	price := 0.5
+	price := money.MustParse("0.5")
 	cfg := &SoraPriceConfig{VideoPricePerRequest: &price}
 	cost := svc.CalculateSoraVideoCost("sora-video", cfg, 1.0)
 
-	require.InDelta(t, 0.5, cost.TotalCost, 1e-10)
+	require.InDelta(t, 0.5, cost.TotalCost.Float64(), 1e-10)
 }
 
 func TestCalculateSoraVideoCost_HDModel(t *testing.T) {
 	svc := newTestBillingService()
 
-	hdPrice := 1.0
-	normalPrice := 0.5
+	hdPrice := money.MustParse("1.0")
+	normalPrice := money.MustParse("0.5")
 	cfg := &SoraPriceConfig{
 		VideoPricePerRequest:   &normalPrice,
 		VideoPricePerRequestHD: &hdPrice,
 	}
 	cost := svc.CalculateSoraVideoCost("sora2pro-hd", cfg, 1.0)
-	require.InDelta(t, 1.0, cost.TotalCost, 1e-10)
+	require.InDelta(t, 1.0, cost.TotalCost.Float64(), 1e-10)
 }
 
 func TestIsModelSupported(t *testing.T) {
@@ -298,8 +299,8 @@ func TestCalculateCost_ZeroTokens(t *testing.T) {
 
 	cost, err := svc.CalculateCost("claude-sonnet-4", UsageTokens{}, 1.0)
 	require.NoError(t, err)
-	require.Equal(t, 0.0, cost.TotalCost)
-	require.Equal(t, 0.0, cost.ActualCost)
+	require.Zero(t, cost.TotalCost)
+	require.Zero(t, cost.ActualCost)
 }
 
 func TestCalculateCostWithConfig(t *testing.T) {
@@ -312,7 +313,7 @@ func TestCalculateCostWithConfig(t *testing.T) {
 	require.NoError(t, err)
 
 	expected, _ := svc.CalculateCost("claude-sonnet-4", tokens, 1.5)
-	require.InDelta(t, expected.ActualCost, cost.ActualCost, 1e-10)
+	require.InDelta(t, expected.ActualCost.Float64(), cost.ActualCost.Float64(), 1e-10)
 }
 
 func TestCalculateCostWithConfig_ZeroMultiplier(t *testing.T) {
@@ -326,7 +327,7 @@ func TestCalculateCostWithConfig_ZeroMultiplier(t *testing.T) {
 
 	// 倍率 <=0 时默认 1.0
 	expected, _ := svc.CalculateCost("claude-sonnet-4", tokens, 1.0)
-	require.InDelta(t, expected.ActualCost, cost.ActualCost, 1e-10)
+	require.InDelta(t, expected.ActualCost.Float64(), cost.ActualCost.Float64(), 1e-10)
 }
 
 func TestGetEstimatedCost(t *testing.T) {
@@ -364,28 +365,28 @@ func TestForceUpdatePricing_NilService(t *testing.T) {
 func TestCalculateSoraImageCost(t *testing.T) {
 	svc := newTestBillingService()
 
-	price360 := 0.05
-	price540 := 0.08
+	price360 := money.MustParse("0.05")
+	price540 := money.MustParse("0.08")
 	cfg := &SoraPriceConfig{ImagePrice360: &price360, ImagePrice540: &price540}
 
 	cost := svc.CalculateSoraImageCost("360", 2, cfg, 1.0)
-	require.InDelta(t, 0.10, cost.TotalCost, 1e-10)
+	require.InDelta(t, 0.10, cost.TotalCost.Float64(), 1e-10)
 
 	cost540 := svc.CalculateSoraImageCost("540", 1, cfg, 2.0)
-	require.InDelta(t, 0.08, cost540.TotalCost, 1e-10)
-	require.InDelta(t, 0.16, cost540.ActualCost, 1e-10)
+	require.InDelta(t, 0.08, cost540.TotalCost.Float64(), 1e-10)
+	require.InDelta(t, 0.16, cost540.ActualCost.Float64(), 1e-10)
 }
 
 func TestCalculateSoraImageCost_ZeroCount(t *testing.T) {
 	svc := newTestBillingService()
 	cost := svc.CalculateSoraImageCost("360", 0, nil, 1.0)
-	require.Equal(t, 0.0, cost.TotalCost)
+	require.Zero(t, cost.TotalCost)
 }
 
 func TestCalculateSoraVideoCost_NilConfig(t *testing.T) {
 	svc := newTestBillingService()
 	cost := svc.CalculateSoraVideoCost("sora-video", nil, 1.0)
-	require.Equal(t, 0.0, cost.TotalCost)
+	require.Zero(t, cost.TotalCost)
 }
 
 func TestCalculateCostWithLongContext_PropagatesError(t *testing.T) {
@@ -426,7 +427,7 @@ func TestCalculateCost_SupportsCacheBreakdown(t *testing.T) {
 
 	expected5m := float64(tokens.CacheCreation5mTokens) * 4e-6
 	expected1h := float64(tokens.CacheCreation1hTokens) * 5e-6
-	require.InDelta(t, expected5m+expected1h, cost.CacheCreationCost, 1e-10)
+	require.InDelta(t, expected5m+expected1h, cost.CacheCreationCost.Float64(), 1e-10)
 }
 
 func TestCalculateCost_LargeTokenCount(t *testing.T) {
@@ -440,8 +441,8 @@ func TestCalculateCost_LargeTokenCount(t *testing.T) {
 	require.NoError(t, err)
 
 	// Input: 1M * 3e-6 = $3, Output: 1M * 15e-6 = $15
-	require.InDelta(t, 3.0, cost.InputCost, 1e-6)
-	require.InDelta(t, 15.0, cost.OutputCost, 1e-6)
-	require.False(t, math.IsNaN(cost.TotalCost))
-	require.False(t, math.IsInf(cost.TotalCost, 0))
+	require.InDelta(t, 3.0, cost.InputCost.Float64(), 1e-6)
+	require.InDelta(t, 15.0, cost.OutputCost.Float64(), 1e-6)
+	require.False(t, math.IsNaN(cost.TotalCost.Float64()))
+	require.False(t, math.IsInf(cost.TotalCost.Float64(), 0))
 }
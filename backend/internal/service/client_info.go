@@ -0,0 +1,86 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Wei-Shaw/sub2api/internal/pkg/ctxkey"
+)
+
+// maxUserAgentLength 入库/入缓存的 User-Agent 最大长度
+const maxUserAgentLength = 512
+
+// ClientInfo 发起登录或刷新 Token 的客户端信息
+type ClientInfo struct {
+	IP        string
+	UserAgent string
+}
+
+// WithClientInfo 将客户端信息写入 context，供会话与登录记录使用
+func WithClientInfo(ctx context.Context, info ClientInfo) context.Context {
+	if len(info.UserAgent) > maxUserAgentLength {
+		info.UserAgent = info.UserAgent[:maxUserAgentLength]
+	}
+	return context.WithValue(ctx, ctxkey.ClientInfo, info)
+}
+
+// ClientInfoFromContext 读取客户端信息，未设置时返回零值
+func ClientInfoFromContext(ctx context.Context) ClientInfo {
+	if ctx == nil {
+		return ClientInfo{}
+	}
+	info, _ := ctx.Value(ctxkey.ClientInfo).(ClientInfo)
+	return info
+}
+
+// DescribeDevice 从 User-Agent 粗略识别浏览器与操作系统，例如 "Chrome on macOS"。
+// 仅用于展示与新设备判断，不做精确解析。
+func DescribeDevice(userAgent string) string {
+	ua := strings.ToLower(userAgent)
+	if strings.TrimSpace(ua) == "" {
+		return "Unknown"
+	}
+
+	var browser string
+	switch {
+	case strings.Contains(ua, "edg/") || strings.Contains(ua, "edga/") || strings.Contains(ua, "edgios/"):
+		browser = "Edge"
+	case strings.Contains(ua, "opr/") || strings.Contains(ua, "opera"):
+		browser = "Opera"
+	case strings.Contains(ua, "firefox/") || strings.Contains(ua, "fxios/"):
+		browser = "Firefox"
+	case strings.Contains(ua, "chrome/") || strings.Contains(ua, "crios/") || strings.Contains(ua, "chromium/"):
+		browser = "Chrome"
+	case strings.Contains(ua, "safari/"):
+		browser = "Safari"
+	case strings.Contains(ua, "curl/"):
+		browser = "curl"
+	case strings.Contains(ua, "python"):
+		browser = "Python"
+	case strings.Contains(ua, "go-http-client"):
+		browser = "Go"
+	default:
+		browser = "Unknown browser"
+	}
+
+	var os string
+	switch {
+	case strings.Contains(ua, "iphone") || strings.Contains(ua, "ipad") || strings.Contains(ua, "ipod"):
+		os = "iOS"
+	case strings.Contains(ua, "android"):
+		os = "Android"
+	case strings.Contains(ua, "windows"):
+		os = "Windows"
+	case strings.Contains(ua, "mac os x") || strings.Contains(ua, "macintosh"):
+		os = "macOS"
+	case strings.Contains(ua, "cros"):
+		os = "ChromeOS"
+	case strings.Contains(ua, "linux"):
+		os = "Linux"
+	}
+
+	if os == "" {
+		return browser
+	}
+	return browser + " on " + os
+}
@@ -37,6 +37,15 @@ type ConcurrencyCache interface {
 	IncrementWaitCount(ctx context.Context, userID int64, maxWait int) (bool, error)
 	DecrementWaitCount(ctx context.Context, userID int64) error
 
+	// API Key 槽位管理（API Key 级并发上限）
+	// 键格式: concurrency:api_key:{apiKeyID}（有序集合，成员为 requestID）
+	AcquireAPIKeySlot(ctx context.Context, apiKeyID int64, maxConcurrency int, requestID string) (bool, error)
+	ReleaseAPIKeySlot(ctx context.Context, apiKeyID int64, requestID string) error
+
+	// API Key 等待队列计数
+	IncrementAPIKeyWaitCount(ctx context.Context, apiKeyID int64, maxWait int) (bool, error)
+	DecrementAPIKeyWaitCount(ctx context.Context, apiKeyID int64) error
+
 	// 批量负载查询（只读）
 	GetAccountsLoadBatch(ctx context.Context, accounts []AccountWithConcurrency) (map[int64]*AccountLoadInfo, error)
 	GetUsersLoadBatch(ctx context.Context, users []UserWithConcurrency) (map[int64]*UserLoadInfo, error)
@@ -189,6 +198,44 @@ func (s *ConcurrencyService) AcquireUserSlot(ctx context.Context, userID int64,
 	}, nil
 }
 
+// AcquireAPIKeySlot attempts to acquire a concurrency slot for an API key.
+// Keys without max_concurrency are not limited.
+// Returns a release function that MUST be called when the request completes.
+func (s *ConcurrencyService) AcquireAPIKeySlot(ctx context.Context, apiKeyID int64, maxConcurrency int) (*AcquireResult, error) {
+	// If maxConcurrency is 0 or negative, no limit
+	if maxConcurrency <= 0 {
+		return &AcquireResult{
+			Acquired:    true,
+			ReleaseFunc: func() {}, // no-op
+		}, nil
+	}
+
+	requestID := generateRequestID()
+
+	acquired, err := s.cache.AcquireAPIKeySlot(ctx, apiKeyID, maxConcurrency, requestID)
+	if err != nil {
+		return nil, err
+	}
+
+	if acquired {
+		return &AcquireResult{
+			Acquired: true,
+			ReleaseFunc: func() {
+				bgCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+				defer cancel()
+				if err := s.cache.ReleaseAPIKeySlot(bgCtx, apiKeyID, requestID); err != nil {
+					logger.LegacyPrintf("service.concurrency", "Warning: failed to release api key slot for %d (req=%s): %v", apiKeyID, requestID, err)
+				}
+			},
+		}, nil
+	}
+
+	return &AcquireResult{
+		Acquired:    false,
+		ReleaseFunc: nil,
+	}, nil
+}
+
 // ============================================
 // Wait Queue Count Methods
 // ============================================
@@ -255,6 +302,34 @@ func (s *ConcurrencyService) DecrementAccountWaitCount(ctx context.Context, acco
 	}
 }
 
+// IncrementAPIKeyWaitCount increments the wait queue counter for an API key.
+func (s *ConcurrencyService) IncrementAPIKeyWaitCount(ctx context.Context, apiKeyID int64, maxWait int) (bool, error) {
+	if s.cache == nil {
+		return true, nil
+	}
+
+	result, err := s.cache.IncrementAPIKeyWaitCount(ctx, apiKeyID, maxWait)
+	if err != nil {
+		logger.LegacyPrintf("service.concurrency", "Warning: increment wait count failed for api key %d: %v", apiKeyID, err)
+		return true, nil
+	}
+	return result, nil
+}
+
+// DecrementAPIKeyWaitCount decrements the wait queue counter for an API key.
+func (s *ConcurrencyService) DecrementAPIKeyWaitCount(ctx context.Context, apiKeyID int64) {
+	if s.cache == nil {
+		return
+	}
+
+	bgCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := s.cache.DecrementAPIKeyWaitCount(bgCtx, apiKeyID); err != nil {
+		logger.LegacyPrintf("service.concurrency", "Warning: decrement wait count failed for api key %d: %v", apiKeyID, err)
+	}
+}
+
 // GetAccountWaitingCount gets current wait queue count for an account.
 func (s *ConcurrencyService) GetAccountWaitingCount(ctx context.Context, accountID int64) (int, error) {
 	if s.cache == nil {
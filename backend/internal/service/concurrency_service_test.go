@@ -81,6 +81,19 @@ func (c *stubConcurrencyCacheForTest) IncrementWaitCount(_ context.Context, _ in
 func (c *stubConcurrencyCacheForTest) DecrementWaitCount(_ context.Context, _ int64) error {
 	return nil
 }
+func (c *stubConcurrencyCacheForTest) AcquireAPIKeySlot(_ context.Context, _ int64, _ int, _ string) (bool, error) {
+	return c.acquireResult, c.acquireErr
+}
+func (c *stubConcurrencyCacheForTest) ReleaseAPIKeySlot(_ context.Context, _ int64, requestID string) error {
+	c.releasedRequestIDs = append(c.releasedRequestIDs, requestID)
+	return c.releaseErr
+}
+func (c *stubConcurrencyCacheForTest) IncrementAPIKeyWaitCount(_ context.Context, _ int64, _ int) (bool, error) {
+	return c.waitAllowed, c.waitErr
+}
+func (c *stubConcurrencyCacheForTest) DecrementAPIKeyWaitCount(_ context.Context, _ int64) error {
+	return nil
+}
 func (c *stubConcurrencyCacheForTest) GetAccountsLoadBatch(_ context.Context, _ []AccountWithConcurrency) (map[int64]*AccountLoadInfo, error) {
 	return c.loadBatch, c.loadBatchErr
 }
@@ -167,6 +180,45 @@ func TestAcquireUserSlot_UnlimitedConcurrency(t *testing.T) {
 	require.True(t, result.Acquired)
 }
 
+func TestAcquireAPIKeySlot_ReleaseUsesRequestID(t *testing.T) {
+	cache := &stubConcurrencyCacheForTest{acquireResult: true}
+	svc := NewConcurrencyService(cache)
+
+	result, err := svc.AcquireAPIKeySlot(context.Background(), 7, 2)
+	require.NoError(t, err)
+	require.True(t, result.Acquired)
+
+	result.ReleaseFunc()
+	require.Len(t, cache.releasedRequestIDs, 1)
+	require.NotEmpty(t, cache.releasedRequestIDs[0])
+}
+
+func TestAcquireAPIKeySlot_Full(t *testing.T) {
+	svc := NewConcurrencyService(&stubConcurrencyCacheForTest{acquireResult: false})
+
+	result, err := svc.AcquireAPIKeySlot(context.Background(), 7, 2)
+	require.NoError(t, err)
+	require.False(t, result.Acquired)
+	require.Nil(t, result.ReleaseFunc)
+}
+
+func TestAcquireAPIKeySlot_UnlimitedConcurrency(t *testing.T) {
+	// 未配置 max_concurrency 时不访问缓存
+	svc := NewConcurrencyService(&stubConcurrencyCacheForTest{acquireErr: errors.New("should not be called")})
+
+	result, err := svc.AcquireAPIKeySlot(context.Background(), 7, 0)
+	require.NoError(t, err)
+	require.True(t, result.Acquired)
+}
+
+func TestIncrementAPIKeyWaitCount_FailOpen(t *testing.T) {
+	svc := NewConcurrencyService(&stubConcurrencyCacheForTest{waitErr: errors.New("redis timeout")})
+
+	allowed, err := svc.IncrementAPIKeyWaitCount(context.Background(), 7, 21)
+	require.NoError(t, err)
+	require.True(t, allowed)
+}
+
 func TestGenerateRequestID_UsesStablePrefixAndMonotonicCounter(t *testing.T) {
 	id1 := generateRequestID()
 	id2 := generateRequestID()
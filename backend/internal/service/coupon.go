@@ -0,0 +1,206 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	infraerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
+)
+
+// 优惠方式
+const (
+	CouponDiscountPercent = "percent" // 按比例折扣
+	CouponDiscountFixed   = "fixed"   // 固定金额立减
+)
+
+// 优惠券适用范围
+const (
+	CouponScopeAll          = "all"          // 所有商品
+	CouponScopeBalance      = "balance"      // 余额充值包
+	CouponScopeSubscription = "subscription" // 订阅套餐
+	CouponScopeProduct      = "product"      // 指定商品（scope_id 为商品 ID）
+	CouponScopeGroup        = "group"        // 指定分组的订阅套餐（scope_id 为分组 ID）
+)
+
+// 用户优惠券状态；过期不单独存储，以 expires_at 判断
+//
+//	unused ──下单──> locked ──订单发放──> used
+//	  ^                 └──订单关闭──┘
+const (
+	UserCouponStatusUnused = "unused"
+	UserCouponStatusLocked = "locked" // 已被待支付订单占用
+	UserCouponStatusUsed   = "used"
+
+	// UserCouponStatusExpired 仅用于查询与展示：未使用且已过期
+	UserCouponStatusExpired = "expired"
+)
+
+// 优惠券发放来源
+const (
+	CouponSourceActivity = "activity" // 活动奖励，source_ref 为活动 ID
+	CouponSourceAdmin    = "admin"    // 管理员发放，source_ref 为管理员 ID
+)
+
+var (
+	ErrCouponNotFound        = infraerrors.NotFound("COUPON_NOT_FOUND", "coupon not found")
+	ErrCouponDisabled        = infraerrors.BadRequest("COUPON_DISABLED", "coupon is disabled or expired")
+	ErrUserCouponNotFound    = infraerrors.NotFound("USER_COUPON_NOT_FOUND", "coupon not found")
+	ErrUserCouponUnavailable = infraerrors.Conflict("USER_COUPON_UNAVAILABLE", "coupon has been used, is in use by another order, or has expired")
+	ErrCouponNotApplicable   = infraerrors.BadRequest("COUPON_NOT_APPLICABLE", "coupon does not apply to this product")
+)
+
+// Coupon 优惠券模板
+type Coupon struct {
+	ID           int64
+	Name         string
+	Description  string
+	DiscountType string
+	// PercentOff percent 优惠的折扣百分比（1-99），如 20 表示减 20%
+	PercentOff int
+	// AmountOffCents / Currency fixed 优惠的立减金额（最小货币单位）与币种
+	AmountOffCents int64
+	Currency       string
+	// MinAmountCents 订单原价门槛
+	MinAmountCents int64
+	Scope          string
+	ScopeID        *int64
+	// ValidDays 发放后有效天数，0 表示不限；与 ExpiresAt 同时设置时取较早者
+	ValidDays int
+	ExpiresAt *time.Time
+	Enabled   bool
+	CreatedAt time.Time
+	UpdatedAt time.Time
+
+	// IssuedCount / UsedCount 仅在管理端列表中填充
+	IssuedCount int64
+	UsedCount   int64
+}
+
+// UserCoupon 用户持有的优惠券
+type UserCoupon struct {
+	ID        int64
+	CouponID  int64
+	UserID    int64
+	Status    string
+	Source    string
+	SourceRef string
+	ExpiresAt *time.Time
+	// OrderNo 锁定或核销该券的支付订单
+	OrderNo   string
+	UsedAt    *time.Time
+	CreatedAt time.Time
+
+	Coupon    *Coupon
+	UserEmail string
+}
+
+// UserCouponFilter 用户优惠券查询条件
+type UserCouponFilter struct {
+	UserID   int64
+	CouponID int64
+	// Status unused（未过期）/locked/used/expired
+	Status string
+}
+
+// CouponQuote 优惠券用于某个商品时的价格
+type CouponQuote struct {
+	UserCoupon    *UserCoupon
+	OriginalCents int64
+	DiscountCents int64
+	AmountCents   int64
+	Currency      string
+}
+
+type CouponRepository interface {
+	Create(ctx context.Context, coupon *Coupon) error
+	Update(ctx context.Context, coupon *Coupon) error
+	Delete(ctx context.Context, id int64) error
+	GetByID(ctx context.Context, id int64) (*Coupon, error)
+	List(ctx context.Context, params pagination.PaginationParams) ([]Coupon, *pagination.PaginationResult, error)
+
+	Issue(ctx context.Context, uc *UserCoupon) error
+	// GetUserCoupon 返回用户优惠券及其模板
+	GetUserCoupon(ctx context.Context, id int64) (*UserCoupon, error)
+	ListUserCoupons(ctx context.Context, params pagination.PaginationParams, filter UserCouponFilter) ([]UserCoupon, *pagination.PaginationResult, error)
+
+	// 以下状态迁移均为条件更新，返回 false 表示优惠券不处于可迁移状态
+	LockForOrder(ctx context.Context, id, userID int64, orderNo string, now time.Time) (bool, error)
+	// ReleaseOrder 将订单占用的优惠券退回未使用
+	ReleaseOrder(ctx context.Context, orderNo string) error
+	// ConsumeForOrder 核销：锁定在该订单上的券，或订单关闭后已退回但仍未使用的券
+	ConsumeForOrder(ctx context.Context, id int64, orderNo string) (bool, error)
+}
+
+// Usable 券未使用且未过期
+func (uc *UserCoupon) Usable(now time.Time) bool {
+	return uc.Status == UserCouponStatusUnused && !uc.Expired(now)
+}
+
+// Expired 券已过期
+func (uc *UserCoupon) Expired(now time.Time) bool {
+	return uc.ExpiresAt != nil && !now.Before(*uc.ExpiresAt)
+}
+
+// DisplayStatus 展示用状态，未使用但已过期时为 expired
+func (uc *UserCoupon) DisplayStatus(now time.Time) string {
+	if uc.Status == UserCouponStatusUnused && uc.Expired(now) {
+		return UserCouponStatusExpired
+	}
+	return uc.Status
+}
+
+// AppliesTo 优惠券是否适用于该商品
+func (c *Coupon) AppliesTo(p *PaymentProduct) bool {
+	if p.PriceCents < c.MinAmountCents {
+		return false
+	}
+	if c.DiscountType == CouponDiscountFixed && c.Currency != p.Currency {
+		return false
+	}
+	switch c.Scope {
+	case CouponScopeAll:
+		return true
+	case CouponScopeBalance:
+		return p.Kind == PaymentProductKindBalance
+	case CouponScopeSubscription:
+		return p.Kind == PaymentProductKindSubscription
+	case CouponScopeProduct:
+		return c.ScopeID != nil && *c.ScopeID == p.ID
+	case CouponScopeGroup:
+		return c.ScopeID != nil && p.GroupID != nil && *c.ScopeID == *p.GroupID
+	}
+	return false
+}
+
+// DiscountCents 计算优惠金额；实付至少保留 1 个最小货币单位，支付渠道不接受 0 元订单
+func (c *Coupon) DiscountCents(priceCents int64) int64 {
+	var discount int64
+	switch c.DiscountType {
+	case CouponDiscountPercent:
+		discount = priceCents * int64(c.PercentOff) / 100
+	case CouponDiscountFixed:
+		discount = c.AmountOffCents
+	}
+	if discount > priceCents-1 {
+		discount = priceCents - 1
+	}
+	if discount < 0 {
+		discount = 0
+	}
+	return discount
+}
+
+// issueExpiry 发放时间为 now 的券的过期时间
+func (c *Coupon) issueExpiry(now time.Time) *time.Time {
+	var expires *time.Time
+	if c.ValidDays > 0 {
+		t := now.AddDate(0, 0, c.ValidDays)
+		expires = &t
+	}
+	if c.ExpiresAt != nil && (expires == nil || c.ExpiresAt.Before(*expires)) {
+		t := *c.ExpiresAt
+		expires = &t
+	}
+	return expires
+}
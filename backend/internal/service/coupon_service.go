@@ -0,0 +1,237 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	infraerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
+)
+
+// CouponService 优惠券：模板管理、发放（活动奖励或管理员），以及下单时的试算、锁定与核销
+type CouponService struct {
+	repo      CouponRepository
+	userRepo  UserRepository
+	groupRepo GroupRepository
+}
+
+// NewCouponService 创建优惠券服务
+func NewCouponService(repo CouponRepository, userRepo UserRepository, groupRepo GroupRepository) *CouponService {
+	return &CouponService{repo: repo, userRepo: userRepo, groupRepo: groupRepo}
+}
+
+// ============================================
+// 模板管理
+// ============================================
+
+// ListCoupons 管理端优惠券列表（含发放与使用数量）
+func (s *CouponService) ListCoupons(ctx context.Context, params pagination.PaginationParams) ([]Coupon, *pagination.PaginationResult, error) {
+	return s.repo.List(ctx, params)
+}
+
+// GetCoupon 获取优惠券模板
+func (s *CouponService) GetCoupon(ctx context.Context, id int64) (*Coupon, error) {
+	return s.repo.GetByID(ctx, id)
+}
+
+// CreateCoupon 创建优惠券模板
+func (s *CouponService) CreateCoupon(ctx context.Context, c *Coupon) error {
+	if err := s.normalizeCoupon(ctx, c); err != nil {
+		return err
+	}
+	return s.repo.Create(ctx, c)
+}
+
+// UpdateCoupon 更新优惠券模板；已发放券的过期时间不变，优惠规则按更新后的模板计算
+func (s *CouponService) UpdateCoupon(ctx context.Context, c *Coupon) error {
+	if _, err := s.repo.GetByID(ctx, c.ID); err != nil {
+		return err
+	}
+	if err := s.normalizeCoupon(ctx, c); err != nil {
+		return err
+	}
+	return s.repo.Update(ctx, c)
+}
+
+// DeleteCoupon 删除优惠券模板及已发放的券
+func (s *CouponService) DeleteCoupon(ctx context.Context, id int64) error {
+	return s.repo.Delete(ctx, id)
+}
+
+func invalidCoupon(message string) error {
+	return infraerrors.BadRequest("COUPON_INVALID", message)
+}
+
+func (s *CouponService) normalizeCoupon(ctx context.Context, c *Coupon) error {
+	c.Name = strings.TrimSpace(c.Name)
+	c.Description = strings.TrimSpace(c.Description)
+	c.Currency = strings.ToUpper(strings.TrimSpace(c.Currency))
+	if c.Name == "" || len([]rune(c.Name)) > 100 {
+		return invalidCoupon("name is required and must be at most 100 characters")
+	}
+	if c.MinAmountCents < 0 {
+		return invalidCoupon("min_amount_cents must be non-negative")
+	}
+	if c.ValidDays < 0 {
+		return invalidCoupon("valid_days must be non-negative")
+	}
+
+	switch c.DiscountType {
+	case CouponDiscountPercent:
+		if c.PercentOff < 1 || c.PercentOff > 99 {
+			return invalidCoupon("percent_off must be between 1 and 99")
+		}
+		c.AmountOffCents = 0
+		c.Currency = ""
+	case CouponDiscountFixed:
+		if c.AmountOffCents <= 0 {
+			return invalidCoupon("amount_off_cents must be greater than 0")
+		}
+		if len(c.Currency) != 3 {
+			return invalidCoupon("currency must be a 3-letter ISO 4217 code")
+		}
+		c.PercentOff = 0
+	default:
+		return invalidCoupon("discount_type must be percent or fixed")
+	}
+
+	switch c.Scope {
+	case "":
+		c.Scope = CouponScopeAll
+		c.ScopeID = nil
+	case CouponScopeAll, CouponScopeBalance, CouponScopeSubscription:
+		c.ScopeID = nil
+	case CouponScopeProduct:
+		if c.ScopeID == nil {
+			return invalidCoupon("scope_id is required for product scope")
+		}
+	case CouponScopeGroup:
+		if c.ScopeID == nil {
+			return invalidCoupon("scope_id is required for group scope")
+		}
+		if _, err := s.groupRepo.GetByID(ctx, *c.ScopeID); err != nil {
+			return err
+		}
+	default:
+		return invalidCoupon("scope must be all, balance, subscription, product or group")
+	}
+	return nil
+}
+
+// ============================================
+// 发放
+// ============================================
+
+// Issue 向用户发放一张优惠券
+func (s *CouponService) Issue(ctx context.Context, couponID, userID int64, source, sourceRef string) (*UserCoupon, error) {
+	coupon, err := s.repo.GetByID(ctx, couponID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if !coupon.Enabled || (coupon.ExpiresAt != nil && !now.Before(*coupon.ExpiresAt)) {
+		return nil, ErrCouponDisabled
+	}
+	uc := &UserCoupon{
+		CouponID:  coupon.ID,
+		UserID:    userID,
+		Status:    UserCouponStatusUnused,
+		Source:    source,
+		SourceRef: sourceRef,
+		ExpiresAt: coupon.issueExpiry(now),
+		Coupon:    coupon,
+	}
+	if err := s.repo.Issue(ctx, uc); err != nil {
+		return nil, fmt.Errorf("issue coupon: %w", err)
+	}
+	return uc, nil
+}
+
+// IssueByAdmin 管理员向多个用户发放优惠券，返回发放成功的券
+func (s *CouponService) IssueByAdmin(ctx context.Context, couponID int64, userIDs []int64, operatorID int64) ([]UserCoupon, error) {
+	out := make([]UserCoupon, 0, len(userIDs))
+	for _, userID := range userIDs {
+		if _, err := s.userRepo.GetByID(ctx, userID); err != nil {
+			return out, err
+		}
+		uc, err := s.Issue(ctx, couponID, userID, CouponSourceAdmin, strconv.FormatInt(operatorID, 10))
+		if err != nil {
+			return out, err
+		}
+		out = append(out, *uc)
+	}
+	return out, nil
+}
+
+// ListUserCoupons 用户优惠券（管理端可按模板与用户过滤）
+func (s *CouponService) ListUserCoupons(ctx context.Context, params pagination.PaginationParams, filter UserCouponFilter) ([]UserCoupon, *pagination.PaginationResult, error) {
+	return s.repo.ListUserCoupons(ctx, params, filter)
+}
+
+// ============================================
+// 下单
+// ============================================
+
+// Quote 试算用户优惠券用于该商品后的价格
+func (s *CouponService) Quote(ctx context.Context, userID, userCouponID int64, product *PaymentProduct) (*CouponQuote, error) {
+	uc, err := s.repo.GetUserCoupon(ctx, userCouponID)
+	if err != nil {
+		return nil, err
+	}
+	if uc.UserID != userID {
+		return nil, ErrUserCouponNotFound
+	}
+	if !uc.Usable(time.Now()) {
+		return nil, ErrUserCouponUnavailable
+	}
+	if !uc.Coupon.Enabled {
+		return nil, ErrCouponDisabled
+	}
+	if !uc.Coupon.AppliesTo(product) {
+		return nil, ErrCouponNotApplicable
+	}
+	discount := uc.Coupon.DiscountCents(product.PriceCents)
+	return &CouponQuote{
+		UserCoupon:    uc,
+		OriginalCents: product.PriceCents,
+		DiscountCents: discount,
+		AmountCents:   product.PriceCents - discount,
+		Currency:      product.Currency,
+	}, nil
+}
+
+// ReserveForOrder 试算并将优惠券锁定到订单，防止同一张券同时用于多个订单
+func (s *CouponService) ReserveForOrder(ctx context.Context, userID, userCouponID int64, product *PaymentProduct, orderNo string) (*CouponQuote, error) {
+	quote, err := s.Quote(ctx, userID, userCouponID, product)
+	if err != nil {
+		return nil, err
+	}
+	locked, err := s.repo.LockForOrder(ctx, userCouponID, userID, orderNo, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("lock coupon: %w", err)
+	}
+	if !locked {
+		return nil, ErrUserCouponUnavailable
+	}
+	return quote, nil
+}
+
+// ReleaseOrder 订单关闭时退回其占用的优惠券
+func (s *CouponService) ReleaseOrder(ctx context.Context, orderNo string) error {
+	return s.repo.ReleaseOrder(ctx, orderNo)
+}
+
+// ConsumeForOrder 订单发放后核销优惠券；券已被其他订单使用时返回 ErrUserCouponUnavailable
+func (s *CouponService) ConsumeForOrder(ctx context.Context, userCouponID int64, orderNo string) error {
+	ok, err := s.repo.ConsumeForOrder(ctx, userCouponID, orderNo)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrUserCouponUnavailable
+	}
+	return nil
+}
@@ -0,0 +1,193 @@
+//go:build unit
+
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
+	"github.com/stretchr/testify/require"
+)
+
+type couponTestRepo struct {
+	coupons map[int64]*Coupon
+	issued  map[int64]*UserCoupon
+	nextID  int64
+}
+
+func newCouponTestRepo() *couponTestRepo {
+	return &couponTestRepo{coupons: map[int64]*Coupon{}, issued: map[int64]*UserCoupon{}}
+}
+
+func (r *couponTestRepo) Create(_ context.Context, c *Coupon) error {
+	r.nextID++
+	c.ID = r.nextID
+	cp := *c
+	r.coupons[c.ID] = &cp
+	return nil
+}
+
+func (r *couponTestRepo) Update(_ context.Context, c *Coupon) error {
+	cp := *c
+	r.coupons[c.ID] = &cp
+	return nil
+}
+
+func (r *couponTestRepo) Delete(_ context.Context, id int64) error {
+	delete(r.coupons, id)
+	return nil
+}
+
+func (r *couponTestRepo) GetByID(_ context.Context, id int64) (*Coupon, error) {
+	c, ok := r.coupons[id]
+	if !ok {
+		return nil, ErrCouponNotFound
+	}
+	cp := *c
+	return &cp, nil
+}
+
+func (r *couponTestRepo) List(_ context.Context, params pagination.PaginationParams) ([]Coupon, *pagination.PaginationResult, error) {
+	out := make([]Coupon, 0, len(r.coupons))
+	for _, c := range r.coupons {
+		out = append(out, *c)
+	}
+	return out, &pagination.PaginationResult{Total: int64(len(out)), Page: params.Page, PageSize: params.PageSize}, nil
+}
+
+func (r *couponTestRepo) Issue(_ context.Context, uc *UserCoupon) error {
+	r.nextID++
+	uc.ID = r.nextID
+	uc.CreatedAt = time.Now()
+	cp := *uc
+	r.issued[uc.ID] = &cp
+	return nil
+}
+
+func (r *couponTestRepo) GetUserCoupon(_ context.Context, id int64) (*UserCoupon, error) {
+	uc, ok := r.issued[id]
+	if !ok {
+		return nil, ErrUserCouponNotFound
+	}
+	cp := *uc
+	coupon := *r.coupons[uc.CouponID]
+	cp.Coupon = &coupon
+	return &cp, nil
+}
+
+func (r *couponTestRepo) ListUserCoupons(_ context.Context, params pagination.PaginationParams, filter UserCouponFilter) ([]UserCoupon, *pagination.PaginationResult, error) {
+	var out []UserCoupon
+	for _, uc := range r.issued {
+		if filter.UserID == 0 || uc.UserID == filter.UserID {
+			out = append(out, *uc)
+		}
+	}
+	return out, &pagination.PaginationResult{Total: int64(len(out)), Page: params.Page, PageSize: params.PageSize}, nil
+}
+
+func (r *couponTestRepo) LockForOrder(_ context.Context, id, userID int64, orderNo string, now time.Time) (bool, error) {
+	uc, ok := r.issued[id]
+	if !ok || uc.UserID != userID || !uc.Usable(now) {
+		return false, nil
+	}
+	uc.Status = UserCouponStatusLocked
+	uc.OrderNo = orderNo
+	return true, nil
+}
+
+func (r *couponTestRepo) ReleaseOrder(_ context.Context, orderNo string) error {
+	for _, uc := range r.issued {
+		if uc.Status == UserCouponStatusLocked && uc.OrderNo == orderNo {
+			uc.Status = UserCouponStatusUnused
+			uc.OrderNo = ""
+		}
+	}
+	return nil
+}
+
+func (r *couponTestRepo) ConsumeForOrder(_ context.Context, id int64, orderNo string) (bool, error) {
+	uc, ok := r.issued[id]
+	if !ok {
+		return false, nil
+	}
+	switch {
+	case uc.Status == UserCouponStatusUsed && uc.OrderNo == orderNo:
+		return true, nil
+	case uc.Status == UserCouponStatusUnused, uc.Status == UserCouponStatusLocked && uc.OrderNo == orderNo:
+		now := time.Now()
+		uc.Status = UserCouponStatusUsed
+		uc.OrderNo = orderNo
+		uc.UsedAt = &now
+		return true, nil
+	}
+	return false, nil
+}
+
+func TestCouponDiscountCents(t *testing.T) {
+	percent := &Coupon{DiscountType: CouponDiscountPercent, PercentOff: 15}
+	require.Equal(t, int64(1050), percent.DiscountCents(7000))
+	require.Equal(t, int64(0), percent.DiscountCents(5))
+
+	fixed := &Coupon{DiscountType: CouponDiscountFixed, AmountOffCents: 500, Currency: "CNY"}
+	require.Equal(t, int64(500), fixed.DiscountCents(7000))
+	require.Equal(t, int64(299), fixed.DiscountCents(300), "paid amount keeps at least one cent")
+}
+
+func TestCouponAppliesTo(t *testing.T) {
+	groupID := int64(3)
+	balance := &PaymentProduct{ID: 1, Kind: PaymentProductKindBalance, PriceCents: 7000, Currency: "CNY"}
+	sub := &PaymentProduct{ID: 2, Kind: PaymentProductKindSubscription, PriceCents: 3000, Currency: "CNY", GroupID: &groupID}
+
+	require.True(t, (&Coupon{Scope: CouponScopeAll}).AppliesTo(balance))
+	require.True(t, (&Coupon{Scope: CouponScopeBalance}).AppliesTo(balance))
+	require.False(t, (&Coupon{Scope: CouponScopeBalance}).AppliesTo(sub))
+	require.True(t, (&Coupon{Scope: CouponScopeGroup, ScopeID: &groupID}).AppliesTo(sub))
+	require.False(t, (&Coupon{Scope: CouponScopeProduct, ScopeID: &groupID}).AppliesTo(sub))
+	require.False(t, (&Coupon{Scope: CouponScopeAll, MinAmountCents: 5000}).AppliesTo(sub))
+	require.False(t, (&Coupon{Scope: CouponScopeAll, DiscountType: CouponDiscountFixed, Currency: "USD"}).AppliesTo(balance))
+}
+
+func TestCouponIssueExpiry(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	deadline := now.AddDate(0, 0, 3)
+
+	require.Nil(t, (&Coupon{}).issueExpiry(now))
+	require.Equal(t, now.AddDate(0, 0, 7), *(&Coupon{ValidDays: 7}).issueExpiry(now))
+	require.Equal(t, deadline, *(&Coupon{ValidDays: 7, ExpiresAt: &deadline}).issueExpiry(now), "earlier of valid_days and expires_at")
+}
+
+func TestCouponServiceReserveForOrder(t *testing.T) {
+	ctx := context.Background()
+	repo := newCouponTestRepo()
+	svc := NewCouponService(repo, nil, nil)
+	product := &PaymentProduct{ID: 1, Kind: PaymentProductKindBalance, PriceCents: 7000, Currency: "CNY"}
+
+	coupon := &Coupon{Name: "20% off", DiscountType: CouponDiscountPercent, PercentOff: 20, Enabled: true}
+	require.NoError(t, svc.CreateCoupon(ctx, coupon))
+	require.Equal(t, CouponScopeAll, coupon.Scope)
+
+	uc, err := svc.Issue(ctx, coupon.ID, 7, CouponSourceActivity, "1")
+	require.NoError(t, err)
+
+	_, err = svc.Quote(ctx, 8, uc.ID, product)
+	require.ErrorIs(t, err, ErrUserCouponNotFound, "other users cannot use the coupon")
+
+	quote, err := svc.ReserveForOrder(ctx, 7, uc.ID, product, "ORDER1")
+	require.NoError(t, err)
+	require.Equal(t, int64(1400), quote.DiscountCents)
+	require.Equal(t, int64(5600), quote.AmountCents)
+
+	_, err = svc.ReserveForOrder(ctx, 7, uc.ID, product, "ORDER2")
+	require.ErrorIs(t, err, ErrUserCouponUnavailable)
+
+	require.NoError(t, svc.ReleaseOrder(ctx, "ORDER1"))
+	_, err = svc.ReserveForOrder(ctx, 7, uc.ID, product, "ORDER2")
+	require.NoError(t, err)
+
+	coupon.Enabled = false
+	require.NoError(t, svc.UpdateCoupon(ctx, coupon))
+	_, err = svc.Issue(ctx, coupon.ID, 7, CouponSourceAdmin, "1")
+	require.ErrorIs(t, err, ErrCouponDisabled)
+}
@@ -15,8 +15,9 @@ const (
 
 // Role constants
 const (
-	RoleAdmin = domain.RoleAdmin
-	RoleUser  = domain.RoleUser
+	RoleAdmin        = domain.RoleAdmin
+	RoleUser         = domain.RoleUser
+	RoleOrganization = domain.RoleOrganization
 )
 
 // Platform constants
@@ -60,6 +61,13 @@ const (
 	PromoCodeStatusDisabled = domain.PromoCodeStatusDisabled
 )
 
+// PromoCode top-up bonus status constants
+const (
+	PromoTopupBonusStatusNone    = domain.PromoTopupBonusStatusNone
+	PromoTopupBonusStatusPending = domain.PromoTopupBonusStatusPending
+	PromoTopupBonusStatusGranted = domain.PromoTopupBonusStatusGranted
+)
+
 // Admin adjustment type constants
 const (
 	AdjustmentTypeAdminBalance     = domain.AdjustmentTypeAdminBalance     // 管理员调整余额
@@ -82,6 +90,12 @@ const (
 // LinuxDoConnectSyntheticEmailDomain 是 LinuxDo Connect 用户的合成邮箱后缀（RFC 保留域名）。
 const LinuxDoConnectSyntheticEmailDomain = "@linuxdo-connect.invalid"
 
+// OrganizationSyntheticEmailDomain 是组织账户的合成邮箱后缀（RFC 保留域名）。
+const OrganizationSyntheticEmailDomain = "@organization.invalid"
+
+// SSOSyntheticEmailDomain 是 SSO 自动开通但未提供已验证邮箱的用户的合成邮箱后缀（RFC 保留域名）。
+const SSOSyntheticEmailDomain = "@sso.invalid"
+
 // Setting keys
 const (
 	// 注册设置
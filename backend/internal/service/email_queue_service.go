@@ -13,14 +13,17 @@ import (
 const (
 	TaskTypeVerifyCode    = "verify_code"
 	TaskTypePasswordReset = "password_reset"
+	TaskTypeLoginNotice   = "login_notice"
 )
 
 // EmailTask 邮件发送任务
 type EmailTask struct {
 	Email    string
 	SiteName string
-	TaskType string // "verify_code" or "password_reset"
+	TaskType string // "verify_code", "password_reset" or "login_notice"
 	ResetURL string // Only used for password_reset task type
+
+	LoginNotice *LoginNotice // Only used for login_notice task type
 }
 
 // EmailQueueService 异步邮件队列服务
@@ -93,6 +96,15 @@ func (s *EmailQueueService) processTask(workerID int, task EmailTask) {
 		} else {
 			logger.LegacyPrintf("service.email_queue", "[EmailQueue] Worker %d sent password reset to %s", workerID, task.Email)
 		}
+	case TaskTypeLoginNotice:
+		if task.LoginNotice == nil {
+			return
+		}
+		if err := s.emailService.SendLoginNoticeEmail(ctx, task.Email, task.SiteName, task.LoginNotice); err != nil {
+			logger.LegacyPrintf("service.email_queue", "[EmailQueue] Worker %d failed to send login notice to %s: %v", workerID, task.Email, err)
+		} else {
+			logger.LegacyPrintf("service.email_queue", "[EmailQueue] Worker %d sent login notice to %s", workerID, task.Email)
+		}
 	default:
 		logger.LegacyPrintf("service.email_queue", "[EmailQueue] Worker %d unknown task type: %s", workerID, task.TaskType)
 	}
@@ -133,6 +145,24 @@ func (s *EmailQueueService) EnqueuePasswordReset(email, siteName, resetURL strin
 	}
 }
 
+// EnqueueLoginNotice 将新 IP / 新设备登录提醒邮件加入队列
+func (s *EmailQueueService) EnqueueLoginNotice(email, siteName string, notice LoginNotice) error {
+	task := EmailTask{
+		Email:       email,
+		SiteName:    siteName,
+		TaskType:    TaskTypeLoginNotice,
+		LoginNotice: &notice,
+	}
+
+	select {
+	case s.taskChan <- task:
+		logger.LegacyPrintf("service.email_queue", "[EmailQueue] Enqueued login notice task for %s", email)
+		return nil
+	default:
+		return fmt.Errorf("email queue is full")
+	}
+}
+
 // Stop 停止队列服务
 func (s *EmailQueueService) Stop() {
 	close(s.stopChan)
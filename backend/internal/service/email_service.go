@@ -737,3 +737,75 @@ func (s *EmailService) buildPasswordResetEmailBody(resetURL, siteName string) st
 </html>
 `, safeSiteName, safeSiteName, safeResetURL, safeResetURL, safeResetURL)
 }
+
+// SendLoginNoticeEmail sends a notice about a login from a new IP or device
+func (s *EmailService) SendLoginNoticeEmail(ctx context.Context, email, siteName string, notice *LoginNotice) error {
+	subject := fmt.Sprintf("[%s] 新设备登录提醒", siteName)
+	body := s.buildLoginNoticeEmailBody(notice, siteName)
+	if err := s.SendEmail(ctx, email, subject, body); err != nil {
+		return fmt.Errorf("send email: %w", err)
+	}
+	return nil
+}
+
+// buildLoginNoticeEmailBody builds the HTML content for login notice email
+func (s *EmailService) buildLoginNoticeEmailBody(notice *LoginNotice, siteName string) string {
+	safeSiteName := html.EscapeString(siteName)
+	ip := notice.IP
+	if ip == "" {
+		ip = "未知"
+	}
+	rows := [][2]string{
+		{"时间", notice.Time.UTC().Format("2006-01-02 15:04:05 UTC")},
+		{"IP 地址", ip},
+		{"设备", notice.Device},
+		{"登录方式", notice.Method},
+	}
+	var details strings.Builder
+	for _, row := range rows {
+		fmt.Fprintf(&details, `<tr><td style="padding:6px 12px 6px 0;color:#6b7280;white-space:nowrap;">%s</td><td style="padding:6px 0;color:#111827;word-break:break-all;">%s</td></tr>`,
+			row[0], html.EscapeString(row[1]))
+	}
+	return fmt.Sprintf(`
+<!DOCTYPE html>
+<html lang="zh-CN">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>%s - 新设备登录提醒</title>
+</head>
+<body style="margin:0;padding:0;background:#f3f6fb;">
+    <table role="presentation" cellpadding="0" cellspacing="0" border="0" width="100%%" style="background:#f3f6fb;padding:24px 12px;">
+        <tr>
+            <td align="center">
+                <table role="presentation" cellpadding="0" cellspacing="0" border="0" width="100%%" style="max-width:640px;background:#ffffff;border:1px solid #e6edf5;border-radius:16px;overflow:hidden;">
+                    <tr>
+                        <td style="background:linear-gradient(135deg,#0f766e 0%%,#0f766e 15%%,#0369a1 100%%);padding:28px 28px 24px;">
+                            <div style="font-family:'PingFang SC','Hiragino Sans GB','Microsoft YaHei',sans-serif;color:#d7efff;font-size:12px;letter-spacing:1px;text-transform:uppercase;">%s</div>
+                            <div style="margin-top:8px;font-family:'PingFang SC','Hiragino Sans GB','Microsoft YaHei',sans-serif;color:#ffffff;font-size:22px;line-height:30px;font-weight:600;">新设备登录提醒</div>
+                            <div style="margin-top:8px;font-family:'PingFang SC','Hiragino Sans GB','Microsoft YaHei',sans-serif;color:#d7efff;font-size:14px;line-height:22px;">您的账户刚刚从新的 IP 地址或设备登录。</div>
+                        </td>
+                    </tr>
+                    <tr>
+                        <td style="padding:28px;">
+                            <table role="presentation" cellpadding="0" cellspacing="0" border="0" style="font-family:'PingFang SC','Hiragino Sans GB','Microsoft YaHei',sans-serif;font-size:14px;line-height:22px;">
+                                %s
+                            </table>
+                            <div style="margin-top:18px;padding:14px;border-radius:10px;background:#fff7ed;border:1px solid #fed7aa;font-family:'PingFang SC','Hiragino Sans GB','Microsoft YaHei',sans-serif;color:#9a3412;font-size:13px;line-height:20px;">
+                                如果这不是您本人操作，请立即修改密码，并在账户安全设置中撤销可疑的登录会话。
+                            </div>
+                        </td>
+                    </tr>
+                    <tr>
+                        <td style="padding:18px 28px;background:#f8fafc;border-top:1px solid #e6edf5;font-family:'PingFang SC','Hiragino Sans GB','Microsoft YaHei',sans-serif;color:#94a3b8;font-size:12px;line-height:20px;">
+                            此邮件由系统自动发送，请勿直接回复。
+                        </td>
+                    </tr>
+                </table>
+            </td>
+        </tr>
+    </table>
+</body>
+</html>
+`, safeSiteName, safeSiteName, details.String())
+}
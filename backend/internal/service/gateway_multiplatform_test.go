@@ -1952,6 +1952,22 @@ func (m *mockConcurrencyCache) DecrementWaitCount(ctx context.Context, userID in
 	return nil
 }
 
+func (m *mockConcurrencyCache) AcquireAPIKeySlot(ctx context.Context, apiKeyID int64, maxConcurrency int, requestID string) (bool, error) {
+	return true, nil
+}
+
+func (m *mockConcurrencyCache) ReleaseAPIKeySlot(ctx context.Context, apiKeyID int64, requestID string) error {
+	return nil
+}
+
+func (m *mockConcurrencyCache) IncrementAPIKeyWaitCount(ctx context.Context, apiKeyID int64, maxWait int) (bool, error) {
+	return true, nil
+}
+
+func (m *mockConcurrencyCache) DecrementAPIKeyWaitCount(ctx context.Context, apiKeyID int64) error {
+	return nil
+}
+
 func (m *mockConcurrencyCache) GetAccountsLoadBatch(ctx context.Context, accounts []AccountWithConcurrency) (map[int64]*AccountLoadInfo, error) {
 	m.loadBatchCalls++
 	if m.loadBatchErr != nil {
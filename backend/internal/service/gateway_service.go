@@ -24,6 +24,8 @@ import (
 	"github.com/Wei-Shaw/sub2api/internal/pkg/claude"
 	"github.com/Wei-Shaw/sub2api/internal/pkg/ctxkey"
 	"github.com/Wei-Shaw/sub2api/internal/pkg/logger"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/tracing"
 	"github.com/Wei-Shaw/sub2api/internal/pkg/usagestats"
 	"github.com/Wei-Shaw/sub2api/internal/util/responseheaders"
 	"github.com/Wei-Shaw/sub2api/internal/util/urlvalidator"
@@ -518,8 +520,10 @@ type GatewayService struct {
 	deferredService      *DeferredService
 	concurrencyService   *ConcurrencyService
 	claudeTokenProvider  *ClaudeTokenProvider
-	sessionLimitCache    SessionLimitCache // 会话数量限制缓存（仅 Anthropic OAuth/SetupToken）
-	rpmCache             RPMCache          // RPM 计数缓存（仅 Anthropic OAuth/SetupToken）
+	sessionLimitCache    SessionLimitCache          // 会话数量限制缓存（仅 Anthropic OAuth/SetupToken）
+	rpmCache             RPMCache                   // RPM 计数缓存（仅 Anthropic OAuth/SetupToken）
+	usageJournal         *UsageJournalService       // 持久化使用量日志（可选）
+	apiKeyTraffic        *APIKeyTrafficLimitService // API Key 级 TPM 计数（可选）
 	userGroupRateCache   *gocache.Cache
 	userGroupRateSF      singleflight.Group
 	modelsListCache      *gocache.Cache
@@ -551,6 +555,8 @@ func NewGatewayService(
 	sessionLimitCache SessionLimitCache,
 	rpmCache RPMCache,
 	digestStore *DigestSessionStore,
+	usageJournal *UsageJournalService,
+	apiKeyTraffic *APIKeyTrafficLimitService,
 ) *GatewayService {
 	userGroupRateTTL := resolveUserGroupRateCacheTTL(cfg)
 	modelsListTTL := resolveModelsListCacheTTL(cfg)
@@ -576,6 +582,8 @@ func NewGatewayService(
 		claudeTokenProvider:  claudeTokenProvider,
 		sessionLimitCache:    sessionLimitCache,
 		rpmCache:             rpmCache,
+		usageJournal:         usageJournal,
+		apiKeyTraffic:        apiKeyTraffic,
 		userGroupRateCache:   gocache.New(userGroupRateTTL, time.Minute),
 		modelsListCache:      gocache.New(modelsListTTL, time.Minute),
 		modelsListCacheTTL:   modelsListTTL,
@@ -1041,6 +1049,13 @@ func (s *GatewayService) SelectAccountForModelWithExclusions(ctx context.Context
 // SelectAccountWithLoadAwareness selects account with load-awareness and wait plan.
 // metadataUserID: 已废弃参数，会话限制现在统一使用 sessionHash
 func (s *GatewayService) SelectAccountWithLoadAwareness(ctx context.Context, groupID *int64, sessionHash string, requestedModel string, excludedIDs map[int64]struct{}, metadataUserID string) (*AccountSelectionResult, error) {
+	ctx, span := startAccountSelectionSpan(ctx, groupID, requestedModel, sessionHash, excludedIDs)
+	selection, err := s.selectAccountWithLoadAwareness(ctx, groupID, sessionHash, requestedModel, excludedIDs, metadataUserID)
+	endAccountSelectionSpan(span, selection, "", err)
+	return selection, err
+}
+
+func (s *GatewayService) selectAccountWithLoadAwareness(ctx context.Context, groupID *int64, sessionHash string, requestedModel string, excludedIDs map[int64]struct{}, metadataUserID string) (*AccountSelectionResult, error) {
 	// 调试日志：记录调度入口参数
 	excludedIDsList := make([]int64, 0, len(excludedIDs))
 	for id := range excludedIDs {
@@ -6368,16 +6383,20 @@ type RecordUsageInput struct {
 	IPAddress         string             // 请求的客户端 IP 地址
 	ForceCacheBilling bool               // 强制缓存计费：将 input_tokens 转为 cache_read 计费（用于粘性会话切换）
 	APIKeyService     APIKeyQuotaUpdater // 可选：用于更新API Key配额
+	BillingHold       *BillingHold       // 可选：请求开始时的预授权冻结，计费后结算为实际费用
 }
 
 // APIKeyQuotaUpdater defines the interface for updating API Key quota and rate limit usage
 type APIKeyQuotaUpdater interface {
-	UpdateQuotaUsed(ctx context.Context, apiKeyID int64, cost float64) error
-	UpdateRateLimitUsage(ctx context.Context, apiKeyID int64, cost float64) error
+	UpdateQuotaUsed(ctx context.Context, apiKeyID int64, cost money.Amount) error
+	UpdateRateLimitUsage(ctx context.Context, apiKeyID int64, cost money.Amount) error
 }
 
 // RecordUsage 记录使用量并扣费（或更新订阅用量）
-func (s *GatewayService) RecordUsage(ctx context.Context, input *RecordUsageInput) error {
+func (s *GatewayService) RecordUsage(ctx context.Context, input *RecordUsageInput) (err error) {
+	ctx, span := startUsageRecordSpan(ctx, input.APIKey, input.Account, input.Result.Model)
+	defer func() { tracing.End(span, err) }()
+
 	result := input.Result
 	apiKey := input.APIKey
 	user := input.User
@@ -6409,6 +6428,7 @@ func (s *GatewayService) RecordUsage(ctx context.Context, input *RecordUsageInpu
 		groupDefault := apiKey.Group.RateMultiplier
 		multiplier = s.getUserGroupRateMultiplier(ctx, user.ID, *apiKey.GroupID, groupDefault)
 	}
+	multiplier = s.billingService.ApplyBatchDiscount(multiplier, apiKey)
 
 	var cost *CostBreakdown
 
@@ -6466,6 +6486,8 @@ func (s *GatewayService) RecordUsage(ctx context.Context, input *RecordUsageInpu
 		billingType = BillingTypeSubscription
 	}
 
+	observeFirstTokenMetric(apiKey, account, result.Model, result.FirstTokenMs)
+
 	// 创建使用日志
 	durationMs := int(result.Duration.Milliseconds())
 	var imageSize *string
@@ -6526,6 +6548,28 @@ func (s *GatewayService) RecordUsage(ctx context.Context, input *RecordUsageInpu
 		usageLog.SubscriptionID = &subscription.ID
 	}
 
+	// 预授权冻结收敛为实际费用
+	input.BillingHold.Settle(ctx, cost)
+	// 计入 API Key 的 TPM 窗口
+	s.apiKeyTraffic.RecordUsage(ctx, apiKey, usageLog)
+	// 计入组织 Key 创建成员的当月消费（成员消费上限）
+	s.billingCacheService.QueueAddOrgMemberSpend(apiKey, cost.ActualCost)
+
+	// 持久化日志：追加成功后由消费者落库扣费，失败时回退到同步路径
+	if s.usageJournal.Enabled() {
+		var billedSubscription *UserSubscription
+		if isSubscriptionBilling {
+			billedSubscription = subscription
+		}
+		chargeAPIKey := input.APIKeyService != nil && cost.ActualCost > 0
+		cmd := newUsageBillingCommand(usageLog, s.cfg == nil || s.cfg.RunMode != config.RunModeSimple, billedSubscription, cost,
+			chargeAPIKey && apiKey.Quota > 0, chargeAPIKey && apiKey.HasRateLimits())
+		if s.usageJournal.TryAppend(ctx, cmd) {
+			s.deferredService.ScheduleLastUsedUpdate(account.ID)
+			return nil
+		}
+	}
+
 	inserted, err := s.usageLogRepo.Create(ctx, usageLog)
 	if err != nil {
 		logger.LegacyPrintf("service.gateway", "Create usage log failed: %v", err)
@@ -6552,7 +6596,7 @@ func (s *GatewayService) RecordUsage(ctx context.Context, input *RecordUsageInpu
 	} else {
 		// 余额模式：扣除用户余额（使用 ActualCost 考虑倍率后的费用）
 		if shouldBill && cost.ActualCost > 0 {
-			if err := s.userRepo.DeductBalance(ctx, user.ID, cost.ActualCost); err != nil {
+			if err := s.userRepo.DeductBalance(ctx, user.ID, cost.ActualCost, usageLedgerSource(usageLog)); err != nil {
 				logger.LegacyPrintf("service.gateway", "Deduct balance failed: %v", err)
 			}
 			// 异步更新余额缓存
@@ -6594,10 +6638,14 @@ type RecordUsageLongContextInput struct {
 	LongContextMultiplier float64           // 超出阈值部分的倍率（如 2.0）
 	ForceCacheBilling     bool              // 强制缓存计费：将 input_tokens 转为 cache_read 计费（用于粘性会话切换）
 	APIKeyService         *APIKeyService    // API Key 配额服务（可选）
+	BillingHold           *BillingHold      // 预授权冻结（可选），计费后结算为实际费用
 }
 
 // RecordUsageWithLongContext 记录使用量并扣费，支持长上下文双倍计费（用于 Gemini）
-func (s *GatewayService) RecordUsageWithLongContext(ctx context.Context, input *RecordUsageLongContextInput) error {
+func (s *GatewayService) RecordUsageWithLongContext(ctx context.Context, input *RecordUsageLongContextInput) (err error) {
+	ctx, span := startUsageRecordSpan(ctx, input.APIKey, input.Account, input.Result.Model)
+	defer func() { tracing.End(span, err) }()
+
 	result := input.Result
 	apiKey := input.APIKey
 	user := input.User
@@ -6629,6 +6677,7 @@ func (s *GatewayService) RecordUsageWithLongContext(ctx context.Context, input *
 		groupDefault := apiKey.Group.RateMultiplier
 		multiplier = s.getUserGroupRateMultiplier(ctx, user.ID, *apiKey.GroupID, groupDefault)
 	}
+	multiplier = s.billingService.ApplyBatchDiscount(multiplier, apiKey)
 
 	var cost *CostBreakdown
 
@@ -6669,6 +6718,8 @@ func (s *GatewayService) RecordUsageWithLongContext(ctx context.Context, input *
 		billingType = BillingTypeSubscription
 	}
 
+	observeFirstTokenMetric(apiKey, account, result.Model, result.FirstTokenMs)
+
 	// 创建使用日志
 	durationMs := int(result.Duration.Milliseconds())
 	var imageSize *string
@@ -6724,6 +6775,28 @@ func (s *GatewayService) RecordUsageWithLongContext(ctx context.Context, input *
 		usageLog.SubscriptionID = &subscription.ID
 	}
 
+	// 预授权冻结收敛为实际费用
+	input.BillingHold.Settle(ctx, cost)
+	// 计入 API Key 的 TPM 窗口
+	s.apiKeyTraffic.RecordUsage(ctx, apiKey, usageLog)
+	// 计入组织 Key 创建成员的当月消费（成员消费上限）
+	s.billingCacheService.QueueAddOrgMemberSpend(apiKey, cost.ActualCost)
+
+	// 持久化日志：追加成功后由消费者落库扣费，失败时回退到同步路径
+	if s.usageJournal.Enabled() {
+		var billedSubscription *UserSubscription
+		if isSubscriptionBilling {
+			billedSubscription = subscription
+		}
+		chargeAPIKey := input.APIKeyService != nil && cost.ActualCost > 0
+		cmd := newUsageBillingCommand(usageLog, s.cfg == nil || s.cfg.RunMode != config.RunModeSimple, billedSubscription, cost,
+			chargeAPIKey && !isSubscriptionBilling && apiKey.Quota > 0, chargeAPIKey && apiKey.HasRateLimits())
+		if s.usageJournal.TryAppend(ctx, cmd) {
+			s.deferredService.ScheduleLastUsedUpdate(account.ID)
+			return nil
+		}
+	}
+
 	inserted, err := s.usageLogRepo.Create(ctx, usageLog)
 	if err != nil {
 		logger.LegacyPrintf("service.gateway", "Create usage log failed: %v", err)
@@ -6750,7 +6823,7 @@ func (s *GatewayService) RecordUsageWithLongContext(ctx context.Context, input *
 	} else {
 		// 余额模式：扣除用户余额（使用 ActualCost 考虑倍率后的费用）
 		if shouldBill && cost.ActualCost > 0 {
-			if err := s.userRepo.DeductBalance(ctx, user.ID, cost.ActualCost); err != nil {
+			if err := s.userRepo.DeductBalance(ctx, user.ID, cost.ActualCost, usageLedgerSource(usageLog)); err != nil {
 				logger.LegacyPrintf("service.gateway", "Deduct balance failed: %v", err)
 			}
 			// 异步更新余额缓存
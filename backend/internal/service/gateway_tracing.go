@@ -0,0 +1,50 @@
+package service
+
+import (
+	"context"
+
+	"github.com/Wei-Shaw/sub2api/internal/pkg/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// startAccountSelectionSpan 创建账号调度 Span
+func startAccountSelectionSpan(ctx context.Context, groupID *int64, requestedModel, sessionHash string, excludedIDs map[int64]struct{}) (context.Context, trace.Span) {
+	attrs := []attribute.KeyValue{
+		attribute.String("gen_ai.request.model", requestedModel),
+		attribute.Bool("scheduler.sticky_session", sessionHash != ""),
+		attribute.Int("scheduler.excluded_count", len(excludedIDs)),
+	}
+	if groupID != nil {
+		attrs = append(attrs, attribute.Int64("group.id", *groupID))
+	}
+	return tracing.Start(ctx, "scheduler.select_account", trace.WithAttributes(attrs...))
+}
+
+// endAccountSelectionSpan 记录调度结果（选中账号、是否需排队、调度层）并结束 Span
+func endAccountSelectionSpan(span trace.Span, selection *AccountSelectionResult, layer string, err error) {
+	if layer != "" {
+		span.SetAttributes(attribute.String("scheduler.layer", layer))
+	}
+	if selection != nil && selection.Account != nil {
+		span.SetAttributes(
+			attribute.Int64("account.id", selection.Account.ID),
+			attribute.String("account.platform", selection.Account.Platform),
+			attribute.Bool("scheduler.slot_acquired", selection.Acquired),
+			attribute.Bool("scheduler.wait_plan", selection.WaitPlan != nil),
+		)
+	}
+	tracing.End(span, err)
+}
+
+// startUsageRecordSpan 创建用量记录 Span；用量记录在请求结束后异步执行，ctx 由处理器挂上请求链路
+func startUsageRecordSpan(ctx context.Context, apiKey *APIKey, account *Account, model string) (context.Context, trace.Span) {
+	attrs := []attribute.KeyValue{attribute.String("gen_ai.request.model", model)}
+	if apiKey != nil {
+		attrs = append(attrs, attribute.Int64("api_key.id", apiKey.ID), attribute.Int64("user.id", apiKey.UserID))
+	}
+	if account != nil {
+		attrs = append(attrs, attribute.Int64("account.id", account.ID))
+	}
+	return tracing.Start(ctx, "usage.record", trace.WithAttributes(attrs...))
+}
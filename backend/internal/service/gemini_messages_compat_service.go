@@ -28,6 +28,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/tidwall/gjson"
+	"github.com/tidwall/sjson"
 )
 
 const geminiStickySessionTTL = time.Hour
@@ -1066,7 +1067,7 @@ func (s *GeminiMessagesCompatService) ForwardNative(ctx context.Context, c *gin.
 	}
 
 	switch action {
-	case "generateContent", "streamGenerateContent", "countTokens":
+	case "generateContent", "streamGenerateContent", "countTokens", "embedContent", "batchEmbedContents":
 		// ok
 	default:
 		return nil, s.writeGoogleError(c, http.StatusNotFound, "Unsupported action: "+action)
@@ -1080,6 +1081,9 @@ func (s *GeminiMessagesCompatService) ForwardNative(ctx context.Context, c *gin.
 	if account.Type == AccountTypeAPIKey {
 		mappedModel = account.GetMappedModel(originalModel)
 	}
+	if action == "batchEmbedContents" && mappedModel != originalModel {
+		body = rewriteGeminiBatchEmbedModels(body, mappedModel)
+	}
 
 	proxyURL := ""
 	if account.ProxyID != nil && account.Proxy != nil {
@@ -1093,7 +1097,8 @@ func (s *GeminiMessagesCompatService) ForwardNative(ctx context.Context, c *gin.
 		useUpstreamStream = true
 		upstreamAction = "streamGenerateContent"
 	}
-	forceAIStudio := action == "countTokens"
+	// Code Assist 不提供 countTokens / embeddings，OAuth 账号统一走 AI Studio
+	forceAIStudio := action == "countTokens" || isGeminiEmbedAction(action)
 
 	var requestIDHeader string
 	var buildReq func(ctx context.Context) (*http.Request, string, error)
@@ -1518,6 +1523,10 @@ func (s *GeminiMessagesCompatService) ForwardNative(ctx context.Context, c *gin.
 	if usage == nil {
 		usage = &ClaudeUsage{}
 	}
+	// embedContent 响应不带 usageMetadata，按请求文本估算输入 token
+	if isGeminiEmbedAction(action) && usage.InputTokens == 0 {
+		usage.InputTokens = estimateGeminiEmbedTokens(body)
+	}
 
 	// 图片生成计费
 	imageCount := 0
@@ -2394,6 +2403,44 @@ func estimateGeminiCountTokens(reqBody []byte) int {
 	return total
 }
 
+func isGeminiEmbedAction(action string) bool {
+	return action == "embedContent" || action == "batchEmbedContents"
+}
+
+// estimateGeminiEmbedTokens estimates input tokens for embedContent
+// (content.parts[].text) and batchEmbedContents (requests[].content.parts[].text).
+func estimateGeminiEmbedTokens(reqBody []byte) int {
+	total := 0
+	countParts := func(content gjson.Result) {
+		content.Get("parts").ForEach(func(_, part gjson.Result) bool {
+			total += estimateTokensForText(part.Get("text").String())
+			return true
+		})
+	}
+	countParts(gjson.GetBytes(reqBody, "content"))
+	gjson.GetBytes(reqBody, "requests").ForEach(func(_, req gjson.Result) bool {
+		countParts(req.Get("content"))
+		return true
+	})
+	return total
+}
+
+// rewriteGeminiBatchEmbedModels points every requests[].model of a
+// batchEmbedContents body at the mapped model; Google rejects entries whose
+// model differs from the one in the URL.
+func rewriteGeminiBatchEmbedModels(body []byte, mappedModel string) []byte {
+	requests := gjson.GetBytes(body, "requests")
+	if !requests.IsArray() {
+		return body
+	}
+	for i := range requests.Array() {
+		if patched, err := sjson.SetBytes(body, fmt.Sprintf("requests.%d.model", i), "models/"+mappedModel); err == nil {
+			body = patched
+		}
+	}
+	return body
+}
+
 func estimateTokensForText(s string) int {
 	s = strings.TrimSpace(s)
 	if s == "" {
@@ -3,6 +3,8 @@ package service
 import (
 	"strings"
 	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
 )
 
 type Group struct {
@@ -16,28 +18,32 @@ type Group struct {
 	Hydrated       bool // indicates the group was loaded from a trusted repository source
 
 	SubscriptionType    string
-	DailyLimitUSD       *float64
-	WeeklyLimitUSD      *float64
-	MonthlyLimitUSD     *float64
+	DailyLimitUSD       *money.Amount
+	WeeklyLimitUSD      *money.Amount
+	MonthlyLimitUSD     *money.Amount
 	DefaultValidityDays int
 
 	// 图片生成计费配置（antigravity 和 gemini 平台使用）
-	ImagePrice1K *float64
-	ImagePrice2K *float64
-	ImagePrice4K *float64
+	ImagePrice1K *money.Amount
+	ImagePrice2K *money.Amount
+	ImagePrice4K *money.Amount
 
 	// Sora 按次计费配置（阶段 1）
-	SoraImagePrice360          *float64
-	SoraImagePrice540          *float64
-	SoraVideoPricePerRequest   *float64
-	SoraVideoPricePerRequestHD *float64
+	SoraImagePrice360          *money.Amount
+	SoraImagePrice540          *money.Amount
+	SoraVideoPricePerRequest   *money.Amount
+	SoraVideoPricePerRequestHD *money.Amount
 
 	// Sora 存储配额
 	SoraStorageQuotaBytes int64
 
 	// 通用视频生成计费配置（用于 Grok 等 OpenAI 兼容平台）
-	VideoPricePerRequest   *float64
-	VideoPricePerRequestHD *float64
+	VideoPricePerRequest   *money.Amount
+	VideoPricePerRequestHD *money.Amount
+
+	// 音频计费配置（OpenAI 兼容平台的转写/翻译/语音合成）
+	AudioPricePerMinute        *money.Amount
+	AudioSpeechPricePer1MChars *money.Amount
 
 	// Claude Code 客户端限制
 	ClaudeCodeOnly  bool
@@ -81,20 +87,20 @@ func (g *Group) IsFreeSubscription() bool {
 }
 
 func (g *Group) HasDailyLimit() bool {
-	return g.DailyLimitUSD != nil && *g.DailyLimitUSD > 0
+	return g.DailyLimitUSD != nil && g.DailyLimitUSD.IsPositive()
 }
 
 func (g *Group) HasWeeklyLimit() bool {
-	return g.WeeklyLimitUSD != nil && *g.WeeklyLimitUSD > 0
+	return g.WeeklyLimitUSD != nil && g.WeeklyLimitUSD.IsPositive()
 }
 
 func (g *Group) HasMonthlyLimit() bool {
-	return g.MonthlyLimitUSD != nil && *g.MonthlyLimitUSD > 0
+	return g.MonthlyLimitUSD != nil && g.MonthlyLimitUSD.IsPositive()
 }
 
 // GetImagePrice 根据 image_size 返回对应的图片生成价格
 // 如果分组未配置价格，返回 nil（调用方应使用默认值）
-func (g *Group) GetImagePrice(imageSize string) *float64 {
+func (g *Group) GetImagePrice(imageSize string) *money.Amount {
 	switch imageSize {
 	case "1K":
 		return g.ImagePrice1K
@@ -109,7 +115,7 @@ func (g *Group) GetImagePrice(imageSize string) *float64 {
 }
 
 // GetSoraImagePrice 根据 Sora 图片尺寸返回价格（360/540）
-func (g *Group) GetSoraImagePrice(imageSize string) *float64 {
+func (g *Group) GetSoraImagePrice(imageSize string) *money.Amount {
 	switch imageSize {
 	case "360":
 		return g.SoraImagePrice360
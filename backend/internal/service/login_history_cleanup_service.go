@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/config"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/logger"
+)
+
+const (
+	loginHistoryCleanupInterval = time.Hour
+	loginHistoryCleanupBatch    = 1000
+	// loginHistoryCleanupMaxBatches 单轮最多删除的批次数，积压较多时分多轮清理
+	loginHistoryCleanupMaxBatches = 50
+)
+
+// LoginHistoryCleanupService 定期删除超过保留期的登录记录。
+type LoginHistoryCleanupService struct {
+	repo      LoginHistoryRepository
+	retention time.Duration
+
+	startOnce sync.Once
+	stopOnce  sync.Once
+	stopCh    chan struct{}
+}
+
+func NewLoginHistoryCleanupService(repo LoginHistoryRepository, cfg *config.Config) *LoginHistoryCleanupService {
+	svc := &LoginHistoryCleanupService{
+		repo:   repo,
+		stopCh: make(chan struct{}),
+	}
+	if cfg != nil && cfg.LoginHistory.RetentionDays > 0 {
+		svc.retention = time.Duration(cfg.LoginHistory.RetentionDays) * 24 * time.Hour
+	}
+	return svc
+}
+
+func (s *LoginHistoryCleanupService) Start() {
+	// 保留天数为 0 表示永久保留
+	if s == nil || s.repo == nil || s.retention <= 0 {
+		return
+	}
+	s.startOnce.Do(func() {
+		logger.LegacyPrintf("service.login_history_cleanup", "[LoginHistoryCleanup] started retention=%s", s.retention)
+		go s.runLoop()
+	})
+}
+
+func (s *LoginHistoryCleanupService) Stop() {
+	if s == nil {
+		return
+	}
+	s.stopOnce.Do(func() {
+		close(s.stopCh)
+		logger.LegacyPrintf("service.login_history_cleanup", "[LoginHistoryCleanup] stopped")
+	})
+}
+
+func (s *LoginHistoryCleanupService) runLoop() {
+	ticker := time.NewTicker(loginHistoryCleanupInterval)
+	defer ticker.Stop()
+
+	s.cleanupOnce()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.cleanupOnce()
+		case <-s.stopCh:
+			return
+		}
+	}
+}
+
+func (s *LoginHistoryCleanupService) cleanupOnce() {
+	cutoff := time.Now().Add(-s.retention)
+	var total int64
+	for range loginHistoryCleanupMaxBatches {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		deleted, err := s.repo.DeleteBefore(ctx, cutoff, loginHistoryCleanupBatch)
+		cancel()
+		if err != nil {
+			logger.LegacyPrintf("service.login_history_cleanup", "[LoginHistoryCleanup] cleanup failed err=%v", err)
+			break
+		}
+		total += deleted
+		if deleted < loginHistoryCleanupBatch {
+			break
+		}
+	}
+	if total > 0 {
+		logger.LegacyPrintf("service.login_history_cleanup", "[LoginHistoryCleanup] cleaned records count=%d", total)
+	}
+}
@@ -0,0 +1,211 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/config"
+	infraerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/logger"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
+)
+
+// 登录方式
+const (
+	LoginMethodPassword     = "password"
+	LoginMethodTotp         = "totp"          // 密码 + TOTP
+	LoginMethodWebAuthn     = "webauthn"      // 密码 + Passkey（第二因素）
+	LoginMethodRecoveryCode = "recovery_code" // 密码 + 恢复码
+	LoginMethodPasskey      = "passkey"       // Passkey 无密码登录
+	LoginMethodLinuxDo      = "linuxdo"
+)
+
+// LoginMethodSSO 返回 SSO 提供方对应的登录方式
+func LoginMethodSSO(slug string) string {
+	return "sso:" + slug
+}
+
+const maxLoginFailureReasonLength = 64
+
+// LoginHistoryEntry 一次登录尝试
+type LoginHistoryEntry struct {
+	ID            int64
+	UserID        *int64 // 邮箱不存在时为空
+	Email         string
+	Success       bool
+	FailureReason string
+	Method        string
+	IP            string
+	UserAgent     string
+	Device        string
+	CreatedAt     time.Time
+}
+
+// LoginHistoryFilter 登录记录查询条件
+type LoginHistoryFilter struct {
+	UserID    int64
+	Email     string
+	IP        string
+	Success   *bool
+	StartTime *time.Time
+	EndTime   *time.Time
+}
+
+// LoginOrigins 用户此前成功登录的来源概况
+type LoginOrigins struct {
+	HasHistory bool // 是否有过成功登录
+	IPSeen     bool // 是否曾从该 IP 成功登录
+	DeviceSeen bool // 是否曾从该设备成功登录
+}
+
+// LoginHistoryRepository 登录记录存储
+type LoginHistoryRepository interface {
+	Create(ctx context.Context, entry *LoginHistoryEntry) error
+	List(ctx context.Context, params pagination.PaginationParams, filter LoginHistoryFilter) ([]LoginHistoryEntry, *pagination.PaginationResult, error)
+	// GetLoginOrigins 查询用户此前成功登录中是否出现过给定的 IP / 设备
+	GetLoginOrigins(ctx context.Context, userID int64, ip, device string) (*LoginOrigins, error)
+	// DeleteBefore 删除早于 cutoff 的记录，单次最多删除 limit 条
+	DeleteBefore(ctx context.Context, cutoff time.Time, limit int) (int64, error)
+}
+
+// LoginNotice 新 IP / 新设备登录提醒内容
+type LoginNotice struct {
+	IP     string
+	Device string
+	Method string
+	Time   time.Time
+}
+
+// LoginHistoryService 记录登录尝试，并在新 IP / 新设备登录时发送邮件提醒。
+// 记录失败只打日志，不影响登录流程。
+type LoginHistoryService struct {
+	repo              LoginHistoryRepository
+	userRepo          UserRepository
+	settingService    *SettingService
+	emailQueueService *EmailQueueService
+	notifyNewDevice   bool
+}
+
+// NewLoginHistoryService 创建登录记录服务
+func NewLoginHistoryService(repo LoginHistoryRepository, userRepo UserRepository, settingService *SettingService, emailQueueService *EmailQueueService, cfg *config.Config) *LoginHistoryService {
+	svc := &LoginHistoryService{
+		repo:              repo,
+		userRepo:          userRepo,
+		settingService:    settingService,
+		emailQueueService: emailQueueService,
+	}
+	if cfg != nil {
+		svc.notifyNewDevice = cfg.LoginHistory.NotifyNewDevice
+	}
+	return svc
+}
+
+// RecordSuccess 记录一次成功登录
+func (s *LoginHistoryService) RecordSuccess(ctx context.Context, user *User, method string) {
+	if s == nil || user == nil {
+		return
+	}
+	entry := s.newEntry(ctx, method)
+	entry.UserID = &user.ID
+	entry.Email = user.Email
+	entry.Success = true
+
+	// 先查来源再写入，避免把本次登录算作“已出现过”
+	var origins *LoginOrigins
+	if s.shouldNotify(user) {
+		var err error
+		origins, err = s.repo.GetLoginOrigins(ctx, user.ID, entry.IP, entry.Device)
+		if err != nil {
+			logger.LegacyPrintf("service.login_history", "[LoginHistory] Failed to query login origins: user=%d err=%v", user.ID, err)
+		}
+	}
+
+	if err := s.repo.Create(ctx, entry); err != nil {
+		logger.LegacyPrintf("service.login_history", "[LoginHistory] Failed to record login: user=%d err=%v", user.ID, err)
+	}
+
+	// 首次登录不提醒；此后任一来源未出现过即提醒
+	if origins != nil && origins.HasHistory && (!origins.IPSeen || !origins.DeviceSeen) {
+		s.enqueueNotice(ctx, user, entry)
+	}
+}
+
+// RecordFailure 记录一次失败的登录尝试。userID 未知时按邮箱关联用户。
+func (s *LoginHistoryService) RecordFailure(ctx context.Context, userID int64, email, method string, cause error) {
+	if s == nil {
+		return
+	}
+	entry := s.newEntry(ctx, method)
+	entry.Email = strings.TrimSpace(email)
+	entry.FailureReason = loginFailureReason(cause)
+
+	if userID <= 0 && entry.Email != "" && s.userRepo != nil {
+		user, err := s.userRepo.GetByEmail(ctx, entry.Email)
+		if err == nil {
+			userID = user.ID
+		} else if !errors.Is(err, ErrUserNotFound) {
+			logger.LegacyPrintf("service.login_history", "[LoginHistory] Failed to resolve user by email: err=%v", err)
+		}
+	}
+	if userID > 0 {
+		entry.UserID = &userID
+	}
+
+	if err := s.repo.Create(ctx, entry); err != nil {
+		logger.LegacyPrintf("service.login_history", "[LoginHistory] Failed to record login failure: err=%v", err)
+	}
+}
+
+// ListUserHistory 返回用户自己的登录记录
+func (s *LoginHistoryService) ListUserHistory(ctx context.Context, userID int64, params pagination.PaginationParams) ([]LoginHistoryEntry, *pagination.PaginationResult, error) {
+	return s.repo.List(ctx, params, LoginHistoryFilter{UserID: userID})
+}
+
+// Search 管理员按条件查询登录记录
+func (s *LoginHistoryService) Search(ctx context.Context, params pagination.PaginationParams, filter LoginHistoryFilter) ([]LoginHistoryEntry, *pagination.PaginationResult, error) {
+	return s.repo.List(ctx, params, filter)
+}
+
+func (s *LoginHistoryService) newEntry(ctx context.Context, method string) *LoginHistoryEntry {
+	client := ClientInfoFromContext(ctx)
+	return &LoginHistoryEntry{
+		Method:    method,
+		IP:        client.IP,
+		UserAgent: client.UserAgent,
+		Device:    DescribeDevice(client.UserAgent),
+	}
+}
+
+func (s *LoginHistoryService) shouldNotify(user *User) bool {
+	// 第三方登录的合成邮箱无法收信
+	return s.notifyNewDevice && s.emailQueueService != nil && !isReservedEmail(user.Email)
+}
+
+func (s *LoginHistoryService) enqueueNotice(ctx context.Context, user *User, entry *LoginHistoryEntry) {
+	siteName := "Sub2API"
+	if s.settingService != nil {
+		siteName = s.settingService.GetSiteName(ctx)
+	}
+	notice := LoginNotice{
+		IP:     entry.IP,
+		Device: entry.Device,
+		Method: entry.Method,
+		Time:   time.Now(),
+	}
+	if err := s.emailQueueService.EnqueueLoginNotice(user.Email, siteName, notice); err != nil {
+		logger.LegacyPrintf("service.login_history", "[LoginHistory] Failed to enqueue login notice: user=%d err=%v", user.ID, err)
+	}
+}
+
+func loginFailureReason(cause error) string {
+	reason := infraerrors.Reason(cause)
+	if reason == "" {
+		reason = "UNKNOWN"
+	}
+	if len(reason) > maxLoginFailureReasonLength {
+		reason = reason[:maxLoginFailureReasonLength]
+	}
+	return reason
+}
@@ -0,0 +1,126 @@
+//go:build unit
+
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/config"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
+	"github.com/stretchr/testify/require"
+)
+
+type loginHistoryRepoFake struct {
+	entries []LoginHistoryEntry
+}
+
+func (r *loginHistoryRepoFake) Create(ctx context.Context, entry *LoginHistoryEntry) error {
+	entry.ID = int64(len(r.entries) + 1)
+	entry.CreatedAt = time.Now()
+	r.entries = append(r.entries, *entry)
+	return nil
+}
+
+func (r *loginHistoryRepoFake) List(ctx context.Context, params pagination.PaginationParams, filter LoginHistoryFilter) ([]LoginHistoryEntry, *pagination.PaginationResult, error) {
+	return r.entries, &pagination.PaginationResult{Total: int64(len(r.entries))}, nil
+}
+
+func (r *loginHistoryRepoFake) GetLoginOrigins(ctx context.Context, userID int64, ip, device string) (*LoginOrigins, error) {
+	origins := &LoginOrigins{}
+	for _, e := range r.entries {
+		if e.UserID == nil || *e.UserID != userID || !e.Success {
+			continue
+		}
+		origins.HasHistory = true
+		origins.IPSeen = origins.IPSeen || e.IP == ip
+		origins.DeviceSeen = origins.DeviceSeen || e.Device == device
+	}
+	return origins, nil
+}
+
+func (r *loginHistoryRepoFake) DeleteBefore(ctx context.Context, cutoff time.Time, limit int) (int64, error) {
+	return 0, nil
+}
+
+const (
+	loginTestChromeMac = "Mozilla/5.0 (Macintosh; Intel Mac OS X 14_5) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/126.0 Safari/537.36"
+	loginTestFirefox   = "Mozilla/5.0 (X11; Linux x86_64; rv:127.0) Gecko/20100101 Firefox/127.0"
+)
+
+func newLoginHistoryTestService(notify bool, users ...*User) (*LoginHistoryService, *loginHistoryRepoFake, chan EmailTask) {
+	repo := &loginHistoryRepoFake{}
+	// 不启动 worker，直接从队列读取任务
+	queue := &EmailQueueService{taskChan: make(chan EmailTask, 10)}
+	cfg := &config.Config{LoginHistory: config.LoginHistoryConfig{NotifyNewDevice: notify}}
+	return NewLoginHistoryService(repo, newSSOUserRepoFake(users...), nil, queue, cfg), repo, queue.taskChan
+}
+
+func loginTestContext(ip, ua string) context.Context {
+	return WithClientInfo(context.Background(), ClientInfo{IP: ip, UserAgent: ua})
+}
+
+func TestLoginHistory_RecordFailureResolvesUserByEmail(t *testing.T) {
+	user := sessionTestUser()
+	svc, repo, _ := newLoginHistoryTestService(false, user)
+
+	svc.RecordFailure(loginTestContext("203.0.113.10", loginTestChromeMac), 0, user.Email, LoginMethodPassword, ErrInvalidCredentials)
+	svc.RecordFailure(loginTestContext("203.0.113.10", loginTestChromeMac), 0, "nobody@example.com", LoginMethodPassword, ErrInvalidCredentials)
+
+	require.Len(t, repo.entries, 2)
+	require.NotNil(t, repo.entries[0].UserID)
+	require.Equal(t, user.ID, *repo.entries[0].UserID)
+	require.False(t, repo.entries[0].Success)
+	require.Equal(t, "INVALID_CREDENTIALS", repo.entries[0].FailureReason)
+	require.Equal(t, "Chrome on macOS", repo.entries[0].Device)
+	require.Nil(t, repo.entries[1].UserID)
+	require.Equal(t, "nobody@example.com", repo.entries[1].Email)
+}
+
+func TestLoginHistory_NotifiesOnNewIPOrDevice(t *testing.T) {
+	user := sessionTestUser()
+	svc, repo, tasks := newLoginHistoryTestService(true, user)
+
+	// 首次登录不提醒
+	svc.RecordSuccess(loginTestContext("203.0.113.10", loginTestChromeMac), user, LoginMethodPassword)
+	require.Empty(t, tasks)
+
+	// 相同 IP 与设备不提醒
+	svc.RecordSuccess(loginTestContext("203.0.113.10", loginTestChromeMac), user, LoginMethodPassword)
+	require.Empty(t, tasks)
+
+	// 新设备
+	svc.RecordSuccess(loginTestContext("203.0.113.10", loginTestFirefox), user, LoginMethodPasskey)
+	require.Len(t, tasks, 1)
+	task := <-tasks
+	require.Equal(t, TaskTypeLoginNotice, task.TaskType)
+	require.Equal(t, user.Email, task.Email)
+	require.Equal(t, "Firefox on Linux", task.LoginNotice.Device)
+	require.Equal(t, LoginMethodPasskey, task.LoginNotice.Method)
+
+	// 新 IP
+	svc.RecordSuccess(loginTestContext("198.51.100.20", loginTestChromeMac), user, LoginMethodPassword)
+	require.Len(t, tasks, 1)
+	<-tasks
+
+	// 失败记录不影响新来源判断
+	svc.RecordFailure(loginTestContext("192.0.2.1", loginTestChromeMac), user.ID, user.Email, LoginMethodTotp, ErrInvalidCredentials)
+	svc.RecordSuccess(loginTestContext("192.0.2.1", loginTestChromeMac), user, LoginMethodTotp)
+	require.Len(t, tasks, 1)
+	require.Len(t, repo.entries, 6)
+}
+
+func TestLoginHistory_NoNoticeWhenDisabledOrSyntheticEmail(t *testing.T) {
+	user := sessionTestUser()
+	svc, _, tasks := newLoginHistoryTestService(false, user)
+	svc.RecordSuccess(loginTestContext("203.0.113.10", loginTestChromeMac), user, LoginMethodPassword)
+	svc.RecordSuccess(loginTestContext("198.51.100.20", loginTestFirefox), user, LoginMethodPassword)
+	require.Empty(t, tasks)
+
+	oauthUser := &User{ID: 9, Email: "linuxdo-42" + LinuxDoConnectSyntheticEmailDomain, Role: RoleUser, Status: StatusActive}
+	svc, _, tasks = newLoginHistoryTestService(true, oauthUser)
+	svc.RecordSuccess(loginTestContext("203.0.113.10", loginTestChromeMac), oauthUser, LoginMethodLinuxDo)
+	svc.RecordSuccess(loginTestContext("198.51.100.20", loginTestFirefox), oauthUser, LoginMethodLinuxDo)
+	require.Empty(t, tasks)
+}
@@ -0,0 +1,407 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/Wei-Shaw/sub2api/internal/pkg/logger"
+	"github.com/gin-gonic/gin"
+	"github.com/tidwall/gjson"
+	"github.com/tidwall/sjson"
+)
+
+const (
+	// OpenAIAudioEndpointTranscriptions 语音转写上游路径
+	OpenAIAudioEndpointTranscriptions = "audio/transcriptions"
+	// OpenAIAudioEndpointTranslations 语音翻译上游路径
+	OpenAIAudioEndpointTranslations = "audio/translations"
+
+	// openAIAudioMultipartMaxMemory multipart 解析时驻留内存的上限，超出部分落盘
+	openAIAudioMultipartMaxMemory = 32 << 20
+	// openAIAudioFallbackBytesPerSecond 无法解析音频头时按 128kbps 估算时长
+	openAIAudioFallbackBytesPerSecond = 16000
+)
+
+// ForwardAudioTranscription forwards a multipart /v1/audio/transcriptions or
+// /v1/audio/translations request to an OpenAI-compatible API Key account.
+// The form is rebuilt with the account's model_mapping applied. Billing uses
+// the upstream usage block when present (tokens for gpt-4o-transcribe,
+// seconds for whisper-1), then verbose_json "duration", and finally a local
+// estimate from the uploaded file.
+func (s *OpenAIGatewayService) ForwardAudioTranscription(ctx context.Context, c *gin.Context, account *Account, endpoint string) (*OpenAIForwardResult, error) {
+	start := time.Now()
+	if account.IsOpenAIOAuth() {
+		return nil, fmt.Errorf("audio endpoints are not supported by oauth account %d", account.ID)
+	}
+	if err := c.Request.ParseMultipartForm(openAIAudioMultipartMaxMemory); err != nil {
+		return nil, fmt.Errorf("parse multipart form: %w", err)
+	}
+	form := c.Request.MultipartForm
+
+	originalModel := strings.TrimSpace(c.Request.FormValue("model"))
+	mappedModel := account.GetMappedModel(originalModel)
+	clientStream := strings.EqualFold(strings.TrimSpace(c.Request.FormValue("stream")), "true")
+
+	var requestBodyBytes bytes.Buffer
+	writer := multipart.NewWriter(&requestBodyBytes)
+	for key, values := range form.Value {
+		for _, value := range values {
+			if key == "model" {
+				value = mappedModel
+			}
+			_ = writer.WriteField(key, value)
+		}
+	}
+	estimatedSeconds := 0.0
+	for key, fileHeaders := range form.File {
+		for _, fileHeader := range fileHeaders {
+			seconds, err := copyAudioFormFile(writer, key, fileHeader)
+			if err != nil {
+				return nil, err
+			}
+			if key == "file" {
+				estimatedSeconds += seconds
+			}
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return nil, fmt.Errorf("close multipart writer: %w", err)
+	}
+
+	token, _, err := s.GetAccessToken(ctx, account)
+	if err != nil {
+		return nil, err
+	}
+	targetURL, err := s.resolveOpenAIAudioTargetURL(account, endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, targetURL, bytes.NewReader(requestBodyBytes.Bytes()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("authorization", "Bearer "+token)
+	copyOpenAIAllowedRequestHeaders(req.Header, c.Request.Header)
+	req.Header.Set("content-type", writer.FormDataContentType())
+
+	resp, err := s.doOpenAIAudioRequest(req, account)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, s.handleOpenAIAudioErrorResponse(ctx, c, resp, account)
+	}
+
+	result := &OpenAIForwardResult{
+		RequestID: strings.TrimSpace(resp.Header.Get("x-request-id")),
+		Model:     originalModel,
+		MediaType: "audio",
+	}
+	if mappedModel != originalModel {
+		result.BillingModel = mappedModel
+		result.UpstreamModel = mappedModel
+	}
+
+	var usageSource []byte
+	if clientStream && strings.Contains(strings.ToLower(resp.Header.Get("Content-Type")), "text/event-stream") {
+		result.Stream = true
+		usageSource, result.FirstTokenMs = s.relayOpenAIAudioTranscriptionStream(ctx, c, resp, account, start)
+	} else {
+		maxBytes := resolveUpstreamResponseReadLimit(s.cfg)
+		respBody, readErr := readUpstreamResponseBodyLimited(resp.Body, maxBytes)
+		if readErr != nil {
+			return nil, readErr
+		}
+		writeOpenAIPassthroughResponseHeaders(c.Writer.Header(), resp.Header, s.responseHeaderFilter)
+		c.Status(resp.StatusCode)
+		_, _ = c.Writer.Write(respBody)
+		usageSource = respBody
+	}
+
+	applyOpenAIAudioTranscriptionUsage(result, usageSource, estimatedSeconds)
+	result.Duration = time.Since(start)
+	return result, nil
+}
+
+// ForwardAudioSpeech forwards a JSON /v1/audio/speech request and streams the
+// binary audio (or SSE when stream_format=sse) back to the client unchanged.
+// Speech is billed by the number of input characters.
+func (s *OpenAIGatewayService) ForwardAudioSpeech(ctx context.Context, c *gin.Context, account *Account, body []byte) (*OpenAIForwardResult, error) {
+	start := time.Now()
+	if account.IsOpenAIOAuth() {
+		return nil, fmt.Errorf("audio endpoints are not supported by oauth account %d", account.ID)
+	}
+
+	originalModel := strings.TrimSpace(gjson.GetBytes(body, "model").String())
+	mappedModel := account.GetMappedModel(originalModel)
+	if mappedModel != originalModel {
+		if patched, setErr := sjson.SetBytes(body, "model", mappedModel); setErr == nil {
+			body = patched
+		}
+	}
+
+	token, _, err := s.GetAccessToken(ctx, account)
+	if err != nil {
+		return nil, err
+	}
+	targetURL, err := s.resolveOpenAIAudioTargetURL(account, "audio/speech")
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, targetURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("authorization", "Bearer "+token)
+	copyOpenAIAllowedRequestHeaders(req.Header, c.Request.Header)
+	req.Header.Set("content-type", "application/json")
+
+	resp, err := s.doOpenAIAudioRequest(req, account)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, s.handleOpenAIAudioErrorResponse(ctx, c, resp, account)
+	}
+
+	writeOpenAIPassthroughResponseHeaders(c.Writer.Header(), resp.Header, s.responseHeaderFilter)
+	c.Status(resp.StatusCode)
+	firstTokenMs := relayOpenAIAudioBinary(ctx, c, resp.Body, account, start)
+
+	characters := utf8.RuneCountInString(gjson.GetBytes(body, "input").String())
+	result := &OpenAIForwardResult{
+		RequestID:       strings.TrimSpace(resp.Header.Get("x-request-id")),
+		Model:           originalModel,
+		Stream:          true,
+		Duration:        time.Since(start),
+		FirstTokenMs:    firstTokenMs,
+		AudioCharacters: characters,
+		MediaType:       "audio",
+	}
+	if mappedModel != originalModel {
+		result.BillingModel = mappedModel
+		result.UpstreamModel = mappedModel
+	}
+	return result, nil
+}
+
+func (s *OpenAIGatewayService) resolveOpenAIAudioTargetURL(account *Account, endpoint string) (string, error) {
+	if baseURL := strings.TrimSpace(account.GetOpenAIBaseURL()); baseURL != "" {
+		validatedURL, err := s.validateUpstreamBaseURL(baseURL)
+		if err != nil {
+			return "", err
+		}
+		return buildOpenAIEndpointURL(validatedURL, endpoint), nil
+	}
+	return buildOpenAIEndpointURL("https://api.openai.com", endpoint), nil
+}
+
+func (s *OpenAIGatewayService) doOpenAIAudioRequest(req *http.Request, account *Account) (*http.Response, error) {
+	proxyURL := ""
+	if account.ProxyID != nil && account.Proxy != nil {
+		proxyURL = account.Proxy.URL()
+	}
+	resp, err := s.httpUpstream.Do(req, proxyURL, account.ID, account.Concurrency)
+	if err != nil {
+		return nil, fmt.Errorf("upstream request failed: %w", err)
+	}
+	return resp, nil
+}
+
+// handleOpenAIAudioErrorResponse 处理非 2xx 响应：可 failover 时不写响应，交由 handler 统一输出
+func (s *OpenAIGatewayService) handleOpenAIAudioErrorResponse(ctx context.Context, c *gin.Context, resp *http.Response, account *Account) error {
+	maxBytes := resolveUpstreamResponseReadLimit(s.cfg)
+	respBody, err := readUpstreamResponseBodyLimited(resp.Body, maxBytes)
+	if err != nil {
+		return err
+	}
+	if s.shouldFailoverUpstreamError(resp.StatusCode) {
+		resp.Body = io.NopCloser(bytes.NewReader(respBody))
+		s.handleFailoverSideEffects(ctx, resp, account)
+		return &UpstreamFailoverError{StatusCode: resp.StatusCode, ResponseBody: respBody}
+	}
+	writeOpenAIPassthroughResponseHeaders(c.Writer.Header(), resp.Header, s.responseHeaderFilter)
+	c.Status(resp.StatusCode)
+	_, _ = c.Writer.Write(respBody)
+	return fmt.Errorf("upstream error: %d message=%s", resp.StatusCode, sanitizeUpstreamErrorMessage(strings.TrimSpace(extractUpstreamErrorMessage(respBody))))
+}
+
+// relayOpenAIAudioTranscriptionStream 透传转写 SSE，返回携带 usage 的最后一个事件
+func (s *OpenAIGatewayService) relayOpenAIAudioTranscriptionStream(ctx context.Context, c *gin.Context, resp *http.Response, account *Account, start time.Time) ([]byte, *int) {
+	writeOpenAIPassthroughResponseHeaders(c.Writer.Header(), resp.Header, s.responseHeaderFilter)
+	c.Status(resp.StatusCode)
+	flusher, _ := c.Writer.(http.Flusher)
+
+	scanner := bufio.NewScanner(resp.Body)
+	maxLineSize := defaultMaxLineSize
+	if s.cfg != nil && s.cfg.Gateway.MaxLineSize > 0 {
+		maxLineSize = s.cfg.Gateway.MaxLineSize
+	}
+	scanBuf := getSSEScannerBuf64K()
+	scanner.Buffer(scanBuf[:0], maxLineSize)
+	defer putSSEScannerBuf64K(scanBuf)
+
+	var usageEvent []byte
+	var firstTokenMs *int
+	clientDisconnected := false
+	for scanner.Scan() {
+		line := scanner.Text()
+		if data, ok := extractOpenAISSEDataLine(line); ok {
+			trimmed := strings.TrimSpace(data)
+			if firstTokenMs == nil && trimmed != "" && trimmed != "[DONE]" {
+				ms := int(time.Since(start).Milliseconds())
+				firstTokenMs = &ms
+			}
+			if gjson.Get(trimmed, "usage").Exists() {
+				usageEvent = []byte(trimmed)
+			}
+		}
+		if clientDisconnected {
+			continue
+		}
+		if _, err := fmt.Fprintln(c.Writer, line); err != nil {
+			clientDisconnected = true
+			continue
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+	}
+	if err := scanner.Err(); err != nil && ctx.Err() == nil {
+		logger.LegacyPrintf("service.openai_gateway", "[OpenAI audio] transcription stream read error: account=%d err=%v", account.ID, err)
+	}
+	return usageEvent, firstTokenMs
+}
+
+// relayOpenAIAudioBinary 以固定块大小透传音频字节并逐块 flush，返回首字节耗时
+func relayOpenAIAudioBinary(ctx context.Context, c *gin.Context, body io.Reader, account *Account, start time.Time) *int {
+	flusher, _ := c.Writer.(http.Flusher)
+	buf := make([]byte, 32*1024)
+	var firstByteMs *int
+	for {
+		n, readErr := body.Read(buf)
+		if n > 0 {
+			if firstByteMs == nil {
+				ms := int(time.Since(start).Milliseconds())
+				firstByteMs = &ms
+			}
+			if _, err := c.Writer.Write(buf[:n]); err != nil {
+				logger.LegacyPrintf("service.openai_gateway", "[OpenAI audio] client disconnected during speech relay: account=%d err=%v", account.ID, err)
+				return firstByteMs
+			}
+			if flusher != nil {
+				flusher.Flush()
+			}
+		}
+		if readErr != nil {
+			if readErr != io.EOF && ctx.Err() == nil {
+				logger.LegacyPrintf("service.openai_gateway", "[OpenAI audio] speech relay read error: account=%d err=%v", account.ID, readErr)
+			}
+			return firstByteMs
+		}
+	}
+}
+
+// applyOpenAIAudioTranscriptionUsage 从上游响应提取计费用量。
+// usage.type=tokens 时按 token 计费；否则按音频时长（usage.seconds → duration → 本地估算）。
+func applyOpenAIAudioTranscriptionUsage(result *OpenAIForwardResult, body []byte, estimatedSeconds float64) {
+	if len(body) > 0 && gjson.ValidBytes(body) {
+		usage := gjson.GetBytes(body, "usage")
+		switch usage.Get("type").String() {
+		case "tokens":
+			result.Usage.InputTokens = int(usage.Get("input_tokens").Int())
+			result.Usage.OutputTokens = int(usage.Get("output_tokens").Int())
+		case "duration":
+			result.AudioDurationSeconds = usage.Get("seconds").Float()
+		}
+		if result.AudioDurationSeconds <= 0 {
+			result.AudioDurationSeconds = gjson.GetBytes(body, "duration").Float()
+		}
+	}
+	if result.AudioDurationSeconds <= 0 {
+		result.AudioDurationSeconds = estimatedSeconds
+	}
+}
+
+func copyOpenAIAllowedRequestHeaders(dst http.Header, src http.Header) {
+	for key, values := range src {
+		if openaiAllowedHeaders[strings.ToLower(key)] {
+			for _, value := range values {
+				dst.Add(key, value)
+			}
+		}
+	}
+}
+
+// copyAudioFormFile 复制上传的音频文件到上游表单，并返回估算的音频时长（秒）
+func copyAudioFormFile(writer *multipart.Writer, key string, fileHeader *multipart.FileHeader) (float64, error) {
+	file, err := fileHeader.Open()
+	if err != nil {
+		return 0, fmt.Errorf("open uploaded file: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	header := make([]byte, 44)
+	n, _ := io.ReadFull(file, header)
+	header = header[:n]
+
+	part, err := writer.CreateFormFile(key, fileHeader.Filename)
+	if err != nil {
+		return 0, fmt.Errorf("create form file: %w", err)
+	}
+	if _, err := part.Write(header); err != nil {
+		return 0, fmt.Errorf("copy file content: %w", err)
+	}
+	if _, err := io.Copy(part, file); err != nil {
+		return 0, fmt.Errorf("copy file content: %w", err)
+	}
+	return estimateAudioDurationSeconds(header, fileHeader.Size), nil
+}
+
+// EstimateAudioFileDurationSeconds 读取上传音频的头部估算时长（秒），用于转发前的预授权冻结
+func EstimateAudioFileDurationSeconds(fileHeader *multipart.FileHeader) float64 {
+	if fileHeader == nil {
+		return 0
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		return estimateAudioDurationSeconds(nil, fileHeader.Size)
+	}
+	defer func() { _ = file.Close() }()
+	header := make([]byte, 44)
+	n, _ := io.ReadFull(file, header)
+	return estimateAudioDurationSeconds(header[:n], fileHeader.Size)
+}
+
+// estimateAudioDurationSeconds 估算音频时长：WAV 按头部字节率精确计算，其他格式按 128kbps 估算
+func estimateAudioDurationSeconds(header []byte, size int64) float64 {
+	if size <= 0 {
+		return 0
+	}
+	if len(header) >= 44 && string(header[0:4]) == "RIFF" && string(header[8:12]) == "WAVE" {
+		byteRate := binary.LittleEndian.Uint32(header[28:32])
+		if byteRate > 0 {
+			dataSize := size - 44
+			if dataSize < 0 {
+				dataSize = 0
+			}
+			return float64(dataSize) / float64(byteRate)
+		}
+	}
+	return float64(size) / openAIAudioFallbackBytesPerSecond
+}
@@ -0,0 +1,178 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Wei-Shaw/sub2api/internal/config"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+)
+
+func newAudioTestAccount() *Account {
+	return &Account{
+		ID:          1,
+		Name:        "audio",
+		Platform:    PlatformOpenAI,
+		Type:        AccountTypeAPIKey,
+		Status:      StatusActive,
+		Schedulable: true,
+		Credentials: map[string]any{
+			"api_key":       "sk-test",
+			"model_mapping": map[string]any{"whisper-1": "whisper-large-v3", "tts-1": "tts-1-hd"},
+		},
+	}
+}
+
+func newAudioMultipartRequest(t *testing.T, fields map[string]string, fileName string, fileContent []byte) *http.Request {
+	t.Helper()
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+	for key, value := range fields {
+		require.NoError(t, writer.WriteField(key, value))
+	}
+	part, err := writer.CreateFormFile("file", fileName)
+	require.NoError(t, err)
+	_, err = part.Write(fileContent)
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+	req := httptest.NewRequest(http.MethodPost, "/v1/audio/transcriptions", &buf)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	return req
+}
+
+func buildTestWAV(seconds int) []byte {
+	const byteRate = 32000 // 16kHz * 16bit * mono
+	header := make([]byte, 44)
+	copy(header[0:4], "RIFF")
+	copy(header[8:12], "WAVE")
+	binary.LittleEndian.PutUint32(header[28:32], byteRate)
+	return append(header, make([]byte, byteRate*seconds)...)
+}
+
+func TestOpenAIForwardAudioTranscription_ModelMappingAndUsageSeconds(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	upstream := &queuedOpenAIHTTPUpstream{
+		responses: []*http.Response{{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": []string{"application/json"}, "X-Request-Id": []string{"req_a"}},
+			Body:       io.NopCloser(strings.NewReader(`{"text":"hello","usage":{"type":"duration","seconds":7}}`)),
+		}},
+	}
+	svc := &OpenAIGatewayService{httpUpstream: upstream, cfg: &config.Config{}}
+	rec := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(rec)
+	c.Request = newAudioMultipartRequest(t, map[string]string{"model": "whisper-1"}, "a.wav", buildTestWAV(2))
+
+	result, err := svc.ForwardAudioTranscription(context.Background(), c, newAudioTestAccount(), OpenAIAudioEndpointTranscriptions)
+	require.NoError(t, err)
+	require.Contains(t, string(upstream.requestBodies[0]), "whisper-large-v3")
+	require.Equal(t, "hello", gjson.Get(rec.Body.String(), "text").String())
+	require.Equal(t, "req_a", result.RequestID)
+	require.Equal(t, "whisper-1", result.Model)
+	require.Equal(t, "whisper-large-v3", result.BillingModel)
+	require.InDelta(t, 7.0, result.AudioDurationSeconds, 0.001)
+	require.Equal(t, "audio", result.MediaType)
+}
+
+func TestOpenAIForwardAudioTranscription_EstimatesDurationFromWAV(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	upstream := &queuedOpenAIHTTPUpstream{
+		responses: []*http.Response{{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": []string{"text/plain"}},
+			Body:       io.NopCloser(strings.NewReader("hello")),
+		}},
+	}
+	svc := &OpenAIGatewayService{httpUpstream: upstream, cfg: &config.Config{}}
+	rec := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(rec)
+	c.Request = newAudioMultipartRequest(t, map[string]string{"model": "whisper-1", "response_format": "text"}, "a.wav", buildTestWAV(3))
+
+	result, err := svc.ForwardAudioTranscription(context.Background(), c, newAudioTestAccount(), OpenAIAudioEndpointTranslations)
+	require.NoError(t, err)
+	require.Equal(t, "hello", rec.Body.String())
+	require.InDelta(t, 3.0, result.AudioDurationSeconds, 0.001)
+}
+
+func TestOpenAIForwardAudioTranscription_TokenUsage(t *testing.T) {
+	result := &OpenAIForwardResult{}
+	applyOpenAIAudioTranscriptionUsage(result, []byte(`{"text":"hi","usage":{"type":"tokens","input_tokens":30,"output_tokens":4}}`), 5)
+	require.Equal(t, 30, result.Usage.InputTokens)
+	require.Equal(t, 4, result.Usage.OutputTokens)
+	require.InDelta(t, 5.0, result.AudioDurationSeconds, 0.001)
+}
+
+func TestOpenAIForwardAudioSpeech_StreamsBinaryAndCountsCharacters(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	audio := []byte{0xff, 0xf3, 0x00, 0x01, 0x02}
+	upstream := &queuedOpenAIHTTPUpstream{
+		responses: []*http.Response{{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": []string{"audio/mpeg"}},
+			Body:       io.NopCloser(bytes.NewReader(audio)),
+		}},
+	}
+	svc := &OpenAIGatewayService{httpUpstream: upstream, cfg: &config.Config{}}
+	rec := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(rec)
+	body := []byte(`{"model":"tts-1","input":"你好, world","voice":"alloy"}`)
+	c.Request = httptest.NewRequest(http.MethodPost, "/v1/audio/speech", bytes.NewReader(body))
+
+	result, err := svc.ForwardAudioSpeech(context.Background(), c, newAudioTestAccount(), body)
+	require.NoError(t, err)
+	require.Equal(t, "tts-1-hd", gjson.GetBytes(upstream.requestBodies[0], "model").String())
+	require.Equal(t, audio, rec.Body.Bytes())
+	require.Equal(t, "audio/mpeg", rec.Header().Get("Content-Type"))
+	require.Equal(t, 9, result.AudioCharacters)
+	require.Equal(t, "tts-1-hd", result.BillingModel)
+}
+
+func TestOpenAIForwardAudioSpeech_FailoverDoesNotWriteResponse(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	upstream := &queuedOpenAIHTTPUpstream{
+		responses: []*http.Response{{
+			StatusCode: http.StatusServiceUnavailable,
+			Header:     http.Header{"Content-Type": []string{"application/json"}},
+			Body:       io.NopCloser(strings.NewReader(`{"error":{"message":"overloaded"}}`)),
+		}},
+	}
+	svc := &OpenAIGatewayService{httpUpstream: upstream, cfg: &config.Config{}}
+	rec := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(rec)
+	body := []byte(`{"model":"tts-1","input":"hi","voice":"alloy"}`)
+	c.Request = httptest.NewRequest(http.MethodPost, "/v1/audio/speech", bytes.NewReader(body))
+
+	_, err := svc.ForwardAudioSpeech(context.Background(), c, newAudioTestAccount(), body)
+	var failoverErr *UpstreamFailoverError
+	require.ErrorAs(t, err, &failoverErr)
+	require.Equal(t, 0, rec.Body.Len())
+}
+
+func TestCalculateAudioCost_GroupAndDefaultPricing(t *testing.T) {
+	svc := &BillingService{}
+
+	perMinute := money.MustParse("0.012")
+	cost := svc.CalculateAudioTranscriptionCost("whisper-1", 90, &AudioPriceConfig{PricePerMinute: &perMinute}, 2.0)
+	require.InDelta(t, 0.018, cost.TotalCost.Float64(), 1e-9)
+	require.InDelta(t, 0.036, cost.ActualCost.Float64(), 1e-9)
+
+	cost = svc.CalculateAudioTranscriptionCost("whisper-1", 60, nil, 1.0)
+	require.InDelta(t, defaultAudioPricePerMinute, cost.TotalCost.Float64(), 1e-9)
+
+	perMillion := 30 * money.USD
+	cost = svc.CalculateAudioSpeechCost("tts-1-hd", 1000, &AudioPriceConfig{SpeechPricePer1MChars: &perMillion}, 1.0)
+	require.InDelta(t, 0.03, cost.TotalCost.Float64(), 1e-9)
+
+	cost = svc.CalculateAudioSpeechCost("tts-1", 0, nil, 1.0)
+	require.Zero(t, cost.TotalCost)
+}
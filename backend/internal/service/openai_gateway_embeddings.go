@@ -0,0 +1,145 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tidwall/gjson"
+	"github.com/tidwall/sjson"
+)
+
+// ForwardEmbeddings forwards an OpenAI /v1/embeddings request to an
+// OpenAI-compatible API Key account. The account's model_mapping is applied
+// and the client-facing model is restored in the response. Input tokens come
+// from usage.prompt_tokens, falling back to a local estimate when the
+// upstream omits usage.
+func (s *OpenAIGatewayService) ForwardEmbeddings(ctx context.Context, c *gin.Context, account *Account, body []byte) (*OpenAIForwardResult, error) {
+	start := time.Now()
+	if account.IsOpenAIOAuth() {
+		return nil, fmt.Errorf("embeddings are not supported by oauth account %d", account.ID)
+	}
+	token, _, err := s.GetAccessToken(ctx, account)
+	if err != nil {
+		return nil, err
+	}
+
+	originalModel := strings.TrimSpace(gjson.GetBytes(body, "model").String())
+	mappedModel := account.GetMappedModel(originalModel)
+	if mappedModel != originalModel {
+		if patched, setErr := sjson.SetBytes(body, "model", mappedModel); setErr == nil {
+			body = patched
+		}
+	}
+
+	targetURL := buildOpenAIEndpointURL("https://api.openai.com", "embeddings")
+	if baseURL := strings.TrimSpace(account.GetOpenAIBaseURL()); baseURL != "" {
+		validatedURL, validateErr := s.validateUpstreamBaseURL(baseURL)
+		if validateErr != nil {
+			return nil, validateErr
+		}
+		targetURL = buildOpenAIEndpointURL(validatedURL, "embeddings")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, targetURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("authorization", "Bearer "+token)
+	for key, values := range c.Request.Header {
+		lowerKey := strings.ToLower(key)
+		if openaiAllowedHeaders[lowerKey] {
+			for _, value := range values {
+				req.Header.Add(key, value)
+			}
+		}
+	}
+	req.Header.Set("content-type", "application/json")
+
+	proxyURL := ""
+	if account.ProxyID != nil && account.Proxy != nil {
+		proxyURL = account.Proxy.URL()
+	}
+
+	resp, err := s.httpUpstream.Do(req, proxyURL, account.ID, account.Concurrency)
+	if err != nil {
+		return nil, fmt.Errorf("upstream request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	maxBytes := resolveUpstreamResponseReadLimit(s.cfg)
+	respBody, err := readUpstreamResponseBodyLimited(resp.Body, maxBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		// failover 时由 handler 统一输出错误，避免重复写入响应
+		if s.shouldFailoverUpstreamError(resp.StatusCode) {
+			resp.Body = io.NopCloser(bytes.NewReader(respBody))
+			s.handleFailoverSideEffects(ctx, resp, account)
+			return nil, &UpstreamFailoverError{StatusCode: resp.StatusCode, ResponseBody: respBody}
+		}
+		writeOpenAIPassthroughResponseHeaders(c.Writer.Header(), resp.Header, s.responseHeaderFilter)
+		c.Status(resp.StatusCode)
+		_, _ = c.Writer.Write(respBody)
+		return nil, fmt.Errorf("upstream error: %d message=%s", resp.StatusCode, sanitizeUpstreamErrorMessage(strings.TrimSpace(extractUpstreamErrorMessage(respBody))))
+	}
+
+	if mappedModel != originalModel && gjson.GetBytes(respBody, "model").Exists() {
+		if patched, setErr := sjson.SetBytes(respBody, "model", originalModel); setErr == nil {
+			respBody = patched
+		}
+	}
+	writeOpenAIPassthroughResponseHeaders(c.Writer.Header(), resp.Header, s.responseHeaderFilter)
+	c.Status(resp.StatusCode)
+	_, _ = c.Writer.Write(respBody)
+
+	inputTokens := int(gjson.GetBytes(respBody, "usage.prompt_tokens").Int())
+	if inputTokens <= 0 {
+		inputTokens = int(gjson.GetBytes(respBody, "usage.total_tokens").Int())
+	}
+	result := &OpenAIForwardResult{
+		RequestID:            strings.TrimSpace(resp.Header.Get("x-request-id")),
+		Usage:                OpenAIUsage{InputTokens: inputTokens},
+		Model:                originalModel,
+		Stream:               false,
+		Duration:             time.Since(start),
+		EstimatedInputTokens: estimateOpenAIEmbeddingInputTokens(body),
+		MediaType:            "embedding",
+	}
+	if mappedModel != originalModel {
+		result.BillingModel = mappedModel
+		result.UpstreamModel = mappedModel
+	}
+	return result, nil
+}
+
+// estimateOpenAIEmbeddingInputTokens estimates the tokens of an embeddings
+// input, which may be a string, a list of strings, or pre-tokenized arrays.
+func estimateOpenAIEmbeddingInputTokens(body []byte) int {
+	input := gjson.GetBytes(body, "input")
+	if !input.Exists() {
+		return 0
+	}
+	if !input.IsArray() {
+		return estimateOpenAITextTokens(input.String())
+	}
+	total := 0
+	for _, item := range input.Array() {
+		switch {
+		case item.Type == gjson.Number:
+			total++
+		case item.IsArray():
+			total += len(item.Array())
+		default:
+			total += estimateOpenAITextTokens(item.String())
+		}
+	}
+	return total
+}
@@ -0,0 +1,113 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Wei-Shaw/sub2api/internal/config"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+)
+
+func newEmbeddingsTestAccount() *Account {
+	return &Account{
+		ID:          1,
+		Name:        "embed",
+		Platform:    PlatformOpenAI,
+		Type:        AccountTypeAPIKey,
+		Status:      StatusActive,
+		Schedulable: true,
+		Credentials: map[string]any{
+			"api_key":       "sk-test",
+			"model_mapping": map[string]any{"text-embedding-3-small": "text-embedding-3-large"},
+		},
+	}
+}
+
+func TestOpenAIForwardEmbeddings_ModelMappingAndUsage(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	upstream := &queuedOpenAIHTTPUpstream{
+		responses: []*http.Response{{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": []string{"application/json"}, "X-Request-Id": []string{"req_1"}},
+			Body:       io.NopCloser(strings.NewReader(`{"object":"list","data":[{"object":"embedding","index":0,"embedding":[0.1]}],"model":"text-embedding-3-large","usage":{"prompt_tokens":12,"total_tokens":12}}`)),
+		}},
+	}
+	svc := &OpenAIGatewayService{httpUpstream: upstream, cfg: &config.Config{}}
+	rec := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(rec)
+	body := []byte(`{"model":"text-embedding-3-small","input":["hello","world"]}`)
+	c.Request = httptest.NewRequest(http.MethodPost, "/v1/embeddings", bytes.NewReader(body))
+
+	result, err := svc.ForwardEmbeddings(context.Background(), c, newEmbeddingsTestAccount(), body)
+	require.NoError(t, err)
+	require.Equal(t, "text-embedding-3-large", gjson.GetBytes(upstream.requestBodies[0], "model").String())
+	require.Equal(t, "text-embedding-3-small", gjson.Get(rec.Body.String(), "model").String())
+	require.Equal(t, "req_1", result.RequestID)
+	require.Equal(t, 12, result.Usage.InputTokens)
+	require.Equal(t, "text-embedding-3-small", result.Model)
+	require.Equal(t, "text-embedding-3-large", result.BillingModel)
+	require.Equal(t, "embedding", result.MediaType)
+}
+
+func TestOpenAIForwardEmbeddings_FailoverDoesNotWriteResponse(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	upstream := &queuedOpenAIHTTPUpstream{
+		responses: []*http.Response{{
+			StatusCode: http.StatusTooManyRequests,
+			Header:     http.Header{"Content-Type": []string{"application/json"}},
+			Body:       io.NopCloser(strings.NewReader(`{"error":{"message":"slow down"}}`)),
+		}},
+	}
+	svc := &OpenAIGatewayService{httpUpstream: upstream, cfg: &config.Config{}}
+	rec := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(rec)
+	body := []byte(`{"model":"text-embedding-3-small","input":"hello"}`)
+	c.Request = httptest.NewRequest(http.MethodPost, "/v1/embeddings", bytes.NewReader(body))
+	account := newEmbeddingsTestAccount()
+	account.NeverSuspend = true
+
+	_, err := svc.ForwardEmbeddings(context.Background(), c, account, body)
+	var failoverErr *UpstreamFailoverError
+	require.True(t, errors.As(err, &failoverErr))
+	require.Equal(t, http.StatusTooManyRequests, failoverErr.StatusCode)
+	require.Zero(t, rec.Body.Len())
+}
+
+func TestOpenAIForwardEmbeddings_RejectsOAuthAccount(t *testing.T) {
+	svc := &OpenAIGatewayService{cfg: &config.Config{}}
+	account := &Account{ID: 2, Platform: PlatformOpenAI, Type: AccountTypeOAuth}
+	_, err := svc.ForwardEmbeddings(context.Background(), nil, account, []byte(`{"model":"text-embedding-3-small","input":"x"}`))
+	require.Error(t, err)
+}
+
+func TestEstimateOpenAIEmbeddingInputTokens(t *testing.T) {
+	require.Equal(t, 0, estimateOpenAIEmbeddingInputTokens([]byte(`{"model":"m"}`)))
+	require.Equal(t, estimateOpenAITextTokens("hello world"), estimateOpenAIEmbeddingInputTokens([]byte(`{"input":"hello world"}`)))
+	require.Equal(t, 3, estimateOpenAIEmbeddingInputTokens([]byte(`{"input":[1,2,3]}`)))
+	require.Equal(t, 5, estimateOpenAIEmbeddingInputTokens([]byte(`{"input":[[1,2],[3,4,5]]}`)))
+	require.Equal(t, estimateOpenAITextTokens("hello world")+estimateOpenAITextTokens("foo bar"), estimateOpenAIEmbeddingInputTokens([]byte(`{"input":["hello world","foo bar"]}`)))
+}
+
+func TestEstimateGeminiEmbedTokens(t *testing.T) {
+	single := []byte(`{"content":{"parts":[{"text":"hello world"}]}}`)
+	require.Equal(t, estimateTokensForText("hello world"), estimateGeminiEmbedTokens(single))
+
+	batch := []byte(`{"requests":[{"model":"models/a","content":{"parts":[{"text":"hello world"}]}},{"model":"models/a","content":{"parts":[{"text":"foo bar"}]}}]}`)
+	require.Equal(t, estimateTokensForText("hello world")+estimateTokensForText("foo bar"), estimateGeminiEmbedTokens(batch))
+}
+
+func TestRewriteGeminiBatchEmbedModels(t *testing.T) {
+	body := []byte(`{"requests":[{"model":"models/text-embedding-004","content":{"parts":[{"text":"a"}]}},{"content":{"parts":[{"text":"b"}]}}]}`)
+	out := rewriteGeminiBatchEmbedModels(body, "gemini-embedding-001")
+	require.Equal(t, "models/gemini-embedding-001", gjson.GetBytes(out, "requests.0.model").String())
+	require.Equal(t, "models/gemini-embedding-001", gjson.GetBytes(out, "requests.1.model").String())
+	require.Equal(t, "b", gjson.GetBytes(out, "requests.1.content.parts.0.text").String())
+}
@@ -22,7 +22,9 @@ import (
 
 	"github.com/Wei-Shaw/sub2api/internal/config"
 	"github.com/Wei-Shaw/sub2api/internal/pkg/logger"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
 	"github.com/Wei-Shaw/sub2api/internal/pkg/openai"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/tracing"
 	"github.com/Wei-Shaw/sub2api/internal/util/responseheaders"
 	"github.com/Wei-Shaw/sub2api/internal/util/urlvalidator"
 	"github.com/gin-gonic/gin"
@@ -231,7 +233,11 @@ type OpenAIForwardResult struct {
 	ImageSize             string
 	VideoCount            int
 	VideoQuality          string // "standard" or "high"
-	MediaType             string
+	// AudioDurationSeconds is the input audio length for transcription/translation billing.
+	AudioDurationSeconds float64
+	// AudioCharacters is the input text length for text-to-speech billing.
+	AudioCharacters int
+	MediaType       string
 }
 
 type OpenAIWSRetryMetricsSnapshot struct {
@@ -280,6 +286,8 @@ type OpenAIGatewayService struct {
 	httpUpstream        HTTPUpstream
 	deferredService     *DeferredService
 	openAITokenProvider *OpenAITokenProvider
+	usageJournal        *UsageJournalService
+	apiKeyTraffic       *APIKeyTrafficLimitService
 	toolCorrector       *CodexToolCorrector
 	openaiWSResolver    OpenAIWSProtocolResolver
 
@@ -312,6 +320,8 @@ func NewOpenAIGatewayService(
 	httpUpstream HTTPUpstream,
 	deferredService *DeferredService,
 	openAITokenProvider *OpenAITokenProvider,
+	usageJournal *UsageJournalService,
+	apiKeyTraffic *APIKeyTrafficLimitService,
 ) *OpenAIGatewayService {
 	svc := &OpenAIGatewayService{
 		accountRepo:          accountRepo,
@@ -329,6 +339,8 @@ func NewOpenAIGatewayService(
 		httpUpstream:         httpUpstream,
 		deferredService:      deferredService,
 		openAITokenProvider:  openAITokenProvider,
+		usageJournal:         usageJournal,
+		apiKeyTraffic:        apiKeyTraffic,
 		toolCorrector:        NewCodexToolCorrector(),
 		openaiWSResolver:     NewOpenAIWSProtocolResolver(cfg),
 		responseHeaderFilter: compileResponseHeaderFilter(cfg),
@@ -3890,6 +3902,7 @@ type OpenAIRecordUsageInput struct {
 	UserAgent     string // 请求的 User-Agent
 	IPAddress     string // 请求的客户端 IP 地址
 	APIKeyService APIKeyQuotaUpdater
+	BillingHold   *BillingHold // optional pre-authorization hold, settled to the actual cost
 }
 
 type openAIAccountStoredModelPricing struct {
@@ -3899,7 +3912,10 @@ type openAIAccountStoredModelPricing struct {
 }
 
 // RecordUsage records usage and deducts balance
-func (s *OpenAIGatewayService) RecordUsage(ctx context.Context, input *OpenAIRecordUsageInput) error {
+func (s *OpenAIGatewayService) RecordUsage(ctx context.Context, input *OpenAIRecordUsageInput) (err error) {
+	ctx, span := startUsageRecordSpan(ctx, input.APIKey, input.Account, input.Result.Model)
+	defer func() { tracing.End(span, err) }()
+
 	result := input.Result
 	apiKey := input.APIKey
 	user := input.User
@@ -3923,6 +3939,7 @@ func (s *OpenAIGatewayService) RecordUsage(ctx context.Context, input *OpenAIRec
 	if apiKey.GroupID != nil && apiKey.Group != nil {
 		multiplier = apiKey.Group.RateMultiplier
 	}
+	multiplier = s.billingService.ApplyBatchDiscount(multiplier, apiKey)
 
 	// Determine billing model (support Anthropic Messages path)
 	billingModel := result.Model
@@ -3945,7 +3962,8 @@ func (s *OpenAIGatewayService) RecordUsage(ctx context.Context, input *OpenAIRec
 		}
 		if imageConfig.PricePerImage == nil {
 			if manual := lookupOpenAIAccountStoredImagePricing(account, billingModel); manual > 0 {
-				imageConfig.PricePerImage = &manual
+				price := money.FromFloat(manual)
+				imageConfig.PricePerImage = &price
 			}
 		}
 		cost = s.billingService.CalculateImageCost(billingModel, result.ImageSize, result.ImageCount, imageConfig, multiplier)
@@ -3965,13 +3983,28 @@ func (s *OpenAIGatewayService) RecordUsage(ctx context.Context, input *OpenAIRec
 		// 查找手动定价：先尝试 image_price_per_image，再尝试视频专用字段
 		if imageConfig.PricePerImage == nil {
 			if manual := lookupOpenAIAccountStoredImagePricing(account, billingModel); manual > 0 {
-				imageConfig.PricePerImage = &manual
+				price := money.FromFloat(manual)
+				imageConfig.PricePerImage = &price
 			} else if manual := lookupOpenAIAccountStoredVideoPricing(account, billingModel, result.VideoQuality); manual > 0 {
-				imageConfig.PricePerImage = &manual
+				price := money.FromFloat(manual)
+				imageConfig.PricePerImage = &price
 			}
 		}
 		// 使用图片计费函数，传入视频数量作为图片数量
 		cost = s.billingService.CalculateImageCost(billingModel, "video", result.VideoCount, imageConfig, multiplier)
+	} else if result.Usage.InputTokens == 0 && result.Usage.OutputTokens == 0 && (result.AudioDurationSeconds > 0 || result.AudioCharacters > 0) {
+		// 音频：转写/翻译按时长计费，语音合成按字符计费（上游返回 token 用量时走 token 计费）
+		audioConfig := &AudioPriceConfig{}
+		if apiKey.Group != nil {
+			audioConfig.PricePerMinute = apiKey.Group.AudioPricePerMinute
+			audioConfig.SpeechPricePer1MChars = apiKey.Group.AudioSpeechPricePer1MChars
+		}
+		if result.AudioCharacters > 0 {
+			cost = s.billingService.CalculateAudioSpeechCost(billingModel, result.AudioCharacters, audioConfig, multiplier)
+		} else {
+			cost = s.billingService.CalculateAudioTranscriptionCost(billingModel, result.AudioDurationSeconds, audioConfig, multiplier)
+		}
+		actualInputTokens, outputTokens = 0, 0
 	} else {
 		tokens := UsageTokens{
 			InputTokens:         actualInputTokens,
@@ -3993,6 +4026,8 @@ func (s *OpenAIGatewayService) RecordUsage(ctx context.Context, input *OpenAIRec
 		billingType = BillingTypeSubscription
 	}
 
+	observeFirstTokenMetric(apiKey, account, result.Model, result.FirstTokenMs)
+
 	// Create usage log
 	durationMs := int(result.Duration.Milliseconds())
 	accountRateMultiplier := account.BillingRateMultiplier()
@@ -4030,6 +4065,14 @@ func (s *OpenAIGatewayService) RecordUsage(ctx context.Context, input *OpenAIRec
 		mediaType := result.MediaType
 		usageLog.MediaType = &mediaType
 	}
+	if result.AudioDurationSeconds > 0 {
+		audioDuration := result.AudioDurationSeconds
+		usageLog.AudioDurationSeconds = &audioDuration
+	}
+	if result.AudioCharacters > 0 {
+		audioCharacters := result.AudioCharacters
+		usageLog.AudioCharacters = &audioCharacters
+	}
 
 	// 添加 UserAgent
 	if input.UserAgent != "" {
@@ -4048,6 +4091,28 @@ func (s *OpenAIGatewayService) RecordUsage(ctx context.Context, input *OpenAIRec
 		usageLog.SubscriptionID = &subscription.ID
 	}
 
+	// Shrink the pre-authorization hold to the actual cost
+	input.BillingHold.Settle(ctx, cost)
+	// Count tokens toward the API key's TPM window
+	s.apiKeyTraffic.RecordUsage(ctx, apiKey, usageLog)
+	// Count toward the creating member's monthly spend (organization keys)
+	s.billingCacheService.QueueAddOrgMemberSpend(apiKey, cost.ActualCost)
+
+	// Durable journal: the consumer applies usage and billing; fall back to inline on append failure
+	if s.usageJournal.Enabled() {
+		var billedSubscription *UserSubscription
+		if isSubscriptionBilling {
+			billedSubscription = subscription
+		}
+		chargeAPIKey := input.APIKeyService != nil && cost.ActualCost > 0
+		cmd := newUsageBillingCommand(usageLog, s.cfg == nil || s.cfg.RunMode != config.RunModeSimple, billedSubscription, cost,
+			chargeAPIKey && apiKey.Quota > 0, chargeAPIKey && apiKey.HasRateLimits())
+		if s.usageJournal.TryAppend(ctx, cmd) {
+			s.deferredService.ScheduleLastUsedUpdate(account.ID)
+			return nil
+		}
+	}
+
 	inserted, err := s.usageLogRepo.Create(ctx, usageLog)
 	if s.cfg != nil && s.cfg.RunMode == config.RunModeSimple {
 		logger.LegacyPrintf("service.openai_gateway", "[SIMPLE MODE] Usage recorded (not billed): user=%d, tokens=%d", usageLog.UserID, usageLog.TotalTokens())
@@ -4065,7 +4130,7 @@ func (s *OpenAIGatewayService) RecordUsage(ctx context.Context, input *OpenAIRec
 		}
 	} else {
 		if shouldBill && cost.ActualCost > 0 {
-			_ = s.userRepo.DeductBalance(ctx, user.ID, cost.ActualCost)
+			_ = s.userRepo.DeductBalance(ctx, user.ID, cost.ActualCost, usageLedgerSource(usageLog))
 			s.billingCacheService.QueueDeductBalance(user.ID, cost.ActualCost)
 		}
 	}
@@ -2448,6 +2448,14 @@ func (s *OpenAIGatewayService) ProxyResponsesWebSocketFromClient(
 				nil,
 			)
 		}
+		// 同一连接内后续 response.create 可切换模型，逐轮校验 API Key 模型限制
+		if apiKey := getOpenAIAPIKeyFromContext(c); apiKey != nil && !apiKey.IsModelAllowed(originalModel) {
+			return openAIWSClientPayload{}, NewOpenAIWSClientCloseError(
+				coderws.StatusPolicyViolation,
+				fmt.Sprintf("model %q is not allowed for this API key", originalModel),
+				nil,
+			)
+		}
 		promptCacheKey := strings.TrimSpace(values[2].String())
 		previousResponseID := strings.TrimSpace(values[3].String())
 		previousResponseIDKind := ClassifyOpenAIPreviousResponseIDKind(previousResponseID)
@@ -3732,6 +3740,18 @@ func populateOpenAIUsageFromResponseJSON(body []byte, usage *OpenAIUsage) {
 	usage.CacheReadInputTokens = int(values[2].Int())
 }
 
+func getOpenAIAPIKeyFromContext(c *gin.Context) *APIKey {
+	if c == nil {
+		return nil
+	}
+	value, exists := c.Get("api_key")
+	if !exists {
+		return nil
+	}
+	apiKey, _ := value.(*APIKey)
+	return apiKey
+}
+
 func getOpenAIGroupIDFromContext(c *gin.Context) int64 {
 	if c == nil {
 		return 0
@@ -32,9 +32,11 @@ return 0
 `)
 
 type OpsAlertEvaluatorService struct {
-	opsService   *OpsService
-	opsRepo      OpsRepository
-	emailService *EmailService
+	opsService          *OpsService
+	opsRepo             OpsRepository
+	emailService        *EmailService
+	notificationService *OpsNotificationService
+	balanceLedger       *BalanceLedgerService
 
 	redisClient *redis.Client
 	cfg         *config.Config
@@ -65,21 +67,28 @@ func NewOpsAlertEvaluatorService(
 	opsService *OpsService,
 	opsRepo OpsRepository,
 	emailService *EmailService,
+	notificationService *OpsNotificationService,
 	redisClient *redis.Client,
 	cfg *config.Config,
 ) *OpsAlertEvaluatorService {
 	return &OpsAlertEvaluatorService{
-		opsService:   opsService,
-		opsRepo:      opsRepo,
-		emailService: emailService,
-		redisClient:  redisClient,
-		cfg:          cfg,
-		instanceID:   uuid.NewString(),
-		ruleStates:   map[int64]*opsAlertRuleState{},
-		emailLimiter: newSlidingWindowLimiter(0, time.Hour),
+		opsService:          opsService,
+		opsRepo:             opsRepo,
+		emailService:        emailService,
+		notificationService: notificationService,
+		redisClient:         redisClient,
+		cfg:                 cfg,
+		instanceID:          uuid.NewString(),
+		ruleStates:          map[int64]*opsAlertRuleState{},
+		emailLimiter:        newSlidingWindowLimiter(0, time.Hour),
 	}
 }
 
+// SetBalanceLedgerService 设置余额流水服务（可选依赖），用于 balance_ledger_mismatch_count 指标
+func (s *OpsAlertEvaluatorService) SetBalanceLedgerService(balanceLedger *BalanceLedgerService) {
+	s.balanceLedger = balanceLedger
+}
+
 func (s *OpsAlertEvaluatorService) Start() {
 	if s == nil {
 		return
@@ -196,6 +205,7 @@ func (s *OpsAlertEvaluatorService) evaluateOnce(interval time.Duration) {
 	eventsCreated := 0
 	eventsResolved := 0
 	emailsSent := 0
+	notificationsQueued := 0
 
 	now := time.Now().UTC()
 	safeEnd := now.Truncate(time.Minute)
@@ -292,6 +302,7 @@ func (s *OpsAlertEvaluatorService) evaluateOnce(interval time.Duration) {
 				if s.maybeSendAlertEmail(ctx, runtimeCfg, rule, created) {
 					emailsSent++
 				}
+				notificationsQueued += s.notificationService.NotifyAlert(ctx, OpsNotificationKindFiring, runtimeCfg, rule, created)
 			}
 			continue
 		}
@@ -303,11 +314,15 @@ func (s *OpsAlertEvaluatorService) evaluateOnce(interval time.Duration) {
 				logger.LegacyPrintf("service.ops_alert_evaluator", "[OpsAlertEvaluator] resolve event failed (event=%d): %v", activeEvent.ID, err)
 			} else {
 				eventsResolved++
+				resolvedEvent := *activeEvent
+				resolvedEvent.Status = OpsAlertStatusResolved
+				resolvedEvent.ResolvedAt = &resolvedAt
+				notificationsQueued += s.notificationService.NotifyAlert(ctx, OpsNotificationKindResolved, runtimeCfg, rule, &resolvedEvent)
 			}
 		}
 	}
 
-	result := truncateString(fmt.Sprintf("rules=%d enabled=%d evaluated=%d created=%d resolved=%d emails_sent=%d", rulesTotal, rulesEnabled, rulesEvaluated, eventsCreated, eventsResolved, emailsSent), 2048)
+	result := truncateString(fmt.Sprintf("rules=%d enabled=%d evaluated=%d created=%d resolved=%d emails_sent=%d notifications_queued=%d", rulesTotal, rulesEnabled, rulesEvaluated, eventsCreated, eventsResolved, emailsSent, notificationsQueued), 2048)
 	s.recordHeartbeatSuccess(runAt, time.Since(startedAt), result)
 }
 
@@ -506,6 +521,16 @@ func (s *OpsAlertEvaluatorService) computeRuleMetric(
 		return float64(countAccountsByCondition(availability.Accounts, func(acc *AccountAvailability) bool {
 			return acc.HasError && acc.TempUnschedulableUntil == nil
 		})), true
+	case "balance_ledger_mismatch_count":
+		// 取最近一次对账结果，与时间窗口无关
+		if s == nil || s.balanceLedger == nil {
+			return 0, false
+		}
+		count, ok := s.balanceLedger.LatestMismatchCount(ctx)
+		if !ok {
+			return 0, false
+		}
+		return float64(count), true
 	}
 
 	overview, err := s.opsRepo.GetDashboardOverview(ctx, &OpsDashboardFilter{
@@ -157,6 +157,7 @@ type opsCleanupDeletedCounts struct {
 	errorLogs     int64
 	retryAttempts int64
 	alertEvents   int64
+	deliveries    int64
 	systemLogs    int64
 	logAudits     int64
 	systemMetrics int64
@@ -166,10 +167,11 @@ type opsCleanupDeletedCounts struct {
 
 func (c opsCleanupDeletedCounts) String() string {
 	return fmt.Sprintf(
-		"error_logs=%d retry_attempts=%d alert_events=%d system_logs=%d log_audits=%d system_metrics=%d hourly_preagg=%d daily_preagg=%d",
+		"error_logs=%d retry_attempts=%d alert_events=%d notification_deliveries=%d system_logs=%d log_audits=%d system_metrics=%d hourly_preagg=%d daily_preagg=%d",
 		c.errorLogs,
 		c.retryAttempts,
 		c.alertEvents,
+		c.deliveries,
 		c.systemLogs,
 		c.logAudits,
 		c.systemMetrics,
@@ -188,7 +190,7 @@ func (s *OpsCleanupService) runCleanupOnce(ctx context.Context) (opsCleanupDelet
 
 	now := time.Now().UTC()
 
-	// Error-like tables: error logs / retry attempts / alert events.
+	// Error-like tables: error logs / retry attempts / alert events / notification deliveries.
 	if days := s.cfg.Ops.Cleanup.ErrorLogRetentionDays; days > 0 {
 		cutoff := now.AddDate(0, 0, -days)
 		n, err := deleteOldRowsByID(ctx, s.db, "ops_error_logs", "created_at", cutoff, batchSize, false)
@@ -209,6 +211,12 @@ func (s *OpsCleanupService) runCleanupOnce(ctx context.Context) (opsCleanupDelet
 		}
 		out.alertEvents = n
 
+		n, err = deleteOldRowsByID(ctx, s.db, "ops_notification_deliveries", "created_at", cutoff, batchSize, false)
+		if err != nil {
+			return out, err
+		}
+		out.deliveries = n
+
 		n, err = deleteOldRowsByID(ctx, s.db, "ops_system_logs", "created_at", cutoff, batchSize, false)
 		if err != nil {
 			return out, err
@@ -0,0 +1,103 @@
+package service
+
+import "time"
+
+// Ops notification channel models (IM / webhook delivery for alerts and reports).
+
+const (
+	OpsNotificationChannelWebhook  = "webhook"
+	OpsNotificationChannelSlack    = "slack"
+	OpsNotificationChannelTelegram = "telegram"
+	OpsNotificationChannelDingTalk = "dingtalk"
+	OpsNotificationChannelFeishu   = "feishu"
+	OpsNotificationChannelWeCom    = "wecom"
+)
+
+const (
+	OpsNotificationKindFiring   = "firing"
+	OpsNotificationKindResolved = "resolved"
+	OpsNotificationKindReport   = "report"
+	OpsNotificationKindTest     = "test"
+
+	OpsNotificationDeliverySuccess = "success"
+	OpsNotificationDeliveryFailed  = "failed"
+)
+
+// OpsNotificationChannelTypes 支持的渠道类型
+var OpsNotificationChannelTypes = []string{
+	OpsNotificationChannelWebhook,
+	OpsNotificationChannelSlack,
+	OpsNotificationChannelTelegram,
+	OpsNotificationChannelDingTalk,
+	OpsNotificationChannelFeishu,
+	OpsNotificationChannelWeCom,
+}
+
+type OpsNotificationChannel struct {
+	ID      int64  `json:"id"`
+	Name    string `json:"name"`
+	Type    string `json:"type"`
+	Enabled bool   `json:"enabled"`
+
+	WebhookURL string `json:"webhook_url"`
+	// Secret 为只写字段：Webhook/钉钉/飞书的签名密钥，或 Telegram 的 Bot Token。
+	// 读取时清空，通过 SecretConfigured 表示是否已配置；更新时留空表示保持不变。
+	Secret           string `json:"secret,omitempty"`
+	SecretConfigured bool   `json:"secret_configured"`
+	TelegramChatID   string `json:"telegram_chat_id"`
+
+	// MinSeverity 与邮件告警相同的级别过滤（critical/warning/info），空表示不过滤。
+	MinSeverity string `json:"min_severity"`
+	// RuleIDs 路由到本渠道的告警规则，空表示全部规则。
+	RuleIDs        []int64 `json:"rule_ids"`
+	NotifyResolved bool    `json:"notify_resolved"`
+	SendReports    bool    `json:"send_reports"`
+	// Template 自定义消息模板（Go text/template），空表示使用默认模板。
+	Template string `json:"template"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type OpsNotificationDelivery struct {
+	ID           int64     `json:"id"`
+	ChannelID    int64     `json:"channel_id"`
+	ChannelType  string    `json:"channel_type"`
+	EventID      *int64    `json:"event_id,omitempty"`
+	Kind         string    `json:"kind"`
+	Status       string    `json:"status"`
+	Attempts     int       `json:"attempts"`
+	HTTPStatus   *int      `json:"http_status,omitempty"`
+	ErrorMessage string    `json:"error_message,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type OpsNotificationDeliveryFilter struct {
+	Limit int
+
+	ChannelID *int64
+	EventID   *int64
+	Status    string
+}
+
+// OpsNotificationMessage 渲染消息模板时可用的字段
+type OpsNotificationMessage struct {
+	Kind        string
+	Title       string
+	EventID     int64
+	RuleID      int64
+	RuleName    string
+	Severity    string
+	Status      string
+	MetricType  string
+	Operator    string
+	MetricValue string
+	Threshold   string
+	Description string
+	Dimensions  map[string]any
+	FiredAt     time.Time
+	ResolvedAt  *time.Time
+
+	// Body 报表/测试消息的正文（纯文本）
+	Body string
+}
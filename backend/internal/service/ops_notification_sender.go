@@ -0,0 +1,306 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	opsNotificationSendTimeout      = 10 * time.Second
+	opsNotificationMaxResponseBytes = 64 << 10
+
+	opsTelegramDefaultAPIBase = "https://api.telegram.org"
+
+	// 通用 Webhook 签名头：X-Sub2API-Signature = "sha256=" + hex(HMAC-SHA256(secret, timestamp + "." + body))
+	opsWebhookTimestampHeader = "X-Sub2API-Timestamp"
+	opsWebhookSignatureHeader = "X-Sub2API-Signature"
+	opsWebhookEventHeader     = "X-Sub2API-Event"
+)
+
+// opsNotificationSendError 描述一次投递失败；Retryable 为 false 时不再重试（如 4xx 配置错误）。
+type opsNotificationSendError struct {
+	HTTPStatus int
+	Retryable  bool
+	Message    string
+}
+
+func (e *opsNotificationSendError) Error() string {
+	if e.HTTPStatus > 0 {
+		return fmt.Sprintf("http %d: %s", e.HTTPStatus, e.Message)
+	}
+	return e.Message
+}
+
+// opsWebhookPayload 通用 Webhook 的 JSON 负载
+type opsWebhookPayload struct {
+	Kind        string         `json:"kind"`
+	Title       string         `json:"title"`
+	Text        string         `json:"text"`
+	EventID     int64          `json:"event_id,omitempty"`
+	RuleID      int64          `json:"rule_id,omitempty"`
+	RuleName    string         `json:"rule_name,omitempty"`
+	Severity    string         `json:"severity,omitempty"`
+	Status      string         `json:"status,omitempty"`
+	MetricType  string         `json:"metric_type,omitempty"`
+	Operator    string         `json:"operator,omitempty"`
+	MetricValue string         `json:"metric_value,omitempty"`
+	Threshold   string         `json:"threshold,omitempty"`
+	Description string         `json:"description,omitempty"`
+	Dimensions  map[string]any `json:"dimensions,omitempty"`
+	FiredAt     *time.Time     `json:"fired_at,omitempty"`
+	ResolvedAt  *time.Time     `json:"resolved_at,omitempty"`
+	SentAt      time.Time      `json:"sent_at"`
+}
+
+// sendOpsNotification 按渠道类型构造请求并投递一次（不含重试）。
+func sendOpsNotification(ctx context.Context, client *http.Client, ch *OpsNotificationChannel, msg *OpsNotificationMessage, text string, now time.Time) (int, error) {
+	if ch == nil || msg == nil {
+		return 0, &opsNotificationSendError{Message: "invalid notification"}
+	}
+	if client == nil {
+		client = &http.Client{Timeout: opsNotificationSendTimeout}
+	}
+
+	var (
+		target  string
+		body    []byte
+		headers = map[string]string{}
+		check   func(status int, respBody []byte) error
+		err     error
+	)
+
+	secret := strings.TrimSpace(ch.Secret)
+	switch strings.TrimSpace(ch.Type) {
+	case OpsNotificationChannelWebhook:
+		target = strings.TrimSpace(ch.WebhookURL)
+		body, err = json.Marshal(buildOpsWebhookPayload(msg, text, now))
+		if err != nil {
+			return 0, &opsNotificationSendError{Message: err.Error()}
+		}
+		ts := strconv.FormatInt(now.Unix(), 10)
+		headers[opsWebhookTimestampHeader] = ts
+		headers[opsWebhookEventHeader] = msg.Kind
+		if secret != "" {
+			headers[opsWebhookSignatureHeader] = "sha256=" + signOpsWebhookPayload(secret, ts, body)
+		}
+	case OpsNotificationChannelSlack:
+		target = strings.TrimSpace(ch.WebhookURL)
+		body, err = json.Marshal(map[string]any{"text": text})
+		if err != nil {
+			return 0, &opsNotificationSendError{Message: err.Error()}
+		}
+	case OpsNotificationChannelTelegram:
+		base := strings.TrimRight(strings.TrimSpace(ch.WebhookURL), "/")
+		if base == "" {
+			base = opsTelegramDefaultAPIBase
+		}
+		target = base + "/bot" + secret + "/sendMessage"
+		body, err = json.Marshal(map[string]any{
+			"chat_id":                  strings.TrimSpace(ch.TelegramChatID),
+			"text":                     text,
+			"disable_web_page_preview": true,
+		})
+		if err != nil {
+			return 0, &opsNotificationSendError{Message: err.Error()}
+		}
+		check = checkOpsTelegramResponse
+	case OpsNotificationChannelDingTalk:
+		target = strings.TrimSpace(ch.WebhookURL)
+		if secret != "" {
+			target, err = appendOpsDingTalkSign(target, secret, now)
+			if err != nil {
+				return 0, &opsNotificationSendError{Message: err.Error()}
+			}
+		}
+		body, err = json.Marshal(map[string]any{
+			"msgtype": "markdown",
+			"markdown": map[string]any{
+				"title": msg.Title,
+				"text":  text,
+			},
+		})
+		if err != nil {
+			return 0, &opsNotificationSendError{Message: err.Error()}
+		}
+		check = checkOpsErrcodeResponse
+	case OpsNotificationChannelFeishu:
+		target = strings.TrimSpace(ch.WebhookURL)
+		payload := map[string]any{
+			"msg_type": "text",
+			"content":  map[string]any{"text": text},
+		}
+		if secret != "" {
+			ts := strconv.FormatInt(now.Unix(), 10)
+			payload["timestamp"] = ts
+			payload["sign"] = signOpsFeishu(secret, ts)
+		}
+		body, err = json.Marshal(payload)
+		if err != nil {
+			return 0, &opsNotificationSendError{Message: err.Error()}
+		}
+		check = checkOpsFeishuResponse
+	case OpsNotificationChannelWeCom:
+		target = strings.TrimSpace(ch.WebhookURL)
+		body, err = json.Marshal(map[string]any{
+			"msgtype":  "markdown",
+			"markdown": map[string]any{"content": text},
+		})
+		if err != nil {
+			return 0, &opsNotificationSendError{Message: err.Error()}
+		}
+		check = checkOpsErrcodeResponse
+	default:
+		return 0, &opsNotificationSendError{Message: "unsupported channel type: " + ch.Type}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(body))
+	if err != nil {
+		return 0, &opsNotificationSendError{Message: "build request failed"}
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "sub2api-ops-notifier")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		// url.Error 会携带完整 URL（Telegram 的 Bot Token 在路径中），只保留底层错误。
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return 0, &opsNotificationSendError{Retryable: true, Message: "request failed: " + err.Error()}
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, opsNotificationMaxResponseBytes))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		retryable := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+		return resp.StatusCode, &opsNotificationSendError{
+			HTTPStatus: resp.StatusCode,
+			Retryable:  retryable,
+			Message:    truncateString(strings.TrimSpace(string(respBody)), 512),
+		}
+	}
+	if check != nil {
+		if err := check(resp.StatusCode, respBody); err != nil {
+			return resp.StatusCode, err
+		}
+	}
+	return resp.StatusCode, nil
+}
+
+func buildOpsWebhookPayload(msg *OpsNotificationMessage, text string, now time.Time) *opsWebhookPayload {
+	payload := &opsWebhookPayload{
+		Kind:        msg.Kind,
+		Title:       msg.Title,
+		Text:        text,
+		EventID:     msg.EventID,
+		RuleID:      msg.RuleID,
+		RuleName:    msg.RuleName,
+		Severity:    msg.Severity,
+		Status:      msg.Status,
+		MetricType:  msg.MetricType,
+		Operator:    msg.Operator,
+		MetricValue: msg.MetricValue,
+		Threshold:   msg.Threshold,
+		Description: msg.Description,
+		Dimensions:  msg.Dimensions,
+		ResolvedAt:  msg.ResolvedAt,
+		SentAt:      now.UTC(),
+	}
+	if !msg.FiredAt.IsZero() {
+		firedAt := msg.FiredAt
+		payload.FiredAt = &firedAt
+	}
+	return payload
+}
+
+func signOpsWebhookPayload(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// appendOpsDingTalkSign 钉钉加签：sign = Base64(HmacSHA256(secret, timestamp_ms + "\n" + secret))
+func appendOpsDingTalkSign(rawURL, secret string, now time.Time) (string, error) {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid webhook url")
+	}
+	ts := strconv.FormatInt(now.UnixMilli(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(ts + "\n" + secret))
+	sign := base64.StdEncoding.EncodeToString(mac.Sum(nil))
+
+	q := parsed.Query()
+	q.Set("timestamp", ts)
+	q.Set("sign", sign)
+	parsed.RawQuery = q.Encode()
+	return parsed.String(), nil
+}
+
+// signOpsFeishu 飞书加签：以 timestamp + "\n" + secret 为密钥，对空串做 HmacSHA256 后 Base64。
+func signOpsFeishu(secret, timestamp string) string {
+	mac := hmac.New(sha256.New, []byte(timestamp+"\n"+secret))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// checkOpsErrcodeResponse 钉钉/企业微信：HTTP 200 但 errcode != 0 视为失败。
+func checkOpsErrcodeResponse(status int, body []byte) error {
+	var parsed struct {
+		ErrCode *int   `json:"errcode"`
+		ErrMsg  string `json:"errmsg"`
+	}
+	if err := json.Unmarshal(body, &parsed); err != nil || parsed.ErrCode == nil {
+		return nil
+	}
+	if *parsed.ErrCode != 0 {
+		return &opsNotificationSendError{HTTPStatus: status, Message: fmt.Sprintf("errcode=%d %s", *parsed.ErrCode, parsed.ErrMsg)}
+	}
+	return nil
+}
+
+func checkOpsFeishuResponse(status int, body []byte) error {
+	var parsed struct {
+		Code *int   `json:"code"`
+		Msg  string `json:"msg"`
+	}
+	if err := json.Unmarshal(body, &parsed); err != nil || parsed.Code == nil {
+		return nil
+	}
+	if *parsed.Code != 0 {
+		return &opsNotificationSendError{HTTPStatus: status, Message: fmt.Sprintf("code=%d %s", *parsed.Code, parsed.Msg)}
+	}
+	return nil
+}
+
+func checkOpsTelegramResponse(status int, body []byte) error {
+	var parsed struct {
+		OK          bool   `json:"ok"`
+		Description string `json:"description"`
+	}
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		return nil
+	}
+	if !parsed.OK {
+		return &opsNotificationSendError{HTTPStatus: status, Message: parsed.Description}
+	}
+	return nil
+}
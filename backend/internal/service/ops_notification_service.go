@@ -0,0 +1,418 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/config"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/httpclient"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/logger"
+)
+
+const (
+	opsNotificationQueueSize    = 256
+	opsNotificationWorkerCount  = 2
+	opsNotificationMaxAttempts  = 3
+	opsNotificationRetryBackoff = 2 * time.Second
+	opsNotificationLogTimeout   = 5 * time.Second
+)
+
+// OpsNotificationService 将运维告警与定时报表异步投递到 IM / Webhook 渠道。
+//
+// 投递失败按指数退避重试（最多 opsNotificationMaxAttempts 次），每次投递的最终结果写入
+// ops_notification_deliveries。渠道级的路由与级别过滤在入队前完成。
+type OpsNotificationService struct {
+	opsRepo OpsRepository
+	cfg     *config.Config
+
+	// httpClient 为空时按配置从共享连接池获取（测试可直接注入）。
+	httpClient   *http.Client
+	retryBackoff time.Duration
+
+	queue     chan *opsNotificationJob
+	stopCh    chan struct{}
+	startOnce sync.Once
+	stopOnce  sync.Once
+	wg        sync.WaitGroup
+}
+
+type opsNotificationJob struct {
+	channel *OpsNotificationChannel
+	message *OpsNotificationMessage
+}
+
+func NewOpsNotificationService(opsRepo OpsRepository, cfg *config.Config) *OpsNotificationService {
+	return &OpsNotificationService{
+		opsRepo:      opsRepo,
+		cfg:          cfg,
+		retryBackoff: opsNotificationRetryBackoff,
+		queue:        make(chan *opsNotificationJob, opsNotificationQueueSize),
+		stopCh:       make(chan struct{}),
+	}
+}
+
+func (s *OpsNotificationService) Start() {
+	if s == nil {
+		return
+	}
+	if s.cfg != nil && !s.cfg.Ops.Enabled {
+		return
+	}
+	s.startOnce.Do(func() {
+		for i := 0; i < opsNotificationWorkerCount; i++ {
+			s.wg.Add(1)
+			go s.worker()
+		}
+	})
+}
+
+func (s *OpsNotificationService) Stop() {
+	if s == nil {
+		return
+	}
+	s.stopOnce.Do(func() {
+		if s.stopCh != nil {
+			close(s.stopCh)
+		}
+	})
+	s.wg.Wait()
+}
+
+func (s *OpsNotificationService) worker() {
+	defer s.wg.Done()
+	for {
+		select {
+		case job := <-s.queue:
+			s.process(job)
+		case <-s.stopCh:
+			return
+		}
+	}
+}
+
+func (s *OpsNotificationService) process(job *opsNotificationJob) {
+	if job == nil {
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-s.stopCh:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	client, err := s.client()
+	if err != nil {
+		logger.LegacyPrintf("service.ops_notification", "[OpsNotification] create http client failed: %v", err)
+		return
+	}
+	deliverOpsNotification(ctx, s.opsRepo, client, job.channel, job.message, opsNotificationMaxAttempts, s.retryBackoff)
+}
+
+func (s *OpsNotificationService) client() (*http.Client, error) {
+	if s.httpClient != nil {
+		return s.httpClient, nil
+	}
+	return opsNotificationHTTPClient(s.cfg)
+}
+
+// NotifyAlert 将告警事件（firing/resolved）分发到匹配的渠道，返回入队的渠道数。
+// 运行时静默配置（与邮件告警共用）命中时不发送。
+func (s *OpsNotificationService) NotifyAlert(ctx context.Context, kind string, runtimeCfg *OpsAlertRuntimeSettings, rule *OpsAlertRule, event *OpsAlertEvent) int {
+	if s == nil || s.opsRepo == nil || rule == nil || event == nil {
+		return 0
+	}
+	if runtimeCfg != nil && runtimeCfg.Silencing.Enabled {
+		if isOpsAlertSilenced(time.Now().UTC(), rule, event, runtimeCfg.Silencing) {
+			return 0
+		}
+	}
+
+	channels, err := s.opsRepo.ListNotificationChannels(ctx)
+	if err != nil {
+		logger.LegacyPrintf("service.ops_notification", "[OpsNotification] list channels failed: %v", err)
+		return 0
+	}
+
+	msg := buildOpsAlertNotificationMessage(kind, rule, event)
+	queued := 0
+	for _, ch := range channels {
+		if !opsNotificationChannelAccepts(ch, kind, rule) {
+			continue
+		}
+		if s.enqueue(ch, msg) {
+			queued++
+		}
+	}
+	return queued
+}
+
+// NotifyReport 将定时报表（HTML）转为纯文本后分发到开启了 send_reports 的渠道。
+func (s *OpsNotificationService) NotifyReport(ctx context.Context, title string, htmlBody string) int {
+	if s == nil || s.opsRepo == nil {
+		return 0
+	}
+
+	channels, err := s.opsRepo.ListNotificationChannels(ctx)
+	if err != nil {
+		logger.LegacyPrintf("service.ops_notification", "[OpsNotification] list channels failed: %v", err)
+		return 0
+	}
+
+	msg := &OpsNotificationMessage{
+		Kind:    OpsNotificationKindReport,
+		Title:   strings.TrimSpace(title),
+		Body:    opsHTMLToText(htmlBody),
+		FiredAt: time.Now().UTC(),
+	}
+	queued := 0
+	for _, ch := range channels {
+		if !opsNotificationChannelAccepts(ch, OpsNotificationKindReport, nil) {
+			continue
+		}
+		if s.enqueue(ch, msg) {
+			queued++
+		}
+	}
+	return queued
+}
+
+func (s *OpsNotificationService) enqueue(ch *OpsNotificationChannel, msg *OpsNotificationMessage) bool {
+	select {
+	case s.queue <- &opsNotificationJob{channel: ch, message: msg}:
+		return true
+	default:
+		logger.LegacyPrintf("service.ops_notification", "[OpsNotification] queue full, dropping %s notification for channel=%d", msg.Kind, ch.ID)
+		recordOpsNotificationDelivery(s.opsRepo, ch, msg, 0, 0, errors.New("notification queue full"))
+		return false
+	}
+}
+
+// opsNotificationChannelAccepts 判断渠道是否接收该消息：启用状态、消息类型开关、规则路由与最低级别。
+func opsNotificationChannelAccepts(ch *OpsNotificationChannel, kind string, rule *OpsAlertRule) bool {
+	if ch == nil || !ch.Enabled {
+		return false
+	}
+	switch kind {
+	case OpsNotificationKindReport:
+		return ch.SendReports
+	case OpsNotificationKindResolved:
+		if !ch.NotifyResolved {
+			return false
+		}
+	case OpsNotificationKindFiring:
+	default:
+		return false
+	}
+	if rule == nil {
+		return false
+	}
+	if len(ch.RuleIDs) > 0 {
+		matched := false
+		for _, id := range ch.RuleIDs {
+			if id == rule.ID {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return shouldSendOpsAlertEmailByMinSeverity(ch.MinSeverity, rule.Severity)
+}
+
+func buildOpsAlertNotificationMessage(kind string, rule *OpsAlertRule, event *OpsAlertEvent) *OpsNotificationMessage {
+	msg := &OpsNotificationMessage{
+		Kind:        kind,
+		Title:       strings.TrimSpace(event.Title),
+		EventID:     event.ID,
+		RuleID:      rule.ID,
+		RuleName:    strings.TrimSpace(rule.Name),
+		Severity:    strings.TrimSpace(rule.Severity),
+		Status:      strings.TrimSpace(event.Status),
+		MetricType:  strings.TrimSpace(rule.MetricType),
+		Operator:    strings.TrimSpace(rule.Operator),
+		MetricValue: "-",
+		Threshold:   fmt.Sprintf("%.2f", rule.Threshold),
+		Description: strings.TrimSpace(event.Description),
+		Dimensions:  event.Dimensions,
+		FiredAt:     event.FiredAt,
+		ResolvedAt:  event.ResolvedAt,
+	}
+	if msg.Title == "" {
+		msg.Title = fmt.Sprintf("%s: %s", msg.Severity, msg.RuleName)
+	}
+	if event.MetricValue != nil {
+		msg.MetricValue = fmt.Sprintf("%.2f", *event.MetricValue)
+	}
+	if event.ThresholdValue != nil {
+		msg.Threshold = fmt.Sprintf("%.2f", *event.ThresholdValue)
+	}
+	if kind == OpsNotificationKindResolved {
+		msg.Status = OpsAlertStatusResolved
+	}
+	return msg
+}
+
+// deliverOpsNotification 渲染并投递一条消息，失败按指数退避重试，最终结果写入投递记录。
+func deliverOpsNotification(ctx context.Context, repo OpsRepository, client *http.Client, ch *OpsNotificationChannel, msg *OpsNotificationMessage, maxAttempts int, backoff time.Duration) *OpsNotificationDelivery {
+	if maxAttempts <= 0 {
+		maxAttempts = 1
+	}
+	text := renderOpsNotificationText(ch, msg)
+
+	var (
+		status   int
+		err      error
+		attempts int
+	)
+	for attempts = 1; attempts <= maxAttempts; attempts++ {
+		status, err = sendOpsNotification(ctx, client, ch, msg, text, time.Now())
+		if err == nil {
+			break
+		}
+		var sendErr *opsNotificationSendError
+		if errors.As(err, &sendErr) && !sendErr.Retryable {
+			break
+		}
+		if attempts == maxAttempts {
+			break
+		}
+		wait := backoff << (attempts - 1)
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			err = ctx.Err()
+		case <-timer.C:
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	if err != nil {
+		logger.LegacyPrintf("service.ops_notification", "[OpsNotification] deliver %s to channel=%d (%s) failed after %d attempt(s): %v", msg.Kind, ch.ID, ch.Type, attempts, err)
+	}
+	return recordOpsNotificationDelivery(repo, ch, msg, attempts, status, err)
+}
+
+func recordOpsNotificationDelivery(repo OpsRepository, ch *OpsNotificationChannel, msg *OpsNotificationMessage, attempts int, status int, err error) *OpsNotificationDelivery {
+	delivery := &OpsNotificationDelivery{
+		ChannelID:   ch.ID,
+		ChannelType: ch.Type,
+		Kind:        msg.Kind,
+		Status:      OpsNotificationDeliverySuccess,
+		Attempts:    attempts,
+		CreatedAt:   time.Now().UTC(),
+	}
+	if msg.EventID > 0 {
+		eventID := msg.EventID
+		delivery.EventID = &eventID
+	}
+	if status > 0 {
+		httpStatus := status
+		delivery.HTTPStatus = &httpStatus
+	}
+	if err != nil {
+		delivery.Status = OpsNotificationDeliveryFailed
+		delivery.ErrorMessage = truncateString(err.Error(), 1024)
+	}
+	if repo != nil {
+		logCtx, cancel := context.WithTimeout(context.Background(), opsNotificationLogTimeout)
+		defer cancel()
+		if insertErr := repo.InsertNotificationDelivery(logCtx, delivery); insertErr != nil {
+			logger.LegacyPrintf("service.ops_notification", "[OpsNotification] insert delivery log failed (channel=%d): %v", ch.ID, insertErr)
+		}
+	}
+	return delivery
+}
+
+func opsNotificationHTTPClient(cfg *config.Config) (*http.Client, error) {
+	opts := httpclient.Options{Timeout: opsNotificationSendTimeout}
+	if cfg != nil {
+		opts.ValidateResolvedIP = cfg.Security.URLAllowlist.Enabled
+		opts.AllowPrivateHosts = cfg.Security.URLAllowlist.AllowPrivateHosts
+	}
+	return httpclient.GetClient(opts)
+}
+
+// renderOpsNotificationText 使用渠道自定义模板渲染消息；模板为空或渲染失败时回退到默认格式。
+func renderOpsNotificationText(ch *OpsNotificationChannel, msg *OpsNotificationMessage) string {
+	if ch != nil && strings.TrimSpace(ch.Template) != "" {
+		tmpl, err := parseOpsNotificationTemplate(ch.Template)
+		if err == nil {
+			var buf bytes.Buffer
+			if err = tmpl.Execute(&buf, msg); err == nil {
+				return buf.String()
+			}
+		}
+		logger.LegacyPrintf("service.ops_notification", "[OpsNotification] render template failed (channel=%d), fallback to default: %v", ch.ID, err)
+	}
+	return defaultOpsNotificationText(msg)
+}
+
+func parseOpsNotificationTemplate(raw string) (*template.Template, error) {
+	return template.New("ops_notification").Option("missingkey=zero").Parse(raw)
+}
+
+func defaultOpsNotificationText(msg *OpsNotificationMessage) string {
+	var b strings.Builder
+	switch msg.Kind {
+	case OpsNotificationKindReport, OpsNotificationKindTest:
+		b.WriteString(msg.Title)
+		if msg.Body != "" {
+			b.WriteString("\n\n")
+			b.WriteString(msg.Body)
+		}
+		return b.String()
+	}
+
+	fmt.Fprintf(&b, "[%s][%s] %s\n", strings.ToUpper(msg.Kind), msg.Severity, msg.RuleName)
+	fmt.Fprintf(&b, "Metric: %s %s %s (current %s)\n", msg.MetricType, msg.Operator, msg.Threshold, msg.MetricValue)
+	if msg.Description != "" {
+		fmt.Fprintf(&b, "Description: %s\n", msg.Description)
+	}
+	if !msg.FiredAt.IsZero() {
+		fmt.Fprintf(&b, "Fired at: %s\n", msg.FiredAt.UTC().Format(time.RFC3339))
+	}
+	if msg.ResolvedAt != nil {
+		fmt.Fprintf(&b, "Resolved at: %s\n", msg.ResolvedAt.UTC().Format(time.RFC3339))
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+var (
+	opsHTMLBlockTagRe = regexp.MustCompile(`(?i)<\s*(br|/p|/h[1-6]|/li|/tr|/div)\s*/?>`)
+	opsHTMLListTagRe  = regexp.MustCompile(`(?i)<\s*li[^>]*>`)
+	opsHTMLTagRe      = regexp.MustCompile(`<[^>]*>`)
+	opsBlankLinesRe   = regexp.MustCompile(`\n{3,}`)
+)
+
+// opsHTMLToText 将报表邮件 HTML 粗略转换为适合 IM 的纯文本。
+func opsHTMLToText(in string) string {
+	out := opsHTMLBlockTagRe.ReplaceAllString(in, "\n")
+	out = opsHTMLListTagRe.ReplaceAllString(out, "- ")
+	out = opsHTMLTagRe.ReplaceAllString(out, "")
+	out = html.UnescapeString(out)
+
+	lines := strings.Split(out, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(line)
+	}
+	out = strings.Join(lines, "\n")
+	out = opsBlankLinesRe.ReplaceAllString(out, "\n\n")
+	return strings.TrimSpace(out)
+}
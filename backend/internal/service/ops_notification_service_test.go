@@ -0,0 +1,332 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/config"
+	"github.com/stretchr/testify/require"
+)
+
+type notificationRepoStub struct {
+	opsRepoMock
+
+	mu         sync.Mutex
+	channels   []*OpsNotificationChannel
+	deliveries []*OpsNotificationDelivery
+	delivered  chan struct{}
+}
+
+func newNotificationRepoStub(channels ...*OpsNotificationChannel) *notificationRepoStub {
+	return &notificationRepoStub{channels: channels, delivered: make(chan struct{}, 16)}
+}
+
+func (r *notificationRepoStub) ListNotificationChannels(ctx context.Context) ([]*OpsNotificationChannel, error) {
+	return r.channels, nil
+}
+
+func (r *notificationRepoStub) GetNotificationChannelByID(ctx context.Context, id int64) (*OpsNotificationChannel, error) {
+	for _, ch := range r.channels {
+		if ch.ID == id {
+			cp := *ch
+			return &cp, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *notificationRepoStub) InsertNotificationDelivery(ctx context.Context, input *OpsNotificationDelivery) error {
+	r.mu.Lock()
+	r.deliveries = append(r.deliveries, input)
+	r.mu.Unlock()
+	r.delivered <- struct{}{}
+	return nil
+}
+
+func (r *notificationRepoStub) waitDeliveries(t *testing.T, n int) []*OpsNotificationDelivery {
+	t.Helper()
+	for i := 0; i < n; i++ {
+		select {
+		case <-r.delivered:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for delivery %d/%d", i+1, n)
+		}
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]*OpsNotificationDelivery(nil), r.deliveries...)
+}
+
+func testAlertMessage() *OpsNotificationMessage {
+	return &OpsNotificationMessage{
+		Kind:        OpsNotificationKindFiring,
+		Title:       "P1: high error rate",
+		EventID:     42,
+		RuleID:      7,
+		RuleName:    "high error rate",
+		Severity:    "P1",
+		Status:      OpsAlertStatusFiring,
+		MetricType:  "error_rate",
+		Operator:    ">",
+		MetricValue: "12.00",
+		Threshold:   "5.00",
+		FiredAt:     time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+}
+
+func TestSendOpsNotification_WebhookSignature(t *testing.T) {
+	var gotBody []byte
+	var gotHeaders http.Header
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotHeaders = r.Header.Clone()
+		gotBody, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	ch := &OpsNotificationChannel{ID: 1, Type: OpsNotificationChannelWebhook, WebhookURL: srv.URL, Secret: "s3cret"}
+	now := time.Unix(1700000000, 0)
+	status, err := sendOpsNotification(context.Background(), srv.Client(), ch, testAlertMessage(), "hello", now)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusNoContent, status)
+
+	require.Equal(t, "1700000000", gotHeaders.Get(opsWebhookTimestampHeader))
+	require.Equal(t, OpsNotificationKindFiring, gotHeaders.Get(opsWebhookEventHeader))
+	mac := hmac.New(sha256.New, []byte("s3cret"))
+	mac.Write([]byte("1700000000." + string(gotBody)))
+	require.Equal(t, "sha256="+hex.EncodeToString(mac.Sum(nil)), gotHeaders.Get(opsWebhookSignatureHeader))
+
+	var payload opsWebhookPayload
+	require.NoError(t, json.Unmarshal(gotBody, &payload))
+	require.Equal(t, int64(42), payload.EventID)
+	require.Equal(t, "hello", payload.Text)
+	require.Equal(t, "error_rate", payload.MetricType)
+}
+
+func TestSendOpsNotification_DingTalkAndFeishuSign(t *testing.T) {
+	now := time.UnixMilli(1700000000123)
+
+	var dingQuery map[string]string
+	ding := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		dingQuery = map[string]string{
+			"access_token": r.URL.Query().Get("access_token"),
+			"timestamp":    r.URL.Query().Get("timestamp"),
+			"sign":         r.URL.Query().Get("sign"),
+		}
+		_, _ = io.WriteString(w, `{"errcode":0,"errmsg":"ok"}`)
+	}))
+	defer ding.Close()
+
+	ch := &OpsNotificationChannel{Type: OpsNotificationChannelDingTalk, WebhookURL: ding.URL + "/robot/send?access_token=abc", Secret: "SECxyz"}
+	_, err := sendOpsNotification(context.Background(), ding.Client(), ch, testAlertMessage(), "hello", now)
+	require.NoError(t, err)
+	mac := hmac.New(sha256.New, []byte("SECxyz"))
+	mac.Write([]byte("1700000000123\nSECxyz"))
+	require.Equal(t, "abc", dingQuery["access_token"])
+	require.Equal(t, "1700000000123", dingQuery["timestamp"])
+	require.Equal(t, base64.StdEncoding.EncodeToString(mac.Sum(nil)), dingQuery["sign"])
+
+	var feishuBody map[string]any
+	feishu := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&feishuBody)
+		_, _ = io.WriteString(w, `{"code":19021,"msg":"sign match fail"}`)
+	}))
+	defer feishu.Close()
+
+	ch = &OpsNotificationChannel{Type: OpsNotificationChannelFeishu, WebhookURL: feishu.URL, Secret: "fs"}
+	_, err = sendOpsNotification(context.Background(), feishu.Client(), ch, testAlertMessage(), "hello", now)
+	require.Error(t, err)
+	var sendErr *opsNotificationSendError
+	require.ErrorAs(t, err, &sendErr)
+	require.False(t, sendErr.Retryable)
+
+	mac = hmac.New(sha256.New, []byte("1700000000\nfs"))
+	require.Equal(t, "1700000000", feishuBody["timestamp"])
+	require.Equal(t, base64.StdEncoding.EncodeToString(mac.Sum(nil)), feishuBody["sign"])
+	require.Equal(t, "text", feishuBody["msg_type"])
+}
+
+func TestSendOpsNotification_TelegramDoesNotLeakToken(t *testing.T) {
+	var gotPath string
+	var gotBody map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		_ = json.NewDecoder(r.Body).Decode(&gotBody)
+		_, _ = io.WriteString(w, `{"ok":true}`)
+	}))
+
+	ch := &OpsNotificationChannel{Type: OpsNotificationChannelTelegram, WebhookURL: srv.URL, Secret: "123:TOKEN", TelegramChatID: "-100"}
+	_, err := sendOpsNotification(context.Background(), srv.Client(), ch, testAlertMessage(), "hello", time.Now())
+	require.NoError(t, err)
+	require.Equal(t, "/bot123:TOKEN/sendMessage", gotPath)
+	require.Equal(t, "-100", gotBody["chat_id"])
+	require.Equal(t, "hello", gotBody["text"])
+
+	srv.Close()
+	_, err = sendOpsNotification(context.Background(), &http.Client{Timeout: time.Second}, ch, testAlertMessage(), "hello", time.Now())
+	require.Error(t, err)
+	require.NotContains(t, err.Error(), "TOKEN")
+	var sendErr *opsNotificationSendError
+	require.ErrorAs(t, err, &sendErr)
+	require.True(t, sendErr.Retryable)
+}
+
+func TestOpsNotificationChannelAccepts(t *testing.T) {
+	rule := &OpsAlertRule{ID: 7, Severity: "P1"}
+
+	cases := []struct {
+		name string
+		ch   *OpsNotificationChannel
+		kind string
+		want bool
+	}{
+		{"disabled", &OpsNotificationChannel{}, OpsNotificationKindFiring, false},
+		{"all rules", &OpsNotificationChannel{Enabled: true}, OpsNotificationKindFiring, true},
+		{"routed rule", &OpsNotificationChannel{Enabled: true, RuleIDs: []int64{3, 7}}, OpsNotificationKindFiring, true},
+		{"other rule", &OpsNotificationChannel{Enabled: true, RuleIDs: []int64{3}}, OpsNotificationKindFiring, false},
+		{"severity below min", &OpsNotificationChannel{Enabled: true, MinSeverity: "critical"}, OpsNotificationKindFiring, false},
+		{"severity meets min", &OpsNotificationChannel{Enabled: true, MinSeverity: "warning"}, OpsNotificationKindFiring, true},
+		{"resolved off", &OpsNotificationChannel{Enabled: true}, OpsNotificationKindResolved, false},
+		{"resolved on", &OpsNotificationChannel{Enabled: true, NotifyResolved: true}, OpsNotificationKindResolved, true},
+		{"report off", &OpsNotificationChannel{Enabled: true}, OpsNotificationKindReport, false},
+		{"report on", &OpsNotificationChannel{Enabled: true, SendReports: true}, OpsNotificationKindReport, true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.want, opsNotificationChannelAccepts(tc.ch, tc.kind, rule))
+		})
+	}
+}
+
+func TestDeliverOpsNotification_RetriesWithBackoff(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	repo := newNotificationRepoStub()
+	ch := &OpsNotificationChannel{ID: 5, Type: OpsNotificationChannelSlack, WebhookURL: srv.URL}
+	delivery := deliverOpsNotification(context.Background(), repo, srv.Client(), ch, testAlertMessage(), 3, time.Millisecond)
+	require.Equal(t, OpsNotificationDeliverySuccess, delivery.Status)
+	require.Equal(t, 3, delivery.Attempts)
+	require.Equal(t, int32(3), calls.Load())
+
+	logged := repo.waitDeliveries(t, 1)
+	require.Equal(t, int64(5), logged[0].ChannelID)
+	require.Equal(t, int64(42), *logged[0].EventID)
+}
+
+func TestDeliverOpsNotification_DoesNotRetryClientErrors(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = io.WriteString(w, "no_service")
+	}))
+	defer srv.Close()
+
+	repo := newNotificationRepoStub()
+	ch := &OpsNotificationChannel{ID: 5, Type: OpsNotificationChannelSlack, WebhookURL: srv.URL}
+	delivery := deliverOpsNotification(context.Background(), repo, srv.Client(), ch, testAlertMessage(), 3, time.Millisecond)
+	require.Equal(t, OpsNotificationDeliveryFailed, delivery.Status)
+	require.Equal(t, 1, delivery.Attempts)
+	require.Equal(t, http.StatusNotFound, *delivery.HTTPStatus)
+	require.Contains(t, delivery.ErrorMessage, "no_service")
+	require.Equal(t, int32(1), calls.Load())
+}
+
+func TestOpsNotificationService_NotifyAlert(t *testing.T) {
+	var mu sync.Mutex
+	var texts []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Text string `json:"text"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		mu.Lock()
+		texts = append(texts, body.Text)
+		mu.Unlock()
+	}))
+	defer srv.Close()
+
+	repo := newNotificationRepoStub(
+		&OpsNotificationChannel{ID: 1, Type: OpsNotificationChannelSlack, Enabled: true, WebhookURL: srv.URL, Template: "{{.Kind}} {{.RuleName}} {{.MetricValue}}"},
+		&OpsNotificationChannel{ID: 2, Type: OpsNotificationChannelSlack, Enabled: true, WebhookURL: srv.URL, RuleIDs: []int64{99}},
+	)
+	svc := NewOpsNotificationService(repo, &config.Config{Ops: config.OpsConfig{Enabled: true}})
+	svc.httpClient = srv.Client()
+	svc.Start()
+	defer svc.Stop()
+
+	rule := &OpsAlertRule{ID: 7, Name: "high error rate", Severity: "P1", MetricType: "error_rate", Operator: ">", Threshold: 5}
+	value := 12.5
+	event := &OpsAlertEvent{ID: 42, RuleID: 7, Severity: "P1", Status: OpsAlertStatusFiring, MetricValue: &value, FiredAt: time.Now().UTC()}
+
+	silenced := &OpsAlertRuntimeSettings{Silencing: OpsAlertSilencingSettings{
+		Enabled:            true,
+		GlobalUntilRFC3339: time.Now().Add(time.Hour).UTC().Format(time.RFC3339),
+	}}
+	require.Equal(t, 0, svc.NotifyAlert(context.Background(), OpsNotificationKindFiring, silenced, rule, event))
+
+	require.Equal(t, 1, svc.NotifyAlert(context.Background(), OpsNotificationKindFiring, nil, rule, event))
+	logged := repo.waitDeliveries(t, 1)
+	require.Equal(t, int64(1), logged[0].ChannelID)
+	require.Equal(t, OpsNotificationDeliverySuccess, logged[0].Status)
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Equal(t, []string{"firing high error rate 12.50"}, texts)
+}
+
+func TestOpsService_NormalizeNotificationChannel(t *testing.T) {
+	svc := &OpsService{cfg: &config.Config{}}
+
+	err := svc.normalizeNotificationChannel(&OpsNotificationChannel{Name: "x", Type: "pager"}, true)
+	require.Error(t, err)
+
+	err = svc.normalizeNotificationChannel(&OpsNotificationChannel{Name: "x", Type: OpsNotificationChannelSlack, WebhookURL: "http://hooks.example.com/x"}, true)
+	require.Error(t, err, "plain http must be rejected unless allow_insecure_http")
+
+	err = svc.normalizeNotificationChannel(&OpsNotificationChannel{Name: "x", Type: OpsNotificationChannelTelegram, Secret: "t"}, true)
+	require.Error(t, err, "telegram requires chat id")
+
+	err = svc.normalizeNotificationChannel(&OpsNotificationChannel{Name: "x", Type: OpsNotificationChannelTelegram, TelegramChatID: "1"}, false)
+	require.NoError(t, err, "existing bot token is kept on update")
+
+	err = svc.normalizeNotificationChannel(&OpsNotificationChannel{Name: "x", Type: OpsNotificationChannelWebhook, WebhookURL: "https://example.com/hook", Template: "{{.Title"}, true)
+	require.Error(t, err)
+
+	ch := &OpsNotificationChannel{Name: " ops ", Type: " WeCom ", WebhookURL: "https://qyapi.weixin.qq.com/cgi-bin/webhook/send?key=k", MinSeverity: "Warning", RuleIDs: []int64{3, 3, 4}}
+	require.NoError(t, svc.normalizeNotificationChannel(ch, true))
+	require.Equal(t, "ops", ch.Name)
+	require.Equal(t, OpsNotificationChannelWeCom, ch.Type)
+	require.Equal(t, "warning", ch.MinSeverity)
+	require.Equal(t, []int64{3, 4}, ch.RuleIDs)
+
+	redacted := redactOpsNotificationChannel(&OpsNotificationChannel{Secret: "x"})
+	require.Empty(t, redacted.Secret)
+	require.True(t, redacted.SecretConfigured)
+}
+
+func TestOpsHTMLToText(t *testing.T) {
+	in := "<h2>日报 &amp; summary</h2>\n<ul>\n  <li><b>Total</b>: 10</li>\n  <li><b>SLA</b>: 99.00%</li>\n</ul>\n"
+	out := opsHTMLToText(in)
+	require.Equal(t, "日报 & summary\n\n- Total: 10\n\n- SLA: 99.00%", strings.TrimSpace(out))
+}
@@ -0,0 +1,267 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	infraerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
+	"github.com/Wei-Shaw/sub2api/internal/util/urlvalidator"
+)
+
+func (s *OpsService) ListNotificationChannels(ctx context.Context) ([]*OpsNotificationChannel, error) {
+	if err := s.RequireMonitoringEnabled(ctx); err != nil {
+		return nil, err
+	}
+	if s.opsRepo == nil {
+		return []*OpsNotificationChannel{}, nil
+	}
+	channels, err := s.opsRepo.ListNotificationChannels(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, ch := range channels {
+		redactOpsNotificationChannel(ch)
+	}
+	return channels, nil
+}
+
+func (s *OpsService) CreateNotificationChannel(ctx context.Context, ch *OpsNotificationChannel) (*OpsNotificationChannel, error) {
+	if err := s.RequireMonitoringEnabled(ctx); err != nil {
+		return nil, err
+	}
+	if s.opsRepo == nil {
+		return nil, infraerrors.ServiceUnavailable("OPS_REPO_UNAVAILABLE", "Ops repository not available")
+	}
+	if ch == nil {
+		return nil, infraerrors.BadRequest("INVALID_NOTIFICATION_CHANNEL", "invalid notification channel")
+	}
+	if err := s.normalizeNotificationChannel(ch, true); err != nil {
+		return nil, err
+	}
+
+	created, err := s.opsRepo.CreateNotificationChannel(ctx, ch)
+	if err != nil {
+		return nil, err
+	}
+	return redactOpsNotificationChannel(created), nil
+}
+
+func (s *OpsService) UpdateNotificationChannel(ctx context.Context, ch *OpsNotificationChannel) (*OpsNotificationChannel, error) {
+	if err := s.RequireMonitoringEnabled(ctx); err != nil {
+		return nil, err
+	}
+	if s.opsRepo == nil {
+		return nil, infraerrors.ServiceUnavailable("OPS_REPO_UNAVAILABLE", "Ops repository not available")
+	}
+	if ch == nil || ch.ID <= 0 {
+		return nil, infraerrors.BadRequest("INVALID_NOTIFICATION_CHANNEL", "invalid notification channel")
+	}
+
+	existing, err := s.opsRepo.GetNotificationChannelByID(ctx, ch.ID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, infraerrors.NotFound("OPS_NOTIFICATION_CHANNEL_NOT_FOUND", "notification channel not found")
+		}
+		return nil, err
+	}
+	// 更新时 secret 留空表示保持不变；仅在类型切换时要求重新提供。
+	requireSecret := existing.Type != strings.TrimSpace(ch.Type) || existing.Secret == ""
+	if err := s.normalizeNotificationChannel(ch, requireSecret); err != nil {
+		return nil, err
+	}
+
+	updated, err := s.opsRepo.UpdateNotificationChannel(ctx, ch)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, infraerrors.NotFound("OPS_NOTIFICATION_CHANNEL_NOT_FOUND", "notification channel not found")
+		}
+		return nil, err
+	}
+	return redactOpsNotificationChannel(updated), nil
+}
+
+func (s *OpsService) DeleteNotificationChannel(ctx context.Context, id int64) error {
+	if err := s.RequireMonitoringEnabled(ctx); err != nil {
+		return err
+	}
+	if s.opsRepo == nil {
+		return infraerrors.ServiceUnavailable("OPS_REPO_UNAVAILABLE", "Ops repository not available")
+	}
+	if id <= 0 {
+		return infraerrors.BadRequest("INVALID_NOTIFICATION_CHANNEL_ID", "invalid notification channel id")
+	}
+	if err := s.opsRepo.DeleteNotificationChannel(ctx, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return infraerrors.NotFound("OPS_NOTIFICATION_CHANNEL_NOT_FOUND", "notification channel not found")
+		}
+		return err
+	}
+	return nil
+}
+
+// TestNotificationChannel 同步发送一条测试消息（不重试），并写入投递记录。
+func (s *OpsService) TestNotificationChannel(ctx context.Context, id int64) (*OpsNotificationDelivery, error) {
+	if err := s.RequireMonitoringEnabled(ctx); err != nil {
+		return nil, err
+	}
+	if s.opsRepo == nil {
+		return nil, infraerrors.ServiceUnavailable("OPS_REPO_UNAVAILABLE", "Ops repository not available")
+	}
+	if id <= 0 {
+		return nil, infraerrors.BadRequest("INVALID_NOTIFICATION_CHANNEL_ID", "invalid notification channel id")
+	}
+
+	ch, err := s.opsRepo.GetNotificationChannelByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, infraerrors.NotFound("OPS_NOTIFICATION_CHANNEL_NOT_FOUND", "notification channel not found")
+		}
+		return nil, err
+	}
+
+	client, err := opsNotificationHTTPClient(s.cfg)
+	if err != nil {
+		return nil, infraerrors.ServiceUnavailable("OPS_NOTIFICATION_CLIENT_UNAVAILABLE", "notification http client not available")
+	}
+
+	now := time.Now().UTC()
+	msg := &OpsNotificationMessage{
+		Kind:     OpsNotificationKindTest,
+		Title:    fmt.Sprintf("[Ops Test] %s", strings.TrimSpace(ch.Name)),
+		RuleName: "test",
+		Severity: "P3",
+		Status:   OpsAlertStatusFiring,
+		FiredAt:  now,
+		Body:     fmt.Sprintf("This is a test notification sent at %s.", now.Format(time.RFC3339)),
+	}
+	return deliverOpsNotification(ctx, s.opsRepo, client, ch, msg, 1, 0), nil
+}
+
+func (s *OpsService) ListNotificationDeliveries(ctx context.Context, filter *OpsNotificationDeliveryFilter) ([]*OpsNotificationDelivery, error) {
+	if err := s.RequireMonitoringEnabled(ctx); err != nil {
+		return nil, err
+	}
+	if s.opsRepo == nil {
+		return []*OpsNotificationDelivery{}, nil
+	}
+	return s.opsRepo.ListNotificationDeliveries(ctx, filter)
+}
+
+// normalizeNotificationChannel 校验并规范化渠道配置。requireSecret 为 true 时，
+// 需要密钥的渠道类型（Telegram）必须提供 secret。
+func (s *OpsService) normalizeNotificationChannel(ch *OpsNotificationChannel, requireSecret bool) error {
+	ch.Name = strings.TrimSpace(ch.Name)
+	ch.Type = strings.ToLower(strings.TrimSpace(ch.Type))
+	ch.WebhookURL = strings.TrimSpace(ch.WebhookURL)
+	ch.Secret = strings.TrimSpace(ch.Secret)
+	ch.TelegramChatID = strings.TrimSpace(ch.TelegramChatID)
+	ch.MinSeverity = strings.ToLower(strings.TrimSpace(ch.MinSeverity))
+
+	if ch.Name == "" {
+		return infraerrors.BadRequest("INVALID_NOTIFICATION_CHANNEL", "name is required")
+	}
+	if len(ch.Name) > 128 {
+		return infraerrors.BadRequest("INVALID_NOTIFICATION_CHANNEL", "name is too long")
+	}
+
+	supported := false
+	for _, t := range OpsNotificationChannelTypes {
+		if ch.Type == t {
+			supported = true
+			break
+		}
+	}
+	if !supported {
+		return infraerrors.BadRequest("INVALID_NOTIFICATION_CHANNEL_TYPE", "unsupported channel type")
+	}
+
+	switch ch.MinSeverity {
+	case "", "critical", "warning", "info":
+	default:
+		return infraerrors.BadRequest("INVALID_NOTIFICATION_CHANNEL", "min_severity must be one of critical/warning/info")
+	}
+
+	if ch.Type == OpsNotificationChannelTelegram {
+		if ch.TelegramChatID == "" {
+			return infraerrors.BadRequest("INVALID_NOTIFICATION_CHANNEL", "telegram_chat_id is required")
+		}
+		if requireSecret && ch.Secret == "" {
+			return infraerrors.BadRequest("INVALID_NOTIFICATION_CHANNEL", "telegram bot token is required")
+		}
+		// webhook_url 可选：自建 Bot API 服务地址，为空时使用官方地址。
+		if ch.WebhookURL != "" {
+			normalized, err := s.validateNotificationURL(ch.WebhookURL)
+			if err != nil {
+				return err
+			}
+			ch.WebhookURL = normalized
+		}
+	} else {
+		normalized, err := s.validateNotificationURL(ch.WebhookURL)
+		if err != nil {
+			return err
+		}
+		ch.WebhookURL = normalized
+	}
+
+	ruleIDs := make([]int64, 0, len(ch.RuleIDs))
+	seen := map[int64]struct{}{}
+	for _, id := range ch.RuleIDs {
+		if id <= 0 {
+			return infraerrors.BadRequest("INVALID_NOTIFICATION_CHANNEL", "rule_ids must be positive")
+		}
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		ruleIDs = append(ruleIDs, id)
+	}
+	ch.RuleIDs = ruleIDs
+
+	if strings.TrimSpace(ch.Template) != "" {
+		if _, err := parseOpsNotificationTemplate(ch.Template); err != nil {
+			return infraerrors.BadRequest("INVALID_NOTIFICATION_TEMPLATE", "invalid template: "+err.Error())
+		}
+	}
+	return nil
+}
+
+func (s *OpsService) validateNotificationURL(raw string) (string, error) {
+	if raw == "" {
+		return "", infraerrors.BadRequest("INVALID_NOTIFICATION_CHANNEL", "webhook_url is required")
+	}
+
+	var (
+		normalized string
+		err        error
+	)
+	if s.cfg != nil && s.cfg.Security.URLAllowlist.Enabled {
+		normalized, err = urlvalidator.ValidateHTTPSURL(raw, urlvalidator.ValidationOptions{
+			AllowPrivate: s.cfg.Security.URLAllowlist.AllowPrivateHosts,
+		})
+	} else {
+		allowInsecure := s.cfg != nil && s.cfg.Security.URLAllowlist.AllowInsecureHTTP
+		normalized, err = urlvalidator.ValidateURLFormat(raw, allowInsecure)
+	}
+	if err != nil {
+		return "", infraerrors.BadRequest("INVALID_NOTIFICATION_URL", "invalid webhook_url: "+err.Error())
+	}
+	return normalized, nil
+}
+
+// redactOpsNotificationChannel 清除只写字段 Secret，仅保留是否已配置的标记。
+func redactOpsNotificationChannel(ch *OpsNotificationChannel) *OpsNotificationChannel {
+	if ch == nil {
+		return nil
+	}
+	ch.SecretConfigured = ch.Secret != "" || ch.SecretConfigured
+	ch.Secret = ""
+	if ch.RuleIDs == nil {
+		ch.RuleIDs = []int64{}
+	}
+	return ch
+}
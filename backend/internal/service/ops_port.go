@@ -59,6 +59,15 @@ type OpsRepository interface {
 	CreateAlertSilence(ctx context.Context, input *OpsAlertSilence) (*OpsAlertSilence, error)
 	IsAlertSilenced(ctx context.Context, ruleID int64, platform string, groupID *int64, region *string, now time.Time) (bool, error)
 
+	// Notification channels (IM / webhook) + delivery log
+	ListNotificationChannels(ctx context.Context) ([]*OpsNotificationChannel, error)
+	GetNotificationChannelByID(ctx context.Context, id int64) (*OpsNotificationChannel, error)
+	CreateNotificationChannel(ctx context.Context, input *OpsNotificationChannel) (*OpsNotificationChannel, error)
+	UpdateNotificationChannel(ctx context.Context, input *OpsNotificationChannel) (*OpsNotificationChannel, error)
+	DeleteNotificationChannel(ctx context.Context, id int64) error
+	InsertNotificationDelivery(ctx context.Context, input *OpsNotificationDelivery) error
+	ListNotificationDeliveries(ctx context.Context, filter *OpsNotificationDeliveryFilter) ([]*OpsNotificationDelivery, error)
+
 	// Pre-aggregation (hourly/daily) used for long-window dashboard performance.
 	UpsertHourlyMetrics(ctx context.Context, startTime, endTime time.Time) error
 	UpsertDailyMetrics(ctx context.Context, startTime, endTime time.Time) error
@@ -185,6 +185,34 @@ func (m *opsRepoMock) IsAlertSilenced(ctx context.Context, ruleID int64, platfor
 	return false, nil
 }
 
+func (m *opsRepoMock) ListNotificationChannels(ctx context.Context) ([]*OpsNotificationChannel, error) {
+	return []*OpsNotificationChannel{}, nil
+}
+
+func (m *opsRepoMock) GetNotificationChannelByID(ctx context.Context, id int64) (*OpsNotificationChannel, error) {
+	return &OpsNotificationChannel{ID: id}, nil
+}
+
+func (m *opsRepoMock) CreateNotificationChannel(ctx context.Context, input *OpsNotificationChannel) (*OpsNotificationChannel, error) {
+	return input, nil
+}
+
+func (m *opsRepoMock) UpdateNotificationChannel(ctx context.Context, input *OpsNotificationChannel) (*OpsNotificationChannel, error) {
+	return input, nil
+}
+
+func (m *opsRepoMock) DeleteNotificationChannel(ctx context.Context, id int64) error {
+	return nil
+}
+
+func (m *opsRepoMock) InsertNotificationDelivery(ctx context.Context, input *OpsNotificationDelivery) error {
+	return nil
+}
+
+func (m *opsRepoMock) ListNotificationDeliveries(ctx context.Context, filter *OpsNotificationDeliveryFilter) ([]*OpsNotificationDelivery, error) {
+	return []*OpsNotificationDelivery{}, nil
+}
+
 func (m *opsRepoMock) UpsertHourlyMetrics(ctx context.Context, startTime, endTime time.Time) error {
 	return nil
 }
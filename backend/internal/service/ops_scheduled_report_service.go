@@ -36,11 +36,12 @@ return 0
 `)
 
 type OpsScheduledReportService struct {
-	opsService   *OpsService
-	userService  *UserService
-	emailService *EmailService
-	redisClient  *redis.Client
-	cfg          *config.Config
+	opsService          *OpsService
+	userService         *UserService
+	emailService        *EmailService
+	notificationService *OpsNotificationService
+	redisClient         *redis.Client
+	cfg                 *config.Config
 
 	instanceID string
 	loc        *time.Location
@@ -59,6 +60,7 @@ func NewOpsScheduledReportService(
 	opsService *OpsService,
 	userService *UserService,
 	emailService *EmailService,
+	notificationService *OpsNotificationService,
 	redisClient *redis.Client,
 	cfg *config.Config,
 ) *OpsScheduledReportService {
@@ -71,11 +73,12 @@ func NewOpsScheduledReportService(
 		}
 	}
 	return &OpsScheduledReportService{
-		opsService:   opsService,
-		userService:  userService,
-		emailService: emailService,
-		redisClient:  redisClient,
-		cfg:          cfg,
+		opsService:          opsService,
+		userService:         userService,
+		emailService:        emailService,
+		notificationService: notificationService,
+		redisClient:         redisClient,
+		cfg:                 cfg,
 
 		instanceID:        uuid.NewString(),
 		loc:               loc,
@@ -103,7 +106,7 @@ func (s *OpsScheduledReportService) StartWithContext(ctx context.Context) {
 	if s.cfg != nil && !s.cfg.Ops.Enabled {
 		return
 	}
-	if s.opsService == nil || s.emailService == nil {
+	if s.opsService == nil || !s.hasDeliveryTarget() {
 		return
 	}
 
@@ -143,8 +146,13 @@ func (s *OpsScheduledReportService) run() {
 	}
 }
 
+// hasDeliveryTarget 报表至少需要邮件或 IM 渠道之一可用。
+func (s *OpsScheduledReportService) hasDeliveryTarget() bool {
+	return s.emailService != nil || s.notificationService != nil
+}
+
 func (s *OpsScheduledReportService) runOnce() {
-	if s == nil || s.opsService == nil || s.emailService == nil {
+	if s == nil || s.opsService == nil || !s.hasDeliveryTarget() {
 		return
 	}
 
@@ -306,7 +314,7 @@ func (s *OpsScheduledReportService) listScheduledReports(ctx context.Context, no
 }
 
 func (s *OpsScheduledReportService) runReport(ctx context.Context, report *opsScheduledReport, now time.Time) (int, error) {
-	if s == nil || s.opsService == nil || s.emailService == nil || report == nil {
+	if s == nil || s.opsService == nil || !s.hasDeliveryTarget() || report == nil {
 		return 0, nil
 	}
 	if ctx == nil {
@@ -325,6 +333,14 @@ func (s *OpsScheduledReportService) runReport(ctx context.Context, report *opsSc
 		return 0, nil
 	}
 
+	subject := fmt.Sprintf("[Ops Report] %s", strings.TrimSpace(report.Name))
+
+	// IM / Webhook 渠道（send_reports=true）异步投递，不受邮件收件人配置影响。
+	attempts := s.notificationService.NotifyReport(ctx, subject, content)
+	if s.emailService == nil {
+		return attempts, nil
+	}
+
 	recipients := report.Recipients
 	if len(recipients) == 0 && s.userService != nil {
 		admin, err := s.userService.GetFirstAdmin(ctx)
@@ -333,12 +349,9 @@ func (s *OpsScheduledReportService) runReport(ctx context.Context, report *opsSc
 		}
 	}
 	if len(recipients) == 0 {
-		return 0, nil
+		return attempts, nil
 	}
 
-	subject := fmt.Sprintf("[Ops Report] %s", strings.TrimSpace(report.Name))
-
-	attempts := 0
 	for _, to := range recipients {
 		addr := strings.TrimSpace(to)
 		if addr == "" {
@@ -0,0 +1,179 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	infraerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
+)
+
+// 组织成员角色
+const (
+	OrgRoleOwner   = "owner"   // 所有者：全部权限，可转让所有权
+	OrgRoleAdmin   = "admin"   // 管理员：管理成员、邀请与全部 API Key，查看账单
+	OrgRoleMember  = "member"  // 成员：创建与管理自己的 API Key
+	OrgRoleBilling = "billing" // 财务：充值、查看余额流水与用量，不可创建 API Key
+)
+
+// 组织邀请状态
+const (
+	OrgInvitationStatusPending  = "pending"
+	OrgInvitationStatusAccepted = "accepted"
+	OrgInvitationStatusRevoked  = "revoked"
+)
+
+var (
+	ErrOrganizationNotFound          = infraerrors.NotFound("ORGANIZATION_NOT_FOUND", "organization not found")
+	ErrOrganizationMemberNotFound    = infraerrors.NotFound("ORGANIZATION_MEMBER_NOT_FOUND", "organization member not found")
+	ErrOrganizationMemberExists      = infraerrors.Conflict("ORGANIZATION_MEMBER_EXISTS", "user is already a member of this organization")
+	ErrOrganizationForbidden         = infraerrors.Forbidden("ORGANIZATION_FORBIDDEN", "insufficient organization permissions")
+	ErrOrganizationInvalidRole       = infraerrors.BadRequest("ORGANIZATION_INVALID_ROLE", "invalid organization role")
+	ErrOrganizationOwnerRequired     = infraerrors.BadRequest("ORGANIZATION_OWNER_REQUIRED", "the owner cannot leave or be removed; transfer ownership first")
+	ErrOrganizationInvitationInvalid = infraerrors.BadRequest("ORGANIZATION_INVITATION_INVALID", "invitation is invalid, expired or already used")
+	ErrOrganizationInvitationEmail   = infraerrors.Forbidden("ORGANIZATION_INVITATION_EMAIL_MISMATCH", "invitation was sent to a different email address")
+	ErrOrganizationInvitationExists  = infraerrors.Conflict("ORGANIZATION_INVITATION_EXISTS", "a pending invitation already exists for this email")
+	ErrOrganizationInvitationMissing = infraerrors.NotFound("ORGANIZATION_INVITATION_NOT_FOUND", "invitation not found")
+
+	// ErrOrganizationMemberSpendLimitExceeded 组织成员当月消费已达上限
+	ErrOrganizationMemberSpendLimitExceeded = infraerrors.TooManyRequests("ORGANIZATION_MEMBER_SPEND_LIMIT_EXCEEDED", "organization member monthly spend limit exceeded")
+	// ErrOrganizationMemberInactive 组织 Key 的创建成员已离开组织或被禁用
+	ErrOrganizationMemberInactive = infraerrors.Forbidden("ORGANIZATION_MEMBER_INACTIVE", "the member who created this key is no longer active in the organization")
+)
+
+// Organization 组织：共享余额、订阅与并发由 AccountUserID 对应的组织账户持有
+type Organization struct {
+	ID            int64
+	Name          string
+	AccountUserID int64
+	CreatedBy     int64
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+// OrganizationMember 组织成员
+type OrganizationMember struct {
+	OrganizationID    int64
+	UserID            int64
+	Role              string
+	MonthlySpendLimit money.Amount // 每自然月（应用时区）消费上限，0 = 不限制
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+
+	// 成员的用户资料，由成员列表联表 users 得到
+	Email    string
+	Username string
+}
+
+// OrganizationMembership 用户所属组织及其角色
+type OrganizationMembership struct {
+	Organization Organization
+	Role         string
+}
+
+// OrganizationInvitation 组织成员邀请
+type OrganizationInvitation struct {
+	ID             int64
+	OrganizationID int64
+	Email          string
+	Role           string
+	TokenHash      string
+	InvitedBy      int64
+	Status         string
+	ExpiresAt      time.Time
+	AcceptedBy     *int64
+	AcceptedAt     *time.Time
+	CreatedAt      time.Time
+}
+
+// OrganizationMemberSpendStatus 成员消费上限检查所需的状态
+type OrganizationMemberSpendStatus struct {
+	Active bool         // 仍是组织成员且用户状态正常
+	Limit  money.Amount // 每月上限，0 = 不限制
+	Spent  money.Amount // 自 since 起该成员创建的组织 Key 的实际消费
+}
+
+// OrganizationMemberUsage 成员用量汇总（组织仪表盘）
+type OrganizationMemberUsage struct {
+	UserID              int64
+	Email               string
+	Username            string
+	Role                string
+	APIKeyCount         int64
+	TotalRequests       int64
+	InputTokens         int64
+	OutputTokens        int64
+	CacheCreationTokens int64
+	CacheReadTokens     int64
+	TotalCost           money.Amount
+	ActualCost          money.Amount
+}
+
+// OrganizationDailyUsage 组织按天用量趋势
+type OrganizationDailyUsage struct {
+	Date          string
+	TotalRequests int64
+	TotalTokens   int64
+	TotalCost     money.Amount
+	ActualCost    money.Amount
+}
+
+// OrganizationRepository 组织数据访问接口
+type OrganizationRepository interface {
+	Create(ctx context.Context, org *Organization) error
+	GetByID(ctx context.Context, id int64) (*Organization, error)
+	UpdateName(ctx context.Context, id int64, name string) error
+	ListByMember(ctx context.Context, userID int64) ([]OrganizationMembership, error)
+
+	AddMember(ctx context.Context, member *OrganizationMember) error
+	GetMember(ctx context.Context, orgID, userID int64) (*OrganizationMember, error)
+	ListMembers(ctx context.Context, orgID int64) ([]OrganizationMember, error)
+	UpdateMemberRole(ctx context.Context, orgID, userID int64, role string) error
+	UpdateMemberSpendLimit(ctx context.Context, orgID, userID int64, limit money.Amount) error
+	RemoveMember(ctx context.Context, orgID, userID int64) error
+	// DisableMemberAPIKeys 停用成员在组织账户下创建的全部 Key，返回被停用的 key 明文（用于清理认证缓存）
+	DisableMemberAPIKeys(ctx context.Context, accountUserID, memberID int64) ([]string, error)
+
+	CreateInvitation(ctx context.Context, inv *OrganizationInvitation) error
+	GetInvitationByTokenHash(ctx context.Context, tokenHash string) (*OrganizationInvitation, error)
+	ListPendingInvitations(ctx context.Context, orgID int64) ([]OrganizationInvitation, error)
+	RevokeInvitation(ctx context.Context, orgID, invitationID int64) error
+	// MarkInvitationAccepted 仅在邀请仍为 pending 时生效，返回是否更新成功
+	MarkInvitationAccepted(ctx context.Context, invitationID, userID int64, acceptedAt time.Time) (bool, error)
+
+	// GetMemberSpendStatus 按组织账户与成员查询成员状态、上限与自 since 起的消费
+	GetMemberSpendStatus(ctx context.Context, accountUserID, memberID int64, since time.Time) (*OrganizationMemberSpendStatus, error)
+	// ListMemberUsage 基于按天 × API Key 预聚合表汇总各成员在 [start, end) 的用量
+	ListMemberUsage(ctx context.Context, orgID int64, start, end time.Time) ([]OrganizationMemberUsage, error)
+	// ListDailyUsage 组织在 [start, end) 的按天用量趋势
+	ListDailyUsage(ctx context.Context, accountUserID int64, start, end time.Time) ([]OrganizationDailyUsage, error)
+}
+
+// IsValidOrgRole 是否为合法的组织角色
+func IsValidOrgRole(role string) bool {
+	switch role {
+	case OrgRoleOwner, OrgRoleAdmin, OrgRoleMember, OrgRoleBilling:
+		return true
+	}
+	return false
+}
+
+// orgRoleCanManageMembers 可邀请、移除成员并调整角色与限额
+func orgRoleCanManageMembers(role string) bool {
+	return role == OrgRoleOwner || role == OrgRoleAdmin
+}
+
+// orgRoleCanManageBilling 可充值、查看余额流水与组织用量
+func orgRoleCanManageBilling(role string) bool {
+	return role == OrgRoleOwner || role == OrgRoleAdmin || role == OrgRoleBilling
+}
+
+// orgRoleCanCreateAPIKeys 可创建组织 Key
+func orgRoleCanCreateAPIKeys(role string) bool {
+	return role == OrgRoleOwner || role == OrgRoleAdmin || role == OrgRoleMember
+}
+
+// orgRoleCanManageAllAPIKeys 可查看与管理其他成员创建的组织 Key
+func orgRoleCanManageAllAPIKeys(role string) bool {
+	return role == OrgRoleOwner || role == OrgRoleAdmin
+}
@@ -0,0 +1,625 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	dbent "github.com/Wei-Shaw/sub2api/ent"
+	"github.com/Wei-Shaw/sub2api/internal/config"
+	infraerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/logger"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
+)
+
+const (
+	organizationInvitationTTL        = 7 * 24 * time.Hour
+	organizationInvitationTokenBytes = 32
+	organizationNameMaxLength        = 100
+)
+
+// OrganizationDetail 组织详情（含组织账户的共享余额与当前用户角色）
+type OrganizationDetail struct {
+	Organization
+	Role          string
+	Balance       money.Amount
+	Concurrency   int
+	Subscriptions []UserSubscription
+}
+
+// OrganizationUsage 组织用量仪表盘
+type OrganizationUsage struct {
+	StartDate string
+	EndDate   string
+	Members   []OrganizationMemberUsage
+	Daily     []OrganizationDailyUsage
+}
+
+// UpdateOrganizationMemberRequest 更新成员请求（nil 表示不修改）
+type UpdateOrganizationMemberRequest struct {
+	Role              *string
+	MonthlySpendLimit *float64 // USD，0 = 不限制
+}
+
+// OrganizationService 组织服务：组织、成员、邀请与组织 Key。
+//
+// 每个组织对应一个不可登录的组织账户（users.role = organization），共享余额、订阅与并发都挂在该账户上，
+// 组织 Key 的 user_id 为组织账户、created_by_user_id 为创建成员，因此网关鉴权、计费、订阅与对账逻辑无需区分个人与组织。
+type OrganizationService struct {
+	orgRepo              OrganizationRepository
+	userRepo             UserRepository
+	apiKeyService        *APIKeyService
+	billingCacheService  *BillingCacheService
+	redeemService        *RedeemService
+	balanceLedgerService *BalanceLedgerService
+	subscriptionService  *SubscriptionService
+	settingService       *SettingService
+	entClient            *dbent.Client
+	cfg                  *config.Config
+}
+
+// NewOrganizationService 创建组织服务
+func NewOrganizationService(
+	orgRepo OrganizationRepository,
+	userRepo UserRepository,
+	apiKeyService *APIKeyService,
+	billingCacheService *BillingCacheService,
+	redeemService *RedeemService,
+	balanceLedgerService *BalanceLedgerService,
+	subscriptionService *SubscriptionService,
+	settingService *SettingService,
+	entClient *dbent.Client,
+	cfg *config.Config,
+) *OrganizationService {
+	return &OrganizationService{
+		orgRepo:              orgRepo,
+		userRepo:             userRepo,
+		apiKeyService:        apiKeyService,
+		billingCacheService:  billingCacheService,
+		redeemService:        redeemService,
+		balanceLedgerService: balanceLedgerService,
+		subscriptionService:  subscriptionService,
+		settingService:       settingService,
+		entClient:            entClient,
+		cfg:                  cfg,
+	}
+}
+
+// ============================================
+// 组织
+// ============================================
+
+// Create 创建组织：同一事务内创建组织账户、组织与 owner 成员
+func (s *OrganizationService) Create(ctx context.Context, actorID int64, name string) (*OrganizationDetail, error) {
+	name, err := normalizeOrganizationName(name)
+	if err != nil {
+		return nil, err
+	}
+	actor, err := s.userRepo.GetByID(ctx, actorID)
+	if err != nil {
+		return nil, err
+	}
+	if actor.Role == RoleOrganization {
+		return nil, ErrOrganizationForbidden
+	}
+
+	suffix, err := randomHexString(8)
+	if err != nil {
+		return nil, fmt.Errorf("generate organization account email: %w", err)
+	}
+	// 组织账户不可登录：密码哈希不是合法的 bcrypt 值，Login 也会直接拒绝该角色
+	placeholder, err := randomHexString(16)
+	if err != nil {
+		return nil, fmt.Errorf("generate organization account password: %w", err)
+	}
+	account := &User{
+		Email:        "org-" + suffix + OrganizationSyntheticEmailDomain,
+		Username:     name,
+		Notes:        "organization account",
+		PasswordHash: "!org:" + placeholder,
+		Role:         RoleOrganization,
+		Concurrency:  s.defaultConcurrency(ctx),
+		Status:       StatusActive,
+	}
+	org := &Organization{Name: name, CreatedBy: actorID}
+	owner := &OrganizationMember{UserID: actorID, Role: OrgRoleOwner}
+
+	err = s.withTx(ctx, func(txCtx context.Context) error {
+		if err := s.userRepo.Create(txCtx, account); err != nil {
+			return fmt.Errorf("create organization account: %w", err)
+		}
+		org.AccountUserID = account.ID
+		if err := s.orgRepo.Create(txCtx, org); err != nil {
+			return fmt.Errorf("create organization: %w", err)
+		}
+		owner.OrganizationID = org.ID
+		return s.orgRepo.AddMember(txCtx, owner)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &OrganizationDetail{
+		Organization: *org,
+		Role:         OrgRoleOwner,
+		Balance:      account.Balance,
+		Concurrency:  account.Concurrency,
+	}, nil
+}
+
+// ListMine 列出用户所属的组织
+func (s *OrganizationService) ListMine(ctx context.Context, userID int64) ([]OrganizationMembership, error) {
+	return s.orgRepo.ListByMember(ctx, userID)
+}
+
+// Get 获取组织详情（任意成员可见）
+func (s *OrganizationService) Get(ctx context.Context, orgID, actorID int64) (*OrganizationDetail, error) {
+	org, member, err := s.authorize(ctx, orgID, actorID, nil)
+	if err != nil {
+		return nil, err
+	}
+	account, err := s.userRepo.GetByID(ctx, org.AccountUserID)
+	if err != nil {
+		return nil, fmt.Errorf("get organization account: %w", err)
+	}
+	detail := &OrganizationDetail{
+		Organization: *org,
+		Role:         member.Role,
+		Balance:      account.Balance,
+		Concurrency:  account.Concurrency,
+	}
+	if s.subscriptionService != nil {
+		subs, err := s.subscriptionService.ListActiveUserSubscriptions(ctx, org.AccountUserID)
+		if err != nil {
+			return nil, fmt.Errorf("list organization subscriptions: %w", err)
+		}
+		detail.Subscriptions = subs
+	}
+	return detail, nil
+}
+
+// UpdateName 修改组织名称（owner/admin）
+func (s *OrganizationService) UpdateName(ctx context.Context, orgID, actorID int64, name string) error {
+	name, err := normalizeOrganizationName(name)
+	if err != nil {
+		return err
+	}
+	if _, _, err := s.authorize(ctx, orgID, actorID, orgRoleCanManageMembers); err != nil {
+		return err
+	}
+	return s.orgRepo.UpdateName(ctx, orgID, name)
+}
+
+// ============================================
+// 成员
+// ============================================
+
+// ListMembers 列出组织成员（任意成员可见）
+func (s *OrganizationService) ListMembers(ctx context.Context, orgID, actorID int64) ([]OrganizationMember, error) {
+	if _, _, err := s.authorize(ctx, orgID, actorID, nil); err != nil {
+		return nil, err
+	}
+	return s.orgRepo.ListMembers(ctx, orgID)
+}
+
+// UpdateMember 调整成员角色或每月消费上限。
+// owner 可调整任何非 owner 成员；admin 只能调整 member/billing，且只能授予 member/billing。
+// 所有权只能通过 TransferOwnership 转让。
+func (s *OrganizationService) UpdateMember(ctx context.Context, orgID, actorID, targetID int64, req UpdateOrganizationMemberRequest) (*OrganizationMember, error) {
+	org, actor, err := s.authorize(ctx, orgID, actorID, orgRoleCanManageMembers)
+	if err != nil {
+		return nil, err
+	}
+	target, err := s.orgRepo.GetMember(ctx, orgID, targetID)
+	if err != nil {
+		return nil, err
+	}
+	if target.Role == OrgRoleOwner {
+		// owner 的角色不可直接修改；仅 owner 本人可为自己设置上限
+		if req.Role != nil || actor.Role != OrgRoleOwner {
+			return nil, ErrOrganizationForbidden
+		}
+	} else if !orgRoleCanManageRole(actor.Role, target.Role) {
+		return nil, ErrOrganizationForbidden
+	}
+
+	if req.Role != nil {
+		role := strings.TrimSpace(*req.Role)
+		if !IsValidOrgRole(role) || role == OrgRoleOwner {
+			return nil, ErrOrganizationInvalidRole
+		}
+		if !orgRoleCanManageRole(actor.Role, role) {
+			return nil, ErrOrganizationForbidden
+		}
+		if err := s.orgRepo.UpdateMemberRole(ctx, orgID, targetID, role); err != nil {
+			return nil, err
+		}
+	}
+	if req.MonthlySpendLimit != nil {
+		if *req.MonthlySpendLimit < 0 {
+			return nil, infraerrors.BadRequest("ORGANIZATION_INVALID_SPEND_LIMIT", "monthly spend limit must be >= 0")
+		}
+		if err := s.orgRepo.UpdateMemberSpendLimit(ctx, orgID, targetID, money.FromFloat(*req.MonthlySpendLimit)); err != nil {
+			return nil, err
+		}
+	}
+	s.invalidateMemberSpend(ctx, org.AccountUserID, targetID)
+	return s.orgRepo.GetMember(ctx, orgID, targetID)
+}
+
+// RemoveMember 移除成员或主动退出组织（actorID == targetID）。
+// 被移除成员创建的组织 Key 会被停用，并清理认证缓存立即生效。
+func (s *OrganizationService) RemoveMember(ctx context.Context, orgID, actorID, targetID int64) error {
+	var allowed func(role string) bool
+	if actorID != targetID {
+		allowed = orgRoleCanManageMembers
+	}
+	org, actor, err := s.authorize(ctx, orgID, actorID, allowed)
+	if err != nil {
+		return err
+	}
+	target, err := s.orgRepo.GetMember(ctx, orgID, targetID)
+	if err != nil {
+		return err
+	}
+	if target.Role == OrgRoleOwner {
+		return ErrOrganizationOwnerRequired
+	}
+	if actorID != targetID && !orgRoleCanManageRole(actor.Role, target.Role) {
+		return ErrOrganizationForbidden
+	}
+
+	var disabledKeys []string
+	err = s.withTx(ctx, func(txCtx context.Context) error {
+		if err := s.orgRepo.RemoveMember(txCtx, orgID, targetID); err != nil {
+			return err
+		}
+		keys, err := s.orgRepo.DisableMemberAPIKeys(txCtx, org.AccountUserID, targetID)
+		if err != nil {
+			return fmt.Errorf("disable member api keys: %w", err)
+		}
+		disabledKeys = keys
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for _, key := range disabledKeys {
+		s.apiKeyService.InvalidateAuthCacheByKey(ctx, key)
+	}
+	s.invalidateMemberSpend(ctx, org.AccountUserID, targetID)
+	return nil
+}
+
+// TransferOwnership 转让所有权（仅 owner），原 owner 降为 admin
+func (s *OrganizationService) TransferOwnership(ctx context.Context, orgID, actorID, targetID int64) error {
+	if _, _, err := s.authorize(ctx, orgID, actorID, func(role string) bool { return role == OrgRoleOwner }); err != nil {
+		return err
+	}
+	if actorID == targetID {
+		return nil
+	}
+	if _, err := s.orgRepo.GetMember(ctx, orgID, targetID); err != nil {
+		return err
+	}
+	// 每个组织仅允许一个 owner（部分唯一索引），先降级再提升
+	return s.withTx(ctx, func(txCtx context.Context) error {
+		if err := s.orgRepo.UpdateMemberRole(txCtx, orgID, actorID, OrgRoleAdmin); err != nil {
+			return err
+		}
+		return s.orgRepo.UpdateMemberRole(txCtx, orgID, targetID, OrgRoleOwner)
+	})
+}
+
+// ============================================
+// 邀请
+// ============================================
+
+// InviteMember 邀请成员，返回邀请与一次性明文令牌（仅保存其 SHA-256）
+func (s *OrganizationService) InviteMember(ctx context.Context, orgID, actorID int64, email, role string) (*OrganizationInvitation, string, error) {
+	_, actor, err := s.authorize(ctx, orgID, actorID, orgRoleCanManageMembers)
+	if err != nil {
+		return nil, "", err
+	}
+	email = strings.TrimSpace(email)
+	if email == "" || !strings.Contains(email, "@") || isReservedEmail(email) {
+		return nil, "", infraerrors.BadRequest("ORGANIZATION_INVALID_EMAIL", "invalid email address")
+	}
+	role = strings.TrimSpace(role)
+	if role == "" {
+		role = OrgRoleMember
+	}
+	if !IsValidOrgRole(role) || role == OrgRoleOwner {
+		return nil, "", ErrOrganizationInvalidRole
+	}
+	if !orgRoleCanManageRole(actor.Role, role) {
+		return nil, "", ErrOrganizationForbidden
+	}
+
+	token, err := generateOrganizationInvitationToken()
+	if err != nil {
+		return nil, "", fmt.Errorf("generate invitation token: %w", err)
+	}
+	inv := &OrganizationInvitation{
+		OrganizationID: orgID,
+		Email:          email,
+		Role:           role,
+		TokenHash:      hashToken(token),
+		InvitedBy:      actorID,
+		Status:         OrgInvitationStatusPending,
+		ExpiresAt:      time.Now().Add(organizationInvitationTTL),
+	}
+	if err := s.orgRepo.CreateInvitation(ctx, inv); err != nil {
+		return nil, "", err
+	}
+	return inv, token, nil
+}
+
+// ListInvitations 列出待处理邀请（owner/admin）
+func (s *OrganizationService) ListInvitations(ctx context.Context, orgID, actorID int64) ([]OrganizationInvitation, error) {
+	if _, _, err := s.authorize(ctx, orgID, actorID, orgRoleCanManageMembers); err != nil {
+		return nil, err
+	}
+	return s.orgRepo.ListPendingInvitations(ctx, orgID)
+}
+
+// RevokeInvitation 撤销待处理邀请（owner/admin）
+func (s *OrganizationService) RevokeInvitation(ctx context.Context, orgID, actorID, invitationID int64) error {
+	if _, _, err := s.authorize(ctx, orgID, actorID, orgRoleCanManageMembers); err != nil {
+		return err
+	}
+	return s.orgRepo.RevokeInvitation(ctx, orgID, invitationID)
+}
+
+// AcceptInvitation 接受邀请：当前登录用户的邮箱须与邀请邮箱一致（不区分大小写）
+func (s *OrganizationService) AcceptInvitation(ctx context.Context, userID int64, token string) (*OrganizationMembership, error) {
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return nil, ErrOrganizationInvitationInvalid
+	}
+	inv, err := s.orgRepo.GetInvitationByTokenHash(ctx, hashToken(token))
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if inv.Status != OrgInvitationStatusPending || !now.Before(inv.ExpiresAt) {
+		return nil, ErrOrganizationInvitationInvalid
+	}
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.Role == RoleOrganization {
+		return nil, ErrOrganizationForbidden
+	}
+	if !strings.EqualFold(strings.TrimSpace(user.Email), strings.TrimSpace(inv.Email)) {
+		return nil, ErrOrganizationInvitationEmail
+	}
+	org, err := s.orgRepo.GetByID(ctx, inv.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.withTx(ctx, func(txCtx context.Context) error {
+		accepted, err := s.orgRepo.MarkInvitationAccepted(txCtx, inv.ID, userID, now)
+		if err != nil {
+			return err
+		}
+		if !accepted {
+			return ErrOrganizationInvitationInvalid
+		}
+		return s.orgRepo.AddMember(txCtx, &OrganizationMember{
+			OrganizationID: inv.OrganizationID,
+			UserID:         userID,
+			Role:           inv.Role,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	// 重新加入的成员可能残留“已离开”的上限缓存
+	s.invalidateMemberSpend(ctx, org.AccountUserID, userID)
+	return &OrganizationMembership{Organization: *org, Role: inv.Role}, nil
+}
+
+// ============================================
+// 组织 API Key
+// ============================================
+
+// ListAPIKeys owner/admin 可见全部组织 Key，其他成员仅可见自己创建的 Key
+func (s *OrganizationService) ListAPIKeys(ctx context.Context, orgID, actorID int64, params pagination.PaginationParams) ([]APIKey, *pagination.PaginationResult, error) {
+	org, member, err := s.authorize(ctx, orgID, actorID, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	if orgRoleCanManageAllAPIKeys(member.Role) {
+		return s.apiKeyService.List(ctx, org.AccountUserID, params)
+	}
+	return s.apiKeyService.ListByCreator(ctx, org.AccountUserID, actorID, params)
+}
+
+// CreateAPIKey 以组织账户为所有者创建 Key，并记录创建成员
+func (s *OrganizationService) CreateAPIKey(ctx context.Context, orgID, actorID int64, req CreateAPIKeyRequest) (*APIKey, error) {
+	org, _, err := s.authorize(ctx, orgID, actorID, orgRoleCanCreateAPIKeys)
+	if err != nil {
+		return nil, err
+	}
+	req.CreatedByUserID = &actorID
+	return s.apiKeyService.Create(ctx, org.AccountUserID, req)
+}
+
+// UpdateAPIKey 更新组织 Key（创建者本人或 owner/admin）
+func (s *OrganizationService) UpdateAPIKey(ctx context.Context, orgID, actorID, keyID int64, req UpdateAPIKeyRequest) (*APIKey, error) {
+	org, err := s.authorizeAPIKey(ctx, orgID, actorID, keyID)
+	if err != nil {
+		return nil, err
+	}
+	return s.apiKeyService.Update(ctx, keyID, org.AccountUserID, req)
+}
+
+// DeleteAPIKey 删除组织 Key（创建者本人或 owner/admin）
+func (s *OrganizationService) DeleteAPIKey(ctx context.Context, orgID, actorID, keyID int64) error {
+	org, err := s.authorizeAPIKey(ctx, orgID, actorID, keyID)
+	if err != nil {
+		return err
+	}
+	return s.apiKeyService.Delete(ctx, keyID, org.AccountUserID)
+}
+
+func (s *OrganizationService) authorizeAPIKey(ctx context.Context, orgID, actorID, keyID int64) (*Organization, error) {
+	org, member, err := s.authorize(ctx, orgID, actorID, nil)
+	if err != nil {
+		return nil, err
+	}
+	key, err := s.apiKeyService.GetByID(ctx, keyID)
+	if err != nil {
+		return nil, err
+	}
+	if key.UserID != org.AccountUserID {
+		return nil, ErrAPIKeyNotFound
+	}
+	if orgRoleCanManageAllAPIKeys(member.Role) {
+		return org, nil
+	}
+	if !orgRoleCanCreateAPIKeys(member.Role) || key.CreatedByUserID == nil || *key.CreatedByUserID != actorID {
+		return nil, ErrOrganizationForbidden
+	}
+	return org, nil
+}
+
+// ============================================
+// 账单与用量
+// ============================================
+
+// Redeem 使用兑换码为组织充值（余额/订阅/并发均计入组织账户）
+func (s *OrganizationService) Redeem(ctx context.Context, orgID, actorID int64, code string) (*RedeemCode, error) {
+	org, _, err := s.authorize(ctx, orgID, actorID, orgRoleCanManageBilling)
+	if err != nil {
+		return nil, err
+	}
+	return s.redeemService.Redeem(ctx, org.AccountUserID, code)
+}
+
+// ListTransactions 组织账户的余额流水
+func (s *OrganizationService) ListTransactions(ctx context.Context, orgID, actorID int64, params pagination.PaginationParams, filter BalanceLedgerFilter) ([]BalanceLedgerEntry, *pagination.PaginationResult, error) {
+	org, _, err := s.authorize(ctx, orgID, actorID, orgRoleCanManageBilling)
+	if err != nil {
+		return nil, nil, err
+	}
+	return s.balanceLedgerService.ListUserTransactions(ctx, org.AccountUserID, params, filter)
+}
+
+// GetUsage 组织用量仪表盘：[start, end) 内按成员汇总与按天趋势（基于按天 × API Key 预聚合）
+func (s *OrganizationService) GetUsage(ctx context.Context, orgID, actorID int64, start, end time.Time) (*OrganizationUsage, error) {
+	org, _, err := s.authorize(ctx, orgID, actorID, orgRoleCanManageBilling)
+	if err != nil {
+		return nil, err
+	}
+	members, err := s.orgRepo.ListMemberUsage(ctx, orgID, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("list member usage: %w", err)
+	}
+	daily, err := s.orgRepo.ListDailyUsage(ctx, org.AccountUserID, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("list daily usage: %w", err)
+	}
+	return &OrganizationUsage{
+		StartDate: start.Format("2006-01-02"),
+		EndDate:   end.AddDate(0, 0, -1).Format("2006-01-02"),
+		Members:   members,
+		Daily:     daily,
+	}, nil
+}
+
+// ============================================
+// 内部方法
+// ============================================
+
+// authorize 校验 actor 是组织成员，allowed 非 nil 时还须满足角色要求
+func (s *OrganizationService) authorize(ctx context.Context, orgID, actorID int64, allowed func(role string) bool) (*Organization, *OrganizationMember, error) {
+	member, err := s.orgRepo.GetMember(ctx, orgID, actorID)
+	if err != nil {
+		if errors.Is(err, ErrOrganizationMemberNotFound) {
+			// 非成员不暴露组织是否存在
+			return nil, nil, ErrOrganizationNotFound
+		}
+		return nil, nil, err
+	}
+	if allowed != nil && !allowed(member.Role) {
+		return nil, nil, ErrOrganizationForbidden
+	}
+	org, err := s.orgRepo.GetByID(ctx, orgID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return org, member, nil
+}
+
+func (s *OrganizationService) invalidateMemberSpend(ctx context.Context, accountUserID, memberID int64) {
+	if s.billingCacheService == nil {
+		return
+	}
+	if err := s.billingCacheService.InvalidateOrgMemberSpend(ctx, accountUserID, memberID); err != nil {
+		logger.LegacyPrintf("service.organization", "[Organization] invalidate member spend cache failed: account=%d member=%d err=%v", accountUserID, memberID, err)
+	}
+}
+
+func (s *OrganizationService) defaultConcurrency(ctx context.Context) int {
+	if s.settingService != nil {
+		return s.settingService.GetDefaultConcurrency(ctx)
+	}
+	if s.cfg != nil {
+		return s.cfg.Default.UserConcurrency
+	}
+	return 1
+}
+
+func (s *OrganizationService) withTx(ctx context.Context, fn func(txCtx context.Context) error) error {
+	if s.entClient == nil {
+		return fn(ctx)
+	}
+	tx, err := s.entClient.Tx(ctx)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err := fn(dbent.NewTxContext(ctx, tx)); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+	return nil
+}
+
+// orgRoleCanManageRole actorRole 是否可以管理（调整、移除或授予）targetRole
+func orgRoleCanManageRole(actorRole, targetRole string) bool {
+	switch actorRole {
+	case OrgRoleOwner:
+		return targetRole != OrgRoleOwner
+	case OrgRoleAdmin:
+		return targetRole == OrgRoleMember || targetRole == OrgRoleBilling
+	}
+	return false
+}
+
+func normalizeOrganizationName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || len([]rune(name)) > organizationNameMaxLength {
+		return "", infraerrors.BadRequest("ORGANIZATION_INVALID_NAME", "organization name must be 1-100 characters")
+	}
+	return name, nil
+}
+
+func generateOrganizationInvitationToken() (string, error) {
+	buf := make([]byte, organizationInvitationTokenBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
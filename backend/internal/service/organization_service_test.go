@@ -0,0 +1,400 @@
+//go:build unit
+
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
+	"github.com/stretchr/testify/require"
+)
+
+type orgMemberKey struct {
+	orgID  int64
+	userID int64
+}
+
+// organizationRepoFake 内存实现，仅覆盖服务层测试用到的行为
+type organizationRepoFake struct {
+	orgs        map[int64]*Organization
+	members     map[orgMemberKey]*OrganizationMember
+	invitations map[int64]*OrganizationInvitation
+	memberKeys  map[int64][]string // memberID -> 组织 Key
+	spend       map[int64]*OrganizationMemberSpendStatus
+
+	nextOrgID        int64
+	nextInvitationID int64
+	disabledFor      []int64
+}
+
+func newOrganizationRepoFake() *organizationRepoFake {
+	return &organizationRepoFake{
+		orgs:        map[int64]*Organization{},
+		members:     map[orgMemberKey]*OrganizationMember{},
+		invitations: map[int64]*OrganizationInvitation{},
+		memberKeys:  map[int64][]string{},
+		spend:       map[int64]*OrganizationMemberSpendStatus{},
+	}
+}
+
+func (r *organizationRepoFake) seed(orgID, accountUserID int64, members map[int64]string) {
+	r.orgs[orgID] = &Organization{ID: orgID, Name: "acme", AccountUserID: accountUserID}
+	for userID, role := range members {
+		r.members[orgMemberKey{orgID, userID}] = &OrganizationMember{OrganizationID: orgID, UserID: userID, Role: role}
+	}
+}
+
+func (r *organizationRepoFake) Create(ctx context.Context, org *Organization) error {
+	r.nextOrgID++
+	org.ID = r.nextOrgID
+	cp := *org
+	r.orgs[org.ID] = &cp
+	return nil
+}
+
+func (r *organizationRepoFake) GetByID(ctx context.Context, id int64) (*Organization, error) {
+	org, ok := r.orgs[id]
+	if !ok {
+		return nil, ErrOrganizationNotFound
+	}
+	cp := *org
+	return &cp, nil
+}
+
+func (r *organizationRepoFake) UpdateName(ctx context.Context, id int64, name string) error {
+	r.orgs[id].Name = name
+	return nil
+}
+
+func (r *organizationRepoFake) ListByMember(ctx context.Context, userID int64) ([]OrganizationMembership, error) {
+	var out []OrganizationMembership
+	for key, m := range r.members {
+		if key.userID == userID {
+			out = append(out, OrganizationMembership{Organization: *r.orgs[key.orgID], Role: m.Role})
+		}
+	}
+	return out, nil
+}
+
+func (r *organizationRepoFake) AddMember(ctx context.Context, member *OrganizationMember) error {
+	key := orgMemberKey{member.OrganizationID, member.UserID}
+	if _, ok := r.members[key]; ok {
+		return ErrOrganizationMemberExists
+	}
+	cp := *member
+	r.members[key] = &cp
+	return nil
+}
+
+func (r *organizationRepoFake) GetMember(ctx context.Context, orgID, userID int64) (*OrganizationMember, error) {
+	m, ok := r.members[orgMemberKey{orgID, userID}]
+	if !ok {
+		return nil, ErrOrganizationMemberNotFound
+	}
+	cp := *m
+	return &cp, nil
+}
+
+func (r *organizationRepoFake) ListMembers(ctx context.Context, orgID int64) ([]OrganizationMember, error) {
+	var out []OrganizationMember
+	for key, m := range r.members {
+		if key.orgID == orgID {
+			out = append(out, *m)
+		}
+	}
+	return out, nil
+}
+
+func (r *organizationRepoFake) UpdateMemberRole(ctx context.Context, orgID, userID int64, role string) error {
+	m, ok := r.members[orgMemberKey{orgID, userID}]
+	if !ok {
+		return ErrOrganizationMemberNotFound
+	}
+	m.Role = role
+	return nil
+}
+
+func (r *organizationRepoFake) UpdateMemberSpendLimit(ctx context.Context, orgID, userID int64, limit money.Amount) error {
+	m, ok := r.members[orgMemberKey{orgID, userID}]
+	if !ok {
+		return ErrOrganizationMemberNotFound
+	}
+	m.MonthlySpendLimit = limit
+	return nil
+}
+
+func (r *organizationRepoFake) RemoveMember(ctx context.Context, orgID, userID int64) error {
+	key := orgMemberKey{orgID, userID}
+	if _, ok := r.members[key]; !ok {
+		return ErrOrganizationMemberNotFound
+	}
+	delete(r.members, key)
+	return nil
+}
+
+func (r *organizationRepoFake) DisableMemberAPIKeys(ctx context.Context, accountUserID, memberID int64) ([]string, error) {
+	r.disabledFor = append(r.disabledFor, memberID)
+	return r.memberKeys[memberID], nil
+}
+
+func (r *organizationRepoFake) CreateInvitation(ctx context.Context, inv *OrganizationInvitation) error {
+	for _, existing := range r.invitations {
+		if existing.OrganizationID == inv.OrganizationID && existing.Status == OrgInvitationStatusPending && existing.Email == inv.Email {
+			return ErrOrganizationInvitationExists
+		}
+	}
+	r.nextInvitationID++
+	inv.ID = r.nextInvitationID
+	cp := *inv
+	r.invitations[inv.ID] = &cp
+	return nil
+}
+
+func (r *organizationRepoFake) GetInvitationByTokenHash(ctx context.Context, tokenHash string) (*OrganizationInvitation, error) {
+	for _, inv := range r.invitations {
+		if inv.TokenHash == tokenHash {
+			cp := *inv
+			return &cp, nil
+		}
+	}
+	return nil, ErrOrganizationInvitationInvalid
+}
+
+func (r *organizationRepoFake) ListPendingInvitations(ctx context.Context, orgID int64) ([]OrganizationInvitation, error) {
+	var out []OrganizationInvitation
+	for _, inv := range r.invitations {
+		if inv.OrganizationID == orgID && inv.Status == OrgInvitationStatusPending {
+			out = append(out, *inv)
+		}
+	}
+	return out, nil
+}
+
+func (r *organizationRepoFake) RevokeInvitation(ctx context.Context, orgID, invitationID int64) error {
+	inv, ok := r.invitations[invitationID]
+	if !ok || inv.OrganizationID != orgID || inv.Status != OrgInvitationStatusPending {
+		return ErrOrganizationInvitationMissing
+	}
+	inv.Status = OrgInvitationStatusRevoked
+	return nil
+}
+
+func (r *organizationRepoFake) MarkInvitationAccepted(ctx context.Context, invitationID, userID int64, acceptedAt time.Time) (bool, error) {
+	inv, ok := r.invitations[invitationID]
+	if !ok || inv.Status != OrgInvitationStatusPending {
+		return false, nil
+	}
+	inv.Status = OrgInvitationStatusAccepted
+	inv.AcceptedBy = &userID
+	inv.AcceptedAt = &acceptedAt
+	return true, nil
+}
+
+func (r *organizationRepoFake) GetMemberSpendStatus(ctx context.Context, accountUserID, memberID int64, since time.Time) (*OrganizationMemberSpendStatus, error) {
+	if status, ok := r.spend[memberID]; ok {
+		return status, nil
+	}
+	return &OrganizationMemberSpendStatus{}, nil
+}
+
+func (r *organizationRepoFake) ListMemberUsage(ctx context.Context, orgID int64, start, end time.Time) ([]OrganizationMemberUsage, error) {
+	return nil, nil
+}
+
+func (r *organizationRepoFake) ListDailyUsage(ctx context.Context, accountUserID int64, start, end time.Time) ([]OrganizationDailyUsage, error) {
+	return nil, nil
+}
+
+func newOrganizationServiceForTest(repo *organizationRepoFake, userRepo *userRepoStub) *OrganizationService {
+	return NewOrganizationService(repo, userRepo, &APIKeyService{}, nil, nil, nil, nil, nil, nil, nil)
+}
+
+func TestOrganizationService_CreateAddsAccountAndOwner(t *testing.T) {
+	repo := newOrganizationRepoFake()
+	userRepo := &userRepoStub{user: &User{ID: 7, Role: RoleUser, Status: StatusActive}, nextID: 100}
+	svc := newOrganizationServiceForTest(repo, userRepo)
+
+	detail, err := svc.Create(context.Background(), 7, "  Acme  ")
+	require.NoError(t, err)
+	require.Equal(t, "Acme", detail.Name)
+	require.Equal(t, OrgRoleOwner, detail.Role)
+	require.Equal(t, int64(100), detail.AccountUserID)
+
+	require.Len(t, userRepo.created, 1)
+	account := userRepo.created[0]
+	require.Equal(t, RoleOrganization, account.Role)
+	require.True(t, isReservedEmail(account.Email))
+
+	owner, err := repo.GetMember(context.Background(), detail.ID, 7)
+	require.NoError(t, err)
+	require.Equal(t, OrgRoleOwner, owner.Role)
+}
+
+func TestOrganizationService_CreateRejectsInvalidName(t *testing.T) {
+	svc := newOrganizationServiceForTest(newOrganizationRepoFake(), &userRepoStub{user: &User{ID: 7}})
+
+	_, err := svc.Create(context.Background(), 7, "   ")
+	require.Error(t, err)
+}
+
+func TestOrganizationService_NonMemberSeesNotFound(t *testing.T) {
+	repo := newOrganizationRepoFake()
+	repo.seed(1, 100, map[int64]string{1: OrgRoleOwner})
+	svc := newOrganizationServiceForTest(repo, &userRepoStub{})
+
+	_, err := svc.ListMembers(context.Background(), 1, 99)
+	require.ErrorIs(t, err, ErrOrganizationNotFound)
+}
+
+func TestOrganizationService_RolePermissions(t *testing.T) {
+	repo := newOrganizationRepoFake()
+	repo.seed(1, 100, map[int64]string{
+		1: OrgRoleOwner,
+		2: OrgRoleAdmin,
+		3: OrgRoleMember,
+		4: OrgRoleBilling,
+	})
+	svc := newOrganizationServiceForTest(repo, &userRepoStub{})
+	ctx := context.Background()
+
+	// billing 不能创建 Key，member 不能邀请成员
+	_, err := svc.CreateAPIKey(ctx, 1, 4, CreateAPIKeyRequest{Name: "k"})
+	require.ErrorIs(t, err, ErrOrganizationForbidden)
+	_, _, err = svc.InviteMember(ctx, 1, 3, "new@example.com", OrgRoleMember)
+	require.ErrorIs(t, err, ErrOrganizationForbidden)
+
+	// member 不能查看账单
+	_, err = svc.GetUsage(ctx, 1, 3, time.Now().AddDate(0, 0, -7), time.Now())
+	require.ErrorIs(t, err, ErrOrganizationForbidden)
+	_, err = svc.GetUsage(ctx, 1, 4, time.Now().AddDate(0, 0, -7), time.Now())
+	require.NoError(t, err)
+
+	// admin 不能授予 admin，也不能修改其他 admin 或 owner
+	admin := OrgRoleAdmin
+	_, err = svc.UpdateMember(ctx, 1, 2, 3, UpdateOrganizationMemberRequest{Role: &admin})
+	require.ErrorIs(t, err, ErrOrganizationForbidden)
+	limit := 5.0
+	_, err = svc.UpdateMember(ctx, 1, 2, 1, UpdateOrganizationMemberRequest{MonthlySpendLimit: &limit})
+	require.ErrorIs(t, err, ErrOrganizationForbidden)
+
+	// owner 可以提升 member 为 admin 并设置限额
+	updated, err := svc.UpdateMember(ctx, 1, 1, 3, UpdateOrganizationMemberRequest{Role: &admin, MonthlySpendLimit: &limit})
+	require.NoError(t, err)
+	require.Equal(t, OrgRoleAdmin, updated.Role)
+	require.Equal(t, 5*money.USD, updated.MonthlySpendLimit)
+
+	// 不能通过 UpdateMember 授予 owner
+	owner := OrgRoleOwner
+	_, err = svc.UpdateMember(ctx, 1, 1, 4, UpdateOrganizationMemberRequest{Role: &owner})
+	require.ErrorIs(t, err, ErrOrganizationInvalidRole)
+}
+
+func TestOrganizationService_RemoveMember(t *testing.T) {
+	repo := newOrganizationRepoFake()
+	repo.seed(1, 100, map[int64]string{
+		1: OrgRoleOwner,
+		2: OrgRoleAdmin,
+		3: OrgRoleMember,
+	})
+	svc := newOrganizationServiceForTest(repo, &userRepoStub{})
+	ctx := context.Background()
+
+	require.ErrorIs(t, svc.RemoveMember(ctx, 1, 2, 1), ErrOrganizationOwnerRequired)
+	require.ErrorIs(t, svc.RemoveMember(ctx, 1, 1, 1), ErrOrganizationOwnerRequired)
+	require.ErrorIs(t, svc.RemoveMember(ctx, 1, 3, 2), ErrOrganizationForbidden)
+
+	// 成员主动退出：Key 随之停用
+	require.NoError(t, svc.RemoveMember(ctx, 1, 3, 3))
+	require.Equal(t, []int64{3}, repo.disabledFor)
+	_, err := repo.GetMember(ctx, 1, 3)
+	require.ErrorIs(t, err, ErrOrganizationMemberNotFound)
+}
+
+func TestOrganizationService_TransferOwnership(t *testing.T) {
+	repo := newOrganizationRepoFake()
+	repo.seed(1, 100, map[int64]string{
+		1: OrgRoleOwner,
+		2: OrgRoleMember,
+	})
+	svc := newOrganizationServiceForTest(repo, &userRepoStub{})
+	ctx := context.Background()
+
+	require.ErrorIs(t, svc.TransferOwnership(ctx, 1, 2, 2), ErrOrganizationForbidden)
+	require.NoError(t, svc.TransferOwnership(ctx, 1, 1, 2))
+
+	prev, _ := repo.GetMember(ctx, 1, 1)
+	next, _ := repo.GetMember(ctx, 1, 2)
+	require.Equal(t, OrgRoleAdmin, prev.Role)
+	require.Equal(t, OrgRoleOwner, next.Role)
+}
+
+func TestOrganizationService_InvitationFlow(t *testing.T) {
+	repo := newOrganizationRepoFake()
+	repo.seed(1, 100, map[int64]string{1: OrgRoleOwner})
+	userRepo := &userRepoStub{user: &User{ID: 5, Email: "Bob@Example.com", Role: RoleUser}}
+	svc := newOrganizationServiceForTest(repo, userRepo)
+	ctx := context.Background()
+
+	inv, token, err := svc.InviteMember(ctx, 1, 1, "bob@example.com", OrgRoleBilling)
+	require.NoError(t, err)
+	require.NotEmpty(t, token)
+	require.NotEqual(t, token, inv.TokenHash)
+	require.Equal(t, hashToken(token), inv.TokenHash)
+
+	_, err = svc.AcceptInvitation(ctx, 5, "wrong-token")
+	require.ErrorIs(t, err, ErrOrganizationInvitationInvalid)
+
+	membership, err := svc.AcceptInvitation(ctx, 5, token)
+	require.NoError(t, err)
+	require.Equal(t, OrgRoleBilling, membership.Role)
+	require.Equal(t, int64(1), membership.Organization.ID)
+
+	// 同一令牌不能重复使用
+	_, err = svc.AcceptInvitation(ctx, 5, token)
+	require.ErrorIs(t, err, ErrOrganizationInvitationInvalid)
+}
+
+func TestOrganizationService_AcceptInvitationRejectsMismatchAndExpired(t *testing.T) {
+	repo := newOrganizationRepoFake()
+	repo.seed(1, 100, map[int64]string{1: OrgRoleOwner})
+	userRepo := &userRepoStub{user: &User{ID: 5, Email: "mallory@example.com", Role: RoleUser}}
+	svc := newOrganizationServiceForTest(repo, userRepo)
+	ctx := context.Background()
+
+	inv, token, err := svc.InviteMember(ctx, 1, 1, "bob@example.com", "")
+	require.NoError(t, err)
+	require.Equal(t, OrgRoleMember, inv.Role)
+
+	_, err = svc.AcceptInvitation(ctx, 5, token)
+	require.ErrorIs(t, err, ErrOrganizationInvitationEmail)
+
+	userRepo.user.Email = "bob@example.com"
+	repo.invitations[inv.ID].ExpiresAt = time.Now().Add(-time.Minute)
+	_, err = svc.AcceptInvitation(ctx, 5, token)
+	require.ErrorIs(t, err, ErrOrganizationInvitationInvalid)
+}
+
+func TestBillingCacheService_CheckOrgMemberSpendLimit(t *testing.T) {
+	repo := newOrganizationRepoFake()
+	repo.spend[2] = &OrganizationMemberSpendStatus{Active: true, Limit: 10 * money.USD, Spent: 10 * money.USD}
+	repo.spend[3] = &OrganizationMemberSpendStatus{Active: true, Limit: 10 * money.USD, Spent: 3 * money.USD}
+	repo.spend[4] = &OrganizationMemberSpendStatus{Active: false}
+	repo.spend[5] = &OrganizationMemberSpendStatus{Active: true, Spent: 1000 * money.USD}
+	svc := &BillingCacheService{orgMemberSpendLoader: repo}
+	ctx := context.Background()
+
+	keyFor := func(memberID int64) *APIKey {
+		return &APIKey{ID: memberID, UserID: 100, CreatedByUserID: &memberID}
+	}
+
+	require.ErrorIs(t, svc.checkOrgMemberSpendLimit(ctx, keyFor(2)), ErrOrganizationMemberSpendLimitExceeded)
+	require.NoError(t, svc.checkOrgMemberSpendLimit(ctx, keyFor(3)))
+	require.ErrorIs(t, svc.checkOrgMemberSpendLimit(ctx, keyFor(4)), ErrOrganizationMemberInactive)
+	// 上限为 0 表示不限制
+	require.NoError(t, svc.checkOrgMemberSpendLimit(ctx, keyFor(5)))
+
+	require.False(t, (&APIKey{UserID: 100}).IsOrganizationKey())
+	require.True(t, keyFor(2).IsOrganizationKey())
+}
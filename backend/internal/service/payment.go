@@ -0,0 +1,258 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"time"
+
+	infraerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
+)
+
+// 支付商品类型
+const (
+	PaymentProductKindBalance      = "balance"      // 余额充值
+	PaymentProductKindSubscription = "subscription" // 订阅套餐
+)
+
+// 支付订单状态
+//
+//	pending ──支付成功──> paid ──权益发放──> fulfilled ──退款──> refunded
+//	   └──超时/取消──> closed（关闭后仍收到支付成功通知时照常发放）
+const (
+	PaymentOrderStatusPending   = "pending"   // 待支付
+	PaymentOrderStatusPaid      = "paid"      // 已支付，待发放（发放失败时停留在此状态等待重试）
+	PaymentOrderStatusFulfilled = "fulfilled" // 已发放
+	PaymentOrderStatusClosed    = "closed"    // 超时或用户取消
+	PaymentOrderStatusRefunded  = "refunded"  // 已退款
+)
+
+// 支付渠道
+const (
+	PaymentProviderEPay   = "epay"
+	PaymentProviderAlipay = "alipay"
+	PaymentProviderWechat = "wechat"
+	PaymentProviderStripe = "stripe"
+)
+
+// 支付订单发放权益所用的兑换码
+const (
+	// PaymentRedeemCodePrefix 兑换码前缀，兑换码为前缀 + 订单号
+	PaymentRedeemCodePrefix = "PAY-"
+	// PaymentRedeemCategory 兑换码分类
+	PaymentRedeemCategory = "payment"
+)
+
+var (
+	ErrPaymentDisabled            = infraerrors.Forbidden("PAYMENT_DISABLED", "payment is disabled")
+	ErrPaymentProductNotFound     = infraerrors.NotFound("PAYMENT_PRODUCT_NOT_FOUND", "payment product not found")
+	ErrPaymentOrderNotFound       = infraerrors.NotFound("PAYMENT_ORDER_NOT_FOUND", "payment order not found")
+	ErrPaymentProviderUnavailable = infraerrors.BadRequest("PAYMENT_PROVIDER_UNAVAILABLE", "payment provider is not available")
+	ErrPaymentMethodUnsupported   = infraerrors.BadRequest("PAYMENT_METHOD_UNSUPPORTED", "payment method is not supported by this provider")
+	ErrPaymentCurrencyUnsupported = infraerrors.BadRequest("PAYMENT_CURRENCY_UNSUPPORTED", "currency is not supported by this provider")
+	ErrPaymentTooManyPending      = infraerrors.TooManyRequests("PAYMENT_TOO_MANY_PENDING_ORDERS", "too many unpaid orders, please pay or cancel existing orders first")
+	ErrPaymentOrderNotPending     = infraerrors.Conflict("PAYMENT_ORDER_NOT_PENDING", "order is no longer awaiting payment")
+	ErrPaymentOrderNotRefundable  = infraerrors.Conflict("PAYMENT_ORDER_NOT_REFUNDABLE", "only paid orders can be refunded")
+	ErrPaymentOrderNotFulfillable = infraerrors.Conflict("PAYMENT_ORDER_NOT_FULFILLABLE", "only paid orders can be fulfilled")
+	ErrPaymentRefundAmount        = infraerrors.BadRequest("PAYMENT_REFUND_AMOUNT_INVALID", "refund amount must be positive and not exceed the paid amount")
+	ErrPaymentRefundReclaim       = infraerrors.BadRequest("PAYMENT_REFUND_RECLAIM_PARTIAL", "subscription benefits can only be reclaimed on a full refund")
+	ErrPaymentAmountMismatch      = infraerrors.BadRequest("PAYMENT_AMOUNT_MISMATCH", "paid amount does not match the order amount")
+	ErrPaymentRefundUnsupported   = infraerrors.BadRequest("PAYMENT_REFUND_UNSUPPORTED", "this provider does not support refunds")
+)
+
+// PaymentProduct 可购买的商品（余额充值包或订阅套餐）
+type PaymentProduct struct {
+	ID          int64
+	Name        string
+	Description string
+	Kind        string
+	PriceCents  int64  // 售价（最小货币单位）
+	Currency    string // ISO 4217 大写，如 CNY、USD
+	// BalanceAmount 余额充值包发放的余额（USD）
+	BalanceAmount money.Amount
+	// GroupID / ValidityDays 订阅套餐对应的分组与天数
+	GroupID      *int64
+	ValidityDays int
+	Enabled      bool
+	SortOrder    int
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+// PaymentOrder 支付订单。商品信息在下单时快照，后续修改商品不影响已有订单。
+type PaymentOrder struct {
+	ID            int64
+	OrderNo       string
+	UserID        int64
+	ProductID     *int64
+	ProductName   string
+	Kind          string
+	BalanceAmount money.Amount
+	GroupID       *int64
+	ValidityDays  int
+	// AmountCents 实付金额；使用优惠券时为原价减去优惠
+	AmountCents int64
+	// OriginalCents / DiscountCents 下单时的商品原价与优惠金额
+	OriginalCents   int64
+	DiscountCents   int64
+	UserCouponID    *int64
+	Currency        string
+	Provider        string
+	Method          string
+	ProviderTradeNo string
+	Status          string
+	RedeemCodeID    *int64
+	FulfillError    string
+	RefundedCents   int64
+	RefundReason    string
+	ClientIP        string
+	ExpiresAt       time.Time
+	PaidAt          *time.Time
+	FulfilledAt     *time.Time
+	RefundedAt      *time.Time
+	ClosedAt        *time.Time
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+	UserEmail       string
+}
+
+// PaymentOrderFilter 订单查询条件
+type PaymentOrderFilter struct {
+	UserID    int64
+	Status    string
+	Provider  string
+	Search    string // 订单号或渠道交易号（精确匹配）
+	StartTime *time.Time
+	EndTime   *time.Time
+}
+
+// PaymentReconciliationFilter 对账报表查询条件（按支付时间统计）
+type PaymentReconciliationFilter struct {
+	StartTime time.Time
+	EndTime   time.Time
+	Provider  string
+	Timezone  string // 按该时区划分自然日
+}
+
+// PaymentReconciliationRow 按日期、渠道与币种汇总的对账数据
+type PaymentReconciliationRow struct {
+	Date           string `json:"date"`
+	Provider       string `json:"provider"`
+	Currency       string `json:"currency"`
+	PaidOrders     int64  `json:"paid_orders"`
+	PaidCents      int64  `json:"paid_cents"`
+	FulfilledCount int64  `json:"fulfilled_count"`
+	PendingFulfill int64  `json:"pending_fulfill"` // 已支付但尚未发放
+	RefundedOrders int64  `json:"refunded_orders"`
+	RefundedCents  int64  `json:"refunded_cents"`
+	NetCents       int64  `json:"net_cents"`
+}
+
+// PaymentReconciliationReport 对账报表
+type PaymentReconciliationReport struct {
+	Rows []PaymentReconciliationRow
+	// Unfulfilled 当前已支付但尚未发放的订单，需人工关注
+	Unfulfilled []PaymentOrder
+}
+
+type PaymentProductRepository interface {
+	Create(ctx context.Context, product *PaymentProduct) error
+	Update(ctx context.Context, product *PaymentProduct) error
+	Delete(ctx context.Context, id int64) error
+	GetByID(ctx context.Context, id int64) (*PaymentProduct, error)
+	List(ctx context.Context, enabledOnly bool) ([]PaymentProduct, error)
+}
+
+type PaymentOrderRepository interface {
+	Create(ctx context.Context, order *PaymentOrder) error
+	GetByID(ctx context.Context, id int64) (*PaymentOrder, error)
+	GetByOrderNo(ctx context.Context, orderNo string) (*PaymentOrder, error)
+	List(ctx context.Context, params pagination.PaginationParams, filter PaymentOrderFilter) ([]PaymentOrder, *pagination.PaginationResult, error)
+	CountPendingByUser(ctx context.Context, userID int64) (int64, error)
+	SetProviderTradeNo(ctx context.Context, id int64, providerTradeNo string) error
+
+	// 以下状态迁移均为条件更新，返回 false 表示订单不处于可迁移状态（已被并发处理）
+	MarkPaid(ctx context.Context, id int64, providerTradeNo string, paidAt time.Time) (bool, error)
+	MarkFulfilled(ctx context.Context, id int64, redeemCodeID int64) (bool, error)
+	MarkClosed(ctx context.Context, id int64) (bool, error)
+	MarkRefunded(ctx context.Context, id int64, refundedCents int64, reason string) (bool, error)
+	SetFulfillError(ctx context.Context, id int64, message string) error
+
+	ListExpiredPending(ctx context.Context, now time.Time, limit int) ([]PaymentOrder, error)
+	ListUnfulfilledPaid(ctx context.Context, paidBefore time.Time, limit int) ([]PaymentOrder, error)
+	Reconcile(ctx context.Context, filter PaymentReconciliationFilter) ([]PaymentReconciliationRow, error)
+}
+
+// PaymentCreateRequest 向支付渠道发起支付
+type PaymentCreateRequest struct {
+	OrderNo     string
+	Subject     string
+	AmountCents int64
+	Currency    string
+	Method      string
+	ClientIP    string
+	NotifyURL   string
+	ReturnURL   string
+	ExpiresAt   time.Time
+}
+
+// PaymentCreateResult 渠道返回的支付方式：跳转链接或二维码内容，二者至少其一
+type PaymentCreateResult struct {
+	PayURL          string
+	QRCode          string
+	ProviderTradeNo string // 渠道侧在下单时即分配的交易号（如 Stripe Checkout Session ID）
+}
+
+// PaymentNotifyRequest 渠道异步通知的原始报文
+type PaymentNotifyRequest struct {
+	Method string
+	Query  url.Values
+	Header http.Header
+	Body   []byte
+}
+
+// PaymentNotification 验签后的支付结果（异步通知或主动查单）
+type PaymentNotification struct {
+	OrderNo         string
+	ProviderTradeNo string
+	Paid            bool  // false 表示未支付或与支付无关的事件，确认收到即可
+	AmountCents     int64 // 实付金额；0 表示渠道未提供
+	Currency        string
+}
+
+// PaymentRefundRequest 向渠道发起退款
+type PaymentRefundRequest struct {
+	OrderNo         string
+	ProviderTradeNo string
+	RefundNo        string
+	AmountCents     int64
+	TotalCents      int64
+	Currency        string
+	Reason          string
+}
+
+// PaymentNotifyAck 应答渠道异步通知的响应，各渠道格式不同
+type PaymentNotifyAck struct {
+	StatusCode  int
+	ContentType string
+	Body        []byte
+}
+
+// PaymentProvider 支付渠道。实现需在 ParseNotification 中完成验签，未通过验签的通知必须返回错误。
+type PaymentProvider interface {
+	Name() string
+	// Methods 渠道内可选的支付方式；为空表示无需选择
+	Methods() []string
+	SupportsCurrency(currency string) bool
+	CreatePayment(ctx context.Context, req *PaymentCreateRequest) (*PaymentCreateResult, error)
+	ParseNotification(ctx context.Context, req *PaymentNotifyRequest) (*PaymentNotification, error)
+	// QueryPayment 主动查询订单支付结果，用于补偿丢失的异步通知
+	QueryPayment(ctx context.Context, order *PaymentOrder) (*PaymentNotification, error)
+	Refund(ctx context.Context, req *PaymentRefundRequest) error
+	Ack(success bool) *PaymentNotifyAck
+}
+
+// PaymentProviders 已启用的支付渠道集合
+type PaymentProviders []PaymentProvider
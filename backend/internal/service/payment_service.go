@@ -0,0 +1,825 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/config"
+	infraerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/logger"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
+)
+
+const (
+	paymentNotifyPath = "/api/v1/payment/notify/"
+	paymentReturnPath = "/payment/result"
+
+	paymentMaintenanceInterval = time.Minute
+	paymentMaintenanceBatch    = 100
+	// paymentFulfillRetryDelay 已支付订单超过该时间仍未发放时由后台任务重试
+	paymentFulfillRetryDelay  = time.Minute
+	paymentFulfillErrorMaxLen = 500
+)
+
+// PaymentProviderInfo 对用户展示的支付渠道
+type PaymentProviderInfo struct {
+	Name    string
+	Methods []string
+}
+
+// CreatePaymentOrderInput 用户下单参数
+type CreatePaymentOrderInput struct {
+	ProductID int64
+	Provider  string
+	Method    string
+	// CouponID 使用的用户优惠券 ID，0 表示不使用
+	CouponID int64
+}
+
+// PaymentRefundInput 管理员退款参数
+type PaymentRefundInput struct {
+	// AmountCents 退款金额，0 表示全额退款
+	AmountCents int64
+	Reason      string
+	// ReclaimBenefits 同时扣回已发放的余额（按退款比例）或订阅天数（仅全额退款）
+	ReclaimBenefits bool
+	// Offline 已在渠道后台或线下完成退款，仅更新订单状态
+	Offline bool
+}
+
+// PaymentService 内置支付订单：商品、下单、回调验签、权益发放、退款与对账
+type PaymentService struct {
+	productRepo          PaymentProductRepository
+	orderRepo            PaymentOrderRepository
+	groupRepo            GroupRepository
+	userRepo             UserRepository
+	redeemService        *RedeemService
+	subscriptionService  *SubscriptionService
+	couponService        *CouponService
+	billingCacheService  *BillingCacheService
+	authCacheInvalidator APIKeyAuthCacheInvalidator
+
+	providers     map[string]PaymentProvider
+	providerOrder []string
+	cfg           config.PaymentConfig
+	frontendURL   string
+
+	startOnce sync.Once
+	stopOnce  sync.Once
+	stopCh    chan struct{}
+}
+
+// NewPaymentService 创建支付订单服务
+func NewPaymentService(
+	productRepo PaymentProductRepository,
+	orderRepo PaymentOrderRepository,
+	groupRepo GroupRepository,
+	userRepo UserRepository,
+	redeemService *RedeemService,
+	subscriptionService *SubscriptionService,
+	couponService *CouponService,
+	billingCacheService *BillingCacheService,
+	authCacheInvalidator APIKeyAuthCacheInvalidator,
+	providers PaymentProviders,
+	cfg *config.Config,
+) *PaymentService {
+	s := &PaymentService{
+		productRepo:          productRepo,
+		orderRepo:            orderRepo,
+		groupRepo:            groupRepo,
+		userRepo:             userRepo,
+		redeemService:        redeemService,
+		subscriptionService:  subscriptionService,
+		couponService:        couponService,
+		billingCacheService:  billingCacheService,
+		authCacheInvalidator: authCacheInvalidator,
+		providers:            make(map[string]PaymentProvider, len(providers)),
+		stopCh:               make(chan struct{}),
+	}
+	for _, p := range providers {
+		if p == nil {
+			continue
+		}
+		if _, exists := s.providers[p.Name()]; !exists {
+			s.providerOrder = append(s.providerOrder, p.Name())
+		}
+		s.providers[p.Name()] = p
+	}
+	if cfg != nil {
+		s.cfg = cfg.Payment
+		s.frontendURL = strings.TrimRight(strings.TrimSpace(cfg.Server.FrontendURL), "/")
+	}
+	return s
+}
+
+// Enabled 支付功能已开启且至少配置了一个渠道
+func (s *PaymentService) Enabled() bool {
+	return s != nil && s.cfg.Enabled && len(s.providers) > 0
+}
+
+// ListProviders 返回已启用的支付渠道
+func (s *PaymentService) ListProviders() []PaymentProviderInfo {
+	if !s.Enabled() {
+		return []PaymentProviderInfo{}
+	}
+	out := make([]PaymentProviderInfo, 0, len(s.providerOrder))
+	for _, name := range s.providerOrder {
+		out = append(out, PaymentProviderInfo{Name: name, Methods: s.providers[name].Methods()})
+	}
+	return out
+}
+
+// ---------- 商品 ----------
+
+// ListProducts 列出商品；enabledOnly 为 true 时仅返回上架商品
+func (s *PaymentService) ListProducts(ctx context.Context, enabledOnly bool) ([]PaymentProduct, error) {
+	return s.productRepo.List(ctx, enabledOnly)
+}
+
+// GetProduct 获取商品
+func (s *PaymentService) GetProduct(ctx context.Context, id int64) (*PaymentProduct, error) {
+	return s.productRepo.GetByID(ctx, id)
+}
+
+// CreateProduct 创建商品
+func (s *PaymentService) CreateProduct(ctx context.Context, product *PaymentProduct) error {
+	if err := s.normalizeProduct(ctx, product); err != nil {
+		return err
+	}
+	return s.productRepo.Create(ctx, product)
+}
+
+// UpdateProduct 更新商品；已创建的订单保留下单时的商品快照
+func (s *PaymentService) UpdateProduct(ctx context.Context, product *PaymentProduct) error {
+	if _, err := s.productRepo.GetByID(ctx, product.ID); err != nil {
+		return err
+	}
+	if err := s.normalizeProduct(ctx, product); err != nil {
+		return err
+	}
+	return s.productRepo.Update(ctx, product)
+}
+
+// DeleteProduct 删除商品
+func (s *PaymentService) DeleteProduct(ctx context.Context, id int64) error {
+	return s.productRepo.Delete(ctx, id)
+}
+
+func invalidPaymentProduct(message string) error {
+	return infraerrors.BadRequest("PAYMENT_PRODUCT_INVALID", message)
+}
+
+func (s *PaymentService) normalizeProduct(ctx context.Context, p *PaymentProduct) error {
+	p.Name = strings.TrimSpace(p.Name)
+	p.Description = strings.TrimSpace(p.Description)
+	p.Currency = strings.ToUpper(strings.TrimSpace(p.Currency))
+	if p.Currency == "" {
+		p.Currency = "CNY"
+	}
+	if p.Name == "" || len([]rune(p.Name)) > 100 {
+		return invalidPaymentProduct("name is required and must be at most 100 characters")
+	}
+	if len(p.Currency) != 3 {
+		return invalidPaymentProduct("currency must be a 3-letter ISO 4217 code")
+	}
+	if p.PriceCents <= 0 {
+		return invalidPaymentProduct("price must be greater than 0")
+	}
+
+	switch p.Kind {
+	case PaymentProductKindBalance:
+		if !p.BalanceAmount.IsPositive() {
+			return invalidPaymentProduct("balance_amount must be greater than 0")
+		}
+		p.GroupID = nil
+		p.ValidityDays = 0
+	case PaymentProductKindSubscription:
+		if p.GroupID == nil {
+			return invalidPaymentProduct("group_id is required for subscription products")
+		}
+		if p.ValidityDays <= 0 || p.ValidityDays > MaxValidityDays {
+			return invalidPaymentProduct(fmt.Sprintf("validity_days must be between 1 and %d", MaxValidityDays))
+		}
+		group, err := s.groupRepo.GetByID(ctx, *p.GroupID)
+		if err != nil {
+			return err
+		}
+		if !group.IsSubscriptionType() {
+			return invalidPaymentProduct("group must be subscription type")
+		}
+		p.BalanceAmount = 0
+	default:
+		return invalidPaymentProduct("kind must be balance or subscription")
+	}
+	return nil
+}
+
+// ---------- 用户下单 ----------
+
+// CreateOrder 创建订单并向支付渠道发起支付
+func (s *PaymentService) CreateOrder(ctx context.Context, userID int64, in *CreatePaymentOrderInput) (*PaymentOrder, *PaymentCreateResult, error) {
+	if !s.Enabled() {
+		return nil, nil, ErrPaymentDisabled
+	}
+	provider, ok := s.providers[in.Provider]
+	if !ok {
+		return nil, nil, ErrPaymentProviderUnavailable
+	}
+	method, err := resolvePaymentMethod(provider, in.Method)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	product, err := s.productRepo.GetByID(ctx, in.ProductID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !product.Enabled {
+		return nil, nil, ErrPaymentProductNotFound
+	}
+	if !provider.SupportsCurrency(product.Currency) {
+		return nil, nil, ErrPaymentCurrencyUnsupported
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !user.IsActive() {
+		return nil, nil, ErrUserNotActive
+	}
+	if s.cfg.MaxPendingOrders > 0 {
+		pending, err := s.orderRepo.CountPendingByUser(ctx, userID)
+		if err != nil {
+			return nil, nil, err
+		}
+		if pending >= int64(s.cfg.MaxPendingOrders) {
+			return nil, nil, ErrPaymentTooManyPending
+		}
+	}
+
+	orderNo, err := generatePaymentOrderNo(time.Now())
+	if err != nil {
+		return nil, nil, err
+	}
+	var quote *CouponQuote
+	if in.CouponID > 0 {
+		if s.couponService == nil {
+			return nil, nil, ErrUserCouponNotFound
+		}
+		if quote, err = s.couponService.ReserveForOrder(ctx, userID, in.CouponID, product, orderNo); err != nil {
+			return nil, nil, err
+		}
+	}
+	order := &PaymentOrder{
+		OrderNo:       orderNo,
+		UserID:        userID,
+		ProductID:     &product.ID,
+		ProductName:   product.Name,
+		Kind:          product.Kind,
+		BalanceAmount: product.BalanceAmount,
+		GroupID:       product.GroupID,
+		ValidityDays:  product.ValidityDays,
+		AmountCents:   product.PriceCents,
+		OriginalCents: product.PriceCents,
+		Currency:      product.Currency,
+		Provider:      provider.Name(),
+		Method:        method,
+		Status:        PaymentOrderStatusPending,
+		ClientIP:      ClientInfoFromContext(ctx).IP,
+		ExpiresAt:     time.Now().Add(time.Duration(s.cfg.OrderExpireMinutes) * time.Minute),
+	}
+	if quote != nil {
+		order.AmountCents = quote.AmountCents
+		order.DiscountCents = quote.DiscountCents
+		order.UserCouponID = &quote.UserCoupon.ID
+	}
+	if err := s.orderRepo.Create(ctx, order); err != nil {
+		if quote != nil {
+			s.releaseCoupon(ctx, order)
+		}
+		return nil, nil, err
+	}
+
+	result, err := provider.CreatePayment(ctx, &PaymentCreateRequest{
+		OrderNo:     order.OrderNo,
+		Subject:     order.ProductName,
+		AmountCents: order.AmountCents,
+		Currency:    order.Currency,
+		Method:      method,
+		ClientIP:    order.ClientIP,
+		NotifyURL:   s.notifyURL(provider.Name()),
+		ReturnURL:   s.returnURL(order.OrderNo),
+		ExpiresAt:   order.ExpiresAt,
+	})
+	if err != nil {
+		logger.LegacyPrintf("service.payment", "[Payment] create payment failed order_no=%s provider=%s err=%v", order.OrderNo, order.Provider, err)
+		if _, closeErr := s.closeOrder(ctx, order); closeErr != nil {
+			logger.LegacyPrintf("service.payment", "[Payment] close order failed order_no=%s err=%v", order.OrderNo, closeErr)
+		}
+		return nil, nil, infraerrors.ServiceUnavailable("PAYMENT_CREATE_FAILED", "failed to create payment, please try again later").WithCause(err)
+	}
+	if result.ProviderTradeNo != "" {
+		if err := s.orderRepo.SetProviderTradeNo(ctx, order.ID, result.ProviderTradeNo); err != nil {
+			return nil, nil, err
+		}
+		order.ProviderTradeNo = result.ProviderTradeNo
+	}
+	return order, result, nil
+}
+
+func resolvePaymentMethod(provider PaymentProvider, method string) (string, error) {
+	methods := provider.Methods()
+	if len(methods) == 0 {
+		return "", nil
+	}
+	method = strings.TrimSpace(method)
+	if method == "" {
+		return methods[0], nil
+	}
+	for _, m := range methods {
+		if m == method {
+			return method, nil
+		}
+	}
+	return "", ErrPaymentMethodUnsupported
+}
+
+// generatePaymentOrderNo 生成订单号：P + 时间戳 + 8 位随机数
+func generatePaymentOrderNo(now time.Time) (string, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(100_000_000))
+	if err != nil {
+		return "", fmt.Errorf("generate order no: %w", err)
+	}
+	return fmt.Sprintf("P%s%08d", now.UTC().Format("20060102150405"), n.Int64()), nil
+}
+
+func (s *PaymentService) notifyURL(provider string) string {
+	return strings.TrimRight(strings.TrimSpace(s.cfg.NotifyBaseURL), "/") + paymentNotifyPath + provider
+}
+
+func (s *PaymentService) returnURL(orderNo string) string {
+	base := strings.TrimSpace(s.cfg.ReturnURL)
+	if base == "" {
+		if s.frontendURL == "" {
+			return ""
+		}
+		base = s.frontendURL + paymentReturnPath
+	}
+	u, err := url.Parse(base)
+	if err != nil {
+		return base
+	}
+	q := u.Query()
+	q.Set("order_no", orderNo)
+	u.RawQuery = q.Encode()
+	return u.String()
+}
+
+// QuoteCoupon 下单前试算优惠券用于该商品后的实付金额
+func (s *PaymentService) QuoteCoupon(ctx context.Context, userID, userCouponID, productID int64) (*CouponQuote, error) {
+	if s.couponService == nil {
+		return nil, ErrUserCouponNotFound
+	}
+	product, err := s.productRepo.GetByID(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	if !product.Enabled {
+		return nil, ErrPaymentProductNotFound
+	}
+	return s.couponService.Quote(ctx, userID, userCouponID, product)
+}
+
+// GetUserOrder 获取用户自己的订单
+func (s *PaymentService) GetUserOrder(ctx context.Context, userID int64, orderNo string) (*PaymentOrder, error) {
+	order, err := s.orderRepo.GetByOrderNo(ctx, orderNo)
+	if err != nil {
+		return nil, err
+	}
+	if order.UserID != userID {
+		return nil, ErrPaymentOrderNotFound
+	}
+	return order, nil
+}
+
+// ListUserOrders 用户订单历史
+func (s *PaymentService) ListUserOrders(ctx context.Context, userID int64, params pagination.PaginationParams, status string) ([]PaymentOrder, *pagination.PaginationResult, error) {
+	return s.orderRepo.List(ctx, params, PaymentOrderFilter{UserID: userID, Status: status})
+}
+
+// CancelOrder 用户取消待支付订单
+func (s *PaymentService) CancelOrder(ctx context.Context, userID int64, orderNo string) (*PaymentOrder, error) {
+	order, err := s.GetUserOrder(ctx, userID, orderNo)
+	if err != nil {
+		return nil, err
+	}
+	closed, err := s.closeOrder(ctx, order)
+	if err != nil {
+		return nil, err
+	}
+	if !closed {
+		return nil, ErrPaymentOrderNotPending
+	}
+	return s.orderRepo.GetByID(ctx, order.ID)
+}
+
+// ---------- 回调与发放 ----------
+
+// HandleNotification 处理渠道异步通知。返回的应答需原样写回渠道；error 仅用于记录日志。
+func (s *PaymentService) HandleNotification(ctx context.Context, providerName string, req *PaymentNotifyRequest) (*PaymentNotifyAck, error) {
+	provider, ok := s.providers[providerName]
+	if !ok || !s.cfg.Enabled {
+		return nil, ErrPaymentProviderUnavailable
+	}
+	notification, err := provider.ParseNotification(ctx, req)
+	if err != nil {
+		return provider.Ack(false), err
+	}
+	if !notification.Paid {
+		return provider.Ack(true), nil
+	}
+	if err := s.confirmPaid(ctx, provider.Name(), notification); err != nil {
+		return provider.Ack(false), err
+	}
+	return provider.Ack(true), nil
+}
+
+// confirmPaid 将订单标记为已支付并发放权益。重复通知幂等；发放失败不影响应答，由后台任务重试。
+func (s *PaymentService) confirmPaid(ctx context.Context, providerName string, n *PaymentNotification) error {
+	order, err := s.orderRepo.GetByOrderNo(ctx, n.OrderNo)
+	if err != nil {
+		return err
+	}
+	if order.Provider != providerName {
+		return ErrPaymentOrderNotFound
+	}
+	if (n.AmountCents > 0 && n.AmountCents != order.AmountCents) ||
+		(n.Currency != "" && !strings.EqualFold(n.Currency, order.Currency)) {
+		logger.LegacyPrintf("service.payment", "[Payment] amount mismatch order_no=%s expected=%d %s got=%d %s",
+			order.OrderNo, order.AmountCents, order.Currency, n.AmountCents, n.Currency)
+		return ErrPaymentAmountMismatch
+	}
+
+	switch order.Status {
+	case PaymentOrderStatusFulfilled, PaymentOrderStatusRefunded:
+		return nil
+	case PaymentOrderStatusPending, PaymentOrderStatusClosed:
+		if order.Status == PaymentOrderStatusClosed {
+			logger.LegacyPrintf("service.payment", "[Payment] payment received for closed order order_no=%s, fulfilling anyway", order.OrderNo)
+		}
+		if _, err := s.orderRepo.MarkPaid(ctx, order.ID, n.ProviderTradeNo, time.Now()); err != nil {
+			return err
+		}
+		if order, err = s.orderRepo.GetByID(ctx, order.ID); err != nil {
+			return err
+		}
+	}
+
+	if order.Status == PaymentOrderStatusPaid {
+		if err := s.fulfill(ctx, order); err != nil {
+			logger.LegacyPrintf("service.payment", "[Payment] fulfill failed order_no=%s err=%v", order.OrderNo, err)
+		}
+	}
+	return nil
+}
+
+// fulfill 通过兑换码发放权益：兑换码为 PAY-{订单号}，其唯一性保证同一订单只发放一次。
+func (s *PaymentService) fulfill(ctx context.Context, order *PaymentOrder) error {
+	code := &RedeemCode{
+		Code:     PaymentRedeemCodePrefix + order.OrderNo,
+		Status:   StatusUnused,
+		Notes:    "payment order " + order.OrderNo,
+		Category: PaymentRedeemCategory,
+	}
+	switch order.Kind {
+	case PaymentProductKindBalance:
+		code.Type = RedeemTypeBalance
+		code.Value = order.BalanceAmount
+	case PaymentProductKindSubscription:
+		code.Type = RedeemTypeSubscription
+		code.Value = redeemCountValue(order.ValidityDays)
+		code.GroupID = order.GroupID
+		code.ValidityDays = order.ValidityDays
+	default:
+		return fmt.Errorf("unsupported payment product kind: %s", order.Kind)
+	}
+
+	redeemed, err := s.redeemService.FulfillCode(ctx, code, order.UserID)
+	if err != nil {
+		msg := err.Error()
+		if len(msg) > paymentFulfillErrorMaxLen {
+			msg = msg[:paymentFulfillErrorMaxLen]
+		}
+		if setErr := s.orderRepo.SetFulfillError(ctx, order.ID, msg); setErr != nil {
+			logger.LegacyPrintf("service.payment", "[Payment] record fulfill error failed order_no=%s err=%v", order.OrderNo, setErr)
+		}
+		return err
+	}
+	if _, err := s.orderRepo.MarkFulfilled(ctx, order.ID, redeemed.ID); err != nil {
+		return err
+	}
+	if order.UserCouponID != nil && s.couponService != nil {
+		// 订单关闭后券已退回并被其他订单使用时仍照常发放，仅记录日志
+		if err := s.couponService.ConsumeForOrder(ctx, *order.UserCouponID, order.OrderNo); err != nil {
+			logger.LegacyPrintf("service.payment", "[Payment] consume coupon failed order_no=%s user_coupon_id=%d err=%v", order.OrderNo, *order.UserCouponID, err)
+		}
+	}
+	return nil
+}
+
+// closeOrder 关闭待支付订单并退回其占用的优惠券
+func (s *PaymentService) closeOrder(ctx context.Context, order *PaymentOrder) (bool, error) {
+	closed, err := s.orderRepo.MarkClosed(ctx, order.ID)
+	if err != nil || !closed {
+		return closed, err
+	}
+	if order.UserCouponID != nil {
+		s.releaseCoupon(ctx, order)
+	}
+	return true, nil
+}
+
+func (s *PaymentService) releaseCoupon(ctx context.Context, order *PaymentOrder) {
+	if s.couponService == nil {
+		return
+	}
+	if err := s.couponService.ReleaseOrder(ctx, order.OrderNo); err != nil {
+		logger.LegacyPrintf("service.payment", "[Payment] release coupon failed order_no=%s err=%v", order.OrderNo, err)
+	}
+}
+
+// ---------- 管理端 ----------
+
+// ListOrders 管理端订单列表
+func (s *PaymentService) ListOrders(ctx context.Context, params pagination.PaginationParams, filter PaymentOrderFilter) ([]PaymentOrder, *pagination.PaginationResult, error) {
+	return s.orderRepo.List(ctx, params, filter)
+}
+
+// GetOrder 管理端订单详情
+func (s *PaymentService) GetOrder(ctx context.Context, id int64) (*PaymentOrder, error) {
+	return s.orderRepo.GetByID(ctx, id)
+}
+
+// CloseOrder 管理员关闭待支付订单
+func (s *PaymentService) CloseOrder(ctx context.Context, id int64) (*PaymentOrder, error) {
+	order, err := s.orderRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	closed, err := s.closeOrder(ctx, order)
+	if err != nil {
+		return nil, err
+	}
+	if !closed {
+		return nil, ErrPaymentOrderNotPending
+	}
+	return s.orderRepo.GetByID(ctx, id)
+}
+
+// RetryFulfill 重新发放已支付但发放失败的订单
+func (s *PaymentService) RetryFulfill(ctx context.Context, id int64) (*PaymentOrder, error) {
+	order, err := s.orderRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if order.Status != PaymentOrderStatusPaid {
+		return nil, ErrPaymentOrderNotFulfillable
+	}
+	if err := s.fulfill(ctx, order); err != nil {
+		return nil, err
+	}
+	return s.orderRepo.GetByID(ctx, id)
+}
+
+// SyncOrder 向渠道查询待支付订单的支付结果，用于处理丢失的异步通知
+func (s *PaymentService) SyncOrder(ctx context.Context, id int64) (*PaymentOrder, error) {
+	order, err := s.orderRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if order.Status != PaymentOrderStatusPending && order.Status != PaymentOrderStatusClosed {
+		return order, nil
+	}
+	provider, ok := s.providers[order.Provider]
+	if !ok {
+		return nil, ErrPaymentProviderUnavailable
+	}
+	n, err := provider.QueryPayment(ctx, order)
+	if err != nil {
+		return nil, infraerrors.ServiceUnavailable("PAYMENT_QUERY_FAILED", "failed to query payment status").WithCause(err)
+	}
+	if n.Paid {
+		if err := s.confirmPaid(ctx, provider.Name(), n); err != nil {
+			return nil, err
+		}
+	}
+	return s.orderRepo.GetByID(ctx, id)
+}
+
+// RefundOrder 退款。渠道退款单号固定为 R{订单号}，重复提交不会重复退款。
+func (s *PaymentService) RefundOrder(ctx context.Context, id int64, in *PaymentRefundInput, operatorID int64) (*PaymentOrder, error) {
+	order, err := s.orderRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if order.Status != PaymentOrderStatusPaid && order.Status != PaymentOrderStatusFulfilled {
+		return nil, ErrPaymentOrderNotRefundable
+	}
+	amount := in.AmountCents
+	if amount == 0 {
+		amount = order.AmountCents
+	}
+	if amount < 0 || amount > order.AmountCents {
+		return nil, ErrPaymentRefundAmount
+	}
+	if in.ReclaimBenefits && order.Kind == PaymentProductKindSubscription && amount != order.AmountCents {
+		return nil, ErrPaymentRefundReclaim
+	}
+	reason := strings.TrimSpace(in.Reason)
+
+	if !in.Offline {
+		provider, ok := s.providers[order.Provider]
+		if !ok {
+			return nil, ErrPaymentProviderUnavailable
+		}
+		if err := provider.Refund(ctx, &PaymentRefundRequest{
+			OrderNo:         order.OrderNo,
+			ProviderTradeNo: order.ProviderTradeNo,
+			RefundNo:        "R" + order.OrderNo,
+			AmountCents:     amount,
+			TotalCents:      order.AmountCents,
+			Currency:        order.Currency,
+			Reason:          reason,
+		}); err != nil {
+			if errors.Is(err, ErrPaymentRefundUnsupported) {
+				return nil, err
+			}
+			return nil, infraerrors.ServiceUnavailable("PAYMENT_REFUND_FAILED", "provider refund failed: "+err.Error()).WithCause(err)
+		}
+	}
+
+	refunded, err := s.orderRepo.MarkRefunded(ctx, order.ID, amount, reason)
+	if err != nil {
+		return nil, err
+	}
+	if !refunded {
+		return nil, ErrPaymentOrderNotRefundable
+	}
+	logger.LegacyPrintf("service.payment", "[Payment] order refunded order_no=%s amount=%d operator=%d offline=%v", order.OrderNo, amount, operatorID, in.Offline)
+
+	if in.ReclaimBenefits && order.Status == PaymentOrderStatusFulfilled {
+		if err := s.reclaimBenefits(ctx, order, amount, reason, operatorID); err != nil {
+			return nil, infraerrors.InternalServer("PAYMENT_RECLAIM_FAILED", "order refunded but reclaiming benefits failed: "+err.Error()).WithCause(err)
+		}
+	}
+	return s.orderRepo.GetByID(ctx, order.ID)
+}
+
+func (s *PaymentService) reclaimBenefits(ctx context.Context, order *PaymentOrder, refundCents int64, reason string, operatorID int64) error {
+	switch order.Kind {
+	case PaymentProductKindBalance:
+		amount := order.BalanceAmount
+		if refundCents != order.AmountCents {
+			amount = amount.Mul(float64(refundCents) / float64(order.AmountCents))
+		}
+		if err := s.userRepo.UpdateBalance(ctx, order.UserID, amount.Neg(), BalanceLedgerSource{
+			Type:        BalanceLedgerSourcePaymentRefund,
+			ReferenceID: order.OrderNo,
+			OperatorID:  &operatorID,
+			Notes:       reason,
+		}); err != nil {
+			return err
+		}
+		s.invalidateUserBalance(ctx, order.UserID)
+		return nil
+	case PaymentProductKindSubscription:
+		if order.GroupID == nil {
+			return nil
+		}
+		sub, err := s.subscriptionService.GetActiveSubscription(ctx, order.UserID, *order.GroupID)
+		if err != nil {
+			if errors.Is(err, ErrSubscriptionNotFound) {
+				return nil
+			}
+			return err
+		}
+		_, err = s.subscriptionService.ExtendSubscription(ctx, sub.ID, -order.ValidityDays)
+		if errors.Is(err, ErrAdjustWouldExpire) {
+			return s.subscriptionService.RevokeSubscription(ctx, sub.ID)
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *PaymentService) invalidateUserBalance(ctx context.Context, userID int64) {
+	if s.authCacheInvalidator != nil {
+		s.authCacheInvalidator.InvalidateAuthCacheByUserID(ctx, userID)
+	}
+	if s.billingCacheService == nil {
+		return
+	}
+	go func() {
+		cacheCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := s.billingCacheService.InvalidateUserBalance(cacheCtx, userID); err != nil {
+			logger.LegacyPrintf("service.payment", "invalidate user balance cache failed: user_id=%d err=%v", userID, err)
+		}
+	}()
+}
+
+// Reconciliation 对账报表：按支付日期、渠道与币种汇总收款与退款，并列出已支付未发放的订单
+func (s *PaymentService) Reconciliation(ctx context.Context, filter PaymentReconciliationFilter) (*PaymentReconciliationReport, error) {
+	rows, err := s.orderRepo.Reconcile(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	unfulfilled, err := s.orderRepo.ListUnfulfilledPaid(ctx, time.Now(), paymentMaintenanceBatch)
+	if err != nil {
+		return nil, err
+	}
+	return &PaymentReconciliationReport{Rows: rows, Unfulfilled: unfulfilled}, nil
+}
+
+// ---------- 后台任务 ----------
+
+// Start 启动后台任务：关闭超时订单（关闭前先查单补偿丢失的通知），重试发放失败的订单
+func (s *PaymentService) Start() {
+	if !s.Enabled() {
+		return
+	}
+	s.startOnce.Do(func() {
+		go s.runLoop()
+	})
+}
+
+func (s *PaymentService) Stop() {
+	if s == nil {
+		return
+	}
+	s.stopOnce.Do(func() {
+		close(s.stopCh)
+	})
+}
+
+func (s *PaymentService) runLoop() {
+	ticker := time.NewTicker(paymentMaintenanceInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			s.runMaintenance(context.Background())
+		case <-s.stopCh:
+			return
+		}
+	}
+}
+
+func (s *PaymentService) runMaintenance(ctx context.Context) {
+	now := time.Now()
+	expired, err := s.orderRepo.ListExpiredPending(ctx, now, paymentMaintenanceBatch)
+	if err != nil {
+		logger.LegacyPrintf("service.payment", "[Payment] list expired orders failed err=%v", err)
+	}
+	for i := range expired {
+		s.expireOrder(ctx, &expired[i])
+	}
+
+	unfulfilled, err := s.orderRepo.ListUnfulfilledPaid(ctx, now.Add(-paymentFulfillRetryDelay), paymentMaintenanceBatch)
+	if err != nil {
+		logger.LegacyPrintf("service.payment", "[Payment] list unfulfilled orders failed err=%v", err)
+	}
+	for i := range unfulfilled {
+		if err := s.fulfill(ctx, &unfulfilled[i]); err != nil {
+			logger.LegacyPrintf("service.payment", "[Payment] retry fulfill failed order_no=%s err=%v", unfulfilled[i].OrderNo, err)
+		}
+	}
+}
+
+func (s *PaymentService) expireOrder(ctx context.Context, order *PaymentOrder) {
+	if provider, ok := s.providers[order.Provider]; ok {
+		queryCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+		n, err := provider.QueryPayment(queryCtx, order)
+		cancel()
+		if err != nil {
+			// 查单失败仍关闭订单：之后收到支付成功通知时会照常发放
+			logger.LegacyPrintf("service.payment", "[Payment] query payment failed order_no=%s err=%v", order.OrderNo, err)
+		} else if n.Paid {
+			if err := s.confirmPaid(ctx, provider.Name(), n); err != nil {
+				logger.LegacyPrintf("service.payment", "[Payment] confirm paid failed order_no=%s err=%v", order.OrderNo, err)
+			}
+			return
+		}
+	}
+	if _, err := s.closeOrder(ctx, order); err != nil {
+		logger.LegacyPrintf("service.payment", "[Payment] close expired order failed order_no=%s err=%v", order.OrderNo, err)
+	}
+}
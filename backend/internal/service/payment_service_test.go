@@ -0,0 +1,511 @@
+//go:build unit
+
+package service
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/config"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
+	"github.com/stretchr/testify/require"
+)
+
+// ---------- fakes ----------
+
+type paymentTestProvider struct {
+	name       string
+	currencies []string
+
+	notification *PaymentNotification
+	notifyErr    error
+	queryResult  *PaymentNotification
+	refunds      []PaymentRefundRequest
+	created      []PaymentCreateRequest
+}
+
+func (p *paymentTestProvider) Name() string      { return p.name }
+func (p *paymentTestProvider) Methods() []string { return []string{"qr", "page"} }
+func (p *paymentTestProvider) SupportsCurrency(currency string) bool {
+	for _, c := range p.currencies {
+		if c == currency {
+			return true
+		}
+	}
+	return false
+}
+func (p *paymentTestProvider) CreatePayment(_ context.Context, req *PaymentCreateRequest) (*PaymentCreateResult, error) {
+	p.created = append(p.created, *req)
+	return &PaymentCreateResult{PayURL: "https://pay.example.com/" + req.OrderNo}, nil
+}
+func (p *paymentTestProvider) ParseNotification(_ context.Context, _ *PaymentNotifyRequest) (*PaymentNotification, error) {
+	if p.notifyErr != nil {
+		return nil, p.notifyErr
+	}
+	n := *p.notification
+	return &n, nil
+}
+func (p *paymentTestProvider) QueryPayment(_ context.Context, order *PaymentOrder) (*PaymentNotification, error) {
+	if p.queryResult == nil || p.queryResult.OrderNo != order.OrderNo {
+		return &PaymentNotification{OrderNo: order.OrderNo}, nil
+	}
+	n := *p.queryResult
+	return &n, nil
+}
+func (p *paymentTestProvider) Refund(_ context.Context, req *PaymentRefundRequest) error {
+	p.refunds = append(p.refunds, *req)
+	return nil
+}
+func (p *paymentTestProvider) Ack(success bool) *PaymentNotifyAck {
+	if success {
+		return &PaymentNotifyAck{StatusCode: 200, Body: []byte("success")}
+	}
+	return &PaymentNotifyAck{StatusCode: 200, Body: []byte("fail")}
+}
+
+type paymentTestProductRepo struct {
+	PaymentProductRepository
+	products map[int64]*PaymentProduct
+}
+
+func (r *paymentTestProductRepo) GetByID(_ context.Context, id int64) (*PaymentProduct, error) {
+	p, ok := r.products[id]
+	if !ok {
+		return nil, ErrPaymentProductNotFound
+	}
+	cp := *p
+	return &cp, nil
+}
+
+type paymentTestOrderRepo struct {
+	PaymentOrderRepository
+	nextID int64
+	orders map[int64]*PaymentOrder
+}
+
+func newPaymentTestOrderRepo() *paymentTestOrderRepo {
+	return &paymentTestOrderRepo{nextID: 1, orders: make(map[int64]*PaymentOrder)}
+}
+
+func (r *paymentTestOrderRepo) Create(_ context.Context, order *PaymentOrder) error {
+	order.ID = r.nextID
+	r.nextID++
+	order.CreatedAt = time.Now()
+	cp := *order
+	r.orders[order.ID] = &cp
+	return nil
+}
+
+func (r *paymentTestOrderRepo) GetByID(_ context.Context, id int64) (*PaymentOrder, error) {
+	o, ok := r.orders[id]
+	if !ok {
+		return nil, ErrPaymentOrderNotFound
+	}
+	cp := *o
+	return &cp, nil
+}
+
+func (r *paymentTestOrderRepo) GetByOrderNo(ctx context.Context, orderNo string) (*PaymentOrder, error) {
+	for id, o := range r.orders {
+		if o.OrderNo == orderNo {
+			return r.GetByID(ctx, id)
+		}
+	}
+	return nil, ErrPaymentOrderNotFound
+}
+
+func (r *paymentTestOrderRepo) CountPendingByUser(_ context.Context, userID int64) (int64, error) {
+	var n int64
+	for _, o := range r.orders {
+		if o.UserID == userID && o.Status == PaymentOrderStatusPending {
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (r *paymentTestOrderRepo) SetProviderTradeNo(_ context.Context, id int64, tradeNo string) error {
+	r.orders[id].ProviderTradeNo = tradeNo
+	return nil
+}
+
+func (r *paymentTestOrderRepo) transition(id int64, from []string, apply func(o *PaymentOrder)) bool {
+	o, ok := r.orders[id]
+	if !ok {
+		return false
+	}
+	for _, s := range from {
+		if o.Status == s {
+			apply(o)
+			return true
+		}
+	}
+	return false
+}
+
+func (r *paymentTestOrderRepo) MarkPaid(_ context.Context, id int64, tradeNo string, paidAt time.Time) (bool, error) {
+	return r.transition(id, []string{PaymentOrderStatusPending, PaymentOrderStatusClosed}, func(o *PaymentOrder) {
+		o.Status = PaymentOrderStatusPaid
+		o.ProviderTradeNo = tradeNo
+		o.PaidAt = &paidAt
+	}), nil
+}
+
+func (r *paymentTestOrderRepo) MarkFulfilled(_ context.Context, id int64, redeemCodeID int64) (bool, error) {
+	return r.transition(id, []string{PaymentOrderStatusPaid}, func(o *PaymentOrder) {
+		now := time.Now()
+		o.Status = PaymentOrderStatusFulfilled
+		o.RedeemCodeID = &redeemCodeID
+		o.FulfilledAt = &now
+		o.FulfillError = ""
+	}), nil
+}
+
+func (r *paymentTestOrderRepo) MarkClosed(_ context.Context, id int64) (bool, error) {
+	return r.transition(id, []string{PaymentOrderStatusPending}, func(o *PaymentOrder) {
+		now := time.Now()
+		o.Status = PaymentOrderStatusClosed
+		o.ClosedAt = &now
+	}), nil
+}
+
+func (r *paymentTestOrderRepo) MarkRefunded(_ context.Context, id int64, cents int64, reason string) (bool, error) {
+	return r.transition(id, []string{PaymentOrderStatusPaid, PaymentOrderStatusFulfilled}, func(o *PaymentOrder) {
+		now := time.Now()
+		o.Status = PaymentOrderStatusRefunded
+		o.RefundedCents = cents
+		o.RefundReason = reason
+		o.RefundedAt = &now
+	}), nil
+}
+
+func (r *paymentTestOrderRepo) SetFulfillError(_ context.Context, id int64, message string) error {
+	r.orders[id].FulfillError = message
+	return nil
+}
+
+func (r *paymentTestOrderRepo) ListExpiredPending(_ context.Context, now time.Time, _ int) ([]PaymentOrder, error) {
+	out := make([]PaymentOrder, 0)
+	for _, o := range r.orders {
+		if o.Status == PaymentOrderStatusPending && o.ExpiresAt.Before(now) {
+			out = append(out, *o)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out, nil
+}
+
+func (r *paymentTestOrderRepo) ListUnfulfilledPaid(_ context.Context, paidBefore time.Time, _ int) ([]PaymentOrder, error) {
+	out := make([]PaymentOrder, 0)
+	for _, o := range r.orders {
+		if o.Status == PaymentOrderStatusPaid && o.PaidAt.Before(paidBefore) {
+			out = append(out, *o)
+		}
+	}
+	return out, nil
+}
+
+func (r *paymentTestOrderRepo) List(_ context.Context, params pagination.PaginationParams, _ PaymentOrderFilter) ([]PaymentOrder, *pagination.PaginationResult, error) {
+	return nil, &pagination.PaginationResult{Page: params.Page, PageSize: params.PageSize}, nil
+}
+
+// paymentTestRedeemRepo 在内存兑换码仓储基础上实现 Use 的状态变更，以验证重复发放的幂等性
+type paymentTestRedeemRepo struct {
+	*checkinTestRedeemRepo
+}
+
+func (r *paymentTestRedeemRepo) Use(_ context.Context, id, userID int64) error {
+	for _, item := range r.byCode {
+		if item.ID != id {
+			continue
+		}
+		if item.Status != StatusUnused {
+			return ErrRedeemCodeUsed
+		}
+		now := time.Now()
+		item.Status = StatusUsed
+		item.UsedBy = &userID
+		item.UsedAt = &now
+		return nil
+	}
+	return ErrRedeemCodeNotFound
+}
+
+type paymentTestEnv struct {
+	svc      *PaymentService
+	provider *paymentTestProvider
+	orders   *paymentTestOrderRepo
+	users    *checkinTestUserRepo
+	redeem   *paymentTestRedeemRepo
+	coupons  *couponTestRepo
+}
+
+const paymentTestUserID = int64(7)
+
+func newPaymentTestEnv(t *testing.T) *paymentTestEnv {
+	t.Helper()
+
+	provider := &paymentTestProvider{name: PaymentProviderEPay, currencies: []string{"CNY"}}
+	users := newCheckinTestUserRepo(map[int64]*User{
+		paymentTestUserID: {ID: paymentTestUserID, Role: RoleUser, Status: StatusActive, Balance: 1 * money.USD},
+	})
+	redeemRepo := &paymentTestRedeemRepo{checkinTestRedeemRepo: newCheckinTestRedeemRepo()}
+	redeemService := NewRedeemService(redeemRepo, users, nil, nil, nil, nil, newPromoStatsTestEntClient(t), nil)
+	products := &paymentTestProductRepo{products: map[int64]*PaymentProduct{
+		1: {ID: 1, Name: "Top-up 10", Kind: PaymentProductKindBalance, PriceCents: 7000, Currency: "CNY", BalanceAmount: 10 * money.USD, Enabled: true},
+		2: {ID: 2, Name: "USD pack", Kind: PaymentProductKindBalance, PriceCents: 1000, Currency: "USD", BalanceAmount: 10 * money.USD, Enabled: true},
+	}}
+	orders := newPaymentTestOrderRepo()
+
+	cfg := &config.Config{Payment: config.PaymentConfig{
+		Enabled:            true,
+		OrderExpireMinutes: 30,
+		MaxPendingOrders:   2,
+		NotifyBaseURL:      "https://api.example.com/",
+		ReturnURL:          "https://app.example.com/payment/result",
+	}}
+	coupons := newCouponTestRepo()
+	svc := NewPaymentService(products, orders, nil, users, redeemService, nil, NewCouponService(coupons, users, nil), nil, nil, PaymentProviders{provider}, cfg)
+	return &paymentTestEnv{svc: svc, provider: provider, orders: orders, users: users, redeem: redeemRepo, coupons: coupons}
+}
+
+func (e *paymentTestEnv) createOrder(t *testing.T) *PaymentOrder {
+	t.Helper()
+	order, result, err := e.svc.CreateOrder(context.Background(), paymentTestUserID, &CreatePaymentOrderInput{ProductID: 1, Provider: PaymentProviderEPay})
+	require.NoError(t, err)
+	require.NotEmpty(t, result.PayURL)
+	return order
+}
+
+func (e *paymentTestEnv) notifyPaid(t *testing.T, order *PaymentOrder, amountCents int64) (*PaymentNotifyAck, error) {
+	t.Helper()
+	e.provider.notification = &PaymentNotification{
+		OrderNo:         order.OrderNo,
+		ProviderTradeNo: "T" + order.OrderNo,
+		Paid:            true,
+		AmountCents:     amountCents,
+		Currency:        "CNY",
+	}
+	return e.svc.HandleNotification(context.Background(), PaymentProviderEPay, &PaymentNotifyRequest{})
+}
+
+func (e *paymentTestEnv) balance() money.Amount {
+	return e.users.users[paymentTestUserID].Balance
+}
+
+// ---------- tests ----------
+
+func TestPaymentCreateOrder(t *testing.T) {
+	env := newPaymentTestEnv(t)
+
+	order := env.createOrder(t)
+	require.Equal(t, PaymentOrderStatusPending, order.Status)
+	require.Equal(t, "qr", order.Method, "first method is the default")
+	require.Equal(t, int64(7000), order.AmountCents)
+	require.Len(t, env.provider.created, 1)
+	require.Equal(t, "https://api.example.com/api/v1/payment/notify/epay", env.provider.created[0].NotifyURL)
+	require.Equal(t, "https://app.example.com/payment/result?order_no="+order.OrderNo, env.provider.created[0].ReturnURL)
+
+	_, _, err := env.svc.CreateOrder(context.Background(), paymentTestUserID, &CreatePaymentOrderInput{ProductID: 2, Provider: PaymentProviderEPay})
+	require.ErrorIs(t, err, ErrPaymentCurrencyUnsupported)
+
+	_, _, err = env.svc.CreateOrder(context.Background(), paymentTestUserID, &CreatePaymentOrderInput{ProductID: 1, Provider: PaymentProviderEPay, Method: "bank"})
+	require.ErrorIs(t, err, ErrPaymentMethodUnsupported)
+
+	_, _, err = env.svc.CreateOrder(context.Background(), paymentTestUserID, &CreatePaymentOrderInput{ProductID: 1, Provider: PaymentProviderStripe})
+	require.ErrorIs(t, err, ErrPaymentProviderUnavailable)
+
+	env.createOrder(t)
+	_, _, err = env.svc.CreateOrder(context.Background(), paymentTestUserID, &CreatePaymentOrderInput{ProductID: 1, Provider: PaymentProviderEPay})
+	require.ErrorIs(t, err, ErrPaymentTooManyPending)
+}
+
+func TestPaymentNotificationFulfillsOnce(t *testing.T) {
+	env := newPaymentTestEnv(t)
+	order := env.createOrder(t)
+
+	for i := 0; i < 3; i++ {
+		ack, err := env.notifyPaid(t, order, 7000)
+		require.NoError(t, err)
+		require.Equal(t, "success", string(ack.Body))
+	}
+
+	got, err := env.orders.GetByID(context.Background(), order.ID)
+	require.NoError(t, err)
+	require.Equal(t, PaymentOrderStatusFulfilled, got.Status)
+	require.Equal(t, "T"+order.OrderNo, got.ProviderTradeNo)
+	require.NotNil(t, got.RedeemCodeID)
+	require.Equal(t, 11*money.USD, env.balance(), "balance is credited exactly once")
+
+	code, err := env.redeem.GetByCode(context.Background(), "PAY-"+order.OrderNo)
+	require.NoError(t, err)
+	require.Equal(t, StatusUsed, code.Status)
+	require.Equal(t, PaymentRedeemCategory, code.Category)
+
+	// 发放失败后由重试补发，兑换码已使用时不会重复入账
+	env.orders.orders[order.ID].Status = PaymentOrderStatusPaid
+	_, err = env.svc.RetryFulfill(context.Background(), order.ID)
+	require.NoError(t, err)
+	require.Equal(t, 11*money.USD, env.balance())
+}
+
+func TestPaymentFulfillCreditsExactBalanceAmount(t *testing.T) {
+	env := newPaymentTestEnv(t)
+	order := env.createOrder(t)
+	amount := money.MustParse("12.3456789012")
+	env.orders.orders[order.ID].BalanceAmount = amount
+
+	_, err := env.notifyPaid(t, order, 7000)
+	require.NoError(t, err)
+
+	code, err := env.redeem.GetByCode(context.Background(), "PAY-"+order.OrderNo)
+	require.NoError(t, err)
+	require.Equal(t, amount, code.Value)
+	require.Equal(t, 1*money.USD+amount, env.balance(), "no float round-trip between order and balance")
+}
+
+func TestPaymentNotificationRejected(t *testing.T) {
+	env := newPaymentTestEnv(t)
+	order := env.createOrder(t)
+
+	ack, err := env.notifyPaid(t, order, 1)
+	require.ErrorIs(t, err, ErrPaymentAmountMismatch)
+	require.Equal(t, "fail", string(ack.Body))
+
+	env.provider.notifyErr = errors.New("invalid signature")
+	ack, err = env.svc.HandleNotification(context.Background(), PaymentProviderEPay, &PaymentNotifyRequest{})
+	require.Error(t, err)
+	require.Equal(t, "fail", string(ack.Body))
+
+	got, err := env.orders.GetByID(context.Background(), order.ID)
+	require.NoError(t, err)
+	require.Equal(t, PaymentOrderStatusPending, got.Status)
+	require.Equal(t, 1*money.USD, env.balance())
+
+	_, err = env.svc.HandleNotification(context.Background(), PaymentProviderStripe, &PaymentNotifyRequest{})
+	require.ErrorIs(t, err, ErrPaymentProviderUnavailable)
+}
+
+func TestPaymentLatePaymentOnClosedOrderIsFulfilled(t *testing.T) {
+	env := newPaymentTestEnv(t)
+	order := env.createOrder(t)
+
+	_, err := env.svc.CancelOrder(context.Background(), paymentTestUserID, order.OrderNo)
+	require.NoError(t, err)
+	_, err = env.svc.CancelOrder(context.Background(), paymentTestUserID, order.OrderNo)
+	require.ErrorIs(t, err, ErrPaymentOrderNotPending)
+
+	_, err = env.notifyPaid(t, order, 7000)
+	require.NoError(t, err)
+	got, err := env.orders.GetByID(context.Background(), order.ID)
+	require.NoError(t, err)
+	require.Equal(t, PaymentOrderStatusFulfilled, got.Status)
+	require.Equal(t, 11*money.USD, env.balance())
+}
+
+func TestPaymentMaintenanceExpiresOrders(t *testing.T) {
+	env := newPaymentTestEnv(t)
+	paid := env.createOrder(t)
+	unpaid := env.createOrder(t)
+	for _, o := range env.orders.orders {
+		o.ExpiresAt = time.Now().Add(-time.Minute)
+	}
+
+	// 通知丢失：查单发现已支付的订单照常发放，其余关闭
+	env.provider.queryResult = &PaymentNotification{OrderNo: paid.OrderNo, ProviderTradeNo: "T1", Paid: true, AmountCents: 7000, Currency: "CNY"}
+	env.svc.runMaintenance(context.Background())
+
+	require.Equal(t, PaymentOrderStatusFulfilled, env.orders.orders[paid.ID].Status)
+	require.Equal(t, PaymentOrderStatusClosed, env.orders.orders[unpaid.ID].Status)
+	require.Equal(t, 11*money.USD, env.balance())
+}
+
+func TestPaymentRefundOrder(t *testing.T) {
+	env := newPaymentTestEnv(t)
+	order := env.createOrder(t)
+
+	_, err := env.svc.RefundOrder(context.Background(), order.ID, &PaymentRefundInput{}, 1)
+	require.ErrorIs(t, err, ErrPaymentOrderNotRefundable)
+
+	_, err = env.notifyPaid(t, order, 7000)
+	require.NoError(t, err)
+
+	_, err = env.svc.RefundOrder(context.Background(), order.ID, &PaymentRefundInput{AmountCents: 7001}, 1)
+	require.ErrorIs(t, err, ErrPaymentRefundAmount)
+
+	got, err := env.svc.RefundOrder(context.Background(), order.ID, &PaymentRefundInput{AmountCents: 3500, Reason: " duplicate ", ReclaimBenefits: true}, 1)
+	require.NoError(t, err)
+	require.Equal(t, PaymentOrderStatusRefunded, got.Status)
+	require.Equal(t, int64(3500), got.RefundedCents)
+	require.Equal(t, "duplicate", got.RefundReason)
+	require.Len(t, env.provider.refunds, 1)
+	require.Equal(t, "R"+order.OrderNo, env.provider.refunds[0].RefundNo)
+	require.Equal(t, int64(7000), env.provider.refunds[0].TotalCents)
+	// 按退款比例扣回一半余额
+	require.Equal(t, 6*money.USD, env.balance())
+
+	_, err = env.svc.RefundOrder(context.Background(), order.ID, &PaymentRefundInput{}, 1)
+	require.ErrorIs(t, err, ErrPaymentOrderNotRefundable)
+	require.Len(t, env.provider.refunds, 1)
+}
+
+func (e *paymentTestEnv) issueCoupon(t *testing.T, coupon *Coupon) *UserCoupon {
+	t.Helper()
+	ctx := context.Background()
+	coupon.Enabled = true
+	require.NoError(t, e.coupons.Create(ctx, coupon))
+	uc := &UserCoupon{CouponID: coupon.ID, UserID: paymentTestUserID, Status: UserCouponStatusUnused, Source: CouponSourceAdmin}
+	require.NoError(t, e.coupons.Issue(ctx, uc))
+	return uc
+}
+
+func TestPaymentCouponDiscountAndConsume(t *testing.T) {
+	env := newPaymentTestEnv(t)
+	uc := env.issueCoupon(t, &Coupon{Name: "10 off", DiscountType: CouponDiscountFixed, AmountOffCents: 1000, Currency: "CNY", Scope: CouponScopeBalance})
+
+	order, _, err := env.svc.CreateOrder(context.Background(), paymentTestUserID, &CreatePaymentOrderInput{ProductID: 1, Provider: PaymentProviderEPay, CouponID: uc.ID})
+	require.NoError(t, err)
+	require.Equal(t, int64(6000), order.AmountCents)
+	require.Equal(t, int64(7000), order.OriginalCents)
+	require.Equal(t, int64(1000), order.DiscountCents)
+	require.Equal(t, int64(6000), env.provider.created[0].AmountCents, "provider charges the discounted amount")
+	require.Equal(t, UserCouponStatusLocked, env.coupons.issued[uc.ID].Status)
+
+	_, _, err = env.svc.CreateOrder(context.Background(), paymentTestUserID, &CreatePaymentOrderInput{ProductID: 1, Provider: PaymentProviderEPay, CouponID: uc.ID})
+	require.ErrorIs(t, err, ErrUserCouponUnavailable)
+
+	_, err = env.notifyPaid(t, order, 7000)
+	require.ErrorIs(t, err, ErrPaymentAmountMismatch)
+	_, err = env.notifyPaid(t, order, 6000)
+	require.NoError(t, err)
+	require.Equal(t, 11*money.USD, env.balance())
+	require.Equal(t, UserCouponStatusUsed, env.coupons.issued[uc.ID].Status)
+	require.Equal(t, order.OrderNo, env.coupons.issued[uc.ID].OrderNo)
+}
+
+func TestPaymentCouponReleasedOnCancel(t *testing.T) {
+	env := newPaymentTestEnv(t)
+	uc := env.issueCoupon(t, &Coupon{Name: "20%", DiscountType: CouponDiscountPercent, PercentOff: 20, Scope: CouponScopeAll})
+
+	order, _, err := env.svc.CreateOrder(context.Background(), paymentTestUserID, &CreatePaymentOrderInput{ProductID: 1, Provider: PaymentProviderEPay, CouponID: uc.ID})
+	require.NoError(t, err)
+	require.Equal(t, int64(5600), order.AmountCents)
+
+	_, err = env.svc.CancelOrder(context.Background(), paymentTestUserID, order.OrderNo)
+	require.NoError(t, err)
+	require.Equal(t, UserCouponStatusUnused, env.coupons.issued[uc.ID].Status)
+
+	// 关闭后仍收到支付：照常发放并核销已退回的券
+	_, err = env.notifyPaid(t, order, 5600)
+	require.NoError(t, err)
+	require.Equal(t, UserCouponStatusUsed, env.coupons.issued[uc.ID].Status)
+
+	// 不适用的券不能下单
+	other := env.issueCoupon(t, &Coupon{Name: "sub only", DiscountType: CouponDiscountPercent, PercentOff: 20, Scope: CouponScopeSubscription})
+	_, _, err = env.svc.CreateOrder(context.Background(), paymentTestUserID, &CreatePaymentOrderInput{ProductID: 1, Provider: PaymentProviderEPay, CouponID: other.ID})
+	require.ErrorIs(t, err, ErrCouponNotApplicable)
+}
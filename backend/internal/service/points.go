@@ -0,0 +1,129 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	infraerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
+)
+
+// 积分流水来源
+const (
+	PointsSourceActivityReward = "activity_reward" // 活动奖励，reference_id 为活动 ID
+	PointsSourceShopExchange   = "shop_exchange"   // 积分商城兑换，reference_id 为兑换记录 ID
+	PointsSourceAdminAdjust    = "admin_adjust"    // 管理员调整，operator_id 为管理员
+)
+
+// 积分商城商品类型
+const (
+	PointsShopKindBalance      = "balance"      // 兑换余额，直接到账
+	PointsShopKindSubscription = "subscription" // 兑换订阅，直接分配或续期
+	PointsShopKindRedeemCode   = "redeem_code"  // 兑换一个未使用的兑换码，可自用或转赠
+)
+
+// pointsRedeemCodeCategory 积分商城生成的兑换码分类
+const pointsRedeemCodeCategory = "points_shop"
+
+var (
+	ErrPointsInsufficient      = infraerrors.BadRequest("POINTS_INSUFFICIENT", "insufficient points")
+	ErrPointsInvalidAmount     = infraerrors.BadRequest("POINTS_INVALID_AMOUNT", "points amount must not be zero")
+	ErrPointsShopItemNotFound  = infraerrors.NotFound("POINTS_SHOP_ITEM_NOT_FOUND", "points shop item not found")
+	ErrPointsShopOutOfStock    = infraerrors.Conflict("POINTS_SHOP_OUT_OF_STOCK", "item is out of stock")
+	ErrPointsShopLimitExceeded = infraerrors.Conflict("POINTS_SHOP_LIMIT_EXCEEDED", "exchange limit for this item has been reached")
+)
+
+// PointsAccount 用户积分账户
+type PointsAccount struct {
+	UserID      int64
+	Balance     int64
+	TotalEarned int64
+	TotalSpent  int64
+	UpdatedAt   time.Time
+}
+
+// PointsLedgerSource 描述一次积分变动的来源
+type PointsLedgerSource struct {
+	Type        string
+	ReferenceID string
+	OperatorID  *int64
+	Notes       string
+}
+
+// PointsLedgerEntry 积分流水
+type PointsLedgerEntry struct {
+	ID           int64
+	UserID       int64
+	Delta        int64
+	BalanceAfter int64
+	SourceType   string
+	ReferenceID  string
+	OperatorID   *int64
+	Notes        string
+	CreatedAt    time.Time
+}
+
+// PointsShopItem 积分商城商品
+type PointsShopItem struct {
+	ID          int64
+	Name        string
+	Description string
+	Kind        string
+	PointsCost  int64
+	// BalanceAmount 兑换的余额（USD）；redeem_code 商品为余额兑换码面值
+	BalanceAmount money.Amount
+	// GroupID / ValidityDays 订阅分组与天数；redeem_code 商品设置 GroupID 时生成订阅兑换码
+	GroupID      *int64
+	ValidityDays int
+	// Stock 剩余库存，-1 表示不限
+	Stock int
+	// PerUserLimit 每人限兑次数，0 表示不限
+	PerUserLimit int
+	Enabled      bool
+	SortOrder    int
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+// PointsExchange 积分兑换记录，商品信息为兑换时快照
+type PointsExchange struct {
+	ID         int64
+	UserID     int64
+	ItemID     *int64
+	ItemName   string
+	Kind       string
+	PointsCost int64
+	// RedeemCode redeem_code 商品发给用户的兑换码
+	RedeemCode string
+	CreatedAt  time.Time
+	UserEmail  string
+}
+
+// PointsExchangeFilter 兑换记录查询条件
+type PointsExchangeFilter struct {
+	UserID int64
+	ItemID int64
+}
+
+// PointsRepository 积分账户、流水、商城商品与兑换记录存储。在事务上下文中调用时加入该事务。
+type PointsRepository interface {
+	// GetAccount 账户不存在时返回零值账户
+	GetAccount(ctx context.Context, userID int64) (*PointsAccount, error)
+	// ApplyDelta 变更积分并写入流水；扣减后余额为负时不做修改并返回 false
+	ApplyDelta(ctx context.Context, userID, delta int64, src PointsLedgerSource) (*PointsLedgerEntry, bool, error)
+	ListLedger(ctx context.Context, userID int64, params pagination.PaginationParams) ([]PointsLedgerEntry, *pagination.PaginationResult, error)
+
+	CreateItem(ctx context.Context, item *PointsShopItem) error
+	UpdateItem(ctx context.Context, item *PointsShopItem) error
+	DeleteItem(ctx context.Context, id int64) error
+	GetItem(ctx context.Context, id int64) (*PointsShopItem, error)
+	// GetItemForUpdate 锁定商品行，串行化同一商品的库存与限兑检查，需在事务内调用
+	GetItemForUpdate(ctx context.Context, id int64) (*PointsShopItem, error)
+	ListItems(ctx context.Context, enabledOnly bool) ([]PointsShopItem, error)
+	DecrementStock(ctx context.Context, id int64) error
+
+	CreateExchange(ctx context.Context, ex *PointsExchange) error
+	CountUserExchanges(ctx context.Context, userID, itemID int64) (int, error)
+	ListExchanges(ctx context.Context, params pagination.PaginationParams, filter PointsExchangeFilter) ([]PointsExchange, *pagination.PaginationResult, error)
+}
@@ -0,0 +1,374 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	dbent "github.com/Wei-Shaw/sub2api/ent"
+	infraerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/logger"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
+)
+
+// PointsService 积分账户与积分商城。
+//
+// 积分通过活动奖励或管理员调整获得，在积分商城兑换余额、订阅或兑换码；
+// 兑换在单个事务内完成扣积分、扣库存、写兑换记录与发放，任一步失败整体回滚。
+type PointsService struct {
+	repo                 PointsRepository
+	userRepo             UserRepository
+	groupRepo            GroupRepository
+	redeemService        *RedeemService
+	subscriptionService  *SubscriptionService
+	billingCacheService  *BillingCacheService
+	authCacheInvalidator APIKeyAuthCacheInvalidator
+	entClient            *dbent.Client
+}
+
+// NewPointsService 创建积分服务
+func NewPointsService(
+	repo PointsRepository,
+	userRepo UserRepository,
+	groupRepo GroupRepository,
+	redeemService *RedeemService,
+	subscriptionService *SubscriptionService,
+	billingCacheService *BillingCacheService,
+	authCacheInvalidator APIKeyAuthCacheInvalidator,
+	entClient *dbent.Client,
+) *PointsService {
+	return &PointsService{
+		repo:                 repo,
+		userRepo:             userRepo,
+		groupRepo:            groupRepo,
+		redeemService:        redeemService,
+		subscriptionService:  subscriptionService,
+		billingCacheService:  billingCacheService,
+		authCacheInvalidator: authCacheInvalidator,
+		entClient:            entClient,
+	}
+}
+
+// ============================================
+// 积分账户
+// ============================================
+
+// GetAccount 用户积分账户
+func (s *PointsService) GetAccount(ctx context.Context, userID int64) (*PointsAccount, error) {
+	return s.repo.GetAccount(ctx, userID)
+}
+
+// ListLedger 用户积分流水
+func (s *PointsService) ListLedger(ctx context.Context, userID int64, params pagination.PaginationParams) ([]PointsLedgerEntry, *pagination.PaginationResult, error) {
+	return s.repo.ListLedger(ctx, userID, params)
+}
+
+// Credit 发放积分（活动奖励等）
+func (s *PointsService) Credit(ctx context.Context, userID, points int64, src PointsLedgerSource) (*PointsLedgerEntry, error) {
+	if points <= 0 {
+		return nil, ErrPointsInvalidAmount
+	}
+	entry, _, err := s.repo.ApplyDelta(ctx, userID, points, src)
+	if err != nil {
+		return nil, fmt.Errorf("credit points: %w", err)
+	}
+	return entry, nil
+}
+
+// AdminAdjust 管理员调整积分；扣减超过当前余额时拒绝
+func (s *PointsService) AdminAdjust(ctx context.Context, userID, delta int64, notes string, operatorID int64) (*PointsLedgerEntry, error) {
+	if delta == 0 {
+		return nil, ErrPointsInvalidAmount
+	}
+	if _, err := s.userRepo.GetByID(ctx, userID); err != nil {
+		return nil, err
+	}
+	entry, ok, err := s.repo.ApplyDelta(ctx, userID, delta, PointsLedgerSource{
+		Type:       PointsSourceAdminAdjust,
+		OperatorID: &operatorID,
+		Notes:      strings.TrimSpace(notes),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("adjust points: %w", err)
+	}
+	if !ok {
+		return nil, ErrPointsInsufficient
+	}
+	return entry, nil
+}
+
+// ============================================
+// 积分商城
+// ============================================
+
+// ListItems 列出商城商品；enabledOnly 为 true 时仅返回上架商品
+func (s *PointsService) ListItems(ctx context.Context, enabledOnly bool) ([]PointsShopItem, error) {
+	return s.repo.ListItems(ctx, enabledOnly)
+}
+
+// GetItem 获取商城商品
+func (s *PointsService) GetItem(ctx context.Context, id int64) (*PointsShopItem, error) {
+	return s.repo.GetItem(ctx, id)
+}
+
+// CreateItem 创建商城商品
+func (s *PointsService) CreateItem(ctx context.Context, item *PointsShopItem) error {
+	if err := s.normalizeItem(ctx, item); err != nil {
+		return err
+	}
+	return s.repo.CreateItem(ctx, item)
+}
+
+// UpdateItem 更新商城商品；已有兑换记录保留兑换时的快照
+func (s *PointsService) UpdateItem(ctx context.Context, item *PointsShopItem) error {
+	if _, err := s.repo.GetItem(ctx, item.ID); err != nil {
+		return err
+	}
+	if err := s.normalizeItem(ctx, item); err != nil {
+		return err
+	}
+	return s.repo.UpdateItem(ctx, item)
+}
+
+// DeleteItem 删除商城商品
+func (s *PointsService) DeleteItem(ctx context.Context, id int64) error {
+	return s.repo.DeleteItem(ctx, id)
+}
+
+func invalidPointsShopItem(message string) error {
+	return infraerrors.BadRequest("POINTS_SHOP_ITEM_INVALID", message)
+}
+
+func (s *PointsService) normalizeItem(ctx context.Context, item *PointsShopItem) error {
+	item.Name = strings.TrimSpace(item.Name)
+	item.Description = strings.TrimSpace(item.Description)
+	if item.Name == "" || len([]rune(item.Name)) > 100 {
+		return invalidPointsShopItem("name is required and must be at most 100 characters")
+	}
+	if item.PointsCost <= 0 {
+		return invalidPointsShopItem("points_cost must be greater than 0")
+	}
+	if item.Stock < -1 {
+		return invalidPointsShopItem("stock must be -1 (unlimited) or non-negative")
+	}
+	if item.PerUserLimit < 0 {
+		return invalidPointsShopItem("per_user_limit must be non-negative")
+	}
+
+	switch item.Kind {
+	case PointsShopKindBalance:
+		item.GroupID = nil
+		item.ValidityDays = 0
+	case PointsShopKindSubscription:
+		if item.GroupID == nil {
+			return invalidPointsShopItem("group_id is required for subscription items")
+		}
+	case PointsShopKindRedeemCode:
+	default:
+		return invalidPointsShopItem("kind must be balance, subscription or redeem_code")
+	}
+
+	if item.GroupID != nil {
+		if item.ValidityDays <= 0 || item.ValidityDays > MaxValidityDays {
+			return invalidPointsShopItem(fmt.Sprintf("validity_days must be between 1 and %d", MaxValidityDays))
+		}
+		group, err := s.groupRepo.GetByID(ctx, *item.GroupID)
+		if err != nil {
+			return err
+		}
+		if !group.IsSubscriptionType() {
+			return invalidPointsShopItem("group must be subscription type")
+		}
+		item.BalanceAmount = 0
+		return nil
+	}
+	if !item.BalanceAmount.IsPositive() {
+		return invalidPointsShopItem("balance_amount must be greater than 0")
+	}
+	item.ValidityDays = 0
+	return nil
+}
+
+// Exchange 用积分兑换商城商品
+func (s *PointsService) Exchange(ctx context.Context, userID, itemID int64) (*PointsExchange, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !user.IsActive() {
+		return nil, ErrUserNotActive
+	}
+
+	var ex *PointsExchange
+	var item *PointsShopItem
+	err = s.withTx(ctx, func(txCtx context.Context) error {
+		var err error
+		if item, err = s.repo.GetItemForUpdate(txCtx, itemID); err != nil {
+			return err
+		}
+		if !item.Enabled {
+			return ErrPointsShopItemNotFound
+		}
+		if item.Stock == 0 {
+			return ErrPointsShopOutOfStock
+		}
+		if item.PerUserLimit > 0 {
+			count, err := s.repo.CountUserExchanges(txCtx, userID, item.ID)
+			if err != nil {
+				return fmt.Errorf("count exchanges: %w", err)
+			}
+			if count >= item.PerUserLimit {
+				return ErrPointsShopLimitExceeded
+			}
+		}
+
+		ex = &PointsExchange{
+			UserID:     userID,
+			ItemID:     &item.ID,
+			ItemName:   item.Name,
+			Kind:       item.Kind,
+			PointsCost: item.PointsCost,
+		}
+		if item.Kind == PointsShopKindRedeemCode {
+			if ex.RedeemCode, err = s.redeemService.GenerateRandomCode(); err != nil {
+				return err
+			}
+		}
+		if err := s.repo.CreateExchange(txCtx, ex); err != nil {
+			return fmt.Errorf("create exchange: %w", err)
+		}
+		ref := strconv.FormatInt(ex.ID, 10)
+
+		_, ok, err := s.repo.ApplyDelta(txCtx, userID, -item.PointsCost, PointsLedgerSource{
+			Type:        PointsSourceShopExchange,
+			ReferenceID: ref,
+			Notes:       item.Name,
+		})
+		if err != nil {
+			return fmt.Errorf("debit points: %w", err)
+		}
+		if !ok {
+			return ErrPointsInsufficient
+		}
+		if item.Stock > 0 {
+			if err := s.repo.DecrementStock(txCtx, item.ID); err != nil {
+				return err
+			}
+		}
+		return s.grantItem(txCtx, userID, item, ex)
+	})
+	if err != nil {
+		return nil, err
+	}
+	switch item.Kind {
+	case PointsShopKindBalance:
+		s.invalidateUserCaches(ctx, userID, nil)
+	case PointsShopKindSubscription:
+		s.invalidateUserCaches(ctx, userID, item.GroupID)
+	}
+	return ex, nil
+}
+
+// grantItem 发放兑换的商品。订阅放在最后一步：续期时订阅服务使用独立事务。
+func (s *PointsService) grantItem(txCtx context.Context, userID int64, item *PointsShopItem, ex *PointsExchange) error {
+	ref := strconv.FormatInt(ex.ID, 10)
+	switch item.Kind {
+	case PointsShopKindBalance:
+		if err := s.userRepo.UpdateBalance(txCtx, userID, item.BalanceAmount, BalanceLedgerSource{
+			Type:        BalanceLedgerSourcePointsExchange,
+			ReferenceID: ref,
+		}); err != nil {
+			return fmt.Errorf("add balance: %w", err)
+		}
+	case PointsShopKindSubscription:
+		if _, _, err := s.subscriptionService.AssignOrExtendSubscription(txCtx, &AssignSubscriptionInput{
+			UserID:       userID,
+			GroupID:      *item.GroupID,
+			ValidityDays: item.ValidityDays,
+			Notes:        fmt.Sprintf("积分兑换 #%s", ref),
+		}); err != nil {
+			return fmt.Errorf("assign subscription: %w", err)
+		}
+	case PointsShopKindRedeemCode:
+		code := &RedeemCode{
+			Code:     ex.RedeemCode,
+			Type:     RedeemTypeBalance,
+			Value:    item.BalanceAmount,
+			Notes:    "points exchange " + ref,
+			Category: pointsRedeemCodeCategory,
+		}
+		if item.GroupID != nil {
+			code.Type = RedeemTypeSubscription
+			code.Value = redeemCountValue(item.ValidityDays)
+			code.GroupID = item.GroupID
+			code.ValidityDays = item.ValidityDays
+		}
+		if err := s.redeemService.CreateCode(txCtx, code); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unsupported points shop item kind: %s", item.Kind)
+	}
+	return nil
+}
+
+// ListUserExchanges 用户的兑换记录
+func (s *PointsService) ListUserExchanges(ctx context.Context, userID int64, params pagination.PaginationParams) ([]PointsExchange, *pagination.PaginationResult, error) {
+	return s.repo.ListExchanges(ctx, params, PointsExchangeFilter{UserID: userID})
+}
+
+// ListExchanges 管理端兑换记录
+func (s *PointsService) ListExchanges(ctx context.Context, params pagination.PaginationParams, filter PointsExchangeFilter) ([]PointsExchange, *pagination.PaginationResult, error) {
+	return s.repo.ListExchanges(ctx, params, filter)
+}
+
+// ============================================
+// 内部方法
+// ============================================
+
+func (s *PointsService) withTx(ctx context.Context, fn func(txCtx context.Context) error) error {
+	if s.entClient == nil {
+		return fn(ctx)
+	}
+	if dbent.TxFromContext(ctx) != nil {
+		return fn(ctx)
+	}
+	tx, err := s.entClient.Tx(ctx)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err := fn(dbent.NewTxContext(ctx, tx)); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+	return nil
+}
+
+// invalidateUserCaches 兑换提交后失效余额（groupID 为 nil）或订阅缓存
+func (s *PointsService) invalidateUserCaches(ctx context.Context, userID int64, groupID *int64) {
+	if s.authCacheInvalidator != nil {
+		s.authCacheInvalidator.InvalidateAuthCacheByUserID(ctx, userID)
+	}
+	if s.billingCacheService == nil {
+		return
+	}
+	go func() {
+		cacheCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		var err error
+		if groupID != nil {
+			err = s.billingCacheService.InvalidateSubscription(cacheCtx, userID, *groupID)
+		} else {
+			err = s.billingCacheService.InvalidateUserBalance(cacheCtx, userID)
+		}
+		if err != nil {
+			logger.LegacyPrintf("service.points", "invalidate cache failed: user_id=%d err=%v", userID, err)
+		}
+	}()
+}
@@ -0,0 +1,251 @@
+//go:build unit
+
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
+	"github.com/stretchr/testify/require"
+)
+
+type pointsTestRepo struct {
+	accounts  map[int64]*PointsAccount
+	ledger    []PointsLedgerEntry
+	items     map[int64]*PointsShopItem
+	exchanges []PointsExchange
+	nextID    int64
+}
+
+func newPointsTestRepo() *pointsTestRepo {
+	return &pointsTestRepo{accounts: map[int64]*PointsAccount{}, items: map[int64]*PointsShopItem{}}
+}
+
+func (r *pointsTestRepo) GetAccount(_ context.Context, userID int64) (*PointsAccount, error) {
+	if a, ok := r.accounts[userID]; ok {
+		cp := *a
+		return &cp, nil
+	}
+	return &PointsAccount{UserID: userID}, nil
+}
+
+func (r *pointsTestRepo) ApplyDelta(_ context.Context, userID, delta int64, src PointsLedgerSource) (*PointsLedgerEntry, bool, error) {
+	a, ok := r.accounts[userID]
+	if !ok {
+		a = &PointsAccount{UserID: userID}
+		r.accounts[userID] = a
+	}
+	if a.Balance+delta < 0 {
+		return nil, false, nil
+	}
+	a.Balance += delta
+	if delta > 0 {
+		a.TotalEarned += delta
+	} else {
+		a.TotalSpent -= delta
+	}
+	r.nextID++
+	entry := PointsLedgerEntry{
+		ID:           r.nextID,
+		UserID:       userID,
+		Delta:        delta,
+		BalanceAfter: a.Balance,
+		SourceType:   src.Type,
+		ReferenceID:  src.ReferenceID,
+		OperatorID:   src.OperatorID,
+		Notes:        src.Notes,
+		CreatedAt:    time.Now(),
+	}
+	r.ledger = append(r.ledger, entry)
+	return &entry, true, nil
+}
+
+func (r *pointsTestRepo) ListLedger(_ context.Context, userID int64, params pagination.PaginationParams) ([]PointsLedgerEntry, *pagination.PaginationResult, error) {
+	var out []PointsLedgerEntry
+	for _, e := range r.ledger {
+		if e.UserID == userID {
+			out = append(out, e)
+		}
+	}
+	return out, &pagination.PaginationResult{Total: int64(len(out)), Page: params.Page, PageSize: params.PageSize}, nil
+}
+
+func (r *pointsTestRepo) CreateItem(_ context.Context, item *PointsShopItem) error {
+	r.nextID++
+	item.ID = r.nextID
+	cp := *item
+	r.items[item.ID] = &cp
+	return nil
+}
+
+func (r *pointsTestRepo) UpdateItem(_ context.Context, item *PointsShopItem) error {
+	cp := *item
+	r.items[item.ID] = &cp
+	return nil
+}
+
+func (r *pointsTestRepo) DeleteItem(_ context.Context, id int64) error {
+	delete(r.items, id)
+	return nil
+}
+
+func (r *pointsTestRepo) GetItem(_ context.Context, id int64) (*PointsShopItem, error) {
+	item, ok := r.items[id]
+	if !ok {
+		return nil, ErrPointsShopItemNotFound
+	}
+	cp := *item
+	return &cp, nil
+}
+
+func (r *pointsTestRepo) GetItemForUpdate(ctx context.Context, id int64) (*PointsShopItem, error) {
+	return r.GetItem(ctx, id)
+}
+
+func (r *pointsTestRepo) ListItems(_ context.Context, enabledOnly bool) ([]PointsShopItem, error) {
+	var out []PointsShopItem
+	for _, item := range r.items {
+		if !enabledOnly || item.Enabled {
+			out = append(out, *item)
+		}
+	}
+	return out, nil
+}
+
+func (r *pointsTestRepo) DecrementStock(_ context.Context, id int64) error {
+	r.items[id].Stock--
+	return nil
+}
+
+func (r *pointsTestRepo) CreateExchange(_ context.Context, ex *PointsExchange) error {
+	r.nextID++
+	ex.ID = r.nextID
+	ex.CreatedAt = time.Now()
+	r.exchanges = append(r.exchanges, *ex)
+	return nil
+}
+
+func (r *pointsTestRepo) CountUserExchanges(_ context.Context, userID, itemID int64) (int, error) {
+	n := 0
+	for _, ex := range r.exchanges {
+		if ex.UserID == userID && ex.ItemID != nil && *ex.ItemID == itemID {
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (r *pointsTestRepo) ListExchanges(_ context.Context, params pagination.PaginationParams, filter PointsExchangeFilter) ([]PointsExchange, *pagination.PaginationResult, error) {
+	var out []PointsExchange
+	for _, ex := range r.exchanges {
+		if filter.UserID == 0 || ex.UserID == filter.UserID {
+			out = append(out, ex)
+		}
+	}
+	return out, &pagination.PaginationResult{Total: int64(len(out)), Page: params.Page, PageSize: params.PageSize}, nil
+}
+
+const pointsTestUserID = int64(9)
+
+func newPointsTestService(t *testing.T) (*PointsService, *pointsTestRepo, *checkinTestUserRepo, *checkinTestRedeemRepo) {
+	t.Helper()
+	users := newCheckinTestUserRepo(map[int64]*User{
+		pointsTestUserID: {ID: pointsTestUserID, Role: RoleUser, Status: StatusActive},
+	})
+	redeemRepo := newCheckinTestRedeemRepo()
+	redeemService := NewRedeemService(redeemRepo, users, nil, nil, nil, nil, newPromoStatsTestEntClient(t), nil)
+	repo := newPointsTestRepo()
+	return NewPointsService(repo, users, nil, redeemService, nil, nil, nil, nil), repo, users, redeemRepo
+}
+
+func TestPointsExchangeBalance(t *testing.T) {
+	ctx := context.Background()
+	svc, repo, users, _ := newPointsTestService(t)
+
+	item := &PointsShopItem{Name: " $5 credit ", Kind: PointsShopKindBalance, PointsCost: 100, BalanceAmount: 5 * money.USD, Stock: 2, Enabled: true}
+	require.NoError(t, svc.CreateItem(ctx, item))
+	require.Equal(t, "$5 credit", item.Name)
+
+	_, err := svc.Exchange(ctx, pointsTestUserID, item.ID)
+	require.ErrorIs(t, err, ErrPointsInsufficient)
+
+	_, err = svc.Credit(ctx, pointsTestUserID, 250, PointsLedgerSource{Type: PointsSourceActivityReward, ReferenceID: "1"})
+	require.NoError(t, err)
+
+	ex, err := svc.Exchange(ctx, pointsTestUserID, item.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(100), ex.PointsCost)
+
+	user, err := users.GetByID(ctx, pointsTestUserID)
+	require.NoError(t, err)
+	require.Equal(t, 5*money.USD, user.Balance)
+
+	account, err := svc.GetAccount(ctx, pointsTestUserID)
+	require.NoError(t, err)
+	require.Equal(t, int64(150), account.Balance)
+	require.Equal(t, int64(250), account.TotalEarned)
+	require.Equal(t, int64(100), account.TotalSpent)
+	require.Equal(t, 1, repo.items[item.ID].Stock)
+
+	_, err = svc.Exchange(ctx, pointsTestUserID, item.ID)
+	require.NoError(t, err)
+	_, err = svc.Exchange(ctx, pointsTestUserID, item.ID)
+	require.ErrorIs(t, err, ErrPointsShopOutOfStock)
+}
+
+func TestPointsExchangeRedeemCodeAndLimit(t *testing.T) {
+	ctx := context.Background()
+	svc, _, _, redeemRepo := newPointsTestService(t)
+
+	item := &PointsShopItem{Name: "Gift code", Kind: PointsShopKindRedeemCode, PointsCost: 10, BalanceAmount: money.MustParse("1.2345678901"), Stock: -1, PerUserLimit: 1, Enabled: true}
+	require.NoError(t, svc.CreateItem(ctx, item))
+	_, err := svc.Credit(ctx, pointsTestUserID, 100, PointsLedgerSource{Type: PointsSourceActivityReward})
+	require.NoError(t, err)
+
+	ex, err := svc.Exchange(ctx, pointsTestUserID, item.ID)
+	require.NoError(t, err)
+	require.NotEmpty(t, ex.RedeemCode)
+
+	code, err := redeemRepo.GetByCode(ctx, ex.RedeemCode)
+	require.NoError(t, err)
+	require.Equal(t, RedeemTypeBalance, code.Type)
+	require.Equal(t, money.MustParse("1.2345678901"), code.Value)
+	require.Equal(t, pointsRedeemCodeCategory, code.Category)
+	require.Equal(t, StatusUnused, code.Status)
+
+	_, err = svc.Exchange(ctx, pointsTestUserID, item.ID)
+	require.ErrorIs(t, err, ErrPointsShopLimitExceeded)
+}
+
+func TestPointsAdminAdjust(t *testing.T) {
+	ctx := context.Background()
+	svc, _, _, _ := newPointsTestService(t)
+
+	_, err := svc.AdminAdjust(ctx, pointsTestUserID, 0, "", 1)
+	require.ErrorIs(t, err, ErrPointsInvalidAmount)
+
+	entry, err := svc.AdminAdjust(ctx, pointsTestUserID, 30, " bonus ", 1)
+	require.NoError(t, err)
+	require.Equal(t, int64(30), entry.BalanceAfter)
+	require.Equal(t, "bonus", entry.Notes)
+	require.Equal(t, PointsSourceAdminAdjust, entry.SourceType)
+
+	_, err = svc.AdminAdjust(ctx, pointsTestUserID, -31, "", 1)
+	require.ErrorIs(t, err, ErrPointsInsufficient)
+
+	_, err = svc.Credit(ctx, pointsTestUserID, -1, PointsLedgerSource{Type: PointsSourceActivityReward})
+	require.ErrorIs(t, err, ErrPointsInvalidAmount)
+}
+
+func TestPointsShopItemValidation(t *testing.T) {
+	ctx := context.Background()
+	svc, _, _, _ := newPointsTestService(t)
+
+	require.Error(t, svc.CreateItem(ctx, &PointsShopItem{Name: "x", Kind: PointsShopKindBalance, PointsCost: 0, BalanceAmount: money.USD}))
+	require.Error(t, svc.CreateItem(ctx, &PointsShopItem{Name: "x", Kind: PointsShopKindBalance, PointsCost: 1}))
+	require.Error(t, svc.CreateItem(ctx, &PointsShopItem{Name: "x", Kind: "gift", PointsCost: 1, BalanceAmount: money.USD}))
+	require.Error(t, svc.CreateItem(ctx, &PointsShopItem{Name: "x", Kind: PointsShopKindBalance, PointsCost: 1, BalanceAmount: money.USD, Stock: -2}))
+}
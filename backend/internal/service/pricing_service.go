@@ -39,6 +39,8 @@ type LiteLLMModelPricing struct {
 	OutputCostPerImage                  float64 `json:"output_cost_per_image"`      // 图片生成模型每张图片价格
 	VideoPricePerRequest                float64 `json:"video_price_per_request"`    // 视频生成标准质量单次价格
 	VideoPricePerRequestHD              float64 `json:"video_price_per_request_hd"` // 视频生成高清质量单次价格
+	InputCostPerSecond                  float64 `json:"input_cost_per_second"`      // 音频转写/翻译每秒价格
+	InputCostPerCharacter               float64 `json:"input_cost_per_character"`   // 语音合成每字符价格
 }
 
 // PricingRemoteClient 远程价格数据获取接口
@@ -60,6 +62,8 @@ type LiteLLMRawEntry struct {
 	OutputCostPerImage                  *float64 `json:"output_cost_per_image"`
 	VideoPricePerRequest                *float64 `json:"video_price_per_request"`
 	VideoPricePerRequestHD              *float64 `json:"video_price_per_request_hd"`
+	InputCostPerSecond                  *float64 `json:"input_cost_per_second"`
+	InputCostPerCharacter               *float64 `json:"input_cost_per_character"`
 }
 
 // PricingService 动态价格服务
@@ -305,8 +309,9 @@ func (s *PricingService) parsePricingData(body []byte) (map[string]*LiteLLMModel
 			continue
 		}
 
-		// 只保留有有效价格的条目
-		if entry.InputCostPerToken == nil && entry.OutputCostPerToken == nil {
+		// 只保留有有效价格的条目（音频模型只有按秒/按字符价格）
+		if entry.InputCostPerToken == nil && entry.OutputCostPerToken == nil &&
+			entry.InputCostPerSecond == nil && entry.InputCostPerCharacter == nil {
 			continue
 		}
 
@@ -340,6 +345,12 @@ func (s *PricingService) parsePricingData(body []byte) (map[string]*LiteLLMModel
 		if entry.VideoPricePerRequestHD != nil {
 			pricing.VideoPricePerRequestHD = *entry.VideoPricePerRequestHD
 		}
+		if entry.InputCostPerSecond != nil {
+			pricing.InputCostPerSecond = *entry.InputCostPerSecond
+		}
+		if entry.InputCostPerCharacter != nil {
+			pricing.InputCostPerCharacter = *entry.InputCostPerCharacter
+		}
 
 		result[modelName] = pricing
 	}
@@ -0,0 +1,375 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/config"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/logger"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/metrics"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	prometheusCollectTimeout = 10 * time.Second
+
+	// 账号状态标签（rate_limited/overloaded/temp_unschedulable 可能同时成立）
+	accountStateAvailable         = "available"
+	accountStateRateLimited       = "rate_limited"
+	accountStateOverloaded        = "overloaded"
+	accountStateTempUnschedulable = "temp_unschedulable"
+	accountStateUnschedulable     = "unschedulable"
+	accountStateError             = "error"
+	accountStateDisabled          = "disabled"
+)
+
+var accountMetricStates = []string{
+	accountStateAvailable,
+	accountStateRateLimited,
+	accountStateOverloaded,
+	accountStateTempUnschedulable,
+	accountStateUnschedulable,
+	accountStateError,
+	accountStateDisabled,
+}
+
+var (
+	accountsDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(metrics.Namespace, "accounts", "state"),
+		"Upstream accounts by platform and state (rate_limited/overloaded/temp_unschedulable may overlap).",
+		[]string{"platform", "state"}, nil)
+	accountSlotsInUseDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(metrics.Namespace, "concurrency", "account_slots_in_use"),
+		"Account concurrency slots currently held, by platform.",
+		[]string{"platform"}, nil)
+	accountSlotsCapacityDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(metrics.Namespace, "concurrency", "account_slots_capacity"),
+		"Total concurrency slots of active accounts, by platform.",
+		[]string{"platform"}, nil)
+	accountWaitingDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(metrics.Namespace, "concurrency", "account_waiting_requests"),
+		"Requests waiting for an account concurrency slot, by platform.",
+		[]string{"platform"}, nil)
+
+	schedulerSelectDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(metrics.Namespace, "openai_scheduler", "selections_total"),
+		"OpenAI account scheduler selections by decision layer.",
+		[]string{"layer"}, nil)
+	schedulerSwitchDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(metrics.Namespace, "openai_scheduler", "account_switches_total"),
+		"OpenAI account switches (failover) reported to the scheduler.",
+		nil, nil)
+	schedulerLatencyDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(metrics.Namespace, "openai_scheduler", "latency_seconds_total"),
+		"Cumulative time spent selecting OpenAI accounts.",
+		nil, nil)
+	schedulerLoadSkewDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(metrics.Namespace, "openai_scheduler", "load_skew_avg"),
+		"Average load skew among candidate accounts at selection time.",
+		nil, nil)
+	schedulerRuntimeAccountsDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(metrics.Namespace, "openai_scheduler", "runtime_stats_accounts"),
+		"Accounts tracked by the OpenAI scheduler runtime stats.",
+		nil, nil)
+
+	usageRecordWorkersDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(metrics.Namespace, "usage_record", "workers_running"),
+		"Running usage-record workers.",
+		nil, nil)
+	usageRecordMaxWorkersDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(metrics.Namespace, "usage_record", "workers_max"),
+		"Maximum usage-record worker concurrency.",
+		nil, nil)
+	usageRecordQueueDepthDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(metrics.Namespace, "usage_record", "queue_depth"),
+		"Usage-record tasks waiting in the worker pool queue.",
+		nil, nil)
+	usageRecordTasksDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(metrics.Namespace, "usage_record", "tasks_total"),
+		"Usage-record tasks by outcome.",
+		[]string{"outcome"}, nil)
+
+	outboxBacklogDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(metrics.Namespace, "scheduler_outbox", "backlog_events"),
+		"Scheduler outbox events not yet applied to the scheduler cache.",
+		nil, nil)
+	outboxLagDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(metrics.Namespace, "scheduler_outbox", "lag_seconds"),
+		"Age of the oldest scheduler outbox event not yet applied.",
+		nil, nil)
+)
+
+// PrometheusMetricsCollector 在抓取时汇总账号状态、并发槽位、调度器、用量记录工作池与 outbox 指标。
+// 账号列表按 metrics.account_state_refresh_seconds 缓存，并发占用每次抓取实时读取 Redis。
+type PrometheusMetricsCollector struct {
+	accountRepo           AccountRepository
+	concurrencyService    *ConcurrencyService
+	openAIGateway         *OpenAIGatewayService
+	usageRecordWorkerPool *UsageRecordWorkerPool
+	schedulerSnapshot     *SchedulerSnapshotService
+	refreshInterval       time.Duration
+
+	mu           sync.Mutex
+	accounts     []Account
+	accountsAt   time.Time
+	accountsLoad func(ctx context.Context) ([]Account, error)
+}
+
+// NewPrometheusMetricsCollector 创建 Prometheus 指标采集器
+func NewPrometheusMetricsCollector(
+	accountRepo AccountRepository,
+	concurrencyService *ConcurrencyService,
+	openAIGateway *OpenAIGatewayService,
+	usageRecordWorkerPool *UsageRecordWorkerPool,
+	schedulerSnapshot *SchedulerSnapshotService,
+	cfg *config.Config,
+) *PrometheusMetricsCollector {
+	refresh := 30 * time.Second
+	if cfg != nil && cfg.Metrics.AccountStateRefreshSeconds > 0 {
+		refresh = time.Duration(cfg.Metrics.AccountStateRefreshSeconds) * time.Second
+	}
+	c := &PrometheusMetricsCollector{
+		accountRepo:           accountRepo,
+		concurrencyService:    concurrencyService,
+		openAIGateway:         openAIGateway,
+		usageRecordWorkerPool: usageRecordWorkerPool,
+		schedulerSnapshot:     schedulerSnapshot,
+		refreshInterval:       refresh,
+	}
+	c.accountsLoad = c.listAllAccounts
+	return c
+}
+
+// Describe 实现 prometheus.Collector
+func (c *PrometheusMetricsCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, d := range []*prometheus.Desc{
+		accountsDesc, accountSlotsInUseDesc, accountSlotsCapacityDesc, accountWaitingDesc,
+		schedulerSelectDesc, schedulerSwitchDesc, schedulerLatencyDesc, schedulerLoadSkewDesc, schedulerRuntimeAccountsDesc,
+		usageRecordWorkersDesc, usageRecordMaxWorkersDesc, usageRecordQueueDepthDesc, usageRecordTasksDesc,
+		outboxBacklogDesc, outboxLagDesc,
+	} {
+		ch <- d
+	}
+}
+
+// Collect 实现 prometheus.Collector；单项数据源失败只跳过对应指标
+func (c *PrometheusMetricsCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), prometheusCollectTimeout)
+	defer cancel()
+
+	c.collectAccounts(ctx, ch)
+	c.collectOpenAIScheduler(ch)
+	c.collectUsageRecordPool(ch)
+	c.collectOutbox(ctx, ch)
+}
+
+func (c *PrometheusMetricsCollector) collectAccounts(ctx context.Context, ch chan<- prometheus.Metric) {
+	accounts, err := c.cachedAccounts(ctx)
+	if err != nil {
+		logger.LegacyPrintf("service.prometheus_metrics", "[Metrics] list accounts failed: %v", err)
+		return
+	}
+
+	now := time.Now()
+	states := make(map[string]map[string]int)
+	inUse := make(map[string]int)
+	capacity := make(map[string]int)
+	waiting := make(map[string]int)
+	loads := c.accountLoads(ctx, accounts)
+
+	for i := range accounts {
+		acc := &accounts[i]
+		if acc.ID <= 0 || acc.Platform == "" {
+			continue
+		}
+		byState, ok := states[acc.Platform]
+		if !ok {
+			byState = make(map[string]int, len(accountMetricStates))
+			states[acc.Platform] = byState
+		}
+		for _, state := range accountMetricStatesOf(acc, now) {
+			byState[state]++
+		}
+		if acc.Status == StatusActive {
+			capacity[acc.Platform] += acc.Concurrency
+		}
+		if load := loads[acc.ID]; load != nil {
+			inUse[acc.Platform] += load.CurrentConcurrency
+			waiting[acc.Platform] += load.WaitingCount
+		}
+	}
+
+	for platform, byState := range states {
+		for _, state := range accountMetricStates {
+			ch <- prometheus.MustNewConstMetric(accountsDesc, prometheus.GaugeValue, float64(byState[state]), platform, state)
+		}
+		ch <- prometheus.MustNewConstMetric(accountSlotsInUseDesc, prometheus.GaugeValue, float64(inUse[platform]), platform)
+		ch <- prometheus.MustNewConstMetric(accountSlotsCapacityDesc, prometheus.GaugeValue, float64(capacity[platform]), platform)
+		ch <- prometheus.MustNewConstMetric(accountWaitingDesc, prometheus.GaugeValue, float64(waiting[platform]), platform)
+	}
+}
+
+// accountMetricStatesOf 判定口径与运维监控的账号可用性统计保持一致
+func accountMetricStatesOf(acc *Account, now time.Time) []string {
+	switch acc.Status {
+	case StatusError:
+		return []string{accountStateError}
+	case StatusActive:
+	default:
+		return []string{accountStateDisabled}
+	}
+
+	var out []string
+	rateLimited := acc.RateLimitResetAt != nil && now.Before(*acc.RateLimitResetAt)
+	overloaded := acc.OverloadUntil != nil && now.Before(*acc.OverloadUntil)
+	tempUnsched := acc.TempUnschedulableUntil != nil && now.Before(*acc.TempUnschedulableUntil)
+	if rateLimited {
+		out = append(out, accountStateRateLimited)
+	}
+	if overloaded {
+		out = append(out, accountStateOverloaded)
+	}
+	if tempUnsched {
+		out = append(out, accountStateTempUnschedulable)
+	}
+	if !acc.Schedulable {
+		out = append(out, accountStateUnschedulable)
+	}
+	if len(out) == 0 {
+		out = append(out, accountStateAvailable)
+	}
+	return out
+}
+
+func (c *PrometheusMetricsCollector) cachedAccounts(ctx context.Context) ([]Account, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.accounts != nil && time.Since(c.accountsAt) < c.refreshInterval {
+		return c.accounts, nil
+	}
+	accounts, err := c.accountsLoad(ctx)
+	if err != nil {
+		// 刷新失败时继续使用上一次的快照，避免抓取结果出现断档
+		if c.accounts != nil {
+			logger.LegacyPrintf("service.prometheus_metrics", "[Metrics] refresh accounts failed, using stale snapshot: %v", err)
+			return c.accounts, nil
+		}
+		return nil, err
+	}
+	c.accounts = accounts
+	c.accountsAt = time.Now()
+	return accounts, nil
+}
+
+func (c *PrometheusMetricsCollector) listAllAccounts(ctx context.Context) ([]Account, error) {
+	if c.accountRepo == nil {
+		return []Account{}, nil
+	}
+	out := make([]Account, 0, 128)
+	for page := 1; page <= 10_000; page++ {
+		accounts, pageInfo, err := c.accountRepo.ListWithFilters(ctx, pagination.PaginationParams{
+			Page:     page,
+			PageSize: opsAccountsPageSize,
+		}, "", "", "", "", 0)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, accounts...)
+		if len(accounts) < opsAccountsPageSize || (pageInfo != nil && int64(len(out)) >= pageInfo.Total) {
+			break
+		}
+	}
+	return out, nil
+}
+
+func (c *PrometheusMetricsCollector) accountLoads(ctx context.Context, accounts []Account) map[int64]*AccountLoadInfo {
+	out := make(map[int64]*AccountLoadInfo, len(accounts))
+	if c.concurrencyService == nil || len(accounts) == 0 {
+		return out
+	}
+	batch := make([]AccountWithConcurrency, 0, len(accounts))
+	for _, acc := range accounts {
+		if acc.ID > 0 && acc.Status == StatusActive {
+			batch = append(batch, AccountWithConcurrency{ID: acc.ID, MaxConcurrency: acc.Concurrency})
+		}
+	}
+	for i := 0; i < len(batch); i += opsConcurrencyBatchChunkSize {
+		end := min(i+opsConcurrencyBatchChunkSize, len(batch))
+		part, err := c.concurrencyService.GetAccountsLoadBatch(ctx, batch[i:end])
+		if err != nil {
+			logger.LegacyPrintf("service.prometheus_metrics", "[Metrics] GetAccountsLoadBatch failed: %v", err)
+			continue
+		}
+		for id, info := range part {
+			out[id] = info
+		}
+	}
+	return out
+}
+
+func (c *PrometheusMetricsCollector) collectOpenAIScheduler(ch chan<- prometheus.Metric) {
+	if c.openAIGateway == nil {
+		return
+	}
+	snap := c.openAIGateway.SnapshotOpenAIAccountSchedulerMetrics()
+	ch <- prometheus.MustNewConstMetric(schedulerSelectDesc, prometheus.CounterValue, float64(snap.StickyPreviousHitTotal), "sticky_previous_response")
+	ch <- prometheus.MustNewConstMetric(schedulerSelectDesc, prometheus.CounterValue, float64(snap.StickySessionHitTotal), "sticky_session")
+	ch <- prometheus.MustNewConstMetric(schedulerSelectDesc, prometheus.CounterValue, float64(snap.LoadBalanceSelectTotal), "load_balance")
+	ch <- prometheus.MustNewConstMetric(schedulerSwitchDesc, prometheus.CounterValue, float64(snap.AccountSwitchTotal))
+	ch <- prometheus.MustNewConstMetric(schedulerLatencyDesc, prometheus.CounterValue, float64(snap.SchedulerLatencyMsTotal)/1000)
+	ch <- prometheus.MustNewConstMetric(schedulerLoadSkewDesc, prometheus.GaugeValue, snap.LoadSkewAvg)
+	ch <- prometheus.MustNewConstMetric(schedulerRuntimeAccountsDesc, prometheus.GaugeValue, float64(snap.RuntimeStatsAccountCount))
+}
+
+func (c *PrometheusMetricsCollector) collectUsageRecordPool(ch chan<- prometheus.Metric) {
+	if c.usageRecordWorkerPool == nil {
+		return
+	}
+	stats := c.usageRecordWorkerPool.Stats()
+	ch <- prometheus.MustNewConstMetric(usageRecordWorkersDesc, prometheus.GaugeValue, float64(stats.RunningWorkers))
+	ch <- prometheus.MustNewConstMetric(usageRecordMaxWorkersDesc, prometheus.GaugeValue, float64(stats.MaxConcurrency))
+	ch <- prometheus.MustNewConstMetric(usageRecordQueueDepthDesc, prometheus.GaugeValue, float64(stats.WaitingTasks))
+	for outcome, v := range map[string]uint64{
+		"submitted":            stats.SubmittedTasks,
+		"succeeded":            stats.SuccessfulTasks,
+		"failed":               stats.FailedTasks,
+		"dropped_queue_full":   stats.DroppedQueueFull,
+		"dropped_pool_stopped": stats.DroppedPoolStopped,
+		"sync_fallback":        stats.SyncFallbackTasks,
+	} {
+		ch <- prometheus.MustNewConstMetric(usageRecordTasksDesc, prometheus.CounterValue, float64(v), outcome)
+	}
+}
+
+func (c *PrometheusMetricsCollector) collectOutbox(ctx context.Context, ch chan<- prometheus.Metric) {
+	if c.schedulerSnapshot == nil {
+		return
+	}
+	lag, err := c.schedulerSnapshot.OutboxLag(ctx)
+	if err != nil {
+		logger.LegacyPrintf("service.prometheus_metrics", "[Metrics] read outbox lag failed: %v", err)
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(outboxBacklogDesc, prometheus.GaugeValue, float64(lag.Backlog))
+	ch <- prometheus.MustNewConstMetric(outboxLagDesc, prometheus.GaugeValue, lag.OldestAge.Seconds())
+}
+
+// observeFirstTokenMetric 把流式请求的首 token 延迟计入 Prometheus 直方图
+func observeFirstTokenMetric(apiKey *APIKey, account *Account, model string, firstTokenMs *int) {
+	if firstTokenMs == nil || *firstTokenMs < 0 {
+		return
+	}
+	platform, group := "", ""
+	if account != nil {
+		platform = account.Platform
+	}
+	if apiKey != nil && apiKey.Group != nil {
+		group = apiKey.Group.Name
+		if apiKey.Group.Platform != "" {
+			platform = apiKey.Group.Platform
+		}
+	}
+	metrics.ObserveTimeToFirstToken(platform, group, model, time.Duration(*firstTokenMs)*time.Millisecond)
+}
@@ -0,0 +1,174 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/config"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+type stubMetricsOutboxRepo struct {
+	events []SchedulerOutboxEvent
+	maxID  int64
+}
+
+func (r stubMetricsOutboxRepo) ListAfter(_ context.Context, afterID int64, limit int) ([]SchedulerOutboxEvent, error) {
+	out := make([]SchedulerOutboxEvent, 0, limit)
+	for _, e := range r.events {
+		if e.ID > afterID && len(out) < limit {
+			out = append(out, e)
+		}
+	}
+	return out, nil
+}
+
+func (r stubMetricsOutboxRepo) MaxID(context.Context) (int64, error) { return r.maxID, nil }
+
+type stubMetricsSchedulerCache struct {
+	SchedulerCache
+	watermark int64
+}
+
+func (c stubMetricsSchedulerCache) GetOutboxWatermark(context.Context) (int64, error) {
+	return c.watermark, nil
+}
+
+func TestAccountMetricStatesOf(t *testing.T) {
+	now := time.Now()
+	future := now.Add(time.Minute)
+	past := now.Add(-time.Minute)
+
+	cases := []struct {
+		name string
+		acc  Account
+		want []string
+	}{
+		{"available", Account{Status: StatusActive, Schedulable: true, RateLimitResetAt: &past}, []string{accountStateAvailable}},
+		{"error wins", Account{Status: StatusError, RateLimitResetAt: &future}, []string{accountStateError}},
+		{"disabled", Account{Status: StatusDisabled, Schedulable: true}, []string{accountStateDisabled}},
+		{"overlapping", Account{Status: StatusActive, Schedulable: true, RateLimitResetAt: &future, TempUnschedulableUntil: &future}, []string{accountStateRateLimited, accountStateTempUnschedulable}},
+		{"overloaded and paused", Account{Status: StatusActive, OverloadUntil: &future}, []string{accountStateOverloaded, accountStateUnschedulable}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.want, accountMetricStatesOf(&tc.acc, now))
+		})
+	}
+}
+
+func TestPrometheusMetricsCollector_Collect(t *testing.T) {
+	future := time.Now().Add(time.Hour)
+	accounts := []Account{
+		{ID: 1, Platform: PlatformAnthropic, Status: StatusActive, Schedulable: true, Concurrency: 5},
+		{ID: 2, Platform: PlatformAnthropic, Status: StatusActive, Schedulable: true, Concurrency: 3, RateLimitResetAt: &future},
+		{ID: 3, Platform: PlatformOpenAI, Status: StatusError, Concurrency: 10},
+	}
+	cfg := &config.Config{}
+	cfg.Metrics.AccountStateRefreshSeconds = 60
+	concurrency := NewConcurrencyService(stubConcurrencyCache{
+		skipDefaultLoad: true,
+		loadMap: map[int64]*AccountLoadInfo{
+			1: {AccountID: 1, CurrentConcurrency: 4, WaitingCount: 2},
+			2: {AccountID: 2, CurrentConcurrency: 1},
+		},
+	})
+	snapshot := NewSchedulerSnapshotService(
+		stubMetricsSchedulerCache{watermark: 10},
+		stubMetricsOutboxRepo{maxID: 15, events: []SchedulerOutboxEvent{{ID: 11, CreatedAt: time.Now().Add(-90 * time.Second)}}},
+		nil, nil, cfg)
+
+	collector := NewPrometheusMetricsCollector(nil, concurrency, nil, nil, snapshot, cfg)
+	loads := 0
+	collector.accountsLoad = func(context.Context) ([]Account, error) {
+		loads++
+		return accounts, nil
+	}
+
+	expected := `
+# HELP sub2api_accounts_state Upstream accounts by platform and state (rate_limited/overloaded/temp_unschedulable may overlap).
+# TYPE sub2api_accounts_state gauge
+sub2api_accounts_state{platform="anthropic",state="available"} 1
+sub2api_accounts_state{platform="anthropic",state="disabled"} 0
+sub2api_accounts_state{platform="anthropic",state="error"} 0
+sub2api_accounts_state{platform="anthropic",state="overloaded"} 0
+sub2api_accounts_state{platform="anthropic",state="rate_limited"} 1
+sub2api_accounts_state{platform="anthropic",state="temp_unschedulable"} 0
+sub2api_accounts_state{platform="anthropic",state="unschedulable"} 0
+sub2api_accounts_state{platform="openai",state="available"} 0
+sub2api_accounts_state{platform="openai",state="disabled"} 0
+sub2api_accounts_state{platform="openai",state="error"} 1
+sub2api_accounts_state{platform="openai",state="overloaded"} 0
+sub2api_accounts_state{platform="openai",state="rate_limited"} 0
+sub2api_accounts_state{platform="openai",state="temp_unschedulable"} 0
+sub2api_accounts_state{platform="openai",state="unschedulable"} 0
+# HELP sub2api_concurrency_account_slots_capacity Total concurrency slots of active accounts, by platform.
+# TYPE sub2api_concurrency_account_slots_capacity gauge
+sub2api_concurrency_account_slots_capacity{platform="anthropic"} 8
+sub2api_concurrency_account_slots_capacity{platform="openai"} 0
+# HELP sub2api_concurrency_account_slots_in_use Account concurrency slots currently held, by platform.
+# TYPE sub2api_concurrency_account_slots_in_use gauge
+sub2api_concurrency_account_slots_in_use{platform="anthropic"} 5
+sub2api_concurrency_account_slots_in_use{platform="openai"} 0
+# HELP sub2api_concurrency_account_waiting_requests Requests waiting for an account concurrency slot, by platform.
+# TYPE sub2api_concurrency_account_waiting_requests gauge
+sub2api_concurrency_account_waiting_requests{platform="anthropic"} 2
+sub2api_concurrency_account_waiting_requests{platform="openai"} 0
+# HELP sub2api_scheduler_outbox_backlog_events Scheduler outbox events not yet applied to the scheduler cache.
+# TYPE sub2api_scheduler_outbox_backlog_events gauge
+sub2api_scheduler_outbox_backlog_events 5
+`
+	require.NoError(t, testutil.CollectAndCompare(collector, strings.NewReader(expected),
+		"sub2api_accounts_state",
+		"sub2api_concurrency_account_slots_capacity",
+		"sub2api_concurrency_account_slots_in_use",
+		"sub2api_concurrency_account_waiting_requests",
+		"sub2api_scheduler_outbox_backlog_events",
+	))
+
+	lag, err := snapshot.OutboxLag(context.Background())
+	require.NoError(t, err)
+	require.InDelta(t, 90, lag.OldestAge.Seconds(), 5)
+
+	// 快照在刷新间隔内复用，不重复扫描账号
+	_ = testutil.CollectAndCount(collector)
+	require.Equal(t, 1, loads)
+}
+
+func TestPrometheusMetricsCollector_StaleSnapshotOnRefreshError(t *testing.T) {
+	collector := NewPrometheusMetricsCollector(nil, nil, nil, nil, nil, &config.Config{})
+	collector.accountsLoad = func(context.Context) ([]Account, error) {
+		return nil, errors.New("db down")
+	}
+	_, err := collector.cachedAccounts(context.Background())
+	require.Error(t, err)
+
+	collector.accounts = []Account{{ID: 1}}
+	collector.accountsAt = time.Now().Add(-time.Hour)
+	got, err := collector.cachedAccounts(context.Background())
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+}
+
+func TestPrometheusMetricsCollector_UsageRecordPool(t *testing.T) {
+	pool := NewUsageRecordWorkerPoolWithOptions(UsageRecordWorkerPoolOptions{WorkerCount: 2, QueueSize: 4, TaskTimeout: time.Second})
+	defer pool.Stop()
+	collector := NewPrometheusMetricsCollector(nil, nil, nil, pool, nil, &config.Config{})
+
+	reg := prometheus.NewPedanticRegistry()
+	require.NoError(t, reg.Register(collector))
+	families, err := reg.Gather()
+	require.NoError(t, err)
+	names := make([]string, 0, len(families))
+	for _, f := range families {
+		names = append(names, f.GetName())
+	}
+	require.Contains(t, names, "sub2api_usage_record_queue_depth")
+	require.Contains(t, names, "sub2api_usage_record_tasks_total")
+	require.Contains(t, names, "sub2api_usage_record_workers_max")
+}
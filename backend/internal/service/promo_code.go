@@ -1,14 +1,17 @@
 package service
 
 import (
+	"strings"
 	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
 )
 
-// PromoCode 注册优惠码
+// PromoCode 优惠码（注册时或注册后使用）
 type PromoCode struct {
 	ID          int64
 	Code        string
-	BonusAmount float64
+	BonusAmount money.Amount
 	MaxUses     int
 	UsedCount   int
 	Status      string
@@ -17,6 +20,21 @@ type PromoCode struct {
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
 
+	// 首次充值赠送：使用后第一笔不低于 MinTopupAmount 的余额充值按比例赠送，TopupBonusMax 为 0 表示不封顶
+	TopupBonusPercent float64
+	TopupBonusMax     money.Amount
+	MinTopupAmount    money.Amount
+
+	// 使用限制
+	PerUserLimit        int               // 每用户最多使用次数，0 表示无限制
+	AllowedEmailDomains []string          // 允许的邮箱域名，空表示不限制
+	RequiredAttributes  map[string]string // 要求的用户属性取值（属性 key -> 值），空表示不限制
+	Stackable           bool              // 可叠加的优惠码只能与其他可叠加的优惠码同时使用
+
+	// 试用订阅
+	TrialGroupID *int64
+	TrialDays    int
+
 	// 关联
 	UsageRecords []PromoCodeUsage
 }
@@ -26,9 +44,13 @@ type PromoCodeUsage struct {
 	ID          int64
 	PromoCodeID int64
 	UserID      int64
-	BonusAmount float64
+	BonusAmount money.Amount
 	UsedAt      time.Time
 
+	TopupBonusStatus string // none / pending / granted
+	TopupBonusAmount money.Amount
+	TopupGrantedAt   *time.Time
+
 	// 关联
 	PromoCode *PromoCode
 	User      *User
@@ -53,21 +75,81 @@ func (p *PromoCode) IsExpired() bool {
 	return p.ExpiresAt != nil && time.Now().After(*p.ExpiresAt)
 }
 
+// HasTopupBonus 是否包含首次充值赠送
+func (p *PromoCode) HasTopupBonus() bool {
+	return p.TopupBonusPercent > 0
+}
+
+// HasTrial 是否赠送试用订阅
+func (p *PromoCode) HasTrial() bool {
+	return p.TrialGroupID != nil && p.TrialDays > 0
+}
+
+// AllowsEmail 检查邮箱域名是否在允许范围内（不区分大小写，未配置时不限制）
+func (p *PromoCode) AllowsEmail(email string) bool {
+	if len(p.AllowedEmailDomains) == 0 {
+		return true
+	}
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return false
+	}
+	domain := strings.ToLower(strings.TrimSpace(email[at+1:]))
+	for _, allowed := range p.AllowedEmailDomains {
+		if strings.ToLower(strings.TrimPrefix(strings.TrimSpace(allowed), "@")) == domain {
+			return true
+		}
+	}
+	return false
+}
+
+// TopupBonusFor 计算一笔充值可获得的赠送金额；未达到最低充值金额时返回 0
+func (p *PromoCode) TopupBonusFor(amount money.Amount) money.Amount {
+	if !p.HasTopupBonus() || !amount.IsPositive() || amount < p.MinTopupAmount {
+		return 0
+	}
+	bonus := amount.Mul(p.TopupBonusPercent / 100)
+	if p.TopupBonusMax.IsPositive() && bonus > p.TopupBonusMax {
+		bonus = p.TopupBonusMax
+	}
+	return bonus
+}
+
 // CreatePromoCodeInput 创建优惠码输入
 type CreatePromoCodeInput struct {
 	Code        string
-	BonusAmount float64
+	BonusAmount money.Amount
 	MaxUses     int
 	ExpiresAt   *time.Time
 	Notes       string
+
+	TopupBonusPercent   float64
+	TopupBonusMax       money.Amount
+	MinTopupAmount      money.Amount
+	PerUserLimit        int
+	AllowedEmailDomains []string
+	RequiredAttributes  map[string]string
+	Stackable           bool
+	TrialGroupID        *int64
+	TrialDays           int
 }
 
 // UpdatePromoCodeInput 更新优惠码输入
 type UpdatePromoCodeInput struct {
 	Code        *string
-	BonusAmount *float64
+	BonusAmount *money.Amount
 	MaxUses     *int
 	Status      *string
 	ExpiresAt   *time.Time
 	Notes       *string
+
+	TopupBonusPercent   *float64
+	TopupBonusMax       *money.Amount
+	MinTopupAmount      *money.Amount
+	PerUserLimit        *int
+	AllowedEmailDomains *[]string
+	RequiredAttributes  *map[string]string
+	Stackable           *bool
+	TrialGroupID        *int64 // 0 表示清除
+	TrialDays           *int
 }
@@ -2,7 +2,9 @@ package service
 
 import (
 	"context"
+	"time"
 
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
 	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
 )
 
@@ -22,7 +24,10 @@ type PromoCodeRepository interface {
 
 	// 使用记录
 	CreateUsage(ctx context.Context, usage *PromoCodeUsage) error
-	GetUsageByPromoCodeAndUser(ctx context.Context, promoCodeID, userID int64) (*PromoCodeUsage, error)
+	CountUsagesByPromoCodeAndUser(ctx context.Context, promoCodeID, userID int64) (int, error)
+	ListUsagesByUser(ctx context.Context, userID int64) ([]PromoCodeUsage, error)                // 附带 PromoCode
+	ListPendingTopupUsagesForUpdate(ctx context.Context, userID int64) ([]PromoCodeUsage, error) // 附带 PromoCode，按使用时间升序并加行锁
+	MarkTopupBonusGranted(ctx context.Context, usageID int64, amount money.Amount, grantedAt time.Time) error
 	GetLatestUsageByUser(ctx context.Context, userID int64) (*PromoCodeUsage, error)
 	ListUsagesByPromoCode(ctx context.Context, promoCodeID int64, params pagination.PaginationParams) ([]PromoCodeUsage, *pagination.PaginationResult, error)
 
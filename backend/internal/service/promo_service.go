@@ -5,28 +5,34 @@ import (
 	"crypto/rand"
 	"encoding/hex"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
 	dbent "github.com/Wei-Shaw/sub2api/ent"
 	"github.com/Wei-Shaw/sub2api/ent/usersubscription"
 	infraerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
 	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
 )
 
 var (
-	ErrPromoCodeNotFound    = infraerrors.NotFound("PROMO_CODE_NOT_FOUND", "promo code not found")
-	ErrPromoCodeExpired     = infraerrors.BadRequest("PROMO_CODE_EXPIRED", "promo code has expired")
-	ErrPromoCodeDisabled    = infraerrors.BadRequest("PROMO_CODE_DISABLED", "promo code is disabled")
-	ErrPromoCodeMaxUsed     = infraerrors.BadRequest("PROMO_CODE_MAX_USED", "promo code has reached maximum uses")
-	ErrPromoCodeAlreadyUsed = infraerrors.Conflict("PROMO_CODE_ALREADY_USED", "you have already used this promo code")
-	ErrPromoCodeInvalid     = infraerrors.BadRequest("PROMO_CODE_INVALID", "invalid promo code")
+	ErrPromoCodeNotFound     = infraerrors.NotFound("PROMO_CODE_NOT_FOUND", "promo code not found")
+	ErrPromoCodeExpired      = infraerrors.BadRequest("PROMO_CODE_EXPIRED", "promo code has expired")
+	ErrPromoCodeDisabled     = infraerrors.BadRequest("PROMO_CODE_DISABLED", "promo code is disabled")
+	ErrPromoCodeMaxUsed      = infraerrors.BadRequest("PROMO_CODE_MAX_USED", "promo code has reached maximum uses")
+	ErrPromoCodeAlreadyUsed  = infraerrors.Conflict("PROMO_CODE_ALREADY_USED", "you have already used this promo code")
+	ErrPromoCodeInvalid      = infraerrors.BadRequest("PROMO_CODE_INVALID", "invalid promo code")
+	ErrPromoCodeNotEligible  = infraerrors.Forbidden("PROMO_CODE_NOT_ELIGIBLE", "your account is not eligible for this promo code")
+	ErrPromoCodeNotStackable = infraerrors.Conflict("PROMO_CODE_NOT_STACKABLE", "promo code cannot be combined with your other pending promo codes")
 )
 
 // PromoService 优惠码服务
 type PromoService struct {
 	promoRepo            PromoCodeRepository
 	userRepo             UserRepository
+	subscriptionService  *SubscriptionService
+	userAttributeService *UserAttributeService
 	billingCacheService  *BillingCacheService
 	entClient            *dbent.Client
 	authCacheInvalidator APIKeyAuthCacheInvalidator
@@ -36,6 +42,8 @@ type PromoService struct {
 func NewPromoService(
 	promoRepo PromoCodeRepository,
 	userRepo UserRepository,
+	subscriptionService *SubscriptionService,
+	userAttributeService *UserAttributeService,
 	billingCacheService *BillingCacheService,
 	entClient *dbent.Client,
 	authCacheInvalidator APIKeyAuthCacheInvalidator,
@@ -43,6 +51,8 @@ func NewPromoService(
 	return &PromoService{
 		promoRepo:            promoRepo,
 		userRepo:             userRepo,
+		subscriptionService:  subscriptionService,
+		userAttributeService: userAttributeService,
 		billingCacheService:  billingCacheService,
 		entClient:            entClient,
 		authCacheInvalidator: authCacheInvalidator,
@@ -103,18 +113,19 @@ func (s *PromoService) validatePromoCodeStatus(promoCode *PromoCode) error {
 	return nil
 }
 
-// ApplyPromoCode 应用优惠码（注册成功后调用）
-// 使用事务和行锁确保并发安全
-func (s *PromoService) ApplyPromoCode(ctx context.Context, userID int64, code string) error {
+// ApplyPromoCode 应用优惠码（注册成功后或在个人中心调用）
+// 使用事务和行锁确保并发安全；依次校验状态、每用户次数、邮箱域名与用户属性、叠加规则，
+// 然后发放固定赠送余额、登记首次充值赠送并发放试用订阅
+func (s *PromoService) ApplyPromoCode(ctx context.Context, userID int64, code string) (*PromoCodeUsage, error) {
 	code = strings.TrimSpace(code)
 	if code == "" {
-		return nil
+		return nil, nil
 	}
 
 	// 开启事务
 	tx, err := s.entClient.Tx(ctx)
 	if err != nil {
-		return fmt.Errorf("begin transaction: %w", err)
+		return nil, fmt.Errorf("begin transaction: %w", err)
 	}
 	defer func() { _ = tx.Rollback() }()
 
@@ -123,52 +134,97 @@ func (s *PromoService) ApplyPromoCode(ctx context.Context, userID int64, code st
 	// 在事务中获取并锁定优惠码记录（FOR UPDATE）
 	promoCode, err := s.promoRepo.GetByCodeForUpdate(txCtx, code)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	// 在事务中验证优惠码状态
 	if err := s.validatePromoCodeStatus(promoCode); err != nil {
-		return err
+		return nil, err
 	}
 
-	// 在事务中检查用户是否已使用过此优惠码
-	existing, err := s.promoRepo.GetUsageByPromoCodeAndUser(txCtx, promoCode.ID, userID)
-	if err != nil {
-		return fmt.Errorf("check existing usage: %w", err)
+	// 在事务中检查用户使用次数
+	if promoCode.PerUserLimit > 0 {
+		used, err := s.promoRepo.CountUsagesByPromoCodeAndUser(txCtx, promoCode.ID, userID)
+		if err != nil {
+			return nil, fmt.Errorf("check existing usage: %w", err)
+		}
+		if used >= promoCode.PerUserLimit {
+			return nil, ErrPromoCodeAlreadyUsed
+		}
 	}
-	if existing != nil {
-		return ErrPromoCodeAlreadyUsed
+
+	if err := s.checkEligibility(txCtx, userID, promoCode); err != nil {
+		return nil, err
+	}
+
+	// 叠加规则：充值赠送在首次达标充值时统一结算，尚未结算的其他优惠码必须双方都可叠加
+	if promoCode.HasTopupBonus() {
+		pending, err := s.promoRepo.ListPendingTopupUsagesForUpdate(txCtx, userID)
+		if err != nil {
+			return nil, fmt.Errorf("list pending topup bonuses: %w", err)
+		}
+		for i := range pending {
+			other := pending[i].PromoCode
+			if pending[i].PromoCodeID == promoCode.ID || other == nil {
+				continue
+			}
+			if !promoCode.Stackable || !other.Stackable {
+				return nil, ErrPromoCodeNotStackable
+			}
+		}
 	}
 
 	// 增加用户余额
-	if err := s.userRepo.UpdateBalance(txCtx, userID, promoCode.BonusAmount); err != nil {
-		return fmt.Errorf("update user balance: %w", err)
+	if promoCode.BonusAmount.IsPositive() {
+		if err := s.userRepo.UpdateBalance(txCtx, userID, promoCode.BonusAmount, BalanceLedgerSource{
+			Type:        BalanceLedgerSourcePromoCode,
+			ReferenceID: strconv.FormatInt(promoCode.ID, 10),
+		}); err != nil {
+			return nil, fmt.Errorf("update user balance: %w", err)
+		}
 	}
 
 	// 创建使用记录
 	usage := &PromoCodeUsage{
-		PromoCodeID: promoCode.ID,
-		UserID:      userID,
-		BonusAmount: promoCode.BonusAmount,
-		UsedAt:      time.Now(),
+		PromoCodeID:      promoCode.ID,
+		UserID:           userID,
+		BonusAmount:      promoCode.BonusAmount,
+		UsedAt:           time.Now(),
+		TopupBonusStatus: PromoTopupBonusStatusNone,
+	}
+	if promoCode.HasTopupBonus() {
+		usage.TopupBonusStatus = PromoTopupBonusStatusPending
 	}
 	if err := s.promoRepo.CreateUsage(txCtx, usage); err != nil {
-		return fmt.Errorf("create usage record: %w", err)
+		return nil, fmt.Errorf("create usage record: %w", err)
 	}
 
 	// 增加使用次数
 	if err := s.promoRepo.IncrementUsedCount(txCtx, promoCode.ID); err != nil {
-		return fmt.Errorf("increment used count: %w", err)
+		return nil, fmt.Errorf("increment used count: %w", err)
+	}
+
+	// 试用订阅（已有同分组订阅时顺延）
+	if promoCode.HasTrial() && s.subscriptionService != nil {
+		if _, _, err := s.subscriptionService.AssignOrExtendSubscription(txCtx, &AssignSubscriptionInput{
+			UserID:       userID,
+			GroupID:      *promoCode.TrialGroupID,
+			ValidityDays: promoCode.TrialDays,
+			AssignedBy:   0, // 系统分配
+			Notes:        fmt.Sprintf("通过优惠码 %s 试用", promoCode.Code),
+		}); err != nil {
+			return nil, fmt.Errorf("assign trial subscription: %w", err)
+		}
 	}
 
 	if err := tx.Commit(); err != nil {
-		return fmt.Errorf("commit transaction: %w", err)
+		return nil, fmt.Errorf("commit transaction: %w", err)
 	}
 
 	s.invalidatePromoCaches(ctx, userID, promoCode.BonusAmount)
 
 	// 失效余额缓存
-	if s.billingCacheService != nil {
+	if s.billingCacheService != nil && !promoCode.BonusAmount.IsZero() {
 		go func() {
 			cacheCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 			defer cancel()
@@ -176,11 +232,89 @@ func (s *PromoService) ApplyPromoCode(ctx context.Context, userID int64, code st
 		}()
 	}
 
+	usage.PromoCode = promoCode
+	return usage, nil
+}
+
+// checkEligibility 校验邮箱域名与用户属性限制
+func (s *PromoService) checkEligibility(ctx context.Context, userID int64, promoCode *PromoCode) error {
+	if len(promoCode.AllowedEmailDomains) > 0 {
+		user, err := s.userRepo.GetByID(ctx, userID)
+		if err != nil {
+			return fmt.Errorf("get user: %w", err)
+		}
+		if !promoCode.AllowsEmail(user.Email) {
+			return ErrPromoCodeNotEligible
+		}
+	}
+
+	if len(promoCode.RequiredAttributes) > 0 {
+		if s.userAttributeService == nil {
+			return ErrPromoCodeNotEligible
+		}
+		values, err := s.userAttributeService.GetUserAttributeValuesByKey(ctx, userID)
+		if err != nil {
+			return fmt.Errorf("get user attributes: %w", err)
+		}
+		for key, want := range promoCode.RequiredAttributes {
+			if strings.TrimSpace(values[key]) != strings.TrimSpace(want) {
+				return ErrPromoCodeNotEligible
+			}
+		}
+	}
 	return nil
 }
 
-func (s *PromoService) invalidatePromoCaches(ctx context.Context, userID int64, bonusAmount float64) {
-	if bonusAmount == 0 || s.authCacheInvalidator == nil {
+// GrantTopupBonus 在付费充值（payment 分类余额兑换码入账）时结算首次充值赠送，必须在充值所在事务中调用。
+// 每个待结算的优惠码在单笔充值中最多结算一次（同一优惠码多次使用时依次在后续充值中结算），
+// 未达到最低充值金额的优惠码继续等待下一笔充值。返回本次赠送的总金额。
+func (s *PromoService) GrantTopupBonus(ctx context.Context, userID int64, amount money.Amount) (money.Amount, error) {
+	if !amount.IsPositive() {
+		return 0, nil
+	}
+
+	pending, err := s.promoRepo.ListPendingTopupUsagesForUpdate(ctx, userID)
+	if err != nil {
+		return 0, fmt.Errorf("list pending topup bonuses: %w", err)
+	}
+
+	now := time.Now()
+	granted := make(map[int64]struct{}, len(pending))
+	var total money.Amount
+	for i := range pending {
+		usage := &pending[i]
+		if usage.PromoCode == nil {
+			continue
+		}
+		if _, ok := granted[usage.PromoCodeID]; ok {
+			continue
+		}
+		bonus := usage.PromoCode.TopupBonusFor(amount)
+		if !bonus.IsPositive() {
+			continue
+		}
+		if err := s.userRepo.UpdateBalance(ctx, userID, bonus, BalanceLedgerSource{
+			Type:        BalanceLedgerSourcePromoTopupBonus,
+			ReferenceID: strconv.FormatInt(usage.ID, 10),
+		}); err != nil {
+			return 0, fmt.Errorf("grant topup bonus: %w", err)
+		}
+		if err := s.promoRepo.MarkTopupBonusGranted(ctx, usage.ID, bonus, now); err != nil {
+			return 0, fmt.Errorf("mark topup bonus granted: %w", err)
+		}
+		granted[usage.PromoCodeID] = struct{}{}
+		total += bonus
+	}
+	return total, nil
+}
+
+// ListUserUsages 获取用户已使用的优惠码（含充值赠送结算状态）
+func (s *PromoService) ListUserUsages(ctx context.Context, userID int64) ([]PromoCodeUsage, error) {
+	return s.promoRepo.ListUsagesByUser(ctx, userID)
+}
+
+func (s *PromoService) invalidatePromoCaches(ctx context.Context, userID int64, bonusAmount money.Amount) {
+	if bonusAmount.IsZero() || s.authCacheInvalidator == nil {
 		return
 	}
 	s.authCacheInvalidator.InvalidateAuthCacheByUserID(ctx, userID)
@@ -215,6 +349,19 @@ func (s *PromoService) Create(ctx context.Context, input *CreatePromoCodeInput)
 		Status:      PromoCodeStatusActive,
 		ExpiresAt:   input.ExpiresAt,
 		Notes:       input.Notes,
+
+		TopupBonusPercent:   input.TopupBonusPercent,
+		TopupBonusMax:       input.TopupBonusMax,
+		MinTopupAmount:      input.MinTopupAmount,
+		PerUserLimit:        input.PerUserLimit,
+		AllowedEmailDomains: normalizeEmailDomains(input.AllowedEmailDomains),
+		RequiredAttributes:  input.RequiredAttributes,
+		Stackable:           input.Stackable,
+		TrialGroupID:        input.TrialGroupID,
+		TrialDays:           input.TrialDays,
+	}
+	if err := s.validateTrial(ctx, promoCode); err != nil {
+		return nil, err
 	}
 
 	if err := s.promoRepo.Create(ctx, promoCode); err != nil {
@@ -224,6 +371,45 @@ func (s *PromoService) Create(ctx context.Context, input *CreatePromoCodeInput)
 	return promoCode, nil
 }
 
+// validateTrial 校验试用订阅配置：分组必须为订阅类型且天数为正
+func (s *PromoService) validateTrial(ctx context.Context, promoCode *PromoCode) error {
+	if promoCode.TrialGroupID == nil {
+		return nil
+	}
+	if promoCode.TrialDays <= 0 || promoCode.TrialDays > MaxValidityDays {
+		return infraerrors.BadRequest("PROMO_CODE_TRIAL_INVALID", "trial days must be between 1 and the maximum validity days")
+	}
+	if s.subscriptionService == nil {
+		return nil
+	}
+	group, err := s.subscriptionService.groupRepo.GetByID(ctx, *promoCode.TrialGroupID)
+	if err != nil {
+		return err
+	}
+	if !group.IsSubscriptionType() {
+		return ErrGroupNotSubscriptionType
+	}
+	return nil
+}
+
+// normalizeEmailDomains 去除空白与 @ 前缀、转为小写并去重
+func normalizeEmailDomains(domains []string) []string {
+	out := make([]string, 0, len(domains))
+	seen := make(map[string]struct{}, len(domains))
+	for _, d := range domains {
+		d = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(d), "@"))
+		if d == "" {
+			continue
+		}
+		if _, ok := seen[d]; ok {
+			continue
+		}
+		seen[d] = struct{}{}
+		out = append(out, d)
+	}
+	return out
+}
+
 // GetByID 根据ID获取优惠码
 func (s *PromoService) GetByID(ctx context.Context, id int64) (*PromoCode, error) {
 	code, err := s.promoRepo.GetByID(ctx, id)
@@ -258,6 +444,41 @@ func (s *PromoService) Update(ctx context.Context, id int64, input *UpdatePromoC
 	if input.Notes != nil {
 		promoCode.Notes = *input.Notes
 	}
+	if input.TopupBonusPercent != nil {
+		promoCode.TopupBonusPercent = *input.TopupBonusPercent
+	}
+	if input.TopupBonusMax != nil {
+		promoCode.TopupBonusMax = *input.TopupBonusMax
+	}
+	if input.MinTopupAmount != nil {
+		promoCode.MinTopupAmount = *input.MinTopupAmount
+	}
+	if input.PerUserLimit != nil {
+		promoCode.PerUserLimit = *input.PerUserLimit
+	}
+	if input.AllowedEmailDomains != nil {
+		promoCode.AllowedEmailDomains = normalizeEmailDomains(*input.AllowedEmailDomains)
+	}
+	if input.RequiredAttributes != nil {
+		promoCode.RequiredAttributes = *input.RequiredAttributes
+	}
+	if input.Stackable != nil {
+		promoCode.Stackable = *input.Stackable
+	}
+	if input.TrialGroupID != nil {
+		if *input.TrialGroupID == 0 {
+			promoCode.TrialGroupID = nil
+		} else {
+			groupID := *input.TrialGroupID
+			promoCode.TrialGroupID = &groupID
+		}
+	}
+	if input.TrialDays != nil {
+		promoCode.TrialDays = *input.TrialDays
+	}
+	if err := s.validateTrial(ctx, promoCode); err != nil {
+		return nil, err
+	}
 
 	if err := s.promoRepo.Update(ctx, promoCode); err != nil {
 		return nil, fmt.Errorf("update promo code: %w", err)
@@ -286,19 +507,19 @@ func (s *PromoService) ListUsages(ctx context.Context, promoCodeID int64, params
 
 // PromoCodeUsageStats 聚合后的优惠码使用统计（按用户和时间窗口粗粒度统计）。
 type PromoCodeUsageStats struct {
-	PromoCodeID      int64   `json:"promo_code_id"`
-	Code             string  `json:"code"`
-	BonusAmount      float64 `json:"bonus_amount"`
-	MaxUses          int     `json:"max_uses"`
-	UsedCount        int     `json:"used_count"`
-	TotalBonusAmount float64 `json:"total_bonus_amount"`
-	TotalUses        int64   `json:"total_uses"`
-	UniqueUsers      int64   `json:"unique_users"`
-	UsesToday        int64   `json:"uses_today"`
-	UsesLast7Days    int64   `json:"uses_last_7_days"`
-	UsesLast30Days   int64   `json:"uses_last_30_days"`
-	ActivatedUsers   int64   `json:"activated_users"`
-	ActivationRate   float64 `json:"activation_rate"`
+	PromoCodeID      int64        `json:"promo_code_id"`
+	Code             string       `json:"code"`
+	BonusAmount      money.Amount `json:"bonus_amount"`
+	MaxUses          int          `json:"max_uses"`
+	UsedCount        int          `json:"used_count"`
+	TotalBonusAmount money.Amount `json:"total_bonus_amount"`
+	TotalUses        int64        `json:"total_uses"`
+	UniqueUsers      int64        `json:"unique_users"`
+	UsesToday        int64        `json:"uses_today"`
+	UsesLast7Days    int64        `json:"uses_last_7_days"`
+	UsesLast30Days   int64        `json:"uses_last_30_days"`
+	ActivatedUsers   int64        `json:"activated_users"`
+	ActivationRate   float64      `json:"activation_rate"`
 }
 
 // GetUsageStats 计算指定优惠码的使用统计信息。
@@ -0,0 +1,331 @@
+//go:build unit
+
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
+	"github.com/stretchr/testify/require"
+)
+
+type promoRulesTestRepo struct {
+	nextID int64
+	codes  map[int64]*PromoCode
+	usages []PromoCodeUsage
+}
+
+func newPromoRulesTestRepo() *promoRulesTestRepo {
+	return &promoRulesTestRepo{nextID: 1, codes: make(map[int64]*PromoCode)}
+}
+
+func (r *promoRulesTestRepo) Create(_ context.Context, code *PromoCode) error {
+	code.ID = r.nextID
+	r.nextID++
+	copyCode := *code
+	r.codes[code.ID] = &copyCode
+	return nil
+}
+
+func (r *promoRulesTestRepo) GetByID(_ context.Context, id int64) (*PromoCode, error) {
+	code, ok := r.codes[id]
+	if !ok {
+		return nil, ErrPromoCodeNotFound
+	}
+	copyCode := *code
+	return &copyCode, nil
+}
+
+func (r *promoRulesTestRepo) GetByCode(ctx context.Context, code string) (*PromoCode, error) {
+	for id, c := range r.codes {
+		if strings.EqualFold(c.Code, code) {
+			return r.GetByID(ctx, id)
+		}
+	}
+	return nil, ErrPromoCodeNotFound
+}
+
+func (r *promoRulesTestRepo) GetByCodeForUpdate(ctx context.Context, code string) (*PromoCode, error) {
+	return r.GetByCode(ctx, code)
+}
+
+func (r *promoRulesTestRepo) Update(_ context.Context, code *PromoCode) error {
+	copyCode := *code
+	r.codes[code.ID] = &copyCode
+	return nil
+}
+
+func (r *promoRulesTestRepo) Delete(_ context.Context, id int64) error {
+	delete(r.codes, id)
+	return nil
+}
+
+func (r *promoRulesTestRepo) List(_ context.Context, _ pagination.PaginationParams) ([]PromoCode, *pagination.PaginationResult, error) {
+	return nil, nil, nil
+}
+
+func (r *promoRulesTestRepo) ListWithFilters(_ context.Context, _ pagination.PaginationParams, _, _ string) ([]PromoCode, *pagination.PaginationResult, error) {
+	return nil, nil, nil
+}
+
+func (r *promoRulesTestRepo) CreateUsage(_ context.Context, usage *PromoCodeUsage) error {
+	usage.ID = int64(len(r.usages) + 1)
+	r.usages = append(r.usages, *usage)
+	return nil
+}
+
+func (r *promoRulesTestRepo) CountUsagesByPromoCodeAndUser(_ context.Context, promoCodeID, userID int64) (int, error) {
+	count := 0
+	for _, u := range r.usages {
+		if u.PromoCodeID == promoCodeID && u.UserID == userID {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *promoRulesTestRepo) withCode(u PromoCodeUsage) PromoCodeUsage {
+	if code, ok := r.codes[u.PromoCodeID]; ok {
+		copyCode := *code
+		u.PromoCode = &copyCode
+	}
+	return u
+}
+
+func (r *promoRulesTestRepo) ListUsagesByUser(_ context.Context, userID int64) ([]PromoCodeUsage, error) {
+	var out []PromoCodeUsage
+	for _, u := range r.usages {
+		if u.UserID == userID {
+			out = append(out, r.withCode(u))
+		}
+	}
+	return out, nil
+}
+
+func (r *promoRulesTestRepo) ListPendingTopupUsagesForUpdate(_ context.Context, userID int64) ([]PromoCodeUsage, error) {
+	var out []PromoCodeUsage
+	for _, u := range r.usages {
+		if u.UserID == userID && u.TopupBonusStatus == PromoTopupBonusStatusPending {
+			out = append(out, r.withCode(u))
+		}
+	}
+	return out, nil
+}
+
+func (r *promoRulesTestRepo) MarkTopupBonusGranted(_ context.Context, usageID int64, amount money.Amount, grantedAt time.Time) error {
+	for i := range r.usages {
+		if r.usages[i].ID == usageID {
+			r.usages[i].TopupBonusStatus = PromoTopupBonusStatusGranted
+			r.usages[i].TopupBonusAmount = amount
+			r.usages[i].TopupGrantedAt = &grantedAt
+		}
+	}
+	return nil
+}
+
+func (r *promoRulesTestRepo) GetLatestUsageByUser(_ context.Context, _ int64) (*PromoCodeUsage, error) {
+	return nil, nil
+}
+
+func (r *promoRulesTestRepo) ListUsagesByPromoCode(_ context.Context, _ int64, _ pagination.PaginationParams) ([]PromoCodeUsage, *pagination.PaginationResult, error) {
+	return nil, nil, nil
+}
+
+func (r *promoRulesTestRepo) IncrementUsedCount(_ context.Context, id int64) error {
+	if code, ok := r.codes[id]; ok {
+		code.UsedCount++
+	}
+	return nil
+}
+
+type promoRulesTestEnv struct {
+	promo  *PromoService
+	redeem *RedeemService
+	repo   *promoRulesTestRepo
+	users  *checkinTestUserRepo
+	codes  *checkinTestRedeemRepo
+}
+
+func newPromoRulesTestEnv(t *testing.T, users map[int64]*User) *promoRulesTestEnv {
+	t.Helper()
+	entClient := newPromoStatsTestEntClient(t)
+	repo := newPromoRulesTestRepo()
+	userRepo := newCheckinTestUserRepo(users)
+	redeemRepo := newCheckinTestRedeemRepo()
+
+	promo := NewPromoService(repo, userRepo, nil, nil, nil, entClient, nil)
+	redeem := NewRedeemService(redeemRepo, userRepo, nil, nil, nil, nil, entClient, nil)
+	redeem.SetPromoService(promo)
+	return &promoRulesTestEnv{promo: promo, redeem: redeem, repo: repo, users: userRepo, codes: redeemRepo}
+}
+
+func (e *promoRulesTestEnv) createCode(t *testing.T, input *CreatePromoCodeInput) *PromoCode {
+	t.Helper()
+	code, err := e.promo.Create(context.Background(), input)
+	require.NoError(t, err)
+	return code
+}
+
+// topup 模拟支付订单完成后的付费充值
+func (e *promoRulesTestEnv) topup(t *testing.T, userID int64, amount money.Amount) {
+	t.Helper()
+	code := &RedeemCode{
+		Code:     PaymentRedeemCodePrefix + time.Now().Format("150405.000000000"),
+		Type:     RedeemTypeBalance,
+		Value:    amount,
+		Status:   StatusUnused,
+		Category: PaymentRedeemCategory,
+	}
+	_, err := e.redeem.FulfillCode(context.Background(), code, userID)
+	require.NoError(t, err)
+}
+
+// redeemBalanceCode 兑换已创建的余额兑换码（积分商城、后台生成或外部支付对接）
+func (e *promoRulesTestEnv) redeemBalanceCode(t *testing.T, userID int64, code string, category string, amount money.Amount) {
+	t.Helper()
+	redeemCode := &RedeemCode{Code: code, Type: RedeemTypeBalance, Value: amount, Status: StatusUnused, Category: category}
+	require.NoError(t, e.codes.Create(context.Background(), redeemCode))
+	_, err := e.redeem.Redeem(context.Background(), userID, code)
+	require.NoError(t, err)
+}
+
+func (e *promoRulesTestEnv) balance(t *testing.T, userID int64) money.Amount {
+	t.Helper()
+	user, err := e.users.GetByID(context.Background(), userID)
+	require.NoError(t, err)
+	return user.Balance
+}
+
+func TestPromoApplyPerUserLimitAndEmailDomain(t *testing.T) {
+	ctx := context.Background()
+	env := newPromoRulesTestEnv(t, map[int64]*User{
+		1: {ID: 1, Email: "alice@other.com"},
+		2: {ID: 2, Email: "bob@Example.com"},
+	})
+	env.createCode(t, &CreatePromoCodeInput{
+		Code:                "welcome",
+		BonusAmount:         1 * money.USD,
+		PerUserLimit:        2,
+		AllowedEmailDomains: []string{" @EXAMPLE.com", "example.com", ""},
+	})
+	require.Equal(t, []string{"example.com"}, env.repo.codes[1].AllowedEmailDomains)
+
+	_, err := env.promo.ApplyPromoCode(ctx, 1, "WELCOME")
+	require.ErrorIs(t, err, ErrPromoCodeNotEligible)
+
+	for i := 0; i < 2; i++ {
+		usage, err := env.promo.ApplyPromoCode(ctx, 2, "welcome")
+		require.NoError(t, err)
+		require.Equal(t, PromoTopupBonusStatusNone, usage.TopupBonusStatus)
+	}
+	_, err = env.promo.ApplyPromoCode(ctx, 2, "welcome")
+	require.ErrorIs(t, err, ErrPromoCodeAlreadyUsed)
+
+	require.Equal(t, 2*money.USD, env.balance(t, 2))
+	require.Equal(t, 2, env.repo.codes[1].UsedCount)
+
+	// 要求用户属性但未配置属性服务时视为不满足条件
+	env.createCode(t, &CreatePromoCodeInput{Code: "VIP", PerUserLimit: 1, RequiredAttributes: map[string]string{"tier": "vip"}})
+	_, err = env.promo.ApplyPromoCode(ctx, 2, "VIP")
+	require.ErrorIs(t, err, ErrPromoCodeNotEligible)
+}
+
+func TestPromoTopupBonusOnFirstQualifyingTopup(t *testing.T) {
+	ctx := context.Background()
+	env := newPromoRulesTestEnv(t, map[int64]*User{1: {ID: 1, Email: "a@example.com"}})
+	env.createCode(t, &CreatePromoCodeInput{
+		Code:              "FIRST10",
+		PerUserLimit:      1,
+		TopupBonusPercent: 10,
+		TopupBonusMax:     3 * money.USD,
+		MinTopupAmount:    20 * money.USD,
+	})
+
+	usage, err := env.promo.ApplyPromoCode(ctx, 1, "FIRST10")
+	require.NoError(t, err)
+	require.Equal(t, PromoTopupBonusStatusPending, usage.TopupBonusStatus)
+	require.Zero(t, env.balance(t, 1))
+
+	env.topup(t, 1, 10*money.USD) // 低于最低充值金额，继续等待
+	require.Equal(t, 10*money.USD, env.balance(t, 1))
+	require.Equal(t, PromoTopupBonusStatusPending, env.repo.usages[0].TopupBonusStatus)
+
+	env.topup(t, 1, 50*money.USD) // 10% 为 5，封顶 3
+	require.Equal(t, 63*money.USD, env.balance(t, 1))
+	require.Equal(t, PromoTopupBonusStatusGranted, env.repo.usages[0].TopupBonusStatus)
+	require.Equal(t, 3*money.USD, env.repo.usages[0].TopupBonusAmount)
+
+	env.topup(t, 1, 50*money.USD)
+	require.Equal(t, 113*money.USD, env.balance(t, 1))
+}
+
+func TestPromoTopupBonusIgnoresUnpaidBalanceCodes(t *testing.T) {
+	ctx := context.Background()
+	env := newPromoRulesTestEnv(t, map[int64]*User{1: {ID: 1, Email: "a@example.com"}})
+	env.createCode(t, &CreatePromoCodeInput{Code: "FIRST10", PerUserLimit: 1, TopupBonusPercent: 10})
+
+	_, err := env.promo.ApplyPromoCode(ctx, 1, "FIRST10")
+	require.NoError(t, err)
+
+	// 积分商城与后台生成的余额兑换码不算充值，不消耗待结算的赠送
+	env.redeemBalanceCode(t, 1, "SHOP-1", pointsRedeemCodeCategory, 20*money.USD)
+	env.redeemBalanceCode(t, 1, "ADMIN-1", "", 30*money.USD)
+	require.Equal(t, 50*money.USD, env.balance(t, 1))
+	require.Equal(t, PromoTopupBonusStatusPending, env.repo.usages[0].TopupBonusStatus)
+
+	env.topup(t, 1, 40*money.USD)
+	require.Equal(t, 94*money.USD, env.balance(t, 1))
+	require.Equal(t, PromoTopupBonusStatusGranted, env.repo.usages[0].TopupBonusStatus)
+}
+
+func TestPromoTopupBonusCountsExternalPaymentCodes(t *testing.T) {
+	ctx := context.Background()
+	env := newPromoRulesTestEnv(t, map[int64]*User{1: {ID: 1, Email: "a@example.com"}})
+	env.createCode(t, &CreatePromoCodeInput{Code: "FIRST10", PerUserLimit: 1, TopupBonusPercent: 10})
+
+	_, err := env.promo.ApplyPromoCode(ctx, 1, "FIRST10")
+	require.NoError(t, err)
+
+	// 外部支付对接（create-and-redeem）创建的兑换码没有 PAY- 前缀，但带 payment 分类，算作付费充值
+	env.redeemBalanceCode(t, 1, "s2p_cm1234567890", PaymentRedeemCategory, 20*money.USD)
+	require.Equal(t, 22*money.USD, env.balance(t, 1))
+	require.Equal(t, PromoTopupBonusStatusGranted, env.repo.usages[0].TopupBonusStatus)
+}
+
+func TestPromoStackingRules(t *testing.T) {
+	ctx := context.Background()
+	env := newPromoRulesTestEnv(t, map[int64]*User{
+		1: {ID: 1, Email: "a@example.com"},
+		2: {ID: 2, Email: "b@example.com"},
+	})
+	env.createCode(t, &CreatePromoCodeInput{Code: "SOLO", PerUserLimit: 1, TopupBonusPercent: 50})
+	env.createCode(t, &CreatePromoCodeInput{Code: "STACK-A", PerUserLimit: 1, TopupBonusPercent: 10, Stackable: true})
+	env.createCode(t, &CreatePromoCodeInput{Code: "STACK-B", PerUserLimit: 1, TopupBonusPercent: 10, Stackable: true})
+	env.createCode(t, &CreatePromoCodeInput{Code: "FLAT", PerUserLimit: 1, BonusAmount: 1 * money.USD})
+
+	_, err := env.promo.ApplyPromoCode(ctx, 1, "SOLO")
+	require.NoError(t, err)
+	_, err = env.promo.ApplyPromoCode(ctx, 1, "STACK-A")
+	require.ErrorIs(t, err, ErrPromoCodeNotStackable)
+	// 不含充值赠送的优惠码不参与叠加判断
+	_, err = env.promo.ApplyPromoCode(ctx, 1, "FLAT")
+	require.NoError(t, err)
+
+	_, err = env.promo.ApplyPromoCode(ctx, 2, "STACK-A")
+	require.NoError(t, err)
+	_, err = env.promo.ApplyPromoCode(ctx, 2, "STACK-B")
+	require.NoError(t, err)
+	_, err = env.promo.ApplyPromoCode(ctx, 2, "SOLO")
+	require.ErrorIs(t, err, ErrPromoCodeNotStackable)
+
+	env.topup(t, 2, 100*money.USD)
+	require.Equal(t, 120*money.USD, env.balance(t, 2))
+
+	// 充值赠送结算后不再阻止使用不可叠加的优惠码
+	_, err = env.promo.ApplyPromoCode(ctx, 2, "SOLO")
+	require.NoError(t, err)
+}
@@ -11,6 +11,7 @@ import (
 
 	dbent "github.com/Wei-Shaw/sub2api/ent"
 	"github.com/Wei-Shaw/sub2api/ent/enttest"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
 	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
 	"github.com/stretchr/testify/require"
 
@@ -47,9 +48,18 @@ func (r *promoStatsTestRepo) ListWithFilters(_ context.Context, _ pagination.Pag
 	return nil, nil, nil
 }
 func (r *promoStatsTestRepo) CreateUsage(_ context.Context, _ *PromoCodeUsage) error { return nil }
-func (r *promoStatsTestRepo) GetUsageByPromoCodeAndUser(_ context.Context, _, _ int64) (*PromoCodeUsage, error) {
+func (r *promoStatsTestRepo) CountUsagesByPromoCodeAndUser(_ context.Context, _, _ int64) (int, error) {
+	return 0, nil
+}
+func (r *promoStatsTestRepo) ListUsagesByUser(_ context.Context, _ int64) ([]PromoCodeUsage, error) {
+	return nil, nil
+}
+func (r *promoStatsTestRepo) ListPendingTopupUsagesForUpdate(_ context.Context, _ int64) ([]PromoCodeUsage, error) {
 	return nil, nil
 }
+func (r *promoStatsTestRepo) MarkTopupBonusGranted(_ context.Context, _ int64, _ money.Amount, _ time.Time) error {
+	return nil
+}
 func (r *promoStatsTestRepo) GetLatestUsageByUser(_ context.Context, _ int64) (*PromoCodeUsage, error) {
 	if len(r.usages) == 0 {
 		return nil, nil
@@ -119,7 +129,7 @@ func TestPromoServiceGetUsageStatsAggregatesCorrectly(t *testing.T) {
 	promoCode := &PromoCode{
 		ID:          codeID,
 		Code:        "PROMO-TEST",
-		BonusAmount: 10,
+		BonusAmount: 10 * money.USD,
 		MaxUses:     0,
 		UsedCount:   3,
 	}
@@ -132,19 +142,19 @@ func TestPromoServiceGetUsageStatsAggregatesCorrectly(t *testing.T) {
 	repo := &promoStatsTestRepo{
 		code: promoCode,
 		usages: []PromoCodeUsage{
-			{PromoCodeID: codeID, UserID: 1, BonusAmount: 10, UsedAt: today},
-			{PromoCodeID: codeID, UserID: 2, BonusAmount: 10, UsedAt: sevenDaysAgo},
-			{PromoCodeID: codeID, UserID: 1, BonusAmount: 10, UsedAt: thirtyDaysAgo},
+			{PromoCodeID: codeID, UserID: 1, BonusAmount: 10 * money.USD, UsedAt: today},
+			{PromoCodeID: codeID, UserID: 2, BonusAmount: 10 * money.USD, UsedAt: sevenDaysAgo},
+			{PromoCodeID: codeID, UserID: 1, BonusAmount: 10 * money.USD, UsedAt: thirtyDaysAgo},
 		},
 	}
 
-	svc := NewPromoService(repo, nil, nil, entClient, nil)
+	svc := NewPromoService(repo, nil, nil, nil, nil, entClient, nil)
 	stats, err := svc.GetUsageStats(context.Background(), codeID)
 	require.NoError(t, err)
 	require.NotNil(t, stats)
 
 	require.Equal(t, int64(3), stats.TotalUses)
-	require.InDelta(t, 30.0, stats.TotalBonusAmount, 1e-9)
+	require.Equal(t, 30*money.USD, stats.TotalBonusAmount)
 	require.Equal(t, int64(2), stats.UniqueUsers)
 	require.GreaterOrEqual(t, stats.UsesToday, int64(1))
 	require.GreaterOrEqual(t, stats.UsesLast7Days, int64(2))
@@ -0,0 +1,183 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	infraerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
+)
+
+var ErrRecoveryCodeInvalid = infraerrors.BadRequest("RECOVERY_CODE_INVALID", "invalid or already used recovery code")
+
+const (
+	recoveryCodeCount = 10
+	// recoveryCodeLength 去掉分隔符后的字符数（base32，每字符 5 bit，共 50 bit 熵）
+	recoveryCodeLength = 10
+)
+
+// RecoveryCode 已保存的恢复码（仅含加密后的摘要）
+type RecoveryCode struct {
+	ID                int64
+	UserID            int64
+	CodeHashEncrypted string
+	UsedAt            *time.Time
+	CreatedAt         time.Time
+}
+
+// RecoveryCodeStatus 恢复码概况
+type RecoveryCodeStatus struct {
+	Total       int
+	Remaining   int
+	GeneratedAt *time.Time
+}
+
+// RecoveryCodeRepository 恢复码存储
+type RecoveryCodeRepository interface {
+	// Replace 删除用户已有的恢复码并写入新的一批
+	Replace(ctx context.Context, userID int64, codeHashesEncrypted []string) error
+	ListByUser(ctx context.Context, userID int64) ([]RecoveryCode, error)
+	// MarkUsed 将未使用的恢复码标记为已用；已被使用（并发消费）时返回 ErrRecoveryCodeInvalid
+	MarkUsed(ctx context.Context, id int64, usedAt time.Time) error
+}
+
+// RecoveryCodeService 双因素恢复码：生成时仅返回一次明文，库中保存经 SecretEncryptor 加密的 SHA-256 摘要
+type RecoveryCodeService struct {
+	repo        RecoveryCodeRepository
+	userRepo    UserRepository
+	encryptor   SecretEncryptor
+	totpCache   TotpCache
+	totpService *TotpService
+}
+
+// NewRecoveryCodeService 创建恢复码服务
+func NewRecoveryCodeService(repo RecoveryCodeRepository, userRepo UserRepository, encryptor SecretEncryptor, totpCache TotpCache, totpService *TotpService) *RecoveryCodeService {
+	return &RecoveryCodeService{
+		repo:        repo,
+		userRepo:    userRepo,
+		encryptor:   encryptor,
+		totpCache:   totpCache,
+		totpService: totpService,
+	}
+}
+
+// GetStatus 返回恢复码剩余数量
+func (s *RecoveryCodeService) GetStatus(ctx context.Context, userID int64) (*RecoveryCodeStatus, error) {
+	codes, err := s.repo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list recovery codes: %w", err)
+	}
+	status := &RecoveryCodeStatus{Total: len(codes)}
+	for i := range codes {
+		if codes[i].UsedAt == nil {
+			status.Remaining++
+		}
+		if status.GeneratedAt == nil {
+			status.GeneratedAt = &codes[i].CreatedAt
+		}
+	}
+	return status, nil
+}
+
+// Regenerate 重新生成恢复码并使旧码全部失效，需先通过邮箱验证码或密码再次确认身份
+func (s *RecoveryCodeService) Regenerate(ctx context.Context, userID int64, emailCode, password string) ([]string, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("get user: %w", err)
+	}
+	if err := s.totpService.VerifyIdentity(ctx, user, emailCode, password); err != nil {
+		return nil, err
+	}
+
+	codes := make([]string, 0, recoveryCodeCount)
+	encrypted := make([]string, 0, recoveryCodeCount)
+	for range recoveryCodeCount {
+		raw := strings.ToLower(rand.Text()[:recoveryCodeLength])
+		enc, err := s.encryptor.Encrypt(hashRecoveryCode(raw))
+		if err != nil {
+			return nil, fmt.Errorf("encrypt recovery code: %w", err)
+		}
+		codes = append(codes, raw[:recoveryCodeLength/2]+"-"+raw[recoveryCodeLength/2:])
+		encrypted = append(encrypted, enc)
+	}
+
+	if err := s.repo.Replace(ctx, userID, encrypted); err != nil {
+		return nil, fmt.Errorf("store recovery codes: %w", err)
+	}
+	return codes, nil
+}
+
+// Consume 校验并消费一个恢复码。与 TOTP 共用失败计数，防止交替尝试绕过限制。
+func (s *RecoveryCodeService) Consume(ctx context.Context, userID int64, code string) error {
+	attempts, err := s.totpCache.GetVerifyAttempts(ctx, userID)
+	if err == nil && attempts >= maxTotpAttempts {
+		return ErrTotpTooManyAttempts
+	}
+
+	normalized := normalizeRecoveryCode(code)
+	if len(normalized) != recoveryCodeLength {
+		return s.recordFailure(ctx, userID)
+	}
+	want := hashRecoveryCode(normalized)
+
+	codes, err := s.repo.ListByUser(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("list recovery codes: %w", err)
+	}
+	for i := range codes {
+		if codes[i].UsedAt != nil {
+			continue
+		}
+		got, err := s.encryptor.Decrypt(codes[i].CodeHashEncrypted)
+		if err != nil {
+			slog.Debug("recovery_code_decrypt_failed", "user_id", userID, "id", codes[i].ID, "error", err)
+			continue
+		}
+		if subtle.ConstantTimeCompare([]byte(got), []byte(want)) != 1 {
+			continue
+		}
+		if err := s.repo.MarkUsed(ctx, codes[i].ID, time.Now()); err != nil {
+			return err
+		}
+		_ = s.totpCache.ClearVerifyAttempts(ctx, userID)
+		return nil
+	}
+	return s.recordFailure(ctx, userID)
+}
+
+// Remaining 返回未使用的恢复码数量
+func (s *RecoveryCodeService) Remaining(ctx context.Context, userID int64) (int, error) {
+	status, err := s.GetStatus(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+	return status.Remaining, nil
+}
+
+func (s *RecoveryCodeService) recordFailure(ctx context.Context, userID int64) error {
+	_, _ = s.totpCache.IncrementVerifyAttempts(ctx, userID)
+	return ErrRecoveryCodeInvalid
+}
+
+// normalizeRecoveryCode 忽略大小写、空白与分隔符
+func normalizeRecoveryCode(code string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(code) {
+		if r == '-' || r == ' ' || r == '\t' {
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func hashRecoveryCode(normalized string) string {
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
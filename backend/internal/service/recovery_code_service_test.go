@@ -0,0 +1,175 @@
+//go:build unit
+
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/config"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// ---- fakes ----
+
+type recoveryCodeRepoFake struct {
+	codes  []RecoveryCode
+	nextID int64
+}
+
+func (r *recoveryCodeRepoFake) Replace(ctx context.Context, userID int64, hashes []string) error {
+	kept := r.codes[:0]
+	for _, c := range r.codes {
+		if c.UserID != userID {
+			kept = append(kept, c)
+		}
+	}
+	r.codes = kept
+	for _, h := range hashes {
+		r.nextID++
+		r.codes = append(r.codes, RecoveryCode{ID: r.nextID, UserID: userID, CodeHashEncrypted: h, CreatedAt: time.Now()})
+	}
+	return nil
+}
+
+func (r *recoveryCodeRepoFake) ListByUser(ctx context.Context, userID int64) ([]RecoveryCode, error) {
+	var out []RecoveryCode
+	for _, c := range r.codes {
+		if c.UserID == userID {
+			out = append(out, c)
+		}
+	}
+	return out, nil
+}
+
+func (r *recoveryCodeRepoFake) MarkUsed(ctx context.Context, id int64, usedAt time.Time) error {
+	for i := range r.codes {
+		if r.codes[i].ID == id && r.codes[i].UsedAt == nil {
+			r.codes[i].UsedAt = &usedAt
+			return nil
+		}
+	}
+	return ErrRecoveryCodeInvalid
+}
+
+// nonceEncryptorFake 模拟 AES-GCM：同一明文每次加密结果不同
+type nonceEncryptorFake struct{}
+
+func (nonceEncryptorFake) Encrypt(plaintext string) (string, error) {
+	return "enc:" + rand.Text()[:8] + ":" + plaintext, nil
+}
+
+func (nonceEncryptorFake) Decrypt(ciphertext string) (string, error) {
+	parts := strings.SplitN(ciphertext, ":", 3)
+	return parts[2], nil
+}
+
+type totpCacheFake struct {
+	TotpCache
+	attempts map[int64]int
+}
+
+func newTotpCacheFake() *totpCacheFake {
+	return &totpCacheFake{attempts: make(map[int64]int)}
+}
+
+func (c *totpCacheFake) IncrementVerifyAttempts(ctx context.Context, userID int64) (int, error) {
+	c.attempts[userID]++
+	return c.attempts[userID], nil
+}
+
+func (c *totpCacheFake) GetVerifyAttempts(ctx context.Context, userID int64) (int, error) {
+	return c.attempts[userID], nil
+}
+
+func (c *totpCacheFake) ClearVerifyAttempts(ctx context.Context, userID int64) error {
+	delete(c.attempts, userID)
+	return nil
+}
+
+func newSecurityTestUser(t *testing.T) *User {
+	hash, err := bcrypt.GenerateFromPassword([]byte("correct-password"), bcrypt.MinCost)
+	require.NoError(t, err)
+	return &User{ID: 7, Email: "alice@example.com", PasswordHash: string(hash), Role: RoleUser, Status: StatusActive}
+}
+
+func newRecoveryCodeTestService(t *testing.T) (*RecoveryCodeService, *recoveryCodeRepoFake, *totpCacheFake) {
+	users := newSSOUserRepoFake(newSecurityTestUser(t))
+	cache := newTotpCacheFake()
+	settings := NewSettingService(&settingRepoStub{values: map[string]string{}}, &config.Config{})
+	totp := NewTotpService(users, nonceEncryptorFake{}, cache, settings, nil, nil)
+	repo := &recoveryCodeRepoFake{}
+	return NewRecoveryCodeService(repo, users, nonceEncryptorFake{}, cache, totp), repo, cache
+}
+
+// ---- tests ----
+
+func TestRecoveryCodes_RegenerateRequiresPassword(t *testing.T) {
+	svc, _, _ := newRecoveryCodeTestService(t)
+
+	_, err := svc.Regenerate(context.Background(), 7, "", "")
+	require.ErrorIs(t, err, ErrPasswordRequired)
+	_, err = svc.Regenerate(context.Background(), 7, "", "wrong")
+	require.ErrorIs(t, err, ErrPasswordIncorrect)
+}
+
+func TestRecoveryCodes_StoredHashedAndEncrypted(t *testing.T) {
+	svc, repo, _ := newRecoveryCodeTestService(t)
+
+	codes, err := svc.Regenerate(context.Background(), 7, "", "correct-password")
+	require.NoError(t, err)
+	require.Len(t, codes, recoveryCodeCount)
+	require.Len(t, repo.codes, recoveryCodeCount)
+	for i, code := range codes {
+		require.Regexp(t, `^[a-z2-7]{5}-[a-z2-7]{5}$`, code)
+		require.NotContains(t, repo.codes[i].CodeHashEncrypted, normalizeRecoveryCode(code))
+		require.Contains(t, repo.codes[i].CodeHashEncrypted, hashRecoveryCode(normalizeRecoveryCode(code)))
+	}
+}
+
+func TestRecoveryCodes_ConsumeIsOneTime(t *testing.T) {
+	svc, _, _ := newRecoveryCodeTestService(t)
+	ctx := context.Background()
+
+	codes, err := svc.Regenerate(ctx, 7, "", "correct-password")
+	require.NoError(t, err)
+
+	// 大小写与分隔符不敏感
+	require.NoError(t, svc.Consume(ctx, 7, strings.ToUpper(strings.ReplaceAll(codes[3], "-", " "))))
+	require.ErrorIs(t, svc.Consume(ctx, 7, codes[3]), ErrRecoveryCodeInvalid)
+
+	remaining, err := svc.Remaining(ctx, 7)
+	require.NoError(t, err)
+	require.Equal(t, recoveryCodeCount-1, remaining)
+
+	// 其他用户不能使用
+	require.ErrorIs(t, svc.Consume(ctx, 8, codes[4]), ErrRecoveryCodeInvalid)
+}
+
+func TestRecoveryCodes_RegenerateInvalidatesOldCodes(t *testing.T) {
+	svc, _, _ := newRecoveryCodeTestService(t)
+	ctx := context.Background()
+
+	old, err := svc.Regenerate(ctx, 7, "", "correct-password")
+	require.NoError(t, err)
+	_, err = svc.Regenerate(ctx, 7, "", "correct-password")
+	require.NoError(t, err)
+
+	require.ErrorIs(t, svc.Consume(ctx, 7, old[0]), ErrRecoveryCodeInvalid)
+}
+
+func TestRecoveryCodes_SharesTotpAttemptLimit(t *testing.T) {
+	svc, _, cache := newRecoveryCodeTestService(t)
+	ctx := context.Background()
+
+	codes, err := svc.Regenerate(ctx, 7, "", "correct-password")
+	require.NoError(t, err)
+
+	cache.attempts[7] = maxTotpAttempts - 1
+	require.ErrorIs(t, svc.Consume(ctx, 7, "aaaaa-aaaaa"), ErrRecoveryCodeInvalid)
+	require.ErrorIs(t, svc.Consume(ctx, 7, codes[0]), ErrTotpTooManyAttempts)
+}
@@ -4,13 +4,15 @@ import (
 	"crypto/rand"
 	"encoding/hex"
 	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
 )
 
 type RedeemCode struct {
 	ID        int64
 	Code      string
 	Type      string
-	Value     float64
+	Value     money.Amount // 余额类为金额；并发、订阅天数等计数类按整数值存储
 	Status    string
 	UsedBy    *int64
 	UsedAt    *time.Time
@@ -33,6 +35,22 @@ func (r *RedeemCode) CanUse() bool {
 	return r.Status == StatusUnused
 }
 
+// IsPaidTopup 是否为付费充值的余额兑换码：内置支付订单（PAY-{订单号}）与外部支付对接
+// （create-and-redeem）创建的兑换码均带 payment 分类；积分商城、后台生成或赠送的余额兑换码均不算充值
+func (r *RedeemCode) IsPaidTopup() bool {
+	return r.Type == RedeemTypeBalance && r.Category == PaymentRedeemCategory
+}
+
+// CountValue 计数类兑换码（并发数、订阅天数）的整数值
+func (r *RedeemCode) CountValue() int {
+	return int(r.Value / money.USD)
+}
+
+// redeemCountValue 将计数值转换为 value 列的存储形式
+func redeemCountValue(n int) money.Amount {
+	return money.Amount(n) * money.USD
+}
+
 func GenerateRedeemCode() (string, error) {
 	b := make([]byte, 16)
 	if _, err := rand.Read(b); err != nil {
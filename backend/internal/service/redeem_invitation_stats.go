@@ -5,6 +5,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
 	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
 )
 
@@ -25,14 +26,14 @@ type InvitationRedeemGroupStat struct {
 // InvitationRedeemDetail describes a single subscription-type redeem code
 // used by a user who registered via the invitation.
 type InvitationRedeemDetail struct {
-	RedeemCodeID int64      `json:"redeem_code_id"`
-	Code         string     `json:"code"`
-	Type         string     `json:"type"`
-	Category     string     `json:"category"`
-	Value        float64    `json:"value"`
-	GroupID      *int64     `json:"group_id,omitempty"`
-	GroupName    string     `json:"group_name,omitempty"`
-	UsedAt       *time.Time `json:"used_at,omitempty"`
+	RedeemCodeID int64        `json:"redeem_code_id"`
+	Code         string       `json:"code"`
+	Type         string       `json:"type"`
+	Category     string       `json:"category"`
+	Value        money.Amount `json:"value"`
+	GroupID      *int64       `json:"group_id,omitempty"`
+	GroupName    string       `json:"group_name,omitempty"`
+	UsedAt       *time.Time   `json:"used_at,omitempty"`
 }
 
 // InvitationRedeemImpactStats captures how a specific invitation redeem code
@@ -7,6 +7,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
 	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
 	"github.com/stretchr/testify/require"
 )
@@ -89,7 +90,7 @@ func (r *invitationStatsTestRedeemRepo) ListByUserPaginated(_ context.Context, u
 	return pageItems, pageResult, nil
 }
 
-func (r *invitationStatsTestRedeemRepo) SumPositiveBalanceByUser(_ context.Context, _ int64) (float64, error) {
+func (r *invitationStatsTestRedeemRepo) SumPositiveBalanceByUser(_ context.Context, _ int64) (money.Amount, error) {
 	return 0, nil
 }
 
@@ -142,7 +143,7 @@ func TestGetInvitationImpactStats_AggregatesRedeems(t *testing.T) {
 			Code:     "SUB-1",
 			Type:     RedeemTypeSubscription,
 			Category: "starter",
-			Value:    1,
+			Value:    redeemCountValue(1),
 			UsedBy:   &userID,
 			UsedAt:   &now,
 		},
@@ -151,7 +152,7 @@ func TestGetInvitationImpactStats_AggregatesRedeems(t *testing.T) {
 			Code:     "SUB-2",
 			Type:     RedeemTypeSubscription,
 			Category: "pro",
-			Value:    2,
+			Value:    redeemCountValue(2),
 			UsedBy:   &userID,
 			GroupID:  &groupID,
 			Group:    &Group{ID: groupID, Name: "Pro Group"},
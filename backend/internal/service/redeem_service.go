@@ -14,6 +14,7 @@ import (
 
 	dbent "github.com/Wei-Shaw/sub2api/ent"
 	infraerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
 	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
 	timezonepkg "github.com/Wei-Shaw/sub2api/internal/pkg/timezone"
 )
@@ -62,22 +63,22 @@ type RedeemCodeRepository interface {
 	// codeType filter is optional - pass empty string to return all types.
 	ListByUserPaginated(ctx context.Context, userID int64, params pagination.PaginationParams, codeType string) ([]RedeemCode, *pagination.PaginationResult, error)
 	// SumPositiveBalanceByUser returns the total recharged amount (sum of positive balance values) for a user.
-	SumPositiveBalanceByUser(ctx context.Context, userID int64) (float64, error)
+	SumPositiveBalanceByUser(ctx context.Context, userID int64) (money.Amount, error)
 }
 
 // GenerateCodesRequest 生成兑换码请求
 type GenerateCodesRequest struct {
-	Count int     `json:"count"`
-	Value float64 `json:"value"`
-	Type  string  `json:"type"`
+	Count int          `json:"count"`
+	Value money.Amount `json:"value"`
+	Type  string       `json:"type"`
 }
 
 // RedeemCodeResponse 兑换码响应
 type RedeemCodeResponse struct {
-	Code      string    `json:"code"`
-	Value     float64   `json:"value"`
-	Status    string    `json:"status"`
-	CreatedAt time.Time `json:"created_at"`
+	Code      string       `json:"code"`
+	Value     money.Amount `json:"value"`
+	Status    string       `json:"status"`
+	CreatedAt time.Time    `json:"created_at"`
 }
 
 // DailyCheckinStatus 每日签到状态
@@ -108,6 +109,7 @@ type RedeemService struct {
 	entClient            *dbent.Client
 	authCacheInvalidator APIKeyAuthCacheInvalidator
 	distributorService   *DistributorService
+	promoService         *PromoService
 }
 
 // NewRedeemService 创建兑换码服务实例
@@ -137,6 +139,11 @@ func (s *RedeemService) SetDistributorService(distributorService *DistributorSer
 	s.distributorService = distributorService
 }
 
+// SetPromoService 设置优惠码服务，余额充值时结算优惠码的首次充值赠送
+func (s *RedeemService) SetPromoService(promoService *PromoService) {
+	s.promoService = promoService
+}
+
 // GenerateRandomCode 生成随机兑换码
 func (s *RedeemService) GenerateRandomCode() (string, error) {
 	// 生成16字节随机数据
@@ -166,7 +173,7 @@ func (s *RedeemService) GenerateCodes(ctx context.Context, req GenerateCodesRequ
 	}
 
 	// 邀请码类型不需要数值，其他类型需要
-	if req.Type != RedeemTypeInvitation && req.Value <= 0 {
+	if req.Type != RedeemTypeInvitation && !req.Value.IsPositive() {
 		return nil, errors.New("value must be greater than 0")
 	}
 
@@ -222,7 +229,7 @@ func (s *RedeemService) CreateCode(ctx context.Context, code *RedeemCode) error
 	if code.Type == "" {
 		code.Type = RedeemTypeBalance
 	}
-	if code.Type != RedeemTypeInvitation && code.Value <= 0 {
+	if code.Type != RedeemTypeInvitation && !code.Value.IsPositive() {
 		return errors.New("value must be greater than 0")
 	}
 	if code.Status == "" {
@@ -293,7 +300,51 @@ func (s *RedeemService) Redeem(ctx context.Context, userID int64, code string) (
 	if err := s.checkRedeemRateLimit(ctx, userID); err != nil {
 		return nil, err
 	}
+	return s.redeem(ctx, userID, code, true)
+}
+
+// FulfillCode 创建（如不存在）指定兑换码并兑换给用户，供系统发放权益（如支付订单）使用。
+// 以兑换码唯一性保证幂等：兑换码已被同一用户使用时直接返回，不会重复发放；不受兑换失败次数限流影响。
+func (s *RedeemService) FulfillCode(ctx context.Context, code *RedeemCode, userID int64) (*RedeemCode, error) {
+	if code == nil {
+		return nil, errors.New("redeem code is required")
+	}
+	existing, err := s.redeemRepo.GetByCode(ctx, code.Code)
+	if errors.Is(err, ErrRedeemCodeNotFound) {
+		if createErr := s.CreateCode(ctx, code); createErr != nil {
+			// 并发创建：以已存在的兑换码为准
+			existing, err = s.redeemRepo.GetByCode(ctx, code.Code)
+			if err != nil {
+				return nil, createErr
+			}
+		} else {
+			existing = code
+		}
+	} else if err != nil {
+		return nil, fmt.Errorf("get redeem code: %w", err)
+	}
 
+	if existing.CanUse() {
+		redeemed, err := s.redeem(ctx, userID, existing.Code, false)
+		if err == nil {
+			return redeemed, nil
+		}
+		if !errors.Is(err, ErrRedeemCodeUsed) {
+			return nil, err
+		}
+		if existing, err = s.redeemRepo.GetByCode(ctx, code.Code); err != nil {
+			return nil, fmt.Errorf("get redeem code: %w", err)
+		}
+	}
+
+	if existing.UsedBy != nil && *existing.UsedBy == userID {
+		return existing, nil
+	}
+	return nil, infraerrors.Conflict("REDEEM_CODE_CONFLICT", "redeem code already used by another user")
+}
+
+// redeem 执行兑换；countErrors 为 true 时失败的兑换计入用户限流次数
+func (s *RedeemService) redeem(ctx context.Context, userID int64, code string, countErrors bool) (*RedeemCode, error) {
 	// 获取分布式锁，防止同一兑换码并发使用
 	if !s.acquireRedeemLock(ctx, code) {
 		return nil, ErrRedeemCodeLocked
@@ -304,7 +355,9 @@ func (s *RedeemService) Redeem(ctx context.Context, userID int64, code string) (
 	redeemCode, err := s.redeemRepo.GetByCode(ctx, code)
 	if err != nil {
 		if errors.Is(err, ErrRedeemCodeNotFound) {
-			s.incrementRedeemErrorCount(ctx, userID)
+			if countErrors {
+				s.incrementRedeemErrorCount(ctx, userID)
+			}
 			return nil, ErrRedeemCodeNotFound
 		}
 		return nil, fmt.Errorf("get redeem code: %w", err)
@@ -312,7 +365,9 @@ func (s *RedeemService) Redeem(ctx context.Context, userID int64, code string) (
 
 	// 检查兑换码状态
 	if !redeemCode.CanUse() {
-		s.incrementRedeemErrorCount(ctx, userID)
+		if countErrors {
+			s.incrementRedeemErrorCount(ctx, userID)
+		}
 		return nil, ErrRedeemCodeUsed
 	}
 
@@ -351,13 +406,23 @@ func (s *RedeemService) Redeem(ctx context.Context, userID int64, code string) (
 	switch redeemCode.Type {
 	case RedeemTypeBalance:
 		// 增加用户余额
-		if err := s.userRepo.UpdateBalance(txCtx, userID, redeemCode.Value); err != nil {
+		if err := s.userRepo.UpdateBalance(txCtx, userID, redeemCode.Value, BalanceLedgerSource{
+			Type:        BalanceLedgerSourceRedeem,
+			ReferenceID: strconv.FormatInt(redeemCode.ID, 10),
+		}); err != nil {
 			return nil, fmt.Errorf("update user balance: %w", err)
 		}
+		// 结算优惠码首次充值赠送（与充值同一事务）；仅支付订单的付费充值参与，
+		// 积分商城、后台生成或赠送的余额兑换码不会消耗待结算的赠送
+		if s.promoService != nil && redeemCode.IsPaidTopup() {
+			if _, err := s.promoService.GrantTopupBonus(txCtx, userID, redeemCode.Value); err != nil {
+				return nil, err
+			}
+		}
 
 	case RedeemTypeConcurrency:
 		// 增加用户并发数
-		if err := s.userRepo.UpdateConcurrency(txCtx, userID, int(redeemCode.Value)); err != nil {
+		if err := s.userRepo.UpdateConcurrency(txCtx, userID, redeemCode.CountValue()); err != nil {
 			return nil, fmt.Errorf("update user concurrency: %w", err)
 		}
 
@@ -541,7 +606,7 @@ func (s *RedeemService) GetDailyCheckinStatus(ctx context.Context, userID int64)
 
 	var rewardAmount *float64
 	if checkedInToday {
-		value := redeemRecord.Value
+		value := redeemRecord.Value.Float64()
 		rewardAmount = &value
 	}
 
@@ -603,13 +668,13 @@ func (s *RedeemService) DailyCheckin(ctx context.Context, userID int64) (*DailyC
 
 	return &DailyCheckinResult{
 		Message:      "Daily check-in successful",
-		RewardAmount: rewardAmount,
-		NewBalance:   updatedUser.Balance,
+		RewardAmount: rewardAmount.Float64(),
+		NewBalance:   updatedUser.Balance.Float64(),
 		CheckedInAt:  now,
 	}, nil
 }
 
-func (s *RedeemService) applyDailyCheckin(ctx context.Context, userID int64, rewardAmount float64, redeemRecord *RedeemCode) error {
+func (s *RedeemService) applyDailyCheckin(ctx context.Context, userID int64, rewardAmount money.Amount, redeemRecord *RedeemCode) error {
 	apply := func(execCtx context.Context) error {
 		if err := s.redeemRepo.Create(execCtx, redeemRecord); err != nil {
 			if isUniqueConstraintError(err) {
@@ -617,7 +682,10 @@ func (s *RedeemService) applyDailyCheckin(ctx context.Context, userID int64, rew
 			}
 			return fmt.Errorf("create daily check-in record: %w", err)
 		}
-		if err := s.userRepo.UpdateBalance(execCtx, userID, rewardAmount); err != nil {
+		if err := s.userRepo.UpdateBalance(execCtx, userID, rewardAmount, BalanceLedgerSource{
+			Type:        BalanceLedgerSourceCheckin,
+			ReferenceID: strconv.FormatInt(redeemRecord.ID, 10),
+		}); err != nil {
 			return fmt.Errorf("update user balance: %w", err)
 		}
 		return nil
@@ -678,10 +746,10 @@ func buildDailyCheckinCode(userID int64, now time.Time) string {
 	return fmt.Sprintf("%s-%s-%s", dailyCheckinCodePrefix, userPart, datePart)
 }
 
-func randomRewardInRange(minAmount, maxAmount float64) (float64, error) {
+func randomRewardInRange(minAmount, maxAmount float64) (money.Amount, error) {
 	minCents, maxCents := rewardBoundsInCents(minAmount, maxAmount)
 	if maxCents <= minCents {
-		return float64(minCents) / 100, nil
+		return money.Amount(minCents) * money.USD / 100, nil
 	}
 
 	delta := maxCents - minCents + 1
@@ -691,7 +759,7 @@ func randomRewardInRange(minAmount, maxAmount float64) (float64, error) {
 	}
 
 	rewardCents := minCents + randomOffset.Int64()
-	return float64(rewardCents) / 100, nil
+	return money.Amount(rewardCents) * money.USD / 100, nil
 }
 
 func rewardBoundsInCents(minAmount, maxAmount float64) (int64, int64) {
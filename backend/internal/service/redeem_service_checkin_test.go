@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/Wei-Shaw/sub2api/internal/config"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
 	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
 	"github.com/stretchr/testify/require"
 )
@@ -22,7 +23,7 @@ func TestRedeemServiceDailyCheckinSuccess(t *testing.T) {
 		1: {
 			ID:      1,
 			Role:    RoleUser,
-			Balance: 10,
+			Balance: 10 * money.USD,
 		},
 	})
 	settingService := newCheckinTestSettingService(true, 6.23, 8.66)
@@ -37,7 +38,7 @@ func TestRedeemServiceDailyCheckinSuccess(t *testing.T) {
 
 	updatedUser, err := userRepo.GetByID(context.Background(), 1)
 	require.NoError(t, err)
-	require.InDelta(t, result.NewBalance, updatedUser.Balance, 1e-9)
+	require.InDelta(t, result.NewBalance, updatedUser.Balance.Float64(), 1e-9)
 }
 
 func TestRedeemServiceDailyCheckinDuplicateSameDay(t *testing.T) {
@@ -48,7 +49,7 @@ func TestRedeemServiceDailyCheckinDuplicateSameDay(t *testing.T) {
 		1: {
 			ID:      1,
 			Role:    RoleUser,
-			Balance: 5,
+			Balance: 5 * money.USD,
 		},
 	})
 	settingService := newCheckinTestSettingService(true, 1, 3)
@@ -63,7 +64,7 @@ func TestRedeemServiceDailyCheckinDuplicateSameDay(t *testing.T) {
 
 	updatedUser, err := userRepo.GetByID(context.Background(), 1)
 	require.NoError(t, err)
-	require.InDelta(t, first.NewBalance, updatedUser.Balance, 1e-9)
+	require.InDelta(t, first.NewBalance, updatedUser.Balance.Float64(), 1e-9)
 }
 
 func TestRedeemServiceDailyCheckinWhenDisabled(t *testing.T) {
@@ -93,7 +94,7 @@ func TestRedeemServiceDailyCheckinAdminForbidden(t *testing.T) {
 		1: {
 			ID:      1,
 			Role:    RoleAdmin,
-			Balance: 9,
+			Balance: 9 * money.USD,
 		},
 	})
 	settingService := newCheckinTestSettingService(true, 1, 3)
@@ -112,7 +113,7 @@ func TestRedeemServiceGetDailyCheckinStatus(t *testing.T) {
 		1: {
 			ID:      1,
 			Role:    RoleUser,
-			Balance: 2,
+			Balance: 2 * money.USD,
 		},
 	})
 	settingService := newCheckinTestSettingService(true, 2.5, 3.5)
@@ -216,7 +217,7 @@ func (r *checkinTestRedeemRepo) ListByUserPaginated(_ context.Context, _ int64,
 	return nil, nil, nil
 }
 
-func (r *checkinTestRedeemRepo) SumPositiveBalanceByUser(_ context.Context, _ int64) (float64, error) {
+func (r *checkinTestRedeemRepo) SumPositiveBalanceByUser(_ context.Context, _ int64) (money.Amount, error) {
 	return 0, nil
 }
 
@@ -266,7 +267,7 @@ func (r *checkinTestUserRepo) ListWithFilters(_ context.Context, _ pagination.Pa
 	return nil, nil, nil
 }
 
-func (r *checkinTestUserRepo) UpdateBalance(_ context.Context, id int64, amount float64) error {
+func (r *checkinTestUserRepo) UpdateBalance(_ context.Context, id int64, amount money.Amount, _ BalanceLedgerSource) error {
 	user, ok := r.users[id]
 	if !ok {
 		return ErrUserNotFound
@@ -275,7 +276,9 @@ func (r *checkinTestUserRepo) UpdateBalance(_ context.Context, id int64, amount
 	return nil
 }
 
-func (r *checkinTestUserRepo) DeductBalance(_ context.Context, _ int64, _ float64) error { return nil }
+func (r *checkinTestUserRepo) DeductBalance(_ context.Context, _ int64, _ money.Amount, _ BalanceLedgerSource) error {
+	return nil
+}
 
 func (r *checkinTestUserRepo) UpdateConcurrency(_ context.Context, _ int64, _ int) error { return nil }
 
@@ -0,0 +1,225 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	infraerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
+)
+
+// 推荐关系状态
+const (
+	ReferralStatusActive  = "active"  // 正常结算返佣
+	ReferralStatusFlagged = "flagged" // 疑似刷量，管理员审核前不发放奖励与返佣
+	ReferralStatusRevoked = "revoked" // 已撤销，不再结算
+)
+
+// 推荐关系被标记为可疑的原因
+const (
+	ReferralFlagSameIP     = "same_ip"     // 注册 IP 是推荐人登录过的 IP
+	ReferralFlagSameDevice = "same_device" // 注册 User-Agent 与推荐人登录时使用的完全一致
+	ReferralFlagIPLimit    = "ip_limit"    // 同一推荐人名下来自同一 IP 的被推荐人过多
+)
+
+// 返佣类型
+const (
+	ReferralCommissionKindBonus    = "bonus"    // 一次性推荐奖励
+	ReferralCommissionKindRecharge = "recharge" // 按被推荐人充值返佣
+	ReferralCommissionKindUsage    = "usage"    // 按被推荐人消费返佣
+)
+
+// 返佣发放方式
+const (
+	ReferralPayoutBalance = "balance" // 计入推荐人账户余额
+	ReferralPayoutWallet  = "wallet"  // 计入可提现的佣金钱包
+)
+
+// 佣金提现方式与状态
+const (
+	ReferralWithdrawalMethodBalance = "balance" // 转入账户余额，立即完成
+	ReferralWithdrawalMethodManual  = "manual"  // 线下打款，需管理员审核
+
+	ReferralWithdrawalStatusPending   = "pending"
+	ReferralWithdrawalStatusCompleted = "completed"
+	ReferralWithdrawalStatusRejected  = "rejected"
+)
+
+var (
+	ErrReferralDisabled            = infraerrors.Forbidden("REFERRAL_DISABLED", "referral program is disabled")
+	ErrReferralCodeNotFound        = infraerrors.NotFound("REFERRAL_CODE_NOT_FOUND", "referral code not found")
+	ErrReferralRelationNotFound    = infraerrors.NotFound("REFERRAL_RELATION_NOT_FOUND", "referral relation not found")
+	ErrReferralAlreadyBound        = infraerrors.Conflict("REFERRAL_ALREADY_BOUND", "user already has a referrer")
+	ErrReferralSelf                = infraerrors.BadRequest("REFERRAL_SELF", "cannot refer yourself")
+	ErrReferralInvalidStatus       = infraerrors.BadRequest("REFERRAL_INVALID_STATUS", "invalid referral status")
+	ErrReferralWalletDisabled      = infraerrors.BadRequest("REFERRAL_WALLET_DISABLED", "commission wallet is not enabled")
+	ErrReferralInsufficientWallet  = infraerrors.BadRequest("REFERRAL_INSUFFICIENT_WALLET", "insufficient commission wallet balance")
+	ErrReferralWithdrawalTooSmall  = infraerrors.BadRequest("REFERRAL_WITHDRAWAL_TOO_SMALL", "withdrawal amount is below the minimum")
+	ErrReferralWithdrawalAccount   = infraerrors.BadRequest("REFERRAL_WITHDRAWAL_ACCOUNT_REQUIRED", "payee account is required for manual withdrawals")
+	ErrReferralWithdrawalMethod    = infraerrors.BadRequest("REFERRAL_WITHDRAWAL_INVALID_METHOD", "invalid withdrawal method")
+	ErrReferralWithdrawalNotFound  = infraerrors.NotFound("REFERRAL_WITHDRAWAL_NOT_FOUND", "withdrawal not found")
+	ErrReferralWithdrawalProcessed = infraerrors.Conflict("REFERRAL_WITHDRAWAL_PROCESSED", "withdrawal has already been processed")
+)
+
+// ReferralRelation 推荐关系
+type ReferralRelation struct {
+	ID                int64
+	ReferrerID        int64
+	RefereeID         int64
+	Code              string
+	Status            string
+	FlagReason        string
+	RegisterIP        string
+	RegisterUserAgent string
+	// CommissionUntil 返佣截止时间，nil 表示永久
+	CommissionUntil *time.Time
+	// SettledUntil 返佣已结算至该时间（不含）
+	SettledUntil time.Time
+	BonusPaidAt  *time.Time
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+
+	// 推荐关系列表的联表与汇总字段
+	ReferrerEmail   string
+	RefereeEmail    string
+	RefereeRecharge money.Amount // 被推荐人累计付费充值（payment 分类余额兑换码）
+	RefereeUsage    money.Amount // 被推荐人累计实际消费
+	Commission      money.Amount // 为推荐人带来的返佣合计
+}
+
+// ReferralCommission 一条返佣记录
+type ReferralCommission struct {
+	ID         int64
+	RelationID int64
+	ReferrerID int64
+	RefereeID  int64
+	Kind       string
+	// SourceRef 返佣来源：bonus / redeem:{兑换码ID} / usage:{结算窗口结束时间}
+	SourceRef    string
+	BaseAmount   money.Amount
+	Rate         float64
+	Amount       money.Amount
+	Payout       string
+	CreatedAt    time.Time
+	RefereeEmail string
+}
+
+// ReferralWallet 佣金钱包
+type ReferralWallet struct {
+	UserID         int64
+	Balance        money.Amount
+	TotalEarned    money.Amount
+	TotalWithdrawn money.Amount
+	UpdatedAt      time.Time
+}
+
+// ReferralWithdrawal 佣金提现申请
+type ReferralWithdrawal struct {
+	ID          int64
+	UserID      int64
+	Amount      money.Amount
+	Method      string
+	Account     string
+	Status      string
+	AdminNotes  string
+	OperatorID  *int64
+	ProcessedAt *time.Time
+	CreatedAt   time.Time
+	UserEmail   string
+}
+
+// ReferralSummary 推荐人名下的推荐概况
+type ReferralSummary struct {
+	TotalReferrals   int64
+	ActiveReferrals  int64
+	FlaggedReferrals int64
+	TotalCommission  money.Amount
+}
+
+// ReferralDailyStat 推荐人仪表盘按天趋势
+type ReferralDailyStat struct {
+	Date            string
+	Signups         int64
+	RefereeRecharge money.Amount
+	RefereeUsage    money.Amount
+	Commission      money.Amount
+}
+
+// ReferralSettleSource 一笔可返佣的充值来源
+type ReferralSettleSource struct {
+	Ref    string
+	Amount money.Amount
+}
+
+// ReferralOriginMatch 被推荐人注册来源与推荐人的重合情况
+type ReferralOriginMatch struct {
+	SameIP          bool  // 推荐人曾从该 IP 成功登录
+	SameDevice      bool  // 推荐人曾使用完全相同的 User-Agent 成功登录
+	SameIPReferrals int64 // 推荐人名下已有的同注册 IP 被推荐人数
+}
+
+// ReferralRelationFilter 推荐关系查询条件
+type ReferralRelationFilter struct {
+	ReferrerID int64
+	RefereeID  int64
+	Status     string
+}
+
+// ReferralCommissionFilter 返佣记录查询条件
+type ReferralCommissionFilter struct {
+	ReferrerID int64
+	Kind       string
+}
+
+// ReferralWithdrawalFilter 提现申请查询条件
+type ReferralWithdrawalFilter struct {
+	UserID int64
+	Status string
+}
+
+// ReferralRepository 推荐码、推荐关系、返佣与佣金钱包存储。
+// 在事务上下文中调用时与余额变更同事务提交。
+type ReferralRepository interface {
+	GetCodeByUserID(ctx context.Context, userID int64) (string, error)
+	// CreateCode 为用户写入推荐码；推荐码已被占用或用户已有推荐码时返回 false
+	CreateCode(ctx context.Context, userID int64, code string) (bool, error)
+	GetUserIDByCode(ctx context.Context, code string) (int64, error)
+
+	// CreateRelation 被推荐人已有推荐关系时返回 ErrReferralAlreadyBound
+	CreateRelation(ctx context.Context, rel *ReferralRelation) error
+	GetRelation(ctx context.Context, id int64) (*ReferralRelation, error)
+	GetRelationForUpdate(ctx context.Context, id int64) (*ReferralRelation, error)
+	ListRelations(ctx context.Context, params pagination.PaginationParams, filter ReferralRelationFilter) ([]ReferralRelation, *pagination.PaginationResult, error)
+	UpdateRelationStatus(ctx context.Context, id int64, status, flagReason string) error
+	// ListSettleDue 返回仍在返佣期内、结算游标早于 before 的正常推荐关系
+	ListSettleDue(ctx context.Context, before time.Time, limit int) ([]ReferralRelation, error)
+	AdvanceSettlement(ctx context.Context, id int64, settledUntil time.Time, bonusPaid bool) error
+	CheckOrigin(ctx context.Context, referrerID int64, ip, userAgent string) (*ReferralOriginMatch, error)
+
+	// ListRechargeSources 返回被推荐人在 [start, end) 内的付费充值（payment 分类的余额兑换码）
+	ListRechargeSources(ctx context.Context, refereeID int64, start, end time.Time) ([]ReferralSettleSource, error)
+	// SumRecharge 被推荐人在 [start, end) 内的付费充值合计
+	SumRecharge(ctx context.Context, refereeID int64, start, end time.Time) (money.Amount, error)
+	// SumUsage 被推荐人在 [start, end) 内余额扣费的实际消费合计
+	SumUsage(ctx context.Context, refereeID int64, start, end time.Time) (money.Amount, error)
+
+	// CreateCommission 同一推荐关系下 SourceRef 已存在时返回 false
+	CreateCommission(ctx context.Context, c *ReferralCommission) (bool, error)
+	ListCommissions(ctx context.Context, params pagination.PaginationParams, filter ReferralCommissionFilter) ([]ReferralCommission, *pagination.PaginationResult, error)
+	GetSummary(ctx context.Context, referrerID int64) (*ReferralSummary, error)
+	DailyStats(ctx context.Context, referrerID int64, start, end time.Time) ([]ReferralDailyStat, error)
+
+	// GetWallet 钱包不存在时返回零值钱包
+	GetWallet(ctx context.Context, userID int64) (*ReferralWallet, error)
+	CreditWallet(ctx context.Context, userID int64, amount money.Amount) error
+	// DebitWallet 余额不足时返回 false
+	DebitWallet(ctx context.Context, userID int64, amount money.Amount) (bool, error)
+	// RestoreWallet 撤回一笔提现（驳回时退回钱包）
+	RestoreWallet(ctx context.Context, userID int64, amount money.Amount) error
+	CreateWithdrawal(ctx context.Context, w *ReferralWithdrawal) error
+	GetWithdrawalForUpdate(ctx context.Context, id int64) (*ReferralWithdrawal, error)
+	ListWithdrawals(ctx context.Context, params pagination.PaginationParams, filter ReferralWithdrawalFilter) ([]ReferralWithdrawal, *pagination.PaginationResult, error)
+	// FinishWithdrawal 仅处理 pending 状态的申请，状态已变化时返回 false
+	FinishWithdrawal(ctx context.Context, id int64, status, notes string, operatorID int64) (bool, error)
+}
@@ -0,0 +1,745 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	dbent "github.com/Wei-Shaw/sub2api/ent"
+	"github.com/Wei-Shaw/sub2api/internal/config"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/logger"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
+)
+
+const (
+	referralCodeLength   = 8
+	referralCodeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789" // 去掉易混淆的 I/O/0/1
+	referralCodeAttempts = 5
+	referralRegisterPath = "/register"
+
+	referralSettleBatch     = 200
+	referralSettleMaxRounds = 50
+	// referralSettleLag 结算截止时间相对当前时间的延迟，给异步写入的用量记录留出落库时间
+	referralSettleLag = 5 * time.Minute
+	// referralSettleTimeout 单次结算任务的超时时间
+	referralSettleTimeout        = 5 * time.Minute
+	referralWithdrawalAccountMax = 500
+	referralWithdrawalNotesMax   = 500
+)
+
+// ReferralTerms 对用户展示的推荐规则
+type ReferralTerms struct {
+	BonusAmount      money.Amount
+	BonusMinRecharge money.Amount
+	CommissionBasis  string
+	CommissionRate   float64
+	CommissionDays   int
+	Payout           string
+	MinWithdrawal    money.Amount
+}
+
+// ReferralOverview 推荐人主页：推荐链接、概况与佣金钱包
+type ReferralOverview struct {
+	Code    string
+	Link    string
+	Terms   ReferralTerms
+	Summary ReferralSummary
+	Wallet  ReferralWallet
+}
+
+// ReferralDashboard 推荐人仪表盘：被推荐人充值、消费与返佣的按天趋势
+type ReferralDashboard struct {
+	StartDate string
+	EndDate   string
+	Daily     []ReferralDailyStat
+}
+
+// ReferralWithdrawInput 佣金提现参数
+type ReferralWithdrawInput struct {
+	Amount  money.Amount
+	Method  string // balance / manual
+	Account string // 线下打款的收款信息
+}
+
+// ReferralService 推荐返佣：推荐码、注册绑定与防刷、定时结算返佣、佣金钱包与提现。
+//
+// 返佣不挂在网关计费或兑换的热路径上，而是由后台任务按推荐关系的 settled_until 游标
+// 从 redeem_codes（充值）或 usage_logs（消费）增量结算；返佣记录的 (relation_id, source_ref)
+// 唯一约束与游标推进在同一事务内，重复执行不会重复发放。
+type ReferralService struct {
+	repo                 ReferralRepository
+	userRepo             UserRepository
+	billingCacheService  *BillingCacheService
+	authCacheInvalidator APIKeyAuthCacheInvalidator
+	entClient            *dbent.Client
+
+	cfg         config.ReferralConfig
+	terms       ReferralTerms // 由 cfg 换算，金额统一为 money.Amount
+	frontendURL string
+
+	startOnce sync.Once
+	stopOnce  sync.Once
+	stopCh    chan struct{}
+}
+
+// NewReferralService 创建推荐返佣服务
+func NewReferralService(
+	repo ReferralRepository,
+	userRepo UserRepository,
+	billingCacheService *BillingCacheService,
+	authCacheInvalidator APIKeyAuthCacheInvalidator,
+	entClient *dbent.Client,
+	cfg *config.Config,
+) *ReferralService {
+	s := &ReferralService{
+		repo:                 repo,
+		userRepo:             userRepo,
+		billingCacheService:  billingCacheService,
+		authCacheInvalidator: authCacheInvalidator,
+		entClient:            entClient,
+		stopCh:               make(chan struct{}),
+	}
+	if cfg != nil {
+		s.cfg = cfg.Referral
+		s.terms = ReferralTerms{
+			BonusAmount:      money.FromFloat(cfg.Referral.BonusAmount),
+			BonusMinRecharge: money.FromFloat(cfg.Referral.BonusMinRecharge),
+			CommissionBasis:  cfg.Referral.CommissionBasis,
+			CommissionRate:   cfg.Referral.CommissionRate,
+			CommissionDays:   cfg.Referral.CommissionDays,
+			Payout:           cfg.Referral.Payout,
+			MinWithdrawal:    money.FromFloat(cfg.Referral.MinWithdrawal),
+		}
+		s.frontendURL = strings.TrimRight(strings.TrimSpace(cfg.Server.FrontendURL), "/")
+	}
+	return s
+}
+
+// Enabled 推荐返佣是否开启
+func (s *ReferralService) Enabled() bool {
+	return s != nil && s.cfg.Enabled
+}
+
+// Terms 当前推荐规则
+func (s *ReferralService) Terms() ReferralTerms {
+	return s.terms
+}
+
+// ============================================
+// 推荐码与推荐人主页
+// ============================================
+
+// GetOrCreateCode 返回用户的推荐码，首次访问时生成
+func (s *ReferralService) GetOrCreateCode(ctx context.Context, userID int64) (string, error) {
+	code, err := s.repo.GetCodeByUserID(ctx, userID)
+	if err == nil {
+		return code, nil
+	}
+	if !errors.Is(err, ErrReferralCodeNotFound) {
+		return "", err
+	}
+
+	for i := 0; i < referralCodeAttempts; i++ {
+		candidate, err := generateReferralCode()
+		if err != nil {
+			return "", fmt.Errorf("generate referral code: %w", err)
+		}
+		created, err := s.repo.CreateCode(ctx, userID, candidate)
+		if err != nil {
+			return "", err
+		}
+		if created {
+			return candidate, nil
+		}
+		// 未写入：可能是并发请求已为该用户生成，也可能是推荐码冲突
+		if code, err := s.repo.GetCodeByUserID(ctx, userID); err == nil {
+			return code, nil
+		}
+	}
+	return "", fmt.Errorf("generate referral code: too many collisions")
+}
+
+// Link 推荐链接；未配置前端地址时返回站内相对路径
+func (s *ReferralService) Link(code string) string {
+	return s.frontendURL + referralRegisterPath + "?ref=" + url.QueryEscape(code)
+}
+
+// GetOverview 推荐人主页
+func (s *ReferralService) GetOverview(ctx context.Context, userID int64) (*ReferralOverview, error) {
+	if !s.Enabled() {
+		return nil, ErrReferralDisabled
+	}
+	code, err := s.GetOrCreateCode(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	summary, err := s.repo.GetSummary(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("get referral summary: %w", err)
+	}
+	wallet, err := s.repo.GetWallet(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("get referral wallet: %w", err)
+	}
+	return &ReferralOverview{
+		Code:    code,
+		Link:    s.Link(code),
+		Terms:   s.Terms(),
+		Summary: *summary,
+		Wallet:  *wallet,
+	}, nil
+}
+
+// ListReferees 推荐人名下的被推荐人（含累计充值、消费与返佣）
+func (s *ReferralService) ListReferees(ctx context.Context, referrerID int64, params pagination.PaginationParams) ([]ReferralRelation, *pagination.PaginationResult, error) {
+	if !s.Enabled() {
+		return nil, nil, ErrReferralDisabled
+	}
+	return s.repo.ListRelations(ctx, params, ReferralRelationFilter{ReferrerID: referrerID})
+}
+
+// ListUserCommissions 推荐人的返佣记录
+func (s *ReferralService) ListUserCommissions(ctx context.Context, referrerID int64, params pagination.PaginationParams) ([]ReferralCommission, *pagination.PaginationResult, error) {
+	if !s.Enabled() {
+		return nil, nil, ErrReferralDisabled
+	}
+	return s.repo.ListCommissions(ctx, params, ReferralCommissionFilter{ReferrerID: referrerID})
+}
+
+// GetDashboard 推荐人仪表盘，[start, end) 按应用时区的自然日汇总
+func (s *ReferralService) GetDashboard(ctx context.Context, referrerID int64, start, end time.Time) (*ReferralDashboard, error) {
+	if !s.Enabled() {
+		return nil, ErrReferralDisabled
+	}
+	daily, err := s.repo.DailyStats(ctx, referrerID, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("list referral daily stats: %w", err)
+	}
+	return &ReferralDashboard{
+		StartDate: start.Format("2006-01-02"),
+		EndDate:   end.AddDate(0, 0, -1).Format("2006-01-02"),
+		Daily:     daily,
+	}, nil
+}
+
+// ============================================
+// 注册绑定
+// ============================================
+
+// BindOnRegister 新用户通过推荐码注册后建立推荐关系。
+// 注册来源（IP / User-Agent）取自 ClientInfo；与推荐人重合的关系标记为 flagged，不发放奖励与返佣。
+func (s *ReferralService) BindOnRegister(ctx context.Context, refereeID int64, code string) (*ReferralRelation, error) {
+	if !s.Enabled() {
+		return nil, ErrReferralDisabled
+	}
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if code == "" {
+		return nil, ErrReferralCodeNotFound
+	}
+	referrerID, err := s.repo.GetUserIDByCode(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+	if referrerID == refereeID {
+		return nil, ErrReferralSelf
+	}
+	referrer, err := s.userRepo.GetByID(ctx, referrerID)
+	if err != nil {
+		return nil, err
+	}
+	if !referrer.IsActive() {
+		return nil, ErrReferralCodeNotFound
+	}
+
+	client := ClientInfoFromContext(ctx)
+	now := time.Now()
+	rel := &ReferralRelation{
+		ReferrerID:        referrerID,
+		RefereeID:         refereeID,
+		Code:              code,
+		Status:            ReferralStatusActive,
+		RegisterIP:        client.IP,
+		RegisterUserAgent: client.UserAgent,
+		SettledUntil:      now,
+	}
+	if s.cfg.CommissionDays > 0 {
+		until := now.AddDate(0, 0, s.cfg.CommissionDays)
+		rel.CommissionUntil = &until
+	}
+	if reason, err := s.abuseFlag(ctx, referrerID, client); err != nil {
+		// 防刷检查失败时从严处理，交由管理员审核
+		logger.LegacyPrintf("service.referral", "[Referral] origin check failed referrer=%d referee=%d err=%v", referrerID, refereeID, err)
+		rel.Status = ReferralStatusFlagged
+		rel.FlagReason = "check_failed"
+	} else if reason != "" {
+		rel.Status = ReferralStatusFlagged
+		rel.FlagReason = reason
+	}
+
+	if err := s.repo.CreateRelation(ctx, rel); err != nil {
+		return nil, err
+	}
+	if rel.Status == ReferralStatusFlagged {
+		logger.LegacyPrintf("service.referral", "[Referral] relation flagged referrer=%d referee=%d reason=%s ip=%s", referrerID, refereeID, rel.FlagReason, client.IP)
+	}
+	return rel, nil
+}
+
+// abuseFlag 返回应标记的原因，未命中任何规则时返回空串
+func (s *ReferralService) abuseFlag(ctx context.Context, referrerID int64, client ClientInfo) (string, error) {
+	if !s.cfg.BlockSameIP && !s.cfg.BlockSameDevice && s.cfg.MaxReferralsPerIP <= 0 {
+		return "", nil
+	}
+	match, err := s.repo.CheckOrigin(ctx, referrerID, client.IP, client.UserAgent)
+	if err != nil {
+		return "", err
+	}
+	switch {
+	case s.cfg.BlockSameIP && match.SameIP:
+		return ReferralFlagSameIP, nil
+	case s.cfg.BlockSameDevice && match.SameDevice:
+		return ReferralFlagSameDevice, nil
+	case s.cfg.MaxReferralsPerIP > 0 && client.IP != "" && match.SameIPReferrals >= int64(s.cfg.MaxReferralsPerIP):
+		return ReferralFlagIPLimit, nil
+	}
+	return "", nil
+}
+
+// ============================================
+// 返佣结算
+// ============================================
+
+// SettleDue 结算所有到期的推荐关系，返回成功结算的关系数
+func (s *ReferralService) SettleDue(ctx context.Context) (int, error) {
+	cutoff := time.Now().Add(-referralSettleLag)
+	settled := 0
+	for round := 0; round < referralSettleMaxRounds; round++ {
+		relations, err := s.repo.ListSettleDue(ctx, cutoff, referralSettleBatch)
+		if err != nil {
+			return settled, err
+		}
+		progressed := 0
+		for i := range relations {
+			if err := s.settleRelation(ctx, &relations[i], cutoff); err != nil {
+				logger.LegacyPrintf("service.referral", "[Referral] settle failed relation=%d err=%v", relations[i].ID, err)
+				continue
+			}
+			progressed++
+		}
+		settled += progressed
+		// 已结算的关系游标推进到 cutoff，不会再次出现在列表中；整批失败时停止，避免空转
+		if len(relations) < referralSettleBatch || progressed == 0 {
+			break
+		}
+	}
+	return settled, nil
+}
+
+func (s *ReferralService) settleRelation(ctx context.Context, rel *ReferralRelation, cutoff time.Time) error {
+	creditedBalance := false
+	err := s.withTx(ctx, func(txCtx context.Context) error {
+		locked, err := s.repo.GetRelationForUpdate(txCtx, rel.ID)
+		if err != nil {
+			return err
+		}
+		if locked.Status != ReferralStatusActive {
+			return nil
+		}
+
+		start := locked.SettledUntil
+		end := cutoff
+		if locked.CommissionUntil != nil && locked.CommissionUntil.Before(end) {
+			end = *locked.CommissionUntil
+		}
+		if !end.After(start) {
+			return nil
+		}
+
+		entries, err := s.commissionEntries(txCtx, locked, start, end)
+		if err != nil {
+			return err
+		}
+		bonus, err := s.bonusEntry(txCtx, locked, end)
+		if err != nil {
+			return err
+		}
+		if bonus != nil {
+			entries = append(entries, *bonus)
+		}
+
+		for i := range entries {
+			entry := &entries[i]
+			if !entry.Amount.IsPositive() {
+				continue
+			}
+			created, err := s.repo.CreateCommission(txCtx, entry)
+			if err != nil {
+				return fmt.Errorf("create commission: %w", err)
+			}
+			if !created {
+				continue
+			}
+			if err := s.payout(txCtx, entry); err != nil {
+				return err
+			}
+			if entry.Payout == ReferralPayoutBalance {
+				creditedBalance = true
+			}
+		}
+		return s.repo.AdvanceSettlement(txCtx, locked.ID, end, bonus != nil)
+	})
+	if err != nil {
+		return err
+	}
+	if creditedBalance {
+		s.invalidateUserBalance(ctx, rel.ReferrerID)
+	}
+	return nil
+}
+
+// commissionEntries 计算 [start, end) 内的比例返佣：充值按每个兑换码一条，消费按结算窗口汇总为一条
+func (s *ReferralService) commissionEntries(ctx context.Context, rel *ReferralRelation, start, end time.Time) ([]ReferralCommission, error) {
+	rate := s.cfg.CommissionRate
+	if rate <= 0 {
+		return nil, nil
+	}
+	newEntry := func(kind, ref string, base money.Amount) ReferralCommission {
+		return ReferralCommission{
+			RelationID: rel.ID,
+			ReferrerID: rel.ReferrerID,
+			RefereeID:  rel.RefereeID,
+			Kind:       kind,
+			SourceRef:  ref,
+			BaseAmount: base,
+			Rate:       rate,
+			Amount:     base.Mul(rate),
+			Payout:     s.cfg.Payout,
+		}
+	}
+
+	switch s.cfg.CommissionBasis {
+	case "usage":
+		total, err := s.repo.SumUsage(ctx, rel.RefereeID, start, end)
+		if err != nil {
+			return nil, fmt.Errorf("sum referee usage: %w", err)
+		}
+		if !total.IsPositive() {
+			return nil, nil
+		}
+		return []ReferralCommission{newEntry(ReferralCommissionKindUsage, "usage:"+strconv.FormatInt(end.Unix(), 10), total)}, nil
+	default:
+		sources, err := s.repo.ListRechargeSources(ctx, rel.RefereeID, start, end)
+		if err != nil {
+			return nil, fmt.Errorf("list referee recharges: %w", err)
+		}
+		entries := make([]ReferralCommission, 0, len(sources))
+		for _, src := range sources {
+			entries = append(entries, newEntry(ReferralCommissionKindRecharge, src.Ref, src.Amount))
+		}
+		return entries, nil
+	}
+}
+
+// bonusEntry 一次性推荐奖励：需在返佣期内达成累计充值门槛
+func (s *ReferralService) bonusEntry(ctx context.Context, rel *ReferralRelation, end time.Time) (*ReferralCommission, error) {
+	if rel.BonusPaidAt != nil || !s.terms.BonusAmount.IsPositive() {
+		return nil, nil
+	}
+	if s.terms.BonusMinRecharge.IsPositive() {
+		total, err := s.repo.SumRecharge(ctx, rel.RefereeID, rel.CreatedAt, end)
+		if err != nil {
+			return nil, fmt.Errorf("sum referee recharge: %w", err)
+		}
+		if total < s.terms.BonusMinRecharge {
+			return nil, nil
+		}
+	}
+	return &ReferralCommission{
+		RelationID: rel.ID,
+		ReferrerID: rel.ReferrerID,
+		RefereeID:  rel.RefereeID,
+		Kind:       ReferralCommissionKindBonus,
+		SourceRef:  ReferralCommissionKindBonus,
+		Amount:     s.terms.BonusAmount,
+		Payout:     s.cfg.Payout,
+	}, nil
+}
+
+func (s *ReferralService) payout(ctx context.Context, entry *ReferralCommission) error {
+	if entry.Payout == ReferralPayoutWallet {
+		if err := s.repo.CreditWallet(ctx, entry.ReferrerID, entry.Amount); err != nil {
+			return fmt.Errorf("credit referral wallet: %w", err)
+		}
+		return nil
+	}
+	if err := s.userRepo.UpdateBalance(ctx, entry.ReferrerID, entry.Amount, BalanceLedgerSource{
+		Type:        BalanceLedgerSourceReferral,
+		ReferenceID: strconv.FormatInt(entry.ID, 10),
+		Notes:       entry.Kind,
+	}); err != nil {
+		return fmt.Errorf("update referrer balance: %w", err)
+	}
+	return nil
+}
+
+// ============================================
+// 佣金钱包与提现
+// ============================================
+
+// Withdraw 从佣金钱包提现：转入余额立即完成，线下打款需管理员审核
+func (s *ReferralService) Withdraw(ctx context.Context, userID int64, in *ReferralWithdrawInput) (*ReferralWithdrawal, error) {
+	if !s.Enabled() {
+		return nil, ErrReferralDisabled
+	}
+	if s.cfg.Payout != ReferralPayoutWallet {
+		return nil, ErrReferralWalletDisabled
+	}
+	amount := in.Amount
+	if !amount.IsPositive() {
+		return nil, ErrReferralWithdrawalTooSmall
+	}
+	w := &ReferralWithdrawal{UserID: userID, Amount: amount, Method: in.Method}
+	switch in.Method {
+	case ReferralWithdrawalMethodBalance:
+		w.Status = ReferralWithdrawalStatusCompleted
+	case ReferralWithdrawalMethodManual:
+		if amount < s.terms.MinWithdrawal {
+			return nil, ErrReferralWithdrawalTooSmall
+		}
+		w.Account = truncateString(strings.TrimSpace(in.Account), referralWithdrawalAccountMax)
+		if w.Account == "" {
+			return nil, ErrReferralWithdrawalAccount
+		}
+		w.Status = ReferralWithdrawalStatusPending
+	default:
+		return nil, ErrReferralWithdrawalMethod
+	}
+
+	err := s.withTx(ctx, func(txCtx context.Context) error {
+		ok, err := s.repo.DebitWallet(txCtx, userID, amount)
+		if err != nil {
+			return fmt.Errorf("debit referral wallet: %w", err)
+		}
+		if !ok {
+			return ErrReferralInsufficientWallet
+		}
+		if err := s.repo.CreateWithdrawal(txCtx, w); err != nil {
+			return fmt.Errorf("create withdrawal: %w", err)
+		}
+		if w.Method != ReferralWithdrawalMethodBalance {
+			return nil
+		}
+		return s.userRepo.UpdateBalance(txCtx, userID, amount, BalanceLedgerSource{
+			Type:        BalanceLedgerSourceReferralWallet,
+			ReferenceID: strconv.FormatInt(w.ID, 10),
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	if w.Method == ReferralWithdrawalMethodBalance {
+		s.invalidateUserBalance(ctx, userID)
+	}
+	return w, nil
+}
+
+// ListUserWithdrawals 用户的提现记录
+func (s *ReferralService) ListUserWithdrawals(ctx context.Context, userID int64, params pagination.PaginationParams) ([]ReferralWithdrawal, *pagination.PaginationResult, error) {
+	return s.repo.ListWithdrawals(ctx, params, ReferralWithdrawalFilter{UserID: userID})
+}
+
+// ============================================
+// 管理员
+// ============================================
+
+// ListRelations 管理员查询推荐关系
+func (s *ReferralService) ListRelations(ctx context.Context, params pagination.PaginationParams, filter ReferralRelationFilter) ([]ReferralRelation, *pagination.PaginationResult, error) {
+	if filter.Status != "" && !isReferralStatus(filter.Status) {
+		return nil, nil, ErrReferralInvalidStatus
+	}
+	return s.repo.ListRelations(ctx, params, filter)
+}
+
+// UpdateRelationStatus 审核推荐关系：flagged 改为 active 后，下次结算会补发此前应得的奖励与返佣
+func (s *ReferralService) UpdateRelationStatus(ctx context.Context, id int64, status string) (*ReferralRelation, error) {
+	if !isReferralStatus(status) {
+		return nil, ErrReferralInvalidStatus
+	}
+	rel, err := s.repo.GetRelation(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	flagReason := rel.FlagReason
+	if status == ReferralStatusActive {
+		flagReason = ""
+	}
+	if err := s.repo.UpdateRelationStatus(ctx, id, status, flagReason); err != nil {
+		return nil, err
+	}
+	return s.repo.GetRelation(ctx, id)
+}
+
+// ListCommissions 管理员查询返佣记录
+func (s *ReferralService) ListCommissions(ctx context.Context, params pagination.PaginationParams, filter ReferralCommissionFilter) ([]ReferralCommission, *pagination.PaginationResult, error) {
+	return s.repo.ListCommissions(ctx, params, filter)
+}
+
+// ListWithdrawals 管理员查询提现申请
+func (s *ReferralService) ListWithdrawals(ctx context.Context, params pagination.PaginationParams, filter ReferralWithdrawalFilter) ([]ReferralWithdrawal, *pagination.PaginationResult, error) {
+	return s.repo.ListWithdrawals(ctx, params, filter)
+}
+
+// ProcessWithdrawal 审核线下提现：approve 表示已打款，reject 将金额退回佣金钱包
+func (s *ReferralService) ProcessWithdrawal(ctx context.Context, id int64, approve bool, notes string, operatorID int64) (*ReferralWithdrawal, error) {
+	status := ReferralWithdrawalStatusRejected
+	if approve {
+		status = ReferralWithdrawalStatusCompleted
+	}
+	notes = truncateString(strings.TrimSpace(notes), referralWithdrawalNotesMax)
+
+	var result *ReferralWithdrawal
+	err := s.withTx(ctx, func(txCtx context.Context) error {
+		w, err := s.repo.GetWithdrawalForUpdate(txCtx, id)
+		if err != nil {
+			return err
+		}
+		if w.Status != ReferralWithdrawalStatusPending {
+			return ErrReferralWithdrawalProcessed
+		}
+		ok, err := s.repo.FinishWithdrawal(txCtx, id, status, notes, operatorID)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return ErrReferralWithdrawalProcessed
+		}
+		if !approve {
+			if err := s.repo.RestoreWallet(txCtx, w.UserID, w.Amount); err != nil {
+				return fmt.Errorf("restore referral wallet: %w", err)
+			}
+		}
+		result, err = s.repo.GetWithdrawalForUpdate(txCtx, id)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// ============================================
+// 后台任务
+// ============================================
+
+// Start 启动返佣结算任务
+func (s *ReferralService) Start() {
+	if !s.Enabled() {
+		return
+	}
+	s.startOnce.Do(func() {
+		go s.runLoop()
+	})
+}
+
+// Stop 停止返佣结算任务
+func (s *ReferralService) Stop() {
+	if s == nil {
+		return
+	}
+	s.stopOnce.Do(func() {
+		close(s.stopCh)
+	})
+}
+
+func (s *ReferralService) runLoop() {
+	interval := time.Duration(s.cfg.SettleIntervalSeconds) * time.Second
+	if interval <= 0 {
+		interval = 5 * time.Minute
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			ctx, cancel := context.WithTimeout(context.Background(), referralSettleTimeout)
+			if n, err := s.SettleDue(ctx); err != nil {
+				logger.LegacyPrintf("service.referral", "[Referral] settlement failed settled=%d err=%v", n, err)
+			}
+			cancel()
+		case <-s.stopCh:
+			return
+		}
+	}
+}
+
+// ============================================
+// 内部方法
+// ============================================
+
+func (s *ReferralService) withTx(ctx context.Context, fn func(txCtx context.Context) error) error {
+	if s.entClient == nil {
+		return fn(ctx)
+	}
+	if dbent.TxFromContext(ctx) != nil {
+		return fn(ctx)
+	}
+	tx, err := s.entClient.Tx(ctx)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err := fn(dbent.NewTxContext(ctx, tx)); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+	return nil
+}
+
+func (s *ReferralService) invalidateUserBalance(ctx context.Context, userID int64) {
+	if s.authCacheInvalidator != nil {
+		s.authCacheInvalidator.InvalidateAuthCacheByUserID(ctx, userID)
+	}
+	if s.billingCacheService == nil {
+		return
+	}
+	go func() {
+		cacheCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := s.billingCacheService.InvalidateUserBalance(cacheCtx, userID); err != nil {
+			logger.LegacyPrintf("service.referral", "invalidate user balance cache failed: user_id=%d err=%v", userID, err)
+		}
+	}()
+}
+
+func isReferralStatus(status string) bool {
+	switch status {
+	case ReferralStatusActive, ReferralStatusFlagged, ReferralStatusRevoked:
+		return true
+	}
+	return false
+}
+
+func generateReferralCode() (string, error) {
+	var sb strings.Builder
+	sb.Grow(referralCodeLength)
+	n := big.NewInt(int64(len(referralCodeAlphabet)))
+	for i := 0; i < referralCodeLength; i++ {
+		idx, err := rand.Int(rand.Reader, n)
+		if err != nil {
+			return "", err
+		}
+		sb.WriteByte(referralCodeAlphabet[idx.Int64()])
+	}
+	return sb.String(), nil
+}
@@ -0,0 +1,358 @@
+//go:build unit
+
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/config"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
+	"github.com/stretchr/testify/require"
+)
+
+// ---------- fakes ----------
+
+type referralTestRepo struct {
+	ReferralRepository
+
+	codes       map[string]int64
+	relations   map[int64]*ReferralRelation
+	commissions []ReferralCommission
+	wallets     map[int64]*ReferralWallet
+	withdrawals map[int64]*ReferralWithdrawal
+
+	origin    ReferralOriginMatch
+	recharges []ReferralSettleSource
+	usage     money.Amount
+}
+
+func newReferralTestRepo() *referralTestRepo {
+	return &referralTestRepo{
+		codes:       map[string]int64{},
+		relations:   map[int64]*ReferralRelation{},
+		wallets:     map[int64]*ReferralWallet{},
+		withdrawals: map[int64]*ReferralWithdrawal{},
+	}
+}
+
+func (r *referralTestRepo) GetUserIDByCode(_ context.Context, code string) (int64, error) {
+	id, ok := r.codes[code]
+	if !ok {
+		return 0, ErrReferralCodeNotFound
+	}
+	return id, nil
+}
+
+func (r *referralTestRepo) CreateRelation(_ context.Context, rel *ReferralRelation) error {
+	for _, existing := range r.relations {
+		if existing.RefereeID == rel.RefereeID {
+			return ErrReferralAlreadyBound
+		}
+	}
+	rel.ID = int64(len(r.relations) + 1)
+	rel.CreatedAt = time.Now()
+	cp := *rel
+	r.relations[rel.ID] = &cp
+	return nil
+}
+
+func (r *referralTestRepo) GetRelationForUpdate(_ context.Context, id int64) (*ReferralRelation, error) {
+	rel, ok := r.relations[id]
+	if !ok {
+		return nil, ErrReferralRelationNotFound
+	}
+	cp := *rel
+	return &cp, nil
+}
+
+func (r *referralTestRepo) ListSettleDue(_ context.Context, before time.Time, limit int) ([]ReferralRelation, error) {
+	out := []ReferralRelation{}
+	for _, rel := range r.relations {
+		if rel.Status == ReferralStatusActive && rel.SettledUntil.Before(before) && len(out) < limit {
+			out = append(out, *rel)
+		}
+	}
+	return out, nil
+}
+
+func (r *referralTestRepo) AdvanceSettlement(_ context.Context, id int64, settledUntil time.Time, bonusPaid bool) error {
+	rel := r.relations[id]
+	rel.SettledUntil = settledUntil
+	if bonusPaid {
+		now := time.Now()
+		rel.BonusPaidAt = &now
+	}
+	return nil
+}
+
+func (r *referralTestRepo) CheckOrigin(context.Context, int64, string, string) (*ReferralOriginMatch, error) {
+	m := r.origin
+	return &m, nil
+}
+
+func (r *referralTestRepo) ListRechargeSources(context.Context, int64, time.Time, time.Time) ([]ReferralSettleSource, error) {
+	return r.recharges, nil
+}
+
+func (r *referralTestRepo) SumRecharge(context.Context, int64, time.Time, time.Time) (money.Amount, error) {
+	var total money.Amount
+	for _, src := range r.recharges {
+		total += src.Amount
+	}
+	return total, nil
+}
+
+func (r *referralTestRepo) SumUsage(context.Context, int64, time.Time, time.Time) (money.Amount, error) {
+	return r.usage, nil
+}
+
+func (r *referralTestRepo) CreateCommission(_ context.Context, c *ReferralCommission) (bool, error) {
+	for _, existing := range r.commissions {
+		if existing.RelationID == c.RelationID && existing.SourceRef == c.SourceRef {
+			return false, nil
+		}
+	}
+	c.ID = int64(len(r.commissions) + 1)
+	r.commissions = append(r.commissions, *c)
+	return true, nil
+}
+
+func (r *referralTestRepo) wallet(userID int64) *ReferralWallet {
+	w, ok := r.wallets[userID]
+	if !ok {
+		w = &ReferralWallet{UserID: userID}
+		r.wallets[userID] = w
+	}
+	return w
+}
+
+func (r *referralTestRepo) CreditWallet(_ context.Context, userID int64, amount money.Amount) error {
+	w := r.wallet(userID)
+	w.Balance += amount
+	w.TotalEarned += amount
+	return nil
+}
+
+func (r *referralTestRepo) DebitWallet(_ context.Context, userID int64, amount money.Amount) (bool, error) {
+	w := r.wallet(userID)
+	if w.Balance < amount {
+		return false, nil
+	}
+	w.Balance -= amount
+	w.TotalWithdrawn += amount
+	return true, nil
+}
+
+func (r *referralTestRepo) RestoreWallet(_ context.Context, userID int64, amount money.Amount) error {
+	w := r.wallet(userID)
+	w.Balance += amount
+	w.TotalWithdrawn -= amount
+	return nil
+}
+
+func (r *referralTestRepo) CreateWithdrawal(_ context.Context, w *ReferralWithdrawal) error {
+	w.ID = int64(len(r.withdrawals) + 1)
+	cp := *w
+	r.withdrawals[w.ID] = &cp
+	return nil
+}
+
+func (r *referralTestRepo) GetWithdrawalForUpdate(_ context.Context, id int64) (*ReferralWithdrawal, error) {
+	w, ok := r.withdrawals[id]
+	if !ok {
+		return nil, ErrReferralWithdrawalNotFound
+	}
+	cp := *w
+	return &cp, nil
+}
+
+func (r *referralTestRepo) FinishWithdrawal(_ context.Context, id int64, status, notes string, operatorID int64) (bool, error) {
+	w := r.withdrawals[id]
+	if w.Status != ReferralWithdrawalStatusPending {
+		return false, nil
+	}
+	w.Status = status
+	w.AdminNotes = notes
+	w.OperatorID = &operatorID
+	return true, nil
+}
+
+// ---------- helpers ----------
+
+func newReferralTestService(repo *referralTestRepo, users map[int64]*User, mutate func(*config.ReferralConfig)) *ReferralService {
+	cfg := &config.Config{}
+	cfg.Referral = config.ReferralConfig{
+		Enabled:           true,
+		CommissionBasis:   "recharge",
+		CommissionRate:    0.1,
+		CommissionDays:    365,
+		Payout:            ReferralPayoutBalance,
+		MinWithdrawal:     10,
+		BlockSameIP:       true,
+		MaxReferralsPerIP: 3,
+	}
+	if mutate != nil {
+		mutate(&cfg.Referral)
+	}
+	return NewReferralService(repo, newCheckinTestUserRepo(users), nil, nil, nil, cfg)
+}
+
+func referralTestUsers() map[int64]*User {
+	return map[int64]*User{
+		1: {ID: 1, Email: "referrer@example.com", Status: StatusActive},
+		2: {ID: 2, Email: "referee@example.com", Status: StatusActive},
+	}
+}
+
+// 绑定关系后把结算游标拨回过去，模拟已经过了一段时间
+func bindReferralForSettle(t *testing.T, svc *ReferralService, repo *referralTestRepo) *ReferralRelation {
+	t.Helper()
+	repo.codes["ABCD2345"] = 1
+	rel, err := svc.BindOnRegister(context.Background(), 2, "abcd2345")
+	require.NoError(t, err)
+	stored := repo.relations[rel.ID]
+	stored.SettledUntil = time.Now().Add(-time.Hour)
+	stored.CreatedAt = stored.SettledUntil
+	return stored
+}
+
+// ---------- tests ----------
+
+func TestReferralBindOnRegister_RejectsSelfAndFlagsSameIP(t *testing.T) {
+	repo := newReferralTestRepo()
+	repo.codes["ABCD2345"] = 1
+	svc := newReferralTestService(repo, referralTestUsers(), nil)
+
+	_, err := svc.BindOnRegister(context.Background(), 1, "ABCD2345")
+	require.ErrorIs(t, err, ErrReferralSelf)
+
+	_, err = svc.BindOnRegister(context.Background(), 2, "ZZZZ9999")
+	require.ErrorIs(t, err, ErrReferralCodeNotFound)
+
+	repo.origin = ReferralOriginMatch{SameIP: true}
+	ctx := WithClientInfo(context.Background(), ClientInfo{IP: "203.0.113.7", UserAgent: "test-agent"})
+	rel, err := svc.BindOnRegister(ctx, 2, " abcd2345 ")
+	require.NoError(t, err)
+	require.Equal(t, ReferralStatusFlagged, rel.Status)
+	require.Equal(t, ReferralFlagSameIP, rel.FlagReason)
+	require.Equal(t, "203.0.113.7", rel.RegisterIP)
+	require.NotNil(t, rel.CommissionUntil)
+
+	_, err = svc.BindOnRegister(ctx, 2, "ABCD2345")
+	require.ErrorIs(t, err, ErrReferralAlreadyBound)
+}
+
+func TestReferralBindOnRegister_FlagsIPLimit(t *testing.T) {
+	repo := newReferralTestRepo()
+	repo.codes["ABCD2345"] = 1
+	repo.origin = ReferralOriginMatch{SameIPReferrals: 3}
+	svc := newReferralTestService(repo, referralTestUsers(), nil)
+
+	ctx := WithClientInfo(context.Background(), ClientInfo{IP: "198.51.100.1"})
+	rel, err := svc.BindOnRegister(ctx, 2, "ABCD2345")
+	require.NoError(t, err)
+	require.Equal(t, ReferralFlagIPLimit, rel.FlagReason)
+}
+
+func TestReferralSettleDue_RechargeCommissionAndBonusAreIdempotent(t *testing.T) {
+	repo := newReferralTestRepo()
+	users := referralTestUsers()
+	svc := newReferralTestService(repo, users, func(c *config.ReferralConfig) {
+		c.BonusAmount = 5
+		c.BonusMinRecharge = 20
+	})
+	rel := bindReferralForSettle(t, svc, repo)
+
+	repo.recharges = []ReferralSettleSource{{Ref: "redeem:11", Amount: 10 * money.USD}}
+	n, err := svc.SettleDue(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+	require.Equal(t, 1*money.USD, users[1].Balance)
+	require.Nil(t, repo.relations[rel.ID].BonusPaidAt, "bonus threshold not reached yet")
+
+	// 再充值一笔后达到门槛：补发奖励，已结算的兑换码不重复返佣
+	repo.recharges = append(repo.recharges, ReferralSettleSource{Ref: "redeem:12", Amount: 15 * money.USD})
+	repo.relations[rel.ID].SettledUntil = time.Now().Add(-time.Hour)
+	_, err = svc.SettleDue(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, money.FromFloat(1+1.5+5), users[1].Balance)
+	require.NotNil(t, repo.relations[rel.ID].BonusPaidAt)
+	require.Len(t, repo.commissions, 3)
+}
+
+func TestReferralSettleDue_SkipsFlaggedRelations(t *testing.T) {
+	repo := newReferralTestRepo()
+	users := referralTestUsers()
+	svc := newReferralTestService(repo, users, nil)
+	rel := bindReferralForSettle(t, svc, repo)
+	rel.Status = ReferralStatusFlagged
+
+	repo.recharges = []ReferralSettleSource{{Ref: "redeem:11", Amount: 10 * money.USD}}
+	n, err := svc.SettleDue(context.Background())
+	require.NoError(t, err)
+	require.Zero(t, n)
+	require.Empty(t, repo.commissions)
+	require.Zero(t, users[1].Balance)
+}
+
+func TestReferralSettleDue_UsageBasisCreditsWallet(t *testing.T) {
+	repo := newReferralTestRepo()
+	users := referralTestUsers()
+	svc := newReferralTestService(repo, users, func(c *config.ReferralConfig) {
+		c.CommissionBasis = "usage"
+		c.CommissionRate = 0.2
+		c.Payout = ReferralPayoutWallet
+	})
+	bindReferralForSettle(t, svc, repo)
+
+	repo.usage = 50 * money.USD
+	_, err := svc.SettleDue(context.Background())
+	require.NoError(t, err)
+	require.Len(t, repo.commissions, 1)
+	require.Equal(t, ReferralCommissionKindUsage, repo.commissions[0].Kind)
+	require.Equal(t, 10*money.USD, repo.wallets[1].Balance)
+	require.Zero(t, users[1].Balance, "wallet payout must not touch the account balance")
+}
+
+func TestReferralWithdraw_BalanceAndManualReject(t *testing.T) {
+	repo := newReferralTestRepo()
+	users := referralTestUsers()
+	svc := newReferralTestService(repo, users, func(c *config.ReferralConfig) {
+		c.Payout = ReferralPayoutWallet
+	})
+	repo.wallets[1] = &ReferralWallet{UserID: 1, Balance: 30 * money.USD, TotalEarned: 30 * money.USD}
+	require.Equal(t, 10*money.USD, svc.Terms().MinWithdrawal)
+
+	w, err := svc.Withdraw(context.Background(), 1, &ReferralWithdrawInput{Amount: 5 * money.USD, Method: ReferralWithdrawalMethodBalance})
+	require.NoError(t, err)
+	require.Equal(t, ReferralWithdrawalStatusCompleted, w.Status)
+	require.Equal(t, 5*money.USD, users[1].Balance)
+
+	_, err = svc.Withdraw(context.Background(), 1, &ReferralWithdrawInput{Amount: 5 * money.USD, Method: ReferralWithdrawalMethodManual, Account: "alipay:x"})
+	require.ErrorIs(t, err, ErrReferralWithdrawalTooSmall)
+	_, err = svc.Withdraw(context.Background(), 1, &ReferralWithdrawInput{Amount: 15 * money.USD, Method: ReferralWithdrawalMethodManual})
+	require.ErrorIs(t, err, ErrReferralWithdrawalAccount)
+	_, err = svc.Withdraw(context.Background(), 1, &ReferralWithdrawInput{Amount: 100 * money.USD, Method: ReferralWithdrawalMethodBalance})
+	require.ErrorIs(t, err, ErrReferralInsufficientWallet)
+
+	w, err = svc.Withdraw(context.Background(), 1, &ReferralWithdrawInput{Amount: 15 * money.USD, Method: ReferralWithdrawalMethodManual, Account: "alipay:x"})
+	require.NoError(t, err)
+	require.Equal(t, ReferralWithdrawalStatusPending, w.Status)
+	require.Equal(t, 10*money.USD, repo.wallets[1].Balance)
+
+	rejected, err := svc.ProcessWithdrawal(context.Background(), w.ID, false, "wrong account", 99)
+	require.NoError(t, err)
+	require.Equal(t, ReferralWithdrawalStatusRejected, rejected.Status)
+	require.Equal(t, 25*money.USD, repo.wallets[1].Balance)
+
+	_, err = svc.ProcessWithdrawal(context.Background(), w.ID, true, "", 99)
+	require.ErrorIs(t, err, ErrReferralWithdrawalProcessed)
+}
+
+func TestReferralWithdraw_RequiresWalletPayout(t *testing.T) {
+	svc := newReferralTestService(newReferralTestRepo(), referralTestUsers(), nil)
+	_, err := svc.Withdraw(context.Background(), 1, &ReferralWithdrawInput{Amount: 5 * money.USD, Method: ReferralWithdrawalMethodBalance})
+	require.ErrorIs(t, err, ErrReferralWalletDisabled)
+}
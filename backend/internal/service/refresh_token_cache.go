@@ -17,6 +17,12 @@ type RefreshTokenData struct {
 	FamilyID     string    `json:"family_id"`     // Token家族ID，用于防重放攻击
 	CreatedAt    time.Time `json:"created_at"`
 	ExpiresAt    time.Time `json:"expires_at"`
+
+	// 会话信息：同一家族（一次登录）内轮转时沿用，便于按设备展示与撤销
+	SessionCreatedAt time.Time `json:"session_created_at"` // 家族首个 Token 的签发时间
+	LastSeenAt       time.Time `json:"last_seen_at"`       // 最近一次签发/刷新时间
+	IP               string    `json:"ip,omitempty"`
+	UserAgent        string    `json:"user_agent,omitempty"`
 }
 
 // RefreshTokenCache 管理Refresh Token的Redis缓存
@@ -39,6 +45,10 @@ type RefreshTokenCache interface {
 	// 返回 (nil, err) 如果发生其他错误
 	GetRefreshToken(ctx context.Context, tokenHash string) (*RefreshTokenData, error)
 
+	// GetRefreshTokens 批量获取Refresh Token数据
+	// 用于列出用户的登录会话；不存在或已过期的Token不会出现在返回结果中
+	GetRefreshTokens(ctx context.Context, tokenHashes []string) (map[string]*RefreshTokenData, error)
+
 	// DeleteRefreshToken 删除单个Refresh Token
 	// 用于Token轮转时使旧Token失效
 	DeleteRefreshToken(ctx context.Context, tokenHash string) error
@@ -63,6 +73,10 @@ type RefreshTokenCache interface {
 	// 用于批量删除用户Token
 	GetUserTokenHashes(ctx context.Context, userID int64) ([]string, error)
 
+	// RemoveFromUserTokenSet 从用户Token集合中移除指定Token
+	// 用于清理已轮转或已过期的Token哈希
+	RemoveFromUserTokenSet(ctx context.Context, userID int64, tokenHashes ...string) error
+
 	// GetFamilyTokenHashes 获取家族的所有Token哈希
 	// 用于批量删除家族Token
 	GetFamilyTokenHashes(ctx context.Context, familyID string) ([]string, error)
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/pkg/apicompat"
+	infraerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
+)
+
+// responsesConversationTTL 网关侧 Responses 会话状态保留时长
+const responsesConversationTTL = 24 * time.Hour
+
+// ErrResponsesConversationNotFound previous_response_id 对应的会话不存在或已过期
+var ErrResponsesConversationNotFound = infraerrors.NotFound("RESPONSE_NOT_FOUND", "previous response not found")
+
+// ResponsesConversation 网关侧保存的 Responses 会话快照。
+// Items 为截至该 response 的完整输入历史（含模型输出），续链时整体重放。
+type ResponsesConversation struct {
+	Model string                         `json:"model"`
+	Items []apicompat.ResponsesInputItem `json:"items"`
+}
+
+// ResponsesConversationCache defines storage for Responses conversation state.
+// GetConversation returns ErrResponsesConversationNotFound when the key is missing.
+type ResponsesConversationCache interface {
+	GetConversation(ctx context.Context, apiKeyID int64, responseID string) (*ResponsesConversation, error)
+	SetConversation(ctx context.Context, apiKeyID int64, responseID string, conv *ResponsesConversation, ttl time.Duration) error
+}
+
+// ResponsesConversationService 为非 OpenAI 上游（Claude/Gemini/Antigravity）提供
+// previous_response_id 续链能力：上游是无状态的 Messages 接口，因此由网关保存每个
+// response 结束时的完整对话，下一轮按 previous_response_id 取回并拼接新输入。
+//
+// 会话按 API Key 隔离，避免不同 Key 之间通过 response_id 读取彼此的上下文。
+type ResponsesConversationService struct {
+	cache ResponsesConversationCache
+}
+
+// NewResponsesConversationService 创建 Responses 会话状态服务
+func NewResponsesConversationService(cache ResponsesConversationCache) *ResponsesConversationService {
+	return &ResponsesConversationService{cache: cache}
+}
+
+// Load 取回 previous_response_id 对应的会话历史
+func (s *ResponsesConversationService) Load(ctx context.Context, apiKeyID int64, responseID string) (*ResponsesConversation, error) {
+	responseID = strings.TrimSpace(responseID)
+	if s == nil || s.cache == nil || responseID == "" {
+		return nil, ErrResponsesConversationNotFound
+	}
+	conv, err := s.cache.GetConversation(ctx, apiKeyID, responseID)
+	if err != nil {
+		return nil, err
+	}
+	if conv == nil {
+		return nil, ErrResponsesConversationNotFound
+	}
+	return conv, nil
+}
+
+// Save 保存 responseID 结束时的完整会话
+func (s *ResponsesConversationService) Save(ctx context.Context, apiKeyID int64, responseID string, conv *ResponsesConversation) error {
+	responseID = strings.TrimSpace(responseID)
+	if s == nil || s.cache == nil || responseID == "" || conv == nil {
+		return nil
+	}
+	return s.cache.SetConversation(ctx, apiKeyID, responseID, conv, responsesConversationTTL)
+}
+
+// IsResponsesConversationNotFound reports whether err means the conversation is missing.
+func IsResponsesConversationNotFound(err error) bool {
+	return errors.Is(err, ErrResponsesConversationNotFound)
+}
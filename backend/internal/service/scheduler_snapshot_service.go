@@ -590,6 +590,44 @@ func (s *SchedulerSnapshotService) checkOutboxLag(ctx context.Context, oldest Sc
 	}
 }
 
+// SchedulerOutboxLag 调度 outbox 的消费进度
+type SchedulerOutboxLag struct {
+	Watermark int64         // 已处理到的事件 ID
+	Backlog   int64         // 尚未处理的事件数（按 ID 差值估算）
+	OldestAge time.Duration // 最早一条未处理事件的等待时长；无积压时为 0
+}
+
+// OutboxLag 返回当前 outbox 积压情况，供监控导出使用
+func (s *SchedulerSnapshotService) OutboxLag(ctx context.Context) (SchedulerOutboxLag, error) {
+	var lag SchedulerOutboxLag
+	if s == nil || s.outboxRepo == nil || s.cache == nil {
+		return lag, nil
+	}
+	watermark, err := s.cache.GetOutboxWatermark(ctx)
+	if err != nil {
+		return lag, err
+	}
+	lag.Watermark = watermark
+
+	maxID, err := s.outboxRepo.MaxID(ctx)
+	if err != nil {
+		return lag, err
+	}
+	if maxID <= watermark {
+		return lag, nil
+	}
+	lag.Backlog = maxID - watermark
+
+	events, err := s.outboxRepo.ListAfter(ctx, watermark, 1)
+	if err != nil {
+		return lag, err
+	}
+	if len(events) > 0 && !events[0].CreatedAt.IsZero() {
+		lag.OldestAge = time.Since(events[0].CreatedAt)
+	}
+	return lag, nil
+}
+
 func (s *SchedulerSnapshotService) loadAccountsFromDB(ctx context.Context, bucket SchedulerBucket, useMixed bool) ([]Account, error) {
 	if s.accountRepo == nil {
 		return nil, ErrSchedulerCacheNotReady
@@ -8,6 +8,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
 	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/stretchr/testify/require"
@@ -153,8 +154,12 @@ func (r *stubUserRepoForQuota) List(context.Context, pagination.PaginationParams
 func (r *stubUserRepoForQuota) ListWithFilters(context.Context, pagination.PaginationParams, UserListFilters) ([]User, *pagination.PaginationResult, error) {
 	return nil, nil, nil
 }
-func (r *stubUserRepoForQuota) UpdateBalance(context.Context, int64, float64) error { return nil }
-func (r *stubUserRepoForQuota) DeductBalance(context.Context, int64, float64) error { return nil }
+func (r *stubUserRepoForQuota) UpdateBalance(context.Context, int64, money.Amount, BalanceLedgerSource) error {
+	return nil
+}
+func (r *stubUserRepoForQuota) DeductBalance(context.Context, int64, money.Amount, BalanceLedgerSource) error {
+	return nil
+}
 func (r *stubUserRepoForQuota) UpdateConcurrency(context.Context, int64, int) error { return nil }
 func (r *stubUserRepoForQuota) ExistsByEmail(context.Context, string) (bool, error) {
 	return false, nil
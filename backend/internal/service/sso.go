@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	infraerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
+)
+
+// SSO 提供方类型
+const (
+	SSOProviderTypeOIDC   = "oidc"   // 通过 issuer 发现端点，校验 ID Token（Keycloak、Authentik、Google、Azure AD 等）
+	SSOProviderTypeOAuth2 = "oauth2" // 手动配置端点，身份信息仅来自 userinfo（如 GitHub）
+)
+
+// 令牌端点客户端认证方式
+const (
+	SSOTokenAuthClientSecretPost  = "client_secret_post"
+	SSOTokenAuthClientSecretBasic = "client_secret_basic"
+	SSOTokenAuthNone              = "none"
+)
+
+var (
+	ErrSSOProviderNotFound      = infraerrors.NotFound("SSO_PROVIDER_NOT_FOUND", "sso provider not found")
+	ErrSSOProviderSlugExists    = infraerrors.Conflict("SSO_PROVIDER_SLUG_EXISTS", "sso provider slug already exists")
+	ErrSSOIdentityNotFound      = infraerrors.NotFound("SSO_IDENTITY_NOT_FOUND", "sso identity not found")
+	ErrSSOIdentityExists        = infraerrors.Conflict("SSO_IDENTITY_EXISTS", "sso identity is already linked")
+	ErrSSOLoginFailed           = infraerrors.Unauthorized("SSO_LOGIN_FAILED", "single sign-on failed")
+	ErrSSOEmailDomainNotAllowed = infraerrors.Forbidden("SSO_EMAIL_DOMAIN_NOT_ALLOWED", "your email domain is not allowed for this sign-in provider")
+	ErrSSOAccountNotProvisioned = infraerrors.Forbidden("SSO_ACCOUNT_NOT_PROVISIONED", "no account is linked to this identity; contact your administrator")
+	ErrSSOAccountExists         = infraerrors.Conflict("SSO_ACCOUNT_EXISTS", "an account with this email already exists; sign in with your password")
+	// ErrSSORequired 邮箱域名由强制 SSO 的提供方管理时禁止密码登录
+	ErrSSORequired = infraerrors.Forbidden("SSO_REQUIRED", "password sign-in is disabled for your organization; use single sign-on")
+)
+
+// SSOProvider 管理员配置的 OIDC/OAuth2 登录提供方
+type SSOProvider struct {
+	ID      int64
+	Slug    string
+	Name    string
+	Type    string
+	Enabled bool
+
+	IssuerURL    string
+	AuthorizeURL string // oidc 留空时使用发现文档
+	TokenURL     string
+	UserInfoURL  string
+	JWKSURL      string
+
+	ClientID              string
+	ClientSecretEncrypted string
+	Scopes                string
+	TokenAuthMethod       string
+	UsePKCE               bool
+	RedirectURL           string // 留空时按 server.frontend_url 或请求地址推导
+	FrontendRedirectURL   string
+
+	// 声明映射（gjson 路径，作用于 ID Token 与 userinfo 合并后的声明）
+	SubjectClaim       string
+	EmailClaim         string
+	EmailVerifiedClaim string // 留空时 oidc 使用 email_verified
+	TrustEmail         bool   // 不检查邮箱验证状态（Azure AD、GitHub 等不返回 email_verified）
+	UsernameClaim      string
+	AttributeMappings  map[string]string // 用户属性 key -> 声明路径
+
+	AllowedEmailDomains []string
+	AutoProvision       bool
+	DefaultGroupIDs     []int64 // 自动开通的用户加入的分组（专属分组写入 allowed_groups）
+	LinkExistingByEmail bool
+	EnforceSSO          bool // AllowedEmailDomains 内的非管理员用户禁止密码登录
+
+	DisplayOrder int
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+// SSOIdentity 外部身份与本地用户的绑定
+type SSOIdentity struct {
+	ID          int64
+	ProviderID  int64
+	Subject     string
+	UserID      int64
+	Email       string
+	LastLoginAt *time.Time
+	CreatedAt   time.Time
+}
+
+// SSOProviderRepository SSO 提供方与身份绑定的数据访问接口
+type SSOProviderRepository interface {
+	Create(ctx context.Context, provider *SSOProvider) error
+	Update(ctx context.Context, provider *SSOProvider) error
+	Delete(ctx context.Context, id int64) error
+	GetByID(ctx context.Context, id int64) (*SSOProvider, error)
+	GetBySlug(ctx context.Context, slug string) (*SSOProvider, error)
+	List(ctx context.Context, enabledOnly bool) ([]SSOProvider, error)
+
+	GetIdentity(ctx context.Context, providerID int64, subject string) (*SSOIdentity, error)
+	CreateIdentity(ctx context.Context, identity *SSOIdentity) error
+	TouchIdentity(ctx context.Context, id int64, email string, at time.Time) error
+}
+
+// SSOTokenRequest 授权码换取令牌请求
+type SSOTokenRequest struct {
+	TokenURL     string
+	ClientID     string
+	ClientSecret string
+	AuthMethod   string
+	Code         string
+	RedirectURI  string
+	CodeVerifier string
+}
+
+// SSOTokenResponse 令牌端点响应
+type SSOTokenResponse struct {
+	AccessToken string
+	TokenType   string
+	IDToken     string
+	ExpiresIn   int64
+}
+
+// SSOProviderClient 与身份提供方交互的 HTTP 客户端
+type SSOProviderClient interface {
+	// GetJSON 获取发现文档、JWKS 或 userinfo；bearerToken 非空时携带 Authorization 头
+	GetJSON(ctx context.Context, url, bearerToken string) ([]byte, error)
+	ExchangeCode(ctx context.Context, req *SSOTokenRequest) (*SSOTokenResponse, error)
+}
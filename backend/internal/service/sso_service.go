@@ -0,0 +1,991 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	dbent "github.com/Wei-Shaw/sub2api/ent"
+	"github.com/Wei-Shaw/sub2api/internal/config"
+	infraerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/logger"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/oauth"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/oidc"
+	"github.com/Wei-Shaw/sub2api/internal/util/urlvalidator"
+	"github.com/tidwall/gjson"
+)
+
+const (
+	ssoDiscoveryTTL           = time.Hour
+	ssoKeySetTTL              = time.Hour
+	ssoKeySetMinRefreshPeriod = 30 * time.Second // 遇到未知 kid 时强制刷新 JWKS 的最小间隔，防止伪造 kid 打满 IdP
+	ssoCallbackPathPrefix     = "/api/v1/auth/oauth/sso/"
+	ssoDefaultOIDCScopes      = "openid email profile"
+	ssoMaxSubjectLength       = 255
+	ssoUsernameMaxLength      = 100
+)
+
+var ssoSlugPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,63}$`)
+
+// 未配置声明路径时按顺序尝试的默认路径
+var (
+	ssoDefaultSubjectClaims  = []string{"sub", "id", "user_id"}
+	ssoDefaultEmailClaims    = []string{"email"}
+	ssoDefaultUsernameClaims = []string{"preferred_username", "name", "login", "username", "nickname"}
+)
+
+// SSOProviderInput 创建/更新提供方请求（更新为整体替换，ClientSecret 为空时保留原值）
+type SSOProviderInput struct {
+	Slug                string
+	Name                string
+	Type                string
+	Enabled             bool
+	IssuerURL           string
+	AuthorizeURL        string
+	TokenURL            string
+	UserInfoURL         string
+	JWKSURL             string
+	ClientID            string
+	ClientSecret        string
+	Scopes              string
+	TokenAuthMethod     string
+	UsePKCE             bool
+	RedirectURL         string
+	FrontendRedirectURL string
+	SubjectClaim        string
+	EmailClaim          string
+	EmailVerifiedClaim  string
+	TrustEmail          bool
+	UsernameClaim       string
+	AttributeMappings   map[string]string
+	AllowedEmailDomains []string
+	AutoProvision       bool
+	DefaultGroupIDs     []int64
+	LinkExistingByEmail bool
+	EnforceSSO          bool
+	DisplayOrder        int
+}
+
+// SSOLoginRequest 发起登录时生成的授权地址与需要在回调时核对的一次性参数
+type SSOLoginRequest struct {
+	AuthURL      string
+	State        string
+	Nonce        string
+	CodeVerifier string
+}
+
+// SSOCallbackInput 回调参数（state 已由调用方核对）
+type SSOCallbackInput struct {
+	Code         string
+	Nonce        string
+	CodeVerifier string
+	RedirectURI  string
+}
+
+// ssoExternalIdentity 从声明中提取的外部身份
+type ssoExternalIdentity struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Username      string
+	claimsJSON    string
+}
+
+type ssoEndpoints struct {
+	authorize string
+	token     string
+	userInfo  string
+	jwks      string
+}
+
+type ssoDiscoveryEntry struct {
+	doc       *oidc.Discovery
+	expiresAt time.Time
+}
+
+type ssoKeySetEntry struct {
+	keys      *oidc.KeySet
+	fetchedAt time.Time
+}
+
+// SSOService 通用 OIDC/OAuth2 单点登录：提供方管理、登录流程、账号开通/关联与强制 SSO 策略
+type SSOService struct {
+	repo                 SSOProviderRepository
+	client               SSOProviderClient
+	encryptor            SecretEncryptor
+	userRepo             UserRepository
+	groupRepo            GroupRepository
+	authService          *AuthService
+	settingService       *SettingService
+	userAttributeService *UserAttributeService
+	entClient            *dbent.Client
+	cfg                  *config.Config
+
+	mu         sync.Mutex
+	discovery  map[string]ssoDiscoveryEntry
+	keySets    map[string]ssoKeySetEntry
+	timeNow    func() time.Time
+	randomText func() (string, error)
+}
+
+// NewSSOService 创建 SSO 服务
+func NewSSOService(
+	repo SSOProviderRepository,
+	client SSOProviderClient,
+	encryptor SecretEncryptor,
+	userRepo UserRepository,
+	groupRepo GroupRepository,
+	authService *AuthService,
+	settingService *SettingService,
+	userAttributeService *UserAttributeService,
+	entClient *dbent.Client,
+	cfg *config.Config,
+) *SSOService {
+	return &SSOService{
+		repo:                 repo,
+		client:               client,
+		encryptor:            encryptor,
+		userRepo:             userRepo,
+		groupRepo:            groupRepo,
+		authService:          authService,
+		settingService:       settingService,
+		userAttributeService: userAttributeService,
+		entClient:            entClient,
+		cfg:                  cfg,
+		discovery:            make(map[string]ssoDiscoveryEntry),
+		keySets:              make(map[string]ssoKeySetEntry),
+		timeNow:              time.Now,
+		randomText:           oauth.GenerateState,
+	}
+}
+
+// ==================== 提供方管理 ====================
+
+// ListProviders 列出全部提供方（管理端）
+func (s *SSOService) ListProviders(ctx context.Context) ([]SSOProvider, error) {
+	return s.repo.List(ctx, false)
+}
+
+// GetProvider 获取提供方（管理端）
+func (s *SSOService) GetProvider(ctx context.Context, id int64) (*SSOProvider, error) {
+	return s.repo.GetByID(ctx, id)
+}
+
+// CreateProvider 创建提供方
+func (s *SSOService) CreateProvider(ctx context.Context, input *SSOProviderInput) (*SSOProvider, error) {
+	provider := &SSOProvider{}
+	if err := s.applyProviderInput(ctx, provider, input); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Create(ctx, provider); err != nil {
+		return nil, err
+	}
+	return provider, nil
+}
+
+// UpdateProvider 更新提供方
+func (s *SSOService) UpdateProvider(ctx context.Context, id int64, input *SSOProviderInput) (*SSOProvider, error) {
+	provider, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	previousIssuer, previousJWKS := provider.IssuerURL, provider.JWKSURL
+	if err := s.applyProviderInput(ctx, provider, input); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Update(ctx, provider); err != nil {
+		return nil, err
+	}
+	s.invalidateCache(previousIssuer, previousJWKS)
+	return provider, nil
+}
+
+// DeleteProvider 删除提供方（同时删除其身份绑定）
+func (s *SSOService) DeleteProvider(ctx context.Context, id int64) error {
+	provider, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return err
+	}
+	s.invalidateCache(provider.IssuerURL, provider.JWKSURL)
+	return nil
+}
+
+func (s *SSOService) applyProviderInput(ctx context.Context, p *SSOProvider, in *SSOProviderInput) error {
+	if in == nil {
+		return infraerrors.BadRequest("SSO_PROVIDER_INVALID", "request body is required")
+	}
+	allowInsecure := s.cfg != nil && s.cfg.Security.URLAllowlist.AllowInsecureHTTP
+
+	slug := strings.ToLower(strings.TrimSpace(in.Slug))
+	if !ssoSlugPattern.MatchString(slug) {
+		return infraerrors.BadRequest("SSO_PROVIDER_INVALID", "slug must be 1-64 characters of a-z, 0-9, '-' or '_'")
+	}
+	name := strings.TrimSpace(in.Name)
+	if name == "" || len([]rune(name)) > 100 {
+		return infraerrors.BadRequest("SSO_PROVIDER_INVALID", "name must be 1-100 characters")
+	}
+	providerType := strings.ToLower(strings.TrimSpace(in.Type))
+	if providerType == "" {
+		providerType = SSOProviderTypeOIDC
+	}
+	if providerType != SSOProviderTypeOIDC && providerType != SSOProviderTypeOAuth2 {
+		return infraerrors.BadRequest("SSO_PROVIDER_INVALID", "type must be oidc or oauth2")
+	}
+
+	normalized := struct{ issuer, authorize, token, userInfo, jwks, redirect string }{}
+	for field, target := range map[string]struct {
+		raw string
+		out *string
+	}{
+		"issuer_url":    {in.IssuerURL, &normalized.issuer},
+		"authorize_url": {in.AuthorizeURL, &normalized.authorize},
+		"token_url":     {in.TokenURL, &normalized.token},
+		"userinfo_url":  {in.UserInfoURL, &normalized.userInfo},
+		"jwks_url":      {in.JWKSURL, &normalized.jwks},
+		"redirect_url":  {in.RedirectURL, &normalized.redirect},
+	} {
+		raw := strings.TrimSpace(target.raw)
+		if raw == "" {
+			continue
+		}
+		v, err := urlvalidator.ValidateURLFormat(raw, allowInsecure)
+		if err != nil {
+			return infraerrors.BadRequest("SSO_PROVIDER_INVALID", field+": "+err.Error())
+		}
+		*target.out = v
+	}
+
+	switch providerType {
+	case SSOProviderTypeOIDC:
+		if normalized.issuer == "" {
+			return infraerrors.BadRequest("SSO_PROVIDER_INVALID", "issuer_url is required for oidc providers")
+		}
+	case SSOProviderTypeOAuth2:
+		if normalized.authorize == "" || normalized.token == "" || normalized.userInfo == "" {
+			return infraerrors.BadRequest("SSO_PROVIDER_INVALID", "authorize_url, token_url and userinfo_url are required for oauth2 providers")
+		}
+	}
+
+	frontendRedirect := strings.TrimSpace(in.FrontendRedirectURL)
+	if frontendRedirect != "" {
+		if err := config.ValidateFrontendRedirectURL(frontendRedirect); err != nil {
+			return infraerrors.BadRequest("SSO_PROVIDER_INVALID", "frontend_redirect_url: "+err.Error())
+		}
+	}
+
+	clientID := strings.TrimSpace(in.ClientID)
+	if clientID == "" || len(clientID) > 255 {
+		return infraerrors.BadRequest("SSO_PROVIDER_INVALID", "client_id is required")
+	}
+	authMethod := strings.ToLower(strings.TrimSpace(in.TokenAuthMethod))
+	if authMethod == "" {
+		authMethod = SSOTokenAuthClientSecretPost
+	}
+	switch authMethod {
+	case SSOTokenAuthClientSecretPost, SSOTokenAuthClientSecretBasic:
+	case SSOTokenAuthNone:
+		if !in.UsePKCE {
+			return infraerrors.BadRequest("SSO_PROVIDER_INVALID", "public clients (token_auth_method=none) must use PKCE")
+		}
+	default:
+		return infraerrors.BadRequest("SSO_PROVIDER_INVALID", "token_auth_method must be client_secret_post, client_secret_basic or none")
+	}
+
+	secretEncrypted := p.ClientSecretEncrypted
+	if secret := strings.TrimSpace(in.ClientSecret); secret != "" {
+		if s.encryptor == nil {
+			return infraerrors.ServiceUnavailable("SSO_ENCRYPTION_UNAVAILABLE", "secret encryption is not configured")
+		}
+		encrypted, err := s.encryptor.Encrypt(secret)
+		if err != nil {
+			return fmt.Errorf("encrypt client secret: %w", err)
+		}
+		secretEncrypted = encrypted
+	}
+	if authMethod == SSOTokenAuthNone {
+		secretEncrypted = ""
+	} else if secretEncrypted == "" {
+		return infraerrors.BadRequest("SSO_PROVIDER_INVALID", "client_secret is required")
+	}
+
+	scopes := strings.Join(strings.Fields(in.Scopes), " ")
+	if providerType == SSOProviderTypeOIDC {
+		if scopes == "" {
+			scopes = ssoDefaultOIDCScopes
+		} else if !slices.Contains(strings.Fields(scopes), "openid") {
+			scopes = "openid " + scopes
+		}
+	}
+
+	mappings := make(map[string]string, len(in.AttributeMappings))
+	for key, path := range in.AttributeMappings {
+		key, path = strings.TrimSpace(key), strings.TrimSpace(path)
+		if key == "" || path == "" {
+			return infraerrors.BadRequest("SSO_PROVIDER_INVALID", "attribute_mappings keys and claim paths must not be empty")
+		}
+		mappings[key] = path
+	}
+
+	domains := normalizeSSOEmailDomains(in.AllowedEmailDomains)
+	if in.EnforceSSO && len(domains) == 0 {
+		return infraerrors.BadRequest("SSO_PROVIDER_INVALID", "enforce_sso requires allowed_email_domains")
+	}
+
+	groupIDs := make([]int64, 0, len(in.DefaultGroupIDs))
+	seenGroups := make(map[int64]struct{}, len(in.DefaultGroupIDs))
+	for _, id := range in.DefaultGroupIDs {
+		if _, ok := seenGroups[id]; ok {
+			continue
+		}
+		seenGroups[id] = struct{}{}
+		if id <= 0 {
+			return ErrGroupNotFound
+		}
+		if s.groupRepo != nil {
+			if _, err := s.groupRepo.GetByIDLite(ctx, id); err != nil {
+				return err
+			}
+		}
+		groupIDs = append(groupIDs, id)
+	}
+
+	p.Slug = slug
+	p.Name = name
+	p.Type = providerType
+	p.Enabled = in.Enabled
+	p.IssuerURL = normalized.issuer
+	p.AuthorizeURL = normalized.authorize
+	p.TokenURL = normalized.token
+	p.UserInfoURL = normalized.userInfo
+	p.JWKSURL = normalized.jwks
+	p.ClientID = clientID
+	p.ClientSecretEncrypted = secretEncrypted
+	p.Scopes = scopes
+	p.TokenAuthMethod = authMethod
+	p.UsePKCE = in.UsePKCE
+	p.RedirectURL = normalized.redirect
+	p.FrontendRedirectURL = frontendRedirect
+	p.SubjectClaim = strings.TrimSpace(in.SubjectClaim)
+	p.EmailClaim = strings.TrimSpace(in.EmailClaim)
+	p.EmailVerifiedClaim = strings.TrimSpace(in.EmailVerifiedClaim)
+	p.TrustEmail = in.TrustEmail
+	p.UsernameClaim = strings.TrimSpace(in.UsernameClaim)
+	p.AttributeMappings = mappings
+	p.AllowedEmailDomains = domains
+	p.AutoProvision = in.AutoProvision
+	p.DefaultGroupIDs = groupIDs
+	p.LinkExistingByEmail = in.LinkExistingByEmail
+	p.EnforceSSO = in.EnforceSSO
+	p.DisplayOrder = in.DisplayOrder
+	return nil
+}
+
+// ==================== 登录流程 ====================
+
+// ListLoginProviders 列出登录页可用的提供方
+func (s *SSOService) ListLoginProviders(ctx context.Context) ([]SSOProvider, error) {
+	return s.repo.List(ctx, true)
+}
+
+// GetLoginProvider 按 slug 获取已启用的提供方
+func (s *SSOService) GetLoginProvider(ctx context.Context, slug string) (*SSOProvider, error) {
+	provider, err := s.repo.GetBySlug(ctx, strings.ToLower(strings.TrimSpace(slug)))
+	if err != nil {
+		return nil, err
+	}
+	if !provider.Enabled {
+		return nil, ErrSSOProviderNotFound
+	}
+	return provider, nil
+}
+
+// RedirectURI 返回提供方的回调地址：优先使用显式配置，其次 server.frontend_url，最后使用请求来源
+func (s *SSOService) RedirectURI(provider *SSOProvider, requestOrigin string) string {
+	if provider.RedirectURL != "" {
+		return provider.RedirectURL
+	}
+	base := requestOrigin
+	if s.cfg != nil && strings.TrimSpace(s.cfg.Server.FrontendURL) != "" {
+		base = s.cfg.Server.FrontendURL
+	}
+	return strings.TrimRight(strings.TrimSpace(base), "/") + ssoCallbackPathPrefix + provider.Slug + "/callback"
+}
+
+// BeginLogin 生成授权地址与 state/nonce/PKCE 参数
+func (s *SSOService) BeginLogin(ctx context.Context, provider *SSOProvider, redirectURI string) (*SSOLoginRequest, error) {
+	endpoints, err := s.resolveEndpoints(ctx, provider)
+	if err != nil {
+		return nil, err
+	}
+
+	login := &SSOLoginRequest{}
+	if login.State, err = s.randomText(); err != nil {
+		return nil, infraerrors.InternalServer("OAUTH_STATE_GEN_FAILED", "failed to generate oauth state").WithCause(err)
+	}
+	if provider.Type == SSOProviderTypeOIDC {
+		if login.Nonce, err = s.randomText(); err != nil {
+			return nil, infraerrors.InternalServer("OAUTH_STATE_GEN_FAILED", "failed to generate oidc nonce").WithCause(err)
+		}
+	}
+
+	u, err := url.Parse(endpoints.authorize)
+	if err != nil {
+		return nil, ErrSSOLoginFailed.WithCause(fmt.Errorf("parse authorize url: %w", err))
+	}
+	q := u.Query()
+	q.Set("response_type", "code")
+	q.Set("client_id", provider.ClientID)
+	q.Set("redirect_uri", redirectURI)
+	if provider.Scopes != "" {
+		q.Set("scope", provider.Scopes)
+	}
+	q.Set("state", login.State)
+	if login.Nonce != "" {
+		q.Set("nonce", login.Nonce)
+	}
+	if provider.UsePKCE {
+		if login.CodeVerifier, err = oauth.GenerateCodeVerifier(); err != nil {
+			return nil, infraerrors.InternalServer("OAUTH_PKCE_GEN_FAILED", "failed to generate pkce verifier").WithCause(err)
+		}
+		q.Set("code_challenge", oauth.GenerateCodeChallenge(login.CodeVerifier))
+		q.Set("code_challenge_method", "S256")
+	}
+	u.RawQuery = q.Encode()
+	login.AuthURL = u.String()
+	return login, nil
+}
+
+// CompleteLogin 用授权码换取身份，解析或开通本地用户并签发令牌
+func (s *SSOService) CompleteLogin(ctx context.Context, provider *SSOProvider, in *SSOCallbackInput) (*TokenPair, *User, error) {
+	claims, err := s.fetchClaims(ctx, provider, in)
+	if err != nil {
+		return nil, nil, err
+	}
+	ext, err := extractSSOIdentity(provider, claims)
+	if err != nil {
+		return nil, nil, ErrSSOLoginFailed.WithCause(err)
+	}
+	if len(provider.AllowedEmailDomains) > 0 {
+		if ext.Email == "" || !ext.EmailVerified || !ssoEmailDomainAllowed(provider.AllowedEmailDomains, ext.Email) {
+			return nil, nil, ErrSSOEmailDomainNotAllowed
+		}
+	}
+
+	user, err := s.resolveUser(ctx, provider, ext)
+	if err != nil {
+		return nil, nil, err
+	}
+	if user.Role == RoleOrganization {
+		return nil, nil, ErrSSOAccountNotProvisioned
+	}
+	if !user.IsActive() {
+		return nil, nil, ErrUserNotActive
+	}
+
+	s.syncAttributes(ctx, provider, user.ID, ext.claimsJSON)
+
+	tokenPair, err := s.authService.GenerateTokenPair(ctx, user, "")
+	if err != nil {
+		return nil, nil, fmt.Errorf("generate token pair: %w", err)
+	}
+	return tokenPair, user, nil
+}
+
+// CheckPasswordLogin 实现 PasswordLoginPolicy：邮箱域名由强制 SSO 的提供方管理时禁止非管理员密码登录
+func (s *SSOService) CheckPasswordLogin(ctx context.Context, user *User) error {
+	if user == nil || user.IsAdmin() {
+		return nil
+	}
+	providers, err := s.repo.List(ctx, true)
+	if err != nil {
+		// 策略查询失败时不阻断密码登录（密码已校验通过）
+		logger.LegacyPrintf("service.sso", "[SSO] list providers for password policy failed: %v", err)
+		return nil
+	}
+	for i := range providers {
+		if providers[i].EnforceSSO && ssoEmailDomainAllowed(providers[i].AllowedEmailDomains, user.Email) {
+			return ErrSSORequired
+		}
+	}
+	return nil
+}
+
+func (s *SSOService) fetchClaims(ctx context.Context, provider *SSOProvider, in *SSOCallbackInput) (map[string]any, error) {
+	endpoints, err := s.resolveEndpoints(ctx, provider)
+	if err != nil {
+		return nil, err
+	}
+
+	secret := ""
+	if provider.ClientSecretEncrypted != "" {
+		if s.encryptor == nil {
+			return nil, infraerrors.ServiceUnavailable("SSO_ENCRYPTION_UNAVAILABLE", "secret encryption is not configured")
+		}
+		if secret, err = s.encryptor.Decrypt(provider.ClientSecretEncrypted); err != nil {
+			return nil, ErrSSOLoginFailed.WithCause(fmt.Errorf("decrypt client secret: %w", err))
+		}
+	}
+
+	token, err := s.client.ExchangeCode(ctx, &SSOTokenRequest{
+		TokenURL:     endpoints.token,
+		ClientID:     provider.ClientID,
+		ClientSecret: secret,
+		AuthMethod:   provider.TokenAuthMethod,
+		Code:         in.Code,
+		RedirectURI:  in.RedirectURI,
+		CodeVerifier: in.CodeVerifier,
+	})
+	if err != nil {
+		logger.LegacyPrintf("service.sso", "[SSO] token exchange failed: provider=%s err=%v", provider.Slug, err)
+		return nil, ErrSSOLoginFailed.WithCause(err)
+	}
+
+	claims := map[string]any{}
+	if provider.Type == SSOProviderTypeOIDC {
+		if token.IDToken == "" {
+			return nil, ErrSSOLoginFailed.WithCause(errors.New("token response missing id_token"))
+		}
+		if claims, err = s.verifyIDToken(ctx, provider, endpoints.jwks, token.IDToken, in.Nonce); err != nil {
+			logger.LegacyPrintf("service.sso", "[SSO] id token verification failed: provider=%s err=%v", provider.Slug, err)
+			return nil, ErrSSOLoginFailed.WithCause(err)
+		}
+	}
+
+	if endpoints.userInfo != "" && token.AccessToken != "" {
+		userInfo, err := s.fetchUserInfo(ctx, endpoints.userInfo, token.AccessToken)
+		switch {
+		case err != nil && provider.Type == SSOProviderTypeOAuth2:
+			return nil, ErrSSOLoginFailed.WithCause(err)
+		case err != nil:
+			// ID Token 已足够完成登录，userinfo 只用于补充声明
+			logger.LegacyPrintf("service.sso", "[SSO] userinfo fetch failed, using id token claims only: provider=%s err=%v", provider.Slug, err)
+		case provider.Type == SSOProviderTypeOIDC && fmt.Sprint(userInfo["sub"]) != fmt.Sprint(claims["sub"]):
+			// OIDC Core 5.3.2：userinfo 的 sub 必须与 ID Token 一致，否则响应不可信
+			logger.LegacyPrintf("service.sso", "[SSO] userinfo sub mismatch, ignoring userinfo: provider=%s", provider.Slug)
+		default:
+			for k, v := range userInfo {
+				if _, exists := claims[k]; !exists {
+					claims[k] = v
+				}
+			}
+		}
+	}
+	if len(claims) == 0 {
+		return nil, ErrSSOLoginFailed.WithCause(errors.New("provider returned no identity claims"))
+	}
+	return claims, nil
+}
+
+func (s *SSOService) fetchUserInfo(ctx context.Context, userInfoURL, accessToken string) (map[string]any, error) {
+	body, err := s.client.GetJSON(ctx, userInfoURL, accessToken)
+	if err != nil {
+		return nil, fmt.Errorf("fetch userinfo: %w", err)
+	}
+	var info map[string]any
+	if err := json.Unmarshal(body, &info); err != nil {
+		return nil, fmt.Errorf("decode userinfo: %w", err)
+	}
+	return info, nil
+}
+
+func (s *SSOService) verifyIDToken(ctx context.Context, provider *SSOProvider, jwksURL, rawIDToken, nonce string) (map[string]any, error) {
+	opts := oidc.VerifyOptions{
+		Issuer:   provider.IssuerURL,
+		ClientID: provider.ClientID,
+		Nonce:    nonce,
+		Now:      s.timeNow,
+	}
+	keys, err := s.getKeySet(ctx, jwksURL, false)
+	if err != nil {
+		return nil, err
+	}
+	claims, err := oidc.VerifyIDToken(rawIDToken, keys, opts)
+	if errors.Is(err, oidc.ErrKeyNotFound) {
+		// IdP 轮换了签名密钥：刷新 JWKS 后重试一次
+		if keys, err = s.getKeySet(ctx, jwksURL, true); err != nil {
+			return nil, err
+		}
+		claims, err = oidc.VerifyIDToken(rawIDToken, keys, opts)
+	}
+	return claims, err
+}
+
+// resolveUser 按 已绑定身份 -> 已验证邮箱关联 -> 自动开通 的顺序解析本地用户
+func (s *SSOService) resolveUser(ctx context.Context, provider *SSOProvider, ext *ssoExternalIdentity) (*User, error) {
+	now := s.timeNow()
+	identity, err := s.repo.GetIdentity(ctx, provider.ID, ext.Subject)
+	if err == nil {
+		user, err := s.userRepo.GetByID(ctx, identity.UserID)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.repo.TouchIdentity(ctx, identity.ID, ext.Email, now); err != nil {
+			logger.LegacyPrintf("service.sso", "[SSO] touch identity failed: identity_id=%d err=%v", identity.ID, err)
+		}
+		return user, nil
+	}
+	if !errors.Is(err, ErrSSOIdentityNotFound) {
+		return nil, err
+	}
+
+	if ext.Email != "" && ext.EmailVerified {
+		existing, err := s.userRepo.GetByEmail(ctx, ext.Email)
+		switch {
+		case err == nil:
+			if !provider.LinkExistingByEmail || existing.Role == RoleOrganization {
+				return nil, ErrSSOAccountExists
+			}
+			if err := s.repo.CreateIdentity(ctx, &SSOIdentity{
+				ProviderID:  provider.ID,
+				Subject:     ext.Subject,
+				UserID:      existing.ID,
+				Email:       ext.Email,
+				LastLoginAt: &now,
+			}); err != nil {
+				// 同一提供方下该用户已绑定其他外部身份
+				return nil, err
+			}
+			logger.LegacyPrintf("service.sso", "[SSO] linked existing user by verified email: provider=%s user_id=%d", provider.Slug, existing.ID)
+			return existing, nil
+		case !errors.Is(err, ErrUserNotFound):
+			return nil, err
+		}
+	}
+
+	if !provider.AutoProvision {
+		return nil, ErrSSOAccountNotProvisioned
+	}
+	return s.provisionUser(ctx, provider, ext, now)
+}
+
+func (s *SSOService) provisionUser(ctx context.Context, provider *SSOProvider, ext *ssoExternalIdentity, now time.Time) (*User, error) {
+	email := ext.Email
+	if email == "" || !ext.EmailVerified {
+		// 未验证的邮箱不能占用本地邮箱（否则可抢注他人邮箱），使用基于 subject 的合成邮箱
+		email = ssoSyntheticEmail(provider.Slug, ext.Subject)
+	}
+
+	randomPassword, err := randomHexString(32)
+	if err != nil {
+		logger.LegacyPrintf("service.sso", "[SSO] Failed to generate random password for sso signup: %v", err)
+		return nil, ErrServiceUnavailable
+	}
+	hashedPassword, err := s.authService.HashPassword(randomPassword)
+	if err != nil {
+		return nil, fmt.Errorf("hash password: %w", err)
+	}
+
+	defaultBalance, defaultConcurrency := 0.0, 1
+	if s.cfg != nil {
+		defaultBalance, defaultConcurrency = s.cfg.Default.UserBalance, s.cfg.Default.UserConcurrency
+	}
+	if s.settingService != nil {
+		defaultBalance = s.settingService.GetDefaultBalance(ctx)
+		defaultConcurrency = s.settingService.GetDefaultConcurrency(ctx)
+	}
+
+	user := &User{
+		Email:        email,
+		Username:     ext.Username,
+		PasswordHash: hashedPassword,
+		Role:         RoleUser,
+		Balance:      money.FromFloat(defaultBalance),
+		Concurrency:  defaultConcurrency,
+		Status:       StatusActive,
+	}
+	err = s.withTx(ctx, func(txCtx context.Context) error {
+		if err := s.userRepo.Create(txCtx, user); err != nil {
+			return err
+		}
+		return s.repo.CreateIdentity(txCtx, &SSOIdentity{
+			ProviderID:  provider.ID,
+			Subject:     ext.Subject,
+			UserID:      user.ID,
+			Email:       ext.Email,
+			LastLoginAt: &now,
+		})
+	})
+	if err != nil {
+		if errors.Is(err, ErrEmailExists) {
+			return nil, ErrSSOAccountExists
+		}
+		return nil, err
+	}
+
+	for _, groupID := range provider.DefaultGroupIDs {
+		if err := s.userRepo.AddGroupToAllowedGroups(ctx, user.ID, groupID); err != nil {
+			logger.LegacyPrintf("service.sso", "[SSO] add default group failed: user_id=%d group_id=%d err=%v", user.ID, groupID, err)
+			continue
+		}
+		user.AllowedGroups = append(user.AllowedGroups, groupID)
+	}
+	s.authService.assignDefaultSubscriptions(ctx, user.ID)
+	logger.LegacyPrintf("service.sso", "[SSO] provisioned user: provider=%s user_id=%d", provider.Slug, user.ID)
+	return user, nil
+}
+
+// syncAttributes 将映射的声明写入用户属性（尽力而为，失败不影响登录）
+func (s *SSOService) syncAttributes(ctx context.Context, provider *SSOProvider, userID int64, claimsJSON string) {
+	if s.userAttributeService == nil || len(provider.AttributeMappings) == 0 {
+		return
+	}
+	values := make(map[string]string, len(provider.AttributeMappings))
+	for key, path := range provider.AttributeMappings {
+		res := gjson.Get(claimsJSON, path)
+		if !res.Exists() {
+			continue
+		}
+		if res.IsArray() {
+			// 多选属性以 JSON 数组存储
+			values[key] = res.Raw
+			continue
+		}
+		values[key] = strings.TrimSpace(res.String())
+	}
+	skipped, err := s.userAttributeService.SyncUserAttributesByKey(ctx, userID, values)
+	if err != nil {
+		logger.LegacyPrintf("service.sso", "[SSO] sync user attributes failed: provider=%s user_id=%d err=%v", provider.Slug, userID, err)
+		return
+	}
+	if len(skipped) > 0 {
+		logger.LegacyPrintf("service.sso", "[SSO] skipped unmapped or invalid attributes: provider=%s user_id=%d keys=%v", provider.Slug, userID, skipped)
+	}
+}
+
+// ==================== 发现文档与 JWKS ====================
+
+func (s *SSOService) resolveEndpoints(ctx context.Context, provider *SSOProvider) (*ssoEndpoints, error) {
+	endpoints := &ssoEndpoints{
+		authorize: provider.AuthorizeURL,
+		token:     provider.TokenURL,
+		userInfo:  provider.UserInfoURL,
+		jwks:      provider.JWKSURL,
+	}
+	if provider.Type != SSOProviderTypeOIDC {
+		return endpoints, nil
+	}
+	if endpoints.authorize != "" && endpoints.token != "" && endpoints.jwks != "" {
+		return endpoints, nil
+	}
+
+	doc, err := s.getDiscovery(ctx, provider.IssuerURL)
+	if err != nil {
+		logger.LegacyPrintf("service.sso", "[SSO] discovery failed: provider=%s err=%v", provider.Slug, err)
+		return nil, ErrSSOLoginFailed.WithCause(err)
+	}
+	if endpoints.authorize == "" {
+		endpoints.authorize = doc.AuthorizationEndpoint
+	}
+	if endpoints.token == "" {
+		endpoints.token = doc.TokenEndpoint
+	}
+	if endpoints.userInfo == "" {
+		endpoints.userInfo = doc.UserInfoEndpoint
+	}
+	if endpoints.jwks == "" {
+		endpoints.jwks = doc.JWKSURI
+	}
+	return endpoints, nil
+}
+
+func (s *SSOService) getDiscovery(ctx context.Context, issuer string) (*oidc.Discovery, error) {
+	now := s.timeNow()
+	s.mu.Lock()
+	entry, ok := s.discovery[issuer]
+	s.mu.Unlock()
+	if ok && now.Before(entry.expiresAt) {
+		return entry.doc, nil
+	}
+
+	body, err := s.client.GetJSON(ctx, oidc.DiscoveryURL(issuer), "")
+	if err != nil {
+		return nil, fmt.Errorf("fetch discovery document: %w", err)
+	}
+	doc, err := oidc.ParseDiscovery(body, issuer)
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	s.discovery[issuer] = ssoDiscoveryEntry{doc: doc, expiresAt: now.Add(ssoDiscoveryTTL)}
+	s.mu.Unlock()
+	return doc, nil
+}
+
+func (s *SSOService) getKeySet(ctx context.Context, jwksURL string, forceRefresh bool) (*oidc.KeySet, error) {
+	now := s.timeNow()
+	s.mu.Lock()
+	entry, ok := s.keySets[jwksURL]
+	s.mu.Unlock()
+	if ok {
+		age := now.Sub(entry.fetchedAt)
+		if (!forceRefresh && age < ssoKeySetTTL) || (forceRefresh && age < ssoKeySetMinRefreshPeriod) {
+			return entry.keys, nil
+		}
+	}
+
+	body, err := s.client.GetJSON(ctx, jwksURL, "")
+	if err != nil {
+		return nil, fmt.Errorf("fetch jwks: %w", err)
+	}
+	keys, err := oidc.ParseKeySet(body)
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	s.keySets[jwksURL] = ssoKeySetEntry{keys: keys, fetchedAt: now}
+	s.mu.Unlock()
+	return keys, nil
+}
+
+func (s *SSOService) invalidateCache(issuer, jwksURL string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if doc, ok := s.discovery[issuer]; ok {
+		delete(s.keySets, doc.doc.JWKSURI)
+	}
+	delete(s.discovery, issuer)
+	delete(s.keySets, jwksURL)
+}
+
+func (s *SSOService) withTx(ctx context.Context, fn func(txCtx context.Context) error) error {
+	if s.entClient == nil {
+		return fn(ctx)
+	}
+	tx, err := s.entClient.Tx(ctx)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err := fn(dbent.NewTxContext(ctx, tx)); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+	return nil
+}
+
+// ==================== 声明解析 ====================
+
+func extractSSOIdentity(provider *SSOProvider, claims map[string]any) (*ssoExternalIdentity, error) {
+	data, err := json.Marshal(claims)
+	if err != nil {
+		return nil, fmt.Errorf("encode claims: %w", err)
+	}
+	raw := string(data)
+
+	subject := firstSSOClaim(raw, provider.SubjectClaim, ssoDefaultSubjectClaims)
+	if subject == "" {
+		return nil, errors.New("identity claims missing subject")
+	}
+	if len(subject) > ssoMaxSubjectLength {
+		return nil, errors.New("identity subject is too long")
+	}
+
+	ext := &ssoExternalIdentity{Subject: subject, claimsJSON: raw}
+	email := strings.TrimSpace(firstSSOClaim(raw, provider.EmailClaim, ssoDefaultEmailClaims))
+	if email != "" && len(email) <= 255 && !isReservedEmail(email) {
+		if _, err := mail.ParseAddress(email); err == nil {
+			ext.Email = email
+		}
+	}
+	if ext.Email != "" {
+		ext.EmailVerified = provider.TrustEmail || ssoClaimIsTrue(raw, firstNonEmptyString(provider.EmailVerifiedClaim, "email_verified"))
+	}
+
+	username := firstSSOClaim(raw, provider.UsernameClaim, ssoDefaultUsernameClaims)
+	if username == "" && ext.Email != "" {
+		username = strings.SplitN(ext.Email, "@", 2)[0]
+	}
+	if username == "" {
+		username = provider.Slug + "_" + ssoSubjectDigest(subject)[:8]
+	}
+	if runes := []rune(username); len(runes) > ssoUsernameMaxLength {
+		username = string(runes[:ssoUsernameMaxLength])
+	}
+	ext.Username = username
+	return ext, nil
+}
+
+func firstSSOClaim(raw, configured string, defaults []string) string {
+	if configured != "" {
+		return strings.TrimSpace(gjson.Get(raw, configured).String())
+	}
+	for _, path := range defaults {
+		if v := strings.TrimSpace(gjson.Get(raw, path).String()); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// ssoClaimIsTrue 兼容布尔值与字符串 "true"（部分 IdP 以字符串返回 email_verified）
+func ssoClaimIsTrue(raw, path string) bool {
+	res := gjson.Get(raw, path)
+	return res.Type == gjson.True || (res.Type == gjson.String && strings.EqualFold(strings.TrimSpace(res.Str), "true"))
+}
+
+func firstNonEmptyString(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+func normalizeSSOEmailDomains(domains []string) []string {
+	out := make([]string, 0, len(domains))
+	seen := make(map[string]struct{}, len(domains))
+	for _, d := range domains {
+		d = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(d)), "@")
+		if d == "" {
+			continue
+		}
+		if _, ok := seen[d]; ok {
+			continue
+		}
+		seen[d] = struct{}{}
+		out = append(out, d)
+	}
+	return out
+}
+
+func ssoEmailDomainAllowed(domains []string, email string) bool {
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return false
+	}
+	domain := strings.ToLower(strings.TrimSpace(email[at+1:]))
+	for _, d := range domains {
+		if domain == d {
+			return true
+		}
+	}
+	return false
+}
+
+func ssoSubjectDigest(subject string) string {
+	sum := sha256.Sum256([]byte(subject))
+	return hex.EncodeToString(sum[:])
+}
+
+// ssoSyntheticEmail 外部 subject 可能包含任意字符，取摘要生成稳定的合成邮箱
+func ssoSyntheticEmail(slug, subject string) string {
+	return "sso-" + slug + "-" + ssoSubjectDigest(subject)[:16] + SSOSyntheticEmailDomain
+}
@@ -0,0 +1,510 @@
+//go:build unit
+
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/config"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	ssoTestIssuer   = "https://idp.corp.example.com/realms/corp"
+	ssoTestClientID = "sub2api"
+	ssoTestJWKSURL  = "https://idp.corp.example.com/certs"
+	ssoTestUserInfo = "https://idp.corp.example.com/userinfo"
+)
+
+// ---- fakes ----
+
+type ssoRepoFake struct {
+	providers  map[int64]*SSOProvider
+	identities []*SSOIdentity
+	nextID     int64
+}
+
+func newSSORepoFake() *ssoRepoFake {
+	return &ssoRepoFake{providers: make(map[int64]*SSOProvider)}
+}
+
+func (r *ssoRepoFake) Create(ctx context.Context, p *SSOProvider) error {
+	for _, existing := range r.providers {
+		if existing.Slug == p.Slug {
+			return ErrSSOProviderSlugExists
+		}
+	}
+	r.nextID++
+	p.ID = r.nextID
+	cp := *p
+	r.providers[p.ID] = &cp
+	return nil
+}
+
+func (r *ssoRepoFake) Update(ctx context.Context, p *SSOProvider) error {
+	if _, ok := r.providers[p.ID]; !ok {
+		return ErrSSOProviderNotFound
+	}
+	cp := *p
+	r.providers[p.ID] = &cp
+	return nil
+}
+
+func (r *ssoRepoFake) Delete(ctx context.Context, id int64) error {
+	delete(r.providers, id)
+	return nil
+}
+
+func (r *ssoRepoFake) GetByID(ctx context.Context, id int64) (*SSOProvider, error) {
+	p, ok := r.providers[id]
+	if !ok {
+		return nil, ErrSSOProviderNotFound
+	}
+	cp := *p
+	return &cp, nil
+}
+
+func (r *ssoRepoFake) GetBySlug(ctx context.Context, slug string) (*SSOProvider, error) {
+	for _, p := range r.providers {
+		if p.Slug == slug {
+			cp := *p
+			return &cp, nil
+		}
+	}
+	return nil, ErrSSOProviderNotFound
+}
+
+func (r *ssoRepoFake) List(ctx context.Context, enabledOnly bool) ([]SSOProvider, error) {
+	out := make([]SSOProvider, 0, len(r.providers))
+	for _, p := range r.providers {
+		if enabledOnly && !p.Enabled {
+			continue
+		}
+		out = append(out, *p)
+	}
+	return out, nil
+}
+
+func (r *ssoRepoFake) GetIdentity(ctx context.Context, providerID int64, subject string) (*SSOIdentity, error) {
+	for _, identity := range r.identities {
+		if identity.ProviderID == providerID && identity.Subject == subject {
+			return identity, nil
+		}
+	}
+	return nil, ErrSSOIdentityNotFound
+}
+
+func (r *ssoRepoFake) CreateIdentity(ctx context.Context, identity *SSOIdentity) error {
+	for _, existing := range r.identities {
+		if existing.ProviderID == identity.ProviderID && (existing.Subject == identity.Subject || existing.UserID == identity.UserID) {
+			return ErrSSOIdentityExists
+		}
+	}
+	identity.ID = int64(len(r.identities) + 1)
+	r.identities = append(r.identities, identity)
+	return nil
+}
+
+func (r *ssoRepoFake) TouchIdentity(ctx context.Context, id int64, email string, at time.Time) error {
+	return nil
+}
+
+type ssoClientFake struct {
+	documents map[string][]byte
+	token     *SSOTokenResponse
+	gets      map[string]int
+	exchanged []*SSOTokenRequest
+}
+
+func (c *ssoClientFake) GetJSON(ctx context.Context, rawURL, bearerToken string) ([]byte, error) {
+	c.gets[rawURL]++
+	body, ok := c.documents[rawURL]
+	if !ok {
+		return nil, ErrSSOLoginFailed
+	}
+	return body, nil
+}
+
+func (c *ssoClientFake) ExchangeCode(ctx context.Context, req *SSOTokenRequest) (*SSOTokenResponse, error) {
+	c.exchanged = append(c.exchanged, req)
+	return c.token, nil
+}
+
+type ssoUserRepoFake struct {
+	UserRepository
+	users  map[int64]*User
+	nextID int64
+	groups map[int64][]int64
+}
+
+func newSSOUserRepoFake(users ...*User) *ssoUserRepoFake {
+	r := &ssoUserRepoFake{users: make(map[int64]*User), nextID: 100, groups: make(map[int64][]int64)}
+	for _, u := range users {
+		r.users[u.ID] = u
+	}
+	return r
+}
+
+func (r *ssoUserRepoFake) Create(ctx context.Context, user *User) error {
+	for _, u := range r.users {
+		if strings.EqualFold(u.Email, user.Email) {
+			return ErrEmailExists
+		}
+	}
+	r.nextID++
+	user.ID = r.nextID
+	r.users[user.ID] = user
+	return nil
+}
+
+func (r *ssoUserRepoFake) GetByID(ctx context.Context, id int64) (*User, error) {
+	if u, ok := r.users[id]; ok {
+		return u, nil
+	}
+	return nil, ErrUserNotFound
+}
+
+func (r *ssoUserRepoFake) GetByEmail(ctx context.Context, email string) (*User, error) {
+	for _, u := range r.users {
+		if u.Email == email {
+			return u, nil
+		}
+	}
+	return nil, ErrUserNotFound
+}
+
+func (r *ssoUserRepoFake) AddGroupToAllowedGroups(ctx context.Context, userID int64, groupID int64) error {
+	r.groups[userID] = append(r.groups[userID], groupID)
+	return nil
+}
+
+type ssoRefreshTokenCacheFake struct {
+	RefreshTokenCache
+}
+
+func (ssoRefreshTokenCacheFake) StoreRefreshToken(ctx context.Context, tokenHash string, data *RefreshTokenData, ttl time.Duration) error {
+	return nil
+}
+
+func (ssoRefreshTokenCacheFake) AddToUserTokenSet(ctx context.Context, userID int64, tokenHash string, ttl time.Duration) error {
+	return nil
+}
+
+func (ssoRefreshTokenCacheFake) AddToFamilyTokenSet(ctx context.Context, familyID string, tokenHash string, ttl time.Duration) error {
+	return nil
+}
+
+type ssoEncryptorFake struct{}
+
+func (ssoEncryptorFake) Encrypt(plaintext string) (string, error) { return "enc:" + plaintext, nil }
+func (ssoEncryptorFake) Decrypt(ciphertext string) (string, error) {
+	return strings.TrimPrefix(ciphertext, "enc:"), nil
+}
+
+// ---- helpers ----
+
+type ssoTestEnv struct {
+	svc      *SSOService
+	repo     *ssoRepoFake
+	client   *ssoClientFake
+	users    *ssoUserRepoFake
+	key      *rsa.PrivateKey
+	kid      string
+	provider *SSOProvider
+}
+
+func ssoTestJWKS(t *testing.T, kid string, key *rsa.PrivateKey) []byte {
+	t.Helper()
+	enc := base64.RawURLEncoding
+	data, err := json.Marshal(map[string]any{"keys": []map[string]string{{
+		"kty": "RSA", "kid": kid, "use": "sig", "alg": "RS256",
+		"n": enc.EncodeToString(key.N.Bytes()),
+		"e": enc.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	}}})
+	require.NoError(t, err)
+	return data
+}
+
+func newSSOTestEnv(t *testing.T, users ...*User) *ssoTestEnv {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	discovery, err := json.Marshal(map[string]any{
+		"issuer":                 ssoTestIssuer,
+		"authorization_endpoint": "https://idp.corp.example.com/auth",
+		"token_endpoint":         "https://idp.corp.example.com/token",
+		"userinfo_endpoint":      ssoTestUserInfo,
+		"jwks_uri":               ssoTestJWKSURL,
+	})
+	require.NoError(t, err)
+
+	client := &ssoClientFake{
+		documents: map[string][]byte{
+			ssoTestIssuer + "/.well-known/openid-configuration": discovery,
+			ssoTestJWKSURL: ssoTestJWKS(t, "k1", key),
+		},
+		gets: make(map[string]int),
+	}
+	cfg := &config.Config{
+		JWT:     config.JWTConfig{Secret: "test-secret", ExpireHour: 1, RefreshTokenExpireDays: 7},
+		Default: config.DefaultConfig{UserBalance: 1, UserConcurrency: 3},
+	}
+	authService := NewAuthService(nil, nil, ssoRefreshTokenCacheFake{}, cfg, nil, nil, nil, nil, nil, nil)
+	repo := newSSORepoFake()
+	userRepo := newSSOUserRepoFake(users...)
+	svc := NewSSOService(repo, client, ssoEncryptorFake{}, userRepo, nil, authService, nil, nil, nil, cfg)
+
+	provider, err := svc.CreateProvider(context.Background(), &SSOProviderInput{
+		Slug:                "Corp",
+		Name:                "Corp SSO",
+		Enabled:             true,
+		IssuerURL:           ssoTestIssuer,
+		ClientID:            ssoTestClientID,
+		ClientSecret:        "s3cret",
+		UsePKCE:             true,
+		AllowedEmailDomains: []string{"@Corp.Example.com"},
+		AutoProvision:       true,
+		DefaultGroupIDs:     []int64{7, 7},
+	})
+	require.NoError(t, err)
+	return &ssoTestEnv{svc: svc, repo: repo, client: client, users: userRepo, key: key, kid: "k1", provider: provider}
+}
+
+func (e *ssoTestEnv) issue(t *testing.T, claims jwt.MapClaims) {
+	t.Helper()
+	now := time.Now()
+	base := jwt.MapClaims{
+		"iss":   ssoTestIssuer,
+		"aud":   ssoTestClientID,
+		"nonce": "nonce-1",
+		"iat":   now.Unix(),
+		"exp":   now.Add(5 * time.Minute).Unix(),
+	}
+	for k, v := range claims {
+		base[k] = v
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, base)
+	token.Header["kid"] = e.kid
+	raw, err := token.SignedString(e.key)
+	require.NoError(t, err)
+	e.client.token = &SSOTokenResponse{AccessToken: "at", TokenType: "Bearer", IDToken: raw}
+}
+
+func (e *ssoTestEnv) login(t *testing.T) (*TokenPair, *User, error) {
+	t.Helper()
+	provider, err := e.svc.GetLoginProvider(context.Background(), "corp")
+	require.NoError(t, err)
+	return e.svc.CompleteLogin(context.Background(), provider, &SSOCallbackInput{
+		Code:         "code-1",
+		Nonce:        "nonce-1",
+		CodeVerifier: "verifier-1",
+		RedirectURI:  "https://app.example.com/api/v1/auth/oauth/sso/corp/callback",
+	})
+}
+
+// ---- tests ----
+
+func TestSSOService_CreateProviderNormalizesInput(t *testing.T) {
+	env := newSSOTestEnv(t)
+	p := env.provider
+	require.Equal(t, "corp", p.Slug)
+	require.Equal(t, SSOProviderTypeOIDC, p.Type)
+	require.Equal(t, "openid email profile", p.Scopes)
+	require.Equal(t, SSOTokenAuthClientSecretPost, p.TokenAuthMethod)
+	require.Equal(t, "enc:s3cret", p.ClientSecretEncrypted)
+	require.Equal(t, []string{"corp.example.com"}, p.AllowedEmailDomains)
+	require.Equal(t, []int64{7}, p.DefaultGroupIDs)
+
+	// 更新时留空 client_secret 保留原值；scopes 自动补齐 openid
+	updated, err := env.svc.UpdateProvider(context.Background(), p.ID, &SSOProviderInput{
+		Slug: "corp", Name: "Corp", Enabled: true, IssuerURL: ssoTestIssuer, ClientID: ssoTestClientID, Scopes: "email groups",
+	})
+	require.NoError(t, err)
+	require.Equal(t, "enc:s3cret", updated.ClientSecretEncrypted)
+	require.Equal(t, "openid email groups", updated.Scopes)
+
+	_, err = env.svc.CreateProvider(context.Background(), &SSOProviderInput{
+		Slug: "github", Name: "GitHub", Type: SSOProviderTypeOAuth2, ClientID: "id", ClientSecret: "x",
+		AuthorizeURL: "https://github.com/login/oauth/authorize", TokenURL: "https://github.com/login/oauth/access_token",
+	})
+	require.Error(t, err, "oauth2 providers need a userinfo endpoint")
+
+	_, err = env.svc.CreateProvider(context.Background(), &SSOProviderInput{
+		Slug: "strict", Name: "Strict", IssuerURL: ssoTestIssuer, ClientID: "id", ClientSecret: "x", EnforceSSO: true,
+	})
+	require.Error(t, err, "enforce_sso requires allowed domains")
+
+	_, err = env.svc.CreateProvider(context.Background(), &SSOProviderInput{
+		Slug: "public", Name: "Public", IssuerURL: ssoTestIssuer, ClientID: "id", TokenAuthMethod: SSOTokenAuthNone,
+	})
+	require.Error(t, err, "public clients must use PKCE")
+}
+
+func TestSSOService_BeginLoginUsesDiscovery(t *testing.T) {
+	env := newSSOTestEnv(t)
+	login, err := env.svc.BeginLogin(context.Background(), env.provider, env.svc.RedirectURI(env.provider, "https://app.example.com"))
+	require.NoError(t, err)
+	require.NotEmpty(t, login.State)
+	require.NotEmpty(t, login.Nonce)
+	require.NotEmpty(t, login.CodeVerifier)
+
+	u, err := url.Parse(login.AuthURL)
+	require.NoError(t, err)
+	require.Equal(t, "idp.corp.example.com", u.Host)
+	q := u.Query()
+	require.Equal(t, "https://app.example.com/api/v1/auth/oauth/sso/corp/callback", q.Get("redirect_uri"))
+	require.Equal(t, login.Nonce, q.Get("nonce"))
+	require.Equal(t, "S256", q.Get("code_challenge_method"))
+
+	// 发现文档被缓存
+	_, err = env.svc.BeginLogin(context.Background(), env.provider, "https://app.example.com/cb")
+	require.NoError(t, err)
+	require.Equal(t, 1, env.client.gets[ssoTestIssuer+"/.well-known/openid-configuration"])
+}
+
+func TestSSOService_CompleteLoginProvisionsAndReusesIdentity(t *testing.T) {
+	env := newSSOTestEnv(t)
+	env.issue(t, jwt.MapClaims{"sub": "emp-1", "email": "alice@corp.example.com", "email_verified": true, "preferred_username": "alice"})
+
+	tokens, user, err := env.login(t)
+	require.NoError(t, err)
+	require.NotEmpty(t, tokens.AccessToken)
+	require.NotEmpty(t, tokens.RefreshToken)
+	require.Equal(t, "alice@corp.example.com", user.Email)
+	require.Equal(t, "alice", user.Username)
+	require.Equal(t, 3, user.Concurrency)
+	require.Equal(t, []int64{7}, env.users.groups[user.ID])
+	require.Len(t, env.repo.identities, 1)
+	require.Equal(t, "s3cret", env.client.exchanged[0].ClientSecret)
+	require.Equal(t, "verifier-1", env.client.exchanged[0].CodeVerifier)
+
+	// 再次登录直接命中已绑定身份，不再创建用户
+	_, again, err := env.login(t)
+	require.NoError(t, err)
+	require.Equal(t, user.ID, again.ID)
+	require.Len(t, env.users.users, 1)
+}
+
+func TestSSOService_CompleteLoginRejectsBadTokens(t *testing.T) {
+	env := newSSOTestEnv(t)
+
+	env.issue(t, jwt.MapClaims{"sub": "emp-1", "email": "alice@corp.example.com", "email_verified": true, "nonce": "replayed"})
+	_, _, err := env.login(t)
+	require.ErrorIs(t, err, ErrSSOLoginFailed)
+
+	env.issue(t, jwt.MapClaims{"sub": "emp-1", "email": "alice@gmail.com", "email_verified": true})
+	_, _, err = env.login(t)
+	require.ErrorIs(t, err, ErrSSOEmailDomainNotAllowed)
+
+	env.issue(t, jwt.MapClaims{"sub": "emp-1", "email": "alice@corp.example.com", "email_verified": false})
+	_, _, err = env.login(t)
+	require.ErrorIs(t, err, ErrSSOEmailDomainNotAllowed, "unverified emails cannot satisfy the domain restriction")
+	require.Empty(t, env.users.users)
+}
+
+func TestSSOService_CompleteLoginRefreshesRotatedKeys(t *testing.T) {
+	env := newSSOTestEnv(t)
+	env.issue(t, jwt.MapClaims{"sub": "emp-1", "email": "alice@corp.example.com", "email_verified": true})
+	_, _, err := env.login(t)
+	require.NoError(t, err)
+
+	// IdP 轮换密钥：未知 kid 触发一次 JWKS 刷新（超过最小刷新间隔后）
+	rotated, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	env.key, env.kid = rotated, "k2"
+	env.client.documents[ssoTestJWKSURL] = ssoTestJWKS(t, "k2", rotated)
+	env.svc.timeNow = func() time.Time { return time.Now().Add(time.Minute) }
+
+	env.issue(t, jwt.MapClaims{"sub": "emp-1", "email": "alice@corp.example.com", "email_verified": true})
+	_, _, err = env.login(t)
+	require.NoError(t, err)
+	require.Equal(t, 2, env.client.gets[ssoTestJWKSURL])
+}
+
+func TestSSOService_CompleteLoginLinksExistingEmail(t *testing.T) {
+	existing := &User{ID: 5, Email: "bob@corp.example.com", Role: RoleUser, Status: StatusActive}
+	env := newSSOTestEnv(t, existing)
+	env.issue(t, jwt.MapClaims{"sub": "emp-2", "email": "bob@corp.example.com", "email_verified": true})
+
+	_, _, err := env.login(t)
+	require.ErrorIs(t, err, ErrSSOAccountExists, "linking is disabled by default")
+
+	env.repo.providers[env.provider.ID].LinkExistingByEmail = true
+	_, user, err := env.login(t)
+	require.NoError(t, err)
+	require.Equal(t, existing.ID, user.ID)
+	require.Len(t, env.repo.identities, 1)
+	require.Equal(t, existing.ID, env.repo.identities[0].UserID)
+}
+
+func TestSSOService_ProvisionWithoutVerifiedEmailUsesSyntheticEmail(t *testing.T) {
+	env := newSSOTestEnv(t)
+	env.repo.providers[env.provider.ID].AllowedEmailDomains = nil
+	env.issue(t, jwt.MapClaims{"sub": "emp-3", "email": "carol@corp.example.com"})
+
+	_, user, err := env.login(t)
+	require.NoError(t, err)
+	require.True(t, strings.HasSuffix(user.Email, SSOSyntheticEmailDomain))
+	require.Equal(t, "carol", user.Username)
+	require.True(t, isReservedEmail(user.Email))
+
+	env.repo.providers[env.provider.ID].AutoProvision = false
+	env.issue(t, jwt.MapClaims{"sub": "emp-4"})
+	_, _, err = env.login(t)
+	require.ErrorIs(t, err, ErrSSOAccountNotProvisioned)
+}
+
+func TestSSOService_CheckPasswordLogin(t *testing.T) {
+	env := newSSOTestEnv(t)
+	ctx := context.Background()
+	member := &User{ID: 1, Email: "dave@CORP.example.com", Role: RoleUser}
+
+	require.NoError(t, env.svc.CheckPasswordLogin(ctx, member), "enforce_sso is off")
+
+	env.repo.providers[env.provider.ID].EnforceSSO = true
+	require.ErrorIs(t, env.svc.CheckPasswordLogin(ctx, member), ErrSSORequired)
+	require.NoError(t, env.svc.CheckPasswordLogin(ctx, &User{Email: "dave@corp.example.com", Role: RoleAdmin}), "admins keep password login as break-glass")
+	require.NoError(t, env.svc.CheckPasswordLogin(ctx, &User{Email: "erin@other.example.com", Role: RoleUser}))
+
+	env.repo.providers[env.provider.ID].Enabled = false
+	require.NoError(t, env.svc.CheckPasswordLogin(ctx, member), "disabled providers do not enforce")
+}
+
+func TestExtractSSOIdentity_CustomClaimPaths(t *testing.T) {
+	p := &SSOProvider{
+		Slug:          "github",
+		SubjectClaim:  "id",
+		EmailClaim:    "emails.0.email",
+		UsernameClaim: "login",
+		TrustEmail:    true,
+	}
+	ext, err := extractSSOIdentity(p, map[string]any{
+		"id":     float64(12345),
+		"login":  "octocat",
+		"emails": []any{map[string]any{"email": "octo@example.com"}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "12345", ext.Subject)
+	require.Equal(t, "octo@example.com", ext.Email)
+	require.True(t, ext.EmailVerified)
+	require.Equal(t, "octocat", ext.Username)
+
+	_, err = extractSSOIdentity(p, map[string]any{"login": "nobody"})
+	require.Error(t, err)
+
+	// 保留域名的邮箱不能用于关联或开通
+	ext, err = extractSSOIdentity(&SSOProvider{Slug: "x", TrustEmail: true}, map[string]any{"sub": "s", "email": "org-1" + OrganizationSyntheticEmailDomain})
+	require.NoError(t, err)
+	require.Empty(t, ext.Email)
+}
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	infraerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
 	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
 	"github.com/stretchr/testify/require"
 )
@@ -119,7 +120,7 @@ func (userSubRepoNoop) ResetWeeklyUsage(context.Context, int64, time.Time) error
 func (userSubRepoNoop) ResetMonthlyUsage(context.Context, int64, time.Time) error {
 	panic("unexpected ResetMonthlyUsage call")
 }
-func (userSubRepoNoop) IncrementUsage(context.Context, int64, float64) error {
+func (userSubRepoNoop) IncrementUsage(context.Context, int64, money.Amount) error {
 	panic("unexpected IncrementUsage call")
 }
 func (userSubRepoNoop) BatchUpdateExpiredStatus(context.Context) (int64, error) {
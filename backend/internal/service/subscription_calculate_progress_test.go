@@ -6,6 +6,8 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
 )
 
 // --- Task 5: 验证 calculateProgress 纯函数行为正确 ---
@@ -14,8 +16,8 @@ func newTestSubscriptionService() *SubscriptionService {
 	return &SubscriptionService{}
 }
 
-func ptrFloat64(v float64) *float64  { return &v }
-func ptrTime(t time.Time) *time.Time { return &t }
+func ptrAmount(v money.Amount) *money.Amount { return &v }
+func ptrTime(t time.Time) *time.Time         { return &t }
 
 func TestCalculateProgress_BasicFields(t *testing.T) {
 	svc := newTestSubscriptionService()
@@ -48,12 +50,12 @@ func TestCalculateProgress_DailyUsage(t *testing.T) {
 	sub := &UserSubscription{
 		ID:               1,
 		ExpiresAt:        now.Add(10 * 24 * time.Hour),
-		DailyUsageUSD:    3.0,
+		DailyUsageUSD:    3 * money.USD,
 		DailyWindowStart: ptrTime(dailyStart),
 	}
 	group := &Group{
 		Name:          "Pro",
-		DailyLimitUSD: ptrFloat64(10.0),
+		DailyLimitUSD: ptrAmount(10 * money.USD),
 	}
 
 	progress := svc.calculateProgress(sub, group)
@@ -74,12 +76,12 @@ func TestCalculateProgress_WeeklyUsage(t *testing.T) {
 	sub := &UserSubscription{
 		ID:                1,
 		ExpiresAt:         now.Add(10 * 24 * time.Hour),
-		WeeklyUsageUSD:    25.0,
+		WeeklyUsageUSD:    25 * money.USD,
 		WeeklyWindowStart: ptrTime(weeklyStart),
 	}
 	group := &Group{
 		Name:           "Pro",
-		WeeklyLimitUSD: ptrFloat64(50.0),
+		WeeklyLimitUSD: ptrAmount(50 * money.USD),
 	}
 
 	progress := svc.calculateProgress(sub, group)
@@ -99,12 +101,12 @@ func TestCalculateProgress_MonthlyUsage(t *testing.T) {
 	sub := &UserSubscription{
 		ID:                 1,
 		ExpiresAt:          now.Add(10 * 24 * time.Hour),
-		MonthlyUsageUSD:    80.0,
+		MonthlyUsageUSD:    80 * money.USD,
 		MonthlyWindowStart: ptrTime(monthlyStart),
 	}
 	group := &Group{
 		Name:            "Enterprise",
-		MonthlyLimitUSD: ptrFloat64(100.0),
+		MonthlyLimitUSD: ptrAmount(100 * money.USD),
 	}
 
 	progress := svc.calculateProgress(sub, group)
@@ -123,12 +125,12 @@ func TestCalculateProgress_OverLimit_ClampedTo100Percent(t *testing.T) {
 	sub := &UserSubscription{
 		ID:               1,
 		ExpiresAt:        now.Add(10 * 24 * time.Hour),
-		DailyUsageUSD:    15.0, // 超过限额
+		DailyUsageUSD:    15 * money.USD, // 超过限额
 		DailyWindowStart: ptrTime(now.Add(-1 * time.Hour)),
 	}
 	group := &Group{
 		Name:          "Pro",
-		DailyLimitUSD: ptrFloat64(10.0),
+		DailyLimitUSD: ptrAmount(10 * money.USD),
 	}
 
 	progress := svc.calculateProgress(sub, group)
@@ -151,8 +153,8 @@ func TestCalculateProgress_NoWindowStart_NoProgress(t *testing.T) {
 	}
 	group := &Group{
 		Name:           "Pro",
-		DailyLimitUSD:  ptrFloat64(10.0),
-		WeeklyLimitUSD: ptrFloat64(50.0),
+		DailyLimitUSD:  ptrAmount(10 * money.USD),
+		WeeklyLimitUSD: ptrAmount(50 * money.USD),
 	}
 
 	progress := svc.calculateProgress(sub, group)
@@ -168,18 +170,18 @@ func TestCalculateProgress_AllLimits(t *testing.T) {
 	sub := &UserSubscription{
 		ID:                 1,
 		ExpiresAt:          now.Add(10 * 24 * time.Hour),
-		DailyUsageUSD:      5.0,
-		WeeklyUsageUSD:     20.0,
-		MonthlyUsageUSD:    60.0,
+		DailyUsageUSD:      5 * money.USD,
+		WeeklyUsageUSD:     20 * money.USD,
+		MonthlyUsageUSD:    60 * money.USD,
 		DailyWindowStart:   ptrTime(now.Add(-6 * time.Hour)),
 		WeeklyWindowStart:  ptrTime(now.Add(-3 * 24 * time.Hour)),
 		MonthlyWindowStart: ptrTime(now.Add(-15 * 24 * time.Hour)),
 	}
 	group := &Group{
 		Name:            "Full",
-		DailyLimitUSD:   ptrFloat64(10.0),
-		WeeklyLimitUSD:  ptrFloat64(50.0),
-		MonthlyLimitUSD: ptrFloat64(100.0),
+		DailyLimitUSD:   ptrAmount(10 * money.USD),
+		WeeklyLimitUSD:  ptrAmount(50 * money.USD),
+		MonthlyLimitUSD: ptrAmount(100 * money.USD),
 	}
 
 	progress := svc.calculateProgress(sub, group)
@@ -215,12 +217,12 @@ func TestCalculateProgress_ResetsInSeconds_NotNegative(t *testing.T) {
 	sub := &UserSubscription{
 		ID:               1,
 		ExpiresAt:        time.Now().Add(10 * 24 * time.Hour),
-		DailyUsageUSD:    1.0,
+		DailyUsageUSD:    money.USD,
 		DailyWindowStart: ptrTime(pastStart),
 	}
 	group := &Group{
 		Name:          "Test",
-		DailyLimitUSD: ptrFloat64(10.0),
+		DailyLimitUSD: ptrAmount(10 * money.USD),
 	}
 
 	progress := svc.calculateProgress(sub, group)
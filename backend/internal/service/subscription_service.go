@@ -12,6 +12,7 @@ import (
 	dbent "github.com/Wei-Shaw/sub2api/ent"
 	"github.com/Wei-Shaw/sub2api/internal/config"
 	infraerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
 	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
 	"github.com/dgraph-io/ristretto"
 	"golang.org/x/sync/singleflight"
@@ -744,7 +745,7 @@ func (s *SubscriptionService) CheckAndResetWindows(ctx context.Context, sub *Use
 
 // CheckUsageLimits 检查使用限额（返回错误如果超限）
 // 用于中间件的快速预检查，additionalCost 通常为 0
-func (s *SubscriptionService) CheckUsageLimits(ctx context.Context, sub *UserSubscription, group *Group, additionalCost float64) error {
+func (s *SubscriptionService) CheckUsageLimits(ctx context.Context, sub *UserSubscription, group *Group, additionalCost money.Amount) error {
 	if !sub.CheckDailyLimit(group, additionalCost) {
 		return ErrDailyLimitExceeded
 	}
@@ -847,7 +848,7 @@ func (s *SubscriptionService) doWindowMaintenance(sub *UserSubscription) {
 }
 
 // RecordUsage 记录使用量到订阅
-func (s *SubscriptionService) RecordUsage(ctx context.Context, subscriptionID int64, costUSD float64) error {
+func (s *SubscriptionService) RecordUsage(ctx context.Context, subscriptionID int64, costUSD money.Amount) error {
 	return s.userSubRepo.IncrementUsage(ctx, subscriptionID, costUSD)
 }
 
@@ -905,10 +906,10 @@ func (s *SubscriptionService) calculateProgress(sub *UserSubscription, group *Gr
 		limit := *group.DailyLimitUSD
 		resetsAt := sub.DailyWindowStart.Add(24 * time.Hour)
 		progress.Daily = &UsageWindowProgress{
-			LimitUSD:        limit,
-			UsedUSD:         sub.DailyUsageUSD,
-			RemainingUSD:    limit - sub.DailyUsageUSD,
-			Percentage:      (sub.DailyUsageUSD / limit) * 100,
+			LimitUSD:        limit.Float64(),
+			UsedUSD:         sub.DailyUsageUSD.Float64(),
+			RemainingUSD:    (limit - sub.DailyUsageUSD).Float64(),
+			Percentage:      (sub.DailyUsageUSD.Float64() / limit.Float64()) * 100,
 			WindowStart:     *sub.DailyWindowStart,
 			ResetsAt:        resetsAt,
 			ResetsInSeconds: int64(time.Until(resetsAt).Seconds()),
@@ -929,10 +930,10 @@ func (s *SubscriptionService) calculateProgress(sub *UserSubscription, group *Gr
 		limit := *group.WeeklyLimitUSD
 		resetsAt := sub.WeeklyWindowStart.Add(7 * 24 * time.Hour)
 		progress.Weekly = &UsageWindowProgress{
-			LimitUSD:        limit,
-			UsedUSD:         sub.WeeklyUsageUSD,
-			RemainingUSD:    limit - sub.WeeklyUsageUSD,
-			Percentage:      (sub.WeeklyUsageUSD / limit) * 100,
+			LimitUSD:        limit.Float64(),
+			UsedUSD:         sub.WeeklyUsageUSD.Float64(),
+			RemainingUSD:    (limit - sub.WeeklyUsageUSD).Float64(),
+			Percentage:      (sub.WeeklyUsageUSD.Float64() / limit.Float64()) * 100,
 			WindowStart:     *sub.WeeklyWindowStart,
 			ResetsAt:        resetsAt,
 			ResetsInSeconds: int64(time.Until(resetsAt).Seconds()),
@@ -953,10 +954,10 @@ func (s *SubscriptionService) calculateProgress(sub *UserSubscription, group *Gr
 		limit := *group.MonthlyLimitUSD
 		resetsAt := sub.MonthlyWindowStart.Add(30 * 24 * time.Hour)
 		progress.Monthly = &UsageWindowProgress{
-			LimitUSD:        limit,
-			UsedUSD:         sub.MonthlyUsageUSD,
-			RemainingUSD:    limit - sub.MonthlyUsageUSD,
-			Percentage:      (sub.MonthlyUsageUSD / limit) * 100,
+			LimitUSD:        limit.Float64(),
+			UsedUSD:         sub.MonthlyUsageUSD.Float64(),
+			RemainingUSD:    (limit - sub.MonthlyUsageUSD).Float64(),
+			Percentage:      (sub.MonthlyUsageUSD.Float64() / limit.Float64()) * 100,
 			WindowStart:     *sub.MonthlyWindowStart,
 			ResetsAt:        resetsAt,
 			ResetsInSeconds: int64(time.Until(resetsAt).Seconds()),
@@ -94,6 +94,12 @@ type TotpService struct {
 	settingService    *SettingService
 	emailService      *EmailService
 	emailQueueService *EmailQueueService
+	enabledListener   TotpEnabledListener
+}
+
+// TotpEnabledListener receives notifications when a user finishes enabling TOTP
+type TotpEnabledListener interface {
+	OnTotpEnabled(ctx context.Context, userID int64)
 }
 
 // NewTotpService creates a new TOTP service
@@ -115,6 +121,11 @@ func NewTotpService(
 	}
 }
 
+// SetEnabledListener injects the TOTP enabled listener (optional)
+func (s *TotpService) SetEnabledListener(listener TotpEnabledListener) {
+	s.enabledListener = listener
+}
+
 // GetStatus returns the TOTP status for a user
 func (s *TotpService) GetStatus(ctx context.Context, userID int64) (*TotpStatus, error) {
 	featureEnabled := s.settingService.IsTotpEnabled(ctx)
@@ -149,23 +160,8 @@ func (s *TotpService) InitiateSetup(ctx context.Context, userID int64, emailCode
 		return nil, ErrTotpAlreadyEnabled
 	}
 
-	// Verify identity based on email verification setting
-	if s.settingService.IsEmailVerifyEnabled(ctx) {
-		// Email verification enabled - verify email code
-		if emailCode == "" {
-			return nil, ErrVerifyCodeRequired
-		}
-		if err := s.emailService.VerifyCode(ctx, user.Email, emailCode); err != nil {
-			return nil, err
-		}
-	} else {
-		// Email verification disabled - verify password
-		if password == "" {
-			return nil, ErrPasswordRequired
-		}
-		if !user.CheckPassword(password) {
-			return nil, ErrPasswordIncorrect
-		}
+	if err := s.VerifyIdentity(ctx, user, emailCode, password); err != nil {
+		return nil, err
 	}
 
 	// Generate a new TOTP key
@@ -280,6 +276,10 @@ func (s *TotpService) CompleteSetup(ctx context.Context, userID int64, totpCode,
 	// Clean up the setup session
 	_ = s.cache.DeleteSetupSession(ctx, userID)
 
+	if s.enabledListener != nil {
+		s.enabledListener.OnTotpEnabled(ctx, userID)
+	}
+
 	return nil
 }
 
@@ -296,30 +296,37 @@ func (s *TotpService) Disable(ctx context.Context, userID int64, emailCode, pass
 		return ErrTotpNotSetup
 	}
 
-	// Verify identity based on email verification setting
+	if err := s.VerifyIdentity(ctx, user, emailCode, password); err != nil {
+		return err
+	}
+
+	// Disable TOTP
+	if err := s.userRepo.DisableTotp(ctx, userID); err != nil {
+		return fmt.Errorf("disable totp: %w", err)
+	}
+
+	return nil
+}
+
+// VerifyIdentity re-authenticates a user before a sensitive security change
+// (TOTP, passkeys, recovery codes). If email verification is enabled, emailCode
+// is required; otherwise password is required.
+func (s *TotpService) VerifyIdentity(ctx context.Context, user *User, emailCode, password string) error {
 	if s.settingService.IsEmailVerifyEnabled(ctx) {
 		// Email verification enabled - verify email code
 		if emailCode == "" {
 			return ErrVerifyCodeRequired
 		}
-		if err := s.emailService.VerifyCode(ctx, user.Email, emailCode); err != nil {
-			return err
-		}
-	} else {
-		// Email verification disabled - verify password
-		if password == "" {
-			return ErrPasswordRequired
-		}
-		if !user.CheckPassword(password) {
-			return ErrPasswordIncorrect
-		}
+		return s.emailService.VerifyCode(ctx, user.Email, emailCode)
 	}
 
-	// Disable TOTP
-	if err := s.userRepo.DisableTotp(ctx, userID); err != nil {
-		return fmt.Errorf("disable totp: %w", err)
+	// Email verification disabled - verify password
+	if password == "" {
+		return ErrPasswordRequired
+	}
+	if !user.CheckPassword(password) {
+		return ErrPasswordIncorrect
 	}
-
 	return nil
 }
 
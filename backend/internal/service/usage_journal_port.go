@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
+)
+
+// UsageBillingCommand 持久化日志中的一条使用量记录：使用日志本身以及落库时需要执行的扣费动作。
+// 扣费金额在请求路径上计算完成，消费者只负责按命令原样执行，保证重放结果与首次执行一致。
+type UsageBillingCommand struct {
+	UsageLog *UsageLog `json:"usage_log"`
+	// Billed 为 false 时只写使用日志不扣费（简单模式）
+	Billed bool `json:"billed"`
+	// SubscriptionID 非空表示订阅计费（扣减 SubscriptionCost），否则扣减用户余额 BalanceCost
+	SubscriptionID      *int64       `json:"subscription_id,omitempty"`
+	SubscriptionCost    money.Amount `json:"subscription_cost,omitempty"`
+	BalanceCost         money.Amount `json:"balance_cost,omitempty"`
+	APIKeyQuotaCost     money.Amount `json:"api_key_quota_cost,omitempty"`
+	APIKeyRateLimitCost money.Amount `json:"api_key_rate_limit_cost,omitempty"`
+	EnqueuedAt          time.Time    `json:"enqueued_at"`
+}
+
+// UsageJournalEntry 持久化日志中的一条原始条目
+type UsageJournalEntry struct {
+	ID      string
+	Payload []byte
+	// Deliveries 为本次投递后的累计投递次数（新条目为 1）
+	Deliveries int64
+}
+
+// UsageJournalClaimOptions 领取待确认条目的参数
+type UsageJournalClaimOptions struct {
+	// OwnOnly 为 true 时只领取本消费者名下的条目（启动重放）
+	OwnOnly bool
+	// MinIdle 只领取空闲超过该时长的条目
+	MinIdle time.Duration
+	// AfterID 非空时只领取 ID 大于该值的条目（分页）
+	AfterID string
+	Count   int
+}
+
+// UsageJournalStats 持久化日志运行状态（管理后台展示）
+type UsageJournalStats struct {
+	Enabled       bool   `json:"enabled"`
+	StreamKey     string `json:"stream_key"`
+	ConsumerGroup string `json:"consumer_group"`
+	Consumer      string `json:"consumer"`
+
+	// StreamLength 为 Stream 中尚未删除的条目数（已落库的条目会被删除）
+	StreamLength int64 `json:"stream_length"`
+	// Lag 为尚未投递给消费者组的条目数
+	Lag int64 `json:"lag"`
+	// PendingCount 为已投递但尚未确认的条目数
+	PendingCount     int64                        `json:"pending_count"`
+	DeadLetterLength int64                        `json:"dead_letter_length"`
+	Consumers        []*UsageJournalConsumerStats `json:"consumers"`
+
+	// 以下为本节点进程内计数
+	Appended       uint64 `json:"appended"`
+	AppendFailures uint64 `json:"append_failures"`
+	Applied        uint64 `json:"applied"`
+	Duplicates     uint64 `json:"duplicates"`
+	ApplyFailures  uint64 `json:"apply_failures"`
+	DeadLettered   uint64 `json:"dead_lettered"`
+}
+
+// UsageJournalConsumerStats 消费者组内单个消费者的状态
+type UsageJournalConsumerStats struct {
+	Name    string `json:"name"`
+	Pending int64  `json:"pending"`
+	IdleMs  int64  `json:"idle_ms"`
+}
+
+// UsageJournalRecord 管理后台展示的日志条目（待确认或死信）
+type UsageJournalRecord struct {
+	ID         string `json:"id"`
+	Consumer   string `json:"consumer,omitempty"`
+	IdleMs     int64  `json:"idle_ms,omitempty"`
+	Deliveries int64  `json:"deliveries"`
+	Reason     string `json:"reason,omitempty"`
+	SourceID   string `json:"source_id,omitempty"`
+
+	RequestID  string       `json:"request_id,omitempty"`
+	UserID     int64        `json:"user_id,omitempty"`
+	APIKeyID   int64        `json:"api_key_id,omitempty"`
+	AccountID  int64        `json:"account_id,omitempty"`
+	Model      string       `json:"model,omitempty"`
+	ActualCost money.Amount `json:"actual_cost"`
+	EnqueuedAt *time.Time   `json:"enqueued_at,omitempty"`
+
+	Payload []byte `json:"-"`
+}
+
+// UsageJournalStore 使用量持久化日志存储（Redis Stream 实现）
+type UsageJournalStore interface {
+	// Append 追加一条记录，返回条目 ID
+	Append(ctx context.Context, payload []byte) (string, error)
+	// EnsureGroup 确保消费者组存在（幂等）
+	EnsureGroup(ctx context.Context) error
+	// ReadNew 读取尚未投递给消费者组的新条目，block 为最长阻塞时间
+	ReadNew(ctx context.Context, consumer string, count int, block time.Duration) ([]UsageJournalEntry, error)
+	// ClaimPending 将符合条件的待确认条目领取到 consumer 名下
+	ClaimPending(ctx context.Context, consumer string, opts UsageJournalClaimOptions) ([]UsageJournalEntry, error)
+	// Ack 确认并删除条目
+	Ack(ctx context.Context, ids ...string) error
+	// DeadLetter 将条目转入死信流并从主流确认删除
+	DeadLetter(ctx context.Context, entry UsageJournalEntry, reason string) error
+	// Stats 返回 Stream 与消费者组状态
+	Stats(ctx context.Context) (*UsageJournalStats, error)
+	// ListPending 按 ID 顺序列出待确认条目
+	ListPending(ctx context.Context, count int) ([]*UsageJournalRecord, error)
+	// ListDeadLetters 按时间倒序列出死信条目
+	ListDeadLetters(ctx context.Context, count int) ([]*UsageJournalRecord, error)
+	// RequeueDeadLetter 将死信条目重新追加到主流，返回新条目 ID
+	RequeueDeadLetter(ctx context.Context, id string) (string, error)
+}
+
+// UsageBillingDedupRepository 账务幂等键仓储（usage_billing_dedup）
+type UsageBillingDedupRepository interface {
+	// Claim 尝试占用 (request_id, api_key_id) 幂等键；已存在（含冷归档）时返回 false。
+	// 支持事务上下文，与使用日志写入、扣费在同一事务内提交。
+	Claim(ctx context.Context, requestID string, apiKeyID int64, fingerprint string) (bool, error)
+}
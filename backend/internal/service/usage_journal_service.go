@@ -0,0 +1,617 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	dbent "github.com/Wei-Shaw/sub2api/ent"
+	"github.com/Wei-Shaw/sub2api/internal/config"
+	infraerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/logger"
+)
+
+const (
+	defaultUsageJournalBatchSize     = 100
+	defaultUsageJournalBlock         = 2 * time.Second
+	defaultUsageJournalClaimIdle     = 60 * time.Second
+	defaultUsageJournalMaxDeliveries = 10
+
+	usageJournalApplyTimeout    = 10 * time.Second
+	usageJournalRetryBackoff    = time.Second
+	usageJournalErrorLogEvery   = 10 * time.Second
+	usageJournalDedupKeyPrefix  = "journal:"
+	usageJournalMaxListRecords  = 500
+	usageJournalDefaultListSize = 50
+)
+
+// ErrUsageJournalDisabled 持久化使用量日志未启用
+var ErrUsageJournalDisabled = infraerrors.ServiceUnavailable("USAGE_JOURNAL_DISABLED", "durable usage journal is disabled")
+
+// UsageJournalService 持久化使用量日志：请求路径把使用量追加到 Redis Stream，
+// 后台消费者组以至少一次语义落库扣费。
+//
+// 可靠性说明：
+//   - 条目在“使用日志写入 + 扣费”事务提交后才被确认删除，进程崩溃时条目保留在待确认列表；
+//   - 启动时先重放本消费者名下遗留的待确认条目，运行中定期接管其他节点空闲超时的条目；
+//   - 重复投递通过 usage_billing_dedup 按 (request_id, api_key_id) 去重，与扣费同事务提交；
+//   - 超过最大投递次数的条目转入死信流，由管理员排查后重新入队。
+type UsageJournalService struct {
+	store               UsageJournalStore
+	dedupRepo           UsageBillingDedupRepository
+	usageLogRepo        UsageLogRepository
+	userRepo            UserRepository
+	userSubRepo         UserSubscriptionRepository
+	apiKeyService       *APIKeyService
+	billingCacheService *BillingCacheService
+	entClient           *dbent.Client
+	cfg                 *config.Config
+
+	enabled       bool
+	consumer      string
+	batchSize     int
+	block         time.Duration
+	claimIdle     time.Duration
+	maxDeliveries int64
+
+	appended       atomic.Uint64
+	appendFailures atomic.Uint64
+	applied        atomic.Uint64
+	duplicates     atomic.Uint64
+	applyFailures  atomic.Uint64
+	deadLettered   atomic.Uint64
+	lastErrorLog   atomic.Int64
+
+	startOnce sync.Once
+	stopOnce  sync.Once
+	cancel    context.CancelFunc
+	wg        sync.WaitGroup
+}
+
+func NewUsageJournalService(
+	store UsageJournalStore,
+	dedupRepo UsageBillingDedupRepository,
+	usageLogRepo UsageLogRepository,
+	userRepo UserRepository,
+	userSubRepo UserSubscriptionRepository,
+	apiKeyService *APIKeyService,
+	billingCacheService *BillingCacheService,
+	entClient *dbent.Client,
+	cfg *config.Config,
+) *UsageJournalService {
+	s := &UsageJournalService{
+		store:               store,
+		dedupRepo:           dedupRepo,
+		usageLogRepo:        usageLogRepo,
+		userRepo:            userRepo,
+		userSubRepo:         userSubRepo,
+		apiKeyService:       apiKeyService,
+		billingCacheService: billingCacheService,
+		entClient:           entClient,
+		cfg:                 cfg,
+		batchSize:           defaultUsageJournalBatchSize,
+		block:               defaultUsageJournalBlock,
+		claimIdle:           defaultUsageJournalClaimIdle,
+		maxDeliveries:       defaultUsageJournalMaxDeliveries,
+	}
+	if cfg != nil {
+		durable := cfg.Gateway.UsageRecord.Durable
+		s.enabled = durable.Enabled
+		s.consumer = strings.TrimSpace(durable.ConsumerName)
+		if durable.BatchSize > 0 {
+			s.batchSize = durable.BatchSize
+		}
+		if durable.BlockMilliseconds > 0 {
+			s.block = time.Duration(durable.BlockMilliseconds) * time.Millisecond
+		}
+		if durable.ClaimIdleSeconds > 0 {
+			s.claimIdle = time.Duration(durable.ClaimIdleSeconds) * time.Second
+		}
+		if durable.MaxDeliveries > 0 {
+			s.maxDeliveries = int64(durable.MaxDeliveries)
+		}
+	}
+	if s.consumer == "" {
+		s.consumer = defaultUsageJournalConsumerName()
+	}
+	return s
+}
+
+// defaultUsageJournalConsumerName 使用主机名作为消费者名称，重启后仍能找回自己名下的待确认条目。
+func defaultUsageJournalConsumerName() string {
+	if host, err := os.Hostname(); err == nil && strings.TrimSpace(host) != "" {
+		return strings.TrimSpace(host)
+	}
+	return "sub2api"
+}
+
+// Enabled 返回是否启用持久化日志（nil 安全）。
+func (s *UsageJournalService) Enabled() bool {
+	return s != nil && s.enabled && s.store != nil
+}
+
+// Start 启动后台消费者。
+func (s *UsageJournalService) Start() {
+	if !s.Enabled() {
+		return
+	}
+	if s.dedupRepo == nil || s.usageLogRepo == nil || s.userRepo == nil || s.userSubRepo == nil {
+		logger.LegacyPrintf("service.usage_journal", "[UsageJournal] not started (missing deps)")
+		return
+	}
+	s.startOnce.Do(func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		s.cancel = cancel
+		s.wg.Add(1)
+		go s.run(ctx)
+		logger.LegacyPrintf("service.usage_journal", "[UsageJournal] started (consumer=%s batch=%d claim_idle=%s max_deliveries=%d)",
+			s.consumer, s.batchSize, s.claimIdle, s.maxDeliveries)
+	})
+}
+
+// Stop 停止后台消费者；未确认的条目保留在 Stream 中，下次启动时重放。
+func (s *UsageJournalService) Stop() {
+	if s == nil {
+		return
+	}
+	s.stopOnce.Do(func() {
+		if s.cancel != nil {
+			s.cancel()
+		}
+		s.wg.Wait()
+	})
+}
+
+// TryAppend 将使用量命令追加到持久化日志。返回 false 表示未启用或追加失败，调用方应回退到同步落库。
+func (s *UsageJournalService) TryAppend(ctx context.Context, cmd *UsageBillingCommand) bool {
+	if !s.Enabled() || cmd == nil || cmd.UsageLog == nil {
+		return false
+	}
+	if cmd.EnqueuedAt.IsZero() {
+		cmd.EnqueuedAt = time.Now().UTC()
+	}
+	// 关联对象不进入日志，避免序列化整棵对象图
+	usageLog := *cmd.UsageLog
+	usageLog.User, usageLog.APIKey, usageLog.Account, usageLog.Group, usageLog.Subscription = nil, nil, nil, nil, nil
+	payloadCmd := *cmd
+	payloadCmd.UsageLog = &usageLog
+
+	payload, err := json.Marshal(&payloadCmd)
+	if err != nil {
+		s.appendFailures.Add(1)
+		logger.LegacyPrintf("service.usage_journal", "[UsageJournal] marshal failed, fallback to inline: request_id=%s err=%v", usageLog.RequestID, err)
+		return false
+	}
+	if _, err := s.store.Append(ctx, payload); err != nil {
+		s.appendFailures.Add(1)
+		logger.LegacyPrintf("service.usage_journal", "[UsageJournal] append failed, fallback to inline: request_id=%s err=%v", usageLog.RequestID, err)
+		return false
+	}
+	s.appended.Add(1)
+	return true
+}
+
+// newUsageBillingCommand 根据请求路径已计算好的费用构建日志命令。
+// billedSubscription 非空表示订阅计费；chargeQuota/chargeRateLimit 表示是否累计 API Key 配额与限速用量。
+func newUsageBillingCommand(usageLog *UsageLog, billed bool, billedSubscription *UserSubscription, cost *CostBreakdown, chargeQuota, chargeRateLimit bool) *UsageBillingCommand {
+	cmd := &UsageBillingCommand{UsageLog: usageLog, Billed: billed}
+	if cost == nil {
+		return cmd
+	}
+	if billedSubscription != nil {
+		subscriptionID := billedSubscription.ID
+		cmd.SubscriptionID = &subscriptionID
+		cmd.SubscriptionCost = cost.TotalCost
+	} else {
+		cmd.BalanceCost = cost.ActualCost
+	}
+	if chargeQuota {
+		cmd.APIKeyQuotaCost = cost.ActualCost
+	}
+	if chargeRateLimit {
+		cmd.APIKeyRateLimitCost = cost.ActualCost
+	}
+	return cmd
+}
+
+func (s *UsageJournalService) run(ctx context.Context) {
+	defer s.wg.Done()
+
+	for ctx.Err() == nil {
+		err := s.store.EnsureGroup(ctx)
+		if err == nil {
+			break
+		}
+		s.logError("ensure consumer group failed: %v", err)
+		if sleepWithContext(ctx, usageJournalRetryBackoff) != nil {
+			return
+		}
+	}
+
+	// 启动重放：本消费者崩溃前已读取但未确认的条目
+	replayed := s.replayOwnPending(ctx)
+	if replayed > 0 {
+		logger.LegacyPrintf("service.usage_journal", "[UsageJournal] replayed %d pending entries on startup", replayed)
+	}
+
+	claimInterval := s.claimIdle / 2
+	if claimInterval < time.Second {
+		claimInterval = time.Second
+	}
+	lastClaim := time.Now()
+	for ctx.Err() == nil {
+		if time.Since(lastClaim) >= claimInterval {
+			s.claimStale(ctx)
+			lastClaim = time.Now()
+		}
+
+		entries, err := s.store.ReadNew(ctx, s.consumer, s.batchSize, s.block)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			s.logError("read failed: %v", err)
+			// 消费者组可能被删除（如 Redis 重建），重新创建后继续
+			_ = s.store.EnsureGroup(ctx)
+			if sleepWithContext(ctx, usageJournalRetryBackoff) != nil {
+				return
+			}
+			continue
+		}
+		s.processEntries(ctx, entries)
+	}
+}
+
+// replayOwnPending 按 ID 分页重放本消费者名下的全部待确认条目，返回处理条数。
+func (s *UsageJournalService) replayOwnPending(ctx context.Context) int {
+	total := 0
+	afterID := ""
+	for ctx.Err() == nil {
+		entries, err := s.store.ClaimPending(ctx, s.consumer, UsageJournalClaimOptions{
+			OwnOnly: true,
+			AfterID: afterID,
+			Count:   s.batchSize,
+		})
+		if err != nil {
+			s.logError("replay pending failed: %v", err)
+			return total
+		}
+		if len(entries) == 0 {
+			return total
+		}
+		s.processEntries(ctx, entries)
+		total += len(entries)
+		afterID = entries[len(entries)-1].ID
+		if len(entries) < s.batchSize {
+			return total
+		}
+	}
+	return total
+}
+
+// claimStale 接管空闲超时的待确认条目（其他节点崩溃或本节点落库失败的条目）。
+func (s *UsageJournalService) claimStale(ctx context.Context) {
+	entries, err := s.store.ClaimPending(ctx, s.consumer, UsageJournalClaimOptions{
+		MinIdle: s.claimIdle,
+		Count:   s.batchSize,
+	})
+	if err != nil {
+		if ctx.Err() == nil {
+			s.logError("claim stale pending failed: %v", err)
+		}
+		return
+	}
+	s.processEntries(ctx, entries)
+}
+
+func (s *UsageJournalService) processEntries(ctx context.Context, entries []UsageJournalEntry) {
+	for _, entry := range entries {
+		if ctx.Err() != nil {
+			return
+		}
+		s.processEntry(ctx, entry)
+	}
+}
+
+func (s *UsageJournalService) processEntry(ctx context.Context, entry UsageJournalEntry) {
+	// 条目已被删除（已确认落库）但仍在待确认列表中：直接确认清理
+	if len(entry.Payload) == 0 {
+		if err := s.store.Ack(ctx, entry.ID); err != nil {
+			s.logError("ack empty entry %s failed: %v", entry.ID, err)
+		}
+		return
+	}
+
+	cmd, err := decodeUsageBillingCommand(entry.Payload)
+	if err != nil {
+		s.deadLetter(ctx, entry, "decode: "+err.Error())
+		return
+	}
+	if s.maxDeliveries > 0 && entry.Deliveries > s.maxDeliveries {
+		s.deadLetter(ctx, entry, fmt.Sprintf("exceeded max deliveries (%d)", s.maxDeliveries))
+		return
+	}
+
+	applyCtx, cancel := context.WithTimeout(ctx, usageJournalApplyTimeout)
+	applied, err := s.apply(applyCtx, entry.ID, cmd)
+	cancel()
+	if err != nil {
+		// 不确认：条目保留在待确认列表，空闲超时后重新领取
+		s.applyFailures.Add(1)
+		s.logError("apply entry %s failed (deliveries=%d): %v", entry.ID, entry.Deliveries, err)
+		return
+	}
+	if applied {
+		s.applied.Add(1)
+	} else {
+		s.duplicates.Add(1)
+	}
+	if err := s.store.Ack(ctx, entry.ID); err != nil {
+		s.logError("ack entry %s failed: %v", entry.ID, err)
+	}
+}
+
+func (s *UsageJournalService) deadLetter(ctx context.Context, entry UsageJournalEntry, reason string) {
+	if err := s.store.DeadLetter(ctx, entry, reason); err != nil {
+		s.logError("dead-letter entry %s failed: %v", entry.ID, err)
+		return
+	}
+	s.deadLettered.Add(1)
+	logger.LegacyPrintf("service.usage_journal", "[UsageJournal] entry %s moved to dead-letter: %s", entry.ID, reason)
+}
+
+func decodeUsageBillingCommand(payload []byte) (*UsageBillingCommand, error) {
+	var cmd UsageBillingCommand
+	if err := json.Unmarshal(payload, &cmd); err != nil {
+		return nil, err
+	}
+	if cmd.UsageLog == nil {
+		return nil, errors.New("missing usage_log")
+	}
+	if cmd.UsageLog.UserID <= 0 || cmd.UsageLog.APIKeyID <= 0 {
+		return nil, errors.New("invalid user_id/api_key_id")
+	}
+	return &cmd, nil
+}
+
+// apply 在一个事务内完成：占用幂等键 → 写使用日志 → 扣减余额/订阅用量。
+// 返回 false 表示该记录已处理过（重复投递）。
+func (s *UsageJournalService) apply(ctx context.Context, entryID string, cmd *UsageBillingCommand) (bool, error) {
+	usageLog := cmd.UsageLog
+	dedupKey := strings.TrimSpace(usageLog.RequestID)
+	if dedupKey == "" {
+		// 无 request_id 的记录以 Stream 条目 ID 去重（仅覆盖同一条目的重复投递）
+		dedupKey = usageJournalDedupKeyPrefix + entryID
+	}
+
+	billed := false
+	err := s.withTx(ctx, func(txCtx context.Context) error {
+		claimed, err := s.dedupRepo.Claim(txCtx, dedupKey, usageLog.APIKeyID, usageBillingFingerprint(usageLog))
+		if err != nil {
+			return fmt.Errorf("claim dedup: %w", err)
+		}
+		if !claimed {
+			return nil
+		}
+
+		inserted, err := s.usageLogRepo.Create(txCtx, usageLog)
+		if err != nil {
+			return fmt.Errorf("create usage log: %w", err)
+		}
+		// 使用日志已存在（如追加超时后回退到同步落库）时不重复扣费
+		if !inserted || !cmd.Billed {
+			return nil
+		}
+
+		if cmd.SubscriptionID != nil {
+			if cmd.SubscriptionCost > 0 {
+				if err := s.userSubRepo.IncrementUsage(txCtx, *cmd.SubscriptionID, cmd.SubscriptionCost); err != nil {
+					if !errors.Is(err, ErrSubscriptionNotFound) {
+						return fmt.Errorf("increment subscription usage: %w", err)
+					}
+					logger.LegacyPrintf("service.usage_journal", "[UsageJournal] subscription %d not found, usage not billed: request_id=%s", *cmd.SubscriptionID, usageLog.RequestID)
+				}
+			}
+		} else if cmd.BalanceCost > 0 {
+			if err := s.userRepo.DeductBalance(txCtx, usageLog.UserID, cmd.BalanceCost, usageLedgerSource(usageLog)); err != nil {
+				if !errors.Is(err, ErrUserNotFound) {
+					return fmt.Errorf("deduct balance: %w", err)
+				}
+				logger.LegacyPrintf("service.usage_journal", "[UsageJournal] user %d not found, usage not billed: request_id=%s", usageLog.UserID, usageLog.RequestID)
+			}
+		}
+		billed = true
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+	if !billed {
+		return false, nil
+	}
+	s.applyPostCommit(ctx, cmd)
+	return true, nil
+}
+
+// applyPostCommit 事务提交后更新 API Key 配额/限速用量与计费缓存。
+// 这些计数不参与事务（仓储不支持），失败只记录日志，与同步落库路径行为一致。
+func (s *UsageJournalService) applyPostCommit(ctx context.Context, cmd *UsageBillingCommand) {
+	usageLog := cmd.UsageLog
+	if s.billingCacheService != nil {
+		if cmd.SubscriptionID != nil {
+			if cmd.SubscriptionCost > 0 && usageLog.GroupID != nil {
+				s.billingCacheService.QueueUpdateSubscriptionUsage(usageLog.UserID, *usageLog.GroupID, cmd.SubscriptionCost)
+			}
+		} else if cmd.BalanceCost > 0 {
+			s.billingCacheService.QueueDeductBalance(usageLog.UserID, cmd.BalanceCost)
+		}
+	}
+
+	if s.apiKeyService == nil {
+		return
+	}
+	if cmd.APIKeyQuotaCost > 0 {
+		if err := s.apiKeyService.UpdateQuotaUsed(ctx, usageLog.APIKeyID, cmd.APIKeyQuotaCost); err != nil {
+			logger.LegacyPrintf("service.usage_journal", "[UsageJournal] update API key quota failed: %v", err)
+		}
+	}
+	if cmd.APIKeyRateLimitCost > 0 {
+		if err := s.apiKeyService.UpdateRateLimitUsage(ctx, usageLog.APIKeyID, cmd.APIKeyRateLimitCost); err != nil {
+			logger.LegacyPrintf("service.usage_journal", "[UsageJournal] update API key rate limit usage failed: %v", err)
+		}
+		if s.billingCacheService != nil {
+			s.billingCacheService.QueueUpdateAPIKeyRateLimitUsage(usageLog.APIKeyID, cmd.APIKeyRateLimitCost)
+		}
+	}
+}
+
+func (s *UsageJournalService) withTx(ctx context.Context, fn func(txCtx context.Context) error) error {
+	if s.entClient == nil {
+		return fn(ctx)
+	}
+	tx, err := s.entClient.Tx(ctx)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err := fn(dbent.NewTxContext(ctx, tx)); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+	return nil
+}
+
+// usageBillingFingerprint 请求指纹：同一幂等键下记录内容的摘要，便于排查重复 request_id 的冲突。
+func usageBillingFingerprint(usageLog *UsageLog) string {
+	raw := fmt.Sprintf("%s|%d|%d|%d|%s|%d|%d|%d|%d|%.10f",
+		strings.TrimSpace(usageLog.RequestID),
+		usageLog.UserID,
+		usageLog.APIKeyID,
+		usageLog.AccountID,
+		usageLog.Model,
+		usageLog.InputTokens,
+		usageLog.OutputTokens,
+		usageLog.CacheCreationTokens,
+		usageLog.CacheReadTokens,
+		usageLog.ActualCost.Float64(),
+	)
+	sum := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(sum[:])
+}
+
+func (s *UsageJournalService) logError(format string, args ...any) {
+	now := time.Now().UnixNano()
+	last := s.lastErrorLog.Load()
+	if now-last < int64(usageJournalErrorLogEvery) || !s.lastErrorLog.CompareAndSwap(last, now) {
+		return
+	}
+	logger.LegacyPrintf("service.usage_journal", "[UsageJournal] "+format, args...)
+}
+
+// ==================== Admin ====================
+
+// GetStats 返回 Stream 积压、待确认与死信统计以及本节点计数。
+func (s *UsageJournalService) GetStats(ctx context.Context) (*UsageJournalStats, error) {
+	if !s.Enabled() {
+		return &UsageJournalStats{Enabled: false, Consumers: []*UsageJournalConsumerStats{}}, nil
+	}
+	stats, err := s.store.Stats(ctx)
+	if err != nil {
+		return nil, err
+	}
+	stats.Enabled = true
+	stats.Consumer = s.consumer
+	stats.Appended = s.appended.Load()
+	stats.AppendFailures = s.appendFailures.Load()
+	stats.Applied = s.applied.Load()
+	stats.Duplicates = s.duplicates.Load()
+	stats.ApplyFailures = s.applyFailures.Load()
+	stats.DeadLettered = s.deadLettered.Load()
+	if stats.Consumers == nil {
+		stats.Consumers = []*UsageJournalConsumerStats{}
+	}
+	return stats, nil
+}
+
+// ListPending 列出待确认条目（已投递但尚未落库确认）。
+func (s *UsageJournalService) ListPending(ctx context.Context, limit int) ([]*UsageJournalRecord, error) {
+	if !s.Enabled() {
+		return nil, ErrUsageJournalDisabled
+	}
+	records, err := s.store.ListPending(ctx, normalizeUsageJournalListLimit(limit))
+	if err != nil {
+		return nil, err
+	}
+	return fillUsageJournalRecords(records), nil
+}
+
+// ListDeadLetters 列出死信条目。
+func (s *UsageJournalService) ListDeadLetters(ctx context.Context, limit int) ([]*UsageJournalRecord, error) {
+	if !s.Enabled() {
+		return nil, ErrUsageJournalDisabled
+	}
+	records, err := s.store.ListDeadLetters(ctx, normalizeUsageJournalListLimit(limit))
+	if err != nil {
+		return nil, err
+	}
+	return fillUsageJournalRecords(records), nil
+}
+
+// RequeueDeadLetter 将死信条目重新入队；幂等键保证已落库的记录不会重复扣费。
+func (s *UsageJournalService) RequeueDeadLetter(ctx context.Context, id string) (string, error) {
+	if !s.Enabled() {
+		return "", ErrUsageJournalDisabled
+	}
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return "", infraerrors.BadRequest("INVALID_USAGE_JOURNAL_ID", "invalid entry id")
+	}
+	return s.store.RequeueDeadLetter(ctx, id)
+}
+
+func normalizeUsageJournalListLimit(limit int) int {
+	if limit <= 0 {
+		return usageJournalDefaultListSize
+	}
+	if limit > usageJournalMaxListRecords {
+		return usageJournalMaxListRecords
+	}
+	return limit
+}
+
+func fillUsageJournalRecords(records []*UsageJournalRecord) []*UsageJournalRecord {
+	if records == nil {
+		return []*UsageJournalRecord{}
+	}
+	for _, rec := range records {
+		if rec == nil || len(rec.Payload) == 0 {
+			continue
+		}
+		var cmd UsageBillingCommand
+		if err := json.Unmarshal(rec.Payload, &cmd); err != nil || cmd.UsageLog == nil {
+			continue
+		}
+		rec.RequestID = cmd.UsageLog.RequestID
+		rec.UserID = cmd.UsageLog.UserID
+		rec.APIKeyID = cmd.UsageLog.APIKeyID
+		rec.AccountID = cmd.UsageLog.AccountID
+		rec.Model = cmd.UsageLog.Model
+		rec.ActualCost = cmd.UsageLog.ActualCost
+		if !cmd.EnqueuedAt.IsZero() {
+			enqueuedAt := cmd.EnqueuedAt
+			rec.EnqueuedAt = &enqueuedAt
+		}
+	}
+	return records
+}
@@ -0,0 +1,369 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/config"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
+	"github.com/stretchr/testify/require"
+)
+
+type journalStoreFake struct {
+	UsageJournalStore
+
+	mu        sync.Mutex
+	nextID    int
+	entries   map[string][]byte
+	pending   map[string]int64
+	acked     []string
+	dead      map[string]string
+	appendErr error
+	claimOpts []UsageJournalClaimOptions
+}
+
+func newJournalStoreFake() *journalStoreFake {
+	return &journalStoreFake{
+		entries: map[string][]byte{},
+		pending: map[string]int64{},
+		dead:    map[string]string{},
+	}
+}
+
+func (f *journalStoreFake) Append(ctx context.Context, payload []byte) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.appendErr != nil {
+		return "", f.appendErr
+	}
+	f.nextID++
+	id := fmt.Sprintf("%d-0", f.nextID)
+	f.entries[id] = payload
+	return id, nil
+}
+
+func (f *journalStoreFake) ClaimPending(ctx context.Context, consumer string, opts UsageJournalClaimOptions) ([]UsageJournalEntry, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.claimOpts = append(f.claimOpts, opts)
+
+	ids := make([]string, 0, len(f.pending))
+	for id := range f.pending {
+		if opts.AfterID == "" || id > opts.AfterID {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	if len(ids) > opts.Count {
+		ids = ids[:opts.Count]
+	}
+	out := make([]UsageJournalEntry, 0, len(ids))
+	for _, id := range ids {
+		f.pending[id]++
+		out = append(out, UsageJournalEntry{ID: id, Payload: f.entries[id], Deliveries: f.pending[id]})
+	}
+	return out, nil
+}
+
+func (f *journalStoreFake) Ack(ctx context.Context, ids ...string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, id := range ids {
+		f.acked = append(f.acked, id)
+		delete(f.pending, id)
+		delete(f.entries, id)
+	}
+	return nil
+}
+
+func (f *journalStoreFake) DeadLetter(ctx context.Context, entry UsageJournalEntry, reason string) error {
+	f.mu.Lock()
+	f.dead[entry.ID] = reason
+	f.mu.Unlock()
+	return f.Ack(ctx, entry.ID)
+}
+
+type journalDedupFake struct {
+	mu   sync.Mutex
+	keys map[string]string
+	err  error
+}
+
+func (f *journalDedupFake) Claim(ctx context.Context, requestID string, apiKeyID int64, fingerprint string) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.err != nil {
+		return false, f.err
+	}
+	if f.keys == nil {
+		f.keys = map[string]string{}
+	}
+	key := fmt.Sprintf("%s|%d", requestID, apiKeyID)
+	if _, ok := f.keys[key]; ok {
+		return false, nil
+	}
+	f.keys[key] = fingerprint
+	return true, nil
+}
+
+type journalUsageLogRepoFake struct {
+	UsageLogRepository
+	created []*UsageLog
+}
+
+func (r *journalUsageLogRepoFake) Create(ctx context.Context, log *UsageLog) (bool, error) {
+	r.created = append(r.created, log)
+	return true, nil
+}
+
+type journalUserRepoFake struct {
+	UserRepository
+	deducted map[int64]money.Amount
+	err      error
+}
+
+func (r *journalUserRepoFake) DeductBalance(ctx context.Context, id int64, amount money.Amount, _ BalanceLedgerSource) error {
+	if r.err != nil {
+		return r.err
+	}
+	if r.deducted == nil {
+		r.deducted = map[int64]money.Amount{}
+	}
+	r.deducted[id] += amount
+	return nil
+}
+
+type journalUserSubRepoFake struct {
+	UserSubscriptionRepository
+	usage map[int64]money.Amount
+}
+
+func (r *journalUserSubRepoFake) IncrementUsage(ctx context.Context, id int64, costUSD money.Amount) error {
+	if r.usage == nil {
+		r.usage = map[int64]money.Amount{}
+	}
+	r.usage[id] += costUSD
+	return nil
+}
+
+type usageJournalTestEnv struct {
+	svc     *UsageJournalService
+	store   *journalStoreFake
+	dedup   *journalDedupFake
+	logs    *journalUsageLogRepoFake
+	users   *journalUserRepoFake
+	userSub *journalUserSubRepoFake
+}
+
+func newUsageJournalTestEnv(t *testing.T, maxDeliveries int) *usageJournalTestEnv {
+	t.Helper()
+	cfg := &config.Config{}
+	cfg.Gateway.UsageRecord.Durable = config.GatewayUsageRecordDurableConfig{
+		Enabled:       true,
+		ConsumerName:  "node-a",
+		BatchSize:     2,
+		MaxDeliveries: maxDeliveries,
+	}
+	env := &usageJournalTestEnv{
+		store:   newJournalStoreFake(),
+		dedup:   &journalDedupFake{},
+		logs:    &journalUsageLogRepoFake{},
+		users:   &journalUserRepoFake{},
+		userSub: &journalUserSubRepoFake{},
+	}
+	env.svc = NewUsageJournalService(env.store, env.dedup, env.logs, env.users, env.userSub, nil, nil, nil, cfg)
+	return env
+}
+
+// deliver 模拟一次投递：条目进入待确认列表后由消费者处理
+func (e *usageJournalTestEnv) deliver(t *testing.T, id string) {
+	t.Helper()
+	e.store.mu.Lock()
+	e.store.pending[id]++
+	entry := UsageJournalEntry{ID: id, Payload: e.store.entries[id], Deliveries: e.store.pending[id]}
+	e.store.mu.Unlock()
+	e.svc.processEntry(context.Background(), entry)
+}
+
+func testUsageBillingCommand(requestID string) *UsageBillingCommand {
+	return &UsageBillingCommand{
+		UsageLog: &UsageLog{
+			UserID:     7,
+			APIKeyID:   9,
+			AccountID:  3,
+			RequestID:  requestID,
+			Model:      "claude-sonnet-4",
+			TotalCost:  money.MustParse("0.2"),
+			ActualCost: money.MustParse("0.3"),
+			User:       &User{ID: 7},
+		},
+		Billed:      true,
+		BalanceCost: money.MustParse("0.3"),
+	}
+}
+
+func TestUsageJournalService_TryAppendDisabledOrFailed(t *testing.T) {
+	var nilSvc *UsageJournalService
+	require.False(t, nilSvc.Enabled())
+	require.False(t, nilSvc.TryAppend(context.Background(), testUsageBillingCommand("req-1")))
+
+	disabled := NewUsageJournalService(newJournalStoreFake(), nil, nil, nil, nil, nil, nil, nil, &config.Config{})
+	require.False(t, disabled.TryAppend(context.Background(), testUsageBillingCommand("req-1")))
+
+	env := newUsageJournalTestEnv(t, 3)
+	env.store.appendErr = errors.New("redis down")
+	require.False(t, env.svc.TryAppend(context.Background(), testUsageBillingCommand("req-1")))
+	require.Equal(t, uint64(1), env.svc.appendFailures.Load())
+}
+
+func TestUsageJournalService_TryAppendStripsRelations(t *testing.T) {
+	env := newUsageJournalTestEnv(t, 3)
+	cmd := testUsageBillingCommand("req-1")
+	require.True(t, env.svc.TryAppend(context.Background(), cmd))
+	require.NotNil(t, cmd.UsageLog.User, "caller's usage log must not be mutated")
+
+	var decoded UsageBillingCommand
+	require.NoError(t, json.Unmarshal(env.store.entries["1-0"], &decoded))
+	require.Nil(t, decoded.UsageLog.User)
+	require.Equal(t, "req-1", decoded.UsageLog.RequestID)
+	require.False(t, decoded.EnqueuedAt.IsZero())
+}
+
+func TestUsageJournalService_ApplyIsIdempotentAcrossRedeliveries(t *testing.T) {
+	env := newUsageJournalTestEnv(t, 5)
+	require.True(t, env.svc.TryAppend(context.Background(), testUsageBillingCommand("req-1")))
+	require.True(t, env.svc.TryAppend(context.Background(), testUsageBillingCommand("req-1")))
+
+	env.deliver(t, "1-0")
+	env.deliver(t, "2-0")
+
+	require.Len(t, env.logs.created, 1)
+	require.Equal(t, money.MustParse("0.3"), env.users.deducted[7])
+	require.ElementsMatch(t, []string{"1-0", "2-0"}, env.store.acked)
+	require.Equal(t, uint64(1), env.svc.applied.Load())
+	require.Equal(t, uint64(1), env.svc.duplicates.Load())
+}
+
+func TestUsageJournalService_ApplySubscriptionAndSimpleMode(t *testing.T) {
+	env := newUsageJournalTestEnv(t, 5)
+
+	subscriptionID := int64(11)
+	sub := testUsageBillingCommand("req-sub")
+	sub.SubscriptionID = &subscriptionID
+	sub.SubscriptionCost = money.MustParse("0.2")
+	sub.BalanceCost = 0
+	require.True(t, env.svc.TryAppend(context.Background(), sub))
+
+	simple := testUsageBillingCommand("req-simple")
+	simple.Billed = false
+	require.True(t, env.svc.TryAppend(context.Background(), simple))
+
+	env.deliver(t, "1-0")
+	env.deliver(t, "2-0")
+
+	require.Len(t, env.logs.created, 2)
+	require.Equal(t, money.MustParse("0.2"), env.userSub.usage[11])
+	require.Empty(t, env.users.deducted)
+}
+
+func TestUsageJournalService_ApplyFailureKeepsEntryPending(t *testing.T) {
+	env := newUsageJournalTestEnv(t, 5)
+	env.users.err = errors.New("db unavailable")
+	require.True(t, env.svc.TryAppend(context.Background(), testUsageBillingCommand("req-1")))
+
+	env.deliver(t, "1-0")
+
+	require.Empty(t, env.store.acked)
+	require.Contains(t, env.store.pending, "1-0")
+	require.Equal(t, uint64(1), env.svc.applyFailures.Load())
+}
+
+func TestUsageJournalService_DeadLettersAfterMaxDeliveries(t *testing.T) {
+	env := newUsageJournalTestEnv(t, 2)
+	env.dedup.err = errors.New("db unavailable")
+	require.True(t, env.svc.TryAppend(context.Background(), testUsageBillingCommand("req-1")))
+
+	env.deliver(t, "1-0")
+	env.deliver(t, "1-0")
+	require.Empty(t, env.store.dead)
+
+	env.deliver(t, "1-0")
+	require.Contains(t, env.store.dead["1-0"], "exceeded max deliveries")
+	require.NotContains(t, env.store.pending, "1-0")
+	require.Equal(t, uint64(1), env.svc.deadLettered.Load())
+}
+
+func TestUsageJournalService_DeadLettersUndecodablePayload(t *testing.T) {
+	env := newUsageJournalTestEnv(t, 5)
+	_, err := env.store.Append(context.Background(), []byte(`{"usage_log":null}`))
+	require.NoError(t, err)
+
+	env.deliver(t, "1-0")
+	require.Contains(t, env.store.dead["1-0"], "missing usage_log")
+}
+
+func TestUsageJournalService_ReplayOwnPendingPaginates(t *testing.T) {
+	env := newUsageJournalTestEnv(t, 5)
+	for i := 1; i <= 3; i++ {
+		require.True(t, env.svc.TryAppend(context.Background(), testUsageBillingCommand(fmt.Sprintf("req-%d", i))))
+	}
+	// 模拟崩溃前已读取未确认
+	for id := range env.store.entries {
+		env.store.pending[id] = 1
+	}
+
+	replayed := env.svc.replayOwnPending(context.Background())
+
+	require.Equal(t, 3, replayed)
+	require.Len(t, env.logs.created, 3)
+	require.Empty(t, env.store.pending)
+	require.Len(t, env.store.claimOpts, 2)
+	require.True(t, env.store.claimOpts[0].OwnOnly)
+	require.Equal(t, "", env.store.claimOpts[0].AfterID)
+	require.Equal(t, "2-0", env.store.claimOpts[1].AfterID)
+}
+
+func TestUsageJournalService_EmptyRequestIDDedupsByEntryID(t *testing.T) {
+	env := newUsageJournalTestEnv(t, 5)
+	require.True(t, env.svc.TryAppend(context.Background(), testUsageBillingCommand("")))
+
+	env.deliver(t, "1-0")
+	require.Contains(t, env.dedup.keys, "journal:1-0|9")
+}
+
+func TestNewUsageBillingCommand(t *testing.T) {
+	cost := &CostBreakdown{TotalCost: money.USD, ActualCost: money.MustParse("1.5")}
+	usageLog := &UsageLog{UserID: 1, APIKeyID: 2}
+
+	balance := newUsageBillingCommand(usageLog, true, nil, cost, true, false)
+	require.Nil(t, balance.SubscriptionID)
+	require.Equal(t, money.MustParse("1.5"), balance.BalanceCost)
+	require.Equal(t, money.MustParse("1.5"), balance.APIKeyQuotaCost)
+	require.Zero(t, balance.APIKeyRateLimitCost)
+
+	sub := newUsageBillingCommand(usageLog, true, &UserSubscription{ID: 5}, cost, false, true)
+	require.Equal(t, int64(5), *sub.SubscriptionID)
+	require.Equal(t, money.USD, sub.SubscriptionCost)
+	require.Zero(t, sub.BalanceCost)
+	require.Equal(t, money.MustParse("1.5"), sub.APIKeyRateLimitCost)
+}
+
+func TestUsageJournalService_StopWithoutStart(t *testing.T) {
+	env := newUsageJournalTestEnv(t, 5)
+	done := make(chan struct{})
+	go func() {
+		env.svc.Stop()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Stop blocked")
+	}
+}
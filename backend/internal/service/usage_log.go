@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"strings"
 	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
 )
 
 const (
@@ -113,12 +115,12 @@ type UsageLog struct {
 	CacheCreation5mTokens int `gorm:"column:cache_creation_5m_tokens"`
 	CacheCreation1hTokens int `gorm:"column:cache_creation_1h_tokens"`
 
-	InputCost         float64
-	OutputCost        float64
-	CacheCreationCost float64
-	CacheReadCost     float64
-	TotalCost         float64
-	ActualCost        float64
+	InputCost         money.Amount
+	OutputCost        money.Amount
+	CacheCreationCost money.Amount
+	CacheReadCost     money.Amount
+	TotalCost         money.Amount
+	ActualCost        money.Amount
 	RateMultiplier    float64
 	// AccountRateMultiplier 账号计费倍率快照（nil 表示历史数据，按 1.0 处理）
 	AccountRateMultiplier *float64
@@ -140,6 +142,10 @@ type UsageLog struct {
 	ImageSize  *string
 	MediaType  *string
 
+	// 音频计量字段（转写/翻译按秒，语音合成按字符）
+	AudioDurationSeconds *float64
+	AudioCharacters      *int
+
 	CreatedAt time.Time
 
 	User         *User
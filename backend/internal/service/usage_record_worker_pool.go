@@ -388,6 +388,10 @@ func usageRecordPoolOptionsFromConfig(cfg *config.Config) UsageRecordWorkerPoolO
 	if cfg.Gateway.UsageRecord.OverflowSamplePercent >= 0 {
 		opts.OverflowSamplePercent = cfg.Gateway.UsageRecord.OverflowSamplePercent
 	}
+	// 持久化日志模式下任务只负责追加 Stream，丢弃任务即丢失计费，因此队列满时强制同步执行。
+	if cfg.Gateway.UsageRecord.Durable.Enabled {
+		opts.OverflowPolicy = config.UsageRecordOverflowPolicySync
+	}
 	opts.AutoScaleEnabled = cfg.Gateway.UsageRecord.AutoScaleEnabled
 	if cfg.Gateway.UsageRecord.AutoScaleMinWorkers > 0 {
 		opts.AutoScaleMinWorkers = cfg.Gateway.UsageRecord.AutoScaleMinWorkers
@@ -328,6 +328,15 @@ func TestUsageRecordWorkerPool_OptionsFromConfig_AutoScaleDisabled(t *testing.T)
 	require.Equal(t, 7*time.Second, opts.TaskTimeout)
 }
 
+func TestUsageRecordWorkerPool_OptionsFromConfig_DurableForcesSync(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Gateway.UsageRecord.OverflowPolicy = config.UsageRecordOverflowPolicyDrop
+	cfg.Gateway.UsageRecord.Durable.Enabled = true
+
+	opts := usageRecordPoolOptionsFromConfig(cfg)
+	require.Equal(t, config.UsageRecordOverflowPolicySync, opts.OverflowPolicy)
+}
+
 func TestUsageRecordWorkerPool_StringHelpers(t *testing.T) {
 	require.Equal(t, "enqueued", UsageRecordSubmitModeEnqueued.String())
 	stats := UsageRecordWorkerPoolStats{RunningWorkers: 2, WaitingTasks: 3, SubmittedTasks: 5, DroppedTasks: 1}
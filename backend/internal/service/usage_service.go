@@ -8,6 +8,7 @@ import (
 
 	dbent "github.com/Wei-Shaw/sub2api/ent"
 	infraerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
 	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
 	"github.com/Wei-Shaw/sub2api/internal/pkg/usagestats"
 )
@@ -18,26 +19,26 @@ var (
 
 // CreateUsageLogRequest 创建使用日志请求
 type CreateUsageLogRequest struct {
-	UserID                int64   `json:"user_id"`
-	APIKeyID              int64   `json:"api_key_id"`
-	AccountID             int64   `json:"account_id"`
-	RequestID             string  `json:"request_id"`
-	Model                 string  `json:"model"`
-	InputTokens           int     `json:"input_tokens"`
-	OutputTokens          int     `json:"output_tokens"`
-	CacheCreationTokens   int     `json:"cache_creation_tokens"`
-	CacheReadTokens       int     `json:"cache_read_tokens"`
-	CacheCreation5mTokens int     `json:"cache_creation_5m_tokens"`
-	CacheCreation1hTokens int     `json:"cache_creation_1h_tokens"`
-	InputCost             float64 `json:"input_cost"`
-	OutputCost            float64 `json:"output_cost"`
-	CacheCreationCost     float64 `json:"cache_creation_cost"`
-	CacheReadCost         float64 `json:"cache_read_cost"`
-	TotalCost             float64 `json:"total_cost"`
-	ActualCost            float64 `json:"actual_cost"`
-	RateMultiplier        float64 `json:"rate_multiplier"`
-	Stream                bool    `json:"stream"`
-	DurationMs            *int    `json:"duration_ms"`
+	UserID                int64        `json:"user_id"`
+	APIKeyID              int64        `json:"api_key_id"`
+	AccountID             int64        `json:"account_id"`
+	RequestID             string       `json:"request_id"`
+	Model                 string       `json:"model"`
+	InputTokens           int          `json:"input_tokens"`
+	OutputTokens          int          `json:"output_tokens"`
+	CacheCreationTokens   int          `json:"cache_creation_tokens"`
+	CacheReadTokens       int          `json:"cache_read_tokens"`
+	CacheCreation5mTokens int          `json:"cache_creation_5m_tokens"`
+	CacheCreation1hTokens int          `json:"cache_creation_1h_tokens"`
+	InputCost             money.Amount `json:"input_cost"`
+	OutputCost            money.Amount `json:"output_cost"`
+	CacheCreationCost     money.Amount `json:"cache_creation_cost"`
+	CacheReadCost         money.Amount `json:"cache_read_cost"`
+	TotalCost             money.Amount `json:"total_cost"`
+	ActualCost            money.Amount `json:"actual_cost"`
+	RateMultiplier        float64      `json:"rate_multiplier"`
+	Stream                bool         `json:"stream"`
+	DurationMs            *int         `json:"duration_ms"`
 }
 
 // UsageStats 使用统计
@@ -122,7 +123,7 @@ func (s *UsageService) Create(ctx context.Context, req CreateUsageLogRequest) (*
 	// 扣除用户余额
 	balanceUpdated := false
 	if inserted && req.ActualCost > 0 {
-		if err := s.userRepo.UpdateBalance(txCtx, req.UserID, -req.ActualCost); err != nil {
+		if err := s.userRepo.UpdateBalance(txCtx, req.UserID, -req.ActualCost, usageLedgerSource(usageLog)); err != nil {
 			return nil, fmt.Errorf("update user balance: %w", err)
 		}
 		balanceUpdated = true
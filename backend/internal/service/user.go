@@ -4,6 +4,8 @@ import (
 	"time"
 
 	"golang.org/x/crypto/bcrypt"
+
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
 )
 
 type User struct {
@@ -13,7 +15,7 @@ type User struct {
 	Notes         string
 	PasswordHash  string
 	Role          string
-	Balance       float64
+	Balance       money.Amount
 	Concurrency   int
 	Status        string
 	AllowedGroups []int64
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -154,6 +155,30 @@ func (s *UserAttributeService) GetUserAttributes(ctx context.Context, userID int
 	return s.valueRepo.GetByUserID(ctx, userID)
 }
 
+// GetUserAttributeValuesByKey retrieves a user's values of enabled attributes keyed by attribute key
+func (s *UserAttributeService) GetUserAttributeValuesByKey(ctx context.Context, userID int64) (map[string]string, error) {
+	defs, err := s.defRepo.List(ctx, true)
+	if err != nil {
+		return nil, fmt.Errorf("list definitions: %w", err)
+	}
+	values, err := s.valueRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("get user attributes: %w", err)
+	}
+
+	keyByID := make(map[int64]string, len(defs))
+	for i := range defs {
+		keyByID[defs[i].ID] = defs[i].Key
+	}
+	out := make(map[string]string, len(values))
+	for _, v := range values {
+		if key, ok := keyByID[v.AttributeID]; ok {
+			out[key] = v.Value
+		}
+	}
+	return out, nil
+}
+
 // GetBatchUserAttributes retrieves attribute values for multiple users
 // Returns a map of userID -> map of attributeID -> value
 func (s *UserAttributeService) GetBatchUserAttributes(ctx context.Context, userIDs []int64) (map[int64]map[int64]string, error) {
@@ -321,3 +346,37 @@ func validateDefinitionPattern(def *UserAttributeDefinition) error {
 	}
 	return nil
 }
+
+// SyncUserAttributesByKey upserts attribute values keyed by attribute key, as mapped from an
+// external identity provider. Unknown keys and values that fail validation are skipped and
+// returned so callers can log them; a single bad claim must not block the whole sync.
+func (s *UserAttributeService) SyncUserAttributesByKey(ctx context.Context, userID int64, values map[string]string) ([]string, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+	defs, err := s.defRepo.List(ctx, true)
+	if err != nil {
+		return nil, fmt.Errorf("list definitions: %w", err)
+	}
+
+	defByKey := make(map[string]*UserAttributeDefinition, len(defs))
+	for i := range defs {
+		defByKey[defs[i].Key] = &defs[i]
+	}
+
+	var skipped []string
+	inputs := make([]UpdateUserAttributeInput, 0, len(values))
+	for key, value := range values {
+		def, ok := defByKey[key]
+		if !ok || s.validateValue(def, value) != nil {
+			skipped = append(skipped, key)
+			continue
+		}
+		inputs = append(inputs, UpdateUserAttributeInput{AttributeID: def.ID, Value: value})
+	}
+	sort.Strings(skipped)
+	if len(inputs) == 0 {
+		return skipped, nil
+	}
+	return skipped, s.valueRepo.UpsertBatch(ctx, userID, inputs)
+}
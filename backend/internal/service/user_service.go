@@ -7,6 +7,7 @@ import (
 	"time"
 
 	infraerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
 	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
 )
 
@@ -35,8 +36,9 @@ type UserRepository interface {
 	List(ctx context.Context, params pagination.PaginationParams) ([]User, *pagination.PaginationResult, error)
 	ListWithFilters(ctx context.Context, params pagination.PaginationParams, filters UserListFilters) ([]User, *pagination.PaginationResult, error)
 
-	UpdateBalance(ctx context.Context, id int64, amount float64) error
-	DeductBalance(ctx context.Context, id int64, amount float64) error
+	// UpdateBalance/DeductBalance 变更余额并追加一条余额流水（BalanceLedgerSource 描述变动来源）
+	UpdateBalance(ctx context.Context, id int64, amount money.Amount, src BalanceLedgerSource) error
+	DeductBalance(ctx context.Context, id int64, amount money.Amount, src BalanceLedgerSource) error
 	UpdateConcurrency(ctx context.Context, id int64, amount int) error
 	ExistsByEmail(ctx context.Context, email string) (bool, error)
 	RemoveGroupFromAllowedGroups(ctx context.Context, groupID int64) (int64, error)
@@ -183,7 +185,7 @@ func (s *UserService) List(ctx context.Context, params pagination.PaginationPara
 
 // UpdateBalance 更新用户余额（管理员功能）
 func (s *UserService) UpdateBalance(ctx context.Context, userID int64, amount float64) error {
-	if err := s.userRepo.UpdateBalance(ctx, userID, amount); err != nil {
+	if err := s.userRepo.UpdateBalance(ctx, userID, money.FromFloat(amount), BalanceLedgerSource{Type: BalanceLedgerSourceAdminAdjust}); err != nil {
 		return fmt.Errorf("update balance: %w", err)
 	}
 	if s.authCacheInvalidator != nil {
@@ -10,6 +10,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
 	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
 	"github.com/stretchr/testify/require"
 )
@@ -18,7 +19,7 @@ import (
 
 type mockUserRepo struct {
 	updateBalanceErr error
-	updateBalanceFn  func(ctx context.Context, id int64, amount float64) error
+	updateBalanceFn  func(ctx context.Context, id int64, amount money.Amount) error
 }
 
 func (m *mockUserRepo) Create(context.Context, *User) error               { return nil }
@@ -33,13 +34,13 @@ func (m *mockUserRepo) List(context.Context, pagination.PaginationParams) ([]Use
 func (m *mockUserRepo) ListWithFilters(context.Context, pagination.PaginationParams, UserListFilters) ([]User, *pagination.PaginationResult, error) {
 	return nil, nil, nil
 }
-func (m *mockUserRepo) UpdateBalance(ctx context.Context, id int64, amount float64) error {
+func (m *mockUserRepo) UpdateBalance(ctx context.Context, id int64, amount money.Amount, _ BalanceLedgerSource) error {
 	if m.updateBalanceFn != nil {
 		return m.updateBalanceFn(ctx, id, amount)
 	}
 	return m.updateBalanceErr
 }
-func (m *mockUserRepo) DeductBalance(context.Context, int64, float64) error { return nil }
+func (m *mockUserRepo) DeductBalance(context.Context, int64, money.Amount, BalanceLedgerSource) error { return nil }
 func (m *mockUserRepo) UpdateConcurrency(context.Context, int64, int) error { return nil }
 func (m *mockUserRepo) ExistsByEmail(context.Context, string) (bool, error) { return false, nil }
 func (m *mockUserRepo) RemoveGroupFromAllowedGroups(context.Context, int64) (int64, error) {
@@ -74,9 +75,9 @@ type mockBillingCache struct {
 	mu                  sync.Mutex
 }
 
-func (m *mockBillingCache) GetUserBalance(context.Context, int64) (float64, error)  { return 0, nil }
-func (m *mockBillingCache) SetUserBalance(context.Context, int64, float64) error    { return nil }
-func (m *mockBillingCache) DeductUserBalance(context.Context, int64, float64) error { return nil }
+func (m *mockBillingCache) GetUserBalance(context.Context, int64) (money.Amount, error)  { return 0, nil }
+func (m *mockBillingCache) SetUserBalance(context.Context, int64, money.Amount) error    { return nil }
+func (m *mockBillingCache) DeductUserBalance(context.Context, int64, money.Amount) error { return nil }
 func (m *mockBillingCache) InvalidateUserBalance(_ context.Context, userID int64) error {
 	m.invalidateCallCount.Add(1)
 	m.mu.Lock()
@@ -90,7 +91,7 @@ func (m *mockBillingCache) GetSubscriptionCache(context.Context, int64, int64) (
 func (m *mockBillingCache) SetSubscriptionCache(context.Context, int64, int64, *SubscriptionCacheData) error {
 	return nil
 }
-func (m *mockBillingCache) UpdateSubscriptionUsage(context.Context, int64, int64, float64) error {
+func (m *mockBillingCache) UpdateSubscriptionUsage(context.Context, int64, int64, money.Amount) error {
 	return nil
 }
 func (m *mockBillingCache) InvalidateSubscriptionCache(context.Context, int64, int64) error {
@@ -102,12 +103,24 @@ func (m *mockBillingCache) GetAPIKeyRateLimit(context.Context, int64) (*APIKeyRa
 func (m *mockBillingCache) SetAPIKeyRateLimit(context.Context, int64, *APIKeyRateLimitCacheData) error {
 	return nil
 }
-func (m *mockBillingCache) UpdateAPIKeyRateLimitUsage(context.Context, int64, float64) error {
+func (m *mockBillingCache) UpdateAPIKeyRateLimitUsage(context.Context, int64, money.Amount) error {
 	return nil
 }
 func (m *mockBillingCache) InvalidateAPIKeyRateLimit(context.Context, int64) error {
 	return nil
 }
+func (m *mockBillingCache) GetOrgMemberSpend(context.Context, int64, int64) (*OrgMemberSpendCacheData, error) {
+	return nil, nil
+}
+func (m *mockBillingCache) SetOrgMemberSpend(context.Context, int64, int64, *OrgMemberSpendCacheData) error {
+	return nil
+}
+func (m *mockBillingCache) AddOrgMemberSpend(context.Context, int64, int64, string, money.Amount) error {
+	return nil
+}
+func (m *mockBillingCache) InvalidateOrgMemberSpend(context.Context, int64, int64) error {
+	return nil
+}
 
 // --- 测试 ---
 
@@ -1,6 +1,10 @@
 package service
 
-import "time"
+import (
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
+)
 
 type UserSubscription struct {
 	ID      int64
@@ -15,9 +19,9 @@ type UserSubscription struct {
 	WeeklyWindowStart  *time.Time
 	MonthlyWindowStart *time.Time
 
-	DailyUsageUSD   float64
-	WeeklyUsageUSD  float64
-	MonthlyUsageUSD float64
+	DailyUsageUSD   money.Amount
+	WeeklyUsageUSD  money.Amount
+	MonthlyUsageUSD money.Amount
 
 	AssignedBy *int64
 	AssignedAt time.Time
@@ -95,28 +99,28 @@ func (s *UserSubscription) MonthlyResetTime() *time.Time {
 	return &t
 }
 
-func (s *UserSubscription) CheckDailyLimit(group *Group, additionalCost float64) bool {
+func (s *UserSubscription) CheckDailyLimit(group *Group, additionalCost money.Amount) bool {
 	if !group.HasDailyLimit() {
 		return true
 	}
 	return s.DailyUsageUSD+additionalCost <= *group.DailyLimitUSD
 }
 
-func (s *UserSubscription) CheckWeeklyLimit(group *Group, additionalCost float64) bool {
+func (s *UserSubscription) CheckWeeklyLimit(group *Group, additionalCost money.Amount) bool {
 	if !group.HasWeeklyLimit() {
 		return true
 	}
 	return s.WeeklyUsageUSD+additionalCost <= *group.WeeklyLimitUSD
 }
 
-func (s *UserSubscription) CheckMonthlyLimit(group *Group, additionalCost float64) bool {
+func (s *UserSubscription) CheckMonthlyLimit(group *Group, additionalCost money.Amount) bool {
 	if !group.HasMonthlyLimit() {
 		return true
 	}
 	return s.MonthlyUsageUSD+additionalCost <= *group.MonthlyLimitUSD
 }
 
-func (s *UserSubscription) CheckAllLimits(group *Group, additionalCost float64) (daily, weekly, monthly bool) {
+func (s *UserSubscription) CheckAllLimits(group *Group, additionalCost money.Amount) (daily, weekly, monthly bool) {
 	daily = s.CheckDailyLimit(group, additionalCost)
 	weekly = s.CheckWeeklyLimit(group, additionalCost)
 	monthly = s.CheckMonthlyLimit(group, additionalCost)
@@ -4,6 +4,7 @@ import (
 	"context"
 	"time"
 
+	"github.com/Wei-Shaw/sub2api/internal/pkg/money"
 	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
 )
 
@@ -29,7 +30,7 @@ type UserSubscriptionRepository interface {
 	ResetDailyUsage(ctx context.Context, id int64, newWindowStart time.Time) error
 	ResetWeeklyUsage(ctx context.Context, id int64, newWindowStart time.Time) error
 	ResetMonthlyUsage(ctx context.Context, id int64, newWindowStart time.Time) error
-	IncrementUsage(ctx context.Context, id int64, costUSD float64) error
+	IncrementUsage(ctx context.Context, id int64, costUSD money.Amount) error
 
 	BatchUpdateExpiredStatus(ctx context.Context) (int64, error)
 }
@@ -0,0 +1,469 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/config"
+	infraerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/webauthn"
+)
+
+var (
+	ErrWebAuthnNotConfigured      = infraerrors.BadRequest("WEBAUTHN_NOT_CONFIGURED", "passkeys are not configured on this site")
+	ErrWebAuthnCeremonyExpired    = infraerrors.BadRequest("WEBAUTHN_CEREMONY_EXPIRED", "passkey request expired, please try again")
+	ErrWebAuthnVerificationFailed = infraerrors.BadRequest("WEBAUTHN_VERIFICATION_FAILED", "passkey verification failed")
+	ErrWebAuthnCredentialNotFound = infraerrors.NotFound("WEBAUTHN_CREDENTIAL_NOT_FOUND", "passkey not found")
+	ErrWebAuthnCredentialExists   = infraerrors.Conflict("WEBAUTHN_CREDENTIAL_EXISTS", "this passkey is already registered")
+	ErrWebAuthnCredentialLimit    = infraerrors.BadRequest("WEBAUTHN_CREDENTIAL_LIMIT", "maximum number of passkeys reached")
+	ErrWebAuthnInvalidName        = infraerrors.BadRequest("WEBAUTHN_INVALID_NAME", "passkey name must be 1-100 characters")
+)
+
+const (
+	webAuthnCeremonyTTL       = 5 * time.Minute
+	webAuthnMaxCredentials    = 10
+	webAuthnMaxNameLength     = 100
+	webAuthnDefaultCredName   = "Passkey"
+	webAuthnCeremonyRegister  = "register"
+	webAuthnCeremonyLogin     = "login"
+	webAuthnCeremonySecondary = "2fa"
+)
+
+// WebAuthnCredential 用户注册的 Passkey
+type WebAuthnCredential struct {
+	ID             int64
+	UserID         int64
+	Name           string
+	CredentialID   []byte
+	PublicKey      []byte
+	Algorithm      int64
+	SignCount      uint32
+	Transports     []string
+	AAGUID         []byte
+	BackupEligible bool
+	BackedUp       bool
+	LastUsedAt     *time.Time
+	CreatedAt      time.Time
+}
+
+// WebAuthnCeremony 注册/认证仪式的挑战会话，存放于缓存中且只能取出一次
+type WebAuthnCeremony struct {
+	Kind      string `json:"kind"`
+	UserID    int64  `json:"user_id"`
+	Challenge []byte `json:"challenge"`
+}
+
+// WebAuthnCredentialRepository Passkey 凭据存储
+type WebAuthnCredentialRepository interface {
+	Create(ctx context.Context, cred *WebAuthnCredential) error
+	GetByCredentialID(ctx context.Context, credentialID []byte) (*WebAuthnCredential, error)
+	ListByUser(ctx context.Context, userID int64) ([]WebAuthnCredential, error)
+	CountByUser(ctx context.Context, userID int64) (int, error)
+	Rename(ctx context.Context, userID, id int64, name string) error
+	Delete(ctx context.Context, userID, id int64) error
+	UpdateUsage(ctx context.Context, id int64, signCount uint32, backedUp bool, usedAt time.Time) error
+}
+
+// WebAuthnCache 挑战会话缓存
+type WebAuthnCache interface {
+	SetCeremony(ctx context.Context, key string, ceremony *WebAuthnCeremony, ttl time.Duration) error
+	// TakeCeremony 原子地取出并删除会话；不存在时返回 nil, nil
+	TakeCeremony(ctx context.Context, key string) (*WebAuthnCeremony, error)
+}
+
+// WebAuthnService Passkey 注册、无密码登录与第二因素校验
+type WebAuthnService struct {
+	credRepo    WebAuthnCredentialRepository
+	cache       WebAuthnCache
+	userRepo    UserRepository
+	authService *AuthService
+	totpService *TotpService
+	rp          *webauthn.RelyingParty // nil 表示未配置，功能关闭
+}
+
+// NewWebAuthnService 创建 Passkey 服务；RP 配置无效时记录警告并关闭功能
+func NewWebAuthnService(
+	credRepo WebAuthnCredentialRepository,
+	cache WebAuthnCache,
+	userRepo UserRepository,
+	authService *AuthService,
+	totpService *TotpService,
+	cfg *config.Config,
+) *WebAuthnService {
+	svc := &WebAuthnService{
+		credRepo:    credRepo,
+		cache:       cache,
+		userRepo:    userRepo,
+		authService: authService,
+		totpService: totpService,
+	}
+	rp := relyingPartyFromConfig(cfg)
+	if rp == nil {
+		return svc
+	}
+	if err := rp.Validate(); err != nil {
+		slog.Warn("webauthn disabled: invalid relying party configuration", "error", err)
+		return svc
+	}
+	svc.rp = rp
+	return svc
+}
+
+// relyingPartyFromConfig 优先使用 webauthn 配置，缺省项回退到 server.frontend_url
+func relyingPartyFromConfig(cfg *config.Config) *webauthn.RelyingParty {
+	if cfg == nil {
+		return nil
+	}
+	rp := &webauthn.RelyingParty{
+		ID:   strings.TrimSpace(cfg.WebAuthn.RPID),
+		Name: strings.TrimSpace(cfg.WebAuthn.RPName),
+	}
+	for _, origin := range cfg.WebAuthn.Origins {
+		if origin = strings.TrimRight(strings.TrimSpace(origin), "/"); origin != "" {
+			rp.Origins = append(rp.Origins, origin)
+		}
+	}
+	if frontend, err := url.Parse(strings.TrimSpace(cfg.Server.FrontendURL)); err == nil && frontend.Host != "" {
+		if rp.ID == "" {
+			rp.ID = frontend.Hostname()
+		}
+		if len(rp.Origins) == 0 {
+			rp.Origins = []string{frontend.Scheme + "://" + frontend.Host}
+		}
+	}
+	if rp.ID == "" && len(rp.Origins) == 0 {
+		return nil
+	}
+	if rp.Name == "" {
+		rp.Name = totpIssuer
+	}
+	return rp
+}
+
+// Enabled 是否已配置 Passkey
+func (s *WebAuthnService) Enabled() bool {
+	return s != nil && s.rp != nil
+}
+
+// BeginRegistration 开始注册 Passkey，需先通过邮箱验证码或密码再次确认身份
+func (s *WebAuthnService) BeginRegistration(ctx context.Context, userID int64, emailCode, password string) (*webauthn.CreationOptions, error) {
+	if !s.Enabled() {
+		return nil, ErrWebAuthnNotConfigured
+	}
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("get user: %w", err)
+	}
+	if err := s.totpService.VerifyIdentity(ctx, user, emailCode, password); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.credRepo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list passkeys: %w", err)
+	}
+	if len(existing) >= webAuthnMaxCredentials {
+		return nil, ErrWebAuthnCredentialLimit
+	}
+
+	challenge, err := s.newCeremony(ctx, webAuthnRegisterKey(userID), webAuthnCeremonyRegister, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	displayName := user.Username
+	if displayName == "" {
+		displayName = user.Email
+	}
+	return s.rp.NewCreationOptions(challenge, webAuthnUserHandle(userID), user.Email, displayName,
+		credentialDescriptors(existing), webAuthnCeremonyTTL.Milliseconds()), nil
+}
+
+// FinishRegistration 校验浏览器返回的注册响应并保存凭据
+func (s *WebAuthnService) FinishRegistration(ctx context.Context, userID int64, name string, response json.RawMessage) (*WebAuthnCredential, error) {
+	if !s.Enabled() {
+		return nil, ErrWebAuthnNotConfigured
+	}
+	name, err := normalizeWebAuthnName(name, true)
+	if err != nil {
+		return nil, err
+	}
+
+	ceremony, err := s.takeCeremony(ctx, webAuthnRegisterKey(userID), webAuthnCeremonyRegister)
+	if err != nil {
+		return nil, err
+	}
+	if ceremony.UserID != userID {
+		return nil, ErrWebAuthnCeremonyExpired
+	}
+
+	resp, err := webauthn.ParseRegistrationResponse(response)
+	if err != nil {
+		slog.Debug("webauthn_registration_parse_failed", "user_id", userID, "error", err)
+		return nil, ErrWebAuthnVerificationFailed
+	}
+	verified, err := s.rp.VerifyRegistration(resp, ceremony.Challenge, false)
+	if err != nil {
+		slog.Debug("webauthn_registration_verify_failed", "user_id", userID, "error", err)
+		return nil, ErrWebAuthnVerificationFailed
+	}
+
+	count, err := s.credRepo.CountByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("count passkeys: %w", err)
+	}
+	if count >= webAuthnMaxCredentials {
+		return nil, ErrWebAuthnCredentialLimit
+	}
+
+	cred := &WebAuthnCredential{
+		UserID:         userID,
+		Name:           name,
+		CredentialID:   verified.ID,
+		PublicKey:      verified.PublicKey,
+		Algorithm:      verified.Algorithm,
+		SignCount:      verified.SignCount,
+		Transports:     verified.Transports,
+		AAGUID:         verified.AAGUID,
+		BackupEligible: verified.BackupEligible,
+		BackedUp:       verified.BackedUp,
+	}
+	if err := s.credRepo.Create(ctx, cred); err != nil {
+		return nil, err
+	}
+	return cred, nil
+}
+
+// ListCredentials 列出用户的 Passkey
+func (s *WebAuthnService) ListCredentials(ctx context.Context, userID int64) ([]WebAuthnCredential, error) {
+	return s.credRepo.ListByUser(ctx, userID)
+}
+
+// RenameCredential 重命名 Passkey
+func (s *WebAuthnService) RenameCredential(ctx context.Context, userID, id int64, name string) error {
+	name, err := normalizeWebAuthnName(name, false)
+	if err != nil {
+		return err
+	}
+	return s.credRepo.Rename(ctx, userID, id, name)
+}
+
+// DeleteCredential 删除 Passkey；删除会削弱账户保护，因此同样需要再次确认身份
+func (s *WebAuthnService) DeleteCredential(ctx context.Context, userID, id int64, emailCode, password string) error {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("get user: %w", err)
+	}
+	if err := s.totpService.VerifyIdentity(ctx, user, emailCode, password); err != nil {
+		return err
+	}
+	return s.credRepo.Delete(ctx, userID, id)
+}
+
+// HasCredentials 用户是否注册了可用的 Passkey（未配置时视为没有）
+func (s *WebAuthnService) HasCredentials(ctx context.Context, userID int64) (bool, error) {
+	if !s.Enabled() {
+		return false, nil
+	}
+	count, err := s.credRepo.CountByUser(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// BeginLogin 开始无密码登录，返回会话 ID 与认证选项（由浏览器列出可发现凭据）
+func (s *WebAuthnService) BeginLogin(ctx context.Context) (string, *webauthn.RequestOptions, error) {
+	if !s.Enabled() {
+		return "", nil, ErrWebAuthnNotConfigured
+	}
+	sessionID, err := generateRandomToken(32)
+	if err != nil {
+		return "", nil, fmt.Errorf("generate session id: %w", err)
+	}
+	challenge, err := s.newCeremony(ctx, webAuthnLoginKey(sessionID), webAuthnCeremonyLogin, 0)
+	if err != nil {
+		return "", nil, err
+	}
+	return sessionID, s.rp.NewRequestOptions(challenge, nil, "required", webAuthnCeremonyTTL.Milliseconds()), nil
+}
+
+// FinishLogin 完成无密码登录。Passkey 本身即包含持有因素与用户验证（UV），不再要求第二因素。
+func (s *WebAuthnService) FinishLogin(ctx context.Context, sessionID string, response json.RawMessage) (*User, error) {
+	if !s.Enabled() {
+		return nil, ErrWebAuthnNotConfigured
+	}
+	ceremony, err := s.takeCeremony(ctx, webAuthnLoginKey(sessionID), webAuthnCeremonyLogin)
+	if err != nil {
+		return nil, err
+	}
+
+	cred, err := s.verifyAssertion(ctx, ceremony, response, 0, true)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.GetByID(ctx, cred.UserID)
+	if err != nil {
+		if errors.Is(err, ErrUserNotFound) {
+			return nil, ErrWebAuthnVerificationFailed
+		}
+		return nil, fmt.Errorf("get user: %w", err)
+	}
+	if err := s.authService.CheckPasskeyLogin(ctx, user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// BeginSecondFactor 为密码登录后的 2FA 会话生成认证选项，仅允许该用户自己的凭据
+func (s *WebAuthnService) BeginSecondFactor(ctx context.Context, tempToken string, userID int64) (*webauthn.RequestOptions, error) {
+	if !s.Enabled() {
+		return nil, ErrWebAuthnNotConfigured
+	}
+	creds, err := s.credRepo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list passkeys: %w", err)
+	}
+	if len(creds) == 0 {
+		return nil, ErrWebAuthnCredentialNotFound
+	}
+	challenge, err := s.newCeremony(ctx, webAuthnSecondFactorKey(tempToken), webAuthnCeremonySecondary, userID)
+	if err != nil {
+		return nil, err
+	}
+	// 密码已验证，此处只需证明持有认证器
+	return s.rp.NewRequestOptions(challenge, credentialDescriptors(creds), "discouraged", webAuthnCeremonyTTL.Milliseconds()), nil
+}
+
+// VerifySecondFactor 校验 2FA 会话中的 Passkey 断言
+func (s *WebAuthnService) VerifySecondFactor(ctx context.Context, tempToken string, userID int64, response json.RawMessage) error {
+	if !s.Enabled() {
+		return ErrWebAuthnNotConfigured
+	}
+	ceremony, err := s.takeCeremony(ctx, webAuthnSecondFactorKey(tempToken), webAuthnCeremonySecondary)
+	if err != nil {
+		return err
+	}
+	if ceremony.UserID != userID {
+		return ErrWebAuthnCeremonyExpired
+	}
+	_, err = s.verifyAssertion(ctx, ceremony, response, userID, false)
+	return err
+}
+
+// verifyAssertion 校验断言并更新计数器；expectedUserID 非 0 时凭据必须属于该用户
+func (s *WebAuthnService) verifyAssertion(ctx context.Context, ceremony *WebAuthnCeremony, response json.RawMessage, expectedUserID int64, requireUV bool) (*WebAuthnCredential, error) {
+	resp, err := webauthn.ParseAuthenticationResponse(response)
+	if err != nil {
+		slog.Debug("webauthn_assertion_parse_failed", "error", err)
+		return nil, ErrWebAuthnVerificationFailed
+	}
+	credID, err := resp.CredentialID()
+	if err != nil {
+		slog.Debug("webauthn_assertion_parse_failed", "error", err)
+		return nil, ErrWebAuthnVerificationFailed
+	}
+
+	cred, err := s.credRepo.GetByCredentialID(ctx, credID)
+	if err != nil {
+		if errors.Is(err, ErrWebAuthnCredentialNotFound) {
+			return nil, ErrWebAuthnVerificationFailed
+		}
+		return nil, fmt.Errorf("get passkey: %w", err)
+	}
+	if expectedUserID != 0 && cred.UserID != expectedUserID {
+		return nil, ErrWebAuthnVerificationFailed
+	}
+	// 可发现凭据会回传 user handle，必须与凭据所属用户一致
+	if handle, err := resp.UserHandleBytes(); err != nil || (handle != nil && string(handle) != string(webAuthnUserHandle(cred.UserID))) {
+		return nil, ErrWebAuthnVerificationFailed
+	}
+
+	result, err := s.rp.VerifyAssertion(resp, ceremony.Challenge, cred.PublicKey, cred.SignCount, requireUV)
+	if err != nil {
+		if errors.Is(err, webauthn.ErrSignCountReplay) {
+			slog.Warn("webauthn signature counter regressed, possible cloned authenticator",
+				"user_id", cred.UserID, "credential_id", cred.ID)
+		} else {
+			slog.Debug("webauthn_assertion_verify_failed", "user_id", cred.UserID, "error", err)
+		}
+		return nil, ErrWebAuthnVerificationFailed
+	}
+
+	if err := s.credRepo.UpdateUsage(ctx, cred.ID, result.SignCount, result.BackedUp, time.Now()); err != nil {
+		return nil, fmt.Errorf("update passkey usage: %w", err)
+	}
+	return cred, nil
+}
+
+func (s *WebAuthnService) newCeremony(ctx context.Context, key, kind string, userID int64) ([]byte, error) {
+	challenge := make([]byte, webauthn.ChallengeSize)
+	if _, err := rand.Read(challenge); err != nil {
+		return nil, fmt.Errorf("generate challenge: %w", err)
+	}
+	ceremony := &WebAuthnCeremony{Kind: kind, UserID: userID, Challenge: challenge}
+	if err := s.cache.SetCeremony(ctx, key, ceremony, webAuthnCeremonyTTL); err != nil {
+		return nil, fmt.Errorf("store webauthn ceremony: %w", err)
+	}
+	return challenge, nil
+}
+
+func (s *WebAuthnService) takeCeremony(ctx context.Context, key, kind string) (*WebAuthnCeremony, error) {
+	ceremony, err := s.cache.TakeCeremony(ctx, key)
+	if err != nil {
+		return nil, fmt.Errorf("load webauthn ceremony: %w", err)
+	}
+	if ceremony == nil || ceremony.Kind != kind {
+		return nil, ErrWebAuthnCeremonyExpired
+	}
+	return ceremony, nil
+}
+
+func webAuthnRegisterKey(userID int64) string {
+	return fmt.Sprintf("%s:%d", webAuthnCeremonyRegister, userID)
+}
+
+func webAuthnLoginKey(sessionID string) string {
+	return webAuthnCeremonyLogin + ":" + sessionID
+}
+
+func webAuthnSecondFactorKey(tempToken string) string {
+	return webAuthnCeremonySecondary + ":" + tempToken
+}
+
+// webAuthnUserHandle 以用户 ID 的 8 字节大端编码作为 user handle（不含邮箱等个人信息）
+func webAuthnUserHandle(userID int64) []byte {
+	return binary.BigEndian.AppendUint64(nil, uint64(userID))
+}
+
+func credentialDescriptors(creds []WebAuthnCredential) []webauthn.CredentialDescriptor {
+	out := make([]webauthn.CredentialDescriptor, 0, len(creds))
+	for _, c := range creds {
+		out = append(out, webauthn.CredentialDescriptor{
+			Type:       "public-key",
+			ID:         webauthn.EncodeBase64URL(c.CredentialID),
+			Transports: c.Transports,
+		})
+	}
+	return out
+}
+
+func normalizeWebAuthnName(name string, allowDefault bool) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" && allowDefault {
+		name = webAuthnDefaultCredName
+	}
+	if name == "" || len([]rune(name)) > webAuthnMaxNameLength {
+		return "", ErrWebAuthnInvalidName
+	}
+	return name, nil
+}
@@ -0,0 +1,107 @@
+//go:build unit
+
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/config"
+	"github.com/stretchr/testify/require"
+)
+
+// ---- fakes ----
+
+type webAuthnCacheFake struct {
+	ceremonies map[string]*WebAuthnCeremony
+}
+
+func (c *webAuthnCacheFake) SetCeremony(ctx context.Context, key string, ceremony *WebAuthnCeremony, ttl time.Duration) error {
+	c.ceremonies[key] = ceremony
+	return nil
+}
+
+func (c *webAuthnCacheFake) TakeCeremony(ctx context.Context, key string) (*WebAuthnCeremony, error) {
+	ceremony := c.ceremonies[key]
+	delete(c.ceremonies, key)
+	return ceremony, nil
+}
+
+type webAuthnCredRepoFake struct {
+	WebAuthnCredentialRepository
+	creds []WebAuthnCredential
+}
+
+func (r *webAuthnCredRepoFake) ListByUser(ctx context.Context, userID int64) ([]WebAuthnCredential, error) {
+	var out []WebAuthnCredential
+	for _, c := range r.creds {
+		if c.UserID == userID {
+			out = append(out, c)
+		}
+	}
+	return out, nil
+}
+
+func (r *webAuthnCredRepoFake) CountByUser(ctx context.Context, userID int64) (int, error) {
+	out, _ := r.ListByUser(ctx, userID)
+	return len(out), nil
+}
+
+// ---- tests ----
+
+func TestRelyingPartyFromConfig(t *testing.T) {
+	require.Nil(t, relyingPartyFromConfig(&config.Config{}))
+
+	cfg := &config.Config{}
+	cfg.Server.FrontendURL = "https://app.example.com/console"
+	rp := relyingPartyFromConfig(cfg)
+	require.Equal(t, "app.example.com", rp.ID)
+	require.Equal(t, []string{"https://app.example.com"}, rp.Origins)
+	require.Equal(t, totpIssuer, rp.Name)
+
+	cfg.WebAuthn.RPID = "example.com"
+	cfg.WebAuthn.RPName = "Example"
+	cfg.WebAuthn.Origins = []string{"https://example.com/", " https://app.example.com "}
+	rp = relyingPartyFromConfig(cfg)
+	require.Equal(t, "example.com", rp.ID)
+	require.Equal(t, []string{"https://example.com", "https://app.example.com"}, rp.Origins)
+	require.NoError(t, rp.Validate())
+}
+
+func TestWebAuthnService_DisabledWithoutConfig(t *testing.T) {
+	svc := NewWebAuthnService(&webAuthnCredRepoFake{creds: []WebAuthnCredential{{UserID: 7}}}, &webAuthnCacheFake{}, nil, nil, nil, &config.Config{})
+	require.False(t, svc.Enabled())
+
+	has, err := svc.HasCredentials(context.Background(), 7)
+	require.NoError(t, err)
+	require.False(t, has, "passkeys must not force 2FA when WebAuthn is unconfigured")
+
+	_, _, err = svc.BeginLogin(context.Background())
+	require.ErrorIs(t, err, ErrWebAuthnNotConfigured)
+}
+
+func TestWebAuthnService_SecondFactorCeremonyIsBoundToSession(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Server.FrontendURL = "https://example.com"
+	cache := &webAuthnCacheFake{ceremonies: make(map[string]*WebAuthnCeremony)}
+	repo := &webAuthnCredRepoFake{creds: []WebAuthnCredential{{ID: 1, UserID: 7, CredentialID: []byte("cred-1"), Transports: []string{"internal"}}}}
+	svc := NewWebAuthnService(repo, cache, nil, nil, nil, cfg)
+	require.True(t, svc.Enabled())
+	ctx := context.Background()
+
+	options, err := svc.BeginSecondFactor(ctx, "temp-token", 7)
+	require.NoError(t, err)
+	require.Equal(t, "example.com", options.RPID)
+	require.Len(t, options.AllowCredentials, 1)
+
+	_, err = svc.BeginSecondFactor(ctx, "other-token", 8)
+	require.ErrorIs(t, err, ErrWebAuthnCredentialNotFound)
+
+	// 其他用户无法使用该 2FA 会话的挑战，且挑战在取出后立即失效
+	err = svc.VerifySecondFactor(ctx, "temp-token", 8, json.RawMessage(`{}`))
+	require.ErrorIs(t, err, ErrWebAuthnCeremonyExpired)
+	err = svc.VerifySecondFactor(ctx, "temp-token", 7, json.RawMessage(`{}`))
+	require.ErrorIs(t, err, ErrWebAuthnCeremonyExpired)
+}
@@ -8,6 +8,7 @@ import (
 	dbent "github.com/Wei-Shaw/sub2api/ent"
 	"github.com/Wei-Shaw/sub2api/internal/config"
 	"github.com/Wei-Shaw/sub2api/internal/pkg/logger"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/metrics"
 	"github.com/google/wire"
 	"github.com/redis/go-redis/v9"
 )
@@ -48,9 +49,11 @@ func ProvideRedeemService(
 	entClient *dbent.Client,
 	authCacheInvalidator APIKeyAuthCacheInvalidator,
 	distributorService *DistributorService,
+	promoService *PromoService,
 ) *RedeemService {
 	svc := NewRedeemService(redeemRepo, userRepo, subscriptionService, settingService, cache, billingCacheService, entClient, authCacheInvalidator)
 	svc.SetDistributorService(distributorService)
+	svc.SetPromoService(promoService)
 	return svc
 }
 
@@ -88,6 +91,38 @@ func ProvideUsageCleanupService(repo UsageCleanupRepository, timingWheel *Timing
 	return svc
 }
 
+// ProvideUsageJournalService 创建并启动持久化使用量日志消费者（未启用时不启动）
+func ProvideUsageJournalService(
+	store UsageJournalStore,
+	dedupRepo UsageBillingDedupRepository,
+	usageLogRepo UsageLogRepository,
+	userRepo UserRepository,
+	userSubRepo UserSubscriptionRepository,
+	apiKeyService *APIKeyService,
+	billingCacheService *BillingCacheService,
+	entClient *dbent.Client,
+	cfg *config.Config,
+) *UsageJournalService {
+	svc := NewUsageJournalService(store, dedupRepo, usageLogRepo, userRepo, userSubRepo, apiKeyService, billingCacheService, entClient, cfg)
+	svc.Start()
+	return svc
+}
+
+// ProvideBatchService 创建并启动批处理任务执行器
+func ProvideBatchService(
+	repo BatchRepository,
+	apiKeyRepo APIKeyRepository,
+	accountRepo AccountRepository,
+	concurrencyService *ConcurrencyService,
+	timingWheel *TimingWheelService,
+	billingHoldService *BillingHoldService,
+	cfg *config.Config,
+) *BatchService {
+	svc := NewBatchService(repo, apiKeyRepo, accountRepo, concurrencyService, timingWheel, billingHoldService, cfg)
+	svc.Start()
+	return svc
+}
+
 // ProvideAccountExpiryService creates and starts AccountExpiryService.
 func ProvideAccountExpiryService(accountRepo AccountRepository) *AccountExpiryService {
 	svc := NewAccountExpiryService(accountRepo, time.Minute)
@@ -95,6 +130,13 @@ func ProvideAccountExpiryService(accountRepo AccountRepository) *AccountExpirySe
 	return svc
 }
 
+// ProvideBalanceLedgerService 创建余额流水服务并启动定时对账
+func ProvideBalanceLedgerService(repo BalanceLedgerRepository, cfg *config.Config) *BalanceLedgerService {
+	svc := NewBalanceLedgerService(repo, cfg)
+	svc.Start()
+	return svc
+}
+
 // ProvideSubscriptionExpiryService creates and starts SubscriptionExpiryService.
 func ProvideSubscriptionExpiryService(userSubRepo UserSubscriptionRepository) *SubscriptionExpiryService {
 	svc := NewSubscriptionExpiryService(userSubRepo, time.Minute)
@@ -187,6 +229,24 @@ func ProvideOpsMetricsCollector(
 	return collector
 }
 
+// ProvidePrometheusMetricsCollector creates the scrape-time collector and registers it when metrics are enabled.
+func ProvidePrometheusMetricsCollector(
+	accountRepo AccountRepository,
+	concurrencyService *ConcurrencyService,
+	openAIGateway *OpenAIGatewayService,
+	usageRecordWorkerPool *UsageRecordWorkerPool,
+	schedulerSnapshot *SchedulerSnapshotService,
+	cfg *config.Config,
+) *PrometheusMetricsCollector {
+	collector := NewPrometheusMetricsCollector(accountRepo, concurrencyService, openAIGateway, usageRecordWorkerPool, schedulerSnapshot, cfg)
+	if cfg.Metrics.Enabled {
+		if err := metrics.Register(collector); err != nil {
+			logger.LegacyPrintf("service.prometheus_metrics", "[Metrics] register collector failed: %v", err)
+		}
+	}
+	return collector
+}
+
 // ProvideOpsAggregationService creates and starts OpsAggregationService (hourly/daily pre-aggregation).
 func ProvideOpsAggregationService(
 	opsRepo OpsRepository,
@@ -200,15 +260,25 @@ func ProvideOpsAggregationService(
 	return svc
 }
 
+// ProvideOpsNotificationService creates and starts OpsNotificationService.
+func ProvideOpsNotificationService(opsRepo OpsRepository, cfg *config.Config) *OpsNotificationService {
+	svc := NewOpsNotificationService(opsRepo, cfg)
+	svc.Start()
+	return svc
+}
+
 // ProvideOpsAlertEvaluatorService creates and starts OpsAlertEvaluatorService.
 func ProvideOpsAlertEvaluatorService(
 	opsService *OpsService,
 	opsRepo OpsRepository,
 	emailService *EmailService,
+	notificationService *OpsNotificationService,
 	redisClient *redis.Client,
+	balanceLedgerService *BalanceLedgerService,
 	cfg *config.Config,
 ) *OpsAlertEvaluatorService {
-	svc := NewOpsAlertEvaluatorService(opsService, opsRepo, emailService, redisClient, cfg)
+	svc := NewOpsAlertEvaluatorService(opsService, opsRepo, emailService, notificationService, redisClient, cfg)
+	svc.SetBalanceLedgerService(balanceLedgerService)
 	svc.Start()
 	return svc
 }
@@ -295,15 +365,71 @@ func ProvideIdempotencyCleanupService(repo IdempotencyRepository, cfg *config.Co
 	return svc
 }
 
+func ProvideLoginHistoryCleanupService(repo LoginHistoryRepository, cfg *config.Config) *LoginHistoryCleanupService {
+	svc := NewLoginHistoryCleanupService(repo, cfg)
+	svc.Start()
+	return svc
+}
+
+// ProvidePaymentService creates PaymentService and starts its order maintenance loop.
+func ProvidePaymentService(
+	productRepo PaymentProductRepository,
+	orderRepo PaymentOrderRepository,
+	groupRepo GroupRepository,
+	userRepo UserRepository,
+	redeemService *RedeemService,
+	subscriptionService *SubscriptionService,
+	couponService *CouponService,
+	billingCacheService *BillingCacheService,
+	authCacheInvalidator APIKeyAuthCacheInvalidator,
+	providers PaymentProviders,
+	cfg *config.Config,
+) *PaymentService {
+	svc := NewPaymentService(productRepo, orderRepo, groupRepo, userRepo, redeemService, subscriptionService, couponService, billingCacheService, authCacheInvalidator, providers, cfg)
+	svc.Start()
+	return svc
+}
+
+// ProvideReferralService creates ReferralService and starts its commission settlement loop.
+func ProvideReferralService(
+	repo ReferralRepository,
+	userRepo UserRepository,
+	billingCacheService *BillingCacheService,
+	authCacheInvalidator APIKeyAuthCacheInvalidator,
+	entClient *dbent.Client,
+	cfg *config.Config,
+) *ReferralService {
+	svc := NewReferralService(repo, userRepo, billingCacheService, authCacheInvalidator, entClient, cfg)
+	svc.Start()
+	return svc
+}
+
+// ProvideActivityService creates ActivityService, subscribes it to TOTP enable events
+// and starts the task progress loop.
+func ProvideActivityService(
+	entClient *dbent.Client,
+	userRepo UserRepository,
+	taskRepo ActivityTaskRepository,
+	pointsService *PointsService,
+	couponService *CouponService,
+	totpService *TotpService,
+) *ActivityService {
+	svc := NewActivityService(entClient, userRepo, taskRepo, pointsService, couponService)
+	totpService.SetEnabledListener(svc)
+	svc.Start()
+	return svc
+}
+
 // ProvideOpsScheduledReportService creates and starts OpsScheduledReportService.
 func ProvideOpsScheduledReportService(
 	opsService *OpsService,
 	userService *UserService,
 	emailService *EmailService,
+	notificationService *OpsNotificationService,
 	redisClient *redis.Client,
 	cfg *config.Config,
 ) *OpsScheduledReportService {
-	svc := NewOpsScheduledReportService(opsService, userService, emailService, redisClient, cfg)
+	svc := NewOpsScheduledReportService(opsService, userService, emailService, notificationService, redisClient, cfg)
 	svc.Start()
 	return svc
 }
@@ -329,6 +455,24 @@ func ProvideSettingService(settingRepo SettingRepository, groupRepo GroupReposit
 	return svc
 }
 
+// ProvideSSOService wires SSOService and registers it as the password login policy (enforce_sso).
+func ProvideSSOService(
+	repo SSOProviderRepository,
+	client SSOProviderClient,
+	encryptor SecretEncryptor,
+	userRepo UserRepository,
+	groupRepo GroupRepository,
+	authService *AuthService,
+	settingService *SettingService,
+	userAttributeService *UserAttributeService,
+	entClient *dbent.Client,
+	cfg *config.Config,
+) *SSOService {
+	svc := NewSSOService(repo, client, encryptor, userRepo, groupRepo, authService, settingService, userAttributeService, entClient, cfg)
+	authService.SetPasswordLoginPolicy(svc)
+	return svc
+}
+
 // ProviderSet is the Wire provider set for all services
 var ProviderSet = wire.NewSet(
 	// Core services
@@ -341,6 +485,8 @@ var ProviderSet = wire.NewSet(
 	NewProxyService,
 	NewDistributorService,
 	ProvideRedeemService,
+	NewOrganizationService,
+	ProvideSSOService,
 	NewPromoService,
 	NewUsageService,
 	NewDashboardService,
@@ -348,6 +494,7 @@ var ProviderSet = wire.NewSet(
 	ProvidePricingService,
 	NewBillingService,
 	NewBillingCacheService,
+	NewBillingHoldService,
 	NewAnnouncementService,
 	NewAdminService,
 	NewGatewayService,
@@ -383,7 +530,9 @@ var ProviderSet = wire.NewSet(
 	NewSecurityChatService,
 	NewSecurityChatAIService,
 	ProvideOpsMetricsCollector,
+	ProvidePrometheusMetricsCollector,
 	ProvideOpsAggregationService,
+	ProvideOpsNotificationService,
 	ProvideOpsAlertEvaluatorService,
 	ProvideOpsCleanupService,
 	ProvideOpsScheduledReportService,
@@ -393,8 +542,10 @@ var ProviderSet = wire.NewSet(
 	NewSubscriptionService,
 	wire.Bind(new(DefaultSubscriptionAssigner), new(*SubscriptionService)),
 	ProvideConcurrencyService,
+	NewAPIKeyTrafficLimitService,
 	ProvideUserMessageQueueService,
 	NewUsageRecordWorkerPool,
+	ProvideUsageJournalService,
 	ProvideSchedulerSnapshotService,
 	NewIdentityService,
 	NewCRSSyncService,
@@ -402,17 +553,29 @@ var ProviderSet = wire.NewSet(
 	ProvideTokenRefreshService,
 	ProvideAccountExpiryService,
 	ProvideSubscriptionExpiryService,
+	ProvideBalanceLedgerService,
 	ProvideTimingWheelService,
 	ProvideDashboardAggregationService,
 	ProvideUsageCleanupService,
+	ProvideBatchService,
 	ProvideSecurityChatCleanupService,
 	ProvideDeferredService,
 	NewAntigravityQuotaFetcher,
 	NewUserAttributeService,
 	NewUsageCache,
 	NewTotpService,
+	NewWebAuthnService,
+	NewRecoveryCodeService,
+	NewLoginHistoryService,
+	ProvideLoginHistoryCleanupService,
+	ProvidePaymentService,
+	ProvideReferralService,
+	ProvideActivityService,
+	NewPointsService,
+	NewCouponService,
 	NewErrorPassthroughService,
 	NewDigestSessionStore,
+	NewResponsesConversationService,
 	ProvideIdempotencyCoordinator,
 	ProvideSystemOperationLockService,
 	ProvideIdempotencyCleanupService,
@@ -52,6 +52,16 @@ func (c StubConcurrencyCache) IncrementWaitCount(_ context.Context, _ int64, _ i
 	return true, nil
 }
 func (c StubConcurrencyCache) DecrementWaitCount(_ context.Context, _ int64) error { return nil }
+func (c StubConcurrencyCache) AcquireAPIKeySlot(_ context.Context, _ int64, _ int, _ string) (bool, error) {
+	return true, nil
+}
+func (c StubConcurrencyCache) ReleaseAPIKeySlot(_ context.Context, _ int64, _ string) error {
+	return nil
+}
+func (c StubConcurrencyCache) IncrementAPIKeyWaitCount(_ context.Context, _ int64, _ int) (bool, error) {
+	return true, nil
+}
+func (c StubConcurrencyCache) DecrementAPIKeyWaitCount(_ context.Context, _ int64) error { return nil }
 func (c StubConcurrencyCache) GetAccountsLoadBatch(_ context.Context, accounts []service.AccountWithConcurrency) (map[int64]*service.AccountLoadInfo, error) {
 	result := make(map[int64]*service.AccountLoadInfo, len(accounts))
 	for _, acc := range accounts {
@@ -110,8 +110,17 @@ func FilterHeaders(src http.Header, filter *CompiledHeaderFilter) http.Header {
 func WriteFilteredHeaders(dst http.Header, src http.Header, filter *CompiledHeaderFilter) {
 	filtered := FilterHeaders(src, filter)
 	for key, values := range filtered {
+		// 网关已写入的限流头（API Key 级 RPM/TPM）优先，不再追加上游账号的同名头
+		if isRateLimitHeader(key) && dst.Get(key) != "" {
+			continue
+		}
 		for _, value := range values {
 			dst.Add(key, value)
 		}
 	}
 }
+
+func isRateLimitHeader(key string) bool {
+	lower := strings.ToLower(key)
+	return strings.HasPrefix(lower, "x-ratelimit-") || strings.HasPrefix(lower, "anthropic-ratelimit-")
+}
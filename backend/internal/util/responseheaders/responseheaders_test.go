@@ -65,3 +65,25 @@ func TestFilterHeadersEnabledUsesAllowlist(t *testing.T) {
 		t.Fatalf("expected X-Blocked removed, got %q", filtered.Get("X-Blocked"))
 	}
 }
+
+func TestWriteFilteredHeadersKeepsGatewayRateLimitHeaders(t *testing.T) {
+	src := http.Header{}
+	src.Add("X-Ratelimit-Limit-Requests", "5000")
+	src.Add("X-Ratelimit-Remaining-Tokens", "800000")
+	src.Add("X-Request-Id", "req-123")
+
+	dst := http.Header{}
+	dst.Set("x-ratelimit-limit-requests", "60")
+
+	WriteFilteredHeaders(dst, src, nil)
+
+	if got := dst.Values("X-Ratelimit-Limit-Requests"); len(got) != 1 || got[0] != "60" {
+		t.Fatalf("expected gateway rate limit header kept, got %v", got)
+	}
+	if dst.Get("X-Ratelimit-Remaining-Tokens") != "800000" {
+		t.Fatalf("expected upstream rate limit header passthrough when gateway did not set it, got %q", dst.Get("X-Ratelimit-Remaining-Tokens"))
+	}
+	if dst.Get("X-Request-Id") != "req-123" {
+		t.Fatalf("expected X-Request-Id passthrough, got %q", dst.Get("X-Request-Id"))
+	}
+}
@@ -0,0 +1,13 @@
+-- Add audio pricing fields for OpenAI-compatible audio endpoints
+-- (/v1/audio/transcriptions, /v1/audio/translations, /v1/audio/speech).
+-- audio_price_per_minute: transcription/translation price per minute of input audio
+-- audio_speech_price_per_1m_chars: text-to-speech price per 1M input characters
+ALTER TABLE groups ADD COLUMN IF NOT EXISTS audio_price_per_minute DECIMAL(20,8);
+ALTER TABLE groups ADD COLUMN IF NOT EXISTS audio_speech_price_per_1m_chars DECIMAL(20,8);
+
+COMMENT ON COLUMN groups.audio_price_per_minute IS '音频转写/翻译每分钟价格';
+COMMENT ON COLUMN groups.audio_speech_price_per_1m_chars IS '语音合成每百万字符价格';
+
+-- usage_logs audio metering: seconds for transcription/translation, characters for speech
+ALTER TABLE usage_logs ADD COLUMN IF NOT EXISTS audio_duration_seconds DOUBLE PRECISION;
+ALTER TABLE usage_logs ADD COLUMN IF NOT EXISTS audio_characters INTEGER;
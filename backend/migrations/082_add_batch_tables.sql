@@ -0,0 +1,98 @@
+-- Migration: 082_add_batch_tables
+-- 异步批处理（OpenAI /v1/files + /v1/batches、Anthropic /v1/messages/batches）所需的表：
+--   1. batch_files：用户上传的 JSONL 输入文件与任务生成的结果文件
+--   2. batch_jobs：批处理任务
+--   3. batch_job_items：任务拆分后的逐行请求（执行器按行领取、回放并保存结果）
+
+-- ============================================================
+-- 1. batch_files 表
+-- ============================================================
+CREATE TABLE IF NOT EXISTS batch_files (
+    id          BIGSERIAL PRIMARY KEY,
+    file_id     VARCHAR(64) NOT NULL UNIQUE,                  -- 对外 ID：file-xxxx
+    user_id     BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    api_key_id  BIGINT,
+    purpose     VARCHAR(32) NOT NULL DEFAULT 'batch',         -- batch / batch_output
+    filename    VARCHAR(255) NOT NULL DEFAULT '',
+    bytes       BIGINT NOT NULL DEFAULT 0,
+    content     BYTEA NOT NULL,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    expires_at  TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_batch_files_user_created
+    ON batch_files(user_id, created_at DESC);
+
+-- ============================================================
+-- 2. batch_jobs 表
+-- ============================================================
+CREATE TABLE IF NOT EXISTS batch_jobs (
+    id                BIGSERIAL PRIMARY KEY,
+    batch_id          VARCHAR(64) NOT NULL UNIQUE,            -- 对外 ID：batch_xxxx / msgbatch_xxxx
+    format            VARCHAR(16) NOT NULL DEFAULT 'openai',  -- openai / anthropic
+    user_id           BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    api_key_id        BIGINT NOT NULL,
+    endpoint          VARCHAR(64) NOT NULL DEFAULT '',
+    input_file_id     VARCHAR(64) NOT NULL DEFAULT '',
+    output_file_id    VARCHAR(64) NOT NULL DEFAULT '',
+    error_file_id     VARCHAR(64) NOT NULL DEFAULT '',
+    completion_window VARCHAR(16) NOT NULL DEFAULT '24h',
+    metadata          JSONB,
+    client_ip         VARCHAR(64) NOT NULL DEFAULT '',
+
+    -- 状态：validating / in_progress / finalizing / completed / failed / expired / cancelling / cancelled
+    status            VARCHAR(16) NOT NULL DEFAULT 'validating',
+    total_count       INT NOT NULL DEFAULT 0,
+    completed_count   INT NOT NULL DEFAULT 0,
+    failed_count      INT NOT NULL DEFAULT 0,
+    cancelled_count   INT NOT NULL DEFAULT 0,
+    expired_count     INT NOT NULL DEFAULT 0,
+    error_message     TEXT NOT NULL DEFAULT '',
+
+    -- 执行器租约（多实例部署时避免同一任务被并发处理）
+    locked_until      TIMESTAMPTZ,
+
+    created_at        TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at        TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    expires_at        TIMESTAMPTZ NOT NULL,
+    in_progress_at    TIMESTAMPTZ,
+    finalizing_at     TIMESTAMPTZ,
+    completed_at      TIMESTAMPTZ,
+    failed_at         TIMESTAMPTZ,
+    expired_at        TIMESTAMPTZ,
+    cancelling_at     TIMESTAMPTZ,
+    cancelled_at      TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_batch_jobs_user_format_created
+    ON batch_jobs(user_id, format, created_at DESC);
+
+CREATE INDEX IF NOT EXISTS idx_batch_jobs_status
+    ON batch_jobs(status, created_at);
+
+-- ============================================================
+-- 3. batch_job_items 表
+-- ============================================================
+CREATE TABLE IF NOT EXISTS batch_job_items (
+    id               BIGSERIAL PRIMARY KEY,
+    job_id           BIGINT NOT NULL REFERENCES batch_jobs(id) ON DELETE CASCADE,
+    line_no          INT NOT NULL,
+    custom_id        VARCHAR(255) NOT NULL DEFAULT '',
+    url              VARCHAR(128) NOT NULL DEFAULT '',
+    body             BYTEA NOT NULL,
+
+    -- 状态：pending / running / succeeded / failed / cancelled / expired
+    status           VARCHAR(16) NOT NULL DEFAULT 'pending',
+    response_status  INT NOT NULL DEFAULT 0,
+    response_body    BYTEA,
+    request_id       VARCHAR(128) NOT NULL DEFAULT '',
+    error_message    TEXT NOT NULL DEFAULT '',
+    started_at       TIMESTAMPTZ,
+    finished_at      TIMESTAMPTZ,
+
+    UNIQUE (job_id, line_no)
+);
+
+-- 执行器按任务领取待处理行
+CREATE INDEX IF NOT EXISTS idx_batch_job_items_job_status
+    ON batch_job_items(job_id, status, line_no);
@@ -0,0 +1,10 @@
+-- Credential encryption at rest (security.credential_encryption).
+-- 启用后 proxies.password 存储信封密文（enc:v1:<kid>:<dek>:<data>），超过原 VARCHAR(100) 上限。
+-- accounts.credentials 仍为 JSONB，仅敏感字段（access_token、refresh_token、api_key 等）加密进
+-- {"__envelope": {"v":2,"kid":...,"dek":...,"data":...}}，model_mapping 等非敏感字段保持明文，SQL 数据迁移仍可修改。
+-- 历史明文数据的加密需要主密钥，不能在 SQL 中完成：服务启动时由 repository.ensureCredentialsEncrypted 执行，
+-- 主密钥轮换使用 `sub2api -rotate-credential-key`。
+ALTER TABLE proxies ALTER COLUMN password TYPE TEXT;
+
+COMMENT ON COLUMN proxies.password IS '代理密码（启用凭证加密后为信封密文）';
+COMMENT ON COLUMN accounts.credentials IS '上游凭证（启用凭证加密后敏感字段存于 __envelope 信封密文）';
@@ -0,0 +1,54 @@
+-- Migration: 084_add_ops_notification_channels
+-- 运维告警/报表的 IM 与 Webhook 通知渠道：
+--   1. ops_notification_channels：通知渠道配置（Webhook/Slack/Telegram/钉钉/飞书/企业微信），
+--      支持按规则路由、最低级别过滤、自定义消息模板
+--   2. ops_notification_deliveries：投递记录（含重试次数、HTTP 状态与错误信息）
+
+-- ============================================================
+-- 1. ops_notification_channels 表
+-- ============================================================
+CREATE TABLE IF NOT EXISTS ops_notification_channels (
+    id               BIGSERIAL PRIMARY KEY,
+    name             VARCHAR(128) NOT NULL,
+    channel_type     VARCHAR(32) NOT NULL,                     -- webhook / slack / telegram / dingtalk / feishu / wecom
+    enabled          BOOLEAN NOT NULL DEFAULT true,
+
+    webhook_url      TEXT NOT NULL DEFAULT '',
+    secret           TEXT NOT NULL DEFAULT '',                 -- Webhook/钉钉/飞书签名密钥；Telegram Bot Token
+    telegram_chat_id VARCHAR(128) NOT NULL DEFAULT '',
+
+    min_severity     VARCHAR(16) NOT NULL DEFAULT '',          -- 空表示不过滤；critical / warning / info
+    rule_ids         JSONB,                                    -- 为空表示接收全部规则
+    notify_resolved  BOOLEAN NOT NULL DEFAULT true,
+    send_reports     BOOLEAN NOT NULL DEFAULT false,
+    template         TEXT NOT NULL DEFAULT '',                 -- Go text/template，空表示使用默认模板
+
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at       TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_ops_notification_channels_name_unique
+    ON ops_notification_channels (name);
+
+-- ============================================================
+-- 2. ops_notification_deliveries 表
+-- ============================================================
+CREATE TABLE IF NOT EXISTS ops_notification_deliveries (
+    id            BIGSERIAL PRIMARY KEY,
+    channel_id    BIGINT NOT NULL,
+    channel_type  VARCHAR(32) NOT NULL,
+    event_id      BIGINT,                                      -- 报表/测试消息为空
+    kind          VARCHAR(16) NOT NULL,                        -- firing / resolved / report / test
+    status        VARCHAR(16) NOT NULL,                        -- success / failed
+    attempts      INT NOT NULL DEFAULT 0,
+    http_status   INT,
+    error_message TEXT,
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_ops_notification_deliveries_channel_created
+    ON ops_notification_deliveries (channel_id, created_at DESC);
+
+CREATE INDEX IF NOT EXISTS idx_ops_notification_deliveries_event
+    ON ops_notification_deliveries (event_id)
+    WHERE event_id IS NOT NULL;
@@ -0,0 +1,54 @@
+-- Migration: 085_exact_decimal_money
+-- 计费金额改为定点数（1e-10 USD）：
+--   应用层金额由 float64 改为 money.Amount（int64，最小单位 1e-10 USD），
+--   余额、API Key 额度/限速、分组限额与按次价格、兑换码面值列由 DECIMAL(20,8) 统一扩展为 DECIMAL(20,10)，
+--   与 usage_logs 成本列、user_subscriptions 用量列精度一致，避免写回时截断。
+--
+-- 现有数据：DECIMAL(20,8) -> DECIMAL(20,10) 为无损转换（仅补零），
+-- 重复执行时类型已一致，ALTER 为空操作。
+-- Redis 中的余额/订阅/限速缓存改用新的 v2 键（整数单位），旧浮点缓存不再读取、按 TTL 自然过期。
+
+-- ============================================================
+-- 1. users.balance
+-- ============================================================
+ALTER TABLE users
+    ALTER COLUMN balance TYPE DECIMAL(20, 10) USING balance::DECIMAL(20, 10);
+
+-- ============================================================
+-- 2. api_keys 额度与限速
+-- ============================================================
+ALTER TABLE api_keys
+    ALTER COLUMN quota TYPE DECIMAL(20, 10) USING quota::DECIMAL(20, 10),
+    ALTER COLUMN quota_used TYPE DECIMAL(20, 10) USING quota_used::DECIMAL(20, 10),
+    ALTER COLUMN rate_limit_5h TYPE DECIMAL(20, 10) USING rate_limit_5h::DECIMAL(20, 10),
+    ALTER COLUMN rate_limit_1d TYPE DECIMAL(20, 10) USING rate_limit_1d::DECIMAL(20, 10),
+    ALTER COLUMN rate_limit_7d TYPE DECIMAL(20, 10) USING rate_limit_7d::DECIMAL(20, 10),
+    ALTER COLUMN usage_5h TYPE DECIMAL(20, 10) USING usage_5h::DECIMAL(20, 10),
+    ALTER COLUMN usage_1d TYPE DECIMAL(20, 10) USING usage_1d::DECIMAL(20, 10),
+    ALTER COLUMN usage_7d TYPE DECIMAL(20, 10) USING usage_7d::DECIMAL(20, 10);
+
+-- ============================================================
+-- 3. groups 订阅限额与按次/音频价格
+-- ============================================================
+ALTER TABLE groups
+    ALTER COLUMN daily_limit_usd TYPE DECIMAL(20, 10) USING daily_limit_usd::DECIMAL(20, 10),
+    ALTER COLUMN weekly_limit_usd TYPE DECIMAL(20, 10) USING weekly_limit_usd::DECIMAL(20, 10),
+    ALTER COLUMN monthly_limit_usd TYPE DECIMAL(20, 10) USING monthly_limit_usd::DECIMAL(20, 10),
+    ALTER COLUMN image_price_1k TYPE DECIMAL(20, 10) USING image_price_1k::DECIMAL(20, 10),
+    ALTER COLUMN image_price_2k TYPE DECIMAL(20, 10) USING image_price_2k::DECIMAL(20, 10),
+    ALTER COLUMN image_price_4k TYPE DECIMAL(20, 10) USING image_price_4k::DECIMAL(20, 10),
+    ALTER COLUMN sora_image_price_360 TYPE DECIMAL(20, 10) USING sora_image_price_360::DECIMAL(20, 10),
+    ALTER COLUMN sora_image_price_540 TYPE DECIMAL(20, 10) USING sora_image_price_540::DECIMAL(20, 10),
+    ALTER COLUMN sora_video_price_per_request TYPE DECIMAL(20, 10) USING sora_video_price_per_request::DECIMAL(20, 10),
+    ALTER COLUMN sora_video_price_per_request_hd TYPE DECIMAL(20, 10) USING sora_video_price_per_request_hd::DECIMAL(20, 10),
+    ALTER COLUMN video_price_per_request TYPE DECIMAL(20, 10) USING video_price_per_request::DECIMAL(20, 10),
+    ALTER COLUMN video_price_per_request_hd TYPE DECIMAL(20, 10) USING video_price_per_request_hd::DECIMAL(20, 10),
+    ALTER COLUMN audio_price_per_minute TYPE DECIMAL(20, 10) USING audio_price_per_minute::DECIMAL(20, 10),
+    ALTER COLUMN audio_speech_price_per_1m_chars TYPE DECIMAL(20, 10) USING audio_speech_price_per_1m_chars::DECIMAL(20, 10);
+
+-- ============================================================
+-- 4. redeem_codes.value
+-- ============================================================
+-- 余额类兑换码为金额；并发、订阅等计数类兑换码的整数值同样存放于此列
+ALTER TABLE redeem_codes
+    ALTER COLUMN value TYPE DECIMAL(20, 10) USING value::DECIMAL(20, 10);
@@ -0,0 +1,75 @@
+-- Migration: 086_create_balance_ledger
+-- 余额流水（只追加）：
+--   每次 users.balance 变动都在同一条语句中写入一条流水，记录来源类型、关联 ID、操作人以及变动前后余额。
+--   流水合计应始终等于 users.balance，由定时对账任务校验，不一致时写入对账记录并触发运维告警。
+
+-- ============================================================
+-- 1. balance_ledger 流水表
+-- ============================================================
+CREATE TABLE IF NOT EXISTS balance_ledger (
+    id              BIGSERIAL PRIMARY KEY,
+    user_id         BIGINT NOT NULL,
+    source_type     VARCHAR(32) NOT NULL,             -- redeem/promo_code/admin_adjust/activity_reward/activity_cost/checkin/usage/initial/opening
+    reference_id    VARCHAR(128) NOT NULL DEFAULT '', -- 兑换码 ID / 优惠码 ID / request_id 等
+    operator_id     BIGINT,                           -- 管理员操作时为管理员用户 ID
+    amount          DECIMAL(20, 10) NOT NULL,         -- 变动金额（正为入账，负为出账）
+    balance_before  DECIMAL(20, 10) NOT NULL,
+    balance_after   DECIMAL(20, 10) NOT NULL,
+    notes           TEXT NOT NULL DEFAULT '',
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_balance_ledger_user_id_id ON balance_ledger (user_id, id DESC);
+CREATE INDEX IF NOT EXISTS idx_balance_ledger_source_created ON balance_ledger (source_type, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_balance_ledger_reference_id ON balance_ledger (reference_id) WHERE reference_id <> '';
+CREATE INDEX IF NOT EXISTS idx_balance_ledger_created_at ON balance_ledger (created_at DESC);
+
+COMMENT ON TABLE balance_ledger IS '用户余额流水（只追加，禁止 UPDATE/DELETE）';
+
+-- 只追加：拒绝对已有流水的修改与删除
+CREATE OR REPLACE FUNCTION balance_ledger_reject_mutation() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'balance_ledger is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_balance_ledger_append_only ON balance_ledger;
+CREATE TRIGGER trg_balance_ledger_append_only
+    BEFORE UPDATE OR DELETE ON balance_ledger
+    FOR EACH ROW EXECUTE FUNCTION balance_ledger_reject_mutation();
+
+-- ============================================================
+-- 2. 期初流水：为存量非零余额写入一条 opening 记录（重复执行时跳过已有流水的用户）
+-- ============================================================
+INSERT INTO balance_ledger (user_id, source_type, amount, balance_before, balance_after, notes, created_at)
+SELECT u.id, 'opening', u.balance, 0, u.balance, 'opening balance before ledger', NOW()
+FROM users u
+WHERE u.balance <> 0
+  AND NOT EXISTS (SELECT 1 FROM balance_ledger l WHERE l.user_id = u.id);
+
+-- ============================================================
+-- 3. 对账记录
+-- ============================================================
+CREATE TABLE IF NOT EXISTS balance_ledger_reconcile_runs (
+    id              BIGSERIAL PRIMARY KEY,
+    started_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    finished_at     TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    checked_users   BIGINT NOT NULL DEFAULT 0,
+    mismatch_count  BIGINT NOT NULL DEFAULT 0,
+    samples         JSONB NOT NULL DEFAULT '[]'::jsonb -- 不一致用户样本 [{user_id, balance, ledger_sum}]
+);
+
+CREATE INDEX IF NOT EXISTS idx_balance_ledger_reconcile_runs_started_at ON balance_ledger_reconcile_runs (started_at DESC);
+
+-- ============================================================
+-- 4. 默认告警规则：余额流水对账不一致
+-- ============================================================
+INSERT INTO ops_alert_rules (
+    name, description, enabled, metric_type, operator, threshold,
+    window_minutes, sustained_minutes, severity, notify_email, cooldown_minutes,
+    created_at, updated_at
+) VALUES (
+    '余额流水对账不一致',
+    '最近一次余额对账发现流水合计与用户余额不一致的用户数大于 0 时触发告警',
+    true, 'balance_ledger_mismatch_count', '>', 0, 5, 1, 'P1', true, 60, NOW(), NOW()
+) ON CONFLICT (name) DO NOTHING;
@@ -0,0 +1,9 @@
+-- Add model restriction fields to api_keys table
+-- model_allowlist: JSON array of model glob patterns (if set, only matching models can be used)
+-- model_denylist: JSON array of model glob patterns (matching models are always rejected)
+
+ALTER TABLE api_keys ADD COLUMN IF NOT EXISTS model_allowlist JSONB DEFAULT NULL;
+ALTER TABLE api_keys ADD COLUMN IF NOT EXISTS model_denylist JSONB DEFAULT NULL;
+
+COMMENT ON COLUMN api_keys.model_allowlist IS 'JSON array of allowed model glob patterns, e.g. ["claude-*-haiku-*", "gpt-4o-mini"]';
+COMMENT ON COLUMN api_keys.model_denylist IS 'JSON array of denied model glob patterns, e.g. ["*opus*"]';
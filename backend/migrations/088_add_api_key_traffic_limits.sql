@@ -0,0 +1,12 @@
+-- Add per-key traffic limit fields to api_keys table
+-- rpm_limit: max requests per minute (0 = unlimited)
+-- tpm_limit: max tokens per minute (0 = unlimited)
+-- max_concurrency: max in-flight requests (0 = unlimited)
+
+ALTER TABLE api_keys ADD COLUMN IF NOT EXISTS rpm_limit INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE api_keys ADD COLUMN IF NOT EXISTS tpm_limit INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE api_keys ADD COLUMN IF NOT EXISTS max_concurrency INTEGER NOT NULL DEFAULT 0;
+
+COMMENT ON COLUMN api_keys.rpm_limit IS 'Max requests per minute for this API key (0 = unlimited)';
+COMMENT ON COLUMN api_keys.tpm_limit IS 'Max tokens per minute for this API key (0 = unlimited)';
+COMMENT ON COLUMN api_keys.max_concurrency IS 'Max in-flight requests for this API key (0 = unlimited)';
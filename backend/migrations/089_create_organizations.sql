@@ -0,0 +1,92 @@
+-- Migration: 089_create_organizations
+-- 组织/团队：
+--   每个组织对应一个组织账户（users.role = 'organization'），组织的共享余额、订阅、并发与余额流水都挂在该账户上，
+--   现有的计费、订阅、兑换与对账逻辑无需区分个人与组织。
+--   组织 API Key 的 user_id 为组织账户，created_by_user_id 为创建该 Key 的成员（用于归属、成员限额与用量统计）。
+
+-- ============================================================
+-- 1. 组织
+-- ============================================================
+CREATE TABLE IF NOT EXISTS organizations (
+    id               BIGSERIAL PRIMARY KEY,
+    name             VARCHAR(100) NOT NULL,
+    account_user_id  BIGINT NOT NULL UNIQUE REFERENCES users(id) ON DELETE CASCADE, -- 组织账户（余额/订阅持有者）
+    created_by       BIGINT NOT NULL,
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at       TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+COMMENT ON TABLE organizations IS '组织：共享余额与订阅由 account_user_id 对应的组织账户持有';
+
+-- ============================================================
+-- 2. 组织成员
+-- ============================================================
+CREATE TABLE IF NOT EXISTS organization_members (
+    organization_id      BIGINT NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    user_id              BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    role                 VARCHAR(20) NOT NULL,                 -- owner/admin/member/billing
+    monthly_spend_limit  DECIMAL(20, 10) NOT NULL DEFAULT 0,   -- 成员每自然月消费上限（USD，0 = 不限制）
+    created_at           TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at           TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (organization_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_organization_members_user_id ON organization_members (user_id);
+-- 每个组织有且只有一个 owner
+CREATE UNIQUE INDEX IF NOT EXISTS idx_organization_members_owner
+    ON organization_members (organization_id) WHERE role = 'owner';
+
+-- ============================================================
+-- 3. 成员邀请
+-- ============================================================
+CREATE TABLE IF NOT EXISTS organization_invitations (
+    id               BIGSERIAL PRIMARY KEY,
+    organization_id  BIGINT NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    email            VARCHAR(255) NOT NULL,
+    role             VARCHAR(20) NOT NULL,
+    token_hash       VARCHAR(64) NOT NULL UNIQUE,                -- 邀请令牌的 SHA-256，明文只在创建时返回一次
+    invited_by       BIGINT NOT NULL,
+    status           VARCHAR(20) NOT NULL DEFAULT 'pending',     -- pending/accepted/revoked
+    expires_at       TIMESTAMPTZ NOT NULL,
+    accepted_by      BIGINT,
+    accepted_at      TIMESTAMPTZ,
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_organization_invitations_org_status
+    ON organization_invitations (organization_id, status, created_at DESC);
+-- 同一组织对同一邮箱只保留一条待处理邀请
+CREATE UNIQUE INDEX IF NOT EXISTS idx_organization_invitations_pending_email
+    ON organization_invitations (organization_id, LOWER(email)) WHERE status = 'pending';
+
+-- ============================================================
+-- 4. API Key 归属成员
+-- ============================================================
+ALTER TABLE api_keys ADD COLUMN IF NOT EXISTS created_by_user_id BIGINT;
+
+CREATE INDEX IF NOT EXISTS apikey_created_by_user_id ON api_keys (created_by_user_id);
+
+COMMENT ON COLUMN api_keys.created_by_user_id IS '组织 Key 的创建成员（NULL 为个人 Key）';
+
+-- ============================================================
+-- 5. 组织用量预聚合（按天 × API Key，仅统计组织账户的用量）
+-- ============================================================
+CREATE TABLE IF NOT EXISTS usage_dashboard_daily_api_keys (
+    bucket_date            DATE NOT NULL,
+    api_key_id             BIGINT NOT NULL,
+    user_id                BIGINT NOT NULL,   -- 组织账户
+    total_requests         BIGINT NOT NULL DEFAULT 0,
+    input_tokens           BIGINT NOT NULL DEFAULT 0,
+    output_tokens          BIGINT NOT NULL DEFAULT 0,
+    cache_creation_tokens  BIGINT NOT NULL DEFAULT 0,
+    cache_read_tokens      BIGINT NOT NULL DEFAULT 0,
+    total_cost             DECIMAL(20, 10) NOT NULL DEFAULT 0,
+    actual_cost            DECIMAL(20, 10) NOT NULL DEFAULT 0,
+    computed_at            TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (bucket_date, api_key_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_usage_dashboard_daily_api_keys_user_date
+    ON usage_dashboard_daily_api_keys (user_id, bucket_date);
+
+COMMENT ON TABLE usage_dashboard_daily_api_keys IS 'Pre-aggregated daily usage per API key for organization accounts (organization dashboards).';
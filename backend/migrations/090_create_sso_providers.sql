@@ -0,0 +1,60 @@
+-- Migration: 090_create_sso_providers
+-- 通用 OIDC / OAuth2 单点登录：
+--   管理员可在运行时配置任意数量的身份提供方（Keycloak、Authentik、Google Workspace、GitHub、Azure AD 等），
+--   外部身份按 (provider_id, subject) 绑定到本地用户，支持自动开通与按已验证邮箱关联已有账号。
+
+-- ============================================================
+-- 1. 身份提供方
+-- ============================================================
+CREATE TABLE IF NOT EXISTS sso_providers (
+    id                       BIGSERIAL PRIMARY KEY,
+    slug                     VARCHAR(64) NOT NULL UNIQUE,              -- 回调路径中的标识，如 /auth/oauth/sso/{slug}/callback
+    name                     VARCHAR(100) NOT NULL,
+    type                     VARCHAR(20) NOT NULL DEFAULT 'oidc',      -- oidc: 通过 issuer 发现端点并校验 ID Token；oauth2: 手动配置端点，仅依赖 userinfo
+    enabled                  BOOLEAN NOT NULL DEFAULT FALSE,
+    issuer_url               TEXT NOT NULL DEFAULT '',
+    authorize_url            TEXT NOT NULL DEFAULT '',                 -- oidc 留空时使用发现文档
+    token_url                TEXT NOT NULL DEFAULT '',
+    userinfo_url             TEXT NOT NULL DEFAULT '',
+    jwks_url                 TEXT NOT NULL DEFAULT '',
+    client_id                VARCHAR(255) NOT NULL,
+    client_secret_encrypted  TEXT NOT NULL DEFAULT '',                 -- AES-GCM 加密
+    scopes                   VARCHAR(500) NOT NULL DEFAULT '',
+    token_auth_method        VARCHAR(32) NOT NULL DEFAULT 'client_secret_post',
+    use_pkce                 BOOLEAN NOT NULL DEFAULT TRUE,
+    redirect_url             TEXT NOT NULL DEFAULT '',                 -- 留空时按 server.frontend_url 或请求地址推导
+    frontend_redirect_url    TEXT NOT NULL DEFAULT '',
+    subject_claim            VARCHAR(255) NOT NULL DEFAULT '',
+    email_claim              VARCHAR(255) NOT NULL DEFAULT '',
+    email_verified_claim     VARCHAR(255) NOT NULL DEFAULT '',         -- 留空时 oidc 使用 email_verified
+    trust_email              BOOLEAN NOT NULL DEFAULT FALSE,           -- 不检查 email_verified（Azure AD、GitHub 等不返回该声明）
+    username_claim           VARCHAR(255) NOT NULL DEFAULT '',
+    attribute_mappings       JSONB NOT NULL DEFAULT '{}'::jsonb,       -- 用户属性 key -> 声明路径
+    allowed_email_domains    JSONB NOT NULL DEFAULT '[]'::jsonb,
+    auto_provision           BOOLEAN NOT NULL DEFAULT FALSE,
+    default_group_ids        JSONB NOT NULL DEFAULT '[]'::jsonb,       -- 自动开通的用户加入的专属分组
+    link_existing_by_email   BOOLEAN NOT NULL DEFAULT FALSE,
+    enforce_sso              BOOLEAN NOT NULL DEFAULT FALSE,           -- allowed_email_domains 内的非管理员用户禁止密码登录
+    display_order            INT NOT NULL DEFAULT 0,
+    created_at               TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at               TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+COMMENT ON TABLE sso_providers IS '管理员配置的 OIDC/OAuth2 单点登录提供方';
+
+-- ============================================================
+-- 2. 外部身份绑定
+-- ============================================================
+CREATE TABLE IF NOT EXISTS user_sso_identities (
+    id             BIGSERIAL PRIMARY KEY,
+    provider_id    BIGINT NOT NULL REFERENCES sso_providers(id) ON DELETE CASCADE,
+    subject        VARCHAR(255) NOT NULL,
+    user_id        BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    email          VARCHAR(255) NOT NULL DEFAULT '',
+    last_login_at  TIMESTAMPTZ,
+    created_at     TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (provider_id, subject),
+    UNIQUE (provider_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_sso_identities_user_id ON user_sso_identities (user_id);
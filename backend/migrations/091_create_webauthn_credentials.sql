@@ -0,0 +1,39 @@
+-- Migration: 091_create_webauthn_credentials
+-- Passkey（WebAuthn）与双因素恢复码：
+--   Passkey 既可用于无密码登录，也可作为密码登录后的第二因素；
+--   恢复码在丢失 TOTP 设备或 Passkey 时完成 /auth/login/2fa，每个仅可使用一次。
+
+-- ============================================================
+-- 1. WebAuthn 凭据
+-- ============================================================
+CREATE TABLE IF NOT EXISTS user_webauthn_credentials (
+    id               BIGSERIAL PRIMARY KEY,
+    user_id          BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name             VARCHAR(100) NOT NULL,
+    credential_id    BYTEA NOT NULL UNIQUE,                -- 认证器生成的凭据 ID（原始字节）
+    public_key       BYTEA NOT NULL,                       -- COSE_Key 编码的公钥
+    algorithm        INTEGER NOT NULL,                     -- COSE 算法标识：-7 ES256 / -8 EdDSA / -257 RS256
+    sign_count       BIGINT NOT NULL DEFAULT 0,            -- 签名计数器，用于检测克隆的认证器
+    transports       JSONB NOT NULL DEFAULT '[]'::jsonb,   -- internal / usb / nfc / ble / hybrid
+    aaguid           BYTEA,
+    backup_eligible  BOOLEAN NOT NULL DEFAULT FALSE,       -- 可同步 Passkey（iCloud 钥匙串、Google 密码管理器等）
+    backed_up        BOOLEAN NOT NULL DEFAULT FALSE,
+    last_used_at     TIMESTAMPTZ,
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_webauthn_credentials_user_id ON user_webauthn_credentials (user_id);
+
+-- ============================================================
+-- 2. 双因素恢复码
+-- ============================================================
+-- 明文仅在生成时展示一次；库中保存 SHA-256 摘要经 SecretEncryptor 加密后的密文
+CREATE TABLE IF NOT EXISTS user_recovery_codes (
+    id                   BIGSERIAL PRIMARY KEY,
+    user_id              BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    code_hash_encrypted  TEXT NOT NULL,
+    used_at              TIMESTAMPTZ,
+    created_at           TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_recovery_codes_user_id ON user_recovery_codes (user_id);
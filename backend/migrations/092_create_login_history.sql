@@ -0,0 +1,20 @@
+-- Migration: 092_create_login_history
+-- 登录记录：记录每次登录尝试（成功与失败）的来源 IP 与设备，
+-- 供用户与管理员查看，并用于判断是否为新 IP / 新设备登录。
+
+CREATE TABLE IF NOT EXISTS login_history (
+    id              BIGSERIAL PRIMARY KEY,
+    user_id         BIGINT REFERENCES users(id) ON DELETE CASCADE,  -- 邮箱不存在时为空
+    email           VARCHAR(255) NOT NULL DEFAULT '',
+    success         BOOLEAN NOT NULL,
+    failure_reason  VARCHAR(64) NOT NULL DEFAULT '',                 -- 失败时的错误原因码，如 INVALID_CREDENTIALS
+    method          VARCHAR(64) NOT NULL,                            -- password / totp / webauthn / recovery_code / passkey / linuxdo / sso:{slug}
+    ip              VARCHAR(64) NOT NULL DEFAULT '',
+    user_agent      VARCHAR(512) NOT NULL DEFAULT '',
+    device          VARCHAR(64) NOT NULL DEFAULT '',                 -- 由 User-Agent 识别，如 "Chrome on macOS"
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_login_history_user_created ON login_history (user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_login_history_ip_created ON login_history (ip, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_login_history_created_at ON login_history (created_at);
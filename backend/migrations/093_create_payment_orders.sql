@@ -0,0 +1,66 @@
+-- Migration: 093_create_payment_orders
+-- 内置支付订单：
+--   商品分为余额充值包与订阅套餐，订单在下单时快照商品信息；
+--   支付成功后通过兑换码 PAY-{order_no} 发放权益（复用兑换逻辑，兑换码唯一性保证幂等）。
+
+-- ============================================================
+-- 1. 商品
+-- ============================================================
+CREATE TABLE IF NOT EXISTS payment_products (
+    id              BIGSERIAL PRIMARY KEY,
+    name            VARCHAR(100) NOT NULL,
+    description     TEXT NOT NULL DEFAULT '',
+    kind            VARCHAR(20) NOT NULL,                  -- balance/subscription
+    price_cents     BIGINT NOT NULL,                       -- 售价（最小货币单位）
+    currency        VARCHAR(3) NOT NULL DEFAULT 'CNY',
+    balance_amount  DECIMAL(20, 10) NOT NULL DEFAULT 0,    -- 余额充值包发放的余额（USD）
+    group_id        BIGINT REFERENCES groups(id) ON DELETE SET NULL, -- 订阅套餐对应分组
+    validity_days   INT NOT NULL DEFAULT 0,
+    enabled         BOOLEAN NOT NULL DEFAULT TRUE,
+    sort_order      INT NOT NULL DEFAULT 0,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at      TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+COMMENT ON TABLE payment_products IS '支付商品：余额充值包与订阅套餐';
+
+-- ============================================================
+-- 2. 订单
+-- ============================================================
+CREATE TABLE IF NOT EXISTS payment_orders (
+    id                 BIGSERIAL PRIMARY KEY,
+    order_no           VARCHAR(32) NOT NULL UNIQUE,
+    user_id            BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    product_id         BIGINT REFERENCES payment_products(id) ON DELETE SET NULL,
+    product_name       VARCHAR(100) NOT NULL,
+    kind               VARCHAR(20) NOT NULL,
+    balance_amount     DECIMAL(20, 10) NOT NULL DEFAULT 0,
+    group_id           BIGINT,
+    validity_days      INT NOT NULL DEFAULT 0,
+    amount_cents       BIGINT NOT NULL,
+    currency           VARCHAR(3) NOT NULL,
+    provider           VARCHAR(20) NOT NULL,                 -- epay/alipay/wechat/stripe
+    method             VARCHAR(20) NOT NULL DEFAULT '',      -- 渠道内支付方式（如易支付的 alipay/wxpay）
+    provider_trade_no  VARCHAR(128) NOT NULL DEFAULT '',
+    status             VARCHAR(20) NOT NULL DEFAULT 'pending', -- pending/paid/fulfilled/closed/refunded
+    redeem_code_id     BIGINT,                               -- 发放权益所用兑换码
+    fulfill_error      TEXT NOT NULL DEFAULT '',
+    refunded_cents     BIGINT NOT NULL DEFAULT 0,
+    refund_reason      TEXT NOT NULL DEFAULT '',
+    client_ip          VARCHAR(64) NOT NULL DEFAULT '',
+    expires_at         TIMESTAMPTZ NOT NULL,
+    paid_at            TIMESTAMPTZ,
+    fulfilled_at       TIMESTAMPTZ,
+    refunded_at        TIMESTAMPTZ,
+    closed_at          TIMESTAMPTZ,
+    created_at         TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at         TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_payment_orders_user_id ON payment_orders (user_id, id DESC);
+CREATE INDEX IF NOT EXISTS idx_payment_orders_status_expires ON payment_orders (expires_at) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_payment_orders_unfulfilled ON payment_orders (paid_at) WHERE status = 'paid';
+CREATE INDEX IF NOT EXISTS idx_payment_orders_paid_at ON payment_orders (paid_at);
+CREATE INDEX IF NOT EXISTS idx_payment_orders_provider_trade_no ON payment_orders (provider, provider_trade_no) WHERE provider_trade_no <> '';
+
+COMMENT ON TABLE payment_orders IS '支付订单：商品信息为下单时快照';
@@ -0,0 +1,95 @@
+-- Migration: 094_create_referrals
+-- 推荐返佣：
+--   每个用户拥有一个推荐码（首次访问时生成），新用户通过推荐链接注册后建立推荐关系；
+--   后台定时任务按 settled_until 游标从兑换记录（充值）或 usage_logs（消费）结算返佣，
+--   (relation_id, source_ref) 唯一约束保证同一笔来源只返佣一次。
+
+-- ============================================================
+-- 1. 推荐码
+-- ============================================================
+CREATE TABLE IF NOT EXISTS referral_codes (
+    user_id     BIGINT PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    code        VARCHAR(16) NOT NULL UNIQUE,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+COMMENT ON TABLE referral_codes IS '用户推荐码';
+
+-- ============================================================
+-- 2. 推荐关系
+-- ============================================================
+CREATE TABLE IF NOT EXISTS referral_relations (
+    id                   BIGSERIAL PRIMARY KEY,
+    referrer_id          BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    referee_id           BIGINT NOT NULL UNIQUE REFERENCES users(id) ON DELETE CASCADE,
+    code                 VARCHAR(16) NOT NULL,
+    status               VARCHAR(20) NOT NULL DEFAULT 'active', -- active/flagged/revoked
+    flag_reason          VARCHAR(64) NOT NULL DEFAULT '',       -- same_ip/same_device/ip_limit
+    register_ip          VARCHAR(64) NOT NULL DEFAULT '',
+    register_user_agent  VARCHAR(512) NOT NULL DEFAULT '',
+    commission_until     TIMESTAMPTZ,                           -- 返佣截止时间，NULL 表示永久
+    settled_until        TIMESTAMPTZ NOT NULL,                  -- 返佣已结算至该时间（不含）
+    bonus_paid_at        TIMESTAMPTZ,
+    created_at           TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at           TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_referral_relations_referrer ON referral_relations (referrer_id, id DESC);
+CREATE INDEX IF NOT EXISTS idx_referral_relations_referrer_ip ON referral_relations (referrer_id, register_ip);
+CREATE INDEX IF NOT EXISTS idx_referral_relations_settle ON referral_relations (status, settled_until);
+
+COMMENT ON TABLE referral_relations IS '推荐关系：每个被推荐人只能有一个推荐人';
+
+-- ============================================================
+-- 3. 返佣记录
+-- ============================================================
+CREATE TABLE IF NOT EXISTS referral_commissions (
+    id           BIGSERIAL PRIMARY KEY,
+    relation_id  BIGINT NOT NULL REFERENCES referral_relations(id) ON DELETE CASCADE,
+    referrer_id  BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    referee_id   BIGINT NOT NULL,
+    kind         VARCHAR(20) NOT NULL,                 -- bonus/recharge/usage
+    source_ref   VARCHAR(64) NOT NULL,                 -- bonus / redeem:{兑换码ID} / usage:{结算窗口结束时间}
+    base_amount  DECIMAL(20, 10) NOT NULL DEFAULT 0,   -- 返佣基数（USD）
+    rate         DECIMAL(10, 6) NOT NULL DEFAULT 0,
+    amount       DECIMAL(20, 10) NOT NULL,             -- 返佣金额（USD）
+    payout       VARCHAR(20) NOT NULL,                 -- balance/wallet
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (relation_id, source_ref)
+);
+
+CREATE INDEX IF NOT EXISTS idx_referral_commissions_referrer ON referral_commissions (referrer_id, id DESC);
+CREATE INDEX IF NOT EXISTS idx_referral_commissions_referrer_created ON referral_commissions (referrer_id, created_at);
+
+COMMENT ON TABLE referral_commissions IS '推荐返佣记录（只追加）';
+
+-- ============================================================
+-- 4. 佣金钱包与提现
+-- ============================================================
+CREATE TABLE IF NOT EXISTS referral_wallets (
+    user_id          BIGINT PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    balance          DECIMAL(20, 10) NOT NULL DEFAULT 0 CHECK (balance >= 0),
+    total_earned     DECIMAL(20, 10) NOT NULL DEFAULT 0,
+    total_withdrawn  DECIMAL(20, 10) NOT NULL DEFAULT 0,
+    updated_at       TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+COMMENT ON TABLE referral_wallets IS '佣金钱包：payout=wallet 时返佣计入此处，可提现或转入余额';
+
+CREATE TABLE IF NOT EXISTS referral_withdrawals (
+    id            BIGSERIAL PRIMARY KEY,
+    user_id       BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    amount        DECIMAL(20, 10) NOT NULL,
+    method        VARCHAR(20) NOT NULL,              -- balance（转入账户余额，立即完成）/manual（线下打款，需审核）
+    account       TEXT NOT NULL DEFAULT '',          -- 收款信息
+    status        VARCHAR(20) NOT NULL,              -- pending/completed/rejected
+    admin_notes   TEXT NOT NULL DEFAULT '',
+    operator_id   BIGINT,
+    processed_at  TIMESTAMPTZ,
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_referral_withdrawals_user ON referral_withdrawals (user_id, id DESC);
+CREATE INDEX IF NOT EXISTS idx_referral_withdrawals_status ON referral_withdrawals (status, id DESC);
+
+COMMENT ON TABLE referral_withdrawals IS '佣金提现申请';
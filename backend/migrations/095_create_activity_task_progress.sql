@@ -0,0 +1,59 @@
+-- Migration: 095_create_activity_task_progress
+-- 任务活动（task / limited_time）：
+--   活动在 activity_config.goals 中声明目标（请求次数、指定模型消费、连续使用天数、绑定 2FA），
+--   后台任务按 usage_logs.id 游标增量累计进度，认证事件（启用 2FA）直接写入进度；
+--   全部目标达成后写入完成记录，(activity_id, user_id, period_key) 唯一约束保证每个周期只发一次奖励。
+
+-- ============================================================
+-- 1. 目标进度（每个目标一行）
+-- ============================================================
+CREATE TABLE IF NOT EXISTS activity_task_progress (
+    id                BIGSERIAL PRIMARY KEY,
+    activity_id       BIGINT NOT NULL REFERENCES activities(id) ON DELETE CASCADE,
+    user_id           BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    period_key        VARCHAR(16) NOT NULL DEFAULT '',   -- 重置周期：'' 不重置 / 2026-10-17 / 2026-W42 / 2026-10
+    goal_index        SMALLINT NOT NULL,                 -- activity_config.goals 中的下标
+    value             DECIMAL(20, 10) NOT NULL DEFAULT 0,-- 请求数 / 消费金额（USD）/ 当前连续天数 / 是否已绑定
+    last_active_date  DATE,                              -- 连续天数目标：最近一次使用日期（UTC）
+    updated_at        TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (activity_id, user_id, period_key, goal_index)
+);
+
+CREATE INDEX IF NOT EXISTS idx_activity_task_progress_user ON activity_task_progress (user_id, activity_id);
+
+COMMENT ON TABLE activity_task_progress IS '任务活动目标进度';
+
+-- ============================================================
+-- 2. 完成记录
+-- ============================================================
+CREATE TABLE IF NOT EXISTS activity_task_completions (
+    id                BIGSERIAL PRIMARY KEY,
+    activity_id       BIGINT NOT NULL REFERENCES activities(id) ON DELETE CASCADE,
+    user_id           BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    period_key        VARCHAR(16) NOT NULL DEFAULT '',
+    status            VARCHAR(20) NOT NULL DEFAULT 'pending', -- pending（待领取）/claimed（已发放）
+    participation_id  BIGINT REFERENCES activity_participations(id) ON DELETE SET NULL,
+    completed_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    claimed_at        TIMESTAMPTZ,
+    UNIQUE (activity_id, user_id, period_key)
+);
+
+CREATE INDEX IF NOT EXISTS idx_activity_task_completions_user ON activity_task_completions (user_id, activity_id);
+
+COMMENT ON TABLE activity_task_completions IS '任务活动完成记录：每个周期只能完成并领取一次';
+
+-- ============================================================
+-- 3. 使用记录游标
+-- ============================================================
+CREATE TABLE IF NOT EXISTS activity_task_cursors (
+    name        VARCHAR(32) PRIMARY KEY,
+    last_id     BIGINT NOT NULL DEFAULT 0,
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+COMMENT ON TABLE activity_task_cursors IS '任务进度已累计到的 usage_logs.id';
+
+-- 从当前最新的使用记录开始累计，不回放历史
+INSERT INTO activity_task_cursors (name, last_id)
+SELECT 'usage_logs', COALESCE(MAX(id), 0) FROM usage_logs
+ON CONFLICT (name) DO NOTHING;
@@ -0,0 +1,123 @@
+-- Migration: 096_create_points_and_coupons
+-- 积分与优惠券：
+--   积分账户与流水（活动奖励、管理员调整获得，积分商城兑换消耗），每次变动写入一条流水；
+--   积分商城商品可兑换余额、订阅或兑换码，兑换在单个事务内扣积分、扣库存并发放；
+--   优惠券分为模板（coupons）与用户持有的券（user_coupons），下单时锁定到订单，
+--   订单发放后核销，订单关闭时退回。
+
+-- ============================================================
+-- 1. 积分账户与流水
+-- ============================================================
+CREATE TABLE IF NOT EXISTS points_accounts (
+    user_id       BIGINT PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    balance       BIGINT NOT NULL DEFAULT 0 CHECK (balance >= 0),
+    total_earned  BIGINT NOT NULL DEFAULT 0,
+    total_spent   BIGINT NOT NULL DEFAULT 0,
+    updated_at    TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+COMMENT ON TABLE points_accounts IS '用户积分账户';
+
+CREATE TABLE IF NOT EXISTS points_ledger (
+    id             BIGSERIAL PRIMARY KEY,
+    user_id        BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    delta          BIGINT NOT NULL,                    -- 正数为获得，负数为消耗
+    balance_after  BIGINT NOT NULL,
+    source_type    VARCHAR(32) NOT NULL,               -- activity_reward/shop_exchange/admin_adjust
+    reference_id   VARCHAR(64) NOT NULL DEFAULT '',
+    operator_id    BIGINT,
+    notes          TEXT NOT NULL DEFAULT '',
+    created_at     TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_points_ledger_user ON points_ledger (user_id, id DESC);
+
+COMMENT ON TABLE points_ledger IS '积分流水';
+
+-- ============================================================
+-- 2. 积分商城
+-- ============================================================
+CREATE TABLE IF NOT EXISTS points_shop_items (
+    id              BIGSERIAL PRIMARY KEY,
+    name            VARCHAR(100) NOT NULL,
+    description     TEXT NOT NULL DEFAULT '',
+    kind            VARCHAR(20) NOT NULL,                  -- balance/subscription/redeem_code
+    points_cost     BIGINT NOT NULL,
+    balance_amount  DECIMAL(20, 10) NOT NULL DEFAULT 0,    -- 余额（USD）；redeem_code 商品为兑换码面值
+    group_id        BIGINT REFERENCES groups(id) ON DELETE SET NULL, -- 订阅分组；redeem_code 商品设置时生成订阅兑换码
+    validity_days   INT NOT NULL DEFAULT 0,
+    stock           INT NOT NULL DEFAULT -1,               -- 剩余库存，-1 表示不限
+    per_user_limit  INT NOT NULL DEFAULT 0,                -- 每人限兑次数，0 表示不限
+    enabled         BOOLEAN NOT NULL DEFAULT TRUE,
+    sort_order      INT NOT NULL DEFAULT 0,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at      TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+COMMENT ON TABLE points_shop_items IS '积分商城商品';
+
+CREATE TABLE IF NOT EXISTS points_exchanges (
+    id              BIGSERIAL PRIMARY KEY,
+    user_id         BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    item_id         BIGINT REFERENCES points_shop_items(id) ON DELETE SET NULL,
+    item_name       VARCHAR(100) NOT NULL,
+    kind            VARCHAR(20) NOT NULL,
+    points_cost     BIGINT NOT NULL,
+    redeem_code     VARCHAR(64) NOT NULL DEFAULT '',       -- redeem_code 商品发给用户的兑换码
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_points_exchanges_user ON points_exchanges (user_id, id DESC);
+CREATE INDEX IF NOT EXISTS idx_points_exchanges_item_user ON points_exchanges (item_id, user_id);
+
+COMMENT ON TABLE points_exchanges IS '积分兑换记录：商品信息为兑换时快照';
+
+-- ============================================================
+-- 3. 优惠券
+-- ============================================================
+CREATE TABLE IF NOT EXISTS coupons (
+    id                BIGSERIAL PRIMARY KEY,
+    name              VARCHAR(100) NOT NULL,
+    description       TEXT NOT NULL DEFAULT '',
+    discount_type     VARCHAR(20) NOT NULL,                -- percent/fixed
+    percent_off       INT NOT NULL DEFAULT 0,              -- percent：折扣百分比 1-99
+    amount_off_cents  BIGINT NOT NULL DEFAULT 0,           -- fixed：立减金额（最小货币单位）
+    currency          VARCHAR(3) NOT NULL DEFAULT '',      -- fixed：仅适用于该币种的商品
+    min_amount_cents  BIGINT NOT NULL DEFAULT 0,           -- 订单原价门槛
+    scope             VARCHAR(20) NOT NULL DEFAULT 'all',  -- all/balance/subscription/product/group
+    scope_id          BIGINT,                              -- scope 为 product/group 时的商品或分组 ID
+    valid_days        INT NOT NULL DEFAULT 0,              -- 发放后有效天数，0 表示不限
+    expires_at        TIMESTAMPTZ,                         -- 统一截止时间，与 valid_days 取较早者
+    enabled           BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at        TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at        TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+COMMENT ON TABLE coupons IS '优惠券模板';
+
+CREATE TABLE IF NOT EXISTS user_coupons (
+    id          BIGSERIAL PRIMARY KEY,
+    coupon_id   BIGINT NOT NULL REFERENCES coupons(id) ON DELETE CASCADE,
+    user_id     BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    status      VARCHAR(20) NOT NULL DEFAULT 'unused',   -- unused/locked/used
+    source      VARCHAR(32) NOT NULL DEFAULT '',         -- activity/admin
+    source_ref  VARCHAR(64) NOT NULL DEFAULT '',
+    expires_at  TIMESTAMPTZ,
+    order_no    VARCHAR(32) NOT NULL DEFAULT '',         -- 锁定或核销该券的支付订单
+    used_at     TIMESTAMPTZ,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_coupons_user ON user_coupons (user_id, status, id DESC);
+CREATE INDEX IF NOT EXISTS idx_user_coupons_order_no ON user_coupons (order_no) WHERE order_no <> '';
+
+COMMENT ON TABLE user_coupons IS '用户持有的优惠券';
+
+-- ============================================================
+-- 4. 支付订单记录所用优惠券
+-- ============================================================
+ALTER TABLE payment_orders ADD COLUMN IF NOT EXISTS user_coupon_id BIGINT;
+ALTER TABLE payment_orders ADD COLUMN IF NOT EXISTS original_cents BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE payment_orders ADD COLUMN IF NOT EXISTS discount_cents BIGINT NOT NULL DEFAULT 0;
+
+UPDATE payment_orders SET original_cents = amount_cents WHERE original_cents = 0;
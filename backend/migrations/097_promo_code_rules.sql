@@ -0,0 +1,44 @@
+-- Migration: 097_promo_code_rules
+-- 优惠码规则：
+--   首次充值比例赠送（可设上限与最低单笔充值金额）、每用户使用次数、
+--   邮箱域名与用户属性限制、叠加规则，以及赠送指定分组的试用订阅；
+--   使用记录不再限制 (promo_code_id, user_id) 唯一，由 per_user_limit 控制次数，
+--   并记录充值赠送的发放状态；赠送金额列与其他金额列一致扩展为 DECIMAL(20,10)。
+
+ALTER TABLE promo_codes ADD COLUMN IF NOT EXISTS topup_bonus_percent DECIMAL(6,2) NOT NULL DEFAULT 0;
+ALTER TABLE promo_codes ADD COLUMN IF NOT EXISTS topup_bonus_max DECIMAL(20,10) NOT NULL DEFAULT 0;
+ALTER TABLE promo_codes ADD COLUMN IF NOT EXISTS min_topup_amount DECIMAL(20,10) NOT NULL DEFAULT 0;
+ALTER TABLE promo_codes ADD COLUMN IF NOT EXISTS per_user_limit INT NOT NULL DEFAULT 1;
+ALTER TABLE promo_codes ADD COLUMN IF NOT EXISTS allowed_email_domains JSONB DEFAULT NULL;
+ALTER TABLE promo_codes ADD COLUMN IF NOT EXISTS required_attributes JSONB DEFAULT NULL;
+ALTER TABLE promo_codes ADD COLUMN IF NOT EXISTS stackable BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE promo_codes ADD COLUMN IF NOT EXISTS trial_group_id BIGINT DEFAULT NULL REFERENCES groups(id) ON DELETE SET NULL;
+ALTER TABLE promo_codes ADD COLUMN IF NOT EXISTS trial_days INT NOT NULL DEFAULT 0;
+
+ALTER TABLE promo_codes
+    ALTER COLUMN bonus_amount TYPE DECIMAL(20, 10) USING bonus_amount::DECIMAL(20, 10);
+
+COMMENT ON COLUMN promo_codes.topup_bonus_percent IS '首次充值赠送比例（百分比），0表示不赠送';
+COMMENT ON COLUMN promo_codes.topup_bonus_max IS '首次充值赠送上限，0表示无上限';
+COMMENT ON COLUMN promo_codes.min_topup_amount IS '触发充值赠送的最低单笔充值金额';
+COMMENT ON COLUMN promo_codes.per_user_limit IS '每个用户最多使用次数，0表示无限制';
+COMMENT ON COLUMN promo_codes.allowed_email_domains IS 'JSON array of allowed email domains, e.g. ["example.com"]';
+COMMENT ON COLUMN promo_codes.required_attributes IS 'JSON object of required user attribute values keyed by attribute key';
+COMMENT ON COLUMN promo_codes.stackable IS '是否可与其他可叠加优惠码同时使用';
+COMMENT ON COLUMN promo_codes.trial_group_id IS '赠送试用订阅的分组ID';
+COMMENT ON COLUMN promo_codes.trial_days IS '试用订阅天数';
+
+ALTER TABLE promo_code_usages ADD COLUMN IF NOT EXISTS topup_bonus_status VARCHAR(20) NOT NULL DEFAULT 'none';
+ALTER TABLE promo_code_usages ADD COLUMN IF NOT EXISTS topup_bonus_amount DECIMAL(20,10) NOT NULL DEFAULT 0;
+ALTER TABLE promo_code_usages ADD COLUMN IF NOT EXISTS topup_granted_at TIMESTAMPTZ DEFAULT NULL;
+
+ALTER TABLE promo_code_usages
+    ALTER COLUMN bonus_amount TYPE DECIMAL(20, 10) USING bonus_amount::DECIMAL(20, 10);
+
+COMMENT ON COLUMN promo_code_usages.topup_bonus_status IS '充值赠送状态: none, pending, granted';
+
+-- 033 以表约束创建的唯一约束，名称由 PostgreSQL 自动生成
+ALTER TABLE promo_code_usages DROP CONSTRAINT IF EXISTS promo_code_usages_promo_code_id_user_id_key;
+DROP INDEX IF EXISTS promocodeusage_promo_code_id_user_id;
+CREATE INDEX IF NOT EXISTS idx_promo_code_usages_code_user ON promo_code_usages(promo_code_id, user_id);
+CREATE INDEX IF NOT EXISTS idx_promo_code_usages_user_topup ON promo_code_usages(user_id, topup_bonus_status);